@@ -0,0 +1,507 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: vc.proto
+
+package vcpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VC_GetIssue_FullMethodName       = "/vc.v1.VC/GetIssue"
+	VC_ListIssues_FullMethodName     = "/vc.v1.VC/ListIssues"
+	VC_CreateIssue_FullMethodName    = "/vc.v1.VC/CreateIssue"
+	VC_UpdateIssue_FullMethodName    = "/vc.v1.VC/UpdateIssue"
+	VC_CloseIssue_FullMethodName     = "/vc.v1.VC/CloseIssue"
+	VC_AddComment_FullMethodName     = "/vc.v1.VC/AddComment"
+	VC_ListExecutions_FullMethodName = "/vc.v1.VC/ListExecutions"
+	VC_GetExecution_FullMethodName   = "/vc.v1.VC/GetExecution"
+	VC_ListEvents_FullMethodName     = "/vc.v1.VC/ListEvents"
+	VC_WatchEvents_FullMethodName    = "/vc.v1.VC/WatchEvents"
+)
+
+// VCClient is the client API for VC service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VC reads and changes the tracker: the core operations on issues,
+// executions and events, plus a streaming watch of new events.
+//
+// Served by 'vc serve --grpc-addr'. Every call needs an
+// "authorization: Bearer <token>" metadata entry with a token from
+// VC_API_TOKENS; changes are recorded as made by the token's actor.
+type VCClient interface {
+	// GetIssue returns an issue with its labels and dependencies
+	GetIssue(ctx context.Context, in *GetIssueRequest, opts ...grpc.CallOption) (*Issue, error)
+	// ListIssues returns a page of issues
+	ListIssues(ctx context.Context, in *ListIssuesRequest, opts ...grpc.CallOption) (*ListIssuesResponse, error)
+	// CreateIssue creates an issue. New issues are open, priority 2 tasks
+	// unless the request says otherwise.
+	CreateIssue(ctx context.Context, in *CreateIssueRequest, opts ...grpc.CallOption) (*Issue, error)
+	// UpdateIssue changes the fields set in the request. Issues are closed
+	// with CloseIssue, so a reason is recorded.
+	UpdateIssue(ctx context.Context, in *UpdateIssueRequest, opts ...grpc.CallOption) (*Issue, error)
+	// CloseIssue closes an issue
+	CloseIssue(ctx context.Context, in *CloseIssueRequest, opts ...grpc.CallOption) (*Issue, error)
+	// AddComment comments on an issue
+	AddComment(ctx context.Context, in *AddCommentRequest, opts ...grpc.CallOption) (*Comment, error)
+	// ListExecutions returns agent executions, newest first
+	ListExecutions(ctx context.Context, in *ListExecutionsRequest, opts ...grpc.CallOption) (*ListExecutionsResponse, error)
+	// GetExecution returns one execution
+	GetExecution(ctx context.Context, in *GetExecutionRequest, opts ...grpc.CallOption) (*Execution, error)
+	// ListEvents returns a page of the issue audit trail
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	// WatchEvents streams issue events, and optionally agent events, as they
+	// are recorded, until the client cancels
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEventsResponse], error)
+}
+
+type vCClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVCClient(cc grpc.ClientConnInterface) VCClient {
+	return &vCClient{cc}
+}
+
+func (c *vCClient) GetIssue(ctx context.Context, in *GetIssueRequest, opts ...grpc.CallOption) (*Issue, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Issue)
+	err := c.cc.Invoke(ctx, VC_GetIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) ListIssues(ctx context.Context, in *ListIssuesRequest, opts ...grpc.CallOption) (*ListIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIssuesResponse)
+	err := c.cc.Invoke(ctx, VC_ListIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) CreateIssue(ctx context.Context, in *CreateIssueRequest, opts ...grpc.CallOption) (*Issue, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Issue)
+	err := c.cc.Invoke(ctx, VC_CreateIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) UpdateIssue(ctx context.Context, in *UpdateIssueRequest, opts ...grpc.CallOption) (*Issue, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Issue)
+	err := c.cc.Invoke(ctx, VC_UpdateIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) CloseIssue(ctx context.Context, in *CloseIssueRequest, opts ...grpc.CallOption) (*Issue, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Issue)
+	err := c.cc.Invoke(ctx, VC_CloseIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) AddComment(ctx context.Context, in *AddCommentRequest, opts ...grpc.CallOption) (*Comment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Comment)
+	err := c.cc.Invoke(ctx, VC_AddComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) ListExecutions(ctx context.Context, in *ListExecutionsRequest, opts ...grpc.CallOption) (*ListExecutionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListExecutionsResponse)
+	err := c.cc.Invoke(ctx, VC_ListExecutions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) GetExecution(ctx context.Context, in *GetExecutionRequest, opts ...grpc.CallOption) (*Execution, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Execution)
+	err := c.cc.Invoke(ctx, VC_GetExecution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, VC_ListEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEventsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VC_ServiceDesc.Streams[0], VC_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, WatchEventsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VC_WatchEventsClient = grpc.ServerStreamingClient[WatchEventsResponse]
+
+// VCServer is the server API for VC service.
+// All implementations must embed UnimplementedVCServer
+// for forward compatibility.
+//
+// VC reads and changes the tracker: the core operations on issues,
+// executions and events, plus a streaming watch of new events.
+//
+// Served by 'vc serve --grpc-addr'. Every call needs an
+// "authorization: Bearer <token>" metadata entry with a token from
+// VC_API_TOKENS; changes are recorded as made by the token's actor.
+type VCServer interface {
+	// GetIssue returns an issue with its labels and dependencies
+	GetIssue(context.Context, *GetIssueRequest) (*Issue, error)
+	// ListIssues returns a page of issues
+	ListIssues(context.Context, *ListIssuesRequest) (*ListIssuesResponse, error)
+	// CreateIssue creates an issue. New issues are open, priority 2 tasks
+	// unless the request says otherwise.
+	CreateIssue(context.Context, *CreateIssueRequest) (*Issue, error)
+	// UpdateIssue changes the fields set in the request. Issues are closed
+	// with CloseIssue, so a reason is recorded.
+	UpdateIssue(context.Context, *UpdateIssueRequest) (*Issue, error)
+	// CloseIssue closes an issue
+	CloseIssue(context.Context, *CloseIssueRequest) (*Issue, error)
+	// AddComment comments on an issue
+	AddComment(context.Context, *AddCommentRequest) (*Comment, error)
+	// ListExecutions returns agent executions, newest first
+	ListExecutions(context.Context, *ListExecutionsRequest) (*ListExecutionsResponse, error)
+	// GetExecution returns one execution
+	GetExecution(context.Context, *GetExecutionRequest) (*Execution, error)
+	// ListEvents returns a page of the issue audit trail
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	// WatchEvents streams issue events, and optionally agent events, as they
+	// are recorded, until the client cancels
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[WatchEventsResponse]) error
+	mustEmbedUnimplementedVCServer()
+}
+
+// UnimplementedVCServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVCServer struct{}
+
+func (UnimplementedVCServer) GetIssue(context.Context, *GetIssueRequest) (*Issue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssue not implemented")
+}
+func (UnimplementedVCServer) ListIssues(context.Context, *ListIssuesRequest) (*ListIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIssues not implemented")
+}
+func (UnimplementedVCServer) CreateIssue(context.Context, *CreateIssueRequest) (*Issue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIssue not implemented")
+}
+func (UnimplementedVCServer) UpdateIssue(context.Context, *UpdateIssueRequest) (*Issue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateIssue not implemented")
+}
+func (UnimplementedVCServer) CloseIssue(context.Context, *CloseIssueRequest) (*Issue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseIssue not implemented")
+}
+func (UnimplementedVCServer) AddComment(context.Context, *AddCommentRequest) (*Comment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddComment not implemented")
+}
+func (UnimplementedVCServer) ListExecutions(context.Context, *ListExecutionsRequest) (*ListExecutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExecutions not implemented")
+}
+func (UnimplementedVCServer) GetExecution(context.Context, *GetExecutionRequest) (*Execution, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExecution not implemented")
+}
+func (UnimplementedVCServer) ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (UnimplementedVCServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[WatchEventsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedVCServer) mustEmbedUnimplementedVCServer() {}
+func (UnimplementedVCServer) testEmbeddedByValue()            {}
+
+// UnsafeVCServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VCServer will
+// result in compilation errors.
+type UnsafeVCServer interface {
+	mustEmbedUnimplementedVCServer()
+}
+
+func RegisterVCServer(s grpc.ServiceRegistrar, srv VCServer) {
+	// If the following call pancis, it indicates UnimplementedVCServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VC_ServiceDesc, srv)
+}
+
+func _VC_GetIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).GetIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_GetIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).GetIssue(ctx, req.(*GetIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_ListIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).ListIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_ListIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).ListIssues(ctx, req.(*ListIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_CreateIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).CreateIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_CreateIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).CreateIssue(ctx, req.(*CreateIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_UpdateIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).UpdateIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_UpdateIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).UpdateIssue(ctx, req.(*UpdateIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_CloseIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).CloseIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_CloseIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).CloseIssue(ctx, req.(*CloseIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_AddComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).AddComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_AddComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).AddComment(ctx, req.(*AddCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_ListExecutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExecutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).ListExecutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_ListExecutions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).ListExecutions(ctx, req.(*ListExecutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_GetExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExecutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).GetExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_GetExecution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).GetExecution(ctx, req.(*GetExecutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VC_ListEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VC_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VCServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, WatchEventsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VC_WatchEventsServer = grpc.ServerStreamingServer[WatchEventsResponse]
+
+// VC_ServiceDesc is the grpc.ServiceDesc for VC service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VC_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vc.v1.VC",
+	HandlerType: (*VCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetIssue",
+			Handler:    _VC_GetIssue_Handler,
+		},
+		{
+			MethodName: "ListIssues",
+			Handler:    _VC_ListIssues_Handler,
+		},
+		{
+			MethodName: "CreateIssue",
+			Handler:    _VC_CreateIssue_Handler,
+		},
+		{
+			MethodName: "UpdateIssue",
+			Handler:    _VC_UpdateIssue_Handler,
+		},
+		{
+			MethodName: "CloseIssue",
+			Handler:    _VC_CloseIssue_Handler,
+		},
+		{
+			MethodName: "AddComment",
+			Handler:    _VC_AddComment_Handler,
+		},
+		{
+			MethodName: "ListExecutions",
+			Handler:    _VC_ListExecutions_Handler,
+		},
+		{
+			MethodName: "GetExecution",
+			Handler:    _VC_GetExecution_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _VC_ListEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _VC_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "vc.proto",
+}
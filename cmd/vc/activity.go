@@ -27,10 +27,8 @@ Shows events from the agent_events table including:
 - Errors and warnings
 - Watchdog alerts and interventions
 
-Use filters to narrow down events by issue, type, or severity.
-
-Examples:
-  vc activity                              # Show last 20 events
+Use filters to narrow down events by issue, type, or severity.`,
+	Example: `  vc activity                              # Show last 20 events
   vc activity -n 50                        # Show last 50 events
   vc activity --issue vc-123               # Show events for specific issue
   vc activity --type error                 # Show only error events
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// archiveFileExt is the extension of archive files in the archive directory
+const archiveFileExt = ".jsonl"
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Move long-closed issues out of the database",
+	Long: `Move issues closed more than --retention-days ago, with their events,
+labels, dependencies and execution history, into an archive file in
+.beads/archive/. This keeps the database small and queries fast while the
+history stays available.
+
+Issues still linked by a dependency to an issue that isn't archived, and
+issues with attachments or custom field values, stay in the database.
+
+Archives use the 'vc export' format: search them with 'vc archive search'
+and bring issues back with 'vc import <archive-file>'.`,
+	Example: `  vc archive                        # Archive issues closed over 90 days ago
+  vc archive --retention-days 365   # Keep a year of closed issues
+  vc archive --dry-run              # Preview what would be archived`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		retentionDays, _ := cmd.Flags().GetInt("retention-days")
+		if retentionDays < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --retention-days cannot be negative\n")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		opts := types.ArchiveOptions{
+			ClosedBefore: time.Now().AddDate(0, 0, -retentionDays),
+			DryRun:       dryRun,
+		}
+		if dryRun {
+			stats, err := store.ArchiveIssues(ctx, opts, nil)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("%s\n", color.YellowString("DRY RUN MODE - Nothing will be archived"))
+			printArchiveStats("Would archive", stats)
+			return
+		}
+
+		dir := archiveDir()
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to create archive directory: %v\n", err)
+			os.Exit(1)
+		}
+		path := filepath.Join(dir, "archive-"+time.Now().Format("20060102-150405")+archiveFileExt)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		stats, err := store.ArchiveIssues(ctx, opts, f)
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write archive: %w", closeErr)
+		}
+		if err != nil || stats.Issues == 0 {
+			_ = os.Remove(path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if stats.Issues == 0 {
+			fmt.Printf("No issues closed more than %d days ago to archive", retentionDays)
+			if stats.Kept > 0 {
+				fmt.Printf(" (%d kept in place)", stats.Kept)
+			}
+			fmt.Println()
+			return
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		printArchiveStats(green("✓")+" Archived", stats)
+		fmt.Printf("  Archive: %s\n", path)
+	},
+}
+
+// printArchiveStats prints what an archive run moved (or would move)
+func printArchiveStats(verb string, stats *types.ArchiveStats) {
+	fmt.Printf("%s %d issue(s) with %d event(s), %d label(s), %d dependencies and %d execution attempt(s)\n",
+		verb, stats.Issues, stats.Events, stats.Labels, stats.Dependencies, stats.Executions)
+	if stats.Kept > 0 {
+		fmt.Printf("  Kept %d closed issue(s) still linked to live issues or holding attachments or custom fields\n", stats.Kept)
+	}
+}
+
+var archiveListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List archive files",
+	Example: `  vc archive list`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		archives, err := readArchives()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(archives) == 0 {
+			fmt.Printf("\nNo archives in %s\n\n", archiveDir())
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Archives in %s:\n\n", cyan("📦"), archiveDir())
+		for _, a := range archives {
+			fmt.Printf("  %s  %5d issue(s)  %s\n", filepath.Base(a.path), len(a.graph.Issues),
+				gray(a.graph.Header.ExportedAt.Local().Format("2006-01-02 15:04")))
+		}
+		fmt.Println()
+	},
+}
+
+var archiveSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search archived issues",
+	Long: `Search the archive files for issues whose ID, title, description or
+notes contain the query (case-insensitive).`,
+	Example: `  vc archive search "login timeout"
+  vc archive search vc-a1b2`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query := strings.ToLower(args[0])
+		archives, err := readArchives()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		found := 0
+		for _, a := range archives {
+			for _, issue := range a.graph.Issues {
+				if !strings.Contains(strings.ToLower(issue.ID), query) &&
+					!strings.Contains(strings.ToLower(issue.Title), query) &&
+					!strings.Contains(strings.ToLower(issue.Description), query) &&
+					!strings.Contains(strings.ToLower(issue.Notes), query) {
+					continue
+				}
+				closed := ""
+				if issue.ClosedAt != nil {
+					closed = "closed " + issue.ClosedAt.Local().Format("2006-01-02")
+				}
+				fmt.Printf("  %s [P%d] %s\n", issue.ID, issue.Priority, issue.Title)
+				fmt.Printf("    %s\n", gray(closed+"  "+filepath.Base(a.path)))
+				found++
+			}
+		}
+		if found == 0 {
+			fmt.Printf("No archived issues match %q\n", args[0])
+		}
+	},
+}
+
+// archiveFile is a parsed archive file
+type archiveFile struct {
+	path  string
+	graph *types.ExportGraph
+}
+
+// archiveDir is where vc archive writes archive files, next to the database
+func archiveDir() string {
+	return filepath.Join(filepath.Dir(dbPath), "archive")
+}
+
+// readArchives parses every archive file, oldest first
+func readArchives() ([]archiveFile, error) {
+	paths, err := filepath.Glob(filepath.Join(archiveDir(), "*"+archiveFileExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var archives []archiveFile
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		graph, err := export.Read(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive %s: %w", path, err)
+		}
+		archives = append(archives, archiveFile{path: path, graph: graph})
+	}
+	return archives, nil
+}
+
+func init() {
+	archiveCmd.Flags().Bool("dry-run", false, "Show what would be archived without archiving")
+	archiveCmd.Flags().Int("retention-days", 90, "Archive issues closed more than this many days ago")
+	archiveCmd.AddCommand(archiveListCmd)
+	archiveCmd.AddCommand(archiveSearchCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var attachmentCmd = &cobra.Command{
+	Use:   "attachment",
+	Short: "Manage issue attachments (diffs, gate logs, transcripts)",
+	Example: `  vc attachment add vc-42 gates.log --kind gate_log
+  vc attachment list vc-42
+  vc attachment get 7 -o gates.log`,
+}
+
+var attachmentListCmd = &cobra.Command{
+	Use:     "list [issue-id]",
+	Short:   "List attachments on an issue",
+	Example: `  vc attachment list vc-42`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		attachments, err := store.ListAttachments(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(attachments) == 0 {
+			fmt.Printf("\n%s has no attachments\n\n", args[0])
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("\n%s Attachments on %s:\n\n", cyan("📎"), args[0])
+		for _, a := range attachments {
+			fmt.Printf("  #%-5d %-10s %8.1f KB  %s  %s\n",
+				a.ID, a.Kind, float64(a.Size)/1024, a.CreatedAt.Format("2006-01-02 15:04"), a.Name)
+		}
+		fmt.Println()
+	},
+}
+
+var attachmentGetCmd = &cobra.Command{
+	Use:   "get [attachment-id]",
+	Short: "Print an attachment's content",
+	Example: `  vc attachment get 7               # Print to stdout
+  vc attachment get 7 -o fix.diff   # Save to a file`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid attachment ID %q\n", args[0])
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		attachment, data, err := store.GetAttachment(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if attachment == nil {
+			fmt.Fprintf(os.Stderr, "Error: attachment %d not found\n", id)
+			os.Exit(1)
+		}
+
+		if output == "" {
+			_, _ = os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Wrote %s (%d bytes) to %s\n", green("✓"), attachment.Name, attachment.Size, output)
+	},
+}
+
+var attachmentAddCmd = &cobra.Command{
+	Use:   "add [issue-id] [file]",
+	Short: "Attach a file to an issue",
+	Example: `  vc attachment add vc-42 fix.diff --kind diff
+  vc attachment add vc-42 /tmp/run.log --name "failing run"`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, _ := cmd.Flags().GetString("kind")
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			name = args[1]
+		}
+
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		attachment := &types.Attachment{
+			IssueID:   args[0],
+			Name:      name,
+			Kind:      types.AttachmentKind(kind),
+			CreatedBy: actor,
+		}
+		ctx := context.Background()
+		if err := store.AddAttachment(ctx, attachment, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Attached %s to %s as #%d\n", green("✓"), name, args[0], attachment.ID)
+	},
+}
+
+func init() {
+	attachmentGetCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	attachmentAddCmd.Flags().StringP("kind", "k", string(types.AttachmentOther), "Attachment kind (diff|gate_log|transcript|other)")
+	attachmentAddCmd.Flags().String("name", "", "Attachment name (default: file path)")
+	attachmentCmd.AddCommand(attachmentListCmd)
+	attachmentCmd.AddCommand(attachmentGetCmd)
+	attachmentCmd.AddCommand(attachmentAddCmd)
+	rootCmd.AddCommand(attachmentCmd)
+}
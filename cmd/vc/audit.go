@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// maxAuditValueWidth truncates long field values (descriptions, notes) in the table
+const maxAuditValueWidth = 60
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [issue-id]",
+	Short: "Show the field-level change history of an issue",
+	Long: `Show every recorded change to an issue's fields: who changed what,
+when, and the value before and after. History is kept after an issue is
+deleted.`,
+	Example: `  vc audit vc-123            # Last 50 changes
+  vc audit vc-123 -n 0       # Full history
+  vc audit vc-123 --full     # Don't truncate long values`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		full, _ := cmd.Flags().GetBool("full")
+
+		ctx := context.Background()
+		entries, err := store.GetAuditLog(ctx, args[0], limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(entries) == 0 {
+			fmt.Printf("\nNo recorded changes for %s\n\n", args[0])
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Change history for %s (newest first):\n\n", cyan("📜"), args[0])
+		for _, entry := range entries {
+			actor := entry.Actor
+			if actor == "" {
+				actor = "-"
+			}
+			fmt.Printf("  %s  %-6s %-20s %s\n",
+				gray(entry.CreatedAt.Local().Format("2006-01-02 15:04:05")), entry.Operation, entry.Field, gray("by "+actor))
+			fmt.Printf("      %s → %s\n", formatAuditValue(entry.OldValue, full), formatAuditValue(entry.NewValue, full))
+		}
+		fmt.Println()
+	},
+}
+
+// formatAuditValue renders a field value on one line, truncated unless full
+func formatAuditValue(value string, full bool) string {
+	if value == "" {
+		return "(empty)"
+	}
+	value = strings.ReplaceAll(value, "\n", "⏎")
+	if runes := []rune(value); !full && len(runes) > maxAuditValueWidth {
+		value = string(runes[:maxAuditValueWidth-3]) + "..."
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+func init() {
+	auditCmd.Flags().IntP("limit", "n", 50, "Maximum number of changes to show (0 = all)")
+	auditCmd.Flags().Bool("full", false, "Show long values in full")
+	rootCmd.AddCommand(auditCmd)
+}
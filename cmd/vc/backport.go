@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/backport"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+var backportCmd = &cobra.Command{
+	Use:   "backport <issue-id>",
+	Short: "Cherry-pick an issue's landed commits onto release branches",
+	Long: `Cherry-pick the commits an issue's executions landed onto the release
+branches its backport:<branch> labels name, e.g. backport:release-1.x.
+
+For each branch, the commits are picked in a scratch worktree, starting from
+<remote>/<branch> as last fetched (or the local branch), and the quality gates
+run there. If they pass, the result is pushed as vc/backport/<branch>/<issue>
+and a pull request is opened against the release branch (with a GitHub or
+GitLab token; otherwise the branch is only prepared locally). If the commits
+conflict or the gates fail, a follow-up issue is filed to finish the backport
+by hand. Outcomes are recorded as comments on the issue.
+
+With --auto-backport (or VC_AUTO_BACKPORT=true), the executor does this by
+itself whenever a labelled issue's work lands.`,
+	Example: `  vc create "Fix token refresh" -t bug -l backport:release-1.x
+  vc backport vc-42`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		projectRoot, err := storage.GetProjectRoot(dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		hostingConfig, err := config.HostingConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		pushChecksConfig, err := config.PushChecksConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		gitOps, err := git.NewGit(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		manager, err := backport.New(backport.Config{
+			Store:      store,
+			Git:        gitOps,
+			RepoPath:   projectRoot,
+			Hosting:    hostingConfig,
+			PushChecks: pushChecksConfig,
+			Actor:      actor,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		results, err := manager.BackportIssue(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(results) == 0 {
+			fmt.Printf("%s has no %s<branch> labels; nothing to backport\n", args[0], backport.LabelPrefix)
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		red := color.New(color.FgRed).SprintFunc()
+		failed := false
+		for _, result := range results {
+			switch result.Status {
+			case backport.StatusOpened:
+				fmt.Printf("%s %s: opened %s\n", green("✓"), result.TargetBranch, result.PRURL)
+			case backport.StatusBranch:
+				fmt.Printf("%s %s: ready on branch %s (%s)\n", green("✓"), result.TargetBranch, result.Branch, result.Reason)
+			case backport.StatusSkipped:
+				fmt.Printf("%s %s: skipped, %s\n", yellow("-"), result.TargetBranch, result.Reason)
+			default:
+				failed = true
+				fmt.Printf("%s %s: %s\n", red("✗"), result.TargetBranch, result.Reason)
+				if result.FollowUpIssueID != "" {
+					fmt.Printf("  Filed %s to finish the backport\n", result.FollowUpIssueID)
+				}
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(backportCmd)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Take a consistent backup of the database",
+	Long: `Take a consistent backup of the VC database using the SQLite backup API.
+
+The database is the single source of truth for missions, plans and execution
+history. Backups are safe to take while the executor is running.
+
+By default backups are written to .beads/backups with a timestamped name.
+Backups of an encrypted database (VC_DB_PASSPHRASE) are encrypted with the
+same passphrase. --passphrase-env names another environment variable to
+encrypt the backup with instead, which is how a database is encrypted,
+decrypted or re-keyed: back it up, stop VC, and move the backup in place.
+Scheduled backups can be enabled for the executor with VC_BACKUP_ENABLED=true
+(see VC_BACKUP_INTERVAL_HOURS, VC_BACKUP_KEEP and VC_BACKUP_DIR).`,
+	Example: `  # Back up to .beads/backups/vc-backup-<timestamp>.db
+  vc backup
+
+  # Back up to a specific file
+  vc backup --output /tmp/vc.db
+
+  # List existing backups
+  vc backup --list
+
+  # Write an encrypted copy of an unencrypted database
+  NEW_PASSPHRASE=... vc backup --output /tmp/vc-encrypted.db --passphrase-env NEW_PASSPHRASE`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		list, _ := cmd.Flags().GetBool("list")
+		keep, _ := cmd.Flags().GetInt("keep")
+		passphraseEnv, _ := cmd.Flags().GetString("passphrase-env")
+
+		if memoryStore {
+			fmt.Fprintf(os.Stderr, "Error: nothing to back up when using --memory\n")
+			os.Exit(1)
+		}
+
+		backupCfg, err := config.BackupConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		dir := backupDir(backupCfg)
+
+		if list {
+			printBackups(dir)
+			return
+		}
+
+		ctx := context.Background()
+		dest := output
+		if dest == "" {
+			dest = filepath.Join(dir, storage.BackupFileName(time.Now()))
+		}
+
+		passphrase := storage.Passphrase()
+		if passphraseEnv != "" {
+			// Set but empty is how to ask for an unencrypted backup
+			value, ok := os.LookupEnv(passphraseEnv)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: environment variable %s is not set\n", passphraseEnv)
+				os.Exit(1)
+			}
+			passphrase = value
+		}
+
+		start := time.Now()
+		if err := storage.BackupDatabaseEncrypted(ctx, dbPath, dest, passphrase); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Backed up %s to %s (%v)\n", green("✓"), dbPath, dest, time.Since(start).Round(time.Millisecond))
+
+		// Retention only applies to the managed backup directory
+		if output == "" && keep > 0 {
+			deleted, err := storage.PruneBackups(dir, keep)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			} else if deleted > 0 {
+				fmt.Printf("  Deleted %d old backup(s), keeping %d most recent\n", deleted, keep)
+			}
+		}
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <backup-file>",
+	Short: "Restore the database from a backup",
+	Long: `Restore the VC database from a backup taken with 'vc backup'.
+
+The backup is verified before anything is overwritten, and the current
+database is backed up first so a restore can itself be undone. Restoring
+is refused while an executor holds the database.`,
+	Example: `  vc restore .beads/backups/vc-backup-20250101-120000.db`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		backupPath := args[0]
+		noSafetyBackup, _ := cmd.Flags().GetBool("no-safety-backup")
+
+		if memoryStore {
+			fmt.Fprintf(os.Stderr, "Error: cannot restore into a --memory database\n")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		if err := storage.VerifyBackup(ctx, backupPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Release our own connection so the restore has the database to itself
+		if store != nil {
+			_ = store.Close()
+			store = nil
+		}
+
+		if !noSafetyBackup {
+			backupCfg, err := config.BackupConfigFromEnv()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			name := strings.TrimSuffix(storage.BackupFileName(time.Now()), storage.BackupFileExt) + "-pre-restore" + storage.BackupFileExt
+			safety := filepath.Join(backupDir(backupCfg), name)
+			if err := storage.BackupDatabase(ctx, dbPath, safety); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to back up current database before restoring: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Use --no-safety-backup to restore anyway\n")
+				os.Exit(1)
+			}
+			fmt.Printf("Backed up current database to %s\n", safety)
+		}
+
+		if err := storage.RestoreDatabase(ctx, backupPath, dbPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Restored %s from %s\n", green("✓"), dbPath, backupPath)
+	},
+}
+
+// backupDir returns the configured backup directory, defaulting to
+// .beads/backups next to the database
+func backupDir(cfg config.BackupConfig) string {
+	if cfg.Dir != "" {
+		return cfg.Dir
+	}
+	return storage.DefaultBackupDir(dbPath)
+}
+
+func printBackups(dir string) {
+	backups, err := storage.ListBackups(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(backups) == 0 {
+		fmt.Printf("No backups in %s\n", dir)
+		return
+	}
+
+	fmt.Printf("Backups in %s:\n\n", dir)
+	for _, b := range backups {
+		fmt.Printf("  %s  %8.1f KB  %s\n",
+			b.ModTime.Format("2006-01-02 15:04:05"), float64(b.Size)/1024, filepath.Base(b.Path))
+	}
+}
+
+// runScheduledBackups takes a backup every cfg.Interval() until ctx is
+// canceled, pruning to cfg.Keep backups after each one. Failures are logged
+// and retried on the next tick rather than stopping the executor.
+func runScheduledBackups(ctx context.Context, dbPath string, cfg config.BackupConfig) {
+	dir := cfg.Dir
+	if dir == "" {
+		dir = storage.DefaultBackupDir(dbPath)
+	}
+
+	ticker := time.NewTicker(cfg.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dest := filepath.Join(dir, storage.BackupFileName(time.Now()))
+			if err := storage.BackupDatabase(ctx, dbPath, dest); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: scheduled backup failed: %v\n", err)
+				continue
+			}
+			fmt.Printf("Backup: Wrote %s\n", dest)
+
+			deleted, err := storage.PruneBackups(dir, cfg.Keep)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to prune old backups: %v\n", err)
+			} else if deleted > 0 {
+				fmt.Printf("Backup: Deleted %d old backup(s) (keeping %d most recent)\n", deleted, cfg.Keep)
+			}
+		}
+	}
+}
+
+func init() {
+	backupCmd.Flags().StringP("output", "o", "", "Backup file path (default: .beads/backups/vc-backup-<timestamp>.db)")
+	backupCmd.Flags().Bool("list", false, "List existing backups instead of taking one")
+	backupCmd.Flags().Int("keep", 0, "After backing up, delete all but this many most recent backups (0 = keep all)")
+	backupCmd.Flags().String("passphrase-env", "", "Encrypt the backup with the passphrase in this environment variable (empty value = unencrypted)")
+	restoreCmd.Flags().Bool("no-safety-backup", false, "Don't back up the current database before restoring")
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -8,14 +8,22 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/ai"
 	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/deduplication"
 	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/maintenance"
+	"github.com/steveyegge/vc/internal/sandbox"
 )
 
 var cleanupCmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Cleanup and maintenance commands",
 	Long:  `Commands for cleaning up old data and performing database maintenance.`,
+	Example: `  vc cleanup all
+  vc cleanup branches --dry-run
+  vc cleanup worktrees
+  vc cleanup events`,
 }
 
 var cleanupBranchesCmd = &cobra.Command{
@@ -28,10 +36,8 @@ executor crashes or is interrupted. This command finds branches matching the
 pattern "mission/*" that have no corresponding worktree and deletes them.
 
 By default, only branches older than 7 days are deleted to avoid removing
-branches from active missions.
-
-Examples:
-  vc cleanup branches                    # Clean up branches older than 7 days
+branches from active missions.`,
+	Example: `  vc cleanup branches                    # Clean up branches older than 7 days
   vc cleanup branches --retention-days 14  # Clean up branches older than 14 days
   vc cleanup branches --dry-run          # Preview what would be deleted`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -85,6 +91,64 @@ Examples:
 	},
 }
 
+var cleanupWorktreesCmd = &cobra.Command{
+	Use:   "worktrees",
+	Short: "Garbage-collect the worktree pool",
+	Long: `Take back pooled worktrees whose owner stopped renewing them, remove
+directories in the pool that aren't worktrees, and shrink the pool to its
+maximum size.
+
+The worktree pool (.sandboxes/pool) holds detached worktrees that are
+assigned to executions one at a time and recycled. Assignments are git
+worktree locks, visible in 'git worktree list'; worktrees locked by anything
+other than the pool are never touched.`,
+	Example: `  vc cleanup worktrees                   # Reclaim assignments older than 2 hours
+  vc cleanup worktrees --stale-hours 12  # Be more patient with long executions`,
+	Run: func(cmd *cobra.Command, args []string) {
+		staleHours, _ := cmd.Flags().GetInt("stale-hours")
+		maxWorktrees, _ := cmd.Flags().GetInt("max")
+
+		ctx := context.Background()
+
+		repoPath, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to get current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		pool, err := sandbox.NewWorktreePool(sandbox.WorktreePoolConfig{
+			ParentRepo:   repoPath,
+			MaxWorktrees: maxWorktrees,
+			StaleAfter:   time.Duration(staleHours) * time.Hour,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		count, err := pool.GC(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: worktree cleanup failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		worktrees, err := pool.List(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Reclaimed or removed %d worktree(s); %d in the pool\n", green("✓"), count, len(worktrees))
+		for _, wt := range worktrees {
+			owner := "idle"
+			if !wt.Idle() {
+				owner = fmt.Sprintf("%s since %s", wt.Owner, wt.AssignedAt.Local().Format("2006-01-02 15:04"))
+			}
+			fmt.Printf("  %s  %s\n", wt.Path, owner)
+		}
+	},
+}
+
 var cleanupEventsCmd = &cobra.Command{
 	Use:   "events",
 	Short: "Clean up old agent events",
@@ -95,11 +159,14 @@ Executes three cleanup strategies in sequence:
   2. Per-issue: Limit events per issue to configured maximum
   3. Global: Enforce global event count limit
 
-Configuration is read from environment variables (see CLAUDE.md for details).
-Default retention: 30 days (regular), 90 days (critical), 1000 events/issue, 100k global.
+Then compacts the issue history (comments, status changes, AI usage records)
+of issues closed longer than the issue event retention period into one
+summary comment per issue. Creation and close events are kept.
 
-Examples:
-  vc cleanup events                # Run cleanup with defaults
+Configuration is read from environment variables (see CLAUDE.md for details).
+Default retention: 30 days (regular), 90 days (critical), 1000 events/issue, 100k global,
+90 days after close for issue events.`,
+	Example: `  vc cleanup events                # Run cleanup with defaults
   vc cleanup events --vacuum       # Run cleanup and reclaim disk space
   vc cleanup events --dry-run      # Preview what would be deleted`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -124,6 +191,11 @@ Examples:
 		fmt.Printf("  Critical events: %d days\n", retentionCfg.RetentionCriticalDays)
 		fmt.Printf("  Per-issue limit: %d events\n", retentionCfg.PerIssueLimitEvents)
 		fmt.Printf("  Global limit: %d events\n", retentionCfg.GlobalLimitEvents)
+		if retentionCfg.IssueEventRetentionDays > 0 {
+			fmt.Printf("  Issue events: %d days after close\n", retentionCfg.IssueEventRetentionDays)
+		} else {
+			fmt.Printf("  Issue events: kept forever\n")
+		}
 		fmt.Printf("  Batch size: %d events/txn\n", retentionCfg.CleanupBatchSize)
 		if dryRun {
 			fmt.Printf("\n%s\n", color.YellowString("DRY RUN MODE - No events will be deleted"))
@@ -201,6 +273,33 @@ Examples:
 		fmt.Printf("  Deleted %s events\n", formatNumber(globalDeleted))
 		totalDeleted += globalDeleted
 
+		// 4. Issue event compaction
+		compacted := 0
+		if retentionCfg.IssueEventRetentionDays > 0 {
+			fmt.Printf("\nCompacting issue events (closed >%d days)...\n",
+				retentionCfg.IssueEventRetentionDays)
+			compacted, err = store.CompactIssueEvents(ctx,
+				retentionCfg.IssueEventRetentionDays,
+				retentionCfg.CleanupBatchSize)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: issue event compaction failed: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("  Compacted %s events\n", formatNumber(compacted))
+		} else {
+			fmt.Printf("\nSkipping issue event compaction (kept forever)\n")
+		}
+
+		// 5. Agent output of old executions
+		fmt.Printf("\nDeleting agent output of executions finished >%d days ago...\n",
+			retentionCfg.RetentionDays)
+		outputDeleted, err := store.CleanupExecutionOutput(ctx, retentionCfg.RetentionDays)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: execution output cleanup failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("  Deleted %s lines\n", formatNumber(outputDeleted))
+
 		// Get event counts after cleanup
 		afterCounts, err := store.GetEventCounts(ctx)
 
@@ -209,6 +308,8 @@ Examples:
 		green := color.New(color.FgGreen).SprintFunc()
 		fmt.Printf("\n%s Cleanup complete\n", green("✓"))
 		fmt.Printf("  Events deleted: %s\n", formatNumber(totalDeleted))
+		fmt.Printf("  Issue events compacted: %s\n", formatNumber(compacted))
+		fmt.Printf("  Output lines deleted: %s\n", formatNumber(outputDeleted))
 
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to get final event counts: %v\n", err)
@@ -238,17 +339,117 @@ Examples:
 	},
 }
 
+var cleanupAllCmd = &cobra.Command{
+	Use:   "all",
+	Short: "Run every maintenance task once, as vc daemon does nightly",
+	Long: `Run the maintenance job once:
+  1. Check the most recently updated open issues for duplicates
+  2. Prune events per the retention policy (VC_EVENT_RETENTION_*)
+  3. Reopen in_progress issues claimed by executors that stopped, and
+     report in_progress issues no executor has claimed
+  4. Remove sandboxes unused for VC_MAINTENANCE_STALE_SANDBOX_DAYS, prune
+     worktrees and delete old mission branches with no worktree
+  5. ANALYZE and VACUUM the database
+
+Problems that need a human (suspected duplicates, unclaimed in_progress
+issues, failed tasks) are filed as an issue labeled health-report, or added
+to the open one. vc daemon runs the same job on VC_MAINTENANCE_SCHEDULE.`,
+	Example: `  vc cleanup all
+  vc cleanup all --sandbox-root .sandboxes --parent-repo .`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sandboxRoot, _ := cmd.Flags().GetString("sandbox-root")
+		parentRepo, _ := cmd.Flags().GetString("parent-repo")
+
+		maintenanceConfig, err := config.MaintenanceConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		job, err := newMaintenanceJob(maintenanceConfig, sandboxRoot, parentRepo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		report := job.RunOnce(context.Background())
+		green := color.New(color.FgGreen).SprintFunc()
+		red := color.New(color.FgRed).SprintFunc()
+		for _, step := range report.Steps {
+			mark := green("✓")
+			if step.Err != nil || len(step.Problems) > 0 {
+				mark = red("✗")
+			}
+			fmt.Printf("%s %s: %s\n", mark, step.Name, step.Summary)
+			if step.Err != nil {
+				fmt.Printf("    failed: %v\n", step.Err)
+			}
+			for _, problem := range step.Problems {
+				fmt.Printf("    - %s\n", problem)
+			}
+		}
+		fmt.Printf("\n%d problem(s) found in %v\n", report.Problems(), report.Duration.Round(time.Millisecond))
+		if report.IssueID != "" {
+			fmt.Printf("Health report: %s\n", report.IssueID)
+		}
+	},
+}
+
+// newMaintenanceJob creates the maintenance job for the open store. The
+// duplicate sweep needs the AI supervisor and is skipped without it.
+func newMaintenanceJob(maintenanceConfig config.MaintenanceConfig, sandboxRoot, parentRepo string) (*maintenance.Job, error) {
+	retentionConfig, err := config.EventRetentionConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid event retention configuration: %w", err)
+	}
+	var dedup deduplication.Deduplicator
+	if maintenanceConfig.DedupMaxIssues > 0 {
+		if supervisor, err := ai.NewSupervisor(&ai.Config{Store: store}); err == nil {
+			dedupConfig, err := deduplication.ConfigFromEnv()
+			if err != nil {
+				return nil, fmt.Errorf("invalid deduplication configuration: %w", err)
+			}
+			if dedup, err = deduplication.NewAIDeduplicator(supervisor, store, dedupConfig); err != nil {
+				return nil, fmt.Errorf("invalid deduplication configuration: %w", err)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: maintenance will skip the duplicate sweep: %v\n", err)
+		}
+	}
+	job, err := maintenance.NewJob(maintenance.Config{
+		Maintenance:  maintenanceConfig,
+		Retention:    retentionConfig,
+		Store:        store,
+		Deduplicator: dedup,
+		SandboxRoot:  sandboxRoot,
+		ParentRepo:   parentRepo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance configuration: %w", err)
+	}
+	return job, nil
+}
+
 func init() {
+	// Maintenance job flags
+	cleanupAllCmd.Flags().String("sandbox-root", ".sandboxes", "Root directory for sandboxes")
+	cleanupAllCmd.Flags().String("parent-repo", ".", "Parent repository path")
+
 	// Branch cleanup flags
 	cleanupBranchesCmd.Flags().Bool("dry-run", false, "Preview deletions without committing")
 	cleanupBranchesCmd.Flags().Int("retention-days", 7, "Delete branches older than N days")
 
+	// Worktree pool flags
+	cleanupWorktreesCmd.Flags().Int("stale-hours", 2, "Reclaim worktrees assigned without renewal for more than N hours")
+	cleanupWorktreesCmd.Flags().Int("max", sandbox.DefaultPoolMaxWorktrees, "Maximum worktrees to keep in the pool")
+
 	// Event cleanup flags
 	cleanupEventsCmd.Flags().Bool("dry-run", false, "Preview deletions without committing")
 	cleanupEventsCmd.Flags().Bool("vacuum", false, "Run VACUUM after cleanup to reclaim disk space")
 
+	cleanupCmd.AddCommand(cleanupAllCmd)
 	cleanupCmd.AddCommand(cleanupBranchesCmd)
 	cleanupCmd.AddCommand(cleanupEventsCmd)
+	cleanupCmd.AddCommand(cleanupWorktreesCmd)
 	rootCmd.AddCommand(cleanupCmd)
 }
 
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone [id]",
+	Short: "Copy an issue, optionally with its child tree and dependencies",
+	Long:  `Copy an issue as a new open issue. Execution history is never copied.`,
+	Example: `  # Re-run a mission template against a new milestone
+  vc clone vc-42 --children --deps --suffix " (M2)"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := types.CloneOptions{}
+		opts.IncludeChildren, _ = cmd.Flags().GetBool("children")
+		opts.IncludeDependencies, _ = cmd.Flags().GetBool("deps")
+		opts.TitleSuffix, _ = cmd.Flags().GetString("suffix")
+
+		ctx := context.Background()
+		result, err := store.CloneIssue(ctx, args[0], opts, actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Cloned %s as %s: %s\n", green("✓"), args[0], result.Root.ID, result.Root.Title)
+		if len(result.IDs) > 1 {
+			originals := make([]string, 0, len(result.IDs))
+			for original := range result.IDs {
+				if original != args[0] {
+					originals = append(originals, original)
+				}
+			}
+			sort.Strings(originals)
+			for _, original := range originals {
+				fmt.Printf("  %s → %s\n", original, result.IDs[original])
+			}
+		}
+	},
+}
+
+func init() {
+	cloneCmd.Flags().Bool("children", false, "Also copy the issue's child tree")
+	cloneCmd.Flags().Bool("deps", false, "Also copy dependencies (pointing at copies where they exist)")
+	cloneCmd.Flags().String("suffix", "", "Text appended to every copied title")
+	rootCmd.AddCommand(cloneCmd)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var commentCmd = &cobra.Command{
+	Use:   "comment",
+	Short: "Discuss an issue in threaded comments",
+	Long: `Add, reply to, edit and react to comments on an issue.
+
+Comments are identified by the number shown next to them in 'vc comment list'
+and 'vc show'. Only a comment's author (--actor) can edit it; earlier versions
+are kept and shown with 'vc comment list --history'.`,
+	Example: `  vc comment add vc-a1b2 "Should this retry on timeout?"
+  vc comment reply 42 "Yes, with exponential backoff"
+  vc comment edit 42 "Yes, with exponential backoff capped at 30s"
+  vc comment react 42 +1
+  vc comment list vc-a1b2`,
+}
+
+var commentListCmd = &cobra.Command{
+	Use:   "list <issue-id>",
+	Short: "Show an issue's comment threads",
+	Example: `  vc comment list vc-42
+  vc comment list vc-42 --history   # Include earlier versions of edited comments`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		history, _ := cmd.Flags().GetBool("history")
+		comments, err := store.GetComments(context.Background(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(comments) == 0 {
+			fmt.Printf("No comments on %s\n", args[0])
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("\n%s Comments on %s (%d):\n\n", cyan("💬"), args[0], len(comments))
+		for _, c := range types.ThreadComments(comments) {
+			printCommentThread(c, 1, history)
+		}
+		fmt.Println()
+	},
+}
+
+var commentAddCmd = &cobra.Command{
+	Use:     "add <issue-id> <text>",
+	Short:   "Comment on an issue",
+	Example: `  vc comment add vc-42 "Repro steps are in the attached log"`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if strings.TrimSpace(args[1]) == "" {
+			fmt.Fprintf(os.Stderr, "Error: comment text is required\n")
+			os.Exit(1)
+		}
+		if err := store.AddComment(context.Background(), args[0], actor, args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Commented on %s\n", green("✓"), args[0])
+	},
+}
+
+var commentReplyCmd = &cobra.Command{
+	Use:     "reply <comment-id> <text>",
+	Short:   "Reply to a comment",
+	Example: `  vc comment reply 12 "Yes, retry twice with backoff"`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		reply, err := store.ReplyToComment(context.Background(), parseCommentID(args[0]), actor, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Replied on %s (comment #%d)\n", green("✓"), reply.IssueID, reply.ID)
+	},
+}
+
+var commentEditCmd = &cobra.Command{
+	Use:     "edit <comment-id> <text>",
+	Short:   "Edit one of your comments",
+	Example: `  vc comment edit 12 "Yes, retry three times with backoff"`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := parseCommentID(args[0])
+		if err := store.EditComment(context.Background(), id, actor, args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Edited comment #%d\n", green("✓"), id)
+	},
+}
+
+var commentReactCmd = &cobra.Command{
+	Use:   "react <comment-id> <reaction>",
+	Short: "React to a comment (e.g. +1, eyes, 🎉)",
+	Example: `  vc comment react 12 +1
+  vc comment react 12 eyes --remove`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		remove, _ := cmd.Flags().GetBool("remove")
+		ctx := context.Background()
+		id := parseCommentID(args[0])
+		var err error
+		if remove {
+			err = store.RemoveReaction(ctx, id, actor, args[1])
+		} else {
+			err = store.AddReaction(ctx, id, actor, args[1])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		if remove {
+			fmt.Printf("%s Removed %s from comment #%d\n", green("✓"), args[1], id)
+		} else {
+			fmt.Printf("%s Reacted %s to comment #%d\n", green("✓"), args[1], id)
+		}
+	},
+}
+
+// parseCommentID parses a comment ID argument, exiting if it isn't one
+func parseCommentID(arg string) int64 {
+	id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid comment ID %q\n", arg)
+		os.Exit(1)
+	}
+	return id
+}
+
+// printCommentThread prints a comment and its replies, indented by depth.
+// With history, earlier versions of edited comments are printed too.
+func printCommentThread(c *types.Comment, depth int, history bool) {
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	indent := strings.Repeat("  ", depth)
+
+	meta := fmt.Sprintf("#%d %s, %s", c.ID, c.Author, c.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if c.EditedAt != nil {
+		meta += " (edited)"
+	}
+	fmt.Printf("%s%s\n", indent, gray(meta))
+	for _, line := range strings.Split(c.Body, "\n") {
+		fmt.Printf("%s%s\n", indent, line)
+	}
+	if history {
+		for i := len(c.Edits) - 1; i >= 0; i-- {
+			edit := c.Edits[i]
+			fmt.Printf("%s%s\n", indent, gray(fmt.Sprintf("  before %s: %s", edit.EditedAt.Local().Format("2006-01-02 15:04"), edit.PreviousBody)))
+		}
+	}
+	if counts := c.ReactionCounts(); len(counts) > 0 {
+		reactions := make([]string, 0, len(counts))
+		for reaction, n := range counts {
+			reactions = append(reactions, fmt.Sprintf("%s %d", reaction, n))
+		}
+		sort.Strings(reactions)
+		fmt.Printf("%s%s\n", indent, gray("["+strings.Join(reactions, "  ")+"]"))
+	}
+	for _, reply := range c.Replies {
+		printCommentThread(reply, depth+1, history)
+	}
+}
+
+func init() {
+	commentListCmd.Flags().Bool("history", false, "Also show earlier versions of edited comments")
+	commentReactCmd.Flags().Bool("remove", false, "Remove the reaction instead of adding it")
+	commentCmd.AddCommand(commentListCmd)
+	commentCmd.AddCommand(commentAddCmd)
+	commentCmd.AddCommand(commentReplyCmd)
+	commentCmd.AddCommand(commentEditCmd)
+	commentCmd.AddCommand(commentReactCmd)
+	rootCmd.AddCommand(commentCmd)
+}
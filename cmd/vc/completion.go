@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Shell completion. Cobra generates the scripts ('vc completion bash|zsh|
+// fish|powershell'); the functions here complete issue IDs, labels,
+// projects, saved filters and schedules from the database. The scripts run
+// 'vc __complete ...', which opens the database read-only and completes
+// nothing from it if there isn't one.
+
+// maxIssueCompletions caps the issue IDs offered, so completing in a big
+// database stays fast
+const maxIssueCompletions = 500
+
+// openCompletionStore opens the database read-only for completion, or
+// returns nil if there isn't one. It never exits: command names still
+// complete outside a project.
+func openCompletionStore() storage.Storage {
+	if memoryStore {
+		return nil
+	}
+	path := dbPath
+	if path == "" {
+		var err error
+		if path, err = storage.DiscoverDatabase(); err != nil {
+			return nil
+		}
+	}
+	s, err := beads.OpenExistingVCStorage(context.Background(), path, storage.Passphrase())
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// completeIssues completes issue IDs (with their titles) matching keep,
+// open issues first
+func completeIssues(toComplete string, keep func(*types.Issue) bool) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	issues, err := store.SearchIssues(context.Background(), "", types.IssueFilter{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Status != types.StatusClosed && issues[j].Status == types.StatusClosed
+	})
+	var completions []cobra.Completion
+	for _, issue := range issues {
+		if !strings.HasPrefix(issue.ID, toComplete) || !keep(issue) {
+			continue
+		}
+		completions = append(completions, cobra.CompletionWithDesc(issue.ID, issue.Title))
+		if len(completions) == maxIssueCompletions {
+			break
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeIssueID completes an issue ID
+func completeIssueID(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return completeIssues(toComplete, func(*types.Issue) bool { return true })
+}
+
+// completeMissionID completes the ID of a mission
+func completeMissionID(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return completeIssues(toComplete, func(issue *types.Issue) bool {
+		return issue.IssueType == types.TypeEpic && issue.IssueSubtype == types.SubtypeMission
+	})
+}
+
+// completeEpicID completes the ID of an epic or mission
+func completeEpicID(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return completeIssues(toComplete, func(issue *types.Issue) bool { return issue.IssueType == types.TypeEpic })
+}
+
+// completePlan completes the mission ID of a draft plan
+func completePlan(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	plans, err := store.ListDraftPlans(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []cobra.Completion
+	for _, plan := range plans {
+		if strings.HasPrefix(plan.MissionID, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(plan.MissionID, string(plan.Status)))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLabel completes a label name
+func completeLabel(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defs, err := store.ListLabelDefinitions(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []cobra.Completion
+	for _, def := range defs {
+		if strings.HasPrefix(def.Name, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(def.Name, def.Description))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProject completes a project name
+func completeProject(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	projects, err := store.ListProjects(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []cobra.Completion
+	for _, project := range projects {
+		if strings.HasPrefix(project.Name, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(project.Name, project.Description))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSavedFilter completes the name of a saved filter
+func completeSavedFilter(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	filters, err := store.ListSavedFilters(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []cobra.Completion
+	for _, filter := range filters {
+		if strings.HasPrefix(filter.Name, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(filter.Name, filter.Description))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSchedule completes the name of a schedule
+func completeSchedule(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	schedules, err := store.ListSchedules(context.Background())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []cobra.Completion
+	for _, schedule := range schedules {
+		if strings.HasPrefix(schedule.Name, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(schedule.Name, schedule.Title))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfile completes the profiles defined in the config file
+func completeProfile(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	// The file is loaded again: applying it fails while the profile being
+	// completed is only partly typed
+	path, err := findConfigFile()
+	if err != nil || path == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	file, err := config.LoadFile(path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []cobra.Completion
+	for _, name := range file.ProfileNames() {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeArgs returns a completion function that completes each argument
+// with the completer at its position. The last completer also completes
+// any further arguments if repeat is set, e.g. for "close [id...]".
+func completeArgs(repeat bool, completers ...cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		i := len(args)
+		if i >= len(completers) {
+			if !repeat {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			i = len(completers) - 1
+		}
+		if completers[i] == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completers[i](cmd, args, toComplete)
+	}
+}
+
+// Fixed values for flags and arguments
+var (
+	completeStatus = cobra.FixedCompletions([]cobra.Completion{
+		string(types.StatusOpen), string(types.StatusInProgress), string(types.StatusBlocked), string(types.StatusClosed),
+	}, cobra.ShellCompDirectiveNoFileComp)
+	completeIssueType = cobra.FixedCompletions([]cobra.Completion{
+		string(types.TypeBug), string(types.TypeFeature), string(types.TypeTask), string(types.TypeEpic), string(types.TypeChore),
+	}, cobra.ShellCompDirectiveNoFileComp)
+	completePriority = cobra.FixedCompletions([]cobra.Completion{
+		cobra.CompletionWithDesc("0", "highest"), "1", "2", "3", cobra.CompletionWithDesc("4", "lowest"),
+	}, cobra.ShellCompDirectiveNoFileComp)
+	completeExportTable = cobra.FixedCompletions([]cobra.Completion{
+		string(export.TableIssues), string(export.TableExecutions), string(export.TableGates), string(export.TableAIUsage),
+	}, cobra.ShellCompDirectiveNoFileComp)
+	completeGitHubSyncPrefer = cobra.FixedCompletions([]cobra.Completion{
+		config.IssueSyncPreferNewer, config.IssueSyncPreferVC, config.HostingGitHub,
+	}, cobra.ShellCompDirectiveNoFileComp)
+	completeGitLabSyncPrefer = cobra.FixedCompletions([]cobra.Completion{
+		config.IssueSyncPreferNewer, config.IssueSyncPreferVC, config.HostingGitLab,
+	}, cobra.ShellCompDirectiveNoFileComp)
+)
+
+// argCompletions sets how each command's arguments complete
+func argCompletions() map[*cobra.Command]cobra.CompletionFunc {
+	issue := completeIssueID
+	return map[*cobra.Command]cobra.CompletionFunc{
+		// Issues
+		showCmd:            completeArgs(false, issue),
+		updateCmd:          completeArgs(false, issue),
+		closeCmd:           completeArgs(true, issue),
+		cloneCmd:           completeArgs(false, issue),
+		auditCmd:           completeArgs(false, issue),
+		executionsCmd:      completeArgs(false, issue),
+		timelineCmd:        completeArgs(false, issue),
+		enhanceCmd:         completeArgs(false, issue),
+		pauseCmd:           completeArgs(false, issue),
+		resumeCmd:          completeArgs(false, issue),
+		backportCmd:        completeArgs(false, issue),
+		tailCmd:            completeArgs(false, issue),
+		depAddCmd:          completeArgs(false, issue, issue),
+		depRemoveCmd:       completeArgs(false, issue, issue),
+		depTreeCmd:         completeArgs(false, issue),
+		depBlockersCmd:     completeArgs(false, issue),
+		commentListCmd:     completeArgs(false, issue),
+		commentAddCmd:      completeArgs(false, issue),
+		attachmentListCmd:  completeArgs(false, issue),
+		attachmentAddCmd:   completeArgs(false, issue, defaultFileCompletion),
+		fieldSetCmd:        completeArgs(false, issue),
+		prListCmd:          completeArgs(false, issue),
+		prChecksCmd:        completeArgs(false, issue),
+		prCommentCmd:       completeArgs(false, issue),
+		prMergeCmd:         completeArgs(false, issue),
+		projectAssignCmd:   completeArgs(true, completeProject, issue),
+		projectUnassignCmd: completeArgs(true, issue),
+		planGenerateCmd:    completeArgs(false, issue),
+
+		// Plans and missions
+		planShowCmd:     completeArgs(false, completePlan),
+		planRefineCmd:   completeArgs(false, completePlan),
+		planValidateCmd: completeArgs(false, completePlan),
+		planApproveCmd:  completeArgs(false, completePlan),
+		releaseCmd:      completeArgs(false, completeMissionID),
+		missionsCmd:     completeArgs(true, completeMissionID),
+
+		// Labels, projects and filters
+		labelDefineCmd:   completeArgs(false, completeLabel),
+		labelRenameCmd:   completeArgs(false, completeLabel),
+		labelDeleteCmd:   completeArgs(false, completeLabel),
+		projectDefineCmd: completeArgs(false, completeProject),
+		filterDeleteCmd:  completeArgs(false, completeSavedFilter),
+
+		// Schedules
+		scheduleDeleteCmd:  completeArgs(false, completeSchedule),
+		scheduleEnableCmd:  completeArgs(false, completeSchedule),
+		scheduleDisableCmd: completeArgs(false, completeSchedule),
+		scheduleRunCmd:     completeArgs(false, completeSchedule),
+	}
+}
+
+// flagCompletions sets how flags complete, by command and flag name
+func flagCompletions() map[*cobra.Command]map[string]cobra.CompletionFunc {
+	return map[*cobra.Command]map[string]cobra.CompletionFunc{
+		rootCmd:         {"profile": completeProfile},
+		createCmd:       {"labels": completeLabel, "type": completeIssueType, "priority": completePriority},
+		listCmd:         {"label": completeLabel, "project": completeProject, "filter": completeSavedFilter, "status": completeStatus, "type": completeIssueType, "priority": completePriority},
+		exportCmd:       {"label": completeLabel, "project": completeProject, "filter": completeSavedFilter, "status": completeStatus, "type": completeIssueType, "priority": completePriority, "table": completeExportTable},
+		updateCmd:       {"status": completeStatus, "priority": completePriority},
+		readyCmd:        {"project": completeProject, "priority": completePriority},
+		executeCmd:      {"project": completeProject, "issue": completeIssueID},
+		scheduleAddCmd:  {"labels": completeLabel, "project": completeProject, "type": completeIssueType, "priority": completePriority},
+		fieldDefineCmd:  {"project": completeProject},
+		fieldDeleteCmd:  {"project": completeProject},
+		gatesExplainCmd: {"issue": completeIssueID},
+		activityCmd:     {"issue": completeIssueID},
+		tailCmd:         {"issue": completeIssueID},
+		watchCmd:        {"issue": completeIssueID},
+		statusCmd:       {"epic": completeEpicID},
+		githubSyncCmd:   {"prefer": completeGitHubSyncPrefer},
+		gitlabSyncCmd:   {"prefer": completeGitLabSyncPrefer},
+	}
+}
+
+// defaultFileCompletion completes file names, for arguments that are files
+func defaultFileCompletion(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveDefault
+}
+
+// registerCompletions attaches the completion functions. It runs once every
+// command's flags are defined.
+func registerCompletions() {
+	for cmd, complete := range argCompletions() {
+		cmd.ValidArgsFunction = complete
+	}
+	for cmd, flags := range flagCompletions() {
+		for name, complete := range flags {
+			if err := cmd.RegisterFlagCompletionFunc(name, complete); err != nil {
+				panic(err) // A flag was renamed without updating flagCompletions
+			}
+		}
+	}
+}
+
+// isCompletionCmd reports whether cmd is 'vc completion' or one of its shell
+// subcommands, which print scripts and don't need a database
+func isCompletionCmd(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Name() == "completion" && c.Parent() != nil && !c.Parent().HasParent() {
+			return true
+		}
+	}
+	return false
+}
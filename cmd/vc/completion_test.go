@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestEveryCommandHasExamples(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, sub := range cmd.Commands() {
+			// Cobra's own help and completion commands have their own help
+			if sub.Hidden || sub.Name() == "help" || sub.Name() == "completion" {
+				continue
+			}
+			if sub.Example == "" {
+				t.Errorf("'%s' has no Example", sub.CommandPath())
+			}
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
+
+func TestCompleteArgs(t *testing.T) {
+	first := cobra.FixedCompletions([]cobra.Completion{"a"}, cobra.ShellCompDirectiveNoFileComp)
+	second := cobra.FixedCompletions([]cobra.Completion{"b"}, cobra.ShellCompDirectiveNoFileComp)
+
+	complete := completeArgs(false, first, second)
+	for args, want := range map[int]string{0: "a", 1: "b", 2: ""} {
+		got, _ := complete(nil, make([]string, args), "")
+		if (want == "" && len(got) != 0) || (want != "" && (len(got) != 1 || got[0] != want)) {
+			t.Errorf("completing argument %d = %v, want %q", args+1, got, want)
+		}
+	}
+
+	repeated := completeArgs(true, first, second)
+	if got, _ := repeated(nil, make([]string, 5), ""); len(got) != 1 || got[0] != "b" {
+		t.Errorf("completing a repeated argument = %v, want b", got)
+	}
+}
+
+func TestCompletionsWithoutDatabase(t *testing.T) {
+	originalStore := store
+	store = nil
+	defer func() { store = originalStore }()
+
+	got, directive := completeIssueID(nil, nil, "")
+	if len(got) != 0 || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("completeIssueID() without a database = %v, %v", got, directive)
+	}
+}
+
+func TestRegisterCompletions(t *testing.T) {
+	// Panics if flagCompletions names a flag a command doesn't have
+	registerCompletions()
+	if showCmd.ValidArgsFunction == nil {
+		t.Error("vc show has no argument completion")
+	}
+}
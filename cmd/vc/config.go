@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+)
+
+var (
+	// configPath is the --config flag
+	configPath string
+
+	// profileName is the --profile flag
+	profileName string
+
+	// configFile is the applied config file (nil if the project has none)
+	configFile *config.File
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show VC's configuration",
+	Long: `VC is configured by settings that can be set three ways, each overriding
+the one before:
+
+  1. .vc/config.yaml in the project (or the file given with --config)
+  2. VC_* environment variables
+  3. Command-line flags, for the settings a command has flags for
+
+The file uses the environment variable names without the VC_ prefix, in lower
+case, and can nest them:
+
+  model_default: claude-sonnet-4-5-20250929
+  enable_auto_commit: true
+  cost:
+    enabled: true            # VC_COST_ENABLED
+    max_cost_per_hour: 5     # VC_COST_MAX_COST_PER_HOUR
+  webhook:
+    url: https://hooks.example.com/vc
+    events: [gate.failed, escalation]
+
+Named profiles override some of the file's settings, e.g. to drive a
+production and a development database with their own budgets and gates:
+
+  profiles:
+    prod:
+      db_path: /srv/vc/prod.db
+      gates_config: .vc/gates-strict.yaml
+      cost: {max_cost_per_hour: 20}
+    dev:
+      agent_provider: amp
+
+Select one with --profile or VC_PROFILE. The environment and flags still
+override the profile's settings.
+
+Secrets (passphrases, tokens and passwords) are only read from the
+environment. See docs/CONFIGURATION.md for every setting.`,
+	Example: `  vc config show
+  vc --config ~/vc-ci.yaml config show
+  vc --profile prod config show`,
+}
+
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List the profiles in the config file",
+	Long: `List the named profiles in .vc/config.yaml (or the --config file) and the
+settings each overrides. The profile in use is marked.`,
+	Example: `  vc config profiles`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if configFile == nil {
+			fmt.Println("No config file (create .vc/config.yaml to add profiles)")
+			return
+		}
+		names := configFile.ProfileNames()
+		if len(names) == 0 {
+			fmt.Printf("%s defines no profiles\n", configFile.Path)
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		green := color.New(color.FgGreen).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\nProfiles in %s:\n", configFile.Path)
+		for _, name := range names {
+			marker := "  "
+			if name == configFile.Profile {
+				marker = green("* ")
+			}
+			fmt.Printf("\n%s%s\n", marker, cyan(name))
+			values := configFile.Profiles[name]
+			envs := make([]string, 0, len(values))
+			for env := range values {
+				envs = append(envs, env)
+			}
+			sort.Strings(envs)
+			for _, env := range envs {
+				fmt.Printf("    %s = %s\n", env, values[env])
+			}
+			if len(envs) == 0 {
+				fmt.Printf("    %s\n", gray("(no settings)"))
+			}
+		}
+		fmt.Println()
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration and where each setting comes from",
+	Long: `Show the effective configuration: each setting's value and whether it
+comes from .vc/config.yaml or the environment. Settings left at their
+defaults are hidden unless --all is given; secrets are masked.
+
+The settings are also validated, and the command exits 1 if any are invalid.`,
+	Example: `  vc config show
+  vc config show --all
+  vc config show --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		format, _ := cmd.Flags().GetString("format")
+		if jsonOutput {
+			format = "json"
+		}
+		if format != "text" && format != "json" {
+			exitWithError(exitError, fmt.Errorf("unknown format %q (want text or json)", format))
+		}
+
+		var settings []config.EffectiveSetting
+		for _, s := range config.Effective(configFile) {
+			if all || s.Source != config.SourceDefault {
+				settings = append(settings, s)
+			}
+		}
+		var problems []string
+		for _, check := range envConfigChecks() {
+			if err := check.validate(); err != nil {
+				problems = append(problems, fmt.Sprintf("%s settings: %v", check.name, err))
+			}
+		}
+
+		if format == "json" {
+			printConfigJSON(settings, problems)
+		} else {
+			printConfig(settings, problems)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configShowCmd.Flags().Bool("all", false, "Also show settings left at their defaults")
+	configShowCmd.Flags().String("format", "text", "Output format: text or json (same as --json)")
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configProfilesCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// findConfigFile returns the --config file, or else the config file of the
+// project the database (or working directory) is in, or "" if there is none
+func findConfigFile() (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if dbPath != "" {
+		dir = filepath.Dir(filepath.Dir(dbPath))
+	}
+	return config.FindFile(dir), nil
+}
+
+// selectedProfile returns the profile chosen with --profile or VC_PROFILE
+func selectedProfile() string {
+	if profileName != "" {
+		return profileName
+	}
+	return os.Getenv("VC_PROFILE")
+}
+
+// loadConfigFile applies the config file, with the selected profile's
+// settings layered over its own
+func loadConfigFile() error {
+	profile := selectedProfile()
+	path, err := findConfigFile()
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		if profile != "" {
+			return fmt.Errorf("profile %q selected, but there is no config file to define it (create .vc/config.yaml)", profile)
+		}
+		return nil
+	}
+	file, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	if profile != "" {
+		if err := file.UseProfile(profile); err != nil {
+			return err
+		}
+	}
+	if err := file.Apply(); err != nil {
+		return err
+	}
+	configFile = file
+	return nil
+}
+
+// printConfig prints settings as a table, followed by any problems
+func printConfig(settings []config.EffectiveSetting, problems []string) {
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	fmt.Printf("\n%s\n\n", cyan("=== Effective Configuration ==="))
+	if configFile != nil {
+		fmt.Printf("Config file: %s\n", configFile.Path)
+	} else {
+		fmt.Printf("Config file: none %s\n", gray("(create .vc/config.yaml to add one)"))
+	}
+	if configFile != nil && configFile.Profile != "" {
+		fmt.Printf("Profile:     %s\n", configFile.Profile)
+	}
+	fmt.Printf("Precedence:  flags > environment > profile > config file > defaults\n\n")
+
+	if len(settings) == 0 {
+		fmt.Printf("All settings are at their defaults %s\n", gray("(see --all)"))
+	} else {
+		width := 0
+		for _, s := range settings {
+			width = max(width, len(s.Env))
+		}
+		for _, s := range settings {
+			source := string(s.Source)
+			if s.FileValue != "" {
+				source += fmt.Sprintf(", overrides file value %q", s.FileValue)
+			}
+			value := s.Display()
+			if s.Source == config.SourceDefault {
+				value = "-"
+			}
+			fmt.Printf("%-*s  %s  %s\n", width, s.Env, value, gray("("+source+")"))
+		}
+	}
+
+	if len(problems) > 0 {
+		fmt.Printf("\n%s Invalid settings:\n", red("✗"))
+		for _, p := range problems {
+			fmt.Printf("  • %s\n", p)
+		}
+	}
+	fmt.Println()
+}
+
+// configSettingJSON is a setting in 'vc config show --format json'
+type configSettingJSON struct {
+	Env       string `json:"env"`
+	Key       string `json:"key"`
+	Value     string `json:"value,omitempty"`
+	Source    string `json:"source"`
+	FileValue string `json:"file_value,omitempty"`
+	Secret    bool   `json:"secret,omitempty"`
+}
+
+// printConfigJSON prints settings and problems as JSON
+func printConfigJSON(settings []config.EffectiveSetting, problems []string) {
+	out := struct {
+		File     string              `json:"file,omitempty"`
+		Profile  string              `json:"profile,omitempty"`
+		Settings []configSettingJSON `json:"settings"`
+		Problems []string            `json:"problems,omitempty"`
+	}{Settings: []configSettingJSON{}, Problems: problems}
+	if configFile != nil {
+		out.File = configFile.Path
+		out.Profile = configFile.Profile
+	}
+	for _, s := range settings {
+		fileValue := s.FileValue
+		if s.Secret {
+			fileValue = ""
+		}
+		out.Settings = append(out.Settings, configSettingJSON{
+			Env:       s.Env,
+			Key:       s.Key(),
+			Value:     s.Display(),
+			Source:    string(s.Source),
+			FileValue: fileValue,
+			Secret:    s.Secret,
+		})
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		exitWithError(exitError, err)
+	}
+}
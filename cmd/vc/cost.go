@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -11,9 +17,44 @@ import (
 
 var costCmd = &cobra.Command{
 	Use:   "cost",
-	Short: "Show AI cost budget and usage statistics",
-	Long:  `Display current AI cost budget status, usage statistics, and spending history.`,
+	Short: "Show AI cost budget and usage statistics, or report spend",
+	Long: `Display current AI cost budget status, usage statistics, and spending history.
+
+With --by, --since, --until or --format, report what was spent instead:
+supervisor AI calls and agent executions, broken down by day, issue,
+mission, operation, model or provider ("supervisor" for AI supervisor spend,
+the agent's provider for agent spend). Agents don't report tokens or models,
+so executions count towards cost only and have no model.
+
+With --json, prints the budget status as JSON and exits 4 if the budget is
+exceeded; with report flags, --json is the same as --format json.`,
+	Example: `  # Spend per day over the last 30 days
+  vc cost --by day
+
+  # Spend per mission in March, as CSV for finance
+  vc cost --by mission --since 2026-03-01 --until 2026-04-01 --format csv
+
+  # Supervisor vs agent spend as JSON
+  vc cost --by provider --json
+
+  # Budget status for a script
+  vc cost --json | jq .status`,
 	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		if flags.Changed("by") || flags.Changed("since") || flags.Changed("until") || flags.Changed("format") {
+			runCostReport(cmd)
+			return
+		}
+
+		if jsonOutput {
+			budget := newBudgetJSON()
+			printJSON(budget)
+			if budget.exceeded() {
+				os.Exit(exitBudgetExceeded)
+			}
+			return
+		}
+
 		// Load cost configuration
 		cfg := cost.LoadFromEnv()
 
@@ -26,8 +67,7 @@ var costCmd = &cobra.Command{
 		// Initialize cost tracker
 		tracker, err := cost.NewTracker(cfg, store)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to initialize cost tracker: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, fmt.Errorf("failed to initialize cost tracker: %w", err))
 		}
 
 		// Get current stats
@@ -117,9 +157,136 @@ var costCmd = &cobra.Command{
 }
 
 func init() {
+	costCmd.Flags().String("by", "day", "Break spend down by: day, issue, mission, operation, model or provider")
+	costCmd.Flags().String("since", "", "Report spend from this date, YYYY-MM-DD (default: 30 days ago)")
+	costCmd.Flags().String("until", "", "Report spend before this date, YYYY-MM-DD (default: now)")
+	costCmd.Flags().String("format", "text", "Output format: text, csv or json")
 	rootCmd.AddCommand(costCmd)
 }
 
+// runCostReport reports spend broken down as the flags ask
+func runCostReport(cmd *cobra.Command) {
+	byFlag, _ := cmd.Flags().GetString("by")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	untilFlag, _ := cmd.Flags().GetString("until")
+	format, _ := cmd.Flags().GetString("format")
+	if jsonOutput {
+		if cmd.Flags().Changed("format") && format != "json" {
+			exitWithError(exitError, fmt.Errorf("--json can't be combined with --format %s", format))
+		}
+		format = "json"
+	}
+
+	by, err := cost.ParseDimension(byFlag)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+	if format != "text" && format != "csv" && format != "json" {
+		exitWithError(exitError, fmt.Errorf("unknown format %q (want text, csv or json)", format))
+	}
+	today := time.Now()
+	since := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -30)
+	if sinceFlag != "" {
+		if since, err = time.ParseInLocation("2006-01-02", sinceFlag, time.Local); err != nil {
+			exitWithError(exitError, fmt.Errorf("invalid --since date %q (want YYYY-MM-DD)", sinceFlag))
+		}
+	}
+	var until time.Time
+	if untilFlag != "" {
+		if until, err = time.ParseInLocation("2006-01-02", untilFlag, time.Local); err != nil {
+			exitWithError(exitError, fmt.Errorf("invalid --until date %q (want YYYY-MM-DD)", untilFlag))
+		}
+		if !until.After(since) {
+			exitWithError(exitError, fmt.Errorf("--until must be after --since"))
+		}
+	}
+
+	spend, err := cost.LoadSpend(context.Background(), store, since, until)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+	report := cost.BuildReport(spend, by, since, until)
+
+	switch format {
+	case "json":
+		printJSON(report)
+	case "csv":
+		err = writeCostCSV(os.Stdout, report)
+	default:
+		printCostReport(report)
+	}
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+}
+
+// writeCostCSV writes a report's rows as CSV, without a total row so the
+// file can be summed as is
+func writeCostCSV(w io.Writer, report *cost.Report) error {
+	withTitle := report.By == cost.ByIssue || report.By == cost.ByMission
+	header := []string{string(report.By)}
+	if withTitle {
+		header = append(header, "title")
+	}
+	header = append(header, "count", "input_tokens", "output_tokens", "cost_usd")
+
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, row := range report.Rows {
+		record := []string{row.Key}
+		if withTitle {
+			record = append(record, row.Title)
+		}
+		record = append(record,
+			strconv.Itoa(row.Count),
+			strconv.FormatInt(row.InputTokens, 10),
+			strconv.FormatInt(row.OutputTokens, 10),
+			strconv.FormatFloat(row.CostUSD, 'f', 4, 64))
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// printCostReport prints a report as a table
+func printCostReport(report *cost.Report) {
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	period := report.Since.Format("2006-01-02") + " → "
+	if report.Until.IsZero() {
+		period += "now"
+	} else {
+		period += report.Until.Format("2006-01-02")
+	}
+	fmt.Printf("\n%s\n", cyan(fmt.Sprintf("=== AI Spend by %s (%s) ===", report.By, period)))
+	fmt.Println()
+
+	if len(report.Rows) == 0 {
+		fmt.Println(gray("No spend recorded in this period"))
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("  %-40s %7s %9s %9s %11s\n", strings.ToUpper(string(report.By)), "COUNT", "INPUT", "OUTPUT", "COST")
+	for _, row := range report.Rows {
+		label := row.Key
+		if row.Title != "" {
+			label += " " + row.Title
+		}
+		fmt.Printf("  %-40s %7d %9s %9s %11s\n", truncateString(label, 40), row.Count,
+			formatTokens(row.InputTokens), formatTokens(row.OutputTokens), fmt.Sprintf("$%.4f", row.CostUSD))
+	}
+	total := report.Total
+	fmt.Printf("  %-40s %7d %9s %9s %11s\n", "TOTAL", total.Count,
+		formatTokens(total.InputTokens), formatTokens(total.OutputTokens), fmt.Sprintf("$%.4f", total.CostUSD))
+	fmt.Println()
+}
+
 // formatTokens formats a token count with commas for readability
 func formatTokens(tokens int64) string {
 	if tokens < 1000 {
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Work the ready queue continuously, with several workers and a budget",
+	Long: `Run the executor as a long-lived service: claim ready issues, execute them,
+handle their results and claim the next, until stopped with Ctrl+C or SIGTERM.
+
+vc daemon takes every 'vc execute' flag, and adds:
+  --workers N             Work on up to N issues at once, each in its own
+                          sandbox and registered as its own executor instance
+  --idle-backoff D        While the ready queue is empty, double the poll
+                          interval after each empty poll, up to D; polling
+                          drops back to --poll-interval once work turns up
+  --max-cost-per-hour     Pause all workers once this much is spent on AI in
+  --max-tokens-per-hour   the budget window, resuming when it resets
+
+The workers share one cost budget, and only the first serves the control
+socket used by 'vc pause'. Use --health-addr to let systemd or Kubernetes
+supervise the daemon.`,
+	Example: `  # Two workers committing passing work, within $5 an hour
+  vc daemon --workers 2 --enable-auto-commit --max-cost-per-hour 5
+
+  # Under Kubernetes, with health checks
+  vc daemon --workers 4 --enable-auto-commit --health-addr 0.0.0.0:7392`,
+	Run: func(cmd *cobra.Command, args []string) {
+		daemonCfg, err := config.DaemonConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if cmd.Flags().Changed("workers") {
+			daemonCfg.Workers, _ = cmd.Flags().GetInt("workers")
+		}
+		if cmd.Flags().Changed("idle-backoff") {
+			daemonCfg.IdleBackoff, _ = cmd.Flags().GetDuration("idle-backoff")
+		}
+		if err := daemonCfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// runExecutor reads the settled values from the flags
+		_ = cmd.Flags().Set("workers", strconv.Itoa(daemonCfg.Workers))
+		_ = cmd.Flags().Set("idle-backoff", daemonCfg.IdleBackoff.String())
+		if err := runExecutor(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	addWorkLoopFlags(daemonCmd)
+	daemonCmd.Flags().Int("workers", 0, "Number of issues to work on at once, each in its own sandbox (default: $VC_DAEMON_WORKERS or 1)")
+	daemonCmd.Flags().Duration("idle-backoff", 0, "Longest poll interval to back off to while the ready queue is empty, 0 to poll steadily (default: $VC_DAEMON_IDLE_BACKOFF or 5m)")
+	daemonCmd.Flags().Float64("max-cost-per-hour", 0, "Pause work once this many dollars are spent on AI per budget window (overrides VC_COST_MAX_COST_PER_HOUR)")
+	daemonCmd.Flags().Int64("max-tokens-per-hour", 0, "Pause work once this many AI tokens are used per budget window (overrides VC_COST_MAX_TOKENS_PER_HOUR)")
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -13,12 +13,18 @@ import (
 var depCmd = &cobra.Command{
 	Use:   "dep",
 	Short: "Manage dependencies",
+	Example: `  vc dep add vc-42 vc-41             # vc-42 is blocked by vc-41
+  vc dep tree vc-42
+  vc dep cycles`,
 }
 
 var depAddCmd = &cobra.Command{
 	Use:   "add [issue-id] [depends-on-id]",
 	Short: "Add a dependency",
-	Args:  cobra.ExactArgs(2),
+	Example: `  vc dep add vc-42 vc-41                       # vc-42 is blocked by vc-41
+  vc dep add vc-42 vc-40 --type related
+  vc dep add vc-42 vc-10 --type parent-child   # vc-42 is a child of vc-10`,
+	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		depType, _ := cmd.Flags().GetString("type")
 
@@ -41,9 +47,10 @@ var depAddCmd = &cobra.Command{
 }
 
 var depRemoveCmd = &cobra.Command{
-	Use:   "remove [issue-id] [depends-on-id]",
-	Short: "Remove a dependency",
-	Args:  cobra.ExactArgs(2),
+	Use:     "remove [issue-id] [depends-on-id]",
+	Short:   "Remove a dependency",
+	Example: `  vc dep remove vc-42 vc-41`,
+	Args:    cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
 		if err := store.RemoveDependency(ctx, args[0], args[1], actor); err != nil {
@@ -58,9 +65,10 @@ var depRemoveCmd = &cobra.Command{
 }
 
 var depTreeCmd = &cobra.Command{
-	Use:   "tree [issue-id]",
-	Short: "Show dependency tree",
-	Args:  cobra.ExactArgs(1),
+	Use:     "tree [issue-id]",
+	Short:   "Show dependency tree",
+	Example: `  vc dep tree vc-42`,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
 		tree, err := store.GetDependencyTree(ctx, args[0], 50)
@@ -100,8 +108,9 @@ var depTreeCmd = &cobra.Command{
 }
 
 var depCyclesCmd = &cobra.Command{
-	Use:   "cycles",
-	Short: "Detect dependency cycles",
+	Use:     "cycles",
+	Short:   "Detect dependency cycles",
+	Example: `  vc dep cycles`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
 		cycles, err := store.DetectCycles(ctx)
@@ -128,11 +137,53 @@ var depCyclesCmd = &cobra.Command{
 	},
 }
 
+var depBlockersCmd = &cobra.Command{
+	Use:   "blockers [issue-id]",
+	Short: "Show what blocks an issue and what it blocks",
+	Long: `Show the unclosed issues blocking an issue and the unclosed issues it blocks.
+
+Only 'blocks' dependencies gate ordering; related, parent-child and
+discovered-from links are not shown.`,
+	Example: `  vc dep blockers vc-42`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		blockers, err := store.GetBlockers(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		blocked, err := store.GetBlocked(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("\n%s is blocked by:\n", args[0])
+		if len(blockers) == 0 {
+			fmt.Println("  (nothing)")
+		}
+		for _, issue := range blockers {
+			fmt.Printf("  → %s: %s [P%d] (%s)\n", issue.ID, issue.Title, issue.Priority, issue.Status)
+		}
+
+		fmt.Printf("\n%s blocks:\n", args[0])
+		if len(blocked) == 0 {
+			fmt.Println("  (nothing)")
+		}
+		for _, issue := range blocked {
+			fmt.Printf("  → %s: %s [P%d] (%s)\n", issue.ID, issue.Title, issue.Priority, issue.Status)
+		}
+		fmt.Println()
+	},
+}
+
 func init() {
 	depAddCmd.Flags().StringP("type", "t", "blocks", "Dependency type (blocks|related|parent-child)")
 	depCmd.AddCommand(depAddCmd)
 	depCmd.AddCommand(depRemoveCmd)
 	depCmd.AddCommand(depTreeCmd)
 	depCmd.AddCommand(depCyclesCmd)
+	depCmd.AddCommand(depBlockersCmd)
 	rootCmd.AddCommand(depCmd)
 }
@@ -34,17 +34,17 @@ Discovery workers scan the code for common problems like:
 - Missing tests and documentation
 - Security vulnerabilities
 - Common bug patterns
+- TODO/FIXME comments and unchecked Markdown tasks (opt-in: --workers=todos)
 
 Workers can be run individually or via presets:
 - quick:    Fast scan, minimal AI usage (~30s, $0.50)
 - standard: Comprehensive scan (~5min, $2.00)
-- thorough: Deep analysis, all workers (~15min, $10.00)
-
-Examples:
-  vc discover                              # Run standard preset
+- thorough: Deep analysis, all workers (~15min, $10.00)`,
+	Example: `  vc discover                              # Run standard preset
   vc discover --preset=quick               # Run quick preset
   vc discover --preset=thorough            # Run thorough preset
   vc discover --workers=filesize,cruft     # Run specific workers
+  vc discover --workers=todos              # File issues for TODO/FIXME comments
   vc discover --dry-run                    # Preview without filing issues
   vc discover --list                       # List available workers`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -2,16 +2,26 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/executor"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/schedule"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/storage/memory"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -25,14 +35,24 @@ This command checks for:
 - Database staleness (sync with issues.jsonl)
 - WAL mode timestamp sync issues
 - Beads daemon conflicts
-- Required environment variables
-- Git repository status
+- API keys and integration settings
+- Git repository status and identity
 - Sandbox directory permissions
+- Database schema version
+- Coding agent binaries and versions
+- Claude CLI authentication
+- Tools the quality gates run
+- Free disk space
+
+Every problem found is listed with how to fix it.
 
 Exit codes:
   0 - All checks passed
   1 - One or more checks failed (but not critical)
   2 - Critical failures that prevent VC from running`,
+	Example: `  vc doctor          # Run every check
+  vc doctor -v       # Include details such as tool paths and versions
+  vc doctor --fix    # Also fix what can be fixed automatically`,
 	Run: func(cmd *cobra.Command, args []string) {
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		fixIssues, _ := cmd.Flags().GetBool("fix")
@@ -44,15 +64,16 @@ Exit codes:
 
 		fmt.Printf("Running VC health checks...\n\n")
 
-		var failures []string
-		var warnings []string
-		var criticalFailures []string
+		var failures []doctorProblem
+		var warnings []doctorProblem
+		var criticalFailures []doctorProblem
 
 		// Check 1: Database discovery
 		fmt.Printf("%s Database discovery\n", cyan("→"))
 		if dbPath == "" {
 			if discoveredPath, err := storage.DiscoverDatabase(); err != nil {
-				criticalFailures = append(criticalFailures, fmt.Sprintf("No database found: %v", err))
+				criticalFailures = append(criticalFailures, doctorProblem{fmt.Sprintf("No database found: %v", err),
+					"Run 'vc init' in the project, or point VC_DB_PATH at the database"})
 				fmt.Printf("  %s No database found\n", red("✗"))
 				if verbose {
 					fmt.Printf("    Error: %v\n", err)
@@ -67,13 +88,14 @@ Exit codes:
 
 		if dbPath == "" {
 			fmt.Printf("\n%s Critical failures prevent VC from running\n", red("✗"))
-			os.Exit(2)
+			os.Exit(exitCritical)
 		}
 
 		// Check 2: Database file accessibility
 		fmt.Printf("%s Database file access\n", cyan("→"))
 		if info, err := os.Stat(dbPath); err != nil {
-			criticalFailures = append(criticalFailures, fmt.Sprintf("Cannot access database: %v", err))
+			criticalFailures = append(criticalFailures, doctorProblem{fmt.Sprintf("Cannot access database: %v", err),
+				"Check the path and its permissions: ls -l " + dbPath})
 			fmt.Printf("  %s Cannot access database file\n", red("✗"))
 			if verbose {
 				fmt.Printf("    Error: %v\n", err)
@@ -81,7 +103,7 @@ Exit codes:
 		} else {
 			fmt.Printf("  %s Database file accessible (%d bytes)\n", green("✓"), info.Size())
 			if info.Size() == 0 {
-				warnings = append(warnings, "Database file is empty (0 bytes)")
+				warnings = append(warnings, doctorProblem{"Database file is empty (0 bytes)", "Run any vc command (e.g. 'vc ready') to initialize it"})
 				fmt.Printf("  %s WARNING: Database is empty\n", yellow("⚠"))
 			}
 		}
@@ -90,7 +112,8 @@ Exit codes:
 		fmt.Printf("%s Project structure\n", cyan("→"))
 		projectRoot, err := storage.GetProjectRoot(dbPath)
 		if err != nil {
-			failures = append(failures, fmt.Sprintf("Invalid project structure: %v", err))
+			failures = append(failures, doctorProblem{fmt.Sprintf("Invalid project structure: %v", err),
+				"Keep the database in <project>/.beads/ and run vc from the project"})
 			fmt.Printf("  %s Invalid project structure\n", red("✗"))
 			if verbose {
 				fmt.Printf("    Error: %v\n", err)
@@ -100,7 +123,7 @@ Exit codes:
 
 			cwd, _ := os.Getwd()
 			if err := storage.ValidateAlignment(dbPath, cwd); err != nil {
-				failures = append(failures, "Database-working directory mismatch")
+				failures = append(failures, doctorProblem{"Database-working directory mismatch", "cd " + projectRoot})
 				fmt.Printf("  %s Working directory not aligned with database\n", yellow("⚠"))
 				if verbose {
 					fmt.Printf("    Error: %v\n", err)
@@ -114,7 +137,7 @@ Exit codes:
 		fmt.Printf("%s Database freshness\n", cyan("→"))
 		if err := storage.ValidateDatabaseFreshness(dbPath); err != nil {
 			if strings.Contains(err.Error(), "stale by") {
-				failures = append(failures, "Database is stale (needs bd import)")
+				failures = append(failures, doctorProblem{"Database is stale (needs bd import)", "vc doctor --fix"})
 				fmt.Printf("  %s Database is out of sync with issues.jsonl\n", red("✗"))
 				if verbose {
 					fmt.Printf("    %v\n", err)
@@ -152,7 +175,8 @@ Exit codes:
 
 			// Check if WAL is much newer than main DB (indicates checkpoint needed)
 			if walInfo.ModTime().Sub(dbInfo.ModTime()) > 5*time.Minute {
-				warnings = append(warnings, "WAL file significantly newer than main DB (consider PRAGMA wal_checkpoint)")
+				warnings = append(warnings, doctorProblem{"WAL file significantly newer than main DB",
+					fmt.Sprintf("sqlite3 %s 'PRAGMA wal_checkpoint(TRUNCATE)' (with no executor running)", dbPath)})
 				fmt.Printf("  %s WAL file significantly newer than main DB\n", yellow("⚠"))
 			}
 		} else {
@@ -162,33 +186,47 @@ Exit codes:
 		// Check 6: Beads daemon conflicts
 		fmt.Printf("%s Beads daemon status\n", cyan("→"))
 		if isBeadsDaemonRunning() {
-			warnings = append(warnings, "Beads daemon is running (may conflict with VC)")
+			warnings = append(warnings, doctorProblem{"Beads daemon is running (may conflict with VC)", "pkill -f 'bd daemon'"})
 			fmt.Printf("  %s Beads daemon detected (may cause conflicts)\n", yellow("⚠"))
-			fmt.Printf("    Recommendation: Stop bd daemon before running VC\n")
-			fmt.Printf("    Command: pkill -f 'bd daemon'\n")
 		} else {
 			fmt.Printf("  %s No beads daemon detected\n", green("✓"))
 		}
 
-		// Check 7: Required environment variables
-		fmt.Printf("%s Environment variables\n", cyan("→"))
+		// Check 7: API keys and integration settings
+		fmt.Printf("%s API keys and configuration\n", cyan("→"))
 		if apiKey := os.Getenv("ANTHROPIC_API_KEY"); apiKey == "" {
-			failures = append(failures, "ANTHROPIC_API_KEY not set")
+			failures = append(failures, doctorProblem{"ANTHROPIC_API_KEY not set",
+				"export ANTHROPIC_API_KEY=<key> (create one at https://console.anthropic.com/settings/keys)"})
 			fmt.Printf("  %s ANTHROPIC_API_KEY not set\n", red("✗"))
 			fmt.Printf("    AI supervision features will not work\n")
 		} else {
 			fmt.Printf("  %s ANTHROPIC_API_KEY is set\n", green("✓"))
-			if verbose {
+			if verbose && len(apiKey) > 14 {
 				fmt.Printf("    Key: %s...%s\n", apiKey[:10], apiKey[len(apiKey)-4:])
 			}
 		}
+		if hosting, err := config.HostingConfigFromEnv(); err == nil && hosting.Enabled() {
+			fmt.Printf("  %s Hosting token is set (pull requests enabled)\n", green("✓"))
+		} else if verbose {
+			fmt.Printf("    No GitHub or GitLab token set (pull requests disabled)\n")
+		}
+		for _, check := range envConfigChecks() {
+			if err := check.validate(); err != nil {
+				failures = append(failures, doctorProblem{fmt.Sprintf("Invalid %s settings: %v", check.name, err),
+					fmt.Sprintf("Fix the %s variables (see docs/CONFIGURATION.md)", check.vars)})
+				fmt.Printf("  %s Invalid %s settings\n", red("✗"), check.name)
+				if verbose {
+					fmt.Printf("    Error: %v\n", err)
+				}
+			}
+		}
 
 		// Check 8: Git repository status
 		fmt.Printf("%s Git repository\n", cyan("→"))
 		if projectRoot != "" {
 			gitDir := filepath.Join(projectRoot, ".git")
 			if _, err := os.Stat(gitDir); err != nil {
-				warnings = append(warnings, "Not a git repository")
+				warnings = append(warnings, doctorProblem{"Not a git repository", "git init && git add -A && git commit -m 'Initial commit'"})
 				fmt.Printf("  %s Not a git repository\n", yellow("⚠"))
 			} else {
 				fmt.Printf("  %s Git repository detected\n", green("✓"))
@@ -213,6 +251,27 @@ Exit codes:
 						fmt.Printf("  %s Working directory clean\n", green("✓"))
 					}
 				}
+
+				// Agent work is committed as the configured user
+				name := gitOutput(projectRoot, "config", "user.name")
+				email := gitOutput(projectRoot, "config", "user.email")
+				if name == "" || email == "" {
+					failures = append(failures, doctorProblem{"Git user identity not configured (commits of agent work will fail)",
+						"git config --global user.name '<name>' && git config --global user.email '<email>'"})
+					fmt.Printf("  %s Git user identity not configured\n", red("✗"))
+				} else {
+					fmt.Printf("  %s Committing as %s <%s>\n", green("✓"), name, email)
+				}
+
+				if state := gitOperationInProgress(projectRoot); state != "" {
+					failures = append(failures, doctorProblem{fmt.Sprintf("A git %s is in progress", state),
+						fmt.Sprintf("Finish or abort it: git %s --continue or git %s --abort", state, state)})
+					fmt.Printf("  %s A git %s is in progress\n", red("✗"), state)
+				}
+				if gitOutput(projectRoot, "symbolic-ref", "-q", "HEAD") == "" {
+					warnings = append(warnings, doctorProblem{"HEAD is detached (work won't land on a branch)", "git switch <branch>"})
+					fmt.Printf("  %s HEAD is detached\n", yellow("⚠"))
+				}
 			}
 		}
 
@@ -222,14 +281,14 @@ Exit codes:
 			sandboxRoot := filepath.Join(projectRoot, ".sandboxes")
 			if info, err := os.Stat(sandboxRoot); err == nil {
 				if !info.IsDir() {
-					warnings = append(warnings, ".sandboxes exists but is not a directory")
+					warnings = append(warnings, doctorProblem{".sandboxes exists but is not a directory", "mv " + sandboxRoot + " " + sandboxRoot + ".bak"})
 					fmt.Printf("  %s .sandboxes exists but is not a directory\n", yellow("⚠"))
 				} else {
 					// Check permissions
 					if info.Mode().Perm()&0700 == 0700 {
 						fmt.Printf("  %s Sandbox directory exists with correct permissions\n", green("✓"))
 					} else {
-						warnings = append(warnings, ".sandboxes has incorrect permissions")
+						warnings = append(warnings, doctorProblem{".sandboxes has incorrect permissions", "chmod 700 " + sandboxRoot})
 						fmt.Printf("  %s Sandbox directory has incorrect permissions\n", yellow("⚠"))
 						fmt.Printf("    Expected: drwx------ (0700), got: %v\n", info.Mode().Perm())
 					}
@@ -251,18 +310,36 @@ Exit codes:
 			}
 		}
 
-		// Check 10: Database issue count
-		fmt.Printf("%s Database statistics\n", cyan("→"))
+		// Check 10: Database schema and statistics. Opened read-only, so
+		// the check doesn't migrate the schema it is checking.
+		fmt.Printf("%s Database schema and statistics\n", cyan("→"))
 		if projectRoot != "" {
-			// Try to connect and get basic stats
 			cfg := storage.DefaultConfig()
 			cfg.Path = dbPath
+			cfg.ReadOnly = true
 			ctx := context.Background()
 			if store, err := storage.NewStorage(ctx, cfg); err == nil {
+				value, _ := store.GetConfig(ctx, beads.SchemaVersionKey)
+				recorded, _ := strconv.Atoi(value)
+				switch {
+				case recorded > beads.SchemaVersion:
+					failures = append(failures, doctorProblem{
+						fmt.Sprintf("Database schema version %d is newer than this vc supports (%d)", recorded, beads.SchemaVersion),
+						"Upgrade vc to the version that last opened this database"})
+					fmt.Printf("  %s Schema version %d is newer than this vc (%d)\n", red("✗"), recorded, beads.SchemaVersion)
+				case recorded < beads.SchemaVersion:
+					warnings = append(warnings, doctorProblem{
+						fmt.Sprintf("Database schema version %d is older than this vc's (%d)", recorded, beads.SchemaVersion),
+						"Run any vc command (e.g. 'vc ready') to migrate it"})
+					fmt.Printf("  %s Schema version %d needs migrating to %d\n", yellow("⚠"), recorded, beads.SchemaVersion)
+				default:
+					fmt.Printf("  %s Schema version %d is current\n", green("✓"), recorded)
+				}
+
 				// Get issue count using SearchIssues with empty query
 				issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
 				if err != nil {
-					warnings = append(warnings, fmt.Sprintf("Cannot query issues: %v", err))
+					warnings = append(warnings, doctorProblem{fmt.Sprintf("Cannot query issues: %v", err), "Run vc doctor -v for details"})
 					fmt.Printf("  %s Cannot query database\n", yellow("⚠"))
 				} else {
 					fmt.Printf("  %s Database contains %d issue(s)\n", green("✓"), len(issues))
@@ -280,7 +357,14 @@ Exit codes:
 				}
 				store.Close()
 			} else {
-				failures = append(failures, fmt.Sprintf("Cannot connect to database: %v", err))
+				fix := "Check the database with: sqlite3 " + dbPath + " 'PRAGMA integrity_check'"
+				if storage.Passphrase() == "" {
+					fix += " (or set " + beads.PassphraseEnvVar + " if it is encrypted)"
+				}
+				if strings.Contains(err.Error(), "not a VC database yet") {
+					fix = "Run any vc command (e.g. 'vc ready') to initialize it"
+				}
+				failures = append(failures, doctorProblem{fmt.Sprintf("Cannot connect to database: %v", err), fix})
 				fmt.Printf("  %s Cannot connect to database\n", red("✗"))
 				if verbose {
 					fmt.Printf("    Error: %v\n", err)
@@ -288,48 +372,129 @@ Exit codes:
 			}
 		}
 
+		// Check 11: Coding agents
+		fmt.Printf("%s Coding agents\n", cyan("→"))
+		for _, agent := range doctorAgents {
+			path, err := exec.LookPath(agent.binary)
+			if err != nil {
+				problem := doctorProblem{fmt.Sprintf("%s CLI (%s) not found on PATH", agent.name, agent.binary), agent.install}
+				if agent.required {
+					failures = append(failures, problem)
+					fmt.Printf("  %s %s not found\n", red("✗"), agent.name)
+				} else if verbose {
+					fmt.Printf("    %s not installed (optional)\n", agent.name)
+				}
+				continue
+			}
+			version, err := toolVersion(path)
+			if err != nil {
+				failures = append(failures, doctorProblem{fmt.Sprintf("%s CLI at %s doesn't run: %v", agent.name, path, err),
+					"Reinstall it: " + agent.install})
+				fmt.Printf("  %s %s at %s doesn't run\n", red("✗"), agent.name, path)
+				continue
+			}
+			fmt.Printf("  %s %s %s\n", green("✓"), agent.name, version)
+			if verbose {
+				fmt.Printf("    Path: %s\n", path)
+			}
+		}
+
+		// Check 12: Claude CLI authentication
+		fmt.Printf("%s Claude CLI authentication\n", cyan("→"))
+		home, _ := os.UserHomeDir()
+		if source := claudeAuth(home); source != "" {
+			fmt.Printf("  %s Authenticated with %s\n", green("✓"), source)
+		} else {
+			failures = append(failures, doctorProblem{"Claude CLI has no credentials (agents will fail to start)",
+				"Run 'claude' and log in with /login, or export ANTHROPIC_API_KEY"})
+			fmt.Printf("  %s No Claude login or API key found\n", red("✗"))
+		}
+
+		// Check 13: Quality gate tools
+		fmt.Printf("%s Quality gate tools\n", cyan("→"))
+		if projectRoot != "" {
+			tools, err := gateTools(projectRoot)
+			if err != nil {
+				failures = append(failures, doctorProblem{fmt.Sprintf("Invalid gate configuration: %v", err), "Fix .vc/gates.yaml (see 'vc gates explain')"})
+				fmt.Printf("  %s Cannot read gate configuration\n", red("✗"))
+			}
+			for _, tool := range tools {
+				if path, err := gates.ResolveTool(tool.name); err != nil {
+					failures = append(failures, doctorProblem{fmt.Sprintf("%s, needed by the %s gate, not found", tool.name, strings.Join(tool.gates, ", ")),
+						gateToolInstall(tool.name)})
+					fmt.Printf("  %s %s not found (%s gate)\n", red("✗"), tool.name, strings.Join(tool.gates, ", "))
+				} else {
+					fmt.Printf("  %s %s\n", green("✓"), tool.name)
+					if verbose {
+						fmt.Printf("    Path: %s\n", path)
+					}
+				}
+			}
+		}
+
+		// Check 14: Free disk space, for sandboxes, gate builds and agent output
+		fmt.Printf("%s Disk space\n", cyan("→"))
+		checked := map[string]bool{}
+		for _, dir := range []string{projectRoot, os.TempDir()} {
+			if dir == "" || checked[dir] {
+				continue
+			}
+			checked[dir] = true
+			free, err := freeDiskSpace(dir)
+			if err != nil {
+				fmt.Printf("  %s Cannot check free space in %s: %v\n", yellow("⚠"), dir, err)
+				continue
+			}
+			switch {
+			case free < doctorMinFreeDisk:
+				failures = append(failures, doctorProblem{fmt.Sprintf("Only %s free in %s", formatBytes(free), dir),
+					"Free up space (e.g. 'vc cleanup worktrees' removes pooled worktrees)"})
+				fmt.Printf("  %s %s free in %s\n", red("✗"), formatBytes(free), dir)
+			case free < doctorLowFreeDisk:
+				warnings = append(warnings, doctorProblem{fmt.Sprintf("Only %s free in %s", formatBytes(free), dir),
+					"Free up space (e.g. 'vc cleanup worktrees' removes pooled worktrees)"})
+				fmt.Printf("  %s %s free in %s\n", yellow("⚠"), formatBytes(free), dir)
+			default:
+				fmt.Printf("  %s %s free in %s\n", green("✓"), formatBytes(free), dir)
+			}
+		}
+
 		// Summary
 		fmt.Printf("\n%s\n", strings.Repeat("─", 60))
 
 		totalIssues := len(criticalFailures) + len(failures) + len(warnings)
 		if totalIssues == 0 {
 			fmt.Printf("%s All checks passed! VC is ready to run.\n", green("✓"))
-			os.Exit(0)
+			os.Exit(exitOK)
 		}
 
 		if len(criticalFailures) > 0 {
 			fmt.Printf("\n%s Critical failures (%d):\n", red("✗"), len(criticalFailures))
-			for _, failure := range criticalFailures {
-				fmt.Printf("  • %s\n", failure)
-			}
+			printDoctorProblems(criticalFailures)
 		}
 
 		if len(failures) > 0 {
 			fmt.Printf("\n%s Failures (%d):\n", red("✗"), len(failures))
-			for _, failure := range failures {
-				fmt.Printf("  • %s\n", failure)
-			}
+			printDoctorProblems(failures)
 		}
 
 		if len(warnings) > 0 {
 			fmt.Printf("\n%s Warnings (%d):\n", yellow("⚠"), len(warnings))
-			for _, warning := range warnings {
-				fmt.Printf("  • %s\n", warning)
-			}
+			printDoctorProblems(warnings)
 		}
 
 		if len(criticalFailures) > 0 {
 			fmt.Printf("\n%s VC cannot run until critical issues are resolved.\n", red("✗"))
-			os.Exit(2)
+			os.Exit(exitCritical)
 		}
 
 		if len(failures) > 0 {
 			fmt.Printf("\n%s VC may not work correctly. Please address the failures above.\n", yellow("⚠"))
-			os.Exit(1)
+			os.Exit(exitError)
 		}
 
 		fmt.Printf("\n%s VC should work, but some warnings were detected.\n", green("✓"))
-		os.Exit(0)
+		os.Exit(exitOK)
 	},
 }
 
@@ -357,3 +522,244 @@ func runBdImport(projectRoot string) error {
 	}
 	return nil
 }
+
+// doctorProblem is a problem vc doctor found and how to fix it
+type doctorProblem struct {
+	message string
+	fix     string
+}
+
+// printDoctorProblems lists problems with their fixes
+func printDoctorProblems(problems []doctorProblem) {
+	for _, p := range problems {
+		fmt.Printf("  • %s\n", p.message)
+		if p.fix != "" {
+			fmt.Printf("    Fix: %s\n", p.fix)
+		}
+	}
+}
+
+// envConfigCheck validates one group of VC_* settings
+type envConfigCheck struct {
+	name     string
+	vars     string
+	validate func() error
+}
+
+// envConfigChecks returns a check for every setting group loaded from the
+// environment, so a typo is reported here rather than when a command first
+// loads it. The API server settings are left to vc serve, since they are
+// incomplete until it is set up.
+func envConfigChecks() []envConfigCheck {
+	check := func(name, vars string, load func() error) envConfigCheck {
+		return envConfigCheck{name: name, vars: vars, validate: load}
+	}
+	return []envConfigCheck{
+		check("commit attribution", "VC_COMMIT_*", func() error { _, err := config.CommitAttributionConfigFromEnv(); return err }),
+		check("commit signing", "VC_COMMIT_SIGNING*", func() error { _, err := config.CommitSigningConfigFromEnv(); return err }),
+		check("agent anomaly", "VC_AGENT_ANOMALY_*", func() error { _, err := config.AgentAnomalyConfigFromEnv(); return err }),
+		check("resource checks", "VC_RESOURCE_CHECK_*, VC_MIN_FREE_*", func() error { _, err := config.ResourceCheckConfigFromEnv(); return err }),
+		check("backup", "VC_BACKUP_*", func() error { _, err := config.BackupConfigFromEnv(); return err }),
+		check("daemon", "VC_DAEMON_*", func() error { _, err := config.DaemonConfigFromEnv(); return err }),
+		check("dependency check", "VC_DEPENDENCY_CHECK_*", func() error { _, err := config.DependencyCheckConfigFromEnv(); return err }),
+		check("dirty worktree", "VC_DIRTY_WORKTREE*", func() error { _, err := config.DirtyWorktreeConfigFromEnv(); return err }),
+		check("email", "VC_SMTP_* and VC_EMAIL_*", func() error { _, err := config.EmailConfigFromEnv(); return err }),
+		check("error tracker", "VC_ERROR_TRACKER_*", func() error { _, err := config.ErrorTrackerConfigFromEnv(); return err }),
+		check("event retention", "VC_EVENT_RETENTION_*", func() error { _, err := config.EventRetentionConfigFromEnv(); return err }),
+		check("git hosting", "VC_GIT_HOSTING*, GH_TOKEN and GITLAB_TOKEN", func() error { _, err := config.HostingConfigFromEnv(); return err }),
+		check("GitHub sync", "VC_GITHUB_SYNC*", func() error { _, err := config.GitHubSyncConfigFromEnv(); return err }),
+		check("GitLab sync", "VC_GITLAB_SYNC*", func() error { _, err := config.GitLabSyncConfigFromEnv(); return err }),
+		check("instance cleanup", "VC_INSTANCE_CLEANUP_*", func() error { _, err := config.InstanceCleanupConfigFromEnv(); return err }),
+		check("Jira", "VC_JIRA_*", func() error { _, err := config.JiraConfigFromEnv(); return err }),
+		check("large files", "VC_LARGE_FILES_* and VC_MAX_BINARY_SIZE_KB", func() error { _, err := config.LargeFilesConfigFromEnv(); return err }),
+		check("maintenance", "VC_MAINTENANCE_*", func() error {
+			cfg, err := config.MaintenanceConfigFromEnv()
+			if err != nil {
+				return err
+			}
+			_, err = schedule.ParseCron(cfg.Schedule)
+			return err
+		}),
+		check("PagerDuty", "VC_PAGERDUTY_*", func() error { _, err := config.PagerDutyConfigFromEnv(); return err }),
+		check("patch proposal", "VC_PATCH_PROPOSAL*", func() error { _, err := config.PatchProposalConfigFromEnv(); return err }),
+		check("path scope", "VC_SCOPE_*", func() error { _, err := config.PathScopeConfigFromEnv(); return err }),
+		check("push checks", "VC_PUSH_*", func() error { _, err := config.PushChecksConfigFromEnv(); return err }),
+		check("push retry", "VC_PUSH_*", func() error { _, err := config.PushRetryConfigFromEnv(); return err }),
+		check("reviewers", "VC_SUGGEST_REVIEWERS and VC_REVIEWERS_*", func() error { _, err := config.ReviewersConfigFromEnv(); return err }),
+		check("runaway cost", "VC_RUNAWAY_COST_*", func() error { _, err := config.RunawayCostConfigFromEnv(); return err }),
+		check("Slack", "VC_SLACK_*", func() error { _, err := config.SlackConfigFromEnv(); return err }),
+		check("stuck work", "VC_STUCK_WORK_*", func() error { _, err := config.StuckWorkConfigFromEnv(); return err }),
+		check("submodules", "VC_SUBMODULE_*", func() error { _, err := config.SubmodulesConfigFromEnv(); return err }),
+		check("webhook", "VC_WEBHOOK_*", func() error { _, err := config.WebhookConfigFromEnv(); return err }),
+		check("logging", "VC_LOG_*", func() error { _, err := config.LoggingConfigFromEnv(); return err }),
+		check("tracing", "VC_TRACING*", func() error { _, err := config.TracingConfigFromEnv(); return err }),
+		check("deduplication", "VC_DEDUP_*", func() error { _, err := deduplication.ConfigFromEnv(); return err }),
+		check("preflight", "VC_PREFLIGHT_*", func() error { _, err := executor.PreFlightConfigFromEnv(); return err }),
+		check("mutation testing", "VC_MUTATION_*", func() error { _, err := gates.MutationConfigFromEnv(); return err }),
+		check("agent provider", "VC_AGENT_PROVIDER", func() error { _, err := executor.AgentTypeFromEnv(); return err }),
+	}
+}
+
+// gitOutput runs git in dir and returns its trimmed output, or "" if it fails
+func gitOutput(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// gitOperationInProgress returns the merge or rebase left unfinished in the
+// repository at dir, or "" if there is none
+func gitOperationInProgress(dir string) string {
+	gitDir := filepath.Join(dir, ".git")
+	for _, op := range []struct{ path, name string }{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+	} {
+		if _, err := os.Stat(filepath.Join(gitDir, op.path)); err == nil {
+			return op.name
+		}
+	}
+	return ""
+}
+
+// doctorAgent is a coding agent CLI vc doctor looks for
+type doctorAgent struct {
+	name     string
+	binary   string
+	required bool // The executor's default agent
+	install  string
+}
+
+var doctorAgents = []doctorAgent{
+	{name: "Claude Code", binary: "claude", required: true, install: "npm install -g @anthropic-ai/claude-code"},
+	{name: "Amp", binary: "amp", install: "npm install -g @sourcegraph/amp"},
+}
+
+// toolVersion returns the first line of a program's --version output
+func toolVersion(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return version, nil
+}
+
+// claudeAuth returns where the Claude CLI gets its credentials from, or ""
+// if it has none. home is the user's home directory.
+func claudeAuth(home string) string {
+	if os.Getenv("ANTHROPIC_API_KEY") != "" {
+		return "ANTHROPIC_API_KEY"
+	}
+	if os.Getenv("CLAUDE_CODE_OAUTH_TOKEN") != "" {
+		return "CLAUDE_CODE_OAUTH_TOKEN"
+	}
+	configDir := os.Getenv("CLAUDE_CONFIG_DIR")
+	if configDir == "" && home != "" {
+		configDir = filepath.Join(home, ".claude")
+	}
+	if configDir != "" {
+		if _, err := os.Stat(filepath.Join(configDir, ".credentials.json")); err == nil {
+			return "saved login (" + filepath.Join(configDir, ".credentials.json") + ")"
+		}
+	}
+	// On macOS the login is kept in the keychain; the account it belongs
+	// to is recorded in ~/.claude.json
+	if home != "" {
+		if data, err := os.ReadFile(filepath.Join(home, ".claude.json")); err == nil {
+			var state struct {
+				OAuthAccount *struct {
+					EmailAddress string `json:"emailAddress"`
+				} `json:"oauthAccount"`
+			}
+			if json.Unmarshal(data, &state) == nil && state.OAuthAccount != nil {
+				if state.OAuthAccount.EmailAddress != "" {
+					return "Claude account " + state.OAuthAccount.EmailAddress
+				}
+				return "Claude account"
+			}
+		}
+	}
+	return ""
+}
+
+// gateTool is a program the quality gates run
+type gateTool struct {
+	name  string
+	gates []string
+}
+
+// gateTools returns the programs the project's quality gates run, in gate
+// order. Skipped gates and custom gate providers aren't checked.
+func gateTools(projectRoot string) ([]gateTool, error) {
+	runner, err := gates.NewRunner(&gates.Config{Store: memory.New(), WorkingDir: projectRoot})
+	if err != nil {
+		return nil, err
+	}
+	var tools []gateTool
+	index := map[string]int{}
+	for _, gate := range runner.Explain().Gates {
+		if gate.SkipReason != "" {
+			continue
+		}
+		for _, name := range gate.Tools {
+			i, ok := index[name]
+			if !ok {
+				i = len(tools)
+				index[name] = i
+				tools = append(tools, gateTool{name: name})
+			}
+			tools[i].gates = append(tools[i].gates, string(gate.Gate))
+		}
+	}
+	return tools, nil
+}
+
+// gateToolInstall returns how to install a gate tool
+func gateToolInstall(name string) string {
+	switch name {
+	case "go":
+		return "Install Go from https://go.dev/dl/ and add it to PATH"
+	case "golangci-lint":
+		return "go install github.com/golangci/golangci-lint/v2/cmd/golangci-lint@latest"
+	}
+	return "Install " + name + " and add it to PATH, or change the gate in .vc/gates.yaml"
+}
+
+// Free disk space thresholds: sandboxes, gate builds and agent output all
+// need room
+const (
+	doctorMinFreeDisk uint64 = 1 << 30 // Below this is a failure
+	doctorLowFreeDisk uint64 = 5 << 30 // Below this is a warning
+)
+
+// freeDiskSpace returns the bytes available to the user on dir's filesystem
+func freeDiskSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "4.2 GiB"
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestClaudeAuth(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("CLAUDE_CODE_OAUTH_TOKEN", "")
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	home := t.TempDir()
+
+	if got := claudeAuth(home); got != "" {
+		t.Errorf("claudeAuth() with no credentials = %q, want none", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(home, ".claude.json"), []byte(`{"oauthAccount":null}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := claudeAuth(home); got != "" {
+		t.Errorf("claudeAuth() after logout = %q, want none", got)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".claude.json"), []byte(`{"oauthAccount":{"emailAddress":"dev@example.com"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := claudeAuth(home); got != "Claude account dev@example.com" {
+		t.Errorf("claudeAuth() = %q, want the logged in account", got)
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "sk-test")
+	if got := claudeAuth(home); got != "ANTHROPIC_API_KEY" {
+		t.Errorf("claudeAuth() = %q, want the API key to take precedence", got)
+	}
+}
+
+func TestGitOperationInProgress(t *testing.T) {
+	dir := t.TempDir()
+	if err := exec.Command("git", "init", "-q", dir).Run(); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	if got := gitOperationInProgress(dir); got != "" {
+		t.Errorf("gitOperationInProgress() = %q in a fresh repo", got)
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".git", "rebase-merge"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitOperationInProgress(dir); got != "rebase" {
+		t.Errorf("gitOperationInProgress() = %q, want rebase", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[uint64]string{512: "512 B", 1536: "1.5 KiB", 5 << 30: "5.0 GiB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
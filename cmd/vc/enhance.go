@@ -24,6 +24,9 @@ This command:
 4. Updates the issue with improved acceptance criteria
 
 The AI will preserve good criteria and only enhance vague ones.`,
+	Example: `  vc enhance-ac vc-42 --dry-run   # Preview the rewritten criteria
+  vc enhance-ac vc-42
+  vc enhance-ac vc-42 --force     # Even if the criteria look well-formed`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		issueID := args[0]
@@ -3,8 +3,11 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -13,11 +16,22 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/ai"
 	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/cost"
 	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/depgraph"
+	"github.com/steveyegge/vc/internal/errortracker"
 	"github.com/steveyegge/vc/internal/executor"
+	"github.com/steveyegge/vc/internal/maintenance"
+	"github.com/steveyegge/vc/internal/notify"
+	"github.com/steveyegge/vc/internal/pagerduty"
+	"github.com/steveyegge/vc/internal/schedule"
+	"github.com/steveyegge/vc/internal/slack"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/types"
+	"github.com/steveyegge/vc/internal/watchdog"
+	"github.com/steveyegge/vc/internal/webhook"
 )
 
 var executeCmd = &cobra.Command{
@@ -40,6 +54,17 @@ with quality gates, outputs JSON to stdout, and exits. This mode skips issue
 claiming and polling since Gastown handles coordination.
 
 See docs/design/GASTOWN_INTEGRATION.md for details.`,
+	Example: `  # Work the ready queue in sandboxes, committing passing work
+  vc execute --enable-auto-commit
+
+  # Also open pull requests, one branch per issue
+  vc execute --enable-auto-commit --enable-auto-pr
+
+  # Only work on one project's issues
+  vc execute --project web --enable-auto-commit
+
+  # Run as a service, with health checks for the supervisor
+  vc execute --enable-auto-commit --health-addr 127.0.0.1:7392`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runExecutor(cmd); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -57,13 +82,28 @@ func runExecutor(cmd *cobra.Command) error {
 	disableSandboxes, _ := cmd.Flags().GetBool("disable-sandboxes")
 	sandboxRoot, _ := cmd.Flags().GetString("sandbox-root")
 	parentRepo, _ := cmd.Flags().GetString("parent-repo")
+	project, _ := cmd.Flags().GetString("project")
 	enableAutoCommit, _ := cmd.Flags().GetBool("enable-auto-commit")
 	enableAutoPR, _ := cmd.Flags().GetBool("enable-auto-pr")
+	autoCommitPaths, _ := cmd.Flags().GetStringArray("auto-commit-path")
+	autoCommitExclude, _ := cmd.Flags().GetStringArray("auto-commit-exclude")
+	autoCommitAmend, _ := cmd.Flags().GetBool("auto-commit-amend-on-retry")
+	branchPerIssue, _ := cmd.Flags().GetBool("branch-per-issue")
+	autoRollback, _ := cmd.Flags().GetBool("auto-rollback")
+	autoBackport, _ := cmd.Flags().GetBool("auto-backport")
+	autoRelease, _ := cmd.Flags().GetBool("auto-release")
+	publishReleases, _ := cmd.Flags().GetBool("publish-releases")
 	polecatMode, _ := cmd.Flags().GetBool("polecat-mode")
 	taskDesc, _ := cmd.Flags().GetString("task")
 	issueID, _ := cmd.Flags().GetString("issue")
 	stdinFlag, _ := cmd.Flags().GetBool("stdin")
 	liteMode, _ := cmd.Flags().GetBool("lite")
+	healthAddr, _ := cmd.Flags().GetString("health-addr")
+	// vc daemon only; vc execute runs one worker polling at a steady interval
+	workers, _ := cmd.Flags().GetInt("workers")
+	idleBackoff, _ := cmd.Flags().GetDuration("idle-backoff")
+	maxCostPerHour, _ := cmd.Flags().GetFloat64("max-cost-per-hour")
+	maxTokensPerHour, _ := cmd.Flags().GetInt64("max-tokens-per-hour")
 
 	// Determine execution mode
 	var mode types.ExecutionMode
@@ -153,6 +193,52 @@ func runExecutor(cmd *cobra.Command) error {
 	if enableAutoPR && !enableAutoCommit {
 		return fmt.Errorf("--enable-auto-pr requires --enable-auto-commit to be enabled")
 	}
+	// Check environment variable as fallback for branch-per-issue
+	if !branchPerIssue {
+		branchPerIssue = os.Getenv("VC_BRANCH_PER_ISSUE") == "true"
+	}
+	if branchPerIssue && !enableAutoCommit {
+		return fmt.Errorf("--branch-per-issue requires --enable-auto-commit to be enabled")
+	}
+	if !autoRollback {
+		autoRollback = os.Getenv("VC_AUTO_ROLLBACK") == "true"
+	}
+	if autoRollback && !enableAutoCommit {
+		return fmt.Errorf("--auto-rollback requires --enable-auto-commit to be enabled")
+	}
+	if !autoBackport {
+		autoBackport = os.Getenv("VC_AUTO_BACKPORT") == "true"
+	}
+	if autoBackport && !enableAutoCommit {
+		return fmt.Errorf("--auto-backport requires --enable-auto-commit to be enabled")
+	}
+	if !autoRelease {
+		autoRelease = os.Getenv("VC_AUTO_RELEASE") == "true"
+	}
+	if autoRelease && !enableAutoCommit {
+		return fmt.Errorf("--auto-release requires --enable-auto-commit to be enabled")
+	}
+	if !publishReleases {
+		publishReleases = os.Getenv("VC_PUBLISH_RELEASES") == "true"
+	}
+	if publishReleases && !autoRelease {
+		return fmt.Errorf("--publish-releases requires --auto-release to be enabled")
+	}
+	if healthAddr == "" {
+		healthAddr = os.Getenv("VC_HEALTH_ADDR")
+	}
+	if workers == 0 {
+		workers = 1
+	}
+	if maxCostPerHour < 0 || maxTokensPerHour < 0 {
+		return fmt.Errorf("--max-cost-per-hour and --max-tokens-per-hour can't be negative")
+	}
+	if workers > 1 && disableSandboxes {
+		return fmt.Errorf("--workers above 1 requires sandboxes, so agents don't edit the same workspace")
+	}
+	if !enableAutoCommit && (len(autoCommitPaths) > 0 || len(autoCommitExclude) > 0 || autoCommitAmend) {
+		return fmt.Errorf("--auto-commit-path, --auto-commit-exclude and --auto-commit-amend-on-retry require --enable-auto-commit")
+	}
 
 	// Derive working directory from database location
 	// This ensures database and code are in the same project
@@ -201,6 +287,258 @@ func runExecutor(cmd *cobra.Command) error {
 		return fmt.Errorf("invalid instance cleanup configuration: %w", err)
 	}
 
+	// Load scheduled backup configuration from environment
+	backupConfig, err := config.BackupConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid backup configuration: %w", err)
+	}
+
+	// Load git hosting integration configuration from environment (used by auto-PR)
+	hostingConfig, err := config.HostingConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid git hosting configuration: %w", err)
+	}
+
+	// Load GitHub Issues sync configuration from environment (VC_GITHUB_SYNC*)
+	githubSyncConfig, err := config.GitHubSyncConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid GitHub sync configuration: %w", err)
+	}
+
+	// Load GitLab issues sync configuration from environment (VC_GITLAB_SYNC*)
+	gitlabSyncConfig, err := config.GitLabSyncConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid GitLab sync configuration: %w", err)
+	}
+
+	// Load Jira connector configuration from environment (VC_JIRA_*)
+	jiraConfig, err := config.JiraConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid Jira configuration: %w", err)
+	}
+
+	// Load outbound webhook configuration from environment (VC_WEBHOOK_*)
+	webhookConfig, err := config.WebhookConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid webhook configuration: %w", err)
+	}
+	var webhooks *webhook.Dispatcher
+	if webhookConfig.Enabled() {
+		if webhooks, err = webhook.NewDispatcher(webhookConfig, store); err != nil {
+			return fmt.Errorf("invalid webhook configuration: %w", err)
+		}
+	}
+
+	// Load email alerts and daily digest configuration from environment
+	// (VC_SMTP_*, VC_EMAIL_*)
+	emailConfig, err := config.EmailConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid email configuration: %w", err)
+	}
+	var notifier *notify.Notifier
+	if emailConfig.Enabled() {
+		// The digest overview is written by the AI supervisor when one is
+		// available; without it the digest is the plain report
+		var summarizer notify.Summarizer
+		if emailConfig.Digest {
+			if supervisor, err := ai.NewSupervisor(&ai.Config{Store: store}); err == nil {
+				summarizer = supervisor
+			} else {
+				fmt.Fprintf(os.Stderr, "warning: daily digest will have no AI summary: %v\n", err)
+			}
+		}
+		if notifier, err = notify.NewNotifier(emailConfig, store, summarizer); err != nil {
+			return fmt.Errorf("invalid email configuration: %w", err)
+		}
+	}
+
+	// Load PagerDuty paging configuration from environment (VC_PAGERDUTY_*).
+	// Pages carry the AI supervisor's summary when one is available.
+	pagerDutyConfig, err := config.PagerDutyConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid PagerDuty configuration: %w", err)
+	}
+	var pager *pagerduty.Pager
+	if pagerDutyConfig.Enabled() {
+		var summarizer pagerduty.Summarizer
+		if supervisor, err := ai.NewSupervisor(&ai.Config{Store: store}); err == nil {
+			summarizer = supervisor
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: PagerDuty pages will have no AI summary: %v\n", err)
+		}
+		if pager, err = pagerduty.NewPager(pagerDutyConfig, store, summarizer); err != nil {
+			return fmt.Errorf("invalid PagerDuty configuration: %w", err)
+		}
+	}
+
+	// Load stuck work watchdog configuration from environment (VC_STUCK_WORK_*)
+	stuckWorkConfig, err := config.StuckWorkConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid stuck work configuration: %w", err)
+	}
+
+	// Load runaway cost detector configuration from environment (VC_RUNAWAY_COST_*)
+	runawayCostConfig, err := config.RunawayCostConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid runaway cost configuration: %w", err)
+	}
+
+	// Load the maintenance job configuration from environment
+	// (VC_MAINTENANCE_*). Only vc daemon runs the job on its schedule.
+	maintenanceConfig, err := config.MaintenanceConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid maintenance configuration: %w", err)
+	}
+	var maintenanceJob *maintenance.Job
+	if maintenanceConfig.Enabled && cmd.Name() == "daemon" {
+		maintenanceRoot := sandboxRoot
+		if disableSandboxes {
+			maintenanceRoot = ""
+		}
+		if maintenanceJob, err = newMaintenanceJob(maintenanceConfig, maintenanceRoot, parentRepo); err != nil {
+			return err
+		}
+	}
+
+	// Load Slack approvals configuration from environment (VC_SLACK_*)
+	slackConfig, err := config.SlackConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid Slack configuration: %w", err)
+	}
+	var slackBot *slack.Bot
+	if slackConfig.Enabled() {
+		if slackBot, err = slack.NewBot(slackConfig, store); err != nil {
+			return fmt.Errorf("invalid Slack configuration: %w", err)
+		}
+	}
+
+	// Load error tracker intake configuration from environment
+	// (VC_ERROR_TRACKER_*). Bug reports are drafted and deduplicated by the
+	// AI supervisor when one is available; without it they're filed from a
+	// template.
+	errorTrackerConfig, err := config.ErrorTrackerConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid error tracker configuration: %w", err)
+	}
+	var errorIntake *errortracker.Intake
+	if errorTrackerConfig.Enabled() {
+		var drafter errortracker.Drafter
+		var dedup deduplication.Deduplicator
+		if supervisor, err := ai.NewSupervisor(&ai.Config{Store: store}); err == nil {
+			drafter = supervisor
+			dedupConfig, err := deduplication.ConfigFromEnv()
+			if err != nil {
+				return fmt.Errorf("invalid deduplication configuration: %w", err)
+			}
+			if dedup, err = deduplication.NewAIDeduplicator(supervisor, store, dedupConfig); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: error tracker bugs won't be deduplicated: %v\n", err)
+				dedup = nil
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: error tracker bugs will be filed without AI drafts: %v\n", err)
+		}
+		if errorIntake, err = errortracker.NewIntake(errorTrackerConfig, store, drafter, dedup); err != nil {
+			return fmt.Errorf("invalid error tracker configuration: %w", err)
+		}
+	}
+
+	// Load auto-commit signing and committer identity from environment
+	// (VC_COMMIT_SIGNING, VC_COMMIT_SIGNING_KEY, VC_COMMITTER_NAME, VC_COMMITTER_EMAIL)
+	commitSigningConfig, err := config.CommitSigningConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid commit signing configuration: %w", err)
+	}
+
+	// Load auto-commit attribution from environment
+	// (VC_COMMIT_CO_AUTHOR, VC_COMMIT_AGENT_TRAILERS)
+	commitAttributionConfig, err := config.CommitAttributionConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid commit attribution configuration: %w", err)
+	}
+
+	// Load pre-push safety checks from environment (VC_PUSH_CHECKS,
+	// VC_PUSH_MAX_FILE_SIZE_KB, VC_PUSH_ALLOW_BINARY, VC_PUSH_ALLOW_FORCE, VC_PUSH_SCAN_SECRETS)
+	pushChecksConfig, err := config.PushChecksConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid push checks configuration: %w", err)
+	}
+
+	// Load what to do with uncommitted changes before an agent runs in the
+	// main workspace (VC_DIRTY_WORKTREE)
+	dirtyWorktreeConfig, err := config.DirtyWorktreeConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid dirty worktree configuration: %w", err)
+	}
+
+	// Load what to do with agent edits outside an issue's "scope:" labels
+	// (VC_SCOPE_VIOLATION)
+	pathScopeConfig, err := config.PathScopeConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid path scope configuration: %w", err)
+	}
+
+	// Load patch-proposal mode: review agent changes as a patch before they
+	// touch the main workspace (VC_PATCH_PROPOSAL)
+	patchProposalConfig, err := config.PatchProposalConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid patch proposal configuration: %w", err)
+	}
+
+	// Load reviewer suggestions for pull requests and escalations
+	// (VC_SUGGEST_REVIEWERS)
+	reviewersConfig, err := config.ReviewersConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid reviewers configuration: %w", err)
+	}
+
+	// Load behavioral anomaly detection for agents (VC_AGENT_ANOMALY_*)
+	agentAnomalyConfig, err := config.AgentAnomalyConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid agent anomaly configuration: %w", err)
+	}
+
+	// Load the disk, temp space and file descriptor checks run before claiming
+	// work (VC_RESOURCE_CHECK_*, VC_MIN_FREE_*)
+	resourceCheckConfig, err := config.ResourceCheckConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid resource check configuration: %w", err)
+	}
+
+	// Load handling of Git LFS files and large binaries (VC_LARGE_FILES_GUARD)
+	largeFilesConfig, err := config.LargeFilesConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid large files configuration: %w", err)
+	}
+
+	// Load submodule handling (VC_SUBMODULE_UPDATE, VC_SUBMODULE_POINTER_CHANGE)
+	submodulesConfig, err := config.SubmodulesConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid submodules configuration: %w", err)
+	}
+
+	// Load push retries (VC_PUSH_MAX_ATTEMPTS, VC_PUSH_RETRY_GATES)
+	pushRetryConfig, err := config.PushRetryConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid push retry configuration: %w", err)
+	}
+
+	// Load dependency graph checks (VC_DEPENDENCY_CHECK_*). Edges to break
+	// are suggested by the AI supervisor when one is available.
+	dependencyCheckConfig, err := config.DependencyCheckConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid dependency check configuration: %w", err)
+	}
+	var dependencyChecker *depgraph.Checker
+	if dependencyCheckConfig.Enabled {
+		var caller depgraph.AICaller
+		if supervisor, err := ai.NewSupervisor(&ai.Config{Store: store}); err == nil {
+			caller = supervisor
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: dependency knot diagnostics will suggest the newest dependency: %v\n", err)
+		}
+		dependencyChecker = depgraph.NewChecker(store, caller)
+	}
+
 	// Create executor configuration
 	cfg := executor.DefaultConfig()
 	cfg.Store = store
@@ -209,35 +547,103 @@ func runExecutor(cmd *cobra.Command) error {
 	cfg.EnableSandboxes = !disableSandboxes // Sandboxes enabled by default (vc-144)
 	cfg.SandboxRoot = sandboxRoot
 	cfg.ParentRepo = parentRepo
+	cfg.Project = project
 	cfg.DeduplicationConfig = &dedupConfig
 	cfg.InstanceCleanupAge = instanceCleanupConfig.CleanupAge() // vc-33: from environment
 	cfg.InstanceCleanupKeep = instanceCleanupConfig.CleanupKeep  // vc-33: from environment
 	cfg.EnableAutoCommit = enableAutoCommit // vc-142: expose auto-commit configuration
 	cfg.EnableAutoPR = enableAutoPR         // vc-389e: expose auto-PR configuration
+	cfg.AutoCommitPaths = autoCommitPaths
+	cfg.AutoCommitExcludePaths = autoCommitExclude
+	cfg.AutoCommitAmendOnRetry = autoCommitAmend
+	cfg.EnableBranchWorkflow = branchPerIssue
+	cfg.EnableAutoRollback = autoRollback
+	cfg.EnableAutoBackport = autoBackport
+	cfg.EnableAutoRelease = autoRelease
+	cfg.PublishReleases = publishReleases
+	cfg.Hosting = hostingConfig
+	cfg.CommitSigning = commitSigningConfig
+	cfg.CommitAttribution = commitAttributionConfig
+	cfg.PushChecks = pushChecksConfig
+	cfg.DirtyWorktree = dirtyWorktreeConfig
+	cfg.PathScope = pathScopeConfig
+	cfg.AgentAnomaly = agentAnomalyConfig
+	cfg.ResourceCheck = resourceCheckConfig
+	cfg.PatchProposal = patchProposalConfig
+	cfg.Reviewers = reviewersConfig
+	cfg.LargeFiles = largeFilesConfig
+	cfg.Submodules = submodulesConfig
+	cfg.PushRetry = pushRetryConfig
+	cfg.DependencyChecker = dependencyChecker
+	cfg.DependencyCheckIdleInterval = dependencyCheckConfig.IdleInterval()
 	if pollSeconds > 0 {
 		cfg.PollInterval = time.Duration(pollSeconds) * time.Second
 	}
+	cfg.IdleBackoffMax = idleBackoff
+
+	// Budget flags override VC_COST_MAX_*_PER_HOUR and turn budgeting on
+	if maxCostPerHour > 0 || maxTokensPerHour > 0 {
+		costConfig := cost.LoadFromEnv()
+		costConfig.Enabled = true
+		if maxCostPerHour > 0 {
+			costConfig.MaxCostPerHour = maxCostPerHour
+		}
+		if maxTokensPerHour > 0 {
+			costConfig.MaxTokensPerHour = maxTokensPerHour
+		}
+		if cfg.CostTracker, err = cost.NewTracker(costConfig, store); err != nil {
+			return fmt.Errorf("failed to initialize cost tracker: %w", err)
+		}
+	}
 
 	// Warn if sandboxes are disabled (vc-144)
-	if disableSandboxes {
+	if disableSandboxes && patchProposalConfig.Enabled {
+		fmt.Printf("Sandboxes are disabled; patch-proposal mode is on, so agent changes are\n")
+		fmt.Printf("reviewed as patches before they are applied to your main workspace.\n\n")
+	} else if disableSandboxes {
 		fmt.Fprintf(os.Stderr, "\n⚠️  WARNING: Sandboxes are disabled!\n")
 		fmt.Fprintf(os.Stderr, "   Agents will work directly in your main workspace.\n")
 		fmt.Fprintf(os.Stderr, "   Failed executions may leave your repository in a dirty state.\n")
+		fmt.Fprintf(os.Stderr, "   Uncommitted changes are handled per VC_DIRTY_WORKTREE (currently: %s).\n", dirtyWorktreeConfig.Policy)
 		fmt.Fprintf(os.Stderr, "   This mode is intended for development/testing only.\n\n")
 	}
 
-	// Create executor instance
-	exec, err := executor.New(cfg)
+	// Create executor instances, one per worker
+	pool, err := executor.NewPool(cfg, workers)
 	if err != nil {
 		return fmt.Errorf("failed to create executor: %w", err)
 	}
 
+	// The stuck work watchdog asks the AI supervisor whether to nudge, kill
+	// or escalate when one is available, and kills agents through the pool
+	var stuckWork *watchdog.StuckWorkWatchdog
+	if stuckWorkConfig.Enabled {
+		var caller watchdog.AICaller
+		if supervisor, err := ai.NewSupervisor(&ai.Config{Store: store}); err == nil {
+			caller = supervisor
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: stuck work watchdog will use rule-based decisions: %v\n", err)
+		}
+		if stuckWork, err = watchdog.NewStuckWorkWatchdog(stuckWorkConfig, store, caller, pool); err != nil {
+			return fmt.Errorf("invalid stuck work configuration: %w", err)
+		}
+	}
+
+	// The runaway cost detector pauses the whole pool, or kills a single
+	// issue's agent, until a human closes the escalation it files
+	var runaway *cost.RunawayDetector
+	if runawayCostConfig.Enabled {
+		if runaway, err = cost.NewRunawayDetector(runawayCostConfig, store, pool); err != nil {
+			return fmt.Errorf("invalid runaway cost configuration: %w", err)
+		}
+	}
+
 	// Ensure instance is marked as stopped on exit (vc-192)
 	// This handles abnormal exits (panics, os.Exit, etc.) in addition to graceful shutdown
 	defer func() {
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if err := exec.MarkInstanceStoppedOnExit(shutdownCtx); err != nil {
+		if err := pool.MarkInstancesStoppedOnExit(shutdownCtx); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to mark instance as stopped: %v\n", err)
 		}
 	}()
@@ -250,19 +656,149 @@ func runExecutor(cmd *cobra.Command) error {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
+	// Serve health endpoints before starting, so supervisors see a live but
+	// not yet ready executor while startup checks run
+	if healthAddr != "" {
+		listener, err := net.Listen("tcp", healthAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s for health checks: %w", healthAddr, err)
+		}
+		go serveHealth(ctx, listener, pool.HealthHandler())
+	}
+
 	// Start executor in background
-	if err := exec.Start(ctx); err != nil {
+	if err := pool.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start executor: %w", err)
 	}
 
 	cyan := color.New(color.FgCyan).SprintFunc()
 	fmt.Printf("%s Executor started (version %s)\n", green("✓"), cyan(version))
-	fmt.Printf("  Polling for ready work every %v\n", cfg.PollInterval)
+	if workers > 1 {
+		fmt.Printf("  Workers: %d\n", workers)
+	}
+	if cfg.IdleBackoffMax > cfg.PollInterval {
+		fmt.Printf("  Polling for ready work every %v, backing off to %v while the queue is empty\n", cfg.PollInterval, cfg.IdleBackoffMax)
+	} else {
+		fmt.Printf("  Polling for ready work every %v\n", cfg.PollInterval)
+	}
 	if cfg.EnableSandboxes {
 		fmt.Printf("  Sandboxes: %s (root: %s)\n", green("enabled"), cfg.SandboxRoot)
 	} else {
 		fmt.Printf("  Sandboxes: disabled\n")
 	}
+	if backupConfig.Enabled {
+		go runScheduledBackups(ctx, dbPath, backupConfig)
+		fmt.Printf("  Backups: %s (every %v, keeping %d)\n", green("enabled"), backupConfig.Interval(), backupConfig.Keep)
+	}
+	// Schedules are polled even when none are enabled yet, so ones added
+	// while the executor runs take effect
+	go schedule.NewScheduler(store).Run(ctx)
+	if schedules, err := store.ListSchedules(ctx); err == nil {
+		enabled := 0
+		for _, s := range schedules {
+			if s.Enabled {
+				enabled++
+			}
+		}
+		if enabled > 0 {
+			fmt.Printf("  Schedules: %s (%d, see 'vc schedule list')\n", green("enabled"), enabled)
+		}
+	}
+	if enableAutoPR && hostingConfig.Enabled() {
+		go runPullRequestSync(ctx, hostingConfig)
+		provider := hostingConfig.Provider
+		if provider == "" {
+			provider = "detected from remote"
+		}
+		fmt.Printf("  Pull requests: %s via API (%s, status checked every %v)\n", green("enabled"), provider, hostingConfig.SyncInterval())
+	}
+	if githubSyncConfig.Enabled {
+		if syncer, repo, err := newIssueSyncer(ctx, githubSyncConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: GitHub issues sync disabled: %v\n", err)
+		} else {
+			go syncer.Run(ctx)
+			fmt.Printf("  GitHub sync: %s (%s, every %v)\n", green("enabled"), repo, githubSyncConfig.Interval())
+		}
+	}
+	if gitlabSyncConfig.Enabled {
+		if syncer, repo, err := newIssueSyncer(ctx, gitlabSyncConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: GitLab issues sync disabled: %v\n", err)
+		} else {
+			go syncer.Run(ctx)
+			fmt.Printf("  GitLab sync: %s (%s, every %v)\n", green("enabled"), repo, gitlabSyncConfig.Interval())
+		}
+	}
+	if jiraConfig.Enabled {
+		if syncer, err := newJiraSyncer(jiraConfig); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: Jira sync disabled: %v\n", err)
+		} else {
+			go syncer.Run(ctx)
+			fmt.Printf("  Jira sync: %s (%s, every %v)\n", green("enabled"), jiraConfig.Project, jiraConfig.Interval())
+		}
+	}
+	if webhooks != nil {
+		go webhooks.Run(ctx)
+		fmt.Printf("  Webhooks: %s (%s)\n", green("enabled"), webhookConfig.URL)
+	}
+	if notifier != nil {
+		go notifier.Run(ctx)
+		var kinds []string
+		if emailConfig.Alerts {
+			kinds = append(kinds, "alerts")
+		}
+		if emailConfig.Digest {
+			kinds = append(kinds, fmt.Sprintf("daily digest at %s", notifier.NextDigest().Format("15:04")))
+		}
+		if len(kinds) == 0 {
+			kinds = append(kinds, "nothing to send")
+		}
+		fmt.Printf("  Email: %s (%s to %s)\n", green("enabled"), strings.Join(kinds, ", "), strings.Join(emailConfig.To, ", "))
+	}
+	if pager != nil {
+		go pager.Run(ctx)
+		fmt.Printf("  PagerDuty: %s (blocked P0s and %d failed executions in a row)\n", green("enabled"), pagerDutyConfig.FailureThreshold)
+	}
+	if stuckWork != nil {
+		go stuckWork.Run(ctx)
+		fmt.Printf("  Stuck work watchdog: %s (claims over %v, executions quiet for %v, dead workers)\n",
+			green("enabled"), stuckWorkConfig.MaxInProgress(), stuckWorkConfig.Idle())
+	}
+	if runaway != nil {
+		go runaway.Run(ctx)
+		fmt.Printf("  Runaway cost detector: %s (spend over %.0fx baseline per %v, issues over %.1fx estimate)\n",
+			green("enabled"), runawayCostConfig.VelocityMultiplier, runawayCostConfig.Window(), runawayCostConfig.IssueMultiplier)
+	}
+	if maintenanceJob != nil {
+		go maintenanceJob.Run(ctx)
+		fmt.Printf("  Maintenance: %s (%s, next run %s)\n",
+			green("enabled"), maintenanceConfig.Schedule, maintenanceJob.Next(time.Now()).Format("2006-01-02 15:04"))
+	}
+	if dependencyChecker != nil {
+		go dependencyChecker.Run(ctx, dependencyCheckConfig.Interval())
+		fmt.Printf("  Dependency checks: %s (every %v, and every %v while idle)\n",
+			green("enabled"), dependencyCheckConfig.Interval(), dependencyCheckConfig.IdleInterval())
+	}
+	if slackBot != nil {
+		go slackBot.Run(ctx)
+		go func() {
+			if err := slackBot.ListenAndServe(ctx, slackConfig.Addr); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: Slack interactivity endpoint stopped: %v\n", err)
+			}
+		}()
+		fmt.Printf("  Slack: %s (approvals in %s, buttons on http://%s/slack/interactions)\n", green("enabled"), slackConfig.Channel, slackConfig.Addr)
+	}
+	if errorIntake != nil {
+		go errorIntake.Run(ctx)
+		go func() {
+			if err := errorIntake.ListenAndServe(ctx, errorTrackerConfig.Addr); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: error tracker endpoint stopped: %v\n", err)
+			}
+		}()
+		fmt.Printf("  Error tracker: %s (http://%s/errors/sentry and /errors/webhook)\n", green("enabled"), errorTrackerConfig.Addr)
+	}
+	if healthAddr != "" {
+		fmt.Printf("  Health: %s (http://%s/healthz and /readyz)\n", green("enabled"), healthAddr)
+	}
 	fmt.Printf("  Press Ctrl+C to stop\n\n")
 
 	// Wait for shutdown signal
@@ -275,7 +811,7 @@ func runExecutor(cmd *cobra.Command) error {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
-	if err := exec.Stop(shutdownCtx); err != nil {
+	if err := pool.Stop(shutdownCtx); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: error during shutdown: %v\n", err)
 	}
 
@@ -283,6 +819,42 @@ func runExecutor(cmd *cobra.Command) error {
 	return nil
 }
 
+// addWorkLoopFlags adds the flags vc execute and vc daemon share
+func addWorkLoopFlags(cmd *cobra.Command) {
+	cmd.Flags().String("version", "0.1.0", "Executor version")
+	cmd.Flags().IntP("poll-interval", "i", 5, "Poll interval in seconds")
+	cmd.Flags().Bool("disable-sandboxes", false, "Disable sandbox isolation (DANGEROUS: for development/testing only)")
+	cmd.Flags().String("sandbox-root", ".sandboxes", "Root directory for sandboxes")
+	cmd.Flags().String("parent-repo", ".", "Parent repository path")
+	cmd.Flags().String("project", "", "Only work on issues in this project, in its repository (see 'vc project')")
+	cmd.Flags().Bool("enable-auto-commit", false, "Enable automatic git commits after successful execution (can also use VC_ENABLE_AUTO_COMMIT=true)")
+	cmd.Flags().Bool("enable-auto-pr", false, "Enable automatic PR creation after successful commit (requires --enable-auto-commit, can also use VC_ENABLE_AUTO_PR=true)")
+	cmd.Flags().StringArray("auto-commit-path", nil, "Only auto-commit changed files matching this pattern, e.g. 'internal/' or '*.go' (repeatable)")
+	cmd.Flags().StringArray("auto-commit-exclude", nil, "Never auto-commit changed files matching this pattern (repeatable)")
+	cmd.Flags().Bool("auto-commit-amend-on-retry", false, "When retrying an issue, amend the previous attempt's commit if it is still HEAD")
+	cmd.Flags().Bool("branch-per-issue", false, "Without sandboxes, work on a vc/<issue-id>-<slug> branch and merge it only after gates and review pass (requires --enable-auto-commit, can also use VC_BRANCH_PER_ISSUE=true)")
+	cmd.Flags().Bool("auto-rollback", false, "Revert an execution's commit and file a follow-up issue when the baseline fails on it after passing on its parent (requires --enable-auto-commit, can also use VC_AUTO_ROLLBACK=true)")
+	cmd.Flags().Bool("auto-backport", false, "Cherry-pick an issue's landed commits onto the release branches its backport:<branch> labels name, run gates there and open pull requests (requires --enable-auto-commit, can also use VC_AUTO_BACKPORT=true)")
+	cmd.Flags().Bool("auto-release", false, "Tag a mission labelled release:<version> with generated release notes once it closes and its work has landed (requires --enable-auto-commit, can also use VC_AUTO_RELEASE=true)")
+	cmd.Flags().Bool("publish-releases", false, "Push release tags and publish releases on GitHub or GitLab (requires --auto-release, can also use VC_PUBLISH_RELEASES=true)")
+	cmd.Flags().String("health-addr", "", "Serve /healthz and /readyz for systemd, Kubernetes and other supervisors on this host:port (can also use VC_HEALTH_ADDR)")
+}
+
+// serveHealth serves the executor's /healthz and /readyz on listener until
+// ctx is done
+func serveHealth(ctx context.Context, listener net.Listener, handler http.Handler) {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "warning: health endpoint stopped: %v\n", err)
+	}
+}
+
 // readTaskFromStdin reads a task description from stdin (vc-d1l9)
 // Supports piped input and heredocs for multi-line task specifications
 func readTaskFromStdin() (*types.PolecatTask, error) {
@@ -406,13 +978,7 @@ func runPolecatExecution(task *types.PolecatTask, liteMode bool) error {
 }
 
 func init() {
-	executeCmd.Flags().String("version", "0.1.0", "Executor version")
-	executeCmd.Flags().IntP("poll-interval", "i", 5, "Poll interval in seconds")
-	executeCmd.Flags().Bool("disable-sandboxes", false, "Disable sandbox isolation (DANGEROUS: for development/testing only)")
-	executeCmd.Flags().String("sandbox-root", ".sandboxes", "Root directory for sandboxes")
-	executeCmd.Flags().String("parent-repo", ".", "Parent repository path")
-	executeCmd.Flags().Bool("enable-auto-commit", false, "Enable automatic git commits after successful execution (can also use VC_ENABLE_AUTO_COMMIT=true)")
-	executeCmd.Flags().Bool("enable-auto-pr", false, "Enable automatic PR creation after successful commit (requires --enable-auto-commit, can also use VC_ENABLE_AUTO_PR=true)")
+	addWorkLoopFlags(executeCmd)
 
 	// Polecat mode flags (vc-m5qr, vc-plr3, vc-5fxi, vc-d1l9, vc-5vod: Gastown integration)
 	executeCmd.Flags().Bool("polecat-mode", false, "Enable polecat mode for single-task execution inside Gastown")
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var executionsCmd = &cobra.Command{
+	Use:   "executions [issue-id]",
+	Short: "List agent executions",
+	Long: `List agent executions, newest first: which agent ran, how it ended,
+how long it took, what it cost and the commit it produced.`,
+	Example: `  vc executions                   # Last 20 executions of any issue
+  vc executions vc-123 -n 0       # Every execution of vc-123
+  vc executions --status failed   # Recent failures
+  vc executions vc-123 --json     # As JSON, for scripts`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := types.ExecutionFilter{}
+		if len(args) == 1 {
+			filter.IssueID = args[0]
+		}
+		status, _ := cmd.Flags().GetString("status")
+		filter.Status = types.ExecutionStatus(status)
+		if filter.Status != "" && !filter.Status.IsValid() {
+			exitWithError(exitError, fmt.Errorf("invalid status %q", status))
+		}
+		filter.Limit, _ = cmd.Flags().GetInt("limit")
+
+		ctx := context.Background()
+		if filter.IssueID != "" {
+			if issue, err := store.GetIssue(ctx, filter.IssueID); err != nil {
+				exitWithError(exitError, err)
+			} else if issue == nil {
+				exitWithError(exitNotFound, fmt.Errorf("issue %s not found", filter.IssueID))
+			}
+		}
+		executions, err := store.ListExecutions(ctx, filter)
+		if err != nil {
+			exitWithError(exitError, err)
+		}
+		if jsonOutput {
+			if executions == nil {
+				executions = []*types.Execution{}
+			}
+			printJSON(struct {
+				Count      int                `json:"count"`
+				Executions []*types.Execution `json:"executions"`
+			}{len(executions), executions})
+			return
+		}
+		if len(executions) == 0 {
+			fmt.Printf("\nNo executions found\n\n")
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Printf("\n%s Executions (%d, newest first):\n\n", cyan("⚙"), len(executions))
+		for _, e := range executions {
+			fmt.Printf("  #%-5d %-10s %-12s %-11s %8s  $%.2f  %s\n",
+				e.ID, e.IssueID, e.AgentProvider, e.Status, e.Duration().Round(time.Second), e.CostUSD,
+				gray(e.StartedAt.Local().Format("2006-01-02 15:04:05")))
+			if e.CommitHash != "" {
+				fmt.Printf("         commit %s\n", e.CommitHash)
+			}
+			if e.IsRolledBack() {
+				rolledBack := fmt.Sprintf("rolled back by %s", e.RevertCommit)
+				if e.RollbackIssueID != "" {
+					rolledBack += fmt.Sprintf(", follow-up %s", e.RollbackIssueID)
+				}
+				fmt.Printf("         %s\n", yellow(rolledBack))
+			}
+			if e.StartSnapshot != nil || e.EndSnapshot != nil {
+				fmt.Printf("         %s\n", gray(fmt.Sprintf("workspace %s -> %s", e.StartSnapshot, e.EndSnapshot)))
+			}
+			if e.Error != "" {
+				fmt.Printf("         %s\n", gray(e.Error))
+			}
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	executionsCmd.Flags().StringP("status", "s", "", "Only executions with this status (running, succeeded, incomplete, failed, interrupted)")
+	executionsCmd.Flags().IntP("limit", "n", 20, "Maximum number of executions to show (0 = all)")
+	rootCmd.AddCommand(executionsCmd)
+}
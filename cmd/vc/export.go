@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/storage/importer"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the full issue graph as JSONL, or a table as CSV or JSON",
+	Long: `Export issues, dependencies, labels, events and execution history as a
+versioned JSONL dump.
+
+The output is stable for an unchanged database (apart from the header
+timestamp), so it diffs cleanly and can be checked into git or moved to
+another machine with 'vc import'.
+
+With --format csv or json, export one table instead, for spreadsheets and BI
+tools:
+
+  issues      id, title, issue_type, issue_subtype, status, priority,
+              assignee, project, labels, estimated_minutes, created_at,
+              updated_at, closed_at
+  executions  id, issue_id, executor_instance_id, agent_provider, status,
+              exit_code, error, started_at, completed_at,
+              agent_duration_seconds, cost_usd, commit_hash, revert_commit,
+              rollback_issue_id
+  gates       event_id, issue_id, timestamp, all_passed, gates_run,
+              passed_count, failed_count, timed_out, canceled, error
+  ai-usage    id, timestamp, issue_id, operation, model, input_tokens,
+              output_tokens, cost_usd, duration_seconds
+
+Columns are only ever added at the end, so sheets and queries built on an
+export keep working. Times are RFC 3339 in UTC; in CSV, labels are joined
+with semicolons and missing values are empty.
+
+The issue filters (--query, --status, --label, --filter...) work as in
+'vc list' and select the issues reported on: the issues themselves, or their
+executions, gate runs and AI usage. --since drops older rows.`,
+	Example: `  # Write to stdout
+  vc export
+
+  # Write to a file
+  vc export -o backlog.jsonl
+
+  # Open bugs as a spreadsheet
+  vc export --format csv --status open --type bug -o open-bugs.csv
+
+  # Executions of issues mentioning "auth" since October, as JSON
+  vc export --format json --table executions --query auth --since 2025-10-01
+
+  # AI usage of a project's issues
+  vc export --format csv --table ai-usage --project billing`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "jsonl" && format != "csv" && format != "json" {
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (want jsonl, csv or json)\n", format)
+			os.Exit(1)
+		}
+		var report *export.Report
+		if format == "jsonl" {
+			for _, name := range exportReportFlags {
+				if cmd.Flags().Changed(name) {
+					fmt.Fprintf(os.Stderr, "Error: --%s requires --format csv or json\n", name)
+					os.Exit(1)
+				}
+			}
+		} else {
+			report = buildExportReport(cmd)
+		}
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		var err error
+		switch format {
+		case "csv":
+			err = report.WriteCSV(w)
+		case "json":
+			err = report.WriteJSON(w)
+		default:
+			err = store.Export(context.Background(), w)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if output != "" {
+			green := color.New(color.FgGreen).SprintFunc()
+			if report != nil {
+				fmt.Printf("%s Exported %d row(s) of %s to %s\n", green("✓"), len(report.Rows), report.Table, output)
+			} else {
+				fmt.Printf("%s Exported issue graph to %s\n", green("✓"), output)
+			}
+		}
+	},
+}
+
+// exportReportFlags are the vc export flags that only apply to --format csv
+// and json
+var exportReportFlags = append([]string{"table", "filter", "since"}, listFilterFlags...)
+
+// buildExportReport builds the table vc export's flags ask for
+func buildExportReport(cmd *cobra.Command) *export.Report {
+	ctx := context.Background()
+	tableFlag, _ := cmd.Flags().GetString("table")
+	filterName, _ := cmd.Flags().GetString("filter")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+
+	table, err := export.ParseTable(tableFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var query export.ReportQuery
+	if sinceFlag != "" {
+		if query.Since, err = time.ParseInLocation("2006-01-02", sinceFlag, time.Local); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --since date %q (want YYYY-MM-DD)\n", sinceFlag)
+			os.Exit(1)
+		}
+	}
+
+	filtered := false
+	for _, name := range listFilterFlags {
+		if cmd.Flags().Changed(name) {
+			if filterName != "" {
+				fmt.Fprintf(os.Stderr, "Error: --filter can't be combined with --%s\n", name)
+				os.Exit(1)
+			}
+			filtered = true
+		}
+	}
+	if filterName != "" {
+		query.Issues, err = store.ListIssuesByFilter(ctx, filterName)
+	} else {
+		saved := savedFilterFromFlags(cmd)
+		query.Issues, err = store.SearchIssues(ctx, saved.Query, saved.IssueFilter())
+		query.AllIssues = !filtered
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, err := export.BuildReport(ctx, store, table, query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return report
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import an issue graph written by 'vc export', or another backlog",
+	Long: `Import a JSONL dump written by 'vc export', or with --from, a backlog
+kept in another issue store.
+
+The whole dump is validated before anything is written, and the import runs
+in a single transaction. Issues that already exist are left untouched, so
+importing the same dump twice is a no-op. Use '-' to read from stdin.
+
+--from beads reads a Beads issues.jsonl file (or the .beads directory holding
+it), keeping IDs, priorities, labels, dependencies, parent links and comments.
+
+--from json and --from csv read a generic backlog: a JSON array of objects
+(or one object per line), or a CSV file with a header row. Fields are read
+from columns or keys named after the VC field (id, title, description, design,
+acceptance_criteria, notes, status, priority, issue_type, assignee,
+estimated_minutes, labels, parent, created_at, closed_at); use --map to read
+them from other names. Common status, type and priority names (done, story,
+high, P1...) are understood. Records without an ID get a generated one.`,
+	Example: `  vc import backlog.jsonl
+  cat backlog.jsonl | vc import -
+  vc import --from beads ../old-project/.beads
+  vc import --from csv tickets.csv --map title=Summary --map labels=Tags --map parent="Parent ID"
+  vc import --from json jira.json --map id=key --map title=fields.summary --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		if from != "" {
+			runForeignImport(cmd, from, args[0])
+			return
+		}
+		if cmd.Flags().Changed("map") || cmd.Flags().Changed("dry-run") {
+			fmt.Fprintf(os.Stderr, "Error: --map and --dry-run require --from\n")
+			os.Exit(1)
+		}
+
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		stats, err := store.Import(context.Background(), r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		printImportStats(stats)
+	},
+}
+
+// printImportStats prints what an import did
+func printImportStats(stats *types.ImportStats) {
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Imported %d issue(s)", green("✓"), stats.Issues)
+	if stats.SkippedIssues > 0 {
+		fmt.Printf(" (%d already present)", stats.SkippedIssues)
+	}
+	fmt.Println()
+	fmt.Printf("  Dependencies: %d\n", stats.Dependencies)
+	fmt.Printf("  Labels:       %d\n", stats.Labels)
+	fmt.Printf("  Events:       %d\n", stats.Events)
+	fmt.Printf("  Executions:   %d\n", stats.Executions)
+}
+
+// runForeignImport converts a backlog from another issue store and imports it
+func runForeignImport(cmd *cobra.Command, from, path string) {
+	pairs, _ := cmd.Flags().GetStringArray("map")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	mapping, err := importer.ParseMapping(pairs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(mapping) > 0 && from == importer.SourceBeads {
+		fmt.Fprintf(os.Stderr, "Error: --map only applies to --from json and --from csv\n")
+		os.Exit(1)
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		if from == importer.SourceBeads {
+			path = beadsIssuesFile(path)
+			if filepath.Ext(path) == ".db" {
+				fmt.Fprintf(os.Stderr, "Error: import Beads' issues.jsonl, not its database (run 'bd export -o issues.jsonl' first)\n")
+				os.Exit(1)
+			}
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	ctx := context.Background()
+	prefix, err := store.GetIssuePrefix(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	res, err := importer.Convert(from, r, importer.Options{Mapping: mapping, IssuePrefix: prefix, Actor: actor})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	yellow := color.New(color.FgYellow).SprintFunc()
+	for _, warning := range res.Warnings {
+		fmt.Printf("  %s %s\n", yellow("warning:"), warning)
+	}
+
+	if dryRun {
+		fmt.Printf("%s\n", color.YellowString("DRY RUN MODE - Nothing will be imported"))
+		fmt.Printf("Would import %d issue(s) with %d dependencies and %d label(s)\n",
+			len(res.Graph.Issues), len(res.Graph.Dependencies), len(res.Graph.Labels))
+		for _, issue := range res.Graph.Issues {
+			fmt.Printf("  %s [P%d] [%s] %s (%s)\n", issue.ID, issue.Priority, issue.IssueType, issue.Title, issue.Status)
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, res.Graph); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	stats, err := store.Import(ctx, &buf)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printImportStats(stats)
+}
+
+// beadsIssuesFile resolves a Beads project, .beads directory or issues file
+// to the issues.jsonl file to read
+func beadsIssuesFile(path string) string {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return path
+	}
+	for _, candidate := range []string{
+		filepath.Join(path, "issues.jsonl"),
+		filepath.Join(path, ".beads", "issues.jsonl"),
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return filepath.Join(path, "issues.jsonl")
+}
+
+func init() {
+	exportCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	exportCmd.Flags().String("format", "jsonl", "Output format: jsonl (the full graph), csv or json (one table)")
+	exportCmd.Flags().String("table", string(export.TableIssues), "Table to export with --format csv or json: issues, executions, gates or ai-usage")
+	exportCmd.Flags().String("since", "", "Only export rows from this date on, YYYY-MM-DD")
+	exportCmd.Flags().StringP("query", "q", "", "Filter by text in title, description or ID")
+	exportCmd.Flags().StringP("status", "s", "", "Filter by status")
+	exportCmd.Flags().IntP("priority", "p", 0, "Filter by priority")
+	exportCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
+	exportCmd.Flags().StringP("type", "t", "", "Filter by type")
+	exportCmd.Flags().StringSliceP("label", "l", nil, "Filter by label (repeatable; issues must have all)")
+	exportCmd.Flags().String("project", "", "Filter by project")
+	exportCmd.Flags().StringToString("field", nil, "Filter by custom field value, e.g. --field component=api (repeatable)")
+	exportCmd.Flags().IntP("limit", "n", 0, "Limit the issues reported on")
+	exportCmd.Flags().StringP("filter", "f", "", "Report on the issues of a saved filter")
+	importCmd.Flags().String("from", "", "Import a backlog from another issue store: "+strings.Join(importer.Sources, ", "))
+	importCmd.Flags().StringArray("map", nil, "Map a VC field to a source field, as vcfield=sourcefield (repeatable; json and csv only)")
+	importCmd.Flags().Bool("dry-run", false, "With --from, show what would be imported without importing")
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var fieldCmd = &cobra.Command{
+	Use:   "field",
+	Short: "Manage custom fields on issues",
+	Long: `Manage custom fields: typed attributes such as component, customer or
+SLA that issues can carry besides the built-in ones. A field is global or
+belongs to a project, whose definition then wins for the project's issues.
+Field values are shown to agents and can be filtered on with
+'vc list --field name=value'.`,
+	Example: `  vc field define customer --type string
+  vc field set vc-42 customer=acme
+  vc list --field customer=acme`,
+}
+
+var fieldListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List custom field definitions",
+	Example: `  vc field list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		fields, err := store.ListCustomFields(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Custom fields (%d):\n\n", cyan("🏷"), len(fields))
+		for _, f := range fields {
+			kind := string(f.Type)
+			if f.Type == types.CustomFieldEnum {
+				kind += " (" + strings.Join(f.Options, ", ") + ")"
+			}
+			fmt.Printf("  %-30s %s\n", types.QualifiedCustomFieldName(f.Project, f.Name), kind)
+			if f.Description != "" {
+				fmt.Printf("  %-30s %s\n", "", gray(f.Description))
+			}
+		}
+		fmt.Println()
+	},
+}
+
+var fieldDefineCmd = &cobra.Command{
+	Use:   "define [name]",
+	Short: "Create or update a custom field",
+	Long:  `Create or update a custom field definition.`,
+	Example: `  vc field define customer --type string
+  vc field define sla-hours --type number --description "Hours to resolve"
+  vc field define component --project web --type enum --options api,ui,payments`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		field := &types.CustomField{Name: args[0]}
+		field.Project, _ = cmd.Flags().GetString("project")
+		fieldType, _ := cmd.Flags().GetString("type")
+		field.Type = types.CustomFieldType(fieldType)
+		field.Description, _ = cmd.Flags().GetString("description")
+		field.Options, _ = cmd.Flags().GetStringSlice("options")
+
+		ctx := context.Background()
+		if err := store.SaveCustomField(ctx, field); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Saved field %s\n", green("✓"), types.QualifiedCustomFieldName(field.Project, field.Name))
+	},
+}
+
+var fieldDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a custom field definition",
+	Long: `Delete a custom field definition. Once no definition of the name is
+left (globally or in any project), its values are deleted from all issues.`,
+	Example: `  vc field delete customer
+  vc field delete sla-hours --project web   # Only the project's definition`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		project, _ := cmd.Flags().GetString("project")
+		ctx := context.Background()
+		if err := store.DeleteCustomField(ctx, project, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Deleted field %s\n", green("✓"), types.QualifiedCustomFieldName(project, args[0]))
+	},
+}
+
+var fieldSetCmd = &cobra.Command{
+	Use:   "set [issue-id] [name=value...]",
+	Short: "Set custom fields on an issue",
+	Long:  `Set custom fields on an issue. An empty value clears the field.`,
+	Example: `  vc field set vc-42 component=payments sla-hours=4
+  vc field set vc-42 customer=`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		green := color.New(color.FgGreen).SprintFunc()
+		for _, assignment := range args[1:] {
+			name, value, ok := strings.Cut(assignment, "=")
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: expected name=value, got %q\n", assignment)
+				os.Exit(1)
+			}
+			if err := store.SetCustomFieldValue(ctx, args[0], name, value, actor); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if value == "" {
+				fmt.Printf("%s Cleared %s on %s\n", green("✓"), name, args[0])
+			} else {
+				fmt.Printf("%s Set %s on %s\n", green("✓"), name, args[0])
+			}
+		}
+	},
+}
+
+func init() {
+	fieldDefineCmd.Flags().String("project", "", "Define the field for this project only")
+	fieldDefineCmd.Flags().String("type", string(types.CustomFieldString), "Field type: string, number or enum")
+	fieldDefineCmd.Flags().StringP("description", "d", "", "Field description")
+	fieldDefineCmd.Flags().StringSlice("options", nil, "Allowed values of an enum field (comma-separated)")
+	fieldDeleteCmd.Flags().String("project", "", "Delete the project's definition instead of the global one")
+
+	fieldCmd.AddCommand(fieldListCmd)
+	fieldCmd.AddCommand(fieldDefineCmd)
+	fieldCmd.AddCommand(fieldDeleteCmd)
+	fieldCmd.AddCommand(fieldSetCmd)
+	rootCmd.AddCommand(fieldCmd)
+}
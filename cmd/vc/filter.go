@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var filterCmd = &cobra.Command{
+	Use:   "filter",
+	Short: "Manage saved filters (run them with 'vc list --filter')",
+	Example: `  vc list --status open --label frontend --save frontend-open
+  vc list --filter frontend-open
+  vc filter delete frontend-open`,
+}
+
+var filterListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List saved filters",
+	Example: `  vc filter list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		filters, err := store.ListSavedFilters(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Saved filters (%d):\n\n", cyan("🔎"), len(filters))
+		for _, f := range filters {
+			fmt.Printf("  %-20s %s\n", f.Name, describeSavedFilter(f))
+			if f.Description != "" {
+				fmt.Printf("  %-20s %s\n", "", gray(f.Description))
+			}
+		}
+		fmt.Println()
+	},
+}
+
+var filterDeleteCmd = &cobra.Command{
+	Use:     "delete [name]",
+	Short:   "Delete a saved filter",
+	Example: `  vc filter delete frontend-open`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		if err := store.DeleteFilter(ctx, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Deleted filter %s\n", green("✓"), args[0])
+	},
+}
+
+// describeSavedFilter renders a saved filter's predicates as the vc list
+// flags that reproduce it
+func describeSavedFilter(f *types.SavedFilter) string {
+	var parts []string
+	if f.Query != "" {
+		parts = append(parts, fmt.Sprintf("--query %q", f.Query))
+	}
+	if f.Status != "" {
+		parts = append(parts, "--status "+string(f.Status))
+	}
+	if f.Priority != nil {
+		parts = append(parts, fmt.Sprintf("--priority %d", *f.Priority))
+	}
+	if f.IssueType != "" {
+		parts = append(parts, "--type "+string(f.IssueType))
+	}
+	if f.Assignee != "" {
+		parts = append(parts, "--assignee "+f.Assignee)
+	}
+	for _, label := range f.Labels {
+		parts = append(parts, "--label "+label)
+	}
+	if f.Project != "" {
+		parts = append(parts, "--project "+f.Project)
+	}
+	names := make([]string, 0, len(f.CustomFields))
+	for name := range f.CustomFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("--field %s=%s", name, f.CustomFields[name]))
+	}
+	if f.Limit > 0 {
+		parts = append(parts, fmt.Sprintf("--limit %d", f.Limit))
+	}
+	if len(parts) == 0 {
+		return "(all issues)"
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	filterCmd.AddCommand(filterListCmd)
+	filterCmd.AddCommand(filterDeleteCmd)
+	rootCmd.AddCommand(filterCmd)
+}
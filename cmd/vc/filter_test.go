@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestDescribeSavedFilter(t *testing.T) {
+	p1 := 1
+	f := &types.SavedFilter{Status: types.StatusOpen, Priority: &p1, Labels: []string{"frontend", "ui"}, Query: "fix login"}
+	want := `--query "fix login" --status open --priority 1 --label frontend --label ui`
+	if got := describeSavedFilter(f); got != want {
+		t.Errorf("describeSavedFilter = %q, want %q", got, want)
+	}
+	f = &types.SavedFilter{Project: "web", CustomFields: map[string]string{"tier": "gold", "component": "api"}}
+	want = `--project web --field component=api --field tier=gold`
+	if got := describeSavedFilter(f); got != want {
+		t.Errorf("describeSavedFilter = %q, want %q", got, want)
+	}
+	if got := describeSavedFilter(&types.SavedFilter{}); got != "(all issues)" {
+		t.Errorf("describeSavedFilter(empty) = %q", got)
+	}
+}
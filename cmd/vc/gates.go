@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+var gatesCmd = &cobra.Command{
+	Use:     "gates",
+	Short:   "Quality gate commands",
+	Long:    `Inspect the quality gates the executor runs after agent work (configured in .vc/gates.yaml).`,
+	Example: `  vc gates explain`,
+}
+
+var gatesExplainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Show which gates would run, without running them",
+	Long: `Show which quality gates would run for the current repo and config, in what
+order, with which commands and timeouts. Nothing is executed.
+
+Use this to debug .vc/gates.yaml before the executor runs it.`,
+	Example: `  # Explain the gate run for the current project
+  vc gates explain
+
+  # Include approved gate overrides for an issue
+  vc gates explain --issue vc-123
+
+  # Explain gates for another directory (e.g. a sandbox)
+  vc gates explain --dir .sandboxes/mission-vc-42
+
+  # The gate commands, for a script
+  vc gates explain --json | jq -r '.gates[].command'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		issueID, _ := cmd.Flags().GetString("issue")
+
+		ctx := context.Background()
+
+		if dir == "" {
+			projectRoot, err := storage.GetProjectRoot(dbPath)
+			if err != nil {
+				exitWithError(exitError, err)
+			}
+			dir = projectRoot
+		}
+
+		var overrides []*gates.GateOverride
+		var pending []gates.PendingOverride
+		if issueID != "" {
+			var err error
+			// Same rule as the executor: automated actors can't approve overrides
+			overrides, pending, err = gates.ResolveOverrides(ctx, store, issueID, gates.AutomatedActors(ctx, store))
+			if err != nil {
+				exitWithError(exitError, fmt.Errorf("failed to resolve gate overrides for %s: %w", issueID, err))
+			}
+			if !jsonOutput {
+				for _, p := range pending {
+					fmt.Printf("Note: override for %s gate not applied: %s\n", p.Gate, p.Reason)
+				}
+			}
+		}
+
+		runner, err := gates.NewRunner(&gates.Config{
+			Store:      store,
+			WorkingDir: dir,
+			Overrides:  overrides,
+		})
+		if err != nil {
+			exitWithError(exitError, err)
+		}
+
+		if jsonOutput {
+			printJSON(newGatePlanJSON(runner.Explain(), pending))
+			return
+		}
+		printGatePlan(runner.Explain())
+	},
+}
+
+// gatePlanJSON is the --json output of vc gates explain
+type gatePlanJSON struct {
+	WorkingDir     string            `json:"working_dir"`
+	ConfigFile     string            `json:"config_file,omitempty"` // Empty: built-in gates
+	Timeout        string            `json:"timeout"`
+	Budget         string            `json:"budget,omitempty"`
+	CustomProvider bool              `json:"custom_provider"` // Gates is empty: they can't be explained
+	Gates          []plannedGateJSON `json:"gates"`
+	// PendingOverrides are overrides for --issue that weren't applied
+	PendingOverrides []pendingOverrideJSON `json:"pending_overrides"`
+	Mutation         *mutationJSON         `json:"mutation,omitempty"` // Scheduled separately, if enabled
+}
+
+// plannedGateJSON is a gate in vc gates explain --json
+type plannedGateJSON struct {
+	Gate       string   `json:"gate"`
+	Command    string   `json:"command"`
+	Shell      string   `json:"shell,omitempty"`
+	Needs      []string `json:"needs"`
+	Timeout    string   `json:"timeout,omitempty"`
+	Required   bool     `json:"required"`
+	SkipReason string   `json:"skip_reason,omitempty"`
+}
+
+// pendingOverrideJSON is a gate override that wasn't applied, and why
+type pendingOverrideJSON struct {
+	Gate   string `json:"gate"`
+	Reason string `json:"reason"`
+}
+
+// mutationJSON is the scheduled mutation gate
+type mutationJSON struct {
+	Command  []string `json:"command"`
+	Interval string   `json:"interval"` // "0s": high-risk issues only
+	Error    string   `json:"error,omitempty"`
+}
+
+// newGatePlanJSON converts a gate plan for --json
+func newGatePlanJSON(plan *gates.Plan, pending []gates.PendingOverride) *gatePlanJSON {
+	out := &gatePlanJSON{
+		WorkingDir:       plan.WorkingDir,
+		ConfigFile:       plan.ConfigFile,
+		Timeout:          gatesTimeout().String(),
+		CustomProvider:   plan.CustomProvider,
+		Gates:            []plannedGateJSON{},
+		PendingOverrides: []pendingOverrideJSON{},
+	}
+	if plan.Budget != nil {
+		out.Budget = plan.Budget.Total
+	}
+	for _, gate := range plan.Gates {
+		needs := []string{}
+		for _, need := range gate.Needs {
+			needs = append(needs, string(need))
+		}
+		out.Gates = append(out.Gates, plannedGateJSON{
+			Gate:       string(gate.Gate),
+			Command:    gate.Command,
+			Shell:      gate.Shell,
+			Needs:      needs,
+			Timeout:    gate.Timeout,
+			Required:   gate.Required,
+			SkipReason: gate.SkipReason,
+		})
+	}
+	for _, p := range pending {
+		out.PendingOverrides = append(out.PendingOverrides, pendingOverrideJSON{Gate: string(p.Gate), Reason: p.Reason})
+	}
+	mutation, err := gates.MutationConfigFromEnv()
+	if err != nil {
+		out.Mutation = &mutationJSON{Command: []string{}, Error: err.Error()}
+	} else if mutation.Enabled {
+		out.Mutation = &mutationJSON{Command: mutation.Command, Interval: mutation.Interval.String()}
+	}
+	return out
+}
+
+// gatesTimeout is the overall gate timeout, from the same source and with
+// the same default as the executor (vc-xcfw)
+func gatesTimeout() time.Duration {
+	if value := os.Getenv("VC_QUALITY_GATES_TIMEOUT"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return 5 * time.Minute
+}
+
+// printGatePlan prints a gate plan in human-readable form
+func printGatePlan(plan *gates.Plan) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Printf("\nGate plan for %s\n", plan.WorkingDir)
+	if plan.ConfigFile != "" {
+		fmt.Printf("Config: %s\n", plan.ConfigFile)
+	} else {
+		fmt.Printf("Config: %s\n", gray("none (built-in gates)"))
+	}
+
+	fmt.Printf("Overall timeout: %v (VC_QUALITY_GATES_TIMEOUT)\n", gatesTimeout())
+
+	if plan.Budget != nil {
+		fmt.Printf("Budget: %s (optional gates are skipped once it is used up)\n", plan.Budget.Total)
+	}
+
+	if plan.CustomProvider {
+		fmt.Printf("\n%s A custom gate provider is configured; its gates can't be explained\n", yellow("⚠"))
+		return
+	}
+
+	fmt.Println()
+	for i, gate := range plan.Gates {
+		fmt.Printf("%2d. %s\n", i+1, cyan(gate.Gate))
+		if gate.Shell != "" {
+			fmt.Printf("    run:     %s -> %s\n", gate.Shell, gate.Command)
+		} else {
+			fmt.Printf("    run:     %s\n", gate.Command)
+		}
+		if len(gate.Needs) > 0 {
+			needs := make([]string, 0, len(gate.Needs))
+			for _, need := range gate.Needs {
+				needs = append(needs, string(need))
+			}
+			fmt.Printf("    needs:   %s\n", strings.Join(needs, ", "))
+		}
+		if gate.Timeout != "" {
+			fmt.Printf("    timeout: %s\n", gate.Timeout)
+		}
+		if !gate.Required {
+			fmt.Printf("    %s\n", gray("optional: skipped if the budget is used up"))
+		}
+		if gate.SkipReason != "" {
+			fmt.Printf("    %s\n", yellow("⊘ would be skipped: "+gate.SkipReason))
+		}
+	}
+
+	mutation, err := gates.MutationConfigFromEnv()
+	if err != nil {
+		fmt.Printf("\n%s Mutation gate config is invalid: %v\n", yellow("⚠"), err)
+	} else if mutation.Enabled {
+		schedule := fmt.Sprintf("at most every %v", mutation.Interval)
+		if mutation.Interval == 0 {
+			schedule = "high-risk issues only"
+		}
+		fmt.Printf("\nScheduled separately: mutation (%s, %s)\n", strings.Join(mutation.Command, " "), schedule)
+	}
+	fmt.Println()
+}
+
+func init() {
+	gatesExplainCmd.Flags().String("dir", "", "Directory to explain gates for (default: project root)")
+	gatesExplainCmd.Flags().String("issue", "", "Issue ID whose approved gate overrides should be applied")
+	gatesCmd.AddCommand(gatesExplainCmd)
+	rootCmd.AddCommand(gatesCmd)
+}
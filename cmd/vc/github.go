@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+)
+
+var githubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Mirror issues to GitHub Issues and back",
+	Long: `Mirror VC issues to the GitHub repository's issues and back, so people who
+live in GitHub can follow and steer the backlog without VC tooling.
+
+Unclosed VC issues are opened on GitHub, and open GitHub issues are imported
+into VC; with VC_GITHUB_SYNC_LABEL, only issues with that label. Once linked,
+changes to the title, description, acceptance criteria (an "Acceptance
+Criteria" section on GitHub), open/closed state, priority (P0-P4 labels on
+GitHub), labels and, with VC_GITHUB_SYNC_MILESTONE_PREFIX, milestones (VC
+labels made of the prefix and the milestone's title) are copied both ways,
+and so are new comments. When both sides changed the same field since the
+last sync, the side updated last wins (see VC_GITHUB_SYNC_PREFER); every
+such conflict is listed.
+
+The repository, token and API URL are those of the git hosting integration
+(VC_GITHUB_REPO or the remote's URL, VC_GITHUB_TOKEN, VC_GITHUB_API_URL).
+With VC_GITHUB_SYNC=true the executor syncs every
+VC_GITHUB_SYNC_INTERVAL_MINUTES. The state of the last sync is kept in
+.beads/sync/ next to the database.`,
+	Example: `  vc github sync
+  vc github sync --prefer vc`,
+}
+
+var githubSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync issues and comments with GitHub Issues",
+	Example: `  vc github sync                  # Conflicts go to the side updated last
+  vc github sync --prefer github  # GitHub wins conflicts`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		syncConfig, err := config.GitHubSyncConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		runIssueSync(cmd, syncConfig)
+	},
+}
+
+func init() {
+	githubSyncCmd.Flags().String("prefer", "", "Conflict winner: newer, vc or github (default: $VC_GITHUB_SYNC_PREFER, else newer)")
+	githubCmd.AddCommand(githubSyncCmd)
+	rootCmd.AddCommand(githubCmd)
+}
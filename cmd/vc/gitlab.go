@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+)
+
+var gitlabCmd = &cobra.Command{
+	Use:   "gitlab",
+	Short: "Mirror issues to GitLab issues and back",
+	Long: `Mirror VC issues to the GitLab project's issues and back, so teams working
+entirely in GitLab can follow and steer the backlog without VC tooling.
+
+Unclosed VC issues are opened on GitLab, and open GitLab issues are imported
+into VC; with VC_GITLAB_SYNC_LABEL, only issues with that label. Once linked,
+changes to the title, description, acceptance criteria (an "Acceptance
+Criteria" section on GitLab), open/closed state, priority (P0-P4 labels on
+GitLab), labels and, with VC_GITLAB_SYNC_MILESTONE_PREFIX, milestones (VC
+labels made of the prefix and the milestone's title) are copied both ways,
+and so are new comments. When both sides changed the same field since the
+last sync, the side updated last wins (see VC_GITLAB_SYNC_PREFER); every
+such conflict is listed.
+
+The project, token and API URL are those of the git hosting integration
+(VC_GITLAB_PROJECT or the remote's URL, VC_GITLAB_TOKEN, VC_GITLAB_API_URL).
+With VC_GITLAB_SYNC=true the executor syncs every
+VC_GITLAB_SYNC_INTERVAL_MINUTES. The state of the last sync is kept in
+.beads/sync/ next to the database.`,
+	Example: `  vc gitlab sync
+  VC_GITLAB_SYNC_MILESTONE_PREFIX=milestone: vc gitlab sync`,
+}
+
+var gitlabSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync issues and comments with GitLab issues",
+	Example: `  vc gitlab sync                  # Conflicts go to the side updated last
+  vc gitlab sync --prefer gitlab  # GitLab wins conflicts`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		syncConfig, err := config.GitLabSyncConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		runIssueSync(cmd, syncConfig)
+	},
+}
+
+func init() {
+	gitlabSyncCmd.Flags().String("prefer", "", "Conflict winner: newer, vc or gitlab (default: $VC_GITLAB_SYNC_PREFER, else newer)")
+	gitlabCmd.AddCommand(gitlabSyncCmd)
+	rootCmd.AddCommand(gitlabCmd)
+}
@@ -29,15 +29,14 @@ Health monitors use AI to detect issues like:
 - Missing tests
 
 All monitors are ZFC-compliant: they collect facts and defer judgment to AI.`,
+	Example: `  vc health check`,
 }
 
 var healthCheckCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Run health monitors and report findings",
-	Long: `Run health monitors to check code quality and file issues.
-
-Examples:
-  # Run all monitors
+	Long:  `Run health monitors to check code quality and file issues.`,
+	Example: `  # Run all monitors
   vc health check
 
   # Run specific monitor
@@ -29,10 +29,8 @@ This creates:
 If no project name is provided, the current directory name is used.
 
 With --discover flag, also runs discovery workers to bootstrap the issue tracker
-with actionable issues found in the codebase.
-
-Example:
-  cd ~/myproject
+with actionable issues found in the codebase.`,
+	Example: `  cd ~/myproject
   vc init                          # Creates .beads/myproject.db
   vc init myapp                    # Creates .beads/myapp.db
   vc init --discover               # Initialize and run discovery (standard preset)
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/hosting"
+	"github.com/steveyegge/vc/internal/issuesync"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// runIssueSync runs one issues sync for 'vc github sync' and 'vc gitlab
+// sync', with the conflict preference of the --prefer flag if set
+func runIssueSync(cmd *cobra.Command, syncConfig config.IssueSyncConfig) {
+	if cmd.Flags().Changed("prefer") {
+		syncConfig.Prefer, _ = cmd.Flags().GetString("prefer")
+		if err := syncConfig.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx := context.Background()
+	syncer, repo, err := newIssueSyncer(ctx, syncConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	stats, err := syncer.Sync(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	remote := hosting.DisplayName(syncConfig.Provider)
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	fmt.Printf("%s Synced with %s %s\n", green("✓"), remote, repo)
+	printIssueSyncSide("VC", stats.VC)
+	printIssueSyncSide(remote, stats.Remote)
+	if stats.Relinked > 0 {
+		fmt.Printf("  Relinked %d issue(s) opened on %s by an earlier sync\n", stats.Relinked, remote)
+	}
+	for _, c := range stats.Conflicts {
+		side, winner := "VC", c.Local
+		if c.Winner == types.SyncRemote {
+			side, winner = remote, c.Remote
+		}
+		fmt.Printf("  %s %s %s: kept %s value %q\n", yellow("conflict"), c.IssueID, c.Field, side, winner)
+	}
+}
+
+// printIssueSyncSide prints what an issues sync changed on one side
+func printIssueSyncSide(label string, s issuesync.SideStats) {
+	if !s.Changed() {
+		fmt.Printf("  %s: no changes\n", label)
+		return
+	}
+	fmt.Printf("  %s: %d issue(s) created, %d updated, %d comment(s) copied\n",
+		label, s.IssuesCreated, s.IssuesUpdated, s.CommentsAdded)
+}
+
+// newIssueSyncer returns a syncer for the repository of cfg's provider in
+// the git hosting configuration (VC_GITHUB_REPO or VC_GITLAB_PROJECT, else
+// the remote's URL), and the repository's path
+func newIssueSyncer(ctx context.Context, cfg config.IssueSyncConfig) (*issuesync.Syncer, string, error) {
+	remote := hosting.DisplayName(cfg.Provider)
+	if memoryStore {
+		return nil, "", fmt.Errorf("cannot sync a --memory database with %s", remote)
+	}
+	hostingConfig, err := config.HostingConfigFromEnv()
+	if err != nil {
+		return nil, "", err
+	}
+	var configuredRepo string
+	switch cfg.Provider {
+	case config.HostingGitHub:
+		if hostingConfig.GitHub.Token == "" {
+			return nil, "", fmt.Errorf("no GitHub token (set VC_GITHUB_TOKEN, GITHUB_TOKEN or GH_TOKEN)")
+		}
+		configuredRepo = hostingConfig.GitHub.Repo
+	case config.HostingGitLab:
+		if hostingConfig.GitLab.Token == "" {
+			return nil, "", fmt.Errorf("no GitLab token (set VC_GITLAB_TOKEN or GITLAB_TOKEN)")
+		}
+		configuredRepo = hostingConfig.GitLab.Project
+	}
+	hostingConfig.Provider = cfg.Provider
+
+	var remoteURL string
+	if configuredRepo == "" {
+		projectRoot, err := storage.GetProjectRoot(dbPath)
+		if err != nil {
+			return nil, "", err
+		}
+		gitOps, err := git.NewGit(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		if remoteURL, err = gitOps.RemoteURL(ctx, projectRoot, hostingConfig.Remote); err != nil {
+			return nil, "", err
+		}
+	}
+	provider, err := hosting.Open(hostingConfig, remoteURL)
+	if err != nil {
+		return nil, "", err
+	}
+	client, ok := provider.(hosting.IssueTracker)
+	if !ok || client.Name() != cfg.Provider {
+		return nil, "", fmt.Errorf("git hosting provider %s is not %s", provider.Name(), remote)
+	}
+	statePath := issuesync.StatePath(filepath.Dir(dbPath), client.Name(), client.Repo())
+	return issuesync.NewSyncer(store, client, cfg, statePath), client.Repo(), nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/jira"
+)
+
+var jiraCmd = &cobra.Command{
+	Use:   "jira",
+	Short: "Import work from Jira and report progress back",
+	Long: `Connect VC to a Jira project, so work planned in Jira reaches the executor
+and its progress shows up in Jira.
+
+Unresolved issues of the project (narrowed by VC_JIRA_JQL) are imported:
+epics as missions, and other issues as VC issues under the mission of
+their epic. VC fields are read from the Jira fields of VC_JIRA_FIELD_MAP,
+and read again when they change in Jira. When an imported issue changes
+status in VC, the Jira issue is moved to the status VC_JIRA_STATUS_MAP
+gives, or the change is commented when no transition leads there. Every
+finished execution is summarized in a comment.
+
+Set VC_JIRA_URL, VC_JIRA_PROJECT and VC_JIRA_TOKEN (with VC_JIRA_EMAIL
+for a Jira Cloud API token). With VC_JIRA_SYNC=true the executor syncs
+every VC_JIRA_SYNC_INTERVAL_MINUTES. Imported issues are labelled
+jira:KEY, and the state of the last sync is kept in .beads/sync/ next to
+the database.`,
+	Example: `  vc jira sync`,
+}
+
+var jiraSyncCmd = &cobra.Command{
+	Use:     "sync",
+	Short:   "Import Jira issues and post progress to Jira",
+	Example: `  VC_JIRA_JQL='labels = vc' vc jira sync`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		jiraConfig, err := config.JiraConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		syncer, err := newJiraSyncer(jiraConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		stats, err := syncer.Sync(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Synced with Jira project %s\n", green("✓"), jiraConfig.Project)
+		if !stats.Changed() {
+			fmt.Println("  No changes")
+			return
+		}
+		fmt.Printf("  VC: %d mission(s) and %d issue(s) imported, %d updated\n",
+			stats.MissionsImported, stats.IssuesImported, stats.IssuesUpdated)
+		fmt.Printf("  Jira: %d transition(s), %d comment(s)\n", stats.Transitions, stats.Comments)
+		if stats.Relinked > 0 {
+			fmt.Printf("  Relinked %d issue(s) imported by an earlier sync\n", stats.Relinked)
+		}
+	},
+}
+
+// newJiraSyncer returns a syncer for the configured Jira project
+func newJiraSyncer(cfg config.JiraConfig) (*jira.Syncer, error) {
+	if memoryStore {
+		return nil, fmt.Errorf("cannot sync a --memory database with Jira")
+	}
+	if !cfg.Configured() {
+		return nil, fmt.Errorf("Jira is not configured (set VC_JIRA_URL, VC_JIRA_PROJECT and VC_JIRA_TOKEN)")
+	}
+	statePath := jira.StatePath(filepath.Dir(dbPath), cfg.Project)
+	return jira.NewSyncer(store, jira.NewClient(cfg), cfg, statePath), nil
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraSyncCmd)
+	rootCmd.AddCommand(jiraCmd)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/cost"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// jsonOutput is set by --json: commands print a single JSON document on
+// stdout instead of text, and errors as JSON on stderr
+var jsonOutput bool
+
+// Exit codes, so scripts can tell outcomes apart without parsing output
+const (
+	exitOK             = 0 // The command succeeded
+	exitError          = 1 // The command failed
+	exitCritical       = 2 // vc doctor: VC can't run until critical problems are fixed
+	exitNotFound       = 3 // A named issue doesn't exist
+	exitBudgetExceeded = 4 // With --json, vc status and vc cost: the AI cost budget is exceeded
+)
+
+// jsonCommands are the commands that support --json. The schemas are
+// stable: fields may be added, but aren't renamed or removed.
+func jsonCommands() map[*cobra.Command]bool {
+	return map[*cobra.Command]bool{
+		createCmd:       true,
+		showCmd:         true,
+		listCmd:         true,
+		updateCmd:       true,
+		closeCmd:        true,
+		readyCmd:        true,
+		blockedCmd:      true,
+		statsCmd:        true,
+		statusCmd:       true,
+		costCmd:         true,
+		executionsCmd:   true,
+		missionsCmd:     true,
+		timelineCmd:     true,
+		gatesExplainCmd: true,
+		configShowCmd:   true,
+	}
+}
+
+// printJSON prints v as indented JSON on stdout
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		exitWithError(exitError, err)
+	}
+}
+
+// exitWithError reports err, as {"error": ..., "exit_code": ...} with --json,
+// and exits with code
+func exitWithError(code int, err error) {
+	if jsonOutput {
+		out, _ := json.Marshal(struct {
+			Error    string `json:"error"`
+			ExitCode int    `json:"exit_code"`
+		}{err.Error(), code})
+		fmt.Fprintln(os.Stderr, string(out))
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// issueListJSON is the --json output of commands that list issues
+type issueListJSON struct {
+	Count  int            `json:"count"`
+	Issues []*types.Issue `json:"issues"`
+}
+
+// newIssueListJSON lists issues, as [] rather than null when there are none
+func newIssueListJSON(issues []*types.Issue) issueListJSON {
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+	return issueListJSON{Count: len(issues), Issues: issues}
+}
+
+// issueRefJSON refers to a related issue in vc show --json
+type issueRefJSON struct {
+	ID       string       `json:"id"`
+	Title    string       `json:"title"`
+	Status   types.Status `json:"status"`
+	Priority int          `json:"priority"`
+}
+
+// issueRefs refers to each of issues
+func issueRefs(issues []*types.Issue) []issueRefJSON {
+	refs := []issueRefJSON{}
+	for _, issue := range issues {
+		refs = append(refs, issueRefJSON{ID: issue.ID, Title: issue.Title, Status: issue.Status, Priority: issue.Priority})
+	}
+	return refs
+}
+
+// issueDetailJSON is the --json output of vc show
+type issueDetailJSON struct {
+	*types.Issue
+	Labels    []string                  `json:"labels"`
+	Fields    []*types.CustomFieldValue `json:"fields"`
+	DependsOn []issueRefJSON            `json:"depends_on"`
+	Blocks    []issueRefJSON            `json:"blocks"`
+	Commits   []commitJSON              `json:"commits"`
+	Comments  []*types.Comment          `json:"comments"` // Threaded: replies are nested
+}
+
+// commitJSON is a commit made by an execution of an issue
+type commitJSON struct {
+	Hash        string    `json:"hash"`
+	ExecutionID int64     `json:"execution_id"`
+	StartedAt   time.Time `json:"started_at"`
+}
+
+// budgetJSON is the AI cost budget in vc status and vc cost --json
+type budgetJSON struct {
+	Enabled bool `json:"enabled"`
+	// Status is healthy, warning or exceeded; the rest is omitted when
+	// budgeting is disabled
+	Status           string     `json:"status,omitempty"`
+	HourlyTokensUsed int64      `json:"hourly_tokens_used"`
+	MaxTokensPerHour int64      `json:"max_tokens_per_hour"` // 0 = unlimited
+	HourlyCostUSD    float64    `json:"hourly_cost_usd"`
+	MaxCostPerHour   float64    `json:"max_cost_per_hour_usd"` // 0 = unlimited
+	TotalTokensUsed  int64      `json:"total_tokens_used"`
+	TotalCostUSD     float64    `json:"total_cost_usd"`
+	WindowStart      *time.Time `json:"window_start,omitempty"`
+	ResetsAt         *time.Time `json:"resets_at,omitempty"`
+	Error            string     `json:"error,omitempty"` // Why the budget couldn't be read
+}
+
+// newBudgetJSON reads the AI cost budget as configured in the environment
+func newBudgetJSON() *budgetJSON {
+	cfg := cost.LoadFromEnv()
+	out := &budgetJSON{Enabled: cfg.Enabled}
+	if !cfg.Enabled {
+		return out
+	}
+	tracker, err := cost.NewTracker(cfg, store)
+	if err != nil {
+		out.Error = err.Error()
+		return out
+	}
+	stats := tracker.GetStats()
+	windowStart := stats.WindowStartTime
+	resetsAt := windowStart.Add(cfg.BudgetResetInterval)
+	out.Status = strings.ToLower(stats.Status.String())
+	out.HourlyTokensUsed = stats.HourlyTokensUsed
+	out.MaxTokensPerHour = cfg.MaxTokensPerHour
+	out.HourlyCostUSD = stats.HourlyCostUsed
+	out.MaxCostPerHour = cfg.MaxCostPerHour
+	out.TotalTokensUsed = stats.TotalTokensUsed
+	out.TotalCostUSD = stats.TotalCostUsed
+	out.WindowStart = &windowStart
+	out.ResetsAt = &resetsAt
+	return out
+}
+
+// exceeded reports whether the budget is enabled and exceeded
+func (b *budgetJSON) exceeded() bool {
+	return b.Status == strings.ToLower(cost.BudgetExceeded.String())
+}
+
+// issueDetail collects what vc show prints about an issue
+func issueDetail(ctx context.Context, issue *types.Issue) *issueDetailJSON {
+	out := &issueDetailJSON{
+		Issue:     issue,
+		Labels:    []string{},
+		Fields:    []*types.CustomFieldValue{},
+		DependsOn: []issueRefJSON{},
+		Blocks:    []issueRefJSON{},
+		Commits:   []commitJSON{},
+		Comments:  []*types.Comment{},
+	}
+	if labels, _ := store.GetLabels(ctx, issue.ID); labels != nil {
+		out.Labels = labels
+	}
+	if fields, _ := store.GetCustomFieldValues(ctx, issue.ID); fields != nil {
+		out.Fields = fields
+	}
+	deps, _ := store.GetDependencies(ctx, issue.ID)
+	out.DependsOn = issueRefs(deps)
+	dependents, _ := store.GetDependents(ctx, issue.ID)
+	out.Blocks = issueRefs(dependents)
+	executions, _ := store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID})
+	for _, e := range executions {
+		if e.CommitHash != "" {
+			out.Commits = append(out.Commits, commitJSON{Hash: e.CommitHash, ExecutionID: e.ID, StartedAt: e.StartedAt})
+		}
+	}
+	if comments, _ := store.GetComments(ctx, issue.ID); len(comments) > 0 {
+		out.Comments = types.ThreadComments(comments)
+	}
+	return out
+}
+
+// closeResultJSON is the --json output of vc close
+type closeResultJSON struct {
+	Closed []string           `json:"closed"`
+	Failed []closeFailureJSON `json:"failed"`
+}
+
+// closeFailureJSON is an issue vc close couldn't close
+type closeFailureJSON struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestIssueDetailJSON(t *testing.T) {
+	originalStore := store
+	store = memory.New()
+	defer func() { store = originalStore }()
+
+	ctx := context.Background()
+	blocker := &types.Issue{Title: "Blocker", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	issue := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	for _, i := range []*types.Issue{blocker, issue} {
+		if err := store.CreateIssue(ctx, i, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: issue.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "backend", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	data, err := json.Marshal(issueDetail(ctx, issue))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	// The issue's own fields are inlined next to what vc show adds
+	if got["id"] != issue.ID || got["status"] != "open" {
+		t.Errorf("expected the issue's fields, got %s", data)
+	}
+	if labels, _ := got["labels"].([]interface{}); len(labels) != 1 || labels[0] != "backend" {
+		t.Errorf("expected labels [backend], got %v", got["labels"])
+	}
+	deps, _ := got["depends_on"].([]interface{})
+	if len(deps) != 1 || deps[0].(map[string]interface{})["id"] != blocker.ID {
+		t.Errorf("expected a dependency on %s, got %v", blocker.ID, got["depends_on"])
+	}
+
+	// Empty lists are [] rather than null, so scripts can iterate them
+	for _, key := range []string{"fields", "blocks", "commits", "comments"} {
+		if list, ok := got[key].([]interface{}); !ok || len(list) != 0 {
+			t.Errorf("expected %s to be an empty list, got %v", key, got[key])
+		}
+	}
+}
+
+func TestIssueListJSON(t *testing.T) {
+	data, err := json.Marshal(newIssueListJSON(nil))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"count":0,"issues":[]}` {
+		t.Errorf("unexpected JSON for no issues: %s", data)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage labels (descriptions, colors, rename, delete)",
+	Example: `  vc label list
+  vc label define frontend --description "UI work"
+  vc label rename ui frontend`,
+}
+
+var labelListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List labels and how many issues carry each",
+	Example: `  vc label list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		defs, err := store.ListLabelDefinitions(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Labels (%d):\n\n", cyan("🏷"), len(defs))
+		for _, def := range defs {
+			marker := " "
+			if def.System {
+				marker = gray("*")
+			}
+			fmt.Printf("  %s %-28s %5d  %-7s  %s\n", marker, def.Name, def.IssueCount, def.Color, gray(def.Description))
+		}
+		fmt.Printf("\n  %s\n\n", gray("* system label (cannot be renamed or deleted)"))
+	},
+}
+
+var labelDefineCmd = &cobra.Command{
+	Use:   "define [name]",
+	Short: "Create or update a label's description and color",
+	Long:  `Create or update a label definition. Only the flags given are changed.`,
+	Example: `  vc label define frontend --description "UI work" --color "#1f77b4"
+  vc label define frontend --color ""`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		def, err := store.GetLabelDefinition(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if def == nil {
+			def = &types.LabelDefinition{Name: args[0]}
+		}
+		if cmd.Flags().Changed("description") {
+			def.Description, _ = cmd.Flags().GetString("description")
+		}
+		if cmd.Flags().Changed("color") {
+			def.Color, _ = cmd.Flags().GetString("color")
+		}
+
+		if err := store.SaveLabelDefinition(ctx, def); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Saved label %s\n", green("✓"), def.Name)
+	},
+}
+
+var labelRenameCmd = &cobra.Command{
+	Use:     "rename [old-name] [new-name]",
+	Short:   "Rename a label on every issue that carries it",
+	Example: `  vc label rename ui frontend`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		count, err := store.RenameLabel(ctx, args[0], args[1], actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Renamed label %s to %s on %d issue(s)\n", green("✓"), args[0], args[1], count)
+	},
+}
+
+var labelDeleteCmd = &cobra.Command{
+	Use:     "delete [name]",
+	Short:   "Delete a label and remove it from every issue",
+	Example: `  vc label delete wontfix`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		count, err := store.DeleteLabel(ctx, args[0], actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Deleted label %s from %d issue(s)\n", green("✓"), args[0], count)
+	},
+}
+
+func init() {
+	labelDefineCmd.Flags().StringP("description", "d", "", "Label description")
+	labelDefineCmd.Flags().StringP("color", "c", "", "Label color as #rrggbb (empty for none)")
+
+	labelCmd.AddCommand(labelListCmd)
+	labelCmd.AddCommand(labelDefineCmd)
+	labelCmd.AddCommand(labelRenameCmd)
+	labelCmd.AddCommand(labelDeleteCmd)
+	rootCmd.AddCommand(labelCmd)
+}
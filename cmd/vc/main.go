@@ -8,50 +8,122 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/logging"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/tracing"
 	"github.com/steveyegge/vc/internal/types"
 )
 
 var (
-	dbPath string
-	actor  string
-	store  storage.Storage
+	dbPath      string
+	actor       string
+	memoryStore bool
+	readOnly    bool
+	store       storage.Storage
+
+	// stopTracing flushes exported spans on exit
+	stopTracing = func(context.Context) error { return nil }
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "vc",
 	Short: "VC - AI-orchestrated coding agent colony",
-	Long:  `VibeCoder v2: Orchestrate coding agents to work on small, well-defined tasks with AI supervision.`,
+	Long: `VibeCoder v2: Orchestrate coding agents to work on small, well-defined tasks with AI supervision.
+
+With --json, commands that support it print one JSON document on stdout, for
+jq and scripts, and errors as {"error": ..., "exit_code": ...} on stderr.
+
+Exit codes:
+  0  Success
+  1  The command failed
+  2  vc doctor: VC can't run until critical problems are fixed
+  3  A named issue doesn't exist
+  4  With --json, vc status and vc cost: the AI cost budget is exceeded`,
+	Example: `  vc init                                      # Set up a tracker in this repository
+  vc create "Fix login timeout" -t bug -p 1    # File work
+  vc ready                                     # See what can be worked on
+  vc execute --enable-auto-commit              # Let agents work the queue
+  vc list --status open --json | jq -r '.issues[].id'`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		// Skip database initialization for init command
-		if cmd.Name() == "init" {
+		// Shell completion reads the database if there is one, and must not
+		// fail or print anything if there isn't
+		if cmd.Name() == cobra.ShellCompRequestCmd {
+			_ = loadConfigFile()
+			store = openCompletionStore()
+			return
+		}
+
+		if jsonOutput && !jsonCommands()[cmd] {
+			exitWithError(exitError, fmt.Errorf("'%s' doesn't support --json", cmd.CommandPath()))
+		}
+
+		// Settings in .vc/config.yaml apply unless the environment sets them
+		if err := loadConfigFile(); err != nil {
+			exitWithError(exitError, err)
+		}
+
+		// Structured logs go to stderr, as text or JSON (VC_LOG_LEVEL, VC_LOG_FORMAT)
+		logCfg, err := config.LoggingConfigFromEnv()
+		if err != nil {
+			exitWithError(exitError, err)
+		}
+		logging.Setup(logCfg, os.Stderr)
+
+		// Spans are exported over OTLP when VC_TRACING is set
+		traceCfg, err := config.TracingConfigFromEnv()
+		if err != nil {
+			exitWithError(exitError, err)
+		}
+		if stopTracing, err = tracing.Setup(context.Background(), traceCfg); err != nil {
+			exitWithError(exitError, fmt.Errorf("failed to set up tracing: %w", err))
+		}
+
+		// Skip database initialization for init, vc config and vc completion
+		if cmd.Name() == "init" || cmd == configCmd || cmd.Parent() == configCmd || isCompletionCmd(cmd) {
 			return
 		}
 
 		// Initialize storage
-		var err error
-		if dbPath == "" {
-			// Auto-discover database by walking up directory tree
-			dbPath, err = storage.DiscoverDatabase()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+		if memoryStore {
+			// Nothing is read from or written to disk, but commands still
+			// derive the project root from dbPath
+			if dbPath == "" {
+				if dbPath, err = storage.DiscoverDatabase(); err != nil {
+					cwd, _ := os.Getwd()
+					dbPath = filepath.Join(cwd, ".beads", "beads.db")
+				}
 			}
+			store = memory.New()
 		} else {
-			// Make path absolute if relative was provided
-			dbPath, err = filepath.Abs(dbPath)
+			if dbPath == "" {
+				// Auto-discover database by walking up directory tree
+				dbPath, err = storage.DiscoverDatabase()
+				if err != nil {
+					exitWithError(exitError, err)
+				}
+			} else {
+				// Make path absolute if relative was provided
+				dbPath, err = filepath.Abs(dbPath)
+				if err != nil {
+					exitWithError(exitError, fmt.Errorf("invalid database path: %w", err))
+				}
+			}
+
+			ctx := context.Background()
+			if readOnly {
+				store, err = beads.OpenExistingVCStorage(ctx, dbPath, storage.Passphrase())
+			} else {
+				store, err = beads.NewVCStorage(ctx, dbPath)
+			}
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid database path: %v\n", err)
-				os.Exit(1)
+				exitWithError(exitError, fmt.Errorf("failed to open database: %w", err))
 			}
 		}
-
-		ctx := context.Background()
-		store, err = beads.NewVCStorage(ctx, dbPath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to open database: %v\n", err)
-			os.Exit(1)
+		if readOnly {
+			store = storage.ReadOnly(store)
 		}
 
 		// Set actor from env or default
@@ -66,18 +138,28 @@ var rootCmd = &cobra.Command{
 		if store != nil {
 			_ = store.Close() // Ignore close error on cleanup
 		}
+		if err := stopTracing(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to flush traces: %v\n", err)
+		}
 	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "", "Database path (default: auto-discover .beads/beads.db)")
 	rootCmd.PersistentFlags().StringVar(&actor, "actor", "", "Actor name for audit trail (default: $USER)")
+	rootCmd.PersistentFlags().BoolVar(&memoryStore, "memory", false, "Use a throwaway in-memory database (nothing is saved)")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Open the database read-only; commands that write fail")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file (default: .vc/config.yaml in the project)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Config file profile to use (default: $VC_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Print machine-readable JSON (see 'vc --help' for exit codes)")
 }
 
 var createCmd = &cobra.Command{
 	Use:   "create [title]",
 	Short: "Create a new issue",
-	Args:  cobra.MinimumNArgs(1),
+	Example: `  vc create "Fix login timeout" -t bug -p 1 --acceptance "Login succeeds after a 30s stall"
+  vc create "Dark mode" -t feature -l frontend,ui -d "Follow the OS setting"`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		title := args[0]
 		description, _ := cmd.Flags().GetString("description")
@@ -101,8 +183,7 @@ var createCmd = &cobra.Command{
 
 		ctx := context.Background()
 		if err := store.CreateIssue(ctx, issue, actor); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
 		}
 
 		// Add labels if specified
@@ -112,6 +193,11 @@ var createCmd = &cobra.Command{
 			}
 		}
 
+		if jsonOutput {
+			printJSON(issue)
+			return
+		}
+
 		green := color.New(color.FgGreen).SprintFunc()
 		fmt.Printf("%s Created issue: %s\n", green("✓"), issue.ID)
 		fmt.Printf("  Title: %s\n", issue.Title)
@@ -132,19 +218,22 @@ func init() {
 }
 
 var showCmd = &cobra.Command{
-	Use:   "show [id]",
-	Short: "Show issue details",
-	Args:  cobra.ExactArgs(1),
+	Use:     "show [id]",
+	Short:   "Show issue details",
+	Example: `  vc show vc-42`,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
 		issue, err := store.GetIssue(ctx, args[0])
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
 		}
 		if issue == nil {
-			fmt.Fprintf(os.Stderr, "Issue %s not found\n", args[0])
-			os.Exit(1)
+			exitWithError(exitNotFound, fmt.Errorf("issue %s not found", args[0]))
+		}
+		if jsonOutput {
+			printJSON(issueDetail(ctx, issue))
+			return
 		}
 
 		cyan := color.New(color.FgCyan).SprintFunc()
@@ -177,6 +266,15 @@ var showCmd = &cobra.Command{
 			fmt.Printf("\nLabels: %v\n", labels)
 		}
 
+		// Show custom fields
+		fields, _ := store.GetCustomFieldValues(ctx, issue.ID)
+		if len(fields) > 0 {
+			fmt.Printf("\nFields:\n")
+			for _, field := range fields {
+				fmt.Printf("  %s: %s\n", field.Name, field.Value)
+			}
+		}
+
 		// Show dependencies
 		deps, _ := store.GetDependencies(ctx, issue.ID)
 		if len(deps) > 0 {
@@ -195,6 +293,30 @@ var showCmd = &cobra.Command{
 			}
 		}
 
+		// Show commits made by executions of the issue
+		executions, _ := store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID})
+		var commits []*types.Execution
+		for _, e := range executions {
+			if e.CommitHash != "" {
+				commits = append(commits, e)
+			}
+		}
+		if len(commits) > 0 {
+			fmt.Printf("\nCommits (%d):\n", len(commits))
+			for _, e := range commits {
+				fmt.Printf("  %s  execution #%d, %s\n", e.CommitHash, e.ID, e.StartedAt.Local().Format("2006-01-02 15:04"))
+			}
+		}
+
+		// Show comment threads
+		comments, _ := store.GetComments(ctx, issue.ID)
+		if len(comments) > 0 {
+			fmt.Printf("\nComments (%d):\n", len(comments))
+			for _, c := range types.ThreadComments(comments) {
+				printCommentThread(c, 1, false)
+			}
+		}
+
 		fmt.Println()
 	},
 }
@@ -206,37 +328,48 @@ func init() {
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List issues",
+	Long: `List issues matching the given filters.
+
+Save a combination of filters under a name with --save, then run it by name
+with --filter instead of repeating the flags. See 'vc filter' to list and
+delete saved filters.`,
+	Example: `  vc list --status open --priority 1 --type task --save ready-p1
+  vc list --filter ready-p1`,
 	Run: func(cmd *cobra.Command, args []string) {
-		status, _ := cmd.Flags().GetString("status")
-		assignee, _ := cmd.Flags().GetString("assignee")
-		issueType, _ := cmd.Flags().GetString("type")
-		limit, _ := cmd.Flags().GetInt("limit")
+		ctx := context.Background()
+		filterName, _ := cmd.Flags().GetString("filter")
+		saveName, _ := cmd.Flags().GetString("save")
 
-		filter := types.IssueFilter{
-			Limit: limit,
-		}
-		if status != "" {
-			s := types.Status(status)
-			filter.Status = &s
-		}
-		// Use Changed() to properly handle P0 (priority=0)
-		if cmd.Flags().Changed("priority") {
-			priority, _ := cmd.Flags().GetInt("priority")
-			filter.Priority = &priority
-		}
-		if assignee != "" {
-			filter.Assignee = &assignee
-		}
-		if issueType != "" {
-			t := types.IssueType(issueType)
-			filter.IssueType = &t
+		var issues []*types.Issue
+		var err error
+		if filterName != "" {
+			for _, name := range listFilterFlags {
+				if cmd.Flags().Changed(name) {
+					exitWithError(exitError, fmt.Errorf("--filter can't be combined with --%s (use --save to change a saved filter)", name))
+				}
+			}
+			issues, err = store.ListIssuesByFilter(ctx, filterName)
+		} else {
+			saved := savedFilterFromFlags(cmd)
+			if saveName != "" {
+				saved.Name = saveName
+				saved.Description, _ = cmd.Flags().GetString("description")
+				if err := store.SaveFilter(ctx, saved); err != nil {
+					exitWithError(exitError, err)
+				}
+				if !jsonOutput {
+					green := color.New(color.FgGreen).SprintFunc()
+					fmt.Printf("%s Saved filter %s\n", green("✓"), saveName)
+				}
+			}
+			issues, err = store.SearchIssues(ctx, saved.Query, saved.IssueFilter())
 		}
-
-		ctx := context.Background()
-		issues, err := store.SearchIssues(ctx, "", filter)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
+		}
+		if jsonOutput {
+			printJSON(newIssueListJSON(issues))
+			return
 		}
 
 		fmt.Printf("\nFound %d issues:\n\n", len(issues))
@@ -251,19 +384,52 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// listFilterFlags are the vc list flags that make up a saved filter
+var listFilterFlags = []string{"query", "status", "priority", "assignee", "type", "label", "project", "field", "limit"}
+
+// savedFilterFromFlags builds an unnamed saved filter from vc list's flags
+func savedFilterFromFlags(cmd *cobra.Command) *types.SavedFilter {
+	f := &types.SavedFilter{}
+	f.Query, _ = cmd.Flags().GetString("query")
+	status, _ := cmd.Flags().GetString("status")
+	f.Status = types.Status(status)
+	issueType, _ := cmd.Flags().GetString("type")
+	f.IssueType = types.IssueType(issueType)
+	// Use Changed() to properly handle P0 (priority=0)
+	if cmd.Flags().Changed("priority") {
+		priority, _ := cmd.Flags().GetInt("priority")
+		f.Priority = &priority
+	}
+	f.Assignee, _ = cmd.Flags().GetString("assignee")
+	f.Labels, _ = cmd.Flags().GetStringSlice("label")
+	f.Project, _ = cmd.Flags().GetString("project")
+	f.CustomFields, _ = cmd.Flags().GetStringToString("field")
+	f.Limit, _ = cmd.Flags().GetInt("limit")
+	return f
+}
+
 func init() {
+	listCmd.Flags().StringP("query", "q", "", "Filter by text in title, description or ID")
 	listCmd.Flags().StringP("status", "s", "", "Filter by status")
 	listCmd.Flags().IntP("priority", "p", 0, "Filter by priority")
 	listCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
 	listCmd.Flags().StringP("type", "t", "", "Filter by type")
+	listCmd.Flags().StringSliceP("label", "l", nil, "Filter by label (repeatable; issues must have all)")
+	listCmd.Flags().String("project", "", "Filter by project")
+	listCmd.Flags().StringToString("field", nil, "Filter by custom field value, e.g. --field component=api (repeatable)")
 	listCmd.Flags().IntP("limit", "n", 0, "Limit results")
+	listCmd.Flags().StringP("filter", "f", "", "Run a saved filter")
+	listCmd.Flags().String("save", "", "Save these filters under a name")
+	listCmd.Flags().StringP("description", "d", "", "Description for --save")
 	rootCmd.AddCommand(listCmd)
 }
 
 var updateCmd = &cobra.Command{
 	Use:   "update [id]",
 	Short: "Update an issue",
-	Args:  cobra.ExactArgs(1),
+	Example: `  vc update vc-42 --status in_progress --assignee alice
+  vc update vc-42 --priority 0 --title "Fix login timeout on slow networks"`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		updates := make(map[string]interface{})
 
@@ -284,12 +450,19 @@ var updateCmd = &cobra.Command{
 			updates["assignee"] = assignee
 		}
 
+		ctx := context.Background()
 		if len(updates) == 0 {
+			if jsonOutput {
+				exitWithError(exitError, fmt.Errorf("no updates specified"))
+			}
 			fmt.Println("No updates specified")
 			return
 		}
-
-		ctx := context.Background()
+		if issue, err := store.GetIssue(ctx, args[0]); err != nil {
+			exitWithError(exitError, err)
+		} else if issue == nil {
+			exitWithError(exitNotFound, fmt.Errorf("issue %s not found", args[0]))
+		}
 
 		// Log status change for audit trail if status is being updated (vc-n4lx)
 		if _, hasStatus := updates["status"]; hasStatus {
@@ -297,8 +470,15 @@ var updateCmd = &cobra.Command{
 		}
 
 		if err := store.UpdateIssue(ctx, args[0], updates, actor); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
+		}
+		if jsonOutput {
+			issue, err := store.GetIssue(ctx, args[0])
+			if err != nil {
+				exitWithError(exitError, err)
+			}
+			printJSON(issue)
+			return
 		}
 
 		green := color.New(color.FgGreen).SprintFunc()
@@ -317,7 +497,9 @@ func init() {
 var closeCmd = &cobra.Command{
 	Use:   "close [id...]",
 	Short: "Close one or more issues",
-	Args:  cobra.MinimumNArgs(1),
+	Example: `  vc close vc-42
+  vc close vc-42 vc-43 --reason "Duplicate of vc-40"`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		reason, _ := cmd.Flags().GetString("reason")
 		if reason == "" {
@@ -325,13 +507,38 @@ var closeCmd = &cobra.Command{
 		}
 
 		ctx := context.Background()
+		out := closeResultJSON{Closed: []string{}, Failed: []closeFailureJSON{}}
+		exitCode := exitOK
 		for _, id := range args {
+			if issue, err := store.GetIssue(ctx, id); err == nil && issue == nil {
+				out.Failed = append(out.Failed, closeFailureJSON{ID: id, Error: "not found"})
+				if exitCode == exitOK {
+					exitCode = exitNotFound
+				}
+				if !jsonOutput {
+					fmt.Fprintf(os.Stderr, "Error closing %s: issue not found\n", id)
+				}
+				continue
+			}
 			if err := store.CloseIssue(ctx, id, reason, actor); err != nil {
-				fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", id, err)
+				out.Failed = append(out.Failed, closeFailureJSON{ID: id, Error: err.Error()})
+				exitCode = exitError
+				if !jsonOutput {
+					fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", id, err)
+				}
 				continue
 			}
-			green := color.New(color.FgGreen).SprintFunc()
-			fmt.Printf("%s Closed %s: %s\n", green("✓"), id, reason)
+			out.Closed = append(out.Closed, id)
+			if !jsonOutput {
+				green := color.New(color.FgGreen).SprintFunc()
+				fmt.Printf("%s Closed %s: %s\n", green("✓"), id, reason)
+			}
+		}
+		if jsonOutput {
+			printJSON(out)
+		}
+		if exitCode != exitOK {
+			os.Exit(exitCode)
 		}
 	},
 }
@@ -342,6 +549,7 @@ func init() {
 }
 
 func main() {
+	registerCompletions()
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/cost"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var missionsCmd = &cobra.Command{
+	Use:   "missions [mission-id...]",
+	Short: "Show spend and progress per mission",
+	Long: `Show each mission's spend to date and progress: cost, tokens, supervisor
+calls and agent executions on the mission and its issues, phases and
+tasks closed, and the quality gate pass rate.
+
+The projected remaining cost extrapolates the spend per closed task to the
+tasks still open, so it is rough early on and shown once a task has
+closed. The same data is served by the REST API at
+/api/v1/missions/progress.`,
+	Example: `  vc missions                # Unclosed missions
+  vc missions --all          # Closed ones too
+  vc missions vc-12 --json   # One mission, as JSON`,
+	Run: func(cmd *cobra.Command, args []string) {
+		includeClosed, _ := cmd.Flags().GetBool("all")
+
+		ctx := context.Background()
+		for _, id := range args {
+			issue, err := store.GetIssue(ctx, id)
+			if err != nil {
+				exitWithError(exitError, err)
+			}
+			if issue == nil || issue.IssueType != types.TypeEpic || issue.IssueSubtype != types.SubtypeMission {
+				exitWithError(exitNotFound, fmt.Errorf("mission %s not found", id))
+			}
+		}
+		reports, err := cost.LoadMissionReports(ctx, store, args, includeClosed)
+		if err != nil {
+			exitWithError(exitError, err)
+		}
+		if jsonOutput {
+			printJSON(struct {
+				Count    int                   `json:"count"`
+				Missions []*cost.MissionReport `json:"missions"`
+			}{len(reports), reports})
+			return
+		}
+		if len(reports) == 0 {
+			fmt.Printf("\nNo missions found\n\n")
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Missions (%d):\n\n", cyan("🎯"), len(reports))
+		for _, r := range reports {
+			fmt.Printf("  %s %s %s\n", r.MissionID, r.Title, gray(fmt.Sprintf("[P%d %s]", r.Priority, r.Status)))
+			fmt.Printf("    Progress:  %d/%d phases, %d/%d tasks closed\n", r.PhasesComplete, r.Phases, r.TasksClosed, r.Tasks)
+			fmt.Printf("    Spend:     $%.2f (%s in / %s out tokens, %d supervisor calls, %d executions)\n",
+				r.CostUSD, formatTokens(r.InputTokens), formatTokens(r.OutputTokens), r.SupervisorCalls, r.Executions)
+			if r.GateRuns > 0 {
+				fmt.Printf("    Gates:     %.0f%% passed (%d/%d runs)\n", r.GatePassRate*100, r.GateRunsPassed, r.GateRuns)
+			}
+			if r.ProjectedRemainingUSD != nil {
+				fmt.Printf("    Remaining: ~$%.2f projected\n", *r.ProjectedRemainingUSD)
+			} else if r.Tasks > r.TasksClosed {
+				fmt.Printf("    Remaining: %s\n", gray("no projection until a task closes"))
+			}
+			fmt.Println()
+		}
+	},
+}
+
+func init() {
+	missionsCmd.Flags().Bool("all", false, "Include closed missions")
+	rootCmd.AddCommand(missionsCmd)
+}
@@ -24,6 +24,8 @@ Use cases:
   - Cost budget approaching limit
   - Want to redirect executor to urgent issue
   - Debug agent state without losing progress`,
+	Example: `  vc pause vc-42
+  vc pause vc-42 --reason "Waiting on an API key"`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		issueID := args[0]
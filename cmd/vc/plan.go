@@ -19,6 +19,9 @@ var planCmd = &cobra.Command{
 	Use:   "plan",
 	Short: "Mission planning commands",
 	Long:  `Interactive mission planning with AI-guided refinement and validation.`,
+	Example: `  vc plan new "Add rate limiting to the public API with per-key quotas and clear 429 responses"
+  vc plan show <mission-id>
+  vc plan approve <mission-id>`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Show help if no subcommand provided
 		cmd.Help()
@@ -30,7 +33,8 @@ var planGenerateCmd = &cobra.Command{
 	Short: "Generate a plan from an existing Beads issue",
 	Long: `Generate an initial mission plan from an existing issue in the Beads tracker.
 This reads the issue's description, design, and acceptance criteria to create a draft plan.`,
-	Args: cobra.ExactArgs(1),
+	Example: `  vc plan generate vc-42`,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		issueID := args[0]
 		ctx := context.Background()
@@ -60,7 +64,8 @@ var planNewCmd = &cobra.Command{
 	Short: "Create a new mission plan from a freeform description",
 	Long: `Create a new mission plan from a natural language description.
 This creates an ephemeral mission plan (identified by UUID) in draft status.`,
-	Args: cobra.MinimumNArgs(1),
+	Example: `  vc plan new "Add rate limiting to the public API with per-key quotas and clear 429 responses"`,
+	Args:    cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		// Join all args to support multi-word descriptions without quotes
 		description := strings.Join(args, " ")
@@ -175,7 +180,8 @@ var planShowCmd = &cobra.Command{
 	Short: "Display a plan's structure and details",
 	Long: `Display a mission plan as a tree structure showing phases and tasks.
 This includes estimates, dependencies, and validation status.`,
-	Args: cobra.ExactArgs(1),
+	Example: `  vc plan show vc-42`,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		missionID := args[0]
 		ctx := context.Background()
@@ -225,9 +231,10 @@ This includes estimates, dependencies, and validation status.`,
 }
 
 var planListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all draft plans",
-	Long:  `List all mission plans that have not been approved yet.`,
+	Use:     "list",
+	Short:   "List all draft plans",
+	Long:    `List all mission plans that have not been approved yet.`,
+	Example: `  vc plan list`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
 
@@ -264,7 +271,8 @@ var planRefineCmd = &cobra.Command{
 	Short: "Refine a plan with AI feedback",
 	Long: `Iteratively refine a mission plan using AI-guided convergence.
 This runs multiple refinement iterations until the plan stabilizes.`,
-	Args: cobra.ExactArgs(1),
+	Example: `  vc plan refine vc-42`,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		missionID := args[0]
 
@@ -280,7 +288,8 @@ var planValidateCmd = &cobra.Command{
 	Short: "Validate a plan against quality checks",
 	Long: `Run validation checks on a mission plan to ensure quality.
 This includes dependency checks, effort estimates, and completeness validation.`,
-	Args: cobra.ExactArgs(1),
+	Example: `  vc plan validate vc-42`,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		missionID := args[0]
 
@@ -296,7 +305,8 @@ var planApproveCmd = &cobra.Command{
 	Short: "Approve a plan and create Beads issues",
 	Long: `Approve a validated plan and atomically create all phase and task issues in Beads.
 This marks the plan as approved and makes it immutable.`,
-	Args: cobra.ExactArgs(1),
+	Example: `  vc plan approve vc-42`,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		missionID := args[0]
 
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/hosting"
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Track pull requests and merge requests opened for issues",
+	Long: `Track GitHub pull requests and GitLab merge requests opened by the executor.
+
+With --enable-auto-pr and a GitHub token (VC_GITHUB_TOKEN, GITHUB_TOKEN or
+GH_TOKEN) or a GitLab token (VC_GITLAB_TOKEN or GITLAB_TOKEN), the executor
+pushes each issue's branch after a successful execution and opens a pull
+request (merge request on GitLab) with an AI-written description. The
+provider is detected from the remote's URL unless VC_GIT_HOSTING is set.
+Pull requests are recorded on their issue, and their status (open, draft,
+merged, closed) is checked every VC_PR_SYNC_INTERVAL_MINUTES while the
+executor runs, or on demand with 'vc pr sync'.
+
+The latest pull request of an issue can be inspected and acted on with
+'vc pr checks', 'vc pr comment' and 'vc pr merge'.
+
+With VC_PR_CHECK_RUNS=true, each quality gate's result is also reported on
+GitHub pull requests as a check run ("vc / test", "vc / lint", ...), with
+the failures its output points at annotated on their lines. GitHub only
+accepts check runs from GitHub Apps, so the token must be an app
+installation token.
+
+See also VC_GIT_HOSTING_REMOTE, VC_PR_DRAFT, VC_PR_LABELS, VC_GITHUB_REPO,
+VC_GITHUB_API_URL, VC_GITLAB_PROJECT and VC_GITLAB_API_URL.`,
+	Example: `  vc pr list
+  vc pr checks vc-42
+  vc pr merge vc-42 --method squash`,
+}
+
+var prListCmd = &cobra.Command{
+	Use:   "list [issue-id]",
+	Short: "List pull requests opened for issues",
+	Example: `  vc pr list           # Every issue's pull requests
+  vc pr list vc-42     # One issue's`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueID := ""
+		if len(args) == 1 {
+			issueID = args[0]
+		}
+
+		ctx := context.Background()
+		prs, err := hosting.ListTracked(ctx, store, issueID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(prs) == 0 {
+			fmt.Printf("\nNo pull requests found\n\n")
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Pull requests (%d):\n\n", cyan("🔀"), len(prs))
+		for _, pr := range prs {
+			fmt.Printf("  %-10s %-7s %s\n", pr.IssueID, pr.Status, pr.URL)
+			fmt.Printf("  %s\n", gray(fmt.Sprintf("%-10s %s -> %s, updated %s", "", pr.Branch, pr.BaseBranch, pr.UpdatedAt.Format("2006-01-02 15:04"))))
+		}
+		fmt.Println()
+	},
+}
+
+var prSyncCmd = &cobra.Command{
+	Use:     "sync",
+	Short:   "Check open pull requests for status changes",
+	Example: `  vc pr sync`,
+	Run: func(cmd *cobra.Command, args []string) {
+		hostingConfig, err := config.HostingConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !hostingConfig.Enabled() {
+			fmt.Fprintf(os.Stderr, "Error: no GitHub or GitLab token (set VC_GITHUB_TOKEN or VC_GITLAB_TOKEN)\n")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		changes, err := hosting.Sync(ctx, store, hostingConfig, actor)
+		printPullRequestChanges(changes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(changes) == 0 {
+			fmt.Println("No pull request status changes")
+		}
+	},
+}
+
+var prChecksCmd = &cobra.Command{
+	Use:     "checks <issue-id>",
+	Short:   "Show the CI status of an issue's pull request",
+	Example: `  vc pr checks vc-42`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		pr, provider := openTrackedPullRequest(ctx, args[0])
+		status, err := provider.GetCIStatus(ctx, pr.Number)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		stateColor := map[string]func(a ...interface{}) string{
+			hosting.CISuccess: color.New(color.FgGreen).SprintFunc(),
+			hosting.CIFailure: color.New(color.FgRed).SprintFunc(),
+			hosting.CIPending: color.New(color.FgYellow).SprintFunc(),
+		}
+		colorize := func(state string) string {
+			if c, ok := stateColor[state]; ok {
+				return c(state)
+			}
+			return state
+		}
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s: %s\n\n", pr.URL, colorize(status.State))
+		for _, check := range status.Checks {
+			fmt.Printf("  %-8s %s %s\n", colorize(check.State), check.Name, gray(check.URL))
+		}
+		if len(status.Checks) > 0 {
+			fmt.Println()
+		}
+	},
+}
+
+var prCommentCmd = &cobra.Command{
+	Use:     "comment <issue-id> <text>",
+	Short:   "Comment on an issue's pull request",
+	Example: `  vc pr comment vc-42 "Rebased on main, ready for another look"`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		pr, provider := openTrackedPullRequest(ctx, args[0])
+		if err := provider.CommentOnPullRequest(ctx, pr.Number, args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Commented on %s\n", green("✓"), pr.URL)
+	},
+}
+
+var prMergeCmd = &cobra.Command{
+	Use:   "merge <issue-id>",
+	Short: "Merge an issue's pull request",
+	Long: `Merge the latest pull request of an issue, after checking that its CI passed.
+
+--method is merge, squash or rebase (GitLab uses the project's merge method,
+so rebase is not available there). --force merges without checking CI.`,
+	Example: `  vc pr merge vc-42
+  vc pr merge vc-42 --method squash
+  vc pr merge vc-42 --force            # Don't wait for CI`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		method, _ := cmd.Flags().GetString("method")
+		force, _ := cmd.Flags().GetBool("force")
+
+		ctx := context.Background()
+		pr, provider := openTrackedPullRequest(ctx, args[0])
+		if !force {
+			status, err := provider.GetCIStatus(ctx, pr.Number)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if status.State == hosting.CIFailure || status.State == hosting.CIPending {
+				fmt.Fprintf(os.Stderr, "Error: CI of %s is %s (use --force to merge anyway)\n", pr.URL, status.State)
+				os.Exit(1)
+			}
+		}
+
+		if err := provider.MergePullRequest(ctx, pr.Number, hosting.MergeOptions{Method: method}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Merged %s\n", green("✓"), pr.URL)
+
+		// Record the new status on the issue now rather than at the next sync
+		hostingConfig, err := config.HostingConfigFromEnv()
+		if err == nil {
+			changes, err := hosting.Sync(ctx, store, hostingConfig, actor)
+			printPullRequestChanges(changes)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: pull request sync failed: %v\n", err)
+			}
+		}
+	},
+}
+
+// openTrackedPullRequest returns an issue's latest tracked pull request and
+// its provider, exiting if there is none
+func openTrackedPullRequest(ctx context.Context, issueID string) (*hosting.TrackedPullRequest, hosting.Provider) {
+	prs, err := hosting.ListTracked(ctx, store, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(prs) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no pull request found for %s\n", issueID)
+		os.Exit(1)
+	}
+	pr := prs[len(prs)-1]
+
+	hostingConfig, err := config.HostingConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	provider, err := hosting.NewProvider(hostingConfig, pr.Provider, pr.Repo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return pr, provider
+}
+
+// printPullRequestChanges prints one line per pull request status change
+func printPullRequestChanges(changes []hosting.StatusChange) {
+	green := color.New(color.FgGreen).SprintFunc()
+	for _, change := range changes {
+		pr := change.PullRequest
+		fmt.Printf("%s %s: pull request %s %s -> %s\n",
+			green("✓"), pr.IssueID, pr.URL, change.PreviousStatus, pr.Status)
+	}
+}
+
+// runPullRequestSync checks tracked pull requests for status changes every
+// sync interval until ctx is cancelled
+func runPullRequestSync(ctx context.Context, cfg config.HostingConfig) {
+	ticker := time.NewTicker(cfg.SyncInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changes, err := hosting.Sync(ctx, store, cfg, actor)
+			printPullRequestChanges(changes)
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "warning: pull request sync failed: %v\n", err)
+			}
+		}
+	}
+}
+
+func init() {
+	prCmd.AddCommand(prListCmd)
+	prCmd.AddCommand(prSyncCmd)
+	prCmd.AddCommand(prChecksCmd)
+	prCmd.AddCommand(prCommentCmd)
+	prCmd.AddCommand(prMergeCmd)
+
+	prMergeCmd.Flags().String("method", hosting.MergeCommit, "Merge method: merge, squash or rebase")
+	prMergeCmd.Flags().Bool("force", false, "Merge without checking CI")
+	rootCmd.AddCommand(prCmd)
+}
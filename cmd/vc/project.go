@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage projects (separate backlogs in one database)",
+	Long: `Manage projects. A project is a namespace for issues, so one database
+can hold the backlogs of several repositories. Run 'vc execute --project NAME'
+to work only on a project's issues, in its repository.`,
+	Example: `  vc project define web --repo ~/src/web
+  vc project assign web vc-42 vc-43
+  vc ready --project web`,
+}
+
+var projectListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List projects and their issue counts",
+	Example: `  vc project list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		projects, err := store.ListProjects(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Projects (%d):\n\n", cyan("📁"), len(projects))
+		for _, p := range projects {
+			fmt.Printf("  %-20s %4d open %4d closed  %s\n", p.Name, p.OpenIssues, p.ClosedIssues, gray(p.RepoPath))
+			if p.Description != "" {
+				fmt.Printf("  %-20s %s\n", "", gray(p.Description))
+			}
+		}
+		fmt.Println()
+	},
+}
+
+var projectDefineCmd = &cobra.Command{
+	Use:   "define [name]",
+	Short: "Create or update a project's configuration",
+	Long:  `Create or update a project. Only the flags given are changed.`,
+	Example: `  vc project define web --repo ~/src/web --branch develop
+  vc project define web --description "Customer-facing site"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		project, err := store.GetProject(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if project == nil {
+			project = &types.Project{Name: args[0]}
+		}
+		if cmd.Flags().Changed("description") {
+			project.Description, _ = cmd.Flags().GetString("description")
+		}
+		if cmd.Flags().Changed("repo") {
+			project.RepoPath, _ = cmd.Flags().GetString("repo")
+		}
+		if cmd.Flags().Changed("branch") {
+			project.DefaultBranch, _ = cmd.Flags().GetString("branch")
+		}
+
+		if err := store.SaveProject(ctx, project); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Saved project %s\n", green("✓"), project.Name)
+	},
+}
+
+var projectAssignCmd = &cobra.Command{
+	Use:     "assign [project] [issue-id...]",
+	Short:   "Move issues into a project",
+	Example: `  vc project assign web vc-42 vc-43`,
+	Args:    cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		setIssuesProject(args[0], args[1:])
+	},
+}
+
+var projectUnassignCmd = &cobra.Command{
+	Use:     "unassign [issue-id...]",
+	Short:   "Remove issues from their project",
+	Example: `  vc project unassign vc-42`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setIssuesProject("", args)
+	},
+}
+
+// setIssuesProject moves each issue into project ("" for none), stopping at
+// the first failure
+func setIssuesProject(project string, issueIDs []string) {
+	ctx := context.Background()
+	green := color.New(color.FgGreen).SprintFunc()
+	for _, id := range issueIDs {
+		if err := store.SetIssueProject(ctx, id, project, actor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if project == "" {
+			fmt.Printf("%s Removed %s from its project\n", green("✓"), id)
+		} else {
+			fmt.Printf("%s Moved %s to project %s\n", green("✓"), id, project)
+		}
+	}
+}
+
+func init() {
+	projectDefineCmd.Flags().StringP("description", "d", "", "Project description")
+	projectDefineCmd.Flags().String("repo", "", "Repository the project's issues are worked in")
+	projectDefineCmd.Flags().String("branch", "", "Default branch for the project's sandboxes")
+
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectDefineCmd)
+	projectCmd.AddCommand(projectAssignCmd)
+	projectCmd.AddCommand(projectUnassignCmd)
+	rootCmd.AddCommand(projectCmd)
+}
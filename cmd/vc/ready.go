@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -13,13 +12,18 @@ import (
 var readyCmd = &cobra.Command{
 	Use:   "ready",
 	Short: "Show ready work (no blockers)",
+	Example: `  vc ready
+  vc ready -p 0 -n 20        # Up to 20 P0 issues
+  vc ready --project web`,
 	Run: func(cmd *cobra.Command, args []string) {
 		limit, _ := cmd.Flags().GetInt("limit")
 		assignee, _ := cmd.Flags().GetString("assignee")
+		project, _ := cmd.Flags().GetString("project")
 
 		filter := types.WorkFilter{
-			Status: types.StatusOpen,
-			Limit:  limit,
+			Status:  types.StatusOpen,
+			Limit:   limit,
+			Project: project,
 		}
 		// Use Changed() to properly handle P0 (priority=0)
 		if cmd.Flags().Changed("priority") {
@@ -33,8 +37,11 @@ var readyCmd = &cobra.Command{
 		ctx := context.Background()
 		issues, err := store.GetReadyWork(ctx, filter)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
+		}
+		if jsonOutput {
+			printJSON(newIssueListJSON(issues))
+			return
 		}
 
 		if len(issues) == 0 {
@@ -61,14 +68,24 @@ var readyCmd = &cobra.Command{
 }
 
 var blockedCmd = &cobra.Command{
-	Use:   "blocked",
-	Short: "Show blocked issues",
+	Use:     "blocked",
+	Short:   "Show blocked issues",
+	Example: `  vc blocked`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
 		blocked, err := store.GetBlockedIssues(ctx)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
+		}
+		if jsonOutput {
+			if blocked == nil {
+				blocked = []*types.BlockedIssue{}
+			}
+			printJSON(struct {
+				Count  int                   `json:"count"`
+				Issues []*types.BlockedIssue `json:"issues"`
+			}{len(blocked), blocked})
+			return
 		}
 
 		if len(blocked) == 0 {
@@ -90,14 +107,18 @@ var blockedCmd = &cobra.Command{
 }
 
 var statsCmd = &cobra.Command{
-	Use:   "stats",
-	Short: "Show statistics",
+	Use:     "stats",
+	Short:   "Show statistics",
+	Example: `  vc stats`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
 		stats, err := store.GetStatistics(ctx)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
+		}
+		if jsonOutput {
+			printJSON(stats)
+			return
 		}
 
 		cyan := color.New(color.FgCyan).SprintFunc()
@@ -122,6 +143,7 @@ func init() {
 	readyCmd.Flags().IntP("limit", "n", 10, "Maximum issues to show")
 	readyCmd.Flags().IntP("priority", "p", 0, "Filter by priority")
 	readyCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
+	readyCmd.Flags().String("project", "", "Filter by project")
 
 	rootCmd.AddCommand(readyCmd)
 	rootCmd.AddCommand(blockedCmd)
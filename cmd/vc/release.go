@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/release"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+var releaseCmd = &cobra.Command{
+	Use:   "release <mission-id>",
+	Short: "Tag a closed release mission and publish its release notes",
+	Long: `Release a closed mission labelled release:<version>, e.g. release:v1.2.0.
+
+Release notes are generated from the issues closed under the mission
+(including those under its child epics), grouped into features, bug fixes
+and other changes. Every one of those issues' committed work must have
+landed on the checked out branch. The checked out commit is then tagged
+<version> with an annotated tag carrying the notes.
+
+With --publish, the tag is pushed and a release is published for it on
+GitHub or GitLab (with a token; otherwise the tag is only created locally).
+Versions with a pre-release suffix, like v1.2.0-rc.1, are published as
+pre-releases. Outcomes are recorded as comments on the mission.
+
+With --auto-release (or VC_AUTO_RELEASE=true), the executor does this by
+itself when a labelled mission closes and its work has landed.`,
+	Example: `  vc create "Widget search" -t epic -l release:v1.2.0
+  vc release vc-10
+  vc release vc-10 --publish --draft`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		publish, _ := cmd.Flags().GetBool("publish")
+		draft, _ := cmd.Flags().GetBool("draft")
+
+		projectRoot, err := storage.GetProjectRoot(dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		hostingConfig, err := config.HostingConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		pushChecksConfig, err := config.PushChecksConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		gitOps, err := git.NewGit(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		manager, err := release.New(release.Config{
+			Store:      store,
+			Git:        gitOps,
+			RepoPath:   projectRoot,
+			Hosting:    hostingConfig,
+			PushChecks: pushChecksConfig,
+			Publish:    publish,
+			Draft:      draft,
+			Actor:      actor,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		result, err := manager.ReleaseMission(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if result == nil {
+			fmt.Printf("%s has no %s<version> label; nothing to release\n", args[0], release.LabelPrefix)
+			return
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		red := color.New(color.FgRed).SprintFunc()
+		switch result.Status {
+		case release.StatusPublished:
+			fmt.Printf("%s %s: published %s\n", green("✓"), result.Tag, result.URL)
+		case release.StatusTagged:
+			fmt.Printf("%s %s: tagged %s\n", green("✓"), result.Tag, result.Commit)
+			if result.Reason != "" {
+				fmt.Printf("%s not published: %s\n", yellow("-"), result.Reason)
+			}
+		case release.StatusSkipped:
+			fmt.Printf("%s %s: skipped, %s\n", yellow("-"), result.Tag, result.Reason)
+			return
+		default:
+			fmt.Printf("%s %s: %s\n", red("✗"), result.Tag, result.Reason)
+			os.Exit(1)
+		}
+		fmt.Printf("\n%s", result.Notes)
+	},
+}
+
+func init() {
+	releaseCmd.Flags().Bool("publish", false, "Push the tag and publish a release on GitHub or GitLab")
+	releaseCmd.Flags().Bool("draft", false, "Publish the release as a draft (GitHub only)")
+	rootCmd.AddCommand(releaseCmd)
+}
@@ -22,6 +22,7 @@ The REPL provides a natural language interface for:
 - Managing the issue tracker
 
 Type 'help' in the REPL for available commands.`,
+	Example: `  vc repl`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate alignment between database and working directory
 		cwd, _ := os.Getwd()
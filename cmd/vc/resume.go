@@ -22,7 +22,8 @@ explaining that it was interrupted and where it left off.
 Note: This command requires the executor to be running. If the executor
 was stopped, use 'vc execute <issue-id>' instead - the executor will
 automatically detect and load the interrupt metadata.`,
-	Args: cobra.ExactArgs(1),
+	Example: `  vc resume vc-42`,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		issueID := args[0]
 
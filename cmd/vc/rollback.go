@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/rollback"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <execution-id | commit>",
+	Short: "Revert the commit an execution produced and file a follow-up issue",
+	Long: `Revert the commit an execution produced, e.g. because it broke the build.
+
+The commit is reverted with a new commit on the checked out branch, a bug is
+filed to redo the work (discovered from the original issue), and the
+execution, the original issue and the event log record the rollback. The
+working tree must be clean and the commit must be in the branch's history.
+
+With --auto-rollback (or VC_AUTO_ROLLBACK=true), the executor does this by
+itself when the baseline fails on an execution's commit after passing on the
+commit before it.`,
+	Example: `  vc rollback 42 --reason "broke the integration tests"
+  vc rollback 3f9c2a1e...   # By the full commit hash from 'vc executions'`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		reason, _ := cmd.Flags().GetString("reason")
+		ctx := context.Background()
+
+		projectRoot, err := storage.GetProjectRoot(dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		gitOps, err := git.NewGit(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		manager, err := rollback.New(rollback.Config{Store: store, Git: gitOps, RepoPath: projectRoot, Actor: actor})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var result *rollback.Result
+		if id, parseErr := strconv.ParseInt(args[0], 10, 64); parseErr == nil {
+			result, err = manager.RollbackExecution(ctx, id, reason)
+		} else {
+			result, err = manager.RollbackCommit(ctx, args[0], reason)
+		}
+		if result == nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Reverted %s (execution %d on %s) with %s\n", green("✓"),
+			result.Execution.CommitHash, result.Execution.ID, result.Execution.IssueID, result.RevertCommit)
+		if result.FollowUpIssueID != "" {
+			fmt.Printf("%s Filed %s to redo the work\n", green("✓"), result.FollowUpIssueID)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().StringP("reason", "r", "", "Why the commit is rolled back (recorded on the follow-up issue)")
+	rootCmd.AddCommand(rollbackCmd)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/schedule"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage recurring issues created on a cron schedule",
+	Long: `Schedules create an issue from a template on a cron schedule, for recurring
+work such as nightly dependency bumps or a weekly lint-debt cleanup. While
+'vc execute' or 'vc daemon' runs, due schedules create their issues, which
+are then worked like any other ready issue.
+
+Cron expressions have five fields (minute hour day-of-month month
+day-of-week) and are evaluated in the executor's local time; @hourly,
+@daily, @weekly, @monthly and @yearly are accepted too. A run is skipped
+while the issue from the schedule's previous run is still open, unless
+the schedule was added with --allow-overlap.`,
+	Example: `  vc schedule add nightly-deps --cron "0 3 * * *" --title "Bump dependencies" \
+    --acceptance "Dependencies are current and gates pass"
+  vc schedule list
+  vc schedule run nightly-deps`,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add a schedule",
+	Example: `  vc schedule add nightly-deps --cron "0 3 * * *" --title "Bump dependencies" \
+    --acceptance "Dependencies are current and gates pass" -l deps
+  vc schedule add lint-debt --cron "0 9 * * mon" -t chore -p 3 --title "Clean up lint debt"
+  vc schedule add dead-code --cron @monthly -t chore --title "Sweep dead code" --project web`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cronExpr, _ := cmd.Flags().GetString("cron")
+		title, _ := cmd.Flags().GetString("title")
+		description, _ := cmd.Flags().GetString("description")
+		acceptance, _ := cmd.Flags().GetString("acceptance")
+		issueType, _ := cmd.Flags().GetString("type")
+		priority, _ := cmd.Flags().GetInt("priority")
+		labels, _ := cmd.Flags().GetStringSlice("labels")
+		project, _ := cmd.Flags().GetString("project")
+		allowOverlap, _ := cmd.Flags().GetBool("allow-overlap")
+		disabled, _ := cmd.Flags().GetBool("disabled")
+		replace, _ := cmd.Flags().GetBool("replace")
+
+		if cronExpr == "" {
+			fmt.Fprintf(os.Stderr, "Error: --cron is required\n")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		if existing, err := store.GetSchedule(ctx, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		} else if existing != nil && !replace {
+			fmt.Fprintf(os.Stderr, "Error: schedule %s already exists (use --replace to replace it)\n", args[0])
+			os.Exit(1)
+		}
+		nextRun, err := schedule.NextRun(cronExpr, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		s := &types.Schedule{
+			Name:               args[0],
+			Cron:               cronExpr,
+			Enabled:            !disabled,
+			AllowOverlap:       allowOverlap,
+			Title:              title,
+			Description:        description,
+			AcceptanceCriteria: acceptance,
+			IssueType:          types.IssueType(issueType),
+			Priority:           priority,
+			Labels:             labels,
+			Project:            project,
+			NextRunAt:          nextRun,
+		}
+		if err := store.SaveSchedule(ctx, s); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Added schedule %s (%s)\n", green("✓"), s.Name, s.Cron)
+		if s.Enabled {
+			fmt.Printf("  Next run: %s\n", s.NextRunAt.Format("2006-01-02 15:04 MST"))
+		} else {
+			fmt.Printf("  Disabled: enable it with 'vc schedule enable %s'\n", s.Name)
+		}
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List schedules",
+	Example: `  vc schedule list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		schedules, err := store.ListSchedules(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("\n%s Schedules (%d):\n\n", cyan("⏰"), len(schedules))
+		for _, s := range schedules {
+			next := "disabled"
+			if s.Enabled {
+				next = "next " + s.NextRunAt.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("  %-20s %-16s %s\n", s.Name, s.Cron, next)
+			detail := fmt.Sprintf("%s [P%d %s]", s.Title, s.Priority, s.IssueType)
+			if s.LastIssueID != "" {
+				detail += ", last created " + s.LastIssueID
+			}
+			fmt.Printf("  %-20s %s\n", "", gray(detail))
+		}
+		fmt.Println()
+	},
+}
+
+var scheduleDeleteCmd = &cobra.Command{
+	Use:     "delete [name]",
+	Short:   "Delete a schedule (issues it created are kept)",
+	Example: `  vc schedule delete lint-debt`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		if err := store.DeleteSchedule(ctx, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Deleted schedule %s\n", green("✓"), args[0])
+	},
+}
+
+var scheduleEnableCmd = &cobra.Command{
+	Use:     "enable [name]",
+	Short:   "Enable a schedule, from its next run after now",
+	Example: `  vc schedule enable lint-debt`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setScheduleEnabled(args[0], true)
+	},
+}
+
+var scheduleDisableCmd = &cobra.Command{
+	Use:     "disable [name]",
+	Short:   "Disable a schedule without deleting it",
+	Example: `  vc schedule disable lint-debt`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setScheduleEnabled(args[0], false)
+	},
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run [name]",
+	Short: "Create a schedule's issue now, without waiting for its next run",
+	Long: `Create the issue a schedule's template describes now. The schedule's next
+run is unchanged, and this works for disabled schedules too, e.g. to try
+a template before enabling it.`,
+	Example: `  vc schedule run nightly-deps`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		s := getScheduleOrExit(ctx, args[0])
+		issue, err := schedule.CreateIssue(ctx, store, s, actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Created issue %s from schedule %s\n", green("✓"), issue.ID, s.Name)
+		fmt.Printf("  Title: %s\n", issue.Title)
+	},
+}
+
+// getScheduleOrExit returns the named schedule, exiting if it doesn't exist
+func getScheduleOrExit(ctx context.Context, name string) *types.Schedule {
+	s, err := store.GetSchedule(ctx, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if s == nil {
+		fmt.Fprintf(os.Stderr, "Error: schedule %s not found\n", name)
+		os.Exit(1)
+	}
+	return s
+}
+
+// setScheduleEnabled enables or disables a schedule. Enabling counts its
+// next run from now, so runs missed while disabled aren't made up.
+func setScheduleEnabled(name string, enabled bool) {
+	ctx := context.Background()
+	s := getScheduleOrExit(ctx, name)
+	s.Enabled = enabled
+	if enabled {
+		nextRun, err := schedule.NextRun(s.Cron, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		s.NextRunAt = nextRun
+	}
+	if err := store.SaveSchedule(ctx, s); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	if enabled {
+		fmt.Printf("%s Enabled schedule %s (next run %s)\n", green("✓"), name, s.NextRunAt.Format("2006-01-02 15:04 MST"))
+	} else {
+		fmt.Printf("%s Disabled schedule %s\n", green("✓"), name)
+	}
+}
+
+func init() {
+	scheduleAddCmd.Flags().String("cron", "", "Cron expression: five fields, or @hourly, @daily, @weekly, @monthly or @yearly (required)")
+	scheduleAddCmd.Flags().String("title", "", "Title of the issue each run creates (required)")
+	scheduleAddCmd.Flags().StringP("description", "d", "", "Issue description")
+	scheduleAddCmd.Flags().String("acceptance", "", "Acceptance criteria (required for task, bug and feature issues)")
+	scheduleAddCmd.Flags().StringP("type", "t", "task", "Issue type (bug|feature|task|epic|chore)")
+	scheduleAddCmd.Flags().IntP("priority", "p", 2, "Priority (0-4, 0=highest)")
+	scheduleAddCmd.Flags().StringSliceP("labels", "l", []string{}, "Labels (comma-separated)")
+	scheduleAddCmd.Flags().String("project", "", "Put the issues in this project")
+	scheduleAddCmd.Flags().Bool("allow-overlap", false, "Create a run's issue even while the previous run's is still open")
+	scheduleAddCmd.Flags().Bool("disabled", false, "Add the schedule disabled")
+	scheduleAddCmd.Flags().Bool("replace", false, "Replace an existing schedule of the same name")
+
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleDeleteCmd)
+	scheduleCmd.AddCommand(scheduleEnableCmd)
+	scheduleCmd.AddCommand(scheduleDisableCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
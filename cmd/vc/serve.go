@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/api"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/cost"
+	"github.com/steveyegge/vc/internal/grpcapi"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a REST API and web dashboard for issues, executions and AI usage",
+	Long: `Serve VC's REST API and web dashboard over HTTP until interrupted, so
+external tools, UIs and teammates can follow and steer the work without
+opening the database themselves.
+
+The dashboard, at the server's root URL, shows the mission tree, the ready
+queue, live output of running executions, gate results, AI cost and an
+inbox of gate overrides awaiting approval. It asks for an API token.
+
+Every route but /api/v1/health needs a bearer token. Tokens are configured
+as actor:token pairs in VC_API_TOKENS; changes made with a token are
+recorded as made by its actor. Use --read-only to serve without allowing
+changes.
+
+Routes (all under /api/v1):
+  GET   /health                          Liveness check (no token needed)
+  GET   /issues                          Search issues (q, status, type, priority,
+                                         assignee, label, project, cursor, limit, ...)
+  POST  /issues                          Create an issue
+  GET   /issues/{id}                     An issue with its labels and dependencies
+  PATCH /issues/{id}                     Update an issue's fields
+  POST  /issues/{id}/close               Close an issue
+  POST  /issues/{id}/comments            Comment on an issue
+  GET   /issues/{id}/events              An issue's audit trail
+  GET   /issues/{id}/approvals           Approved and pending gate overrides
+  POST  /issues/{id}/approvals/{gate}    Approve a requested gate override
+  GET   /approvals                       Gate overrides awaiting approval
+  GET   /ready                           The ready queue, in claim order
+  GET   /missions                        Missions and their child issues
+  GET   /events                          Audit trail of all issues
+  GET   /agent-events                    Agent and executor events
+  GET   /executions                      Agent executions, newest first
+  GET   /executions/{id}                 One execution
+  GET   /usage                           AI usage and cost budget
+
+With --grpc-addr (or VC_GRPC_ADDR), the gRPC API is served there too: the
+issue, execution and event operations above plus WatchEvents, which streams
+new events as they are recorded. It takes the same tokens, as
+"authorization: Bearer <token>" metadata. The service is defined in
+api/vcpb/vc.proto; Go programs can use its generated client (vcpb.NewClient).`,
+	Example: `  # Serve on the default address (127.0.0.1:7390)
+  VC_API_TOKENS=alice:$(openssl rand -hex 16) vc serve
+
+  # Then
+  curl -H "Authorization: Bearer $TOKEN" localhost:7390/api/v1/issues?status=open
+
+  # Also serve the gRPC API
+  vc serve --grpc-addr 127.0.0.1:7391`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ServeConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if addr, _ := cmd.Flags().GetString("addr"); addr != "" {
+			cfg.Addr = addr
+		}
+		if addr, _ := cmd.Flags().GetString("grpc-addr"); addr != "" {
+			cfg.GRPCAddr = addr
+		}
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		tokens, _ := cfg.ParseTokens() // Validated by ServeConfigFromEnv
+
+		var tracker *cost.Tracker
+		if costCfg := cost.LoadFromEnv(); costCfg.Enabled {
+			if tracker, err = cost.NewTracker(costCfg, store); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to initialize cost tracker: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		server, err := api.NewServer(api.Config{Store: store, Tokens: tokens, Costs: tracker})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		var grpcServer *grpcapi.Server
+		if cfg.GRPCAddr != "" {
+			if grpcServer, err = grpcapi.NewServer(grpcapi.Config{Store: store, Tokens: tokens}); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			cancel()
+		}()
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Serving the dashboard on http://%s and the API on http://%s/api/v1 (%d token(s), Ctrl+C to stop)\n",
+			green("✓"), cfg.Addr, cfg.Addr, len(tokens))
+		errCh := make(chan error, 2)
+		servers := 1
+		go func() { errCh <- server.ListenAndServe(ctx, cfg.Addr) }()
+		if grpcServer != nil {
+			servers++
+			fmt.Printf("%s Serving the gRPC API on %s\n", green("✓"), cfg.GRPCAddr)
+			go func() { errCh <- grpcServer.ListenAndServe(ctx, cfg.GRPCAddr) }()
+		}
+		// A server that fails takes the other down with it
+		for i := 0; i < servers; i++ {
+			if err := <-errCh; err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Println("API server stopped")
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "", "host:port to listen on (default: $VC_API_ADDR or 127.0.0.1:7390)")
+	serveCmd.Flags().String("grpc-addr", "", "host:port to serve the gRPC API on (default: $VC_GRPC_ADDR, or not served)")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -18,10 +18,8 @@ var staleCmd = &cobra.Command{
 
 This command identifies:
 1. Issues claimed by executors with status='stopped'
-2. Issues claimed by executors with stale heartbeats (no heartbeat for threshold duration)
-
-Examples:
-  # Show all stale claims (default: 5 minute heartbeat threshold)
+2. Issues claimed by executors with stale heartbeats (no heartbeat for threshold duration)`,
+	Example: `  # Show all stale claims (default: 5 minute heartbeat threshold)
   vc stale
 
   # Show stale claims with custom heartbeat threshold
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -15,9 +16,21 @@ import (
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show executor status and budget information",
-	Long:  `Display executor instance status, AI cost budget, and system health.`,
+	Long: `Display executor instance status, AI cost budget, and system health.
+
+Use --epic to add progress rollups for epics or missions: children by status,
+estimated vs actual effort, open blockers and quality gate pass rate.
+
+With --json, exits 4 if the AI cost budget is exceeded (the executor is paused).`,
+	Example: `  vc status
+  vc status --epic vc-10 --epic vc-20
+  vc status --json | jq .budget.status`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
+		if jsonOutput {
+			printStatusJSON(ctx, cmd)
+			return
+		}
 
 		// Print header
 		cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
@@ -27,8 +40,7 @@ var statusCmd = &cobra.Command{
 		// Get active executor instances
 		instances, err := store.GetActiveInstances(ctx)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to get executor instances: %v\n", err)
-			os.Exit(1)
+			exitWithError(exitError, fmt.Errorf("failed to get executor instances: %w", err))
 		}
 
 		// Display executor instances
@@ -175,9 +187,90 @@ var statusCmd = &cobra.Command{
 		}
 
 		fmt.Println()
+
+		// Display epic/mission progress rollups
+		epicIDs, _ := cmd.Flags().GetStringSlice("epic")
+		if len(epicIDs) > 0 {
+			fmt.Printf("%s\n", yellow("Epic Progress:"))
+			for _, epicID := range epicIDs {
+				rollup, err := store.GetEpicRollup(ctx, epicID)
+				if err != nil {
+					fmt.Printf("  %s %s: %v\n", red("✗"), epicID, err)
+					continue
+				}
+				printEpicRollup(rollup)
+			}
+			fmt.Println()
+		}
 	},
 }
 
+// statusJSON is the --json output of vc status
+type statusJSON struct {
+	Executors  []*types.ExecutorInstance `json:"executors"`
+	Budget     *budgetJSON               `json:"budget"`
+	ReadyCount int                       `json:"ready_count"`
+	Epics      []*types.EpicRollup       `json:"epics,omitempty"` // With --epic
+}
+
+// printStatusJSON prints the status as JSON, exiting 4 if the budget is
+// exceeded
+func printStatusJSON(ctx context.Context, cmd *cobra.Command) {
+	instances, err := store.GetActiveInstances(ctx)
+	if err != nil {
+		exitWithError(exitError, fmt.Errorf("failed to get executor instances: %w", err))
+	}
+	if instances == nil {
+		instances = []*types.ExecutorInstance{}
+	}
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		exitWithError(exitError, fmt.Errorf("failed to get ready work: %w", err))
+	}
+	out := statusJSON{Executors: instances, Budget: newBudgetJSON(), ReadyCount: len(ready)}
+
+	epicIDs, _ := cmd.Flags().GetStringSlice("epic")
+	for _, epicID := range epicIDs {
+		rollup, err := store.GetEpicRollup(ctx, epicID)
+		if err != nil {
+			exitWithError(exitError, fmt.Errorf("%s: %w", epicID, err))
+		}
+		out.Epics = append(out.Epics, rollup)
+	}
+
+	printJSON(out)
+	if out.Budget.exceeded() {
+		os.Exit(exitBudgetExceeded)
+	}
+}
+
+// printEpicRollup prints an epic's progress, effort, blockers and gate pass rate
+func printEpicRollup(rollup *types.EpicRollup) {
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	fmt.Printf("  %s\n", rollup.EpicID)
+	fmt.Printf("    Children: %d/%d closed", rollup.Closed(), rollup.Children)
+	for _, status := range []types.Status{types.StatusInProgress, types.StatusBlocked, types.StatusOpen} {
+		if n := rollup.ByStatus[status]; n > 0 {
+			fmt.Printf(", %d %s", n, status)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("    Effort:   %.0f min actual / %d min estimated", rollup.ActualMinutes, rollup.EstimatedMinutes)
+	if rollup.UnestimatedChildren > 0 {
+		fmt.Printf(" %s", gray(fmt.Sprintf("(%d unestimated)", rollup.UnestimatedChildren)))
+	}
+	fmt.Println()
+	if len(rollup.OpenBlockers) > 0 {
+		fmt.Printf("    Blockers: %s\n", strings.Join(rollup.OpenBlockers, ", "))
+	}
+	if rollup.GateRuns > 0 {
+		fmt.Printf("    Gates:    %d/%d runs passed (%.0f%%)\n", rollup.GateRunsPassed, rollup.GateRuns, rollup.GatePassRate()*100)
+	} else {
+		fmt.Printf("    Gates:    %s\n", gray("no runs yet"))
+	}
+}
+
 func init() {
+	statusCmd.Flags().StringSliceP("epic", "e", nil, "Also show progress of these epics or missions (repeatable)")
 	rootCmd.AddCommand(statusCmd)
 }
@@ -22,10 +22,8 @@ This command will:
 2. Send SIGINT for graceful shutdown
 3. Wait for the executor to shut down cleanly
 4. Send SIGKILL if shutdown takes longer than 30 seconds
-5. Update database state as needed
-
-Example:
-  $ vc stop
+5. Update database state as needed`,
+	Example: `  $ vc stop
   Found running executor (PID 965, started 5m ago)
   Sending shutdown signal...
   ✓ Executor stopped gracefully`,
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/dbsync"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <remote-db>",
+	Short: "Exchange issues and events with another VC database",
+	Long: `Sync this database with another one, such as a shared team database,
+so both end up with the same issues, labels, dependencies and events.
+
+Changes made on either side since the last sync are copied to the other.
+When both sides changed the same field, the side whose issue was updated
+last wins; every such conflict is listed. The state of the last sync is kept
+in .beads/sync/ next to this database, one file per remote.
+
+An encrypted remote database is opened with $VC_REMOTE_DB_PASSPHRASE, or
+$VC_DB_PASSPHRASE if that is unset.`,
+	Example: `  vc sync /mnt/team/.beads/beads.db
+  vc sync ~/shared/vc.db --name team`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+
+		if memoryStore {
+			fmt.Fprintf(os.Stderr, "Error: cannot sync a --memory database\n")
+			os.Exit(1)
+		}
+		remotePath, err := filepath.Abs(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid remote path: %v\n", err)
+			os.Exit(1)
+		}
+		if remotePath == dbPath {
+			fmt.Fprintf(os.Stderr, "Error: cannot sync a database with itself\n")
+			os.Exit(1)
+		}
+		if _, err := os.Stat(remotePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: remote database: %v\n", err)
+			os.Exit(1)
+		}
+		if name == "" {
+			sum := sha256.Sum256([]byte(remotePath))
+			name = hex.EncodeToString(sum[:6])
+		}
+		basePath := filepath.Join(filepath.Dir(dbPath), "sync", name+".jsonl")
+
+		ctx := context.Background()
+		remote, err := storage.NewStorage(ctx, &storage.Config{
+			Path:       remotePath,
+			Passphrase: os.Getenv("VC_REMOTE_DB_PASSPHRASE"),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to open remote database: %v\n", err)
+			os.Exit(1)
+		}
+		defer remote.Close()
+
+		base, err := dbsync.LoadBase(basePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		stats, newBase, err := dbsync.Sync(ctx, store, remote, base)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := dbsync.SaveBase(basePath, newBase); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+		fmt.Printf("%s Synced with %s\n", green("✓"), remotePath)
+		if base == nil {
+			fmt.Printf("  %s\n", gray("First sync: every difference was resolved as a conflict"))
+		}
+		printSyncSide("Local", stats.Local)
+		printSyncSide("Remote", stats.Remote)
+		for _, c := range stats.Conflicts {
+			winner := c.Local
+			if c.Winner == types.SyncRemote {
+				winner = c.Remote
+			}
+			fmt.Printf("  %s %s %s: kept %s value %q\n", yellow("conflict"), c.IssueID, c.Field, c.Winner, winner)
+		}
+		for _, skipped := range stats.Skipped {
+			fmt.Printf("  %s %s\n", yellow("skipped"), skipped)
+		}
+	},
+}
+
+// printSyncSide prints what a sync changed in one database
+func printSyncSide(label string, s types.SyncSideStats) {
+	if !s.Changed() {
+		fmt.Printf("  %s: no changes\n", label)
+		return
+	}
+	fmt.Printf("  %s: %d issue(s) created, %d field(s) updated, %d/%d label(s) added/removed, %d/%d dependencies added/removed, %d event(s) copied\n",
+		label, s.IssuesCreated, s.FieldsUpdated, s.LabelsAdded, s.LabelsRemoved,
+		s.DependenciesAdded, s.DependenciesRemoved, s.EventsCopied)
+}
+
+func init() {
+	syncCmd.Flags().String("name", "", "Name for this remote's sync state (default: derived from its path)")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -14,7 +14,7 @@ import (
 )
 
 var tailCmd = &cobra.Command{
-	Use:   "tail",
+	Use:   "tail [issue-id]",
 	Short: "Watch VC execution in real-time",
 	Long: `Display recent activity from the VC executor and follow live updates.
 
@@ -25,14 +25,44 @@ Shows events from the agent_events table including:
 - Completions
 - Errors and warnings
 
-Also shows comments from the events table for additional context.`,
+Also shows comments from the events table for additional context.
+
+Given an issue ID, streams the output of the issue's latest execution
+instead: what the agent writes, as it writes it, followed by the quality gate
+progress, until the execution finishes. The executor stores agent output as
+the agent runs, so this works from any terminal, with no log files to find.
+With --follow, keeps going across retries: when an execution finishes, waits
+for the issue's next one. Agent JSON output is summarized unless --raw is
+given.`,
+	Example: `  vc tail                  # Last 20 events
+  vc tail -f               # Follow live
+  vc tail -f -i vc-42      # Follow one issue's events
+
+  vc tail vc-42            # Stream the agent working on vc-42
+  vc tail vc-42 -f         # ...and its retries, until Ctrl+C
+  vc tail vc-42 -n 200     # Start from the last 200 lines`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		follow, _ := cmd.Flags().GetBool("follow")
 		issueID, _ := cmd.Flags().GetString("issue")
 		limit, _ := cmd.Flags().GetInt("limit")
+		raw, _ := cmd.Flags().GetBool("raw")
 
 		ctx := context.Background()
 
+		if len(args) == 1 {
+			if issueID != "" {
+				fmt.Fprintf(os.Stderr, "Error: --issue can't be combined with an issue ID argument\n")
+				os.Exit(1)
+			}
+			runTailExecution(ctx, args[0], follow, raw, limit)
+			return
+		}
+		if raw {
+			fmt.Fprintf(os.Stderr, "Error: --raw requires an issue ID argument\n")
+			os.Exit(1)
+		}
+
 		if follow {
 			runTailFollow(ctx, issueID, limit)
 		} else {
@@ -44,7 +74,8 @@ Also shows comments from the events table for additional context.`,
 func init() {
 	tailCmd.Flags().BoolP("follow", "f", false, "Follow mode - watch for live updates (Ctrl+C to stop)")
 	tailCmd.Flags().StringP("issue", "i", "", "Filter events by issue ID")
-	tailCmd.Flags().IntP("limit", "n", 20, "Number of recent events to show initially")
+	tailCmd.Flags().IntP("limit", "n", 20, "Number of recent events (or output lines) to show initially")
+	tailCmd.Flags().Bool("raw", false, "With an issue ID, print agent output exactly as written")
 	rootCmd.AddCommand(tailCmd)
 }
 
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/executor"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// tailPollInterval is how often 'vc tail <issue-id>' checks for new output.
+// The executor stores output about twice a second.
+const tailPollInterval = 500 * time.Millisecond
+
+// gateEventTypes are the agent events shown as gate progress
+var gateEventTypes = map[events.EventType]bool{
+	events.EventTypeQualityGatesStarted:   true,
+	events.EventTypeQualityGatesProgress:  true,
+	events.EventTypeQualityGatesCompleted: true,
+	events.EventTypeQualityGatesSkipped:   true,
+	events.EventTypeQualityGatesDeferred:  true,
+	events.EventTypeQualityGatesRollback:  true,
+	events.EventTypeQualityGateOverridden: true,
+}
+
+// executionTail follows the output and gate progress of an issue's
+// executions
+type executionTail struct {
+	issueID string
+	raw     bool
+
+	execution  *types.Execution // The execution being shown; nil before the first
+	lastLineID int64
+	lastEvent  time.Time
+	seenEvents map[string]bool
+}
+
+// runTailExecution streams the output of an issue's latest execution until
+// it finishes, or with follow, of each execution after it too
+func runTailExecution(ctx context.Context, issueID string, follow, raw bool, limit int) {
+	issue, err := store.GetIssue(ctx, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if issue == nil {
+		fmt.Fprintf(os.Stderr, "Error: issue %s not found\n", issueID)
+		os.Exit(1)
+	}
+
+	t := &executionTail{issueID: issueID, raw: raw, seenEvents: map[string]bool{}}
+	latest, err := t.latestExecution(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	if latest == nil {
+		if !follow {
+			fmt.Printf("No executions of %s yet %s\n", issueID, gray("(use --follow to wait for one)"))
+			return
+		}
+		fmt.Printf("%s\n", gray(fmt.Sprintf("Waiting for an execution of %s (Ctrl+C to stop)...", issueID)))
+	} else {
+		t.start(latest)
+		if err := t.poll(ctx, limit); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if t.execution.Status.IsFinal() {
+			t.finish()
+			if !follow {
+				return
+			}
+		}
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigChan:
+			fmt.Println("\nStopped following")
+			return
+		case <-ticker.C:
+		}
+
+		// Between executions: wait for the next one
+		if t.execution == nil || t.execution.Status.IsFinal() {
+			latest, err := t.latestExecution(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fetching executions: %v\n", err)
+				continue
+			}
+			if latest == nil || (t.execution != nil && latest.ID == t.execution.ID) {
+				continue
+			}
+			t.start(latest)
+		}
+
+		// Check the status before fetching, so no output is missed when
+		// the execution finishes in between
+		execution, err := store.GetExecution(ctx, t.execution.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching execution: %v\n", err)
+			continue
+		}
+		if err := t.poll(ctx, 0); err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching output: %v\n", err)
+			continue
+		}
+		if execution == nil || execution.Status.IsFinal() {
+			if execution != nil {
+				t.execution = execution
+			} else {
+				t.execution.Status = types.ExecutionFailed
+			}
+			t.finish()
+			if !follow {
+				return
+			}
+		}
+	}
+}
+
+// latestExecution returns the issue's most recent execution, or nil
+func (t *executionTail) latestExecution(ctx context.Context) (*types.Execution, error) {
+	executions, err := store.ListExecutions(ctx, types.ExecutionFilter{IssueID: t.issueID, Limit: 1})
+	if err != nil || len(executions) == 0 {
+		return nil, err
+	}
+	return executions[0], nil
+}
+
+// start switches to showing an execution
+func (t *executionTail) start(execution *types.Execution) {
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	t.execution = execution
+	t.lastLineID = 0
+	// Gate events a second early in case timestamps are stored with less
+	// precision; events already shown are skipped
+	t.lastEvent = execution.StartedAt.Add(-time.Second)
+	fmt.Printf("\n%s\n", cyan(fmt.Sprintf("=== %s: execution #%d (%s), started %s ===",
+		t.issueID, execution.ID, execution.AgentProvider, execution.StartedAt.Format("2006-01-02 15:04:05"))))
+}
+
+// finish reports how the execution being shown ended
+func (t *executionTail) finish() {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	e := t.execution
+	status := string(e.Status)
+	switch e.Status {
+	case types.ExecutionSucceeded:
+		status = green("✓ " + status)
+	case types.ExecutionFailed:
+		status = red("✗ " + status)
+	default:
+		status = yellow(status)
+	}
+	fmt.Printf("\nExecution #%d finished: %s", e.ID, status)
+	if e.CompletedAt != nil {
+		fmt.Printf(" after %s", e.Duration().Round(time.Second))
+	}
+	if e.Error != "" {
+		fmt.Printf(" (%s)", e.Error)
+	}
+	fmt.Println()
+}
+
+// tailItem is a line of output or a gate event, to be shown in time order
+type tailItem struct {
+	at    time.Time
+	line  *types.OutputLine
+	event *events.AgentEvent
+}
+
+// poll shows the output and gate events recorded since the last poll; the
+// first poll of an execution shows only its last limit lines if limit > 0
+func (t *executionTail) poll(ctx context.Context, limit int) error {
+	lines, err := store.ListExecutionOutput(ctx, types.OutputFilter{
+		ExecutionID: t.execution.ID,
+		AfterID:     t.lastLineID,
+		Limit:       limit,
+	})
+	if err != nil {
+		return err
+	}
+	agentEvents, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: t.issueID, AfterTime: t.lastEvent})
+	if err != nil {
+		return err
+	}
+
+	var items []tailItem
+	for _, line := range lines {
+		items = append(items, tailItem{at: line.Timestamp, line: line})
+		t.lastLineID = line.ID
+	}
+	for _, event := range agentEvents {
+		if !gateEventTypes[event.Type] || t.seenEvents[event.ID] {
+			continue
+		}
+		t.seenEvents[event.ID] = true
+		items = append(items, tailItem{at: event.Timestamp, event: event})
+		if event.Timestamp.After(t.lastEvent) {
+			t.lastEvent = event.Timestamp
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].at.Before(items[j].at) })
+
+	for _, item := range items {
+		if item.event != nil {
+			displayActivityEvent(item.event)
+			continue
+		}
+		for _, text := range formatOutputLine(item.line.Line, t.raw) {
+			if item.line.Stream == types.OutputStderr {
+				text = color.New(color.FgRed).Sprint(text)
+			}
+			fmt.Println(text)
+		}
+	}
+	return nil
+}
+
+// formatOutputLine returns the text to show for a line of agent output.
+// Lines of JSON agent messages are summarized (text as is, tool calls as
+// "→ Tool target", the final result) and other messages dropped, unless raw
+// is set.
+func formatOutputLine(line string, raw bool) []string {
+	if raw || !strings.HasPrefix(line, "{") {
+		return []string{line}
+	}
+	var msg executor.AgentMessage
+	if err := json.Unmarshal([]byte(line), &msg); err != nil || msg.Type == "" {
+		return []string{line}
+	}
+
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	switch msg.Type {
+	case "system":
+		if msg.Subtype == "init" {
+			if msg.Model != "" {
+				return []string{gray(fmt.Sprintf("Agent session started (%s)", msg.Model))}
+			}
+			return []string{gray("Agent session started")}
+		}
+	case "assistant":
+		if msg.Message == nil {
+			return nil
+		}
+		var out []string
+		for _, content := range msg.Message.Content {
+			switch content.Type {
+			case "text":
+				if text := strings.TrimSpace(content.Text); text != "" {
+					out = append(out, text)
+				}
+			case "tool_use":
+				out = append(out, gray("→ "+content.Name+" "+toolTarget(content.Input)))
+			}
+		}
+		return out
+	case "result":
+		if msg.IsError {
+			return []string{color.RedString("Agent finished with an error: %s", truncateString(msg.Result, 500))}
+		}
+		return []string{gray("Agent finished: " + truncateString(msg.Result, 500))}
+	}
+	return nil
+}
+
+// toolTarget returns what a tool call acts on, e.g. its file or command
+func toolTarget(input map[string]interface{}) string {
+	for _, key := range []string{"file_path", "path", "command", "cmd", "pattern", "description"} {
+		if value, ok := input[key].(string); ok && value != "" {
+			return truncateString(strings.ReplaceAll(value, "\n", " "), 120)
+		}
+	}
+	return ""
+}
@@ -198,3 +198,38 @@ func TestTailCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatOutputLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		raw  bool
+		want []string
+	}{
+		{"plain text", "go test ./...", false, []string{"go test ./..."}},
+		{"not an agent message", `{"level":"info"}`, false, []string{`{"level":"info"}`}},
+		{"raw", `{"type":"user"}`, true, []string{`{"type":"user"}`}},
+		{"tool results dropped", `{"type":"user","message":{"content":[]}}`, false, nil},
+		{"session start", `{"type":"system","subtype":"init","model":"sonnet"}`, false, []string{"Agent session started (sonnet)"}},
+		{
+			"text and tool calls",
+			`{"type":"assistant","message":{"content":[{"type":"text","text":"Reading it.\n"},{"type":"tool_use","name":"Bash","input":{"command":"go test\n./..."}}]}}`,
+			false,
+			[]string{"Reading it.", "→ Bash go test ./..."},
+		},
+		{"result", `{"type":"result","result":"All done"}`, false, []string{"Agent finished: All done"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatOutputLine(tt.line, tt.raw)
+			if len(got) != len(tt.want) {
+				t.Fatalf("formatOutputLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("formatOutputLine(%q)[%d] = %q, want %q", tt.line, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Timeline entry sources
+const (
+	timelineAction    = "action"    // The audit trail: changes by people and the executor
+	timelineAgent     = "agent"     // Agent events: assessments, agent runs, gates, analysis
+	timelineExecution = "execution" // Agent executions starting and ending
+	timelineAI        = "ai"        // AI supervisor calls
+)
+
+// timelineGapWarning is the pause between steps highlighted as a gap
+const timelineGapWarning = 10 * time.Minute
+
+// timelineDetailEvents are agent events only shown with --verbose: they
+// come many per execution and bury the steps
+var timelineDetailEvents = map[events.EventType]bool{
+	events.EventTypeAgentToolUse:     true,
+	events.EventTypeAgentHeartbeat:   true,
+	events.EventTypeAgentStateChange: true,
+	events.EventTypeProgress:         true,
+	events.EventTypeContextUsage:     true,
+	events.EventTypeFileModified:     true,
+	events.EventTypeBuildOutput:      true,
+	events.EventTypeLintOutput:       true,
+	events.EventTypeTestRun:          true,
+	events.EventTypeGitOperation:     true,
+}
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline [issue-id]",
+	Short: "Replay everything that happened to an issue, in order",
+	Long: `Reconstruct the timeline of an issue from everything VC recorded about
+it: changes by people and the executor (the audit trail), assessments,
+agent runs, quality gate results and analysis (agent events), executions,
+and AI supervisor calls, oldest first, with the time between steps.
+
+Use it to work out after the fact why an issue took the path it did.
+Pauses of 10 minutes or more are highlighted. Per-tool agent events are
+hidden unless --verbose is given.`,
+	Example: `  vc timeline vc-123
+  vc timeline vc-123 --verbose   # Include every tool call and file change
+  vc timeline vc-123 --json      # As JSON, for scripts`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		ctx := context.Background()
+		issue, err := store.GetIssue(ctx, args[0])
+		if err != nil {
+			exitWithError(exitError, err)
+		}
+		if issue == nil {
+			exitWithError(exitNotFound, fmt.Errorf("issue %s not found", args[0]))
+		}
+		entries, err := loadTimeline(ctx, store, issue.ID, verbose)
+		if err != nil {
+			exitWithError(exitError, err)
+		}
+		if jsonOutput {
+			printJSON(struct {
+				IssueID string           `json:"issue_id"`
+				Count   int              `json:"count"`
+				Entries []*timelineEntry `json:"entries"`
+			}{issue.ID, len(entries), entries})
+			return
+		}
+		printTimeline(issue, entries)
+	},
+}
+
+// timelineEntry is one step in an issue's timeline
+type timelineEntry struct {
+	Time     time.Time `json:"time"`
+	Source   string    `json:"source"` // action, agent, execution or ai
+	Kind     string    `json:"kind"`   // The event type, execution_started or execution_ended, or the AI operation
+	Actor    string    `json:"actor,omitempty"`
+	Summary  string    `json:"summary"`
+	Severity string    `json:"severity,omitempty"` // Agent events only
+	CostUSD  float64   `json:"cost_usd,omitempty"`
+
+	// SincePrevious is the time since the previous entry, zero for the first
+	SincePrevious time.Duration `json:"since_previous"`
+}
+
+// loadTimeline merges everything recorded about an issue, oldest first
+func loadTimeline(ctx context.Context, s storage.Storage, issueID string, verbose bool) ([]*timelineEntry, error) {
+	var entries []*timelineEntry
+
+	auditEvents, err := s.GetEvents(ctx, issueID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	for _, e := range auditEvents {
+		entries = append(entries, &timelineEntry{
+			Time:    e.CreatedAt,
+			Source:  timelineAction,
+			Kind:    string(e.EventType),
+			Actor:   e.Actor,
+			Summary: auditSummary(e),
+		})
+	}
+
+	agentEvents, err := s.GetAgentEventsByIssue(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent events: %w", err)
+	}
+	for _, e := range agentEvents {
+		if !verbose && (timelineDetailEvents[e.Type] || shouldSkipEvent(e)) {
+			continue
+		}
+		actor := e.AgentID
+		if actor == "" {
+			actor = e.ExecutorID
+		}
+		entries = append(entries, &timelineEntry{
+			Time:     e.Timestamp,
+			Source:   timelineAgent,
+			Kind:     string(e.Type),
+			Actor:    actor,
+			Summary:  agentEventSummary(e),
+			Severity: string(e.Severity),
+		})
+	}
+
+	executions, err := s.ListExecutions(ctx, types.ExecutionFilter{IssueID: issueID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executions: %w", err)
+	}
+	for _, e := range executions {
+		entries = append(entries, &timelineEntry{
+			Time:    e.StartedAt,
+			Source:  timelineExecution,
+			Kind:    "execution_started",
+			Actor:   e.ExecutorInstanceID,
+			Summary: fmt.Sprintf("Execution #%d started (%s)", e.ID, e.AgentProvider),
+		})
+		if e.CompletedAt == nil {
+			continue
+		}
+		summary := fmt.Sprintf("Execution #%d %s after %s", e.ID, e.Status, e.Duration().Round(time.Second))
+		if e.CommitHash != "" {
+			summary += ", commit " + e.CommitHash
+		}
+		if e.Error != "" {
+			summary += ": " + truncateString(e.Error, 80)
+		}
+		entries = append(entries, &timelineEntry{
+			Time:    *e.CompletedAt,
+			Source:  timelineExecution,
+			Kind:    "execution_ended",
+			Actor:   e.ExecutorInstanceID,
+			Summary: summary,
+			CostUSD: e.CostUSD,
+		})
+	}
+
+	usage, err := s.ListAIUsage(ctx, types.AIUsageFilter{IssueID: issueID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI usage: %w", err)
+	}
+	for _, u := range usage {
+		summary := fmt.Sprintf("AI %s (%s): %s in / %s out tokens", u.Operation, u.Model,
+			formatTokens(u.InputTokens), formatTokens(u.OutputTokens))
+		if u.Duration > 0 {
+			summary += fmt.Sprintf(", %s", u.Duration.Round(100*time.Millisecond))
+		}
+		entries = append(entries, &timelineEntry{
+			Time:    u.Timestamp,
+			Source:  timelineAI,
+			Kind:    u.Operation,
+			Summary: summary,
+			CostUSD: u.CostUSD,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	for i := 1; i < len(entries); i++ {
+		entries[i].SincePrevious = entries[i].Time.Sub(entries[i-1].Time)
+	}
+	return entries, nil
+}
+
+// auditSummary describes an audit trail event
+func auditSummary(e *types.Event) string {
+	comment := ""
+	if e.Comment != nil {
+		comment = strings.ReplaceAll(*e.Comment, "\n", " ")
+	}
+	switch e.EventType {
+	case types.EventCreated:
+		return "Created"
+	case types.EventStatusChanged, types.EventClosed, types.EventReopened:
+		summary := strings.ReplaceAll(string(e.EventType), "_", " ")
+		summary = strings.ToUpper(summary[:1]) + summary[1:]
+		if oldStatus, newStatus := e.StatusChange(); oldStatus != "" && newStatus != "" {
+			summary = statusArrow(oldStatus, newStatus)
+		}
+		if comment != "" {
+			summary += ": " + truncateString(comment, 80)
+		}
+		return summary
+	case types.EventUpdated:
+		summary := "Updated"
+		var fields map[string]json.RawMessage
+		if e.NewValue != nil && json.Unmarshal([]byte(*e.NewValue), &fields) == nil && len(fields) > 0 {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			summary += " " + strings.Join(names, ", ")
+		}
+		if oldStatus, newStatus := e.StatusChange(); newStatus != "" && newStatus != oldStatus {
+			summary += " (" + statusArrow(oldStatus, newStatus) + ")"
+		}
+		return summary
+	case types.EventCommented:
+		return "Comment: " + truncateString(comment, 80)
+	}
+	if comment != "" {
+		return truncateString(comment, 80)
+	}
+	return strings.ReplaceAll(string(e.EventType), "_", " ")
+}
+
+// statusArrow describes a status change, e.g. "Status open → in_progress"
+func statusArrow(oldStatus, newStatus string) string {
+	if oldStatus == "" {
+		return "Status → " + newStatus
+	}
+	return fmt.Sprintf("Status %s → %s", oldStatus, newStatus)
+}
+
+// agentEventSummary describes an agent event, saying whether gates passed
+// (the activity feed's message doesn't)
+func agentEventSummary(e *events.AgentEvent) string {
+	if e.Type == events.EventTypeQualityGatesCompleted {
+		duration := formatDurationMs(getIntField(e.Data, "duration_ms"))
+		switch {
+		case getBoolField(e.Data, "canceled", false):
+			return fmt.Sprintf("Quality gates canceled (%s)", duration)
+		case getBoolField(e.Data, "all_passed", true):
+			return fmt.Sprintf("Quality gates passed (%s)", duration)
+		default:
+			return fmt.Sprintf("Quality gates failed (%s)", duration)
+		}
+	}
+	return buildDisplayMessage(e)
+}
+
+// printTimeline prints an issue's timeline, one step per line
+func printTimeline(issue *types.Issue, entries []*timelineEntry) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	fmt.Printf("\n%s Timeline for %s: %s (%d steps)\n\n", cyan("🕒"), issue.ID, issue.Title, len(entries))
+	if len(entries) == 0 {
+		fmt.Printf("  %s\n\n", gray("Nothing recorded"))
+		return
+	}
+
+	var total float64
+	for i, entry := range entries {
+		gap := ""
+		if i > 0 {
+			gap = "+" + formatGap(entry.SincePrevious)
+		}
+		if entry.SincePrevious >= timelineGapWarning {
+			gap = yellow(fmt.Sprintf("%10s", gap))
+		} else {
+			gap = gray(fmt.Sprintf("%10s", gap))
+		}
+		summary := entry.Summary
+		switch entry.Severity {
+		case string(events.SeverityError), string(events.SeverityCritical):
+			summary = getSeverityColor(events.EventSeverity(entry.Severity)).Sprint(summary)
+		case string(events.SeverityWarning):
+			summary = yellow(summary)
+		}
+		extra := ""
+		if entry.CostUSD > 0 {
+			extra += fmt.Sprintf(" $%.4f", entry.CostUSD)
+			total += entry.CostUSD
+		}
+		if entry.Actor != "" {
+			extra += " by " + entry.Actor
+		}
+		fmt.Printf("  %s %s  %-9s %s%s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), gap, entry.Source, summary, gray(extra))
+	}
+
+	elapsed := entries[len(entries)-1].Time.Sub(entries[0].Time)
+	fmt.Printf("\n  %s\n\n", gray(fmt.Sprintf("%s from first to last step, $%.4f spent", formatGap(elapsed), total)))
+}
+
+// formatGap formats the time between steps compactly, e.g. 45s, 3m05s, 2h10m
+func formatGap(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%02dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+func init() {
+	timelineCmd.Flags().BoolP("verbose", "v", false, "Include per-tool agent events (tool calls, file changes, output)")
+	rootCmd.AddCommand(timelineCmd)
+}
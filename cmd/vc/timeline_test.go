@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestLoadTimeline(t *testing.T) {
+	ctx := context.Background()
+	testStore := memory.New()
+
+	issue := &types.Issue{Title: "Fix login", AcceptanceCriteria: "Login works", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+	if err := testStore.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+
+	// Recorded after the issue was created, a minute apart
+	start := time.Now().Add(time.Minute)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	for _, event := range []*events.AgentEvent{
+		{ID: "e1", Type: events.EventTypeAssessmentCompleted, Timestamp: at(0), IssueID: issue.ID, Severity: events.SeverityInfo,
+			Data: map[string]interface{}{"confidence": 0.9, "step_count": 3}},
+		{ID: "e2", Type: events.EventTypeAgentToolUse, Timestamp: at(2), IssueID: issue.ID, Severity: events.SeverityInfo,
+			Data: map[string]interface{}{"tool_name": "Edit", "target_file": "login.go"}},
+		{ID: "e3", Type: events.EventTypeQualityGatesCompleted, Timestamp: at(5), IssueID: issue.ID, Severity: events.SeverityWarning,
+			Data: map[string]interface{}{"all_passed": false}},
+	} {
+		if err := testStore.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("StoreAgentEvent() error = %v", err)
+		}
+	}
+	completed := at(4)
+	if err := testStore.CreateExecution(ctx, &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code",
+		Status: types.ExecutionSucceeded, StartedAt: at(1), CompletedAt: &completed, CostUSD: 0.5, CommitHash: "abc123"}); err != nil {
+		t.Fatalf("CreateExecution() error = %v", err)
+	}
+	if err := testStore.RecordAIUsage(ctx, &types.AIUsage{ID: "u1", Timestamp: at(6), IssueID: issue.ID,
+		Operation: "analysis", Model: "sonnet", InputTokens: 1200, OutputTokens: 300, CostUSD: 0.02}); err != nil {
+		t.Fatalf("RecordAIUsage() error = %v", err)
+	}
+
+	entries, err := loadTimeline(ctx, testStore, issue.ID, false)
+	if err != nil {
+		t.Fatalf("loadTimeline() error = %v", err)
+	}
+	want := []struct{ source, kind, summary string }{
+		{timelineAction, string(types.EventCreated), "Created"},
+		{timelineAgent, string(events.EventTypeAssessmentCompleted), "Assessment complete"},
+		{timelineExecution, "execution_started", "started (claude-code)"},
+		{timelineExecution, "execution_ended", "succeeded after 3m0s, commit abc123"},
+		{timelineAgent, string(events.EventTypeQualityGatesCompleted), "Quality gates failed"},
+		{timelineAI, "analysis", "AI analysis (sonnet): 1.2K in / 300 out tokens"},
+	}
+	if len(entries) != len(want) {
+		for _, e := range entries {
+			t.Logf("%s %s %s", e.Source, e.Kind, e.Summary)
+		}
+		t.Fatalf("loadTimeline() = %d entries, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Source != w.source || e.Kind != w.kind || !strings.Contains(e.Summary, w.summary) {
+			t.Errorf("entry %d = %s %s %q, want %s %s containing %q", i, e.Source, e.Kind, e.Summary, w.source, w.kind, w.summary)
+		}
+	}
+	if entries[0].Actor != "alice" || entries[0].SincePrevious != 0 {
+		t.Errorf("first entry = %+v, want alice's creation with no gap", entries[0])
+	}
+	if entries[3].SincePrevious != 3*time.Minute || entries[3].CostUSD != 0.5 {
+		t.Errorf("execution end = %+v, want 3m after its start and $0.50", entries[3])
+	}
+
+	verbose, err := loadTimeline(ctx, testStore, issue.ID, true)
+	if err != nil {
+		t.Fatalf("loadTimeline(verbose) error = %v", err)
+	}
+	if len(verbose) != len(want)+1 || verbose[3].Summary != "tool:Edit login.go" {
+		t.Errorf("expected --verbose to add the tool call, got %d entries", len(verbose))
+	}
+}
+
+func TestAuditSummary(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		event *types.Event
+		want  string
+	}{
+		{&types.Event{EventType: types.EventStatusChanged, OldValue: str(`{"status":"open"}`), NewValue: str(`{"status":"in_progress"}`)},
+			"Status open → in_progress"},
+		{&types.Event{EventType: types.EventClosed, Comment: str("done")}, "Closed: done"},
+		{&types.Event{EventType: types.EventReopened}, "Reopened"},
+		{&types.Event{EventType: types.EventUpdated, NewValue: str(`{"status":"closed"}`)}, "Updated status (Status → closed)"},
+		{&types.Event{EventType: types.EventUpdated, OldValue: str(`{"status":"open"}`), NewValue: str(`{"priority":0,"status":"blocked"}`)},
+			"Updated priority, status (Status open → blocked)"},
+		{&types.Event{EventType: types.EventCommented, Comment: str("Looks\nflaky")}, "Comment: Looks flaky"},
+		{&types.Event{EventType: types.EventLabelAdded, Comment: str("Added label: urgent")}, "Added label: urgent"},
+	}
+	for _, tt := range tests {
+		if got := auditSummary(tt.event); got != tt.want {
+			t.Errorf("auditSummary(%s) = %q, want %q", tt.event.EventType, got, tt.want)
+		}
+	}
+}
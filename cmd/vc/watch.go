@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream issue and execution changes as they happen",
+	Long: `Print issue lifecycle changes (create, update, status change, comment, ...)
+and new execution attempts as they are recorded, until interrupted.
+
+Changes made by any process using the database are shown, including a
+running executor.`,
+	Example: `  # Everything
+  vc watch
+
+  # One issue
+  vc watch --issue vc-123
+
+  # Only status changes and closes
+  vc watch --event status_changed --event closed`,
+	Run: func(cmd *cobra.Command, args []string) {
+		issueIDs, _ := cmd.Flags().GetStringSlice("issue")
+		eventTypes, _ := cmd.Flags().GetStringSlice("event")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		filter := types.WatchFilter{IssueIDs: issueIDs, PollInterval: interval}
+		for _, et := range eventTypes {
+			filter.EventTypes = append(filter.EventTypes, types.EventType(et))
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			cancel()
+		}()
+
+		changes, err := store.Watch(ctx, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("%s Watching for changes (Ctrl+C to stop)...\n\n", cyan("👀"))
+		for change := range changes {
+			printChange(change)
+		}
+	},
+}
+
+func printChange(change types.ChangeEvent) {
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	ts := gray(change.Timestamp.Local().Format("15:04:05"))
+	switch change.Kind {
+	case types.ChangeExecution:
+		magenta := color.New(color.FgMagenta).SprintFunc()
+		fmt.Printf("%s %s %s execution attempt #%d\n", ts, magenta("▶"), change.IssueID, change.EventID)
+	default:
+		blue := color.New(color.FgBlue).SprintFunc()
+		fmt.Printf("%s %s %s %s by %s\n", ts, blue("●"), change.IssueID, change.EventType, change.Actor)
+	}
+}
+
+func init() {
+	watchCmd.Flags().StringSliceP("issue", "i", nil, "Only show changes to these issues (repeatable)")
+	watchCmd.Flags().StringSliceP("event", "e", nil, "Only show these issue event types, e.g. status_changed (repeatable)")
+	watchCmd.Flags().Duration("interval", time.Second, "How often to check the database for changes")
+	rootCmd.AddCommand(watchCmd)
+}
@@ -46,10 +46,21 @@ type CompletionAssessment struct {
 	Caveats     []string `json:"caveats"`      // Any caveats or concerns
 }
 
-// AssessIssueState performs AI assessment before executing an issue
+// AssessIssueState performs AI assessment before executing an issue.
+// If neither the issue nor its children changed since the last assessment,
+// the cached assessment is returned without a model call.
 func (s *Supervisor) AssessIssueState(ctx context.Context, issue *types.Issue) (*Assessment, error) {
 	startTime := time.Now()
 
+	hash := s.assessmentHash(ctx, issue)
+	if hash != "" {
+		if cached, ok := s.assessments.get(issue.ID, hash); ok {
+			fmt.Printf("AI Assessment for %s: unchanged since last assessment, reusing it (confidence=%.2f)\n",
+				issue.ID, cached.Confidence)
+			return cached, nil
+		}
+	}
+
 	// Build the prompt for assessment
 	prompt := s.buildAssessmentPrompt(issue)
 
@@ -103,6 +114,9 @@ func (s *Supervisor) AssessIssueState(ctx context.Context, issue *types.Issue) (
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
+	if hash != "" {
+		s.assessments.put(issue.ID, hash, &assessment)
+	}
 	return &assessment, nil
 }
 
@@ -385,7 +399,7 @@ func (s *Supervisor) AssessCompletion(ctx context.Context, issue *types.Issue, c
 	startTime := time.Now()
 
 	// Build the prompt for completion assessment
-	prompt := s.buildCompletionPrompt(issue, children)
+	prompt := s.buildCompletionPrompt(issue, children, s.completionRollup(ctx, issue, children))
 
 	// Call Anthropic API with retry logic
 	var response *anthropic.Message
@@ -545,25 +559,32 @@ IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`
 		decompositionGuidance)
 }
 
+// completionRollup returns the storage rollup for an epic or mission, or one
+// computed from children if there is no store or the query fails
+func (s *Supervisor) completionRollup(ctx context.Context, issue *types.Issue, children []*types.Issue) *types.EpicRollup {
+	if s.store != nil {
+		rollup, err := s.store.GetEpicRollup(ctx, issue.ID)
+		if err == nil && rollup != nil {
+			return rollup
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to get rollup for %s: %v\n", issue.ID, err)
+		}
+	}
+	return types.NewEpicRollup(issue.ID, children)
+}
+
 // buildCompletionPrompt builds the prompt for assessing epic/mission completion
-func (s *Supervisor) buildCompletionPrompt(issue *types.Issue, children []*types.Issue) string {
+func (s *Supervisor) buildCompletionPrompt(issue *types.Issue, children []*types.Issue, rollup *types.EpicRollup) string {
 	// Build child summary
 	var childSummary strings.Builder
-	closedCount := 0
-	openCount := 0
-	blockedCount := 0
-
 	for _, child := range children {
 		statusSymbol := "○"
 		switch child.Status {
 		case types.StatusClosed:
 			statusSymbol = "✓"
-			closedCount++
 		case types.StatusBlocked:
 			statusSymbol = "✗"
-			blockedCount++
-		default:
-			openCount++
 		}
 
 		childSummary.WriteString(fmt.Sprintf("%s %s (%s) - %s\n", statusSymbol, child.ID, child.Status, child.Title))
@@ -608,6 +629,7 @@ Acceptance Criteria:
 
 CHILD ISSUES (%d total: %d closed, %d open, %d blocked):
 %s
+PROGRESS: %s%s
 
 ASSESSMENT TASK:
 Determine if this %s should be closed. Consider:
@@ -644,7 +666,16 @@ IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`
 		strings.ToUpper(issueTypeStr),
 		issue.ID, issue.Title, issue.Description,
 		issue.AcceptanceCriteria,
-		len(children), closedCount, openCount, blockedCount,
+		rollup.Children, rollup.Closed(), rollup.Children-rollup.Closed()-rollup.ByStatus[types.StatusBlocked], rollup.ByStatus[types.StatusBlocked],
 		childSummary.String(),
+		rollup.Summary(), openBlockersLine(rollup),
 		issueTypeStr)
 }
+
+// openBlockersLine lists a rollup's open blockers for the completion prompt
+func openBlockersLine(rollup *types.EpicRollup) string {
+	if len(rollup.OpenBlockers) == 0 {
+		return ""
+	}
+	return "\nOPEN BLOCKERS: " + strings.Join(rollup.OpenBlockers, ", ")
+}
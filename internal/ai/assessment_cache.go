@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// maxCachedAssessments bounds the assessment cache; it is emptied when full
+const maxCachedAssessments = 1000
+
+// assessmentCache remembers the last assessment of each issue with a hash of
+// what it was made from. The zero value is ready to use.
+type assessmentCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedAssessment // By issue ID
+}
+
+// cachedAssessment is an assessment and the content hash it is valid for
+type cachedAssessment struct {
+	hash       string
+	assessment Assessment
+}
+
+// get returns the issue's cached assessment if it was made from content with
+// the given hash
+func (c *assessmentCache) get(issueID, hash string) (*Assessment, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[issueID]
+	if !ok || entry.hash != hash {
+		return nil, false
+	}
+	assessment := entry.assessment
+	return &assessment, true
+}
+
+// put caches an assessment of the issue made from content with the given hash,
+// replacing any made from older content
+func (c *assessmentCache) put(issueID, hash string, assessment *Assessment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil || len(c.entries) >= maxCachedAssessments {
+		c.entries = make(map[string]cachedAssessment)
+	}
+	c.entries[issueID] = cachedAssessment{hash: hash, assessment: *assessment}
+}
+
+// assessmentHash hashes what an assessment of the issue depends on: the
+// model, the fields the prompt quotes, and the ID, status and title of each
+// issue depending on it (its children). Mutating any of them changes the
+// hash, which invalidates the cached assessment. Returns "" if the children
+// can't be listed, so the issue is assessed afresh.
+func (s *Supervisor) assessmentHash(ctx context.Context, issue *types.Issue) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%d\x00%s\x00%s\x00%s\x00",
+		s.model, issue.ID, issue.Title, issue.IssueType, issue.Priority,
+		issue.Description, issue.Design, issue.AcceptanceCriteria)
+
+	if s.store != nil {
+		children, err := s.store.GetDependents(ctx, issue.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to get children of %s for assessment cache: %v\n", issue.ID, err)
+			return ""
+		}
+		sort.Slice(children, func(i, j int) bool { return children[i].ID < children[j].ID })
+		for _, child := range children {
+			fmt.Fprintf(h, "%s\x00%s\x00%s\x00", child.ID, child.Status, child.Title)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
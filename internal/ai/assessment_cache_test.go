@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestAssessmentCache(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	parent := &types.Issue{Title: "Split the importer", Description: "It does too much", AcceptanceCriteria: "Importer is split", IssueType: types.TypeEpic, Status: types.StatusOpen, Priority: 2}
+	child := &types.Issue{Title: "Extract the parser", AcceptanceCriteria: "Parser has its own package", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2}
+	for _, issue := range []*types.Issue{parent, child} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue() error = %v", err)
+		}
+	}
+	dep := &types.Dependency{IssueID: child.ID, DependsOnID: parent.ID, Type: types.DepParentChild}
+	if err := store.AddDependency(ctx, dep, "test"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+
+	// The zero client would panic if AssessIssueState called the model
+	s := &Supervisor{store: store, model: "test-model"}
+	hash := s.assessmentHash(ctx, parent)
+	s.assessments.put(parent.ID, hash, &Assessment{Strategy: "cached", Confidence: 0.9})
+
+	assessment, err := s.AssessIssueState(ctx, parent)
+	if err != nil {
+		t.Fatalf("AssessIssueState() error = %v", err)
+	}
+	if assessment.Strategy != "cached" {
+		t.Errorf("AssessIssueState() strategy = %q, want the cached assessment", assessment.Strategy)
+	}
+
+	// Changing the issue or one of its children invalidates the assessment
+	edited := *parent
+	edited.Description = "It does too much and is slow"
+	if s.assessmentHash(ctx, &edited) == hash {
+		t.Error("editing the issue should change its assessment hash")
+	}
+	if err := store.UpdateIssue(ctx, child.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "test"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	changed := s.assessmentHash(ctx, parent)
+	if changed == hash {
+		t.Error("starting work on a child should change the parent's assessment hash")
+	}
+	if _, ok := s.assessments.get(parent.ID, changed); ok {
+		t.Error("an assessment of the old content should not be returned")
+	}
+}
@@ -116,10 +116,17 @@ func (s *Supervisor) AnalyzeCodeReviewNeed(ctx context.Context, issue *types.Iss
 //
 // Returns test sufficiency analysis with specific test issues to file.
 func (s *Supervisor) AnalyzeTestCoverage(ctx context.Context, issue *types.Issue, gitDiff string, existingTests string) (*TestSufficiencyAnalysis, error) {
+	return s.AnalyzeTestCoverageWithMutation(ctx, issue, gitDiff, existingTests, "")
+}
+
+// AnalyzeTestCoverageWithMutation is AnalyzeTestCoverage with an optional mutation
+// testing summary. Surviving mutants are strong evidence of weak assertions, so
+// when a mutation run happened its score is included in the prompt.
+func (s *Supervisor) AnalyzeTestCoverageWithMutation(ctx context.Context, issue *types.Issue, gitDiff string, existingTests string, mutationSummary string) (*TestSufficiencyAnalysis, error) {
 	startTime := time.Now()
 
 	// Build the prompt for test coverage analysis
-	prompt := s.buildTestCoveragePrompt(issue, gitDiff, existingTests)
+	prompt := s.buildTestCoveragePrompt(issue, gitDiff, existingTests, mutationSummary)
 
 	// Call Anthropic API with retry logic using Sonnet (thorough analysis)
 	var response *anthropic.Message
@@ -314,7 +321,7 @@ IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`
 }
 
 // buildTestCoveragePrompt builds the prompt for test coverage analysis
-func (s *Supervisor) buildTestCoveragePrompt(issue *types.Issue, gitDiff string, existingTests string) string {
+func (s *Supervisor) buildTestCoveragePrompt(issue *types.Issue, gitDiff string, existingTests string, mutationSummary string) string {
 	// Truncate diff if it's too large
 	diffToAnalyze := gitDiff
 	diffTruncated := false
@@ -338,6 +345,13 @@ func (s *Supervisor) buildTestCoveragePrompt(issue *types.Issue, gitDiff string,
 		truncationNote = "\n\nNote: Content was truncated. Base your analysis on what's shown."
 	}
 
+	mutationSection := ""
+	if mutationSummary != "" {
+		mutationSection = fmt.Sprintf("\n\nMUTATION TESTING RESULTS:\n%s\n"+
+			"Surviving mutants mean the tests execute the code but don't assert on its behavior. "+
+			"Treat a low mutation score as evidence of weak assertions, not just missing tests.", mutationSummary)
+	}
+
 	return fmt.Sprintf(`You are analyzing test coverage for code changes to identify specific test gaps.
 
 IMPORTANT: Your job is to find SPECIFIC, ACTIONABLE test gaps. Each gap you identify will become a separate test improvement issue.
@@ -353,7 +367,7 @@ GIT DIFF (what changed):
 %s
 
 EXISTING TESTS (for reference):
-%s%s
+%s%s%s
 
 ANALYSIS TASK:
 Analyze the changes and existing tests to identify specific test coverage gaps. Consider:
@@ -457,7 +471,8 @@ IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`
 		issue.Description,
 		diffToAnalyze,
 		testsToAnalyze,
-		truncationNote)
+		truncationNote,
+		mutationSection)
 }
 
 // buildCodeQualityPrompt builds the prompt for automated code quality analysis
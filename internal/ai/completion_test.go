@@ -8,7 +8,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/memory"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -190,12 +190,7 @@ func TestAssessCompletion_EmptyChildren(t *testing.T) {
 
 func TestAssessCompletion_ErrorHandling(t *testing.T) {
 	// Create supervisor with invalid API key to force errors
-	tmpDB := t.TempDir() + "/test.db"
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: tmpDB})
-	if err != nil {
-		t.Fatalf("Failed to create test store: %v", err)
-	}
-	t.Cleanup(func() { _ = store.Close() })
+	store := memory.New()
 
 	cfg := &Config{
 		Store:  store,
@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/steveyegge/vc/internal/logging"
+)
+
+// BugReportDraft is a bug issue drafted from production errors
+type BugReportDraft struct {
+	Title              string `json:"title"`
+	Description        string `json:"description"`
+	AcceptanceCriteria string `json:"acceptance_criteria"`
+}
+
+// DraftBugReport uses AI to draft a bug issue from a report of errors
+// captured by an error tracker: their message, stack trace, how often they
+// occur and in which environments and releases. The description should let
+// someone reproduce and fix the error without opening the tracker.
+func (s *Supervisor) DraftBugReport(ctx context.Context, report string) (*BugReportDraft, error) {
+	startTime := time.Now()
+
+	if strings.TrimSpace(report) == "" {
+		return nil, fmt.Errorf("error report cannot be empty")
+	}
+	if err := s.checkBudget(""); err != nil {
+		return nil, err
+	}
+
+	prompt := fmt.Sprintf(`You are filing a bug issue for an AI coding agent that will fix it. An error tracker captured the following production errors, grouped as one problem:
+
+%s
+
+Draft the issue. Respond with ONLY raw JSON (no markdown fences):
+{
+  "title": "Short imperative title naming the failure and where it happens (max 100 characters)",
+  "description": "Markdown: what fails and its impact (frequency, environments, releases), the most relevant stack frames, likely cause if the trace shows it, and how to reproduce",
+  "acceptance_criteria": "Verifiable conditions for the bug to be fixed, including a regression test"
+}
+
+Only state what the report supports; say when the cause or reproduction is unknown.`,
+		safeTruncateString(report, 30000))
+
+	var response *anthropic.Message
+	err := s.retryWithBackoff(ctx, "bug-report-draft", func(attemptCtx context.Context) error {
+		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
+			Model:     anthropic.Model(s.model),
+			MaxTokens: 4096,
+			Messages: []anthropic.MessageParam{
+				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+			},
+		})
+		if apiErr != nil {
+			return apiErr
+		}
+		response = resp
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("AI bug report draft failed after %d retry attempts: %w", s.retry.MaxRetries+1, err)
+	}
+
+	var responseText string
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			responseText += block.Text
+		}
+	}
+	parseResult := Parse[BugReportDraft](responseText, ParseOptions{
+		Context:   "bug report draft response",
+		LogErrors: boolPtr(true),
+	})
+	if !parseResult.Success {
+		return nil, fmt.Errorf("failed to parse bug report draft: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+	}
+	draft := parseResult.Data
+	if strings.TrimSpace(draft.Title) == "" {
+		return nil, fmt.Errorf("bug report draft has no title")
+	}
+
+	duration := time.Since(startTime)
+	slog.InfoContext(ctx, "AI bug report draft completed",
+		logging.KeyOperation, "bug-report-draft", logging.KeyProvider, providerAnthropic,
+		logging.KeyModel, s.model, "input_chars", len(report), "duration", duration)
+
+	if err := s.recordAIUsage(ctx, "", "bug-report-draft", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+		slog.WarnContext(ctx, "Failed to log AI usage", logging.KeyOperation, "bug-report-draft", "error", err)
+	}
+
+	return &draft, nil
+}
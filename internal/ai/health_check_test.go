@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestSupervisorHealthCheck(t *testing.T) {
@@ -60,6 +61,25 @@ func TestSupervisorHealthCheck(t *testing.T) {
 		}
 	})
 
+	t.Run("passes once the open timeout has passed", func(t *testing.T) {
+		s := &Supervisor{
+			retry:          DefaultRetryConfig(),
+			circuitBreaker: NewCircuitBreaker(2, 1, 10*time.Millisecond),
+		}
+
+		// Open the circuit, then wait out its timeout without any API calls
+		s.circuitBreaker.RecordFailure()
+		s.circuitBreaker.RecordFailure()
+		time.Sleep(20 * time.Millisecond)
+
+		if err := s.HealthCheck(context.Background()); err != nil {
+			t.Errorf("expected recovery probe to be allowed, got: %v", err)
+		}
+		if state := s.circuitBreaker.GetState(); state != CircuitHalfOpen {
+			t.Errorf("expected half-open state, got: %v", state)
+		}
+	})
+
 	t.Run("passes when circuit breaker is disabled", func(t *testing.T) {
 		s := &Supervisor{
 			retry:          DefaultRetryConfig(),
@@ -0,0 +1,195 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// maxLintTriageInput bounds how much linter output is sent for triage
+const maxLintTriageInput = 50000
+
+// maxFocusedPreExisting bounds how many pre-existing findings are listed in
+// the focused recovery output (the rest are only counted)
+const maxFocusedPreExisting = 5
+
+// LintFinding is a single linter finding identified during triage
+type LintFinding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Linter  string `json:"linter"`
+	Message string `json:"message"`
+}
+
+// String formats the finding the way linters print it
+func (f LintFinding) String() string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	if f.Linter != "" {
+		return fmt.Sprintf("%s: %s (%s)", location, f.Message, f.Linter)
+	}
+	return fmt.Sprintf("%s: %s", location, f.Message)
+}
+
+// LintTriage buckets lint findings so recovery can focus on what the current
+// work actually broke instead of the whole linter output.
+type LintTriage struct {
+	MustFix     []LintFinding `json:"must_fix"`     // Real problems in code changed by this issue
+	PreExisting []LintFinding `json:"pre_existing"` // Findings in code this issue didn't touch
+	Stylistic   []LintFinding `json:"stylistic"`    // Style-only findings (formatting, naming, comments)
+	Summary     string        `json:"summary"`      // Short overall assessment
+}
+
+// FocusedOutput renders the triage for the recovery prompt: must-fix findings
+// in full, pre-existing findings abbreviated, stylistic findings only counted.
+func (t *LintTriage) FocusedOutput() string {
+	var sb strings.Builder
+
+	if t.Summary != "" {
+		sb.WriteString(fmt.Sprintf("Triage summary: %s\n\n", t.Summary))
+	}
+
+	sb.WriteString(fmt.Sprintf("MUST FIX - in code changed by this issue (%d):\n", len(t.MustFix)))
+	if len(t.MustFix) == 0 {
+		sb.WriteString("  (none)\n")
+	}
+	for _, f := range t.MustFix {
+		sb.WriteString(fmt.Sprintf("  - %s\n", f))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nPRE-EXISTING - not caused by this issue (%d):\n", len(t.PreExisting)))
+	for i, f := range t.PreExisting {
+		if i >= maxFocusedPreExisting {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(t.PreExisting)-maxFocusedPreExisting))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("  - %s\n", f))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nSTYLISTIC - formatting/naming only (%d, not listed)\n", len(t.Stylistic)))
+
+	return sb.String()
+}
+
+// TriageLintFailures uses AI to bucket lint findings into must-fix (changed code),
+// pre-existing, and stylistic findings (ZFC).
+//
+// This keeps recovery focused when the lint gate reports dozens of findings:
+// the recovery prompt gets the triage instead of the raw (truncated) linter output.
+func (s *Supervisor) TriageLintFailures(ctx context.Context, issue *types.Issue, lintOutput string, changedFiles []string) (*LintTriage, error) {
+	if issue == nil {
+		return nil, fmt.Errorf("issue cannot be nil")
+	}
+	if lintOutput == "" {
+		return nil, fmt.Errorf("lint output cannot be empty")
+	}
+	if len(lintOutput) > maxLintTriageInput {
+		lintOutput = lintOutput[:maxLintTriageInput] + "\n... (truncated)"
+	}
+
+	startTime := time.Now()
+
+	prompt := s.buildLintTriagePrompt(issue, lintOutput, changedFiles)
+
+	// Call Anthropic API with retry logic
+	var response *anthropic.Message
+	err := s.retryWithBackoff(ctx, "lint-triage", func(attemptCtx context.Context) error {
+		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
+			Model:     anthropic.Model(s.model),
+			MaxTokens: 4096,
+			Messages: []anthropic.MessageParam{
+				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+			},
+		})
+		if apiErr != nil {
+			return apiErr
+		}
+		response = resp
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("anthropic API call failed: %w", err)
+	}
+
+	// Extract the text content from the response
+	var responseText string
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			responseText += block.Text
+		}
+	}
+
+	// Parse the response as JSON using resilient parser
+	parseResult := Parse[LintTriage](responseText, ParseOptions{
+		Context:   "lint triage response",
+		LogErrors: boolPtr(true),
+	})
+	if !parseResult.Success {
+		return nil, fmt.Errorf("failed to parse lint triage response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+	}
+	triage := parseResult.Data
+
+	duration := time.Since(startTime)
+	fmt.Printf("AI Lint Triage for %s: must_fix=%d, pre_existing=%d, stylistic=%d, duration=%v\n",
+		issue.ID, len(triage.MustFix), len(triage.PreExisting), len(triage.Stylistic), duration)
+
+	// Log AI usage to events
+	if err := s.recordAIUsage(ctx, issue.ID, "lint-triage", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
+	}
+
+	return &triage, nil
+}
+
+// buildLintTriagePrompt builds the prompt for bucketing lint findings
+func (s *Supervisor) buildLintTriagePrompt(issue *types.Issue, lintOutput string, changedFiles []string) string {
+	changed := "(unknown - use the issue description to judge what this work touched)"
+	if len(changedFiles) > 0 {
+		changed = "- " + strings.Join(changedFiles, "\n- ")
+	}
+
+	return fmt.Sprintf(`You are triaging linter findings from a failed lint quality gate.
+
+The linter reported many findings. Most are usually unrelated to the current work.
+Your job is to separate what the current work must fix from everything else, so
+the recovery step can focus on the findings that matter.
+
+ISSUE DETAILS:
+ID: %s
+Title: %s
+Description: %s
+
+FILES CHANGED BY THIS WORK:
+%s
+
+LINTER OUTPUT:
+`+"```"+`
+%s
+`+"```"+`
+
+Put every finding in exactly one bucket:
+1. "must_fix" - real problems (bugs, unchecked errors, unused code, vet issues) in code changed by this work
+2. "pre_existing" - real problems in code this work did not touch
+3. "stylistic" - formatting, naming, comment style and similar findings, wherever they are
+
+Provide your triage as a JSON object:
+{
+  "must_fix": [{"file": "path/to/file.go", "line": 42, "linter": "errcheck", "message": "Error return value is not checked"}],
+  "pre_existing": [],
+  "stylistic": [],
+  "summary": "One or two sentences on what the current work needs to fix"
+}
+
+IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`+"```"+`). Just the JSON object.`,
+		issue.ID, issue.Title, issue.Description,
+		changed,
+		lintOutput)
+}
@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestLintTriage_FocusedOutput(t *testing.T) {
+	triage := &LintTriage{
+		MustFix: []LintFinding{
+			{File: "internal/foo/foo.go", Line: 12, Linter: "errcheck", Message: "Error return value is not checked"},
+		},
+		Stylistic: []LintFinding{
+			{File: "internal/foo/foo.go", Line: 3, Linter: "gofmt", Message: "File is not gofmt-ed"},
+		},
+		Summary: "One unchecked error in the new code",
+	}
+	for i := 0; i < maxFocusedPreExisting+3; i++ {
+		triage.PreExisting = append(triage.PreExisting, LintFinding{File: "old.go", Line: i + 1, Message: "unused"})
+	}
+
+	output := triage.FocusedOutput()
+
+	if !strings.Contains(output, "internal/foo/foo.go:12: Error return value is not checked (errcheck)") {
+		t.Errorf("Expected must-fix finding in output, got:\n%s", output)
+	}
+	if !strings.Contains(output, "PRE-EXISTING - not caused by this issue (8)") {
+		t.Errorf("Expected pre-existing count in output, got:\n%s", output)
+	}
+	if !strings.Contains(output, "... and 3 more") {
+		t.Errorf("Expected pre-existing findings to be abbreviated, got:\n%s", output)
+	}
+	if strings.Contains(output, "gofmt-ed") {
+		t.Errorf("Expected stylistic findings to be counted, not listed, got:\n%s", output)
+	}
+	if !strings.Contains(output, "One unchecked error in the new code") {
+		t.Errorf("Expected summary in output, got:\n%s", output)
+	}
+}
+
+func TestBuildRecoveryPrompt_UsesLintTriage(t *testing.T) {
+	s := &Supervisor{}
+	issue := &types.Issue{ID: "vc-1", Title: "Add feature", IssueType: types.TypeFeature, Priority: 2}
+
+	var rawOutput strings.Builder
+	for i := 0; i < 50; i++ {
+		rawOutput.WriteString(fmt.Sprintf("old.go:%d:1: RAW-FINDING (unused)\n", i+1))
+	}
+
+	prompt := s.buildRecoveryPrompt(issue, []GateFailure{{
+		Gate:   "lint",
+		Output: rawOutput.String(),
+		Error:  "golangci-lint failed: exit status 1",
+		Triage: &LintTriage{
+			MustFix: []LintFinding{{File: "new.go", Line: 5, Linter: "errcheck", Message: "unchecked error"}},
+		},
+	}})
+
+	if strings.Contains(prompt, "RAW-FINDING") {
+		t.Error("Expected raw lint output to be replaced by the triage")
+	}
+	if !strings.Contains(prompt, "new.go:5: unchecked error (errcheck)") {
+		t.Error("Expected must-fix finding in recovery prompt")
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/memory"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -19,13 +19,7 @@ func TestMetaIssueWorkflowIntegration(t *testing.T) {
 	ctx := context.Background()
 
 	// Create in-memory storage
-	cfg := storage.DefaultConfig()
-	cfg.Path = ":memory:"
-	store, err := storage.NewStorage(ctx, cfg)
-	if err != nil {
-		t.Fatalf("failed to create storage: %v", err)
-	}
-	defer store.Close()
+	store := memory.New()
 
 	// Create supervisor with storage (no real API calls in this test)
 	supervisor := &Supervisor{
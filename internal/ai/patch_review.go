@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// PatchReview is the supervisor's verdict on a patch an agent proposed in
+// patch-proposal mode, before it is applied to the real branch
+type PatchReview struct {
+	Approved   bool     `json:"approved"`   // Should the patch be applied?
+	Reasoning  string   `json:"reasoning"`  // Why it should or shouldn't be
+	Concerns   []string `json:"concerns"`   // Specific problems found (may be set even when approved)
+	Confidence float64  `json:"confidence"` // Confidence in the verdict (0.0-1.0)
+}
+
+// ReviewPatch reviews a patch proposed by an agent for an issue and decides
+// whether it may be applied to the working branch.
+//
+// The AI reviews the patch using Sonnet, looking for:
+// - Whether it does what the issue asks, and nothing else
+// - Destructive or risky edits (deleted code, CI/config/credential changes)
+// - Obvious bugs and security problems
+//
+// Returns the verdict with reasoning.
+func (s *Supervisor) ReviewPatch(ctx context.Context, issue *types.Issue, patch string) (*PatchReview, error) {
+	startTime := time.Now()
+
+	prompt := s.buildPatchReviewPrompt(issue, patch)
+
+	// Call Anthropic API with retry logic using Sonnet (thorough analysis)
+	var response *anthropic.Message
+	err := s.retryWithBackoff(ctx, "patch-review", func(attemptCtx context.Context) error {
+		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
+			Model:     anthropic.Model(s.model),
+			MaxTokens: 2048,
+			Messages: []anthropic.MessageParam{
+				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+			},
+		})
+		if apiErr != nil {
+			return apiErr
+		}
+		response = resp
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("anthropic API call failed: %w", err)
+	}
+
+	// Extract the text content from the response
+	var responseText string
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			responseText += block.Text
+		}
+	}
+
+	// Parse the response as JSON using resilient parser
+	parseResult := Parse[PatchReview](responseText, ParseOptions{
+		Context:   "patch review response",
+		LogErrors: boolPtr(true),
+	})
+	if !parseResult.Success {
+		return nil, fmt.Errorf("failed to parse patch review response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+	}
+	review := parseResult.Data
+
+	// Log the review
+	duration := time.Since(startTime)
+	fmt.Printf("AI Patch Review for %s: approved=%v, concerns=%d, confidence=%.2f, duration=%v\n",
+		issue.ID, review.Approved, len(review.Concerns), review.Confidence, duration)
+
+	// Log AI usage to events
+	if err := s.recordAIUsage(ctx, issue.ID, "patch-review", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
+	}
+
+	return &review, nil
+}
+
+// buildPatchReviewPrompt builds the prompt for reviewing a proposed patch
+func (s *Supervisor) buildPatchReviewPrompt(issue *types.Issue, patch string) string {
+	// Truncate the patch if it's too large; a reviewer that can't see all of
+	// it is told so, and should be more cautious
+	patchToReview := patch
+	truncationNote := ""
+	const maxPatchSize = 50000
+	if len(patch) > maxPatchSize {
+		patchToReview = safeTruncateString(patch, maxPatchSize) + "\n\n... [patch truncated - remaining content omitted] ..."
+		truncationNote = "\n\nNote: The patch was truncated. Do not approve it unless what's shown gives you no reason for doubt."
+	}
+
+	return fmt.Sprintf(`You are reviewing a patch that a coding agent proposed for an issue. The agent worked in a scratch copy of the repository; the patch will only be applied to the real branch if you approve it. This repository does not allow agents to edit it directly, so be careful.
+
+ISSUE CONTEXT:
+Issue ID: %s
+Title: %s
+Type: %s
+Priority: P%d
+Description: %s
+
+Acceptance Criteria:
+%s
+
+PROPOSED PATCH:
+%s%s
+
+REVIEW TASK:
+Decide whether this patch should be applied. Consider:
+
+1. **Fit**: Does it do what the issue asks? Does it change anything unrelated?
+2. **Destructive edits**: Does it delete or rewrite code, tests or data it shouldn't?
+3. **Risky files**: Does it touch CI, build, deployment, dependency or credential files without the issue calling for it?
+4. **Correctness and security**: Are there obvious bugs, vulnerabilities or leaked secrets?
+
+Reject patches that are unrelated to the issue, destructive, or unsafe. Minor style problems are not a reason to reject; list them as concerns instead.
+
+Provide your verdict as a JSON object:
+{
+  "approved": true/false,
+  "reasoning": "Why the patch should or shouldn't be applied",
+  "concerns": ["Specific problem, with the file it's in"],
+  "confidence": 0.9
+}
+
+IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`+"```"+`). Just the JSON object.`,
+		issue.ID, issue.Title, issue.IssueType, issue.Priority,
+		issue.Description,
+		issue.AcceptanceCriteria,
+		patchToReview,
+		truncationNote)
+}
@@ -0,0 +1,35 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestBuildPatchReviewPrompt(t *testing.T) {
+	s := &Supervisor{}
+	issue := &types.Issue{
+		ID:                 "vc-42",
+		Title:              "Retry failed charges",
+		IssueType:          types.TypeTask,
+		Priority:           1,
+		AcceptanceCriteria: "Charges are retried up to 3 times",
+	}
+	patch := "diff --git a/pay.go b/pay.go\n+retry()\n"
+
+	prompt := s.buildPatchReviewPrompt(issue, patch)
+	for _, want := range []string{"vc-42", "Charges are retried up to 3 times", patch, `"approved"`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q", want)
+		}
+	}
+	if strings.Contains(prompt, "patch was truncated") {
+		t.Error("a small patch should not be truncated")
+	}
+
+	prompt = s.buildPatchReviewPrompt(issue, patch+strings.Repeat("+x\n", 30000))
+	if !strings.Contains(prompt, "patch was truncated") {
+		t.Error("a large patch should be truncated with a note")
+	}
+}
@@ -25,9 +25,10 @@ type RecoveryStrategy struct {
 
 // GateFailure represents a failed quality gate with details
 type GateFailure struct {
-	Gate   string // Gate type: "test", "lint", "build"
-	Output string // Truncated output from the gate
-	Error  string // Error message
+	Gate   string      // Gate type: "test", "lint", "build"
+	Output string      // Truncated output from the gate
+	Error  string      // Error message
+	Triage *LintTriage // Optional: bucketed lint findings, used instead of Output when set
 }
 
 // GenerateRecoveryStrategy uses AI to determine how to recover from quality gate failures.
@@ -106,7 +107,9 @@ func (s *Supervisor) buildRecoveryPrompt(issue *types.Issue, gateResults []GateF
 	for i, result := range gateResults {
 		failureSummary.WriteString(fmt.Sprintf("\n%d. %s GATE FAILED:\n", i+1, strings.ToUpper(result.Gate)))
 		failureSummary.WriteString(fmt.Sprintf("   Error: %s\n", result.Error))
-		if result.Output != "" {
+		if result.Triage != nil {
+			failureSummary.WriteString(fmt.Sprintf("   Findings (triaged):\n%s\n", result.Triage.FocusedOutput()))
+		} else if result.Output != "" {
 			failureSummary.WriteString(fmt.Sprintf("   Output:\n```\n%s\n```\n", result.Output))
 		}
 	}
@@ -143,6 +146,7 @@ Examples:
 - Flaky test failures → retry or acceptable_failure
 - Critical bug in P0 issue → fix_in_place
 - Lint warnings in chore task → acceptable_failure (with blocker issue for pre-existing lint errors)
+- Triaged lint findings → fix only the MUST FIX findings; pre-existing and stylistic findings must not block the issue
 - Build failures → fix_in_place
 - Test failures for new features → fix_in_place
 - Pre-existing test failures unrelated to current work → acceptable_failure (with blocker issue to fix them)
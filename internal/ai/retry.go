@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"regexp"
@@ -13,6 +14,9 @@ import (
 	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/steveyegge/vc/internal/logging"
+	"github.com/steveyegge/vc/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Pre-compiled regex patterns for parseRetryAfterFromMessage (vc-5b22)
@@ -117,16 +121,16 @@ func DefaultRetryConfig() RetryConfig {
 		if d, err := time.ParseDuration(env); err == nil {
 			// Validate bounds
 			if d <= 0 {
-				fmt.Fprintf(os.Stderr, "Warning: VC_MAX_QUOTA_WAIT must be positive (%v), using default 15m\n", d)
+				slog.Warn("VC_MAX_QUOTA_WAIT must be positive, using default 15m", "value", d)
 				maxQuotaWait = 15 * time.Minute
 			} else if d > 24*time.Hour {
-				fmt.Fprintf(os.Stderr, "Warning: VC_MAX_QUOTA_WAIT exceeds 24h (%v), capping at 24h\n", d)
+				slog.Warn("VC_MAX_QUOTA_WAIT exceeds 24h, capping at 24h", "value", d)
 				maxQuotaWait = 24 * time.Hour
 			} else {
 				maxQuotaWait = d
 			}
 		} else {
-			fmt.Fprintf(os.Stderr, "Warning: Invalid VC_MAX_QUOTA_WAIT format (%q), using default 15m\n", env)
+			slog.Warn("Invalid VC_MAX_QUOTA_WAIT format, using default 15m", "value", env)
 		}
 	}
 
@@ -261,7 +265,8 @@ func (cb *CircuitBreaker) transitionToClosed() {
 	cb.failureCount = 0
 	cb.successCount = 0
 	cb.lastStateChange = time.Now()
-	fmt.Printf("Circuit breaker state transition: %s → %s (failures reset)\n", oldState, cb.state)
+	slog.Info("Circuit breaker state transition (failures reset)",
+		logging.KeyProvider, providerAnthropic, "from", oldState.String(), "to", cb.state.String())
 }
 
 // transitionToOpen moves the circuit to open state (must be called with lock held)
@@ -270,8 +275,9 @@ func (cb *CircuitBreaker) transitionToOpen() {
 	cb.state = CircuitOpen
 	cb.successCount = 0
 	cb.lastStateChange = time.Now()
-	fmt.Printf("Circuit breaker state transition: %s → %s (failures=%d, will reopen in %v)\n",
-		oldState, cb.state, cb.failureCount, cb.openTimeout)
+	slog.Warn("Circuit breaker state transition",
+		logging.KeyProvider, providerAnthropic, "from", oldState.String(), "to", cb.state.String(),
+		"failures", cb.failureCount, "reopen_in", cb.openTimeout)
 }
 
 // transitionToHalfOpen moves the circuit to half-open state (must be called with lock held)
@@ -280,7 +286,8 @@ func (cb *CircuitBreaker) transitionToHalfOpen() {
 	cb.state = CircuitHalfOpen
 	cb.successCount = 0
 	cb.lastStateChange = time.Now()
-	fmt.Printf("Circuit breaker state transition: %s → %s (probing for recovery)\n", oldState, cb.state)
+	slog.Info("Circuit breaker state transition (probing for recovery)",
+		logging.KeyProvider, providerAnthropic, "from", oldState.String(), "to", cb.state.String())
 }
 
 // classifyError determines the error type for intelligent retry handling (vc-5b22)
@@ -395,7 +402,7 @@ func parseRetryAfter(apiErr *anthropic.Error) time.Duration {
 					return waitTime
 				} else if waitTime < 0 {
 					// Clock skew or stale header - log for debugging
-					fmt.Fprintf(os.Stderr, "Warning: X-RateLimit-Reset is in the past (skew: %v)\n", -waitTime)
+					slog.Warn("X-RateLimit-Reset is in the past", logging.KeyProvider, providerAnthropic, "skew", -waitTime)
 				}
 			}
 		}
@@ -462,8 +469,13 @@ func parseRetryAfterFromMessage(msg string) time.Duration {
 	return 0
 }
 
-// retryWithBackoff executes an operation with retry and exponential backoff
-func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn func(context.Context) error) error {
+// retryWithBackoff executes an operation with retry and exponential backoff.
+// The operation is traced as one span, parent to a span per API request.
+func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn func(context.Context) error) (err error) {
+	ctx, span := tracing.Start(ctx, "ai "+operation,
+		attribute.String(tracing.AttrOperation, operation), attribute.String(tracing.AttrProvider, providerAnthropic))
+	defer func() { tracing.End(span, err) }()
+
 	// Acquire concurrency slot if limiter is enabled (vc-220)
 	if s.concurrencySem != nil {
 		if err := s.concurrencySem.Acquire(ctx, 1); err != nil {
@@ -474,13 +486,14 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 
 	var lastErr error
 	backoff := s.retry.InitialBackoff
+	logger := slog.With(logging.KeyOperation, operation, logging.KeyProvider, providerAnthropic)
 
 	for attempt := 0; attempt <= s.retry.MaxRetries; attempt++ {
 		// Check cost budget before attempting request (vc-e3s7)
 		// Note: We check budget per-attempt to handle budget resets during retries
 		if err := s.checkBudget(""); err != nil {
 			// Budget exceeded, fail fast without retrying
-			fmt.Fprintf(os.Stderr, "AI API %s blocked by cost budget: %v\n", operation, err)
+			logger.WarnContext(ctx, "AI API call blocked by cost budget", "error", err)
 			return fmt.Errorf("%s failed: %w", operation, err)
 		}
 
@@ -489,8 +502,8 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 			if err := s.circuitBreaker.Allow(); err != nil {
 				// Circuit is open, fail fast without retrying
 				state, failures, _ := s.circuitBreaker.GetMetrics()
-				fmt.Fprintf(os.Stderr, "AI API %s blocked by circuit breaker (state=%s, failures=%d)\n",
-					operation, state, failures)
+				logger.WarnContext(ctx, "AI API call blocked by circuit breaker",
+					"state", state.String(), "failures", failures)
 				return fmt.Errorf("%s failed: %w", operation, err)
 			}
 		}
@@ -510,7 +523,7 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 			}
 
 			if attempt > 0 {
-				fmt.Printf("AI API %s succeeded after %d retries\n", operation, attempt)
+				logger.InfoContext(ctx, "AI API call succeeded after retries", "retries", attempt)
 			}
 			return nil
 		}
@@ -533,17 +546,16 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 		switch errorType {
 		case ErrorAuth, ErrorInvalid:
 			// Non-retriable errors - fail immediately
-			fmt.Fprintf(os.Stderr, "AI API %s failed with non-retriable error (%s): %v\n",
-				operation, errorType, err)
+			logger.ErrorContext(ctx, "AI API call failed with non-retriable error",
+				"error_type", errorType.String(), "error", err)
 			return err
 
 		case ErrorQuota:
 			// Quota exceeded - intelligent wait based on retry-after (vc-5b22)
 			if quotaWait > s.retry.MaxQuotaWait {
-				fmt.Fprintf(os.Stderr, "⚠️  Quota exceeded: retry-after (%v) exceeds max wait (%v)\n",
-					quotaWait, s.retry.MaxQuotaWait)
-				fmt.Fprintf(os.Stderr, "    Current attempt: %d/%d\n", attempt+1, s.retry.MaxRetries+1)
-				fmt.Fprintf(os.Stderr, "    Consider adjusting VC_MAX_QUOTA_WAIT or waiting manually\n")
+				logger.ErrorContext(ctx, "Quota exceeded: retry-after exceeds max wait; consider adjusting VC_MAX_QUOTA_WAIT or waiting manually",
+					"retry_after", quotaWait, "max_wait", s.retry.MaxQuotaWait,
+					"attempt", attempt+1, "max_attempts", s.retry.MaxRetries+1)
 				return fmt.Errorf("%s failed: %w (quota wait %v exceeds max %v)",
 					operation, err, quotaWait, s.retry.MaxQuotaWait)
 			}
@@ -560,14 +572,13 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 
 			// Wait for quota reset
 			resetAt := time.Now().Add(quotaWait)
-			fmt.Printf("⚠️  Quota exceeded: API rate limit hit\n")
-			fmt.Printf("    Retry after: %v (at %s)\n", quotaWait, resetAt.Format("15:04:05 MST"))
-			fmt.Printf("    Attempt: %d/%d\n", attempt+1, s.retry.MaxRetries+1)
-			fmt.Printf("    Waiting for quota reset...\n")
+			logger.WarnContext(ctx, "Quota exceeded: waiting for quota reset",
+				"retry_after", quotaWait, "reset_at", resetAt.Format("15:04:05 MST"),
+				"attempt", attempt+1, "max_attempts", s.retry.MaxRetries+1)
 
 			select {
 			case <-time.After(quotaWait):
-				fmt.Printf("Quota wait completed, retrying %s\n", operation)
+				logger.InfoContext(ctx, "Quota wait completed, retrying")
 				continue // Retry immediately after wait
 			case <-ctx.Done():
 				return fmt.Errorf("%s failed: context canceled during quota wait: %w", operation, ctx.Err())
@@ -586,8 +597,8 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 			}
 
 			// Log the retry
-			fmt.Printf("AI API %s failed (attempt %d/%d), retrying in %v: %v\n",
-				operation, attempt+1, s.retry.MaxRetries+1, backoff, err)
+			logger.WarnContext(ctx, "AI API call failed, retrying",
+				"attempt", attempt+1, "max_attempts", s.retry.MaxRetries+1, "backoff", backoff, "error", err)
 
 			// Sleep with exponential backoff
 			select {
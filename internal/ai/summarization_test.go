@@ -5,7 +5,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/memory"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -160,12 +160,7 @@ func TestSummarizeAgentOutput_LargeOutput(t *testing.T) {
 // without falling back to heuristics (ZFC compliance)
 func TestSummarizeAgentOutput_ErrorHandling(t *testing.T) {
 	// Create supervisor with invalid API key to force errors
-	tmpDB := t.TempDir() + "/test.db"
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: tmpDB})
-	if err != nil {
-		t.Fatalf("Failed to create test store: %v", err)
-	}
-	t.Cleanup(func() { _ = store.Close() })
+	store := memory.New()
 
 	cfg := &Config{
 		Store:  store,
@@ -204,16 +199,8 @@ func TestSummarizeAgentOutput_ErrorHandling(t *testing.T) {
 func createTestSupervisor(t *testing.T) (*Supervisor, error) {
 	t.Helper()
 
-	// Use temp SQLite database for testing
-	tmpDB := t.TempDir() + "/test.db"
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: tmpDB})
-	if err != nil {
-		return nil, err
-	}
-	t.Cleanup(func() { _ = store.Close() })
-
 	cfg := &Config{
-		Store: store,
+		Store: memory.New(),
 		// API key from environment
 		Retry: DefaultRetryConfig(),
 	}
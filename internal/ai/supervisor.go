@@ -3,11 +3,14 @@ package ai
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/steveyegge/vc/internal/logging"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/tracing"
 	"github.com/steveyegge/vc/internal/types"
 	"golang.org/x/sync/semaphore"
 )
@@ -31,6 +34,9 @@ const (
 	ModelHaiku = "claude-3-5-haiku-20241022"
 )
 
+// providerAnthropic names the AI provider in log records
+const providerAnthropic = "anthropic"
+
 // GetDefaultModel returns the default model, checking VC_MODEL_DEFAULT env var first
 func GetDefaultModel() string {
 	if model := os.Getenv("VC_MODEL_DEFAULT"); model != "" {
@@ -54,6 +60,7 @@ func GetSimpleTaskModel() string {
 // - supervisor.go: Core struct and constructor (this file)
 // - retry.go: Circuit breaker and retry logic
 // - assessment.go: Pre-execution assessment and completion assessment
+// - assessment_cache.go: Reuse of assessments of unchanged issues
 // - analysis.go: Post-execution analysis
 // - recovery.go: Quality gate failure recovery strategies
 // - code_review.go: Code quality and test coverage analysis
@@ -69,6 +76,7 @@ type Supervisor struct {
 	circuitBreaker *CircuitBreaker
 	concurrencySem *semaphore.Weighted // Limits concurrent AI API calls (vc-220)
 	costTracker    CostTracker         // Tracks AI costs and enforces budgets (vc-e3s7)
+	assessments    assessmentCache     // Assessments of issues unchanged since they were made
 }
 
 // Compile-time check that Supervisor implements MissionPlanner
@@ -122,7 +130,7 @@ func NewSupervisor(cfg *Config) (*Supervisor, error) {
 		retry = DefaultRetryConfig()
 	}
 
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
+	client := anthropic.NewClient(option.WithAPIKey(apiKey), option.WithMiddleware(tracing.AnthropicMiddleware()))
 
 	// Initialize circuit breaker if enabled
 	var circuitBreaker *CircuitBreaker
@@ -132,15 +140,17 @@ func NewSupervisor(cfg *Config) (*Supervisor, error) {
 			retry.SuccessThreshold,
 			retry.OpenTimeout,
 		)
-		fmt.Printf("Circuit breaker initialized: threshold=%d failures, recovery=%d successes, timeout=%v\n",
-			retry.FailureThreshold, retry.SuccessThreshold, retry.OpenTimeout)
+		slog.Info("Circuit breaker initialized", logging.KeyProvider, providerAnthropic,
+			"failure_threshold", retry.FailureThreshold, "success_threshold", retry.SuccessThreshold,
+			"open_timeout", retry.OpenTimeout)
 	}
 
 	// Initialize concurrency limiter (vc-220)
 	var concurrencySem *semaphore.Weighted
 	if retry.MaxConcurrentCalls > 0 {
 		concurrencySem = semaphore.NewWeighted(int64(retry.MaxConcurrentCalls))
-		fmt.Printf("AI concurrency limiter initialized: max_concurrent=%d calls\n", retry.MaxConcurrentCalls)
+		slog.Info("AI concurrency limiter initialized", logging.KeyProvider, providerAnthropic,
+			"max_concurrent", retry.MaxConcurrentCalls)
 	}
 
 	return &Supervisor{
@@ -157,16 +167,19 @@ func NewSupervisor(cfg *Config) (*Supervisor, error) {
 // HealthCheck performs a pre-flight check of the supervisor's health
 // Returns an error if the circuit breaker is open or if there are API connectivity issues
 func (s *Supervisor) HealthCheck(ctx context.Context) error {
-	// Check circuit breaker state
+	// Check circuit breaker state. Allow moves an open circuit whose timeout
+	// has passed to half-open, so the check itself notices recovery even
+	// when nothing else is calling the API.
 	if s.circuitBreaker != nil {
-		state, failures, _ := s.circuitBreaker.GetMetrics()
-		switch state {
-		case CircuitOpen:
+		if err := s.circuitBreaker.Allow(); err != nil {
+			_, failures, _ := s.circuitBreaker.GetMetrics()
 			return fmt.Errorf("AI supervisor unavailable: %w (failures=%d, retry in %v)",
-				ErrCircuitOpen, failures, s.retry.OpenTimeout)
+				err, failures, s.retry.OpenTimeout)
+		}
+		switch s.circuitBreaker.GetState() {
 		case CircuitHalfOpen:
 			// Allow execution in half-open state (probing for recovery)
-			fmt.Printf("AI supervisor in half-open state (probing for recovery)\n")
+			slog.InfoContext(ctx, "AI supervisor in half-open state (probing for recovery)", logging.KeyProvider, providerAnthropic)
 		case CircuitClosed:
 			// Normal operation
 		}
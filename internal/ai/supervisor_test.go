@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"testing"
@@ -114,6 +115,12 @@ func (m *mockStorage) CloseIssue(ctx context.Context, id string, reason string,
 func (m *mockStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
 	return nil, nil
 }
+func (m *mockStorage) SearchIssuesPage(ctx context.Context, query string, filter types.IssueFilter, page types.PageRequest) (*types.IssuePage, error) {
+	return &types.IssuePage{}, nil
+}
+func (m *mockStorage) CloneIssue(ctx context.Context, id string, opts types.CloneOptions, actor string) (*types.CloneResult, error) {
+	return nil, nil
+}
 func (m *mockStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
 	return nil
 }
@@ -132,6 +139,12 @@ func (m *mockStorage) GetDependencyTree(ctx context.Context, issueID string, max
 func (m *mockStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
 	return nil, nil
 }
+func (m *mockStorage) GetBlockers(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return nil, nil
+}
+func (m *mockStorage) GetBlocked(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return nil, nil
+}
 func (m *mockStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
 	// vc-151: Actually store labels for testing
 	if m.labels[issueID] == nil {
@@ -150,6 +163,86 @@ func (m *mockStorage) GetLabels(ctx context.Context, issueID string) ([]string,
 func (m *mockStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
 	return nil, nil
 }
+
+func (m *mockStorage) ListLabelDefinitions(ctx context.Context) ([]*types.LabelDefinition, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) GetLabelDefinition(ctx context.Context, name string) (*types.LabelDefinition, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) SaveLabelDefinition(ctx context.Context, def *types.LabelDefinition) error {
+	return nil
+}
+
+func (m *mockStorage) RenameLabel(ctx context.Context, oldName, newName, actor string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) DeleteLabel(ctx context.Context, name, actor string) (int, error) {
+	return 0, nil
+}
+func (m *mockStorage) ListProjects(ctx context.Context) ([]*types.Project, error) {
+	return nil, nil
+}
+func (m *mockStorage) GetProject(ctx context.Context, name string) (*types.Project, error) {
+	return nil, nil
+}
+func (m *mockStorage) SaveProject(ctx context.Context, project *types.Project) error {
+	return nil
+}
+func (m *mockStorage) SetIssueProject(ctx context.Context, issueID, project, actor string) error {
+	return nil
+}
+func (m *mockStorage) ListCustomFields(ctx context.Context) ([]*types.CustomField, error) {
+	return nil, nil
+}
+func (m *mockStorage) SaveCustomField(ctx context.Context, field *types.CustomField) error {
+	return nil
+}
+func (m *mockStorage) DeleteCustomField(ctx context.Context, project, name string) error {
+	return nil
+}
+func (m *mockStorage) SetCustomFieldValue(ctx context.Context, issueID, name, value, actor string) error {
+	return nil
+}
+func (m *mockStorage) GetCustomFieldValues(ctx context.Context, issueID string) ([]*types.CustomFieldValue, error) {
+	return nil, nil
+}
+func (m *mockStorage) ListSavedFilters(ctx context.Context) ([]*types.SavedFilter, error) {
+	return nil, nil
+}
+func (m *mockStorage) GetSavedFilter(ctx context.Context, name string) (*types.SavedFilter, error) {
+	return nil, nil
+}
+func (m *mockStorage) SaveFilter(ctx context.Context, filter *types.SavedFilter) error {
+	return nil
+}
+func (m *mockStorage) DeleteFilter(ctx context.Context, name string) error {
+	return nil
+}
+func (m *mockStorage) ListIssuesByFilter(ctx context.Context, name string) ([]*types.Issue, error) {
+	return nil, nil
+}
+func (m *mockStorage) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	return nil, nil
+}
+func (m *mockStorage) GetSchedule(ctx context.Context, name string) (*types.Schedule, error) {
+	return nil, nil
+}
+func (m *mockStorage) SaveSchedule(ctx context.Context, schedule *types.Schedule) error {
+	return nil
+}
+func (m *mockStorage) DeleteSchedule(ctx context.Context, name string) error {
+	return nil
+}
+func (m *mockStorage) ClaimScheduleRun(ctx context.Context, name string, now, next time.Time) (bool, error) {
+	return false, nil
+}
+func (m *mockStorage) SetScheduleLastIssue(ctx context.Context, name, issueID string) error {
+	return nil
+}
 func (m *mockStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
 	return nil, nil
 }
@@ -168,6 +261,10 @@ func (m *mockStorage) GetReadyDependentsOfBlockedBaselines(ctx context.Context,
 func (m *mockStorage) IsEpicComplete(ctx context.Context, epicID string) (bool, error) {
 	return false, nil
 }
+
+func (m *mockStorage) GetEpicRollup(ctx context.Context, epicID string) (*types.EpicRollup, error) {
+	return nil, nil
+}
 func (m *mockStorage) GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error) {
 	return nil, errors.New("not implemented in mock")
 }
@@ -177,6 +274,27 @@ func (m *mockStorage) GetMissionsNeedingGates(ctx context.Context) ([]*types.Iss
 func (m *mockStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
 	return nil, nil
 }
+func (m *mockStorage) GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error) {
+	return &types.EventPage{}, nil
+}
+func (m *mockStorage) GetAuditLog(ctx context.Context, issueID string, limit int) ([]*types.AuditEntry, error) {
+	return nil, nil
+}
+func (m *mockStorage) GetComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	return nil, nil
+}
+func (m *mockStorage) ReplyToComment(ctx context.Context, parentID int64, actor, body string) (*types.Comment, error) {
+	return nil, nil
+}
+func (m *mockStorage) EditComment(ctx context.Context, commentID int64, actor, body string) error {
+	return nil
+}
+func (m *mockStorage) AddReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	return nil
+}
+func (m *mockStorage) RemoveReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	return nil
+}
 func (m *mockStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
 	return nil, nil
 }
@@ -204,6 +322,12 @@ func (m *mockStorage) DeleteOldStoppedInstances(ctx context.Context, olderThanSe
 func (m *mockStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
 	return nil
 }
+func (m *mockStorage) ClaimNextReadyIssue(ctx context.Context, workerID string, lease time.Duration, project string) (*types.Issue, error) {
+	return nil, nil
+}
+func (m *mockStorage) RenewClaimLease(ctx context.Context, issueID, workerID string, lease time.Duration) error {
+	return nil
+}
 func (m *mockStorage) GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error) {
 	return nil, nil
 }
@@ -245,6 +369,57 @@ func (m *mockStorage) GetExecutionHistoryPaginated(ctx context.Context, issueID
 func (m *mockStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
 	return nil
 }
+func (m *mockStorage) CreateExecution(ctx context.Context, execution *types.Execution) error {
+	return nil
+}
+func (m *mockStorage) UpdateExecution(ctx context.Context, execution *types.Execution) error {
+	return nil
+}
+func (m *mockStorage) GetExecution(ctx context.Context, id int64) (*types.Execution, error) {
+	return nil, nil
+}
+func (m *mockStorage) ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error) {
+	return nil, nil
+}
+func (m *mockStorage) DeleteExecution(ctx context.Context, id int64) error {
+	return nil
+}
+func (m *mockStorage) RecordAIUsage(ctx context.Context, usage *types.AIUsage) error {
+	return nil
+}
+func (m *mockStorage) ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error) {
+	return nil, nil
+}
+func (m *mockStorage) AppendExecutionOutput(ctx context.Context, lines []*types.OutputLine) error {
+	return nil
+}
+func (m *mockStorage) ListExecutionOutput(ctx context.Context, filter types.OutputFilter) ([]*types.OutputLine, error) {
+	return nil, nil
+}
+func (m *mockStorage) CleanupExecutionOutput(ctx context.Context, retentionDays int) (int, error) {
+	return 0, nil
+}
+func (m *mockStorage) AddAttachment(ctx context.Context, attachment *types.Attachment, data []byte) error {
+	return nil
+}
+func (m *mockStorage) GetAttachment(ctx context.Context, id int64) (*types.Attachment, []byte, error) {
+	return nil, nil, nil
+}
+func (m *mockStorage) ListAttachments(ctx context.Context, issueID string) ([]*types.Attachment, error) {
+	return nil, nil
+}
+func (m *mockStorage) DeleteAttachment(ctx context.Context, id int64) error {
+	return nil
+}
+func (m *mockStorage) GetIdempotencyKey(ctx context.Context, key string, since time.Time) (string, bool, error) {
+	return "", false, nil
+}
+func (m *mockStorage) RecordIdempotencyKey(ctx context.Context, key, result string) error {
+	return nil
+}
+func (m *mockStorage) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
 
 // Agent Events methods
 func (m *mockStorage) StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error {
@@ -281,6 +456,9 @@ func (m *mockStorage) RecordWatchdogIntervention(ctx context.Context, issueID st
 func (m *mockStorage) CleanupEventsByGlobalLimit(ctx context.Context, globalLimit int, batchSize int) (int, error) {
 	return 0, nil
 }
+func (m *mockStorage) CompactIssueEvents(ctx context.Context, retentionDays, batchSize int) (int, error) {
+	return 0, nil
+}
 
 func (m *mockStorage) CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit int, batchSize int) (int, error) {
 	return 0, nil
@@ -293,6 +471,23 @@ func (m *mockStorage) GetEventCounts(ctx context.Context) (*types.EventCounts, e
 func (m *mockStorage) VacuumDatabase(ctx context.Context) error {
 	return nil
 }
+func (m *mockStorage) AnalyzeDatabase(ctx context.Context) error {
+	return nil
+}
+func (m *mockStorage) Export(ctx context.Context, w io.Writer) error {
+	return nil
+}
+func (m *mockStorage) Import(ctx context.Context, r io.Reader) (*types.ImportStats, error) {
+	return &types.ImportStats{}, nil
+}
+
+func (m *mockStorage) ImportEvents(ctx context.Context, events []*types.Event) (int, error) {
+	return 0, nil
+}
+
+func (m *mockStorage) ArchiveIssues(ctx context.Context, opts types.ArchiveOptions, w io.Writer) (*types.ArchiveStats, error) {
+	return &types.ArchiveStats{}, nil
+}
 
 // Baseline Diagnostics methods (vc-9aa9)
 func (m *mockStorage) StoreDiagnosis(ctx context.Context, issueID string, diagnosis *TestFailureDiagnosis) error {
@@ -333,6 +528,9 @@ func (m *mockStorage) DeletePlan(ctx context.Context, missionID string) error {
 func (m *mockStorage) ListDraftPlans(ctx context.Context) ([]*types.MissionPlan, error) {
 	return nil, nil
 }
+func (m *mockStorage) Watch(ctx context.Context, filter types.WatchFilter) (<-chan types.ChangeEvent, error) {
+	return nil, nil
+}
 
 func (m *mockStorage) RunInVCTransaction(ctx context.Context, fn func(tx *storage.VCTransaction) error) error {
 	return nil // Mock does not support transactions
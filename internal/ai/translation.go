@@ -39,6 +39,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/steveyegge/vc/internal/idempotency"
 	"github.com/steveyegge/vc/internal/priorities"
 	"github.com/steveyegge/vc/internal/types"
 )
@@ -259,9 +260,14 @@ func (s *Supervisor) verifyMetaIssueStillNeeded(ctx context.Context, parentIssue
 //
 // 5. Circuit Breaker (vc-4vot): If >5 blockers discovered at once, create a single
 //    escalation issue instead. This catches systemic problems and runaway recursion.
+//
+// Issues are keyed on the parent and their title (see internal/idempotency), so
+// a handler re-run after a crash gets back the IDs of the issues its earlier
+// run filed, as long as they are still open, instead of filing them again.
 func (s *Supervisor) CreateDiscoveredIssues(ctx context.Context, parentIssue *types.Issue, discovered []DiscoveredIssue) ([]string, error) {
 	var createdIDs []string
 	var skipped []string
+	filed := idempotency.NewScope(s.store, "discovered", parentIssue.ID)
 
 	// vc-4vot: Circuit breaker - if more than maxBlockersBeforeEscalation discovered blockers, something is wrong
 	blockerCount := 0
@@ -272,6 +278,11 @@ func (s *Supervisor) CreateDiscoveredIssues(ctx context.Context, parentIssue *ty
 	}
 
 	if blockerCount > maxBlockersBeforeEscalation {
+		if id, ok := s.filedOpenIssue(ctx, filed, "escalation"); ok {
+			fmt.Printf("Escalation issue %s was already filed for %s\n", id, parentIssue.ID)
+			return []string{id}, nil
+		}
+
 		fmt.Fprintf(os.Stderr, "⚠️  WARNING: Excessive blocker discovery detected (%d blockers)\n", blockerCount)
 		fmt.Fprintf(os.Stderr, "   This may indicate infinite recursion. Escalating to human review.\n")
 
@@ -288,6 +299,7 @@ func (s *Supervisor) CreateDiscoveredIssues(ctx context.Context, parentIssue *ty
 		if err := s.store.CreateIssue(ctx, escalationIssue, "ai-supervisor"); err != nil {
 			return nil, fmt.Errorf("failed to create escalation issue: %w", err)
 		}
+		filed.Record(ctx, "escalation", escalationIssue.ID)
 
 		if err := s.store.AddLabel(ctx, escalationIssue.ID, "escalated", "ai-supervisor"); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to add escalated label: %v\n", err)
@@ -359,6 +371,13 @@ func (s *Supervisor) CreateDiscoveredIssues(ctx context.Context, parentIssue *ty
 			}
 		}
 
+		effect := "issue:" + idempotency.Hash(disc.Title)
+		if id, ok := s.filedOpenIssue(ctx, filed, effect); ok {
+			createdIDs = append(createdIDs, id)
+			fmt.Printf("Discovered issue %s was already filed: %s\n", id, disc.Title)
+			continue
+		}
+
 		// Calculate priority based on discovery type and parent priority (vc-152)
 		// This overrides the AI-suggested priority string (disc.Priority) for blockers/related/background
 		// The AI's priority suggestion is stored but not used (may be useful for future enhancements)
@@ -401,6 +420,7 @@ func (s *Supervisor) CreateDiscoveredIssues(ctx context.Context, parentIssue *ty
 
 		// The ID is set on the issue by CreateIssue
 		id := newIssue.ID
+		filed.Record(ctx, effect, id)
 
 		createdIDs = append(createdIDs, id)
 		fmt.Printf("Created discovered issue %s: %s\n", id, disc.Title)
@@ -454,6 +474,21 @@ func (s *Supervisor) CreateDiscoveredIssues(ctx context.Context, parentIssue *ty
 	return createdIDs, nil
 }
 
+// filedOpenIssue returns the issue recorded for effect in scope, if one was
+// filed and is still open. A closed issue means its work was done, so any
+// recurrence is filed afresh.
+func (s *Supervisor) filedOpenIssue(ctx context.Context, scope *idempotency.Scope, effect string) (string, bool) {
+	id, ok := scope.Lookup(ctx, effect)
+	if !ok {
+		return "", false
+	}
+	issue, err := s.store.GetIssue(ctx, id)
+	if err != nil || issue == nil || issue.Status == types.StatusClosed {
+		return "", false
+	}
+	return id, true
+}
+
 // formatDiscoveredIssues formats a list of discovered issues for display
 func formatDiscoveredIssues(issues []DiscoveredIssue) string {
 	var sb strings.Builder
@@ -4,7 +4,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/memory"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -13,13 +13,7 @@ func TestMetaIssueRecursionPrevention(t *testing.T) {
 	ctx := context.Background()
 
 	// Create in-memory storage
-	cfg := storage.DefaultConfig()
-	cfg.Path = ":memory:"
-	store, err := storage.NewStorage(ctx, cfg)
-	if err != nil {
-		t.Fatalf("failed to create storage: %v", err)
-	}
-	defer store.Close()
+	store := memory.New()
 
 	// Create supervisor with storage
 	supervisor := &Supervisor{
@@ -416,3 +410,50 @@ func TestMetaIssueRecursionPrevention(t *testing.T) {
 		}
 	})
 }
+
+// TestCreateDiscoveredIssuesRerun verifies that a re-run for the same parent,
+// e.g. after a crash, returns the issues already filed instead of filing them
+// again, unless they have since been closed
+func TestCreateDiscoveredIssuesRerun(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	supervisor := &Supervisor{store: store}
+
+	parentIssue := &types.Issue{
+		Title:              "Implement feature Z",
+		IssueType:          types.TypeTask,
+		Status:             types.StatusOpen,
+		Priority:           2,
+		AcceptanceCriteria: "1. Feature works",
+	}
+	if err := store.CreateIssue(ctx, parentIssue, "test"); err != nil {
+		t.Fatalf("failed to create parent issue: %v", err)
+	}
+	discoveredIssues := []DiscoveredIssue{
+		{Title: "Fix flaky test", Description: "It flakes", Type: "bug", DiscoveryType: "related"},
+		{Title: "Document feature Z", Description: "Needs docs", Type: "task", DiscoveryType: "background"},
+	}
+
+	first, err := supervisor.CreateDiscoveredIssues(ctx, parentIssue, discoveredIssues)
+	if err != nil || len(first) != 2 {
+		t.Fatalf("first run created %v (err %v), want 2 issues", first, err)
+	}
+	second, err := supervisor.CreateDiscoveredIssues(ctx, parentIssue, discoveredIssues)
+	if err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if len(second) != 2 || second[0] != first[0] || second[1] != first[1] {
+		t.Errorf("second run returned %v, want the issues already filed %v", second, first)
+	}
+
+	if err := store.CloseIssue(ctx, first[0], "fixed", "test"); err != nil {
+		t.Fatalf("failed to close issue: %v", err)
+	}
+	third, err := supervisor.CreateDiscoveredIssues(ctx, parentIssue, discoveredIssues)
+	if err != nil {
+		t.Fatalf("third run failed: %v", err)
+	}
+	if len(third) != 2 || third[0] == first[0] || third[1] != first[1] {
+		t.Errorf("third run returned %v, want a new issue for the closed %s and %s reused", third, first[0], first[1])
+	}
+}
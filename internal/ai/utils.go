@@ -3,12 +3,13 @@ package ai
 import (
 	"context"
 	"fmt"
-	"os"
+	"log/slog"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/steveyegge/vc/internal/logging"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -34,7 +35,7 @@ func (s *Supervisor) recordAIUsage(ctx context.Context, issueID, activity string
 		// Record aggregated usage
 		if _, err := s.costTracker.RecordUsage(ctx, issueID, inputTokens, outputTokens); err != nil {
 			// Log warning but don't fail (cost tracking is best-effort)
-			fmt.Fprintf(os.Stderr, "Warning: failed to record AI cost: %v\n", err)
+			slog.WarnContext(ctx, "Failed to record AI cost", logging.KeyIssueID, issueID, logging.KeyOperation, activity, "error", err)
 		}
 
 		// Record operation-level details for quota monitoring (vc-7e21)
@@ -49,7 +50,7 @@ func (s *Supervisor) recordAIUsage(ctx context.Context, issueID, activity string
 		}
 		if err := s.costTracker.RecordOperation(ctx, op); err != nil {
 			// Log warning but don't fail (operation tracking is best-effort)
-			fmt.Fprintf(os.Stderr, "Warning: failed to record quota operation: %v\n", err)
+			slog.WarnContext(ctx, "Failed to record quota operation", logging.KeyIssueID, issueID, logging.KeyOperation, activity, "error", err)
 		}
 	}
 
@@ -121,8 +122,9 @@ func (s *Supervisor) CallAI(ctx context.Context, prompt string, operation string
 
 	// Log the call (vc-35: include model for cost tracking)
 	duration := time.Since(startTime)
-	fmt.Printf("AI %s call: input=%d tokens, output=%d tokens, duration=%v, model=%s\n",
-		operation, response.Usage.InputTokens, response.Usage.OutputTokens, duration, model)
+	slog.InfoContext(ctx, "AI call completed",
+		logging.KeyOperation, operation, logging.KeyProvider, providerAnthropic, logging.KeyModel, model,
+		"input_tokens", response.Usage.InputTokens, "output_tokens", response.Usage.OutputTokens, "duration", duration)
 
 	return responseText, nil
 }
@@ -185,12 +187,13 @@ func (s *Supervisor) SummarizeAgentOutput(ctx context.Context, issue *types.Issu
 
 	// Log the summarization
 	duration := time.Since(startTime)
-	fmt.Printf("AI Summarization: input=%d chars, output=%d chars, duration=%v\n",
-		len(fullOutput), len(summaryText), duration)
+	slog.InfoContext(ctx, "AI summarization completed",
+		logging.KeyIssueID, issue.ID, logging.KeyOperation, "summarization", logging.KeyProvider, providerAnthropic,
+		logging.KeyModel, s.model, "input_chars", len(fullOutput), "output_chars", len(summaryText), "duration", duration)
 
 	// Log AI usage to events
 	if err := s.recordAIUsage(ctx, issue.ID, "summarization", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
+		slog.WarnContext(ctx, "Failed to log AI usage", logging.KeyIssueID, issue.ID, logging.KeyOperation, "summarization", "error", err)
 	}
 
 	return summaryText, nil
@@ -242,6 +245,132 @@ Focus on information that would be useful to someone reviewing this work later.
 		maxLength)
 }
 
+// SummarizeDigest uses AI to write the overview at the top of the daily
+// email digest from a plain-text report of the day's executions, costs and
+// discovered issues. The report itself is sent along with the summary, so
+// the summary should highlight what needs attention rather than repeat it.
+func (s *Supervisor) SummarizeDigest(ctx context.Context, report string, maxLength int) (string, error) {
+	startTime := time.Now()
+
+	if err := s.checkBudget(""); err != nil {
+		return "", err
+	}
+
+	prompt := fmt.Sprintf(`You are writing the overview for a daily email digest about an AI coding system that works through an issue tracker. Below is the factual report for the period; it is included in the email after your overview.
+
+Report:
+%s
+
+Write a concise overview (max %d characters) as plain text that:
+1. States how productive the period was (work completed vs. failed)
+2. Calls out anything a human should look at: repeated failures, unusually expensive issues, escalations, blocked work
+3. Notes themes in the newly discovered issues, if any
+
+Be specific and refer to issue IDs. Don't repeat the full report or add greetings.`,
+		safeTruncateString(report, 50000), maxLength)
+
+	var response *anthropic.Message
+	err := s.retryWithBackoff(ctx, "digest-summarization", func(attemptCtx context.Context) error {
+		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
+			Model:     anthropic.Model(s.model),
+			MaxTokens: 2048,
+			Messages: []anthropic.MessageParam{
+				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+			},
+		})
+		if apiErr != nil {
+			return apiErr
+		}
+		response = resp
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("AI digest summarization failed after %d retry attempts: %w", s.retry.MaxRetries+1, err)
+	}
+
+	var summary strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			summary.WriteString(block.Text)
+		}
+	}
+	summaryText := summary.String()
+
+	duration := time.Since(startTime)
+	slog.InfoContext(ctx, "AI digest summarization completed",
+		logging.KeyOperation, "digest-summarization", logging.KeyProvider, providerAnthropic,
+		logging.KeyModel, s.model, "input_chars", len(report), "output_chars", len(summaryText), "duration", duration)
+
+	if err := s.recordAIUsage(ctx, "", "digest-summarization", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+		slog.WarnContext(ctx, "Failed to log AI usage", logging.KeyOperation, "digest-summarization", "error", err)
+	}
+
+	return summaryText, nil
+}
+
+// SummarizeIncident uses AI to describe a page about critical work that
+// VC can't finish on its own: what the issue is, what went wrong and what
+// the responder should look at first. The report is the issue and its
+// recent executions; the summary is sent in place of reading it.
+func (s *Supervisor) SummarizeIncident(ctx context.Context, report string, maxLength int) (string, error) {
+	startTime := time.Now()
+
+	if err := s.checkBudget(""); err != nil {
+		return "", err
+	}
+
+	prompt := fmt.Sprintf(`You are writing a page for the on-call engineer about an AI coding system that is stuck on critical (P0) work. Below is the factual report: the issue, why it is paging, and its recent execution attempts.
+
+Report:
+%s
+
+Write a concise summary (max %d characters) as plain text that:
+1. States what the issue is about and why it needs a human now
+2. Explains what the recent attempts tried and how they failed, citing specific errors
+3. Suggests the first thing the responder should check
+
+Be specific and refer to issue IDs. Don't add greetings or repeat the report verbatim.`,
+		safeTruncateString(report, 30000), maxLength)
+
+	var response *anthropic.Message
+	err := s.retryWithBackoff(ctx, "incident-summarization", func(attemptCtx context.Context) error {
+		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
+			Model:     anthropic.Model(s.model),
+			MaxTokens: 1024,
+			Messages: []anthropic.MessageParam{
+				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+			},
+		})
+		if apiErr != nil {
+			return apiErr
+		}
+		response = resp
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("AI incident summarization failed after %d retry attempts: %w", s.retry.MaxRetries+1, err)
+	}
+
+	var summary strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			summary.WriteString(block.Text)
+		}
+	}
+	summaryText := summary.String()
+
+	duration := time.Since(startTime)
+	slog.InfoContext(ctx, "AI incident summarization completed",
+		logging.KeyOperation, "incident-summarization", logging.KeyProvider, providerAnthropic,
+		logging.KeyModel, s.model, "input_chars", len(report), "output_chars", len(summaryText), "duration", duration)
+
+	if err := s.recordAIUsage(ctx, "", "incident-summarization", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+		slog.WarnContext(ctx, "Failed to log AI usage", logging.KeyOperation, "incident-summarization", "error", err)
+	}
+
+	return summaryText, nil
+}
+
 // join concatenates a slice of strings with a separator
 func join(strs []string, sep string) string {
 	if len(strs) == 0 {
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webFS holds the dashboard: a static page that polls the API
+//
+//go:embed web
+var webFS embed.FS
+
+// dashboard serves the web dashboard. Its files are public; the page asks
+// for an API token and sends it with each API request.
+func dashboard() http.Handler {
+	root, err := fs.Sub(webFS, "web")
+	if err != nil {
+		panic(err) // The embedded directory always exists
+	}
+	files := http.FileServerFS(root)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+}
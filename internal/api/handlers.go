@@ -0,0 +1,868 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/cost"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// handleHealth answers GET /api/v1/health, without authentication
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleListIssues answers GET /api/v1/issues with a page of issues.
+//
+// Query parameters: q (text search), status, type, priority, assignee,
+// label (repeatable, all must match), project, and the paging parameters
+// cursor, limit, order (asc or desc), order_by (created_at or updated_at)
+// and since.
+func (s *Server) handleListIssues(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := types.IssueFilter{Labels: query["label"], Project: query.Get("project")}
+	if value := query.Get("status"); value != "" {
+		status := types.Status(value)
+		if !status.IsValid() {
+			writeError(w, http.StatusBadRequest, badRequest("invalid status: %q", value))
+			return
+		}
+		filter.Status = &status
+	}
+	if value := query.Get("type"); value != "" {
+		issueType := types.IssueType(value)
+		if !issueType.IsValid() {
+			writeError(w, http.StatusBadRequest, badRequest("invalid type: %q", value))
+			return
+		}
+		filter.IssueType = &issueType
+	}
+	if value := query.Get("priority"); value != "" {
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, badRequest("invalid priority: %q", value))
+			return
+		}
+		filter.Priority = &priority
+	}
+	if value := query.Get("assignee"); value != "" {
+		filter.Assignee = &value
+	}
+
+	page, err := pageRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	issues, err := s.store.SearchIssuesPage(r.Context(), query.Get("q"), filter, page)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, issues)
+}
+
+// issueDetail is an issue with its labels and dependencies
+type issueDetail struct {
+	*types.Issue
+	Labels       []string            `json:"labels"`
+	Dependencies []*types.Dependency `json:"dependencies"`
+}
+
+// handleGetIssue answers GET /api/v1/issues/{id}
+func (s *Server) handleGetIssue(w http.ResponseWriter, r *http.Request) {
+	detail, err := s.issueDetail(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// issueDetail loads an issue with its labels and dependencies
+func (s *Server) issueDetail(ctx context.Context, id string) (*issueDetail, error) {
+	issue, err := s.getIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.store.GetLabels(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.store.GetDependencyRecords(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	if deps == nil {
+		deps = []*types.Dependency{}
+	}
+	return &issueDetail{Issue: issue, Labels: labels, Dependencies: deps}, nil
+}
+
+// getIssue loads an issue, failing with errNotFound if it doesn't exist
+func (s *Server) getIssue(ctx context.Context, id string) (*types.Issue, error) {
+	issue, err := s.store.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s: %w", id, errNotFound)
+	}
+	return issue, nil
+}
+
+// handleReady answers GET /api/v1/ready with the ready queue: open,
+// unblocked issues in the order the executor claims them.
+//
+// Query parameters: project and limit (default 50).
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", 50)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	issues, err := s.store.GetReadyWork(r.Context(), types.WorkFilter{
+		Status:     types.StatusOpen,
+		Limit:      limit,
+		Project:    r.URL.Query().Get("project"),
+		SortPolicy: types.SortPolicyPriority,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"issues": issues})
+}
+
+// maxMissionDepth bounds the mission tree, in case of parent-child cycles
+const maxMissionDepth = 5
+
+// missionNode is an issue in the mission tree, with its progress if it has
+// children
+type missionNode struct {
+	*types.Issue
+	Rollup   *types.EpicRollup `json:"rollup,omitempty"`
+	Children []*missionNode    `json:"children"`
+}
+
+// handleListMissions answers GET /api/v1/missions with each unclosed
+// mission and its tree of child issues. Pass closed=true to include closed
+// missions.
+func (s *Server) handleListMissions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	includeClosed := r.URL.Query().Get("closed") == "true"
+	epic := types.TypeEpic
+	epics, err := s.store.SearchIssues(ctx, "", types.IssueFilter{IssueType: &epic})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	missions := []*missionNode{}
+	for _, issue := range epics {
+		if issue.IssueSubtype != types.SubtypeMission || (issue.Status == types.StatusClosed && !includeClosed) {
+			continue
+		}
+		node, err := s.missionTree(ctx, issue, 0)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		missions = append(missions, node)
+	}
+	sort.Slice(missions, func(i, j int) bool {
+		if missions[i].Priority != missions[j].Priority {
+			return missions[i].Priority < missions[j].Priority
+		}
+		return missions[i].ID < missions[j].ID
+	})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"missions": missions})
+}
+
+// missionTree loads an issue's parent-child subtree
+func (s *Server) missionTree(ctx context.Context, issue *types.Issue, depth int) (*missionNode, error) {
+	node := &missionNode{Issue: issue, Children: []*missionNode{}}
+	if depth >= maxMissionDepth {
+		return node, nil
+	}
+	dependents, err := s.store.GetDependents(ctx, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dependent := range dependents {
+		// Dependents include issues the parent blocks; keep only children
+		deps, err := s.store.GetDependencyRecords(ctx, dependent.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			if dep.Type != types.DepParentChild || dep.DependsOnID != issue.ID {
+				continue
+			}
+			child, err := s.missionTree(ctx, dependent, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+			break
+		}
+	}
+	if len(node.Children) > 0 {
+		if node.Rollup, err = s.store.GetEpicRollup(ctx, issue.ID); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// handleMissionProgress reports spend and progress per mission: unclosed
+// ones, or all with closed=true
+func (s *Server) handleMissionProgress(w http.ResponseWriter, r *http.Request) {
+	includeClosed := r.URL.Query().Get("closed") == "true"
+	reports, err := cost.LoadMissionReports(r.Context(), s.store, nil, includeClosed)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"missions": reports})
+}
+
+// handleGetMissionProgress reports one mission's spend and progress
+func (s *Server) handleGetMissionProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+	issue, err := s.getIssue(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if issue.IssueType != types.TypeEpic || issue.IssueSubtype != types.SubtypeMission {
+		writeError(w, http.StatusNotFound, fmt.Errorf("mission %s: %w", id, errNotFound))
+		return
+	}
+	reports, err := cost.LoadMissionReports(ctx, s.store, []string{id}, true)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reports[0])
+}
+
+// createIssueRequest is the body of POST /api/v1/issues
+type createIssueRequest struct {
+	Title              string          `json:"title"`
+	Description        string          `json:"description"`
+	Design             string          `json:"design"`
+	AcceptanceCriteria string          `json:"acceptance_criteria"`
+	Notes              string          `json:"notes"`
+	Priority           *int            `json:"priority"`
+	IssueType          types.IssueType `json:"issue_type"`
+	Assignee           string          `json:"assignee"`
+	EstimatedMinutes   *int            `json:"estimated_minutes"`
+	Labels             []string        `json:"labels"`
+}
+
+// handleCreateIssue answers POST /api/v1/issues with the created issue.
+// New issues are open, priority 2 tasks unless the body says otherwise.
+func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
+	var req createIssueRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	issue := &types.Issue{
+		Title:              req.Title,
+		Description:        req.Description,
+		Design:             req.Design,
+		AcceptanceCriteria: req.AcceptanceCriteria,
+		Notes:              req.Notes,
+		Status:             types.StatusOpen,
+		Priority:           2,
+		IssueType:          req.IssueType,
+		Assignee:           req.Assignee,
+		EstimatedMinutes:   req.EstimatedMinutes,
+	}
+	if req.Priority != nil {
+		issue.Priority = *req.Priority
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = types.TypeTask
+	}
+	if err := issue.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, badRequest("%v", err))
+		return
+	}
+
+	ctx := r.Context()
+	if err := s.store.CreateIssue(ctx, issue, actor(r)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, label := range req.Labels {
+		if err := s.store.AddLabel(ctx, issue.ID, label, actor(r)); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("created %s but failed to add label %s: %w", issue.ID, label, err))
+			return
+		}
+	}
+	detail, err := s.issueDetail(ctx, issue.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, detail)
+}
+
+// updateIssueRequest is the body of PATCH /api/v1/issues/{id}; only the
+// fields present are changed
+type updateIssueRequest struct {
+	Title              *string       `json:"title"`
+	Description        *string       `json:"description"`
+	Design             *string       `json:"design"`
+	AcceptanceCriteria *string       `json:"acceptance_criteria"`
+	Notes              *string       `json:"notes"`
+	Status             *types.Status `json:"status"`
+	Priority           *int          `json:"priority"`
+	Assignee           *string       `json:"assignee"`
+	EstimatedMinutes   *int          `json:"estimated_minutes"`
+}
+
+// handleUpdateIssue answers PATCH /api/v1/issues/{id} with the updated issue.
+// Issues are closed with POST /api/v1/issues/{id}/close, so a reason is
+// recorded.
+func (s *Server) handleUpdateIssue(w http.ResponseWriter, r *http.Request) {
+	var req updateIssueRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ctx := r.Context()
+	id := r.PathValue("id")
+	issue, err := s.getIssue(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Validate the result of the update before applying it
+	updated := *issue
+	updates := map[string]interface{}{}
+	setString := func(field string, value *string, dest *string) {
+		if value != nil {
+			updates[field] = *value
+			*dest = *value
+		}
+	}
+	setString("title", req.Title, &updated.Title)
+	setString("description", req.Description, &updated.Description)
+	setString("design", req.Design, &updated.Design)
+	setString("acceptance_criteria", req.AcceptanceCriteria, &updated.AcceptanceCriteria)
+	setString("notes", req.Notes, &updated.Notes)
+	setString("assignee", req.Assignee, &updated.Assignee)
+	if req.Status != nil {
+		if *req.Status == types.StatusClosed {
+			writeError(w, http.StatusBadRequest, badRequest("close issues with POST /api/v1/issues/%s/close", id))
+			return
+		}
+		updates["status"] = string(*req.Status)
+		updated.Status = *req.Status
+	}
+	if req.Priority != nil {
+		updates["priority"] = *req.Priority
+		updated.Priority = *req.Priority
+	}
+	if req.EstimatedMinutes != nil {
+		updates["estimated_minutes"] = *req.EstimatedMinutes
+		updated.EstimatedMinutes = req.EstimatedMinutes
+	}
+	if len(updates) == 0 {
+		writeError(w, http.StatusBadRequest, badRequest("nothing to update"))
+		return
+	}
+	if err := updated.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, badRequest("%v", err))
+		return
+	}
+
+	if req.Status != nil {
+		s.store.LogStatusChangeFromUpdates(ctx, id, updates, actor(r), "updated via API")
+	}
+	if err := s.store.UpdateIssue(ctx, id, updates, actor(r)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	detail, err := s.issueDetail(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// handleCloseIssue answers POST /api/v1/issues/{id}/close, whose body may
+// give a {"reason": "..."}
+func (s *Server) handleCloseIssue(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := decodeBody(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if req.Reason == "" {
+		req.Reason = "Closed via API"
+	}
+	ctx := r.Context()
+	id := r.PathValue("id")
+	if _, err := s.getIssue(ctx, id); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.store.CloseIssue(ctx, id, req.Reason, actor(r)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	detail, err := s.issueDetail(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// handleAddComment answers POST /api/v1/issues/{id}/comments, whose body is
+// {"body": "..."}, with the issue's comments
+func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Body string `json:"body"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		writeError(w, http.StatusBadRequest, badRequest("comment body is required"))
+		return
+	}
+	ctx := r.Context()
+	id := r.PathValue("id")
+	if _, err := s.getIssue(ctx, id); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := s.store.AddComment(ctx, id, actor(r), req.Body); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	comments, err := s.store.GetComments(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"comments": comments})
+}
+
+// handleListEvents answers GET /api/v1/events and
+// GET /api/v1/issues/{id}/events with a page of issue events (comments,
+// status changes and other audit entries). Takes the paging parameters
+// cursor, limit, order and since.
+func (s *Server) handleListEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+	if id != "" {
+		if _, err := s.getIssue(ctx, id); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	page, err := pageRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	result, err := s.store.GetEventsPage(ctx, id, page)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleListAgentEvents answers GET /api/v1/agent-events with the newest
+// structured agent and executor events.
+//
+// Query parameters: issue, type, severity, since, until and limit
+// (default 100).
+func (s *Server) handleListAgentEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := events.EventFilter{
+		IssueID:  query.Get("issue"),
+		Type:     events.EventType(query.Get("type")),
+		Severity: events.EventSeverity(query.Get("severity")),
+	}
+	var err error
+	if filter.AfterTime, err = queryTime(r, "since"); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if filter.BeforeTime, err = queryTime(r, "until"); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if filter.Limit, err = queryInt(r, "limit", 100); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	agentEvents, err := s.store.GetAgentEvents(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if agentEvents == nil {
+		agentEvents = []*events.AgentEvent{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"events": agentEvents})
+}
+
+// handleListExecutions answers GET /api/v1/executions with executions,
+// newest first.
+//
+// Query parameters: issue, status, since and limit (default 50, 0 = all).
+func (s *Server) handleListExecutions(w http.ResponseWriter, r *http.Request) {
+	filter, err := executionFilter(r, 50)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	executions, err := s.store.ListExecutions(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if executions == nil {
+		executions = []*types.Execution{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"executions": executions})
+}
+
+// handleGetExecution answers GET /api/v1/executions/{id}
+func (s *Server) handleGetExecution(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, badRequest("invalid execution ID: %q", r.PathValue("id")))
+		return
+	}
+	execution, err := s.store.GetExecution(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if execution == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("execution %d: %w", id, errNotFound))
+		return
+	}
+	writeJSON(w, http.StatusOK, execution)
+}
+
+// approval is an approved gate override
+type approval struct {
+	Gate        gates.GateType `json:"gate"`
+	RequestedBy string         `json:"requested_by,omitempty"`
+	ApprovedBy  string         `json:"approved_by"`
+	ApprovedAt  time.Time      `json:"approved_at"`
+}
+
+// pendingApproval is a requested gate override awaiting approval
+type pendingApproval struct {
+	Gate   gates.GateType `json:"gate"`
+	Reason string         `json:"reason"`
+}
+
+// approvals is the body of the approvals routes
+type approvals struct {
+	Approved []approval        `json:"approved"`
+	Pending  []pendingApproval `json:"pending"`
+}
+
+// handleListApprovals answers GET /api/v1/issues/{id}/approvals with the
+// issue's gate overrides: those approved and those awaiting approval
+func (s *Server) handleListApprovals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+	if _, err := s.getIssue(ctx, id); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	result, _, err := s.approvals(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleApprove answers POST /api/v1/issues/{id}/approvals/{gate}, approving
+// a requested override of the gate as the token's actor
+func (s *Server) handleApprove(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := r.PathValue("id")
+	gate := gates.GateType(r.PathValue("gate"))
+	if _, err := s.getIssue(ctx, id); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	_, pending, err := s.approvals(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !pending[gate] {
+		writeError(w, http.StatusConflict, fmt.Errorf("no pending override of gate %s on %s (request one with the %s%s label)",
+			gate, id, gates.OverrideLabelPrefix, gate))
+		return
+	}
+	if err := gates.ApproveOverride(ctx, s.store, id, gate, actor(r)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	result, _, err := s.approvals(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// inboxItem is a gate override awaiting approval
+type inboxItem struct {
+	IssueID  string         `json:"issue_id"`
+	Title    string         `json:"title"`
+	Status   types.Status   `json:"status"`
+	Priority int            `json:"priority"`
+	Gate     gates.GateType `json:"gate"`
+	Reason   string         `json:"reason"`
+}
+
+// handleApprovalInbox answers GET /api/v1/approvals with the gate overrides
+// of unclosed issues that await approval, most urgent first
+func (s *Server) handleApprovalInbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	labels, err := s.store.ListLabelDefinitions(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	requested := map[string]*types.Issue{}
+	for _, label := range labels {
+		if !strings.HasPrefix(label.Name, gates.OverrideLabelPrefix) {
+			continue
+		}
+		issues, err := s.store.GetIssuesByLabel(ctx, label.Name)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		for _, issue := range issues {
+			if issue.Status != types.StatusClosed {
+				requested[issue.ID] = issue
+			}
+		}
+	}
+
+	inbox := []inboxItem{}
+	automatedActors := gates.AutomatedActors(ctx, s.store)
+	for _, issue := range requested {
+		_, pending, err := gates.ResolveOverrides(ctx, s.store, issue.ID, automatedActors)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		for _, p := range pending {
+			inbox = append(inbox, inboxItem{
+				IssueID:  issue.ID,
+				Title:    issue.Title,
+				Status:   issue.Status,
+				Priority: issue.Priority,
+				Gate:     p.Gate,
+				Reason:   p.Reason,
+			})
+		}
+	}
+	sort.Slice(inbox, func(i, j int) bool {
+		if inbox[i].Priority != inbox[j].Priority {
+			return inbox[i].Priority < inbox[j].Priority
+		}
+		if inbox[i].IssueID != inbox[j].IssueID {
+			return inbox[i].IssueID < inbox[j].IssueID
+		}
+		return inbox[i].Gate < inbox[j].Gate
+	})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"pending": inbox})
+}
+
+// approvals resolves an issue's gate overrides, also returning the gates
+// whose overrides are pending
+func (s *Server) approvals(ctx context.Context, issueID string) (*approvals, map[gates.GateType]bool, error) {
+	overrides, pendingOverrides, err := gates.ResolveOverrides(ctx, s.store, issueID, gates.AutomatedActors(ctx, s.store))
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &approvals{Approved: []approval{}, Pending: []pendingApproval{}}
+	for _, o := range overrides {
+		result.Approved = append(result.Approved, approval{Gate: o.Gate, RequestedBy: o.RequestedBy, ApprovedBy: o.ApprovedBy, ApprovedAt: o.ApprovedAt})
+	}
+	pending := map[gates.GateType]bool{}
+	for _, p := range pendingOverrides {
+		result.Pending = append(result.Pending, pendingApproval{Gate: p.Gate, Reason: p.Reason})
+		pending[p.Gate] = true
+	}
+	return result, pending, nil
+}
+
+// providerUsage is the AI usage of one agent provider
+type providerUsage struct {
+	Executions int     `json:"executions"`
+	CostUSD    float64 `json:"cost_usd"`
+}
+
+// usage is the body of GET /api/v1/usage
+type usage struct {
+	Since      *time.Time                `json:"since,omitempty"`
+	Executions int                       `json:"executions"`
+	CostUSD    float64                   `json:"cost_usd"`
+	ByStatus   map[string]int            `json:"by_status"`
+	ByProvider map[string]*providerUsage `json:"by_provider"`
+	TopIssues  []issueUsage              `json:"top_issues"`
+	Budget     interface{}               `json:"budget,omitempty"`
+}
+
+// issueUsage is the AI usage of one issue
+type issueUsage struct {
+	IssueID    string  `json:"issue_id"`
+	Executions int     `json:"executions"`
+	CostUSD    float64 `json:"cost_usd"`
+}
+
+// handleUsage answers GET /api/v1/usage with AI usage: agent executions
+// and their reported cost, overall, by status, by provider and for the ten
+// most expensive issues, plus the AI cost budget if cost tracking is on.
+//
+// Query parameters: issue and since.
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	filter, err := executionFilter(r, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	executions, err := s.store.ListExecutions(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := &usage{ByStatus: map[string]int{}, ByProvider: map[string]*providerUsage{}, TopIssues: []issueUsage{}}
+	if !filter.Since.IsZero() {
+		result.Since = &filter.Since
+	}
+	byIssue := map[string]*issueUsage{}
+	for _, e := range executions {
+		result.Executions++
+		result.CostUSD += e.CostUSD
+		result.ByStatus[string(e.Status)]++
+		provider := result.ByProvider[e.AgentProvider]
+		if provider == nil {
+			provider = &providerUsage{}
+			result.ByProvider[e.AgentProvider] = provider
+		}
+		provider.Executions++
+		provider.CostUSD += e.CostUSD
+		issue := byIssue[e.IssueID]
+		if issue == nil {
+			issue = &issueUsage{IssueID: e.IssueID}
+			byIssue[e.IssueID] = issue
+		}
+		issue.Executions++
+		issue.CostUSD += e.CostUSD
+	}
+	for _, issue := range byIssue {
+		result.TopIssues = append(result.TopIssues, *issue)
+	}
+	sort.Slice(result.TopIssues, func(i, j int) bool {
+		if result.TopIssues[i].CostUSD != result.TopIssues[j].CostUSD {
+			return result.TopIssues[i].CostUSD > result.TopIssues[j].CostUSD
+		}
+		return result.TopIssues[i].IssueID < result.TopIssues[j].IssueID
+	})
+	if len(result.TopIssues) > 10 {
+		result.TopIssues = result.TopIssues[:10]
+	}
+	if s.costs != nil {
+		stats := s.costs.GetStats()
+		result.Budget = map[string]interface{}{
+			"status":             stats.Status.String(),
+			"hourly_tokens_used": stats.HourlyTokensUsed,
+			"hourly_cost_used":   stats.HourlyCostUsed,
+			"total_tokens_used":  stats.TotalTokensUsed,
+			"total_cost_used":    stats.TotalCostUsed,
+			"window_start_time":  stats.WindowStartTime,
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// executionFilter builds an execution filter from the issue, status, since
+// and limit query parameters
+func executionFilter(r *http.Request, defaultLimit int) (types.ExecutionFilter, error) {
+	query := r.URL.Query()
+	filter := types.ExecutionFilter{
+		IssueID: query.Get("issue"),
+		Status:  types.ExecutionStatus(query.Get("status")),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return filter, badRequest("invalid status: %q", filter.Status)
+	}
+	var err error
+	if filter.Since, err = queryTime(r, "since"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = queryInt(r, "limit", defaultLimit); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// pageRequest builds a page request from the cursor, limit, order,
+// order_by and since query parameters
+func pageRequest(r *http.Request) (types.PageRequest, error) {
+	query := r.URL.Query()
+	page := types.PageRequest{
+		Cursor:  query.Get("cursor"),
+		Order:   types.SortOrder(query.Get("order")),
+		OrderBy: query.Get("order_by"),
+	}
+	var err error
+	if page.Limit, err = queryInt(r, "limit", 0); err != nil {
+		return page, err
+	}
+	if page.Since, err = queryTime(r, "since"); err != nil {
+		return page, err
+	}
+	if err := page.Validate(); err != nil {
+		return page, badRequest("%v", err)
+	}
+	return page, nil
+}
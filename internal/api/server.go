@@ -0,0 +1,247 @@
+// Package api serves VC's REST API, letting external tools and UIs read and
+// change the tracker without linking the storage package or opening the
+// database themselves.
+//
+// All routes live under /api/v1 and exchange JSON. Every route but
+// /api/v1/health needs an "Authorization: Bearer <token>" header; changes
+// made through the API are recorded as made by the token's actor. Errors
+// are returned as {"error": "..."} with a matching status code.
+//
+// Every other path serves the web dashboard, a static page that asks for a
+// token and then uses the API from the browser.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/cost"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+// Config holds API server configuration
+type Config struct {
+	Store storage.Storage
+
+	// Tokens maps each accepted bearer token to the actor it acts as
+	// (see config.ServeConfig.ParseTokens)
+	Tokens map[string]string
+
+	// Costs reports the AI cost budget on /api/v1/usage (optional)
+	Costs *cost.Tracker
+}
+
+// Server serves the REST API over a store
+type Server struct {
+	store  storage.Storage
+	tokens map[string]string
+	costs  *cost.Tracker
+	mux    *http.ServeMux
+}
+
+// NewServer creates an API server
+func NewServer(cfg Config) (*Server, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if len(cfg.Tokens) == 0 {
+		return nil, fmt.Errorf("at least one API token is required")
+	}
+	s := &Server{
+		store:  cfg.Store,
+		tokens: cfg.Tokens,
+		costs:  cfg.Costs,
+		mux:    http.NewServeMux(),
+	}
+	s.routes()
+	return s, nil
+}
+
+// routes registers every API route
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/health", s.handleHealth)
+
+	s.handle("GET /api/v1/issues", s.handleListIssues)
+	s.handle("POST /api/v1/issues", s.handleCreateIssue)
+	s.handle("GET /api/v1/issues/{id}", s.handleGetIssue)
+	s.handle("PATCH /api/v1/issues/{id}", s.handleUpdateIssue)
+	s.handle("POST /api/v1/issues/{id}/close", s.handleCloseIssue)
+	s.handle("POST /api/v1/issues/{id}/comments", s.handleAddComment)
+	s.handle("GET /api/v1/issues/{id}/events", s.handleListEvents)
+	s.handle("GET /api/v1/issues/{id}/approvals", s.handleListApprovals)
+	s.handle("POST /api/v1/issues/{id}/approvals/{gate}", s.handleApprove)
+	s.handle("GET /api/v1/approvals", s.handleApprovalInbox)
+
+	s.handle("GET /api/v1/ready", s.handleReady)
+	s.handle("GET /api/v1/missions", s.handleListMissions)
+	s.handle("GET /api/v1/missions/progress", s.handleMissionProgress)
+	s.handle("GET /api/v1/missions/{id}/progress", s.handleGetMissionProgress)
+
+	s.handle("GET /api/v1/events", s.handleListEvents)
+	s.handle("GET /api/v1/agent-events", s.handleListAgentEvents)
+
+	s.handle("GET /api/v1/executions", s.handleListExecutions)
+	s.handle("GET /api/v1/executions/{id}", s.handleGetExecution)
+
+	s.handle("GET /api/v1/usage", s.handleUsage)
+
+	s.mux.Handle("GET /", dashboard())
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the API on addr until ctx is done, then shuts down
+// gracefully
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the API on listener until ctx is done
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to shut down API server: %w", err)
+		}
+		return nil
+	}
+}
+
+// actorKey carries the authenticated actor on a request's context
+type actorKey struct{}
+
+// handle registers an authenticated route
+func (s *Server) handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := s.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="vc"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		handler(w, r.WithContext(context.WithValue(r.Context(), actorKey{}, actor)))
+	})
+}
+
+// authenticate returns the actor of the request's bearer token
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	// Compare against every token so timing doesn't reveal which one matched
+	var actor string
+	for candidate, candidateActor := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			actor = candidateActor
+		}
+	}
+	return actor, actor != ""
+}
+
+// actor returns the authenticated actor of a request
+func actor(r *http.Request) string {
+	actor, _ := r.Context().Value(actorKey{}).(string)
+	return actor
+}
+
+// errNotFound marks errors answered with 404
+var errNotFound = errors.New("not found")
+
+// httpError is an error answered with a specific status code
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+func (e *httpError) Unwrap() error { return e.err }
+
+// badRequest wraps err to be answered with 400
+func badRequest(format string, args ...interface{}) error {
+	return &httpError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+// writeJSON writes v as the response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) // Nothing to do if the client went away
+}
+
+// writeError answers with err, using its status if it carries one
+func writeError(w http.ResponseWriter, status int, err error) {
+	var herr *httpError
+	switch {
+	case errors.As(err, &herr):
+		status = herr.status
+	case errors.Is(err, errNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, storage.ErrReadOnly):
+		status = http.StatusForbidden
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// decodeBody decodes the JSON request body into v, rejecting unknown fields
+func decodeBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// queryInt parses an integer query parameter, or returns def if it is absent
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, badRequest("invalid %s: %q", name, value)
+	}
+	return n, nil
+}
+
+// queryTime parses an RFC 3339 time query parameter, or returns the zero
+// time if it is absent
+func queryTime(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, badRequest("invalid %s: %q (want RFC 3339)", name, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/cost"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+const testToken = "0123456789abcdef"
+
+func newTestServer(t *testing.T) (*httptest.Server, *memory.Store) {
+	t.Helper()
+	store := memory.New()
+	server, err := NewServer(Config{Store: store, Tokens: map[string]string{testToken: "alice"}})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	return ts, store
+}
+
+// do sends an authenticated request and decodes the response into out
+func do(t *testing.T, ts *httptest.Server, method, path string, body interface{}, out interface{}) int {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to encode body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, ts.URL+path, reader)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("failed to decode %s %s response: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestAuthentication(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/api/v1/health")
+	if err != nil {
+		t.Fatalf("GET /api/v1/health failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("health status = %d, want 200 without a token", resp.StatusCode)
+	}
+
+	for _, header := range []string{"", "Bearer wrong-token-0000000", "Basic " + testToken} {
+		req, _ := http.NewRequest("GET", ts.URL+"/api/v1/issues", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /api/v1/issues failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("Authorization %q: status = %d, want 401 with a challenge", header, resp.StatusCode)
+		}
+	}
+}
+
+func TestIssues(t *testing.T) {
+	ts, store := newTestServer(t)
+
+	var created issueDetail
+	status := do(t, ts, "POST", "/api/v1/issues", map[string]interface{}{
+		"title":               "Add API",
+		"acceptance_criteria": "Serves issues",
+		"labels":              []string{"api"},
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201", status)
+	}
+	if created.Status != types.StatusOpen || created.Priority != 2 || created.IssueType != types.TypeTask ||
+		len(created.Labels) != 1 || created.Labels[0] != "api" {
+		t.Errorf("created = %+v", created)
+	}
+	events, _ := store.GetEvents(context.Background(), created.ID, 0)
+	if len(events) == 0 || events[len(events)-1].Actor != "alice" {
+		t.Errorf("issue not created as the token's actor: %v", events)
+	}
+
+	if status := do(t, ts, "POST", "/api/v1/issues", map[string]interface{}{"title": "No criteria"}, nil); status != http.StatusBadRequest {
+		t.Errorf("create without acceptance criteria status = %d, want 400", status)
+	}
+
+	var updated issueDetail
+	if status := do(t, ts, "PATCH", "/api/v1/issues/"+created.ID, map[string]interface{}{"priority": 0, "status": "in_progress"}, &updated); status != http.StatusOK {
+		t.Fatalf("update status = %d, want 200", status)
+	}
+	if updated.Priority != 0 || updated.Status != types.StatusInProgress {
+		t.Errorf("updated = %+v", updated)
+	}
+
+	var page types.IssuePage
+	if status := do(t, ts, "GET", "/api/v1/issues?status=in_progress&label=api", nil, &page); status != http.StatusOK {
+		t.Fatalf("list status = %d, want 200", status)
+	}
+	if len(page.Issues) != 1 || page.Issues[0].ID != created.ID {
+		t.Errorf("list = %+v, want the created issue", page)
+	}
+
+	var comments struct {
+		Comments []*types.Comment `json:"comments"`
+	}
+	if status := do(t, ts, "POST", "/api/v1/issues/"+created.ID+"/comments", map[string]string{"body": "looks good"}, &comments); status != http.StatusCreated {
+		t.Fatalf("comment status = %d, want 201", status)
+	}
+	if len(comments.Comments) != 1 || comments.Comments[0].Author != "alice" {
+		t.Errorf("comments = %+v", comments.Comments)
+	}
+
+	var closed issueDetail
+	if status := do(t, ts, "POST", "/api/v1/issues/"+created.ID+"/close", map[string]string{"reason": "done"}, &closed); status != http.StatusOK {
+		t.Fatalf("close status = %d, want 200", status)
+	}
+	if closed.Status != types.StatusClosed {
+		t.Errorf("closed status = %s", closed.Status)
+	}
+
+	var missing map[string]string
+	if status := do(t, ts, "GET", "/api/v1/issues/vc-missing", nil, &missing); status != http.StatusNotFound || missing["error"] == "" {
+		t.Errorf("missing issue status = %d (%v), want 404 with an error", status, missing)
+	}
+}
+
+func TestApprovals(t *testing.T) {
+	ts, store := newTestServer(t)
+	ctx := context.Background()
+	issue := &types.Issue{Title: "Flaky", AcceptanceCriteria: "Ships", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+	if err := store.CreateIssue(ctx, issue, "bob"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+
+	path := "/api/v1/issues/" + issue.ID + "/approvals/test"
+	if status := do(t, ts, "POST", path, nil, nil); status != http.StatusConflict {
+		t.Errorf("approve without a request status = %d, want 409", status)
+	}
+
+	if err := store.AddLabel(ctx, issue.ID, gates.OverrideLabelPrefix+"test", "bob"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	var before approvals
+	do(t, ts, "GET", "/api/v1/issues/"+issue.ID+"/approvals", nil, &before)
+	if len(before.Pending) != 1 || before.Pending[0].Gate != gates.GateTest || len(before.Approved) != 0 {
+		t.Errorf("approvals before = %+v, want test pending", before)
+	}
+
+	var after approvals
+	if status := do(t, ts, "POST", path, nil, &after); status != http.StatusOK {
+		t.Fatalf("approve status = %d, want 200", status)
+	}
+	if len(after.Approved) != 1 || after.Approved[0].ApprovedBy != "alice" || after.Approved[0].RequestedBy != "bob" || len(after.Pending) != 0 {
+		t.Errorf("approvals after = %+v, want test approved by alice", after)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	ts, store := newTestServer(t)
+	ctx := context.Background()
+	issue := &types.Issue{Title: "Work", AcceptanceCriteria: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "bob"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	for _, e := range []*types.Execution{
+		{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded, CostUSD: 1.5},
+		{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionFailed, CostUSD: 0.5},
+	} {
+		if err := store.CreateExecution(ctx, e); err != nil {
+			t.Fatalf("CreateExecution() error = %v", err)
+		}
+	}
+
+	var got usage
+	if status := do(t, ts, "GET", "/api/v1/usage", nil, &got); status != http.StatusOK {
+		t.Fatalf("usage status = %d, want 200", status)
+	}
+	if got.Executions != 2 || got.CostUSD != 2 || got.ByProvider["claude-code"].Executions != 2 ||
+		got.ByStatus[string(types.ExecutionFailed)] != 1 || len(got.TopIssues) != 1 || got.TopIssues[0].CostUSD != 2 {
+		t.Errorf("usage = %+v", got)
+	}
+
+	var list struct {
+		Executions []*types.Execution `json:"executions"`
+	}
+	do(t, ts, "GET", "/api/v1/executions?status=failed", nil, &list)
+	if len(list.Executions) != 1 || list.Executions[0].CostUSD != 0.5 {
+		t.Errorf("failed executions = %+v", list.Executions)
+	}
+	if status := do(t, ts, "GET", "/api/v1/executions?status=bogus", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("invalid status filter status = %d, want 400", status)
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	ts, _ := newTestServer(t)
+	for _, path := range []string{"/", "/app.js", "/style.css"} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Security-Policy") == "" {
+			t.Errorf("GET %s status = %d, want 200 without a token and with a CSP", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestMissionsReadyAndInbox(t *testing.T) {
+	ts, store := newTestServer(t)
+	ctx := context.Background()
+	mission := &types.Issue{Title: "Ship API", AcceptanceCriteria: "Shipped", Status: types.StatusOpen, Priority: 1,
+		IssueType: types.TypeEpic, IssueSubtype: types.SubtypeMission}
+	task := &types.Issue{Title: "Write handlers", AcceptanceCriteria: "Written", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	blocked := &types.Issue{Title: "Document API", AcceptanceCriteria: "Documented", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{mission, task, blocked} {
+		if err := store.CreateIssue(ctx, issue, "bob"); err != nil {
+			t.Fatalf("CreateIssue() error = %v", err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: task.ID, DependsOnID: mission.ID, Type: types.DepParentChild},
+		{IssueID: blocked.ID, DependsOnID: task.ID, Type: types.DepBlocks},
+	} {
+		if err := store.AddDependency(ctx, dep, "bob"); err != nil {
+			t.Fatalf("AddDependency() error = %v", err)
+		}
+	}
+
+	var missions struct {
+		Missions []*missionNode `json:"missions"`
+	}
+	do(t, ts, "GET", "/api/v1/missions", nil, &missions)
+	if len(missions.Missions) != 1 || len(missions.Missions[0].Children) != 1 ||
+		missions.Missions[0].Children[0].ID != task.ID || len(missions.Missions[0].Children[0].Children) != 0 ||
+		missions.Missions[0].Rollup == nil || missions.Missions[0].Rollup.Children != 1 {
+		t.Errorf("missions = %+v, want the mission with its one child", missions.Missions)
+	}
+
+	if err := store.CreateExecution(ctx, &types.Execution{IssueID: task.ID, AgentProvider: "claude-code",
+		Status: types.ExecutionFailed, CostUSD: 1.25}); err != nil {
+		t.Fatalf("CreateExecution() error = %v", err)
+	}
+	var progress struct {
+		Missions []*cost.MissionReport `json:"missions"`
+	}
+	do(t, ts, "GET", "/api/v1/missions/progress", nil, &progress)
+	if len(progress.Missions) != 1 || progress.Missions[0].MissionID != mission.ID || progress.Missions[0].Tasks != 1 ||
+		progress.Missions[0].Executions != 1 || progress.Missions[0].CostUSD != 1.25 || progress.Missions[0].ProjectedRemainingUSD != nil {
+		t.Errorf("progress = %+v, want the mission with one open task and $1.25 spent", progress.Missions)
+	}
+	var one cost.MissionReport
+	if status := do(t, ts, "GET", "/api/v1/missions/"+mission.ID+"/progress", nil, &one); status != http.StatusOK || one.MissionID != mission.ID {
+		t.Errorf("mission progress status = %d (%+v), want 200 with the mission", status, one)
+	}
+	if status := do(t, ts, "GET", "/api/v1/missions/"+task.ID+"/progress", nil, nil); status != http.StatusNotFound {
+		t.Errorf("progress of a task status = %d, want 404", status)
+	}
+
+	var ready struct {
+		Issues []*types.Issue `json:"issues"`
+	}
+	do(t, ts, "GET", "/api/v1/ready", nil, &ready)
+	if len(ready.Issues) != 1 || ready.Issues[0].ID != task.ID {
+		t.Errorf("ready = %+v, want only the unblocked task", ready.Issues)
+	}
+
+	if err := store.AddLabel(ctx, blocked.ID, gates.OverrideLabelPrefix+"lint", "bob"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	var inbox struct {
+		Pending []inboxItem `json:"pending"`
+	}
+	do(t, ts, "GET", "/api/v1/approvals", nil, &inbox)
+	if len(inbox.Pending) != 1 || inbox.Pending[0].IssueID != blocked.ID || inbox.Pending[0].Gate != gates.GateLint {
+		t.Errorf("inbox = %+v, want the lint override of %s", inbox.Pending, blocked.ID)
+	}
+	do(t, ts, "POST", "/api/v1/issues/"+blocked.ID+"/approvals/lint", nil, nil)
+	do(t, ts, "GET", "/api/v1/approvals", nil, &inbox)
+	if len(inbox.Pending) != 0 {
+		t.Errorf("inbox after approval = %+v, want empty", inbox.Pending)
+	}
+}
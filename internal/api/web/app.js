@@ -0,0 +1,319 @@
+// VC dashboard: polls the REST API with the token kept in localStorage.
+// Everything is rendered with textContent, never innerHTML, since issue
+// titles and agent output are untrusted.
+"use strict";
+
+const API = "/api/v1";
+const POLL_MS = 5000;
+const LIVE_POLL_MS = 2000;
+
+let token = localStorage.getItem("vc-token") || "";
+let pollTimer = null;
+let liveIssue = "";
+let liveSince = "";
+
+// el creates an element with attributes and children (strings or nodes)
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key.startsWith("on")) {
+      node.addEventListener(key.slice(2), value);
+    } else {
+      node.setAttribute(key, value);
+    }
+  }
+  for (const child of children) {
+    if (child !== null && child !== undefined) {
+      node.append(child);
+    }
+  }
+  return node;
+}
+
+function replace(id, ...children) {
+  document.getElementById(id).replaceChildren(...children);
+}
+
+class Unauthorized extends Error {}
+
+async function api(path, options = {}) {
+  const response = await fetch(API + path, {
+    ...options,
+    headers: { Authorization: "Bearer " + token, "Content-Type": "application/json" },
+  });
+  if (response.status === 401) {
+    throw new Unauthorized("invalid token");
+  }
+  const body = await response.json();
+  if (!response.ok) {
+    throw new Error(body.error || response.statusText);
+  }
+  return body;
+}
+
+function showError(err) {
+  if (err instanceof Unauthorized) {
+    signOut("That token was not accepted.");
+    return;
+  }
+  const node = document.getElementById("error");
+  node.textContent = err ? String(err.message || err) : "";
+  node.hidden = !err;
+}
+
+function when(timestamp) {
+  return new Date(timestamp).toLocaleString();
+}
+
+function usd(amount) {
+  return "$" + (amount || 0).toFixed(2);
+}
+
+function issueID(id) {
+  return el("code", {}, id);
+}
+
+function statusTag(status) {
+  return el("span", { class: "status status-" + status }, status);
+}
+
+// Views
+
+function missionNode(node) {
+  const summary = el("span", {}, issueID(node.id), " ", statusTag(node.status), " P" + node.priority + " ", node.title);
+  if (node.rollup && node.rollup.children > 0) {
+    const closed = (node.rollup.by_status && node.rollup.by_status.closed) || 0;
+    summary.append(" ", el("progress", { max: String(node.rollup.children), value: String(closed) }),
+      ` ${closed}/${node.rollup.children}`);
+  }
+  if (!node.children || node.children.length === 0) {
+    return el("li", {}, summary);
+  }
+  const details = el("details", { open: "" }, el("summary", {}, summary),
+    el("ul", {}, ...node.children.map(missionNode)));
+  return el("li", {}, details);
+}
+
+async function loadMissions() {
+  const closed = document.getElementById("missions-closed").checked;
+  const { missions } = await api("/missions" + (closed ? "?closed=true" : ""));
+  if (missions.length === 0) {
+    replace("mission-tree", el("p", { class: "empty" }, "No missions."));
+    return;
+  }
+  replace("mission-tree", el("ul", { class: "tree" }, ...missions.map(missionNode)));
+}
+
+async function loadReady() {
+  const { issues } = await api("/ready");
+  replace("ready-rows", ...issues.map((issue, i) => el("tr", {},
+    el("td", {}, String(i + 1)),
+    el("td", {}, issueID(issue.id)),
+    el("td", {}, "P" + issue.priority),
+    el("td", {}, issue.issue_type),
+    el("td", {}, issue.title))));
+  if (issues.length === 0) {
+    replace("ready-rows", el("tr", {}, el("td", { colspan: "5", class: "empty" }, "Nothing is ready.")));
+  }
+}
+
+async function loadLive() {
+  const { executions } = await api("/executions?status=running");
+  replace("running", ...executions.map((execution) => el("li", {},
+    el("button", {
+      type: "button",
+      class: execution.issue_id === liveIssue ? "selected" : "",
+      onclick: () => selectLive(execution),
+    }, `${execution.issue_id} · #${execution.id} · ${execution.agent_provider}`),
+    el("small", {}, " since " + when(execution.started_at)))));
+  if (executions.length === 0) {
+    replace("running", el("li", { class: "empty" }, "Nothing is running."));
+  }
+  if (liveIssue) {
+    await loadOutput();
+  }
+}
+
+function selectLive(execution) {
+  liveIssue = execution.issue_id;
+  liveSince = execution.started_at;
+  document.getElementById("output").textContent = "";
+  loadLive().catch(showError);
+}
+
+async function loadOutput() {
+  const params = new URLSearchParams({ issue: liveIssue, since: liveSince, limit: "500" });
+  const { events } = await api("/agent-events?" + params);
+  const output = document.getElementById("output");
+  const atBottom = output.scrollTop + output.clientHeight >= output.scrollHeight - 4;
+  // Events come newest first
+  for (const event of events.reverse()) {
+    output.append(`${new Date(event.timestamp).toLocaleTimeString()} [${event.type}] ${event.message}\n`);
+    liveSince = event.timestamp;
+  }
+  if (atBottom) {
+    output.scrollTop = output.scrollHeight;
+  }
+}
+
+async function loadGates() {
+  const { events } = await api("/agent-events?type=quality_gates_completed&limit=50");
+  replace("gate-rows", ...events.map((event) => {
+    const data = event.data || {};
+    const result = data.error ? data.error : data.all_passed ? "passed" : "failed";
+    return el("tr", {},
+      el("td", {}, when(event.timestamp)),
+      el("td", {}, issueID(event.issue_id)),
+      el("td", {}, el("span", { class: "result result-" + (data.all_passed ? "pass" : "fail") }, result)),
+      el("td", {}, String(data.passed_count ?? "")),
+      el("td", {}, String(data.failed_count ?? "")),
+      el("td", {}, event.message));
+  }));
+  if (events.length === 0) {
+    replace("gate-rows", el("tr", {}, el("td", { colspan: "6", class: "empty" }, "No gate runs yet.")));
+  }
+}
+
+function card(label, value) {
+  return el("div", { class: "card" }, el("div", { class: "card-value" }, value), el("div", {}, label));
+}
+
+function bars(rows) {
+  const max = Math.max(...rows.map((row) => row.cost), 0.01);
+  return el("table", { class: "bars" }, ...rows.map((row) => el("tr", {},
+    el("td", {}, row.label),
+    el("td", {}, el("meter", { min: "0", max: String(max), value: String(row.cost) })),
+    el("td", {}, usd(row.cost)),
+    el("td", {}, `${row.executions} execution${row.executions === 1 ? "" : "s"}`))));
+}
+
+async function loadCost() {
+  const hours = document.getElementById("cost-since").value;
+  const query = hours ? "?since=" + new Date(Date.now() - hours * 3600 * 1000).toISOString() : "";
+  const usage = await api("/usage" + query);
+
+  const cards = [
+    card("Executions", String(usage.executions)),
+    card("Reported cost", usd(usage.cost_usd)),
+    card("Succeeded", String(usage.by_status.succeeded || 0)),
+    card("Failed", String(usage.by_status.failed || 0)),
+  ];
+  if (usage.budget) {
+    cards.push(card("Budget (" + usage.budget.status + ")", usd(usage.budget.hourly_cost_used) + " this hour"));
+  }
+  replace("cost-summary", ...cards);
+
+  replace("cost-providers", bars(Object.entries(usage.by_provider).map(([provider, p]) => ({
+    label: provider || "unknown", cost: p.cost_usd, executions: p.executions,
+  }))));
+  replace("cost-issues", bars(usage.top_issues.map((issue) => ({
+    label: issueID(issue.issue_id), cost: issue.cost_usd, executions: issue.executions,
+  }))));
+}
+
+async function approve(item, button) {
+  button.disabled = true;
+  try {
+    await api(`/issues/${encodeURIComponent(item.issue_id)}/approvals/${encodeURIComponent(item.gate)}`, { method: "POST" });
+    await loadApprovals();
+  } catch (err) {
+    button.disabled = false;
+    showError(err);
+  }
+}
+
+async function loadApprovals() {
+  const { pending } = await api("/approvals");
+  const count = document.getElementById("approval-count");
+  count.textContent = String(pending.length);
+  count.hidden = pending.length === 0;
+  replace("approval-rows", ...pending.map((item) => {
+    const button = el("button", { type: "button" }, "Approve");
+    button.addEventListener("click", () => approve(item, button));
+    return el("tr", {},
+      el("td", {}, issueID(item.issue_id)),
+      el("td", {}, "P" + item.priority),
+      el("td", {}, item.title),
+      el("td", {}, item.gate),
+      el("td", {}, item.reason),
+      el("td", {}, button));
+  }));
+  if (pending.length === 0) {
+    replace("approval-rows", el("tr", {}, el("td", { colspan: "6", class: "empty" }, "Nothing awaits approval.")));
+  }
+}
+
+const views = {
+  missions: loadMissions,
+  ready: loadReady,
+  live: loadLive,
+  gates: loadGates,
+  cost: loadCost,
+  approvals: loadApprovals,
+};
+
+function currentView() {
+  const name = location.hash.slice(1);
+  return views[name] ? name : "missions";
+}
+
+// refresh reloads the current view and the approval count, then schedules
+// the next poll
+async function refresh() {
+  clearTimeout(pollTimer);
+  const name = currentView();
+  for (const section of document.querySelectorAll(".view")) {
+    section.hidden = section.id !== name;
+  }
+  for (const link of document.querySelectorAll("nav a")) {
+    link.classList.toggle("active", link.getAttribute("href") === "#" + name);
+  }
+  try {
+    await views[name]();
+    if (name !== "approvals") {
+      await loadApprovals();
+    }
+    showError(null);
+  } catch (err) {
+    showError(err);
+    if (err instanceof Unauthorized) {
+      return;
+    }
+  }
+  pollTimer = setTimeout(refresh, name === "live" ? LIVE_POLL_MS : POLL_MS);
+}
+
+function signOut(message) {
+  clearTimeout(pollTimer);
+  token = "";
+  localStorage.removeItem("vc-token");
+  document.getElementById("sign-in").hidden = false;
+  document.getElementById("sign-out").hidden = true;
+  document.getElementById("sign-in-error").textContent = message || "";
+  for (const section of document.querySelectorAll(".view")) {
+    section.hidden = true;
+  }
+}
+
+function signIn(value) {
+  token = value;
+  localStorage.setItem("vc-token", token);
+  document.getElementById("sign-in").hidden = true;
+  document.getElementById("sign-out").hidden = false;
+  refresh();
+}
+
+document.getElementById("sign-in").addEventListener("submit", (event) => {
+  event.preventDefault();
+  signIn(document.getElementById("token").value.trim());
+});
+document.getElementById("sign-out").addEventListener("click", () => signOut());
+document.getElementById("missions-closed").addEventListener("change", () => refresh());
+document.getElementById("cost-since").addEventListener("change", () => refresh());
+window.addEventListener("hashchange", () => refresh());
+
+if (token) {
+  signIn(token);
+} else {
+  signOut();
+}
@@ -0,0 +1,456 @@
+// Package backport carries fixes to release branches: for each
+// "backport:<branch>" label on an issue, it cherry-picks the commits that
+// landed for the issue onto the release branch in a scratch worktree, runs
+// the quality gates there, and opens a pull request against the release
+// branch. When the commits don't apply cleanly or the gates fail, it files a
+// follow-up issue to finish the backport by hand instead.
+package backport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/hosting"
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// LabelPrefix marks labels that request a backport, e.g.
+// "backport:release-1.x" backports the issue's fix to release-1.x
+const LabelPrefix = "backport:"
+
+// BranchPrefix prefixes the branches backports are prepared on
+const BranchPrefix = git.IssueBranchPrefix + "backport/"
+
+// maxGateOutput caps how much of a failed gate's output goes into a
+// follow-up issue
+const maxGateOutput = 4000
+
+// Backport outcomes
+const (
+	StatusOpened      = "opened"         // Pull request opened against the release branch
+	StatusBranch      = "branch_created" // Branch prepared locally; no hosting provider to open a pull request with
+	StatusConflict    = "conflict"       // Commits didn't apply cleanly; follow-up issue filed
+	StatusGatesFailed = "gates_failed"   // Quality gates failed on the release branch; follow-up issue filed
+	StatusSkipped     = "skipped"        // Already backported by an earlier run
+	StatusFailed      = "failed"         // Backport couldn't be attempted
+)
+
+// Label returns the label that requests a backport to branch
+func Label(branch string) string {
+	return LabelPrefix + branch
+}
+
+// Branches returns the release branches labels request backports to
+func Branches(labels []string) []string {
+	var branches []string
+	for _, label := range labels {
+		if branch, ok := strings.CutPrefix(label, LabelPrefix); ok && branch != "" {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// BranchName returns the branch an issue's backport to target is prepared on
+func BranchName(issueID, target string) string {
+	return BranchPrefix + target + "/" + issueID
+}
+
+// Config configures a Manager
+type Config struct {
+	Store      storage.Storage
+	Git        git.GitOperations
+	RepoPath   string                  // Repository the commits landed in
+	Hosting    config.HostingConfig    // Opens pull requests; without a token backport branches are only prepared locally
+	PushChecks config.PushChecksConfig // Pre-push checks for backport branches
+	Actor      string                  // Recorded as the creator of follow-up issues and comments
+	ExecutorID string                  // Recorded on backport events (optional)
+
+	// HoldWorktree keeps a pooled worktree assigned while a backport uses
+	// it and returns a function that stops doing so (optional)
+	HoldWorktree func(pool *sandbox.WorktreePool, wt *sandbox.PooledWorktree) func()
+}
+
+// Manager backports issues to release branches
+type Manager struct {
+	store      storage.Storage
+	git        git.GitOperations
+	repoPath   string
+	hosting    config.HostingConfig
+	pushChecks config.PushChecksConfig
+	actor      string
+	executorID string
+	hold       func(pool *sandbox.WorktreePool, wt *sandbox.PooledWorktree) func()
+}
+
+// Result describes the backport of an issue to one release branch
+type Result struct {
+	TargetBranch    string
+	Status          string
+	Commits         []string // Landed commits that were picked, oldest first
+	Branch          string   // Branch holding the picked commits (StatusOpened, StatusBranch, StatusSkipped)
+	PRURL           string   // StatusOpened
+	Conflicts       []string // StatusConflict
+	FollowUpIssueID string   // StatusConflict, StatusGatesFailed
+	Reason          string   // Why the backport didn't open a pull request
+}
+
+// New creates a backport manager
+func New(cfg Config) (*Manager, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	if cfg.Git == nil {
+		return nil, fmt.Errorf("git operations are required")
+	}
+	if cfg.RepoPath == "" {
+		return nil, fmt.Errorf("repository path is required")
+	}
+	if cfg.Actor == "" {
+		cfg.Actor = "vc-backport"
+	}
+	if cfg.Hosting.Remote == "" {
+		cfg.Hosting.Remote = "origin"
+	}
+	return &Manager{
+		store:      cfg.Store,
+		git:        cfg.Git,
+		repoPath:   cfg.RepoPath,
+		hosting:    cfg.Hosting,
+		pushChecks: cfg.PushChecks,
+		actor:      cfg.Actor,
+		executorID: cfg.ExecutorID,
+		hold:       cfg.HoldWorktree,
+	}, nil
+}
+
+// BackportIssue backports an issue to every release branch its labels
+// request. Returns one result per branch; nil if none are requested. Per
+// branch failures are reported in the results; an error means the issue
+// couldn't be backported at all, e.g. none of its commits have landed.
+func (m *Manager) BackportIssue(ctx context.Context, issueID string) ([]*Result, error) {
+	labels, err := m.store.GetLabels(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels of %s: %w", issueID, err)
+	}
+	targets := Branches(labels)
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	issue, err := m.store.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", issueID, err)
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s not found", issueID)
+	}
+	commits, err := m.LandedCommits(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits for %s have landed in %s", issueID, m.repoPath)
+	}
+
+	results := make([]*Result, 0, len(targets))
+	for _, target := range targets {
+		result := m.backport(ctx, issue, target, commits)
+		m.record(ctx, issue, result)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// LandedCommits returns the commits the issue's executions made that are in
+// the checked out branch's history and weren't rolled back, oldest first
+func (m *Manager) LandedCommits(ctx context.Context, issueID string) ([]string, error) {
+	execs, err := m.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issueID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions of %s: %w", issueID, err)
+	}
+	sort.SliceStable(execs, func(i, j int) bool {
+		if !execs[i].StartedAt.Equal(execs[j].StartedAt) {
+			return execs[i].StartedAt.Before(execs[j].StartedAt)
+		}
+		return execs[i].ID < execs[j].ID
+	})
+	var commits []string
+	seen := make(map[string]bool)
+	for _, exec := range execs {
+		if exec.CommitHash == "" || exec.IsRolledBack() || seen[exec.CommitHash] {
+			continue
+		}
+		landed, err := m.git.IsAncestor(ctx, m.repoPath, exec.CommitHash)
+		if err != nil {
+			return nil, err
+		}
+		if landed {
+			seen[exec.CommitHash] = true
+			commits = append(commits, exec.CommitHash)
+		}
+	}
+	return commits, nil
+}
+
+// backport picks commits onto target in a scratch worktree
+func (m *Manager) backport(ctx context.Context, issue *types.Issue, target string, commits []string) *Result {
+	result := &Result{TargetBranch: target, Commits: commits, Branch: BranchName(issue.ID, target)}
+	fail := func(status, reason string) *Result {
+		if status != StatusSkipped {
+			result.Branch = "" // Not created
+		}
+		result.Status = status
+		result.Reason = reason
+		return result
+	}
+
+	if _, err := m.git.ResolveRef(ctx, m.repoPath, "refs/heads/"+result.Branch); err == nil {
+		return fail(StatusSkipped, fmt.Sprintf("branch %s already exists from an earlier backport", result.Branch))
+	}
+	// Prefer the release branch as last fetched from the remote
+	start, err := m.git.ResolveRef(ctx, m.repoPath, m.hosting.Remote+"/"+target)
+	if err != nil {
+		if start, err = m.git.ResolveRef(ctx, m.repoPath, target); err != nil {
+			return fail(StatusFailed, fmt.Sprintf("release branch %s not found", target))
+		}
+	}
+
+	pool, err := sandbox.NewWorktreePool(sandbox.WorktreePoolConfig{ParentRepo: m.repoPath})
+	if err != nil {
+		return fail(StatusFailed, fmt.Sprintf("failed to open worktree pool: %v", err))
+	}
+	wt, err := pool.Acquire(ctx, issue.ID, start)
+	if err != nil {
+		return fail(StatusFailed, fmt.Sprintf("failed to get a worktree: %v", err))
+	}
+	unhold := func() {}
+	if m.hold != nil {
+		unhold = m.hold(pool, wt)
+	}
+	defer func() {
+		unhold()
+		if err := pool.Release(context.WithoutCancel(ctx), wt); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to release worktree %s: %v\n", wt.Path, err)
+		}
+	}()
+
+	picked, err := m.git.CherryPick(ctx, wt.Path, commits)
+	if err != nil {
+		return fail(StatusFailed, err.Error())
+	}
+	if len(picked.Conflicts) > 0 {
+		result.Conflicts = picked.Conflicts
+		reason := fmt.Sprintf("commit %s conflicts with %s in %s",
+			shortHash(picked.ConflictingCommit), target, strings.Join(picked.Conflicts, ", "))
+		details := fmt.Sprintf("Cherry-picking commit %s onto %s conflicts in:\n- %s",
+			picked.ConflictingCommit, target, strings.Join(picked.Conflicts, "\n- "))
+		result.FollowUpIssueID = m.fileFollowUp(ctx, issue, target, commits, details)
+		return fail(StatusConflict, reason)
+	}
+
+	runner, err := gates.NewRunner(&gates.Config{Store: m.store, WorkingDir: wt.Path})
+	if err != nil {
+		return fail(StatusFailed, fmt.Sprintf("failed to create gates runner: %v", err))
+	}
+	gateResults, passed := runner.RunAll(ctx)
+	if !passed {
+		var failed []string
+		var details strings.Builder
+		fmt.Fprintf(&details, "The commits apply cleanly to %s, but quality gates fail there:\n", target)
+		for _, gate := range gateResults {
+			if gate.Passed {
+				continue
+			}
+			failed = append(failed, string(gate.Gate))
+			fmt.Fprintf(&details, "\n### %s\n", gate.Gate)
+			if gate.Error != nil {
+				fmt.Fprintf(&details, "%v\n", gate.Error)
+			}
+			if output := strings.TrimSpace(gate.Output); output != "" {
+				fmt.Fprintf(&details, "```\n%s\n```\n", truncate(output, maxGateOutput))
+			}
+		}
+		result.FollowUpIssueID = m.fileFollowUp(ctx, issue, target, commits, details.String())
+		return fail(StatusGatesFailed, fmt.Sprintf("quality gates failed on %s: %s", target, strings.Join(failed, ", ")))
+	}
+
+	if err := m.git.CheckoutBranch(ctx, wt.Path, result.Branch, ""); err != nil {
+		return fail(StatusFailed, err.Error())
+	}
+	if !m.hosting.Enabled() {
+		result.Status = StatusBranch
+		result.Reason = "no git hosting token is configured to open a pull request with"
+		return result
+	}
+	prURL, err := m.openPullRequest(ctx, issue, wt.Path, result.Branch, target, commits)
+	if err != nil {
+		return fail(StatusFailed, err.Error())
+	}
+	result.Status = StatusOpened
+	result.PRURL = prURL
+	return result
+}
+
+// openPullRequest pushes branch from dir and opens a pull request for it
+// against target. Returns the pull request URL.
+func (m *Manager) openPullRequest(ctx context.Context, issue *types.Issue, dir, branch, target string, commits []string) (string, error) {
+	remoteURL, err := m.git.RemoteURL(ctx, dir, m.hosting.Remote)
+	if err != nil {
+		return "", err
+	}
+	provider, err := hosting.Open(m.hosting, remoteURL)
+	if err != nil {
+		return "", err
+	}
+	if err := m.git.Push(ctx, dir, git.PushOptions{
+		Remote:    m.hosting.Remote,
+		Branch:    branch,
+		Token:     provider.Token(),
+		TokenUser: provider.PushUser(),
+		Checks:    m.gitPushChecks(),
+	}); err != nil {
+		return "", err
+	}
+
+	body := fmt.Sprintf("Backport of %s to %s.\n\nCherry-picked commits:\n", issue.ID, target)
+	for _, commit := range commits {
+		body += fmt.Sprintf("- %s\n", commit)
+	}
+	body += fmt.Sprintf("\nThe quality gates pass on the result.\n\n---\nvc issue %s: %s\n", issue.ID, issue.Title)
+	pr, err := provider.CreatePullRequest(ctx, hosting.NewPullRequest{
+		Title:        fmt.Sprintf("[%s] [%s] %s", target, issue.ID, issue.Title),
+		Body:         body,
+		SourceBranch: branch,
+		TargetBranch: target,
+		Draft:        m.hosting.Draft,
+		Labels:       m.hosting.Labels,
+	})
+	if pr == nil {
+		return "", err
+	}
+	if err != nil {
+		// Opened, but not labelled
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	if err := hosting.RecordCreated(ctx, m.store, issue.ID, m.executorID, provider, pr); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record pull request: %v\n", err)
+	}
+	return pr.URL, nil
+}
+
+// gitPushChecks returns the pre-push checks to run, or nil if they are off
+func (m *Manager) gitPushChecks() *git.PushChecks {
+	if !m.pushChecks.Enabled {
+		return nil
+	}
+	return &git.PushChecks{
+		MaxFileSize:    int64(m.pushChecks.MaxFileSizeKB) * 1024,
+		AllowBinary:    m.pushChecks.AllowBinary,
+		AllowForcePush: m.pushChecks.AllowForcePush,
+		ScanSecrets:    m.pushChecks.ScanSecrets,
+	}
+}
+
+// fileFollowUp files an issue to finish a backport by hand, discovered from
+// the original issue. Returns its ID, or "" if it couldn't be filed.
+func (m *Manager) fileFollowUp(ctx context.Context, issue *types.Issue, target string, commits []string, details string) string {
+	followUp := &types.Issue{
+		Title: fmt.Sprintf("Backport %s to %s: %s", issue.ID, target, issue.Title),
+		Description: fmt.Sprintf("The fix for %s couldn't be backported to %s automatically.\n\n%s\n\n"+
+			"Commits to backport, oldest first:\n- %s\n\nOriginal description:\n%s",
+			issue.ID, target, strings.TrimSpace(details), strings.Join(commits, "\n- "), issue.Description),
+		AcceptanceCriteria: fmt.Sprintf("The changes from %s are on %s and all quality gates pass there", issue.ID, target),
+		Status:             types.StatusOpen,
+		Priority:           issue.Priority,
+		IssueType:          types.TypeTask,
+	}
+	if err := m.store.CreateIssue(ctx, followUp, m.actor); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to file backport follow-up issue: %v\n", err)
+		return ""
+	}
+	dep := &types.Dependency{
+		IssueID:     followUp.ID,
+		DependsOnID: issue.ID,
+		Type:        types.DepDiscoveredFrom,
+	}
+	if err := m.store.AddDependency(ctx, dep, m.actor); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to link follow-up issue %s to %s: %v\n", followUp.ID, issue.ID, err)
+	}
+	return followUp.ID
+}
+
+// record comments on the issue and logs a backport event
+func (m *Manager) record(ctx context.Context, issue *types.Issue, result *Result) {
+	severity := events.SeverityInfo
+	var comment string
+	switch result.Status {
+	case StatusOpened:
+		comment = fmt.Sprintf("Backported to %s: %s", result.TargetBranch, result.PRURL)
+	case StatusBranch:
+		comment = fmt.Sprintf("Backport to %s is ready on branch %s; push it and open a pull request against %s (%s)",
+			result.TargetBranch, result.Branch, result.TargetBranch, result.Reason)
+	case StatusSkipped:
+		comment = fmt.Sprintf("Backport to %s skipped: %s", result.TargetBranch, result.Reason)
+	default:
+		severity = events.SeverityWarning
+		comment = fmt.Sprintf("Backport to %s failed: %s", result.TargetBranch, result.Reason)
+		if result.FollowUpIssueID != "" {
+			comment += fmt.Sprintf("\nFollow-up issue: %s", result.FollowUpIssueID)
+		}
+	}
+	if err := m.store.AddComment(ctx, issue.ID, m.actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to comment on %s: %v\n", issue.ID, err)
+	}
+
+	event := &events.AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       events.EventTypeBackport,
+		Timestamp:  time.Now(),
+		IssueID:    issue.ID,
+		ExecutorID: m.executorID,
+		Severity:   severity,
+		Message:    comment,
+	}
+	if err := event.SetBackportData(events.BackportData{
+		TargetBranch:    result.TargetBranch,
+		Commits:         result.Commits,
+		Status:          result.Status,
+		Branch:          result.Branch,
+		PRURL:           result.PRURL,
+		Conflicts:       result.Conflicts,
+		FollowUpIssueID: result.FollowUpIssueID,
+		Reason:          result.Reason,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to set backport data: %v\n", err)
+	}
+	if err := m.store.StoreAgentEvent(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store backport event: %v\n", err)
+	}
+}
+
+// truncate caps s at n bytes, marking the cut
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "\n... (truncated)"
+}
+
+// shortHash abbreviates a commit hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
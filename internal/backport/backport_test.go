@@ -0,0 +1,196 @@
+package backport
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestBranches(t *testing.T) {
+	got := Branches([]string{"bug", Label("release-1.x"), "backport:", "scope:cmd/**", Label("release-2.x")})
+	if len(got) != 2 || got[0] != "release-1.x" || got[1] != "release-2.x" {
+		t.Errorf("Branches() = %v, want [release-1.x release-2.x]", got)
+	}
+}
+
+func TestBackportIssue(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name, content, message string) string {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", "-A")
+		run("commit", "-m", message)
+		return run("rev-parse", "HEAD")
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(dir, ".git", "info", "exclude"), []byte(".sandboxes/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commit(".vc/gates.yaml", "pipeline:\n  - name: check\n    command: test ! -e broken.txt\n", "add gates")
+	commit("a.txt", "one\n", "initial")
+	run("branch", "release-1.x")
+
+	store := memory.New()
+	newIssue := func(title string, labels ...string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeChore}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		for _, label := range labels {
+			if err := store.AddLabel(ctx, issue.ID, label, "test"); err != nil {
+				t.Fatalf("AddLabel failed: %v", err)
+			}
+		}
+		return issue
+	}
+	landed := func(issue *types.Issue, commit string) {
+		t.Helper()
+		execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded, CommitHash: commit}
+		if err := store.CreateExecution(ctx, execution); err != nil {
+			t.Fatalf("CreateExecution failed: %v", err)
+		}
+	}
+	release := Label("release-1.x")
+	fixed := newIssue("Fix b", release)
+	landed(fixed, commit("b.txt", "fixed\n", "fix b"))
+	conflicting := newIssue("Change a", release)
+	landed(conflicting, commit("a.txt", "two\n", "change a"))
+	broken := newIssue("Break the gates", release)
+	landed(broken, commit("broken.txt", "x\n", "break"))
+	unlabelled := newIssue("Not backported")
+	landed(unlabelled, commit("c.txt", "c\n", "add c"))
+	unlanded := newIssue("Never landed", release)
+	run("checkout", "-q", "release-1.x")
+	commit("a.txt", "release\n", "release change to a")
+	run("checkout", "-q", "main")
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+	manager, err := New(Config{Store: store, Git: gitOps, RepoPath: dir, Actor: "test"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	backport := func(issue *types.Issue) *Result {
+		t.Helper()
+		results, err := manager.BackportIssue(ctx, issue.ID)
+		if err != nil {
+			t.Fatalf("BackportIssue(%s) error = %v", issue.Title, err)
+		}
+		if len(results) != 1 || results[0].TargetBranch != "release-1.x" {
+			t.Fatalf("BackportIssue(%s) = %+v, want one result for release-1.x", issue.Title, results)
+		}
+		return results[0]
+	}
+
+	if results, err := manager.BackportIssue(ctx, unlabelled.ID); err != nil || results != nil {
+		t.Errorf("BackportIssue(unlabelled) = %v, %v; want nothing to do", results, err)
+	}
+	if _, err := manager.BackportIssue(ctx, unlanded.ID); err == nil {
+		t.Error("expected an error backporting an issue without landed commits")
+	}
+
+	// Without a hosting provider the branch is prepared locally
+	result := backport(fixed)
+	if result.Status != StatusBranch || result.Branch != BranchName(fixed.ID, "release-1.x") {
+		t.Fatalf("result = %+v, want a prepared branch", result)
+	}
+	if content := run("show", result.Branch+":b.txt"); content != "fixed" {
+		t.Errorf("b.txt on the backport branch = %q, want the fix", content)
+	}
+	if parent := run("rev-parse", result.Branch+"~1"); parent != run("rev-parse", "release-1.x") {
+		t.Errorf("backport branch doesn't start at release-1.x")
+	}
+	if body := run("log", "-1", "--format=%b", result.Branch); !strings.Contains(body, "cherry picked from commit") {
+		t.Errorf("backport commit doesn't record its origin: %q", body)
+	}
+	if again := backport(fixed); again.Status != StatusSkipped {
+		t.Errorf("second backport = %+v, want it skipped", again)
+	}
+
+	// A conflict files a follow-up issue and leaves no branch behind
+	result = backport(conflicting)
+	if result.Status != StatusConflict || len(result.Conflicts) != 1 || result.Conflicts[0] != "a.txt" {
+		t.Fatalf("result = %+v, want a conflict in a.txt", result)
+	}
+	assertFollowUp(t, store, conflicting, result.FollowUpIssueID)
+	if _, err := gitOps.ResolveRef(ctx, dir, BranchName(conflicting.ID, "release-1.x")); err == nil {
+		t.Error("backport branch created despite the conflict")
+	}
+
+	// So does a gate failure on the release branch
+	result = backport(broken)
+	if result.Status != StatusGatesFailed || !strings.Contains(result.Reason, "check") {
+		t.Fatalf("result = %+v, want the check gate to fail", result)
+	}
+	assertFollowUp(t, store, broken, result.FollowUpIssueID)
+
+	evts, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeBackport})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(evts) != 4 {
+		t.Fatalf("got %d backport events, want 4", len(evts))
+	}
+	comments, err := store.GetComments(ctx, fixed.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) == 0 || !strings.Contains(comments[0].Body, result.TargetBranch) {
+		t.Errorf("comments = %+v, want the backport noted on the issue", comments)
+	}
+	if status := run("status", "--porcelain"); status != "" {
+		t.Errorf("main working tree changed:\n%s", status)
+	}
+}
+
+// assertFollowUp checks that a follow-up issue was filed, discovered from issue
+func assertFollowUp(t *testing.T, store *memory.Store, issue *types.Issue, followUpID string) {
+	t.Helper()
+	ctx := context.Background()
+	if followUpID == "" {
+		t.Fatal("no follow-up issue filed")
+	}
+	followUp, err := store.GetIssue(ctx, followUpID)
+	if err != nil || followUp == nil {
+		t.Fatalf("GetIssue(%s) = %v, %v", followUpID, followUp, err)
+	}
+	if !strings.Contains(followUp.Title, issue.ID) || !strings.Contains(followUp.Title, "release-1.x") {
+		t.Errorf("follow-up title = %q, want it to name %s and release-1.x", followUp.Title, issue.ID)
+	}
+	deps, err := store.GetDependencyRecords(ctx, followUpID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != issue.ID || deps[0].Type != types.DepDiscoveredFrom {
+		t.Errorf("follow-up dependencies = %+v, want discovered-from %s", deps, issue.ID)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AgentAnomalyConfig configures the behavioral anomaly detectors that watch
+// an agent's tool calls while it runs. An agent that deletes files en masse,
+// edits far outside its issue's scope, repeats the same call over and over or
+// touches a protected path is stopped and its issue escalated to a human.
+type AgentAnomalyConfig struct {
+	// Enabled turns the anomaly detectors on
+	// Default: true
+	Enabled bool
+
+	// MaxDeletions is how many files an agent can delete in one execution.
+	// Recursive deletes of the whole working tree always count as too many.
+	// Default: 25, Range: 1-10000
+	MaxDeletions int
+
+	// MaxOutOfScopeEdits is how many distinct files outside a scoped
+	// issue's paths an agent can edit. Edits outside the working tree
+	// always count as too many.
+	// Default: 5, Range: 1-1000
+	MaxOutOfScopeEdits int
+
+	// MaxRepeatedCalls is how many times an agent can make the same tool
+	// call, with the same input
+	// Default: 8, Range: 2-1000
+	MaxRepeatedCalls int
+
+	// ProtectedPaths are directories agents must never modify, relative to
+	// the working tree: the git repository and VC's own config and database
+	// Default: .git, .vc, .beads
+	ProtectedPaths []string
+}
+
+// DefaultAgentAnomalyConfig returns the default agent anomaly configuration
+func DefaultAgentAnomalyConfig() AgentAnomalyConfig {
+	return AgentAnomalyConfig{
+		Enabled:            true,
+		MaxDeletions:       25,
+		MaxOutOfScopeEdits: 5,
+		MaxRepeatedCalls:   8,
+		ProtectedPaths:     []string{".git", ".vc", ".beads"},
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c AgentAnomalyConfig) Validate() error {
+	if c.MaxDeletions < 1 || c.MaxDeletions > 10000 {
+		return fmt.Errorf("max deletions must be between 1 and 10000 (got %d)", c.MaxDeletions)
+	}
+	if c.MaxOutOfScopeEdits < 1 || c.MaxOutOfScopeEdits > 1000 {
+		return fmt.Errorf("max out-of-scope edits must be between 1 and 1000 (got %d)", c.MaxOutOfScopeEdits)
+	}
+	if c.MaxRepeatedCalls < 2 || c.MaxRepeatedCalls > 1000 {
+		return fmt.Errorf("max repeated calls must be between 2 and 1000 (got %d)", c.MaxRepeatedCalls)
+	}
+	for _, path := range c.ProtectedPaths {
+		if path == "" || path == "." || strings.HasPrefix(path, "/") || strings.HasPrefix(path, "..") {
+			return fmt.Errorf("protected path %q must be a directory inside the working tree", path)
+		}
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c AgentAnomalyConfig) String() string {
+	return fmt.Sprintf("AgentAnomalyConfig{Enabled: %v, MaxDeletions: %d, MaxOutOfScopeEdits: %d, MaxRepeatedCalls: %d, ProtectedPaths: %v}",
+		c.Enabled, c.MaxDeletions, c.MaxOutOfScopeEdits, c.MaxRepeatedCalls, c.ProtectedPaths)
+}
+
+// AgentAnomalyConfigFromEnv creates an AgentAnomalyConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_AGENT_ANOMALY_ENABLED: Stop agents that behave suspiciously and escalate (default: true)
+//   - VC_AGENT_ANOMALY_MAX_DELETIONS: Files an agent can delete in one execution (default: 25)
+//   - VC_AGENT_ANOMALY_MAX_OUT_OF_SCOPE_EDITS: Files outside a scoped issue's paths an agent can edit (default: 5)
+//   - VC_AGENT_ANOMALY_MAX_REPEATED_CALLS: Times an agent can make the same tool call (default: 8)
+//   - VC_AGENT_ANOMALY_PROTECTED_PATHS: Comma-separated directories agents must not modify (default: .git,.vc,.beads)
+//
+// Returns an error if any environment variable has an invalid value.
+func AgentAnomalyConfigFromEnv() (AgentAnomalyConfig, error) {
+	cfg := DefaultAgentAnomalyConfig()
+
+	if err := parseEnvBool("VC_AGENT_ANOMALY_ENABLED", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_AGENT_ANOMALY_MAX_DELETIONS", &cfg.MaxDeletions); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_AGENT_ANOMALY_MAX_OUT_OF_SCOPE_EDITS", &cfg.MaxOutOfScopeEdits); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_AGENT_ANOMALY_MAX_REPEATED_CALLS", &cfg.MaxRepeatedCalls); err != nil {
+		return cfg, err
+	}
+	var protected string
+	parseEnvString("VC_AGENT_ANOMALY_PROTECTED_PATHS", &protected)
+	if protected != "" {
+		cfg.ProtectedPaths = nil
+		for _, path := range strings.Split(protected, ",") {
+			if path = strings.Trim(strings.TrimSpace(path), "/"); path != "" {
+				cfg.ProtectedPaths = append(cfg.ProtectedPaths, path)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid agent anomaly configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
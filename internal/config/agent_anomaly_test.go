@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAgentAnomalyConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg AgentAnomalyConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg AgentAnomalyConfig) {
+				if !reflect.DeepEqual(cfg, DefaultAgentAnomalyConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultAgentAnomalyConfig())
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_AGENT_ANOMALY_ENABLED":                "false",
+				"VC_AGENT_ANOMALY_MAX_DELETIONS":          "100",
+				"VC_AGENT_ANOMALY_MAX_OUT_OF_SCOPE_EDITS": "2",
+				"VC_AGENT_ANOMALY_MAX_REPEATED_CALLS":     "20",
+				"VC_AGENT_ANOMALY_PROTECTED_PATHS":        ".git, deploy/secrets/ ,",
+			},
+			check: func(t *testing.T, cfg AgentAnomalyConfig) {
+				if cfg.Enabled || cfg.MaxDeletions != 100 || cfg.MaxOutOfScopeEdits != 2 || cfg.MaxRepeatedCalls != 20 {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if !reflect.DeepEqual(cfg.ProtectedPaths, []string{".git", "deploy/secrets"}) {
+					t.Errorf("ProtectedPaths = %v", cfg.ProtectedPaths)
+				}
+			},
+		},
+		{
+			name:    "repeated calls too low",
+			envVars: map[string]string{"VC_AGENT_ANOMALY_MAX_REPEATED_CALLS": "1"},
+			wantErr: true,
+		},
+		{
+			name:    "protected path outside the working tree",
+			envVars: map[string]string{"VC_AGENT_ANOMALY_PROTECTED_PATHS": "../shared"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid max deletions",
+			envVars: map[string]string{"VC_AGENT_ANOMALY_MAX_DELETIONS": "lots"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_AGENT_ANOMALY_ENABLED", "VC_AGENT_ANOMALY_MAX_DELETIONS", "VC_AGENT_ANOMALY_MAX_OUT_OF_SCOPE_EDITS",
+				"VC_AGENT_ANOMALY_MAX_REPEATED_CALLS", "VC_AGENT_ANOMALY_PROTECTED_PATHS"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := AgentAnomalyConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AgentAnomalyConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
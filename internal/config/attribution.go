@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// CoAuthorNone turns off the Co-Authored-By trailer
+const CoAuthorNone = "none"
+
+// identityRegex matches a git identity, "Name <email>"
+var identityRegex = regexp.MustCompile(`^[^<>\n]+ <[^<>\s]+>$`)
+
+// CommitAttributionConfig configures the trailers that attribute
+// auto-commits to the agent and execution that made them, so blame and
+// history show which changes were machine-generated
+type CommitAttributionConfig struct {
+	// CoAuthor is the Co-Authored-By identity, "Name <email>"
+	// Default: "" (the agent's own identity, e.g. Claude <noreply@anthropic.com>)
+	// "none" omits the trailer
+	CoAuthor string
+
+	// AgentTrailers adds VC-Agent (agent provider and model) and
+	// VC-Execution (execution ID) trailers
+	// Default: true
+	AgentTrailers bool
+}
+
+// DefaultCommitAttributionConfig returns the default commit attribution
+// configuration
+//
+// Auto-commits credit the agent as co-author and name the agent and
+// execution in trailers.
+func DefaultCommitAttributionConfig() CommitAttributionConfig {
+	return CommitAttributionConfig{
+		AgentTrailers: true,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c CommitAttributionConfig) Validate() error {
+	if c.CoAuthor != "" && c.CoAuthor != CoAuthorNone && !identityRegex.MatchString(c.CoAuthor) {
+		return fmt.Errorf("co-author must be \"Name <email>\" or %q (got %q)", CoAuthorNone, c.CoAuthor)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c CommitAttributionConfig) String() string {
+	return fmt.Sprintf("CommitAttributionConfig{CoAuthor: %q, AgentTrailers: %v}", c.CoAuthor, c.AgentTrailers)
+}
+
+// CommitAttributionConfigFromEnv creates a CommitAttributionConfig from
+// environment variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_COMMIT_CO_AUTHOR: Co-Authored-By identity, "Name <email>", or "none" (default: the agent's)
+//   - VC_COMMIT_AGENT_TRAILERS: Add VC-Agent and VC-Execution trailers (default: true)
+//
+// Returns an error if any environment variable has an invalid value.
+func CommitAttributionConfigFromEnv() (CommitAttributionConfig, error) {
+	cfg := DefaultCommitAttributionConfig()
+
+	parseEnvString("VC_COMMIT_CO_AUTHOR", &cfg.CoAuthor)
+	if err := parseEnvBool("VC_COMMIT_AGENT_TRAILERS", &cfg.AgentTrailers); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid commit attribution configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
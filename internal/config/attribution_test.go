@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCommitAttributionConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    CommitAttributionConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    CommitAttributionConfig{AgentTrailers: true},
+		},
+		{
+			name: "custom co-author",
+			envVars: map[string]string{
+				"VC_COMMIT_CO_AUTHOR": "vc-bot <vc-bot@example.com>",
+			},
+			want: CommitAttributionConfig{CoAuthor: "vc-bot <vc-bot@example.com>", AgentTrailers: true},
+		},
+		{
+			name: "no attribution",
+			envVars: map[string]string{
+				"VC_COMMIT_CO_AUTHOR":      "none",
+				"VC_COMMIT_AGENT_TRAILERS": "false",
+			},
+			want: CommitAttributionConfig{CoAuthor: CoAuthorNone},
+		},
+		{
+			name: "co-author without email",
+			envVars: map[string]string{
+				"VC_COMMIT_CO_AUTHOR": "vc-bot",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bool value",
+			envVars: map[string]string{
+				"VC_COMMIT_AGENT_TRAILERS": "maybe",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_COMMIT_CO_AUTHOR",
+				"VC_COMMIT_AGENT_TRAILERS",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := CommitAttributionConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CommitAttributionConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
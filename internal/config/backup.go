@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// BackupConfig holds configuration for scheduled automatic database backups
+// taken while the executor runs
+type BackupConfig struct {
+	// Enabled turns on scheduled backups
+	// Default: false (opt-in)
+	Enabled bool
+
+	// IntervalHours is how often to take a backup (in hours)
+	// Default: 24, Range: 1-720 (1 hour - 30 days)
+	IntervalHours int
+
+	// Keep is how many scheduled backups to retain; older ones are deleted
+	// Default: 7, Range: 0-1000
+	// 0 = keep all backups
+	Keep int
+
+	// Dir is the directory backups are written to
+	// Default: "" (use .beads/backups next to the database)
+	Dir string
+}
+
+// DefaultBackupConfig returns the default backup configuration
+//
+// Scheduled backups are off by default. When enabled, the defaults keep a
+// week of daily backups.
+func DefaultBackupConfig() BackupConfig {
+	return BackupConfig{
+		Enabled:       false,
+		IntervalHours: 24,
+		Keep:          7,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c BackupConfig) Validate() error {
+	if c.IntervalHours < 1 || c.IntervalHours > 720 {
+		return fmt.Errorf("interval_hours must be between 1 and 720 (got %d)", c.IntervalHours)
+	}
+	if c.Keep < 0 || c.Keep > 1000 {
+		return fmt.Errorf("keep must be between 0 and 1000 (got %d)", c.Keep)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c BackupConfig) String() string {
+	return fmt.Sprintf(
+		"BackupConfig{Enabled: %v, IntervalHours: %d, Keep: %d, Dir: %q}",
+		c.Enabled, c.IntervalHours, c.Keep, c.Dir,
+	)
+}
+
+// Interval returns the backup interval as a time.Duration
+func (c BackupConfig) Interval() time.Duration {
+	return time.Duration(c.IntervalHours) * time.Hour
+}
+
+// BackupConfigFromEnv creates a BackupConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_BACKUP_ENABLED: Enable scheduled backups (default: false)
+//   - VC_BACKUP_INTERVAL_HOURS: Hours between backups (default: 24)
+//   - VC_BACKUP_KEEP: Number of backups to retain, 0 = all (default: 7)
+//   - VC_BACKUP_DIR: Backup directory (default: .beads/backups)
+//
+// Returns an error if any environment variable has an invalid value.
+func BackupConfigFromEnv() (BackupConfig, error) {
+	cfg := DefaultBackupConfig()
+
+	if err := parseEnvBool("VC_BACKUP_ENABLED", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_BACKUP_INTERVAL_HOURS", &cfg.IntervalHours); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_BACKUP_KEEP", &cfg.Keep); err != nil {
+		return cfg, err
+	}
+	parseEnvString("VC_BACKUP_DIR", &cfg.Dir)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid backup configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestBackupConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg BackupConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			wantErr: false,
+			check: func(t *testing.T, cfg BackupConfig) {
+				defaults := DefaultBackupConfig()
+				if cfg != defaults {
+					t.Errorf("cfg = %v, want %v", cfg, defaults)
+				}
+				if cfg.Enabled {
+					t.Errorf("Enabled = true, want scheduled backups off by default")
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_BACKUP_ENABLED":        "true",
+				"VC_BACKUP_INTERVAL_HOURS": "6",
+				"VC_BACKUP_KEEP":           "20",
+				"VC_BACKUP_DIR":            "/var/backups/vc",
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg BackupConfig) {
+				if !cfg.Enabled {
+					t.Errorf("Enabled = false, want true")
+				}
+				if cfg.IntervalHours != 6 {
+					t.Errorf("IntervalHours = %v, want 6", cfg.IntervalHours)
+				}
+				if cfg.Interval() != 6*time.Hour {
+					t.Errorf("Interval() = %v, want 6h", cfg.Interval())
+				}
+				if cfg.Keep != 20 {
+					t.Errorf("Keep = %v, want 20", cfg.Keep)
+				}
+				if cfg.Dir != "/var/backups/vc" {
+					t.Errorf("Dir = %q, want /var/backups/vc", cfg.Dir)
+				}
+			},
+		},
+		{
+			name: "keep all backups (zero value)",
+			envVars: map[string]string{
+				"VC_BACKUP_KEEP": "0",
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg BackupConfig) {
+				if cfg.Keep != 0 {
+					t.Errorf("Keep = %v, want 0 (keep all)", cfg.Keep)
+				}
+			},
+		},
+		{
+			name: "invalid bool value",
+			envVars: map[string]string{
+				"VC_BACKUP_ENABLED": "sometimes",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid int value",
+			envVars: map[string]string{
+				"VC_BACKUP_INTERVAL_HOURS": "daily",
+			},
+			wantErr: true,
+		},
+		{
+			name: "interval too low",
+			envVars: map[string]string{
+				"VC_BACKUP_INTERVAL_HOURS": "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "interval too high",
+			envVars: map[string]string{
+				"VC_BACKUP_INTERVAL_HOURS": "1000",
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative keep",
+			envVars: map[string]string{
+				"VC_BACKUP_KEEP": "-1",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_BACKUP_ENABLED",
+				"VC_BACKUP_INTERVAL_HOURS",
+				"VC_BACKUP_KEEP",
+				"VC_BACKUP_DIR",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := BackupConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BackupConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
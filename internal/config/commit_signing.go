@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commit signing formats
+const (
+	SigningGPG = "gpg"
+	SigningSSH = "ssh"
+)
+
+// CommitSigningConfig configures how auto-commits are signed and who they
+// are committed as, so autonomous commits can be verified and told apart
+// from human ones in the history
+type CommitSigningConfig struct {
+	// Format signs auto-commits with a GPG or SSH key: "gpg" or "ssh"
+	// Default: "" (don't sign)
+	Format string
+
+	// Key is the GPG key ID, or the SSH key (a public key file path, as
+	// git's user.signingkey expects)
+	// Default: "" (the repository's user.signingkey, so it can be set per repo)
+	Key string
+
+	// CommitterName and CommitterEmail set a distinct committer identity,
+	// e.g. "vc-bot", leaving the author as configured
+	// Default: "" (git's configured identity)
+	CommitterName  string
+	CommitterEmail string
+}
+
+// DefaultCommitSigningConfig returns the default commit signing configuration
+//
+// Signing is off by default and commits use git's configured identity.
+func DefaultCommitSigningConfig() CommitSigningConfig {
+	return CommitSigningConfig{}
+}
+
+// Enabled reports whether auto-commits are signed
+func (c CommitSigningConfig) Enabled() bool {
+	return c.Format != ""
+}
+
+// Validate checks if the configuration has valid values
+func (c CommitSigningConfig) Validate() error {
+	switch c.Format {
+	case "", SigningGPG, SigningSSH:
+	default:
+		return fmt.Errorf("signing format must be %q or %q (got %q)", SigningGPG, SigningSSH, c.Format)
+	}
+	if c.Key != "" && c.Format == "" {
+		return fmt.Errorf("a signing key requires a signing format")
+	}
+	if (c.CommitterName == "") != (c.CommitterEmail == "") {
+		return fmt.Errorf("committer name and email must be set together")
+	}
+	if strings.ContainsAny(c.CommitterName+c.CommitterEmail, "<>\n") {
+		return fmt.Errorf("committer name and email must not contain '<', '>' or newlines")
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c CommitSigningConfig) String() string {
+	return fmt.Sprintf(
+		"CommitSigningConfig{Format: %q, Key: %q, CommitterName: %q, CommitterEmail: %q}",
+		c.Format, c.Key, c.CommitterName, c.CommitterEmail,
+	)
+}
+
+// CommitSigningConfigFromEnv creates a CommitSigningConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_COMMIT_SIGNING: Sign auto-commits, "gpg" or "ssh" (default: don't sign)
+//   - VC_COMMIT_SIGNING_KEY: Key to sign with (default: the repo's user.signingkey)
+//   - VC_COMMITTER_NAME: Committer name for auto-commits, e.g. vc-bot
+//   - VC_COMMITTER_EMAIL: Committer email for auto-commits
+//
+// Returns an error if any environment variable has an invalid value.
+func CommitSigningConfigFromEnv() (CommitSigningConfig, error) {
+	cfg := DefaultCommitSigningConfig()
+
+	parseEnvString("VC_COMMIT_SIGNING", &cfg.Format)
+	cfg.Format = strings.ToLower(cfg.Format)
+	parseEnvString("VC_COMMIT_SIGNING_KEY", &cfg.Key)
+	parseEnvString("VC_COMMITTER_NAME", &cfg.CommitterName)
+	parseEnvString("VC_COMMITTER_EMAIL", &cfg.CommitterEmail)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid commit signing configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCommitSigningConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg CommitSigningConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			wantErr: false,
+			check: func(t *testing.T, cfg CommitSigningConfig) {
+				if cfg != DefaultCommitSigningConfig() {
+					t.Errorf("cfg = %v, want defaults", cfg)
+				}
+				if cfg.Enabled() {
+					t.Errorf("Enabled() = true, want signing off by default")
+				}
+			},
+		},
+		{
+			name: "SSH signing as vc-bot",
+			envVars: map[string]string{
+				"VC_COMMIT_SIGNING":     "SSH",
+				"VC_COMMIT_SIGNING_KEY": "/home/vc/.ssh/id_ed25519.pub",
+				"VC_COMMITTER_NAME":     "vc-bot",
+				"VC_COMMITTER_EMAIL":    "vc-bot@example.com",
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg CommitSigningConfig) {
+				want := CommitSigningConfig{Format: SigningSSH, Key: "/home/vc/.ssh/id_ed25519.pub",
+					CommitterName: "vc-bot", CommitterEmail: "vc-bot@example.com"}
+				if cfg != want {
+					t.Errorf("cfg = %v, want %v", cfg, want)
+				}
+			},
+		},
+		{
+			name: "GPG signing with the repository's key",
+			envVars: map[string]string{
+				"VC_COMMIT_SIGNING": "gpg",
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg CommitSigningConfig) {
+				if !cfg.Enabled() || cfg.Format != SigningGPG || cfg.Key != "" {
+					t.Errorf("cfg = %v, want GPG signing with no key", cfg)
+				}
+			},
+		},
+		{
+			name: "unknown format",
+			envVars: map[string]string{
+				"VC_COMMIT_SIGNING": "x509",
+			},
+			wantErr: true,
+		},
+		{
+			name: "key without format",
+			envVars: map[string]string{
+				"VC_COMMIT_SIGNING_KEY": "ABCDEF01",
+			},
+			wantErr: true,
+		},
+		{
+			name: "committer name without email",
+			envVars: map[string]string{
+				"VC_COMMITTER_NAME": "vc-bot",
+			},
+			wantErr: true,
+		},
+		{
+			name: "committer email with angle brackets",
+			envVars: map[string]string{
+				"VC_COMMITTER_NAME":  "vc-bot",
+				"VC_COMMITTER_EMAIL": "<vc-bot@example.com>",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_COMMIT_SIGNING",
+				"VC_COMMIT_SIGNING_KEY",
+				"VC_COMMITTER_NAME",
+				"VC_COMMITTER_EMAIL",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := CommitSigningConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CommitSigningConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// DaemonConfig configures the work loop run by 'vc daemon'
+type DaemonConfig struct {
+	// Workers is how many issues are worked on at once, each by its own
+	// executor instance in its own sandbox
+	// Default: 1, Range: 1-32
+	Workers int
+
+	// IdleBackoff is the longest poll interval to back off to while the
+	// ready queue is empty
+	// Default: 5m, 0 = poll at the executor's poll interval
+	IdleBackoff time.Duration
+}
+
+// DefaultDaemonConfig returns the default daemon configuration
+//
+// One worker, backing off to polling every five minutes while there is no
+// ready work.
+func DefaultDaemonConfig() DaemonConfig {
+	return DaemonConfig{
+		Workers:     1,
+		IdleBackoff: 5 * time.Minute,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c DaemonConfig) Validate() error {
+	if c.Workers < 1 || c.Workers > 32 {
+		return fmt.Errorf("workers must be between 1 and 32 (got %d)", c.Workers)
+	}
+	if c.IdleBackoff < 0 {
+		return fmt.Errorf("idle backoff can't be negative (got %v)", c.IdleBackoff)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c DaemonConfig) String() string {
+	return fmt.Sprintf("DaemonConfig{Workers: %d, IdleBackoff: %v}", c.Workers, c.IdleBackoff)
+}
+
+// DaemonConfigFromEnv creates a DaemonConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_DAEMON_WORKERS: Issues worked on at once (default: 1)
+//   - VC_DAEMON_IDLE_BACKOFF: Longest poll interval while the queue is empty, e.g. 10m (default: 5m)
+//
+// Returns an error if any environment variable has an invalid value.
+func DaemonConfigFromEnv() (DaemonConfig, error) {
+	cfg := DefaultDaemonConfig()
+
+	if err := parseEnvInt("VC_DAEMON_WORKERS", &cfg.Workers); err != nil {
+		return cfg, err
+	}
+	if value := os.Getenv("VC_DAEMON_IDLE_BACKOFF"); value != "" {
+		backoff, err := time.ParseDuration(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid value for VC_DAEMON_IDLE_BACKOFF: %w", err)
+		}
+		cfg.IdleBackoff = backoff
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid daemon configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
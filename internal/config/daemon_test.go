@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDaemonConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    DaemonConfig
+	}{
+		{
+			name:    "defaults",
+			envVars: map[string]string{},
+			want:    DaemonConfig{Workers: 1, IdleBackoff: 5 * time.Minute},
+		},
+		{
+			name: "custom values",
+			envVars: map[string]string{
+				"VC_DAEMON_WORKERS":      "4",
+				"VC_DAEMON_IDLE_BACKOFF": "10m",
+			},
+			want: DaemonConfig{Workers: 4, IdleBackoff: 10 * time.Minute},
+		},
+		{
+			name:    "backoff disabled",
+			envVars: map[string]string{"VC_DAEMON_IDLE_BACKOFF": "0"},
+			want:    DaemonConfig{Workers: 1},
+		},
+		{
+			name:    "no workers",
+			envVars: map[string]string{"VC_DAEMON_WORKERS": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "too many workers",
+			envVars: map[string]string{"VC_DAEMON_WORKERS": "33"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid backoff",
+			envVars: map[string]string{"VC_DAEMON_IDLE_BACKOFF": "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "negative backoff",
+			envVars: map[string]string{"VC_DAEMON_IDLE_BACKOFF": "-1m"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VC_DAEMON_WORKERS", "")
+			t.Setenv("VC_DAEMON_IDLE_BACKOFF", "")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := DaemonConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DaemonConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DependencyCheckConfig configures the dependency graph checker, which looks
+// for cycles and mutually-blocked clusters of issues that can never become
+// ready and files a diagnostic issue for each
+type DependencyCheckConfig struct {
+	// Enabled turns the dependency graph checker on
+	// Default: true
+	Enabled bool
+
+	// IntervalMinutes is how often the graph is checked as a maintenance job
+	// Default: 360, Range: 5-10080
+	IntervalMinutes int
+
+	// IdleIntervalMinutes is how often an executor that finds no ready work
+	// checks the graph, since a knot is a common reason there is none
+	// Default: 10, Range: 1-1440
+	IdleIntervalMinutes int
+}
+
+// DefaultDependencyCheckConfig returns the default dependency check
+// configuration
+func DefaultDependencyCheckConfig() DependencyCheckConfig {
+	return DependencyCheckConfig{
+		Enabled:             true,
+		IntervalMinutes:     360,
+		IdleIntervalMinutes: 10,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c DependencyCheckConfig) Validate() error {
+	if c.IntervalMinutes < 5 || c.IntervalMinutes > 10080 {
+		return fmt.Errorf("interval_minutes must be between 5 and 10080 (got %d)", c.IntervalMinutes)
+	}
+	if c.IdleIntervalMinutes < 1 || c.IdleIntervalMinutes > 1440 {
+		return fmt.Errorf("idle_interval_minutes must be between 1 and 1440 (got %d)", c.IdleIntervalMinutes)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c DependencyCheckConfig) String() string {
+	return fmt.Sprintf("DependencyCheckConfig{Enabled: %v, IntervalMinutes: %d, IdleIntervalMinutes: %d}",
+		c.Enabled, c.IntervalMinutes, c.IdleIntervalMinutes)
+}
+
+// Interval returns the maintenance check interval as a time.Duration
+func (c DependencyCheckConfig) Interval() time.Duration {
+	return time.Duration(c.IntervalMinutes) * time.Minute
+}
+
+// IdleInterval returns how often idle executors check as a time.Duration
+func (c DependencyCheckConfig) IdleInterval() time.Duration {
+	return time.Duration(c.IdleIntervalMinutes) * time.Minute
+}
+
+// DependencyCheckConfigFromEnv creates a DependencyCheckConfig from
+// environment variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_DEPENDENCY_CHECK_ENABLED: Look for dependency cycles and knots (default: true)
+//   - VC_DEPENDENCY_CHECK_INTERVAL_MINUTES: Minutes between maintenance checks (default: 360)
+//   - VC_DEPENDENCY_CHECK_IDLE_INTERVAL_MINUTES: Minutes between checks by idle executors (default: 10)
+//
+// Returns an error if any environment variable has an invalid value.
+func DependencyCheckConfigFromEnv() (DependencyCheckConfig, error) {
+	cfg := DefaultDependencyCheckConfig()
+
+	if err := parseEnvBool("VC_DEPENDENCY_CHECK_ENABLED", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_DEPENDENCY_CHECK_INTERVAL_MINUTES", &cfg.IntervalMinutes); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_DEPENDENCY_CHECK_IDLE_INTERVAL_MINUTES", &cfg.IdleIntervalMinutes); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid dependency check configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
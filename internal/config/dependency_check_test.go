@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDependencyCheckConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg DependencyCheckConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg DependencyCheckConfig) {
+				if cfg != DefaultDependencyCheckConfig() {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultDependencyCheckConfig())
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_DEPENDENCY_CHECK_ENABLED":               "false",
+				"VC_DEPENDENCY_CHECK_INTERVAL_MINUTES":      "60",
+				"VC_DEPENDENCY_CHECK_IDLE_INTERVAL_MINUTES": "2",
+			},
+			check: func(t *testing.T, cfg DependencyCheckConfig) {
+				if cfg.Enabled || cfg.Interval() != time.Hour || cfg.IdleInterval() != 2*time.Minute {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+			},
+		},
+		{
+			name:    "interval too short",
+			envVars: map[string]string{"VC_DEPENDENCY_CHECK_INTERVAL_MINUTES": "1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid idle interval",
+			envVars: map[string]string{"VC_DEPENDENCY_CHECK_IDLE_INTERVAL_MINUTES": "often"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_DEPENDENCY_CHECK_ENABLED", "VC_DEPENDENCY_CHECK_INTERVAL_MINUTES", "VC_DEPENDENCY_CHECK_IDLE_INTERVAL_MINUTES"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := DependencyCheckConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DependencyCheckConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
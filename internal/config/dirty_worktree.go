@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+)
+
+// What the executor does when the working directory has uncommitted changes
+// before an agent starts
+const (
+	DirtyWorktreeRefuse  = "refuse"   // Don't run the agent; the issue is released
+	DirtyWorktreeStash   = "stash"    // Stash the changes and restore them afterwards
+	DirtyWorktreeIsolate = "worktree" // Run the agent in a separate worktree
+	DirtyWorktreeIgnore  = "ignore"   // Run the agent on top of the changes
+)
+
+// DirtyWorktreeConfig configures how the executor protects uncommitted human
+// changes in the working directory, so an agent's edits (and auto-commits)
+// never mix with a developer's work in progress. It only applies outside
+// sandboxes, which are isolated already.
+type DirtyWorktreeConfig struct {
+	// Policy is "refuse", "stash", "worktree" or "ignore"
+	// Default: "stash"
+	Policy string
+}
+
+// DefaultDirtyWorktreeConfig returns the default dirty worktree configuration
+//
+// Uncommitted changes are stashed while the agent runs and restored after.
+func DefaultDirtyWorktreeConfig() DirtyWorktreeConfig {
+	return DirtyWorktreeConfig{
+		Policy: DirtyWorktreeStash,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c DirtyWorktreeConfig) Validate() error {
+	switch c.Policy {
+	case DirtyWorktreeRefuse, DirtyWorktreeStash, DirtyWorktreeIsolate, DirtyWorktreeIgnore:
+		return nil
+	}
+	return fmt.Errorf("policy must be %q, %q, %q or %q (got %q)",
+		DirtyWorktreeRefuse, DirtyWorktreeStash, DirtyWorktreeIsolate, DirtyWorktreeIgnore, c.Policy)
+}
+
+// String returns a human-readable representation of the config
+func (c DirtyWorktreeConfig) String() string {
+	return fmt.Sprintf("DirtyWorktreeConfig{Policy: %s}", c.Policy)
+}
+
+// DirtyWorktreeConfigFromEnv creates a DirtyWorktreeConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_DIRTY_WORKTREE: refuse, stash, worktree or ignore (default: stash)
+//
+// Returns an error if any environment variable has an invalid value.
+func DirtyWorktreeConfigFromEnv() (DirtyWorktreeConfig, error) {
+	cfg := DefaultDirtyWorktreeConfig()
+
+	parseEnvString("VC_DIRTY_WORKTREE", &cfg.Policy)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid dirty worktree configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDirtyWorktreeConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    DirtyWorktreeConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultDirtyWorktreeConfig(),
+		},
+		{
+			name: "refuse",
+			envVars: map[string]string{
+				"VC_DIRTY_WORKTREE": "refuse",
+			},
+			want: DirtyWorktreeConfig{Policy: DirtyWorktreeRefuse},
+		},
+		{
+			name: "worktree",
+			envVars: map[string]string{
+				"VC_DIRTY_WORKTREE": "worktree",
+			},
+			want: DirtyWorktreeConfig{Policy: DirtyWorktreeIsolate},
+		},
+		{
+			name: "invalid policy",
+			envVars: map[string]string{
+				"VC_DIRTY_WORKTREE": "discard",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_DIRTY_WORKTREE",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := DirtyWorktreeConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DirtyWorktreeConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
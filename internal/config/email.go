@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// EmailConfig configures email notifications sent over SMTP: immediate
+// alerts for escalations and blocked P0 issues, and a daily digest of
+// executions, costs and newly discovered issues
+type EmailConfig struct {
+	// SMTPHost is the mail server. Email is disabled when empty.
+	// Default: ""
+	SMTPHost string
+
+	// SMTPPort is the mail server's submission port. STARTTLS is used when
+	// the server offers it.
+	// Default: 587, Range: 1-65535
+	SMTPPort int
+
+	// Username and Password authenticate with the mail server (PLAIN auth).
+	// No authentication is attempted when Username is empty.
+	// Default: ""
+	Username string
+	Password string
+
+	// From is the sender address. Required when SMTPHost is set.
+	// Default: ""
+	From string
+
+	// To lists the recipients. Required when SMTPHost is set.
+	// Default: none
+	To []string
+
+	// Alerts sends an email as soon as an issue is escalated or a P0 issue
+	// is blocked
+	// Default: true
+	Alerts bool
+
+	// Digest sends a daily summary email
+	// Default: true
+	Digest bool
+
+	// DigestHour is the local hour the daily digest is sent at
+	// Default: 8, Range: 0-23
+	DigestHour int
+
+	// PollIntervalSeconds is how often the database is checked for alerts
+	// Default: 30, Range: 1-3600
+	PollIntervalSeconds int
+}
+
+// DefaultEmailConfig returns the default email configuration
+func DefaultEmailConfig() EmailConfig {
+	return EmailConfig{
+		SMTPPort:            587,
+		Alerts:              true,
+		Digest:              true,
+		DigestHour:          8,
+		PollIntervalSeconds: 30,
+	}
+}
+
+// Enabled reports whether a mail server is configured
+func (c EmailConfig) Enabled() bool {
+	return c.SMTPHost != ""
+}
+
+// Validate checks if the configuration has valid values
+func (c EmailConfig) Validate() error {
+	if c.SMTPPort < 1 || c.SMTPPort > 65535 {
+		return fmt.Errorf("smtp_port must be between 1 and 65535 (got %d)", c.SMTPPort)
+	}
+	if c.DigestHour < 0 || c.DigestHour > 23 {
+		return fmt.Errorf("digest_hour must be between 0 and 23 (got %d)", c.DigestHour)
+	}
+	if c.PollIntervalSeconds < 1 || c.PollIntervalSeconds > 3600 {
+		return fmt.Errorf("poll_interval_seconds must be between 1 and 3600 (got %d)", c.PollIntervalSeconds)
+	}
+	if c.SMTPHost == "" {
+		return nil
+	}
+	if c.From == "" {
+		return fmt.Errorf("email sender is required (VC_EMAIL_FROM)")
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("invalid email sender %q: %w", c.From, err)
+	}
+	if len(c.To) == 0 {
+		return fmt.Errorf("at least one email recipient is required (VC_EMAIL_TO)")
+	}
+	for _, to := range c.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid email recipient %q: %w", to, err)
+		}
+	}
+	if c.Username != "" && c.Password == "" {
+		return fmt.Errorf("SMTP password is required with a username (VC_SMTP_PASSWORD)")
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config. The
+// password is never included.
+func (c EmailConfig) String() string {
+	return fmt.Sprintf("EmailConfig{SMTPHost: %q, SMTPPort: %d, Username: %q, Password: %v, From: %q, To: %v, Alerts: %v, Digest: %v, DigestHour: %d, PollIntervalSeconds: %d}",
+		c.SMTPHost, c.SMTPPort, c.Username, c.Password != "", c.From, c.To, c.Alerts, c.Digest, c.DigestHour, c.PollIntervalSeconds)
+}
+
+// Addr returns the mail server address as host:port
+func (c EmailConfig) Addr() string {
+	return fmt.Sprintf("%s:%d", c.SMTPHost, c.SMTPPort)
+}
+
+// PollInterval returns the poll interval as a time.Duration
+func (c EmailConfig) PollInterval() time.Duration {
+	return time.Duration(c.PollIntervalSeconds) * time.Second
+}
+
+// EmailConfigFromEnv creates an EmailConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_SMTP_HOST: Mail server host (default: disabled)
+//   - VC_SMTP_PORT: Mail server port (default: 587)
+//   - VC_SMTP_USERNAME: SMTP username (default: no authentication)
+//   - VC_SMTP_PASSWORD: SMTP password (required with a username)
+//   - VC_EMAIL_FROM: Sender address (required with a host)
+//   - VC_EMAIL_TO: Comma-separated recipient addresses (required with a host)
+//   - VC_EMAIL_ALERTS: Email escalations and blocked P0 issues (default: true)
+//   - VC_EMAIL_DIGEST: Send a daily digest (default: true)
+//   - VC_EMAIL_DIGEST_HOUR: Local hour to send the digest at (default: 8)
+//   - VC_EMAIL_POLL_INTERVAL_SECONDS: Seconds between checks for alerts (default: 30)
+//
+// Returns an error if any environment variable has an invalid value.
+func EmailConfigFromEnv() (EmailConfig, error) {
+	cfg := DefaultEmailConfig()
+
+	parseEnvString("VC_SMTP_HOST", &cfg.SMTPHost)
+	if err := parseEnvInt("VC_SMTP_PORT", &cfg.SMTPPort); err != nil {
+		return cfg, err
+	}
+	parseEnvString("VC_SMTP_USERNAME", &cfg.Username)
+	parseEnvString("VC_SMTP_PASSWORD", &cfg.Password)
+	parseEnvString("VC_EMAIL_FROM", &cfg.From)
+	var to string
+	parseEnvString("VC_EMAIL_TO", &to)
+	for _, addr := range strings.Split(to, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			cfg.To = append(cfg.To, addr)
+		}
+	}
+	if err := parseEnvBool("VC_EMAIL_ALERTS", &cfg.Alerts); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_EMAIL_DIGEST", &cfg.Digest); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_EMAIL_DIGEST_HOUR", &cfg.DigestHour); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_EMAIL_POLL_INTERVAL_SECONDS", &cfg.PollIntervalSeconds); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid email configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg EmailConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg EmailConfig) {
+				if !reflect.DeepEqual(cfg, DefaultEmailConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultEmailConfig())
+				}
+				if cfg.Enabled() {
+					t.Error("Enabled() = true without an SMTP host")
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_SMTP_HOST":                   "smtp.example.com",
+				"VC_SMTP_PORT":                   "2525",
+				"VC_SMTP_USERNAME":               "vc",
+				"VC_SMTP_PASSWORD":               "s3cret",
+				"VC_EMAIL_FROM":                  "VC <vc@example.com>",
+				"VC_EMAIL_TO":                    "alice@example.com, bob@example.com ,",
+				"VC_EMAIL_ALERTS":                "false",
+				"VC_EMAIL_DIGEST_HOUR":           "17",
+				"VC_EMAIL_POLL_INTERVAL_SECONDS": "60",
+			},
+			check: func(t *testing.T, cfg EmailConfig) {
+				if !cfg.Enabled() || cfg.Addr() != "smtp.example.com:2525" || cfg.Alerts || !cfg.Digest || cfg.DigestHour != 17 {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if !reflect.DeepEqual(cfg.To, []string{"alice@example.com", "bob@example.com"}) {
+					t.Errorf("To = %q, want [alice@example.com bob@example.com]", cfg.To)
+				}
+				if cfg.PollInterval() != time.Minute {
+					t.Errorf("PollInterval() = %v, want 1m", cfg.PollInterval())
+				}
+				if strings.Contains(cfg.String(), "s3cret") {
+					t.Error("String() must not include the password")
+				}
+			},
+		},
+		{
+			name:    "host without recipients",
+			envVars: map[string]string{"VC_SMTP_HOST": "smtp.example.com", "VC_EMAIL_FROM": "vc@example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "host without sender",
+			envVars: map[string]string{"VC_SMTP_HOST": "smtp.example.com", "VC_EMAIL_TO": "alice@example.com"},
+			wantErr: true,
+		},
+		{
+			name: "invalid recipient",
+			envVars: map[string]string{"VC_SMTP_HOST": "smtp.example.com", "VC_EMAIL_FROM": "vc@example.com",
+				"VC_EMAIL_TO": "alice"},
+			wantErr: true,
+		},
+		{
+			name: "username without password",
+			envVars: map[string]string{"VC_SMTP_HOST": "smtp.example.com", "VC_EMAIL_FROM": "vc@example.com",
+				"VC_EMAIL_TO": "alice@example.com", "VC_SMTP_USERNAME": "vc"},
+			wantErr: true,
+		},
+		{
+			name:    "digest hour out of range",
+			envVars: map[string]string{"VC_EMAIL_DIGEST_HOUR": "24"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid alerts flag",
+			envVars: map[string]string{"VC_EMAIL_ALERTS": "sometimes"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_SMTP_HOST", "VC_SMTP_PORT", "VC_SMTP_USERNAME", "VC_SMTP_PASSWORD", "VC_EMAIL_FROM",
+				"VC_EMAIL_TO", "VC_EMAIL_ALERTS", "VC_EMAIL_DIGEST", "VC_EMAIL_DIGEST_HOUR", "VC_EMAIL_POLL_INTERVAL_SECONDS"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := EmailConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EmailConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrorTrackerConfig configures error tracker intake, which receives error
+// events from Sentry (or any tracker through a generic webhook), groups
+// them and files a bug issue for each group
+type ErrorTrackerConfig struct {
+	// Secret verifies that webhooks come from the tracker: the client
+	// secret of a Sentry internal integration, or the key generic webhooks
+	// are signed with. Intake is disabled when empty.
+	// Default: ""
+	Secret string
+
+	// Addr is the host:port the webhook endpoints listen on; the tracker
+	// must reach /errors/sentry or /errors/webhook, e.g. through a reverse
+	// proxy
+	// Default: "127.0.0.1:7392"
+	Addr string
+
+	// FlushIntervalSeconds is how often grouped events are turned into
+	// issues
+	// Default: 60, Range: 5-86400
+	FlushIntervalSeconds int
+
+	// MinEvents is how many events a group needs before an issue is filed
+	// for it
+	// Default: 1, Range: 1-100000
+	MinEvents int
+
+	// Environments limits intake to events from these environments (e.g.
+	// production); events without an environment are always taken
+	// Default: none (every environment)
+	Environments []string
+}
+
+// DefaultErrorTrackerConfig returns the default error tracker configuration
+func DefaultErrorTrackerConfig() ErrorTrackerConfig {
+	return ErrorTrackerConfig{
+		Addr:                 "127.0.0.1:7392",
+		FlushIntervalSeconds: 60,
+		MinEvents:            1,
+	}
+}
+
+// Enabled reports whether a webhook secret is configured
+func (c ErrorTrackerConfig) Enabled() bool {
+	return c.Secret != ""
+}
+
+// Validate checks if the configuration has valid values
+func (c ErrorTrackerConfig) Validate() error {
+	if c.FlushIntervalSeconds < 5 || c.FlushIntervalSeconds > 86400 {
+		return fmt.Errorf("flush_interval_seconds must be between 5 and 86400 (got %d)", c.FlushIntervalSeconds)
+	}
+	if c.MinEvents < 1 || c.MinEvents > 100000 {
+		return fmt.Errorf("min_events must be between 1 and 100000 (got %d)", c.MinEvents)
+	}
+	for _, env := range c.Environments {
+		if strings.TrimSpace(env) == "" {
+			return fmt.Errorf("environments cannot be empty")
+		}
+	}
+	if c.Secret != "" && c.Addr == "" {
+		return fmt.Errorf("webhook address is required (VC_ERROR_TRACKER_ADDR)")
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config. The secret
+// is never included.
+func (c ErrorTrackerConfig) String() string {
+	return fmt.Sprintf("ErrorTrackerConfig{Secret: %v, Addr: %q, FlushIntervalSeconds: %d, MinEvents: %d, Environments: %v}",
+		c.Secret != "", c.Addr, c.FlushIntervalSeconds, c.MinEvents, c.Environments)
+}
+
+// FlushInterval returns the flush interval as a time.Duration
+func (c ErrorTrackerConfig) FlushInterval() time.Duration {
+	return time.Duration(c.FlushIntervalSeconds) * time.Second
+}
+
+// TakesEnvironment reports whether events from env are taken in
+func (c ErrorTrackerConfig) TakesEnvironment(env string) bool {
+	if env == "" || len(c.Environments) == 0 {
+		return true
+	}
+	for _, allowed := range c.Environments {
+		if strings.EqualFold(allowed, env) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorTrackerConfigFromEnv creates an ErrorTrackerConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_ERROR_TRACKER_SECRET: Webhook signing secret (default: disabled)
+//   - VC_ERROR_TRACKER_ADDR: host:port of the webhook endpoints (default: 127.0.0.1:7392)
+//   - VC_ERROR_TRACKER_FLUSH_INTERVAL_SECONDS: Seconds between filing grouped events (default: 60)
+//   - VC_ERROR_TRACKER_MIN_EVENTS: Events a group needs before it's filed (default: 1)
+//   - VC_ERROR_TRACKER_ENVIRONMENTS: Comma-separated environments taken in (default: all)
+//
+// Returns an error if any environment variable has an invalid value.
+func ErrorTrackerConfigFromEnv() (ErrorTrackerConfig, error) {
+	cfg := DefaultErrorTrackerConfig()
+
+	parseEnvString("VC_ERROR_TRACKER_SECRET", &cfg.Secret)
+	parseEnvString("VC_ERROR_TRACKER_ADDR", &cfg.Addr)
+	if err := parseEnvInt("VC_ERROR_TRACKER_FLUSH_INTERVAL_SECONDS", &cfg.FlushIntervalSeconds); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_ERROR_TRACKER_MIN_EVENTS", &cfg.MinEvents); err != nil {
+		return cfg, err
+	}
+	var environments string
+	parseEnvString("VC_ERROR_TRACKER_ENVIRONMENTS", &environments)
+	for _, env := range strings.Split(environments, ",") {
+		if env = strings.TrimSpace(env); env != "" {
+			cfg.Environments = append(cfg.Environments, env)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid error tracker configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
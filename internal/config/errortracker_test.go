@@ -0,0 +1,92 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorTrackerConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg ErrorTrackerConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg ErrorTrackerConfig) {
+				if !reflect.DeepEqual(cfg, DefaultErrorTrackerConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultErrorTrackerConfig())
+				}
+				if cfg.Enabled() {
+					t.Error("Enabled() = true without a secret")
+				}
+				if !cfg.TakesEnvironment("staging") {
+					t.Error("TakesEnvironment() = false without environments")
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_ERROR_TRACKER_SECRET":                 "s3cret",
+				"VC_ERROR_TRACKER_ADDR":                   ":8080",
+				"VC_ERROR_TRACKER_FLUSH_INTERVAL_SECONDS": "300",
+				"VC_ERROR_TRACKER_MIN_EVENTS":             "5",
+				"VC_ERROR_TRACKER_ENVIRONMENTS":           "production, canary ,",
+			},
+			check: func(t *testing.T, cfg ErrorTrackerConfig) {
+				if !cfg.Enabled() || cfg.Addr != ":8080" || cfg.MinEvents != 5 || cfg.FlushInterval() != 5*time.Minute {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if !reflect.DeepEqual(cfg.Environments, []string{"production", "canary"}) {
+					t.Errorf("Environments = %q, want [production canary]", cfg.Environments)
+				}
+				if !cfg.TakesEnvironment("Production") || !cfg.TakesEnvironment("") || cfg.TakesEnvironment("staging") {
+					t.Errorf("TakesEnvironment() doesn't follow the environments %v", cfg.Environments)
+				}
+				if strings.Contains(cfg.String(), "s3cret") {
+					t.Errorf("String() must not include the secret: %s", cfg.String())
+				}
+			},
+		},
+		{
+			name:    "flush interval out of range",
+			envVars: map[string]string{"VC_ERROR_TRACKER_FLUSH_INTERVAL_SECONDS": "1"},
+			wantErr: true,
+		},
+		{
+			name:    "min events out of range",
+			envVars: map[string]string{"VC_ERROR_TRACKER_MIN_EVENTS": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid min events",
+			envVars: map[string]string{"VC_ERROR_TRACKER_MIN_EVENTS": "many"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_ERROR_TRACKER_SECRET", "VC_ERROR_TRACKER_ADDR", "VC_ERROR_TRACKER_FLUSH_INTERVAL_SECONDS",
+				"VC_ERROR_TRACKER_MIN_EVENTS", "VC_ERROR_TRACKER_ENVIRONMENTS"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := ErrorTrackerConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ErrorTrackerConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -31,6 +31,14 @@ type EventRetentionConfig struct {
 	// Default: 100000, Range: 1000-1000000
 	GlobalLimitEvents int
 
+	// IssueEventRetentionDays is how long issue events (comments, status
+	// changes, AI usage records) are kept once an issue is closed. Older
+	// events of issues closed longer ago are compacted into one summary
+	// comment; creation and close events are kept.
+	// Set to 0 to keep issue events forever
+	// Default: 90, Range: 0 or 7-3650
+	IssueEventRetentionDays int
+
 	// CleanupIntervalHours is how often to run cleanup (in hours)
 	// Default: 24, Range: 1-168 (1 week)
 	CleanupIntervalHours int
@@ -62,19 +70,21 @@ type EventRetentionConfig struct {
 // - Extend critical event retention for error analysis (90 days)
 // - Prevent runaway issues (1000 events per issue max)
 // - Cap total database size (100k events = ~50 MB)
+// - Compact issue history once an issue has been closed for 90 days
 // - Run cleanup daily during off-hours
 // - Use non-blocking cleanup (no VACUUM by default)
 func DefaultEventRetentionConfig() EventRetentionConfig {
 	return EventRetentionConfig{
-		RetentionDays:         30,
-		RetentionCriticalDays: 90,
-		PerIssueLimitEvents:   1000,
-		GlobalLimitEvents:     100000,
-		CleanupIntervalHours:  24,
-		CleanupBatchSize:      1000,
-		CleanupEnabled:        true,
-		CleanupStrategy:       "oldest_non_critical",
-		CleanupVacuum:         false,
+		RetentionDays:           30,
+		RetentionCriticalDays:   90,
+		PerIssueLimitEvents:     1000,
+		GlobalLimitEvents:       100000,
+		IssueEventRetentionDays: 90,
+		CleanupIntervalHours:    24,
+		CleanupBatchSize:        1000,
+		CleanupEnabled:          true,
+		CleanupStrategy:         "oldest_non_critical",
+		CleanupVacuum:           false,
 	}
 }
 
@@ -119,6 +129,20 @@ func (c EventRetentionConfig) Validate() error {
 			c.GlobalLimitEvents)
 	}
 
+	// Validate IssueEventRetentionDays (0 = keep forever, or 7-3650)
+	if c.IssueEventRetentionDays < 0 {
+		return fmt.Errorf("issue_event_retention_days cannot be negative (got %d)",
+			c.IssueEventRetentionDays)
+	}
+	if c.IssueEventRetentionDays > 0 && c.IssueEventRetentionDays < 7 {
+		return fmt.Errorf("issue_event_retention_days must be 0 (keep forever) or >= 7 (got %d)",
+			c.IssueEventRetentionDays)
+	}
+	if c.IssueEventRetentionDays > 3650 {
+		return fmt.Errorf("issue_event_retention_days too large (got %d, max 3650)",
+			c.IssueEventRetentionDays)
+	}
+
 	// Validate CleanupIntervalHours
 	if c.CleanupIntervalHours < 1 {
 		return fmt.Errorf("cleanup_interval_hours must be at least 1 (got %d)",
@@ -152,10 +176,10 @@ func (c EventRetentionConfig) Validate() error {
 func (c EventRetentionConfig) String() string {
 	return fmt.Sprintf(
 		"EventRetentionConfig{RetentionDays: %d, RetentionCriticalDays: %d, "+
-			"PerIssueLimit: %d, GlobalLimit: %d, CleanupInterval: %dh, "+
+			"PerIssueLimit: %d, GlobalLimit: %d, IssueEventRetention: %dd, CleanupInterval: %dh, "+
 			"BatchSize: %d, Enabled: %t, Strategy: %s, Vacuum: %t}",
 		c.RetentionDays, c.RetentionCriticalDays, c.PerIssueLimitEvents,
-		c.GlobalLimitEvents, c.CleanupIntervalHours, c.CleanupBatchSize,
+		c.GlobalLimitEvents, c.IssueEventRetentionDays, c.CleanupIntervalHours, c.CleanupBatchSize,
 		c.CleanupEnabled, c.CleanupStrategy, c.CleanupVacuum,
 	)
 }
@@ -168,6 +192,7 @@ func (c EventRetentionConfig) String() string {
 //   - VC_EVENT_RETENTION_CRITICAL_DAYS: Retention period for critical events in days (default: 90)
 //   - VC_EVENT_PER_ISSUE_LIMIT: Maximum events per issue, 0 for unlimited (default: 1000)
 //   - VC_EVENT_GLOBAL_LIMIT: Maximum total events (default: 100000)
+//   - VC_EVENT_ISSUE_RETENTION_DAYS: Days to keep closed issues' events before compacting, 0 to keep forever (default: 90)
 //   - VC_EVENT_CLEANUP_INTERVAL_HOURS: How often to run cleanup in hours (default: 24)
 //   - VC_EVENT_CLEANUP_BATCH_SIZE: Events to delete per transaction (default: 1000)
 //   - VC_EVENT_CLEANUP_ENABLED: Enable automatic cleanup (default: true)
@@ -191,6 +216,9 @@ func EventRetentionConfigFromEnv() (EventRetentionConfig, error) {
 	if err := parseEnvInt("VC_EVENT_GLOBAL_LIMIT", &cfg.GlobalLimitEvents); err != nil {
 		return cfg, err
 	}
+	if err := parseEnvInt("VC_EVENT_ISSUE_RETENTION_DAYS", &cfg.IssueEventRetentionDays); err != nil {
+		return cfg, err
+	}
 	if err := parseEnvInt("VC_EVENT_CLEANUP_INTERVAL_HOURS", &cfg.CleanupIntervalHours); err != nil {
 		return cfg, err
 	}
@@ -227,6 +255,20 @@ func parseEnvInt(key string, dest *int) error {
 	return nil
 }
 
+// parseEnvFloat parses a float64 from an environment variable
+func parseEnvFloat(key string, dest *float64) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil // Use default
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	*dest = parsed
+	return nil
+}
+
 // parseEnvBool parses a bool from an environment variable
 func parseEnvBool(key string, dest *bool) error {
 	value := os.Getenv(key)
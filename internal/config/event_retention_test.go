@@ -103,6 +103,25 @@ func TestEventRetentionConfigFromEnv(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "issue event compaction disabled (zero value)",
+			envVars: map[string]string{
+				"VC_EVENT_ISSUE_RETENTION_DAYS": "0",
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg EventRetentionConfig) {
+				if cfg.IssueEventRetentionDays != 0 {
+					t.Errorf("IssueEventRetentionDays = %v, want 0 (keep forever)", cfg.IssueEventRetentionDays)
+				}
+			},
+		},
+		{
+			name: "issue event retention too low (not zero)",
+			envVars: map[string]string{
+				"VC_EVENT_ISSUE_RETENTION_DAYS": "3",
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid int value",
 			envVars: map[string]string{
@@ -247,6 +266,7 @@ func TestEventRetentionConfigFromEnv(t *testing.T) {
 				"VC_EVENT_RETENTION_CRITICAL_DAYS",
 				"VC_EVENT_PER_ISSUE_LIMIT",
 				"VC_EVENT_GLOBAL_LIMIT",
+				"VC_EVENT_ISSUE_RETENTION_DAYS",
 				"VC_EVENT_CLEANUP_INTERVAL_HOURS",
 				"VC_EVENT_CLEANUP_BATCH_SIZE",
 				"VC_EVENT_CLEANUP_ENABLED",
@@ -423,6 +443,23 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "issue event retention too high",
+			config: EventRetentionConfig{
+				RetentionDays:           30,
+				RetentionCriticalDays:   90,
+				PerIssueLimitEvents:     1000,
+				GlobalLimitEvents:       100000,
+				IssueEventRetentionDays: 5000,
+				CleanupIntervalHours:    24,
+				CleanupBatchSize:        1000,
+				CleanupEnabled:          true,
+				CleanupStrategy:         "oldest_non_critical",
+				CleanupVacuum:           false,
+			},
+			wantErr: true,
+			errMsg:  "issue_event_retention_days too large",
+		},
 		{
 			name: "invalid cleanup strategy",
 			config: EventRetentionConfig{
@@ -468,6 +505,7 @@ func TestEventRetentionConfigString(t *testing.T) {
 		"RetentionCriticalDays: 90",
 		"PerIssueLimit: 1000",
 		"GlobalLimit: 100000",
+		"IssueEventRetention: 90d",
 		"CleanupInterval: 24h",
 		"BatchSize: 1000",
 		"Enabled: true",
@@ -0,0 +1,278 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FilePath returns the location of a project's config file
+func FilePath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".vc", "config.yaml")
+}
+
+// FindFile returns the config file of the project dir is in, looking in
+// dir and each of its parents, or "" if there is none
+func FindFile(dir string) string {
+	for {
+		path := FilePath(dir)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// File is a loaded .vc/config.yaml. It sets the same settings as the VC_*
+// environment variables, with the VC_ prefix dropped and in lower case:
+//
+//	model_default: claude-sonnet-4-5-20250929
+//	enable_auto_commit: true
+//	cost:
+//	  enabled: true
+//	  max_cost_per_hour: 5
+//	webhook:
+//	  events: [gate.failed, escalation]
+//
+// Nested keys are joined with underscores (cost.enabled is VC_COST_ENABLED)
+// and lists are joined with commas. Settings are layered: the environment
+// overrides the file, and command-line flags override both.
+//
+// Named profiles under "profiles" hold settings that override the file's
+// own when the profile is in use (see UseProfile), so one file can keep
+// e.g. a database, budget and gates config per environment:
+//
+//	profiles:
+//	  prod:
+//	    db_path: /srv/vc/prod.db
+//	    cost: {max_cost_per_hour: 20}
+type File struct {
+	// Path is the file loaded
+	Path string
+
+	// Values are the file's settings, by environment variable, including
+	// those of the profile in use
+	Values map[string]string
+
+	// Profiles are the named profiles' settings, by profile name and
+	// environment variable
+	Profiles map[string]map[string]string
+
+	// Profile is the profile in use ("" for none)
+	Profile string
+
+	// fromProfile records the settings in Values the profile set
+	fromProfile map[string]bool
+
+	// applied records the settings Apply set, i.e. those not overridden by
+	// the environment
+	applied map[string]bool
+}
+
+// LoadFile loads and validates a config file. Every unknown key, secret and
+// malformed value is reported, not just the first.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	file := &File{Path: path, Values: map[string]string{}, Profiles: map[string]map[string]string{}}
+	var errs []error
+	for _, key := range sortedKeys(root) {
+		if key == "profiles" {
+			errs = append(errs, file.addProfiles(root[key])...)
+			continue
+		}
+		errs = append(errs, addSetting(file.Values, key, key, root[key])...)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, errors.Join(errs...))
+	}
+	return file, nil
+}
+
+// addProfiles adds the profiles under the "profiles" key
+func (f *File) addProfiles(value interface{}) []error {
+	profiles, ok := value.(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("profiles must map profile names to settings")}
+	}
+	var errs []error
+	for _, name := range sortedKeys(profiles) {
+		settings, ok := profiles[name].(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("profile %s must be a map of settings", name))
+			continue
+		}
+		values := map[string]string{}
+		for _, key := range sortedKeys(settings) {
+			errs = append(errs, addSetting(values, "profiles."+name+"."+key, key, settings[key])...)
+		}
+		f.Profiles[name] = values
+	}
+	return errs
+}
+
+// addSetting adds the setting at key (dotted, for errors) and path
+// (underscored) to values
+func addSetting(values map[string]string, key, path string, value interface{}) []error {
+	path = strings.ReplaceAll(strings.ToLower(path), "-", "_")
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var errs []error
+		for _, k := range sortedKeys(v) {
+			errs = append(errs, addSetting(values, key+"."+k, path+"_"+k, v[k])...)
+		}
+		return errs
+	case nil:
+		return []error{fmt.Errorf("%s has no value", key)}
+	}
+
+	env := "VC_" + strings.ToUpper(path)
+	setting, ok := LookupSetting(env)
+	if !ok {
+		return []error{fmt.Errorf("unknown setting %s (no %s)", key, env)}
+	}
+	if setting.Secret {
+		return []error{fmt.Errorf("%s is a secret: set %s in the environment instead", key, env)}
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}, nil:
+				return []error{fmt.Errorf("%s must be a list of values", key)}
+			}
+			items[i] = fmt.Sprint(item)
+		}
+		values[env] = strings.Join(items, ",")
+	default:
+		values[env] = fmt.Sprint(v)
+	}
+	return nil
+}
+
+// ProfileNames returns the names of the file's profiles, sorted
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseProfile layers a profile's settings over the file's own. Call it
+// before Apply.
+func (f *File) UseProfile(name string) error {
+	values, ok := f.Profiles[name]
+	if !ok {
+		if len(f.Profiles) == 0 {
+			return fmt.Errorf("unknown profile %q: %s defines no profiles", name, f.Path)
+		}
+		return fmt.Errorf("unknown profile %q (%s defines %s)", name, f.Path, strings.Join(f.ProfileNames(), ", "))
+	}
+	f.Profile = name
+	f.fromProfile = map[string]bool{}
+	for env, value := range values {
+		f.Values[env] = value
+		f.fromProfile[env] = true
+	}
+	return nil
+}
+
+// Apply sets the environment variable of each setting in the file, unless
+// the environment already sets it, so the VC_* loaders see the file's
+// values
+func (f *File) Apply() error {
+	f.applied = map[string]bool{}
+	for env, value := range f.Values {
+		if os.Getenv(env) != "" {
+			continue
+		}
+		if err := os.Setenv(env, value); err != nil {
+			return fmt.Errorf("failed to apply %s: %w", env, err)
+		}
+		f.applied[env] = true
+	}
+	return nil
+}
+
+// Source is where a setting's effective value comes from
+type Source string
+
+// Setting sources, lowest precedence first
+const (
+	SourceDefault Source = "default" // Not set; the built-in default applies
+	SourceFile    Source = "file"    // .vc/config.yaml
+	SourceProfile Source = "profile" // The profile in use, in .vc/config.yaml
+	SourceEnv     Source = "env"     // The environment
+)
+
+// EffectiveSetting is a setting's value and where it comes from
+type EffectiveSetting struct {
+	Setting
+	Value  string // Empty for SourceDefault
+	Source Source
+
+	// FileValue is the file's (or profile's) value when the environment
+	// overrides it
+	FileValue string
+}
+
+// Effective returns every setting with its effective value. file is the
+// applied config file, or nil if there is none.
+func Effective(file *File) []EffectiveSetting {
+	var result []EffectiveSetting
+	for _, s := range Settings {
+		e := EffectiveSetting{Setting: s, Value: os.Getenv(s.Env), Source: SourceEnv}
+		switch {
+		case e.Value == "":
+			e.Source = SourceDefault
+		case file != nil && file.applied[s.Env] && file.fromProfile[s.Env]:
+			e.Source = SourceProfile
+		case file != nil && file.applied[s.Env]:
+			e.Source = SourceFile
+		case file != nil && file.Values[s.Env] != "":
+			e.FileValue = file.Values[s.Env]
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// Display returns the value to show, with secrets masked
+func (e EffectiveSetting) Display() string {
+	if e.Secret && e.Value != "" {
+		return "********"
+	}
+	return e.Value
+}
+
+// sortedKeys returns a map's keys in order, so errors are reported in a
+// stable order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := FilePath(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`
+model_default: claude-sonnet-4-5-20250929
+enable_auto_commit: true
+cost:
+  enabled: true
+  max_cost_per_hour: 2.5
+webhook:
+  url: https://hooks.example.com/vc
+  events: [gate.failed, escalation]
+`)
+	if got := FindFile(filepath.Join(dir, ".vc")); got != path {
+		t.Errorf("FindFile() = %q, want %q", got, path)
+	}
+	file, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	want := map[string]string{
+		"VC_MODEL_DEFAULT":          "claude-sonnet-4-5-20250929",
+		"VC_ENABLE_AUTO_COMMIT":     "true",
+		"VC_COST_ENABLED":           "true",
+		"VC_COST_MAX_COST_PER_HOUR": "2.5",
+		"VC_WEBHOOK_URL":            "https://hooks.example.com/vc",
+		"VC_WEBHOOK_EVENTS":         "gate.failed,escalation",
+	}
+	if len(file.Values) != len(want) {
+		t.Errorf("Values = %v, want %v", file.Values, want)
+	}
+	for env, value := range want {
+		if file.Values[env] != value {
+			t.Errorf("Values[%s] = %q, want %q", env, file.Values[env], value)
+		}
+	}
+
+	// The environment overrides the file
+	for env := range want {
+		t.Setenv(env, "")
+	}
+	t.Setenv("VC_COST_ENABLED", "false")
+	if err := file.Apply(); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := os.Getenv("VC_MODEL_DEFAULT"); got != "claude-sonnet-4-5-20250929" {
+		t.Errorf("VC_MODEL_DEFAULT = %q after Apply()", got)
+	}
+	for _, e := range Effective(file) {
+		switch e.Env {
+		case "VC_MODEL_DEFAULT":
+			if e.Source != SourceFile {
+				t.Errorf("VC_MODEL_DEFAULT source = %s, want file", e.Source)
+			}
+		case "VC_COST_ENABLED":
+			if e.Source != SourceEnv || e.Value != "false" || e.FileValue != "true" {
+				t.Errorf("VC_COST_ENABLED = %+v, want the environment to override the file", e)
+			}
+		case "VC_COST_MAX_TOKENS_PER_HOUR":
+			if e.Source != SourceDefault {
+				t.Errorf("VC_COST_MAX_TOKENS_PER_HOUR source = %s, want default", e.Source)
+			}
+		}
+	}
+
+	write(`
+cost:
+  enabld: true
+smtp_password: hunter2
+model_simple:
+`)
+	_, err = LoadFile(path)
+	if err == nil {
+		t.Fatal("LoadFile() succeeded, want errors")
+	}
+	for _, want := range []string{"unknown setting cost.enabld", "smtp_password is a secret", "model_simple has no value"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadFile() error = %v, want it to mention %q", err, want)
+		}
+	}
+
+	write("cost: [")
+	if _, err := LoadFile(path); err == nil {
+		t.Error("LoadFile() succeeded on malformed YAML")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+model_default: claude-sonnet-4-5-20250929
+cost:
+  max_cost_per_hour: 5
+profiles:
+  prod:
+    db_path: /srv/vc/prod.db
+    cost: {max_cost_per_hour: 20}
+  dev:
+    agent_provider: amp
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if names := file.ProfileNames(); len(names) != 2 || names[0] != "dev" || names[1] != "prod" {
+		t.Errorf("ProfileNames() = %v, want [dev prod]", names)
+	}
+	if _, ok := file.Values["VC_DB_PATH"]; ok {
+		t.Error("a profile's settings apply without the profile")
+	}
+
+	if err := file.UseProfile("staging"); err == nil || !strings.Contains(err.Error(), "dev, prod") {
+		t.Errorf("UseProfile(staging) error = %v, want the profiles listed", err)
+	}
+	if err := file.UseProfile("prod"); err != nil {
+		t.Fatalf("UseProfile(prod) error = %v", err)
+	}
+	for _, env := range []string{"VC_MODEL_DEFAULT", "VC_DB_PATH", "VC_COST_MAX_COST_PER_HOUR", "VC_AGENT_PROVIDER"} {
+		t.Setenv(env, "")
+	}
+	if err := file.Apply(); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := os.Getenv("VC_COST_MAX_COST_PER_HOUR"); got != "20" {
+		t.Errorf("VC_COST_MAX_COST_PER_HOUR = %q, want the profile to override the file", got)
+	}
+	if got := os.Getenv("VC_AGENT_PROVIDER"); got != "" {
+		t.Errorf("VC_AGENT_PROVIDER = %q, want only the selected profile applied", got)
+	}
+	for _, e := range Effective(file) {
+		switch e.Env {
+		case "VC_DB_PATH", "VC_COST_MAX_COST_PER_HOUR":
+			if e.Source != SourceProfile {
+				t.Errorf("%s source = %s, want profile", e.Env, e.Source)
+			}
+		case "VC_MODEL_DEFAULT":
+			if e.Source != SourceFile {
+				t.Errorf("VC_MODEL_DEFAULT source = %s, want file", e.Source)
+			}
+		}
+	}
+
+	// Profile settings are validated like the file's own
+	if err := os.WriteFile(path, []byte("profiles:\n  prod:\n    cost: {enabld: true}\n  dev: amp\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadFile(path)
+	for _, want := range []string{"unknown setting profiles.prod.cost.enabld", "profile dev must be a map"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadFile() error = %v, want it to mention %q", err, want)
+		}
+	}
+}
+
+func TestSettings(t *testing.T) {
+	seen := map[string]bool{}
+	for _, s := range Settings {
+		if !strings.HasPrefix(s.Env, "VC_") || seen[s.Env] {
+			t.Errorf("bad or duplicate setting %s", s.Env)
+		}
+		seen[s.Env] = true
+	}
+	if s, ok := LookupSetting("VC_API_TOKENS"); !ok || !s.Secret || s.Key() != "api_tokens" {
+		t.Errorf("LookupSetting(VC_API_TOKENS) = %+v, %v", s, ok)
+	}
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Git hosting providers
+const (
+	HostingGitHub = "github"
+	HostingGitLab = "gitlab"
+)
+
+// HostingConfig holds configuration for the git hosting integration, which
+// pushes issue branches and opens pull requests (GitHub) or merge requests
+// (GitLab) after successful executions
+type HostingConfig struct {
+	// Provider is the hosting provider: "github" or "gitlab"
+	// Default: "" (detected from the remote's URL)
+	Provider string
+
+	// Remote is the git remote branches are pushed to
+	// Default: "origin"
+	Remote string
+
+	// Draft opens pull requests as drafts
+	// Default: false
+	Draft bool
+
+	// Labels are applied to every pull request opened
+	// Default: none
+	Labels []string
+
+	// CheckRuns reports each quality gate's result on the pull requests
+	// opened as a GitHub check run, annotated with the failures found in
+	// its output. GitHub only lets GitHub Apps create check runs, so the
+	// token must be an app installation token.
+	// Default: false
+	CheckRuns bool
+
+	// SyncIntervalMinutes is how often the executor checks open pull
+	// requests for status changes
+	// Default: 10, Range: 1-1440 (1 minute - 1 day)
+	SyncIntervalMinutes int
+
+	// GitHub configures the GitHub provider
+	GitHub GitHubConfig
+
+	// GitLab configures the GitLab provider
+	GitLab GitLabConfig
+}
+
+// GitHubConfig holds GitHub-specific hosting configuration
+type GitHubConfig struct {
+	// Token authenticates API calls and branch pushes over HTTPS.
+	// Default: "" (provider disabled; auto-PR falls back to the gh CLI)
+	Token string
+
+	// Repo is the repository pull requests are opened in, as "owner/name"
+	// Default: "" (derived from the remote's URL)
+	Repo string
+
+	// APIURL is the GitHub API endpoint (set for GitHub Enterprise)
+	// Default: "https://api.github.com"
+	APIURL string
+}
+
+// GitLabConfig holds GitLab-specific hosting configuration
+type GitLabConfig struct {
+	// Token authenticates API calls and branch pushes over HTTPS.
+	// Default: "" (provider disabled)
+	Token string
+
+	// Project is the project merge requests are opened in, as its full
+	// path ("group/subgroup/name")
+	// Default: "" (derived from the remote's URL)
+	Project string
+
+	// APIURL is the GitLab API endpoint (set for self-managed GitLab)
+	// Default: "https://gitlab.com/api/v4"
+	APIURL string
+}
+
+// DefaultHostingConfig returns the default hosting configuration
+func DefaultHostingConfig() HostingConfig {
+	return HostingConfig{
+		Remote:              "origin",
+		SyncIntervalMinutes: 10,
+		GitHub:              GitHubConfig{APIURL: "https://api.github.com"},
+		GitLab:              GitLabConfig{APIURL: "https://gitlab.com/api/v4"},
+	}
+}
+
+// Enabled reports whether a token is configured for the selected provider,
+// or for either provider when it's detected from the remote
+func (c HostingConfig) Enabled() bool {
+	switch c.Provider {
+	case HostingGitHub:
+		return c.GitHub.Token != ""
+	case HostingGitLab:
+		return c.GitLab.Token != ""
+	}
+	return c.GitHub.Token != "" || c.GitLab.Token != ""
+}
+
+// Validate checks if the configuration has valid values
+func (c HostingConfig) Validate() error {
+	if c.Provider != "" && c.Provider != HostingGitHub && c.Provider != HostingGitLab {
+		return fmt.Errorf("provider must be %q or %q (got %q)", HostingGitHub, HostingGitLab, c.Provider)
+	}
+	if c.Remote == "" {
+		return fmt.Errorf("remote cannot be empty")
+	}
+	for _, label := range c.Labels {
+		if strings.TrimSpace(label) == "" || strings.Contains(label, ",") {
+			return fmt.Errorf("invalid label %q", label)
+		}
+	}
+	if c.SyncIntervalMinutes < 1 || c.SyncIntervalMinutes > 1440 {
+		return fmt.Errorf("sync_interval_minutes must be between 1 and 1440 (got %d)", c.SyncIntervalMinutes)
+	}
+	if c.GitHub.Repo != "" {
+		owner, name, ok := strings.Cut(c.GitHub.Repo, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("github repo must be owner/name (got %q)", c.GitHub.Repo)
+		}
+	}
+	if c.GitLab.Project != "" {
+		for _, part := range strings.Split(c.GitLab.Project, "/") {
+			if part == "" {
+				return fmt.Errorf("gitlab project must be a path like group/name (got %q)", c.GitLab.Project)
+			}
+		}
+		if !strings.Contains(c.GitLab.Project, "/") {
+			return fmt.Errorf("gitlab project must be a path like group/name (got %q)", c.GitLab.Project)
+		}
+	}
+	for name, url := range map[string]string{"github": c.GitHub.APIURL, "gitlab": c.GitLab.APIURL} {
+		if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+			return fmt.Errorf("%s api_url must be an http(s) URL (got %q)", name, url)
+		}
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config. Tokens are
+// never included.
+func (c HostingConfig) String() string {
+	return fmt.Sprintf(
+		"HostingConfig{Provider: %q, Remote: %q, Draft: %v, Labels: %v, CheckRuns: %v, SyncIntervalMinutes: %d, "+
+			"GitHub: {Token: %v, Repo: %q, APIURL: %q}, GitLab: {Token: %v, Project: %q, APIURL: %q}}",
+		c.Provider, c.Remote, c.Draft, c.Labels, c.CheckRuns, c.SyncIntervalMinutes,
+		c.GitHub.Token != "", c.GitHub.Repo, c.GitHub.APIURL,
+		c.GitLab.Token != "", c.GitLab.Project, c.GitLab.APIURL,
+	)
+}
+
+// SyncInterval returns the pull request status sync interval as a time.Duration
+func (c HostingConfig) SyncInterval() time.Duration {
+	return time.Duration(c.SyncIntervalMinutes) * time.Minute
+}
+
+// HostingConfigFromEnv creates a HostingConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_GIT_HOSTING: Provider, github or gitlab (default: from the remote URL)
+//   - VC_GIT_HOSTING_REMOTE: Remote to push to (default: origin)
+//   - VC_PR_DRAFT: Open pull requests as drafts (default: false)
+//   - VC_PR_LABELS: Comma-separated labels for pull requests (default: none)
+//   - VC_PR_CHECK_RUNS: Report gate results as GitHub check runs (default: false)
+//   - VC_PR_SYNC_INTERVAL_MINUTES: Minutes between status checks (default: 10)
+//   - VC_GITHUB_TOKEN: GitHub token (falls back to GITHUB_TOKEN, then GH_TOKEN)
+//   - VC_GITHUB_REPO: GitHub repository as owner/name (default: from the remote URL)
+//   - VC_GITHUB_API_URL: GitHub API endpoint (default: https://api.github.com)
+//   - VC_GITLAB_TOKEN: GitLab token (falls back to GITLAB_TOKEN)
+//   - VC_GITLAB_PROJECT: GitLab project path (default: from the remote URL)
+//   - VC_GITLAB_API_URL: GitLab API endpoint (default: https://gitlab.com/api/v4)
+//
+// Returns an error if any environment variable has an invalid value.
+func HostingConfigFromEnv() (HostingConfig, error) {
+	cfg := DefaultHostingConfig()
+
+	parseEnvString("VC_GIT_HOSTING", &cfg.Provider)
+	cfg.Provider = strings.ToLower(cfg.Provider)
+	parseEnvString("VC_GIT_HOSTING_REMOTE", &cfg.Remote)
+	if err := parseEnvBool("VC_PR_DRAFT", &cfg.Draft); err != nil {
+		return cfg, err
+	}
+	var labels string
+	parseEnvString("VC_PR_LABELS", &labels)
+	for _, label := range strings.Split(labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			cfg.Labels = append(cfg.Labels, label)
+		}
+	}
+	if err := parseEnvBool("VC_PR_CHECK_RUNS", &cfg.CheckRuns); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_PR_SYNC_INTERVAL_MINUTES", &cfg.SyncIntervalMinutes); err != nil {
+		return cfg, err
+	}
+
+	parseEnvString("GH_TOKEN", &cfg.GitHub.Token)
+	parseEnvString("GITHUB_TOKEN", &cfg.GitHub.Token)
+	parseEnvString("VC_GITHUB_TOKEN", &cfg.GitHub.Token)
+	parseEnvString("VC_GITHUB_REPO", &cfg.GitHub.Repo)
+	parseEnvString("VC_GITHUB_API_URL", &cfg.GitHub.APIURL)
+	cfg.GitHub.APIURL = strings.TrimSuffix(cfg.GitHub.APIURL, "/")
+
+	parseEnvString("GITLAB_TOKEN", &cfg.GitLab.Token)
+	parseEnvString("VC_GITLAB_TOKEN", &cfg.GitLab.Token)
+	parseEnvString("VC_GITLAB_PROJECT", &cfg.GitLab.Project)
+	parseEnvString("VC_GITLAB_API_URL", &cfg.GitLab.APIURL)
+	cfg.GitLab.APIURL = strings.TrimSuffix(cfg.GitLab.APIURL, "/")
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid git hosting configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
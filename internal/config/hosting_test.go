@@ -0,0 +1,148 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHostingConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg HostingConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg HostingConfig) {
+				if !reflect.DeepEqual(cfg, DefaultHostingConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultHostingConfig())
+				}
+				if cfg.Enabled() {
+					t.Error("Enabled() = true without a token")
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_GIT_HOSTING":              "GitLab",
+				"VC_GIT_HOSTING_REMOTE":       "upstream",
+				"VC_PR_DRAFT":                 "true",
+				"VC_PR_LABELS":                "vc, automated ,",
+				"VC_PR_CHECK_RUNS":            "true",
+				"VC_PR_SYNC_INTERVAL_MINUTES": "30",
+				"VC_GITLAB_TOKEN":             "glpat_secret",
+				"VC_GITLAB_PROJECT":           "acme/tools/widgets",
+				"VC_GITLAB_API_URL":           "https://gitlab.example.com/api/v4/",
+				"VC_GITHUB_TOKEN":             "ghp_secret",
+				"VC_GITHUB_REPO":              "acme/widgets",
+			},
+			check: func(t *testing.T, cfg HostingConfig) {
+				if !cfg.Enabled() || cfg.Provider != HostingGitLab || cfg.Remote != "upstream" || !cfg.Draft || !cfg.CheckRuns {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if !reflect.DeepEqual(cfg.Labels, []string{"vc", "automated"}) {
+					t.Errorf("Labels = %q, want [vc automated]", cfg.Labels)
+				}
+				if cfg.GitLab.Project != "acme/tools/widgets" || cfg.GitHub.Repo != "acme/widgets" {
+					t.Errorf("unexpected repositories: %v", cfg)
+				}
+				if cfg.GitLab.APIURL != "https://gitlab.example.com/api/v4" {
+					t.Errorf("APIURL = %q, want the trailing slash trimmed", cfg.GitLab.APIURL)
+				}
+				if cfg.SyncInterval() != 30*time.Minute {
+					t.Errorf("SyncInterval() = %v, want 30m", cfg.SyncInterval())
+				}
+				if s := cfg.String(); strings.Contains(s, "ghp_secret") || strings.Contains(s, "glpat_secret") {
+					t.Error("String() must not include tokens")
+				}
+			},
+		},
+		{
+			name:    "selected provider without a token is disabled",
+			envVars: map[string]string{"VC_GIT_HOSTING": "gitlab", "GITHUB_TOKEN": "github"},
+			check: func(t *testing.T, cfg HostingConfig) {
+				if cfg.Enabled() {
+					t.Error("Enabled() = true without a GitLab token")
+				}
+			},
+		},
+		{
+			name:    "VC_GITHUB_TOKEN takes precedence over GITHUB_TOKEN and GH_TOKEN",
+			envVars: map[string]string{"VC_GITHUB_TOKEN": "vc", "GITHUB_TOKEN": "github", "GH_TOKEN": "gh"},
+			check: func(t *testing.T, cfg HostingConfig) {
+				if cfg.GitHub.Token != "vc" {
+					t.Errorf("Token = %q, want vc", cfg.GitHub.Token)
+				}
+			},
+		},
+		{
+			name:    "GITHUB_TOKEN is used when VC_GITHUB_TOKEN is unset",
+			envVars: map[string]string{"GITHUB_TOKEN": "github", "GH_TOKEN": "gh"},
+			check: func(t *testing.T, cfg HostingConfig) {
+				if cfg.GitHub.Token != "github" || !cfg.Enabled() {
+					t.Errorf("Token = %q, want github", cfg.GitHub.Token)
+				}
+			},
+		},
+		{
+			name:    "GITLAB_TOKEN is used when VC_GITLAB_TOKEN is unset",
+			envVars: map[string]string{"GITLAB_TOKEN": "gitlab"},
+			check: func(t *testing.T, cfg HostingConfig) {
+				if cfg.GitLab.Token != "gitlab" || !cfg.Enabled() {
+					t.Errorf("Token = %q, want gitlab", cfg.GitLab.Token)
+				}
+			},
+		},
+		{
+			name:    "invalid provider",
+			envVars: map[string]string{"VC_GIT_HOSTING": "bitbucket"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid github repo",
+			envVars: map[string]string{"VC_GITHUB_REPO": "widgets"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid gitlab project",
+			envVars: map[string]string{"VC_GITLAB_PROJECT": "acme//widgets"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid draft flag",
+			envVars: map[string]string{"VC_PR_DRAFT": "maybe"},
+			wantErr: true,
+		},
+		{
+			name:    "sync interval out of range",
+			envVars: map[string]string{"VC_PR_SYNC_INTERVAL_MINUTES": "0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_GIT_HOSTING", "VC_GIT_HOSTING_REMOTE", "VC_PR_DRAFT", "VC_PR_LABELS", "VC_PR_CHECK_RUNS",
+				"VC_PR_SYNC_INTERVAL_MINUTES", "VC_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN", "VC_GITHUB_REPO",
+				"VC_GITHUB_API_URL", "VC_GITLAB_TOKEN", "GITLAB_TOKEN", "VC_GITLAB_PROJECT", "VC_GITLAB_API_URL"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := HostingConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HostingConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
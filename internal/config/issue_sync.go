@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sides an issues sync can prefer when both changed the same field. The
+// remote side is preferred by the provider's name (HostingGitHub or
+// HostingGitLab).
+const (
+	IssueSyncPreferNewer = "newer"
+	IssueSyncPreferVC    = "vc"
+)
+
+// IssueSyncConfig configures an issues sync, which mirrors issues, their
+// comments, labels and milestones between VC and the issue tracker of a
+// git host, reached through the git hosting configuration (token,
+// repository and API URL)
+type IssueSyncConfig struct {
+	// Provider is the git host synced with: HostingGitHub or HostingGitLab
+	Provider string
+
+	// Enabled runs the sync in the executor. 'vc github sync' and
+	// 'vc gitlab sync' run it on demand either way.
+	// Default: false
+	Enabled bool
+
+	// IntervalMinutes is how often the executor syncs
+	// Default: 15, Range: 1-1440 (1 minute - 1 day)
+	IntervalMinutes int
+
+	// Label limits the sync to issues with this label: VC issues are only
+	// opened on the remote, and remote issues only imported, once
+	// labelled. Issues already linked keep syncing.
+	// Default: "" (every issue)
+	Label string
+
+	// LabelMap renames VC labels on the remote (VC label -> remote label).
+	// Unmapped labels keep their name.
+	// Default: none
+	LabelMap map[string]string
+
+	// MilestonePrefix maps remote milestones to VC labels: an issue in
+	// milestone "v2" is labelled prefix+"v2" in VC, and the other way
+	// round. Missing milestones are created on the remote.
+	// Default: "" (milestones aren't synced)
+	MilestonePrefix string
+
+	// Prefer decides conflicts, where both sides changed a field since the
+	// last sync: "newer" keeps the side updated last, "vc" or the
+	// provider's name always keeps that side
+	// Default: "newer"
+	Prefer string
+}
+
+// DefaultIssueSyncConfig returns the default configuration of syncing
+// with provider
+func DefaultIssueSyncConfig(provider string) IssueSyncConfig {
+	return IssueSyncConfig{
+		Provider:        provider,
+		IntervalMinutes: 15,
+		Prefer:          IssueSyncPreferNewer,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c IssueSyncConfig) Validate() error {
+	switch c.Provider {
+	case HostingGitHub, HostingGitLab:
+	default:
+		return fmt.Errorf("provider must be %q or %q (got %q)", HostingGitHub, HostingGitLab, c.Provider)
+	}
+	if c.IntervalMinutes < 1 || c.IntervalMinutes > 1440 {
+		return fmt.Errorf("interval_minutes must be between 1 and 1440 (got %d)", c.IntervalMinutes)
+	}
+	if strings.Contains(c.Label, ",") {
+		return fmt.Errorf("invalid label %q", c.Label)
+	}
+	if strings.Contains(c.MilestonePrefix, ",") {
+		return fmt.Errorf("invalid milestone prefix %q", c.MilestonePrefix)
+	}
+	mapped := make(map[string]string)
+	for vcLabel, remoteLabel := range c.LabelMap {
+		if strings.TrimSpace(vcLabel) == "" || strings.TrimSpace(remoteLabel) == "" {
+			return fmt.Errorf("label map entries must be vc=%s (got %q=%q)", c.Provider, vcLabel, remoteLabel)
+		}
+		if other, ok := mapped[remoteLabel]; ok {
+			return fmt.Errorf("labels %q and %q both map to %s label %q", other, vcLabel, c.Provider, remoteLabel)
+		}
+		if c.MilestonePrefix != "" && strings.HasPrefix(vcLabel, c.MilestonePrefix) {
+			return fmt.Errorf("label %q has the milestone prefix %q and can't be mapped", vcLabel, c.MilestonePrefix)
+		}
+		mapped[remoteLabel] = vcLabel
+	}
+	switch c.Prefer {
+	case IssueSyncPreferNewer, IssueSyncPreferVC, c.Provider:
+	default:
+		return fmt.Errorf("prefer must be %q, %q or %q (got %q)", IssueSyncPreferNewer, IssueSyncPreferVC, c.Provider, c.Prefer)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c IssueSyncConfig) String() string {
+	pairs := make([]string, 0, len(c.LabelMap))
+	for vcLabel, remoteLabel := range c.LabelMap {
+		pairs = append(pairs, vcLabel+"="+remoteLabel)
+	}
+	sort.Strings(pairs)
+	return fmt.Sprintf("IssueSyncConfig{Provider: %q, Enabled: %v, IntervalMinutes: %d, Label: %q, LabelMap: %v, MilestonePrefix: %q, Prefer: %q}",
+		c.Provider, c.Enabled, c.IntervalMinutes, c.Label, pairs, c.MilestonePrefix, c.Prefer)
+}
+
+// Interval returns the sync interval as a time.Duration
+func (c IssueSyncConfig) Interval() time.Duration {
+	return time.Duration(c.IntervalMinutes) * time.Minute
+}
+
+// GitHubSyncConfigFromEnv creates the configuration of syncing with GitHub
+// Issues from environment variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_GITHUB_SYNC: Sync GitHub issues while the executor runs (default: false)
+//   - VC_GITHUB_SYNC_INTERVAL_MINUTES: Minutes between syncs (default: 15)
+//   - VC_GITHUB_SYNC_LABEL: Only sync issues with this label (default: every issue)
+//   - VC_GITHUB_SYNC_LABEL_MAP: Comma-separated vc=github label renames (default: none)
+//   - VC_GITHUB_SYNC_MILESTONE_PREFIX: VC label prefix milestones map to (default: milestones not synced)
+//   - VC_GITHUB_SYNC_PREFER: Conflict winner, newer, vc or github (default: newer)
+//
+// Returns an error if any environment variable has an invalid value.
+func GitHubSyncConfigFromEnv() (IssueSyncConfig, error) {
+	return issueSyncConfigFromEnv("VC_GITHUB_SYNC", HostingGitHub, "GitHub")
+}
+
+// GitLabSyncConfigFromEnv creates the configuration of syncing with GitLab
+// issues from environment variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_GITLAB_SYNC: Sync GitLab issues while the executor runs (default: false)
+//   - VC_GITLAB_SYNC_INTERVAL_MINUTES: Minutes between syncs (default: 15)
+//   - VC_GITLAB_SYNC_LABEL: Only sync issues with this label (default: every issue)
+//   - VC_GITLAB_SYNC_LABEL_MAP: Comma-separated vc=gitlab label renames (default: none)
+//   - VC_GITLAB_SYNC_MILESTONE_PREFIX: VC label prefix milestones map to (default: milestones not synced)
+//   - VC_GITLAB_SYNC_PREFER: Conflict winner, newer, vc or gitlab (default: newer)
+//
+// Returns an error if any environment variable has an invalid value.
+func GitLabSyncConfigFromEnv() (IssueSyncConfig, error) {
+	return issueSyncConfigFromEnv("VC_GITLAB_SYNC", HostingGitLab, "GitLab")
+}
+
+// issueSyncConfigFromEnv reads the configuration of syncing with provider
+// (named name in errors) from the environment variables starting with prefix
+func issueSyncConfigFromEnv(prefix, provider, name string) (IssueSyncConfig, error) {
+	cfg := DefaultIssueSyncConfig(provider)
+
+	if err := parseEnvBool(prefix, &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt(prefix+"_INTERVAL_MINUTES", &cfg.IntervalMinutes); err != nil {
+		return cfg, err
+	}
+	parseEnvString(prefix+"_LABEL", &cfg.Label)
+	cfg.Label = strings.TrimSpace(cfg.Label)
+	var labelMap string
+	parseEnvString(prefix+"_LABEL_MAP", &labelMap)
+	for _, pair := range strings.Split(labelMap, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		vcLabel, remoteLabel, _ := strings.Cut(pair, "=")
+		if cfg.LabelMap == nil {
+			cfg.LabelMap = make(map[string]string)
+		}
+		cfg.LabelMap[strings.TrimSpace(vcLabel)] = strings.TrimSpace(remoteLabel)
+	}
+	parseEnvString(prefix+"_MILESTONE_PREFIX", &cfg.MilestonePrefix)
+	cfg.MilestonePrefix = strings.TrimSpace(cfg.MilestonePrefix)
+	parseEnvString(prefix+"_PREFER", &cfg.Prefer)
+	cfg.Prefer = strings.ToLower(cfg.Prefer)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid %s sync configuration from environment: %w", name, err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitHubSyncConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg IssueSyncConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg IssueSyncConfig) {
+				if !reflect.DeepEqual(cfg, DefaultIssueSyncConfig(HostingGitHub)) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultIssueSyncConfig(HostingGitHub))
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_GITHUB_SYNC":                  "true",
+				"VC_GITHUB_SYNC_INTERVAL_MINUTES": "5",
+				"VC_GITHUB_SYNC_LABEL":            " public ",
+				"VC_GITHUB_SYNC_LABEL_MAP":        "ui=area: ui, docs = documentation,",
+				"VC_GITHUB_SYNC_MILESTONE_PREFIX": " release: ",
+				"VC_GITHUB_SYNC_PREFER":           "GitHub",
+			},
+			check: func(t *testing.T, cfg IssueSyncConfig) {
+				if !cfg.Enabled || cfg.Interval() != 5*time.Minute || cfg.Label != "public" ||
+					cfg.MilestonePrefix != "release:" || cfg.Prefer != HostingGitHub {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				want := map[string]string{"ui": "area: ui", "docs": "documentation"}
+				if !reflect.DeepEqual(cfg.LabelMap, want) {
+					t.Errorf("LabelMap = %v, want %v", cfg.LabelMap, want)
+				}
+			},
+		},
+		{
+			name:    "label map entry without a GitHub label",
+			envVars: map[string]string{"VC_GITHUB_SYNC_LABEL_MAP": "ui"},
+			wantErr: true,
+		},
+		{
+			name:    "two labels mapped to the same GitHub label",
+			envVars: map[string]string{"VC_GITHUB_SYNC_LABEL_MAP": "ui=frontend,web=frontend"},
+			wantErr: true,
+		},
+		{
+			name:    "mapped label with the milestone prefix",
+			envVars: map[string]string{"VC_GITHUB_SYNC_LABEL_MAP": "release:v2=v2", "VC_GITHUB_SYNC_MILESTONE_PREFIX": "release:"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown conflict preference",
+			envVars: map[string]string{"VC_GITHUB_SYNC_PREFER": "local"},
+			wantErr: true,
+		},
+		{
+			name:    "preferring another provider",
+			envVars: map[string]string{"VC_GITHUB_SYNC_PREFER": "gitlab"},
+			wantErr: true,
+		},
+		{
+			name:    "interval out of range",
+			envVars: map[string]string{"VC_GITHUB_SYNC_INTERVAL_MINUTES": "0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_GITHUB_SYNC", "VC_GITHUB_SYNC_INTERVAL_MINUTES", "VC_GITHUB_SYNC_LABEL",
+				"VC_GITHUB_SYNC_LABEL_MAP", "VC_GITHUB_SYNC_MILESTONE_PREFIX", "VC_GITHUB_SYNC_PREFER"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := GitHubSyncConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GitHubSyncConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestGitLabSyncConfigFromEnv(t *testing.T) {
+	for _, key := range []string{"VC_GITHUB_SYNC", "VC_GITHUB_SYNC_PREFER"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("VC_GITLAB_SYNC", "true")
+	t.Setenv("VC_GITLAB_SYNC_INTERVAL_MINUTES", "30")
+	t.Setenv("VC_GITLAB_SYNC_LABEL", "")
+	t.Setenv("VC_GITLAB_SYNC_LABEL_MAP", "bug=type::bug")
+	t.Setenv("VC_GITLAB_SYNC_MILESTONE_PREFIX", "milestone:")
+	t.Setenv("VC_GITLAB_SYNC_PREFER", "gitlab")
+
+	cfg, err := GitLabSyncConfigFromEnv()
+	if err != nil {
+		t.Fatalf("GitLabSyncConfigFromEnv() error = %v", err)
+	}
+	want := IssueSyncConfig{Provider: HostingGitLab, Enabled: true, IntervalMinutes: 30,
+		LabelMap: map[string]string{"bug": "type::bug"}, MilestonePrefix: "milestone:", Prefer: HostingGitLab}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("cfg = %v, want %v", cfg, want)
+	}
+
+	t.Setenv("VC_GITLAB_SYNC_PREFER", "github")
+	if _, err := GitLabSyncConfigFromEnv(); err == nil || !strings.Contains(err.Error(), "GitLab") {
+		t.Errorf("GitLabSyncConfigFromEnv() error = %v, want an invalid GitLab sync configuration", err)
+	}
+}
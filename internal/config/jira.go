@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Issue fields the Jira field map can fill
+var jiraMappableFields = []string{"title", "description", "acceptance_criteria", "design", "notes"}
+
+// Issue statuses the Jira status map can move issues to
+var jiraMappableStatuses = []string{"open", "in_progress", "blocked", "closed"}
+
+// JiraConfig configures the Jira connector, which imports a Jira project's
+// epics as missions and its other issues as VC issues, and reports progress
+// back as comments and workflow transitions
+type JiraConfig struct {
+	// Enabled runs the sync in the executor. 'vc jira sync' runs it on
+	// demand either way.
+	// Default: false
+	Enabled bool
+
+	// URL is the Jira site, e.g. https://acme.atlassian.net
+	// Default: "" (not configured)
+	URL string
+
+	// Email is the account the token belongs to. With an email the token
+	// is a Jira Cloud API token; without one, a Jira Server or Data Center
+	// personal access token.
+	// Default: ""
+	Email string
+
+	// Token authenticates with Jira
+	// Default: ""
+	Token string
+
+	// Project is the key of the Jira project to import from
+	// Default: "" (not configured)
+	Project string
+
+	// JQL narrows the issues imported from the project, e.g.
+	// 'labels = vc'
+	// Default: "" (every issue of the project)
+	JQL string
+
+	// IntervalMinutes is how often the executor syncs
+	// Default: 15, Range: 1-1440 (1 minute - 1 day)
+	IntervalMinutes int
+
+	// FieldMap maps VC issue fields (title, description,
+	// acceptance_criteria, design, notes) to the Jira fields they are
+	// imported from, by field ID (e.g. customfield_10035). Entries replace
+	// the defaults.
+	// Default: title=summary, description=description
+	FieldMap map[string]string
+
+	// StatusMap maps VC statuses to the Jira status (or transition name)
+	// linked issues are moved to when their VC issue reaches that status.
+	// Entries replace the defaults; unmapped statuses are reported as
+	// comments.
+	// Default: open=To Do, in_progress=In Progress, closed=Done
+	StatusMap map[string]string
+
+	// EpicLinkField is the ID of the "Epic Link" field of company-managed
+	// projects on older Jira versions, which link stories to their epic
+	// instead of the parent field
+	// Default: "" (the parent field only)
+	EpicLinkField string
+}
+
+// DefaultJiraConfig returns the default Jira configuration
+func DefaultJiraConfig() JiraConfig {
+	return JiraConfig{
+		IntervalMinutes: 15,
+		FieldMap: map[string]string{
+			"title":       "summary",
+			"description": "description",
+		},
+		StatusMap: map[string]string{
+			"open":        "To Do",
+			"in_progress": "In Progress",
+			"closed":      "Done",
+		},
+	}
+}
+
+// Configured reports whether enough is set to connect to Jira
+func (c JiraConfig) Configured() bool {
+	return c.URL != "" && c.Token != "" && c.Project != ""
+}
+
+// Validate checks if the configuration has valid values
+func (c JiraConfig) Validate() error {
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url must be an http(s) URL (got %q)", c.URL)
+		}
+	}
+	if strings.ContainsAny(c.Project, " \"'") {
+		return fmt.Errorf("invalid project key %q", c.Project)
+	}
+	if c.IntervalMinutes < 1 || c.IntervalMinutes > 1440 {
+		return fmt.Errorf("interval_minutes must be between 1 and 1440 (got %d)", c.IntervalMinutes)
+	}
+	for field, jiraField := range c.FieldMap {
+		if !slices.Contains(jiraMappableFields, field) {
+			return fmt.Errorf("field map: unknown field %q (want one of %s)", field, strings.Join(jiraMappableFields, ", "))
+		}
+		if strings.TrimSpace(jiraField) == "" {
+			return fmt.Errorf("field map: no Jira field for %q", field)
+		}
+	}
+	if c.FieldMap["title"] == "" {
+		return fmt.Errorf("field map: title must be mapped")
+	}
+	for status, jiraStatus := range c.StatusMap {
+		if !slices.Contains(jiraMappableStatuses, status) {
+			return fmt.Errorf("status map: unknown status %q (want one of %s)", status, strings.Join(jiraMappableStatuses, ", "))
+		}
+		if strings.TrimSpace(jiraStatus) == "" {
+			return fmt.Errorf("status map: no Jira status for %q", status)
+		}
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c JiraConfig) String() string {
+	return fmt.Sprintf("JiraConfig{Enabled: %v, URL: %q, Email: %q, Token: %v, Project: %q, JQL: %q, IntervalMinutes: %d, FieldMap: %v, StatusMap: %v, EpicLinkField: %q}",
+		c.Enabled, c.URL, c.Email, c.Token != "", c.Project, c.JQL, c.IntervalMinutes,
+		sortedPairs(c.FieldMap), sortedPairs(c.StatusMap), c.EpicLinkField)
+}
+
+// Interval returns the sync interval as a time.Duration
+func (c JiraConfig) Interval() time.Duration {
+	return time.Duration(c.IntervalMinutes) * time.Minute
+}
+
+// JiraConfigFromEnv creates a JiraConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_JIRA_URL: Jira site URL (default: none)
+//   - VC_JIRA_EMAIL: Account of a Jira Cloud API token (default: none, a personal access token)
+//   - VC_JIRA_TOKEN: API token or personal access token (default: none)
+//   - VC_JIRA_PROJECT: Key of the project to import (default: none)
+//   - VC_JIRA_JQL: JQL narrowing the issues imported (default: every issue)
+//   - VC_JIRA_SYNC: Sync with Jira while the executor runs (default: false)
+//   - VC_JIRA_SYNC_INTERVAL_MINUTES: Minutes between syncs (default: 15)
+//   - VC_JIRA_FIELD_MAP: Comma-separated vc_field=jira_field overrides (default: title=summary,description=description)
+//   - VC_JIRA_STATUS_MAP: Comma-separated vc_status=Jira status overrides (default: open=To Do,in_progress=In Progress,closed=Done)
+//   - VC_JIRA_EPIC_LINK_FIELD: ID of the Epic Link field (default: none)
+//
+// Returns an error if any environment variable has an invalid value.
+func JiraConfigFromEnv() (JiraConfig, error) {
+	cfg := DefaultJiraConfig()
+
+	parseEnvString("VC_JIRA_URL", &cfg.URL)
+	cfg.URL = strings.TrimSuffix(strings.TrimSpace(cfg.URL), "/")
+	parseEnvString("VC_JIRA_EMAIL", &cfg.Email)
+	parseEnvString("VC_JIRA_TOKEN", &cfg.Token)
+	parseEnvString("VC_JIRA_PROJECT", &cfg.Project)
+	cfg.Project = strings.TrimSpace(cfg.Project)
+	parseEnvString("VC_JIRA_JQL", &cfg.JQL)
+	if err := parseEnvBool("VC_JIRA_SYNC", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_JIRA_SYNC_INTERVAL_MINUTES", &cfg.IntervalMinutes); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvPairs("VC_JIRA_FIELD_MAP", cfg.FieldMap); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvPairs("VC_JIRA_STATUS_MAP", cfg.StatusMap); err != nil {
+		return cfg, err
+	}
+	parseEnvString("VC_JIRA_EPIC_LINK_FIELD", &cfg.EpicLinkField)
+	cfg.EpicLinkField = strings.TrimSpace(cfg.EpicLinkField)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid Jira configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// parseEnvPairs adds the comma-separated key=value pairs of an environment
+// variable to dest
+func parseEnvPairs(key string, dest map[string]string) error {
+	var value string
+	parseEnvString(key, &value)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid %s entry %q: want key=value", key, pair)
+		}
+		dest[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return nil
+}
+
+// sortedPairs returns a map's entries as sorted key=value strings
+func sortedPairs(m map[string]string) []string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
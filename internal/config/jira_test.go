@@ -0,0 +1,105 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJiraConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg JiraConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg JiraConfig) {
+				if !reflect.DeepEqual(cfg, DefaultJiraConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultJiraConfig())
+				}
+				if cfg.Configured() {
+					t.Error("default config should not be configured")
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_JIRA_URL":                   "https://acme.atlassian.net/",
+				"VC_JIRA_EMAIL":                 "bot@acme.com",
+				"VC_JIRA_TOKEN":                 "secret",
+				"VC_JIRA_PROJECT":               " ENG ",
+				"VC_JIRA_JQL":                   "labels = vc",
+				"VC_JIRA_SYNC":                  "true",
+				"VC_JIRA_SYNC_INTERVAL_MINUTES": "5",
+				"VC_JIRA_FIELD_MAP":             "acceptance_criteria=customfield_10035, notes = environment,",
+				"VC_JIRA_STATUS_MAP":            "closed=Resolved,blocked=On Hold",
+				"VC_JIRA_EPIC_LINK_FIELD":       "customfield_10014",
+			},
+			check: func(t *testing.T, cfg JiraConfig) {
+				if !cfg.Enabled || !cfg.Configured() || cfg.URL != "https://acme.atlassian.net" || cfg.Project != "ENG" ||
+					cfg.Interval() != 5*time.Minute || cfg.EpicLinkField != "customfield_10014" {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				wantFields := map[string]string{"title": "summary", "description": "description",
+					"acceptance_criteria": "customfield_10035", "notes": "environment"}
+				if !reflect.DeepEqual(cfg.FieldMap, wantFields) {
+					t.Errorf("FieldMap = %v, want %v", cfg.FieldMap, wantFields)
+				}
+				wantStatuses := map[string]string{"open": "To Do", "in_progress": "In Progress",
+					"closed": "Resolved", "blocked": "On Hold"}
+				if !reflect.DeepEqual(cfg.StatusMap, wantStatuses) {
+					t.Errorf("StatusMap = %v, want %v", cfg.StatusMap, wantStatuses)
+				}
+			},
+		},
+		{
+			name:    "URL without a scheme",
+			envVars: map[string]string{"VC_JIRA_URL": "acme.atlassian.net"},
+			wantErr: true,
+		},
+		{
+			name:    "field map entry without a Jira field",
+			envVars: map[string]string{"VC_JIRA_FIELD_MAP": "notes"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			envVars: map[string]string{"VC_JIRA_FIELD_MAP": "assignee=assignee"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown status",
+			envVars: map[string]string{"VC_JIRA_STATUS_MAP": "done=Done"},
+			wantErr: true,
+		},
+		{
+			name:    "interval out of range",
+			envVars: map[string]string{"VC_JIRA_SYNC_INTERVAL_MINUTES": "0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_JIRA_URL", "VC_JIRA_EMAIL", "VC_JIRA_TOKEN", "VC_JIRA_PROJECT", "VC_JIRA_JQL",
+				"VC_JIRA_SYNC", "VC_JIRA_SYNC_INTERVAL_MINUTES", "VC_JIRA_FIELD_MAP", "VC_JIRA_STATUS_MAP", "VC_JIRA_EPIC_LINK_FIELD"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := JiraConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JiraConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+)
+
+// LargeFilesConfig configures how VC treats Git LFS-tracked files and large
+// binaries. Their content is left out of the diffs fed to AI prompts (code
+// review, test coverage, commit messages, PR descriptions), the agent is
+// told not to touch LFS-tracked paths, and changes to either are flagged.
+type LargeFilesConfig struct {
+	// Enabled turns on LFS and large-binary handling
+	// Default: true
+	Enabled bool
+
+	// MaxBinarySizeKB is the size from which a binary file is treated as
+	// large; smaller binaries are handled like any other file
+	// Default: 256; 0 treats only LFS-tracked files as large
+	MaxBinarySizeKB int
+}
+
+// DefaultLargeFilesConfig returns the default large file configuration
+//
+// LFS-tracked files and binaries of 256 KB or more are kept out of prompts.
+func DefaultLargeFilesConfig() LargeFilesConfig {
+	return LargeFilesConfig{
+		Enabled:         true,
+		MaxBinarySizeKB: 256,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c LargeFilesConfig) Validate() error {
+	if c.MaxBinarySizeKB < 0 {
+		return fmt.Errorf("max binary size must be non-negative (got %d KB)", c.MaxBinarySizeKB)
+	}
+	return nil
+}
+
+// MaxBinarySize returns MaxBinarySizeKB in bytes
+func (c LargeFilesConfig) MaxBinarySize() int64 {
+	return int64(c.MaxBinarySizeKB) * 1024
+}
+
+// String returns a human-readable representation of the config
+func (c LargeFilesConfig) String() string {
+	return fmt.Sprintf("LargeFilesConfig{Enabled: %v, MaxBinarySizeKB: %d}", c.Enabled, c.MaxBinarySizeKB)
+}
+
+// LargeFilesConfigFromEnv creates a LargeFilesConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_LARGE_FILES_GUARD: Keep LFS files and large binaries out of prompts and flag changes to them (default: true)
+//   - VC_MAX_BINARY_SIZE_KB: Size from which binaries are treated as large, 0 for LFS files only (default: 256)
+//
+// Returns an error if any environment variable has an invalid value.
+func LargeFilesConfigFromEnv() (LargeFilesConfig, error) {
+	cfg := DefaultLargeFilesConfig()
+
+	if err := parseEnvBool("VC_LARGE_FILES_GUARD", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_MAX_BINARY_SIZE_KB", &cfg.MaxBinarySizeKB); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid large files configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
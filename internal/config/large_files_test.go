@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLargeFilesConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    LargeFilesConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultLargeFilesConfig(),
+		},
+		{
+			name: "LFS files only",
+			envVars: map[string]string{
+				"VC_MAX_BINARY_SIZE_KB": "0",
+			},
+			want: LargeFilesConfig{Enabled: true},
+		},
+		{
+			name: "disabled",
+			envVars: map[string]string{
+				"VC_LARGE_FILES_GUARD": "false",
+			},
+			want: LargeFilesConfig{MaxBinarySizeKB: 256},
+		},
+		{
+			name: "negative size",
+			envVars: map[string]string{
+				"VC_MAX_BINARY_SIZE_KB": "-1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bool value",
+			envVars: map[string]string{
+				"VC_LARGE_FILES_GUARD": "maybe",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_LARGE_FILES_GUARD",
+				"VC_MAX_BINARY_SIZE_KB",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := LargeFilesConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LargeFilesConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
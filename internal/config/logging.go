@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Log formats
+const (
+	LogFormatText = "text" // key=value lines for humans
+	LogFormatJSON = "json" // one JSON object per line, for log aggregation
+)
+
+// LoggingConfig configures VC's structured log output
+type LoggingConfig struct {
+	// Level is the least severe level logged: debug, info, warn or error
+	// Default: info
+	Level string
+
+	// Format is how records are written: text or json
+	// Default: text
+	Format string
+}
+
+// DefaultLoggingConfig returns the default logging configuration
+//
+// Info and above is logged as text.
+func DefaultLoggingConfig() LoggingConfig {
+	return LoggingConfig{
+		Level:  "info",
+		Format: LogFormatText,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c LoggingConfig) Validate() error {
+	if _, err := parseLogLevel(c.Level); err != nil {
+		return err
+	}
+	if c.Format != LogFormatText && c.Format != LogFormatJSON {
+		return fmt.Errorf("log format must be %q or %q (got %q)", LogFormatText, LogFormatJSON, c.Format)
+	}
+	return nil
+}
+
+// SlogLevel returns Level as a slog level, or info if it is invalid
+func (c LoggingConfig) SlogLevel() slog.Level {
+	level, err := parseLogLevel(c.Level)
+	if err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// String returns a human-readable representation of the config
+func (c LoggingConfig) String() string {
+	return fmt.Sprintf("LoggingConfig{Level: %s, Format: %s}", c.Level, c.Format)
+}
+
+// LoggingConfigFromEnv creates a LoggingConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_LOG_LEVEL: Least severe level logged: debug, info, warn or error (default: info)
+//   - VC_LOG_FORMAT: Log format, text or json (default: text)
+//   - VC_DEBUG_EVENTS: If set and VC_LOG_LEVEL is not, log at debug level
+//
+// Returns an error if any environment variable has an invalid value.
+func LoggingConfigFromEnv() (LoggingConfig, error) {
+	cfg := DefaultLoggingConfig()
+
+	if os.Getenv("VC_DEBUG_EVENTS") != "" {
+		cfg.Level = "debug"
+	}
+	parseEnvString("VC_LOG_LEVEL", &cfg.Level)
+	parseEnvString("VC_LOG_FORMAT", &cfg.Format)
+	cfg.Level = strings.ToLower(cfg.Level)
+	cfg.Format = strings.ToLower(cfg.Format)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid logging configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// parseLogLevel parses a level name as accepted by VC_LOG_LEVEL
+func parseLogLevel(name string) (slog.Level, error) {
+	switch name {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("log level must be debug, info, warn or error (got %q)", name)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestLoggingConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    LoggingConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultLoggingConfig(),
+		},
+		{
+			name: "json at debug",
+			envVars: map[string]string{
+				"VC_LOG_LEVEL":  "DEBUG",
+				"VC_LOG_FORMAT": "json",
+			},
+			want: LoggingConfig{Level: "debug", Format: LogFormatJSON},
+		},
+		{
+			name: "debug events implies debug level",
+			envVars: map[string]string{
+				"VC_DEBUG_EVENTS": "1",
+			},
+			want: LoggingConfig{Level: "debug", Format: LogFormatText},
+		},
+		{
+			name: "explicit level wins over debug events",
+			envVars: map[string]string{
+				"VC_DEBUG_EVENTS": "1",
+				"VC_LOG_LEVEL":    "warn",
+			},
+			want: LoggingConfig{Level: "warn", Format: LogFormatText},
+		},
+		{
+			name: "invalid level",
+			envVars: map[string]string{
+				"VC_LOG_LEVEL": "verbose",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid format",
+			envVars: map[string]string{
+				"VC_LOG_FORMAT": "xml",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_LOG_LEVEL",
+				"VC_LOG_FORMAT",
+				"VC_DEBUG_EVENTS",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := LoggingConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoggingConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggingConfigSlogLevel(t *testing.T) {
+	if got := (LoggingConfig{Level: "warn"}).SlogLevel(); got != slog.LevelWarn {
+		t.Errorf("SlogLevel() = %v, want WARN", got)
+	}
+	if got := (LoggingConfig{Level: "bogus"}).SlogLevel(); got != slog.LevelInfo {
+		t.Errorf("SlogLevel() = %v, want INFO for an invalid level", got)
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MaintenanceConfig configures the maintenance job 'vc daemon' runs on a
+// schedule (and 'vc cleanup all' runs on demand): a duplicate sweep of open
+// issues, event pruning, database VACUUM and ANALYZE, stale sandbox cleanup
+// and a check of in_progress issues no executor holds. Problems it finds are
+// filed as a health report issue.
+type MaintenanceConfig struct {
+	// Enabled turns the scheduled maintenance job on in 'vc daemon'
+	// Default: true
+	Enabled bool
+
+	// Schedule is when the job runs, as a cron expression
+	// Default: "0 3 * * *" (nightly at 03:00, local time)
+	Schedule string
+
+	// DedupMaxIssues is how many of the most recently updated open issues
+	// are checked for duplicates
+	// Default: 50, Range: 0-1000 (0 = skip the sweep)
+	DedupMaxIssues int
+
+	// StaleSandboxDays is how long a sandbox can go unmodified before it is
+	// removed, unless its issue is still in progress
+	// Default: 7, Range: 1-365
+	StaleSandboxDays int
+
+	// Vacuum runs VACUUM to reclaim disk space. ANALYZE always runs.
+	// Default: true
+	Vacuum bool
+}
+
+// DefaultMaintenanceConfig returns the default maintenance configuration
+func DefaultMaintenanceConfig() MaintenanceConfig {
+	return MaintenanceConfig{
+		Enabled:          true,
+		Schedule:         "0 3 * * *",
+		DedupMaxIssues:   50,
+		StaleSandboxDays: 7,
+		Vacuum:           true,
+	}
+}
+
+// Validate checks if the configuration has valid values. The schedule is
+// parsed by the maintenance job itself.
+func (c MaintenanceConfig) Validate() error {
+	if strings.TrimSpace(c.Schedule) == "" {
+		return fmt.Errorf("schedule must be a cron expression")
+	}
+	if c.DedupMaxIssues < 0 || c.DedupMaxIssues > 1000 {
+		return fmt.Errorf("dedup_max_issues must be between 0 and 1000 (got %d)", c.DedupMaxIssues)
+	}
+	if c.StaleSandboxDays < 1 || c.StaleSandboxDays > 365 {
+		return fmt.Errorf("stale_sandbox_days must be between 1 and 365 (got %d)", c.StaleSandboxDays)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c MaintenanceConfig) String() string {
+	return fmt.Sprintf("MaintenanceConfig{Enabled: %v, Schedule: %q, DedupMaxIssues: %d, StaleSandboxDays: %d, Vacuum: %v}",
+		c.Enabled, c.Schedule, c.DedupMaxIssues, c.StaleSandboxDays, c.Vacuum)
+}
+
+// MaintenanceConfigFromEnv creates a MaintenanceConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_MAINTENANCE_ENABLED: Run the maintenance job in vc daemon (default: true)
+//   - VC_MAINTENANCE_SCHEDULE: Cron expression for when it runs (default: "0 3 * * *")
+//   - VC_MAINTENANCE_DEDUP_MAX_ISSUES: Open issues checked for duplicates, 0 to skip (default: 50)
+//   - VC_MAINTENANCE_STALE_SANDBOX_DAYS: Days before an unused sandbox is removed (default: 7)
+//   - VC_MAINTENANCE_VACUUM: VACUUM the database (default: true)
+//
+// Returns an error if any environment variable has an invalid value.
+func MaintenanceConfigFromEnv() (MaintenanceConfig, error) {
+	cfg := DefaultMaintenanceConfig()
+
+	if err := parseEnvBool("VC_MAINTENANCE_ENABLED", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if value := os.Getenv("VC_MAINTENANCE_SCHEDULE"); value != "" {
+		cfg.Schedule = value
+	}
+	if err := parseEnvInt("VC_MAINTENANCE_DEDUP_MAX_ISSUES", &cfg.DedupMaxIssues); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_MAINTENANCE_STALE_SANDBOX_DAYS", &cfg.StaleSandboxDays); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_MAINTENANCE_VACUUM", &cfg.Vacuum); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid maintenance configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import "testing"
+
+func TestMaintenanceConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg MaintenanceConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg MaintenanceConfig) {
+				if cfg != DefaultMaintenanceConfig() {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultMaintenanceConfig())
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_MAINTENANCE_ENABLED":            "false",
+				"VC_MAINTENANCE_SCHEDULE":           "30 2 * * 0",
+				"VC_MAINTENANCE_DEDUP_MAX_ISSUES":   "0",
+				"VC_MAINTENANCE_STALE_SANDBOX_DAYS": "14",
+				"VC_MAINTENANCE_VACUUM":             "false",
+			},
+			check: func(t *testing.T, cfg MaintenanceConfig) {
+				want := MaintenanceConfig{Schedule: "30 2 * * 0", StaleSandboxDays: 14}
+				if cfg != want {
+					t.Errorf("cfg = %v, want %v", cfg, want)
+				}
+			},
+		},
+		{
+			name:    "too many issues to sweep",
+			envVars: map[string]string{"VC_MAINTENANCE_DEDUP_MAX_ISSUES": "5000"},
+			wantErr: true,
+		},
+		{
+			name:    "stale sandbox days out of range",
+			envVars: map[string]string{"VC_MAINTENANCE_STALE_SANDBOX_DAYS": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid vacuum flag",
+			envVars: map[string]string{"VC_MAINTENANCE_VACUUM": "sometimes"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_MAINTENANCE_ENABLED", "VC_MAINTENANCE_SCHEDULE", "VC_MAINTENANCE_DEDUP_MAX_ISSUES", "VC_MAINTENANCE_STALE_SANDBOX_DAYS", "VC_MAINTENANCE_VACUUM"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := MaintenanceConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MaintenanceConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PagerDutyConfig configures paging through the PagerDuty Events API when
+// critical work is stuck: a P0 issue becomes blocked, or its executions
+// keep failing
+type PagerDutyConfig struct {
+	// RoutingKey is the integration key of a PagerDuty service's Events API
+	// v2 integration. Paging is disabled when empty.
+	// Default: ""
+	RoutingKey string
+
+	// EventsURL is the Events API endpoint
+	// Default: "https://events.pagerduty.com/v2/enqueue"
+	EventsURL string
+
+	// Source names this VC instance in incidents, e.g. its host
+	// Default: "vc"
+	Source string
+
+	// FailureThreshold is how many executions of a P0 issue must fail in a
+	// row before it pages
+	// Default: 3, Range: 1-20
+	FailureThreshold int
+
+	// PollIntervalSeconds is how often the database is checked for stuck
+	// P0 work and failed pages are retried
+	// Default: 30, Range: 1-3600
+	PollIntervalSeconds int
+}
+
+// DefaultPagerDutyConfig returns the default PagerDuty configuration
+func DefaultPagerDutyConfig() PagerDutyConfig {
+	return PagerDutyConfig{
+		EventsURL:           "https://events.pagerduty.com/v2/enqueue",
+		Source:              "vc",
+		FailureThreshold:    3,
+		PollIntervalSeconds: 30,
+	}
+}
+
+// Enabled reports whether a routing key is configured
+func (c PagerDutyConfig) Enabled() bool {
+	return c.RoutingKey != ""
+}
+
+// Validate checks if the configuration has valid values
+func (c PagerDutyConfig) Validate() error {
+	if !strings.HasPrefix(c.EventsURL, "https://") && !strings.HasPrefix(c.EventsURL, "http://") {
+		return fmt.Errorf("events URL must be an http(s) URL (got %q)", c.EventsURL)
+	}
+	if strings.TrimSpace(c.Source) == "" {
+		return fmt.Errorf("source cannot be empty")
+	}
+	if c.FailureThreshold < 1 || c.FailureThreshold > 20 {
+		return fmt.Errorf("failure_threshold must be between 1 and 20 (got %d)", c.FailureThreshold)
+	}
+	if c.PollIntervalSeconds < 1 || c.PollIntervalSeconds > 3600 {
+		return fmt.Errorf("poll_interval_seconds must be between 1 and 3600 (got %d)", c.PollIntervalSeconds)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config. The
+// routing key is never included.
+func (c PagerDutyConfig) String() string {
+	return fmt.Sprintf("PagerDutyConfig{RoutingKey: %v, EventsURL: %q, Source: %q, FailureThreshold: %d, PollIntervalSeconds: %d}",
+		c.RoutingKey != "", c.EventsURL, c.Source, c.FailureThreshold, c.PollIntervalSeconds)
+}
+
+// PollInterval returns the poll interval as a time.Duration
+func (c PagerDutyConfig) PollInterval() time.Duration {
+	return time.Duration(c.PollIntervalSeconds) * time.Second
+}
+
+// PagerDutyConfigFromEnv creates a PagerDutyConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_PAGERDUTY_ROUTING_KEY: Events API v2 integration key (default: disabled)
+//   - VC_PAGERDUTY_EVENTS_URL: Events API endpoint (default: https://events.pagerduty.com/v2/enqueue)
+//   - VC_PAGERDUTY_SOURCE: Source named in incidents (default: vc)
+//   - VC_PAGERDUTY_FAILURE_THRESHOLD: Failed executions in a row that page for a P0 (default: 3)
+//   - VC_PAGERDUTY_POLL_INTERVAL_SECONDS: Seconds between checks for stuck P0 work (default: 30)
+//
+// Returns an error if any environment variable has an invalid value.
+func PagerDutyConfigFromEnv() (PagerDutyConfig, error) {
+	cfg := DefaultPagerDutyConfig()
+
+	parseEnvString("VC_PAGERDUTY_ROUTING_KEY", &cfg.RoutingKey)
+	parseEnvString("VC_PAGERDUTY_EVENTS_URL", &cfg.EventsURL)
+	parseEnvString("VC_PAGERDUTY_SOURCE", &cfg.Source)
+	if err := parseEnvInt("VC_PAGERDUTY_FAILURE_THRESHOLD", &cfg.FailureThreshold); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_PAGERDUTY_POLL_INTERVAL_SECONDS", &cfg.PollIntervalSeconds); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid PagerDuty configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
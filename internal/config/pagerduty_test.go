@@ -0,0 +1,86 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPagerDutyConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg PagerDutyConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg PagerDutyConfig) {
+				if !reflect.DeepEqual(cfg, DefaultPagerDutyConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultPagerDutyConfig())
+				}
+				if cfg.Enabled() {
+					t.Error("Enabled() = true without a routing key")
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_PAGERDUTY_ROUTING_KEY":           "r0ut1ng",
+				"VC_PAGERDUTY_EVENTS_URL":            "http://localhost:8080/v2/enqueue",
+				"VC_PAGERDUTY_SOURCE":                "build-01",
+				"VC_PAGERDUTY_FAILURE_THRESHOLD":     "5",
+				"VC_PAGERDUTY_POLL_INTERVAL_SECONDS": "60",
+			},
+			check: func(t *testing.T, cfg PagerDutyConfig) {
+				if !cfg.Enabled() || cfg.EventsURL != "http://localhost:8080/v2/enqueue" || cfg.Source != "build-01" || cfg.FailureThreshold != 5 {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if cfg.PollInterval() != time.Minute {
+					t.Errorf("PollInterval() = %v, want 1m", cfg.PollInterval())
+				}
+				if strings.Contains(cfg.String(), "r0ut1ng") {
+					t.Error("String() must not include the routing key")
+				}
+			},
+		},
+		{
+			name:    "events URL is not http",
+			envVars: map[string]string{"VC_PAGERDUTY_EVENTS_URL": "events.pagerduty.com"},
+			wantErr: true,
+		},
+		{
+			name:    "failure threshold out of range",
+			envVars: map[string]string{"VC_PAGERDUTY_FAILURE_THRESHOLD": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid poll interval",
+			envVars: map[string]string{"VC_PAGERDUTY_POLL_INTERVAL_SECONDS": "often"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_PAGERDUTY_ROUTING_KEY", "VC_PAGERDUTY_EVENTS_URL", "VC_PAGERDUTY_SOURCE",
+				"VC_PAGERDUTY_FAILURE_THRESHOLD", "VC_PAGERDUTY_POLL_INTERVAL_SECONDS"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := PagerDutyConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PagerDutyConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
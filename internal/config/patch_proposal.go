@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+)
+
+// PatchProposalConfig configures patch-proposal mode, for repositories that
+// don't want agents editing the working tree in place. The agent works in a
+// scratch worktree; what it changed is captured as a patch, checked to apply
+// cleanly, reviewed by the AI supervisor, and only then applied to the real
+// branch. It only applies outside sandboxes.
+type PatchProposalConfig struct {
+	// Enabled turns on patch-proposal mode
+	// Default: false
+	Enabled bool
+
+	// MaxFiles rejects patches that change more files than this without
+	// reviewing them (0 = no limit)
+	// Default: 50
+	MaxFiles int
+}
+
+// DefaultPatchProposalConfig returns the default patch proposal configuration
+//
+// Agents edit the working tree directly.
+func DefaultPatchProposalConfig() PatchProposalConfig {
+	return PatchProposalConfig{
+		Enabled:  false,
+		MaxFiles: 50,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c PatchProposalConfig) Validate() error {
+	if c.MaxFiles < 0 {
+		return fmt.Errorf("max files cannot be negative (got %d)", c.MaxFiles)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c PatchProposalConfig) String() string {
+	return fmt.Sprintf("PatchProposalConfig{Enabled: %v, MaxFiles: %d}", c.Enabled, c.MaxFiles)
+}
+
+// PatchProposalConfigFromEnv creates a PatchProposalConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_PATCH_PROPOSAL: review agent changes as a patch before applying them (default: false)
+//   - VC_PATCH_PROPOSAL_MAX_FILES: reject patches changing more files (default: 50, 0 = no limit)
+//
+// Returns an error if any environment variable has an invalid value.
+func PatchProposalConfigFromEnv() (PatchProposalConfig, error) {
+	cfg := DefaultPatchProposalConfig()
+
+	if err := parseEnvBool("VC_PATCH_PROPOSAL", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_PATCH_PROPOSAL_MAX_FILES", &cfg.MaxFiles); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid patch proposal configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
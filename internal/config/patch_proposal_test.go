@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPatchProposalConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    PatchProposalConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultPatchProposalConfig(),
+		},
+		{
+			name: "enabled without a file limit",
+			envVars: map[string]string{
+				"VC_PATCH_PROPOSAL":           "true",
+				"VC_PATCH_PROPOSAL_MAX_FILES": "0",
+			},
+			want: PatchProposalConfig{Enabled: true},
+		},
+		{
+			name: "negative file limit",
+			envVars: map[string]string{
+				"VC_PATCH_PROPOSAL_MAX_FILES": "-1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bool value",
+			envVars: map[string]string{
+				"VC_PATCH_PROPOSAL": "maybe",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_PATCH_PROPOSAL",
+				"VC_PATCH_PROPOSAL_MAX_FILES",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := PatchProposalConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PatchProposalConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+)
+
+// What the executor does with agent edits outside an issue's path scope
+// (its "scope:" labels)
+const (
+	ScopeViolationRevert = "revert" // Discard the edits and note them on the issue
+	ScopeViolationFlag   = "flag"   // Keep the edits out of the commit and note them on the issue
+)
+
+// PathScopeConfig configures how issue path scopes are enforced, so a task
+// in one part of a monorepo can't quietly modify another. Scoped issues'
+// diffs, quality gates and auto-commits only cover the scope's paths either way.
+type PathScopeConfig struct {
+	// OnViolation is "revert" or "flag"
+	// Default: "revert"
+	OnViolation string
+}
+
+// DefaultPathScopeConfig returns the default path scope configuration
+//
+// Edits outside the scope are reverted.
+func DefaultPathScopeConfig() PathScopeConfig {
+	return PathScopeConfig{
+		OnViolation: ScopeViolationRevert,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c PathScopeConfig) Validate() error {
+	if c.OnViolation != ScopeViolationRevert && c.OnViolation != ScopeViolationFlag {
+		return fmt.Errorf("scope violation action must be %q or %q (got %q)", ScopeViolationRevert, ScopeViolationFlag, c.OnViolation)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c PathScopeConfig) String() string {
+	return fmt.Sprintf("PathScopeConfig{OnViolation: %s}", c.OnViolation)
+}
+
+// PathScopeConfigFromEnv creates a PathScopeConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_SCOPE_VIOLATION: revert or flag edits outside an issue's scope (default: revert)
+//
+// Returns an error if any environment variable has an invalid value.
+func PathScopeConfigFromEnv() (PathScopeConfig, error) {
+	cfg := DefaultPathScopeConfig()
+
+	parseEnvString("VC_SCOPE_VIOLATION", &cfg.OnViolation)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid path scope configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPathScopeConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    PathScopeConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultPathScopeConfig(),
+		},
+		{
+			name: "flag",
+			envVars: map[string]string{
+				"VC_SCOPE_VIOLATION": "flag",
+			},
+			want: PathScopeConfig{OnViolation: ScopeViolationFlag},
+		},
+		{
+			name: "invalid action",
+			envVars: map[string]string{
+				"VC_SCOPE_VIOLATION": "ignore",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_SCOPE_VIOLATION",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := PathScopeConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PathScopeConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+)
+
+// PushChecksConfig configures the safety checks run before VC pushes a
+// branch or opens a pull request, so an autonomous agent can't pollute the
+// remote with huge files, binaries, credentials or rewritten history
+type PushChecksConfig struct {
+	// Enabled runs the checks before every push
+	// Default: true
+	Enabled bool
+
+	// MaxFileSizeKB is the largest file a pushed commit may add or change
+	// Default: 1024 (1 MB); 0 means no limit
+	MaxFileSizeKB int
+
+	// AllowBinary allows pushing binary files
+	// Default: false
+	AllowBinary bool
+
+	// AllowForcePush allows pushes that rewrite the remote branch's history
+	// Default: false
+	AllowForcePush bool
+
+	// ScanSecrets blocks pushes that add lines that look like credentials
+	// Default: true
+	ScanSecrets bool
+}
+
+// DefaultPushChecksConfig returns the default pre-push checks configuration
+//
+// All checks are on: files up to 1 MB, no binaries, no secrets, no force
+// pushes.
+func DefaultPushChecksConfig() PushChecksConfig {
+	return PushChecksConfig{
+		Enabled:       true,
+		MaxFileSizeKB: 1024,
+		ScanSecrets:   true,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c PushChecksConfig) Validate() error {
+	if c.MaxFileSizeKB < 0 {
+		return fmt.Errorf("max file size must be non-negative (got %d KB)", c.MaxFileSizeKB)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c PushChecksConfig) String() string {
+	return fmt.Sprintf("PushChecksConfig{Enabled: %v, MaxFileSizeKB: %d, AllowBinary: %v, AllowForcePush: %v, ScanSecrets: %v}",
+		c.Enabled, c.MaxFileSizeKB, c.AllowBinary, c.AllowForcePush, c.ScanSecrets)
+}
+
+// PushChecksConfigFromEnv creates a PushChecksConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_PUSH_CHECKS: Run pre-push checks (default: true)
+//   - VC_PUSH_MAX_FILE_SIZE_KB: Largest file that may be pushed, 0 for no limit (default: 1024)
+//   - VC_PUSH_ALLOW_BINARY: Allow pushing binary files (default: false)
+//   - VC_PUSH_ALLOW_FORCE: Allow pushes that rewrite remote history (default: false)
+//   - VC_PUSH_SCAN_SECRETS: Block pushes that add credentials (default: true)
+//
+// Returns an error if any environment variable has an invalid value.
+func PushChecksConfigFromEnv() (PushChecksConfig, error) {
+	cfg := DefaultPushChecksConfig()
+
+	if err := parseEnvBool("VC_PUSH_CHECKS", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_PUSH_MAX_FILE_SIZE_KB", &cfg.MaxFileSizeKB); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_PUSH_ALLOW_BINARY", &cfg.AllowBinary); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_PUSH_ALLOW_FORCE", &cfg.AllowForcePush); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_PUSH_SCAN_SECRETS", &cfg.ScanSecrets); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid push checks configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPushChecksConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    PushChecksConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultPushChecksConfig(),
+		},
+		{
+			name: "relaxed checks",
+			envVars: map[string]string{
+				"VC_PUSH_MAX_FILE_SIZE_KB": "0",
+				"VC_PUSH_ALLOW_BINARY":     "true",
+				"VC_PUSH_ALLOW_FORCE":      "true",
+				"VC_PUSH_SCAN_SECRETS":     "false",
+			},
+			want: PushChecksConfig{Enabled: true, AllowBinary: true, AllowForcePush: true},
+		},
+		{
+			name: "disabled",
+			envVars: map[string]string{
+				"VC_PUSH_CHECKS": "false",
+			},
+			want: PushChecksConfig{MaxFileSizeKB: 1024, ScanSecrets: true},
+		},
+		{
+			name: "negative size",
+			envVars: map[string]string{
+				"VC_PUSH_MAX_FILE_SIZE_KB": "-1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bool value",
+			envVars: map[string]string{
+				"VC_PUSH_ALLOW_FORCE": "sometimes",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_PUSH_CHECKS",
+				"VC_PUSH_MAX_FILE_SIZE_KB",
+				"VC_PUSH_ALLOW_BINARY",
+				"VC_PUSH_ALLOW_FORCE",
+				"VC_PUSH_SCAN_SECRETS",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := PushChecksConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PushChecksConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
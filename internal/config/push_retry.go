@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+)
+
+// PushRetryConfig configures how VC recovers when a push is rejected
+// because the remote branch advanced: it fetches the branch, rebases onto
+// it, re-runs the quality gates and pushes again, up to MaxAttempts pushes.
+type PushRetryConfig struct {
+	// MaxAttempts is the most pushes made before giving up
+	// Default: 3, Range: 1-10 (1 = never retry)
+	MaxAttempts int
+
+	// RerunGates re-runs the quality gates on the rebased branch before
+	// pushing it again
+	// Default: true
+	RerunGates bool
+}
+
+// DefaultPushRetryConfig returns the default push retry configuration
+//
+// A rejected push is retried twice, re-running gates after each rebase.
+func DefaultPushRetryConfig() PushRetryConfig {
+	return PushRetryConfig{
+		MaxAttempts: 3,
+		RerunGates:  true,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c PushRetryConfig) Validate() error {
+	if c.MaxAttempts < 1 || c.MaxAttempts > 10 {
+		return fmt.Errorf("max push attempts must be between 1 and 10 (got %d)", c.MaxAttempts)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c PushRetryConfig) String() string {
+	return fmt.Sprintf("PushRetryConfig{MaxAttempts: %d, RerunGates: %v}", c.MaxAttempts, c.RerunGates)
+}
+
+// PushRetryConfigFromEnv creates a PushRetryConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_PUSH_MAX_ATTEMPTS: Pushes made before giving up when the remote keeps advancing, 1 to never retry (default: 3)
+//   - VC_PUSH_RETRY_GATES: Re-run quality gates after rebasing onto the remote (default: true)
+//
+// Returns an error if any environment variable has an invalid value.
+func PushRetryConfigFromEnv() (PushRetryConfig, error) {
+	cfg := DefaultPushRetryConfig()
+
+	if err := parseEnvInt("VC_PUSH_MAX_ATTEMPTS", &cfg.MaxAttempts); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_PUSH_RETRY_GATES", &cfg.RerunGates); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid push retry configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
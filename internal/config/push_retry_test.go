@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPushRetryConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    PushRetryConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultPushRetryConfig(),
+		},
+		{
+			name: "never retry",
+			envVars: map[string]string{
+				"VC_PUSH_MAX_ATTEMPTS": "1",
+			},
+			want: PushRetryConfig{MaxAttempts: 1, RerunGates: true},
+		},
+		{
+			name: "retry without gates",
+			envVars: map[string]string{
+				"VC_PUSH_MAX_ATTEMPTS": "5",
+				"VC_PUSH_RETRY_GATES":  "false",
+			},
+			want: PushRetryConfig{MaxAttempts: 5},
+		},
+		{
+			name: "zero attempts",
+			envVars: map[string]string{
+				"VC_PUSH_MAX_ATTEMPTS": "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "too many attempts",
+			envVars: map[string]string{
+				"VC_PUSH_MAX_ATTEMPTS": "11",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid integer value",
+			envVars: map[string]string{
+				"VC_PUSH_MAX_ATTEMPTS": "three",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_PUSH_MAX_ATTEMPTS",
+				"VC_PUSH_RETRY_GATES",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := PushRetryConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PushRetryConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
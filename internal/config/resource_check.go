@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+)
+
+// ResourceCheckConfig configures the resource checks the executor runs before
+// claiming work. Agents and test suites fail in confusing ways when the disk
+// fills up or file descriptors run out, so no issue is claimed and no quality
+// gates are run while free disk space, temp space or file descriptor
+// headroom is below its threshold.
+type ResourceCheckConfig struct {
+	// Enabled turns the resource checks on
+	// Default: true
+	Enabled bool
+
+	// MinFreeDiskMB is the free space needed on the filesystem of the
+	// working directory and sandboxes, in megabytes
+	// Default: 1024, Range: 0-1048576 (0 disables the check)
+	MinFreeDiskMB int
+
+	// MinFreeTmpMB is the free space needed in the temp directory, in
+	// megabytes
+	// Default: 512, Range: 0-1048576 (0 disables the check)
+	MinFreeTmpMB int
+
+	// MinFreeFDs is how many more files the executor must be able to open
+	// before reaching its file descriptor limit
+	// Default: 256, Range: 0-1000000 (0 disables the check)
+	MinFreeFDs int
+
+	// Cleanup removes retained failed sandboxes when a check fails, and
+	// checks again before refusing to start
+	// Default: true
+	Cleanup bool
+}
+
+// DefaultResourceCheckConfig returns the default resource check configuration
+func DefaultResourceCheckConfig() ResourceCheckConfig {
+	return ResourceCheckConfig{
+		Enabled:       true,
+		MinFreeDiskMB: 1024,
+		MinFreeTmpMB:  512,
+		MinFreeFDs:    256,
+		Cleanup:       true,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c ResourceCheckConfig) Validate() error {
+	if c.MinFreeDiskMB < 0 || c.MinFreeDiskMB > 1048576 {
+		return fmt.Errorf("min free disk must be between 0 and 1048576 MB (got %d)", c.MinFreeDiskMB)
+	}
+	if c.MinFreeTmpMB < 0 || c.MinFreeTmpMB > 1048576 {
+		return fmt.Errorf("min free tmp must be between 0 and 1048576 MB (got %d)", c.MinFreeTmpMB)
+	}
+	if c.MinFreeFDs < 0 || c.MinFreeFDs > 1000000 {
+		return fmt.Errorf("min free file descriptors must be between 0 and 1000000 (got %d)", c.MinFreeFDs)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c ResourceCheckConfig) String() string {
+	return fmt.Sprintf("ResourceCheckConfig{Enabled: %v, MinFreeDiskMB: %d, MinFreeTmpMB: %d, MinFreeFDs: %d, Cleanup: %v}",
+		c.Enabled, c.MinFreeDiskMB, c.MinFreeTmpMB, c.MinFreeFDs, c.Cleanup)
+}
+
+// ResourceCheckConfigFromEnv creates a ResourceCheckConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_RESOURCE_CHECK_ENABLED: Check resources before claiming work (default: true)
+//   - VC_MIN_FREE_DISK_MB: Free space needed for the working directory and sandboxes (default: 1024)
+//   - VC_MIN_FREE_TMP_MB: Free space needed in the temp directory (default: 512)
+//   - VC_MIN_FREE_FDS: File descriptor headroom needed (default: 256)
+//   - VC_RESOURCE_CHECK_CLEANUP: Remove failed sandboxes and check again before refusing (default: true)
+//
+// Returns an error if any environment variable has an invalid value.
+func ResourceCheckConfigFromEnv() (ResourceCheckConfig, error) {
+	cfg := DefaultResourceCheckConfig()
+
+	if err := parseEnvBool("VC_RESOURCE_CHECK_ENABLED", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_MIN_FREE_DISK_MB", &cfg.MinFreeDiskMB); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_MIN_FREE_TMP_MB", &cfg.MinFreeTmpMB); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_MIN_FREE_FDS", &cfg.MinFreeFDs); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_RESOURCE_CHECK_CLEANUP", &cfg.Cleanup); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid resource check configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
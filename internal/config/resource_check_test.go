@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestResourceCheckConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    ResourceCheckConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultResourceCheckConfig(),
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_RESOURCE_CHECK_ENABLED": "true",
+				"VC_MIN_FREE_DISK_MB":       "4096",
+				"VC_MIN_FREE_TMP_MB":        "0",
+				"VC_MIN_FREE_FDS":           "1024",
+				"VC_RESOURCE_CHECK_CLEANUP": "false",
+			},
+			want: ResourceCheckConfig{Enabled: true, MinFreeDiskMB: 4096, MinFreeTmpMB: 0, MinFreeFDs: 1024, Cleanup: false},
+		},
+		{
+			name:    "negative disk threshold",
+			envVars: map[string]string{"VC_MIN_FREE_DISK_MB": "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid file descriptor threshold",
+			envVars: map[string]string{"VC_MIN_FREE_FDS": "plenty"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_RESOURCE_CHECK_ENABLED", "VC_MIN_FREE_DISK_MB", "VC_MIN_FREE_TMP_MB",
+				"VC_MIN_FREE_FDS", "VC_RESOURCE_CHECK_CLEANUP"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := ResourceCheckConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResourceCheckConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReviewersConfig configures reviewer suggestions. When an execution opens a
+// pull request or escalates to a human, the code owners (by CODEOWNERS) and
+// recent authors of the files it touched are suggested as reviewers: on the
+// issue, in the pull request description and in the escalation comment.
+type ReviewersConfig struct {
+	// Enabled turns on reviewer suggestions
+	// Default: true
+	Enabled bool
+
+	// Max is the number of reviewers suggested
+	// Default: 3, Range: 1-20
+	Max int
+
+	// WindowDays is how far back authorship is counted
+	// Default: 180, Range: 1-3650
+	WindowDays int
+
+	// Assign makes the top suggestion the issue's assignee, if it has none
+	// Default: false
+	Assign bool
+
+	// Exclude lists authors never suggested, by email or name (e.g. bots)
+	// Default: none
+	Exclude []string
+
+	// CodeOwners suggests the owners of the files by the repository's
+	// CODEOWNERS file ahead of recent authors, and labels the issue with
+	// them (owner:<owner>)
+	// Default: true
+	CodeOwners bool
+
+	// RequestReviews asks the code owners that are users or teams to review
+	// the pull requests VC opens
+	// Default: true
+	RequestReviews bool
+}
+
+// DefaultReviewersConfig returns the default reviewer suggestion configuration
+//
+// Code owners are suggested, and asked to review pull requests, ahead of up
+// to 3 authors from the last 180 days of history, without assigning anyone.
+func DefaultReviewersConfig() ReviewersConfig {
+	return ReviewersConfig{
+		Enabled:        true,
+		Max:            3,
+		WindowDays:     180,
+		Assign:         false,
+		CodeOwners:     true,
+		RequestReviews: true,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c ReviewersConfig) Validate() error {
+	if c.Max < 1 || c.Max > 20 {
+		return fmt.Errorf("max must be between 1 and 20 (got %d)", c.Max)
+	}
+	if c.WindowDays < 1 || c.WindowDays > 3650 {
+		return fmt.Errorf("window_days must be between 1 and 3650 (got %d)", c.WindowDays)
+	}
+	for _, author := range c.Exclude {
+		if strings.TrimSpace(author) == "" {
+			return fmt.Errorf("excluded author cannot be empty")
+		}
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c ReviewersConfig) String() string {
+	return fmt.Sprintf("ReviewersConfig{Enabled: %v, Max: %d, WindowDays: %d, Assign: %v, Exclude: %v, CodeOwners: %v, RequestReviews: %v}",
+		c.Enabled, c.Max, c.WindowDays, c.Assign, c.Exclude, c.CodeOwners, c.RequestReviews)
+}
+
+// ReviewersConfigFromEnv creates a ReviewersConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_SUGGEST_REVIEWERS: suggest reviewers from recent authorship (default: true)
+//   - VC_REVIEWERS_MAX: number of reviewers suggested (default: 3)
+//   - VC_REVIEWERS_WINDOW_DAYS: days of history counted (default: 180)
+//   - VC_ASSIGN_REVIEWERS: assign the top suggestion to unassigned issues (default: false)
+//   - VC_REVIEWERS_EXCLUDE: comma-separated emails or names never suggested (default: none)
+//   - VC_REVIEWERS_CODEOWNERS: suggest and label the CODEOWNERS owners of the files (default: true)
+//   - VC_REQUEST_REVIEWS: ask code owners to review pull requests (default: true)
+//
+// Returns an error if any environment variable has an invalid value.
+func ReviewersConfigFromEnv() (ReviewersConfig, error) {
+	cfg := DefaultReviewersConfig()
+
+	if err := parseEnvBool("VC_SUGGEST_REVIEWERS", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_REVIEWERS_MAX", &cfg.Max); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_REVIEWERS_WINDOW_DAYS", &cfg.WindowDays); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_ASSIGN_REVIEWERS", &cfg.Assign); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_REVIEWERS_CODEOWNERS", &cfg.CodeOwners); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_REQUEST_REVIEWS", &cfg.RequestReviews); err != nil {
+		return cfg, err
+	}
+	var exclude string
+	parseEnvString("VC_REVIEWERS_EXCLUDE", &exclude)
+	for _, author := range strings.Split(exclude, ",") {
+		if author = strings.TrimSpace(author); author != "" {
+			cfg.Exclude = append(cfg.Exclude, author)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid reviewers configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
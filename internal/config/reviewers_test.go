@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReviewersConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    ReviewersConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultReviewersConfig(),
+		},
+		{
+			name: "custom configuration",
+			envVars: map[string]string{
+				"VC_REVIEWERS_MAX":         "5",
+				"VC_REVIEWERS_WINDOW_DAYS": "30",
+				"VC_ASSIGN_REVIEWERS":      "true",
+				"VC_REVIEWERS_EXCLUDE":     "bot@example.com, Release Bot ,",
+			},
+			want: ReviewersConfig{Enabled: true, Max: 5, WindowDays: 30, Assign: true,
+				Exclude: []string{"bot@example.com", "Release Bot"}, CodeOwners: true, RequestReviews: true},
+		},
+		{
+			name: "disabled",
+			envVars: map[string]string{
+				"VC_SUGGEST_REVIEWERS": "false",
+			},
+			want: ReviewersConfig{Max: 3, WindowDays: 180, CodeOwners: true, RequestReviews: true},
+		},
+		{
+			name: "without code owners",
+			envVars: map[string]string{
+				"VC_REVIEWERS_CODEOWNERS": "false",
+				"VC_REQUEST_REVIEWS":      "false",
+			},
+			want: ReviewersConfig{Enabled: true, Max: 3, WindowDays: 180},
+		},
+		{
+			name: "max out of range",
+			envVars: map[string]string{
+				"VC_REVIEWERS_MAX": "0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "window out of range",
+			envVars: map[string]string{
+				"VC_REVIEWERS_WINDOW_DAYS": "5000",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bool value",
+			envVars: map[string]string{
+				"VC_ASSIGN_REVIEWERS": "maybe",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_SUGGEST_REVIEWERS",
+				"VC_REVIEWERS_MAX",
+				"VC_REVIEWERS_WINDOW_DAYS",
+				"VC_ASSIGN_REVIEWERS",
+				"VC_REVIEWERS_EXCLUDE",
+				"VC_REVIEWERS_CODEOWNERS",
+				"VC_REQUEST_REVIEWS",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := ReviewersConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReviewersConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(cfg, tt.want) {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
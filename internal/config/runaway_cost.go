@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// RunawayCostConfig configures the runaway cost detector, which pauses the
+// executor when AI spend suddenly speeds up and pauses issues that cost far
+// more than issues like them, until a human approves
+type RunawayCostConfig struct {
+	// Enabled turns the runaway cost detector on
+	// Default: true
+	Enabled bool
+
+	// CheckIntervalSeconds is how often spend is checked
+	// Default: 60, Range: 10-3600
+	CheckIntervalSeconds int
+
+	// WindowMinutes is the window spend velocity is measured over
+	// Default: 10, Range: 1-1440
+	WindowMinutes int
+
+	// BaselineHours is how far back the rolling baseline reaches. Issue
+	// budgets are estimated from the issues that closed in it.
+	// Default: 24, Range: 1-720
+	BaselineHours int
+
+	// VelocityMultiplier is how many times the baseline spend per window
+	// the latest window can reach before the executor is paused
+	// Default: 10, Range: 2-1000
+	VelocityMultiplier float64
+
+	// IssueMultiplier is how many times its estimated budget an issue can
+	// spend before it is paused
+	// Default: 3, Range: 1.5-100
+	IssueMultiplier float64
+
+	// MinSpendUSD is the least spend, in a window or on an issue, that can
+	// count as runaway, so cheap bursts over a quiet baseline don't pause
+	// anything
+	// Default: 1.00, Range: 0-10000
+	MinSpendUSD float64
+}
+
+// DefaultRunawayCostConfig returns the default runaway cost configuration
+func DefaultRunawayCostConfig() RunawayCostConfig {
+	return RunawayCostConfig{
+		Enabled:              true,
+		CheckIntervalSeconds: 60,
+		WindowMinutes:        10,
+		BaselineHours:        24,
+		VelocityMultiplier:   10,
+		IssueMultiplier:      3,
+		MinSpendUSD:          1.00,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c RunawayCostConfig) Validate() error {
+	if c.CheckIntervalSeconds < 10 || c.CheckIntervalSeconds > 3600 {
+		return fmt.Errorf("check_interval_seconds must be between 10 and 3600 (got %d)", c.CheckIntervalSeconds)
+	}
+	if c.WindowMinutes < 1 || c.WindowMinutes > 1440 {
+		return fmt.Errorf("window_minutes must be between 1 and 1440 (got %d)", c.WindowMinutes)
+	}
+	if c.BaselineHours < 1 || c.BaselineHours > 720 {
+		return fmt.Errorf("baseline_hours must be between 1 and 720 (got %d)", c.BaselineHours)
+	}
+	if c.Baseline() <= 2*c.Window() {
+		return fmt.Errorf("baseline_hours (%d) must cover more than two windows of %d minutes", c.BaselineHours, c.WindowMinutes)
+	}
+	if c.VelocityMultiplier < 2 || c.VelocityMultiplier > 1000 {
+		return fmt.Errorf("velocity_multiplier must be between 2 and 1000 (got %g)", c.VelocityMultiplier)
+	}
+	if c.IssueMultiplier < 1.5 || c.IssueMultiplier > 100 {
+		return fmt.Errorf("issue_multiplier must be between 1.5 and 100 (got %g)", c.IssueMultiplier)
+	}
+	if c.MinSpendUSD < 0 || c.MinSpendUSD > 10000 {
+		return fmt.Errorf("min_spend_usd must be between 0 and 10000 (got %g)", c.MinSpendUSD)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c RunawayCostConfig) String() string {
+	return fmt.Sprintf("RunawayCostConfig{Enabled: %v, CheckIntervalSeconds: %d, WindowMinutes: %d, BaselineHours: %d, VelocityMultiplier: %g, IssueMultiplier: %g, MinSpendUSD: %.2f}",
+		c.Enabled, c.CheckIntervalSeconds, c.WindowMinutes, c.BaselineHours, c.VelocityMultiplier, c.IssueMultiplier, c.MinSpendUSD)
+}
+
+// CheckInterval returns the check interval as a time.Duration
+func (c RunawayCostConfig) CheckInterval() time.Duration {
+	return time.Duration(c.CheckIntervalSeconds) * time.Second
+}
+
+// Window returns the velocity window as a time.Duration
+func (c RunawayCostConfig) Window() time.Duration {
+	return time.Duration(c.WindowMinutes) * time.Minute
+}
+
+// Baseline returns how far back the baseline reaches
+func (c RunawayCostConfig) Baseline() time.Duration {
+	return time.Duration(c.BaselineHours) * time.Hour
+}
+
+// RunawayCostConfigFromEnv creates a RunawayCostConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_RUNAWAY_COST_ENABLED: Run the runaway cost detector (default: true)
+//   - VC_RUNAWAY_COST_CHECK_INTERVAL_SECONDS: Seconds between checks (default: 60)
+//   - VC_RUNAWAY_COST_WINDOW_MINUTES: Minutes spend velocity is measured over (default: 10)
+//   - VC_RUNAWAY_COST_BASELINE_HOURS: Hours the rolling baseline covers (default: 24)
+//   - VC_RUNAWAY_COST_VELOCITY_MULTIPLIER: Times the baseline that pauses the executor (default: 10)
+//   - VC_RUNAWAY_COST_ISSUE_MULTIPLIER: Times its estimated budget that pauses an issue (default: 3)
+//   - VC_RUNAWAY_COST_MIN_SPEND_USD: Least spend that can count as runaway (default: 1.00)
+//
+// Returns an error if any environment variable has an invalid value.
+func RunawayCostConfigFromEnv() (RunawayCostConfig, error) {
+	cfg := DefaultRunawayCostConfig()
+
+	if err := parseEnvBool("VC_RUNAWAY_COST_ENABLED", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_RUNAWAY_COST_CHECK_INTERVAL_SECONDS", &cfg.CheckIntervalSeconds); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_RUNAWAY_COST_WINDOW_MINUTES", &cfg.WindowMinutes); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_RUNAWAY_COST_BASELINE_HOURS", &cfg.BaselineHours); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvFloat("VC_RUNAWAY_COST_VELOCITY_MULTIPLIER", &cfg.VelocityMultiplier); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvFloat("VC_RUNAWAY_COST_ISSUE_MULTIPLIER", &cfg.IssueMultiplier); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvFloat("VC_RUNAWAY_COST_MIN_SPEND_USD", &cfg.MinSpendUSD); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid runaway cost configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
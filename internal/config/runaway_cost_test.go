@@ -0,0 +1,82 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunawayCostConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg RunawayCostConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg RunawayCostConfig) {
+				if !reflect.DeepEqual(cfg, DefaultRunawayCostConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultRunawayCostConfig())
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_RUNAWAY_COST_ENABLED":                "false",
+				"VC_RUNAWAY_COST_CHECK_INTERVAL_SECONDS": "30",
+				"VC_RUNAWAY_COST_WINDOW_MINUTES":         "5",
+				"VC_RUNAWAY_COST_BASELINE_HOURS":         "48",
+				"VC_RUNAWAY_COST_VELOCITY_MULTIPLIER":    "20",
+				"VC_RUNAWAY_COST_ISSUE_MULTIPLIER":       "2.5",
+				"VC_RUNAWAY_COST_MIN_SPEND_USD":          "0.25",
+			},
+			check: func(t *testing.T, cfg RunawayCostConfig) {
+				if cfg.Enabled || cfg.VelocityMultiplier != 20 || cfg.IssueMultiplier != 2.5 || cfg.MinSpendUSD != 0.25 {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if cfg.CheckInterval() != 30*time.Second || cfg.Window() != 5*time.Minute || cfg.Baseline() != 48*time.Hour {
+					t.Errorf("durations = %v, %v, %v", cfg.CheckInterval(), cfg.Window(), cfg.Baseline())
+				}
+			},
+		},
+		{
+			name:    "velocity multiplier too low",
+			envVars: map[string]string{"VC_RUNAWAY_COST_VELOCITY_MULTIPLIER": "1"},
+			wantErr: true,
+		},
+		{
+			name:    "window longer than the baseline",
+			envVars: map[string]string{"VC_RUNAWAY_COST_WINDOW_MINUTES": "60", "VC_RUNAWAY_COST_BASELINE_HOURS": "1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid minimum spend",
+			envVars: map[string]string{"VC_RUNAWAY_COST_MIN_SPEND_USD": "a dollar"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_RUNAWAY_COST_ENABLED", "VC_RUNAWAY_COST_CHECK_INTERVAL_SECONDS", "VC_RUNAWAY_COST_WINDOW_MINUTES",
+				"VC_RUNAWAY_COST_BASELINE_HOURS", "VC_RUNAWAY_COST_VELOCITY_MULTIPLIER", "VC_RUNAWAY_COST_ISSUE_MULTIPLIER",
+				"VC_RUNAWAY_COST_MIN_SPEND_USD"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := RunawayCostConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunawayCostConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
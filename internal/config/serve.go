@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minAPITokenLength is the shortest bearer token the API server accepts
+const minAPITokenLength = 16
+
+// ServeConfig configures the REST and gRPC API servers started by
+// 'vc serve'
+type ServeConfig struct {
+	// Addr is the host:port the server listens on
+	// Default: "127.0.0.1:7390"
+	Addr string
+
+	// GRPCAddr is the host:port the gRPC API listens on. The gRPC API is
+	// not served when empty.
+	// Default: ""
+	GRPCAddr string
+
+	// Tokens lists the API's bearer tokens as comma-separated actor:token
+	// pairs. Requests authenticate with "Authorization: Bearer <token>" and
+	// changes they make are recorded as made by the token's actor.
+	// Default: "" (the server refuses to start without a token)
+	Tokens string
+}
+
+// DefaultServeConfig returns the default API server configuration
+//
+// The server listens on localhost only and has no tokens.
+func DefaultServeConfig() ServeConfig {
+	return ServeConfig{
+		Addr: "127.0.0.1:7390",
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c ServeConfig) Validate() error {
+	if c.Addr == "" {
+		return fmt.Errorf("API server address is required")
+	}
+	if c.GRPCAddr != "" && c.GRPCAddr == c.Addr {
+		return fmt.Errorf("gRPC and REST API servers can't share an address (%s)", c.Addr)
+	}
+	tokens, err := c.ParseTokens()
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("at least one API token is required (VC_API_TOKENS=actor:token)")
+	}
+	return nil
+}
+
+// ParseTokens returns the actor of each token in Tokens
+func (c ServeConfig) ParseTokens() (map[string]string, error) {
+	tokens := map[string]string{}
+	for _, pair := range strings.Split(c.Tokens, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		actor, token, ok := strings.Cut(pair, ":")
+		actor, token = strings.TrimSpace(actor), strings.TrimSpace(token)
+		if !ok || actor == "" || token == "" {
+			return nil, fmt.Errorf("API tokens must be actor:token pairs (got %q)", pair)
+		}
+		if len(token) < minAPITokenLength {
+			return nil, fmt.Errorf("API token for %s must be at least %d characters", actor, minAPITokenLength)
+		}
+		if other, dup := tokens[token]; dup {
+			return nil, fmt.Errorf("API token for %s is also used by %s", actor, other)
+		}
+		tokens[token] = actor
+	}
+	return tokens, nil
+}
+
+// String returns a human-readable representation of the config, without
+// the tokens themselves
+func (c ServeConfig) String() string {
+	tokens, _ := c.ParseTokens()
+	return fmt.Sprintf("ServeConfig{Addr: %s, GRPCAddr: %s, Tokens: %d}", c.Addr, c.GRPCAddr, len(tokens))
+}
+
+// ServeConfigFromEnv creates a ServeConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_API_ADDR: host:port the API server listens on (default: 127.0.0.1:7390)
+//   - VC_GRPC_ADDR: host:port the gRPC API listens on (default: not served)
+//   - VC_API_TOKENS: Comma-separated actor:token pairs accepted as bearer tokens
+//
+// Returns an error if any environment variable has an invalid value.
+func ServeConfigFromEnv() (ServeConfig, error) {
+	cfg := DefaultServeConfig()
+
+	parseEnvString("VC_API_ADDR", &cfg.Addr)
+	parseEnvString("VC_GRPC_ADDR", &cfg.GRPCAddr)
+	parseEnvString("VC_API_TOKENS", &cfg.Tokens)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid API server configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestServeConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    ServeConfig
+	}{
+		{
+			name:    "no tokens",
+			envVars: map[string]string{},
+			wantErr: true,
+		},
+		{
+			name: "tokens on the default address",
+			envVars: map[string]string{
+				"VC_API_TOKENS": "alice:0123456789abcdef, ci:fedcba9876543210",
+			},
+			want: ServeConfig{Addr: "127.0.0.1:7390", Tokens: "alice:0123456789abcdef, ci:fedcba9876543210"},
+		},
+		{
+			name: "custom address",
+			envVars: map[string]string{
+				"VC_API_ADDR":   ":8080",
+				"VC_API_TOKENS": "alice:0123456789abcdef",
+			},
+			want: ServeConfig{Addr: ":8080", Tokens: "alice:0123456789abcdef"},
+		},
+		{
+			name: "gRPC address",
+			envVars: map[string]string{
+				"VC_GRPC_ADDR":  "127.0.0.1:7391",
+				"VC_API_TOKENS": "alice:0123456789abcdef",
+			},
+			want: ServeConfig{Addr: "127.0.0.1:7390", GRPCAddr: "127.0.0.1:7391", Tokens: "alice:0123456789abcdef"},
+		},
+		{
+			name: "gRPC on the REST address",
+			envVars: map[string]string{
+				"VC_GRPC_ADDR":  "127.0.0.1:7390",
+				"VC_API_TOKENS": "alice:0123456789abcdef",
+			},
+			wantErr: true,
+		},
+		{
+			name: "token without actor",
+			envVars: map[string]string{
+				"VC_API_TOKENS": "0123456789abcdef",
+			},
+			wantErr: true,
+		},
+		{
+			name: "short token",
+			envVars: map[string]string{
+				"VC_API_TOKENS": "alice:secret",
+			},
+			wantErr: true,
+		},
+		{
+			name: "shared token",
+			envVars: map[string]string{
+				"VC_API_TOKENS": "alice:0123456789abcdef,bob:0123456789abcdef",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_API_ADDR",
+				"VC_GRPC_ADDR",
+				"VC_API_TOKENS",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := ServeConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ServeConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeConfigParseTokens(t *testing.T) {
+	tokens, err := ServeConfig{Tokens: "alice:0123456789abcdef, ci:fedcba9876543210,"}.ParseTokens()
+	if err != nil {
+		t.Fatalf("ParseTokens() error = %v", err)
+	}
+	if len(tokens) != 2 || tokens["0123456789abcdef"] != "alice" || tokens["fedcba9876543210"] != "ci" {
+		t.Errorf("ParseTokens() = %v", tokens)
+	}
+}
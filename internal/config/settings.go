@@ -0,0 +1,306 @@
+package config
+
+import "strings"
+
+// Setting is an environment variable that configures VC. Each can also be
+// set in .vc/config.yaml (see LoadFile).
+type Setting struct {
+	// Env is the environment variable, e.g. "VC_COST_ENABLED"
+	Env string
+
+	// Secret settings (passphrases, tokens, passwords) are only read from
+	// the environment, so they stay out of files that get committed, and
+	// are masked when shown
+	Secret bool
+}
+
+// Key returns the setting's key in .vc/config.yaml, e.g. "cost_enabled"
+// for VC_COST_ENABLED. Keys can also be nested, as in "cost: {enabled: true}".
+func (s Setting) Key() string {
+	return strings.ToLower(strings.TrimPrefix(s.Env, "VC_"))
+}
+
+// Settings lists every setting VC reads, grouped by area
+var Settings = []Setting{
+	// Database
+	{Env: "VC_DB_PASSPHRASE", Secret: true},
+	{Env: "VC_DB_PATH"},
+	{Env: "VC_REMOTE_DB_PASSPHRASE", Secret: true},
+
+	// AI supervisor
+	{Env: "VC_MAX_DEPENDENCY_DEPTH"},
+	{Env: "VC_MAX_PHASE_TASKS"},
+	{Env: "VC_MAX_PLAN_PHASES"},
+	{Env: "VC_MAX_QUOTA_WAIT"},
+	{Env: "VC_MODEL_DEFAULT"},
+	{Env: "VC_MODEL_SIMPLE"},
+	{Env: "VC_VALIDATOR_TIMEOUT"},
+
+	// Executor
+	{Env: "VC_AGENT_ANOMALY_ENABLED"},
+	{Env: "VC_AGENT_ANOMALY_MAX_DELETIONS"},
+	{Env: "VC_AGENT_ANOMALY_MAX_OUT_OF_SCOPE_EDITS"},
+	{Env: "VC_AGENT_ANOMALY_MAX_REPEATED_CALLS"},
+	{Env: "VC_AGENT_ANOMALY_PROTECTED_PATHS"},
+	{Env: "VC_AGENT_PROVIDER"},
+	{Env: "VC_AUTO_APPROVE"},
+	{Env: "VC_AUTO_BACKPORT"},
+	{Env: "VC_AUTO_RELEASE"},
+	{Env: "VC_AUTO_ROLLBACK"},
+	{Env: "VC_BOOTSTRAP_MODE_LABELS"},
+	{Env: "VC_BOOTSTRAP_MODE_TITLE_KEYWORDS"},
+	{Env: "VC_BRANCH_PER_ISSUE"},
+	{Env: "VC_DAEMON_IDLE_BACKOFF"},
+	{Env: "VC_DAEMON_WORKERS"},
+	{Env: "VC_DEPENDENCY_CHECK_ENABLED"},
+	{Env: "VC_DEPENDENCY_CHECK_IDLE_INTERVAL_MINUTES"},
+	{Env: "VC_DEPENDENCY_CHECK_INTERVAL_MINUTES"},
+	{Env: "VC_DIRTY_WORKTREE"},
+	{Env: "VC_DISABLE_AI_LOOP_DETECTION"},
+	{Env: "VC_ENABLE_AUTO_COMMIT"},
+	{Env: "VC_ENABLE_AUTO_PR"},
+	{Env: "VC_ENABLE_BOOTSTRAP_MODE"},
+	{Env: "VC_ENABLE_ITERATIVE_REFINEMENT"},
+	{Env: "VC_GATES_CONFIG"},
+	{Env: "VC_HEALTH_ADDR"},
+	{Env: "VC_INSTANCE_CLEANUP_AGE_HOURS"},
+	{Env: "VC_INSTANCE_CLEANUP_KEEP"},
+	{Env: "VC_LARGE_FILES_GUARD"},
+	{Env: "VC_LOOP_DETECTOR_CHECK_INTERVAL"},
+	{Env: "VC_LOOP_DETECTOR_ENABLED"},
+	{Env: "VC_LOOP_DETECTOR_LOOKBACK_WINDOW"},
+	{Env: "VC_LOOP_DETECTOR_MIN_CONFIDENCE"},
+	{Env: "VC_MAX_BINARY_SIZE_KB"},
+	{Env: "VC_MAX_INCOMPLETE_RETRIES"},
+	{Env: "VC_MIN_FREE_DISK_MB"},
+	{Env: "VC_MIN_FREE_FDS"},
+	{Env: "VC_MIN_FREE_TMP_MB"},
+	{Env: "VC_MUTATION_COMMAND"},
+	{Env: "VC_MUTATION_ENABLED"},
+	{Env: "VC_MUTATION_HIGH_RISK_PRIORITY"},
+	{Env: "VC_MUTATION_INTERVAL"},
+	{Env: "VC_MUTATION_TIMEOUT"},
+	{Env: "VC_PATCH_PROPOSAL"},
+	{Env: "VC_PATCH_PROPOSAL_MAX_FILES"},
+	{Env: "VC_PREFLIGHT_CACHE_TTL"},
+	{Env: "VC_PREFLIGHT_ENABLED"},
+	{Env: "VC_PREFLIGHT_FAILURE_MODE"},
+	{Env: "VC_PREFLIGHT_GATES_TIMEOUT"},
+	{Env: "VC_PUBLISH_RELEASES"},
+	{Env: "VC_QUALITY_GATES_TIMEOUT"},
+	{Env: "VC_RESOURCE_CHECK_CLEANUP"},
+	{Env: "VC_RESOURCE_CHECK_ENABLED"},
+	{Env: "VC_SCOPE_VIOLATION"},
+	{Env: "VC_SELF_HEALING_DEADLOCK_TIMEOUT"},
+	{Env: "VC_SELF_HEALING_MAX_ATTEMPTS"},
+	{Env: "VC_SELF_HEALING_MAX_DURATION"},
+	{Env: "VC_SELF_HEALING_RECHECK_INTERVAL"},
+	{Env: "VC_SELF_HEALING_VERBOSE_LOGGING"},
+	{Env: "VC_SUBMODULE_POINTER_CHANGE"},
+	{Env: "VC_SUBMODULE_UPDATE"},
+
+	// Commits and pushes
+	{Env: "VC_COMMITTER_EMAIL"},
+	{Env: "VC_COMMITTER_NAME"},
+	{Env: "VC_COMMIT_AGENT_TRAILERS"},
+	{Env: "VC_COMMIT_CO_AUTHOR"},
+	{Env: "VC_COMMIT_SIGNING"},
+	{Env: "VC_COMMIT_SIGNING_KEY"},
+	{Env: "VC_PUSH_ALLOW_BINARY"},
+	{Env: "VC_PUSH_ALLOW_FORCE"},
+	{Env: "VC_PUSH_CHECKS"},
+	{Env: "VC_PUSH_MAX_ATTEMPTS"},
+	{Env: "VC_PUSH_MAX_FILE_SIZE_KB"},
+	{Env: "VC_PUSH_RETRY_GATES"},
+	{Env: "VC_PUSH_SCAN_SECRETS"},
+
+	// Git hosting
+	{Env: "VC_ASSIGN_REVIEWERS"},
+	{Env: "VC_GITHUB_API_URL"},
+	{Env: "VC_GITHUB_REPO"},
+	{Env: "VC_GITHUB_SYNC"},
+	{Env: "VC_GITHUB_SYNC_INTERVAL_MINUTES"},
+	{Env: "VC_GITHUB_SYNC_LABEL"},
+	{Env: "VC_GITHUB_SYNC_LABEL_MAP"},
+	{Env: "VC_GITHUB_SYNC_MILESTONE_PREFIX"},
+	{Env: "VC_GITHUB_SYNC_PREFER"},
+	{Env: "VC_GITHUB_TOKEN", Secret: true},
+	{Env: "VC_GITLAB_API_URL"},
+	{Env: "VC_GITLAB_PROJECT"},
+	{Env: "VC_GITLAB_SYNC"},
+	{Env: "VC_GITLAB_SYNC_INTERVAL_MINUTES"},
+	{Env: "VC_GITLAB_SYNC_LABEL"},
+	{Env: "VC_GITLAB_SYNC_LABEL_MAP"},
+	{Env: "VC_GITLAB_SYNC_MILESTONE_PREFIX"},
+	{Env: "VC_GITLAB_SYNC_PREFER"},
+	{Env: "VC_GITLAB_TOKEN", Secret: true},
+	{Env: "VC_GIT_HOSTING"},
+	{Env: "VC_GIT_HOSTING_REMOTE"},
+	{Env: "VC_PR_CHECK_RUNS"},
+	{Env: "VC_PR_DRAFT"},
+	{Env: "VC_PR_LABELS"},
+	{Env: "VC_PR_SYNC_INTERVAL_MINUTES"},
+	{Env: "VC_REQUEST_REVIEWS"},
+	{Env: "VC_REVIEWERS_CODEOWNERS"},
+	{Env: "VC_REVIEWERS_EXCLUDE"},
+	{Env: "VC_REVIEWERS_MAX"},
+	{Env: "VC_REVIEWERS_WINDOW_DAYS"},
+	{Env: "VC_SUGGEST_REVIEWERS"},
+
+	// Jira
+	{Env: "VC_JIRA_EMAIL"},
+	{Env: "VC_JIRA_EPIC_LINK_FIELD"},
+	{Env: "VC_JIRA_FIELD_MAP"},
+	{Env: "VC_JIRA_JQL"},
+	{Env: "VC_JIRA_PROJECT"},
+	{Env: "VC_JIRA_STATUS_MAP"},
+	{Env: "VC_JIRA_SYNC"},
+	{Env: "VC_JIRA_SYNC_INTERVAL_MINUTES"},
+	{Env: "VC_JIRA_TOKEN", Secret: true},
+	{Env: "VC_JIRA_URL"},
+
+	// Error tracker
+	{Env: "VC_ERROR_TRACKER_ADDR"},
+	{Env: "VC_ERROR_TRACKER_ENVIRONMENTS"},
+	{Env: "VC_ERROR_TRACKER_FLUSH_INTERVAL_SECONDS"},
+	{Env: "VC_ERROR_TRACKER_MIN_EVENTS"},
+	{Env: "VC_ERROR_TRACKER_SECRET", Secret: true},
+
+	// Cost and quota
+	{Env: "VC_COST_ALERT_THRESHOLD"},
+	{Env: "VC_COST_BUDGET_RESET_INTERVAL"},
+	{Env: "VC_COST_ENABLED"},
+	{Env: "VC_COST_INPUT_TOKEN_COST"},
+	{Env: "VC_COST_MAX_COST_PER_HOUR"},
+	{Env: "VC_COST_MAX_TOKENS_PER_HOUR"},
+	{Env: "VC_COST_MAX_TOKENS_PER_ISSUE"},
+	{Env: "VC_COST_OUTPUT_TOKEN_COST"},
+	{Env: "VC_COST_PERSIST_STATE_PATH"},
+	{Env: "VC_ENABLE_QUOTA_MONITORING"},
+	{Env: "VC_QUOTA_ALERT_ORANGE"},
+	{Env: "VC_QUOTA_ALERT_RED"},
+	{Env: "VC_QUOTA_ALERT_YELLOW"},
+	{Env: "VC_QUOTA_AUTO_CREATE_CRISIS_ISSUE"},
+	{Env: "VC_QUOTA_RETENTION_DAYS"},
+	{Env: "VC_QUOTA_SNAPSHOT_INTERVAL"},
+	{Env: "VC_RUNAWAY_COST_BASELINE_HOURS"},
+	{Env: "VC_RUNAWAY_COST_CHECK_INTERVAL_SECONDS"},
+	{Env: "VC_RUNAWAY_COST_ENABLED"},
+	{Env: "VC_RUNAWAY_COST_ISSUE_MULTIPLIER"},
+	{Env: "VC_RUNAWAY_COST_MIN_SPEND_USD"},
+	{Env: "VC_RUNAWAY_COST_VELOCITY_MULTIPLIER"},
+	{Env: "VC_RUNAWAY_COST_WINDOW_MINUTES"},
+
+	// Deduplication
+	{Env: "VC_DEDUP_BATCH_SIZE"},
+	{Env: "VC_DEDUP_CONFIDENCE_THRESHOLD"},
+	{Env: "VC_DEDUP_FAIL_OPEN"},
+	{Env: "VC_DEDUP_INCLUDE_CLOSED"},
+	{Env: "VC_DEDUP_LOOKBACK_DAYS"},
+	{Env: "VC_DEDUP_MAX_CANDIDATES"},
+	{Env: "VC_DEDUP_MAX_RETRIES"},
+	{Env: "VC_DEDUP_MIN_TITLE_LENGTH"},
+	{Env: "VC_DEDUP_TIMEOUT_SECS"},
+	{Env: "VC_DEDUP_WITHIN_BATCH"},
+
+	// Watchdog
+	{Env: "VC_STUCK_WORK_ALLOW_KILL"},
+	{Env: "VC_STUCK_WORK_CHECK_INTERVAL_SECONDS"},
+	{Env: "VC_STUCK_WORK_ENABLED"},
+	{Env: "VC_STUCK_WORK_IDLE_MINUTES"},
+	{Env: "VC_STUCK_WORK_MAX_IN_PROGRESS_MINUTES"},
+	{Env: "VC_WATCHDOG_AUTO_KILL"},
+	{Env: "VC_WATCHDOG_BACKOFF_BASE_INTERVAL"},
+	{Env: "VC_WATCHDOG_BACKOFF_ENABLED"},
+	{Env: "VC_WATCHDOG_BACKOFF_MAX_INTERVAL"},
+	{Env: "VC_WATCHDOG_BACKOFF_MULTIPLIER"},
+	{Env: "VC_WATCHDOG_BACKOFF_THRESHOLD"},
+	{Env: "VC_WATCHDOG_CHECK_INTERVAL"},
+	{Env: "VC_WATCHDOG_ENABLED"},
+	{Env: "VC_WATCHDOG_ESCALATE_CRITICAL"},
+	{Env: "VC_WATCHDOG_LOG_ANOMALIES"},
+	{Env: "VC_WATCHDOG_MAX_HISTORY"},
+	{Env: "VC_WATCHDOG_MAX_RETRIES"},
+	{Env: "VC_WATCHDOG_MIN_CONFIDENCE"},
+	{Env: "VC_WATCHDOG_MIN_SEVERITY"},
+	{Env: "VC_WATCHDOG_TELEMETRY_WINDOW"},
+
+	// Events, backups and maintenance
+	{Env: "VC_BACKUP_DIR"},
+	{Env: "VC_BACKUP_ENABLED"},
+	{Env: "VC_BACKUP_INTERVAL_HOURS"},
+	{Env: "VC_BACKUP_KEEP"},
+	{Env: "VC_EVENT_CLEANUP_BATCH_SIZE"},
+	{Env: "VC_EVENT_CLEANUP_ENABLED"},
+	{Env: "VC_EVENT_CLEANUP_INTERVAL_HOURS"},
+	{Env: "VC_EVENT_CLEANUP_STRATEGY"},
+	{Env: "VC_EVENT_CLEANUP_VACUUM"},
+	{Env: "VC_EVENT_GLOBAL_LIMIT"},
+	{Env: "VC_EVENT_ISSUE_RETENTION_DAYS"},
+	{Env: "VC_EVENT_PER_ISSUE_LIMIT"},
+	{Env: "VC_EVENT_RETENTION_CRITICAL_DAYS"},
+	{Env: "VC_EVENT_RETENTION_DAYS"},
+	{Env: "VC_MAINTENANCE_DEDUP_MAX_ISSUES"},
+	{Env: "VC_MAINTENANCE_ENABLED"},
+	{Env: "VC_MAINTENANCE_SCHEDULE"},
+	{Env: "VC_MAINTENANCE_STALE_SANDBOX_DAYS"},
+	{Env: "VC_MAINTENANCE_VACUUM"},
+
+	// Notifications
+	{Env: "VC_EMAIL_ALERTS"},
+	{Env: "VC_EMAIL_DIGEST"},
+	{Env: "VC_EMAIL_DIGEST_HOUR"},
+	{Env: "VC_EMAIL_FROM"},
+	{Env: "VC_EMAIL_POLL_INTERVAL_SECONDS"},
+	{Env: "VC_EMAIL_TO"},
+	{Env: "VC_PAGERDUTY_EVENTS_URL"},
+	{Env: "VC_PAGERDUTY_FAILURE_THRESHOLD"},
+	{Env: "VC_PAGERDUTY_POLL_INTERVAL_SECONDS"},
+	{Env: "VC_PAGERDUTY_ROUTING_KEY", Secret: true},
+	{Env: "VC_PAGERDUTY_SOURCE"},
+	{Env: "VC_SLACK_ADDR"},
+	{Env: "VC_SLACK_API_URL"},
+	{Env: "VC_SLACK_APPROVERS"},
+	{Env: "VC_SLACK_BOT_TOKEN", Secret: true},
+	{Env: "VC_SLACK_CHANNEL"},
+	{Env: "VC_SLACK_POLL_INTERVAL_SECONDS"},
+	{Env: "VC_SLACK_SIGNING_SECRET", Secret: true},
+	{Env: "VC_SMTP_HOST"},
+	{Env: "VC_SMTP_PASSWORD", Secret: true},
+	{Env: "VC_SMTP_PORT"},
+	{Env: "VC_SMTP_USERNAME"},
+	{Env: "VC_WEBHOOK_EVENTS"},
+	{Env: "VC_WEBHOOK_MAX_ATTEMPTS"},
+	{Env: "VC_WEBHOOK_POLL_INTERVAL_SECONDS"},
+	{Env: "VC_WEBHOOK_SECRET", Secret: true},
+	{Env: "VC_WEBHOOK_TIMEOUT_SECONDS"},
+	{Env: "VC_WEBHOOK_URL"},
+
+	// API server
+	{Env: "VC_API_ADDR"},
+	{Env: "VC_API_TOKENS", Secret: true},
+	{Env: "VC_GRPC_ADDR"},
+
+	// Logging, tracing and debugging
+	{Env: "VC_DEBUG_EVENTS"},
+	{Env: "VC_DEBUG_PROMPTS"},
+	{Env: "VC_DEBUG_STATUS"},
+	{Env: "VC_DEBUG_WORK_SELECTION"},
+	{Env: "VC_LOG_FORMAT"},
+	{Env: "VC_LOG_LEVEL"},
+	{Env: "VC_TRACING"},
+	{Env: "VC_TRACING_ENDPOINT"},
+	{Env: "VC_TRACING_INSECURE"},
+	{Env: "VC_TRACING_SAMPLE_PERCENT"},
+	{Env: "VC_TRACING_SERVICE_NAME"},
+}
+
+// LookupSetting returns the setting for an environment variable
+func LookupSetting(env string) (Setting, bool) {
+	for _, s := range Settings {
+		if s.Env == env {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
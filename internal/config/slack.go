@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SlackConfig configures the Slack app, which posts escalations and gate
+// override requests to a channel with Approve and Reject buttons, and
+// records the decisions made with them
+type SlackConfig struct {
+	// BotToken is the app's bot token (xoxb-...). Slack is disabled when
+	// empty.
+	// Default: ""
+	BotToken string
+
+	// SigningSecret verifies that button clicks come from Slack. Required
+	// when BotToken is set.
+	// Default: ""
+	SigningSecret string
+
+	// Channel is the channel ID (or #name) requests are posted to.
+	// Required when BotToken is set.
+	// Default: ""
+	Channel string
+
+	// Addr is the host:port the interactivity endpoint listens on; Slack
+	// must reach its /slack/interactions over HTTPS, e.g. through a
+	// reverse proxy
+	// Default: "127.0.0.1:7391"
+	Addr string
+
+	// Approvers limits who can decide to these Slack user IDs
+	// Default: none (anyone who can click the buttons)
+	Approvers []string
+
+	// PollIntervalSeconds is how often the database is checked for new
+	// escalations and override requests
+	// Default: 5, Range: 1-3600
+	PollIntervalSeconds int
+
+	// APIURL is the Slack Web API base URL
+	// Default: "https://slack.com/api"
+	APIURL string
+}
+
+// DefaultSlackConfig returns the default Slack configuration
+func DefaultSlackConfig() SlackConfig {
+	return SlackConfig{
+		Addr:                "127.0.0.1:7391",
+		PollIntervalSeconds: 5,
+		APIURL:              "https://slack.com/api",
+	}
+}
+
+// Enabled reports whether a bot token is configured
+func (c SlackConfig) Enabled() bool {
+	return c.BotToken != ""
+}
+
+// Validate checks if the configuration has valid values
+func (c SlackConfig) Validate() error {
+	if c.PollIntervalSeconds < 1 || c.PollIntervalSeconds > 3600 {
+		return fmt.Errorf("poll_interval_seconds must be between 1 and 3600 (got %d)", c.PollIntervalSeconds)
+	}
+	if !strings.HasPrefix(c.APIURL, "https://") && !strings.HasPrefix(c.APIURL, "http://") {
+		return fmt.Errorf("Slack API URL must be an http(s) URL (got %q)", c.APIURL)
+	}
+	for _, approver := range c.Approvers {
+		if strings.TrimSpace(approver) == "" {
+			return fmt.Errorf("approver IDs cannot be empty")
+		}
+	}
+	if c.BotToken == "" {
+		return nil
+	}
+	if c.SigningSecret == "" {
+		return fmt.Errorf("Slack signing secret is required to verify button clicks (VC_SLACK_SIGNING_SECRET)")
+	}
+	if c.Channel == "" {
+		return fmt.Errorf("Slack channel is required (VC_SLACK_CHANNEL)")
+	}
+	if c.Addr == "" {
+		return fmt.Errorf("interactivity address is required (VC_SLACK_ADDR)")
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config. The token
+// and signing secret are never included.
+func (c SlackConfig) String() string {
+	return fmt.Sprintf("SlackConfig{BotToken: %v, SigningSecret: %v, Channel: %q, Addr: %q, Approvers: %v, PollIntervalSeconds: %d, APIURL: %q}",
+		c.BotToken != "", c.SigningSecret != "", c.Channel, c.Addr, c.Approvers, c.PollIntervalSeconds, c.APIURL)
+}
+
+// PollInterval returns the poll interval as a time.Duration
+func (c SlackConfig) PollInterval() time.Duration {
+	return time.Duration(c.PollIntervalSeconds) * time.Second
+}
+
+// CanApprove reports whether the Slack user may approve or reject requests
+func (c SlackConfig) CanApprove(userID string) bool {
+	if len(c.Approvers) == 0 {
+		return true
+	}
+	for _, approver := range c.Approvers {
+		if approver == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// SlackConfigFromEnv creates a SlackConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_SLACK_BOT_TOKEN: Bot token of the Slack app (default: disabled)
+//   - VC_SLACK_SIGNING_SECRET: Signing secret of the Slack app (required with a token)
+//   - VC_SLACK_CHANNEL: Channel requests are posted to (required with a token)
+//   - VC_SLACK_ADDR: host:port of the interactivity endpoint (default: 127.0.0.1:7391)
+//   - VC_SLACK_APPROVERS: Comma-separated Slack user IDs allowed to decide (default: anyone)
+//   - VC_SLACK_POLL_INTERVAL_SECONDS: Seconds between checks for requests (default: 5)
+//   - VC_SLACK_API_URL: Slack Web API base URL (default: https://slack.com/api)
+//
+// Returns an error if any environment variable has an invalid value.
+func SlackConfigFromEnv() (SlackConfig, error) {
+	cfg := DefaultSlackConfig()
+
+	parseEnvString("VC_SLACK_BOT_TOKEN", &cfg.BotToken)
+	parseEnvString("VC_SLACK_SIGNING_SECRET", &cfg.SigningSecret)
+	parseEnvString("VC_SLACK_CHANNEL", &cfg.Channel)
+	parseEnvString("VC_SLACK_ADDR", &cfg.Addr)
+	var approvers string
+	parseEnvString("VC_SLACK_APPROVERS", &approvers)
+	for _, approver := range strings.Split(approvers, ",") {
+		if approver = strings.TrimSpace(approver); approver != "" {
+			cfg.Approvers = append(cfg.Approvers, approver)
+		}
+	}
+	if err := parseEnvInt("VC_SLACK_POLL_INTERVAL_SECONDS", &cfg.PollIntervalSeconds); err != nil {
+		return cfg, err
+	}
+	parseEnvString("VC_SLACK_API_URL", &cfg.APIURL)
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid Slack configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlackConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg SlackConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg SlackConfig) {
+				if !reflect.DeepEqual(cfg, DefaultSlackConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultSlackConfig())
+				}
+				if cfg.Enabled() {
+					t.Error("Enabled() = true without a bot token")
+				}
+				if !cfg.CanApprove("U123") {
+					t.Error("CanApprove() = false without approvers")
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_SLACK_BOT_TOKEN":             "xoxb-secret",
+				"VC_SLACK_SIGNING_SECRET":        "s3cret",
+				"VC_SLACK_CHANNEL":               "C0123",
+				"VC_SLACK_ADDR":                  ":8080",
+				"VC_SLACK_APPROVERS":             "U1, U2 ,",
+				"VC_SLACK_POLL_INTERVAL_SECONDS": "30",
+				"VC_SLACK_API_URL":               "http://localhost:9000/api/",
+			},
+			check: func(t *testing.T, cfg SlackConfig) {
+				if !cfg.Enabled() || cfg.Channel != "C0123" || cfg.Addr != ":8080" || cfg.APIURL != "http://localhost:9000/api" {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if !reflect.DeepEqual(cfg.Approvers, []string{"U1", "U2"}) {
+					t.Errorf("Approvers = %q, want [U1 U2]", cfg.Approvers)
+				}
+				if !cfg.CanApprove("U2") || cfg.CanApprove("U3") {
+					t.Errorf("CanApprove() doesn't follow the approvers %v", cfg.Approvers)
+				}
+				if cfg.PollInterval() != 30*time.Second {
+					t.Errorf("PollInterval() = %v, want 30s", cfg.PollInterval())
+				}
+				if s := cfg.String(); strings.Contains(s, "xoxb-secret") || strings.Contains(s, "s3cret") {
+					t.Errorf("String() must not include the token or secret: %s", s)
+				}
+			},
+		},
+		{
+			name:    "token without a signing secret",
+			envVars: map[string]string{"VC_SLACK_BOT_TOKEN": "xoxb-secret", "VC_SLACK_CHANNEL": "C0123"},
+			wantErr: true,
+		},
+		{
+			name:    "token without a channel",
+			envVars: map[string]string{"VC_SLACK_BOT_TOKEN": "xoxb-secret", "VC_SLACK_SIGNING_SECRET": "s3cret"},
+			wantErr: true,
+		},
+		{
+			name:    "API URL that isn't http(s)",
+			envVars: map[string]string{"VC_SLACK_API_URL": "slack.com/api"},
+			wantErr: true,
+		},
+		{
+			name:    "poll interval out of range",
+			envVars: map[string]string{"VC_SLACK_POLL_INTERVAL_SECONDS": "0"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_SLACK_BOT_TOKEN", "VC_SLACK_SIGNING_SECRET", "VC_SLACK_CHANNEL", "VC_SLACK_ADDR",
+				"VC_SLACK_APPROVERS", "VC_SLACK_POLL_INTERVAL_SECONDS", "VC_SLACK_API_URL"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := SlackConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SlackConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// StuckWorkConfig configures the stuck work watchdog, which looks for
+// issues in progress for too long, running executions that went quiet and
+// claims held by dead workers, and has the AI supervisor decide whether to
+// nudge, kill or escalate each
+type StuckWorkConfig struct {
+	// Enabled turns the stuck work watchdog on
+	// Default: true
+	Enabled bool
+
+	// CheckIntervalSeconds is how often the database is scanned
+	// Default: 300, Range: 10-86400
+	CheckIntervalSeconds int
+
+	// MaxInProgressMinutes is how long an issue can stay claimed before it
+	// counts as stuck
+	// Default: 240, Range: 1-10080
+	MaxInProgressMinutes int
+
+	// IdleMinutes is how long a running execution can go without agent
+	// events or output before it counts as stuck. It is also how long the
+	// watchdog leaves an issue alone after acting on it.
+	// Default: 30, Range: 1-1440
+	IdleMinutes int
+
+	// AllowKill lets the watchdog stop agents and release claims. When
+	// false, kill decisions are downgraded to escalations.
+	// Default: true
+	AllowKill bool
+}
+
+// DefaultStuckWorkConfig returns the default stuck work configuration
+func DefaultStuckWorkConfig() StuckWorkConfig {
+	return StuckWorkConfig{
+		Enabled:              true,
+		CheckIntervalSeconds: 300,
+		MaxInProgressMinutes: 240,
+		IdleMinutes:          30,
+		AllowKill:            true,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c StuckWorkConfig) Validate() error {
+	if c.CheckIntervalSeconds < 10 || c.CheckIntervalSeconds > 86400 {
+		return fmt.Errorf("check_interval_seconds must be between 10 and 86400 (got %d)", c.CheckIntervalSeconds)
+	}
+	if c.MaxInProgressMinutes < 1 || c.MaxInProgressMinutes > 10080 {
+		return fmt.Errorf("max_in_progress_minutes must be between 1 and 10080 (got %d)", c.MaxInProgressMinutes)
+	}
+	if c.IdleMinutes < 1 || c.IdleMinutes > 1440 {
+		return fmt.Errorf("idle_minutes must be between 1 and 1440 (got %d)", c.IdleMinutes)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c StuckWorkConfig) String() string {
+	return fmt.Sprintf("StuckWorkConfig{Enabled: %v, CheckIntervalSeconds: %d, MaxInProgressMinutes: %d, IdleMinutes: %d, AllowKill: %v}",
+		c.Enabled, c.CheckIntervalSeconds, c.MaxInProgressMinutes, c.IdleMinutes, c.AllowKill)
+}
+
+// CheckInterval returns the check interval as a time.Duration
+func (c StuckWorkConfig) CheckInterval() time.Duration {
+	return time.Duration(c.CheckIntervalSeconds) * time.Second
+}
+
+// MaxInProgress returns how long an issue can stay claimed
+func (c StuckWorkConfig) MaxInProgress() time.Duration {
+	return time.Duration(c.MaxInProgressMinutes) * time.Minute
+}
+
+// Idle returns how long an execution can go quiet
+func (c StuckWorkConfig) Idle() time.Duration {
+	return time.Duration(c.IdleMinutes) * time.Minute
+}
+
+// StuckWorkConfigFromEnv creates a StuckWorkConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_STUCK_WORK_ENABLED: Run the stuck work watchdog (default: true)
+//   - VC_STUCK_WORK_CHECK_INTERVAL_SECONDS: Seconds between scans (default: 300)
+//   - VC_STUCK_WORK_MAX_IN_PROGRESS_MINUTES: Minutes an issue can stay claimed (default: 240)
+//   - VC_STUCK_WORK_IDLE_MINUTES: Minutes an execution can go quiet (default: 30)
+//   - VC_STUCK_WORK_ALLOW_KILL: Let the watchdog stop agents and release claims (default: true)
+//
+// Returns an error if any environment variable has an invalid value.
+func StuckWorkConfigFromEnv() (StuckWorkConfig, error) {
+	cfg := DefaultStuckWorkConfig()
+
+	if err := parseEnvBool("VC_STUCK_WORK_ENABLED", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_STUCK_WORK_CHECK_INTERVAL_SECONDS", &cfg.CheckIntervalSeconds); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_STUCK_WORK_MAX_IN_PROGRESS_MINUTES", &cfg.MaxInProgressMinutes); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_STUCK_WORK_IDLE_MINUTES", &cfg.IdleMinutes); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvBool("VC_STUCK_WORK_ALLOW_KILL", &cfg.AllowKill); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid stuck work configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
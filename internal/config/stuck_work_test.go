@@ -0,0 +1,79 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStuckWorkConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg StuckWorkConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg StuckWorkConfig) {
+				if !reflect.DeepEqual(cfg, DefaultStuckWorkConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultStuckWorkConfig())
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_STUCK_WORK_ENABLED":                 "false",
+				"VC_STUCK_WORK_CHECK_INTERVAL_SECONDS":  "60",
+				"VC_STUCK_WORK_MAX_IN_PROGRESS_MINUTES": "90",
+				"VC_STUCK_WORK_IDLE_MINUTES":            "10",
+				"VC_STUCK_WORK_ALLOW_KILL":              "false",
+			},
+			check: func(t *testing.T, cfg StuckWorkConfig) {
+				if cfg.Enabled || cfg.AllowKill {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if cfg.CheckInterval() != time.Minute || cfg.MaxInProgress() != 90*time.Minute || cfg.Idle() != 10*time.Minute {
+					t.Errorf("durations = %v, %v, %v", cfg.CheckInterval(), cfg.MaxInProgress(), cfg.Idle())
+				}
+			},
+		},
+		{
+			name:    "check interval too short",
+			envVars: map[string]string{"VC_STUCK_WORK_CHECK_INTERVAL_SECONDS": "1"},
+			wantErr: true,
+		},
+		{
+			name:    "idle minutes out of range",
+			envVars: map[string]string{"VC_STUCK_WORK_IDLE_MINUTES": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid allow kill flag",
+			envVars: map[string]string{"VC_STUCK_WORK_ALLOW_KILL": "maybe"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_STUCK_WORK_ENABLED", "VC_STUCK_WORK_CHECK_INTERVAL_SECONDS", "VC_STUCK_WORK_MAX_IN_PROGRESS_MINUTES",
+				"VC_STUCK_WORK_IDLE_MINUTES", "VC_STUCK_WORK_ALLOW_KILL"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := StuckWorkConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StuckWorkConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+)
+
+// What the executor does when an agent moves a submodule pointer (commits
+// inside a submodule, or checks out a different commit of it)
+const (
+	SubmodulePointerRevert = "revert" // Check the submodule out at the recorded commit again and note it on the issue
+	SubmodulePointerCommit = "commit" // Commit the new pointer with the agent's changes and note it on the issue
+)
+
+// SubmodulesConfig configures how repositories with git submodules are
+// handled. Uncommitted changes inside a submodule can never be committed by
+// the superproject, so they are always left out of auto-commits.
+type SubmodulesConfig struct {
+	// Update initializes and updates submodules before quality gates run
+	// Default: true
+	Update bool
+
+	// OnPointerChange is "revert" or "commit"
+	// Default: "revert"
+	OnPointerChange string
+}
+
+// DefaultSubmodulesConfig returns the default submodule configuration
+//
+// Submodules are updated before gates and pointer changes are reverted.
+func DefaultSubmodulesConfig() SubmodulesConfig {
+	return SubmodulesConfig{
+		Update:          true,
+		OnPointerChange: SubmodulePointerRevert,
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c SubmodulesConfig) Validate() error {
+	if c.OnPointerChange != SubmodulePointerRevert && c.OnPointerChange != SubmodulePointerCommit {
+		return fmt.Errorf("submodule pointer change action must be %q or %q (got %q)", SubmodulePointerRevert, SubmodulePointerCommit, c.OnPointerChange)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c SubmodulesConfig) String() string {
+	return fmt.Sprintf("SubmodulesConfig{Update: %v, OnPointerChange: %s}", c.Update, c.OnPointerChange)
+}
+
+// SubmodulesConfigFromEnv creates a SubmodulesConfig from environment
+// variables, falling back to defaults
+//
+// Environment variables:
+//   - VC_SUBMODULE_UPDATE: Initialize and update submodules before gates (default: true)
+//   - VC_SUBMODULE_POINTER_CHANGE: revert or commit submodule pointers an agent moved (default: revert)
+//
+// Returns an error if any environment variable has an invalid value.
+func SubmodulesConfigFromEnv() (SubmodulesConfig, error) {
+	cfg := DefaultSubmodulesConfig()
+
+	if err := parseEnvBool("VC_SUBMODULE_UPDATE", &cfg.Update); err != nil {
+		return cfg, err
+	}
+	parseEnvString("VC_SUBMODULE_POINTER_CHANGE", &cfg.OnPointerChange)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid submodules configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSubmodulesConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    SubmodulesConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultSubmodulesConfig(),
+		},
+		{
+			name: "commit pointer changes without updating",
+			envVars: map[string]string{
+				"VC_SUBMODULE_UPDATE":         "false",
+				"VC_SUBMODULE_POINTER_CHANGE": "commit",
+			},
+			want: SubmodulesConfig{OnPointerChange: SubmodulePointerCommit},
+		},
+		{
+			name: "invalid action",
+			envVars: map[string]string{
+				"VC_SUBMODULE_POINTER_CHANGE": "ignore",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bool value",
+			envVars: map[string]string{
+				"VC_SUBMODULE_UPDATE": "maybe",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_SUBMODULE_UPDATE",
+				"VC_SUBMODULE_POINTER_CHANGE",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := SubmodulesConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SubmodulesConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
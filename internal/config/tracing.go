@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+)
+
+// TracingConfig configures OpenTelemetry tracing of executions. Spans are
+// exported over OTLP/HTTP; the standard OTEL_EXPORTER_OTLP_* variables
+// (endpoint, headers, TLS) are honoured by the exporter.
+type TracingConfig struct {
+	// Enabled turns on span export
+	// Default: false
+	Enabled bool
+
+	// Endpoint is the OTLP/HTTP collector as host:port, overriding
+	// OTEL_EXPORTER_OTLP_ENDPOINT
+	// Default: "" (the exporter's default, localhost:4318)
+	Endpoint string
+
+	// Insecure exports over plain HTTP rather than HTTPS
+	// Default: false
+	Insecure bool
+
+	// SamplePercent is the percentage of executions traced
+	// Default: 100, Range: 0-100
+	SamplePercent int
+
+	// ServiceName is the service.name resource attribute
+	// Default: "vc"
+	ServiceName string
+}
+
+// DefaultTracingConfig returns the default tracing configuration
+//
+// Tracing is off; when turned on, every execution is traced.
+func DefaultTracingConfig() TracingConfig {
+	return TracingConfig{
+		Enabled:       false,
+		SamplePercent: 100,
+		ServiceName:   "vc",
+	}
+}
+
+// Validate checks if the configuration has valid values
+func (c TracingConfig) Validate() error {
+	if c.SamplePercent < 0 || c.SamplePercent > 100 {
+		return fmt.Errorf("trace sample percent must be between 0 and 100 (got %d)", c.SamplePercent)
+	}
+	if c.Enabled && c.ServiceName == "" {
+		return fmt.Errorf("trace service name is required when tracing is enabled")
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config
+func (c TracingConfig) String() string {
+	return fmt.Sprintf("TracingConfig{Enabled: %v, Endpoint: %q, Insecure: %v, SamplePercent: %d, ServiceName: %q}",
+		c.Enabled, c.Endpoint, c.Insecure, c.SamplePercent, c.ServiceName)
+}
+
+// TracingConfigFromEnv creates a TracingConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_TRACING: Export OpenTelemetry spans for executions (default: false)
+//   - VC_TRACING_ENDPOINT: OTLP/HTTP collector host:port (default: OTEL_EXPORTER_OTLP_ENDPOINT, else localhost:4318)
+//   - VC_TRACING_INSECURE: Export over plain HTTP (default: false)
+//   - VC_TRACING_SAMPLE_PERCENT: Percentage of executions traced (default: 100)
+//   - VC_TRACING_SERVICE_NAME: service.name of exported spans (default: vc)
+//
+// Returns an error if any environment variable has an invalid value.
+func TracingConfigFromEnv() (TracingConfig, error) {
+	cfg := DefaultTracingConfig()
+
+	if err := parseEnvBool("VC_TRACING", &cfg.Enabled); err != nil {
+		return cfg, err
+	}
+	parseEnvString("VC_TRACING_ENDPOINT", &cfg.Endpoint)
+	if err := parseEnvBool("VC_TRACING_INSECURE", &cfg.Insecure); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_TRACING_SAMPLE_PERCENT", &cfg.SamplePercent); err != nil {
+		return cfg, err
+	}
+	parseEnvString("VC_TRACING_SERVICE_NAME", &cfg.ServiceName)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid tracing configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTracingConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		want    TracingConfig
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			want:    DefaultTracingConfig(),
+		},
+		{
+			name: "enabled with a local collector",
+			envVars: map[string]string{
+				"VC_TRACING":                "true",
+				"VC_TRACING_ENDPOINT":       "localhost:4318",
+				"VC_TRACING_INSECURE":       "true",
+				"VC_TRACING_SAMPLE_PERCENT": "25",
+				"VC_TRACING_SERVICE_NAME":   "vc-staging",
+			},
+			want: TracingConfig{Enabled: true, Endpoint: "localhost:4318", Insecure: true, SamplePercent: 25, ServiceName: "vc-staging"},
+		},
+		{
+			name: "sample percent out of range",
+			envVars: map[string]string{
+				"VC_TRACING_SAMPLE_PERCENT": "101",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid boolean value",
+			envVars: map[string]string{
+				"VC_TRACING": "sometimes",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv := []string{
+				"VC_TRACING",
+				"VC_TRACING_ENDPOINT",
+				"VC_TRACING_INSECURE",
+				"VC_TRACING_SAMPLE_PERCENT",
+				"VC_TRACING_SERVICE_NAME",
+			}
+			for _, key := range clearEnv {
+				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+			}
+			for key, value := range tt.envVars {
+				_ = os.Setenv(key, value) // Intentionally ignore error in test setup
+			}
+			defer func() {
+				for _, key := range clearEnv {
+					_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
+				}
+			}()
+
+			cfg, err := TracingConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TracingConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %v, want %v", cfg, tt.want)
+			}
+		})
+	}
+}
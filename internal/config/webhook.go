@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WebhookConfig configures outbound webhooks, which POST lifecycle events
+// (issue status changes, finished executions, gate failures, escalations)
+// to an external URL
+type WebhookConfig struct {
+	// URL receives the events. Webhooks are disabled when empty.
+	// Default: ""
+	URL string
+
+	// Secret signs each payload with HMAC-SHA256; receivers verify the
+	// X-VC-Signature-256 header with it. Required when URL is set.
+	// Default: ""
+	Secret string
+
+	// Events limits which event types are sent (see the webhook package)
+	// Default: none (all events)
+	Events []string
+
+	// MaxAttempts is how many times a delivery is tried before it is dropped
+	// Default: 5, Range: 1-20
+	MaxAttempts int
+
+	// TimeoutSeconds bounds each delivery request
+	// Default: 10, Range: 1-300
+	TimeoutSeconds int
+
+	// PollIntervalSeconds is how often the database is checked for new
+	// events and failed deliveries are retried
+	// Default: 5, Range: 1-3600
+	PollIntervalSeconds int
+}
+
+// DefaultWebhookConfig returns the default webhook configuration
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		MaxAttempts:         5,
+		TimeoutSeconds:      10,
+		PollIntervalSeconds: 5,
+	}
+}
+
+// Enabled reports whether a webhook URL is configured
+func (c WebhookConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+// Validate checks if the configuration has valid values
+func (c WebhookConfig) Validate() error {
+	if c.URL != "" {
+		if !strings.HasPrefix(c.URL, "https://") && !strings.HasPrefix(c.URL, "http://") {
+			return fmt.Errorf("webhook URL must be an http(s) URL (got %q)", c.URL)
+		}
+		if c.Secret == "" {
+			return fmt.Errorf("webhook secret is required to sign payloads (VC_WEBHOOK_SECRET)")
+		}
+	}
+	for _, event := range c.Events {
+		if strings.TrimSpace(event) == "" {
+			return fmt.Errorf("webhook event names cannot be empty")
+		}
+	}
+	if c.MaxAttempts < 1 || c.MaxAttempts > 20 {
+		return fmt.Errorf("max_attempts must be between 1 and 20 (got %d)", c.MaxAttempts)
+	}
+	if c.TimeoutSeconds < 1 || c.TimeoutSeconds > 300 {
+		return fmt.Errorf("timeout_seconds must be between 1 and 300 (got %d)", c.TimeoutSeconds)
+	}
+	if c.PollIntervalSeconds < 1 || c.PollIntervalSeconds > 3600 {
+		return fmt.Errorf("poll_interval_seconds must be between 1 and 3600 (got %d)", c.PollIntervalSeconds)
+	}
+	return nil
+}
+
+// String returns a human-readable representation of the config. The secret
+// is never included.
+func (c WebhookConfig) String() string {
+	return fmt.Sprintf("WebhookConfig{URL: %q, Secret: %v, Events: %v, MaxAttempts: %d, TimeoutSeconds: %d, PollIntervalSeconds: %d}",
+		c.URL, c.Secret != "", c.Events, c.MaxAttempts, c.TimeoutSeconds, c.PollIntervalSeconds)
+}
+
+// Timeout returns the delivery timeout as a time.Duration
+func (c WebhookConfig) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// PollInterval returns the poll interval as a time.Duration
+func (c WebhookConfig) PollInterval() time.Duration {
+	return time.Duration(c.PollIntervalSeconds) * time.Second
+}
+
+// WebhookConfigFromEnv creates a WebhookConfig from environment variables,
+// falling back to defaults
+//
+// Environment variables:
+//   - VC_WEBHOOK_URL: URL events are POSTed to (default: disabled)
+//   - VC_WEBHOOK_SECRET: HMAC-SHA256 signing secret (required with a URL)
+//   - VC_WEBHOOK_EVENTS: Comma-separated event types to send (default: all)
+//   - VC_WEBHOOK_MAX_ATTEMPTS: Delivery attempts before giving up (default: 5)
+//   - VC_WEBHOOK_TIMEOUT_SECONDS: Per-request timeout (default: 10)
+//   - VC_WEBHOOK_POLL_INTERVAL_SECONDS: Seconds between checks for events (default: 5)
+//
+// Returns an error if any environment variable has an invalid value.
+func WebhookConfigFromEnv() (WebhookConfig, error) {
+	cfg := DefaultWebhookConfig()
+
+	parseEnvString("VC_WEBHOOK_URL", &cfg.URL)
+	parseEnvString("VC_WEBHOOK_SECRET", &cfg.Secret)
+	var events string
+	parseEnvString("VC_WEBHOOK_EVENTS", &events)
+	for _, event := range strings.Split(events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			cfg.Events = append(cfg.Events, event)
+		}
+	}
+	if err := parseEnvInt("VC_WEBHOOK_MAX_ATTEMPTS", &cfg.MaxAttempts); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_WEBHOOK_TIMEOUT_SECONDS", &cfg.TimeoutSeconds); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_WEBHOOK_POLL_INTERVAL_SECONDS", &cfg.PollIntervalSeconds); err != nil {
+		return cfg, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid webhook configuration from environment: %w", err)
+	}
+
+	return cfg, nil
+}
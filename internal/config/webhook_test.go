@@ -0,0 +1,95 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+		check   func(t *testing.T, cfg WebhookConfig)
+	}{
+		{
+			name:    "no environment variables uses defaults",
+			envVars: map[string]string{},
+			check: func(t *testing.T, cfg WebhookConfig) {
+				if !reflect.DeepEqual(cfg, DefaultWebhookConfig()) {
+					t.Errorf("cfg = %v, want %v", cfg, DefaultWebhookConfig())
+				}
+				if cfg.Enabled() {
+					t.Error("Enabled() = true without a URL")
+				}
+			},
+		},
+		{
+			name: "valid custom configuration",
+			envVars: map[string]string{
+				"VC_WEBHOOK_URL":                   "https://hooks.example.com/vc",
+				"VC_WEBHOOK_SECRET":                "s3cret",
+				"VC_WEBHOOK_EVENTS":                "gate.failed, escalation ,",
+				"VC_WEBHOOK_MAX_ATTEMPTS":          "8",
+				"VC_WEBHOOK_TIMEOUT_SECONDS":       "30",
+				"VC_WEBHOOK_POLL_INTERVAL_SECONDS": "60",
+			},
+			check: func(t *testing.T, cfg WebhookConfig) {
+				if !cfg.Enabled() || cfg.URL != "https://hooks.example.com/vc" || cfg.MaxAttempts != 8 {
+					t.Errorf("unexpected config: %v", cfg)
+				}
+				if !reflect.DeepEqual(cfg.Events, []string{"gate.failed", "escalation"}) {
+					t.Errorf("Events = %q, want [gate.failed escalation]", cfg.Events)
+				}
+				if cfg.Timeout() != 30*time.Second || cfg.PollInterval() != time.Minute {
+					t.Errorf("Timeout() = %v, PollInterval() = %v", cfg.Timeout(), cfg.PollInterval())
+				}
+				if strings.Contains(cfg.String(), "s3cret") {
+					t.Error("String() must not include the secret")
+				}
+			},
+		},
+		{
+			name:    "URL without a secret",
+			envVars: map[string]string{"VC_WEBHOOK_URL": "https://hooks.example.com/vc"},
+			wantErr: true,
+		},
+		{
+			name:    "URL that isn't http(s)",
+			envVars: map[string]string{"VC_WEBHOOK_URL": "hooks.example.com", "VC_WEBHOOK_SECRET": "s3cret"},
+			wantErr: true,
+		},
+		{
+			name:    "max attempts out of range",
+			envVars: map[string]string{"VC_WEBHOOK_MAX_ATTEMPTS": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			envVars: map[string]string{"VC_WEBHOOK_TIMEOUT_SECONDS": "soon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"VC_WEBHOOK_URL", "VC_WEBHOOK_SECRET", "VC_WEBHOOK_EVENTS", "VC_WEBHOOK_MAX_ATTEMPTS",
+				"VC_WEBHOOK_TIMEOUT_SECONDS", "VC_WEBHOOK_POLL_INTERVAL_SECONDS"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := WebhookConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WebhookConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
@@ -98,12 +98,13 @@ func NewTracker(cfg *Config, store storage.Storage) (*Tracker, error) {
 	}
 
 	// Try to load existing state from disk (for restart recovery)
+	// Messages go to stderr so they don't mix with command output (vc --json)
 	if cfg.PersistStatePath != "" {
 		if err := t.loadState(); err != nil {
 			// Log warning but continue with fresh state
-			fmt.Printf("Warning: failed to load cost state from %s: %v (starting fresh)\n", cfg.PersistStatePath, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to load cost state from %s: %v (starting fresh)\n", cfg.PersistStatePath, err)
 		} else {
-			fmt.Printf("✓ Loaded cost budget state from %s (total: $%.2f, hourly: %d tokens)\n",
+			fmt.Fprintf(os.Stderr, "✓ Loaded cost budget state from %s (total: $%.2f, hourly: %d tokens)\n",
 				cfg.PersistStatePath, t.state.TotalCostUsed, t.state.HourlyTokensUsed)
 		}
 	}
@@ -947,43 +948,22 @@ func (t *Tracker) RecordOperation(ctx context.Context, opInterface interface{})
 	return nil
 }
 
-// storeOperation stores a quota operation in the database
+// storeOperation stores a quota operation in the database, where 'vc cost'
+// reports on it
 func (t *Tracker) storeOperation(ctx context.Context, op QuotaOperation) error {
 	if t.store == nil {
 		return nil // No storage configured
 	}
 
-	// Convert to storage layer type
-	storageOp := &struct {
-		ID            string
-		Timestamp     time.Time
-		IssueID       string
-		OperationType string
-		Model         string
-		InputTokens   int64
-		OutputTokens  int64
-		Cost          float64
-		DurationMs    int64
-	}{
-		ID:            op.ID,
-		Timestamp:     op.Timestamp,
-		IssueID:       op.IssueID,
-		OperationType: op.OperationType,
-		Model:         op.Model,
-		InputTokens:   op.InputTokens,
-		OutputTokens:  op.OutputTokens,
-		Cost:          op.Cost,
-		DurationMs:    op.DurationMs,
-	}
-
-	// Use type assertion to call StoreQuotaOperation on the storage interface
-	type quotaStore interface {
-		StoreQuotaOperation(ctx context.Context, op interface{}) error
-	}
-
-	if qs, ok := t.store.(quotaStore); ok {
-		return qs.StoreQuotaOperation(ctx, storageOp)
-	}
-
-	return nil // Storage doesn't support quota monitoring yet
+	return t.store.RecordAIUsage(ctx, &types.AIUsage{
+		ID:           op.ID,
+		Timestamp:    op.Timestamp,
+		IssueID:      op.IssueID,
+		Operation:    op.OperationType,
+		Model:        op.Model,
+		InputTokens:  op.InputTokens,
+		OutputTokens: op.OutputTokens,
+		CostUSD:      op.Cost,
+		Duration:     time.Duration(op.DurationMs) * time.Millisecond,
+	})
 }
@@ -0,0 +1,200 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// MissionReport is a mission's spend to date and progress, for dashboards.
+// Phases are the mission's child epics; tasks are its other children and
+// the children of its phases.
+type MissionReport struct {
+	MissionID string       `json:"mission_id"`
+	Title     string       `json:"title"`
+	Status    types.Status `json:"status"`
+	Priority  int          `json:"priority"`
+
+	// Spend on the mission and every issue in it
+	CostUSD         float64 `json:"cost_usd"`
+	InputTokens     int64   `json:"input_tokens"`
+	OutputTokens    int64   `json:"output_tokens"`
+	SupervisorCalls int     `json:"supervisor_calls"`
+	Executions      int     `json:"executions"`
+
+	Phases         int `json:"phases"`
+	PhasesComplete int `json:"phases_complete"`
+	Tasks          int `json:"tasks"`
+	TasksClosed    int `json:"tasks_closed"`
+
+	// Quality gate runs on the mission's phases and tasks (see
+	// types.EpicRollup)
+	GateRuns       int     `json:"gate_runs"`
+	GateRunsPassed int     `json:"gate_runs_passed"`
+	GatePassRate   float64 `json:"gate_pass_rate"`
+
+	// ProjectedRemainingUSD extrapolates the spend per closed task to the
+	// tasks still open. nil until a task has closed.
+	ProjectedRemainingUSD *float64 `json:"projected_remaining_usd"`
+}
+
+// MissionStore is the storage LoadMissionReports reads
+type MissionStore interface {
+	ReportStore
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error)
+	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
+	GetEpicRollup(ctx context.Context, epicID string) (*types.EpicRollup, error)
+}
+
+// LoadMissionReports reports the spend to date and progress of the missions
+// with the given IDs, or of every mission if there are none (closed ones
+// only with includeClosed). Reports are sorted by priority, then ID.
+func LoadMissionReports(ctx context.Context, store MissionStore, ids []string, includeClosed bool) ([]*MissionReport, error) {
+	var missions []*types.Issue
+	if len(ids) > 0 {
+		for _, id := range ids {
+			issue, err := store.GetIssue(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get mission %s: %w", id, err)
+			}
+			if issue == nil || issue.IssueType != types.TypeEpic || issue.IssueSubtype != types.SubtypeMission {
+				return nil, fmt.Errorf("mission %s not found", id)
+			}
+			missions = append(missions, issue)
+		}
+	} else {
+		epic := types.TypeEpic
+		epics, err := store.SearchIssues(ctx, "", types.IssueFilter{IssueType: &epic})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list missions: %w", err)
+		}
+		for _, issue := range epics {
+			if issue.IssueSubtype == types.SubtypeMission && (includeClosed || issue.Status != types.StatusClosed) {
+				missions = append(missions, issue)
+			}
+		}
+	}
+
+	reports := make([]*MissionReport, 0, len(missions))
+	byID := make(map[string]*MissionReport, len(missions))
+	for _, mission := range missions {
+		report := &MissionReport{MissionID: mission.ID, Title: mission.Title, Status: mission.Status, Priority: mission.Priority}
+		if err := report.loadProgress(ctx, store); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+		byID[mission.ID] = report
+	}
+	if len(reports) == 0 {
+		return reports, nil
+	}
+
+	spend, err := LoadSpend(ctx, store, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range spend {
+		if report := byID[s.MissionID]; report != nil {
+			report.addSpend(s)
+		}
+	}
+	for _, report := range reports {
+		report.project()
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Priority != reports[j].Priority {
+			return reports[i].Priority < reports[j].Priority
+		}
+		return reports[i].MissionID < reports[j].MissionID
+	})
+	return reports, nil
+}
+
+// loadProgress counts the mission's phases and tasks, and gate runs on them
+func (r *MissionReport) loadProgress(ctx context.Context, store MissionStore) error {
+	rollup, err := store.GetEpicRollup(ctx, r.MissionID)
+	if err != nil {
+		return fmt.Errorf("failed to get progress of %s: %w", r.MissionID, err)
+	}
+	r.GateRuns, r.GateRunsPassed = rollup.GateRuns, rollup.GateRunsPassed
+
+	children, err := childrenOf(ctx, store, r.MissionID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		closed := child.Status == types.StatusClosed
+		if child.IssueType != types.TypeEpic {
+			r.Tasks++
+			if closed {
+				r.TasksClosed++
+			}
+			continue
+		}
+		r.Phases++
+		if closed {
+			r.PhasesComplete++
+		}
+		phase, err := store.GetEpicRollup(ctx, child.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get progress of %s: %w", child.ID, err)
+		}
+		r.Tasks += phase.Children
+		r.TasksClosed += phase.Closed()
+		r.GateRuns += phase.GateRuns
+		r.GateRunsPassed += phase.GateRunsPassed
+	}
+	if r.GateRuns > 0 {
+		r.GatePassRate = float64(r.GateRunsPassed) / float64(r.GateRuns)
+	}
+	return nil
+}
+
+// addSpend adds an item of spend on the mission
+func (r *MissionReport) addSpend(s Spend) {
+	r.CostUSD += s.CostUSD
+	r.InputTokens += s.InputTokens
+	r.OutputTokens += s.OutputTokens
+	if s.Provider == SupervisorProvider {
+		r.SupervisorCalls++
+	} else {
+		r.Executions++
+	}
+}
+
+// project sets the projected remaining cost from the spend per closed task
+func (r *MissionReport) project() {
+	if r.TasksClosed == 0 {
+		return
+	}
+	remaining := r.CostUSD / float64(r.TasksClosed) * float64(r.Tasks-r.TasksClosed)
+	r.ProjectedRemainingUSD = &remaining
+}
+
+// childrenOf returns the issues linked to parent by parent-child
+// dependencies. Dependents also include the issues parent blocks.
+func childrenOf(ctx context.Context, store MissionStore, parentID string) ([]*types.Issue, error) {
+	dependents, err := store.GetDependents(ctx, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get children of %s: %w", parentID, err)
+	}
+	var children []*types.Issue
+	for _, dependent := range dependents {
+		deps, err := store.GetDependencyRecords(ctx, dependent.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", dependent.ID, err)
+		}
+		for _, dep := range deps {
+			if dep.Type == types.DepParentChild && dep.DependsOnID == parentID {
+				children = append(children, dependent)
+				break
+			}
+		}
+	}
+	return children, nil
+}
@@ -0,0 +1,139 @@
+package cost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestMissionReports(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	mission := &types.Mission{
+		Issue: types.Issue{Title: "Mission", Status: types.StatusOpen, Priority: 1,
+			IssueType: types.TypeEpic, IssueSubtype: types.SubtypeMission},
+		Goal: "Ship it",
+	}
+	if err := store.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("CreateMission() error = %v", err)
+	}
+	done := &types.Mission{
+		Issue: types.Issue{Title: "Done mission", Status: types.StatusOpen, Priority: 0,
+			IssueType: types.TypeEpic, IssueSubtype: types.SubtypeMission},
+		Goal: "Shipped",
+	}
+	if err := store.CreateMission(ctx, done, "test"); err != nil {
+		t.Fatalf("CreateMission() error = %v", err)
+	}
+	if err := store.CloseIssue(ctx, done.ID, "shipped", "test"); err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+
+	// Two phases with two tasks each, and a task directly under the mission
+	create := func(issue *types.Issue, parentID string) *types.Issue {
+		t.Helper()
+		status := issue.Status
+		issue.Status = types.StatusOpen
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue() error = %v", err)
+		}
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: issue.ID, DependsOnID: parentID, Type: types.DepParentChild}, "test"); err != nil {
+			t.Fatalf("AddDependency() error = %v", err)
+		}
+		if status == types.StatusClosed {
+			if err := store.CloseIssue(ctx, issue.ID, "done", "test"); err != nil {
+				t.Fatalf("CloseIssue() error = %v", err)
+			}
+			issue.Status = status
+		}
+		return issue
+	}
+	task := func(title string, status types.Status) *types.Issue {
+		return &types.Issue{Title: title, AcceptanceCriteria: "Done", Status: status, Priority: 2, IssueType: types.TypeTask}
+	}
+	phase1 := create(&types.Issue{Title: "Phase 1", Status: types.StatusClosed, Priority: 1, IssueType: types.TypeEpic}, mission.ID)
+	phase2 := create(&types.Issue{Title: "Phase 2", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}, mission.ID)
+	task1 := create(task("Task 1", types.StatusClosed), phase1.ID)
+	create(task("Task 2", types.StatusClosed), phase1.ID)
+	task3 := create(task("Task 3", types.StatusOpen), phase2.ID)
+	create(task("Task 4", types.StatusOpen), phase2.ID)
+	create(task("Task 5", types.StatusOpen), mission.ID)
+
+	for _, usage := range []*types.AIUsage{
+		{ID: "u1", Timestamp: time.Now(), IssueID: task1.ID, Operation: "assessment", Model: "sonnet", InputTokens: 1000, OutputTokens: 100, CostUSD: 0.5},
+		{ID: "u2", Timestamp: time.Now(), IssueID: mission.ID, Operation: "planning", Model: "opus", InputTokens: 2000, OutputTokens: 200, CostUSD: 1.5},
+		{ID: "u3", Timestamp: time.Now(), IssueID: "SYSTEM", Operation: "loop-detection", Model: "haiku", CostUSD: 10},
+	} {
+		if err := store.RecordAIUsage(ctx, usage); err != nil {
+			t.Fatalf("RecordAIUsage() error = %v", err)
+		}
+	}
+	if err := store.CreateExecution(ctx, &types.Execution{IssueID: task3.ID, AgentProvider: "claude-code",
+		Status: types.ExecutionSucceeded, StartedAt: time.Now(), CostUSD: 2}); err != nil {
+		t.Fatalf("CreateExecution() error = %v", err)
+	}
+	for _, event := range []*events.AgentEvent{
+		{ID: "gates-1", Type: events.EventTypeQualityGatesCompleted, Timestamp: time.Now(), IssueID: task1.ID,
+			Data: map[string]interface{}{"all_passed": false}},
+		{ID: "gates-2", Type: events.EventTypeQualityGatesCompleted, Timestamp: time.Now(), IssueID: task1.ID,
+			Data: map[string]interface{}{"all_passed": true}},
+		{ID: "gates-3", Type: events.EventTypeQualityGatesCompleted, Timestamp: time.Now(), IssueID: task3.ID,
+			Data: map[string]interface{}{"all_passed": true}},
+		{ID: "gates-4", Type: events.EventTypeQualityGatesCompleted, Timestamp: time.Now(), IssueID: phase1.ID,
+			Data: map[string]interface{}{"all_passed": true}},
+	} {
+		if err := store.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("StoreAgentEvent() error = %v", err)
+		}
+	}
+
+	reports, err := LoadMissionReports(ctx, store, nil, false)
+	if err != nil {
+		t.Fatalf("LoadMissionReports() error = %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("LoadMissionReports() = %d reports, want the unclosed mission only: %+v", len(reports), reports)
+	}
+	r := reports[0]
+	if r.MissionID != mission.ID || r.Title != "Mission" || r.Status != types.StatusOpen {
+		t.Errorf("unexpected mission: %+v", r)
+	}
+	if r.CostUSD != 4 || r.InputTokens != 3000 || r.OutputTokens != 300 || r.SupervisorCalls != 2 || r.Executions != 1 {
+		t.Errorf("spend = $%.2f, %d/%d tokens, %d calls, %d executions; want $4.00, 3000/300, 2, 1",
+			r.CostUSD, r.InputTokens, r.OutputTokens, r.SupervisorCalls, r.Executions)
+	}
+	if r.Phases != 2 || r.PhasesComplete != 1 || r.Tasks != 5 || r.TasksClosed != 2 {
+		t.Errorf("progress = %d/%d phases, %d/%d tasks; want 1/2, 2/5", r.PhasesComplete, r.Phases, r.TasksClosed, r.Tasks)
+	}
+	if r.GateRuns != 4 || r.GateRunsPassed != 3 || r.GatePassRate != 0.75 {
+		t.Errorf("gates = %d/%d (%.2f), want 3/4 (0.75)", r.GateRunsPassed, r.GateRuns, r.GatePassRate)
+	}
+	// $2 per closed task, three open
+	if r.ProjectedRemainingUSD == nil || *r.ProjectedRemainingUSD != 6 {
+		t.Errorf("ProjectedRemainingUSD = %v, want 6", r.ProjectedRemainingUSD)
+	}
+
+	reports, err = LoadMissionReports(ctx, store, nil, true)
+	if err != nil {
+		t.Fatalf("LoadMissionReports() error = %v", err)
+	}
+	if len(reports) != 2 || reports[0].MissionID != done.ID {
+		t.Fatalf("expected both missions, highest priority first, got %+v", reports)
+	}
+	if reports[0].ProjectedRemainingUSD != nil || reports[0].Tasks != 0 {
+		t.Errorf("expected no projection for a mission without closed tasks, got %+v", reports[0])
+	}
+
+	if _, err := LoadMissionReports(ctx, store, []string{task1.ID}, false); err == nil {
+		t.Error("expected an error for an ID that isn't a mission")
+	}
+	reports, err = LoadMissionReports(ctx, store, []string{done.ID}, false)
+	if err != nil || len(reports) != 1 || reports[0].MissionID != done.ID {
+		t.Errorf("expected the closed mission by ID, got %+v (err %v)", reports, err)
+	}
+}
@@ -0,0 +1,250 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// SupervisorProvider is the provider recorded for AI supervisor spend, to
+// tell it apart from agent spend, which is recorded under the agent's
+// provider (e.g. "claude-code")
+const SupervisorProvider = "supervisor"
+
+// ExecutionOperation is the operation recorded for agent executions
+const ExecutionOperation = "execution"
+
+// Spend is one item of AI spend: a call the supervisor made, or an agent
+// execution. Agents don't report tokens or models, so those are zero and
+// empty for executions.
+type Spend struct {
+	Time         time.Time
+	IssueID      string // Empty for supervisor work not on an issue
+	IssueTitle   string
+	MissionID    string // The mission the issue belongs to, if any
+	MissionTitle string
+	Operation    string
+	Model        string
+	Provider     string
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+}
+
+// ReportStore is the storage LoadSpend reads
+type ReportStore interface {
+	ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error)
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error)
+}
+
+// LoadSpend returns the supervisor calls made and the executions started
+// from since until before until (unbounded when zero), oldest first, with
+// each attributed to its issue and mission
+func LoadSpend(ctx context.Context, store ReportStore, since, until time.Time) ([]Spend, error) {
+	usage, err := store.ListAIUsage(ctx, types.AIUsageFilter{Since: since, Until: until})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AI usage: %w", err)
+	}
+	executions, err := store.ListExecutions(ctx, types.ExecutionFilter{Since: since})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions: %w", err)
+	}
+
+	var spend []Spend
+	for _, u := range usage {
+		spend = append(spend, Spend{
+			Time:         u.Timestamp,
+			IssueID:      u.IssueID,
+			Operation:    u.Operation,
+			Model:        u.Model,
+			Provider:     SupervisorProvider,
+			InputTokens:  u.InputTokens,
+			OutputTokens: u.OutputTokens,
+			CostUSD:      u.CostUSD,
+		})
+	}
+	for _, e := range executions {
+		if !until.IsZero() && !e.StartedAt.Before(until) {
+			continue
+		}
+		spend = append(spend, Spend{
+			Time:      e.StartedAt,
+			IssueID:   e.IssueID,
+			Operation: ExecutionOperation,
+			Provider:  e.AgentProvider,
+			CostUSD:   e.CostUSD,
+		})
+	}
+	sort.SliceStable(spend, func(i, j int) bool { return spend[i].Time.Before(spend[j].Time) })
+
+	attributor := &attributor{store: store, titles: map[string]string{}, missions: map[string]string{}}
+	for i := range spend {
+		if err := attributor.attribute(ctx, &spend[i]); err != nil {
+			return nil, err
+		}
+	}
+	return spend, nil
+}
+
+// attributor fills in issue titles and missions, looking each issue up once
+type attributor struct {
+	store    ReportStore
+	titles   map[string]string
+	missions map[string]string
+}
+
+// attribute sets s's issue title and mission
+func (a *attributor) attribute(ctx context.Context, s *Spend) error {
+	if s.IssueID == "" {
+		return nil
+	}
+	missionID, ok := a.missions[s.IssueID]
+	if !ok {
+		issue, err := a.store.GetIssue(ctx, s.IssueID)
+		if err != nil {
+			return fmt.Errorf("failed to get issue %s: %w", s.IssueID, err)
+		}
+		if issue != nil {
+			a.titles[s.IssueID] = issue.Title
+			if issue.IssueType == types.TypeEpic && issue.IssueSubtype == types.SubtypeMission {
+				missionID = issue.ID
+			} else if mission, err := a.store.GetMissionForTask(ctx, s.IssueID); err == nil && mission != nil {
+				// An error means the issue isn't part of a mission
+				missionID = mission.MissionID
+			}
+		}
+		a.missions[s.IssueID] = missionID
+	}
+	s.IssueTitle = a.titles[s.IssueID]
+	s.MissionID = missionID
+	if missionID != "" {
+		if _, ok := a.titles[missionID]; !ok {
+			mission, err := a.store.GetIssue(ctx, missionID)
+			if err != nil {
+				return fmt.Errorf("failed to get mission %s: %w", missionID, err)
+			}
+			if mission != nil {
+				a.titles[missionID] = mission.Title
+			}
+		}
+		s.MissionTitle = a.titles[missionID]
+	}
+	return nil
+}
+
+// Dimension is what a report breaks spend down by
+type Dimension string
+
+// Report dimensions
+const (
+	ByDay       Dimension = "day"       // Local calendar day
+	ByIssue     Dimension = "issue"     // Issue worked on
+	ByMission   Dimension = "mission"   // Mission the issue belongs to
+	ByOperation Dimension = "operation" // Supervisor operation, or "execution"
+	ByModel     Dimension = "model"     // Supervisor model; unknown for agents
+	ByProvider  Dimension = "provider"  // "supervisor" or the agent provider
+)
+
+// Dimensions lists every report dimension
+var Dimensions = []Dimension{ByDay, ByIssue, ByMission, ByOperation, ByModel, ByProvider}
+
+// ParseDimension parses a report dimension
+func ParseDimension(s string) (Dimension, error) {
+	for _, d := range Dimensions {
+		if string(d) == s {
+			return d, nil
+		}
+	}
+	names := make([]string, len(Dimensions))
+	for i, d := range Dimensions {
+		names[i] = string(d)
+	}
+	return "", fmt.Errorf("unknown breakdown %q (want one of: %s)", s, strings.Join(names, ", "))
+}
+
+// NoneKey is the key of spend with no value for a dimension, e.g. supervisor
+// calls not made for an issue, or agent executions broken down by model
+const NoneKey = "(none)"
+
+// Row is the spend for one key of a report's dimension
+type Row struct {
+	Key          string  `json:"key"`
+	Title        string  `json:"title,omitempty"` // For issues and missions
+	Count        int     `json:"count"`           // Supervisor calls and executions
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// add adds s to the row
+func (r *Row) add(s Spend) {
+	r.Count++
+	r.InputTokens += s.InputTokens
+	r.OutputTokens += s.OutputTokens
+	r.CostUSD += s.CostUSD
+}
+
+// Report breaks spend down by one dimension
+type Report struct {
+	By    Dimension `json:"by"`
+	Since time.Time `json:"since,omitzero"`
+	Until time.Time `json:"until,omitzero"`
+	Rows  []Row     `json:"rows"`
+	Total Row       `json:"total"`
+}
+
+// BuildReport breaks spend down by dimension. Days are in chronological
+// order; everything else is costliest first.
+func BuildReport(spend []Spend, by Dimension, since, until time.Time) *Report {
+	report := &Report{By: by, Since: since, Until: until, Rows: []Row{}, Total: Row{Key: "total"}}
+	rows := map[string]*Row{}
+	for _, s := range spend {
+		key, title := s.key(by)
+		if key == "" {
+			key = NoneKey
+		}
+		row, ok := rows[key]
+		if !ok {
+			row = &Row{Key: key, Title: title}
+			rows[key] = row
+		}
+		row.add(s)
+		report.Total.add(s)
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if by != ByDay && a.CostUSD != b.CostUSD {
+			return a.CostUSD > b.CostUSD
+		}
+		return a.Key < b.Key
+	})
+	return report
+}
+
+// key returns the spend's key and title for a dimension
+func (s Spend) key(by Dimension) (key, title string) {
+	switch by {
+	case ByDay:
+		return s.Time.Local().Format("2006-01-02"), ""
+	case ByIssue:
+		return s.IssueID, s.IssueTitle
+	case ByMission:
+		return s.MissionID, s.MissionTitle
+	case ByOperation:
+		return s.Operation, ""
+	case ByModel:
+		return s.Model, ""
+	case ByProvider:
+		return s.Provider, ""
+	}
+	return "", ""
+}
@@ -0,0 +1,119 @@
+package cost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSpendReport(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	mission := &types.Mission{
+		Issue: types.Issue{Title: "Mission", Status: types.StatusOpen, Priority: 1,
+			IssueType: types.TypeEpic, IssueSubtype: types.SubtypeMission},
+		Goal: "Ship it",
+	}
+	if err := store.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("CreateMission() error = %v", err)
+	}
+	task := &types.Issue{Title: "Task", AcceptanceCriteria: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, task, "test"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: mission.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+
+	day1 := time.Date(2026, 3, 2, 12, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	for _, usage := range []*types.AIUsage{
+		{ID: "u1", Timestamp: day1, IssueID: task.ID, Operation: "assessment", Model: "sonnet", InputTokens: 1000, OutputTokens: 100, CostUSD: 0.25},
+		{ID: "u2", Timestamp: day2, IssueID: mission.ID, Operation: "planning", Model: "opus", InputTokens: 2000, OutputTokens: 200, CostUSD: 1},
+		{ID: "u3", Timestamp: day2, IssueID: "SYSTEM", Operation: "loop-detection", Model: "haiku", InputTokens: 10, OutputTokens: 1, CostUSD: 0.01},
+		{ID: "u4", Timestamp: day2.AddDate(0, 0, 1), IssueID: task.ID, Operation: "analysis", Model: "sonnet", CostUSD: 5},
+	} {
+		if err := store.RecordAIUsage(ctx, usage); err != nil {
+			t.Fatalf("RecordAIUsage() error = %v", err)
+		}
+	}
+	for _, execution := range []*types.Execution{
+		{IssueID: task.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded, StartedAt: day1, CostUSD: 2},
+		{IssueID: task.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded, StartedAt: day1.AddDate(0, 0, -1), CostUSD: 7},
+	} {
+		if err := store.CreateExecution(ctx, execution); err != nil {
+			t.Fatalf("CreateExecution() error = %v", err)
+		}
+	}
+
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	until := since.AddDate(0, 0, 2)
+	spend, err := LoadSpend(ctx, store, since, until)
+	if err != nil {
+		t.Fatalf("LoadSpend() error = %v", err)
+	}
+	if len(spend) != 4 {
+		t.Fatalf("LoadSpend() = %d items, want 4 in range: %+v", len(spend), spend)
+	}
+
+	tests := []struct {
+		by   Dimension
+		want []Row
+	}{
+		{ByDay, []Row{
+			{Key: "2026-03-02", Count: 2, InputTokens: 1000, OutputTokens: 100, CostUSD: 2.25},
+			{Key: "2026-03-03", Count: 2, InputTokens: 2010, OutputTokens: 201, CostUSD: 1.01},
+		}},
+		{ByIssue, []Row{
+			{Key: task.ID, Title: "Task", Count: 2, InputTokens: 1000, OutputTokens: 100, CostUSD: 2.25},
+			{Key: mission.ID, Title: "Mission", Count: 1, InputTokens: 2000, OutputTokens: 200, CostUSD: 1},
+			{Key: NoneKey, Count: 1, InputTokens: 10, OutputTokens: 1, CostUSD: 0.01},
+		}},
+		{ByMission, []Row{
+			{Key: mission.ID, Title: "Mission", Count: 3, InputTokens: 3000, OutputTokens: 300, CostUSD: 3.25},
+			{Key: NoneKey, Count: 1, InputTokens: 10, OutputTokens: 1, CostUSD: 0.01},
+		}},
+		{ByProvider, []Row{
+			{Key: "claude-code", Count: 1, CostUSD: 2},
+			{Key: SupervisorProvider, Count: 3, InputTokens: 3010, OutputTokens: 301, CostUSD: 1.26},
+		}},
+		{ByModel, []Row{
+			{Key: NoneKey, Count: 1, CostUSD: 2},
+			{Key: "opus", Count: 1, InputTokens: 2000, OutputTokens: 200, CostUSD: 1},
+			{Key: "sonnet", Count: 1, InputTokens: 1000, OutputTokens: 100, CostUSD: 0.25},
+			{Key: "haiku", Count: 1, InputTokens: 10, OutputTokens: 1, CostUSD: 0.01},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.by), func(t *testing.T) {
+			report := BuildReport(spend, tt.by, since, until)
+			if len(report.Rows) != len(tt.want) {
+				t.Fatalf("rows = %+v, want %+v", report.Rows, tt.want)
+			}
+			for i, row := range report.Rows {
+				want := tt.want[i]
+				if row.Key != want.Key || row.Title != want.Title || row.Count != want.Count ||
+					row.InputTokens != want.InputTokens || row.OutputTokens != want.OutputTokens || !near(row.CostUSD, want.CostUSD) {
+					t.Errorf("row %d = %+v, want %+v", i, row, want)
+				}
+			}
+			if report.Total.Count != 4 || !near(report.Total.CostUSD, 3.26) {
+				t.Errorf("total = %+v, want 4 items costing $3.26", report.Total)
+			}
+		})
+	}
+
+	if _, err := ParseDimension("team"); err == nil {
+		t.Error("ParseDimension(team) succeeded, want an error")
+	}
+}
+
+// near reports whether two costs are equal to within rounding
+func near(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
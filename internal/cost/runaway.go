@@ -0,0 +1,478 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// RunawayLabel marks the escalations filed by the runaway cost detector.
+// An open one without a discovered-from dependency pauses the executor; one
+// discovered from an issue holds that issue.
+const RunawayLabel = "runaway-cost"
+
+// runawayActor is the actor recorded on the detector's issues and labels
+const runawayActor = "cost-monitor"
+
+// minEstimateSamples is how many closed issues of a type are needed to
+// estimate the budget of issues of that type
+const minEstimateSamples = 3
+
+// RunawayStore is the storage the runaway cost detector reads spend from and
+// files escalations in
+type RunawayStore interface {
+	ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error)
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
+	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
+	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+	StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error
+}
+
+// Pauser holds back work while a human reviews runaway spend (implemented
+// by executor.Pool)
+type Pauser interface {
+	// PauseWork stops claiming new issues until ResumeWork
+	PauseWork(reason string)
+	ResumeWork()
+	// KillAgent stops the agent working on issueID, returning false if none
+	// runs in this process
+	KillAgent(issueID, reason string) bool
+}
+
+// spendItem is one cost: a supervisor call or a finished execution
+type spendItem struct {
+	at      time.Time
+	issueID string
+	usd     float64
+}
+
+// RunawayDetector watches AI spend for two kinds of runaway:
+//
+//   - Velocity: the spend in the latest window is over VelocityMultiplier
+//     times the average spend per window across the baseline. The executor
+//     stops claiming work until a human closes the escalation filed for it.
+//   - Issue: an issue has spent over IssueMultiplier times its estimated
+//     budget, the median spend of issues of its type that closed during the
+//     baseline. Its agent is stopped, it gets the no-auto-claim label and an
+//     escalation is filed from it; approving the escalation in Slack (or
+//     closing it and removing the label) lets VC resume it.
+//
+// Spend approved by closing an escalation isn't counted against the next
+// check. Every pause and resume is recorded as a budget_alert agent event.
+type RunawayDetector struct {
+	cfg    config.RunawayCostConfig
+	store  RunawayStore
+	pauser Pauser // nil = escalations are filed but nothing is paused
+
+	paused bool                    // Whether the pauser is paused for a velocity escalation
+	closed map[string]*types.Issue // Closed issues, which don't change, by ID
+}
+
+// NewRunawayDetector creates a runaway cost detector. pauser is optional.
+func NewRunawayDetector(cfg config.RunawayCostConfig, store RunawayStore, pauser Pauser) (*RunawayDetector, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	return &RunawayDetector{cfg: cfg, store: store, pauser: pauser, closed: make(map[string]*types.Issue)}, nil
+}
+
+// Run checks spend every check interval until ctx is canceled
+func (d *RunawayDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.CheckInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Check(ctx); err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to check for runaway spend: %v\n", err)
+			}
+		}
+	}
+}
+
+// runawayHolds are the detector's escalations: open ones hold work, closed
+// ones approve the spend before they closed
+type runawayHolds struct {
+	executor         *types.Issue            // Open escalation pausing the executor
+	executorApproved time.Time               // When the last executor escalation closed
+	issues           map[string]*types.Issue // Issue ID -> open escalation holding it
+	issuesApproved   map[string]time.Time    // Issue ID -> when its last escalation closed
+}
+
+// Check applies the holds of open escalations, resumes work whose
+// escalations closed, and pauses runaway spend
+func (d *RunawayDetector) Check(ctx context.Context) error {
+	holds, err := d.loadHolds(ctx)
+	if err != nil {
+		return err
+	}
+	d.applyExecutorHold(ctx, holds.executor)
+
+	now := time.Now()
+	spend, err := d.loadSpend(ctx, types.AIUsageFilter{Since: now.Add(-d.cfg.Baseline())}, types.ExecutionFilter{Since: now.Add(-d.cfg.Baseline())})
+	if err != nil {
+		return err
+	}
+
+	windowStart := now.Add(-d.cfg.Window())
+	if holds.executor == nil && holds.executorApproved.Before(windowStart) {
+		var current, before float64
+		for _, item := range spend {
+			if item.at.Before(windowStart) {
+				before += item.usd
+			} else {
+				current += item.usd
+			}
+		}
+		baseline := before / float64(d.cfg.Baseline()-d.cfg.Window()) * float64(d.cfg.Window())
+		if baseline > 0 && current >= d.cfg.MinSpendUSD && current > d.cfg.VelocityMultiplier*baseline {
+			if err := d.pauseExecutor(ctx, current, baseline); err != nil {
+				return err
+			}
+		}
+	}
+
+	return d.checkIssues(ctx, spend, windowStart, holds)
+}
+
+// loadHolds reads the detector's escalations
+func (d *RunawayDetector) loadHolds(ctx context.Context) (*runawayHolds, error) {
+	escalations, err := d.store.GetIssuesByLabel(ctx, RunawayLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runaway cost escalations: %w", err)
+	}
+	holds := &runawayHolds{issues: make(map[string]*types.Issue), issuesApproved: make(map[string]time.Time)}
+	for _, escalation := range escalations {
+		deps, err := d.store.GetDependencyRecords(ctx, escalation.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", escalation.ID, err)
+		}
+		var heldID string
+		for _, dep := range deps {
+			if dep.Type == types.DepDiscoveredFrom {
+				heldID = dep.DependsOnID
+			}
+		}
+
+		open := escalation.Status != types.StatusClosed
+		var closedAt time.Time
+		if escalation.ClosedAt != nil {
+			closedAt = *escalation.ClosedAt
+		}
+		switch {
+		case heldID == "" && open:
+			holds.executor = escalation
+		case heldID == "":
+			if closedAt.After(holds.executorApproved) {
+				holds.executorApproved = closedAt
+			}
+		case open:
+			holds.issues[heldID] = escalation
+		default:
+			if closedAt.After(holds.issuesApproved[heldID]) {
+				holds.issuesApproved[heldID] = closedAt
+			}
+		}
+	}
+	return holds, nil
+}
+
+// applyExecutorHold pauses the executor while escalation is open and
+// resumes it once it closes
+func (d *RunawayDetector) applyExecutorHold(ctx context.Context, escalation *types.Issue) {
+	if d.pauser == nil {
+		return
+	}
+	if escalation != nil && !d.paused {
+		reason := fmt.Sprintf("runaway AI spend, waiting for %s to be closed", escalation.ID)
+		d.pauser.PauseWork(reason)
+		fmt.Printf("⏸️  Executor paused: %s\n", reason)
+		d.paused = true
+	} else if escalation == nil && d.paused {
+		d.pauser.ResumeWork()
+		d.paused = false
+		fmt.Printf("▶️  Runaway spend approved, executor resumed\n")
+		d.logEvent(ctx, "SYSTEM", events.SeverityInfo, "Runaway AI spend approved, executor resumed",
+			map[string]interface{}{"event_subtype": "runaway_resumed"})
+	}
+}
+
+// loadSpend returns the supervisor calls and finished executions matching
+// the filters, executions counted when they finished
+func (d *RunawayDetector) loadSpend(ctx context.Context, usageFilter types.AIUsageFilter, executionFilter types.ExecutionFilter) ([]spendItem, error) {
+	usage, err := d.store.ListAIUsage(ctx, usageFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AI usage: %w", err)
+	}
+	executions, err := d.store.ListExecutions(ctx, executionFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions: %w", err)
+	}
+
+	spend := make([]spendItem, 0, len(usage)+len(executions))
+	for _, u := range usage {
+		spend = append(spend, spendItem{at: u.Timestamp, issueID: u.IssueID, usd: u.CostUSD})
+	}
+	for _, execution := range executions {
+		if execution.CompletedAt == nil || execution.CostUSD == 0 {
+			continue
+		}
+		spend = append(spend, spendItem{at: *execution.CompletedAt, issueID: execution.IssueID, usd: execution.CostUSD})
+	}
+	return spend, nil
+}
+
+// checkIssues pauses the issues with spend in the latest window whose spend
+// since their last approval is over the issue multiplier times their
+// estimated budget
+func (d *RunawayDetector) checkIssues(ctx context.Context, spend []spendItem, windowStart time.Time, holds *runawayHolds) error {
+	active := make(map[string]bool)
+	for _, item := range spend {
+		if item.issueID != "" && !item.at.Before(windowStart) {
+			active[item.issueID] = true
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	estimates, err := d.estimateBudgets(ctx, spend)
+	if err != nil {
+		return err
+	}
+
+	for issueID := range active {
+		if holds.issues[issueID] != nil {
+			continue
+		}
+		issue, err := d.getIssue(ctx, issueID)
+		if err != nil {
+			return err
+		}
+		if issue == nil || issue.Status == types.StatusClosed {
+			continue
+		}
+		estimate, ok := estimates[issue.IssueType]
+		if !ok {
+			continue
+		}
+
+		approved := holds.issuesApproved[issueID]
+		issueSpend, err := d.loadSpend(ctx, types.AIUsageFilter{IssueID: issueID, Since: approved}, types.ExecutionFilter{IssueID: issueID})
+		if err != nil {
+			return err
+		}
+		var total float64
+		for _, item := range issueSpend {
+			if !item.at.Before(approved) {
+				total += item.usd
+			}
+		}
+		if total >= d.cfg.MinSpendUSD && total > d.cfg.IssueMultiplier*estimate {
+			if err := d.pauseIssue(ctx, issue, total, estimate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// estimateBudgets returns, per issue type, the median baseline spend of the
+// issues of that type that are closed, for types with enough of them
+func (d *RunawayDetector) estimateBudgets(ctx context.Context, spend []spendItem) (map[types.IssueType]float64, error) {
+	perIssue := make(map[string]float64)
+	for _, item := range spend {
+		if item.issueID != "" {
+			perIssue[item.issueID] += item.usd
+		}
+	}
+
+	byType := make(map[types.IssueType][]float64)
+	for issueID, usd := range perIssue {
+		issue, err := d.getIssue(ctx, issueID)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil && issue.Status == types.StatusClosed {
+			byType[issue.IssueType] = append(byType[issue.IssueType], usd)
+		}
+	}
+
+	estimates := make(map[types.IssueType]float64)
+	for issueType, samples := range byType {
+		if len(samples) < minEstimateSamples {
+			continue
+		}
+		sort.Float64s(samples)
+		median := samples[len(samples)/2]
+		if len(samples)%2 == 0 {
+			median = (samples[len(samples)/2-1] + median) / 2
+		}
+		estimates[issueType] = median
+	}
+	return estimates, nil
+}
+
+// getIssue returns an issue, caching closed ones
+func (d *RunawayDetector) getIssue(ctx context.Context, id string) (*types.Issue, error) {
+	if issue, ok := d.closed[id]; ok {
+		return issue, nil
+	}
+	issue, err := d.store.GetIssue(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", id, err)
+	}
+	if issue != nil && issue.Status == types.StatusClosed {
+		d.closed[id] = issue
+	}
+	return issue, nil
+}
+
+// pauseExecutor files an escalation for runaway spend velocity and pauses
+// the executor until it is closed
+func (d *RunawayDetector) pauseExecutor(ctx context.Context, current, baseline float64) error {
+	window := d.cfg.Window()
+	escalation := &types.Issue{
+		Title:              fmt.Sprintf("Runaway AI spend: $%.2f in %v, %.0fx the baseline", current, window, current/baseline),
+		IssueType:          types.TypeTask,
+		Priority:           0,
+		Status:             types.StatusOpen,
+		AcceptanceCriteria: "The cause of the spend is understood and this issue is closed to let the executor resume",
+		Description: fmt.Sprintf(`# Runaway AI Spend
+
+VC spent **$%.2f** in the last %v, against a baseline of $%.4f per %v over the last %v (%.0fx; the limit is %gx).
+
+The executor has stopped claiming new work. Work already in progress carries on.
+
+## Next Steps
+1. Check recent activity (`+"`vc activity`"+`, `+"`vc cost`"+`) for a looping agent or supervisor
+2. Stop or fix whatever is burning budget
+3. Close this issue (or approve it in Slack) to let the executor resume
+`, current, window, baseline, window, d.cfg.Baseline(), current/baseline, d.cfg.VelocityMultiplier),
+	}
+	if err := d.fileEscalation(ctx, escalation, ""); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n🚨 RUNAWAY AI SPEND: $%.2f in %v (%.0fx baseline), filed %s\n", current, window, current/baseline, escalation.ID)
+	d.logEvent(ctx, "SYSTEM", events.SeverityCritical,
+		fmt.Sprintf("🚨 EXECUTOR PAUSED: runaway AI spend, $%.2f in %v (%.0fx baseline), approve %s to resume", current, window, current/baseline, escalation.ID),
+		map[string]interface{}{
+			"event_subtype":       "runaway_spend",
+			"paused":              true,
+			"window_spend_usd":    current,
+			"baseline_spend_usd":  baseline,
+			"window_minutes":      d.cfg.WindowMinutes,
+			"velocity_multiplier": d.cfg.VelocityMultiplier,
+			"escalation_issue":    escalation.ID,
+		})
+	d.applyExecutorHold(ctx, escalation)
+	return nil
+}
+
+// pauseIssue stops the agent on an issue over budget, keeps it from being
+// claimed and files an escalation from it
+func (d *RunawayDetector) pauseIssue(ctx context.Context, issue *types.Issue, spent, estimate float64) error {
+	reason := fmt.Sprintf("spent $%.2f, %.1fx the estimated $%.2f for a %s", spent, spent/estimate, estimate, issue.IssueType)
+
+	if err := d.store.AddLabel(ctx, issue.ID, "no-auto-claim", runawayActor); err != nil {
+		return fmt.Errorf("failed to hold %s: %w", issue.ID, err)
+	}
+	killed := d.pauser != nil && d.pauser.KillAgent(issue.ID, "runaway AI spend: "+reason)
+
+	escalation := &types.Issue{
+		Title:              fmt.Sprintf("Runaway AI spend on %s: $%.2f, %.1fx its estimated budget", issue.ID, spent, spent/estimate),
+		IssueType:          types.TypeTask,
+		Priority:           issue.Priority,
+		Status:             types.StatusOpen,
+		AcceptanceCriteria: "Decided whether VC should keep spending on the issue",
+		Description: fmt.Sprintf(`# Runaway AI Spend on %s
+
+**%s** has %s. The estimate is the median spend of the %ss that closed in the last %v; the limit is %gx.
+
+VC won't claim %s again until this is approved.
+
+## Next Steps
+1. Review the issue's executions (`+"`vc executions %s`"+`) for why it is so expensive
+2. Split, clarify or close the issue if it is too big or unclear
+3. Approve this issue in Slack, or close it and remove the no-auto-claim label from %s, to let VC resume it
+`, issue.ID, issue.Title, reason, issue.IssueType, d.cfg.Baseline(), d.cfg.IssueMultiplier, issue.ID, issue.ID, issue.ID),
+	}
+	if err := d.fileEscalation(ctx, escalation, issue.ID); err != nil {
+		return err
+	}
+
+	comment := fmt.Sprintf("Paused for runaway AI spend: %s. Waiting for %s to be approved.", reason, escalation.ID)
+	if killed {
+		comment += " The running agent was stopped."
+	}
+	if err := d.store.AddComment(ctx, issue.ID, runawayActor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to comment on %s: %v\n", issue.ID, err)
+	}
+
+	fmt.Printf("🚨 Runaway AI spend on %s: %s - paused until %s is approved\n", issue.ID, reason, escalation.ID)
+	d.logEvent(ctx, issue.ID, events.SeverityError, fmt.Sprintf("🚨 ISSUE PAUSED: runaway AI spend, %s", reason),
+		map[string]interface{}{
+			"event_subtype":    "runaway_issue",
+			"paused":           true,
+			"issue_spend_usd":  spent,
+			"estimated_usd":    estimate,
+			"issue_multiplier": d.cfg.IssueMultiplier,
+			"agent_stopped":    killed,
+			"escalation_issue": escalation.ID,
+		})
+	return nil
+}
+
+// fileEscalation creates a runaway cost escalation, discovered from
+// heldID unless it is empty
+func (d *RunawayDetector) fileEscalation(ctx context.Context, escalation *types.Issue, heldID string) error {
+	if err := d.store.CreateIssue(ctx, escalation, runawayActor); err != nil {
+		return fmt.Errorf("failed to create runaway cost escalation: %w", err)
+	}
+	for _, label := range []string{RunawayLabel, "escalation", "no-auto-claim"} {
+		if err := d.store.AddLabel(ctx, escalation.ID, label, runawayActor); err != nil {
+			return fmt.Errorf("failed to add %s label to %s: %w", label, escalation.ID, err)
+		}
+	}
+	if heldID != "" {
+		dep := &types.Dependency{IssueID: escalation.ID, DependsOnID: heldID, Type: types.DepDiscoveredFrom}
+		if err := d.store.AddDependency(ctx, dep, runawayActor); err != nil {
+			return fmt.Errorf("failed to link %s to %s: %w", escalation.ID, heldID, err)
+		}
+	}
+	return nil
+}
+
+// logEvent records a budget_alert agent event (best-effort)
+func (d *RunawayDetector) logEvent(ctx context.Context, issueID string, severity events.EventSeverity, message string, data map[string]interface{}) {
+	event := &events.AgentEvent{
+		ID:        uuid.New().String(),
+		Type:      events.EventTypeBudgetAlert,
+		Timestamp: time.Now(),
+		IssueID:   issueID,
+		Severity:  severity,
+		Message:   message,
+		Data:      data,
+	}
+	if err := d.store.StoreAgentEvent(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to log runaway cost event: %v\n", err)
+	}
+}
@@ -0,0 +1,175 @@
+package cost
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+type fakePauser struct {
+	paused  bool
+	resumes int
+	killed  []string
+}
+
+func (f *fakePauser) PauseWork(reason string) { f.paused = true }
+func (f *fakePauser) ResumeWork()             { f.paused = false; f.resumes++ }
+func (f *fakePauser) KillAgent(issueID, reason string) bool {
+	f.killed = append(f.killed, issueID)
+	return true
+}
+
+func recordSpend(t *testing.T, store *memory.Store, id, issueID string, ago time.Duration, usd float64) {
+	t.Helper()
+	usage := &types.AIUsage{ID: id, Timestamp: time.Now().Add(-ago), IssueID: issueID, Operation: "analysis", Model: "sonnet", CostUSD: usd}
+	if err := store.RecordAIUsage(context.Background(), usage); err != nil {
+		t.Fatalf("RecordAIUsage() error = %v", err)
+	}
+}
+
+func runawayEscalations(t *testing.T, store *memory.Store) []*types.Issue {
+	t.Helper()
+	issues, err := store.GetIssuesByLabel(context.Background(), RunawayLabel)
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel() error = %v", err)
+	}
+	return issues
+}
+
+func budgetAlerts(t *testing.T, store *memory.Store, issueID string) []*events.AgentEvent {
+	t.Helper()
+	found, err := store.GetAgentEvents(context.Background(), events.EventFilter{IssueID: issueID, Type: events.EventTypeBudgetAlert})
+	if err != nil {
+		t.Fatalf("GetAgentEvents() error = %v", err)
+	}
+	return found
+}
+
+func TestRunawayVelocityPausesExecutor(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	for i, hours := range []int{2, 6, 12} {
+		recordSpend(t, store, "base-"+string(rune('a'+i)), "SYSTEM", time.Duration(hours)*time.Hour, 0.10)
+	}
+	recordSpend(t, store, "burst", "SYSTEM", 2*time.Minute, 2.50)
+
+	pauser := &fakePauser{}
+	d, err := NewRunawayDetector(config.DefaultRunawayCostConfig(), store, pauser)
+	if err != nil {
+		t.Fatalf("NewRunawayDetector() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := d.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+	}
+
+	escalations := runawayEscalations(t, store)
+	if len(escalations) != 1 || escalations[0].Priority != 0 {
+		t.Fatalf("escalations = %+v, want one P0", escalations)
+	}
+	labels, err := store.GetLabels(ctx, escalations[0].ID)
+	if err != nil {
+		t.Fatalf("GetLabels() error = %v", err)
+	}
+	if !slices.Contains(labels, "escalation") || !slices.Contains(labels, "no-auto-claim") {
+		t.Errorf("escalation labels = %v", labels)
+	}
+	if !pauser.paused {
+		t.Fatal("executor not paused")
+	}
+	if alerts := budgetAlerts(t, store, "SYSTEM"); len(alerts) != 1 || alerts[0].Data["event_subtype"] != "runaway_spend" {
+		t.Errorf("budget alerts = %+v", alerts)
+	}
+
+	// Approving resumes the executor, and the approved burst doesn't pause it again
+	if err := store.CloseIssue(ctx, escalations[0].ID, "looked into it", "alice"); err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+	if err := d.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if pauser.paused || pauser.resumes != 1 || len(runawayEscalations(t, store)) != 1 {
+		t.Errorf("paused = %v, resumes = %d, escalations = %d after approval", pauser.paused, pauser.resumes, len(runawayEscalations(t, store)))
+	}
+}
+
+func TestRunawayIssuePausesIssue(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	newTask := func(title string) *types.Issue {
+		issue := &types.Issue{Title: title, AcceptanceCriteria: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue() error = %v", err)
+		}
+		return issue
+	}
+	for i, usd := range []float64{0.80, 1.00, 1.20} {
+		done := newTask("Finished task")
+		recordSpend(t, store, "done-"+string(rune('a'+i)), done.ID, time.Duration(i+3)*time.Hour, usd)
+		if err := store.CloseIssue(ctx, done.ID, "done", "executor"); err != nil {
+			t.Fatalf("CloseIssue() error = %v", err)
+		}
+	}
+	cheap := newTask("Cheap task")
+	recordSpend(t, store, "cheap", cheap.ID, time.Minute, 2.00)
+	runaway := newTask("Runaway task")
+	recordSpend(t, store, "runaway-1", runaway.ID, time.Hour, 1.50)
+	recordSpend(t, store, "runaway-2", runaway.ID, time.Minute, 2.00)
+
+	cfg := config.DefaultRunawayCostConfig()
+	cfg.VelocityMultiplier = 1000 // Only the issue check trips
+	pauser := &fakePauser{}
+	d, err := NewRunawayDetector(cfg, store, pauser)
+	if err != nil {
+		t.Fatalf("NewRunawayDetector() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := d.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+	}
+
+	escalations := runawayEscalations(t, store)
+	if len(escalations) != 1 {
+		t.Fatalf("escalations = %+v, want one for %s", escalations, runaway.ID)
+	}
+	deps, err := store.GetDependencyRecords(ctx, escalations[0].ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords() error = %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != runaway.ID || deps[0].Type != types.DepDiscoveredFrom {
+		t.Errorf("escalation deps = %+v", deps)
+	}
+	labels, err := store.GetLabels(ctx, runaway.ID)
+	if err != nil {
+		t.Fatalf("GetLabels() error = %v", err)
+	}
+	if !slices.Contains(labels, "no-auto-claim") {
+		t.Errorf("runaway issue labels = %v, want no-auto-claim", labels)
+	}
+	if len(pauser.killed) != 1 || pauser.killed[0] != runaway.ID || pauser.paused {
+		t.Errorf("killed = %v, paused = %v", pauser.killed, pauser.paused)
+	}
+	if alerts := budgetAlerts(t, store, runaway.ID); len(alerts) != 1 || alerts[0].Data["estimated_usd"] != 1.00 {
+		t.Errorf("budget alerts = %+v", alerts)
+	}
+
+	// Once approved, only spend after the approval counts
+	if err := store.CloseIssue(ctx, escalations[0].ID, "worth it", "alice"); err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+	recordSpend(t, store, "runaway-3", runaway.ID, 0, 0.50)
+	if err := d.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if got := runawayEscalations(t, store); len(got) != 1 {
+		t.Errorf("escalations = %d after approval, want 1", len(got))
+	}
+}
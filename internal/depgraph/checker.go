@@ -0,0 +1,293 @@
+package depgraph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// KnotLabel marks the diagnostic issues the checker files
+const KnotLabel = "dependency-knot"
+
+// actor is who the checker files diagnostics as
+const actor = "dependency-checker"
+
+// Store is the storage the checker reads the graph from and files
+// diagnostics in
+type Store interface {
+	GraphStore
+	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
+	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
+}
+
+// AICaller makes a one-off AI call (implemented by ai.Supervisor)
+type AICaller interface {
+	CallAI(ctx context.Context, prompt string, operation string, model string, maxTokens int) (string, error)
+}
+
+// Suggestion is the dependency to remove to untie a knot: IssueID's
+// dependency on DependsOnID
+type Suggestion struct {
+	IssueID     string `json:"issue_id"`
+	DependsOnID string `json:"depends_on_id"`
+	Reasoning   string `json:"reasoning"`
+}
+
+// Checker finds knots in the dependency graph and files a diagnostic issue
+// for each one that doesn't have an open one already. It is safe for
+// concurrent use: executors share one.
+type Checker struct {
+	store Store
+	ai    AICaller // nil = rule-based suggestions
+
+	mu        sync.Mutex
+	lastCheck time.Time
+}
+
+// NewChecker creates a dependency graph checker. caller is optional: without
+// it, the most recently added dependency in the knot is suggested for removal.
+func NewChecker(store Store, caller AICaller) *Checker {
+	return &Checker{store: store, ai: caller}
+}
+
+// Run checks the graph every interval until ctx is done
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.Check(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("depgraph: dependency check failed", "error", err)
+			}
+		}
+	}
+}
+
+// CheckIfStale runs Check unless the graph was checked within maxAge. The
+// executor calls it when it finds no ready work, the moment a knot would
+// leave it idle.
+func (c *Checker) CheckIfStale(ctx context.Context, maxAge time.Duration) ([]*types.Issue, error) {
+	c.mu.Lock()
+	stale := time.Since(c.lastCheck) >= maxAge
+	c.mu.Unlock()
+	if !stale {
+		return nil, nil
+	}
+	return c.Check(ctx)
+}
+
+// Check finds the knots in the graph and files a diagnostic issue for each
+// one not already covered by an open diagnostic, returning the new ones
+func (c *Checker) Check(ctx context.Context) ([]*types.Issue, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastCheck = time.Now()
+
+	knots, err := FindKnots(ctx, c.store)
+	if err != nil {
+		return nil, err
+	}
+	if len(knots) == 0 {
+		return nil, nil
+	}
+	covered, err := c.openDiagnostics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filed []*types.Issue
+	for _, knot := range knots {
+		if coveredBy(covered, knot) {
+			continue
+		}
+		diagnostic, err := c.file(ctx, knot, c.suggest(ctx, knot))
+		if err != nil {
+			return filed, err
+		}
+		fmt.Printf("🪢 Dependency %s of %d issues (%s), filed %s\n", knot.Kind, len(knot.Issues), strings.Join(knot.IDs(), ", "), diagnostic.ID)
+		filed = append(filed, diagnostic)
+	}
+	return filed, nil
+}
+
+// openDiagnostics returns, for each open diagnostic, the issues it covers:
+// those it was discovered from
+func (c *Checker) openDiagnostics(ctx context.Context) ([]map[string]bool, error) {
+	diagnostics, err := c.store.GetIssuesByLabel(ctx, KnotLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependency diagnostics: %w", err)
+	}
+	var covered []map[string]bool
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Status == types.StatusClosed {
+			continue
+		}
+		deps, err := c.store.GetDependencyRecords(ctx, diagnostic.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", diagnostic.ID, err)
+		}
+		issues := make(map[string]bool)
+		for _, dep := range deps {
+			if dep.Type == types.DepDiscoveredFrom {
+				issues[dep.DependsOnID] = true
+			}
+		}
+		covered = append(covered, issues)
+	}
+	return covered, nil
+}
+
+// coveredBy reports whether an open diagnostic covers every issue in knot
+func coveredBy(covered []map[string]bool, knot Knot) bool {
+	for _, issues := range covered {
+		all := true
+		for _, issue := range knot.Issues {
+			all = all && issues[issue.ID]
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest asks the AI supervisor which edge of the knot to break, falling
+// back to the most recently added one
+func (c *Checker) suggest(ctx context.Context, knot Knot) Suggestion {
+	fallback := defaultSuggestion(knot)
+	if c.ai == nil {
+		return fallback
+	}
+
+	response, err := c.ai.CallAI(ctx, buildSuggestionPrompt(knot), "dependency-knot", "", 1024)
+	if err != nil {
+		slog.Warn("depgraph: AI edge suggestion failed, using default", "issues", knot.IDs(), "error", err)
+		return fallback
+	}
+	parseResult := ai.Parse[Suggestion](response, ai.ParseOptions{
+		Context:   "dependency knot suggestion response",
+		LogErrors: ai.BoolPtr(true),
+	})
+	if !parseResult.Success {
+		return fallback
+	}
+	suggestion := parseResult.Data
+	for _, edge := range knot.Edges {
+		if edge.Dependency.IssueID == suggestion.IssueID && edge.Dependency.DependsOnID == suggestion.DependsOnID {
+			return suggestion
+		}
+	}
+	slog.Warn("depgraph: AI suggested a dependency outside the knot, using default",
+		"issue", suggestion.IssueID, "depends_on", suggestion.DependsOnID)
+	return fallback
+}
+
+// defaultSuggestion picks the most recently added dependency in the knot,
+// the likeliest to be the mistake that closed it
+func defaultSuggestion(knot Knot) Suggestion {
+	latest := knot.Edges[0].Dependency
+	for _, edge := range knot.Edges[1:] {
+		if edge.Dependency.CreatedAt.After(latest.CreatedAt) {
+			latest = edge.Dependency
+		}
+	}
+	return Suggestion{
+		IssueID:     latest.IssueID,
+		DependsOnID: latest.DependsOnID,
+		Reasoning:   fmt.Sprintf("It is the most recently added dependency in the knot (by %s), so the likeliest to be the one that closed it.", latest.CreatedBy),
+	}
+}
+
+// buildSuggestionPrompt describes the knot and asks which dependency to
+// remove
+func buildSuggestionPrompt(knot Knot) string {
+	var b strings.Builder
+	b.WriteString("You are maintaining the issue tracker of an autonomous coding system. These issues form a knot in the dependency graph: ")
+	b.WriteString("each waits, directly or not, on all the others, so none of them can ever become ready.\n\n")
+	b.WriteString("A 'blocks' dependency makes an issue wait until the issue it depends on closes. ")
+	b.WriteString("A 'parent-child' dependency makes the parent wait until the child closes.\n\n")
+	b.WriteString(knot.describe())
+	b.WriteString("\nDescriptions:\n")
+	for _, issue := range knot.Issues {
+		if issue.Description != "" {
+			fmt.Fprintf(&b, "- %s: %s\n", issue.ID, truncate(issue.Description, 500))
+		}
+	}
+	if len(knot.Waiting) > 0 {
+		fmt.Fprintf(&b, "\n%d other issues are stuck behind the knot.\n", len(knot.Waiting))
+	}
+	b.WriteString("\nWhich single dependency should be removed to untie the knot? Pick the one that is least likely to reflect a real ordering constraint.\n")
+	b.WriteString(`Respond with JSON only: {"issue_id": "<issue the dependency belongs to>", "depends_on_id": "<issue it depends on>", "reasoning": "<one or two sentences>"}`)
+	return b.String()
+}
+
+// file creates the diagnostic issue for a knot. It is discovered from every
+// issue in the knot, which is also how later checks tell the knot is known.
+func (c *Checker) file(ctx context.Context, knot Knot, suggestion Suggestion) (*types.Issue, error) {
+	var waiting string
+	if len(knot.Waiting) > 0 {
+		ids := make([]string, len(knot.Waiting))
+		for i, issue := range knot.Waiting {
+			ids[i] = issue.ID
+		}
+		waiting = fmt.Sprintf("\n%d more issues wait on the knot and are stuck too: %s\n", len(knot.Waiting), strings.Join(ids, ", "))
+	}
+	priority := 4
+	for _, issue := range append(append([]*types.Issue{}, knot.Issues...), knot.Waiting...) {
+		priority = min(priority, issue.Priority)
+	}
+
+	diagnostic := &types.Issue{
+		Title:     fmt.Sprintf("Dependency %s: %s can never become ready", knot.Kind, strings.Join(knot.IDs(), ", ")),
+		IssueType: types.TypeTask,
+		Priority:  priority,
+		Status:    types.StatusOpen,
+		Description: fmt.Sprintf(`These %d issues wait on each other through their dependencies, so none of them can ever be worked.
+
+%s%s
+## Suggested fix
+Remove the dependency of **%s** on **%s**: %s
+
+`+"```"+`
+vc dep remove %s %s
+`+"```"+`
+`, len(knot.Issues), knot.describe(), waiting, suggestion.IssueID, suggestion.DependsOnID, suggestion.Reasoning,
+			suggestion.IssueID, suggestion.DependsOnID),
+		AcceptanceCriteria: "No dependency knot remains among these issues",
+	}
+	if err := c.store.CreateIssue(ctx, diagnostic, actor); err != nil {
+		return nil, fmt.Errorf("failed to file dependency diagnostic: %w", err)
+	}
+	for _, label := range []string{KnotLabel, "no-auto-claim"} {
+		if err := c.store.AddLabel(ctx, diagnostic.ID, label, actor); err != nil {
+			return diagnostic, fmt.Errorf("failed to label %s: %w", diagnostic.ID, err)
+		}
+	}
+	for _, issue := range knot.Issues {
+		dep := &types.Dependency{IssueID: diagnostic.ID, DependsOnID: issue.ID, Type: types.DepDiscoveredFrom}
+		if err := c.store.AddDependency(ctx, dep, actor); err != nil {
+			return diagnostic, fmt.Errorf("failed to link %s to %s: %w", diagnostic.ID, issue.ID, err)
+		}
+	}
+	return diagnostic, nil
+}
+
+// truncate shortens s to n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,248 @@
+// Package depgraph finds knots in the dependency graph: cycles and
+// mutually-blocked clusters of issues that can never become ready.
+//
+// Only some dependencies make one issue wait on another. A 'blocks'
+// dependency makes the issue wait for the issue it depends on to close, and
+// a 'parent-child' dependency makes the parent wait for the child (an epic
+// completes once its children do). Related and discovered-from links never
+// hold anything up. A knot is a strongly connected component of this
+// waits-on graph among unclosed issues: each of its issues waits, directly
+// or not, on every other, so none of them can ever be worked. A knot made
+// only of 'blocks' dependencies is a cycle; one that goes through
+// parent-child links is a cluster.
+//
+// The Checker runs while executors are idle and periodically as a
+// maintenance job. It files a diagnostic issue for each new knot describing
+// it, with the edge the AI supervisor thinks should be broken.
+package depgraph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Kinds of knot
+const (
+	KindCycle   = "cycle"   // Only 'blocks' dependencies
+	KindCluster = "cluster" // Held together by parent-child links too
+)
+
+// GraphStore is the storage the dependency graph is read from
+type GraphStore interface {
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
+}
+
+// Edge is one issue waiting on another because of a dependency
+type Edge struct {
+	Waiter     string            // The issue that waits
+	WaitsOn    string            // The issue it waits on
+	Dependency *types.Dependency // The dependency that makes it wait
+}
+
+// String describes the edge and the dependency behind it
+func (e Edge) String() string {
+	if e.Dependency.Type == types.DepParentChild {
+		return fmt.Sprintf("%s waits on its child %s (%s parent-child %s)", e.Waiter, e.WaitsOn, e.Dependency.IssueID, e.Dependency.DependsOnID)
+	}
+	return fmt.Sprintf("%s is blocked by %s", e.Waiter, e.WaitsOn)
+}
+
+// Knot is a set of unclosed issues that all wait on each other
+type Knot struct {
+	Kind    string
+	Issues  []*types.Issue // Sorted by ID
+	Edges   []Edge         // The waits-on edges between the knot's issues
+	Waiting []*types.Issue // Other unclosed issues stuck behind the knot
+}
+
+// IDs returns the IDs of the knot's issues, sorted
+func (k Knot) IDs() []string {
+	ids := make([]string, len(k.Issues))
+	for i, issue := range k.Issues {
+		ids[i] = issue.ID
+	}
+	return ids
+}
+
+// graph is the waits-on graph of the unclosed issues
+type graph struct {
+	issues map[string]*types.Issue
+	edges  map[string][]Edge // Waiter -> what it waits on
+}
+
+// FindKnots returns the knots among the unclosed issues in store, largest
+// first
+func FindKnots(ctx context.Context, store GraphStore) ([]Knot, error) {
+	g, err := loadGraph(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	var knots []Knot
+	for _, component := range g.components() {
+		if len(component) < 2 {
+			continue // AddDependency rejects self-dependencies
+		}
+		knots = append(knots, g.knot(component))
+	}
+	sort.Slice(knots, func(i, j int) bool {
+		if len(knots[i].Issues) != len(knots[j].Issues) {
+			return len(knots[i].Issues) > len(knots[j].Issues)
+		}
+		return knots[i].Issues[0].ID < knots[j].Issues[0].ID
+	})
+	return knots, nil
+}
+
+// loadGraph reads the unclosed issues and the dependencies that make them
+// wait on each other
+func loadGraph(ctx context.Context, store GraphStore) (*graph, error) {
+	g := &graph{issues: make(map[string]*types.Issue), edges: make(map[string][]Edge)}
+	for _, status := range []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked} {
+		status := status
+		issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Status: &status})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s issues: %w", status, err)
+		}
+		for _, issue := range issues {
+			g.issues[issue.ID] = issue
+		}
+	}
+
+	for id := range g.issues {
+		deps, err := store.GetDependencyRecords(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", id, err)
+		}
+		for _, dep := range deps {
+			if _, open := g.issues[dep.DependsOnID]; !open {
+				continue // Closed issues hold nothing up
+			}
+			switch dep.Type {
+			case types.DepBlocks:
+				g.edges[dep.IssueID] = append(g.edges[dep.IssueID], Edge{Waiter: dep.IssueID, WaitsOn: dep.DependsOnID, Dependency: dep})
+			case types.DepParentChild:
+				g.edges[dep.DependsOnID] = append(g.edges[dep.DependsOnID], Edge{Waiter: dep.DependsOnID, WaitsOn: dep.IssueID, Dependency: dep})
+			}
+		}
+	}
+	return g, nil
+}
+
+// components returns the strongly connected components of the graph
+// (Tarjan's algorithm)
+func (g *graph) components() [][]string {
+	ids := make([]string, 0, len(g.issues))
+	for id := range g.issues {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids) // Deterministic output
+
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	next := 0
+
+	var connect func(id string)
+	connect = func(id string) {
+		index[id] = next
+		lowlink[id] = next
+		next++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, edge := range g.edges[id] {
+			if _, visited := index[edge.WaitsOn]; !visited {
+				connect(edge.WaitsOn)
+				lowlink[id] = min(lowlink[id], lowlink[edge.WaitsOn])
+			} else if onStack[edge.WaitsOn] {
+				lowlink[id] = min(lowlink[id], index[edge.WaitsOn])
+			}
+		}
+
+		if lowlink[id] == index[id] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+	for _, id := range ids {
+		if _, visited := index[id]; !visited {
+			connect(id)
+		}
+	}
+	return components
+}
+
+// knot describes the component made of ids
+func (g *graph) knot(ids []string) Knot {
+	sort.Strings(ids)
+	members := make(map[string]bool, len(ids))
+	knot := Knot{Kind: KindCycle}
+	for _, id := range ids {
+		members[id] = true
+		knot.Issues = append(knot.Issues, g.issues[id])
+	}
+	for _, id := range ids {
+		for _, edge := range g.edges[id] {
+			if members[edge.WaitsOn] {
+				knot.Edges = append(knot.Edges, edge)
+				if edge.Dependency.Type != types.DepBlocks {
+					knot.Kind = KindCluster
+				}
+			}
+		}
+	}
+
+	// Everything that waits on the knot, directly or not, is stuck too
+	waiters := make(map[string][]string)
+	for waiter, edges := range g.edges {
+		for _, edge := range edges {
+			waiters[edge.WaitsOn] = append(waiters[edge.WaitsOn], waiter)
+		}
+	}
+	seen := make(map[string]bool)
+	queue := append([]string{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, waiter := range waiters[id] {
+			if !members[waiter] && !seen[waiter] {
+				seen[waiter] = true
+				knot.Waiting = append(knot.Waiting, g.issues[waiter])
+				queue = append(queue, waiter)
+			}
+		}
+	}
+	sort.Slice(knot.Waiting, func(i, j int) bool { return knot.Waiting[i].ID < knot.Waiting[j].ID })
+	return knot
+}
+
+// describe lists the knot's issues and edges for prompts and issue bodies
+func (k Knot) describe() string {
+	var b strings.Builder
+	b.WriteString("Issues:\n")
+	for _, issue := range k.Issues {
+		fmt.Fprintf(&b, "- %s: %s [P%d %s %s]\n", issue.ID, issue.Title, issue.Priority, issue.IssueType, issue.Status)
+	}
+	b.WriteString("\nWaits on:\n")
+	for _, edge := range k.Edges {
+		fmt.Fprintf(&b, "- %s\n", edge)
+	}
+	return b.String()
+}
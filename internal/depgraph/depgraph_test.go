@@ -0,0 +1,146 @@
+package depgraph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// graphStore is a fixed dependency graph. The real stores refuse to add
+// dependencies that close a cycle, but graphs imported from elsewhere can
+// still have them.
+type graphStore struct {
+	issues []*types.Issue
+	deps   []*types.Dependency
+}
+
+func (g *graphStore) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	var issues []*types.Issue
+	for _, issue := range g.issues {
+		if filter.Status == nil || issue.Status == *filter.Status {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+func (g *graphStore) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	var deps []*types.Dependency
+	for _, dep := range g.deps {
+		if dep.IssueID == issueID {
+			deps = append(deps, dep)
+		}
+	}
+	return deps, nil
+}
+
+func TestFindKnots(t *testing.T) {
+	issue := func(id string, status types.Status) *types.Issue {
+		return &types.Issue{ID: id, Title: "Issue " + id, Status: status, IssueType: types.TypeTask}
+	}
+	blocks := func(issueID, dependsOnID string) *types.Dependency {
+		return &types.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: types.DepBlocks}
+	}
+	store := &graphStore{
+		issues: []*types.Issue{
+			issue("a", types.StatusOpen), issue("b", types.StatusBlocked), issue("c", types.StatusInProgress),
+			issue("d", types.StatusOpen),                                 // Waits on the cycle
+			issue("e", types.StatusOpen), issue("f", types.StatusClosed), // A cycle broken by closing f
+			issue("g", types.StatusOpen), issue("h", types.StatusOpen), // Only related
+		},
+		deps: []*types.Dependency{
+			blocks("a", "b"), blocks("b", "c"), blocks("c", "a"),
+			blocks("d", "a"),
+			blocks("e", "f"), blocks("f", "e"),
+			{IssueID: "g", DependsOnID: "h", Type: types.DepRelated}, {IssueID: "h", DependsOnID: "g", Type: types.DepRelated},
+		},
+	}
+
+	knots, err := FindKnots(context.Background(), store)
+	if err != nil {
+		t.Fatalf("FindKnots failed: %v", err)
+	}
+	if len(knots) != 1 {
+		t.Fatalf("found %d knots, want 1: %+v", len(knots), knots)
+	}
+	knot := knots[0]
+	if knot.Kind != KindCycle || !reflect.DeepEqual(knot.IDs(), []string{"a", "b", "c"}) || len(knot.Edges) != 3 {
+		t.Errorf("knot = %s %v with %d edges, want cycle [a b c] with 3", knot.Kind, knot.IDs(), len(knot.Edges))
+	}
+	if len(knot.Waiting) != 1 || knot.Waiting[0].ID != "d" {
+		t.Errorf("waiting = %v, want d", knot.Waiting)
+	}
+}
+
+func TestCheckerFilesDiagnosticOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	create := func(title string, issueType types.IssueType) *types.Issue {
+		issue := &types.Issue{Title: title, IssueType: issueType, Status: types.StatusOpen, Priority: 2, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	depend := func(issue, dependsOn *types.Issue, depType types.DependencyType) {
+		dep := &types.Dependency{IssueID: issue.ID, DependsOnID: dependsOn.ID, Type: depType}
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	// The epic waits on its child, the child on the schema task and the
+	// schema task on the epic: no cycle in the stored dependencies, but
+	// none of them can ever be ready
+	epic := create("Billing", types.TypeEpic)
+	child := create("Invoice API", types.TypeTask)
+	schema := create("Invoice schema", types.TypeTask)
+	docs := create("Invoice docs", types.TypeTask)
+	depend(child, epic, types.DepParentChild)
+	depend(child, schema, types.DepBlocks)
+	depend(docs, child, types.DepBlocks)
+	depend(schema, epic, types.DepBlocks)
+
+	checker := NewChecker(store, nil)
+	filed, err := checker.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(filed) != 1 {
+		t.Fatalf("filed %d diagnostics, want 1", len(filed))
+	}
+	diagnostic := filed[0]
+	for _, want := range []string{"cluster", fmt.Sprintf("vc dep remove %s %s", schema.ID, epic.ID), docs.ID} {
+		if !strings.Contains(diagnostic.Title+diagnostic.Description, want) {
+			t.Errorf("diagnostic doesn't mention %q:\n%s\n%s", want, diagnostic.Title, diagnostic.Description)
+		}
+	}
+	labels, err := store.GetLabels(ctx, diagnostic.ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if !reflect.DeepEqual(labels, []string{KnotLabel, "no-auto-claim"}) {
+		t.Errorf("labels = %v", labels)
+	}
+	deps, err := store.GetDependencyRecords(ctx, diagnostic.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 3 {
+		t.Errorf("diagnostic has %d dependencies, want one per knot issue", len(deps))
+	}
+
+	// The open diagnostic covers the knot from then on
+	if filed, err = checker.Check(ctx); err != nil || len(filed) != 0 {
+		t.Errorf("second Check filed %d diagnostics (err %v), want none", len(filed), err)
+	}
+	if filed, err = checker.CheckIfStale(ctx, 0); err != nil || len(filed) != 0 {
+		t.Errorf("CheckIfStale filed %d diagnostics (err %v), want none", len(filed), err)
+	}
+}
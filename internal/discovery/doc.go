@@ -31,6 +31,7 @@
 //   - zfc: Detects Zero Framework Cognition violations
 //   - architecture: Analyzes package structure and boundaries (future)
 //   - bugs: Scans for common bug patterns (future)
+//   - todos: Files TODO/FIXME comments and unchecked Markdown tasks (opt-in)
 //   - documentation: Finds missing or outdated docs (future)
 //   - tests: Identifies test coverage gaps (future)
 //   - dependencies: Analyzes dependency health (future)
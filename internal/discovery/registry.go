@@ -255,6 +255,13 @@ func DefaultRegistry(healthRegistry *health.MonitorRegistry) (*WorkerRegistry, e
 		return nil, fmt.Errorf("registering bug hunter worker: %w", err)
 	}
 
+	// TODO scanner - TODO/FIXME comments and unchecked Markdown tasks.
+	// Not in any preset: run it explicitly with --workers=todos.
+	todoWorker := NewTODOScannerWorker()
+	if err := registry.Register(todoWorker); err != nil {
+		return nil, fmt.Errorf("registering todo scanner worker: %w", err)
+	}
+
 	// TODO: Additional workers to implement in future epics
 	// Note: Other workers (doc_auditor, dependency_auditor, test_coverage_analyzer, security_scanner)
 	// are being implemented in parallel epic vc-cq4l
@@ -0,0 +1,288 @@
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/health"
+)
+
+// TODOScannerWorker turns work left in the code into issues: TODO and FIXME
+// comments, and unchecked Markdown task-list items ("- [ ] ...").
+// Philosophy: 'Work noted in the code is work nobody tracks. Put it where it gets done'
+//
+// Each item becomes a low-priority issue (P4, P3 for FIXME) whose
+// description records the file and line it came from. Filed issues go
+// through the orchestrator's deduplication like any other, so items already
+// tracked aren't filed twice.
+//
+// ZFC Compliance: Pure pattern matching. Deduplication decides what's new.
+type TODOScannerWorker struct{}
+
+// NewTODOScannerWorker creates a new TODO scanner worker.
+func NewTODOScannerWorker() *TODOScannerWorker {
+	return &TODOScannerWorker{}
+}
+
+const (
+	// maxTODOFileSize skips files larger than this (generated or data files)
+	maxTODOFileSize = 1 << 20
+	// maxTODOTitle bounds the length of an issue title, in characters
+	maxTODOTitle = 100
+	// maxTODOContinuation bounds how many comment lines after a marker are
+	// read as part of its text
+	maxTODOContinuation = 5
+)
+
+var (
+	// todoCommentRe matches a TODO or FIXME marker starting a comment, with
+	// an optional owner: "// TODO(alice): text", "# FIXME text". The comment
+	// must start a line or follow whitespace, so quoted markers and URLs
+	// don't match.
+	todoCommentRe = regexp.MustCompile(`(?:^|\s)(//|#|/\*|<!--|--|;)\s*(TODO|FIXME)\b(?:\([^)]*\))?:?\s*(.*)$`)
+	// taskItemRe matches an unchecked Markdown task-list item
+	taskItemRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[ \]\s+(.+)$`)
+	// headingRe matches a Markdown heading
+	headingRe = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+)
+
+// todoSkipDirs are directories never scanned
+var todoSkipDirs = map[string]bool{"vendor": true, "node_modules": true, "testdata": true}
+
+// Name implements DiscoveryWorker.
+func (w *TODOScannerWorker) Name() string {
+	return "todos"
+}
+
+// Philosophy implements DiscoveryWorker.
+func (w *TODOScannerWorker) Philosophy() string {
+	return "Work noted in the code is work nobody tracks. Put it where it gets done"
+}
+
+// Scope implements DiscoveryWorker.
+func (w *TODOScannerWorker) Scope() string {
+	return "TODO and FIXME comments in source files, unchecked task-list items in Markdown files"
+}
+
+// Cost implements DiscoveryWorker.
+func (w *TODOScannerWorker) Cost() health.CostEstimate {
+	return health.CostEstimate{
+		EstimatedDuration: 20 * time.Second,
+		AICallsEstimated:  0, // Pure pattern matching
+		RequiresFullScan:  true,
+		Category:          health.CostCheap,
+	}
+}
+
+// Dependencies implements DiscoveryWorker.
+func (w *TODOScannerWorker) Dependencies() []string {
+	return nil
+}
+
+// Analyze implements DiscoveryWorker.
+func (w *TODOScannerWorker) Analyze(ctx context.Context, codebase health.CodebaseContext) (*WorkerResult, error) {
+	startTime := time.Now()
+
+	result := &WorkerResult{
+		IssuesDiscovered: []DiscoveredIssue{},
+		AnalyzedAt:       startTime,
+		Context:          fmt.Sprintf("Scanned %s for TODO/FIXME comments and unchecked Markdown tasks", codebase.RootPath),
+		Reasoning:        fmt.Sprintf("Based on philosophy: '%s'", w.Philosophy()),
+	}
+
+	err := filepath.WalkDir(codebase.RootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != codebase.RootPath && (strings.HasPrefix(name, ".") || todoSkipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isTextFile(path) || strings.HasPrefix(name, ".") || strings.Contains(name, "_generated.") || strings.HasSuffix(name, ".pb.go") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxTODOFileSize {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			result.Stats.ErrorsIgnored++
+			return nil
+		}
+
+		relPath, err := filepath.Rel(codebase.RootPath, path)
+		if err != nil {
+			relPath = path
+		}
+		result.Stats.FilesAnalyzed++
+		for _, issue := range w.scanFile(filepath.ToSlash(relPath), data) {
+			issue.DiscoveredAt = startTime
+			result.IssuesDiscovered = append(result.IssuesDiscovered, issue)
+			result.Stats.PatternsFound++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for TODOs: %w", err)
+	}
+
+	result.Stats.IssuesFound = len(result.IssuesDiscovered)
+	result.Stats.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// scanFile finds the TODO and FIXME comments in a file, and its unchecked
+// task-list items if it's Markdown
+func (w *TODOScannerWorker) scanFile(relPath string, data []byte) []DiscoveredIssue {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTODOFileSize)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	markdown := strings.EqualFold(filepath.Ext(relPath), ".md")
+	var issues []DiscoveredIssue
+	var heading string
+	inFence := false
+	for i, line := range lines {
+		if markdown {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				inFence = !inFence
+				continue
+			}
+			if inFence {
+				continue // Examples, not work
+			}
+			if m := headingRe.FindStringSubmatch(line); m != nil {
+				heading = m[1]
+				continue
+			}
+			if m := taskItemRe.FindStringSubmatch(line); m != nil {
+				issues = append(issues, w.taskIssue(relPath, i+1, strings.TrimSpace(m[1]), heading))
+				continue
+			}
+		}
+
+		m := todoCommentRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		prefix := line[m[2]:m[3]]
+		marker := line[m[4]:m[5]]
+		text := cleanCommentText(line[m[6]:m[7]])
+		// A comment continues on the following lines with the same prefix
+		end := i
+		for j := i + 1; j < len(lines) && j <= i+maxTODOContinuation; j++ {
+			next := strings.TrimSpace(lines[j])
+			if !strings.HasPrefix(next, prefix) || todoCommentRe.MatchString(next) {
+				break
+			}
+			more := cleanCommentText(strings.TrimPrefix(next, prefix))
+			if more == "" {
+				break
+			}
+			text = strings.TrimSpace(text + " " + more)
+			end = j
+		}
+		if text == "" {
+			continue // Nothing to act on
+		}
+		issues = append(issues, w.commentIssue(relPath, i+1, end+1, marker, text))
+	}
+	return issues
+}
+
+// commentIssue builds the issue for a TODO or FIXME comment
+func (w *TODOScannerWorker) commentIssue(relPath string, line, lineEnd int, marker, text string) DiscoveredIssue {
+	issueType, priority := "task", 4
+	if marker == "FIXME" {
+		issueType, priority = "bug", 3
+	}
+	return DiscoveredIssue{
+		Title:       todoTitle(text),
+		Description: fmt.Sprintf("%s comment at `%s:%d`:\n\n> %s\n\nDo the work the comment describes, then remove the comment.", marker, relPath, line, text),
+		Category:    "todo",
+		Type:        issueType,
+		Priority:    priority,
+		Tags:        []string{"todo", strings.ToLower(marker)},
+		FilePath:    relPath,
+		LineStart:   line,
+		LineEnd:     lineEnd,
+		Evidence: map[string]interface{}{
+			"marker": marker,
+			"text":   text,
+		},
+		DiscoveredBy: w.Name(),
+		Confidence:   1.0, // Exact match
+	}
+}
+
+// taskIssue builds the issue for an unchecked Markdown task-list item,
+// listed under heading
+func (w *TODOScannerWorker) taskIssue(relPath string, line int, text, heading string) DiscoveredIssue {
+	location := fmt.Sprintf("`%s:%d`", relPath, line)
+	if heading != "" {
+		location += fmt.Sprintf(" (under %q)", heading)
+	}
+	return DiscoveredIssue{
+		Title:       todoTitle(text),
+		Description: fmt.Sprintf("Unchecked task at %s:\n\n> %s\n\nDo the task, then check it off.", location, text),
+		Category:    "todo",
+		Type:        "task",
+		Priority:    4,
+		Tags:        []string{"todo", "checklist"},
+		FilePath:    relPath,
+		LineStart:   line,
+		LineEnd:     line,
+		Evidence: map[string]interface{}{
+			"text":    text,
+			"heading": heading,
+		},
+		DiscoveredBy: w.Name(),
+		Confidence:   1.0, // Exact match
+	}
+}
+
+// cleanCommentText strips comment closers and decoration from comment text
+func cleanCommentText(text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.TrimSuffix(text, "-->")
+	text = strings.TrimSuffix(text, "*/")
+	return strings.TrimSpace(strings.TrimLeft(text, "*"))
+}
+
+// todoTitle makes an issue title from an item's text: its first sentence,
+// capitalized and bounded in length
+func todoTitle(text string) string {
+	title := text
+	if i := strings.Index(title, ". "); i > 0 {
+		title = title[:i]
+	}
+	title = strings.TrimRight(title, ".:; ")
+	runes := []rune(title)
+	if len(runes) > maxTODOTitle {
+		title = strings.TrimSpace(string(runes[:maxTODOTitle-1])) + "…"
+		runes = []rune(title)
+	}
+	if len(runes) > 0 {
+		title = strings.ToUpper(string(runes[0])) + string(runes[1:])
+	}
+	return title
+}
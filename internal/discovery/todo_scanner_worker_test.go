@@ -0,0 +1,112 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/health"
+)
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTODOScannerWorker(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "internal/cache/cache.go", `package cache
+
+// TODO(alice): evict entries by size, not count.
+// Count-based eviction lets large values
+// blow the memory budget.
+func Evict() {}
+
+func Get() {
+	x := 1 // FIXME: racy under concurrent writers
+	_ = x
+	// TODO:
+}
+`)
+	writeFile(t, root, "scripts/deploy.sh", "#!/bin/sh\n# TODO retry failed uploads\n")
+	writeFile(t, root, "docs/ROADMAP.md", "# Roadmap\n\n## Storage\n\n- [x] SQLite backend\n- [ ] Postgres backend\n\n```\n- [ ] example, not work\n```\n\n1. [ ] Document migrations\n")
+	writeFile(t, root, "vendor/lib/lib.go", "package lib\n\n// TODO: vendored, not ours\n")
+	writeFile(t, root, ".git/hooks/pre-commit", "# TODO: hidden, not scanned\n")
+
+	worker := NewTODOScannerWorker()
+	result, err := worker.Analyze(context.Background(), health.CodebaseContext{RootPath: root})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	byTitle := make(map[string]DiscoveredIssue)
+	for _, issue := range result.IssuesDiscovered {
+		byTitle[issue.Title] = issue
+	}
+	if len(byTitle) != 5 || result.Stats.IssuesFound != 5 {
+		t.Fatalf("found %d issues, want 5: %v", len(byTitle), result.IssuesDiscovered)
+	}
+
+	evict, ok := byTitle["Evict entries by size, not count"]
+	if !ok {
+		t.Fatalf("TODO comment not found: %v", result.IssuesDiscovered)
+	}
+	if evict.FilePath != "internal/cache/cache.go" || evict.LineStart != 3 || evict.LineEnd != 5 {
+		t.Errorf("TODO location = %s:%d-%d", evict.FilePath, evict.LineStart, evict.LineEnd)
+	}
+	if evict.Type != "task" || evict.Priority != 4 || evict.Category != "todo" || evict.DiscoveredBy != "todos" {
+		t.Errorf("unexpected TODO issue %+v", evict)
+	}
+	if !strings.Contains(evict.Description, "`internal/cache/cache.go:3`") || !strings.Contains(evict.Description, "blow the memory budget.") {
+		t.Errorf("description lacks provenance or continuation: %q", evict.Description)
+	}
+
+	racy := byTitle["Racy under concurrent writers"]
+	if racy.Type != "bug" || racy.Priority != 3 || racy.LineStart != 9 {
+		t.Errorf("unexpected FIXME issue %+v", racy)
+	}
+	if deploy := byTitle["Retry failed uploads"]; deploy.FilePath != "scripts/deploy.sh" || deploy.LineStart != 2 {
+		t.Errorf("unexpected shell TODO issue %+v", deploy)
+	}
+
+	postgres := byTitle["Postgres backend"]
+	if postgres.FilePath != "docs/ROADMAP.md" || postgres.LineStart != 6 || postgres.Priority != 4 ||
+		!strings.Contains(postgres.Description, `(under "Storage")`) {
+		t.Errorf("unexpected checklist issue %+v", postgres)
+	}
+	if _, ok := byTitle["Document migrations"]; !ok {
+		t.Errorf("numbered checklist item not found: %v", result.IssuesDiscovered)
+	}
+}
+
+func TestTODOTitle(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"handle errors. Currently they're dropped", "Handle errors"},
+		{"retry on 503:", "Retry on 503"},
+		{strings.Repeat("é", 150), strings.Repeat("É", 1) + strings.Repeat("é", 98) + "…"},
+	}
+	for _, tt := range tests {
+		if got := todoTitle(tt.text); got != tt.want {
+			t.Errorf("todoTitle(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTODOScannerIgnoresQuotedMarkers(t *testing.T) {
+	worker := NewTODOScannerWorker()
+	issues := worker.scanFile("main.go", []byte("package main\n\nvar example = \"// TODO: not a comment\"\nvar url = \"http://x/#TODO\"\n"))
+	if len(issues) != 0 {
+		t.Errorf("quoted markers matched: %v", issues)
+	}
+}
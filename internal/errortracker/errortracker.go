@@ -0,0 +1,436 @@
+// Package errortracker turns production errors into bug issues.
+//
+// Sentry (through an internal integration's webhooks) or any other error
+// tracker (through the generic webhook) sends error events to an Intake,
+// which groups them by fingerprint. Every flush interval, each group with
+// new events is reconciled with the issue tracker:
+//
+//   - A group without an issue gets one once it has enough events: the AI
+//     supervisor drafts a bug report with the repro context (message, stack
+//     trace, frequency, environments and releases), which is checked for
+//     duplicates of open issues before it's filed
+//   - An open issue is raised in priority when the group becomes more
+//     frequent or severe
+//   - A closed issue is reopened: the error has regressed
+//
+// Issues are linked to their group by an error-group:<hash> label, so the
+// link survives restarts and duplicates found by the deduplicator.
+package errortracker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+const (
+	// Actor is who issues are filed and updated as
+	Actor = "error-tracker"
+
+	// Label marks issues filed from error tracker events
+	Label = "error-tracker"
+
+	// GroupLabelPrefix links an issue to its error group
+	GroupLabelPrefix = "error-group:"
+
+	// maxGroups bounds memory; the groups seen least recently are dropped
+	// first. A dropped group is found again through its label.
+	maxGroups = 10000
+
+	// maxValues bounds the environments and releases kept per group
+	maxValues = 10
+)
+
+// Store is the storage an Intake files and updates issues in
+type Store interface {
+	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
+	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+}
+
+// Drafter drafts bug issues from error reports; *ai.Supervisor implements it
+type Drafter interface {
+	DraftBugReport(ctx context.Context, report string) (*ai.BugReportDraft, error)
+}
+
+// GroupLabel returns the label linking issues to the group of fingerprint
+func GroupLabel(fingerprint string) string {
+	sum := sha256.Sum256([]byte(fingerprint))
+	return GroupLabelPrefix + hex.EncodeToString(sum[:6])
+}
+
+// Priority returns the priority of a group of errors at level that occurred
+// count times: fatal errors are P1, errors P2, warnings P3 and anything else
+// P4, raised by one at 100 occurrences and by two at 1000
+func Priority(level string, count int) int {
+	priority := 4
+	switch level {
+	case "fatal", "critical":
+		priority = 1
+	case "error":
+		priority = 2
+	case "warning":
+		priority = 3
+	}
+	switch {
+	case count >= 1000:
+		priority -= 2
+	case count >= 100:
+		priority--
+	}
+	return max(priority, 0)
+}
+
+// group is the events sharing a fingerprint
+type group struct {
+	latest       Event
+	count        int // Occurrences seen
+	pending      int // Occurrences since the last flush
+	firstSeen    time.Time
+	lastSeen     time.Time
+	environments []string
+	releases     []string
+}
+
+// Intake groups error events and files them as issues
+type Intake struct {
+	cfg     config.ErrorTrackerConfig
+	store   Store
+	drafter Drafter
+	dedup   deduplication.Deduplicator
+	now     func() time.Time
+
+	mu     sync.Mutex
+	groups map[string]*group
+}
+
+// NewIntake creates an intake for cfg, which must be enabled. Without a
+// drafter, issues are filed from a template; without a deduplicator, they
+// aren't checked for duplicates.
+func NewIntake(cfg config.ErrorTrackerConfig, store Store, drafter Drafter, dedup deduplication.Deduplicator) (*Intake, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("error tracker secret is not configured")
+	}
+	return &Intake{
+		cfg:     cfg,
+		store:   store,
+		drafter: drafter,
+		dedup:   dedup,
+		now:     time.Now,
+		groups:  make(map[string]*group),
+	}, nil
+}
+
+// Record adds an event to its group. Events from environments that aren't
+// taken in are dropped.
+func (in *Intake) Record(event Event) error {
+	if err := event.normalize(in.now()); err != nil {
+		return err
+	}
+	if !in.cfg.TakesEnvironment(event.Environment) {
+		return nil
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	g, ok := in.groups[event.Fingerprint]
+	if !ok {
+		if len(in.groups) >= maxGroups {
+			in.evict()
+		}
+		g = &group{firstSeen: event.Timestamp}
+		in.groups[event.Fingerprint] = g
+	}
+	g.latest = event
+	g.count += event.Count
+	g.pending += event.Count
+	if event.Total > g.count {
+		g.count = event.Total
+	}
+	g.firstSeen = minTime(g.firstSeen, event.Timestamp)
+	if event.Timestamp.After(g.lastSeen) {
+		g.lastSeen = event.Timestamp
+	}
+	g.environments = addValue(g.environments, event.Environment)
+	g.releases = addValue(g.releases, event.Release)
+	return nil
+}
+
+// evict drops the group seen least recently. The caller holds mu.
+func (in *Intake) evict() {
+	var oldest string
+	for fingerprint, g := range in.groups {
+		if oldest == "" || g.lastSeen.Before(in.groups[oldest].lastSeen) {
+			oldest = fingerprint
+		}
+	}
+	delete(in.groups, oldest)
+}
+
+// Run flushes groups every flush interval until ctx is done
+func (in *Intake) Run(ctx context.Context) {
+	ticker := time.NewTicker(in.cfg.FlushInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := in.Flush(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("error tracker: failed to file errors", "error", err)
+			}
+		}
+	}
+}
+
+// Flush reconciles every group with new events with the issue tracker. A
+// group that fails keeps its events for the next flush; the other groups
+// are still flushed and the first error is returned.
+func (in *Intake) Flush(ctx context.Context) error {
+	in.mu.Lock()
+	var fingerprints []string
+	snapshots := make(map[string]group)
+	for fingerprint, g := range in.groups {
+		if g.pending > 0 {
+			fingerprints = append(fingerprints, fingerprint)
+			snapshots[fingerprint] = *g
+		}
+	}
+	in.mu.Unlock()
+	slices.Sort(fingerprints)
+
+	var firstErr error
+	for _, fingerprint := range fingerprints {
+		snapshot := snapshots[fingerprint]
+		issueID, err := in.flushGroup(ctx, fingerprint, snapshot)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to file %q: %w", snapshot.latest.Title, err)
+			}
+			continue
+		}
+
+		// Groups below the event threshold keep their events for the next
+		// flush
+		if issueID == "" {
+			continue
+		}
+		in.mu.Lock()
+		if g, ok := in.groups[fingerprint]; ok {
+			g.pending -= snapshot.pending
+		}
+		in.mu.Unlock()
+	}
+	return firstErr
+}
+
+// flushGroup files or updates the issue of a group, returning its ID, or
+// "" if the group doesn't have enough events for one yet
+func (in *Intake) flushGroup(ctx context.Context, fingerprint string, g group) (string, error) {
+	issue, err := in.findIssue(ctx, fingerprint)
+	if err != nil {
+		return "", err
+	}
+	priority := Priority(g.latest.Level, g.count)
+
+	switch {
+	case issue == nil:
+		if g.count < in.cfg.MinEvents {
+			return "", nil
+		}
+		return in.file(ctx, fingerprint, g, priority)
+
+	case issue.Status == types.StatusClosed:
+		updates := map[string]interface{}{"status": string(types.StatusOpen)}
+		if priority < issue.Priority {
+			updates["priority"] = priority
+		}
+		if err := in.store.UpdateIssue(ctx, issue.ID, updates, Actor); err != nil {
+			return "", fmt.Errorf("failed to reopen %s: %w", issue.ID, err)
+		}
+		comment := fmt.Sprintf("Reopened: the error regressed with %d new occurrence(s)%s.\n\n%s",
+			g.pending, releaseNote(g.latest), in.report(g))
+		if err := in.store.AddComment(ctx, issue.ID, Actor, comment); err != nil {
+			return "", fmt.Errorf("failed to comment on %s: %w", issue.ID, err)
+		}
+		slog.Info("error tracker: reopened regressed issue", "issue", issue.ID, "occurrences", g.pending)
+
+	case priority < issue.Priority:
+		if err := in.store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": priority}, Actor); err != nil {
+			return "", fmt.Errorf("failed to raise priority of %s: %w", issue.ID, err)
+		}
+		comment := fmt.Sprintf("Raised from P%d to P%d: the error has occurred %d time(s) at level %s.",
+			issue.Priority, priority, g.count, g.latest.Level)
+		if err := in.store.AddComment(ctx, issue.ID, Actor, comment); err != nil {
+			return "", fmt.Errorf("failed to comment on %s: %w", issue.ID, err)
+		}
+		slog.Info("error tracker: raised issue priority", "issue", issue.ID, "priority", priority)
+	}
+	return issue.ID, nil
+}
+
+// findIssue returns the issue linked to a group: an open one if there is
+// one, else the one closed last
+func (in *Intake) findIssue(ctx context.Context, fingerprint string) (*types.Issue, error) {
+	issues, err := in.store.GetIssuesByLabel(ctx, GroupLabel(fingerprint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up error group issue: %w", err)
+	}
+	var found *types.Issue
+	for _, issue := range issues {
+		switch {
+		case found == nil:
+			found = issue
+		case found.Status == types.StatusClosed && issue.Status != types.StatusClosed:
+			found = issue
+		case (found.Status == types.StatusClosed) == (issue.Status == types.StatusClosed) && issue.UpdatedAt.After(found.UpdatedAt):
+			found = issue
+		}
+	}
+	return found, nil
+}
+
+// file drafts and files the issue of a group, or links the group to an
+// open issue the draft duplicates
+func (in *Intake) file(ctx context.Context, fingerprint string, g group, priority int) (string, error) {
+	report := in.report(g)
+	draft := templateDraft(g, report)
+	if in.drafter != nil {
+		drafted, err := in.drafter.DraftBugReport(ctx, report)
+		if err != nil {
+			slog.Warn("error tracker: AI draft failed, filing from template", "error", err)
+		} else {
+			draft = drafted
+			// Keep the raw context next to the AI's summary
+			draft.Description = strings.TrimSpace(draft.Description) + "\n\n---\n\n" + report
+		}
+	}
+
+	issue := &types.Issue{
+		Title:              truncate(draft.Title, 200),
+		Description:        draft.Description,
+		AcceptanceCriteria: draft.AcceptanceCriteria,
+		Status:             types.StatusOpen,
+		Priority:           priority,
+		IssueType:          types.TypeBug,
+	}
+	label := GroupLabel(fingerprint)
+
+	if in.dedup != nil {
+		decision, err := in.dedup.CheckDuplicate(ctx, issue)
+		if err != nil {
+			slog.Warn("error tracker: duplicate check failed, filing anyway", "error", err)
+		} else if decision.IsDuplicate && decision.DuplicateOf != "" {
+			if err := in.store.AddLabel(ctx, decision.DuplicateOf, label, Actor); err != nil {
+				return "", fmt.Errorf("failed to link %s to its error group: %w", decision.DuplicateOf, err)
+			}
+			comment := fmt.Sprintf("Error tracker reports this in production (%d occurrence(s)).\n\n%s", g.count, report)
+			if err := in.store.AddComment(ctx, decision.DuplicateOf, Actor, comment); err != nil {
+				return "", fmt.Errorf("failed to comment on %s: %w", decision.DuplicateOf, err)
+			}
+			slog.Info("error tracker: linked error group to existing issue",
+				"issue", decision.DuplicateOf, "confidence", decision.Confidence)
+			return decision.DuplicateOf, nil
+		}
+	}
+
+	if err := in.store.CreateIssue(ctx, issue, Actor); err != nil {
+		return "", fmt.Errorf("failed to create issue: %w", err)
+	}
+	for _, l := range []string{Label, label} {
+		if err := in.store.AddLabel(ctx, issue.ID, l, Actor); err != nil {
+			return "", fmt.Errorf("failed to label %s: %w", issue.ID, err)
+		}
+	}
+	slog.Info("error tracker: filed bug", "issue", issue.ID, "priority", priority, "occurrences", g.count)
+	return issue.ID, nil
+}
+
+// report describes a group for the drafter and the issue
+func (in *Intake) report(g group) string {
+	e := g.latest
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Error:** %s\n", e.Title)
+	fmt.Fprintf(&b, "**Level:** %s\n", e.Level)
+	if e.Culprit != "" {
+		fmt.Fprintf(&b, "**Culprit:** %s\n", e.Culprit)
+	}
+	fmt.Fprintf(&b, "**Occurrences:** %d (first seen %s, last seen %s)\n",
+		g.count, g.firstSeen.UTC().Format(time.RFC3339), g.lastSeen.UTC().Format(time.RFC3339))
+	if len(g.environments) > 0 {
+		fmt.Fprintf(&b, "**Environments:** %s\n", strings.Join(g.environments, ", "))
+	}
+	if len(g.releases) > 0 {
+		fmt.Fprintf(&b, "**Releases:** %s\n", strings.Join(g.releases, ", "))
+	}
+	if e.URL != "" {
+		fmt.Fprintf(&b, "**Link:** %s\n", e.URL)
+	}
+	if e.Message != "" && e.Message != e.Title {
+		fmt.Fprintf(&b, "\n**Message:**\n```\n%s\n```\n", truncate(e.Message, 4000))
+	}
+	if e.Stacktrace != "" {
+		fmt.Fprintf(&b, "\n**Stack trace:**\n```\n%s\n```\n", truncate(e.Stacktrace, 12000))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// templateDraft is the issue filed when there's no drafter or it fails
+func templateDraft(g group, report string) *ai.BugReportDraft {
+	where := ""
+	if g.latest.Culprit != "" {
+		where = " in " + g.latest.Culprit
+	}
+	return &ai.BugReportDraft{
+		Title:       fmt.Sprintf("Fix %s%s", g.latest.Title, where),
+		Description: report,
+		AcceptanceCriteria: "The error no longer occurs for the inputs that raised it, " +
+			"and a regression test reproduces it without the fix",
+	}
+}
+
+func releaseNote(e Event) string {
+	if e.Release == "" {
+		return ""
+	}
+	return " in release " + e.Release
+}
+
+func addValue(values []string, value string) []string {
+	if value == "" || slices.Contains(values, value) || len(values) >= maxValues {
+		return values
+	}
+	return append(values, value)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.IsZero() || b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,316 @@
+package errortracker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+type fakeDrafter struct {
+	reports []string
+	err     error
+}
+
+func (f *fakeDrafter) DraftBugReport(ctx context.Context, report string) (*ai.BugReportDraft, error) {
+	f.reports = append(f.reports, report)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &ai.BugReportDraft{Title: "Fix nil user in checkout", Description: "Checkout panics.", AcceptanceCriteria: "No panic"}, nil
+}
+
+type fakeDedup struct {
+	duplicateOf string
+}
+
+func (f *fakeDedup) CheckDuplicate(ctx context.Context, issue *types.Issue) (*deduplication.DuplicateDecision, error) {
+	return &deduplication.DuplicateDecision{IsDuplicate: f.duplicateOf != "", DuplicateOf: f.duplicateOf, Confidence: 0.9}, nil
+}
+
+func (f *fakeDedup) DeduplicateBatch(ctx context.Context, candidates []*types.Issue) (*deduplication.DeduplicationResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func newTestIntake(t *testing.T, drafter Drafter, dedup deduplication.Deduplicator) (*Intake, *memory.Store) {
+	t.Helper()
+	cfg := config.DefaultErrorTrackerConfig()
+	cfg.Secret = "s3cret"
+	cfg.MinEvents = 2
+	store := memory.New()
+	intake, err := NewIntake(cfg, store, drafter, dedup)
+	if err != nil {
+		t.Fatalf("NewIntake() error = %v", err)
+	}
+	return intake, store
+}
+
+func groupIssues(t *testing.T, store *memory.Store, fingerprint string) []*types.Issue {
+	t.Helper()
+	issues, err := store.GetIssuesByLabel(context.Background(), GroupLabel(fingerprint))
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel() error = %v", err)
+	}
+	return issues
+}
+
+func TestPriority(t *testing.T) {
+	tests := []struct {
+		level string
+		count int
+		want  int
+	}{
+		{"fatal", 1, 1},
+		{"error", 1, 2},
+		{"error", 100, 1},
+		{"error", 1000, 0},
+		{"fatal", 5000, 0},
+		{"warning", 150, 2},
+		{"info", 1, 4},
+	}
+	for _, tt := range tests {
+		if got := Priority(tt.level, tt.count); got != tt.want {
+			t.Errorf("Priority(%q, %d) = %d, want %d", tt.level, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestIntakeFilesGroupedErrors(t *testing.T) {
+	ctx := context.Background()
+	drafter := &fakeDrafter{}
+	intake, store := newTestIntake(t, drafter, &fakeDedup{})
+
+	event := Event{Fingerprint: "checkout-nil", Title: "TypeError: user is nil", Culprit: "checkout.Submit",
+		Stacktrace: "checkout.go:42 in Submit", Environment: "production", Release: "1.4.0"}
+	if err := intake.Record(event); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	// Below the event threshold: nothing is filed, the event is kept
+	if err := intake.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if issues := groupIssues(t, store, "checkout-nil"); len(issues) != 0 {
+		t.Fatalf("filed %d issues below the threshold", len(issues))
+	}
+
+	event.Release = "1.4.1"
+	if err := intake.Record(event); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := intake.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	issues := groupIssues(t, store, "checkout-nil")
+	if len(issues) != 1 {
+		t.Fatalf("filed %d issues, want 1", len(issues))
+	}
+	issue := issues[0]
+	if issue.Title != "Fix nil user in checkout" || issue.IssueType != types.TypeBug || issue.Priority != 2 {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+	if !strings.Contains(issue.Description, "Checkout panics.") || !strings.Contains(issue.Description, "checkout.go:42") {
+		t.Errorf("description should carry the draft and the report:\n%s", issue.Description)
+	}
+	if len(drafter.reports) != 1 || !strings.Contains(drafter.reports[0], "**Occurrences:** 2") ||
+		!strings.Contains(drafter.reports[0], "1.4.0, 1.4.1") {
+		t.Errorf("unexpected reports %q", drafter.reports)
+	}
+	labels, _ := store.GetLabels(ctx, issue.ID)
+	if !strings.Contains(strings.Join(labels, ","), Label) {
+		t.Errorf("labels = %v, want %s", labels, Label)
+	}
+
+	// Flushing again without new events changes nothing
+	if err := intake.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(drafter.reports) != 1 {
+		t.Errorf("drafted %d times, want once", len(drafter.reports))
+	}
+
+	// A flood of events raises the priority
+	event.Count = 200
+	if err := intake.Record(event); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := intake.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if issue, _ := store.GetIssue(ctx, issue.ID); issue.Priority != 1 {
+		t.Errorf("priority = %d, want 1 after 202 occurrences", issue.Priority)
+	}
+
+	// Events after the issue is closed reopen it
+	if err := store.CloseIssue(ctx, issue.ID, "fixed", "test"); err != nil {
+		t.Fatal(err)
+	}
+	event.Count = 1
+	if err := intake.Record(event); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := intake.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if issue, _ := store.GetIssue(ctx, issue.ID); issue.Status != types.StatusOpen {
+		t.Errorf("status = %s, want the regressed issue reopened", issue.Status)
+	}
+	if issues := groupIssues(t, store, "checkout-nil"); len(issues) != 1 {
+		t.Errorf("group has %d issues, want 1", len(issues))
+	}
+}
+
+func TestIntakeLinksDuplicates(t *testing.T) {
+	ctx := context.Background()
+	intake, store := newTestIntake(t, &fakeDrafter{err: fmt.Errorf("AI unavailable")}, nil)
+	existing := &types.Issue{Title: "Checkout crashes for guests", Status: types.StatusOpen, Priority: 2,
+		IssueType: types.TypeBug, AcceptanceCriteria: "No crash"}
+	if err := store.CreateIssue(ctx, existing, "test"); err != nil {
+		t.Fatal(err)
+	}
+	intake.dedup = &fakeDedup{duplicateOf: existing.ID}
+
+	if err := intake.Record(Event{Title: "TypeError: user is nil", Count: 3}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := intake.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	fingerprint := Event{Title: "TypeError: user is nil"}
+	if err := fingerprint.normalize(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	issues := groupIssues(t, store, fingerprint.Fingerprint)
+	if len(issues) != 1 || issues[0].ID != existing.ID {
+		t.Fatalf("group issues = %v, want the existing issue linked", issues)
+	}
+	comments, _ := store.GetComments(ctx, existing.ID)
+	if len(comments) != 1 || !strings.Contains(comments[0].Body, "3 occurrence(s)") {
+		t.Errorf("comments = %v, want the report", comments)
+	}
+}
+
+func TestIntakeEnvironments(t *testing.T) {
+	intake, _ := newTestIntake(t, nil, nil)
+	intake.cfg.Environments = []string{"production"}
+
+	for _, env := range []string{"staging", "production", ""} {
+		if err := intake.Record(Event{Title: "boom", Environment: env}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	for _, g := range intake.groups {
+		if g.count != 2 {
+			t.Errorf("count = %d, want staging dropped", g.count)
+		}
+	}
+	if err := intake.Record(Event{}); err == nil {
+		t.Error("expected an error for an event without a title")
+	}
+}
+
+const sentryEventAlertBody = `{
+  "action": "triggered",
+  "data": {
+    "event": {
+      "issue_id": 1170820242,
+      "title": "ZeroDivisionError: division by zero",
+      "level": "error",
+      "culprit": "billing.invoice in total",
+      "environment": "production",
+      "web_url": "https://sentry.io/organizations/acme/issues/1170820242/events/abc/",
+      "timestamp": 1700000000.5,
+      "tags": [["release", "2.3.1"], ["server_name", "web-1"]],
+      "exception": {"values": [{"type": "ZeroDivisionError", "value": "division by zero",
+        "stacktrace": {"frames": [
+          {"filename": "app.py", "function": "handle", "lineno": 10},
+          {"filename": "billing/invoice.py", "function": "total", "lineno": "88", "in_app": true}
+        ]}}]}
+    },
+    "triggered_rule": "Errors in production"
+  }
+}`
+
+func TestHandlerSentry(t *testing.T) {
+	intake, _ := newTestIntake(t, nil, nil)
+	handler := intake.Handler()
+
+	send := func(resource, body, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/errors/sentry", strings.NewReader(body))
+		req.Header.Set(HeaderSentryResource, resource)
+		req.Header.Set(HeaderSentrySignature, signature)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := send("event_alert", sentryEventAlertBody, "bad"); code != http.StatusUnauthorized {
+		t.Errorf("unsigned webhook: status %d, want 401", code)
+	}
+	signature := hexMAC("s3cret", []byte(sentryEventAlertBody))
+	if code := send("event_alert", sentryEventAlertBody, signature); code != http.StatusAccepted {
+		t.Fatalf("event alert: status %d, want 202", code)
+	}
+	g := intake.groups["sentry:1170820242"]
+	if g == nil {
+		t.Fatalf("groups = %v, want the Sentry issue", intake.groups)
+	}
+	e := g.latest
+	if e.Release != "2.3.1" || e.Environment != "production" || !e.Timestamp.Equal(time.Unix(1700000000, 5e8)) {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if !strings.Contains(e.Stacktrace, "* billing/invoice.py:88 in total") || !strings.Contains(e.Stacktrace, "  app.py:10 in handle") {
+		t.Errorf("unexpected stack trace:\n%s", e.Stacktrace)
+	}
+
+	// Issue webhooks report the group's total count; resolutions aren't errors
+	issueBody := `{"action": "unresolved", "data": {"issue": {"id": "1170820242", "title": "ZeroDivisionError", "level": "error", "count": "150"}}}`
+	if code := send("issue", issueBody, hexMAC("s3cret", []byte(issueBody))); code != http.StatusAccepted {
+		t.Fatalf("issue: status %d, want 202", code)
+	}
+	resolved := strings.Replace(issueBody, "unresolved", "resolved", 1)
+	if code := send("issue", resolved, hexMAC("s3cret", []byte(resolved))); code != http.StatusAccepted {
+		t.Fatalf("resolved issue: status %d, want 202", code)
+	}
+	if g.count != 150 {
+		t.Errorf("count = %d, want the total Sentry reported", g.count)
+	}
+}
+
+func TestHandlerWebhook(t *testing.T) {
+	intake, _ := newTestIntake(t, nil, nil)
+	body := []byte(`[{"title": "Timeout calling payments", "level": "warning"}, {"title": "Timeout calling payments", "count": 4}]`)
+
+	req := httptest.NewRequest(http.MethodPost, "/errors/webhook", bytes.NewReader(body))
+	req.Header.Set(HeaderSignature, Sign("s3cret", body))
+	rec := httptest.NewRecorder()
+	intake.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status %d, want 202: %s", rec.Code, rec.Body)
+	}
+	if len(intake.groups) != 1 {
+		t.Fatalf("got %d groups, want 1", len(intake.groups))
+	}
+	for _, g := range intake.groups {
+		if g.count != 5 {
+			t.Errorf("count = %d, want 5", g.count)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/errors/webhook", bytes.NewReader(body))
+	req.Header.Set(HeaderSignature, Sign("wrong", body))
+	rec = httptest.NewRecorder()
+	intake.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status %d, want 401 for a bad signature", rec.Code)
+	}
+}
@@ -0,0 +1,266 @@
+package errortracker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is an error captured by a tracker. Events with the same
+// fingerprint are grouped as one problem and filed as one issue.
+type Event struct {
+	Source      string    `json:"source,omitempty"`      // sentry, or the tracker's name for generic webhooks
+	Fingerprint string    `json:"fingerprint,omitempty"` // Defaults to a hash of title and culprit
+	Title       string    `json:"title"`
+	Message     string    `json:"message,omitempty"`
+	Level       string    `json:"level,omitempty"`   // fatal, error, warning, info or debug
+	Culprit     string    `json:"culprit,omitempty"` // Where the error was raised, e.g. a function or route
+	Stacktrace  string    `json:"stacktrace,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Release     string    `json:"release,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	Count       int       `json:"count,omitempty"` // Occurrences this event stands for; at least 1
+	Total       int       `json:"total,omitempty"` // Occurrences the tracker has counted for the group, if it reports them
+	Timestamp   time.Time `json:"timestamp,omitempty"`
+}
+
+// normalize fills in defaults and checks that the event can be grouped
+func (e *Event) normalize(now time.Time) error {
+	e.Title = strings.TrimSpace(e.Title)
+	if e.Title == "" {
+		e.Title = firstLine(e.Message)
+	}
+	if e.Title == "" {
+		return fmt.Errorf("event has no title or message")
+	}
+	if e.Source == "" {
+		e.Source = "webhook"
+	}
+	if e.Fingerprint == "" {
+		sum := sha256.Sum256([]byte(e.Title + "\x00" + e.Culprit))
+		e.Fingerprint = e.Source + ":" + hex.EncodeToString(sum[:])
+	}
+	e.Level = strings.ToLower(strings.TrimSpace(e.Level))
+	if e.Level == "" {
+		e.Level = "error"
+	}
+	if e.Count < 1 {
+		e.Count = 1
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = now
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// parseGeneric parses the body of a generic webhook: one event, or an array
+// of them
+func parseGeneric(body []byte) ([]Event, error) {
+	var events []Event
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(body, &events); err != nil {
+			return nil, fmt.Errorf("invalid events: %w", err)
+		}
+		return events, nil
+	}
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	return []Event{event}, nil
+}
+
+// Sentry integration webhook resources (the Sentry-Hook-Resource header)
+const (
+	resourceEventAlert = "event_alert"
+	resourceError      = "error"
+	resourceIssue      = "issue"
+)
+
+type sentryPayload struct {
+	Action string `json:"action"`
+	Data   struct {
+		Event *sentryEvent `json:"event"`
+		Error *sentryEvent `json:"error"`
+		Issue *sentryIssue `json:"issue"`
+	} `json:"data"`
+}
+
+type sentryEvent struct {
+	IssueID     flexString `json:"issue_id"`
+	Title       string     `json:"title"`
+	Message     string     `json:"message"`
+	Level       string     `json:"level"`
+	Culprit     string     `json:"culprit"`
+	Environment string     `json:"environment"`
+	Release     string     `json:"release"`
+	WebURL      string     `json:"web_url"`
+	Timestamp   flexString `json:"timestamp"` // Unix seconds, or RFC 3339 in older payloads
+	Tags        [][]string `json:"tags"`
+	Exception   struct {
+		Values []struct {
+			Type       string `json:"type"`
+			Value      string `json:"value"`
+			Stacktrace *struct {
+				Frames []struct {
+					Filename string     `json:"filename"`
+					Function string     `json:"function"`
+					LineNo   flexString `json:"lineno"`
+					InApp    bool       `json:"in_app"`
+				} `json:"frames"`
+			} `json:"stacktrace"`
+		} `json:"values"`
+	} `json:"exception"`
+}
+
+type sentryIssue struct {
+	ID        flexString `json:"id"`
+	Title     string     `json:"title"`
+	Culprit   string     `json:"culprit"`
+	Level     string     `json:"level"`
+	Count     flexString `json:"count"`
+	Permalink string     `json:"permalink"`
+	WebURL    string     `json:"web_url"`
+	LastSeen  time.Time  `json:"lastSeen"`
+	Metadata  struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"metadata"`
+}
+
+// flexString accepts JSON strings and numbers; Sentry sends IDs and counts
+// as either
+type flexString string
+
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = flexString(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*f = flexString(n.String())
+	return nil
+}
+
+// parseSentry parses a Sentry integration webhook for resource. Resources
+// and actions that don't report errors yield no events.
+func parseSentry(resource string, body []byte) ([]Event, error) {
+	var payload sentryPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid Sentry payload: %w", err)
+	}
+	switch resource {
+	case resourceEventAlert:
+		if payload.Data.Event == nil {
+			return nil, fmt.Errorf("Sentry event alert has no event")
+		}
+		return []Event{payload.Data.Event.event()}, nil
+	case resourceError:
+		if payload.Data.Error == nil {
+			return nil, fmt.Errorf("Sentry error webhook has no error")
+		}
+		return []Event{payload.Data.Error.event()}, nil
+	case resourceIssue:
+		// Resolved, assigned and ignored issues aren't new occurrences
+		if payload.Data.Issue == nil || (payload.Action != "created" && payload.Action != "unresolved") {
+			return nil, nil
+		}
+		return []Event{payload.Data.Issue.event()}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (s *sentryEvent) event() Event {
+	e := Event{
+		Source:      "sentry",
+		Title:       s.Title,
+		Message:     s.Message,
+		Level:       s.Level,
+		Culprit:     s.Culprit,
+		Environment: s.Environment,
+		Release:     s.Release,
+		URL:         s.WebURL,
+	}
+	if s.IssueID != "" {
+		e.Fingerprint = "sentry:" + string(s.IssueID)
+	}
+	for _, tag := range s.Tags {
+		if len(tag) != 2 {
+			continue
+		}
+		switch {
+		case tag[0] == "environment" && e.Environment == "":
+			e.Environment = tag[1]
+		case tag[0] == "release" && e.Release == "":
+			e.Release = tag[1]
+		case tag[0] == "level" && e.Level == "":
+			e.Level = tag[1]
+		}
+	}
+	if seconds, err := strconv.ParseFloat(string(s.Timestamp), 64); err == nil && seconds > 0 {
+		e.Timestamp = time.Unix(0, int64(seconds*float64(time.Second))).UTC()
+	} else if t, err := time.Parse(time.RFC3339, string(s.Timestamp)); err == nil {
+		e.Timestamp = t
+	}
+
+	// Most recent call last, like the traces people read in Sentry
+	var trace strings.Builder
+	for _, exc := range s.Exception.Values {
+		fmt.Fprintf(&trace, "%s: %s\n", exc.Type, exc.Value)
+		if exc.Stacktrace == nil {
+			continue
+		}
+		for _, frame := range exc.Stacktrace.Frames {
+			marker := " "
+			if frame.InApp {
+				marker = "*"
+			}
+			fmt.Fprintf(&trace, "  %s %s:%s in %s\n", marker, frame.Filename, frame.LineNo, frame.Function)
+		}
+	}
+	e.Stacktrace = strings.TrimRight(trace.String(), "\n")
+	return e
+}
+
+func (s *sentryIssue) event() Event {
+	e := Event{
+		Source:      "sentry",
+		Fingerprint: "sentry:" + string(s.ID),
+		Title:       s.Title,
+		Message:     strings.TrimSpace(s.Metadata.Type + ": " + s.Metadata.Value),
+		Level:       s.Level,
+		Culprit:     s.Culprit,
+		URL:         s.WebURL,
+		Timestamp:   s.LastSeen,
+	}
+	if e.URL == "" {
+		e.URL = s.Permalink
+	}
+	if e.Message == ":" {
+		e.Message = ""
+	}
+	if total, err := strconv.Atoi(string(s.Count)); err == nil {
+		e.Total = total
+	}
+	return e
+}
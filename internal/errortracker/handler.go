@@ -0,0 +1,138 @@
+package errortracker
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderSentrySignature carries the hex HMAC-SHA256 of a Sentry webhook
+	// body, keyed with the integration's client secret
+	HeaderSentrySignature = "Sentry-Hook-Signature"
+
+	// HeaderSentryResource names what a Sentry webhook is about
+	HeaderSentryResource = "Sentry-Hook-Resource"
+
+	// HeaderSignature carries the signature of a generic webhook body, as
+	// returned by Sign
+	HeaderSignature = "X-VC-Signature"
+
+	// maxBodySize bounds webhook bodies; stack traces can be long
+	maxBodySize = 4 << 20
+)
+
+// Handler serves the webhook endpoints: POST /errors/sentry for Sentry
+// integration webhooks and POST /errors/webhook for other trackers
+func (in *Intake) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /errors/sentry", in.handleSentry)
+	mux.HandleFunc("POST /errors/webhook", in.handleWebhook)
+	return mux
+}
+
+// ListenAndServe serves the webhook endpoints on addr until ctx is done,
+// then shuts down gracefully
+func (in *Intake) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{Handler: in.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to shut down error tracker endpoint: %w", err)
+		}
+		return nil
+	}
+}
+
+// handleSentry records the errors a Sentry webhook reports
+func (in *Intake) handleSentry(w http.ResponseWriter, r *http.Request) {
+	body, ok := in.readSigned(w, r, func(body []byte) bool {
+		return hmac.Equal([]byte(r.Header.Get(HeaderSentrySignature)), []byte(hexMAC(in.cfg.Secret, body)))
+	})
+	if !ok {
+		return
+	}
+	events, err := parseSentry(r.Header.Get(HeaderSentryResource), body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	in.recordAll(w, events)
+}
+
+// handleWebhook records the errors of a generic webhook
+func (in *Intake) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	body, ok := in.readSigned(w, r, func(body []byte) bool {
+		return hmac.Equal([]byte(r.Header.Get(HeaderSignature)), []byte(Sign(in.cfg.Secret, body)))
+	})
+	if !ok {
+		return
+	}
+	events, err := parseGeneric(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	in.recordAll(w, events)
+}
+
+// readSigned reads a request body and checks its signature, replying with
+// an error if either fails
+func (in *Intake) readSigned(w http.ResponseWriter, r *http.Request, valid func([]byte) bool) ([]byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return nil, false
+	}
+	if !valid(body) {
+		slog.Warn("error tracker: refused webhook", "path", r.URL.Path, "error", "signature mismatch")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return nil, false
+	}
+	return body, true
+}
+
+func (in *Intake) recordAll(w http.ResponseWriter, events []Event) {
+	var problems []string
+	for _, event := range events {
+		if err := in.Record(event); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		http.Error(w, strings.Join(problems, "; "), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Sign returns the X-VC-Signature header value of a generic webhook body
+func Sign(secret string, body []byte) string {
+	return "sha256=" + hexMAC(secret, body)
+}
+
+func hexMAC(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -273,3 +273,212 @@ func (e *AgentEvent) GetEpicCleanupCompletedData() (*EpicCleanupCompletedData, e
 	}
 	return &data, nil
 }
+
+// SetPullRequestData sets the Data field with PullRequestData in a type-safe way.
+func (e *AgentEvent) SetPullRequestData(data PullRequestData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert PullRequestData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetPullRequestData retrieves PullRequestData from the Data field.
+func (e *AgentEvent) GetPullRequestData() (*PullRequestData, error) {
+	var data PullRequestData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse PullRequestData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetPushBlockedData sets the Data field with PushBlockedData in a type-safe way.
+func (e *AgentEvent) SetPushBlockedData(data PushBlockedData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert PushBlockedData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetPushBlockedData retrieves PushBlockedData from the Data field.
+func (e *AgentEvent) GetPushBlockedData() (*PushBlockedData, error) {
+	var data PushBlockedData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse PushBlockedData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetPushRetryData sets the Data field with PushRetryData in a type-safe way.
+func (e *AgentEvent) SetPushRetryData(data PushRetryData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert PushRetryData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetPushRetryData retrieves PushRetryData from the Data field.
+func (e *AgentEvent) GetPushRetryData() (*PushRetryData, error) {
+	var data PushRetryData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse PushRetryData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetScopeViolationData sets the Data field with ScopeViolationData in a type-safe way.
+func (e *AgentEvent) SetScopeViolationData(data ScopeViolationData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert ScopeViolationData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetScopeViolationData retrieves ScopeViolationData from the Data field.
+func (e *AgentEvent) GetScopeViolationData() (*ScopeViolationData, error) {
+	var data ScopeViolationData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse ScopeViolationData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetPatchProposalData sets the Data field with PatchProposalData in a type-safe way.
+func (e *AgentEvent) SetPatchProposalData(data PatchProposalData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert PatchProposalData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetPatchProposalData retrieves PatchProposalData from the Data field.
+func (e *AgentEvent) GetPatchProposalData() (*PatchProposalData, error) {
+	var data PatchProposalData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse PatchProposalData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetReviewerSuggestionData sets the Data field with ReviewerSuggestionData in a type-safe way.
+func (e *AgentEvent) SetReviewerSuggestionData(data ReviewerSuggestionData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert ReviewerSuggestionData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetReviewerSuggestionData retrieves ReviewerSuggestionData from the Data field.
+func (e *AgentEvent) GetReviewerSuggestionData() (*ReviewerSuggestionData, error) {
+	var data ReviewerSuggestionData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse ReviewerSuggestionData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetLargeFileChangeData sets the Data field with LargeFileChangeData in a type-safe way.
+func (e *AgentEvent) SetLargeFileChangeData(data LargeFileChangeData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert LargeFileChangeData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetLargeFileChangeData retrieves LargeFileChangeData from the Data field.
+func (e *AgentEvent) GetLargeFileChangeData() (*LargeFileChangeData, error) {
+	var data LargeFileChangeData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse LargeFileChangeData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetSubmoduleChangeData sets the Data field with SubmoduleChangeData in a type-safe way.
+func (e *AgentEvent) SetSubmoduleChangeData(data SubmoduleChangeData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert SubmoduleChangeData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetSubmoduleChangeData retrieves SubmoduleChangeData from the Data field.
+func (e *AgentEvent) GetSubmoduleChangeData() (*SubmoduleChangeData, error) {
+	var data SubmoduleChangeData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse SubmoduleChangeData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetRollbackData sets the Data field with RollbackData in a type-safe way.
+func (e *AgentEvent) SetRollbackData(data RollbackData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert RollbackData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetRollbackData retrieves RollbackData from the Data field.
+func (e *AgentEvent) GetRollbackData() (*RollbackData, error) {
+	var data RollbackData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse RollbackData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetBackportData sets the Data field with BackportData in a type-safe way.
+func (e *AgentEvent) SetBackportData(data BackportData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert BackportData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetBackportData retrieves BackportData from the Data field.
+func (e *AgentEvent) GetBackportData() (*BackportData, error) {
+	var data BackportData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse BackportData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetReleaseData sets the Data field with ReleaseData in a type-safe way.
+func (e *AgentEvent) SetReleaseData(data ReleaseData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert ReleaseData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetReleaseData retrieves ReleaseData from the Data field.
+func (e *AgentEvent) GetReleaseData() (*ReleaseData, error) {
+	var data ReleaseData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse ReleaseData: %w", err)
+	}
+	return &data, nil
+}
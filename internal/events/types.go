@@ -61,6 +61,10 @@ const (
 	EventTypeQualityGatesDeferred EventType = "quality_gates_deferred"
 	// EventTypeQualityGatesRollback indicates changes were rolled back after quality gate failure (vc-16fe)
 	EventTypeQualityGatesRollback EventType = "quality_gates_rollback"
+	// EventTypeMutationTestCompleted indicates an optional mutation testing run completed
+	EventTypeMutationTestCompleted EventType = "mutation_test_completed"
+	// EventTypeQualityGateOverridden indicates a gate was skipped via an approved gate-override label
+	EventTypeQualityGateOverridden EventType = "quality_gate_overridden"
 
 	// Deduplication events (vc-151)
 	// EventTypeDeduplicationBatchStarted indicates batch deduplication processing started
@@ -179,6 +183,72 @@ const (
 	// Quota monitoring events (vc-7e21)
 	// EventTypeQuotaAlert indicates predictive quota alert (YELLOW/ORANGE/RED)
 	EventTypeQuotaAlert EventType = "quota_alert"
+
+	// Pull request events (GitHub pull requests and GitLab merge requests)
+	// EventTypePullRequestCreated indicates a pull request was opened for an issue's branch
+	EventTypePullRequestCreated EventType = "pull_request_created"
+	// EventTypePullRequestStatus indicates a tracked pull request changed status (merged, closed, reopened...)
+	EventTypePullRequestStatus EventType = "pull_request_status"
+	// EventTypePushBlocked indicates pre-push safety checks stopped a push
+	// (oversized or binary files, secrets, or a force push)
+	EventTypePushBlocked EventType = "push_blocked"
+	// EventTypePushRetried indicates a push was rejected because the remote
+	// branch advanced, and was retried after rebasing onto it
+	EventTypePushRetried EventType = "push_retried"
+	// EventTypeScopeViolation indicates an agent changed files outside its
+	// issue's path scope
+	EventTypeScopeViolation EventType = "scope_violation"
+	// EventTypePatchProposal indicates a patch an agent proposed in
+	// patch-proposal mode was reviewed, and applied or rejected
+	EventTypePatchProposal EventType = "patch_proposal"
+	// EventTypeReviewersSuggested indicates human reviewers were suggested
+	// for an issue's changes from the recent authorship of the files touched
+	EventTypeReviewersSuggested EventType = "reviewers_suggested"
+	// EventTypeLargeFileChanged indicates an agent changed Git LFS-tracked
+	// files or large binaries
+	EventTypeLargeFileChanged EventType = "large_file_changed"
+	// EventTypeSubmoduleChanged indicates an agent moved submodule pointers
+	// or changed files inside submodules
+	EventTypeSubmoduleChanged EventType = "submodule_changed"
+	// EventTypeAgentAnomaly indicates an agent was stopped for suspicious
+	// behavior (mass deletions, edits far outside its scope, repeated
+	// identical tool calls, touching protected paths) and its issue escalated
+	EventTypeAgentAnomaly EventType = "agent_anomaly"
+
+	// Crash recovery events
+	// EventTypeCrashRecovery indicates startup recovery reopened an issue a
+	// crashed executor had claimed, or cleaned up a plan whose approval it
+	// interrupted
+	EventTypeCrashRecovery EventType = "crash_recovery"
+	// EventTypeExecutorPanic indicates a panic in an executor worker was
+	// recovered, its execution failed and a bug filed against VC
+	EventTypeExecutorPanic EventType = "executor_panic"
+
+	// AI availability events
+	// EventTypeWaitingOnAI indicates an issue was deferred with the
+	// waiting-on-ai label because the AI provider's circuit breaker was open,
+	// or released for retry once the provider recovered
+	EventTypeWaitingOnAI EventType = "waiting_on_ai"
+
+	// Resource events
+	// EventTypeResourceCheck indicates the executor stopped claiming work
+	// because free disk space, temp space or file descriptors ran low, or
+	// resumed once they recovered
+	EventTypeResourceCheck EventType = "resource_check"
+
+	// Rollback events
+	// EventTypeCommitRolledBack indicates an execution's commit was reverted and a follow-up issue filed
+	EventTypeCommitRolledBack EventType = "commit_rolled_back"
+
+	// Backport events
+	// EventTypeBackport indicates an issue's commits were cherry-picked onto a
+	// release branch, or a follow-up issue was filed because they couldn't be
+	EventTypeBackport EventType = "backport"
+
+	// Release events
+	// EventTypeRelease indicates a closed release mission was tagged, and
+	// possibly published as a release on the hosting provider
+	EventTypeRelease EventType = "release"
 )
 
 // EventSeverity represents the severity level of an event.
@@ -240,6 +310,160 @@ type TestRunData struct {
 	Output string `json:"output"`
 }
 
+// PullRequestData contains structured data for pull request events.
+type PullRequestData struct {
+	// Provider is the git hosting provider: "github" or "gitlab" (empty means github)
+	Provider string `json:"provider,omitempty"`
+	// Number is the pull request number (the iid of a GitLab merge request)
+	Number int `json:"number"`
+	// URL is the pull request's web URL
+	URL string `json:"url"`
+	// Repo is the repository as owner/name (a full project path on GitLab)
+	Repo string `json:"repo"`
+	// Branch is the branch the pull request merges
+	Branch string `json:"branch"`
+	// BaseBranch is the branch it merges into
+	BaseBranch string `json:"base_branch"`
+	// Status is "open", "draft", "merged" or "closed"
+	Status string `json:"status"`
+	// PreviousStatus is the status before this change (status events only)
+	PreviousStatus string `json:"previous_status,omitempty"`
+}
+
+// PushBlockedData contains structured data for push blocked events.
+type PushBlockedData struct {
+	// Remote is the remote the push was for
+	Remote string `json:"remote"`
+	// Branch is the branch that was not pushed
+	Branch string `json:"branch"`
+	// Violations describes each failed check, e.g. "secret in config.go (1a2b3c4d): GitHub token"
+	Violations []string `json:"violations"`
+}
+
+// PushRetryData contains structured data for push retried events.
+type PushRetryData struct {
+	// Remote is the remote the push was for
+	Remote string `json:"remote"`
+	// Branch is the branch that was pushed
+	Branch string `json:"branch"`
+	// Attempts is the number of pushes made
+	Attempts int `json:"attempts"`
+	// Outcome is pushed, conflict, gates_failed or failed
+	Outcome string `json:"outcome"`
+	// Conflicts lists the files that conflicted when rebasing
+	Conflicts []string `json:"conflicts,omitempty"`
+	// FailedGates lists the gates that failed on the rebased branch
+	FailedGates []string `json:"failed_gates,omitempty"`
+	// Reason explains a push that was given up on
+	Reason string `json:"reason,omitempty"`
+}
+
+// ScopeViolationData contains structured data for scope violation events.
+type ScopeViolationData struct {
+	// Scope is the issue's path scope, e.g. ["services/payments/**"]
+	Scope []string `json:"scope"`
+	// Files are the changed files outside the scope
+	Files []string `json:"files"`
+	// Reverted is true if the changes to Files were discarded
+	Reverted bool `json:"reverted"`
+}
+
+// PatchProposalData contains structured data for patch proposal events.
+type PatchProposalData struct {
+	// Files are the files the patch changes
+	Files []string `json:"files"`
+	// Additions and Deletions count the patch's added and removed lines
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	// Applied is true if the patch was applied to the working branch
+	Applied bool `json:"applied"`
+	// Reason explains a rejection, or the reviewer's reasoning
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReviewerSuggestionData contains structured data for reviewer suggestion events.
+type ReviewerSuggestionData struct {
+	// Trigger is what prompted the suggestion: pull_request or escalation
+	Trigger string `json:"trigger"`
+	// Files is the number of touched files authorship was computed for
+	Files int `json:"files"`
+	// Reviewers are the suggested reviewers, best first, as "Name <email>"
+	Reviewers []string `json:"reviewers"`
+	// Owners are the code owners of the touched files by CODEOWNERS, most
+	// files owned first
+	Owners []string `json:"owners,omitempty"`
+	// Assigned is the reviewer made the issue's assignee, if any
+	Assigned string `json:"assigned,omitempty"`
+}
+
+// LargeFileChangeData contains structured data for large file change events.
+type LargeFileChangeData struct {
+	// LFS are the changed files tracked by Git LFS
+	LFS []string `json:"lfs,omitempty"`
+	// Binaries are the changed binary files at or over the size limit
+	Binaries []string `json:"binaries,omitempty"`
+}
+
+// SubmoduleChangeData contains structured data for submodule change events.
+type SubmoduleChangeData struct {
+	// Pointers describes each moved submodule pointer, e.g. "libs/core (1a2b3c4d -> 5e6f7a8b)"
+	Pointers []string `json:"pointers,omitempty"`
+	// Dirty are the submodules with uncommitted changes inside them
+	Dirty []string `json:"dirty,omitempty"`
+	// Reverted is true if the moved pointers were reset to the recorded commits
+	Reverted bool `json:"reverted"`
+}
+
+// RollbackData contains structured data for commit rollback events.
+type RollbackData struct {
+	// ExecutionID is the execution whose commit was reverted
+	ExecutionID int64 `json:"execution_id"`
+	// Commit is the reverted commit
+	Commit string `json:"commit"`
+	// RevertCommit is the commit that reverted it
+	RevertCommit string `json:"revert_commit"`
+	// FollowUpIssueID is the issue filed to redo the work
+	FollowUpIssueID string `json:"follow_up_issue_id,omitempty"`
+	// Reason is why the commit was rolled back
+	Reason string `json:"reason"`
+}
+
+// BackportData contains structured data for backport events.
+type BackportData struct {
+	// TargetBranch is the release branch the commits were picked onto
+	TargetBranch string `json:"target_branch"`
+	// Commits are the landed commits that were cherry-picked, oldest first
+	Commits []string `json:"commits"`
+	// Status is the outcome: opened, pushed, conflict, gates_failed or failed
+	Status string `json:"status"`
+	// Branch is the branch holding the cherry-picked commits
+	Branch string `json:"branch,omitempty"`
+	// PRURL is the pull request opened against the release branch
+	PRURL string `json:"pr_url,omitempty"`
+	// Conflicts lists the files that conflicted
+	Conflicts []string `json:"conflicts,omitempty"`
+	// FollowUpIssueID is the issue filed to finish the backport by hand
+	FollowUpIssueID string `json:"follow_up_issue_id,omitempty"`
+	// Reason explains a failed backport
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReleaseData contains structured data for release events.
+type ReleaseData struct {
+	// Tag is the annotated tag created for the release
+	Tag string `json:"tag"`
+	// Commit is the commit the tag points to
+	Commit string `json:"commit,omitempty"`
+	// Issues are the closed issues the release notes were generated from
+	Issues []string `json:"issues"`
+	// Status is the outcome: published, tagged or failed
+	Status string `json:"status"`
+	// URL is the release published on the hosting provider
+	URL string `json:"url,omitempty"`
+	// Reason explains a failed release
+	Reason string `json:"reason,omitempty"`
+}
+
 // GitOperationData contains structured data for git operation events.
 type GitOperationData struct {
 	// Command is the git command that was executed
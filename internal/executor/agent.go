@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"strings"
@@ -16,9 +17,12 @@ import (
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/config"
 	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/logging"
 	"github.com/steveyegge/vc/internal/sandbox"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/tracing"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -30,6 +34,24 @@ const (
 	AgentTypeClaudeCode  AgentType = "claude-code" // Anthropic Claude Code
 )
 
+// IsValid reports whether the agent type is one VC can spawn
+func (t AgentType) IsValid() bool {
+	return t == AgentTypeAmp || t == AgentTypeClaudeCode
+}
+
+// AgentTypeFromEnv returns the agent set by VC_AGENT_PROVIDER, Claude Code
+// by default
+func AgentTypeFromEnv() (AgentType, error) {
+	t := AgentType(os.Getenv("VC_AGENT_PROVIDER"))
+	if t == "" {
+		return AgentTypeClaudeCode, nil
+	}
+	if !t.IsValid() {
+		return "", fmt.Errorf("invalid VC_AGENT_PROVIDER %q (want claude-code or amp)", t)
+	}
+	return t, nil
+}
+
 // AgentConfig holds configuration for spawning an agent
 type AgentConfig struct {
 	Type        AgentType
@@ -41,12 +63,18 @@ type AgentConfig struct {
 	Store      storage.Storage
 	ExecutorID string
 	AgentID    string
+	// Execution the agent runs for (optional - if 0, output isn't stored for 'vc tail')
+	ExecutionID int64
 	// Watchdog monitoring (optional - if nil, events won't be reported to watchdog)
 	Monitor    interface{ RecordEvent(eventType string) }
 	// Sandbox context (optional - if nil, agent runs in main workspace)
 	Sandbox    *sandbox.Sandbox
 	// Interrupt manager for graceful pause/resume (optional - if nil, no interrupt checks)
 	InterruptMgr interface{ IsInterruptRequested() bool }
+	// Behavioral anomaly detection (optional - if disabled, tool calls aren't checked)
+	Anomalies config.AgentAnomalyConfig
+	// Paths the issue's changes must stay within (empty = anywhere)
+	PathScope types.PathScope
 }
 
 const (
@@ -173,12 +201,14 @@ type AgentMessage struct {
 	// System event fields
 	Cwd    string   `json:"cwd,omitempty"`   // Current working directory (system init events)
 	Tools  []string `json:"tools,omitempty"` // Available tools (system init events)
+	Model  string   `json:"model,omitempty"` // Model the agent runs (system init events)
 
 	// Result event fields
-	DurationMs int    `json:"duration_ms,omitempty"` // Execution duration (result events)
-	IsError    bool   `json:"is_error,omitempty"`    // Whether execution failed (result events)
-	Result     string `json:"result,omitempty"`      // Final result message (result events)
-	NumTurns   int    `json:"num_turns,omitempty"`   // Number of conversation turns (result events)
+	DurationMs   int     `json:"duration_ms,omitempty"`    // Execution duration (result events)
+	IsError      bool    `json:"is_error,omitempty"`       // Whether execution failed (result events)
+	Result       string  `json:"result,omitempty"`         // Final result message (result events)
+	NumTurns     int     `json:"num_turns,omitempty"`      // Number of conversation turns (result events)
+	TotalCostUSD float64 `json:"total_cost_usd,omitempty"` // Cost of the whole run (Claude Code result events)
 
 	// Assistant message wrapper (contains nested tool use)
 	Message *AssistantMessage `json:"message,omitempty"` // Nested message structure (assistant events)
@@ -196,6 +226,7 @@ type AssistantMessage struct {
 	Content    []MessageContent         `json:"content"`               // Array of text and tool_use items
 	StopReason string                   `json:"stop_reason,omitempty"` // Why the agent stopped: "tool_use", "end_turn", etc.
 	Usage      map[string]interface{}   `json:"usage,omitempty"`       // Token usage statistics
+	Model      string                   `json:"model,omitempty"`       // Model that produced the message
 }
 
 // MessageContent represents an item in the assistant message content array.
@@ -218,8 +249,9 @@ type Agent struct {
 	ctx       context.Context // Context for storage operations
 
 	mu     sync.Mutex
-	result AgentResult
-	parser *events.OutputParser // Parser for extracting events from output
+	result   AgentResult
+	parser   *events.OutputParser // Parser for extracting events from output
+	recorder *outputRecorder      // Stores output for 'vc tail'; nil if not storing
 
 	// Circuit breaker state for detecting infinite loops (vc-117, vc-34cz, vc-139)
 	totalReadCount  int            // Total number of Read tool invocations
@@ -238,6 +270,11 @@ type Agent struct {
 
 	// Interrupt state for graceful pause/resume (vc-d25s)
 	interruptDetected atomic.Bool // Whether an interrupt was detected (lock-free for monitoring goroutine)
+
+	// Behavioral anomaly detection
+	anomalies       *anomalyDetector // Watches tool calls; nil if disabled
+	anomalyDetected atomic.Bool      // Whether suspicious behavior was detected (lock-free for monitoring goroutine)
+	anomaly         *AnomalyError    // The first anomaly detected
 }
 
 // SpawnAgent starts a coding agent process with a pre-built prompt
@@ -307,10 +344,18 @@ func SpawnAgent(ctx context.Context, cfg AgentConfig, prompt string) (*Agent, er
 		// loopDetected is atomic.Bool and initializes to false automatically
 	}
 
+	if cfg.Anomalies.Enabled {
+		agent.anomalies = newAnomalyDetector(cfg.Anomalies, cfg.WorkingDir, cfg.PathScope)
+	}
+
 	// Initialize OutputParser if event storage is enabled
 	if cfg.Store != nil && cfg.Issue != nil {
 		agent.parser = events.NewOutputParser(cfg.Issue.ID, cfg.ExecutorID, cfg.AgentID)
 	}
+	// Store output as it's written if the agent runs for a recorded execution
+	if cfg.Store != nil && cfg.Issue != nil && cfg.ExecutionID != 0 {
+		agent.recorder = newOutputRecorder(ctx, cfg.Store, cfg.ExecutionID, cfg.Issue.ID)
+	}
 
 	// Start goroutines to capture output
 	go agent.captureOutput()
@@ -318,6 +363,20 @@ func SpawnAgent(ctx context.Context, cfg AgentConfig, prompt string) (*Agent, er
 	return agent, nil
 }
 
+// logger returns a logger whose records name the agent's provider and the
+// fields of the execution it runs for, or just its issue outside one
+func (a *Agent) logger() *slog.Logger {
+	args := []any{logging.KeyProvider, string(a.config.Type), "agent_id", a.config.AgentID}
+	if attrs := logging.Attrs(a.ctx); len(attrs) > 0 {
+		for _, attr := range attrs {
+			args = append(args, attr)
+		}
+	} else if a.config.Issue != nil {
+		args = append(args, logging.KeyIssueID, a.config.Issue.ID)
+	}
+	return slog.With(args...)
+}
+
 // Wait waits for the agent to complete and returns the result
 func (a *Agent) Wait(ctx context.Context) (*AgentResult, error) {
 	// Check if parent context is already done
@@ -354,8 +413,8 @@ func (a *Agent) Wait(ctx context.Context) (*AgentResult, error) {
 					return
 				}
 
-				// Check if circuit breaker was triggered (lock-free read via atomic)
-				if a.loopDetected.Load() {
+				// Check if circuit breaker or anomaly detection was triggered (lock-free read via atomic)
+				if a.loopDetected.Load() || a.anomalyDetected.Load() {
 					// Kill the agent
 					if err := a.Kill(); err != nil {
 						fmt.Fprintf(os.Stderr, "warning: failed to kill agent after circuit breaker: %v\n", err)
@@ -398,6 +457,14 @@ func (a *Agent) Wait(ctx context.Context) (*AgentResult, error) {
 			return nil, fmt.Errorf("agent interrupted by user request")
 		}
 
+		// Check if it was stopped for suspicious behavior
+		if a.anomalyDetected.Load() {
+			a.mu.Lock()
+			anomaly := a.anomaly
+			a.mu.Unlock()
+			return nil, anomaly
+		}
+
 		// Check if it was killed by circuit breaker (lock-free atomic read)
 		if a.loopDetected.Load() {
 			// Read loopReason with mutex protection (it's a string)
@@ -461,6 +528,9 @@ func (a *Agent) captureOutput() {
 			// Print immediately for real-time user feedback
 			// This happens outside the mutex and doesn't affect batching
 			fmt.Println(line)
+			if a.recorder != nil {
+				a.recorder.Record(types.OutputStdout, line)
+			}
 
 			// Parse JSON if streaming JSON mode (outside mutex)
 			var msg AgentMessage
@@ -561,6 +631,9 @@ func (a *Agent) captureOutput() {
 			// Print immediately for real-time user feedback
 			// This happens outside the mutex and doesn't affect batching
 			fmt.Fprintln(os.Stderr, line)
+			if a.recorder != nil {
+				a.recorder.Record(types.OutputStderr, line)
+			}
 
 			// Add to batch
 			batch = append(batch, line)
@@ -616,6 +689,9 @@ func (a *Agent) captureOutput() {
 	}()
 
 	wg.Wait()
+	if a.recorder != nil {
+		a.recorder.Close()
+	}
 }
 
 // parseAndStoreEvents parses a line for events and stores them immediately
@@ -669,22 +745,17 @@ func (a *Agent) parseAndStoreEvents(line string) {
 // assistant messages at message.content[]. This function extracts all tool_use items
 // from the nested content array and converts them to AgentEvents.
 //
-// Set VC_DEBUG_EVENTS=1 to enable debug logging of JSON event parsing.
+// Parsing details are logged at debug level (VC_LOG_LEVEL=debug, or VC_DEBUG_EVENTS=1).
 func (a *Agent) convertJSONToEvent(msg AgentMessage) *events.AgentEvent {
 	// Only process "assistant" messages - these contain tool use in nested content array
 	if msg.Type != "assistant" {
-		// Debug log non-assistant events if debugging is enabled
-		if os.Getenv("VC_DEBUG_EVENTS") != "" {
-			fmt.Fprintf(os.Stderr, "[DEBUG] Skipping non-assistant event: type=%s subtype=%s\n", msg.Type, msg.Subtype)
-		}
+		a.logger().Debug("Skipping non-assistant event", "type", msg.Type, "subtype", msg.Subtype)
 		return nil
 	}
 
 	// Check if message wrapper exists
 	if msg.Message == nil {
-		if os.Getenv("VC_DEBUG_EVENTS") != "" {
-			fmt.Fprintf(os.Stderr, "[DEBUG] Assistant message has no nested message field\n")
-		}
+		a.logger().Debug("Assistant message has no nested message field")
 		return nil
 	}
 
@@ -701,16 +772,14 @@ func (a *Agent) convertJSONToEvent(msg AgentMessage) *events.AgentEvent {
 
 		// Skip internal tools that aren't code operations (vc-107)
 		if shouldSkipTool(toolName) {
-			if os.Getenv("VC_DEBUG_EVENTS") != "" {
-				fmt.Fprintf(os.Stderr, "[DEBUG] Skipping internal tool: name=%s\n", content.Name)
-			}
+			a.logger().Debug("Skipping internal tool", "tool", content.Name)
 			continue
 		}
 
 		// Circuit breaker: Track all tool usage to detect infinite loops (vc-117, vc-34cz)
 		// Check general tool call limits first (applies to ALL tools)
 		if err := a.checkToolCallLimit(toolName); err != nil {
-			fmt.Fprintf(os.Stderr, "\n!!! CIRCUIT BREAKER TRIGGERED !!!\n%v\n", err)
+			a.logger().Error("Agent circuit breaker triggered", "tool", toolName, "error", err)
 			return nil
 		}
 
@@ -731,7 +800,7 @@ func (a *Agent) convertJSONToEvent(msg AgentMessage) *events.AgentEvent {
 			// Note: Do NOT kill here while holding mutex - just set flag
 			if err := a.checkCircuitBreaker(filePath); err != nil {
 				// Circuit breaker triggered - log it but don't kill yet
-				fmt.Fprintf(os.Stderr, "\n!!! CIRCUIT BREAKER TRIGGERED !!!\n%v\n", err)
+				a.logger().Error("Agent circuit breaker triggered", "tool", toolName, "error", err)
 				// Don't return an event - the agent will be terminated by Wait()
 				return nil
 			}
@@ -747,7 +816,7 @@ func (a *Agent) convertJSONToEvent(msg AgentMessage) *events.AgentEvent {
 			}
 
 			if err := a.checkGrepCircuitBreaker(grepPattern); err != nil {
-				fmt.Fprintf(os.Stderr, "\n!!! CIRCUIT BREAKER TRIGGERED !!!\n%v\n", err)
+				a.logger().Error("Agent circuit breaker triggered", "tool", toolName, "error", err)
 				return nil
 			}
 		}
@@ -762,7 +831,7 @@ func (a *Agent) convertJSONToEvent(msg AgentMessage) *events.AgentEvent {
 			}
 
 			if err := a.checkGlobCircuitBreaker(globPattern); err != nil {
-				fmt.Fprintf(os.Stderr, "\n!!! CIRCUIT BREAKER TRIGGERED !!!\n%v\n", err)
+				a.logger().Error("Agent circuit breaker triggered", "tool", toolName, "error", err)
 				return nil
 			}
 		}
@@ -790,6 +859,10 @@ func (a *Agent) convertJSONToEvent(msg AgentMessage) *events.AgentEvent {
 			}
 		}
 
+		// Stop agents that behave suspiciously; the event is still returned
+		// so the offending call shows up in the activity feed
+		a.checkAnomaly(toolName, content.Input, targetFile, command)
+
 		// Build human-readable message for the event (vc-9lvs)
 		// Use title case for tool name (capitalize first letter)
 		toolNameDisplay := toolName
@@ -850,11 +923,7 @@ func (a *Agent) convertJSONToEvent(msg AgentMessage) *events.AgentEvent {
 			continue
 		}
 
-		// Debug log successful event conversion
-		if os.Getenv("VC_DEBUG_EVENTS") != "" {
-			fmt.Fprintf(os.Stderr, "[DEBUG] Parsed tool_use event: tool=%s file=%s command=%s pattern=%s\n",
-				toolName, targetFile, command, pattern)
-		}
+		a.logger().Debug("Parsed tool_use event", "tool", toolName, "file", targetFile, "command", command, "pattern", pattern)
 
 		// Record event with watchdog monitor for anomaly detection (vc-118)
 		if a.config.Monitor != nil {
@@ -866,9 +935,7 @@ func (a *Agent) convertJSONToEvent(msg AgentMessage) *events.AgentEvent {
 	}
 
 	// No tool_use found in content array
-	if os.Getenv("VC_DEBUG_EVENTS") != "" {
-		fmt.Fprintf(os.Stderr, "[DEBUG] Assistant message has no tool_use in content array\n")
-	}
+	a.logger().Debug("Assistant message has no tool_use in content array")
 	return nil
 }
 
@@ -1069,6 +1136,24 @@ func (a *Agent) checkGlobCircuitBreaker(pattern string) error {
 	return nil
 }
 
+// checkAnomaly runs a tool call past the anomaly detectors, flagging the
+// agent to be stopped by Wait's monitor on the first anomaly
+func (a *Agent) checkAnomaly(toolName string, input map[string]interface{}, targetFile, command string) {
+	if a.anomalies == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.anomaly != nil {
+		return
+	}
+	if anomaly := a.anomalies.observe(toolName, input, targetFile, command); anomaly != nil {
+		a.anomaly = anomaly
+		a.anomalyDetected.Store(true)
+		a.logger().Error("Agent behavioral anomaly detected", "kind", anomaly.Kind, "detail", anomaly.Detail)
+	}
+}
+
 // checkToolCallLimit checks if a specific tool is being called too many times (vc-34cz)
 // Uses both AI-based loop detection (ZFC-compliant) and hard limits as backstop
 func (a *Agent) checkToolCallLimit(toolName string) error {
@@ -1180,7 +1265,7 @@ Only say stuck=true if you're confident (>0.8) this is a loop.`, summary)
 	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
+	client := anthropic.NewClient(option.WithAPIKey(apiKey), option.WithMiddleware(tracing.AnthropicMiddleware()))
 
 	resp, err := client.Messages.New(checkCtx, anthropic.MessageNewParams{
 		Model:     anthropic.Model("claude-3-5-haiku-20241022"), // Haiku for speed/cost
@@ -1192,9 +1277,7 @@ Only say stuck=true if you're confident (>0.8) this is a loop.`, summary)
 
 	if err != nil {
 		// AI call failed - don't halt, just log and continue
-		if os.Getenv("VC_DEBUG_EVENTS") != "" {
-			fmt.Fprintf(os.Stderr, "[DEBUG] AI loop detection failed: %v\n", err)
-		}
+		a.logger().Debug("AI loop detection failed", logging.KeyOperation, "loop_detection", "error", err)
 		return false, ""
 	}
 
@@ -0,0 +1,332 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Kinds of suspicious agent behavior
+const (
+	AnomalyMassDeletion  = "mass_deletion"
+	AnomalyOutOfScope    = "out_of_scope_edit"
+	AnomalyRepeatedCall  = "repeated_tool_call"
+	AnomalyProtectedPath = "protected_path"
+)
+
+// AnomalyError is returned by Agent.Wait when the agent was stopped because
+// its tool calls looked suspicious
+type AnomalyError struct {
+	Kind   string // One of the Anomaly* kinds
+	Detail string // What the agent did
+}
+
+func (e *AnomalyError) Error() string {
+	return fmt.Sprintf("agent stopped for suspicious behavior (%s): %s", e.Kind, e.Detail)
+}
+
+// editTools are the tools that write the file they target
+var editTools = map[string]bool{
+	"edit":         true,
+	"multiedit":    true,
+	"write":        true,
+	"notebookedit": true,
+}
+
+// wholeTreeTargets are rm targets that delete the entire working tree (or
+// more) whatever the working directory
+var wholeTreeTargets = map[string]bool{
+	".": true, "./": true, "*": true, "./*": true, "..": true, "../": true,
+	"/": true, "/*": true, "~": true, "~/": true, "$HOME": true,
+}
+
+// anomalyDetector watches one execution's tool calls for suspicious
+// behavior. It isn't safe for concurrent use; the agent serializes calls.
+type anomalyDetector struct {
+	cfg        config.AgentAnomalyConfig
+	workingDir string          // Absolute working tree the agent runs in
+	scope      types.PathScope // The issue's path scope (empty = anywhere)
+
+	deletions  int             // Files deleted so far
+	outOfScope map[string]bool // Files edited outside the scope
+	calls      map[string]int  // Identical calls since the last edit, by tool and input
+}
+
+// newAnomalyDetector returns a detector for an agent running in workingDir
+// on an issue confined to scope
+func newAnomalyDetector(cfg config.AgentAnomalyConfig, workingDir string, scope types.PathScope) *anomalyDetector {
+	if abs, err := filepath.Abs(workingDir); err == nil {
+		workingDir = abs
+	}
+	return &anomalyDetector{
+		cfg:        cfg,
+		workingDir: workingDir,
+		scope:      scope,
+		outOfScope: make(map[string]bool),
+		calls:      make(map[string]int),
+	}
+}
+
+// observe records a tool call and returns the anomaly it amounts to, or nil
+func (d *anomalyDetector) observe(toolName string, input map[string]interface{}, targetFile, command string) *AnomalyError {
+	if anomaly := d.checkRepeated(toolName, input); anomaly != nil {
+		return anomaly
+	}
+	if editTools[toolName] {
+		if targetFile == "" {
+			if path, ok := input["notebook_path"].(string); ok {
+				targetFile = path
+			}
+		}
+		if targetFile != "" {
+			return d.checkEdit(targetFile)
+		}
+	}
+	if toolName == "bash" && command != "" {
+		return d.checkCommand(command)
+	}
+	return nil
+}
+
+// checkRepeated counts identical calls. An edit in between means the agent
+// is making progress, so it starts the count over.
+func (d *anomalyDetector) checkRepeated(toolName string, input map[string]interface{}) *AnomalyError {
+	if editTools[toolName] {
+		clear(d.calls)
+	}
+	encoded, err := json.Marshal(input) // Map keys are sorted, so equal inputs encode equally
+	if err != nil {
+		return nil
+	}
+	key := toolName + " " + string(encoded)
+	d.calls[key]++
+	if d.calls[key] > d.cfg.MaxRepeatedCalls {
+		return &AnomalyError{Kind: AnomalyRepeatedCall,
+			Detail: fmt.Sprintf("%s called %d times with the same input without editing anything: %s", toolName, d.calls[key], truncate(string(encoded), 200))}
+	}
+	return nil
+}
+
+// checkEdit checks a file the agent writes
+func (d *anomalyDetector) checkEdit(path string) *AnomalyError {
+	rel, inside := d.relative(path)
+	if !inside {
+		if d.isTemp(path) {
+			return nil
+		}
+		return &AnomalyError{Kind: AnomalyOutOfScope, Detail: fmt.Sprintf("edited %s, outside the working tree", path)}
+	}
+	if protected := d.protected(rel); protected != "" {
+		return &AnomalyError{Kind: AnomalyProtectedPath, Detail: fmt.Sprintf("edited %s inside protected path %s", rel, protected)}
+	}
+	if len(d.scope) > 0 && !d.scope.Contains(rel) {
+		d.outOfScope[rel] = true
+		if len(d.outOfScope) > d.cfg.MaxOutOfScopeEdits {
+			return &AnomalyError{Kind: AnomalyOutOfScope,
+				Detail: fmt.Sprintf("edited %d files outside scope %s, most recently %s", len(d.outOfScope), d.scope, rel)}
+		}
+	}
+	return nil
+}
+
+// checkCommand checks each simple command of a shell command line for
+// deletions and writes to protected paths
+func (d *anomalyDetector) checkCommand(command string) *AnomalyError {
+	for _, args := range splitCommands(command) {
+		if anomaly := d.checkProtectedWrite(args); anomaly != nil {
+			return anomaly
+		}
+		if anomaly := d.checkDeletion(args); anomaly != nil {
+			return anomaly
+		}
+	}
+	return nil
+}
+
+// checkProtectedWrite looks for commands that modify protected paths:
+// file-changing commands naming one, output redirected into one, and git
+// config writes, when .git is protected
+func (d *anomalyDetector) checkProtectedWrite(args []string) *AnomalyError {
+	var targets []string
+	switch args[0] {
+	case "rm", "mv", "tee", "truncate", "chmod", "chown", "ln", "touch", "unlink", "rmdir":
+		targets = args[1:]
+	case "cp":
+		targets = args[len(args)-1:]
+	case "sed", "perl":
+		if hasFlag(args, "i") {
+			targets = args[1:]
+		}
+	case "git":
+		if len(args) > 1 && args[1] == "config" && isConfigWrite(args[2:]) && d.protected(".git/config") != "" {
+			return &AnomalyError{Kind: AnomalyProtectedPath, Detail: fmt.Sprintf("ran %q, which modifies git configuration", strings.Join(args, " "))}
+		}
+	}
+	for i, arg := range args {
+		if strings.HasPrefix(arg, ">") {
+			if target := strings.TrimLeft(arg, ">"); target != "" {
+				targets = append(targets, target)
+			} else if i+1 < len(args) {
+				targets = append(targets, args[i+1])
+			}
+		}
+	}
+	for _, target := range targets {
+		if strings.HasPrefix(target, "-") {
+			continue
+		}
+		if rel, inside := d.relative(target); inside {
+			if protected := d.protected(rel); protected != "" {
+				return &AnomalyError{Kind: AnomalyProtectedPath, Detail: fmt.Sprintf("ran %q, which modifies protected path %s", strings.Join(args, " "), protected)}
+			}
+		}
+	}
+	return nil
+}
+
+// checkDeletion counts the files rm and git rm delete, walking the working
+// tree for recursive deletes
+func (d *anomalyDetector) checkDeletion(args []string) *AnomalyError {
+	if args[0] == "git" && len(args) > 1 && args[1] == "rm" {
+		args = args[1:]
+	}
+	if args[0] != "rm" {
+		return nil
+	}
+	recursive := hasFlag(args, "r") || hasFlag(args, "R") || containsArg(args, "--recursive")
+	for _, target := range args[1:] {
+		if strings.HasPrefix(target, "-") {
+			continue
+		}
+		if recursive && (wholeTreeTargets[target] || target == d.workingDir) {
+			return &AnomalyError{Kind: AnomalyMassDeletion, Detail: fmt.Sprintf("ran %q, which deletes the whole working tree", strings.Join(args, " "))}
+		}
+		d.deletions += d.countFiles(target, recursive)
+	}
+	if d.deletions > d.cfg.MaxDeletions {
+		return &AnomalyError{Kind: AnomalyMassDeletion,
+			Detail: fmt.Sprintf("deleted %d files (limit %d), most recently with %q", d.deletions, d.cfg.MaxDeletions, strings.Join(args, " "))}
+	}
+	return nil
+}
+
+// countFiles returns how many files deleting target removes: the files under
+// it for a recursive delete of a directory, otherwise one. Walking stops once
+// the limit is exceeded.
+func (d *anomalyDetector) countFiles(target string, recursive bool) int {
+	path := target
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(d.workingDir, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() || !recursive {
+		return 1
+	}
+	count := 0
+	_ = filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			count++
+		}
+		if count > d.cfg.MaxDeletions {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return count
+}
+
+// relative returns path relative to the working tree, and whether it is
+// inside it
+func (d *anomalyDetector) relative(path string) (string, bool) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(d.workingDir, path)
+	}
+	rel, err := filepath.Rel(d.workingDir, filepath.Clean(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// isTemp reports whether path is in the system temp directory, where agents
+// may keep scratch files
+func (d *anomalyDetector) isTemp(path string) bool {
+	rel, err := filepath.Rel(os.TempDir(), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// protected returns the protected path rel is in, or ""
+func (d *anomalyDetector) protected(rel string) string {
+	for _, protected := range d.cfg.ProtectedPaths {
+		if rel == protected || strings.HasPrefix(rel, protected+"/") {
+			return protected
+		}
+	}
+	return ""
+}
+
+// splitCommands splits a shell command line into its simple commands, each
+// as words with quotes stripped, leading variable assignments and sudo
+// dropped. It is a heuristic, not a shell parser.
+func splitCommands(command string) [][]string {
+	replacer := strings.NewReplacer("&&", "\n", "||", "\n", ";", "\n", "|", "\n", "(", "\n", ")", "\n")
+	var commands [][]string
+	for _, line := range strings.Split(replacer.Replace(command), "\n") {
+		var args []string
+		for _, word := range strings.Fields(line) {
+			word = strings.Trim(word, `"'`)
+			if len(args) == 0 && (word == "sudo" || word == "command" || strings.Contains(word, "=")) {
+				continue
+			}
+			args = append(args, word)
+		}
+		if len(args) > 0 {
+			commands = append(commands, args)
+		}
+	}
+	return commands
+}
+
+// hasFlag reports whether a short flag appears in args, alone or combined
+// with others (-rf)
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args[1:] {
+		if len(arg) > 1 && arg[0] == '-' && arg[1] != '-' && strings.Contains(arg[1:], flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsArg reports whether args contains want
+func containsArg(args []string, want string) bool {
+	for _, arg := range args {
+		if arg == want {
+			return true
+		}
+	}
+	return false
+}
+
+// isConfigWrite reports whether git config arguments set or unset a value
+// rather than read one
+func isConfigWrite(args []string) bool {
+	var names []string
+	for _, arg := range args {
+		switch {
+		case arg == "--get" || arg == "--get-all" || arg == "--get-regexp" || arg == "--list" || arg == "-l" || arg == "--show-origin":
+			return false
+		case arg == "--unset" || arg == "--unset-all" || arg == "--add" || arg == "--replace-all" || arg == "--remove-section" || arg == "--rename-section":
+			return true
+		case !strings.HasPrefix(arg, "-"):
+			names = append(names, arg)
+		}
+	}
+	return len(names) >= 2 // name value
+}
@@ -0,0 +1,186 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+type toolCall struct {
+	tool    string
+	file    string
+	command string
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	workDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workDir, "testdata"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 30; i++ {
+		if err := os.WriteFile(filepath.Join(workDir, "testdata", fmt.Sprintf("case%d.json", i)), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "fixture.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repeat := func(n int, call toolCall) []toolCall {
+		calls := make([]toolCall, n)
+		for i := range calls {
+			calls[i] = call
+		}
+		return calls
+	}
+
+	tests := []struct {
+		name     string
+		scope    types.PathScope
+		calls    []toolCall
+		wantKind string // Empty for no anomaly
+	}{
+		{
+			name:  "ordinary work",
+			scope: types.PathScope{"internal/api"},
+			calls: []toolCall{
+				{tool: "read", file: "internal/api/server.go"},
+				{tool: "edit", file: "internal/api/server.go"},
+				{tool: "bash", command: "rm -rf /tmp/build && go test ./internal/api/... 2>/dev/null"},
+				{tool: "write", file: filepath.Join(os.TempDir(), "notes.md")},
+				{tool: "bash", command: "git config --get user.name; rm fixture.json"},
+			},
+		},
+		{
+			name:     "recursive delete of the working tree",
+			calls:    []toolCall{{tool: "bash", command: "cd src && sudo rm -rf ./*"}},
+			wantKind: AnomalyMassDeletion,
+		},
+		{
+			name:     "recursive delete of a large directory",
+			calls:    []toolCall{{tool: "bash", command: "rm -r testdata"}},
+			wantKind: AnomalyMassDeletion,
+		},
+		{
+			name:     "edit outside the working tree",
+			calls:    []toolCall{{tool: "edit", file: "../other-repo/main.go"}},
+			wantKind: AnomalyOutOfScope,
+		},
+		{
+			name:  "too many edits outside the issue's scope",
+			scope: types.PathScope{"internal/api"},
+			calls: []toolCall{
+				{tool: "edit", file: "cmd/a.go"}, {tool: "edit", file: "cmd/b.go"}, {tool: "edit", file: "cmd/c.go"},
+				{tool: "edit", file: "cmd/a.go"}, {tool: "write", file: "docs/d.md"}, {tool: "edit", file: "web/e.ts"},
+				{tool: "edit", file: "web/f.ts"},
+			},
+			wantKind: AnomalyOutOfScope,
+		},
+		{
+			name:     "same test run over and over",
+			calls:    repeat(9, toolCall{tool: "bash", command: "go test ./..."}),
+			wantKind: AnomalyRepeatedCall,
+		},
+		{
+			name: "edits in between reset repeated calls",
+			calls: append(append(repeat(8, toolCall{tool: "bash", command: "go test ./..."}),
+				toolCall{tool: "edit", file: "main.go"}), repeat(8, toolCall{tool: "bash", command: "go test ./..."})...),
+		},
+		{
+			name:     "edit inside .git",
+			calls:    []toolCall{{tool: "write", file: ".git/hooks/pre-commit"}},
+			wantKind: AnomalyProtectedPath,
+		},
+		{
+			name:     "redirect into vc config",
+			calls:    []toolCall{{tool: "bash", command: "echo 'enable_auto_commit: true' > .vc/config.yaml"}},
+			wantKind: AnomalyProtectedPath,
+		},
+		{
+			name:     "git config write",
+			calls:    []toolCall{{tool: "bash", command: "git config core.hooksPath /dev/null"}},
+			wantKind: AnomalyProtectedPath,
+		},
+		{
+			name:     "deleting the repository",
+			calls:    []toolCall{{tool: "bash", command: `rm -rf "` + filepath.Join(workDir, ".git") + `"`}},
+			wantKind: AnomalyProtectedPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newAnomalyDetector(config.DefaultAgentAnomalyConfig(), workDir, tt.scope)
+			var got *AnomalyError
+			for _, call := range tt.calls {
+				input := map[string]interface{}{}
+				if call.file != "" {
+					input["file_path"] = call.file
+				}
+				if call.command != "" {
+					input["command"] = call.command
+				}
+				if got = d.observe(call.tool, input, call.file, call.command); got != nil {
+					break
+				}
+			}
+			switch {
+			case tt.wantKind == "" && got != nil:
+				t.Errorf("observe() = %v, want no anomaly", got)
+			case tt.wantKind != "" && (got == nil || got.Kind != tt.wantKind):
+				t.Errorf("observe() = %v, want %s", got, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestEscalateAnomaly(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Tidy fixtures", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	e := &Executor{store: store, instanceID: "exec-test", config: &Config{}}
+	anomaly := &AnomalyError{Kind: AnomalyMassDeletion, Detail: `ran "rm -rf ./*", which deletes the whole working tree`}
+
+	e.escalateAnomaly(ctx, issue, &types.Execution{ID: 7}, anomaly)
+
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if len(labels) != 1 || labels[0] != "no-auto-claim" {
+		t.Errorf("issue labels = %v, want no-auto-claim", labels)
+	}
+	escalations, err := store.GetIssuesByLabel(ctx, "escalation")
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel failed: %v", err)
+	}
+	if len(escalations) != 1 || !strings.Contains(escalations[0].Title, "mass file deletion") {
+		t.Fatalf("escalations = %+v", escalations)
+	}
+	deps, err := store.GetDependencyRecords(ctx, escalations[0].ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != issue.ID || deps[0].Type != types.DepDiscoveredFrom {
+		t.Errorf("escalation deps = %+v", deps)
+	}
+	recorded, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeAgentAnomaly})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(recorded) != 1 || recorded[0].Data["kind"] != AnomalyMassDeletion {
+		t.Errorf("anomaly events = %+v", recorded)
+	}
+}
@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/backport"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// tryAutoBackport backports an issue whose work just landed in the working
+// directory to the release branches its "backport:<branch>" labels name.
+// Outcomes are recorded on the issue by the backport manager; failures here
+// never fail the execution.
+func (e *Executor) tryAutoBackport(ctx context.Context, issue *types.Issue) {
+	if e.backport == nil {
+		return
+	}
+	labels, err := e.store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get labels of %s: %v\n", issue.ID, err)
+		return
+	}
+	if len(backport.Branches(labels)) == 0 {
+		return
+	}
+
+	results, err := e.backport.BackportIssue(ctx, issue.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: auto-backport of %s failed: %v\n", issue.ID, err)
+		e.logEvent(ctx, events.EventTypeError, events.SeverityError, issue.ID,
+			fmt.Sprintf("Auto-backport of %s failed: %v", issue.ID, err),
+			map[string]interface{}{"labels": labels})
+		return
+	}
+	for _, result := range results {
+		switch result.Status {
+		case backport.StatusOpened:
+			fmt.Printf("✓ Backported %s to %s: %s\n", issue.ID, result.TargetBranch, result.PRURL)
+		case backport.StatusBranch:
+			fmt.Printf("✓ Backport of %s to %s ready on branch %s\n", issue.ID, result.TargetBranch, result.Branch)
+		case backport.StatusSkipped:
+			fmt.Printf("Backport of %s to %s skipped: %s\n", issue.ID, result.TargetBranch, result.Reason)
+		default:
+			fmt.Fprintf(os.Stderr, "warning: backport of %s to %s failed: %s\n", issue.ID, result.TargetBranch, result.Reason)
+			if result.FollowUpIssueID != "" {
+				fmt.Printf("   Filed %s to finish the backport\n", result.FollowUpIssueID)
+			}
+		}
+	}
+}
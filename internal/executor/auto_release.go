@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/release"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// tryAutoRelease releases the missions above an issue whose work just
+// landed, if closing the issue closed them and their "release:<version>"
+// labels ask for a release. Outcomes are recorded on the mission by the
+// release manager; failures here never fail the execution.
+func (e *Executor) tryAutoRelease(ctx context.Context, issue *types.Issue) {
+	if e.release == nil {
+		return
+	}
+	seen := map[string]bool{issue.ID: true}
+	pending := []string{issue.ID}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		parents, err := e.store.GetDependencies(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to get parents of %s: %v\n", id, err)
+			continue
+		}
+		for _, parent := range parents {
+			if parent.IssueType != types.TypeEpic || parent.Status != types.StatusClosed || seen[parent.ID] {
+				continue
+			}
+			seen[parent.ID] = true
+			pending = append(pending, parent.ID)
+			e.releaseMission(ctx, parent)
+		}
+	}
+}
+
+// releaseMission releases one closed epic, if its labels name a version
+func (e *Executor) releaseMission(ctx context.Context, mission *types.Issue) {
+	labels, err := e.store.GetLabels(ctx, mission.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get labels of %s: %v\n", mission.ID, err)
+		return
+	}
+	if release.Version(labels) == "" {
+		return
+	}
+
+	result, err := e.release.ReleaseMission(ctx, mission.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: auto-release of %s failed: %v\n", mission.ID, err)
+		e.logEvent(ctx, events.EventTypeError, events.SeverityError, mission.ID,
+			fmt.Sprintf("Auto-release of %s failed: %v", mission.ID, err),
+			map[string]interface{}{"labels": labels})
+		return
+	}
+	switch result.Status {
+	case release.StatusPublished:
+		fmt.Printf("✓ Released %s for mission %s: %s\n", result.Tag, mission.ID, result.URL)
+	case release.StatusTagged:
+		fmt.Printf("✓ Tagged %s for mission %s\n", result.Tag, mission.ID)
+		if result.Reason != "" {
+			fmt.Fprintf(os.Stderr, "warning: release %s not published: %s\n", result.Tag, result.Reason)
+		}
+	case release.StatusSkipped:
+		// Released by an earlier execution
+	default:
+		fmt.Fprintf(os.Stderr, "warning: release %s of %s failed: %s\n", result.Tag, mission.ID, result.Reason)
+	}
+}
@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// tryAutoRollback reverts commitHash when the baseline failed on it, an
+// execution made it, and the baseline passed on its parent - i.e. that
+// execution's commit broke the build. Returns true if it was reverted, in
+// which case the baseline should be rechecked instead of self-healing.
+func (e *Executor) tryAutoRollback(ctx context.Context, commitHash string) bool {
+	if e.rollback == nil || e.preFlightChecker == nil {
+		return false
+	}
+
+	execs, err := e.store.ListExecutions(ctx, types.ExecutionFilter{CommitHash: commitHash})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to look up the execution of %s: %v\n", commitHash, err)
+		return false
+	}
+	if len(execs) == 0 || execs[0].IsRolledBack() {
+		return false
+	}
+	execution := execs[0]
+
+	// Only blame the commit if the baseline passed right before it
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", commitHash+"^")
+	cmd.Dir = e.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return false // Root commit, or git trouble: nothing to compare with
+	}
+	parent := strings.TrimSpace(string(output))
+	parentBaseline, err := e.preFlightChecker.storage.GetGateBaseline(ctx, parent)
+	if err != nil || parentBaseline == nil || !parentBaseline.AllPassed {
+		return false
+	}
+
+	reason := "quality gates failed on the baseline after this commit"
+	if results, err := e.preFlightChecker.GetCachedResults(ctx, commitHash); err == nil && results != nil {
+		if failing := e.preFlightChecker.getFailingGates(results); len(failing) > 0 {
+			reason = fmt.Sprintf("quality gates failed on the baseline after this commit (%s)", strings.Join(failing, ", "))
+		}
+	}
+
+	result, err := e.rollback.RollbackExecution(ctx, execution.ID, reason)
+	if result == nil {
+		fmt.Fprintf(os.Stderr, "warning: auto-rollback of %s failed: %v\n", commitHash, err)
+		e.logEvent(ctx, events.EventTypeError, events.SeverityError, execution.IssueID,
+			fmt.Sprintf("Auto-rollback of commit %s failed: %v", commitHash, err),
+			map[string]interface{}{"execution_id": execution.ID, "commit": commitHash})
+		return false
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	fmt.Printf("↩️  Rolled back commit %s from %s (execution %d): %s\n", safeShortHash(commitHash), execution.IssueID, execution.ID, reason)
+	if result.FollowUpIssueID != "" {
+		fmt.Printf("   Filed %s to redo the work\n", result.FollowUpIssueID)
+	}
+	e.preFlightChecker.InvalidateAllCache()
+	return true
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/rollback"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestAutoRollback verifies that an execution's commit is reverted when the
+// baseline fails on it after passing on its parent
+func TestAutoRollback(t *testing.T) {
+	ctx := context.Background()
+	cfg := storage.DefaultConfig()
+	cfg.Path = t.TempDir() + "/test.db"
+	store, err := storage.NewStorage(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	vcStorage := store.(*beads.VCStorage)
+
+	gitDir := t.TempDir()
+	if err := setupTestGitRepo(gitDir); err != nil {
+		t.Fatalf("Failed to setup test git repo: %v", err)
+	}
+	gitRun := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = gitDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	parent := gitRun("rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(gitDir, "broken.go"), []byte("package broken\n\nbroken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun("add", "-A")
+	gitRun("commit", "-m", "vc-1: add broken.go")
+	broken := gitRun("rev-parse", "HEAD")
+
+	issue := &types.Issue{
+		Title:              "Add broken.go",
+		Description:        "Adds a file that breaks the build",
+		IssueType:          types.TypeTask,
+		Status:             types.StatusOpen,
+		Priority:           2,
+		AcceptanceCriteria: "Builds",
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded, CommitHash: broken}
+	if err := store.CreateExecution(ctx, execution); err != nil {
+		t.Fatalf("Failed to create execution: %v", err)
+	}
+
+	checker, err := NewPreFlightChecker(vcStorage, &mockGateRunner{}, &PreFlightConfig{
+		Enabled: true, CacheTTL: 5 * time.Minute, FailureMode: FailureModeBlock, WorkingDir: gitDir, GatesTimeout: 30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create preflight checker: %v", err)
+	}
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create git operations: %v", err)
+	}
+	manager, err := rollback.New(rollback.Config{Store: store, Git: gitOps, RepoPath: gitDir, Actor: "test"})
+	if err != nil {
+		t.Fatalf("Failed to create rollback manager: %v", err)
+	}
+	e := &Executor{store: store, preFlightChecker: checker, rollback: manager, workingDir: gitDir, instanceID: "test-executor"}
+
+	// Without a passing baseline on the parent the commit isn't blamed
+	if e.tryAutoRollback(ctx, broken) {
+		t.Fatal("rolled back without knowing the parent's baseline passed")
+	}
+
+	if err := vcStorage.SetGateBaseline(ctx, &beads.GateBaseline{
+		CommitHash: parent, BranchName: "main", Timestamp: time.Now().Format(time.RFC3339), AllPassed: true,
+		Results: map[string]*types.GateResult{},
+	}); err != nil {
+		t.Fatalf("Failed to store baseline: %v", err)
+	}
+	if !e.tryAutoRollback(ctx, broken) {
+		t.Fatal("expected the commit to be rolled back")
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "broken.go")); !os.IsNotExist(err) {
+		t.Errorf("broken.go should be reverted, stat error: %v", err)
+	}
+
+	got, err := store.GetExecution(ctx, execution.ID)
+	if err != nil {
+		t.Fatalf("GetExecution failed: %v", err)
+	}
+	if got.RevertCommit != gitRun("rev-parse", "HEAD") || got.RollbackIssueID == "" {
+		t.Errorf("execution not linked to the rollback: %+v", got)
+	}
+
+	// Already rolled back: nothing more to do
+	if e.tryAutoRollback(ctx, broken) {
+		t.Error("rolled back the same commit twice")
+	}
+}
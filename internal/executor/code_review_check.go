@@ -8,6 +8,7 @@ import (
 
 	"github.com/steveyegge/vc/internal/codereview"
 	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/storage/beads"
 	"github.com/steveyegge/vc/internal/types"
 )
@@ -29,7 +30,7 @@ func (e *Executor) checkCodeReviewSweep(ctx context.Context) error {
 	}
 
 	// Get VCStorage from storage interface
-	vcStorage, ok := e.store.(*beads.VCStorage)
+	vcStorage, ok := storage.Unscoped(e.store).(*beads.VCStorage)
 	if !ok {
 		// Log warning and skip
 		fmt.Fprintf(os.Stderr, "warning: storage is not VCStorage, skipping code review check\n")
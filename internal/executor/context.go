@@ -21,6 +21,15 @@ type PromptContext struct {
 	// ParentMission is the parent issue if this is a subtask
 	ParentMission *types.Issue
 
+	// CustomFields are the issue's custom field values (component, customer, ...)
+	CustomFields []*types.CustomFieldValue
+
+	// PathScope confines the issue's changes to these paths (empty = anywhere)
+	PathScope types.PathScope
+
+	// LFSPatterns are the paths Git LFS tracks, which the agent must not change
+	LFSPatterns []string
+
 	// RelatedIssues contains all dependency and relationship information
 	RelatedIssues *RelatedIssues
 
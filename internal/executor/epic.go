@@ -145,7 +145,7 @@ func checkAndCloseEpicIfComplete(ctx context.Context, store storage.Storage, sup
 			fmt.Printf("AI recommends closing epic %s (confidence: %.2f)\n", epicID, assessment.Confidence)
 
 			reason := fmt.Sprintf("AI assessment: objectives met (confidence: %.2f)", assessment.Confidence)
-			if err := store.CloseIssue(ctx, epicID, reason, "ai-supervisor"); err != nil {
+			if err := closeCompleted(ctx, store, epic, reason, "ai-supervisor"); err != nil {
 				return false, fmt.Errorf("failed to close epic: %w", err)
 			}
 
@@ -164,15 +164,6 @@ func checkAndCloseEpicIfComplete(ctx context.Context, store storage.Storage, sup
 			message := fmt.Sprintf("Epic %s completed: %s (AI assessment, confidence: %.2f)", epicID, epic.Title, assessment.Confidence)
 			logEpicCompletedEvent(ctx, store, epicID, instanceID, message, eventData)
 
-			// vc-218: If this is a mission epic, transition to needs-quality-gates state
-			if epic.IssueSubtype == types.SubtypeMission {
-				if err := labels.TransitionState(ctx, store, epicID, "", labels.LabelNeedsQualityGates, labels.TriggerEpicCompleted, "ai-supervisor"); err != nil {
-					// Log warning but don't fail - state transition is best-effort
-					fmt.Printf("Warning: failed to transition mission %s to needs-quality-gates: %v\n", epicID, err)
-				} else {
-					fmt.Printf("✓ Mission %s transitioned to needs-quality-gates state\n", epicID)
-				}
-			}
 
 			return true, nil // Successfully closed
 		} else {
@@ -200,7 +191,7 @@ func checkAndCloseEpicIfComplete(ctx context.Context, store storage.Storage, sup
 		fmt.Printf("All children of epic %s are complete, closing epic\n", epicID)
 
 		reason := fmt.Sprintf("All %d child issues completed (fallback logic)", len(children))
-		if err := store.CloseIssue(ctx, epicID, reason, "executor"); err != nil {
+		if err := closeCompleted(ctx, store, epic, reason, "executor"); err != nil {
 			return false, fmt.Errorf("failed to close epic: %w", err)
 		}
 
@@ -219,15 +210,6 @@ func checkAndCloseEpicIfComplete(ctx context.Context, store storage.Storage, sup
 		message := fmt.Sprintf("Epic %s completed: %s (all %d children closed)", epicID, epic.Title, len(children))
 		logEpicCompletedEvent(ctx, store, epicID, instanceID, message, eventData)
 
-		// vc-218: If this is a mission epic, transition to needs-quality-gates state
-		if epic.IssueSubtype == types.SubtypeMission {
-			if err := labels.TransitionState(ctx, store, epicID, "", labels.LabelNeedsQualityGates, labels.TriggerEpicCompleted, "executor"); err != nil {
-				// Log warning but don't fail - state transition is best-effort
-				fmt.Printf("Warning: failed to transition mission %s to needs-quality-gates: %v\n", epicID, err)
-			} else {
-				fmt.Printf("✓ Mission %s transitioned to needs-quality-gates state\n", epicID)
-			}
-		}
 
 		return true, nil // Successfully closed
 	}
@@ -235,6 +217,39 @@ func checkAndCloseEpicIfComplete(ctx context.Context, store storage.Storage, sup
 	return false, nil
 }
 
+// closeCompleted closes a completed epic or decomposed parent in one
+// transaction, together with moving a mission epic to the
+// needs-quality-gates state (vc-218). A failure part way leaves the issue
+// open for the next completion check, rather than closed without its
+// mission ever reaching quality gates.
+func closeCompleted(ctx context.Context, store storage.Storage, issue *types.Issue, reason, actor string) error {
+	isMission := issue.IssueType == types.TypeEpic && issue.IssueSubtype == types.SubtypeMission
+	err := store.RunInVCTransaction(ctx, func(tx *storage.VCTransaction) error {
+		// Closed issues keep no assignee (vc-3e0o)
+		if err := tx.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": nil}, actor); err != nil {
+			return fmt.Errorf("failed to clear assignee of %s: %w", issue.ID, err)
+		}
+		if err := tx.CloseIssue(ctx, issue.ID, reason, actor); err != nil {
+			return fmt.Errorf("failed to close %s: %w", issue.ID, err)
+		}
+		if isMission {
+			if err := tx.AddLabel(ctx, issue.ID, labels.LabelNeedsQualityGates, actor); err != nil {
+				return fmt.Errorf("failed to transition mission %s to needs-quality-gates: %w", issue.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil || !isMission {
+		return err
+	}
+
+	fmt.Printf("✓ Mission %s transitioned to needs-quality-gates state\n", issue.ID)
+	if err := labels.LogTransition(ctx, store, issue.ID, "", labels.LabelNeedsQualityGates, labels.TriggerEpicCompleted, actor); err != nil {
+		fmt.Printf("Warning: failed to log state transition of mission %s: %v\n", issue.ID, err)
+	}
+	return nil
+}
+
 // cleanupMissionSandboxIfComplete checks if a closed epic is a mission and cleans up its sandbox
 // This is called after checkAndCloseEpicIfComplete successfully closes an epic
 // vc-276: Added instanceID parameter to properly attribute events to the executor instance
@@ -397,7 +412,7 @@ func checkAndCloseDecomposedParent(ctx context.Context, store storage.Storage, i
 		fmt.Printf("All %d children of decomposed parent %s are complete, auto-closing parent\n", len(children), parentID)
 
 		reason := fmt.Sprintf("All %d decomposed child issues completed", len(children))
-		if err := store.CloseIssue(ctx, parentID, reason, "ai-supervisor"); err != nil {
+		if err := closeCompleted(ctx, store, parent, reason, "ai-supervisor"); err != nil {
 			return fmt.Errorf("failed to close decomposed parent: %w", err)
 		}
 
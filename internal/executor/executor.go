@@ -9,23 +9,29 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/google/uuid"
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/backport"
 	"github.com/steveyegge/vc/internal/config"
 	"github.com/steveyegge/vc/internal/control"
 	"github.com/steveyegge/vc/internal/cost"
 	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/depgraph"
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/gates"
 	"github.com/steveyegge/vc/internal/git"
 	"github.com/steveyegge/vc/internal/health"
+	"github.com/steveyegge/vc/internal/release"
+	"github.com/steveyegge/vc/internal/rollback"
 	"github.com/steveyegge/vc/internal/sandbox"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/tracing"
 	"github.com/steveyegge/vc/internal/types"
 	"github.com/steveyegge/vc/internal/watchdog"
 )
@@ -69,7 +75,13 @@ type Executor struct {
 	deduplicator     deduplication.Deduplicator // Shared deduplicator for sandbox manager and results processor (vc-137)
 	gitOps           git.GitOperations          // Git operations for auto-commit (vc-136)
 	messageGen       *git.MessageGenerator      // Commit message generator (vc-136)
+	workflow         *git.WorkflowManager       // Branch-per-issue workflow (nil = work on the checked-out branch)
+	rollback         *rollback.Manager          // Reverts execution commits that break the baseline (nil = disabled)
+	backport         *backport.Manager          // Cherry-picks landed work onto release branches (nil = disabled)
+	release          *release.Manager           // Tags closed release missions (nil = disabled)
 	qaWorker         *QualityGateWorker         // QA worker for quality gate execution (vc-254)
+	mutationSched    *gates.MutationScheduler   // Scheduler for optional mutation testing gate (nil = disabled)
+	gateFullRuns     *gates.FullRunTracker      // Tracks the periodic full gate run for incremental gates
 	costTracker      *cost.Tracker              // Cost budget tracker (vc-e3s7)
 	loopDetector     *LoopDetector              // Loop detector for unproductive patterns (vc-0vfg)
 	controlServer    *control.Server            // Control server for pause/resume commands (vc-00cu)
@@ -110,6 +122,20 @@ type Executor struct {
 	running            bool
 	selfHealingMsgLast time.Time      // Last time we printed the self-healing mode message (for throttling)
 	qaWorkersWg        sync.WaitGroup // Tracks active QA worker goroutines for graceful shutdown (vc-0d58)
+	activeQAWorkers    atomic.Int32   // QA worker goroutines running gates, reported by /readyz
+	lastPoll           time.Time      // When the event loop last polled for work (protected by mu)
+	workPause          string         // Why claiming new work is paused, empty when it isn't (protected by mu)
+	claimedIssueID     string         // Issue being executed, whose claim lease the heartbeat renews (protected by mu)
+	aiDown             bool           // Whether the AI provider's circuit breaker was open at the last poll (protected by mu)
+	resourcesLow       bool           // Whether a resource check failed at the last poll (protected by mu)
+
+	// Pooled worktrees in use, whose assignment the heartbeat renews (protected by mu)
+	heldWorktrees map[*sandbox.PooledWorktree]*sandbox.WorktreePool
+
+	// The running agent, so the stuck work watchdog can stop it (protected by agentMu)
+	agentMu      sync.Mutex
+	agentIssueID string
+	agentCancel  context.CancelFunc
 
 	// Self-healing state machine (vc-23t0)
 	selfHealingMode SelfHealingMode // Current state in the self-healing state machine
@@ -131,6 +157,7 @@ type Executor struct {
 	currentPollInterval time.Duration // Dynamic poll interval (increases in steady state)
 	steadyStateCount    int           // Consecutive polls in steady state
 	lastGitCommit       string        // Last seen git commit hash
+	idlePollInterval    time.Duration // Poll interval backed off to while the ready queue is empty (0 = not backing off)
 	steadyStateMutex    sync.RWMutex  // Protects steady state fields
 }
 
@@ -360,8 +387,10 @@ type Config struct {
 	EnableAISupervision     bool                         // Enable AI assessment and analysis (default: true)
 	EnableQualityGates      bool                         // Enable quality gates enforcement (default: true)
 	GatesTimeout            time.Duration                // Quality gates timeout (default: 5 minutes, env: VC_QUALITY_GATES_TIMEOUT, vc-xcfw)
+	AgentType               AgentType                    // Coding agent to run (default: claude-code, env: VC_AGENT_PROVIDER)
 	EnableAutoCommit        bool                         // Enable automatic git commits after successful execution (default: false, vc-142)
 	EnableAutoPR            bool                         // Enable automatic PR creation after successful commit (default: false, requires EnableAutoCommit, vc-389e)
+	Hosting                 config.HostingConfig         // GitHub/GitLab API integration used by auto-PR; without a token the gh CLI is used
 	EnableSandboxes         bool                         // Enable sandbox isolation (default: true, vc-144)
 	KeepSandboxOnFailure    bool                         // Keep failed sandboxes for debugging (default: false)
 	KeepBranches            bool                         // Keep mission branches after cleanup (default: false)
@@ -375,6 +404,7 @@ type Config struct {
 	SandboxRoot             string                       // Root directory for sandboxes (default: ".sandboxes")
 	ParentRepo              string                       // Parent repository path (default: ".")
 	DefaultBranch           string                       // Default git branch for sandboxes (default: "main")
+	Project                 string                       // Only work on issues in this project, using its repo and branch settings (default: "" = all issues)
 	WatchdogConfig          *watchdog.WatchdogConfig     // Watchdog configuration (default: conservative defaults)
 	DeduplicationConfig     *deduplication.Config        // Deduplication configuration (default: sensible defaults, nil = use defaults)
 	EventRetentionConfig    *config.EventRetentionConfig // Event retention and cleanup configuration (default: sensible defaults, nil = use defaults)
@@ -405,6 +435,96 @@ type Config struct {
 	// Control server configuration (vc-00cu)
 	EnableControlServer bool   // Enable control server for pause/resume commands (default: true)
 	ControlSocketPath   string // Path to control socket (default: ".vc/executor.sock")
+
+	// Back off while the ready queue is empty: after each poll that finds no
+	// work, double the poll interval up to this, and drop back to
+	// PollInterval once work turns up (default: 0, always poll at PollInterval)
+	IdleBackoffMax time.Duration
+
+	// Cost budget tracker to use instead of loading one from VC_COST_*, so
+	// the executors of a Pool share one budget (default: nil)
+	CostTracker *cost.Tracker
+
+	// Dependency graph checker to run when no ready work is found, at most
+	// every DependencyCheckIdleInterval, since a dependency cycle or knot is
+	// a common reason there is none (default: nil, don't check)
+	DependencyChecker           *depgraph.Checker
+	DependencyCheckIdleInterval time.Duration
+
+	// Auto-commit configuration (only used with EnableAutoCommit)
+	AutoCommitPaths        []string // Only commit changed files matching these patterns (default: all files)
+	AutoCommitExcludePaths []string // Never commit changed files matching these patterns
+	AutoCommitAmendOnRetry bool     // Amend the previous attempt's commit when retrying an issue, if it is still HEAD (default: false)
+	CommitSigning          config.CommitSigningConfig // Sign auto-commits and/or commit them as a distinct identity (default: neither)
+	CommitAttribution      config.CommitAttributionConfig // Co-Authored-By, VC-Agent and VC-Execution trailers on auto-commits (default: all)
+	PushChecks             config.PushChecksConfig        // Safety checks before auto-PR pushes (default: all on)
+
+	// What to do with uncommitted human changes in the working directory
+	// before an agent runs outside a sandbox: refuse, stash, worktree or
+	// ignore (default: stash; empty means ignore)
+	DirtyWorktree config.DirtyWorktreeConfig
+
+	// What to do with agent edits outside an issue's path scope (its
+	// "scope:" labels): revert or flag (default: revert; empty means flag)
+	PathScope config.PathScopeConfig
+
+	// Stop agents that delete files en masse, edit far outside their
+	// issue's scope, repeat the same tool call or modify protected paths,
+	// and escalate their issues (default: on; the zero value is off)
+	AgentAnomaly config.AgentAnomalyConfig
+
+	// Refuse to claim work or run quality gates while free disk space, temp
+	// space or file descriptor headroom is low, after removing failed
+	// sandboxes (default: on; the zero value is off)
+	ResourceCheck config.ResourceCheckConfig
+
+	// Patch-proposal mode: outside sandboxes, the agent works in a scratch
+	// worktree and its changes are applied only once the supervisor approves
+	// them as a patch (default: off)
+	PatchProposal config.PatchProposalConfig
+
+	// Suggest human reviewers from the recent authors of the files an
+	// execution touched, when it opens a pull request or escalates
+	// (default: on; the zero value is off)
+	Reviewers config.ReviewersConfig
+
+	// Keep Git LFS-tracked files and large binaries out of AI prompts, tell
+	// the agent not to touch LFS paths, and flag changes to either
+	// (default: on; the zero value is off)
+	LargeFiles config.LargeFilesConfig
+
+	// Update submodules before gates and revert or commit submodule pointers
+	// agents move (default: update, revert)
+	Submodules config.SubmodulesConfig
+
+	// Fetch, rebase, re-run gates and push again when a push is rejected
+	// because the remote branch advanced (default: 3 attempts; the zero
+	// value never retries)
+	PushRetry config.PushRetryConfig
+
+	// Branch-per-issue workflow: outside sandboxes, work on vc/<issue-id>-<slug>
+	// and merge into the checked-out branch only once gates and review pass
+	// (default: false, requires EnableAutoCommit)
+	EnableBranchWorkflow bool
+
+	// Revert an execution's commit, and file a follow-up issue to redo it,
+	// when the baseline fails on that commit but passed on its parent
+	// (default: false, requires EnableAutoCommit)
+	EnableAutoRollback bool
+
+	// Cherry-pick an issue's commits onto the release branches its
+	// "backport:<branch>" labels name once they land, and open pull
+	// requests there (default: false, requires EnableAutoCommit)
+	EnableAutoBackport bool
+
+	// Tag a mission labelled "release:<version>" once it closes and its
+	// work has landed, with release notes generated from the issues closed
+	// under it (default: false, requires EnableAutoCommit)
+	EnableAutoRelease bool
+
+	// Push release tags and publish releases on the git hosting provider
+	// (default: false, requires EnableAutoRelease)
+	PublishReleases bool
 }
 
 // Validate checks the configuration for invalid combinations (vc-q5ve)
@@ -420,6 +540,102 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("EnableAutoPR requires EnableAutoCommit to be enabled")
 	}
 
+	if c.Hosting.Enabled() {
+		if err := c.Hosting.Validate(); err != nil {
+			return fmt.Errorf("invalid git hosting configuration: %w", err)
+		}
+	}
+
+	// Branch-per-issue merges auto-committed work; without commits nothing would merge
+	if c.EnableBranchWorkflow && !c.EnableAutoCommit {
+		return fmt.Errorf("EnableBranchWorkflow requires EnableAutoCommit to be enabled")
+	}
+
+	// Only auto-committed work has an execution to roll back
+	if c.EnableAutoRollback && !c.EnableAutoCommit {
+		return fmt.Errorf("EnableAutoRollback requires EnableAutoCommit to be enabled")
+	}
+	if c.EnableAutoBackport && !c.EnableAutoCommit {
+		return fmt.Errorf("EnableAutoBackport requires EnableAutoCommit to be enabled")
+	}
+	if c.EnableAutoRelease && !c.EnableAutoCommit {
+		return fmt.Errorf("EnableAutoRelease requires EnableAutoCommit to be enabled")
+	}
+	if c.PublishReleases && !c.EnableAutoRelease {
+		return fmt.Errorf("PublishReleases requires EnableAutoRelease to be enabled")
+	}
+
+	if err := c.CommitSigning.Validate(); err != nil {
+		return fmt.Errorf("invalid commit signing configuration: %w", err)
+	}
+
+	if err := c.CommitAttribution.Validate(); err != nil {
+		return fmt.Errorf("invalid commit attribution configuration: %w", err)
+	}
+
+	if err := c.PushChecks.Validate(); err != nil {
+		return fmt.Errorf("invalid push checks configuration: %w", err)
+	}
+
+	if c.DirtyWorktree.Policy != "" {
+		if err := c.DirtyWorktree.Validate(); err != nil {
+			return fmt.Errorf("invalid dirty worktree configuration: %w", err)
+		}
+	}
+
+	if c.PathScope.OnViolation != "" {
+		if err := c.PathScope.Validate(); err != nil {
+			return fmt.Errorf("invalid path scope configuration: %w", err)
+		}
+	}
+
+	if c.AgentAnomaly.Enabled {
+		if err := c.AgentAnomaly.Validate(); err != nil {
+			return fmt.Errorf("invalid agent anomaly configuration: %w", err)
+		}
+	}
+
+	if c.ResourceCheck.Enabled {
+		if err := c.ResourceCheck.Validate(); err != nil {
+			return fmt.Errorf("invalid resource check configuration: %w", err)
+		}
+	}
+
+	if c.Reviewers.Enabled {
+		if err := c.Reviewers.Validate(); err != nil {
+			return fmt.Errorf("invalid reviewers configuration: %w", err)
+		}
+	}
+
+	if err := c.LargeFiles.Validate(); err != nil {
+		return fmt.Errorf("invalid large files configuration: %w", err)
+	}
+
+	if c.Submodules.OnPointerChange != "" {
+		if err := c.Submodules.Validate(); err != nil {
+			return fmt.Errorf("invalid submodules configuration: %w", err)
+		}
+	}
+
+	if c.PushRetry.MaxAttempts != 0 {
+		if err := c.PushRetry.Validate(); err != nil {
+			return fmt.Errorf("invalid push retry configuration: %w", err)
+		}
+	}
+
+	if err := c.PatchProposal.Validate(); err != nil {
+		return fmt.Errorf("invalid patch proposal configuration: %w", err)
+	}
+	if c.PatchProposal.Enabled && !c.EnableAISupervision {
+		return fmt.Errorf("patch-proposal mode requires EnableAISupervision to review patches")
+	}
+
+	for _, pattern := range append(append([]string{}, c.AutoCommitPaths...), c.AutoCommitExcludePaths...) {
+		if err := validatePathPattern(pattern); err != nil {
+			return err
+		}
+	}
+
 	// Quality gate worker requires quality gates
 	if c.EnableQualityGateWorker && !c.EnableQualityGates {
 		return fmt.Errorf("EnableQualityGateWorker requires EnableQualityGates to be enabled")
@@ -463,6 +679,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("SandboxRetentionCount must be non-negative, got %d", c.SandboxRetentionCount)
 	}
 
+	if c.AgentType != "" && !c.AgentType.IsValid() {
+		return fmt.Errorf("invalid AgentType %q (VC_AGENT_PROVIDER must be claude-code or amp)", c.AgentType)
+	}
+
 	return nil
 }
 
@@ -479,6 +699,7 @@ func DefaultConfig() *Config {
 		EnableAISupervision:     true,
 		EnableQualityGates:      true,
 		GatesTimeout:            getEnvDuration("VC_QUALITY_GATES_TIMEOUT", 5*time.Minute), // Configurable timeout (vc-xcfw)
+		AgentType:               AgentType(os.Getenv("VC_AGENT_PROVIDER")),
 		EnableSandboxes:         true, // Changed to true for safety (vc-144)
 		KeepSandboxOnFailure:    false,
 		KeepBranches:            false,
@@ -492,6 +713,18 @@ func DefaultConfig() *Config {
 		SandboxRoot:             ".sandboxes",
 		ParentRepo:              ".",
 		DefaultBranch:           "main",
+		Hosting:                 config.DefaultHostingConfig(),
+		CommitAttribution:       config.DefaultCommitAttributionConfig(),
+		PushChecks:              config.DefaultPushChecksConfig(),
+		DirtyWorktree:           config.DefaultDirtyWorktreeConfig(),
+		PathScope:               config.DefaultPathScopeConfig(),
+		AgentAnomaly:            config.DefaultAgentAnomalyConfig(),
+		ResourceCheck:           config.DefaultResourceCheckConfig(),
+		PatchProposal:           config.DefaultPatchProposalConfig(),
+		Reviewers:               config.DefaultReviewersConfig(),
+		LargeFiles:              config.DefaultLargeFilesConfig(),
+		Submodules:              config.DefaultSubmodulesConfig(),
+		PushRetry:               config.DefaultPushRetryConfig(),
 		// Self-healing / Escalation configuration (vc-h8b8, vc-tn9c)
 		// MaxEscalation* fields are legacy, use SelfHealing* fields for consistency
 		MaxEscalationAttempts:      getEnvInt("VC_SELF_HEALING_MAX_ATTEMPTS", 5),
@@ -518,6 +751,11 @@ func New(cfg *Config) (*Executor, error) {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	// Confine the executor to its project's backlog and repository
+	if err := applyProjectConfig(context.Background(), cfg); err != nil {
+		return nil, err
+	}
+
 	hostname, err := os.Hostname()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get hostname: %w", err)
@@ -611,9 +849,9 @@ func New(cfg *Config) (*Executor, error) {
 
 	// Initialize cost tracker first (vc-e3s7)
 	// This is initialized even if AI supervision is disabled, for budget monitoring
-	var costTracker *cost.Tracker
+	costTracker := cfg.CostTracker
 	costConfig := cost.LoadFromEnv()
-	if costConfig.Enabled {
+	if costTracker == nil && costConfig.Enabled {
 		tracker, err := cost.NewTracker(costConfig, cfg.Store)
 		if err != nil {
 			// Log warning but continue without cost tracking
@@ -649,6 +887,51 @@ func New(cfg *Config) (*Executor, error) {
 		fmt.Fprintf(os.Stderr, "Warning: failed to initialize git operations: %v (auto-commit disabled)\n", err)
 	} else {
 		e.gitOps = gitOps
+		if cfg.EnableBranchWorkflow {
+			e.workflow = git.NewWorkflowManager(gitOps, workingDir)
+		}
+		if cfg.EnableAutoRollback {
+			e.rollback, err = rollback.New(rollback.Config{
+				Store:      cfg.Store,
+				Git:        gitOps,
+				RepoPath:   workingDir,
+				Actor:      "vc-executor",
+				ExecutorID: e.instanceID,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to initialize rollback: %v (auto-rollback disabled)\n", err)
+			}
+		}
+		if cfg.EnableAutoBackport {
+			e.backport, err = backport.New(backport.Config{
+				Store:        cfg.Store,
+				Git:          gitOps,
+				RepoPath:     workingDir,
+				Hosting:      cfg.Hosting,
+				PushChecks:   cfg.PushChecks,
+				Actor:        "vc-executor",
+				ExecutorID:   e.instanceID,
+				HoldWorktree: e.holdWorktree,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to initialize backport: %v (auto-backport disabled)\n", err)
+			}
+		}
+		if cfg.EnableAutoRelease {
+			e.release, err = release.New(release.Config{
+				Store:      cfg.Store,
+				Git:        gitOps,
+				RepoPath:   workingDir,
+				Hosting:    cfg.Hosting,
+				PushChecks: cfg.PushChecks,
+				Publish:    cfg.PublishReleases,
+				Actor:      "vc-executor",
+				ExecutorID: e.instanceID,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to initialize release: %v (auto-release disabled)\n", err)
+			}
+		}
 	}
 
 	// Initialize message generator for auto-commit (vc-136)
@@ -658,7 +941,7 @@ func New(cfg *Config) (*Executor, error) {
 		apiKey := os.Getenv("ANTHROPIC_API_KEY")
 		if apiKey != "" {
 			// Create Anthropic client for message generation (vc-35: using Haiku for cost efficiency)
-			client := anthropic.NewClient(option.WithAPIKey(apiKey))
+			client := anthropic.NewClient(option.WithAPIKey(apiKey), option.WithMiddleware(tracing.AnthropicMiddleware()))
 			e.messageGen = git.NewMessageGenerator(&client, ai.GetSimpleTaskModel())
 		} else {
 			fmt.Fprintf(os.Stderr, "Warning: ANTHROPIC_API_KEY not set (auto-commit message generation disabled)\n")
@@ -835,7 +1118,7 @@ func New(cfg *Config) (*Executor, error) {
 		preFlightConfig.WorkingDir = workingDir
 
 		// Get VCStorage from storage interface
-		vcStorage, ok := cfg.Store.(*beads.VCStorage)
+		vcStorage, ok := storage.Unscoped(cfg.Store).(*beads.VCStorage)
 		if !ok {
 			fmt.Fprintf(os.Stderr, "Warning: storage is not VCStorage (preflight disabled)\n")
 		} else {
@@ -863,6 +1146,23 @@ func New(cfg *Config) (*Executor, error) {
 		}
 	}
 
+	// Shared across gate runs so incremental gates (.vc/gates.yaml) still do periodic full runs
+	if cfg.EnableQualityGates {
+		e.gateFullRuns = gates.NewFullRunTracker()
+	}
+
+	// Initialize optional mutation testing gate (opt-in via VC_MUTATION_ENABLED)
+	if cfg.EnableQualityGates {
+		mutationConfig, err := gates.MutationConfigFromEnv()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid mutation testing configuration: %v (mutation testing disabled)\n", err)
+		} else if mutationConfig.Enabled {
+			e.mutationSched = gates.NewMutationScheduler(mutationConfig)
+			fmt.Printf("✓ Mutation testing gate enabled (interval: %v, command: %s)\n",
+				mutationConfig.Interval, strings.Join(mutationConfig.Command, " "))
+		}
+	}
+
 	// Initialize QA worker if enabled (vc-254)
 	if cfg.EnableQualityGateWorker && cfg.EnableQualityGates {
 		// Create gates runner for QA worker (separate from preflight runner)
@@ -979,6 +1279,15 @@ func (e *Executor) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to register executor instance: %w", err)
 	}
 
+	// Reconcile what a crashed vc process left behind: claims of executors
+	// that are gone, executions still marked running and half-finished plan
+	// approvals. Runs before claiming work, like the cleanup below.
+	if recovered, err := e.recoverOrphanedState(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: crash recovery on startup failed: %v\n", err)
+	} else if recovered > 0 {
+		fmt.Printf("Recovery: Reconciled %d item(s) left behind by stopped executors\n", recovered)
+	}
+
 	// Clean up orphaned claims and stale instances on startup (vc-109)
 	// This runs synchronously before event loop starts to prevent claiming already-claimed issues
 	staleThresholdSecs := int(e.staleThreshold.Seconds())
@@ -1105,9 +1414,59 @@ func (e *Executor) heartbeatLoop(ctx context.Context) {
 		case <-e.heartbeatStopCh:
 			return
 		case <-ticker.C:
-			if err := e.store.UpdateHeartbeat(ctx, e.instanceID); err != nil {
-				fmt.Fprintf(os.Stderr, "heartbeat update failed: %v\n", err)
-			}
+			e.runStep(ctx, "heartbeat", func() {
+				if err := e.store.UpdateHeartbeat(ctx, e.instanceID); err != nil {
+					fmt.Fprintf(os.Stderr, "heartbeat update failed: %v\n", err)
+				}
+				e.renewClaimLease(ctx)
+				e.touchWorktrees(ctx)
+			})
+		}
+	}
+}
+
+// renewClaimLease extends the lease on the issue being executed, so other
+// workers don't take it over while this executor is alive
+func (e *Executor) renewClaimLease(ctx context.Context) {
+	e.mu.RLock()
+	issueID := e.claimedIssueID
+	e.mu.RUnlock()
+	if issueID == "" {
+		return
+	}
+	if err := e.store.RenewClaimLease(ctx, issueID, e.instanceID, types.DefaultClaimLease); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to renew claim lease on %s: %v\n", issueID, err)
+	}
+}
+
+// holdWorktree has the heartbeat keep a pooled worktree assigned, so the
+// pool's GC doesn't reclaim it while this executor uses it. Call the
+// returned function before releasing it.
+func (e *Executor) holdWorktree(pool *sandbox.WorktreePool, wt *sandbox.PooledWorktree) func() {
+	e.mu.Lock()
+	if e.heldWorktrees == nil {
+		e.heldWorktrees = make(map[*sandbox.PooledWorktree]*sandbox.WorktreePool)
+	}
+	e.heldWorktrees[wt] = pool
+	e.mu.Unlock()
+	return func() {
+		e.mu.Lock()
+		delete(e.heldWorktrees, wt)
+		e.mu.Unlock()
+	}
+}
+
+// touchWorktrees renews the assignment of every worktree this executor holds
+func (e *Executor) touchWorktrees(ctx context.Context) {
+	e.mu.RLock()
+	held := make(map[*sandbox.PooledWorktree]*sandbox.WorktreePool, len(e.heldWorktrees))
+	for wt, pool := range e.heldWorktrees {
+		held[wt] = pool
+	}
+	e.mu.RUnlock()
+	for wt, pool := range held {
+		if err := pool.Touch(ctx, wt); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to renew assignment of worktree %s: %v\n", wt.Path, err)
 		}
 	}
 }
@@ -1237,6 +1596,29 @@ func (e *Executor) IsRunning() bool {
 	return e.running
 }
 
+// setRunningAgent records the agent running for issueID and the function
+// that stops it, or clears it when issueID is empty
+func (e *Executor) setRunningAgent(issueID string, cancel context.CancelFunc) {
+	e.agentMu.Lock()
+	defer e.agentMu.Unlock()
+	e.agentIssueID = issueID
+	e.agentCancel = cancel
+}
+
+// KillAgent stops the agent working on issueID, which the executor then
+// handles as a failed execution. Returns false if this executor isn't
+// running an agent for issueID.
+func (e *Executor) KillAgent(issueID, reason string) bool {
+	e.agentMu.Lock()
+	defer e.agentMu.Unlock()
+	if e.agentCancel == nil || e.agentIssueID != issueID {
+		return false
+	}
+	fmt.Printf("⛔ Stopping agent for %s: %s\n", issueID, reason)
+	e.agentCancel()
+	return true
+}
+
 // MarkInstanceStoppedOnExit marks this executor instance as stopped.
 // This is called via defer to ensure instance is marked stopped even on abnormal exit.
 // It's idempotent - safe to call multiple times.
@@ -1359,11 +1741,35 @@ func (e *Executor) increasePollInterval() {
 	fmt.Printf("Entering steady state: increasing poll interval %v → %v\n", oldInterval, e.currentPollInterval)
 }
 
+// backOffIdle doubles the poll interval, up to IdleBackoffMax, after a poll
+// that found no work. It is tracked apart from steady state, so only finding
+// work (resetIdleBackoff) drops it back.
+func (e *Executor) backOffIdle() {
+	e.steadyStateMutex.Lock()
+	defer e.steadyStateMutex.Unlock()
+
+	if e.idlePollInterval >= e.config.IdleBackoffMax {
+		return
+	}
+	e.idlePollInterval = 2 * max(e.idlePollInterval, e.basePollInterval)
+	if e.idlePollInterval >= e.config.IdleBackoffMax {
+		e.idlePollInterval = e.config.IdleBackoffMax
+		fmt.Printf("Ready queue empty: polling every %v until work turns up\n", e.idlePollInterval)
+	}
+}
+
+// resetIdleBackoff returns to the base poll interval once work turns up
+func (e *Executor) resetIdleBackoff() {
+	e.steadyStateMutex.Lock()
+	defer e.steadyStateMutex.Unlock()
+	e.idlePollInterval = 0
+}
+
 // getCurrentPollInterval returns the current dynamic poll interval (thread-safe)
 func (e *Executor) getCurrentPollInterval() time.Duration {
 	e.steadyStateMutex.RLock()
 	defer e.steadyStateMutex.RUnlock()
-	return e.currentPollInterval
+	return max(e.currentPollInterval, e.idlePollInterval)
 }
 
 // safePrefix returns the first n characters of s, or the entire string if shorter than n.
@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// aiWaitActor is the actor recorded on the labels and comments of issues
+// deferred while the AI provider is down
+const aiWaitActor = "ai-wait"
+
+// checkAIAvailable reports whether the AI provider can take work. While its
+// circuit breaker is open nothing is claimed; once the health check passes
+// again, issues deferred with the waiting-on-ai label are released for retry.
+func (e *Executor) checkAIAvailable(ctx context.Context) bool {
+	if !e.enableAISupervision || e.supervisor == nil {
+		return true
+	}
+
+	err := e.supervisor.HealthCheck(ctx)
+	down := errors.Is(err, ai.ErrCircuitOpen)
+
+	e.mu.Lock()
+	wasDown := e.aiDown
+	e.aiDown = down
+	e.mu.Unlock()
+
+	if down {
+		if !wasDown {
+			fmt.Printf("⏸️  AI provider unavailable, not claiming work until it recovers: %v\n", err)
+		}
+		return false
+	}
+	if wasDown {
+		fmt.Printf("▶️  AI provider recovered, resuming work\n")
+	}
+
+	e.resumeWaitingOnAI(ctx)
+	return true
+}
+
+// deferForAI releases a claimed issue the AI provider is too unhealthy to
+// work on. It is reopened with the waiting-on-ai label, which keeps it out of
+// ready work without counting the attempt as a failure, so an outage doesn't
+// get issues blocked for consecutive failures.
+func (e *Executor) deferForAI(ctx context.Context, issue *types.Issue, cause error) {
+	fmt.Fprintf(os.Stderr, "Deferring %s until the AI provider recovers: %v\n", issue.ID, cause)
+
+	if err := e.store.AddLabel(ctx, issue.ID, types.LabelWaitingOnAI, aiWaitActor); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add %s label to %s: %v\n", types.LabelWaitingOnAI, issue.ID, err)
+	}
+
+	comment := fmt.Sprintf("**Waiting on AI**: the AI provider is unavailable (%v). This issue will be retried automatically once it recovers.", cause)
+	if err := e.store.ReleaseIssueAndReopen(ctx, issue.ID, aiWaitActor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to release and reopen %s: %v\n", issue.ID, err)
+	}
+
+	e.logEvent(ctx, events.EventTypeWaitingOnAI, events.SeverityWarning, issue.ID,
+		fmt.Sprintf("Deferred %s until the AI provider recovers", issue.ID),
+		map[string]interface{}{
+			"action": "deferred",
+			"error":  cause.Error(),
+		})
+}
+
+// resumeWaitingOnAI removes the waiting-on-ai label from deferred issues so
+// they are claimed again
+func (e *Executor) resumeWaitingOnAI(ctx context.Context) {
+	issues, err := e.store.GetIssuesByLabel(ctx, types.LabelWaitingOnAI)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get issues waiting on AI: %v\n", err)
+		return
+	}
+
+	for _, issue := range issues {
+		if err := e.store.RemoveLabel(ctx, issue.ID, types.LabelWaitingOnAI, aiWaitActor); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to remove %s label from %s: %v\n", types.LabelWaitingOnAI, issue.ID, err)
+			continue
+		}
+		if err := e.store.AddComment(ctx, issue.ID, aiWaitActor, "The AI provider recovered; released for retry."); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add comment to %s: %v\n", issue.ID, err)
+		}
+		e.logEvent(ctx, events.EventTypeWaitingOnAI, events.SeverityInfo, issue.ID,
+			fmt.Sprintf("Released %s for retry, the AI provider recovered", issue.ID),
+			map[string]interface{}{
+				"action": "resumed",
+			})
+	}
+}
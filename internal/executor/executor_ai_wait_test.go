@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestDeferForAI(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Add retries", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	e := &Executor{store: store, instanceID: "exec-test", config: &Config{}}
+	instance := &types.ExecutorInstance{
+		InstanceID:    e.instanceID,
+		Hostname:      "host",
+		Status:        types.ExecutorStatusRunning,
+		StartedAt:     time.Now(),
+		LastHeartbeat: time.Now(),
+		Metadata:      "{}",
+	}
+	if err := store.RegisterInstance(ctx, instance); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, e.instanceID); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+
+	e.deferForAI(ctx, issue, fmt.Errorf("AI supervisor unavailable: %w", ai.ErrCircuitOpen))
+
+	// Deferred issues are reopened but kept out of ready work
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Status != types.StatusOpen {
+		t.Errorf("deferred issue status = %s, want open", got.Status)
+	}
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 0 {
+		t.Errorf("ready work = %d issues while waiting on AI, want 0", len(ready))
+	}
+
+	// Deferring doesn't count toward blocking for consecutive failures
+	for i := 0; i < 3; i++ {
+		if err := store.ClaimIssue(ctx, issue.ID, e.instanceID); err != nil {
+			t.Fatalf("ClaimIssue failed: %v", err)
+		}
+		e.deferForAI(ctx, issue, ai.ErrCircuitOpen)
+	}
+	if got, _ := store.GetIssue(ctx, issue.ID); got.Status == types.StatusBlocked {
+		t.Error("repeated deferrals blocked the issue")
+	}
+
+	e.resumeWaitingOnAI(ctx)
+
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if len(labels) != 0 {
+		t.Errorf("labels after recovery = %v, want none", labels)
+	}
+	ready, err = store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != issue.ID {
+		t.Errorf("ready work after recovery = %+v, want %s", ready, issue.ID)
+	}
+
+	recorded, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeWaitingOnAI})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	resumed := 0
+	for _, event := range recorded {
+		if event.Data["action"] == "resumed" {
+			resumed++
+		}
+	}
+	if len(recorded) != 5 || resumed != 1 {
+		t.Errorf("got %d waiting-on-ai events, %d resumed; want 5, 1 resumed", len(recorded), resumed)
+	}
+}
@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// anomalyActor is the actor recorded on the labels, comments and escalation
+// issues of anomaly escalations
+const anomalyActor = "anomaly-detector"
+
+// escalateAnomaly handles an agent stopped for suspicious behavior. Its
+// issue is reopened with the no-auto-claim label so it isn't retried, and an
+// escalation issue discovered from it asks a human to review what the agent
+// did. The agent's changes are left uncommitted for that review.
+func (e *Executor) escalateAnomaly(ctx context.Context, issue *types.Issue, execution *types.Execution, anomaly *AnomalyError) {
+	fmt.Fprintf(os.Stderr, "\n🛑 Agent stopped for suspicious behavior on %s (%s): %s\n", issue.ID, anomaly.Kind, anomaly.Detail)
+
+	if err := e.store.AddLabel(ctx, issue.ID, "no-auto-claim", anomalyActor); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add no-auto-claim label to %s: %v\n", issue.ID, err)
+	}
+
+	escalation := &types.Issue{
+		Title:              fmt.Sprintf("Agent stopped on %s: %s", issue.ID, anomalyTitles[anomaly.Kind]),
+		IssueType:          types.TypeTask,
+		Priority:           issue.Priority,
+		Status:             types.StatusOpen,
+		AcceptanceCriteria: fmt.Sprintf("The agent's changes for %s are reviewed and kept or reverted, and %s is released for another attempt or closed", issue.ID, issue.ID),
+		Description: fmt.Sprintf(`# Suspicious Agent Behavior
+
+The agent working on **%s** (%s) was stopped because its tool calls looked suspicious.
+
+- **Anomaly**: %s
+- **Detail**: %s
+- **Execution**: %d
+
+## What Happened
+The execution was failed before results processing, so nothing the agent changed was committed, but its changes are still in the working tree or sandbox it ran in. %s has the no-auto-claim label so VC won't try again on its own.
+
+## Next Steps
+1. Review the execution's events and output: `+"`vc tail %s`"+`
+2. Keep or revert what the agent changed
+3. Remove the no-auto-claim label from %s if it should be retried, perhaps with clearer instructions
+4. Close this escalation
+`, issue.ID, issue.Title, anomaly.Kind, anomaly.Detail, execution.ID, issue.ID, issue.ID, issue.ID),
+	}
+	if err := e.store.CreateIssue(ctx, escalation, anomalyActor); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create anomaly escalation for %s: %v\n", issue.ID, err)
+		escalation.ID = ""
+	} else {
+		for _, label := range []string{"escalation", "no-auto-claim"} {
+			if err := e.store.AddLabel(ctx, escalation.ID, label, anomalyActor); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to add %s label to %s: %v\n", label, escalation.ID, err)
+			}
+		}
+		dep := &types.Dependency{IssueID: escalation.ID, DependsOnID: issue.ID, Type: types.DepDiscoveredFrom}
+		if err := e.store.AddDependency(ctx, dep, anomalyActor); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add discovered-from dependency: %v\n", err)
+		}
+		fmt.Printf("✓ Created escalation issue: %s\n", escalation.ID)
+	}
+
+	e.logEvent(ctx, events.EventTypeAgentAnomaly, events.SeverityError, issue.ID,
+		fmt.Sprintf("Agent stopped for suspicious behavior (%s): %s", anomaly.Kind, anomaly.Detail),
+		map[string]interface{}{
+			"kind":             anomaly.Kind,
+			"detail":           anomaly.Detail,
+			"execution_id":     execution.ID,
+			"escalation_issue": escalation.ID,
+		})
+
+	if err := e.store.ReleaseIssueAndReopen(ctx, issue.ID, anomalyActor,
+		fmt.Sprintf("**Agent stopped for suspicious behavior** (%s): %s\n\nEscalated in %s; this issue won't be claimed again until the no-auto-claim label is removed.",
+			anomaly.Kind, anomaly.Detail, escalation.ID)); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to release issue %s: %v\n", issue.ID, err)
+	}
+}
+
+// anomalyTitles describe each kind of anomaly in escalation titles
+var anomalyTitles = map[string]string{
+	AnomalyMassDeletion:  "mass file deletion",
+	AnomalyOutOfScope:    "edits far outside the issue's scope",
+	AnomalyRepeatedCall:  "repeated identical tool calls",
+	AnomalyProtectedPath: "modified a protected path",
+}
@@ -97,6 +97,28 @@ func (e *Executor) checkBudgetBeforeWork(ctx context.Context) bool {
 	return true // Proceed with work
 }
 
+// PauseWork stops the executor claiming new work until ResumeWork, while a
+// human looks into reason. Work in progress carries on.
+func (e *Executor) PauseWork(reason string) {
+	e.mu.Lock()
+	e.workPause = reason
+	e.mu.Unlock()
+}
+
+// ResumeWork lets the executor claim work again after PauseWork
+func (e *Executor) ResumeWork() {
+	e.mu.Lock()
+	e.workPause = ""
+	e.mu.Unlock()
+}
+
+// workPaused reports whether claiming new work is paused
+func (e *Executor) workPaused() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.workPause != ""
+}
+
 // GetBudgetStatus returns the current budget status for status commands
 func (e *Executor) GetBudgetStatus() (enabled bool, status string, stats cost.BudgetStats) {
 	if e.costTracker == nil {
@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestEventLoopClaimsIssueOnce runs two executors' event loops against one
+// ready issue: exactly one claims it, with a lease the heartbeat renews
+func TestEventLoopClaimsIssueOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	// A stand-in agent that holds the claim long enough to inspect it
+	bin := t.TempDir()
+	agent := "#!/bin/sh\nsleep 2\n"
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte(agent), 0755); err != nil {
+		t.Fatalf("Failed to write fake agent: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	repo := t.TempDir()
+	if err := setupGitRepo(t, repo); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+
+	var workers []*Executor
+	for i := 0; i < 2; i++ {
+		cfg := DefaultConfig()
+		cfg.Store = store
+		cfg.EnableAISupervision = false
+		cfg.EnableQualityGates = false
+		cfg.EnableQualityGateWorker = false
+		cfg.EnableSandboxes = false
+		cfg.WorkingDir = repo
+		e, err := New(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create executor %d: %v", i+1, err)
+		}
+		instance := &types.ExecutorInstance{
+			InstanceID:    e.instanceID,
+			Hostname:      e.hostname,
+			PID:           e.pid,
+			Status:        types.ExecutorStatusRunning,
+			StartedAt:     time.Now(),
+			LastHeartbeat: time.Now(),
+			Version:       e.version,
+			Metadata:      "{}",
+		}
+		if err := store.RegisterInstance(ctx, instance); err != nil {
+			t.Fatalf("Failed to register executor %d: %v", i+1, err)
+		}
+		workers = append(workers, e)
+	}
+
+	issue := &types.Issue{
+		Title:              "Claimed once",
+		Description:        "Only one worker may execute this",
+		IssueType:          types.TypeTask,
+		Status:             types.StatusOpen,
+		Priority:           1,
+		AcceptanceCriteria: "Exactly one worker claims it",
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	found := make([]bool, len(workers))
+	for i, e := range workers {
+		wg.Add(1)
+		go func(i int, e *Executor) {
+			defer wg.Done()
+			_, found[i] = e.processNextIssue(ctx)
+		}(i, e)
+	}
+
+	// While the agent runs, the winner holds a lease and renews it
+	var winner *Executor
+	deadline := time.Now().Add(10 * time.Second)
+	for winner == nil && time.Now().Before(deadline) {
+		for _, e := range workers {
+			e.mu.RLock()
+			claimed := e.claimedIssueID
+			e.mu.RUnlock()
+			if claimed == issue.ID {
+				winner = e
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if winner == nil {
+		t.Fatal("no worker claimed the issue")
+	}
+	state, err := store.GetExecutionState(ctx, issue.ID)
+	if err != nil || state == nil {
+		t.Fatalf("GetExecutionState() = %v, %v", state, err)
+	}
+	if state.ExecutorInstanceID != winner.instanceID {
+		t.Errorf("claim held by %s, want %s", state.ExecutorInstanceID, winner.instanceID)
+	}
+	if state.LeaseExpiresAt == nil {
+		t.Fatal("claim has no lease")
+	}
+	leased := *state.LeaseExpiresAt
+	time.Sleep(10 * time.Millisecond)
+	winner.renewClaimLease(ctx)
+	if state, err = store.GetExecutionState(ctx, issue.ID); err != nil || state == nil || state.LeaseExpiresAt == nil || !state.LeaseExpiresAt.After(leased) {
+		t.Errorf("heartbeat did not renew the lease: %+v, %v", state, err)
+	}
+
+	wg.Wait()
+	if found[0] == found[1] {
+		t.Errorf("workers found work = %v, want exactly one", found)
+	}
+}
+
+// TestEventLoopClaimsOwnProjectOnly runs an executor confined to one project
+// against another project's ready issue and expired lease: it claims neither
+func TestEventLoopClaimsOwnProjectOnly(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	for _, name := range []string{"web", "api"} {
+		if err := store.SaveProject(ctx, &types.Project{Name: name}); err != nil {
+			t.Fatalf("SaveProject(%s) failed: %v", name, err)
+		}
+	}
+
+	repo := t.TempDir()
+	if err := setupGitRepo(t, repo); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.Store = store
+	cfg.Project = "web"
+	cfg.EnableAISupervision = false
+	cfg.EnableQualityGates = false
+	cfg.EnableQualityGateWorker = false
+	cfg.EnableSandboxes = false
+	cfg.WorkingDir = repo
+	e, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	other := &types.ExecutorInstance{InstanceID: "api-worker", Hostname: "localhost", PID: 1, Status: types.ExecutorStatusRunning,
+		StartedAt: time.Now(), LastHeartbeat: time.Now(), Version: "test", Metadata: "{}"}
+	if err := store.RegisterInstance(ctx, other); err != nil {
+		t.Fatalf("Failed to register instance: %v", err)
+	}
+	var apiIssues []*types.Issue
+	for _, title := range []string{"Leased API task", "Open API task"} {
+		issue := &types.Issue{Title: title, IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 0, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		if err := store.SetIssueProject(ctx, issue.ID, "api", "test"); err != nil {
+			t.Fatalf("SetIssueProject failed: %v", err)
+		}
+		apiIssues = append(apiIssues, issue)
+		if len(apiIssues) == 1 {
+			if claimed, err := store.ClaimNextReadyIssue(ctx, other.InstanceID, time.Millisecond, "api"); err != nil || claimed == nil {
+				t.Fatalf("ClaimNextReadyIssue(api) = %+v, %v", claimed, err)
+			}
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if err, found := e.processNextIssue(ctx); err != nil || found {
+		t.Fatalf("processNextIssue() = %v, found %v; want no work outside project web", err, found)
+	}
+	if issue, _ := store.GetIssue(ctx, apiIssues[1].ID); issue.Status != types.StatusOpen {
+		t.Errorf("api issue was claimed by the web executor: %s", issue.Status)
+	}
+	if state, _ := store.GetExecutionState(ctx, apiIssues[0].ID); state == nil || state.ExecutorInstanceID != other.InstanceID {
+		t.Errorf("expired api lease was taken over by the web executor: %+v", state)
+	}
+}
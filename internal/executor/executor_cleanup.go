@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime/debug"
 	"time"
 
 	"github.com/steveyegge/vc/internal/config"
@@ -69,6 +70,12 @@ func (e *Executor) cleanupLoop(ctx context.Context) {
 			// Use a channel to make cleanup interruptible
 			done := make(chan error, 1)
 			go func() {
+				// A panic is reported as a cleanup error, so the loop isn't left waiting
+				defer func() {
+					if r := recover(); r != nil {
+						done <- e.reportPanic(ctx, "cleanup", nil, r, debug.Stack())
+					}
+				}()
 				staleThresholdSecs := int(e.staleThreshold.Seconds())
 				cleaned, err := e.store.CleanupStaleInstances(ctx, staleThresholdSecs)
 				if err != nil {
@@ -170,9 +177,11 @@ func (e *Executor) eventCleanupLoop(ctx context.Context) {
 
 			// Run cleanup directly (blocking) - it's okay to block the loop
 			// since cleanup should be relatively quick and we want clean shutdown
-			if err := e.runEventCleanup(ctx, retentionCfg); err != nil {
-				fmt.Fprintf(os.Stderr, "event cleanup: error during cleanup: %v\n", err)
-			}
+			e.runStep(ctx, "event cleanup", func() {
+				if err := e.runEventCleanup(ctx, retentionCfg); err != nil {
+					fmt.Fprintf(os.Stderr, "event cleanup: error during cleanup: %v\n", err)
+				}
+			})
 		}
 	}
 }
@@ -220,8 +229,26 @@ func (e *Executor) runEventCleanup(ctx context.Context, cfg config.EventRetentio
 
 	totalDeleted := timeBasedDeleted + perIssueDeleted + globalLimitDeleted
 
-	// Step 4: Optional VACUUM to reclaim disk space
-	if cfg.CleanupVacuum && totalDeleted > 0 {
+	// Step 4: Compact issue events of long-closed issues into summaries
+	// Failures here don't affect the agent event cleanup above
+	issueEventsCompacted := 0
+	if cfg.IssueEventRetentionDays > 0 {
+		compacted, err := e.store.CompactIssueEvents(ctx, cfg.IssueEventRetentionDays, cfg.CleanupBatchSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "event cleanup: warning: issue event compaction failed: %v\n", err)
+		}
+		issueEventsCompacted = compacted
+	}
+
+	// Step 5: Delete the stored agent output of old executions, which is
+	// kept for 'vc tail' as long as regular events are
+	outputDeleted, err := e.store.CleanupExecutionOutput(ctx, cfg.RetentionDays)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "event cleanup: warning: execution output cleanup failed: %v\n", err)
+	}
+
+	// Step 6: Optional VACUUM to reclaim disk space
+	if cfg.CleanupVacuum && totalDeleted+issueEventsCompacted+outputDeleted > 0 {
 		if err := e.store.VacuumDatabase(ctx); err != nil {
 			// Don't fail the whole cleanup if VACUUM fails
 			fmt.Fprintf(os.Stderr, "event cleanup: warning: VACUUM failed: %v\n", err)
@@ -253,6 +280,14 @@ func (e *Executor) runEventCleanup(ctx context.Context, cfg config.EventRetentio
 		}
 		fmt.Printf(" (remaining=%d)\n", eventsRemaining)
 	}
+	if issueEventsCompacted > 0 {
+		fmt.Printf("Event cleanup: Compacted %d issue events of issues closed over %d days ago\n",
+			issueEventsCompacted, cfg.IssueEventRetentionDays)
+	}
+	if outputDeleted > 0 {
+		fmt.Printf("Event cleanup: Deleted %d lines of agent output of executions finished over %d days ago\n",
+			outputDeleted, cfg.RetentionDays)
+	}
 
 	return nil
 }
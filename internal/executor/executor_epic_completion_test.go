@@ -1,8 +1,14 @@
 package executor
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/steveyegge/vc/internal/labels"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/storage/memory"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -423,3 +429,70 @@ func TestCheckEpicCompletion_EventLogging(t *testing.T) {
 
 	t.Log("✓ Epic completion detected and label added correctly")
 }
+
+// failingLabelStore fails adding a label inside transactions, after the
+// transaction has already closed the issue
+type failingLabelStore struct {
+	storage.Storage
+}
+
+func (s *failingLabelStore) RunInVCTransaction(ctx context.Context, fn func(tx *storage.VCTransaction) error) error {
+	return s.Storage.RunInVCTransaction(ctx, func(tx *storage.VCTransaction) error {
+		return fn(beads.NewVCTransaction(&failingLabelTx{tx}))
+	})
+}
+
+type failingLabelTx struct {
+	*storage.VCTransaction
+}
+
+func (t *failingLabelTx) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	return errors.New("injected failure")
+}
+
+// TestCheckEpicCompletion_CloseIsAtomic tests that a mission whose state
+// transition fails is not left closed without reaching quality gates
+func TestCheckEpicCompletion_CloseIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.New()
+	store := &failingLabelStore{mem}
+
+	mission := &types.Issue{Title: "Ship auth", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic, IssueSubtype: types.SubtypeMission}
+	task := &types.Issue{Title: "Add login", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	for _, issue := range []*types.Issue{mission, task} {
+		if err := mem.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+	}
+	if err := mem.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: mission.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	if err := mem.CloseIssue(ctx, task.ID, "completed", "test"); err != nil {
+		t.Fatalf("Failed to close task: %v", err)
+	}
+
+	closed, err := checkAndCloseEpicIfComplete(ctx, store, nil, "exec-1", mission.ID)
+	if err == nil || closed {
+		t.Fatalf("expected the close to fail, got closed=%v err=%v", closed, err)
+	}
+
+	got, err := mem.GetIssue(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("Failed to get mission: %v", err)
+	}
+	if got.Status == types.StatusClosed {
+		t.Error("mission was closed although its state transition failed")
+	}
+	if has, _ := labels.HasLabel(ctx, mem, mission.ID, labels.LabelNeedsQualityGates); has {
+		t.Error("mission has needs-quality-gates label although the transaction failed")
+	}
+
+	// With the failure gone, the next check closes it and moves it on
+	closed, err = checkAndCloseEpicIfComplete(ctx, mem, nil, "exec-1", mission.ID)
+	if err != nil || !closed {
+		t.Fatalf("expected the mission to close, got closed=%v err=%v", closed, err)
+	}
+	if has, _ := labels.HasLabel(ctx, mem, mission.ID, labels.LabelNeedsQualityGates); !has {
+		t.Error("closed mission is missing the needs-quality-gates label")
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/tracing"
 	"github.com/steveyegge/vc/internal/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // eventLoop is the main event loop that processes issues
@@ -26,9 +28,28 @@ func (e *Executor) eventLoop(ctx context.Context) {
 		case <-e.stopCh:
 			return
 		case <-nextPoll:
+			e.mu.Lock()
+			e.lastPoll = time.Now()
+			e.mu.Unlock()
+
 			// Track if work was found in this iteration
 			foundWork := false
 
+			// Claim nothing while paused for a human, e.g. for runaway spend
+			if e.workPaused() {
+				e.checkAndUpdateSteadyState(ctx, false)
+				nextPoll = time.After(e.getCurrentPollInterval())
+				continue
+			}
+
+			// Claim nothing while the AI provider is down; issues deferred
+			// meanwhile are released for retry once it recovers
+			if !e.checkAIAvailable(ctx) {
+				e.checkAndUpdateSteadyState(ctx, false)
+				nextPoll = time.After(e.getCurrentPollInterval())
+				continue
+			}
+
 			// Check budget before processing work (vc-e3s7)
 			// If budget exceeded, pause and skip this cycle
 			if !e.checkBudgetBeforeWork(ctx) {
@@ -40,7 +61,10 @@ func (e *Executor) eventLoop(ctx context.Context) {
 
 			// Process one code work issue (regular tasks)
 			// Note: Heartbeat updates now happen in dedicated heartbeatLoop() goroutine (vc-m4od)
-			err, workFound := e.processNextIssue(ctx)
+			// A panic in any step is recovered and reported, and the loop goes on
+			var err error
+			var workFound bool
+			e.runStep(ctx, "event loop", func() { err, workFound = e.processNextIssue(ctx) })
 			if err != nil {
 				// Log error but continue
 				fmt.Fprintf(os.Stderr, "error processing issue: %v\n", err)
@@ -49,7 +73,9 @@ func (e *Executor) eventLoop(ctx context.Context) {
 
 			// Process one QA work issue (quality gates for missions) (vc-254)
 			if e.enableQualityGateWorker && e.qaWorker != nil {
-				if err := e.processNextQAWork(ctx); err != nil {
+				var err error
+				e.runStep(ctx, "quality gate worker", func() { err = e.processNextQAWork(ctx) })
+				if err != nil {
 					// Log error but continue
 					fmt.Fprintf(os.Stderr, "error processing QA work: %v\n", err)
 				}
@@ -57,7 +83,9 @@ func (e *Executor) eventLoop(ctx context.Context) {
 
 			// Check health monitors after completing an issue (if enabled)
 			if e.enableHealthMonitoring && e.healthRegistry != nil {
-				if err := e.checkHealthMonitors(ctx); err != nil {
+				var err error
+				e.runStep(ctx, "health monitors", func() { err = e.checkHealthMonitors(ctx) })
+				if err != nil {
 					// Log error but continue
 					fmt.Fprintf(os.Stderr, "error running health monitors: %v\n", err)
 				}
@@ -75,6 +103,12 @@ func (e *Executor) eventLoop(ctx context.Context) {
 // checkAndUpdateSteadyState checks if we're in steady state and adjusts poll interval
 // vc-onch: Steady state detection and exponential backoff
 func (e *Executor) checkAndUpdateSteadyState(ctx context.Context, foundWork bool) {
+	if foundWork {
+		e.resetIdleBackoff()
+	} else if e.config.IdleBackoffMax > 0 {
+		e.backOffIdle()
+	}
+
 	inSteadyState, err := e.checkSteadyState(ctx, foundWork)
 	if err != nil {
 		// Log error but don't fail the loop
@@ -89,6 +123,11 @@ func (e *Executor) checkAndUpdateSteadyState(ctx context.Context, foundWork bool
 
 // processNextQAWork attempts to claim and process one mission that needs quality gates (vc-254)
 func (e *Executor) processNextQAWork(ctx context.Context) error {
+	// Gates need disk and file descriptors as much as agents do
+	if !e.checkResources(ctx) {
+		return nil
+	}
+
 	// Try to claim a mission needing quality gates
 	mission, err := e.qaWorker.ClaimReadyWork(ctx)
 	if err != nil {
@@ -103,8 +142,11 @@ func (e *Executor) processNextQAWork(ctx context.Context) error {
 	// Execute quality gates in background goroutine to enable parallelism
 	// This allows code workers to continue working while gates run
 	e.qaWorkersWg.Add(1) // Track goroutine for graceful shutdown (vc-0d58)
+	e.activeQAWorkers.Add(1)
 	go func() {
 		defer e.qaWorkersWg.Done() // Release goroutine tracker (vc-0d58)
+		defer e.activeQAWorkers.Add(-1)
+		defer e.recoverPanic(ctx, "quality gate worker", nil)
 		if err := e.qaWorker.Execute(ctx, mission); err != nil {
 			// Log error - QA worker handles state transitions internally
 			fmt.Fprintf(os.Stderr, "QA worker execution failed for %s: %v\n", mission.ID, err)
@@ -252,6 +294,12 @@ func (e *Executor) getNextReadyBlocker(ctx context.Context) (*types.Issue, error
 // vc-a6ko: Refactored to use GetReadyWork() with smart fallback chain for self-healing mode
 // vc-onch: Returns (error, foundWork bool) to support steady state detection
 func (e *Executor) processNextIssue(ctx context.Context) (error, bool) {
+	// Agents and gates fail in confusing ways when the disk fills up, so
+	// claim nothing while disk, temp space or file descriptors are low
+	if !e.checkResources(ctx) {
+		return nil, false
+	}
+
 	// vc-196: Run preflight quality gates check before claiming work
 	if e.preFlightChecker != nil {
 		// vc-onch: Don't invalidate cache on every poll - this causes thrashing
@@ -265,6 +313,12 @@ func (e *Executor) processNextIssue(ctx context.Context) (error, bool) {
 			return nil, false
 		}
 
+		// A commit from an execution that broke a passing baseline is reverted
+		// rather than fixed forward; recheck the baseline next poll
+		if !allPassed && e.tryAutoRollback(ctx, commitHash) {
+			return nil, false
+		}
+
 		if !allPassed {
 			// Baseline failed - enter self-healing mode
 			failureMode := e.preFlightChecker.config.FailureMode
@@ -324,23 +378,70 @@ func (e *Executor) processNextIssue(ctx context.Context) (error, bool) {
 	}
 
 	// Get next ready work using smart selection
-	issue, err := e.GetReadyWork(ctx)
+	issue, fromQueue, err := e.selectWork(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get ready work: %w", err), false
 	}
 
-	// No work available
+	// Work from the regular queue is claimed atomically by the store, which
+	// also takes over issues whose claim lease expired with their executor.
+	// With nothing ready, that may still find an expired lease to take over.
+	if fromQueue {
+		claimCtx, claimSpan := tracing.Start(ctx, "vc.claim")
+		issue, err = e.store.ClaimNextReadyIssue(claimCtx, e.instanceID, types.DefaultClaimLease, "")
+		tracing.End(claimSpan, err)
+		if err != nil {
+			return fmt.Errorf("failed to claim ready work: %w", err), false
+		}
+		if issue == nil {
+			return nil, false
+		}
+		return e.executeClaimedIssue(ctx, issue), true
+	}
 	if issue == nil {
 		return nil, false
 	}
 
-	// Attempt to claim the issue
-	if err := e.store.ClaimIssue(ctx, issue.ID, e.instanceID); err != nil {
+	// Blockers and self-healing picks are claimed by ID, then leased
+	claimCtx, claimSpan := tracing.Start(ctx, "vc.claim")
+	err = e.store.ClaimIssue(claimCtx, issue.ID, e.instanceID)
+	if err == nil {
+		err = e.store.RenewClaimLease(claimCtx, issue.ID, e.instanceID, types.DefaultClaimLease)
+		if err != nil {
+			// Claimed without a lease: recovery falls back to the heartbeat
+			fmt.Fprintf(os.Stderr, "warning: failed to lease claim on %s: %v\n", issue.ID, err)
+			err = nil
+		}
+	}
+	tracing.End(claimSpan, err)
+	if err != nil {
 		// Issue may have been claimed by another executor
 		// This is expected in multi-executor scenarios
 		return nil, false
 	}
 
-	// Successfully claimed - now execute it
-	return e.executeIssue(ctx, issue), true
+	return e.executeClaimedIssue(ctx, issue), true
+}
+
+// executeClaimedIssue executes an issue this executor has claimed, renewing
+// its claim lease from the heartbeat loop until the execution is over
+func (e *Executor) executeClaimedIssue(ctx context.Context, issue *types.Issue) error {
+	// Trace the execution end to end, from claiming the issue on
+	ctx, span := tracing.Start(ctx, "vc.execution",
+		attribute.String(tracing.AttrIssueID, issue.ID),
+		attribute.String(tracing.AttrIssueType, string(issue.IssueType)),
+		attribute.String(tracing.AttrExecutorID, e.instanceID))
+
+	e.mu.Lock()
+	e.claimedIssueID = issue.ID
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.claimedIssueID = ""
+		e.mu.Unlock()
+	}()
+
+	err := e.executeIssueRecovered(ctx, issue)
+	tracing.End(span, err)
+	return err
 }
@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -9,14 +10,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/steveyegge/vc/internal/ai"
 	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
 	"github.com/steveyegge/vc/internal/iterative"
+	"github.com/steveyegge/vc/internal/logging"
 	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/tracing"
 	"github.com/steveyegge/vc/internal/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // executeIssue executes a single issue by spawning a coding agent
 func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 	fmt.Printf("Executing issue %s: %s\n", issue.ID, issue.Title)
+	ctx = logging.With(ctx, logging.KeyIssueID, issue.ID)
 
 	// Check if bootstrap mode should be activated (vc-b027)
 	bootstrapMode, bootstrapReason := e.ShouldUseBootstrapMode(ctx, issue)
@@ -67,6 +73,12 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 	// Pre-flight health check: verify AI supervisor is healthy before proceeding (vc-182)
 	if e.enableAISupervision && e.supervisor != nil {
 		if err := e.supervisor.HealthCheck(ctx); err != nil {
+			// The provider is down: wait for it rather than count a failure
+			if errors.Is(err, ai.ErrCircuitOpen) {
+				e.deferForAI(context.Background(), issue, err)
+				e.getMonitor().EndExecution(false, false)
+				return nil
+			}
 			// Circuit breaker is open or API is unhealthy - fail fast
 			fmt.Fprintf(os.Stderr, "AI supervisor health check failed: %v\n", err)
 			e.logEvent(ctx, events.EventTypeAssessmentCompleted, events.SeverityError, issue.ID,
@@ -95,6 +107,7 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 
 		// Track assessment phase duration
 		assessStart := time.Now()
+		assessCtx, assessSpan := tracing.Start(ctx, "vc.assess")
 		var err error
 
 		// vc-43kd: Use iterative refinement if enabled for complex/high-risk issues
@@ -103,7 +116,7 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			collector := iterative.NewInMemoryMetricsCollector()
 
 			var refinementResult *iterative.ConvergenceResult
-			assessment, refinementResult, err = e.supervisor.AssessIssueStateWithRefinement(ctx, issue, collector)
+			assessment, refinementResult, err = e.supervisor.AssessIssueStateWithRefinement(assessCtx, issue, collector)
 
 			e.getMonitor().RecordPhaseDuration("assess", time.Since(assessStart))
 
@@ -126,9 +139,10 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			}
 		} else {
 			// Fall back to single-pass assessment
-			assessment, err = e.supervisor.AssessIssueState(ctx, issue)
+			assessment, err = e.supervisor.AssessIssueState(assessCtx, issue)
 			e.getMonitor().RecordPhaseDuration("assess", time.Since(assessStart))
 		}
+		tracing.End(assessSpan, err)
 		if err != nil {
 			// Check if context was canceled (shutdown initiated)
 			if ctx.Err() != nil {
@@ -311,6 +325,58 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		}
 	}
 
+	// Phase 2.05: Outside a sandbox, keep the agent's edits apart from
+	// uncommitted human changes in the working directory: refuse to run,
+	// stash them until the execution is over, or run in a separate worktree
+	isolated := false
+	if sb == nil {
+		dir, restore, err := e.protectDirtyWorkspace(ctx, issue, workingDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Not running agent: %v\n", err)
+			e.releaseIssueWithError(ctx, issue.ID, fmt.Sprintf("Working directory not ready: %v", err))
+			e.getMonitor().EndExecution(false, false)
+			return err
+		}
+		defer restore()
+		isolated = dir != workingDir
+		workingDir = dir
+	}
+
+	// Phase 2.1: Outside a sandbox, work on the issue's own branch rather than
+	// the checked-out one. Work merges back only if it's accepted below.
+	// An isolated worktree is on the issue's branch already.
+	var issueBranch *git.IssueBranch
+	mergeIssueBranch := false
+	if sb == nil && !isolated && e.workflow != nil {
+		branch, err := e.workflow.Start(ctx, issue.ID, issue.Title)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to start issue branch: %v (continuing on the current branch)\n", err)
+		} else {
+			issueBranch = branch
+			fmt.Printf("Working on branch %s (from %s)\n", issueBranch.Name, issueBranch.BaseBranch)
+			defer func() {
+				e.finishIssueBranch(ctx, issue, issueBranch, mergeIssueBranch)
+			}()
+		}
+	}
+
+	// Phase 2.2: In patch-proposal mode, the agent runs in a scratch worktree
+	// and its changes reach workingDir only as a reviewed patch (Phase 3.0)
+	agentDir := workingDir
+	var proposal *patchProposal
+	if sb == nil && e.config.PatchProposal.Enabled && e.gitOps != nil {
+		p, err := e.startPatchProposal(ctx, issue, workingDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Not running agent: %v\n", err)
+			e.releaseIssueWithError(ctx, issue.ID, fmt.Sprintf("Failed to prepare patch-proposal worktree: %v", err))
+			e.getMonitor().EndExecution(false, false)
+			return err
+		}
+		proposal = p
+		defer proposal.release(ctx)
+		agentDir = proposal.Dir()
+	}
+
 	// Phase 2.5: Diagnose baseline test failures (vc-230)
 	// If this is a baseline test issue, use AI to diagnose the failure
 	// vc-261: Use IsBaselineIssue() helper instead of duplicated map
@@ -423,6 +489,8 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		e.watchdog.SetAgentContext(issue.ID, agentCancel)
 		defer e.watchdog.ClearAgentContext()
 	}
+	e.setRunningAgent(issue.ID, agentCancel)
+	defer e.setRunningAgent("", nil)
 
 	// Gather context for comprehensive prompt
 	gatherer := NewContextGatherer(e.store)
@@ -444,6 +512,15 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		promptCtx.ResumeHint = resumeContext
 	}
 
+	// Tell the agent which paths Git LFS tracks, so it leaves them alone
+	if e.config.LargeFiles.Enabled {
+		patterns, err := git.LFSPatterns(agentDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v (not listing LFS paths in the prompt)\n", err)
+		}
+		promptCtx.LFSPatterns = patterns
+	}
+
 	// Build comprehensive prompt using PromptBuilder
 	builder, err := NewPromptBuilder()
 	if err != nil {
@@ -479,9 +556,15 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 	// Generate a unique agent ID for this execution
 	agentID := uuid.New().String()
 
+	// Claude Code is the primary agent worker (vc-q788) unless configured
+	// otherwise, e.g. by a profile
+	agentType := AgentTypeClaudeCode
+	if e.config != nil && e.config.AgentType != "" {
+		agentType = e.config.AgentType
+	}
 	agentCfg := AgentConfig{
-		Type:       AgentTypeClaudeCode, // Use Claude Code as primary agent worker (vc-q788)
-		WorkingDir: workingDir,
+		Type:       agentType,
+		WorkingDir: agentDir,
 		Issue:      issue,
 		StreamJSON: true, // Enable --output-format stream-json for structured events (vc-q788)
 		Timeout:    30 * time.Minute,
@@ -492,10 +575,31 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		Monitor:      e.getMonitor(), // Pass monitor for watchdog visibility (vc-118)
 		Sandbox:      sb,
 		InterruptMgr: e.interruptMgr, // Pass interrupt manager for graceful pause (vc-d25s)
+		Anomalies:    e.config.AgentAnomaly,
+		PathScope:    promptCtx.PathScope,
 	}
 
+	execution := e.startExecution(ctx, issue, agentCfg.Type, prompt, agentDir)
+	// Edits outside the path scope are judged against the working tree the
+	// agent's changes land in, as it was before they did
+	var scopeBaseline *types.WorkspaceSnapshot
+	if len(promptCtx.PathScope) > 0 {
+		scopeBaseline = execution.StartSnapshot
+		if agentDir != workingDir {
+			scopeBaseline = e.snapshotWorkspace(ctx, workingDir)
+		}
+	}
+	agentCfg.ExecutionID = execution.ID
+	if execution.ID != 0 {
+		ctx = logging.With(ctx, logging.KeyExecutionID, execution.ID)
+		agentCtx = logging.With(agentCtx, logging.KeyExecutionID, execution.ID)
+		tracing.SetAttributes(ctx, attribute.Int64(tracing.AttrExecutionID, execution.ID))
+	}
+	agentCtx, agentSpan := tracing.Start(agentCtx, "vc.agent", attribute.String(tracing.AttrAgent, string(agentCfg.Type)))
 	agent, err := SpawnAgent(agentCtx, agentCfg, prompt)
 	if err != nil {
+		tracing.End(agentSpan, err)
+		e.finishExecution(ctx, execution, types.ExecutionFailed, nil, fmt.Sprintf("failed to spawn agent: %v", err))
 		// Log agent spawn failure BEFORE releasing issue
 		e.logEvent(ctx, events.EventTypeAgentSpawned, events.SeverityError, issue.ID,
 			fmt.Sprintf("Failed to spawn agent: %v", err),
@@ -523,9 +627,16 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 	execStart := time.Now()
 	result, err := agent.Wait(agentCtx)
 	e.getMonitor().RecordPhaseDuration("execute", time.Since(execStart))
+	if result != nil {
+		agentSpan.SetAttributes(attribute.Bool("vc.agent.success", result.Success), attribute.Int("vc.agent.exit_code", result.ExitCode))
+	}
+	tracing.End(agentSpan, err)
+	// Snapshot what the agent left behind before results processing commits it
+	execution.EndSnapshot = e.snapshotWorkspace(ctx, agentDir)
 	if err != nil {
 		// Check if this was an interrupt (vc-d25s)
 		if err.Error() == "agent interrupted by user request" {
+			e.finishExecution(ctx, execution, types.ExecutionInterrupted, result, "")
 			fmt.Printf("⏸️  Agent interrupted during execution - pausing task\n")
 			if err := e.interruptMgr.SaveInterruptContext(ctx, issue, "control-cli", "user requested pause", "during_execution"); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to save interrupt context: %v\n", err)
@@ -539,6 +650,17 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			return nil
 		}
 
+		e.finishExecution(ctx, execution, types.ExecutionFailed, result, fmt.Sprintf("agent execution failed: %v", err))
+
+		// An agent stopped for suspicious behavior isn't retried: its issue
+		// waits for a human instead
+		var anomaly *AnomalyError
+		if errors.As(err, &anomaly) {
+			e.escalateAnomaly(ctx, issue, execution, anomaly)
+			e.getMonitor().EndExecution(false, false)
+			return fmt.Errorf("agent execution failed: %w", err)
+		}
+
 		// Log agent execution failure BEFORE releasing issue
 		e.logEvent(ctx, events.EventTypeAgentCompleted, events.SeverityError, issue.ID,
 			fmt.Sprintf("Agent execution failed: %v", err),
@@ -564,6 +686,7 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 
 	// Checkpoint 3: Check for interrupt before analysis (vc-d25s)
 	if e.interruptMgr != nil && e.interruptMgr.IsInterruptRequested() {
+		e.finishExecution(ctx, execution, types.ExecutionInterrupted, result, "")
 		fmt.Printf("⏸️  Interrupt detected before analysis - pausing task\n")
 		if err := e.interruptMgr.SaveInterruptContext(ctx, issue, "control-cli", "user requested pause", "before_analysis"); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to save interrupt context: %v\n", err)
@@ -577,6 +700,18 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		return nil
 	}
 
+	// Phase 3.0: In patch-proposal mode, review the agent's changes and
+	// apply them to workingDir, or stop here if they're rejected
+	if proposal != nil {
+		if err := e.applyPatchProposal(ctx, issue, proposal, workingDir); err != nil {
+			e.finishExecution(ctx, execution, types.ExecutionFailed, result, err.Error())
+			e.releaseIssueWithError(ctx, issue.ID, fmt.Sprintf("Agent's changes were not applied: %v", err))
+			e.getMonitor().EndExecution(false, false)
+			return err
+		}
+		execution.EndSnapshot = e.snapshotWorkspace(ctx, workingDir)
+	}
+
 	// Phase 3: Process results using ResultsProcessor
 	// This handles AI analysis, quality gates, discovered issues, and tracker updates
 
@@ -585,6 +720,12 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		fmt.Sprintf("Starting results processing for issue %s", issue.ID),
 		map[string]interface{}{})
 
+	// Pull requests target the branch the issue branch was started from
+	prBaseBranch := e.config.DefaultBranch
+	if issueBranch != nil {
+		prBaseBranch = issueBranch.BaseBranch
+	}
+
 	// Use shared deduplicator instance (vc-137)
 	// Created once in New() and reused for both sandbox manager and results processor
 	processor, err := NewResultsProcessor(&ResultsProcessorConfig{
@@ -596,6 +737,22 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		EnableQualityGates: e.enableQualityGates,
 		EnableAutoCommit:   e.config.EnableAutoCommit, // Auto-commit configuration (vc-142)
 		EnableAutoPR:       e.config.EnableAutoPR,     // Auto-PR configuration (vc-389e)
+		Hosting:            e.config.Hosting,
+		PRBaseBranch:       prBaseBranch,
+		AutoCommitPaths:        e.config.AutoCommitPaths,
+		AutoCommitExcludePaths: e.config.AutoCommitExcludePaths,
+		AutoCommitAmendOnRetry: e.config.AutoCommitAmendOnRetry,
+		CommitSigning:          e.config.CommitSigning,
+		CommitAttribution:      e.config.CommitAttribution,
+		PushChecks:             e.config.PushChecks,
+		PathScope:              promptCtx.PathScope,
+		ScopeViolation:         e.config.PathScope.OnViolation,
+		ScopeBaseline:          scopeBaseline,
+		Reviewers:              e.config.Reviewers,
+		LargeFiles:             e.config.LargeFiles,
+		Submodules:             e.config.Submodules,
+		PushRetry:              e.config.PushRetry,
+		Execution:              execution,
 		WorkingDir:         workingDir,                // Use sandbox path if sandboxing is enabled (vc-117)
 		Actor:              e.instanceID,
 		Sandbox:            sb,           // Pass sandbox for status tracking (vc-134)
@@ -607,6 +764,8 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		BootstrapMode:        bootstrapMode, // Bootstrap mode for quota crisis (vc-b027)
 	})
 	if err != nil {
+		e.finishExecution(ctx, execution, types.ExecutionFailed, result, fmt.Sprintf("failed to create results processor: %v", err))
+
 		// Log results processing failure BEFORE releasing issue
 		e.logEvent(ctx, events.EventTypeResultsProcessingCompleted, events.SeverityError, issue.ID,
 			fmt.Sprintf("Results processor creation failed: %v", err),
@@ -620,8 +779,12 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		return fmt.Errorf("failed to create results processor: %w", err)
 	}
 
-	procResult, err := processor.ProcessAgentResult(ctx, issue, result)
+	resultsCtx, resultsSpan := tracing.Start(ctx, "vc.results")
+	procResult, err := processor.ProcessAgentResult(resultsCtx, issue, result)
+	tracing.End(resultsSpan, err)
 	if err != nil {
+		e.finishExecution(ctx, execution, types.ExecutionFailed, result, fmt.Sprintf("failed to process results: %v", err))
+
 		// Log results processing failure BEFORE releasing issue
 		e.logEvent(ctx, events.EventTypeResultsProcessingCompleted, events.SeverityError, issue.ID,
 			fmt.Sprintf("Results processing failed: %v", err),
@@ -646,9 +809,27 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			"commit_hash":       procResult.CommitHash,
 		})
 
+	execution.CommitHash = procResult.CommitHash
+	// Merge the issue branch only for finished, committed work that passed gates and review.
+	// Work with a pull request is merged through it instead.
+	mergeIssueBranch = procResult.Completed && procResult.GatesPassed && procResult.CommitHash != "" && !procResult.NeedsReview &&
+		procResult.PRURL == ""
+	if procResult.Completed && result.Success {
+		e.finishExecution(ctx, execution, types.ExecutionSucceeded, result, "")
+	} else {
+		e.finishExecution(ctx, execution, types.ExecutionIncomplete, result, "")
+	}
+
 	// Print summary
 	fmt.Println(procResult.Summary)
 
+	// Work committed straight onto the checked-out branch has landed; work on
+	// an issue branch lands when the branch is merged (see finishIssueBranch)
+	if mergeIssueBranch && issueBranch == nil && sb == nil && !isolated {
+		e.tryAutoBackport(ctx, issue)
+		e.tryAutoRelease(ctx, issue)
+	}
+
 	// vc-154: Check mission convergence if this was a blocker and completed successfully
 	if procResult.Completed && result.Success {
 		if err := e.checkMissionConvergence(ctx, issue); err != nil {
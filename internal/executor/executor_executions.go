@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// startExecution records a running execution of issue by provider on prompt,
+// with a snapshot of the workspace in workingDir the agent starts from.
+// Recording is best effort: if it fails, the returned execution has no ID
+// and finishExecution skips it, so history never stops work.
+func (e *Executor) startExecution(ctx context.Context, issue *types.Issue, provider AgentType, prompt, workingDir string) *types.Execution {
+	execution := &types.Execution{
+		IssueID:            issue.ID,
+		ExecutorInstanceID: e.instanceID,
+		AgentProvider:      string(provider),
+		PromptHash:         types.PromptHash(prompt),
+		Status:             types.ExecutionRunning,
+		StartedAt:          time.Now(),
+		StartSnapshot:      e.snapshotWorkspace(ctx, workingDir),
+	}
+	if err := e.store.CreateExecution(ctx, execution); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record execution of %s: %v\n", issue.ID, err)
+	}
+	return execution
+}
+
+// finishExecution records how an execution ended. result is the agent's
+// result if it ran; errMsg says why a failed execution failed.
+func (e *Executor) finishExecution(ctx context.Context, execution *types.Execution, status types.ExecutionStatus, result *AgentResult, errMsg string) {
+	if execution.ID == 0 {
+		return
+	}
+	now := time.Now()
+	execution.Status = status
+	execution.CompletedAt = &now
+	execution.Error = errMsg
+	if result != nil {
+		exitCode := result.ExitCode
+		execution.ExitCode = &exitCode
+		execution.AgentDuration = result.Duration
+		execution.CostUSD = result.CostUSD()
+	}
+	if err := e.store.UpdateExecution(ctx, execution); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record outcome of execution %d: %v\n", execution.ID, err)
+	}
+}
+
+// snapshotWorkspace snapshots the git working tree in dir, or returns nil if
+// git operations are disabled or the snapshot fails
+func (e *Executor) snapshotWorkspace(ctx context.Context, dir string) *types.WorkspaceSnapshot {
+	if e.gitOps == nil {
+		return nil
+	}
+	snapshot, err := e.gitOps.Snapshot(ctx, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to snapshot workspace %s: %v\n", dir, err)
+		return nil
+	}
+	return snapshot
+}
+
+// CostUSD returns the cost the agent reported for its run, or 0 if it
+// reported none
+func (r *AgentResult) CostUSD() float64 {
+	var cost float64
+	for _, msg := range r.ParsedJSON {
+		if msg.Type == "result" {
+			cost += msg.TotalCostUSD
+		}
+	}
+	return cost
+}
+
+// Model returns the model the agent reported running, or "" if it reported
+// none
+func (r *AgentResult) Model() string {
+	for _, msg := range r.ParsedJSON {
+		if msg.Model != "" {
+			return msg.Model
+		}
+		if msg.Message != nil && msg.Message.Model != "" {
+			return msg.Message.Model
+		}
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestExecutionRecording(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{
+		Title:              "Record executions",
+		IssueType:          types.TypeTask,
+		Status:             types.StatusOpen,
+		Priority:           1,
+		AcceptanceCriteria: "Executions are recorded",
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create git operations: %v", err)
+	}
+
+	e := &Executor{store: store, instanceID: "exec-test", gitOps: gitOps}
+	execution := e.startExecution(ctx, issue, AgentTypeClaudeCode, "prompt", repoDir)
+	if execution.ID == 0 {
+		t.Fatal("expected the execution to be recorded")
+	}
+	if execution.StartSnapshot == nil || execution.StartSnapshot.CommitSHA == "" || !execution.StartSnapshot.IsClean() {
+		t.Fatalf("expected a clean start snapshot, got %v", execution.StartSnapshot)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "new.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	execution.EndSnapshot = e.snapshotWorkspace(ctx, repoDir)
+	if execution.EndSnapshot == nil || execution.EndSnapshot.IsClean() {
+		t.Fatalf("expected a dirty end snapshot, got %v", execution.EndSnapshot)
+	}
+
+	result := &AgentResult{
+		Success:  true,
+		ExitCode: 0,
+		Duration: 3 * time.Minute,
+		ParsedJSON: []AgentMessage{
+			{Type: "assistant"},
+			{Type: "result", TotalCostUSD: 0.42},
+		},
+	}
+	execution.CommitHash = "abc123"
+	e.finishExecution(ctx, execution, types.ExecutionSucceeded, result, "")
+
+	got, err := store.GetExecution(ctx, execution.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetExecution failed: %v", err)
+	}
+	if got.Status != types.ExecutionSucceeded || got.AgentProvider != "claude-code" || got.ExecutorInstanceID != "exec-test" {
+		t.Errorf("unexpected execution: %+v", got)
+	}
+	if got.CostUSD != 0.42 || got.AgentDuration != 3*time.Minute || got.CommitHash != "abc123" || got.CompletedAt == nil {
+		t.Errorf("outcome not recorded: %+v", got)
+	}
+	if got.PromptHash != types.PromptHash("prompt") {
+		t.Errorf("PromptHash = %q", got.PromptHash)
+	}
+	if got.StartSnapshot == nil || got.StartSnapshot.CommitSHA != execution.StartSnapshot.CommitSHA ||
+		got.StartSnapshot.DirtyHash != execution.StartSnapshot.DirtyHash ||
+		got.EndSnapshot == nil || got.EndSnapshot.CommitSHA != execution.EndSnapshot.CommitSHA ||
+		got.EndSnapshot.DirtyHash != execution.EndSnapshot.DirtyHash {
+		t.Errorf("snapshots not recorded: start %v, end %v", got.StartSnapshot, got.EndSnapshot)
+	}
+}
@@ -0,0 +1,189 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// healthCheckTimeout bounds each readiness check, so a wedged database
+// answers /readyz with 503 instead of hanging the probe
+const healthCheckTimeout = 2 * time.Second
+
+// Readiness is what /readyz reports: whether the executors can take work,
+// why not if they can't, and what each is doing
+type Readiness struct {
+	Ready   bool          `json:"ready"`
+	Checks  []HealthCheck `json:"checks"`
+	Workers []WorkerState `json:"workers"`
+}
+
+// HealthCheck is the result of one readiness check
+type HealthCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"` // Why the check failed, or that it was skipped
+}
+
+// WorkerState is what an executor is doing
+type WorkerState struct {
+	InstanceID      string     `json:"instance_id"`
+	Running         bool       `json:"running"`
+	CurrentIssue    string     `json:"current_issue,omitempty"`
+	QAWorkerEnabled bool       `json:"qa_worker_enabled"`
+	ActiveQAWorkers int        `json:"active_qa_workers"`
+	SelfHealingMode string     `json:"self_healing_mode"`
+	LastPoll        *time.Time `json:"last_poll,omitempty"`
+	PollInterval    string     `json:"poll_interval"` // Grows while the ready queue is empty, with IdleBackoffMax
+	BudgetStatus    string     `json:"budget_status,omitempty"`
+}
+
+// Live reports whether the executor is alive: it is starting or running,
+// and hasn't been stopped. Startup (baseline checks, sandbox rebasing) can
+// take a while, so a starting executor is live but not ready.
+func (e *Executor) Live() bool {
+	select {
+	case <-e.stopCh:
+		return false
+	default:
+		return true
+	}
+}
+
+// Readiness checks whether the executor can take work: the database answers,
+// the AI supervisor's circuit breaker is closed, and the event loop is running
+func (e *Executor) Readiness(ctx context.Context) *Readiness {
+	return readiness(ctx, []*Executor{e})
+}
+
+// HealthHandler serves the executor's health over HTTP, for supervisors such
+// as systemd or Kubernetes:
+//
+//	GET /healthz  200 while the executor is alive, 503 once it is stopping
+//	GET /readyz   200 when it can take work, else 503; the body is a Readiness
+func (e *Executor) HealthHandler() http.Handler {
+	return healthHandler([]*Executor{e})
+}
+
+// readiness checks whether all of executors can take work. They share a
+// database, so it is checked once.
+func readiness(ctx context.Context, executors []*Executor) *Readiness {
+	r := &Readiness{Workers: []WorkerState{}}
+	for _, e := range executors {
+		r.Workers = append(r.Workers, e.workerState())
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	_, err := executors[0].store.GetActiveInstances(dbCtx)
+	r.Checks = append(r.Checks, newHealthCheck("database", err))
+
+	supervised := false
+	var supervisorErr error
+	for _, e := range executors {
+		if e.enableAISupervision && e.supervisor != nil {
+			supervised = true
+			if err := e.supervisor.HealthCheck(ctx); err != nil && supervisorErr == nil {
+				supervisorErr = err
+			}
+		}
+	}
+	if supervised {
+		r.Checks = append(r.Checks, newHealthCheck("ai_supervisor", supervisorErr))
+	} else {
+		r.Checks = append(r.Checks, HealthCheck{Name: "ai_supervisor", OK: true, Detail: "disabled"})
+	}
+
+	stopped := 0
+	for _, w := range r.Workers {
+		if !w.Running {
+			stopped++
+		}
+	}
+	var running error
+	switch {
+	case len(executors) == 1 && stopped == 1:
+		running = fmt.Errorf("event loop is not running")
+	case stopped > 0:
+		running = fmt.Errorf("%d of %d event loops are not running", stopped, len(executors))
+	}
+	r.Checks = append(r.Checks, newHealthCheck("executor", running))
+
+	r.Ready = true
+	for _, check := range r.Checks {
+		r.Ready = r.Ready && check.OK
+	}
+	return r
+}
+
+// newHealthCheck reports a check that failed with err, or passed if err is nil
+func newHealthCheck(name string, err error) HealthCheck {
+	if err != nil {
+		return HealthCheck{Name: name, Detail: err.Error()}
+	}
+	return HealthCheck{Name: name, OK: true}
+}
+
+// workerState snapshots what the executor's workers are doing
+func (e *Executor) workerState() WorkerState {
+	e.mu.RLock()
+	running := e.running
+	lastPoll := e.lastPoll
+	e.mu.RUnlock()
+
+	state := WorkerState{
+		InstanceID:      e.instanceID,
+		Running:         running,
+		QAWorkerEnabled: e.enableQualityGateWorker && e.qaWorker != nil,
+		ActiveQAWorkers: int(e.activeQAWorkers.Load()),
+		SelfHealingMode: e.getSelfHealingMode().String(),
+		PollInterval:    e.getCurrentPollInterval().String(),
+	}
+	if !lastPoll.IsZero() {
+		state.LastPoll = &lastPoll
+	}
+	if e.interruptMgr != nil {
+		if issue := e.interruptMgr.GetCurrentIssue(); issue != nil {
+			state.CurrentIssue = issue.ID
+		}
+	}
+	if e.costTracker != nil {
+		state.BudgetStatus = strings.ToLower(e.costTracker.GetStats().Status.String())
+	}
+	return state
+}
+
+// healthHandler serves /healthz and /readyz for executors: alive while all
+// of them are, ready when all of them can take work
+func healthHandler(executors []*Executor) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		status, body := http.StatusOK, "ok"
+		for _, e := range executors {
+			if !e.Live() {
+				status, body = http.StatusServiceUnavailable, "stopping"
+			}
+		}
+		writeHealthJSON(w, status, map[string]string{"status": body})
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		readiness := readiness(r.Context(), executors)
+		status := http.StatusOK
+		if !readiness.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		writeHealthJSON(w, status, readiness)
+	})
+	return mux
+}
+
+// writeHealthJSON writes v as a health endpoint's response body
+func writeHealthJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) // Nothing to do if the prober went away
+}
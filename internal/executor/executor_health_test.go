@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+)
+
+func TestHealthHandler(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableQualityGateWorker = false
+	execCfg.EnableSandboxes = false
+	execCfg.EnableControlServer = false
+	execCfg.PollInterval = 50 * time.Millisecond
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+	handler := exec.HealthHandler()
+
+	get := func(path string) (int, map[string]interface{}) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("GET %s: invalid JSON %q: %v", path, rec.Body.String(), err)
+		}
+		return rec.Code, body
+	}
+
+	// Before Start the executor is alive but can't take work yet
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("expected /healthz 200 before start, got %d", code)
+	}
+	if code, body := get("/readyz"); code != http.StatusServiceUnavailable || body["ready"] != false {
+		t.Errorf("expected /readyz 503 before start, got %d %v", code, body)
+	}
+
+	execCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := exec.Start(execCtx); err != nil {
+		t.Fatalf("failed to start executor: %v", err)
+	}
+	time.Sleep(150 * time.Millisecond) // Let the event loop poll
+
+	code, body := get("/readyz")
+	if code != http.StatusOK || body["ready"] != true {
+		t.Fatalf("expected /readyz 200 once running, got %d %v", code, body)
+	}
+	checks := map[string]bool{}
+	for _, c := range body["checks"].([]interface{}) {
+		check := c.(map[string]interface{})
+		checks[check["name"].(string)] = check["ok"].(bool)
+	}
+	for _, name := range []string{"database", "ai_supervisor", "executor"} {
+		if ok, found := checks[name]; !found || !ok {
+			t.Errorf("expected passing %s check, got %v", name, body["checks"])
+		}
+	}
+	workers := body["workers"].([]interface{})
+	if len(workers) != 1 {
+		t.Fatalf("expected one worker, got %v", workers)
+	}
+	if worker := workers[0].(map[string]interface{}); worker["running"] != true || worker["last_poll"] == nil {
+		t.Errorf("expected a running, polling worker, got %v", worker)
+	}
+
+	cancel()
+	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 3*time.Second)
+	defer shutdownCancel()
+	if err := exec.Stop(shutdownCtx); err != nil {
+		t.Fatalf("executor shutdown failed: %v", err)
+	}
+	if code, _ := get("/healthz"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected /healthz 503 once stopped, got %d", code)
+	}
+	if code, _ := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected /readyz 503 once stopped, got %d", code)
+	}
+}
@@ -0,0 +1,196 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/idempotency"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// panicActor is the actor recorded on the attempts, attachments and bug
+// issues of recovered panics
+const panicActor = "panic-recovery"
+
+// maxPanicStackInline is how much of a stack trace is quoted in a bug issue's
+// description; the full trace is attached
+const maxPanicStackInline = 4000
+
+// recoverPanic recovers a panic in an executor worker, so one bad execution
+// doesn't take down the daemon, and reports it with reportPanic. It must be
+// deferred directly: recover only stops a panic in the deferred call itself.
+func (e *Executor) recoverPanic(ctx context.Context, worker string, issue *types.Issue) {
+	if r := recover(); r != nil {
+		e.reportPanic(ctx, worker, issue, r, debug.Stack())
+	}
+}
+
+// runStep runs one step of a worker loop, recovering a panic in it so the
+// loop carries on with its next step
+func (e *Executor) runStep(ctx context.Context, worker string, step func()) {
+	defer e.recoverPanic(ctx, worker, nil)
+	step()
+}
+
+// executeIssueRecovered is executeIssue, with a panic in it recovered and
+// reported as a failed execution
+func (e *Executor) executeIssueRecovered(ctx context.Context, issue *types.Issue) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = e.reportPanic(ctx, "executor", issue, r, debug.Stack())
+		}
+	}()
+	return e.executeIssue(ctx, issue)
+}
+
+// reportPanic records a recovered panic. When the worker was executing an
+// issue, the execution is recorded as a failed attempt with the stack trace
+// attached and the issue is released like any other failure. A bug is filed
+// against VC itself for the panic, once per worker and panic value; a repeat
+// is commented on the existing bug instead. Returns the panic as an error.
+func (e *Executor) reportPanic(ctx context.Context, worker string, issue *types.Issue, r interface{}, stack []byte) error {
+	panicErr := fmt.Errorf("panic in %s: %v", worker, r)
+	fmt.Fprintf(os.Stderr, "\n💥 Recovered %v\n%s\n", panicErr, stack)
+
+	// The panic may have come from a canceled context; report it regardless
+	ctx = context.WithoutCancel(ctx)
+
+	issueID := ""
+	if issue != nil {
+		issueID = issue.ID
+		e.failPanickedExecution(ctx, issue, panicErr, stack)
+	}
+
+	scope := idempotency.NewScope(e.store, "panic", worker, fmt.Sprint(r))
+	bugID, filed, err := scope.Do(ctx, "bug", func() (string, error) {
+		return e.filePanicBug(ctx, worker, issueID, panicErr, stack)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to file bug for %v: %v\n", panicErr, err)
+	} else if !filed {
+		comment := fmt.Sprintf("Panicked again in %s", worker)
+		if issueID != "" {
+			comment += fmt.Sprintf(" while executing %s", issueID)
+		}
+		if err := e.store.AddComment(ctx, bugID, panicActor, comment+"."); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add comment to %s: %v\n", bugID, err)
+		}
+	}
+
+	e.logEvent(ctx, events.EventTypeExecutorPanic, events.SeverityError, issueID,
+		fmt.Sprintf("Recovered %v", panicErr),
+		map[string]interface{}{
+			"worker":    worker,
+			"panic":     fmt.Sprint(r),
+			"bug_issue": bugID,
+		})
+
+	return panicErr
+}
+
+// failPanickedExecution records the execution of issue that panicked as a
+// failed attempt, attaches the stack trace to it and releases the issue
+func (e *Executor) failPanickedExecution(ctx context.Context, issue *types.Issue, panicErr error, stack []byte) {
+	history, err := e.store.GetExecutionHistory(ctx, issue.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get execution history for %s: %v\n", issue.ID, err)
+	}
+	now := time.Now()
+	success := false
+	attempt := &types.ExecutionAttempt{
+		IssueID:            issue.ID,
+		ExecutorInstanceID: e.instanceID,
+		AttemptNumber:      len(history) + 1,
+		StartedAt:          now,
+		CompletedAt:        &now,
+		Success:            &success,
+		Summary:            panicErr.Error(),
+		ErrorSample:        string(stack),
+	}
+	if err := e.store.RecordExecutionAttempt(ctx, attempt); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record failed attempt for %s: %v\n", issue.ID, err)
+	}
+
+	attachment := &types.Attachment{
+		IssueID:   issue.ID,
+		Name:      "panic stack trace",
+		Kind:      types.AttachmentOther,
+		CreatedBy: panicActor,
+	}
+	if attempt.ID != 0 {
+		attachment.ExecutionID = &attempt.ID
+	}
+	if err := e.store.AddAttachment(ctx, attachment, stack); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to attach stack trace to %s: %v\n", issue.ID, err)
+	}
+
+	e.getMonitor().EndExecution(false, false)
+	e.releaseIssueWithError(ctx, issue.ID, fmt.Sprintf("Execution failed: %v (stack trace attached)", panicErr))
+}
+
+// filePanicBug files a bug against VC for a recovered panic, with the stack
+// trace attached, and returns its ID
+func (e *Executor) filePanicBug(ctx context.Context, worker, issueID string, panicErr error, stack []byte) (string, error) {
+	inline := string(stack)
+	if len(inline) > maxPanicStackInline {
+		inline = inline[:maxPanicStackInline] + "\n..."
+	}
+	during := "It wasn't executing an issue."
+	if issueID != "" {
+		during = fmt.Sprintf("It was executing **%s**, which was released with the panic recorded as a failed attempt.", issueID)
+	}
+
+	title := panicErr.Error()
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = title[:i]
+	}
+	if len(title) > 100 {
+		title = title[:97] + "..."
+	}
+
+	bug := &types.Issue{
+		Title:     "VC " + title,
+		IssueType: types.TypeBug,
+		Priority:  1,
+		Status:    types.StatusOpen,
+		Description: fmt.Sprintf(`# Executor Panic
+
+The %s worker of executor %s panicked. The panic was recovered and the executor kept running. %s
+
+- **Panic**: %v
+
+## Stack Trace
+
+`+"```"+`
+%s
+`+"```"+`
+`, worker, e.instanceID, during, panicErr, inline),
+		AcceptanceCriteria: fmt.Sprintf("The cause of the panic in the %s worker is fixed, with a test that reproduces it", worker),
+	}
+	if err := e.store.CreateIssue(ctx, bug, panicActor); err != nil {
+		return "", fmt.Errorf("failed to create bug issue: %w", err)
+	}
+
+	attachment := &types.Attachment{
+		IssueID:   bug.ID,
+		Name:      "panic stack trace",
+		Kind:      types.AttachmentOther,
+		CreatedBy: panicActor,
+	}
+	if err := e.store.AddAttachment(ctx, attachment, stack); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to attach stack trace to %s: %v\n", bug.ID, err)
+	}
+	if issueID != "" {
+		dep := &types.Dependency{IssueID: bug.ID, DependsOnID: issueID, Type: types.DepDiscoveredFrom}
+		if err := e.store.AddDependency(ctx, dep, panicActor); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add discovered-from dependency: %v\n", err)
+		}
+	}
+	fmt.Printf("✓ Filed bug %s for the panic\n", bug.ID)
+	return bug.ID, nil
+}
@@ -0,0 +1,123 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+	"github.com/steveyegge/vc/internal/watchdog"
+)
+
+func TestRecoverPanic(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Parse config", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	e := &Executor{store: store, instanceID: "exec-test", config: &Config{}, monitor: watchdog.NewMonitor(nil)}
+	instance := &types.ExecutorInstance{
+		InstanceID:    e.instanceID,
+		Hostname:      "host",
+		Status:        types.ExecutorStatusRunning,
+		StartedAt:     time.Now(),
+		LastHeartbeat: time.Now(),
+		Metadata:      "{}",
+	}
+	if err := store.RegisterInstance(ctx, instance); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+
+	bugType := types.TypeBug
+	execute := func() {
+		if err := store.ClaimIssue(ctx, issue.ID, e.instanceID); err != nil {
+			t.Fatalf("ClaimIssue failed: %v", err)
+		}
+		defer e.recoverPanic(ctx, "executor", issue)
+		var m map[string]int
+		m["boom"]++ // Panics: assignment to entry in nil map
+	}
+	execute()
+
+	// The execution is failed with the stack trace attached, and the issue released
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Status != types.StatusOpen {
+		t.Errorf("issue status after panic = %s, want open", got.Status)
+	}
+	history, err := store.GetExecutionHistory(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionHistory failed: %v", err)
+	}
+	if len(history) != 1 || history[0].Success == nil || *history[0].Success || !strings.Contains(history[0].Summary, "nil map") {
+		t.Errorf("execution history = %+v, want one failed attempt", history)
+	}
+	attachments, err := store.ListAttachments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("ListAttachments failed: %v", err)
+	}
+	if len(attachments) != 1 || attachments[0].ExecutionID == nil || *attachments[0].ExecutionID != history[0].ID {
+		t.Errorf("attachments = %+v, want the stack trace on the failed attempt", attachments)
+	}
+
+	// A bug is filed against VC, discovered from the issue
+	bugs, err := store.SearchIssues(ctx, "", types.IssueFilter{IssueType: &bugType})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(bugs) != 1 || !strings.Contains(bugs[0].Title, "panic in executor") || !strings.Contains(bugs[0].Description, "TestRecoverPanic") {
+		t.Fatalf("bugs = %+v, want one for the panic", bugs)
+	}
+	deps, err := store.GetDependencyRecords(ctx, bugs[0].ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != issue.ID || deps[0].Type != types.DepDiscoveredFrom {
+		t.Errorf("bug deps = %+v", deps)
+	}
+
+	// The same panic again is commented on the existing bug
+	execute()
+	bugs, err = store.SearchIssues(ctx, "", types.IssueFilter{IssueType: &bugType})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(bugs) != 1 {
+		t.Errorf("got %d bugs after a repeated panic, want 1", len(bugs))
+	}
+	comments, err := store.GetComments(ctx, bugs[0].ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0].Body, "Panicked again") {
+		t.Errorf("bug comments = %+v", comments)
+	}
+
+	recorded, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeExecutorPanic})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(recorded) != 2 {
+		t.Errorf("got %d panic events, want 2", len(recorded))
+	}
+}
+
+func TestRunStepRecoversPanic(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	e := &Executor{store: store, instanceID: "exec-test", config: &Config{}}
+
+	ran := false
+	e.runStep(ctx, "heartbeat", func() { panic("lost connection") })
+	e.runStep(ctx, "heartbeat", func() { ran = true })
+	if !ran {
+		t.Error("step after a panic didn't run")
+	}
+}
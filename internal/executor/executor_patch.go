@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// maxPatchCommentSize caps how much of a rejected patch is quoted in the
+// issue comment
+const maxPatchCommentSize = 20000
+
+// patchProposal is an agent run in patch-proposal mode: the agent works in
+// a scratch worktree, and its changes reach the working directory only as
+// a reviewed patch
+type patchProposal struct {
+	pool   *sandbox.WorktreePool
+	wt     *sandbox.PooledWorktree
+	base   string // Commit the worktree started from
+	unhold func() // Stops the heartbeat renewing the worktree's assignment
+}
+
+// Dir is where the agent runs
+func (p *patchProposal) Dir() string {
+	return p.wt.Path
+}
+
+// startPatchProposal gets a scratch worktree at workingDir's HEAD for the
+// agent to run in
+func (e *Executor) startPatchProposal(ctx context.Context, issue *types.Issue, workingDir string) (*patchProposal, error) {
+	snapshot, err := e.gitOps.Snapshot(ctx, workingDir)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.CommitSHA == "" {
+		return nil, fmt.Errorf("%s has no commits to start a worktree from", workingDir)
+	}
+	pool, err := sandbox.NewWorktreePool(sandbox.WorktreePoolConfig{ParentRepo: e.workingDir})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worktree pool: %w", err)
+	}
+	wt, err := pool.Acquire(ctx, issue.ID, snapshot.CommitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a worktree: %w", err)
+	}
+	fmt.Printf("Patch-proposal mode: agent runs in %s; its changes are reviewed before they reach %s\n", wt.Path, workingDir)
+	return &patchProposal{pool: pool, wt: wt, base: snapshot.CommitSHA, unhold: e.holdWorktree(pool, wt)}, nil
+}
+
+// release gives the scratch worktree back to the pool, discarding it
+func (p *patchProposal) release(ctx context.Context) {
+	p.unhold()
+	if err := p.pool.Release(context.WithoutCancel(ctx), p.wt); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to release worktree %s: %v\n", p.wt.Path, err)
+	}
+}
+
+// applyPatchProposal captures what the agent changed in the scratch
+// worktree as a patch, validates it, has the supervisor review it, and
+// applies it to workingDir if it's approved. Returns an error if the patch
+// was rejected; the patch is then quoted in a comment on the issue so it
+// can still be applied by hand.
+func (e *Executor) applyPatchProposal(ctx context.Context, issue *types.Issue, proposal *patchProposal, workingDir string) error {
+	patch, err := e.gitOps.CapturePatch(ctx, proposal.Dir(), proposal.base)
+	if err != nil {
+		return fmt.Errorf("failed to capture the agent's changes: %w", err)
+	}
+	if strings.TrimSpace(patch) == "" {
+		fmt.Printf("Agent proposed no changes\n")
+		return nil
+	}
+
+	diff := git.ParseDiff(patch)
+	data := events.PatchProposalData{}
+	for _, f := range diff.Files {
+		data.Files = append(data.Files, f.Path)
+		data.Additions += f.Additions
+		data.Deletions += f.Deletions
+	}
+	fmt.Printf("Agent proposed a patch: %d files, +%d -%d\n", len(data.Files), data.Additions, data.Deletions)
+
+	reject := func(reason string, concerns []string) error {
+		data.Reason = reason
+		e.logPatchProposal(ctx, issue, data)
+
+		var comment strings.Builder
+		fmt.Fprintf(&comment, "**Patch rejected**: %s\n", reason)
+		for _, concern := range concerns {
+			fmt.Fprintf(&comment, "\n- %s", concern)
+		}
+		quoted := patch
+		if len(quoted) > maxPatchCommentSize {
+			quoted = quoted[:maxPatchCommentSize] + "\n... [patch truncated]\n"
+		}
+		fmt.Fprintf(&comment, "\n\nThe proposed patch was not applied:\n\n```diff\n%s```", quoted)
+		if err := e.store.AddComment(ctx, issue.ID, e.instanceID, comment.String()); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add patch comment: %v\n", err)
+		}
+		return fmt.Errorf("patch rejected: %s", reason)
+	}
+
+	// Validate: small enough to review, and applies to the real branch
+	if limit := e.config.PatchProposal.MaxFiles; limit > 0 && len(data.Files) > limit {
+		return reject(fmt.Sprintf("it changes %d files, more than the limit of %d", len(data.Files), limit), nil)
+	}
+	if err := e.gitOps.CheckPatch(ctx, workingDir, patch); err != nil {
+		return reject(fmt.Sprintf("it does not apply cleanly to %s: %v", workingDir, err), nil)
+	}
+
+	// Review
+	if e.supervisor == nil {
+		return reject("there is no AI supervisor to review it", nil)
+	}
+	review, err := e.supervisor.ReviewPatch(ctx, issue, patch)
+	if err != nil {
+		return reject(fmt.Sprintf("the review failed: %v", err), nil)
+	}
+	if !review.Approved {
+		return reject(review.Reasoning, review.Concerns)
+	}
+
+	if err := e.gitOps.ApplyPatch(ctx, workingDir, patch); err != nil {
+		return reject(fmt.Sprintf("it could not be applied: %v", err), nil)
+	}
+	fmt.Printf("✓ Patch approved and applied to %s\n", workingDir)
+	data.Applied = true
+	data.Reason = review.Reasoning
+	e.logPatchProposal(ctx, issue, data)
+	return nil
+}
+
+// logPatchProposal records the outcome of a patch proposal
+func (e *Executor) logPatchProposal(ctx context.Context, issue *types.Issue, data events.PatchProposalData) {
+	severity := events.SeverityInfo
+	message := fmt.Sprintf("Applied the agent's patch (%d files)", len(data.Files))
+	if !data.Applied {
+		severity = events.SeverityWarning
+		message = fmt.Sprintf("Rejected the agent's patch (%d files): %s", len(data.Files), data.Reason)
+	}
+	e.logEvent(ctx, events.EventTypePatchProposal, severity, issue.ID, message,
+		map[string]interface{}{
+			"files":     data.Files,
+			"additions": data.Additions,
+			"deletions": data.Deletions,
+			"applied":   data.Applied,
+			"reason":    data.Reason,
+		})
+}
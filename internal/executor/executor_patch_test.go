@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestPatchProposal(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Add greeting", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	// Sandboxes live in the repository, which ignores them
+	if err := os.WriteFile(filepath.Join(repoDir, ".git", "info", "exclude"), []byte(".sandboxes/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create git operations: %v", err)
+	}
+	e := &Executor{store: store, instanceID: "exec-test", gitOps: gitOps, workingDir: repoDir, config: &Config{}}
+	e.config.PatchProposal.MaxFiles = 2
+
+	status := func() string {
+		t.Helper()
+		out, err := exec.Command("git", "-C", repoDir, "status", "--porcelain").CombinedOutput()
+		if err != nil {
+			t.Fatalf("git status failed: %v\n%s", err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	propose := func(files ...string) error {
+		t.Helper()
+		proposal, err := e.startPatchProposal(ctx, issue, repoDir)
+		if err != nil {
+			t.Fatalf("startPatchProposal failed: %v", err)
+		}
+		defer proposal.release(ctx)
+		if proposal.Dir() == repoDir {
+			t.Fatal("expected the agent to run in a separate worktree")
+		}
+		for _, name := range files {
+			if err := os.WriteFile(filepath.Join(proposal.Dir(), name), []byte("agent work\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return e.applyPatchProposal(ctx, issue, proposal, repoDir)
+	}
+
+	// No changes: nothing to review
+	if err := propose(); err != nil {
+		t.Errorf("applyPatchProposal with no changes = %v, want nil", err)
+	}
+
+	// Too many files: rejected without review
+	if err := propose("a.txt", "b.txt", "c.txt"); err == nil || !strings.Contains(err.Error(), "limit of 2") {
+		t.Errorf("applyPatchProposal over the file limit = %v, want a rejection", err)
+	}
+
+	// Doesn't apply: rejected without review
+	if err := os.WriteFile(filepath.Join(repoDir, "a.txt"), []byte("human work\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := propose("a.txt"); err == nil || !strings.Contains(err.Error(), "does not apply") {
+		t.Errorf("applyPatchProposal of a conflicting patch = %v, want a rejection", err)
+	}
+	if err := os.Remove(filepath.Join(repoDir, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	// No supervisor to review it: rejected, and the working tree is untouched
+	if err := propose("a.txt"); err == nil || !strings.Contains(err.Error(), "no AI supervisor") {
+		t.Errorf("applyPatchProposal without a supervisor = %v, want a rejection", err)
+	}
+	if s := status(); s != "" {
+		t.Errorf("rejected patches changed the working tree:\n%s", s)
+	}
+
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 3 || !strings.Contains(comments[2].Body, "```diff\ndiff --git a/a.txt b/a.txt") {
+		t.Errorf("expected a comment quoting each rejected patch, got %d comments", len(comments))
+	}
+}
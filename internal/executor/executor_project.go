@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+// applyProjectConfig confines an executor configured with a project to that
+// project: its store only sees and creates the project's issues, and the
+// project's repository and default branch replace the defaults ("." and
+// "main") but not explicitly configured values.
+func applyProjectConfig(ctx context.Context, cfg *Config) error {
+	if cfg.Project == "" {
+		return nil
+	}
+
+	project, err := cfg.Store.GetProject(ctx, cfg.Project)
+	if err != nil {
+		return fmt.Errorf("failed to load project %s: %w", cfg.Project, err)
+	}
+	if project == nil {
+		return fmt.Errorf("project %s not found (define it with 'vc project define')", cfg.Project)
+	}
+
+	// The working directory follows the repository, so it moves with it
+	if project.RepoPath != "" && (cfg.ParentRepo == "" || cfg.ParentRepo == ".") {
+		cfg.ParentRepo = project.RepoPath
+		cfg.WorkingDir = project.RepoPath
+	}
+	if project.DefaultBranch != "" && (cfg.DefaultBranch == "" || cfg.DefaultBranch == "main") {
+		cfg.DefaultBranch = project.DefaultBranch
+	}
+
+	cfg.Store = storage.ScopeToProject(cfg.Store, cfg.Project)
+	return nil
+}
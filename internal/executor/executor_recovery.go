@@ -0,0 +1,322 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// recoveryActor is who startup crash recovery changes issues as
+const recoveryActor = "crash-recovery"
+
+// recoverOrphanedState reconciles the state a crashed vc process left
+// behind, before this executor claims any work:
+//   - executor instances on this host whose process is gone are marked
+//     stopped, without waiting for their heartbeat to go stale
+//   - in_progress issues claimed by an executor that is no longer running,
+//     whose lease (if any) has run out, are reopened. If the crash happened
+//     after the agent started, interrupt metadata is saved so the next run
+//     resumes from where it left off instead of starting over.
+//   - executions still recorded as running under such an executor are
+//     marked failed
+//   - plans whose approval created the mission's issues but crashed before
+//     finishing are finalized, and leftover plans of approved or missing
+//     missions are deleted
+//
+// An in_progress issue no executor ever claimed may be a human's and is left
+// alone. Returns how many issues, executions and plans were reconciled.
+func (e *Executor) recoverOrphanedState(ctx context.Context) (int, error) {
+	live, err := e.liveInstances(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	n, err := e.recoverOrphanedClaims(ctx, live)
+	recovered += n
+	if err != nil {
+		return recovered, err
+	}
+	n, err = e.failOrphanedExecutions(ctx, live)
+	recovered += n
+	if err != nil {
+		return recovered, err
+	}
+	n, err = e.recoverHalfApprovedPlans(ctx)
+	recovered += n
+	return recovered, err
+}
+
+// liveInstances maps the executor instances marked running to whether they
+// are alive: their heartbeat is fresh and, on this host, their process still
+// exists. Local instances whose process is gone are marked stopped and left
+// out, like every instance that isn't running.
+func (e *Executor) liveInstances(ctx context.Context) (map[string]bool, error) {
+	instances, err := e.store.GetActiveInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executor instances: %w", err)
+	}
+	live := map[string]bool{e.instanceID: true}
+	for _, instance := range instances {
+		if instance.InstanceID == e.instanceID {
+			continue
+		}
+		if instance.Hostname == e.hostname && processGone(instance.PID) {
+			fmt.Printf("Recovery: Executor %s (PID %d) is no longer running\n", instance.InstanceID, instance.PID)
+			if err := e.store.MarkInstanceStopped(ctx, instance.InstanceID); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to mark executor %s stopped: %v\n", instance.InstanceID, err)
+			}
+			continue
+		}
+		live[instance.InstanceID] = time.Since(instance.LastHeartbeat) <= e.staleThreshold
+	}
+	return live, nil
+}
+
+// processGone reports whether no process with pid exists on this host.
+// Errors other than "no such process" (e.g. EPERM, or signals being
+// unsupported) count as the process existing.
+func processGone(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return errors.Is(err, os.ErrProcessDone) || errors.Is(err, syscall.ESRCH)
+}
+
+// recoverOrphanedClaims reopens in_progress issues whose claim is held by an
+// executor that is no longer running. The claim of an executor that is still
+// marked running but has stopped heartbeating is kept until its lease, if it
+// has one, expires.
+func (e *Executor) recoverOrphanedClaims(ctx context.Context, live map[string]bool) (int, error) {
+	status := types.StatusInProgress
+	issues, err := e.store.SearchIssues(ctx, "", types.IssueFilter{Status: &status})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list in_progress issues: %w", err)
+	}
+
+	recovered := 0
+	for _, issue := range issues {
+		state, err := e.store.GetExecutionState(ctx, issue.ID)
+		if err != nil {
+			return recovered, fmt.Errorf("failed to get execution state of %s: %w", issue.ID, err)
+		}
+		if state == nil {
+			continue
+		}
+		alive, running := live[state.ExecutorInstanceID]
+		if alive {
+			continue
+		}
+		if running && state.LeaseExpiresAt != nil && state.LeaseExpiresAt.After(time.Now()) {
+			continue // Still leased, and its holder may only be slow to heartbeat
+		}
+		if err := e.recoverIssue(ctx, issue, state); err != nil {
+			return recovered, err
+		}
+		recovered++
+	}
+	return recovered, nil
+}
+
+// recoverIssue reopens an issue whose executor died. Work that got as far as
+// the agent is marked interrupted, so the next run is given a resume brief.
+func (e *Executor) recoverIssue(ctx context.Context, issue *types.Issue, state *types.IssueExecutionState) error {
+	holder := state.ExecutorInstanceID
+	if holder == "" {
+		holder = "unknown"
+	}
+	reason := fmt.Sprintf("Executor %s stopped while this issue was %s (claimed %s); reopened on startup by %s",
+		holder, state.State, state.ClaimedAt.Format(time.RFC3339), e.instanceID)
+
+	resumable := false
+	switch state.State {
+	case types.ExecutionStateExecuting, types.ExecutionStateAnalyzing, types.ExecutionStateGates, types.ExecutionStateCommitting:
+		resumable = true
+		if err := e.saveCrashResumeContext(ctx, issue, state); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to save resume context for %s: %v\n", issue.ID, err)
+			resumable = false
+		}
+	}
+
+	if err := e.store.ReleaseIssueAndReopen(ctx, issue.ID, recoveryActor, reason); err != nil {
+		return fmt.Errorf("failed to reopen %s: %w", issue.ID, err)
+	}
+
+	e.logEvent(ctx, events.EventTypeCrashRecovery, events.SeverityWarning, issue.ID, reason,
+		map[string]interface{}{
+			"executor_instance_id": state.ExecutorInstanceID,
+			"execution_state":      string(state.State),
+			"resumable":            resumable,
+		})
+	if resumable {
+		fmt.Printf("Recovery: Reopened %s (was %s on %s), the next run resumes it\n", issue.ID, state.State, holder)
+	} else {
+		fmt.Printf("Recovery: Reopened %s (was %s on %s)\n", issue.ID, state.State, holder)
+	}
+	return nil
+}
+
+// saveCrashResumeContext records the crash as an interruption, so the agent
+// that picks the issue up next is told to continue from the work the
+// crashed one left in the sandbox rather than start over
+func (e *Executor) saveCrashResumeContext(ctx context.Context, issue *types.Issue, state *types.IssueExecutionState) error {
+	resumeCount := 0
+	if existing, err := e.store.GetInterruptMetadata(ctx, issue.ID); err == nil && existing != nil {
+		resumeCount = existing.ResumeCount
+	}
+
+	now := time.Now()
+	agentContext := types.AgentContext{
+		InterruptedAt: now,
+		WorkingNotes: fmt.Sprintf("The executor running this task crashed during the %s phase. "+
+			"Changes the previous agent made may already be in your working directory: "+
+			"review them with git status and git diff and build on them.", state.State),
+		SessionDuration: now.Sub(state.ClaimedAt),
+	}
+	if state.CheckpointData != "" {
+		agentContext.ProgressSummary = "Last checkpoint: " + state.CheckpointData
+	}
+	snapshot, err := json.Marshal(agentContext)
+	if err != nil {
+		return fmt.Errorf("failed to serialize agent context: %w", err)
+	}
+
+	metadata := &types.InterruptMetadata{
+		IssueID:            issue.ID,
+		InterruptedAt:      now,
+		InterruptedBy:      "system",
+		Reason:             fmt.Sprintf("executor %s crashed", state.ExecutorInstanceID),
+		ExecutorInstanceID: state.ExecutorInstanceID,
+		ExecutionState:     string(state.State),
+		ContextSnapshot:    string(snapshot),
+		ResumeCount:        resumeCount,
+	}
+	if err := e.store.SaveInterruptMetadata(ctx, metadata); err != nil {
+		return err
+	}
+	if err := e.store.AddLabel(ctx, issue.ID, "interrupted", recoveryActor); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add interrupted label to %s: %v\n", issue.ID, err)
+	}
+	return nil
+}
+
+// failOrphanedExecutions marks executions still recorded as running under an
+// executor that is no longer running as failed
+func (e *Executor) failOrphanedExecutions(ctx context.Context, live map[string]bool) (int, error) {
+	running, err := e.store.ListExecutions(ctx, types.ExecutionFilter{Status: types.ExecutionRunning})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list running executions: %w", err)
+	}
+
+	failed := 0
+	for _, execution := range running {
+		if live[execution.ExecutorInstanceID] {
+			continue
+		}
+		completed := time.Now()
+		execution.Status = types.ExecutionFailed
+		execution.Error = fmt.Sprintf("executor %s stopped before the execution finished", execution.ExecutorInstanceID)
+		execution.CompletedAt = &completed
+		if err := e.store.UpdateExecution(ctx, execution); err != nil {
+			return failed, fmt.Errorf("failed to fail execution #%d: %w", execution.ID, err)
+		}
+		failed++
+	}
+	if failed > 0 {
+		fmt.Printf("Recovery: Marked %d execution(s) of stopped executors failed\n", failed)
+	}
+	return failed, nil
+}
+
+// recoverHalfApprovedPlans finishes plan approvals a crash interrupted.
+// Approval creates the mission's issues in one transaction, then records the
+// approval and deletes the plan: a plan whose mission already has its
+// generated issues is finalized rather than left to be approved twice, and a
+// plan whose mission is approved, closed or gone is deleted.
+func (e *Executor) recoverHalfApprovedPlans(ctx context.Context) (int, error) {
+	plans, err := e.store.ListDraftPlans(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list plans: %w", err)
+	}
+
+	recovered := 0
+	for _, plan := range plans {
+		issue, err := e.store.GetIssue(ctx, plan.MissionID)
+		if err != nil {
+			return recovered, fmt.Errorf("failed to get mission %s: %w", plan.MissionID, err)
+		}
+		var mission *types.Mission
+		if issue != nil && issue.IssueSubtype == types.SubtypeMission {
+			if mission, err = e.store.GetMission(ctx, plan.MissionID); err != nil {
+				return recovered, fmt.Errorf("failed to get mission %s: %w", plan.MissionID, err)
+			}
+		}
+
+		var why string
+		switch {
+		case issue == nil:
+			why = "its mission no longer exists"
+		case issue.Status == types.StatusClosed:
+			why = "its mission is closed"
+		case mission == nil:
+			continue // Plans for plain epics have no approval to finish
+		case mission.ApprovedAt != nil:
+			why = "its mission was already approved"
+		default:
+			generated, err := e.planGeneratedIssues(ctx, plan.MissionID)
+			if err != nil {
+				return recovered, err
+			}
+			if len(generated) == 0 {
+				continue
+			}
+			updates := map[string]interface{}{"approved_at": time.Now(), "approved_by": recoveryActor}
+			if err := e.store.UpdateMission(ctx, plan.MissionID, updates, recoveryActor); err != nil {
+				return recovered, fmt.Errorf("failed to record approval of mission %s: %w", plan.MissionID, err)
+			}
+			why = fmt.Sprintf("its approval created %d issue(s) but stopped before finishing", len(generated))
+		}
+
+		if err := e.store.DeletePlan(ctx, plan.MissionID); err != nil {
+			return recovered, fmt.Errorf("failed to delete plan for %s: %w", plan.MissionID, err)
+		}
+		message := fmt.Sprintf("Cleaned up the %s plan for %s: %s", plan.Status, plan.MissionID, why)
+		e.logEvent(ctx, events.EventTypeCrashRecovery, events.SeverityInfo, plan.MissionID, message,
+			map[string]interface{}{"plan_status": plan.Status})
+		fmt.Printf("Recovery: %s\n", message)
+		recovered++
+	}
+	return recovered, nil
+}
+
+// planGeneratedIssues returns the issues plan approval created for a
+// mission: the generated:plan issues the mission depends on
+func (e *Executor) planGeneratedIssues(ctx context.Context, missionID string) ([]string, error) {
+	deps, err := e.store.GetDependencyRecords(ctx, missionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies of %s: %w", missionID, err)
+	}
+	var generated []string
+	for _, dep := range deps {
+		labels, err := e.store.GetLabels(ctx, dep.DependsOnID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get labels of %s: %w", dep.DependsOnID, err)
+		}
+		for _, label := range labels {
+			if label == "generated:plan" {
+				generated = append(generated, dep.DependsOnID)
+				break
+			}
+		}
+	}
+	return generated, nil
+}
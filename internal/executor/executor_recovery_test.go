@@ -0,0 +1,208 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestRecoverOrphanedState(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableQualityGateWorker = false
+	execCfg.EnableSandboxes = false
+	e, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+
+	// A process that has exited stands in for the crashed executor: its
+	// heartbeat is still fresh, so only its missing process gives it away
+	dead := exec.Command("true")
+	if err := dead.Run(); err != nil {
+		t.Skipf("can't run a process to stand in for a crashed executor: %v", err)
+	}
+	hostname, _ := os.Hostname()
+	register := func(id, host string, pid int) {
+		instance := &types.ExecutorInstance{InstanceID: id, Hostname: host, PID: pid, Status: types.ExecutorStatusRunning,
+			StartedAt: time.Now(), LastHeartbeat: time.Now(), Version: "test", Metadata: "{}"}
+		if err := store.RegisterInstance(ctx, instance); err != nil {
+			t.Fatalf("RegisterInstance failed: %v", err)
+		}
+	}
+	register("crashed", hostname, dead.Process.Pid)
+	register("remote", "elsewhere.example.com", 1)
+
+	claim := func(title, instanceID string, states ...types.ExecutionState) *types.Issue {
+		issue := &types.Issue{Title: title, IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if err := store.ClaimIssue(ctx, issue.ID, instanceID); err != nil {
+			t.Fatalf("ClaimIssue failed: %v", err)
+		}
+		for _, state := range states {
+			if err := store.UpdateExecutionState(ctx, issue.ID, state); err != nil {
+				t.Fatalf("UpdateExecutionState failed: %v", err)
+			}
+		}
+		return issue
+	}
+	stranded := claim("Stranded", "crashed", types.ExecutionStateAssessing, types.ExecutionStateExecuting)
+	elsewhere := claim("Running elsewhere", "remote")
+	execution := &types.Execution{IssueID: stranded.ID, ExecutorInstanceID: "crashed", AgentProvider: "test", Status: types.ExecutionRunning}
+	if err := store.CreateExecution(ctx, execution); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+
+	// A mission whose plan approval created its issues but crashed before
+	// recording the approval, and one whose plan is still a draft
+	mission := func(title string) *types.Mission {
+		mission := &types.Mission{Issue: types.Issue{Title: title, IssueType: types.TypeEpic, IssueSubtype: types.SubtypeMission, Status: types.StatusOpen, Priority: 1}, Goal: title}
+		if err := store.CreateMission(ctx, mission, "test"); err != nil {
+			t.Fatalf("CreateMission failed: %v", err)
+		}
+		plan := &types.MissionPlan{
+			MissionID:       mission.ID,
+			Phases:          []types.PlannedPhase{{PhaseNumber: 1, Title: "Phase 1", Description: "First", Strategy: "Do it", Tasks: []string{"task"}, EstimatedEffort: "1 day"}},
+			Strategy:        "Do it",
+			EstimatedEffort: "1 day",
+			Confidence:      0.8,
+			GeneratedAt:     time.Now(),
+			GeneratedBy:     "test",
+			Status:          "validated",
+		}
+		if _, err := store.StorePlan(ctx, plan, 0); err != nil {
+			t.Fatalf("StorePlan failed: %v", err)
+		}
+		return mission
+	}
+	halfApproved := mission("Half approved")
+	draft := mission("Draft")
+	phase := &types.Issue{Title: "Phase 1", IssueType: types.TypeChore, Status: types.StatusOpen, Priority: 1}
+	if err := store.CreateIssue(ctx, phase, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, phase.ID, "generated:plan", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: halfApproved.ID, DependsOnID: phase.ID, Type: types.DepBlocks}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	recovered, err := e.recoverOrphanedState(ctx)
+	if err != nil {
+		t.Fatalf("recoverOrphanedState failed: %v", err)
+	}
+	// The stranded issue, its execution and the half-approved plan
+	if recovered != 3 {
+		t.Errorf("recovered %d items, want 3", recovered)
+	}
+
+	issue, err := store.GetIssue(ctx, stranded.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Status != types.StatusOpen {
+		t.Errorf("stranded issue is %s, want open", issue.Status)
+	}
+	metadata, err := store.GetInterruptMetadata(ctx, stranded.ID)
+	if err != nil || metadata == nil {
+		t.Fatalf("no resume context saved for the stranded issue (err %v)", err)
+	}
+	if metadata.ExecutionState != string(types.ExecutionStateExecuting) {
+		t.Errorf("resume context is for the %s phase, want executing", metadata.ExecutionState)
+	}
+	found, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: stranded.ID, Type: events.EventTypeCrashRecovery})
+	if err != nil || len(found) != 1 {
+		t.Errorf("found %d crash_recovery events (err %v), want 1", len(found), err)
+	}
+	if failed, err := store.GetExecution(ctx, execution.ID); err != nil || failed.Status != types.ExecutionFailed {
+		t.Errorf("stranded execution = %+v (err %v), want failed", failed, err)
+	}
+
+	if issue, err := store.GetIssue(ctx, elsewhere.ID); err != nil || issue.Status != types.StatusInProgress {
+		t.Errorf("issue claimed by a live executor was changed: %+v (err %v)", issue, err)
+	}
+
+	if m, err := store.GetMission(ctx, halfApproved.ID); err != nil || m.ApprovedAt == nil {
+		t.Errorf("half-approved mission wasn't approved: %+v (err %v)", m, err)
+	}
+	if plan, _, err := store.GetPlan(ctx, halfApproved.ID); err != nil || plan != nil {
+		t.Errorf("half-approved plan wasn't deleted (err %v)", err)
+	}
+	if plan, _, err := store.GetPlan(ctx, draft.ID); err != nil || plan == nil {
+		t.Errorf("draft plan was deleted (err %v)", err)
+	}
+
+	// Nothing is left to recover
+	if recovered, err := e.recoverOrphanedState(ctx); err != nil || recovered != 0 {
+		t.Errorf("second recovery reconciled %d items (err %v), want none", recovered, err)
+	}
+}
+
+// TestRecoverOrphanedClaimsLeases checks that the claim of an executor that
+// stopped heartbeating is kept while its lease lasts and reopened once the
+// lease has expired, with leases taken the way the event loop takes them
+func TestRecoverOrphanedClaimsLeases(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableQualityGateWorker = false
+	execCfg.EnableSandboxes = false
+	e, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+
+	// Still marked running on another host, but silent for an hour
+	silent := &types.ExecutorInstance{InstanceID: "silent", Hostname: "elsewhere.example.com", PID: 1, Status: types.ExecutorStatusRunning,
+		StartedAt: time.Now().Add(-2 * time.Hour), LastHeartbeat: time.Now().Add(-time.Hour), Version: "test", Metadata: "{}"}
+	if err := store.RegisterInstance(ctx, silent); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+
+	claim := func(title string, lease time.Duration) *types.Issue {
+		issue := &types.Issue{Title: title, IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		claimed, err := store.ClaimNextReadyIssue(ctx, silent.InstanceID, lease, "")
+		if err != nil || claimed == nil || claimed.ID != issue.ID {
+			t.Fatalf("ClaimNextReadyIssue() = %+v, %v, want %s", claimed, err, issue.ID)
+		}
+		return issue
+	}
+	leased := claim("Still leased", time.Hour)
+	expired := claim("Lease expired", time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	recovered, err := e.recoverOrphanedState(ctx)
+	if err != nil {
+		t.Fatalf("recoverOrphanedState failed: %v", err)
+	}
+	if recovered != 1 {
+		t.Errorf("recovered %d items, want 1", recovered)
+	}
+	if issue, err := store.GetIssue(ctx, expired.ID); err != nil || issue.Status != types.StatusOpen {
+		t.Errorf("issue with an expired lease = %+v (err %v), want reopened", issue, err)
+	}
+	if issue, err := store.GetIssue(ctx, leased.ID); err != nil || issue.Status != types.StatusInProgress {
+		t.Errorf("issue still leased = %+v (err %v), want it left in progress", issue, err)
+	}
+}
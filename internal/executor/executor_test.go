@@ -888,6 +888,15 @@ func TestConfigValidation(t *testing.T) {
 			wantError: true,
 			errMsg:    "SandboxRetentionCount must be non-negative",
 		},
+		{
+			name: "unknown AgentType should fail",
+			config: &Config{
+				Store:     store,
+				AgentType: "cursor",
+			},
+			wantError: true,
+			errMsg:    "invalid AgentType",
+		},
 		{
 			name: "valid minimal config should pass",
 			config: &Config{
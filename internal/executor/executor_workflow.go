@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// finishIssueBranch ends an execution on an issue branch: merge merges it
+// into its base branch, otherwise its work is saved on the branch for the
+// next attempt. Either way the base branch is checked out afterwards.
+// Failures are logged but never fail the execution.
+func (e *Executor) finishIssueBranch(ctx context.Context, issue *types.Issue, branch *git.IssueBranch, merge bool) {
+	// Finish even if the execution was cancelled, so the repository isn't
+	// left on the issue branch
+	ctx = context.WithoutCancel(ctx)
+
+	if !merge {
+		message := fmt.Sprintf("WIP: %s (%s)\n\nUnfinished work saved by the executor; not merged into %s.",
+			issue.Title, issue.ID, branch.BaseBranch)
+		if err := e.workflow.Leave(ctx, branch, message); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to leave branch %s: %v\n", branch.Name, err)
+			return
+		}
+		fmt.Printf("Left work on branch %s (not merged into %s)\n", branch.Name, branch.BaseBranch)
+		return
+	}
+
+	result, err := e.workflow.Merge(ctx, branch)
+	if result == nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to merge branch %s: %v\n", branch.Name, err)
+		comment := fmt.Sprintf("Could not merge %s into %s: %v\n\nThe work is kept on %s for manual merging.",
+			branch.Name, branch.BaseBranch, err, branch.Name)
+		if err := e.store.AddComment(ctx, issue.ID, e.instanceID, comment); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add merge failure comment: %v\n", err)
+		}
+		e.logEvent(ctx, events.EventTypeGitOperation, events.SeverityWarning, issue.ID,
+			fmt.Sprintf("Failed to merge %s into %s", branch.Name, branch.BaseBranch),
+			map[string]interface{}{
+				"command":     "merge",
+				"success":     false,
+				"branch":      branch.Name,
+				"base_branch": branch.BaseBranch,
+				"error":       err.Error(),
+			})
+		return
+	}
+	if err != nil {
+		// Merged, but the branch couldn't be deleted
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	how := "merged"
+	if result.FastForward {
+		how = "fast-forwarded"
+	}
+	fmt.Printf("✓ %s %s into %s: %s\n", how, branch.Name, branch.BaseBranch, safeShortHash(result.Commit))
+	comment := fmt.Sprintf("Merged %s into %s (%s): %s", branch.Name, branch.BaseBranch, how, result.Commit)
+	if result.Stashed != "" {
+		fmt.Printf("Stashed uncommitted changes left on %s (%q)\n", branch.Name, result.Stashed)
+		comment += fmt.Sprintf("\n\nChanges left uncommitted on the branch were not merged; they are stashed as %q.", result.Stashed)
+	}
+	if err := e.store.AddComment(ctx, issue.ID, e.instanceID, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add merge comment: %v\n", err)
+	}
+	e.logEvent(ctx, events.EventTypeGitOperation, events.SeverityInfo, issue.ID,
+		fmt.Sprintf("Merged %s into %s", branch.Name, branch.BaseBranch),
+		map[string]interface{}{
+			"command":      "merge",
+			"success":      true,
+			"branch":       branch.Name,
+			"base_branch":  branch.BaseBranch,
+			"fast_forward": result.FastForward,
+			"commit_hash":  result.Commit,
+			"stashed":      result.Stashed,
+		})
+
+	e.tryAutoBackport(ctx, issue)
+	e.tryAutoRelease(ctx, issue)
+}
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestFinishIssueBranch(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Add greeting", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	gitOut := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	base := gitOut("branch", "--show-current")
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create git operations: %v", err)
+	}
+	e := &Executor{store: store, instanceID: "exec-test", workflow: git.NewWorkflowManager(gitOps, repoDir)}
+
+	// Unaccepted work is saved on the issue branch, not the base branch
+	branch, err := e.workflow.Start(ctx, issue.ID, issue.Title)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "greeting.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e.finishIssueBranch(ctx, issue, branch, false)
+	if current := gitOut("branch", "--show-current"); current != base {
+		t.Fatalf("expected %s checked out, got %s", base, current)
+	}
+	if subject := gitOut("log", "-1", "--format=%s", branch.Name); !strings.HasPrefix(subject, "WIP: Add greeting") {
+		t.Errorf("expected the work saved in a WIP commit, got %q", subject)
+	}
+
+	// Accepted work is merged into the base branch
+	branch, err = e.workflow.Start(ctx, issue.ID, issue.Title)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "greeting.txt"), []byte("hello, world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOut("commit", "-am", "Add greeting")
+	e.finishIssueBranch(ctx, issue, branch, true)
+
+	if current := gitOut("branch", "--show-current"); current != base {
+		t.Fatalf("expected %s checked out, got %s", base, current)
+	}
+	if content, err := os.ReadFile(filepath.Join(repoDir, "greeting.txt")); err != nil || string(content) != "hello, world" {
+		t.Errorf("expected the work merged into %s, got %q (%v)", base, content, err)
+	}
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	found := false
+	for _, ev := range events {
+		if ev.EventType == types.EventCommented && ev.Comment != nil && strings.HasPrefix(*ev.Comment, "Merged "+branch.Name) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected the merge to be recorded in a comment on the issue")
+	}
+}
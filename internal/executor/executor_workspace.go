@@ -0,0 +1,162 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// protectDirtyWorkspace applies the dirty worktree policy before an agent
+// runs in workingDir outside a sandbox, so the agent's edits don't mix with
+// uncommitted human changes. It returns the directory to run the agent in
+// (workingDir, or an isolated worktree) and a function to call once the
+// execution is over, which restores stashed changes or gives the worktree
+// back. Returns an error only if the policy refuses to run the agent.
+func (e *Executor) protectDirtyWorkspace(ctx context.Context, issue *types.Issue, workingDir string) (string, func(), error) {
+	noop := func() {}
+	policy := e.config.DirtyWorktree.Policy
+	if e.gitOps == nil || policy == "" || policy == config.DirtyWorktreeIgnore {
+		return workingDir, noop, nil
+	}
+
+	status, err := e.gitOps.GetStatus(ctx, workingDir)
+	if err != nil {
+		// Not a git repository, or git failed: nothing to protect
+		return workingDir, noop, nil
+	}
+	if !status.HasChanges {
+		return workingDir, noop, nil
+	}
+	changed := len(status.Modified) + len(status.Added) + len(status.Deleted) + len(status.Renamed) + len(status.Untracked)
+	fmt.Printf("Working directory %s has %d uncommitted change(s) (policy: %s)\n", workingDir, changed, policy)
+
+	switch policy {
+	case config.DirtyWorktreeRefuse:
+		message := fmt.Sprintf("Not running %s: %s has uncommitted changes (commit or stash them, or set VC_DIRTY_WORKTREE)", issue.ID, workingDir)
+		e.logEvent(ctx, events.EventTypeGitOperation, events.SeverityWarning, issue.ID, message,
+			map[string]interface{}{
+				"command":       "dirty_worktree",
+				"policy":        policy,
+				"changed_files": changed,
+				"success":       false,
+			})
+		return "", nil, fmt.Errorf("%s has uncommitted changes", workingDir)
+
+	case config.DirtyWorktreeStash:
+		return e.stashDirtyWorkspace(ctx, issue, workingDir)
+
+	case config.DirtyWorktreeIsolate:
+		return e.isolateDirtyWorkspace(ctx, issue, workingDir)
+	}
+	return workingDir, noop, nil
+}
+
+// stashDirtyWorkspace stashes the uncommitted changes in workingDir, and
+// restores them once the execution is over
+func (e *Executor) stashDirtyWorkspace(ctx context.Context, issue *types.Issue, workingDir string) (string, func(), error) {
+	stash, err := e.gitOps.Stash(ctx, workingDir, fmt.Sprintf("vc: uncommitted changes set aside while %s runs", issue.ID))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to stash uncommitted changes: %w", err)
+	}
+	if stash == "" {
+		return workingDir, func() {}, nil
+	}
+	fmt.Printf("Stashed uncommitted changes (%s); they will be restored after the execution\n", safeShortHash(stash))
+	e.logEvent(ctx, events.EventTypeGitOperation, events.SeverityInfo, issue.ID,
+		fmt.Sprintf("Stashed uncommitted changes in %s before running the agent", workingDir),
+		map[string]interface{}{
+			"command": "stash",
+			"stash":   stash,
+			"success": true,
+		})
+
+	restore := func() {
+		// Restore even if the execution was cancelled
+		ctx := context.WithoutCancel(ctx)
+		if err := e.gitOps.RestoreStash(ctx, workingDir, stash); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to restore stashed changes: %v\n", err)
+			comment := fmt.Sprintf("Uncommitted changes in %s were stashed while this issue ran, and could not be restored "+
+				"automatically: %v\n\nThey are kept in the stash (commit %s); restore them with 'git stash apply'.",
+				workingDir, err, stash)
+			if err := e.store.AddComment(ctx, issue.ID, e.instanceID, comment); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to add stash comment: %v\n", err)
+			}
+			e.logEvent(ctx, events.EventTypeGitOperation, events.SeverityWarning, issue.ID,
+				"Failed to restore stashed uncommitted changes",
+				map[string]interface{}{
+					"command": "stash_apply",
+					"stash":   stash,
+					"success": false,
+					"error":   err.Error(),
+				})
+			return
+		}
+		fmt.Printf("Restored stashed uncommitted changes (%s)\n", safeShortHash(stash))
+	}
+	return workingDir, restore, nil
+}
+
+// isolateDirtyWorkspace runs the execution in a pooled worktree, on the
+// issue's branch, leaving workingDir untouched. Work the agent didn't commit
+// is saved on the branch before the worktree is given back.
+func (e *Executor) isolateDirtyWorkspace(ctx context.Context, issue *types.Issue, workingDir string) (string, func(), error) {
+	snapshot, err := e.gitOps.Snapshot(ctx, workingDir)
+	if err != nil {
+		return "", nil, err
+	}
+	if snapshot.CommitSHA == "" {
+		return "", nil, fmt.Errorf("%s has no commits to start a worktree from", workingDir)
+	}
+	pool, err := sandbox.NewWorktreePool(sandbox.WorktreePoolConfig{ParentRepo: workingDir})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open worktree pool: %w", err)
+	}
+	wt, err := pool.Acquire(ctx, issue.ID, snapshot.CommitSHA)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get a worktree: %w", err)
+	}
+	unhold := e.holdWorktree(pool, wt)
+	branch := git.IssueBranchName(issue.ID, issue.Title)
+	if err := e.gitOps.CheckoutBranch(ctx, wt.Path, branch, ""); err != nil {
+		unhold()
+		if releaseErr := pool.Release(context.WithoutCancel(ctx), wt); releaseErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to release worktree %s: %v\n", wt.Path, releaseErr)
+		}
+		return "", nil, err
+	}
+	fmt.Printf("Running in worktree %s on branch %s, leaving uncommitted changes in %s untouched\n", wt.Path, branch, workingDir)
+	e.logEvent(ctx, events.EventTypeGitOperation, events.SeverityInfo, issue.ID,
+		fmt.Sprintf("Isolated execution in worktree %s (branch %s) from uncommitted changes in %s", wt.Path, branch, workingDir),
+		map[string]interface{}{
+			"command":  "worktree",
+			"worktree": wt.Path,
+			"branch":   branch,
+			"success":  true,
+		})
+
+	release := func() {
+		ctx := context.WithoutCancel(ctx)
+		if dirty, err := e.gitOps.HasUncommittedChanges(ctx, wt.Path); err == nil && dirty {
+			message := fmt.Sprintf("WIP: %s (%s)\n\nUnfinished work saved by the executor from an isolated worktree.", issue.Title, issue.ID)
+			if _, err := e.gitOps.CommitChanges(ctx, wt.Path, git.CommitOptions{Message: message, AddAll: true}); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to save work on %s: %v\n", branch, err)
+			}
+		}
+		unhold()
+		if err := pool.Release(ctx, wt); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to release worktree %s: %v\n", wt.Path, err)
+		}
+		comment := fmt.Sprintf("Ran in a separate worktree because %s had uncommitted changes; the work is on branch %s.",
+			workingDir, branch)
+		if err := e.store.AddComment(ctx, issue.ID, e.instanceID, comment); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add worktree comment: %v\n", err)
+		}
+	}
+	return wt.Path, release, nil
+}
@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestProtectDirtyWorkspace(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Add greeting", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	gitOut := func(dir string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	readFile := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	wip := filepath.Join(repoDir, "wip.txt")
+	if err := os.WriteFile(wip, []byte("human work in progress"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create git operations: %v", err)
+	}
+	e := &Executor{store: store, instanceID: "exec-test", gitOps: gitOps, config: &Config{}}
+
+	// Refuse: nothing runs, nothing changes
+	e.config.DirtyWorktree.Policy = config.DirtyWorktreeRefuse
+	if _, _, err := e.protectDirtyWorkspace(ctx, issue, repoDir); err == nil {
+		t.Fatal("expected the refuse policy to fail")
+	}
+	if readFile(wip) != "human work in progress" {
+		t.Error("refuse changed the working tree")
+	}
+
+	// Stash: the agent sees a clean tree; the work comes back afterwards
+	e.config.DirtyWorktree.Policy = config.DirtyWorktreeStash
+	dir, restore, err := e.protectDirtyWorkspace(ctx, issue, repoDir)
+	if err != nil {
+		t.Fatalf("protectDirtyWorkspace (stash) failed: %v", err)
+	}
+	if dir != repoDir {
+		t.Errorf("stash ran in %s, want %s", dir, repoDir)
+	}
+	if status := gitOut(repoDir, "status", "--porcelain"); status != "" {
+		t.Errorf("expected a clean tree while the agent runs, got:\n%s", status)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "agent.txt"), []byte("agent work"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOut(repoDir, "add", "agent.txt")
+	gitOut(repoDir, "commit", "-m", "Agent work")
+	restore()
+	if readFile(wip) != "human work in progress" {
+		t.Error("stashed work was not restored")
+	}
+	if stashes := gitOut(repoDir, "stash", "list"); stashes != "" {
+		t.Errorf("expected the stash dropped, got %s", stashes)
+	}
+
+	// Worktree: the agent runs elsewhere, on the issue branch
+	e.config.DirtyWorktree.Policy = config.DirtyWorktreeIsolate
+	dir, release, err := e.protectDirtyWorkspace(ctx, issue, repoDir)
+	if err != nil {
+		t.Fatalf("protectDirtyWorkspace (worktree) failed: %v", err)
+	}
+	if dir == repoDir {
+		t.Fatal("expected a separate worktree")
+	}
+	branch := git.IssueBranchName(issue.ID, issue.Title)
+	if current := gitOut(dir, "branch", "--show-current"); current != branch {
+		t.Errorf("worktree is on %q, want %q", current, branch)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wip.txt")); !os.IsNotExist(err) {
+		t.Error("uncommitted human work leaked into the worktree")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "isolated.txt"), []byte("agent work"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The heartbeat keeps the worktree assigned until it's released
+	if len(e.heldWorktrees) != 1 {
+		t.Fatalf("expected the worktree held for the heartbeat, got %d", len(e.heldWorktrees))
+	}
+	for wt := range e.heldWorktrees {
+		assigned := wt.AssignedAt
+		e.touchWorktrees(ctx)
+		if !wt.AssignedAt.After(assigned) {
+			t.Errorf("touchWorktrees didn't renew the assignment of %s", wt.Path)
+		}
+	}
+	release()
+	if len(e.heldWorktrees) != 0 {
+		t.Error("released worktree still held for the heartbeat")
+	}
+	if subject := gitOut(repoDir, "log", "-1", "--format=%s", branch); !strings.HasPrefix(subject, "WIP: Add greeting") {
+		t.Errorf("expected uncommitted agent work saved on %s, got %q", branch, subject)
+	}
+	if readFile(wip) != "human work in progress" {
+		t.Error("worktree isolation changed the working tree")
+	}
+}
@@ -34,6 +34,16 @@ func (g *contextGatherer) GatherContext(ctx context.Context, issue *types.Issue,
 		pc.ParentMission = parent
 	}
 
+	// Custom fields are part of the task description
+	if fields, err := g.store.GetCustomFieldValues(ctx, issue.ID); err == nil {
+		pc.CustomFields = fields
+	}
+
+	// Path scope from the issue's "scope:" labels
+	if labels, err := g.store.GetLabels(ctx, issue.ID); err == nil {
+		pc.PathScope = types.ScopeFromLabels(labels)
+	}
+
 	// 2. Get related issues (blockers, dependents, siblings)
 	if related, err := g.GetRelatedIssues(ctx, issue); err == nil {
 		pc.RelatedIssues = related
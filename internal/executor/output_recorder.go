@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+const (
+	// outputFlushInterval is how often recorded output is written to storage,
+	// which bounds how far 'vc tail' lags behind the agent
+	outputFlushInterval = 500 * time.Millisecond
+
+	// outputFlushLines writes recorded output early once this many lines are
+	// waiting, so a burst of output doesn't pile up between flushes
+	outputFlushLines = 100
+)
+
+// outputRecorder stores an execution's agent output as it is written, so
+// 'vc tail' can follow the agent from another process. Lines are buffered
+// and written in batches; storage errors are reported once and otherwise
+// ignored, since the agent's run matters more than its transcript.
+type outputRecorder struct {
+	store       storage.Storage
+	ctx         context.Context
+	executionID int64
+	issueID     string
+
+	mu       sync.Mutex
+	pending  []*types.OutputLine
+	recorded int  // Lines accepted, stopping at maxOutputLines
+	failed   bool // A write failed and was reported
+
+	flush chan struct{}
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// newOutputRecorder starts recording output for an execution
+func newOutputRecorder(ctx context.Context, store storage.Storage, executionID int64, issueID string) *outputRecorder {
+	r := &outputRecorder{
+		store: store,
+		// Output written as the agent is killed should still be stored
+		ctx:         context.WithoutCancel(ctx),
+		executionID: executionID,
+		issueID:     issueID,
+		flush:       make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r
+}
+
+// Record queues a line for storage. Past maxOutputLines, lines are dropped
+// after a truncation marker, as in AgentResult.
+func (r *outputRecorder) Record(stream types.OutputStream, line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.recorded > maxOutputLines {
+		return
+	}
+	r.recorded++
+	if r.recorded > maxOutputLines {
+		line = "[... output truncated: limit reached ...]"
+	}
+	r.pending = append(r.pending, &types.OutputLine{
+		ExecutionID: r.executionID,
+		IssueID:     r.issueID,
+		Stream:      stream,
+		Line:        line,
+		Timestamp:   time.Now(),
+	})
+	if len(r.pending) >= outputFlushLines {
+		select {
+		case r.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close writes any remaining lines and stops the recorder
+func (r *outputRecorder) Close() {
+	close(r.done)
+	r.wg.Wait()
+}
+
+// run writes pending lines every outputFlushInterval, or sooner when many
+// are waiting, until Close
+func (r *outputRecorder) run() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(outputFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.write()
+		case <-r.flush:
+			r.write()
+		case <-r.done:
+			r.write()
+			return
+		}
+	}
+}
+
+// write stores the pending lines
+func (r *outputRecorder) write() {
+	r.mu.Lock()
+	lines := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	if len(lines) == 0 {
+		return
+	}
+	if err := r.store.AppendExecutionOutput(r.ctx, lines); err != nil && !r.failed {
+		r.failed = true
+		fmt.Fprintf(os.Stderr, "warning: failed to store agent output of execution %d: %v\n", r.executionID, err)
+	}
+}
@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestOutputRecorder(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := &types.Issue{
+		Title:              "Record output",
+		IssueType:          types.TypeTask,
+		Status:             types.StatusOpen,
+		Priority:           1,
+		AcceptanceCriteria: "Output is stored",
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning}
+	if err := store.CreateExecution(ctx, execution); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+
+	// A canceled context (e.g. the agent was killed) still stores the output
+	canceled, cancel := context.WithCancel(ctx)
+	recorder := newOutputRecorder(canceled, store, execution.ID, issue.ID)
+	cancel()
+	for i := 0; i < maxOutputLines+10; i++ {
+		recorder.Record(types.OutputStdout, fmt.Sprintf("line %d", i))
+	}
+	recorder.Close()
+
+	lines, err := store.ListExecutionOutput(ctx, types.OutputFilter{ExecutionID: execution.ID})
+	if err != nil {
+		t.Fatalf("ListExecutionOutput failed: %v", err)
+	}
+	if len(lines) != maxOutputLines+1 {
+		t.Fatalf("expected %d lines and a truncation marker, got %d", maxOutputLines, len(lines))
+	}
+	if lines[0].Line != "line 0" || lines[0].Stream != types.OutputStdout || lines[0].IssueID != issue.ID {
+		t.Errorf("unexpected first line: %+v", lines[0])
+	}
+	if last := lines[len(lines)-1].Line; last != "[... output truncated: limit reached ...]" {
+		t.Errorf("expected a truncation marker, got %q", last)
+	}
+}
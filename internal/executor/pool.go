@@ -0,0 +1,137 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/steveyegge/vc/internal/cost"
+)
+
+// Pool runs several executors in one process. Each registers as its own
+// instance and claims ready work atomically, so they work on different
+// issues concurrently, each in its own sandbox. They share one cost budget.
+type Pool struct {
+	executors []*Executor
+}
+
+// NewPool creates a pool of workers executors from cfg. Only the first
+// serves the control socket, so pause commands reach only its issue.
+func NewPool(cfg *Config, workers int) (*Pool, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("a pool needs at least one worker, got %d", workers)
+	}
+	if workers > 1 && !cfg.EnableSandboxes {
+		return nil, fmt.Errorf("more than one worker requires sandboxes, so agents don't edit the same workspace")
+	}
+
+	// Share one budget rather than have each worker track, and persist,
+	// its own view of the same spend
+	costTracker := cfg.CostTracker
+	if costConfig := cost.LoadFromEnv(); costTracker == nil && costConfig.Enabled && workers > 1 {
+		tracker, err := cost.NewTracker(costConfig, cfg.Store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize cost tracker: %w", err)
+		}
+		costTracker = tracker
+	}
+
+	p := &Pool{}
+	for i := 0; i < workers; i++ {
+		workerCfg := *cfg // New adjusts its config, e.g. for the project
+		workerCfg.CostTracker = costTracker
+		workerCfg.EnableControlServer = cfg.EnableControlServer && i == 0
+		e, err := New(&workerCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create worker %d: %w", i+1, err)
+		}
+		if workers > 1 && e.sandboxMgr == nil {
+			return nil, fmt.Errorf("worker %d has no sandboxes (see the warning above); more than one worker requires them", i+1)
+		}
+		p.executors = append(p.executors, e)
+	}
+	return p, nil
+}
+
+// Executors returns the pool's executors
+func (p *Pool) Executors() []*Executor {
+	return p.executors
+}
+
+// KillAgent stops the agent working on issueID in whichever executor runs
+// it. Returns false if no executor in the pool is running one.
+func (p *Pool) KillAgent(issueID, reason string) bool {
+	for _, e := range p.executors {
+		if e.KillAgent(issueID, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// PauseWork stops every executor in the pool claiming new work until
+// ResumeWork
+func (p *Pool) PauseWork(reason string) {
+	for _, e := range p.executors {
+		e.PauseWork(reason)
+	}
+}
+
+// ResumeWork lets every executor in the pool claim work again
+func (p *Pool) ResumeWork() {
+	for _, e := range p.executors {
+		e.ResumeWork()
+	}
+}
+
+// Start starts every executor, stopping those already started if one fails
+func (p *Pool) Start(ctx context.Context) error {
+	for i, e := range p.executors {
+		if err := e.Start(ctx); err != nil {
+			for _, started := range p.executors[:i] {
+				_ = started.Stop(ctx)
+			}
+			return fmt.Errorf("failed to start worker %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Stop stops every executor concurrently, waiting for their work to wind down
+func (p *Pool) Stop(ctx context.Context) error {
+	errCh := make(chan error, len(p.executors))
+	for _, e := range p.executors {
+		go func(e *Executor) { errCh <- e.Stop(ctx) }(e)
+	}
+	var errs []error
+	for range p.executors {
+		if err := <-errCh; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MarkInstancesStoppedOnExit marks every executor's instance as stopped
+// (see Executor.MarkInstanceStoppedOnExit)
+func (p *Pool) MarkInstancesStoppedOnExit(ctx context.Context) error {
+	var errs []error
+	for _, e := range p.executors {
+		if err := e.MarkInstanceStoppedOnExit(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Readiness checks whether every executor can take work
+func (p *Pool) Readiness(ctx context.Context) *Readiness {
+	return readiness(ctx, p.executors)
+}
+
+// HealthHandler serves /healthz and /readyz for the whole pool: alive while
+// every executor is, ready when every executor can take work
+func (p *Pool) HealthHandler() http.Handler {
+	return healthHandler(p.executors)
+}
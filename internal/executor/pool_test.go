@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/cost"
+	"github.com/steveyegge/vc/internal/storage/memory"
+)
+
+func TestNewPool(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	cfg := DefaultConfig()
+	cfg.Store = store
+	cfg.EnableAISupervision = false
+	cfg.EnableQualityGates = false
+	cfg.EnableQualityGateWorker = false
+	cfg.EnableControlServer = true
+	repo := t.TempDir()
+	if err := setupGitRepo(t, repo); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	cfg.WorkingDir = repo
+	cfg.ParentRepo = repo
+	cfg.SandboxRoot = t.TempDir()
+
+	if _, err := NewPool(cfg, 0); err == nil {
+		t.Error("expected an error for a pool without workers")
+	}
+	cfg.EnableSandboxes = false
+	if _, err := NewPool(cfg, 2); err == nil || !strings.Contains(err.Error(), "sandboxes") {
+		t.Errorf("expected several workers without sandboxes to be refused, got %v", err)
+	}
+
+	cfg.EnableSandboxes = true
+	costConfig := cost.DefaultConfig()
+	costConfig.PersistStatePath = t.TempDir() + "/cost_state.json"
+	tracker, err := cost.NewTracker(costConfig, store)
+	if err != nil {
+		t.Fatalf("NewTracker failed: %v", err)
+	}
+	cfg.CostTracker = tracker
+	pool, err := NewPool(cfg, 3)
+	if err != nil {
+		t.Fatalf("NewPool failed: %v", err)
+	}
+
+	executors := pool.Executors()
+	if len(executors) != 3 {
+		t.Fatalf("expected 3 executors, got %d", len(executors))
+	}
+	ids := map[string]bool{}
+	for i, e := range executors {
+		ids[e.instanceID] = true
+		if e.costTracker != tracker {
+			t.Errorf("worker %d: expected the shared cost tracker", i+1)
+		}
+		if hasControl := e.controlServer != nil; hasControl != (i == 0) {
+			t.Errorf("worker %d: control server = %v, want only the first worker to serve it", i+1, hasControl)
+		}
+	}
+	if len(ids) != 3 {
+		t.Errorf("expected each worker to be its own instance, got %v", ids)
+	}
+	if !pool.Readiness(ctx).Checks[0].OK {
+		t.Errorf("expected the database check to pass: %+v", pool.Readiness(ctx).Checks)
+	}
+	if pool.Readiness(ctx).Ready {
+		t.Error("expected a pool that hasn't started not to be ready")
+	}
+}
+
+func TestBackOffIdle(t *testing.T) {
+	e := &Executor{
+		config:              &Config{IdleBackoffMax: 35 * time.Second},
+		basePollInterval:    5 * time.Second,
+		currentPollInterval: 5 * time.Second,
+	}
+
+	var got []time.Duration
+	for i := 0; i < 4; i++ {
+		e.backOffIdle()
+		got = append(got, e.getCurrentPollInterval())
+	}
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 35 * time.Second, 35 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected poll intervals %v, got %v", want, got)
+		}
+	}
+
+	// Steady state resets (e.g. a new commit) leave the backoff alone;
+	// finding work drops back to the base interval
+	e.steadyStateMutex.Lock()
+	e.resetSteadyStateUnlocked()
+	e.steadyStateMutex.Unlock()
+	if interval := e.getCurrentPollInterval(); interval != 35*time.Second {
+		t.Errorf("expected the backoff to survive a steady state reset, got %v", interval)
+	}
+	e.resetIdleBackoff()
+	if interval := e.getCurrentPollInterval(); interval != 5*time.Second {
+		t.Errorf("expected the base interval after a reset, got %v", interval)
+	}
+}
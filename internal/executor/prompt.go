@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"text/template"
 	"time"
+
+	"github.com/steveyegge/vc/internal/gates"
 )
 
 // PromptBuilder constructs comprehensive prompts from PromptContext using structured templates.
@@ -43,6 +45,13 @@ Mission Goal:
 ## Design
 {{.Issue.Design}}
 
+{{end}}
+{{if .CustomFields -}}
+## Fields
+{{range .CustomFields -}}
+- **{{.Name}}**: {{.Value}}
+{{end}}
+
 {{end}}
 {{if .Issue.AcceptanceCriteria -}}
 ## Acceptance Criteria
@@ -50,6 +59,24 @@ Mission Goal:
 
 **IMPORTANT**: These criteria define success. ALL criteria must be met. Do not add extra work beyond what's required.
 
+{{end}}
+{{if .PathScope -}}
+## Scope
+Only change files under these paths:
+{{range .PathScope -}}
+- {{.}}
+{{end}}
+Changes to any other file will be reverted or left out of the commit.
+
+{{end}}
+{{if .LFSPatterns -}}
+## Large Files
+These paths are tracked by Git LFS. Do not modify, add or delete files matching them:
+{{range .LFSPatterns -}}
+- {{.}}
+{{end}}
+Also avoid adding or rewriting large binary files. Changes to either will be flagged for review.
+
 {{end}}
 {{if .Sandbox -}}
 # ENVIRONMENT
@@ -177,7 +204,7 @@ This task has been attempted {{len .PreviousAttempts}} time(s) before:
 {{if not .Passed -}}
 - **{{.Gate}}**: Failed
 {{if .Output -}}
-  Output: {{truncate .Output 200}}
+  Output: {{truncate (compress .Output) 200}}
 {{end}}
 {{if .Error -}}
   Error: {{.Error}}
@@ -519,6 +546,7 @@ func NewPromptBuilder() (*PromptBuilder, error) {
 	tmpl := template.New("prompt").Funcs(template.FuncMap{
 		"formatTime": formatTime,
 		"truncate":   truncate,
+		"compress":   gates.CompressOutput,
 		"isNil":      isNil,
 		"deref":      deref,
 		"derefInt":   derefInt,
@@ -333,6 +333,84 @@ func TestBuildPrompt_WithNotes(t *testing.T) {
 	}
 }
 
+// TestBuildPrompt_WithCustomFields tests custom field rendering
+func TestBuildPrompt_WithCustomFields(t *testing.T) {
+	pb, err := NewPromptBuilder()
+	if err != nil {
+		t.Fatalf("NewPromptBuilder() failed: %v", err)
+	}
+
+	ctx := &PromptContext{
+		Issue: &types.Issue{ID: "vc-101", Title: "Fix checkout timeout"},
+		CustomFields: []*types.CustomFieldValue{
+			{Name: "component", Type: types.CustomFieldEnum, Value: "payments"},
+			{Name: "sla-hours", Type: types.CustomFieldNumber, Value: "4"},
+		},
+	}
+
+	prompt, err := pb.BuildPrompt(ctx)
+	if err != nil {
+		t.Fatalf("BuildPrompt() failed: %v", err)
+	}
+
+	if !strings.Contains(prompt, "## Fields") {
+		t.Error("Prompt missing 'Fields' section")
+	}
+	if !strings.Contains(prompt, "- **component**: payments") || !strings.Contains(prompt, "- **sla-hours**: 4") {
+		t.Error("Prompt missing custom field values")
+	}
+}
+
+// TestBuildPrompt_WithPathScope tests path scope rendering
+func TestBuildPrompt_WithPathScope(t *testing.T) {
+	pb, err := NewPromptBuilder()
+	if err != nil {
+		t.Fatalf("NewPromptBuilder() failed: %v", err)
+	}
+
+	ctx := &PromptContext{
+		Issue:     &types.Issue{ID: "vc-102", Title: "Retry failed charges"},
+		PathScope: types.PathScope{"services/payments/**", "libs/money"},
+	}
+
+	prompt, err := pb.BuildPrompt(ctx)
+	if err != nil {
+		t.Fatalf("BuildPrompt() failed: %v", err)
+	}
+
+	if !strings.Contains(prompt, "## Scope") {
+		t.Error("Prompt missing 'Scope' section")
+	}
+	if !strings.Contains(prompt, "- services/payments/**\n- libs/money\n") {
+		t.Error("Prompt missing scope patterns")
+	}
+}
+
+// TestBuildPrompt_WithLFSPatterns tests Git LFS path rendering
+func TestBuildPrompt_WithLFSPatterns(t *testing.T) {
+	pb, err := NewPromptBuilder()
+	if err != nil {
+		t.Fatalf("NewPromptBuilder() failed: %v", err)
+	}
+
+	ctx := &PromptContext{
+		Issue:       &types.Issue{ID: "vc-103", Title: "Update the logo"},
+		LFSPatterns: []string{"*.psd", "assets/**"},
+	}
+
+	prompt, err := pb.BuildPrompt(ctx)
+	if err != nil {
+		t.Fatalf("BuildPrompt() failed: %v", err)
+	}
+
+	if !strings.Contains(prompt, "## Large Files") {
+		t.Error("Prompt missing 'Large Files' section")
+	}
+	if !strings.Contains(prompt, "- *.psd\n- assets/**\n") {
+		t.Error("Prompt missing LFS patterns")
+	}
+}
+
 // TestBuildPrompt_NilContext tests error handling for nil context
 func TestBuildPrompt_NilContext(t *testing.T) {
 	pb, err := NewPromptBuilder()
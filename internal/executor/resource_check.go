@@ -0,0 +1,152 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+// resourceShortage is a resource below its threshold
+type resourceShortage struct {
+	Resource string // "disk", "tmp" or "file descriptors"
+	Path     string // The directory checked, for disk and tmp
+	Free     uint64 // Free megabytes, or free file descriptors
+	Min      uint64
+}
+
+func (s resourceShortage) String() string {
+	if s.Path != "" {
+		return fmt.Sprintf("%s: %d MB free in %s, need %d MB", s.Resource, s.Free, s.Path, s.Min)
+	}
+	return fmt.Sprintf("%s: %d free, need %d", s.Resource, s.Free, s.Min)
+}
+
+// checkResources reports whether there are enough resources to start work:
+// free disk space for the working directory and sandboxes, free temp space
+// and file descriptor headroom. When something is short it first removes
+// retained failed sandboxes, if configured to, and checks again. A shortage
+// is reported when it starts and when it ends, not on every poll.
+func (e *Executor) checkResources(ctx context.Context) bool {
+	if e.config == nil || !e.config.ResourceCheck.Enabled {
+		return true
+	}
+
+	shortages := e.findResourceShortages()
+	if len(shortages) > 0 && e.config.ResourceCheck.Cleanup && e.sandboxMgr != nil {
+		fmt.Printf("Low on resources (%s), removing failed sandboxes\n", formatShortages(shortages))
+		if err := e.sandboxMgr.CleanupStaleFailedSandboxes(ctx, 1); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to remove failed sandboxes: %v\n", err)
+		}
+		shortages = e.findResourceShortages()
+	}
+
+	low := len(shortages) > 0
+	e.mu.Lock()
+	wasLow := e.resourcesLow
+	e.resourcesLow = low
+	e.mu.Unlock()
+
+	switch {
+	case low && !wasLow:
+		fmt.Fprintf(os.Stderr, "⏸️  Not claiming work, low on resources: %s\n", formatShortages(shortages))
+		details := make([]string, len(shortages))
+		for i, s := range shortages {
+			details[i] = s.String()
+		}
+		e.logEvent(ctx, events.EventTypeResourceCheck, events.SeverityWarning, "",
+			fmt.Sprintf("Not claiming work, low on resources: %s", formatShortages(shortages)),
+			map[string]interface{}{
+				"low":       true,
+				"shortages": details,
+			})
+	case !low && wasLow:
+		fmt.Printf("▶️  Resources recovered, resuming work\n")
+		e.logEvent(ctx, events.EventTypeResourceCheck, events.SeverityInfo, "",
+			"Resources recovered, resuming work",
+			map[string]interface{}{
+				"low": false,
+			})
+	}
+	return !low
+}
+
+// findResourceShortages returns the resources below their thresholds
+func (e *Executor) findResourceShortages() []resourceShortage {
+	cfg := e.config.ResourceCheck
+	var shortages []resourceShortage
+
+	if cfg.MinFreeDiskMB > 0 {
+		dirs := []string{e.workingDir}
+		// Sandboxes live under the working directory by default; check their
+		// root too when it is somewhere else
+		if e.sandboxMgr != nil && e.config.SandboxRoot != "" {
+			if rel, err := filepath.Rel(e.workingDir, e.config.SandboxRoot); err != nil || strings.HasPrefix(rel, "..") {
+				dirs = append(dirs, e.config.SandboxRoot)
+			}
+		}
+		for _, dir := range dirs {
+			if s, ok := checkFreeSpace("disk", dir, cfg.MinFreeDiskMB); !ok {
+				shortages = append(shortages, s)
+			}
+		}
+	}
+	if cfg.MinFreeTmpMB > 0 {
+		if s, ok := checkFreeSpace("tmp", os.TempDir(), cfg.MinFreeTmpMB); !ok {
+			shortages = append(shortages, s)
+		}
+	}
+	if cfg.MinFreeFDs > 0 {
+		if free, ok := freeFileDescriptors(); ok && free < uint64(cfg.MinFreeFDs) {
+			shortages = append(shortages, resourceShortage{Resource: "file descriptors", Free: free, Min: uint64(cfg.MinFreeFDs)})
+		}
+	}
+	return shortages
+}
+
+// checkFreeSpace checks that the filesystem holding dir has minMB free.
+// A directory that can't be checked (e.g. a sandbox root not created yet)
+// passes.
+func checkFreeSpace(resource, dir string, minMB int) (resourceShortage, bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return resourceShortage{}, true
+	}
+	free := uint64(stat.Bavail) * uint64(stat.Bsize) / (1024 * 1024)
+	if free >= uint64(minMB) {
+		return resourceShortage{}, true
+	}
+	return resourceShortage{Resource: resource, Path: dir, Free: free, Min: uint64(minMB)}, false
+}
+
+// freeFileDescriptors returns how many more files this process can open
+// before reaching its limit. ok is false where open descriptors can't be
+// counted.
+func freeFileDescriptors() (free uint64, ok bool) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, false
+	}
+	// /dev/fd lists this process's open descriptors on Linux and macOS
+	open, err := os.ReadDir("/dev/fd")
+	if err != nil {
+		return 0, false
+	}
+	if uint64(len(open)) >= uint64(limit.Cur) {
+		return 0, true
+	}
+	return uint64(limit.Cur) - uint64(len(open)), true
+}
+
+// formatShortages joins shortages for a log line
+func formatShortages(shortages []resourceShortage) string {
+	parts := make([]string, len(shortages))
+	for i, s := range shortages {
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+)
+
+func TestCheckResources(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	workDir := t.TempDir()
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(workDir, &stat); err != nil {
+		t.Skipf("can't check free space: %v", err)
+	}
+	freeMB := int(uint64(stat.Bavail) * uint64(stat.Bsize) / (1024 * 1024))
+
+	e := &Executor{store: store, instanceID: "exec-test", workingDir: workDir, config: &Config{
+		ResourceCheck: config.ResourceCheckConfig{Enabled: true, MinFreeDiskMB: freeMB + 1024},
+	}}
+
+	// Short on disk: nothing is claimed, and the shortage is reported once
+	for i := 0; i < 2; i++ {
+		if e.checkResources(ctx) {
+			t.Fatalf("check %d passed with %d MB free and %d MB needed", i, freeMB, freeMB+1024)
+		}
+	}
+	shortages := e.findResourceShortages()
+	if len(shortages) != 1 || shortages[0].Resource != "disk" || shortages[0].Path != workDir {
+		t.Errorf("shortages = %v, want the working directory's disk", shortages)
+	}
+
+	// Recovered: work resumes
+	e.config.ResourceCheck.MinFreeDiskMB = 1
+	if !e.checkResources(ctx) {
+		t.Errorf("check failed after lowering the threshold: %v", e.findResourceShortages())
+	}
+
+	recorded, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeResourceCheck})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(recorded) != 2 {
+		t.Errorf("got %d resource check events, want 2 (low, then recovered)", len(recorded))
+	}
+
+	// Disabled checks always pass
+	e.config.ResourceCheck = config.ResourceCheckConfig{MinFreeDiskMB: freeMB + 1024}
+	if !e.checkResources(ctx) {
+		t.Error("disabled resource check failed")
+	}
+}
+
+func TestFreeFileDescriptors(t *testing.T) {
+	free, ok := freeFileDescriptors()
+	if !ok {
+		t.Skip("can't count open file descriptors here")
+	}
+	if free == 0 {
+		t.Error("no free file descriptors reported for the test process")
+	}
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/hosting"
+)
+
+// checkRunPrefix starts the name of the check run of each gate, e.g.
+// "vc / test"
+const checkRunPrefix = "vc / "
+
+// reportCheckRuns reports each gate result as a check run on the head of the
+// branch just pushed, annotated with the failures found in the gate's
+// output, so reviewers see the gates' verdicts in the pull request. Errors
+// are only warned about: the pull request is open either way.
+func (rp *ResultsProcessor) reportCheckRuns(ctx context.Context, provider hosting.Provider, gateResults []*gates.Result) {
+	reporter, ok := provider.(hosting.CheckReporter)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "warning: %s has no check runs; gate results are only in the pull request description\n",
+			hosting.DisplayName(provider.Name()))
+		return
+	}
+	head, err := exec.CommandContext(ctx, "git", "-C", rp.workingDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to resolve HEAD for check runs: %v\n", err)
+		return
+	}
+	files := rp.trackedFiles(ctx)
+	for _, result := range gateResults {
+		run := gateCheckRun(result, strings.TrimSpace(string(head)), func(file string) string {
+			return checkPath(file, rp.workingDir, files)
+		})
+		if _, err := reporter.CreateCheckRun(ctx, run); err != nil {
+			// Every other gate would fail alike, e.g. without a GitHub App token
+			fmt.Fprintf(os.Stderr, "warning: failed to report gate results as check runs: %v\n", err)
+			return
+		}
+	}
+	fmt.Printf("✓ Reported %d gate result(s) as check runs\n", len(gateResults))
+}
+
+// gateCheckRun returns the check run reporting a gate result on commit sha.
+// path resolves the files failures point at to repository paths, "" for
+// those outside the repository, which aren't annotated.
+func gateCheckRun(result *gates.Result, sha string, path func(string) string) hosting.CheckRun {
+	run := hosting.CheckRun{Name: checkRunPrefix + string(result.Gate), HeadSHA: sha}
+	switch {
+	case result.Skipped:
+		run.Conclusion, run.Title = hosting.CheckSkipped, "Skipped"
+		run.Summary = fmt.Sprintf("The %s gate was skipped: %s", result.Gate, result.SkipReason)
+		return run
+	case result.Passed:
+		run.Conclusion, run.Title = hosting.CheckSuccess, "Passed"
+		run.Summary = fmt.Sprintf("The %s gate passed.", result.Gate)
+	default:
+		run.Conclusion, run.Title = hosting.CheckFailure, "Failed"
+		run.Summary = fmt.Sprintf("The %s gate failed.", result.Gate)
+		if result.Error != nil {
+			run.Summary = fmt.Sprintf("The %s gate failed: %v", result.Gate, result.Error)
+		}
+		for _, f := range gates.ParseFailures(result.Output) {
+			if p := path(f.File); p != "" {
+				run.Annotations = append(run.Annotations, hosting.CheckAnnotation{Path: p, Line: f.Line, Title: f.Test, Message: f.Message})
+			}
+		}
+		if n := len(run.Annotations); n > 0 {
+			run.Title = fmt.Sprintf("%d failure(s)", n)
+		}
+	}
+	if output := strings.TrimSpace(result.Output); output != "" {
+		run.Text = "```\n" + output + "\n```"
+	}
+	return run
+}
+
+// trackedFiles lists the files git tracks in the working directory, which
+// failures are resolved against
+func (rp *ResultsProcessor) trackedFiles(ctx context.Context) []string {
+	out, err := exec.CommandContext(ctx, "git", "-C", rp.workingDir, "ls-files").Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSpace(string(out)), "\n")
+}
+
+// checkPath resolves a file a gate's output mentions to its path in the
+// repository at root: absolute paths are made relative, and base names (go
+// test prints only those) are matched against the tracked files. Returns ""
+// if the file isn't tracked or the match is ambiguous.
+func checkPath(file, root string, tracked []string) string {
+	if filepath.IsAbs(file) {
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return ""
+		}
+		file = rel
+	}
+	file = filepath.ToSlash(filepath.Clean(file))
+	match := ""
+	for _, t := range tracked {
+		if t == file {
+			return t
+		}
+	}
+	for _, t := range tracked {
+		if strings.HasSuffix(t, "/"+file) {
+			if match != "" {
+				return ""
+			}
+			match = t
+		}
+	}
+	return match
+}
@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/hosting"
+)
+
+func TestCheckPath(t *testing.T) {
+	tracked := []string{"main.go", "checkout/cart_test.go", "api/handler.go", "web/handler.go"}
+	tests := map[string]string{
+		"main.go":                      "main.go",
+		"./main.go":                    "main.go",
+		"/repo/checkout/cart_test.go":  "checkout/cart_test.go",
+		"cart_test.go":                 "checkout/cart_test.go", // go test prints base names
+		"handler.go":                   "",                      // Ambiguous
+		"/usr/lib/go/src/fmt/print.go": "",
+		"gone.go":                      "",
+	}
+	for file, want := range tests {
+		if got := checkPath(file, "/repo", tracked); got != want {
+			t.Errorf("checkPath(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestGateCheckRun(t *testing.T) {
+	path := func(file string) string { return checkPath(file, "/repo", []string{"checkout/cart_test.go"}) }
+
+	skipped := gateCheckRun(&gates.Result{Gate: gates.GateLint, Passed: true, Skipped: true, SkipReason: "approved override"}, "abc", path)
+	if skipped.Name != "vc / lint" || skipped.Conclusion != hosting.CheckSkipped || skipped.Summary != "The lint gate was skipped: approved override" {
+		t.Errorf("skipped gate = %+v", skipped)
+	}
+
+	failed := gateCheckRun(&gates.Result{Gate: gates.GateTest, Error: errors.New("exit status 1"),
+		Output: "--- FAIL: TestTotal (0.00s)\n    cart_test.go:12: total = 5, want 0\nFAIL\n"}, "abc", path)
+	want := hosting.CheckAnnotation{Path: "checkout/cart_test.go", Line: 12, Title: "TestTotal", Message: "total = 5, want 0"}
+	if failed.Conclusion != hosting.CheckFailure || failed.HeadSHA != "abc" || failed.Title != "1 failure(s)" ||
+		failed.Summary != "The test gate failed: exit status 1" || len(failed.Annotations) != 1 || failed.Annotations[0] != want {
+		t.Errorf("failed gate = %+v", failed)
+	}
+}
@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+)
+
+// pendingAnalysis is a supervisor call running in the background
+type pendingAnalysis[T any] struct {
+	done   chan struct{}
+	result T
+	err    error
+}
+
+// startAnalysis runs call in the background, so supervisor calls that don't
+// depend on each other overlap instead of running back to back. Concurrent
+// calls still queue on the supervisor's semaphore (vc-220), so this never
+// exceeds the configured number of in-flight API calls. A panic in call is
+// returned as its error rather than taking down the executor.
+func startAnalysis[T any](ctx context.Context, call func(context.Context) (T, error)) *pendingAnalysis[T] {
+	p := &pendingAnalysis[T]{done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		defer func() {
+			if r := recover(); r != nil {
+				p.err = fmt.Errorf("analysis panicked: %v", r)
+			}
+		}()
+		p.result, p.err = call(ctx)
+	}()
+	return p
+}
+
+// wait blocks until the call returns and gives its result
+func (p *pendingAnalysis[T]) wait() (T, error) {
+	<-p.done
+	return p.result, p.err
+}
@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStartAnalysis(t *testing.T) {
+	ctx := context.Background()
+
+	// Two calls that each wait for the other only finish if they overlap
+	a, b := make(chan struct{}), make(chan struct{})
+	first := startAnalysis(ctx, func(context.Context) (string, error) {
+		close(a)
+		<-b
+		return "first", nil
+	})
+	second := startAnalysis(ctx, func(context.Context) (string, error) {
+		close(b)
+		<-a
+		return "", errors.New("boom")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if result, err := first.wait(); err != nil || result != "first" {
+			t.Errorf("first.wait() = (%q, %v), want (first, nil)", result, err)
+		}
+		if _, err := second.wait(); err == nil || err.Error() != "boom" {
+			t.Errorf("second.wait() error = %v, want boom", err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("analyses did not run concurrently")
+	}
+}
+
+func TestStartAnalysisPanic(t *testing.T) {
+	pending := startAnalysis(context.Background(), func(context.Context) (int, error) {
+		panic("nil supervisor")
+	})
+	if _, err := pending.wait(); err == nil {
+		t.Error("panicking analysis returned no error")
+	}
+}
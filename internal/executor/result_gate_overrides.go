@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// resolveGateOverrides looks up approved gate-override labels for the issue.
+// Requests that aren't validly approved are reported on the issue so a human
+// knows what's missing; they don't skip anything. Each is reported once per
+// request, not on every gate run.
+func (rp *ResultsProcessor) resolveGateOverrides(ctx context.Context, issue *types.Issue) []*gates.GateOverride {
+	// The executor itself and the AI supervisor must never approve an override
+	automatedActors := gates.AutomatedActors(ctx, rp.store, rp.actor)
+
+	overrides, pending, err := gates.ResolveOverrides(ctx, rp.store, issue.ID, automatedActors)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to resolve gate overrides for %s: %v (running all gates)\n", issue.ID, err)
+		return nil
+	}
+
+	var comments []*types.Comment
+	if len(pending) > 0 {
+		if comments, err = rp.store.GetComments(ctx, issue.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to get comments of %s: %v\n", issue.ID, err)
+		}
+	}
+	for _, p := range pending {
+		comment := fmt.Sprintf("Gate override for **%s** requested but not applied: %s", p.Gate, p.Reason)
+		if commentedSince(comments, "quality-gates", comment, p.RequestedAt) {
+			continue
+		}
+		if err := rp.store.AddComment(ctx, issue.ID, "quality-gates", comment); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add pending override comment: %v\n", err)
+		}
+	}
+
+	return overrides
+}
+
+// commentedSince reports whether author already posted body at or after since
+func commentedSince(comments []*types.Comment, author, body string, since time.Time) bool {
+	for _, c := range comments {
+		if c.Author == author && c.Body == body && !c.CreatedAt.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// logGateOverrides records an audit event for every gate skipped by an approved override
+func (rp *ResultsProcessor) logGateOverrides(ctx context.Context, issue *types.Issue, overrides []*gates.GateOverride, gateResults []*gates.Result) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	byGate := make(map[gates.GateType]*gates.GateOverride, len(overrides))
+	for _, o := range overrides {
+		byGate[o.Gate] = o
+	}
+
+	for _, result := range gateResults {
+		override, ok := byGate[result.Gate]
+		if !ok || !result.Skipped {
+			continue
+		}
+		rp.logEvent(ctx, events.EventTypeQualityGateOverridden, events.SeverityWarning, issue.ID,
+			fmt.Sprintf("Quality gate %s skipped for %s (approved by %s)", result.Gate, issue.ID, override.ApprovedBy),
+			map[string]interface{}{
+				"gate":         string(result.Gate),
+				"requested_by": override.RequestedBy,
+				"approved_by":  override.ApprovedBy,
+				"approved_at":  override.ApprovedAt.Format(time.RFC3339),
+			})
+	}
+}
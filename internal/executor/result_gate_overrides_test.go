@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestResolveGateOverridesCommentsOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Flaky lint", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "gate-override:lint", "alice"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	rp := &ResultsProcessor{store: store, actor: "executor-1"}
+	for i := 0; i < 3; i++ {
+		if overrides := rp.resolveGateOverrides(ctx, issue); len(overrides) != 0 {
+			t.Fatalf("unapproved override applied: %+v", overrides)
+		}
+	}
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected one pending override comment across gate runs, got %d", len(comments))
+	}
+
+	// A self-approval leaves the request pending for a new reason, reported once
+	if err := store.AddLabel(ctx, issue.ID, "gate-override-approved:lint", "alice"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if overrides := rp.resolveGateOverrides(ctx, issue); len(overrides) != 0 {
+			t.Fatalf("self-approved override applied: %+v", overrides)
+		}
+	}
+	comments, err = store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("expected a second comment for the self-approval, got %d", len(comments))
+	}
+}
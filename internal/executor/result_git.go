@@ -8,19 +8,49 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/vc/internal/config"
 	"github.com/steveyegge/vc/internal/gates"
 	"github.com/steveyegge/vc/internal/git"
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// getUncommittedDiff gets the git diff for uncommitted changes
-func (rp *ResultsProcessor) getUncommittedDiff(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "git", "-C", rp.workingDir, "diff", "HEAD")
-	output, err := cmd.Output()
+// trackedGitOps wraps rp.gitOps with event tracking for issueID, falling back
+// to the untracked operations if the tracker can't be created
+func (rp *ResultsProcessor) trackedGitOps(issueID string) git.GitOperations {
+	trackedGit, err := git.NewEventTracker(&git.EventTrackerConfig{
+		Git:        rp.gitOps,
+		Store:      rp.store,
+		IssueID:    issueID,
+		ExecutorID: rp.actor,
+		AgentID:    "results-processor",
+	})
 	if err != nil {
-		return "", fmt.Errorf("git diff failed: %w", err)
+		fmt.Fprintf(os.Stderr, "warning: failed to create git event tracker: %v\n", err)
+		return rp.gitOps
 	}
-	return string(output), nil
+	return trackedGit
+}
+
+// gitPushChecks returns the pre-push checks to run, or nil if they are off
+func (rp *ResultsProcessor) gitPushChecks() *git.PushChecks {
+	if !rp.pushChecks.Enabled {
+		return nil
+	}
+	return &git.PushChecks{
+		MaxFileSize:    int64(rp.pushChecks.MaxFileSizeKB) * 1024,
+		AllowBinary:    rp.pushChecks.AllowBinary,
+		AllowForcePush: rp.pushChecks.AllowForcePush,
+		ScanSecrets:    rp.pushChecks.ScanSecrets,
+	}
+}
+
+// diffSummarizer returns the commit message generator's diff summarizer, so
+// code review and commit messages share summaries of the same files
+func (rp *ResultsProcessor) diffSummarizer() *git.DiffSummarizer {
+	if rp.messageGen != nil {
+		return rp.messageGen.Diffs()
+	}
+	return git.NewDiffSummarizer(nil)
 }
 
 // getCommitDiff gets the git diff for a specific commit using git directly
@@ -134,30 +164,13 @@ func (rp *ResultsProcessor) getExistingTests(ctx context.Context) (string, error
 	return testContent.String(), nil
 }
 
-// autoCommit performs auto-commit with AI-generated message.
+// autoCommit performs auto-commit with AI-generated message, attributed to
+// the agent run in agentResult.
 // Returns the commit hash if successful, empty string if no changes to commit.
-func (rp *ResultsProcessor) autoCommit(ctx context.Context, issue *types.Issue) (string, error) {
+func (rp *ResultsProcessor) autoCommit(ctx context.Context, issue *types.Issue, agentResult *AgentResult) (string, error) {
 	fmt.Printf("\n=== Auto-commit ===\n")
 
-	// Wrap git operations with event tracking
-	trackedGit, err := git.NewEventTracker(&git.EventTrackerConfig{
-		Git:        rp.gitOps,
-		Store:      rp.store,
-		IssueID:    issue.ID,
-		ExecutorID: rp.actor,
-		AgentID:    "results-processor",
-	})
-	if err != nil {
-		// Fallback to regular git ops if event tracker fails
-		fmt.Fprintf(os.Stderr, "warning: failed to create git event tracker: %v\n", err)
-		trackedGit = nil
-	}
-
-	// Use tracked git if available, otherwise use regular git ops
-	gitOps := rp.gitOps
-	if trackedGit != nil {
-		gitOps = trackedGit
-	}
+	gitOps := rp.trackedGitOps(issue.ID)
 
 	// Check for context cancellation before starting auto-commit (vc-25e5)
 	if ctx.Err() != nil {
@@ -182,27 +195,116 @@ func (rp *ResultsProcessor) autoCommit(ctx context.Context, issue *types.Issue)
 	}
 
 	// Collect all changed files
-	changedFiles := append([]string{}, status.Modified...)
-	changedFiles = append(changedFiles, status.Added...)
-	changedFiles = append(changedFiles, status.Deleted...)
-	changedFiles = append(changedFiles, status.Renamed...)
-	changedFiles = append(changedFiles, status.Untracked...)
+	changedFiles := statusFiles(status)
 
 	fmt.Printf("Found %d changed files\n", len(changedFiles))
 
+	// Apply path filters: only matching files are staged and committed, the
+	// rest stay in the working tree
+	filtered := len(rp.autoCommitPaths) > 0 || len(rp.autoCommitExcludePaths) > 0
+	if filtered {
+		kept, skipped := filterAutoCommitPaths(changedFiles, rp.autoCommitPaths, rp.autoCommitExcludePaths)
+		if len(skipped) > 0 {
+			fmt.Printf("Leaving %d files out of the commit (auto-commit path filters): %s\n",
+				len(skipped), strings.Join(skipped, ", "))
+		}
+		if len(kept) == 0 {
+			fmt.Printf("No changed files match the auto-commit path filters - skipping commit\n")
+			return "", nil
+		}
+		changedFiles = kept
+	}
+
+	// Only commit files within the issue's path scope
+	if len(rp.pathScope) > 0 {
+		filtered = true
+		kept, skipped := rp.pathScope.Split(changedFiles)
+		if len(skipped) > 0 {
+			fmt.Printf("Leaving %d files out of the commit (outside scope %s): %s\n",
+				len(skipped), rp.pathScope, strings.Join(skipped, ", "))
+		}
+		if len(kept) == 0 {
+			fmt.Printf("No changed files within scope %s - skipping commit\n", rp.pathScope)
+			return "", nil
+		}
+		changedFiles = kept
+	}
+
+	// Submodules are committed as pointers only: changes inside them, and
+	// moved pointers that are reverted rather than committed, stay out
+	if skip := rp.uncommittableSubmodules(ctx); len(skip) > 0 {
+		skipped := make(map[string]bool, len(skip))
+		for _, path := range skip {
+			skipped[path] = true
+		}
+		var kept []string
+		for _, file := range changedFiles {
+			if !skipped[file] {
+				kept = append(kept, file)
+			}
+		}
+		if len(kept) < len(changedFiles) {
+			filtered = true
+			fmt.Printf("Leaving submodules out of the commit: %s\n", strings.Join(skip, ", "))
+			if len(kept) == 0 {
+				fmt.Printf("No changes to commit outside submodules - skipping commit\n")
+				return "", nil
+			}
+			changedFiles = kept
+		}
+	}
+
+	// Amend-on-retry: if a previous attempt at this issue committed and
+	// nothing has landed since, fold this attempt into that commit
+	var amendCommit string
+	if rp.autoCommitAmendOnRetry {
+		amendCommit = rp.previousAttemptCommit(ctx, issue)
+	}
+	messageFiles := changedFiles
+	if amendCommit != "" {
+		fmt.Printf("Amending commit %s from a previous attempt\n", safeShortHash(amendCommit))
+		// The amended commit keeps the previous attempt's changes, so the
+		// message must describe them too
+		previousFiles, err := rp.getCommitFiles(ctx, amendCommit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to list files of commit %s: %v\n", safeShortHash(amendCommit), err)
+		}
+		messageFiles = mergeFileLists(previousFiles, changedFiles)
+	}
+
 	// Check for context cancellation before expensive AI operation (vc-25e5)
 	if ctx.Err() != nil {
 		return "", ctx.Err()
 	}
 
-	// Step 3: Generate commit message using AI
+	// Step 3: Generate commit message using AI, following the repository's
+	// conventions (.vc/commit_template) if it has any
+	template, err := git.LoadCommitTemplate(rp.workingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v (using the default commit message format)\n", err)
+		template = git.DefaultCommitTemplate()
+	}
 	req := git.CommitMessageRequest{
 		IssueID:          issue.ID,
 		IssueTitle:       issue.Title,
 		IssueDescription: issue.Description,
-		ChangedFiles:     changedFiles,
-		// Note: We're skipping diff for now to keep prompt size manageable
-		// Could add: Diff: getDiff() if needed for better messages
+		ChangedFiles:     messageFiles,
+		Template:         template,
+	}
+
+	// The diff of what's being committed; an amended commit's own changes
+	// are part of it. The generator summarizes files that don't fit.
+	diffBase := ""
+	if amendCommit != "" {
+		diffBase = amendCommit + "^"
+	}
+	if diff, err := gitOps.Diff(ctx, rp.workingDir, diffBase); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get diff for commit message: %v (continuing without it)\n", err)
+	} else {
+		if filtered {
+			diff = diff.Only(messageFiles)
+		}
+		req.Diff = rp.promptDiff(ctx, diff).String()
 	}
 
 	fmt.Printf("Generating commit message via AI...\n")
@@ -230,13 +332,22 @@ func (rp *ResultsProcessor) autoCommit(ctx context.Context, issue *types.Issue)
 	}
 
 	// Step 4: Commit the changes
+	coAuthors, trailers := rp.commitTrailers(agentResult)
 	commitOpts := git.CommitOptions{
-		Message: commitMessage,
-		CoAuthors: []string{
-			"Claude <noreply@anthropic.com>",
-		},
-		AddAll:     true, // Stage all changes
+		Message:    commitMessage,
+		CoAuthors:  coAuthors,
+		Trailers:   trailers,
+		AddAll:     !filtered, // Stage all changes unless path filters or a scope apply
 		AllowEmpty: false,
+		Amend:      amendCommit != "",
+
+		CommitterName:  rp.commitSigning.CommitterName,
+		CommitterEmail: rp.commitSigning.CommitterEmail,
+		SignFormat:     rp.commitSigning.Format,
+		SigningKey:     rp.commitSigning.Key,
+	}
+	if filtered {
+		commitOpts.Paths = changedFiles
 	}
 
 	commitHash, err := gitOps.CommitChanges(ctx, rp.workingDir, commitOpts)
@@ -247,6 +358,155 @@ func (rp *ResultsProcessor) autoCommit(ctx context.Context, issue *types.Issue)
 	return commitHash, nil
 }
 
+// agentCoAuthors are the identities agents sign their commits with
+var agentCoAuthors = map[AgentType]string{
+	AgentTypeClaudeCode: "Claude <noreply@anthropic.com>",
+	AgentTypeAmp:        "Amp <amp@ampcode.com>",
+}
+
+// commitTrailers returns the Co-Authored-By identities and VC trailers that
+// attribute an auto-commit to the agent and execution that made it:
+//
+//	Co-Authored-By: Claude <noreply@anthropic.com>
+//	VC-Agent: claude-code (claude-sonnet-4-5)
+//	VC-Execution: 42
+func (rp *ResultsProcessor) commitTrailers(agentResult *AgentResult) (coAuthors, trailers []string) {
+	var provider AgentType
+	var executionID int64
+	if rp.execution != nil {
+		provider = AgentType(rp.execution.AgentProvider)
+		executionID = rp.execution.ID
+	}
+
+	switch coAuthor := rp.commitAttribution.CoAuthor; coAuthor {
+	case config.CoAuthorNone:
+	case "":
+		if identity, ok := agentCoAuthors[provider]; ok {
+			coAuthors = append(coAuthors, identity)
+		} else {
+			// Unknown agents have always been credited as Claude
+			coAuthors = append(coAuthors, agentCoAuthors[AgentTypeClaudeCode])
+		}
+	default:
+		coAuthors = append(coAuthors, coAuthor)
+	}
+
+	if !rp.commitAttribution.AgentTrailers {
+		return coAuthors, nil
+	}
+	if provider != "" {
+		agent := string(provider)
+		if model := agentResult.Model(); model != "" {
+			agent += " (" + model + ")"
+		}
+		trailers = append(trailers, "VC-Agent: "+agent)
+	}
+	if executionID != 0 {
+		trailers = append(trailers, fmt.Sprintf("VC-Execution: %d", executionID))
+	}
+	return coAuthors, trailers
+}
+
+// previousAttemptCommit returns the commit made by the latest earlier
+// execution of issue if it is still HEAD, or "" if there is none, other
+// commits have landed on top of it, or it has been pushed (amending it would
+// rewrite published history)
+func (rp *ResultsProcessor) previousAttemptCommit(ctx context.Context, issue *types.Issue) string {
+	executions, err := rp.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to list previous executions of %s: %v\n", issue.ID, err)
+		return ""
+	}
+	var previous string
+	for _, execution := range executions {
+		// Newest first; running executions (including this one) haven't committed
+		if execution.Status.IsFinal() && execution.CommitHash != "" {
+			previous = execution.CommitHash
+			break
+		}
+	}
+	if previous == "" {
+		return ""
+	}
+
+	output, err := exec.CommandContext(ctx, "git", "-C", rp.workingDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to resolve HEAD: %v\n", err)
+		return ""
+	}
+	if strings.TrimSpace(string(output)) != previous {
+		return ""
+	}
+
+	output, err = exec.CommandContext(ctx, "git", "-C", rp.workingDir, "for-each-ref", "--contains", previous, "--format=%(refname)", "refs/remotes").Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check whether %s was pushed: %v\n", safeShortHash(previous), err)
+		return ""
+	}
+	if strings.TrimSpace(string(output)) != "" {
+		return "" // Already on a remote
+	}
+	return previous
+}
+
+// getCommitFiles lists the files a commit changed
+func (rp *ResultsProcessor) getCommitFiles(ctx context.Context, commitHash string) ([]string, error) {
+	if !isValidGitRef(commitHash) {
+		return nil, fmt.Errorf("invalid commit hash format: %s", commitHash)
+	}
+	cmd := exec.CommandContext(ctx, "git", "-C", rp.workingDir, "show", "--name-only", "--format=", commitHash)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show failed: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// mergeFileLists returns the files in a followed by those in b not in a
+func mergeFileLists(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, file := range append(append([]string{}, a...), b...) {
+		if !seen[file] {
+			seen[file] = true
+			merged = append(merged, file)
+		}
+	}
+	return merged
+}
+
+// filterAutoCommitPaths splits changed files into those to commit and those
+// to leave out. With include patterns, only matching files are kept; files
+// matching an exclude pattern are always left out.
+func filterAutoCommitPaths(files, include, exclude []string) (kept, skipped []string) {
+	for _, file := range files {
+		if (len(include) == 0 || matchesAnyPathPattern(file, include)) && !matchesAnyPathPattern(file, exclude) {
+			kept = append(kept, file)
+		} else {
+			skipped = append(skipped, file)
+		}
+	}
+	return kept, skipped
+}
+
+// matchesAnyPathPattern reports whether file matches one of patterns
+func matchesAnyPathPattern(file string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if types.MatchPathPattern(file, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePathPattern checks that an auto-commit path pattern is a valid glob
+func validatePathPattern(pattern string) error {
+	if err := types.ValidatePathPattern(pattern); err != nil {
+		return fmt.Errorf("invalid auto-commit path pattern: %w", err)
+	}
+	return nil
+}
+
 // isVCRepo checks if the working directory is the VC repository
 // This is used to determine if quality gates should run (vc-144)
 func (rp *ResultsProcessor) isVCRepo() bool {
@@ -272,7 +532,8 @@ func (rp *ResultsProcessor) isVCRepo() bool {
 	return false
 }
 
-// createAutoPR creates a GitHub PR using gh CLI after successful auto-commit (vc-389e)
+// createAutoPR creates a PR after successful auto-commit (vc-389e): through the GitHub
+// or GitLab API when a token is configured, otherwise a GitHub PR using the gh CLI
 // Returns the PR URL if successful, empty string if PR creation was skipped or failed
 func (rp *ResultsProcessor) createAutoPR(ctx context.Context, issue *types.Issue, commitHash string, gateResults []*gates.Result) (string, error) {
 	fmt.Printf("\n=== Auto-PR Creation ===\n")
@@ -282,11 +543,6 @@ func (rp *ResultsProcessor) createAutoPR(ctx context.Context, issue *types.Issue
 		return "", ctx.Err()
 	}
 
-	// Check if gh CLI is available
-	if _, err := exec.LookPath("gh"); err != nil {
-		return "", fmt.Errorf("gh CLI not found: %w (install from https://cli.github.com/)", err)
-	}
-
 	// Get current branch name
 	branchCmd := exec.CommandContext(ctx, "git", "-C", rp.workingDir, "branch", "--show-current")
 	branchOutput, err := branchCmd.Output()
@@ -326,16 +582,44 @@ func (rp *ResultsProcessor) createAutoPR(ctx context.Context, issue *types.Issue
 	}
 
 	bodyBuilder.WriteString(fmt.Sprintf("\n## Commit\n\n- %s\n\n", commitHash))
+
+	// Suggest reviewers from who owns and recently worked on the files changed
+	reviewers := rp.suggestReviewers(ctx, issue, rp.commitFiles(ctx, commitHash), reviewTriggerPullRequest)
+	if reviewers != nil {
+		bodyBuilder.WriteString("## Suggested Reviewers\n\n")
+		bodyBuilder.WriteString(formatReviewers(reviewers))
+		bodyBuilder.WriteString("\n")
+	}
 	bodyBuilder.WriteString("---\n")
 	bodyBuilder.WriteString("🤖 Generated with [Claude Code](https://claude.com/claude-code)\n")
 
 	prBody := bodyBuilder.String()
 
-	// Create PR using gh CLI
-	cmd := exec.CommandContext(ctx, "gh", "pr", "create",
+	// With a token, push and open the PR through the hosting provider's API
+	if rp.hosting.Enabled() {
+		return rp.createHostedPR(ctx, issue, branchName, prTitle, prBody, reviewers, gateResults)
+	}
+
+	// Otherwise create PR using gh CLI
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "", fmt.Errorf("gh CLI not found: %w (install from https://cli.github.com/ or set VC_GITHUB_TOKEN)", err)
+	}
+	if checks := rp.gitPushChecks(); checks != nil {
+		if err := rp.trackedGitOps(issue.ID).CheckPush(ctx, rp.workingDir, git.PushOptions{Branch: branchName}, *checks); err != nil {
+			return "", err
+		}
+	}
+	args := []string{"pr", "create",
 		"--title", prTitle,
 		"--body", prBody,
-		"--head", branchName)
+		"--head", branchName}
+	if rp.reviewers.RequestReviews {
+		users, teams := reviewers.requested()
+		if requested := append(users, teams...); len(requested) > 0 {
+			args = append(args, "--reviewer", strings.Join(requested, ","))
+		}
+	}
+	cmd := exec.CommandContext(ctx, "gh", args...)
 	cmd.Dir = rp.workingDir
 
 	output, err := cmd.CombinedOutput()
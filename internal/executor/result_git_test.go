@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestFilterAutoCommitPaths(t *testing.T) {
+	files := []string{"cmd/vc/main.go", "internal/git/git.go", "internal/git/git_test.go", "notes.txt"}
+
+	kept, skipped := filterAutoCommitPaths(files, []string{"internal"}, []string{"*_test.go"})
+	if !reflect.DeepEqual(kept, []string{"internal/git/git.go"}) {
+		t.Errorf("kept = %v", kept)
+	}
+	if !reflect.DeepEqual(skipped, []string{"cmd/vc/main.go", "internal/git/git_test.go", "notes.txt"}) {
+		t.Errorf("skipped = %v", skipped)
+	}
+
+	kept, skipped = filterAutoCommitPaths(files, nil, []string{"*.txt"})
+	if len(kept) != 3 || !reflect.DeepEqual(skipped, []string{"notes.txt"}) {
+		t.Errorf("exclude only: kept %v, skipped %v", kept, skipped)
+	}
+}
+
+func TestValidatePathPattern(t *testing.T) {
+	if err := validatePathPattern("internal/*.go"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validatePathPattern("["); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+	if err := validatePathPattern(" "); err == nil {
+		t.Error("expected an error for an empty pattern")
+	}
+}
+
+func TestPreviousAttemptCommit(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Retry me", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	head := func() string {
+		out, err := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+		if err != nil {
+			t.Fatalf("git rev-parse failed: %v", err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	rp := &ResultsProcessor{store: store, workingDir: repoDir}
+	if got := rp.previousAttemptCommit(ctx, issue); got != "" {
+		t.Errorf("expected no previous commit without executions, got %q", got)
+	}
+
+	previous := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionIncomplete, CommitHash: head()}
+	if err := store.CreateExecution(ctx, previous); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+	current := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning}
+	if err := store.CreateExecution(ctx, current); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+	if got := rp.previousAttemptCommit(ctx, issue); got != previous.CommitHash {
+		t.Errorf("previousAttemptCommit = %q, want %q", got, previous.CommitHash)
+	}
+
+	// A pushed commit isn't amended either
+	if err := exec.Command("git", "-C", repoDir, "update-ref", "refs/remotes/origin/main", previous.CommitHash).Run(); err != nil {
+		t.Fatalf("git update-ref failed: %v", err)
+	}
+	if got := rp.previousAttemptCommit(ctx, issue); got != "" {
+		t.Errorf("expected no commit to amend once it was pushed, got %q", got)
+	}
+	if err := exec.Command("git", "-C", repoDir, "update-ref", "-d", "refs/remotes/origin/main").Run(); err != nil {
+		t.Fatalf("git update-ref -d failed: %v", err)
+	}
+
+	// Once another commit lands on top, the previous attempt can't be amended
+	if err := exec.Command("git", "-C", repoDir, "commit", "--allow-empty", "-m", "someone else").Run(); err != nil {
+		t.Fatalf("git commit failed: %v", err)
+	}
+	if got := rp.previousAttemptCommit(ctx, issue); got != "" {
+		t.Errorf("expected no commit to amend after HEAD moved, got %q", got)
+	}
+}
+
+func TestCommitTrailers(t *testing.T) {
+	agentResult := &AgentResult{ParsedJSON: []AgentMessage{
+		{Type: "system", Subtype: "init", Model: "claude-sonnet-4-5"},
+		{Type: "result"},
+	}}
+	execution := &types.Execution{ID: 42, AgentProvider: string(AgentTypeClaudeCode)}
+
+	tests := []struct {
+		name          string
+		attribution   config.CommitAttributionConfig
+		execution     *types.Execution
+		wantCoAuthors []string
+		wantTrailers  []string
+	}{
+		{
+			name:          "defaults",
+			attribution:   config.DefaultCommitAttributionConfig(),
+			execution:     execution,
+			wantCoAuthors: []string{"Claude <noreply@anthropic.com>"},
+			wantTrailers:  []string{"VC-Agent: claude-code (claude-sonnet-4-5)", "VC-Execution: 42"},
+		},
+		{
+			name:          "amp",
+			attribution:   config.DefaultCommitAttributionConfig(),
+			execution:     &types.Execution{ID: 7, AgentProvider: string(AgentTypeAmp)},
+			wantCoAuthors: []string{"Amp <amp@ampcode.com>"},
+			wantTrailers:  []string{"VC-Agent: amp (claude-sonnet-4-5)", "VC-Execution: 7"},
+		},
+		{
+			name:          "custom co-author, no trailers",
+			attribution:   config.CommitAttributionConfig{CoAuthor: "vc-bot <vc-bot@example.com>"},
+			execution:     execution,
+			wantCoAuthors: []string{"vc-bot <vc-bot@example.com>"},
+		},
+		{
+			name:         "no co-author",
+			attribution:  config.CommitAttributionConfig{CoAuthor: config.CoAuthorNone, AgentTrailers: true},
+			execution:    execution,
+			wantTrailers: []string{"VC-Agent: claude-code (claude-sonnet-4-5)", "VC-Execution: 42"},
+		},
+		{
+			name:          "no execution record",
+			attribution:   config.DefaultCommitAttributionConfig(),
+			wantCoAuthors: []string{"Claude <noreply@anthropic.com>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := &ResultsProcessor{commitAttribution: tt.attribution, execution: tt.execution}
+			coAuthors, trailers := rp.commitTrailers(agentResult)
+			if !reflect.DeepEqual(coAuthors, tt.wantCoAuthors) {
+				t.Errorf("coAuthors = %v, want %v", coAuthors, tt.wantCoAuthors)
+			}
+			if !reflect.DeepEqual(trailers, tt.wantTrailers) {
+				t.Errorf("trailers = %v, want %v", trailers, tt.wantTrailers)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/hosting"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// createHostedPR pushes branch (rebasing it onto the remote branch if that
+// advanced) and opens a pull request (a merge request on GitLab) for it
+// through the hosting provider's API, then records it so its status is
+// tracked. The title and body are AI-generated when possible;
+// title and body are the fallback. Suggested reviewers are listed in either,
+// and the code owners among them asked to review if configured. With check
+// runs enabled, gateResults are also reported as check runs.
+// Returns the pull request URL.
+func (rp *ResultsProcessor) createHostedPR(ctx context.Context, issue *types.Issue, branch, title, body string, reviewers *reviewSuggestion, gateResults []*gates.Result) (string, error) {
+	remoteURL, err := rp.gitOps.RemoteURL(ctx, rp.workingDir, rp.hosting.Remote)
+	if err != nil {
+		return "", err
+	}
+	provider, err := hosting.Open(rp.hosting, remoteURL)
+	if err != nil {
+		return "", err
+	}
+	base := rp.prBaseBranch
+	if base == "" {
+		base = "main"
+	}
+
+	if err := rp.pushBranch(ctx, issue, git.PushOptions{
+		Remote:      rp.hosting.Remote,
+		Branch:      branch,
+		Token:       provider.Token(),
+		TokenUser:   provider.PushUser(),
+		SetUpstream: true,
+		Checks:      rp.gitPushChecks(),
+	}); err != nil {
+		return "", err
+	}
+	fmt.Printf("✓ Pushed %s to %s\n", branch, rp.hosting.Remote)
+
+	if rp.messageGen != nil {
+		desc, err := rp.messageGen.GeneratePRDescription(ctx, rp.prDescriptionRequest(ctx, issue, base))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to generate PR description: %v (using default)\n", err)
+		} else {
+			title = fmt.Sprintf("[%s] %s", issue.ID, desc.Title)
+			body = strings.TrimSpace(desc.Body) + "\n\n"
+			if reviewers != nil {
+				body += "## Suggested Reviewers\n\n" + formatReviewers(reviewers) + "\n"
+			}
+			body += fmt.Sprintf("---\nvc issue %s: %s\n", issue.ID, issue.Title)
+		}
+	}
+
+	newPR := hosting.NewPullRequest{
+		Title:        title,
+		Body:         body,
+		SourceBranch: branch,
+		TargetBranch: base,
+		Draft:        rp.hosting.Draft,
+		Labels:       rp.hosting.Labels,
+	}
+	if rp.reviewers.RequestReviews {
+		newPR.Reviewers, newPR.TeamReviewers = reviewers.requested()
+	}
+	pr, err := provider.CreatePullRequest(ctx, newPR)
+	if pr == nil {
+		return "", err
+	}
+	if err != nil {
+		// Opened, but not labelled or reviewers not requested
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	fmt.Printf("✓ Created PR: %s\n", pr.URL)
+
+	if err := hosting.RecordCreated(ctx, rp.store, issue.ID, rp.actor, provider, pr); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record pull request: %v\n", err)
+	}
+	if rp.hosting.CheckRuns && len(gateResults) > 0 {
+		rp.reportCheckRuns(ctx, provider, gateResults)
+	}
+	return pr.URL, nil
+}
+
+// prDescriptionRequest collects what the branch changes relative to base for
+// the PR description generator. Anything git can't provide is left out.
+func (rp *ResultsProcessor) prDescriptionRequest(ctx context.Context, issue *types.Issue, base string) git.PRDescriptionRequest {
+	req := git.PRDescriptionRequest{
+		IssueID:            issue.ID,
+		IssueTitle:         issue.Title,
+		IssueDescription:   issue.Description,
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+	}
+	if !isValidGitRef(base) {
+		return req
+	}
+
+	gitLines := func(args ...string) []string {
+		out, err := exec.CommandContext(ctx, "git", append([]string{"-C", rp.workingDir}, args...)...).Output()
+		if err != nil {
+			return nil
+		}
+		var lines []string
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+	req.Commits = gitLines("log", "--reverse", "--format=%s", base+"..HEAD")
+	req.ChangedFiles = gitLines("diff", "--name-only", base+"...HEAD")
+	if out, err := exec.CommandContext(ctx, "git", "-C", rp.workingDir, "diff", base+"...HEAD").Output(); err == nil {
+		req.Diff = rp.promptDiff(ctx, git.ParseDiff(string(out))).String()
+	}
+	return req
+}
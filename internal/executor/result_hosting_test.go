@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/hosting"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestCreateAutoPRWithHostingAPI(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	issue := &types.Issue{Title: "Add greeting", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	remoteDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--bare", remoteDir},
+		{"-C", repoDir, "remote", "add", "origin", remoteDir},
+		{"-C", repoDir, "checkout", "-b", "vc/" + issue.ID + "-add-greeting"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	var got struct{ Title, Body, Head, Base string }
+	var checkRuns []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/acme/widgets/check-runs" {
+			var run map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&run)
+			checkRuns = append(checkRuns, run)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		if r.URL.Path != "/repos/acme/widgets/pulls" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 3, "html_url": "https://github.com/acme/widgets/pull/3", "state": "open",
+			"head": {"ref": "` + got.Head + `"}, "base": {"ref": "` + got.Base + `"}}`))
+	}))
+	defer server.Close()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create git operations: %v", err)
+	}
+	// The remote is a local path, so the provider and repository must be configured
+	hostingCfg := config.DefaultHostingConfig()
+	hostingCfg.Provider = config.HostingGitHub
+	hostingCfg.GitHub.Token = "ghp_secret"
+	hostingCfg.GitHub.Repo = "acme/widgets"
+	hostingCfg.GitHub.APIURL = server.URL
+	hostingCfg.CheckRuns = true
+	rp := &ResultsProcessor{store: store, gitOps: gitOps, workingDir: repoDir, actor: "exec-test", hosting: hostingCfg, prBaseBranch: "develop"}
+
+	gateResults := []*gates.Result{
+		{Gate: gates.GateTest, Passed: true, Output: "ok"},
+		{Gate: gates.GateLint, Output: "README.md:1:1: missing blank line (md022)\n/usr/lib/go/x.go:3: not ours"},
+	}
+	url, err := rp.createAutoPR(ctx, issue, "abc123", gateResults)
+	if err != nil {
+		t.Fatalf("createAutoPR failed: %v", err)
+	}
+	if url != "https://github.com/acme/widgets/pull/3" {
+		t.Errorf("url = %q", url)
+	}
+
+	branch := "vc/" + issue.ID + "-add-greeting"
+	if out, err := exec.Command("git", "-C", remoteDir, "rev-parse", "--verify", branch).CombinedOutput(); err != nil {
+		t.Errorf("expected %s pushed: %v\n%s", branch, err, out)
+	}
+	if got.Head != branch || got.Base != "develop" || !strings.Contains(got.Title, issue.ID) || !strings.Contains(got.Body, issue.ID) {
+		t.Errorf("unexpected pull request: %+v", got)
+	}
+
+	tracked, err := hosting.ListTracked(ctx, store, issue.ID)
+	if err != nil {
+		t.Fatalf("ListTracked failed: %v", err)
+	}
+	if len(tracked) != 1 || tracked[0].Number != 3 || tracked[0].Repo != "acme/widgets" || tracked[0].Branch != branch {
+		t.Errorf("expected the pull request tracked on the issue, got %+v", tracked)
+	}
+
+	head, _ := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+	if len(checkRuns) != 2 || checkRuns[0]["name"] != "vc / test" || checkRuns[0]["conclusion"] != "success" ||
+		checkRuns[1]["conclusion"] != "failure" || checkRuns[1]["head_sha"] != strings.TrimSpace(string(head)) {
+		t.Fatalf("unexpected check runs: %v", checkRuns)
+	}
+	// Only the failure in the repository is annotated
+	annotations := checkRuns[1]["output"].(map[string]interface{})["annotations"].([]interface{})
+	if len(annotations) != 1 || annotations[0].(map[string]interface{})["path"] != "README.md" {
+		t.Errorf("lint annotations = %v", annotations)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/idempotency"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -54,12 +55,21 @@ _This issue was automatically created by AI code review analysis._`,
 		AcceptanceCriteria: acceptanceCriteria,
 	}
 
-	err := rp.store.CreateIssue(ctx, reviewIssue, "ai-supervisor")
+	// Keyed on the commit (see internal/idempotency), so reviewing it again
+	// after a crash doesn't file a second review
+	filed := idempotency.NewScope(rp.store, "code-review", parentIssue.ID, commitHash)
+	reviewIssueID, created, err := filed.Do(ctx, "issue", func() (string, error) {
+		if err := rp.store.CreateIssue(ctx, reviewIssue, "ai-supervisor"); err != nil {
+			return "", err
+		}
+		return reviewIssue.ID, nil
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to create code review issue: %w", err)
 	}
-
-	reviewIssueID := reviewIssue.ID
+	if !created {
+		return reviewIssueID, nil
+	}
 
 	// vc-d0r3: Add discovered:supervisor label to VC-filed code review issues
 	if err := rp.store.AddLabel(ctx, reviewIssueID, types.LabelDiscoveredSupervisor, "ai-supervisor"); err != nil {
@@ -95,6 +105,10 @@ func (rp *ResultsProcessor) createQualityIssues(ctx context.Context, parentIssue
 	var createdIssues []string
 	var errors []error
 
+	// Keyed on the commit and title (see internal/idempotency), so analyzing
+	// the commit again after a crash files nothing twice
+	filed := idempotency.NewScope(rp.store, "code-quality", parentIssue.ID, commitHash)
+
 	for i, qualityIssue := range qualityIssues {
 		// Create issue title with commit reference
 		title := qualityIssue.Title
@@ -155,7 +169,12 @@ _This issue was automatically created by AI code quality analysis (vc-216)._`,
 			AcceptanceCriteria: acceptanceCriteria,
 		}
 
-		err := rp.store.CreateIssue(ctx, fixIssue, "ai-supervisor")
+		fixIssueID, created, err := filed.Do(ctx, "issue:"+idempotency.Hash(title), func() (string, error) {
+			if err := rp.store.CreateIssue(ctx, fixIssue, "ai-supervisor"); err != nil {
+				return "", err
+			}
+			return fixIssue.ID, nil
+		})
 		if err != nil {
 			// Collect error but continue creating remaining issues
 			errors = append(errors, fmt.Errorf("failed to create quality fix issue %d (%s): %w", i+1, title, err))
@@ -163,8 +182,11 @@ _This issue was automatically created by AI code quality analysis (vc-216)._`,
 			continue
 		}
 
-		fixIssueID := fixIssue.ID
 		createdIssues = append(createdIssues, fixIssueID)
+		if !created {
+			fmt.Printf("  ✓ Already filed %s: %s\n", fixIssueID, title)
+			continue
+		}
 
 		// vc-d0r3: Add discovered:supervisor label to VC-filed quality issues
 		if err := rp.store.AddLabel(ctx, fixIssueID, types.LabelDiscoveredSupervisor, "ai-supervisor"); err != nil {
@@ -191,7 +213,9 @@ _This issue was automatically created by AI code quality analysis (vc-216)._`,
 	if len(createdIssues) > 0 {
 		qualityComment := fmt.Sprintf("Automated code quality analysis found %d issues:\n%v\n\nThis issue is now blocked pending quality fixes.",
 			len(createdIssues), createdIssues)
-		if err := rp.store.AddComment(ctx, parentIssue.ID, "ai-supervisor", qualityComment); err != nil {
+		if err := filed.Once(ctx, "comment:"+idempotency.Hash(createdIssues...), func() error {
+			return rp.store.AddComment(ctx, parentIssue.ID, "ai-supervisor", qualityComment)
+		}); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to add quality issues comment to parent: %v\n", err)
 		}
 	}
@@ -215,6 +239,10 @@ func (rp *ResultsProcessor) createTestIssues(ctx context.Context, parentIssue *t
 	var createdIssues []string
 	var errors []error
 
+	// Keyed on the title (see internal/idempotency), so analyzing the same
+	// work again after a crash files nothing twice
+	filed := idempotency.NewScope(rp.store, "test-coverage", parentIssue.ID)
+
 	for i, testIssue := range testIssues {
 		title := testIssue.Title
 
@@ -270,14 +298,23 @@ _This issue was automatically created by AI test coverage analysis._`,
 			AcceptanceCriteria: acceptanceCriteria,
 		}
 
-		err := rp.store.CreateIssue(ctx, newIssue, "ai-supervisor")
+		testIssueID, created, err := filed.Do(ctx, "issue:"+idempotency.Hash(title), func() (string, error) {
+			if err := rp.store.CreateIssue(ctx, newIssue, "ai-supervisor"); err != nil {
+				return "", err
+			}
+			return newIssue.ID, nil
+		})
 		if err != nil {
 			errors = append(errors, fmt.Errorf("failed to create test issue %d (%s): %w", i+1, title, err))
 			fmt.Fprintf(os.Stderr, "warning: failed to create test issue %d (%s): %v\n", i+1, title, err)
 			continue
 		}
 
-		createdIssues = append(createdIssues, newIssue.ID)
+		createdIssues = append(createdIssues, testIssueID)
+		if !created {
+			fmt.Printf("  ✓ Already filed %s: %s\n", testIssueID, title)
+			continue
+		}
 
 		// vc-d0r3: Add discovered:supervisor label to VC-filed test issues
 		if err := rp.store.AddLabel(ctx, newIssue.ID, types.LabelDiscoveredSupervisor, "ai-supervisor"); err != nil {
@@ -302,7 +339,9 @@ _This issue was automatically created by AI test coverage analysis._`,
 	if len(createdIssues) > 0 {
 		testComment := fmt.Sprintf("Test coverage analysis found %d test gaps and created issues:\n%v",
 			len(createdIssues), createdIssues)
-		if err := rp.store.AddComment(ctx, parentIssue.ID, "ai-supervisor", testComment); err != nil {
+		if err := filed.Once(ctx, "comment:"+idempotency.Hash(createdIssues...), func() error {
+			return rp.store.AddComment(ctx, parentIssue.ID, "ai-supervisor", testComment)
+		}); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to add test issues comment to parent: %v\n", err)
 		}
 	}
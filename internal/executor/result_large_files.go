@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// promptDiff returns d with the content of Git LFS-tracked files and large
+// binaries left out, so they can't blow up the prompt it is fed to
+func (rp *ResultsProcessor) promptDiff(ctx context.Context, d *git.Diff) *git.Diff {
+	if !rp.largeFiles.Enabled || rp.gitOps == nil || len(d.Files) == 0 {
+		return d
+	}
+	heavy, err := rp.gitOps.HeavyFiles(ctx, rp.workingDir, d.Paths(), rp.largeFiles.MaxBinarySize())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to look for large files in diff: %v\n", err)
+		return d
+	}
+	return d.Omit(heavy)
+}
+
+// flagLargeFileChanges warns when the agent changed Git LFS-tracked files
+// or large binaries, which are rarely meant to be touched and bloat history.
+// The changes are left in place; the issue gets a comment listing them.
+func (rp *ResultsProcessor) flagLargeFileChanges(ctx context.Context, issue *types.Issue) {
+	if !rp.largeFiles.Enabled || rp.gitOps == nil {
+		return
+	}
+	status, err := rp.gitOps.GetStatus(ctx, rp.workingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check changes for large files: %v\n", err)
+		return
+	}
+	heavy, err := rp.gitOps.HeavyFiles(ctx, rp.workingDir, statusFiles(status), rp.largeFiles.MaxBinarySize())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check changes for large files: %v\n", err)
+		return
+	}
+	if len(heavy) == 0 {
+		return
+	}
+
+	var lfs, binaries, described []string
+	for _, h := range heavy {
+		if h.Reason == git.HeavyLFS {
+			lfs = append(lfs, h.Path)
+		} else {
+			binaries = append(binaries, h.Path)
+		}
+		described = append(described, h.String())
+	}
+	fmt.Printf("⚠️  Agent changed %d LFS-tracked or large binary files: %s\n", len(heavy), strings.Join(described, ", "))
+
+	rp.logEvent(ctx, events.EventTypeLargeFileChanged, events.SeverityWarning, issue.ID,
+		fmt.Sprintf("Agent changed %d LFS-tracked or large binary files", len(heavy)),
+		map[string]interface{}{
+			"lfs":      lfs,
+			"binaries": binaries,
+		})
+
+	comment := fmt.Sprintf("**Large files changed**: the agent changed Git LFS-tracked files or large binaries. "+
+		"Check that these changes are intended before they are committed:\n\n- %s", strings.Join(described, "\n- "))
+	if err := rp.store.AddComment(ctx, issue.ID, rp.actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add large file comment: %v\n", err)
+	}
+}
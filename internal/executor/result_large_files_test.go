@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestLargeFileHandling(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	files := map[string][]byte{
+		".gitattributes": []byte("*.psd filter=lfs diff=lfs merge=lfs -text\n"),
+		"logo.psd":       []byte("version https://git-lfs.github.com/spec/v1\noid sha256:abc\n"),
+		"model.bin":      append([]byte{0}, bytes.Repeat([]byte{1}, 4096)...),
+		"main.go":        []byte("package main\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repoDir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issue := &types.Issue{Title: "Update the logo", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	rp := &ResultsProcessor{store: store, gitOps: gitOps, workingDir: repoDir, actor: "test"}
+	diff, err := gitOps.Diff(ctx, repoDir, "")
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if got := rp.promptDiff(ctx, diff); got != diff {
+		t.Error("promptDiff() with the guard off should return the diff unchanged")
+	}
+	rp.flagLargeFileChanges(ctx, issue)
+	if comments, _ := store.GetComments(ctx, issue.ID); len(comments) != 0 {
+		t.Errorf("flagLargeFileChanges() with the guard off commented: %+v", comments)
+	}
+
+	rp.largeFiles = config.LargeFilesConfig{Enabled: true, MaxBinarySizeKB: 1}
+	prompt := rp.promptDiff(ctx, diff).String()
+	if strings.Contains(prompt, "oid sha256:abc") || !strings.Contains(prompt, "[Git LFS file: content omitted]") {
+		t.Errorf("promptDiff() kept the LFS file's content:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "[binary, 4 KB file: content omitted]") {
+		t.Errorf("promptDiff() kept the large binary:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "+package main") {
+		t.Errorf("promptDiff() dropped an ordinary file:\n%s", prompt)
+	}
+
+	rp.flagLargeFileChanges(ctx, issue)
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0].Body, "- logo.psd (Git LFS)\n- model.bin (binary, 4 KB)") {
+		t.Errorf("comments = %+v, want the large files listed", comments)
+	}
+	evts, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeLargeFileChanged})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(evts) != 1 {
+		t.Fatalf("got %d large file events, want 1", len(evts))
+	}
+	if data, err := evts[0].GetLargeFileChangeData(); err != nil || len(data.LFS) != 1 || len(data.Binaries) != 1 {
+		t.Errorf("event data = %+v, %v", data, err)
+	}
+}
@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// runScheduledMutationGate runs the optional mutation testing gate when the
+// executor's scheduler says it is due (interval elapsed or high-risk issue).
+// The score is recorded as an event and comment, and kept on the processor so
+// test coverage analysis can use it. Mutation results never block the issue.
+func (rp *ResultsProcessor) runScheduledMutationGate(ctx context.Context, issue *types.Issue, gateRunner *gates.Runner) {
+	if rp.executor == nil || rp.executor.mutationSched == nil {
+		return
+	}
+	sched := rp.executor.mutationSched
+
+	issueLabels, err := rp.store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get labels for mutation scheduling: %v\n", err)
+	}
+
+	shouldRun, reason := sched.ShouldRun(issue, issueLabels, time.Now())
+	if !shouldRun {
+		return
+	}
+
+	fmt.Printf("Running mutation gate (%s)...\n", reason)
+	startTime := time.Now()
+	sched.MarkRun(startTime)
+
+	mutationResult, score := gateRunner.RunMutationGate(ctx, sched.Config())
+	duration := time.Since(startTime)
+
+	data := map[string]interface{}{
+		"reason":      reason,
+		"duration_ms": duration.Milliseconds(),
+		"success":     mutationResult.Passed,
+	}
+
+	if score == nil {
+		errMsg := "unknown error"
+		if mutationResult.Error != nil {
+			errMsg = mutationResult.Error.Error()
+		}
+		data["error"] = errMsg
+		rp.logEvent(ctx, events.EventTypeMutationTestCompleted, events.SeverityWarning, issue.ID,
+			fmt.Sprintf("Mutation testing did not produce a score: %s", errMsg), data)
+		return
+	}
+
+	data["score"] = score.Score
+	data["killed"] = score.Killed
+	data["survived"] = score.Survived
+	data["skipped"] = score.Skipped
+	data["total"] = score.Total
+	rp.logEvent(ctx, events.EventTypeMutationTestCompleted, events.SeverityInfo, issue.ID,
+		fmt.Sprintf("Mutation testing completed: %s", score.Summary()), data)
+
+	comment := fmt.Sprintf("**Mutation Testing** (%s)\n\n%s\nDuration: %v",
+		reason, score.Summary(), duration.Round(time.Second))
+	if err := rp.store.AddComment(ctx, issue.ID, "quality-gates", comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add mutation testing comment: %v\n", err)
+	}
+
+	rp.mutationSummary = score.Summary()
+	fmt.Printf("✓ Mutation gate: %s\n", score.Summary())
+}
@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,10 +13,13 @@ import (
 	"github.com/steveyegge/vc/internal/ai"
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/git"
 	"github.com/steveyegge/vc/internal/iterative"
 	"github.com/steveyegge/vc/internal/labels"
 	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/tracing"
 	"github.com/steveyegge/vc/internal/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // NewResultsProcessor creates a new results processor
@@ -57,6 +61,22 @@ func NewResultsProcessor(cfg *ResultsProcessorConfig) (*ResultsProcessor, error)
 		enableQualityGates:        cfg.EnableQualityGates,
 		enableAutoCommit:          cfg.EnableAutoCommit,
 		enableAutoPR:              cfg.EnableAutoPR,
+		hosting:                   cfg.Hosting,
+		prBaseBranch:              cfg.PRBaseBranch,
+		autoCommitPaths:           cfg.AutoCommitPaths,
+		autoCommitExcludePaths:    cfg.AutoCommitExcludePaths,
+		autoCommitAmendOnRetry:    cfg.AutoCommitAmendOnRetry,
+		commitSigning:             cfg.CommitSigning,
+		commitAttribution:         cfg.CommitAttribution,
+		pushChecks:                cfg.PushChecks,
+		pathScope:                 cfg.PathScope,
+		scopeViolation:            cfg.ScopeViolation,
+		scopeBaseline:             cfg.ScopeBaseline,
+		reviewers:                 cfg.Reviewers,
+		largeFiles:                cfg.LargeFiles,
+		submodules:                cfg.Submodules,
+		pushRetry:                 cfg.PushRetry,
+		execution:                 cfg.Execution,
 		enableIterativeRefinement: cfg.EnableIterativeRefinement, // vc-t9ls
 		workingDir:                cfg.WorkingDir,
 		actor:                     cfg.Actor,
@@ -99,6 +119,15 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 	// Step 1: Extract agent output summary
 	agentOutput := rp.extractSummary(ctx, issue, agentResult)
 
+	// Step 1.1: Revert or flag edits outside the issue's path scope
+	rp.enforcePathScope(ctx, issue)
+
+	// Step 1.2: Flag changes to LFS-tracked files and large binaries
+	rp.flagLargeFileChanges(ctx, issue)
+
+	// Step 1.3: Revert or keep moved submodule pointers
+	rp.handleSubmoduleChanges(ctx, issue)
+
 	fmt.Printf("\n=== Agent Execution Complete ===\n")
 	fmt.Printf("Success: %v\n", agentResult.Success)
 	fmt.Printf("Exit Code: %d\n", agentResult.ExitCode)
@@ -118,7 +147,10 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 	}
 
 	// Step 3: Quality Gates (if enabled and agent succeeded)
-	shouldReturn, gateResults = rp.handleQualityGates(ctx, issue, agentResult, result)
+	gatesCtx, gatesSpan := tracing.Start(ctx, "vc.gates")
+	shouldReturn, gateResults = rp.handleQualityGates(gatesCtx, issue, agentResult, result)
+	gatesSpan.SetAttributes(attribute.Int("vc.gates.run", len(gateResults)), attribute.Bool("vc.gates.passed", result.GatesPassed))
+	gatesSpan.End()
 	if shouldReturn {
 		return result, nil
 	}
@@ -211,14 +243,18 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 	}
 
 	// Step 3.6: Test Coverage Analysis (vc-217)
-	// After quality gates pass, analyze test coverage and file test improvement issues
-	if err := rp.handleTestCoverageAnalysis(ctx, issue, agentResult, result); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: test coverage analysis failed: %v\n", err)
-	}
+	// After quality gates pass, analyze test coverage and file test improvement issues.
+	// The AI call doesn't depend on code review, so it runs while the changes are
+	// committed and reviewed, and its results are filed once review is done.
+	finishTestCoverage := rp.startTestCoverageAnalysis(ctx, issue, agentResult, result)
 
 	// Step 3.7: Auto-commit and code review (if enabled, agent succeeded, and gates passed)
 	rp.handleAutoCommitAndCodeReview(ctx, issue, agentResult, result, gateResults)
 
+	if err := finishTestCoverage(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: test coverage analysis failed: %v\n", err)
+	}
+
 	// Step 4: Update issue status
 	if agentResult.Success && result.GatesPassed {
 		// Handle success path
@@ -688,6 +724,9 @@ func (rp *ResultsProcessor) handleQualityGates(ctx context.Context, issue *types
 		fmt.Fprintf(os.Stderr, "warning: failed to update execution state: %v\n", err)
 	}
 
+	// Gates build against the submodule commits the tree records
+	rp.updateSubmodules(ctx, issue)
+
 	// Log quality gates started
 	gatesStartTime := time.Now()
 	rp.logEvent(ctx, events.EventTypeQualityGatesStarted, events.SeverityInfo, issue.ID,
@@ -715,11 +754,23 @@ func (rp *ResultsProcessor) handleQualityGates(ctx context.Context, issue *types
 		rp.logProgressEvent(ctx, events.SeverityInfo, issue.ID, message, progressData)
 	}
 
+	// Per-issue gate overrides (gate-override:<gate> + human approval label)
+	gateOverrides := rp.resolveGateOverrides(ctx, issue)
+
+	// Incremental gates need the executor's full-run tracker (nil for REPL = always full)
+	var fullRuns *gates.FullRunTracker
+	if rp.executor != nil {
+		fullRuns = rp.executor.gateFullRuns
+	}
+
 	gateRunner, err := gates.NewRunner(&gates.Config{
 		Store:            rp.store,
 		Supervisor:       rp.supervisor, // Enable AI-driven recovery strategies (ZFC)
 		WorkingDir:       rp.workingDir,
 		ProgressCallback: progressCallback, // vc-267: Progress reporting
+		Overrides:        gateOverrides,
+		FullRunTracker:   fullRuns,
+		Paths:            rp.pathScope,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to create quality gate runner: %v (skipping gates)\n", err)
@@ -764,6 +815,9 @@ func (rp *ResultsProcessor) handleQualityGates(ctx context.Context, issue *types
 		}
 	}
 
+	// Audit trail for gates skipped via approved overrides
+	rp.logGateOverrides(ctx, issue, gateOverrides, gateResults)
+
 	// Log progress for each gate (vc-245)
 	for i, gateResult := range gateResults {
 		status := "PASS"
@@ -901,6 +955,11 @@ func (rp *ResultsProcessor) handleQualityGates(ctx context.Context, issue *types
 		}
 	}
 
+	// Optional mutation testing - only meaningful once the regular test gate is green
+	if allPassed && !canceled && !timedOut {
+		rp.runScheduledMutationGate(ctx, issue, gateRunner)
+	}
+
 	// vc-218: If this is a mission with needs-quality-gates label and gates passed,
 	// transition to needs-review state (for future QA workers)
 	if allPassed && !canceled && !timedOut && issue.IssueSubtype == types.SubtypeMission {
@@ -991,11 +1050,14 @@ func (rp *ResultsProcessor) handleQualityGates(ctx context.Context, issue *types
 	return false, gateResults
 }
 
-// handleTestCoverageAnalysis runs test coverage analysis if preconditions are met (vc-217).
-// Returns error if analysis fails, nil otherwise (failure is non-fatal).
-func (rp *ResultsProcessor) handleTestCoverageAnalysis(ctx context.Context, issue *types.Issue, agentResult *AgentResult, result *ProcessingResult) error {
+// startTestCoverageAnalysis starts test coverage analysis if preconditions are met (vc-217).
+// The uncommitted diff is read right away, before auto-commit, and the AI call runs
+// in the background. The returned function waits for the analysis and files its
+// results; its error is non-fatal.
+func (rp *ResultsProcessor) startTestCoverageAnalysis(ctx context.Context, issue *types.Issue, agentResult *AgentResult, result *ProcessingResult) func() error {
+	skip := func() error { return nil }
 	if !agentResult.Success || !result.GatesPassed || rp.supervisor == nil || rp.gitOps == nil {
-		return nil // Preconditions not met, skip silently
+		return skip // Preconditions not met, skip silently
 	}
 
 	fmt.Printf("\n=== Test Coverage Analysis ===\n")
@@ -1003,23 +1065,24 @@ func (rp *ResultsProcessor) handleTestCoverageAnalysis(ctx context.Context, issu
 	// Check if there are uncommitted changes to analyze
 	hasChanges, err := rp.gitOps.HasUncommittedChanges(ctx, rp.workingDir)
 	if err != nil {
-		return fmt.Errorf("failed to check for changes: %w", err)
+		return func() error { return fmt.Errorf("failed to check for changes: %w", err) }
 	}
 
 	if !hasChanges {
 		fmt.Printf("No uncommitted changes - skipping test coverage analysis\n")
-		return nil
+		return skip
 	}
 
-	// Get the diff of uncommitted changes
-	diff, err := rp.getUncommittedDiff(ctx)
+	// Get the diff of uncommitted changes, new files included
+	changes, err := rp.gitOps.Diff(ctx, rp.workingDir, "")
 	if err != nil {
-		return fmt.Errorf("failed to get diff: %w", err)
+		return func() error { return fmt.Errorf("failed to get diff: %w", err) }
 	}
 
-	if diff == "" {
-		return nil
+	if len(changes.Files) == 0 {
+		return skip
 	}
+	diff := rp.diffSummarizer().Render(ctx, rp.promptDiff(ctx, changes), reviewDiffBudget)
 
 	// Get existing test files to understand test patterns
 	existingTests, err := rp.getExistingTests(ctx)
@@ -1028,12 +1091,24 @@ func (rp *ResultsProcessor) handleTestCoverageAnalysis(ctx context.Context, issu
 		// Continue with empty existing tests
 	}
 
-	// Analyze test coverage
-	testAnalysis, err := rp.supervisor.AnalyzeTestCoverage(ctx, issue, diff, existingTests)
-	if err != nil {
-		return fmt.Errorf("AI analysis failed: %w", err)
+	// Analyze test coverage in the background
+	mutationSummary := rp.mutationSummary
+	pending := startAnalysis(ctx, func(ctx context.Context) (*ai.TestSufficiencyAnalysis, error) {
+		return rp.supervisor.AnalyzeTestCoverageWithMutation(ctx, issue, diff, existingTests, mutationSummary)
+	})
+
+	return func() error {
+		testAnalysis, err := pending.wait()
+		if err != nil {
+			return fmt.Errorf("AI analysis failed: %w", err)
+		}
+		return rp.fileTestCoverageAnalysis(ctx, issue, testAnalysis, result)
 	}
+}
 
+// fileTestCoverageAnalysis records a test coverage analysis on the issue and
+// files test improvement issues for the gaps it found
+func (rp *ResultsProcessor) fileTestCoverageAnalysis(ctx context.Context, issue *types.Issue, testAnalysis *ai.TestSufficiencyAnalysis, result *ProcessingResult) error {
 	// Add analysis summary as comment
 	testComment := fmt.Sprintf("**Test Coverage Analysis**\n\n%s\n\nSufficient Coverage: %v\nConfidence: %.0f%%\nTest Issues Found: %d",
 		testAnalysis.Summary, testAnalysis.SufficientCoverage, testAnalysis.Confidence*100, len(testAnalysis.TestIssues))
@@ -1071,7 +1146,10 @@ func (rp *ResultsProcessor) handleAutoCommitAndCodeReview(ctx context.Context, i
 		return // Preconditions not met, skip silently
 	}
 
-	commitHash, err := rp.autoCommit(ctx, issue)
+	commitCtx, commitSpan := tracing.Start(ctx, "vc.commit")
+	commitHash, err := rp.autoCommit(commitCtx, issue, agentResult)
+	commitSpan.SetAttributes(attribute.String("vc.commit.hash", commitHash))
+	tracing.End(commitSpan, err)
 	if err != nil {
 		// Don't fail - just log and continue
 		fmt.Fprintf(os.Stderr, "Warning: auto-commit failed: %v (continuing without commit)\n", err)
@@ -1094,9 +1172,26 @@ func (rp *ResultsProcessor) handleAutoCommitAndCodeReview(ctx context.Context, i
 	// Auto-PR creation (if enabled) (vc-389e)
 	if rp.enableAutoPR {
 		prURL, err := rp.createAutoPR(ctx, issue, commitHash, gateResults)
-		if err != nil {
+		var blocked *git.PushBlockedError
+		if errors.As(err, &blocked) {
+			// Leave it to a human: the work stays committed locally
+			fmt.Fprintf(os.Stderr, "Warning: %v (continuing without PR)\n", err)
+			comment := fmt.Sprintf("**Auto-PR blocked by pre-push checks**\n\nBranch %s was not pushed:\n", blocked.Branch)
+			for _, v := range blocked.Violations {
+				comment += fmt.Sprintf("- %s\n", v)
+			}
+			if err := rp.store.AddComment(ctx, issue.ID, rp.actor, comment); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to add push blocked comment: %v\n", err)
+			}
+		} else if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: auto-PR failed: %v (continuing without PR)\n", err)
 		} else if prURL != "" {
+			result.PRURL = prURL
+			// A retried push rebases the commit onto the remote branch
+			if head, err := rp.gitOps.ResolveRef(ctx, rp.workingDir, "HEAD"); err == nil && head != commitHash {
+				commitHash = head
+				result.CommitHash = head
+			}
 			prComment := fmt.Sprintf("Auto-created PR: %s", prURL)
 			if err := rp.store.AddComment(ctx, issue.ID, rp.actor, prComment); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to add PR comment: %v\n", err)
@@ -1106,8 +1201,13 @@ func (rp *ResultsProcessor) handleAutoCommitAndCodeReview(ctx context.Context, i
 
 	// AI-based code review decision and automated quality analysis (vc-216)
 	if rp.supervisor != nil {
-		if err := rp.handleCodeReviewDecision(ctx, issue, commitHash, result); err != nil {
+		reviewCtx, reviewSpan := tracing.Start(ctx, "vc.review")
+		err := rp.handleCodeReviewDecision(reviewCtx, issue, commitHash, result)
+		reviewSpan.SetAttributes(attribute.Bool("vc.review.needed", result.NeedsReview))
+		tracing.End(reviewSpan, err)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: code review decision failed: %v\n", err)
+			result.NeedsReview = true
 		}
 	}
 }
@@ -1123,10 +1223,11 @@ func (rp *ResultsProcessor) handleCodeReviewDecision(ctx context.Context, issue
 	}
 
 	// Get the diff for this commit using git directly
-	diff, err := rp.getCommitDiff(ctx, commitHash)
+	rawDiff, err := rp.getCommitDiff(ctx, commitHash)
 	if err != nil {
 		return fmt.Errorf("failed to get diff: %w", err)
 	}
+	diff := rp.diffSummarizer().Render(ctx, rp.promptDiff(ctx, git.ParseDiff(rawDiff)), reviewDiffBudget)
 
 	// Use Haiku to decide if review is needed (fast and cheap)
 	decision, err := rp.supervisor.AnalyzeCodeReviewNeed(ctx, issue, diff)
@@ -1182,6 +1283,7 @@ func (rp *ResultsProcessor) handleCodeReviewDecision(ctx context.Context, issue
 			if err := rp.store.AddComment(ctx, issue.ID, "ai-supervisor", failureComment); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to add quality analysis failure comment: %v\n", err)
 			}
+			result.NeedsReview = true
 			return nil // Don't fail on quality analysis error
 		}
 
@@ -1194,6 +1296,7 @@ func (rp *ResultsProcessor) handleCodeReviewDecision(ctx context.Context, issue
 
 		// File granular issues for each quality problem found
 		if len(qualityAnalysis.Issues) > 0 {
+			result.NeedsReview = true
 			fmt.Printf("Filing %d quality issues...\n", len(qualityAnalysis.Issues))
 			createdIssues, err := rp.createQualityIssues(ctx, issue, commitHash, qualityAnalysis.Issues)
 			if err != nil {
@@ -1658,6 +1761,7 @@ If this issue continues to have incomplete attempts after %d total tries, it wil
 
 	} else {
 		// Exceeded retry limit - escalate with needs-human-review label
+		reviewers := rp.suggestReviewers(ctx, issue, rp.touchedFiles(ctx, issue), reviewTriggerEscalation)
 		escalationComment := fmt.Sprintf(`**Incomplete Work Escalated**
 
 This task has been attempted %d times but the agent has not been able to fully complete it.
@@ -1678,6 +1782,9 @@ This issue needs human intervention to either:
 
 The issue has been marked with the 'needs-human-review' label to prevent further automatic retries.`,
 			incompleteAttempts, analysis.Summary, issue.AcceptanceCriteria)
+		if reviewers != nil {
+			escalationComment += "\n\n**Suggested reviewers** (code owners and recent authors of the files touched):\n" + formatReviewers(reviewers)
+		}
 
 		if err := rp.store.AddComment(ctx, issue.ID, "ai-supervisor", escalationComment); err != nil {
 			return fmt.Errorf("failed to add escalation comment: %w", err)
@@ -1711,6 +1818,7 @@ The issue has been marked with the 'needs-human-review' label to prevent further
 				"max_retries":         maxIncompleteRetries,
 				"analysis_summary":    analysis.Summary,
 				"escalated":           true,
+				"suggested_reviewers": reviewers.names(),
 			})
 
 		// Release execution state - issue is now blocked and needs human review
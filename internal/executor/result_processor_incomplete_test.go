@@ -197,11 +197,12 @@ func TestHandleIncompleteWork(t *testing.T) {
 				t.Fatalf("Failed to get events: %v", err)
 			}
 
-			// Find the comment added by handleIncompleteWork (last comment event, not label event)
+			// Find the comment added by handleIncompleteWork (latest comment event, not label event).
+			// Events are returned newest first.
 			var lastComment string
-			for i := len(issueEvents) - 1; i >= 0; i-- {
-				if issueEvents[i].Comment != nil && issueEvents[i].EventType == types.EventCommented {
-					lastComment = *issueEvents[i].Comment
+			for _, event := range issueEvents {
+				if event.Comment != nil && event.EventType == types.EventCommented {
+					lastComment = *event.Comment
 					break
 				}
 			}
@@ -538,11 +539,11 @@ func TestHandleIncompleteWorkAttemptCounting(t *testing.T) {
 				t.Fatalf("Failed to get events: %v", err)
 			}
 
-			// Find the last comment event (not label event)
+			// Find the latest comment event (not label event). Events are returned newest first.
 			var lastComment string
-			for i := len(issueEvents) - 1; i >= 0; i-- {
-				if issueEvents[i].Comment != nil && issueEvents[i].EventType == types.EventCommented {
-					lastComment = *issueEvents[i].Comment
+			for _, event := range issueEvents {
+				if event.Comment != nil && event.EventType == types.EventCommented {
+					lastComment = *event.Comment
 					break
 				}
 			}
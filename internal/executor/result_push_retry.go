@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Push retry outcomes, as recorded on push retried events
+const (
+	pushRetryPushed      = "pushed"
+	pushRetryConflict    = "conflict"
+	pushRetryGatesFailed = "gates_failed"
+	pushRetryFailed      = "failed"
+)
+
+// pushRetryAttempt records what happened to a push that had to be retried
+type pushRetryAttempt struct {
+	attempts    int
+	outcome     string
+	conflicts   []string
+	failedGates []string
+	reason      string
+}
+
+// pushBranch pushes opts.Branch. When the remote rejects the push because
+// its branch advanced, the branch is fetched, rebased onto the remote one,
+// the quality gates are re-run and the push is tried again, up to the
+// configured number of pushes. A retried push is recorded on the issue;
+// if it is given up on, the issue gets a comment saying why.
+func (rp *ResultsProcessor) pushBranch(ctx context.Context, issue *types.Issue, opts git.PushOptions) error {
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+	gitOps := rp.trackedGitOps(issue.ID)
+	maxAttempts := rp.pushRetry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := gitOps.Push(ctx, rp.workingDir, opts)
+		if err == nil {
+			if attempt > 1 {
+				rp.recordPushRetry(ctx, issue, opts, pushRetryAttempt{attempts: attempt, outcome: pushRetryPushed})
+			}
+			return nil
+		}
+		if !git.RemoteAdvanced(err) || maxAttempts == 1 {
+			return err
+		}
+		if attempt == maxAttempts {
+			reason := fmt.Sprintf("%s/%s kept advancing; gave up after %d pushes", opts.Remote, opts.Branch, attempt)
+			rp.recordPushRetry(ctx, issue, opts, pushRetryAttempt{attempts: attempt, outcome: pushRetryFailed, reason: reason})
+			return fmt.Errorf("push of %s failed: %s", opts.Branch, reason)
+		}
+
+		fmt.Printf("Remote %s/%s has advanced; rebasing onto it and retrying the push (%d/%d)\n",
+			opts.Remote, opts.Branch, attempt+1, maxAttempts)
+		if retry := rp.rebaseOntoRemote(ctx, issue, opts); retry != nil {
+			retry.attempts = attempt
+			rp.recordPushRetry(ctx, issue, opts, *retry)
+			return fmt.Errorf("push of %s failed: %s", opts.Branch, retry.reason)
+		}
+	}
+}
+
+// rebaseOntoRemote fetches opts.Branch, rebases the checked out branch onto
+// the remote one and re-runs the gates on the result. Returns nil if the
+// branch is ready to push again, or what went wrong. A conflicting rebase
+// is abandoned, leaving the branch as it was.
+func (rp *ResultsProcessor) rebaseOntoRemote(ctx context.Context, issue *types.Issue, opts git.PushOptions) *pushRetryAttempt {
+	gitOps := rp.trackedGitOps(issue.ID)
+	fetch := git.PushOptions{Remote: opts.Remote, Branch: opts.Branch, Token: opts.Token, TokenUser: opts.TokenUser}
+	if err := gitOps.Fetch(ctx, rp.workingDir, fetch); err != nil {
+		return &pushRetryAttempt{outcome: pushRetryFailed, reason: err.Error()}
+	}
+
+	upstream := opts.Remote + "/" + opts.Branch
+	rebase, err := gitOps.Rebase(ctx, rp.workingDir, git.RebaseOptions{BaseBranch: upstream, Autostash: true})
+	if rebase != nil && rebase.HasConflicts {
+		if _, abortErr := gitOps.Rebase(ctx, rp.workingDir, git.RebaseOptions{Abort: true}); abortErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to abort rebase onto %s: %v\n", upstream, abortErr)
+		}
+		return &pushRetryAttempt{
+			outcome:   pushRetryConflict,
+			conflicts: rebase.ConflictedFiles,
+			reason:    fmt.Sprintf("rebasing onto %s conflicts in %s", upstream, strings.Join(rebase.ConflictedFiles, ", ")),
+		}
+	}
+	if err != nil {
+		return &pushRetryAttempt{outcome: pushRetryFailed, reason: err.Error()}
+	}
+
+	if failed, err := rp.rerunGates(ctx, issue); err != nil {
+		return &pushRetryAttempt{outcome: pushRetryFailed, reason: fmt.Sprintf("failed to re-run quality gates: %v", err)}
+	} else if len(failed) > 0 {
+		return &pushRetryAttempt{
+			outcome:     pushRetryGatesFailed,
+			failedGates: failed,
+			reason:      fmt.Sprintf("quality gates fail after rebasing onto %s: %s", upstream, strings.Join(failed, ", ")),
+		}
+	}
+	return nil
+}
+
+// rerunGates runs the quality gates again on a rebased branch, when they ran
+// for the issue in the first place. Returns the gates that failed.
+func (rp *ResultsProcessor) rerunGates(ctx context.Context, issue *types.Issue) ([]string, error) {
+	if !rp.pushRetry.RerunGates || !rp.enableQualityGates || !rp.isVCRepo() {
+		return nil, nil
+	}
+	rp.updateSubmodules(ctx, issue)
+
+	var fullRuns *gates.FullRunTracker
+	if rp.executor != nil {
+		fullRuns = rp.executor.gateFullRuns
+	}
+	// Pending overrides were already reported when the gates first ran
+	overrides, _, err := gates.ResolveOverrides(ctx, rp.store, issue.ID, gates.AutomatedActors(ctx, rp.store, rp.actor))
+	if err != nil {
+		return nil, err
+	}
+	runner, err := gates.NewRunner(&gates.Config{
+		Store:          rp.store,
+		WorkingDir:     rp.workingDir,
+		Overrides:      overrides,
+		FullRunTracker: fullRuns,
+		Paths:          rp.pathScope,
+	})
+	if err != nil {
+		return nil, err
+	}
+	gateCtx, cancel := context.WithTimeout(ctx, rp.gatesTimeout)
+	defer cancel()
+
+	fmt.Printf("Re-running quality gates on the rebased branch (timeout: %v)...\n", rp.gatesTimeout)
+	results, passed := runner.RunAll(gateCtx)
+	if passed {
+		return nil, nil
+	}
+	var failed []string
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, string(result.Gate))
+		}
+	}
+	return failed, nil
+}
+
+// recordPushRetry logs a push retried event and, for a push that was given
+// up on, comments on the issue
+func (rp *ResultsProcessor) recordPushRetry(ctx context.Context, issue *types.Issue, opts git.PushOptions, retry pushRetryAttempt) {
+	severity := events.SeverityInfo
+	message := fmt.Sprintf("Pushed %s after rebasing onto %s/%s (%d attempts)", opts.Branch, opts.Remote, opts.Branch, retry.attempts)
+	if retry.outcome == pushRetryPushed {
+		fmt.Printf("✓ %s\n", message)
+	} else {
+		severity = events.SeverityWarning
+		message = fmt.Sprintf("Push of %s given up: %s", opts.Branch, retry.reason)
+		fmt.Printf("⚠️  %s\n", message)
+	}
+
+	rp.logEvent(ctx, events.EventTypePushRetried, severity, issue.ID, message,
+		map[string]interface{}{
+			"remote":       opts.Remote,
+			"branch":       opts.Branch,
+			"attempts":     retry.attempts,
+			"outcome":      retry.outcome,
+			"conflicts":    retry.conflicts,
+			"failed_gates": retry.failedGates,
+			"reason":       retry.reason,
+		})
+
+	if retry.outcome == pushRetryPushed {
+		return
+	}
+	comment := fmt.Sprintf("**Push failed**: %s/%s advanced while this issue was worked on, and branch %s could not be "+
+		"brought up to date automatically: %s.\n\nThe work is committed locally on %s; rebase it onto %s/%s and push it by hand.",
+		opts.Remote, opts.Branch, opts.Branch, retry.reason, opts.Branch, opts.Remote, opts.Branch)
+	if err := rp.store.AddComment(ctx, issue.ID, rp.actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add push failure comment: %v\n", err)
+	}
+}
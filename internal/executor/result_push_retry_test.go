@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestPushBranchRetriesWhenRemoteAdvanced(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(dir, name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(dir, "add", "-A")
+		run(dir, "commit", "-q", "-m", "change "+name)
+	}
+
+	remote := t.TempDir()
+	repoDir := t.TempDir()
+	other := t.TempDir()
+	run(remote, "init", "-q", "--bare")
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	run(repoDir, "checkout", "-q", "-b", "vc/vc-1-feature")
+	run(repoDir, "remote", "add", "origin", remote)
+	run(repoDir, "push", "-q", "origin", "vc/vc-1-feature")
+	run(other, "clone", "-q", "-b", "vc/vc-1-feature", remote, ".")
+
+	issue := &types.Issue{Title: "Feature", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	rp := &ResultsProcessor{store: store, gitOps: gitOps, workingDir: repoDir, actor: "test",
+		pushRetry: config.PushRetryConfig{MaxAttempts: 3}}
+	opts := git.PushOptions{Branch: "vc/vc-1-feature", Checks: &git.PushChecks{}}
+
+	// Someone else pushes to the branch; ours is rebased onto theirs
+	commit(other, "theirs.txt", "theirs")
+	run(other, "push", "-q", "origin", "vc/vc-1-feature")
+	commit(repoDir, "ours.txt", "ours")
+	if err := rp.pushBranch(ctx, issue, opts); err != nil {
+		t.Fatalf("pushBranch() error = %v", err)
+	}
+	if got, want := run(remote, "rev-parse", "vc/vc-1-feature"), run(repoDir, "rev-parse", "HEAD"); got != want {
+		t.Errorf("remote branch at %s, want the rebased HEAD %s", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "theirs.txt")); err != nil {
+		t.Errorf("rebased branch is missing the remote's commit: %v", err)
+	}
+
+	// A conflicting change is given up on, leaving our commit as it was
+	run(other, "pull", "-q", "--rebase")
+	commit(other, "README.md", "theirs")
+	run(other, "push", "-q", "origin", "vc/vc-1-feature")
+	commit(repoDir, "README.md", "ours")
+	ours := run(repoDir, "rev-parse", "HEAD")
+	err = rp.pushBranch(ctx, issue, opts)
+	if err == nil || !strings.Contains(err.Error(), "README.md") {
+		t.Fatalf("pushBranch() error = %v, want a conflict in README.md", err)
+	}
+	if head := run(repoDir, "rev-parse", "HEAD"); head != ours {
+		t.Errorf("HEAD at %s after the conflict, want it left at %s", head, ours)
+	}
+
+	evts, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypePushRetried})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(evts) != 2 {
+		t.Fatalf("got %d push retried events, want 2", len(evts))
+	}
+	outcomes := map[string]bool{}
+	for _, evt := range evts {
+		data, err := evt.GetPushRetryData()
+		if err != nil {
+			t.Fatalf("GetPushRetryData failed: %v", err)
+		}
+		outcomes[data.Outcome] = true
+	}
+	if !outcomes[pushRetryPushed] || !outcomes[pushRetryConflict] {
+		t.Errorf("outcomes = %v, want pushed and conflict", outcomes)
+	}
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0].Body, "conflicts in README.md") {
+		t.Errorf("comments = %+v, want the conflict explained", comments)
+	}
+}
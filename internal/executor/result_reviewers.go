@@ -0,0 +1,230 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// What prompted a reviewer suggestion
+const (
+	reviewTriggerPullRequest = "pull_request"
+	reviewTriggerEscalation  = "escalation"
+)
+
+// reviewSuggestion is who should review changes to a set of files
+type reviewSuggestion struct {
+	owners  []git.CodeOwner // By CODEOWNERS, most files owned first
+	authors []git.Reviewer  // Recent authors, best first
+}
+
+// requested returns the code owners to ask for a pull request review: users
+// by login and teams as "org/team". Owners named by email can't be asked.
+func (s *reviewSuggestion) requested() (users, teams []string) {
+	if s == nil {
+		return nil, nil
+	}
+	for _, owner := range s.owners {
+		switch {
+		case owner.IsTeam():
+			teams = append(teams, owner.Login())
+		case owner.IsUser():
+			users = append(users, owner.Login())
+		}
+	}
+	return users, teams
+}
+
+// names returns the suggested reviewers, code owners first, as written in
+// CODEOWNERS and as "Name <email>"
+func (s *reviewSuggestion) names() []string {
+	if s == nil {
+		return []string{}
+	}
+	names := make([]string, 0, len(s.owners)+len(s.authors))
+	for _, owner := range s.owners {
+		names = append(names, owner.Name)
+	}
+	for _, reviewer := range s.authors {
+		names = append(names, reviewer.String())
+	}
+	return names
+}
+
+// assignee returns who to assign the issue to: the top code owner that
+// isn't a team, else the top author
+func (s *reviewSuggestion) assignee() string {
+	for _, owner := range s.owners {
+		if !owner.IsTeam() {
+			return owner.Login()
+		}
+	}
+	if len(s.authors) > 0 {
+		return s.authors[0].Email
+	}
+	return ""
+}
+
+// suggestReviewers suggests human reviewers for changes to files: their
+// code owners by CODEOWNERS, then who recently authored commits to them.
+// The suggestion is recorded on the issue as a comment and an event, the
+// owners as owner:<owner> labels and, if configured, the top reviewer is
+// made the assignee of an unassigned issue. Returns the suggestion for the
+// caller to include in the pull request or escalation; nil if suggestions
+// are off or nobody else owns or has touched the files.
+func (rp *ResultsProcessor) suggestReviewers(ctx context.Context, issue *types.Issue, files []string, trigger string) *reviewSuggestion {
+	if !rp.reviewers.Enabled || rp.gitOps == nil || len(files) == 0 {
+		return nil
+	}
+	suggestion := &reviewSuggestion{}
+	if rp.reviewers.CodeOwners {
+		codeOwners, err := git.LoadCodeOwners(rp.workingDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to read CODEOWNERS: %v\n", err)
+		} else if codeOwners != nil {
+			suggestion.owners = codeOwners.OwnersOf(files)
+		}
+	}
+	authors, err := rp.gitOps.SuggestReviewers(ctx, rp.workingDir, files, git.ReviewerOptions{
+		Since:   time.Now().AddDate(0, 0, -rp.reviewers.WindowDays),
+		Max:     rp.reviewers.Max,
+		Exclude: rp.reviewers.Exclude,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to suggest reviewers: %v\n", err)
+	}
+	suggestion.authors = authors
+	if len(suggestion.owners) == 0 && len(suggestion.authors) == 0 {
+		return nil
+	}
+
+	rp.labelOwners(ctx, issue, suggestion.owners)
+
+	assigned := ""
+	if top := suggestion.assignee(); rp.reviewers.Assign && issue.Assignee == "" && top != "" {
+		if err := rp.store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": top}, rp.actor); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to assign %s to %s: %v\n", top, issue.ID, err)
+		} else {
+			assigned = top
+			issue.Assignee = assigned
+		}
+	}
+
+	owners := make([]string, len(suggestion.owners))
+	for i, owner := range suggestion.owners {
+		owners[i] = owner.Name
+	}
+	names := make([]string, len(suggestion.authors))
+	for i, reviewer := range suggestion.authors {
+		names[i] = reviewer.String()
+	}
+	all := suggestion.names()
+	fmt.Printf("Suggested reviewers: %s\n", strings.Join(all, ", "))
+
+	comment := fmt.Sprintf("**Suggested reviewers** (code owners and recent authors of the %d files touched):\n\n%s", len(files), formatReviewers(suggestion))
+	if assigned != "" {
+		comment += fmt.Sprintf("\nAssigned to %s.", assigned)
+	}
+	if err := rp.store.AddComment(ctx, issue.ID, rp.actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add reviewer suggestion comment: %v\n", err)
+	}
+	rp.logEvent(ctx, events.EventTypeReviewersSuggested, events.SeverityInfo, issue.ID,
+		fmt.Sprintf("Suggested %d reviewer(s) for %s: %s", len(all), strings.ReplaceAll(trigger, "_", " "), strings.Join(all, ", ")),
+		map[string]interface{}{
+			"trigger":   trigger,
+			"files":     len(files),
+			"reviewers": names,
+			"owners":    owners,
+			"assigned":  assigned,
+		})
+	return suggestion
+}
+
+// labelOwners labels the issue with each of its code owners, so they show
+// in notifications and can be filtered on
+func (rp *ResultsProcessor) labelOwners(ctx context.Context, issue *types.Issue, owners []git.CodeOwner) {
+	if len(owners) == 0 {
+		return
+	}
+	labels, err := rp.store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get labels of %s: %v\n", issue.ID, err)
+		return
+	}
+	for _, owner := range owners {
+		label := types.OwnerLabel(owner.Name)
+		if slices.Contains(labels, label) {
+			continue
+		}
+		if err := rp.store.AddLabel(ctx, issue.ID, label, rp.actor); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add label %s to %s: %v\n", label, issue.ID, err)
+		}
+	}
+}
+
+// formatReviewers renders a suggestion as a markdown list, code owners first
+func formatReviewers(s *reviewSuggestion) string {
+	var b strings.Builder
+	for _, owner := range s.owners {
+		fmt.Fprintf(&b, "- %s: code owner of %d of the files\n", owner.Name, owner.Files)
+	}
+	for _, reviewer := range s.authors {
+		fmt.Fprintf(&b, "- %s: %d commit(s) to %d of the files\n", reviewer, reviewer.Commits, reviewer.Files)
+	}
+	return b.String()
+}
+
+// commitFiles returns the files a commit changed, by their path before the
+// change so their history can be looked up
+func (rp *ResultsProcessor) commitFiles(ctx context.Context, commitHash string) []string {
+	raw, err := rp.getCommitDiff(ctx, commitHash)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, file := range git.ParseDiff(raw).Files {
+		if file.OldPath != "" {
+			files = append(files, file.OldPath)
+		} else {
+			files = append(files, file.Path)
+		}
+	}
+	return files
+}
+
+// touchedFiles returns the files the issue's work has touched: uncommitted
+// changes in the working tree plus the commits its executions made
+func (rp *ResultsProcessor) touchedFiles(ctx context.Context, issue *types.Issue) []string {
+	if rp.gitOps == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var files []string
+	add := func(paths []string) {
+		for _, path := range paths {
+			if !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	if status, err := rp.gitOps.GetStatus(ctx, rp.workingDir); err == nil {
+		add(statusFiles(status))
+	}
+	execs, err := rp.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID})
+	if err == nil {
+		for _, exec := range execs {
+			if exec.CommitHash != "" && !exec.IsRolledBack() {
+				add(rp.commitFiles(ctx, exec.CommitHash))
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,171 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSuggestReviewers(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	commitAs := func(name, email, file string) {
+		t.Helper()
+		path := filepath.Join(repoDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		content, _ := os.ReadFile(path)
+		if err := os.WriteFile(path, append(content, []byte(name+"\n")...), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", "-A"}, {"commit", "-m", "change " + file, "--author", name + " <" + email + ">"}} {
+			if out, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v\n%s", args, err, out)
+			}
+		}
+	}
+	commitAs("Ada", "ada@example.com", "pay/pay.go")
+	commitAs("Bob", "bob@example.com", "pay/pay.go")
+	commitAs("Bob", "bob@example.com", "pay/refund.go")
+
+	issue := &types.Issue{Title: "Fix refunds", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	rp := &ResultsProcessor{store: store, gitOps: gitOps, workingDir: repoDir, actor: "test"}
+	if got := rp.suggestReviewers(ctx, issue, []string{"pay/pay.go"}, reviewTriggerEscalation); got != nil {
+		t.Errorf("suggestReviewers() with suggestions off = %v, want nil", got)
+	}
+
+	// The agent's uncommitted edits are what escalation looks at
+	for _, file := range []string{"pay/pay.go", "pay/refund.go"} {
+		if err := os.WriteFile(filepath.Join(repoDir, file), []byte("package pay\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rp.reviewers = config.DefaultReviewersConfig()
+	rp.reviewers.Assign = true
+	reviewers := rp.suggestReviewers(ctx, issue, rp.touchedFiles(ctx, issue), reviewTriggerEscalation)
+	if reviewers == nil || len(reviewers.authors) != 2 || reviewers.authors[0].Email != "bob@example.com" || reviewers.authors[1].Email != "ada@example.com" {
+		t.Fatalf("suggestReviewers() = %+v, want Bob then Ada", reviewers)
+	}
+
+	updated, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if updated.Assignee != "bob@example.com" {
+		t.Errorf("Assignee = %q, want the top reviewer", updated.Assignee)
+	}
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0].Body, "Bob <bob@example.com>: 2 commit(s) to 2 of the files") {
+		t.Errorf("comments = %+v, want the suggestion recorded", comments)
+	}
+	evts, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeReviewersSuggested})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(evts) != 1 {
+		t.Fatalf("got %d reviewer events, want 1", len(evts))
+	}
+	if data, err := evts[0].GetReviewerSuggestionData(); err != nil || data.Trigger != reviewTriggerEscalation || data.Assigned != "bob@example.com" || len(data.Reviewers) != 2 {
+		t.Errorf("event data = %+v, %v", data, err)
+	}
+
+	// An assigned issue keeps its assignee
+	rp.suggestReviewers(ctx, issue, []string{"pay/pay.go"}, reviewTriggerPullRequest)
+	if updated, _ := store.GetIssue(ctx, issue.ID); updated.Assignee != "bob@example.com" {
+		t.Errorf("Assignee = %q, want it unchanged", updated.Assignee)
+	}
+}
+
+func TestSuggestReviewersCodeOwners(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoDir, ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	codeOwners := "* @acme/core\n/pay/ @acme/payments @carol\n"
+	if err := os.WriteFile(filepath.Join(repoDir, ".github", "CODEOWNERS"), []byte(codeOwners), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &types.Issue{Title: "Fix refunds", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	rp := &ResultsProcessor{store: store, gitOps: gitOps, workingDir: repoDir, actor: "test", reviewers: config.DefaultReviewersConfig()}
+	rp.reviewers.Assign = true
+
+	// New files have no authors, but still have owners
+	suggestion := rp.suggestReviewers(ctx, issue, []string{"pay/pay.go", "pay/refund.go", "main.go"}, reviewTriggerPullRequest)
+	if suggestion == nil {
+		t.Fatal("suggestReviewers() = nil, want the code owners")
+	}
+	want := []git.CodeOwner{{Name: "@acme/payments", Files: 2}, {Name: "@carol", Files: 2}, {Name: "@acme/core", Files: 1}}
+	if !reflect.DeepEqual(suggestion.owners, want) {
+		t.Errorf("owners = %v, want %v", suggestion.owners, want)
+	}
+	users, teams := suggestion.requested()
+	if !reflect.DeepEqual(users, []string{"carol"}) || !reflect.DeepEqual(teams, []string{"acme/payments", "acme/core"}) {
+		t.Errorf("requested() = %v, %v", users, teams)
+	}
+
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	for _, owner := range want {
+		if !slices.Contains(labels, types.OwnerLabel(owner.Name)) {
+			t.Errorf("labels = %v, want %s", labels, types.OwnerLabel(owner.Name))
+		}
+	}
+	// The first owner that isn't a team is assigned
+	if updated, _ := store.GetIssue(ctx, issue.ID); updated.Assignee != "carol" {
+		t.Errorf("Assignee = %q, want carol", updated.Assignee)
+	}
+	if body := formatReviewers(suggestion); !strings.Contains(body, "- @acme/payments: code owner of 2 of the files") {
+		t.Errorf("formatReviewers() = %q", body)
+	}
+
+	// Without code owners, nobody is left to suggest
+	rp.reviewers.CodeOwners = false
+	if got := rp.suggestReviewers(ctx, issue, []string{"pay/pay.go"}, reviewTriggerPullRequest); got != nil {
+		t.Errorf("suggestReviewers() without code owners = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// enforcePathScope looks for agent edits outside the issue's path scope.
+// Depending on the configured action they are reverted, or left in the
+// working tree (auto-commit never stages them); either way the issue gets a
+// comment listing them. Does nothing for issues without a scope.
+//
+// Only the agent's own edits count: files that were already dirty or
+// untracked before it ran (scopeBaseline) and that it left alone are ignored.
+// Files it changed on top of such work are flagged but never reverted, and
+// without a baseline nothing is reverted, since discarding them could throw
+// away a developer's uncommitted work.
+func (rp *ResultsProcessor) enforcePathScope(ctx context.Context, issue *types.Issue) {
+	if len(rp.pathScope) == 0 || rp.gitOps == nil {
+		return
+	}
+	status, err := rp.gitOps.GetStatus(ctx, rp.workingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check changes against scope %s: %v\n", rp.pathScope, err)
+		return
+	}
+	_, outside := rp.pathScope.Split(statusFiles(status))
+	outside, revertable := rp.agentScopeChanges(ctx, outside)
+	if len(outside) == 0 {
+		return
+	}
+
+	reverted := false
+	if rp.scopeViolation == config.ScopeViolationRevert && len(revertable) > 0 {
+		if err := rp.trackedGitOps(issue.ID).DiscardChanges(ctx, rp.workingDir, revertable); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to revert changes outside scope %s: %v\n", rp.pathScope, err)
+		} else {
+			reverted = true
+		}
+	}
+
+	action := "left out of the commit"
+	switch {
+	case reverted && len(revertable) == len(outside):
+		action = "reverted"
+	case reverted:
+		action = fmt.Sprintf("reverted except %d files that had uncommitted changes before the agent ran, which were left out of the commit",
+			len(outside)-len(revertable))
+	}
+	fmt.Printf("⚠️  %d changed files are outside scope %s (%s): %s\n",
+		len(outside), rp.pathScope, action, strings.Join(outside, ", "))
+
+	rp.logEvent(ctx, events.EventTypeScopeViolation, events.SeverityWarning, issue.ID,
+		fmt.Sprintf("Agent changed %d files outside scope %s (%s)", len(outside), rp.pathScope, action),
+		map[string]interface{}{
+			"scope":    []string(rp.pathScope),
+			"files":    outside,
+			"reverted": reverted,
+		})
+
+	comment := fmt.Sprintf("**Scope violation**: the agent changed files outside this issue's scope (%s). These changes were %s:\n\n- %s",
+		rp.pathScope, action, strings.Join(outside, "\n- "))
+	if err := rp.store.AddComment(ctx, issue.ID, rp.actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add scope violation comment: %v\n", err)
+	}
+}
+
+// agentScopeChanges narrows files outside the scope to those the agent
+// changed, comparing them with scopeBaseline. revertable are the ones that
+// were clean before the agent ran, so discarding them loses only its edits.
+// Without a baseline every file counts as changed and none is revertable.
+func (rp *ResultsProcessor) agentScopeChanges(ctx context.Context, files []string) (changed, revertable []string) {
+	if len(files) == 0 || rp.scopeBaseline == nil || rp.scopeBaseline.Files == nil {
+		return files, nil
+	}
+	current, err := rp.gitOps.Snapshot(ctx, rp.workingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to snapshot workspace to check scope: %v\n", err)
+		return files, nil
+	}
+	for _, file := range files {
+		before, dirty := rp.scopeBaseline.Files[file]
+		switch {
+		case !dirty:
+			changed = append(changed, file)
+			revertable = append(revertable, file)
+		case current.Files[file] != before:
+			changed = append(changed, file)
+		}
+	}
+	return changed, revertable
+}
+
+// statusFiles returns every file a git status reports as changed. Renames
+// are reported as "old -> new"; both sides are part of the change.
+func statusFiles(status *git.Status) []string {
+	files := append([]string{}, status.Modified...)
+	files = append(files, status.Added...)
+	files = append(files, status.Deleted...)
+	for _, renamed := range status.Renamed {
+		files = append(files, strings.Split(renamed, " -> ")...)
+	}
+	return append(files, status.Untracked...)
+}
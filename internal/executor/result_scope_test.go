@@ -0,0 +1,157 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestEnforcePathScope(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	issue := &types.Issue{Title: "Fix payments", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	for _, action := range []string{config.ScopeViolationFlag, config.ScopeViolationRevert} {
+		t.Run(action, func(t *testing.T) {
+			repoDir := t.TempDir()
+			if err := setupTestGitRepo(repoDir); err != nil {
+				t.Fatalf("Failed to set up git repo: %v", err)
+			}
+			writeFile := func(name, content string) {
+				path := filepath.Join(repoDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// A developer's untracked work outside the scope, there before the agent ran
+			writeFile("notes/todo.txt", "wip\n")
+			baseline, err := gitOps.Snapshot(ctx, repoDir)
+			if err != nil {
+				t.Fatalf("Snapshot failed: %v", err)
+			}
+
+			writeFile("services/payments/pay.go", "package x\n")
+			writeFile("services/billing/bill.go", "package x\n")
+
+			rp := &ResultsProcessor{
+				store:          store,
+				gitOps:         gitOps,
+				workingDir:     repoDir,
+				actor:          "test",
+				pathScope:      types.PathScope{"services/payments/**"},
+				scopeViolation: action,
+				scopeBaseline:  baseline,
+			}
+			rp.enforcePathScope(ctx, issue)
+
+			out, err := exec.Command("git", "-C", repoDir, "status", "--porcelain", "-uall").Output()
+			if err != nil {
+				t.Fatalf("git status failed: %v", err)
+			}
+			status := string(out)
+			if !strings.Contains(status, "services/payments/pay.go") {
+				t.Errorf("in-scope change was discarded:\n%s", status)
+			}
+			if reverted := !strings.Contains(status, "services/billing/bill.go"); reverted != (action == config.ScopeViolationRevert) {
+				t.Errorf("out-of-scope change reverted = %v with action %s:\n%s", reverted, action, status)
+			}
+			if data, err := os.ReadFile(filepath.Join(repoDir, "notes/todo.txt")); err != nil || string(data) != "wip\n" {
+				t.Errorf("pre-existing untracked file was touched: %q, %v", data, err)
+			}
+		})
+	}
+
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 2 || !strings.Contains(comments[1].Body, "services/billing/bill.go") ||
+		strings.Contains(comments[1].Body, "notes/todo.txt") {
+		t.Errorf("expected a scope violation comment per run naming only the agent's file, got %+v", comments)
+	}
+}
+
+func TestEnforcePathScopeKeepsDirtyWork(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	issue := &types.Issue{Title: "Fix payments", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	repoDir := t.TempDir()
+	if err := setupTestGitRepo(repoDir); err != nil {
+		t.Fatalf("Failed to set up git repo: %v", err)
+	}
+	todo := filepath.Join(repoDir, "todo.txt")
+	if err := os.WriteFile(todo, []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := gitOps.Snapshot(ctx, repoDir)
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	rp := &ResultsProcessor{
+		store:          store,
+		gitOps:         gitOps,
+		workingDir:     repoDir,
+		actor:          "test",
+		pathScope:      types.PathScope{"services/payments/**"},
+		scopeViolation: config.ScopeViolationRevert,
+	}
+
+	// Untouched by the agent: not a violation at all
+	rp.scopeBaseline = baseline
+	rp.enforcePathScope(ctx, issue)
+	if comments, _ := store.GetComments(ctx, issue.ID); len(comments) != 0 {
+		t.Errorf("pre-existing work was reported as a violation: %+v", comments)
+	}
+
+	// Changed by the agent on top of the developer's work, or with no
+	// baseline to tell: flagged, but never reverted
+	if err := os.WriteFile(todo, []byte("wip\nagent\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []*types.WorkspaceSnapshot{baseline, nil} {
+		rp.scopeBaseline = b
+		rp.enforcePathScope(ctx, issue)
+		if data, err := os.ReadFile(todo); err != nil || string(data) != "wip\nagent\n" {
+			t.Errorf("dirty file was reverted (baseline %v): %q, %v", b != nil, data, err)
+		}
+	}
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 2 || !strings.Contains(comments[0].Body, "todo.txt") {
+		t.Errorf("expected the changed file to be flagged twice, got %+v", comments)
+	}
+}
@@ -0,0 +1,123 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// handleSubmoduleChanges looks for submodules the agent changed. Moved
+// pointers are reverted or kept for the commit, depending on the configured
+// action; uncommitted changes inside a submodule can't be committed here and
+// are left in place. Either way the issue gets a comment listing them.
+func (rp *ResultsProcessor) handleSubmoduleChanges(ctx context.Context, issue *types.Issue) {
+	if rp.gitOps == nil {
+		return
+	}
+	changes, err := rp.gitOps.SubmoduleChanges(ctx, rp.workingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check for submodule changes: %v\n", err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	var moved, pointers, dirty []string
+	for _, change := range changes {
+		if change.PointerChanged() {
+			moved = append(moved, change.Path)
+			pointers = append(pointers, change.String())
+		}
+		if change.Dirty {
+			dirty = append(dirty, change.Path)
+		}
+	}
+
+	reverted := false
+	if len(moved) > 0 && rp.submodules.OnPointerChange == config.SubmodulePointerRevert {
+		if err := rp.trackedGitOps(issue.ID).ResetSubmodules(ctx, rp.workingDir, moved); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to revert submodule pointers: %v\n", err)
+		} else {
+			reverted = true
+		}
+	}
+
+	var notes []string
+	if len(pointers) > 0 {
+		action := "kept and will be committed"
+		if reverted {
+			action = "reverted to the commits recorded in HEAD"
+		} else if rp.submodules.OnPointerChange == config.SubmodulePointerRevert {
+			action = "left out of the commit (reverting them failed)"
+		}
+		fmt.Printf("⚠️  Agent moved %d submodule pointers (%s): %s\n", len(pointers), action, strings.Join(pointers, ", "))
+		notes = append(notes, fmt.Sprintf("The agent moved these submodule pointers, which were %s:\n\n- %s",
+			action, strings.Join(pointers, "\n- ")))
+	}
+	if len(dirty) > 0 {
+		fmt.Printf("⚠️  Agent left uncommitted changes inside %d submodules (not committed): %s\n", len(dirty), strings.Join(dirty, ", "))
+		notes = append(notes, fmt.Sprintf("The agent changed files inside these submodules. Changes inside a submodule must be committed "+
+			"to the submodule's own repository, so they were left uncommitted:\n\n- %s", strings.Join(dirty, "\n- ")))
+	}
+
+	rp.logEvent(ctx, events.EventTypeSubmoduleChanged, events.SeverityWarning, issue.ID,
+		fmt.Sprintf("Agent changed %d submodules", len(changes)),
+		map[string]interface{}{
+			"pointers": pointers,
+			"dirty":    dirty,
+			"reverted": reverted,
+		})
+
+	comment := "**Submodules changed**: " + strings.Join(notes, "\n\n")
+	if err := rp.store.AddComment(ctx, issue.ID, rp.actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add submodule change comment: %v\n", err)
+	}
+}
+
+// uncommittableSubmodules returns the submodule paths auto-commit must leave
+// out: submodules whose only changes are inside them (committing the
+// superproject records nothing for those), and, unless pointer changes are
+// committed, submodules whose pointer moved
+func (rp *ResultsProcessor) uncommittableSubmodules(ctx context.Context) []string {
+	changes, err := rp.gitOps.SubmoduleChanges(ctx, rp.workingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check for submodule changes: %v\n", err)
+		return nil
+	}
+	var paths []string
+	for _, change := range changes {
+		if !change.PointerChanged() || rp.submodules.OnPointerChange == config.SubmodulePointerRevert {
+			paths = append(paths, change.Path)
+		}
+	}
+	return paths
+}
+
+// updateSubmodules initializes and updates submodules before gates run, so
+// they build against the submodule commits the tree records. Submodules whose
+// pointer the agent moved are left at the agent's commit.
+func (rp *ResultsProcessor) updateSubmodules(ctx context.Context, issue *types.Issue) {
+	if !rp.submodules.Update || rp.gitOps == nil {
+		return
+	}
+	changes, err := rp.gitOps.SubmoduleChanges(ctx, rp.workingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check for submodule changes: %v (not updating submodules)\n", err)
+		return
+	}
+	var keep []string
+	for _, change := range changes {
+		if change.PointerChanged() {
+			keep = append(keep, change.Path)
+		}
+	}
+	if err := rp.trackedGitOps(issue.ID).UpdateSubmodules(ctx, rp.workingDir, keep); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v (gates run against the submodules as checked out)\n", err)
+	}
+}
@@ -0,0 +1,109 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestHandleSubmoduleChanges(t *testing.T) {
+	ctx := context.Background()
+	// Submodules are cloned from a local path, which git only allows when asked
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	store := memory.New()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	libDir := t.TempDir()
+	repoDir := t.TempDir()
+	for _, dir := range []string{libDir, repoDir} {
+		if err := setupTestGitRepo(dir); err != nil {
+			t.Fatalf("Failed to set up git repo: %v", err)
+		}
+	}
+	run(repoDir, "submodule", "add", "-q", libDir, "lib")
+	run(repoDir, "submodule", "add", "-q", libDir, "vendor/lib")
+	run(repoDir, "commit", "-q", "-m", "add submodules")
+	recorded := run(repoDir, "rev-parse", "HEAD:lib")
+
+	// The agent commits inside one submodule and edits files in the other
+	for _, sub := range []string{"lib", "vendor/lib"} {
+		dir := filepath.Join(repoDir, sub)
+		run(dir, "config", "user.email", "test@example.com")
+		run(dir, "config", "user.name", "Test User")
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run(filepath.Join(repoDir, "lib"), "commit", "-q", "-am", "agent change")
+
+	issue := &types.Issue{Title: "Bump lib", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	rp := &ResultsProcessor{store: store, gitOps: gitOps, workingDir: repoDir, actor: "test",
+		submodules: config.SubmodulesConfig{Update: true, OnPointerChange: config.SubmodulePointerCommit}}
+	if got := rp.uncommittableSubmodules(ctx); !reflect.DeepEqual(got, []string{"vendor/lib"}) {
+		t.Errorf("uncommittableSubmodules() committing pointers = %v, want only the dirty submodule", got)
+	}
+	rp.updateSubmodules(ctx, issue)
+	if head := run(filepath.Join(repoDir, "lib"), "rev-parse", "HEAD"); head == recorded {
+		t.Error("updateSubmodules() reset a pointer that is to be committed")
+	}
+
+	rp.submodules.OnPointerChange = config.SubmodulePointerRevert
+	if got := rp.uncommittableSubmodules(ctx); !reflect.DeepEqual(got, []string{"lib", "vendor/lib"}) {
+		t.Errorf("uncommittableSubmodules() reverting pointers = %v, want both submodules", got)
+	}
+	rp.handleSubmoduleChanges(ctx, issue)
+	if head := run(filepath.Join(repoDir, "lib"), "rev-parse", "HEAD"); head != recorded {
+		t.Errorf("lib is at %s after revert, want %s", head, recorded)
+	}
+	if content, _ := os.ReadFile(filepath.Join(repoDir, "vendor/lib/README.md")); string(content) != "# Changed" {
+		t.Error("changes inside vendor/lib were discarded")
+	}
+
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0].Body, "reverted to the commits recorded in HEAD:\n\n- lib (") ||
+		!strings.Contains(comments[0].Body, "left uncommitted:\n\n- vendor/lib") {
+		t.Errorf("comments = %+v, want both submodules listed", comments)
+	}
+	evts, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeSubmoduleChanged})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(evts) != 1 {
+		t.Fatalf("got %d submodule events, want 1", len(evts))
+	}
+	if data, err := evts[0].GetSubmoduleChangeData(); err != nil || !data.Reverted || len(data.Pointers) != 1 || len(data.Dirty) != 1 {
+		t.Errorf("event data = %+v, %v", data, err)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"time"
 
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/config"
 	"github.com/steveyegge/vc/internal/deduplication"
 	"github.com/steveyegge/vc/internal/git"
 	"github.com/steveyegge/vc/internal/sandbox"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
 	"github.com/steveyegge/vc/internal/watchdog"
 )
 
@@ -16,6 +18,10 @@ const (
 	// minCodeReviewConfidence is the minimum confidence threshold for skipping code review.
 	// If AI confidence is below this threshold, we request review as a safety measure.
 	minCodeReviewConfidence = 0.70
+
+	// reviewDiffBudget is the most diff text sent to code review and test
+	// coverage analysis; larger diffs are partly summarized
+	reviewDiffBudget = 30000
 )
 
 // ResultsProcessor handles post-execution results collection and tracker updates
@@ -28,6 +34,22 @@ type ResultsProcessor struct {
 	enableQualityGates        bool
 	enableAutoCommit          bool
 	enableAutoPR              bool
+	hosting                   config.HostingConfig // Git hosting API integration for auto-PR (no token = gh CLI)
+	prBaseBranch              string              // Branch pull requests merge into
+	autoCommitPaths           []string // Patterns of files to auto-commit (empty = all)
+	autoCommitExcludePaths    []string // Patterns of files never to auto-commit
+	autoCommitAmendOnRetry    bool     // Amend the previous attempt's commit if it is still HEAD
+	commitSigning             config.CommitSigningConfig // Signing key and committer identity for auto-commits
+	commitAttribution         config.CommitAttributionConfig // Co-author and VC trailers for auto-commits
+	pushChecks                config.PushChecksConfig        // Safety checks before pushing or opening a PR
+	pathScope                 types.PathScope                // Paths the issue's changes must stay within (empty = anywhere)
+	scopeViolation            string                         // What to do with edits outside pathScope: revert or flag
+	scopeBaseline             *types.WorkspaceSnapshot       // Working tree before the agent ran; edits outside pathScope are judged against it
+	reviewers                 config.ReviewersConfig         // Reviewer suggestions for pull requests and escalations
+	largeFiles                config.LargeFilesConfig        // LFS files and large binaries kept out of prompts and flagged when changed
+	submodules                config.SubmodulesConfig        // Submodule updates before gates and handling of moved pointers
+	pushRetry                 config.PushRetryConfig         // Fetch-rebase-retry of pushes the remote rejects as out of date
+	execution                 *types.Execution               // Execution whose results are processed (can be nil for REPL)
 	enableIterativeRefinement bool   // Enable iterative refinement in analysis phase (vc-t9ls)
 	workingDir                string
 	actor                     string             // The actor performing the update (e.g., "repl", "executor-instance-id")
@@ -39,6 +61,7 @@ type ResultsProcessor struct {
 	dedupBatchSize            int                // Max deduplication batch size (default: 100) (vc-a80e)
 	maxIncompleteRetries      int                // Max retries for incomplete work before escalation (default: 1) (vc-hsfz)
 	bootstrapMode             bool               // Bootstrap mode active (quota crisis) (vc-b027)
+	mutationSummary           string             // Mutation score summary from this execution, fed into test coverage analysis
 }
 
 // ResultsProcessorConfig holds configuration for the results processor
@@ -51,6 +74,22 @@ type ResultsProcessorConfig struct {
 	EnableQualityGates        bool
 	EnableAutoCommit          bool
 	EnableAutoPR              bool
+	Hosting                   config.HostingConfig // Open pull requests through the hosting provider's API when it has a token
+	PRBaseBranch              string              // Branch pull requests merge into (default: "main")
+	AutoCommitPaths           []string // Only auto-commit changed files matching these patterns (empty = all)
+	AutoCommitExcludePaths    []string // Never auto-commit changed files matching these patterns
+	AutoCommitAmendOnRetry    bool     // Amend the previous attempt's commit when retrying, if it is still HEAD
+	CommitSigning             config.CommitSigningConfig // Sign auto-commits and/or commit them as a distinct identity
+	CommitAttribution         config.CommitAttributionConfig // Attribute auto-commits to the agent and execution
+	PushChecks                config.PushChecksConfig        // Safety checks before pushing or opening a PR
+	PathScope                 types.PathScope                // Confine diffs, gates and the commit to these paths (empty = anywhere)
+	ScopeViolation            string                         // Revert or flag edits outside PathScope (empty = flag)
+	ScopeBaseline             *types.WorkspaceSnapshot       // Working tree before the agent ran (nil = never revert edits outside PathScope)
+	Reviewers                 config.ReviewersConfig         // Suggest reviewers from recent authorship (zero value = off)
+	LargeFiles                config.LargeFilesConfig        // Keep LFS files and large binaries out of prompts (zero value = off)
+	Submodules                config.SubmodulesConfig        // Update submodules before gates; revert or commit moved pointers (empty action = commit)
+	PushRetry                 config.PushRetryConfig         // Retry pushes rejected because the remote advanced (zero value = never)
+	Execution                 *types.Execution               // Execution record of the agent run (can be nil)
 	EnableIterativeRefinement bool         // Enable iterative refinement in analysis phase (vc-t9ls)
 	WorkingDir                string
 	Actor                     string           // Actor ID for tracking who made the changes
@@ -70,6 +109,8 @@ type ProcessingResult struct {
 	DiscoveredIssues []string // IDs of discovered issues created
 	GatesPassed      bool     // Did quality gates pass?
 	CommitHash       string   // Git commit hash (if auto-commit succeeded)
+	NeedsReview      bool     // Code review found problems in the commit, or couldn't check it
+	PRURL            string   // Pull request opened for the commit (if auto-PR succeeded)
 	Summary          string   // Human-readable summary
 	AIAnalysis       *ai.Analysis // The AI analysis result (if available)
 }
@@ -23,6 +23,13 @@ import (
 //
 // When in HEALTHY or ESCALATED mode, claims regular work.
 func (e *Executor) GetReadyWork(ctx context.Context) (*types.Issue, error) {
+	issue, _, err := e.selectWork(ctx)
+	return issue, err
+}
+
+// selectWork is GetReadyWork, also reporting whether the issue is simply the
+// top of the regular ready queue rather than a blocker or self-healing pick
+func (e *Executor) selectWork(ctx context.Context) (issue *types.Issue, fromQueue bool, err error) {
 	mode := e.getSelfHealingMode()
 
 	switch mode {
@@ -33,17 +40,17 @@ func (e *Executor) GetReadyWork(ctx context.Context) (*types.Issue, error) {
 		// Try fallback chain
 		if work := e.findBaselineIssues(ctx); work != nil {
 			e.recordSelfHealingProgress()
-			return work, nil
+			return work, false, nil
 		}
 
 		if work := e.investigateBlockedBaseline(ctx); work != nil {
 			e.recordSelfHealingProgress()
-			return work, nil
+			return work, false, nil
 		}
 
 		if work := e.findDiscoveredBlockers(ctx); work != nil {
 			e.recordSelfHealingProgress()
-			return work, nil
+			return work, false, nil
 		}
 
 		// No work found - increment counter and check for deadlock (vc-ipoj)
@@ -78,7 +85,7 @@ func (e *Executor) GetReadyWork(ctx context.Context) (*types.Issue, error) {
 		return e.getNormalWork(ctx)
 
 	default:
-		return nil, fmt.Errorf("unknown self-healing mode: %v", mode)
+		return nil, false, fmt.Errorf("unknown self-healing mode: %v", mode)
 	}
 }
 
@@ -441,7 +448,9 @@ func (e *Executor) escalateSelfHealingDeadlock(ctx context.Context) error {
 
 // getNormalWork retrieves regular ready work (not in self-healing mode).
 // This is the standard work selection path when baseline is healthy.
-func (e *Executor) getNormalWork(ctx context.Context) (*types.Issue, error) {
+// Reports whether the issue came from the regular ready queue rather than
+// blocker priority; with no work at all, that is the queue too.
+func (e *Executor) getNormalWork(ctx context.Context) (*types.Issue, bool, error) {
 	// Priority 1: Try to get a ready blocker (if blocker priority enabled)
 	var issue *types.Issue
 	var foundViaBlocker bool
@@ -449,7 +458,7 @@ func (e *Executor) getNormalWork(ctx context.Context) (*types.Issue, error) {
 		var err error
 		issue, err = e.getNextReadyBlocker(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get ready blockers: %w", err)
+			return nil, false, fmt.Errorf("failed to get ready blockers: %w", err)
 		}
 
 		// Track whether we found work via blocker path
@@ -468,12 +477,13 @@ func (e *Executor) getNormalWork(ctx context.Context) (*types.Issue, error) {
 
 		issues, err := e.store.GetReadyWork(ctx, filter)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get ready work: %w", err)
+			return nil, false, fmt.Errorf("failed to get ready work: %w", err)
 		}
 
 		if len(issues) == 0 {
 			// No work available
-			return nil, nil
+			e.checkDependencyGraph(ctx)
+			return nil, true, nil
 		}
 
 		// vc-7100: Take the first issue after filtering
@@ -494,5 +504,17 @@ func (e *Executor) getNormalWork(ctx context.Context) (*types.Issue, error) {
 			})
 	}
 
-	return issue, nil
+	return issue, !foundViaBlocker, nil
+}
+
+// checkDependencyGraph looks for dependency cycles and knots that could be
+// why there is no ready work, filing a diagnostic for each new one. Checks
+// are shared between executors and throttled by the checker.
+func (e *Executor) checkDependencyGraph(ctx context.Context) {
+	if e.config.DependencyChecker == nil {
+		return
+	}
+	if _, err := e.config.DependencyChecker.CheckIfStale(ctx, e.config.DependencyCheckIdleInterval); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: dependency graph check failed: %v\n", err)
+	}
 }
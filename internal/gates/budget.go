@@ -0,0 +1,103 @@
+package gates
+
+import (
+	"fmt"
+	"time"
+)
+
+// BudgetSkipReason marks optional gates skipped because the time budget ran out
+const BudgetSkipReason = "not run (budget)"
+
+// defaultRequiredGates always complete, even when the budget is exhausted
+var defaultRequiredGates = []string{string(GateBuild), string(GateTest)}
+
+// BudgetConfig is a soft wall-clock budget for a gate run.
+//
+// Required gates (and the gates they need) run first and always complete.
+// Once the budget is used up, the remaining optional gates are skipped and
+// reported as "not run (budget)" instead of failing. This keeps the agent loop
+// tight on very large repos. The hard limit is still VC_QUALITY_GATES_TIMEOUT.
+//
+// Example .vc/gates.yaml:
+//
+//	budget:
+//	  total: 3m
+//	  required: [build, test]
+type BudgetConfig struct {
+	Total    string   `yaml:"total"`    // Duration string, e.g. "3m"
+	Required []string `yaml:"required"` // Gates that always complete (default: build, test)
+}
+
+// Validate checks the budget config for invalid settings
+func (c *BudgetConfig) Validate() error {
+	if c.Total == "" {
+		return fmt.Errorf("total is required")
+	}
+	d, err := time.ParseDuration(c.Total)
+	if err != nil {
+		return fmt.Errorf("invalid total: %w", err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("total must be positive (got %v)", d)
+	}
+	return nil
+}
+
+// total returns the parsed budget (Validate has already checked it)
+func (c *BudgetConfig) total() time.Duration {
+	d, _ := time.ParseDuration(c.Total)
+	return d
+}
+
+// requiredGates returns the configured required gates or the default
+func (c *BudgetConfig) requiredGates() []string {
+	if len(c.Required) == 0 {
+		return defaultRequiredGates
+	}
+	return c.Required
+}
+
+// requiredStages returns the required gates plus everything they need, since
+// a required gate can't complete without its dependencies.
+func requiredStages(stages []gateStage, required []string) map[GateType]bool {
+	byType := make(map[GateType]gateStage, len(stages))
+	for _, stage := range stages {
+		byType[stage.gateType] = stage
+	}
+
+	result := make(map[GateType]bool)
+	var visit func(gate GateType)
+	visit = func(gate GateType) {
+		stage, ok := byType[gate]
+		if !ok || result[gate] {
+			return
+		}
+		result[gate] = true
+		for _, need := range stage.needs {
+			visit(need)
+		}
+	}
+	for _, name := range required {
+		visit(GateType(name))
+	}
+	return result
+}
+
+// prioritizeStages moves required stages ahead of optional ones, so the
+// budget is spent on them first. Relative order is kept within each group,
+// which preserves dependency order because required stages never need
+// optional ones.
+func prioritizeStages(stages []gateStage, required map[GateType]bool) []gateStage {
+	prioritized := make([]gateStage, 0, len(stages))
+	for _, stage := range stages {
+		if required[stage.gateType] {
+			prioritized = append(prioritized, stage)
+		}
+	}
+	for _, stage := range stages {
+		if !required[stage.gateType] {
+			prioritized = append(prioritized, stage)
+		}
+	}
+	return prioritized
+}
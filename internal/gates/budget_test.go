@@ -0,0 +1,81 @@
+package gates
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBudgetConfig_Validate(t *testing.T) {
+	valid := &BudgetConfig{Total: "3m"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid budget, got %v", err)
+	}
+	for _, total := range []string{"", "soon", "-1m"} {
+		if err := (&BudgetConfig{Total: total}).Validate(); err == nil {
+			t.Errorf("Expected error for total %q", total)
+		}
+	}
+}
+
+func TestPrioritizeStages_RequiredFirstWithDependencies(t *testing.T) {
+	stages := []gateStage{
+		{gateType: "generate"},
+		{gateType: "lint"},
+		{gateType: "build", needs: []GateType{"generate"}},
+		{gateType: "docs"},
+		{gateType: "test", needs: []GateType{"build"}},
+	}
+
+	required := requiredStages(stages, []string{"test"})
+	for _, gate := range []GateType{"generate", "build", "test"} {
+		if !required[gate] {
+			t.Errorf("Expected %s to be required (dependency of test)", gate)
+		}
+	}
+	if required["lint"] || required["docs"] {
+		t.Error("Expected lint and docs to be optional")
+	}
+
+	var order []GateType
+	for _, stage := range prioritizeStages(stages, required) {
+		order = append(order, stage.gateType)
+	}
+	want := []GateType{"generate", "build", "test", "lint", "docs"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Expected order %v, got %v", want, order)
+		}
+	}
+}
+
+func TestRunAll_BudgetSkipsOptionalGates(t *testing.T) {
+	sorted, err := sortPipeline([]PipelineStage{
+		{Name: "style", Command: "true"},
+		{Name: "compile", Command: "sleep 0.05"},
+		{Name: "unit", Command: "true", Needs: []string{"compile"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid pipeline, got %v", err)
+	}
+	runner := &Runner{
+		workingDir: t.TempDir(),
+		pipeline:   sorted,
+		budget:     &BudgetConfig{Total: "10ms", Required: []string{"unit"}},
+	}
+
+	results, allPassed := runner.RunAll(context.Background())
+	if !allPassed {
+		t.Fatal("Expected budget skips not to fail the run")
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	// Required gate and its dependency run first and complete despite the budget
+	if results[0].Gate != "compile" || results[0].Skipped || results[1].Gate != "unit" || results[1].Skipped {
+		t.Errorf("Expected compile and unit to run first, got %s, %s", results[0].Gate, results[1].Gate)
+	}
+	if results[2].Gate != "style" || !results[2].Skipped || results[2].SkipReason != BudgetSkipReason {
+		t.Errorf("Expected style to be skipped for budget, got %+v", results[2])
+	}
+}
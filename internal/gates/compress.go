@@ -0,0 +1,41 @@
+package gates
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// passingTestLineRe matches go test -v progress lines for tests that ran and
+// passed, which say nothing about why a gate failed
+var passingTestLineRe = regexp.MustCompile(`^\s*(=== (RUN|PAUSE|CONT|NAME)\s|--- (PASS|SKIP): )`)
+
+// CompressOutput makes gate output smaller for a prompt while keeping what
+// explains the failure: progress lines of passing tests are dropped and runs
+// of repeated lines are collapsed into one line with a count. Failure
+// messages, file:line references and panics are all kept.
+func CompressOutput(output string) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	var out []string
+	repeats := 0
+	flush := func() {
+		if repeats > 0 {
+			out = append(out, fmt.Sprintf("[previous line repeated %d more times]", repeats))
+			repeats = 0
+		}
+	}
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if passingTestLineRe.MatchString(line) {
+			continue
+		}
+		if len(out) > 0 && line != "" && line == out[len(out)-1] {
+			repeats++
+			continue
+		}
+		flush()
+		out = append(out, line)
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
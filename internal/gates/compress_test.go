@@ -0,0 +1,30 @@
+package gates
+
+import "testing"
+
+func TestCompressOutput(t *testing.T) {
+	output := "=== RUN   TestCheckout\n" +
+		"=== RUN   TestCheckout/empty_cart\n" +
+		"    cart_test.go:12: total = 3, want 0\n" +
+		"--- FAIL: TestCheckout (0.00s)\n" +
+		"    --- PASS: TestCheckout/full_cart (0.00s)\n" +
+		"    --- FAIL: TestCheckout/empty_cart (0.00s)\n" +
+		"warning: retrying connection\r\n" +
+		"warning: retrying connection\n" +
+		"warning: retrying connection  \n" +
+		"FAIL\n"
+	want := "    cart_test.go:12: total = 3, want 0\n" +
+		"--- FAIL: TestCheckout (0.00s)\n" +
+		"    --- FAIL: TestCheckout/empty_cart (0.00s)\n" +
+		"warning: retrying connection\n" +
+		"[previous line repeated 2 more times]\n" +
+		"FAIL\n"
+	if got := CompressOutput(output); got != want {
+		t.Errorf("CompressOutput() = %q, want %q", got, want)
+	}
+
+	// The failures it points at survive compression
+	if got, want := len(ParseFailures(CompressOutput(output))), len(ParseFailures(output)); got != want {
+		t.Errorf("compressed output has %d failures, want %d", got, want)
+	}
+}
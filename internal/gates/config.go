@@ -0,0 +1,100 @@
+package gates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile represents the structure of .vc/gates.yaml
+type ConfigFile struct {
+	// Integration test stage with service dependencies (optional)
+	Integration *IntegrationConfig `yaml:"integration"`
+
+	// Custom gate pipeline (optional, default: build -> test -> lint)
+	Pipeline []PipelineStage `yaml:"pipeline"`
+
+	// Soft time budget for a gate run (optional, default: no budget)
+	Budget *BudgetConfig `yaml:"budget"`
+
+	// Shell for gate commands: sh, bash, cmd, powershell or pwsh
+	// (optional, default: sh, or cmd on Windows)
+	Shell string `yaml:"shell"`
+
+	// Incremental build/test for large monorepos (optional, default: full runs)
+	Incremental *IncrementalConfig `yaml:"incremental"`
+}
+
+// ConfigFilePath returns the location of the gates config file for a
+// project: .vc/gates.yaml, or VC_GATES_CONFIG if set (e.g. by a profile).
+// A relative VC_GATES_CONFIG is relative to the project root.
+func ConfigFilePath(projectRoot string) string {
+	if path := os.Getenv("VC_GATES_CONFIG"); path != "" {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(projectRoot, path)
+	}
+	return filepath.Join(projectRoot, ".vc", "gates.yaml")
+}
+
+// LoadConfigFile loads configuration from .vc/gates.yaml (see ConfigFilePath).
+// A missing file is not an error - it returns an empty config (built-in gates only).
+func LoadConfigFile(projectRoot string) (*ConfigFile, error) {
+	configPath := ConfigFilePath(projectRoot)
+
+	// If file doesn't exist, return empty config, unless it was named
+	// explicitly
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if os.Getenv("VC_GATES_CONFIG") != "" {
+			return nil, fmt.Errorf("gates config file %s (VC_GATES_CONFIG) not found", configPath)
+		}
+		return &ConfigFile{}, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading gates config file: %w", err)
+	}
+
+	var configFile ConfigFile
+	if err := yaml.Unmarshal(data, &configFile); err != nil {
+		return nil, fmt.Errorf("parsing gates config file: %w", err)
+	}
+
+	if err := configFile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid gates config file %s: %w", configPath, err)
+	}
+
+	return &configFile, nil
+}
+
+// Validate checks the config file for invalid settings
+func (cf *ConfigFile) Validate() error {
+	if cf.Integration != nil {
+		if err := cf.Integration.Validate(); err != nil {
+			return fmt.Errorf("integration: %w", err)
+		}
+	}
+	if err := validateShell(cf.Shell); err != nil {
+		return err
+	}
+	if cf.Incremental != nil {
+		if err := cf.Incremental.Validate(); err != nil {
+			return fmt.Errorf("incremental: %w", err)
+		}
+	}
+	if cf.Budget != nil {
+		if err := cf.Budget.Validate(); err != nil {
+			return fmt.Errorf("budget: %w", err)
+		}
+	}
+	if len(cf.Pipeline) > 0 {
+		if _, err := sortPipeline(cf.Pipeline); err != nil {
+			return fmt.Errorf("pipeline: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package gates
+
+import (
+	"os"
+	"runtime"
+)
+
+// PlannedGate describes how a gate would run, without running it
+type PlannedGate struct {
+	Gate       GateType
+	Command    string     // What the gate runs
+	Tools      []string   // Programs the command runs (best effort for custom commands)
+	Shell      string     // Shell for custom commands ("" for built-in gates)
+	Needs      []GateType // Gates that must pass first
+	Timeout    string     // Gate-specific timeout ("" = only the overall timeout applies)
+	Required   bool       // Always completes, even when the budget is used up
+	SkipReason string     // Set if the gate would be skipped (e.g. approved override)
+}
+
+// Plan describes a gate run for the current config, for debugging .vc/gates.yaml
+type Plan struct {
+	WorkingDir     string
+	ConfigFile     string // Path of .vc/gates.yaml ("" if the project has none)
+	CustomProvider bool   // A custom GateProvider is configured; its gates can't be explained
+	Budget         *BudgetConfig
+	Gates          []PlannedGate // In execution order
+}
+
+// Explain returns the gates that RunAll would run, in order, with their
+// commands and timeouts. Nothing is executed.
+func (r *Runner) Explain() *Plan {
+	plan := &Plan{
+		WorkingDir: r.workingDir,
+		Budget:     r.budget,
+	}
+	if _, err := os.Stat(ConfigFilePath(r.workingDir)); err == nil {
+		plan.ConfigFile = ConfigFilePath(r.workingDir)
+	}
+	if r.provider != nil {
+		plan.CustomProvider = true
+		return plan
+	}
+
+	// Mirror RunAll's ordering
+	stages := r.stages()
+	var required map[GateType]bool
+	if r.budget != nil {
+		required = requiredStages(stages, r.budget.requiredGates())
+		stages = prioritizeStages(stages, required)
+	}
+
+	for _, stage := range stages {
+		planned := PlannedGate{
+			Gate:     stage.gateType,
+			Command:  stage.command,
+			Tools:    stage.tools,
+			Needs:    stage.needs,
+			Timeout:  stage.timeout,
+			Required: r.budget == nil || required[stage.gateType],
+		}
+		if stage.custom {
+			planned.Shell = r.effectiveShell()
+		}
+		if override, ok := r.overrides[stage.gateType]; ok {
+			planned.SkipReason = override.SkipReason()
+		}
+		plan.Gates = append(plan.Gates, planned)
+	}
+	return plan
+}
+
+// effectiveShell returns the shell custom commands run under
+func (r *Runner) effectiveShell() string {
+	name, _ := shellInvocation(r.shell, runtime.GOOS, "")
+	return name
+}
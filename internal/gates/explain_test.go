@@ -0,0 +1,70 @@
+package gates
+
+import (
+	"testing"
+)
+
+func TestExplain_DefaultGates(t *testing.T) {
+	runner := &Runner{workingDir: t.TempDir()}
+
+	plan := runner.Explain()
+	if plan.ConfigFile != "" {
+		t.Errorf("Expected no config file, got %s", plan.ConfigFile)
+	}
+	if len(plan.Gates) != 3 {
+		t.Fatalf("Expected 3 built-in gates, got %d", len(plan.Gates))
+	}
+	if plan.Gates[0].Gate != GateBuild || plan.Gates[0].Command != "go build ./..." || plan.Gates[0].Shell != "" {
+		t.Errorf("Unexpected build gate plan: %+v", plan.Gates[0])
+	}
+	if len(plan.Gates[2].Tools) != 1 || plan.Gates[2].Tools[0] != "golangci-lint" {
+		t.Errorf("Expected the lint gate to need golangci-lint, got %v", plan.Gates[2].Tools)
+	}
+	for _, gate := range plan.Gates {
+		if !gate.Required {
+			t.Errorf("Expected %s to be required without a budget", gate.Gate)
+		}
+	}
+}
+
+func TestExplain_PipelineBudgetAndOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeGatesConfig(t, dir, "shell: bash\n")
+
+	sorted, err := sortPipeline([]PipelineStage{
+		{Name: "vet", Command: "go vet ./...", Needs: []string{"build"}},
+		{Name: "build"},
+		{Name: "test", Needs: []string{"build"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid pipeline, got %v", err)
+	}
+	runner := &Runner{
+		workingDir: dir,
+		pipeline:   sorted,
+		shell:      ShellBash,
+		budget:     &BudgetConfig{Total: "1m"},
+		overrides:  map[GateType]*GateOverride{GateTest: {Gate: GateTest, ApprovedBy: "bob"}},
+	}
+
+	plan := runner.Explain()
+	if plan.ConfigFile != ConfigFilePath(dir) {
+		t.Errorf("Expected config file path, got %q", plan.ConfigFile)
+	}
+
+	// Required gates (build, test) run before optional vet
+	want := []GateType{GateBuild, GateTest, "vet"}
+	for i, gate := range plan.Gates {
+		if gate.Gate != want[i] {
+			t.Fatalf("Expected order %v, got gate %d = %s", want, i, gate.Gate)
+		}
+	}
+
+	vet := plan.Gates[2]
+	if vet.Required || vet.Shell != ShellBash || vet.Command != "go vet ./..." || len(vet.Needs) != 1 {
+		t.Errorf("Unexpected vet plan: %+v", vet)
+	}
+	if plan.Gates[1].SkipReason == "" {
+		t.Error("Expected overridden test gate to show a skip reason")
+	}
+}
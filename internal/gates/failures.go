@@ -0,0 +1,69 @@
+package gates
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Failure is a problem a gate's output points at in the code: a compile
+// error, a vet or lint finding, or a failed test assertion
+type Failure struct {
+	// File is the path as the tool printed it: relative to where it ran,
+	// absolute, or only the base name (go test)
+	File    string
+	Line    int
+	Column  int    // 0 if not printed
+	Test    string // Test the failure was reported in, for test output
+	Message string
+}
+
+var (
+	// failureLineRe matches "file.go:12:5: message", the format of go
+	// build, go vet, golangci-lint and t.Errorf. The file must have an
+	// extension, so "panic: runtime error: ..." doesn't match.
+	failureLineRe = regexp.MustCompile(`^(\s*)((?:[A-Za-z]:)?[^\s:]+\.[A-Za-z0-9]+):(\d+)(?::(\d+))?: (.+)$`)
+	// failedTestRe matches the header of a failed test's output
+	failedTestRe = regexp.MustCompile(`^\s*--- FAIL: (\S+)`)
+)
+
+// ParseFailures extracts the failures a gate's output points at in the
+// code, in order and without duplicates. Lines indented under an indented
+// failure (a multi-line test message) are part of its message; the rest of
+// the output, such as the source lines linters quote, is ignored.
+func ParseFailures(output string) []Failure {
+	var failures []Failure
+	seen := make(map[Failure]bool)
+	var test string
+	last, lastIndent := -1, 0
+	for _, line := range strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n") {
+		if m := failedTestRe.FindStringSubmatch(line); m != nil {
+			test, last = m[1], -1
+			continue
+		}
+		if m := failureLineRe.FindStringSubmatch(line); m != nil {
+			f := Failure{File: m[2], Test: test, Message: strings.TrimSpace(m[5])}
+			f.Line, _ = strconv.Atoi(m[3])
+			f.Column, _ = strconv.Atoi(m[4])
+			if seen[f] {
+				last = -1
+				continue
+			}
+			seen[f] = true
+			failures = append(failures, f)
+			last, lastIndent = len(failures)-1, len(m[1])
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if last >= 0 && lastIndent > 0 && trimmed != "" && indent > lastIndent {
+			failures[last].Message += "\n" + trimmed
+			continue
+		}
+		if trimmed != "" && indent == 0 {
+			test = "" // The test's output ended, e.g. with "FAIL"
+		}
+		last = -1
+	}
+	return failures
+}
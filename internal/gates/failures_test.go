@@ -0,0 +1,64 @@
+package gates
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []Failure
+	}{
+		{
+			name: "build and vet errors",
+			output: `# github.com/acme/widgets/internal/store
+internal/store/store.go:42:9: undefined: sqlx
+internal/store/store.go:42:9: undefined: sqlx
+./main.go:7: unreachable code
+`,
+			want: []Failure{
+				{File: "internal/store/store.go", Line: 42, Column: 9, Message: "undefined: sqlx"},
+				{File: "./main.go", Line: 7, Message: "unreachable code"},
+			},
+		},
+		{
+			name: "lint findings quote source lines",
+			output: `internal/foo/foo.go:12:2: Error return value of ` + "`f.Close`" + ` is not checked (errcheck)
+	defer f.Close()
+	^
+1 issues:
+`,
+			want: []Failure{
+				{File: "internal/foo/foo.go", Line: 12, Column: 2, Message: "Error return value of `f.Close` is not checked (errcheck)"},
+			},
+		},
+		{
+			name: "test failures",
+			output: `--- FAIL: TestCheckout (0.00s)
+    --- FAIL: TestCheckout/empty_cart (0.00s)
+        checkout_test.go:31: total = 5, want 0
+            cart: []
+FAIL
+FAIL	github.com/acme/widgets/checkout	0.012s
+panic: runtime error: index out of range [recovered]
+	/usr/local/go/src/testing/testing.go:1631 +0x2a
+`,
+			want: []Failure{
+				{File: "checkout_test.go", Line: 31, Test: "TestCheckout/empty_cart", Message: "total = 5, want 0\ncart: []"},
+			},
+		},
+		{
+			name:   "no failures",
+			output: "ok  \tgithub.com/acme/widgets\t0.5s\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseFailures(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFailures() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
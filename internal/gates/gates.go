@@ -2,14 +2,17 @@ package gates
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/idempotency"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/types"
 )
@@ -22,14 +25,18 @@ const (
 	GateLint     GateType = "lint"
 	GateBuild    GateType = "build"
 	GateApproval GateType = "approval" // Human approval gate (vc-145)
+	GateMutation GateType = "mutation" // Optional, scheduled mutation testing gate
+	GateIntegration GateType = "integration" // Integration tests against declared service dependencies
 )
 
 // Result represents the outcome of a quality gate check
 type Result struct {
-	Gate    GateType
-	Passed  bool
-	Output  string
-	Error   error
+	Gate       GateType
+	Passed     bool
+	Output     string
+	Error      error
+	Skipped    bool   // Gate was not executed (Passed is true so it doesn't block)
+	SkipReason string // Why the gate was skipped (e.g. approved override)
 }
 
 // GateProvider is an interface for running quality gates
@@ -58,6 +65,15 @@ type Runner struct {
 	workingDir       string
 	provider         GateProvider     // Optional: pluggable gate provider (defaults to built-in)
 	progressCallback ProgressCallback // Optional: progress reporting callback (vc-267)
+	integration      *IntegrationConfig // Optional: integration test stage (nil or disabled = skipped)
+	overrides        map[GateType]*GateOverride // Approved per-issue gate overrides
+	pipeline         []PipelineStage // Optional: custom gate DAG in execution order (empty = built-in gates)
+	budget           *BudgetConfig   // Optional: soft time budget (nil = all gates always run)
+	shell            string          // Shell for gate commands ("" = OS default)
+	incremental      *IncrementalConfig // Optional: scope build/test to affected packages (nil or disabled = full runs)
+	fullRuns         *FullRunTracker    // Optional: tracks the periodic full run (nil = every run is full)
+	paths            types.PathScope    // Optional: only build/test packages under these paths (nil = all)
+	scope            []string           // Packages for build/test in this run (nil = ./...)
 }
 
 // Config holds quality gate runner configuration
@@ -67,6 +83,14 @@ type Config struct {
 	WorkingDir       string           // Directory where gate commands are executed
 	Provider         GateProvider     // Optional: pluggable gate provider (defaults to built-in)
 	ProgressCallback ProgressCallback // Optional: progress reporting callback (vc-267). Note: only works with built-in gates, not custom providers.
+	Integration      *IntegrationConfig // Optional: integration test stage (default: loaded from .vc/gates.yaml in WorkingDir)
+	Overrides        []*GateOverride    // Optional: approved overrides for the issue being gated (see ResolveOverrides)
+	Pipeline         []PipelineStage    // Optional: custom gate pipeline (default: loaded from .vc/gates.yaml in WorkingDir)
+	Budget           *BudgetConfig      // Optional: soft time budget (default: loaded from .vc/gates.yaml in WorkingDir)
+	Shell            string             // Optional: shell for gate commands (default: loaded from .vc/gates.yaml, else OS default)
+	Incremental      *IncrementalConfig // Optional: incremental build/test (default: loaded from .vc/gates.yaml in WorkingDir)
+	FullRunTracker   *FullRunTracker    // Optional: shared across runs so incremental mode knows when a full run is due
+	Paths            types.PathScope    // Optional: the issue's path scope; build/test only cover packages within it
 }
 
 // NewRunner creates a new quality gate runner
@@ -78,13 +102,97 @@ func NewRunner(cfg *Config) (*Runner, error) {
 		cfg.WorkingDir = "."
 	}
 
-	return &Runner{
+	// Load optional gate configuration from .vc/gates.yaml unless provided explicitly
+	integration := cfg.Integration
+	pipeline := cfg.Pipeline
+	budget := cfg.Budget
+	shell := cfg.Shell
+	incremental := cfg.Incremental
+	if integration == nil || pipeline == nil || budget == nil || shell == "" || incremental == nil {
+		fileConfig, err := LoadConfigFile(cfg.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+		if integration == nil {
+			integration = fileConfig.Integration
+		}
+		if pipeline == nil {
+			pipeline = fileConfig.Pipeline
+		}
+		if budget == nil {
+			budget = fileConfig.Budget
+		}
+		if shell == "" {
+			shell = fileConfig.Shell
+		}
+		if incremental == nil {
+			incremental = fileConfig.Incremental
+		}
+	}
+	if err := validateShell(shell); err != nil {
+		return nil, err
+	}
+	if cfg.Integration != nil {
+		if err := integration.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid integration config: %w", err)
+		}
+	}
+	if cfg.Budget != nil {
+		if err := budget.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid gate budget: %w", err)
+		}
+	}
+	if cfg.Incremental != nil {
+		if err := incremental.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid incremental config: %w", err)
+		}
+	}
+
+	sortedPipeline, err := sortPipeline(pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gate pipeline: %w", err)
+	}
+	for _, stage := range sortedPipeline {
+		if GateType(stage.Name) == GateIntegration && stage.Command == "" && (integration == nil || !integration.Enabled) {
+			return nil, fmt.Errorf("invalid gate pipeline: integration stage requires an enabled integration config")
+		}
+	}
+
+	overrides := make(map[GateType]*GateOverride, len(cfg.Overrides))
+	for _, override := range cfg.Overrides {
+		overrides[override.Gate] = override
+	}
+
+	runner := &Runner{
 		store:            cfg.Store,
 		supervisor:       cfg.Supervisor,
 		workingDir:       cfg.WorkingDir,
 		provider:         cfg.Provider,         // Can be nil (defaults to built-in implementation)
 		progressCallback: cfg.ProgressCallback, // Can be nil (no progress reporting)
-	}, nil
+		integration:      integration.forOS(runtime.GOOS), // Can be nil (no integration stage)
+		overrides:        overrides,
+		pipeline:         sortedPipeline,
+		budget:           budget, // Can be nil (no budget)
+		shell:            shell,  // Can be empty (OS default)
+		incremental:      incremental,        // Can be nil (full runs)
+		fullRuns:         cfg.FullRunTracker, // Can be nil (every run is full)
+		paths:            cfg.Paths,          // Can be nil (whole module)
+	}
+
+	// Required gates must exist, or the budget would silently skip them
+	if budget != nil {
+		known := make(map[GateType]bool)
+		for _, stage := range runner.stages() {
+			known[stage.gateType] = true
+		}
+		for _, name := range budget.requiredGates() {
+			if !known[GateType(name)] {
+				return nil, fmt.Errorf("invalid gate budget: required gate %q is not in the pipeline", name)
+			}
+		}
+	}
+
+	return runner, nil
 }
 
 // GetProvider returns the configured gate provider (for testing)
@@ -104,16 +212,18 @@ func (r *Runner) RunAll(ctx context.Context) ([]*Result, bool) {
 	var results []*Result
 	allPassed := true
 
-	// Run gates in order: build -> test -> lint
-	// BUILD runs first to catch compilation errors before running tests
-	// This prevents confusing test failures on code that doesn't even compile
-	gates := []struct {
-		gateType GateType
-		runFunc  func(context.Context) *Result
-	}{
-		{GateBuild, r.runBuildGate},
-		{GateTest, r.runTestGate},
-		{GateLint, r.runLintGate},
+	// Incremental mode: scope build/test to the packages affected by the changes
+	r.scope = r.resolveScope(ctx)
+
+	// Gates run one at a time in dependency order: they share the working
+	// directory, and later stages may use artifacts of earlier ones
+	gates := r.stages()
+
+	// With a time budget, required gates run first and always complete
+	var required map[GateType]bool
+	if r.budget != nil {
+		required = requiredStages(gates, r.budget.requiredGates())
+		gates = prioritizeStages(gates, required)
 	}
 
 	// vc-267: Track start time for progress reporting
@@ -157,6 +267,10 @@ func (r *Runner) RunAll(ctx context.Context) ([]*Result, bool) {
 		}()
 	}
 
+	// Results by gate, for dependency checks
+	completed := make(map[GateType]*Result, len(gates))
+	blocked := make(map[GateType]bool) // Skipped because a dependency didn't pass
+
 	for i, gate := range gates {
 		// Check if context is already canceled before starting gate (vc-119)
 		if ctx.Err() != nil {
@@ -168,6 +282,64 @@ func (r *Runner) RunAll(ctx context.Context) ([]*Result, bool) {
 			return results, false
 		}
 
+		// Gates whose dependencies didn't pass can't run (their inputs are missing).
+		// The failed dependency already fails the pipeline, so don't report twice.
+		if need, unmet := unmetDependency(gate, completed, blocked); unmet {
+			reason := fmt.Sprintf("dependency %s did not pass", need)
+			fmt.Printf("Skipping %s gate (%s)\n", gate.gateType, reason)
+			blocked[gate.gateType] = true
+			result := &Result{
+				Gate:       gate.gateType,
+				Passed:     true,
+				Skipped:    true,
+				SkipReason: reason,
+				Output:     "Gate skipped: " + reason,
+			}
+			results = append(results, result)
+			completed[gate.gateType] = result
+			if r.progressCallback != nil {
+				gatesCompletedCount.Store(int32(i + 1))
+			}
+			continue
+		}
+
+		// Once the budget is used up, optional gates are skipped rather than failed
+		if r.budget != nil && !required[gate.gateType] && time.Since(startTime) >= r.budget.total() {
+			fmt.Printf("Skipping %s gate (%s)\n", gate.gateType, BudgetSkipReason)
+			blocked[gate.gateType] = true // Dependents can't use its output
+			result := &Result{
+				Gate:       gate.gateType,
+				Passed:     true,
+				Skipped:    true,
+				SkipReason: BudgetSkipReason,
+				Output:     fmt.Sprintf("Gate skipped: time budget of %s used up after %v", r.budget.Total, time.Since(startTime).Round(time.Second)),
+			}
+			results = append(results, result)
+			completed[gate.gateType] = result
+			if r.progressCallback != nil {
+				gatesCompletedCount.Store(int32(i + 1))
+			}
+			continue
+		}
+
+		// Approved overrides skip the gate without failing it
+		if override, ok := r.overrides[gate.gateType]; ok {
+			fmt.Printf("Skipping %s gate (%s)\n", gate.gateType, override.SkipReason())
+			result := &Result{
+				Gate:       gate.gateType,
+				Passed:     true,
+				Skipped:    true,
+				SkipReason: override.SkipReason(),
+				Output:     fmt.Sprintf("Gate skipped: %s (requested by %s)", override.SkipReason(), override.RequestedBy),
+			}
+			results = append(results, result)
+			completed[gate.gateType] = result
+			if r.progressCallback != nil {
+				gatesCompletedCount.Store(int32(i + 1))
+			}
+			continue
+		}
+
 		fmt.Printf("Running %s gate...\n", gate.gateType)
 
 		// vc-267: Report progress when starting each gate
@@ -179,6 +351,7 @@ func (r *Runner) RunAll(ctx context.Context) ([]*Result, bool) {
 
 		result := gate.runFunc(ctx)
 		results = append(results, result)
+		completed[gate.gateType] = result
 
 		// vc-267: Update completed count atomically
 		if r.progressCallback != nil {
@@ -199,17 +372,25 @@ func (r *Runner) RunAll(ctx context.Context) ([]*Result, bool) {
 		close(progressDone)
 	}
 
+	// A passing full run resets the incremental safety net
+	if r.fullRuns != nil && r.scope == nil && allPassed {
+		r.fullRuns.MarkPassed(time.Now())
+	}
+
 	return results, allPassed
 }
 
 // runTestGate executes go test
 func (r *Runner) runTestGate(ctx context.Context) *Result {
+	if skipped := r.scopeSkip(GateTest); skipped != nil {
+		return skipped
+	}
 	result := &Result{Gate: GateTest}
 
 	// vc-130: Add explicit timeout and skip long-running integration tests
 	// Use -short flag to skip integration tests (tagged with `if testing.Short()`)
 	// Use -timeout to enforce hard deadline (2 minutes per test)
-	cmd := exec.CommandContext(ctx, "go", "test", "-short", "-timeout=2m", "./...")
+	cmd := exec.CommandContext(ctx, "go", append([]string{"test", "-short", "-timeout=2m"}, r.packageArgs()...)...)
 	cmd.Dir = r.workingDir
 
 	// vc-235: Isolate test database to prevent pollution of production databases
@@ -247,15 +428,16 @@ func (r *Runner) runTestGate(ctx context.Context) *Result {
 func (r *Runner) runLintGate(ctx context.Context) *Result {
 	result := &Result{Gate: GateLint}
 
-	// Check if golangci-lint is available
-	if _, err := exec.LookPath("golangci-lint"); err != nil {
+	// Check if golangci-lint is available (PATH, GOBIN or GOPATH/bin)
+	lintPath, err := ResolveTool("golangci-lint")
+	if err != nil {
 		result.Passed = false
 		result.Error = fmt.Errorf("golangci-lint not found in PATH")
 		result.Output = "golangci-lint is not installed or not in PATH"
 		return result
 	}
 
-	cmd := exec.CommandContext(ctx, "golangci-lint", "run", "./...")
+	cmd := exec.CommandContext(ctx, lintPath, "run", "./...")
 	cmd.Dir = r.workingDir
 
 	output, err := cmd.CombinedOutput()
@@ -283,9 +465,12 @@ func (r *Runner) runLintGate(ctx context.Context) *Result {
 
 // runBuildGate executes go build
 func (r *Runner) runBuildGate(ctx context.Context) *Result {
+	if skipped := r.scopeSkip(GateBuild); skipped != nil {
+		return skipped
+	}
 	result := &Result{Gate: GateBuild}
 
-	cmd := exec.CommandContext(ctx, "go", "build", "./...")
+	cmd := exec.CommandContext(ctx, "go", append([]string{"build"}, r.packageArgs()...)...)
 	cmd.Dir = r.workingDir
 
 	output, err := cmd.CombinedOutput()
@@ -361,11 +546,24 @@ func (r *Runner) CreateBlockingIssue(ctx context.Context, originalIssue *types.I
 
 // HandleGateResults processes gate results using AI-driven recovery strategies (ZFC)
 // Falls back to hardcoded behavior if supervisor is unavailable
+//
+// It is safe to re-run for the same outcome, e.g. after a crash: each comment,
+// issue, close and the AI's strategy itself are keyed on the issue and which
+// gates failed (see internal/idempotency), so a re-run carries out the same
+// decision and skips what was already done.
 func (r *Runner) HandleGateResults(ctx context.Context, originalIssue *types.Issue, results []*Result, allPassed bool) error {
+	effects := idempotency.NewScope(r.store, "gates", originalIssue.ID, gateOutcome(results)...)
+
 	// Log all gate results as events
 	for _, result := range results {
-		eventComment := r.formatGateResult(result)
-		if err := r.store.AddComment(ctx, originalIssue.ID, "quality-gates", eventComment); err != nil {
+		err := effects.Once(ctx, "comment:"+string(result.Gate), func() error {
+			eventComment := r.formatGateResult(result)
+			if id := r.attachGateOutput(ctx, originalIssue, result); id != 0 {
+				eventComment += fmt.Sprintf("\nFull output: attachment #%d (`vc attachment get %d`)\n", id, id)
+			}
+			return r.store.AddComment(ctx, originalIssue.ID, "quality-gates", eventComment)
+		})
+		if err != nil {
 			// Don't fail on logging errors
 			fmt.Printf("warning: failed to log gate result: %v\n", err)
 		}
@@ -374,7 +572,9 @@ func (r *Runner) HandleGateResults(ctx context.Context, originalIssue *types.Iss
 	// If all gates passed, nothing else to do
 	if allPassed {
 		successComment := "All quality gates passed:\n- ✓ go build\n- ✓ go test\n- ✓ golangci-lint"
-		if err := r.store.AddComment(ctx, originalIssue.ID, "quality-gates", successComment); err != nil {
+		if err := effects.Once(ctx, "comment:passed", func() error {
+			return r.store.AddComment(ctx, originalIssue.ID, "quality-gates", successComment)
+		}); err != nil {
 			fmt.Printf("warning: failed to add success comment: %v\n", err)
 		}
 		return nil
@@ -382,49 +582,58 @@ func (r *Runner) HandleGateResults(ctx context.Context, originalIssue *types.Iss
 
 	// ZFC: Use AI to determine recovery strategy
 	if r.supervisor != nil {
-		return r.handleGateResultsWithAI(ctx, originalIssue, results)
+		return r.handleGateResultsWithAI(ctx, originalIssue, results, effects)
 	}
 
 	// Fallback: Use hardcoded behavior (backward compatibility)
 	fmt.Printf("warning: No AI supervisor configured for quality gates on %s, using fallback logic\n", originalIssue.ID)
-	return r.handleGateResultsFallback(ctx, originalIssue, results)
+	return r.handleGateResultsFallback(ctx, originalIssue, results, effects)
 }
 
-// handleGateResultsWithAI uses AI supervisor to determine recovery strategy (ZFC)
-func (r *Runner) handleGateResultsWithAI(ctx context.Context, originalIssue *types.Issue, results []*Result) error {
-	// Convert gate results to AI format
-	var gateFailures []ai.GateFailure
+// gateOutcome describes which gates passed and failed, the inputs that key
+// HandleGateResults' effects. Output is left out: it varies between runs of
+// the same failure (timings, temp paths).
+func gateOutcome(results []*Result) []string {
+	outcome := make([]string, 0, len(results))
 	for _, result := range results {
-		if !result.Passed {
-			// Truncate output for AI consumption
-			output := result.Output
-			if len(output) > 1000 {
-				output = output[:1000] + "\n... (truncated)"
-			}
-
-			errMsg := ""
-			if result.Error != nil {
-				errMsg = result.Error.Error()
-			}
-
-			gateFailures = append(gateFailures, ai.GateFailure{
-				Gate:   string(result.Gate),
-				Output: output,
-				Error:  errMsg,
-			})
+		state := "passed"
+		if result.Skipped {
+			state = "skipped"
+		} else if !result.Passed {
+			state = "failed"
 		}
+		outcome = append(outcome, string(result.Gate)+"="+state)
 	}
+	return outcome
+}
 
-	// Ask AI for recovery strategy with timeout protection (vc-225)
-	// Prevent hanging on AI API issues - fallback after 2 minutes
-	aiCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
-	defer cancel()
-
-	strategy, err := r.supervisor.GenerateRecoveryStrategy(aiCtx, originalIssue, gateFailures)
+// handleGateResultsWithAI uses AI supervisor to determine recovery strategy (ZFC).
+// The strategy is recorded, so a re-run carries out the same decision rather
+// than asking again and possibly acting on a different one.
+func (r *Runner) handleGateResultsWithAI(ctx context.Context, originalIssue *types.Issue, results []*Result, effects *idempotency.Scope) error {
+	recorded, applied, err := effects.Do(ctx, "strategy", func() (string, error) {
+		strategy, err := r.generateRecoveryStrategy(ctx, originalIssue, results)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(strategy)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode recovery strategy: %w", err)
+		}
+		return string(data), nil
+	})
 	if err != nil {
 		// If AI fails, fall back to hardcoded behavior
 		fmt.Printf("warning: AI recovery strategy failed for %s: %v (falling back)\n", originalIssue.ID, err)
-		return r.handleGateResultsFallback(ctx, originalIssue, results)
+		return r.handleGateResultsFallback(ctx, originalIssue, results, effects)
+	}
+	var strategy ai.RecoveryStrategy
+	if err := json.Unmarshal([]byte(recorded), &strategy); err != nil {
+		fmt.Printf("warning: recorded recovery strategy for %s is unreadable: %v (falling back)\n", originalIssue.ID, err)
+		return r.handleGateResultsFallback(ctx, originalIssue, results, effects)
+	}
+	if !applied {
+		fmt.Printf("Resuming recorded AI recovery strategy (%s) for %s\n", strategy.Action, originalIssue.ID)
 	}
 
 	// Log the AI's reasoning
@@ -433,40 +642,79 @@ func (r *Runner) handleGateResultsWithAI(ctx context.Context, originalIssue *typ
 		"Confidence: %.2f\n\n"+
 		"Reasoning: %s\n",
 		strategy.Action, strategy.Confidence, strategy.Reasoning)
-	if err := r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", reasoningComment); err != nil {
+	if err := effects.Once(ctx, "comment:strategy", func() error {
+		return r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", reasoningComment)
+	}); err != nil {
 		fmt.Printf("warning: failed to add AI reasoning comment: %v\n", err)
 	}
 
 	// Execute the recommended action
 	switch strategy.Action {
 	case "fix_in_place":
-		return r.executeFixInPlace(ctx, originalIssue, strategy)
+		return r.executeFixInPlace(ctx, originalIssue, &strategy, effects)
 
 	case "acceptable_failure":
-		return r.executeAcceptableFailure(ctx, originalIssue, strategy)
+		return r.executeAcceptableFailure(ctx, originalIssue, &strategy, effects)
 
 	case "split_work":
-		return r.executeSplitWork(ctx, originalIssue, strategy)
+		return r.executeSplitWork(ctx, originalIssue, &strategy, effects)
 
 	case "escalate":
-		return r.executeEscalate(ctx, originalIssue, strategy)
+		return r.executeEscalate(ctx, originalIssue, &strategy, effects)
 
 	case "retry":
-		return r.executeRetry(ctx, originalIssue, strategy)
+		return r.executeRetry(ctx, originalIssue, &strategy, effects)
 
 	default:
 		fmt.Printf("warning: unknown recovery action '%s' for %s, falling back\n", strategy.Action, originalIssue.ID)
-		return r.handleGateResultsFallback(ctx, originalIssue, results)
+		return r.handleGateResultsFallback(ctx, originalIssue, results, effects)
+	}
+}
+
+// generateRecoveryStrategy asks the AI supervisor how to recover from the failed gates
+func (r *Runner) generateRecoveryStrategy(ctx context.Context, originalIssue *types.Issue, results []*Result) (*ai.RecoveryStrategy, error) {
+	// Convert gate results to AI format
+	var gateFailures []ai.GateFailure
+	for _, result := range results {
+		if !result.Passed {
+			// Compress and truncate output for AI consumption
+			output := CompressOutput(result.Output)
+			if len(output) > 1000 {
+				output = output[:1000] + "\n... (truncated)"
+			}
+
+			errMsg := ""
+			if result.Error != nil {
+				errMsg = result.Error.Error()
+			}
+
+			gateFailures = append(gateFailures, ai.GateFailure{
+				Gate:   string(result.Gate),
+				Output: output,
+				Error:  errMsg,
+				// Large lint failures are triaged so recovery focuses on must-fix findings
+				Triage: r.triageLintFailure(ctx, originalIssue, result),
+			})
+		}
 	}
+
+	// Ask AI for recovery strategy with timeout protection (vc-225)
+	// Prevent hanging on AI API issues - fallback after 2 minutes
+	aiCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	return r.supervisor.GenerateRecoveryStrategy(aiCtx, originalIssue, gateFailures)
 }
 
 // handleGateResultsFallback uses hardcoded logic (old behavior)
-func (r *Runner) handleGateResultsFallback(ctx context.Context, originalIssue *types.Issue, results []*Result) error {
+func (r *Runner) handleGateResultsFallback(ctx context.Context, originalIssue *types.Issue, results []*Result, effects *idempotency.Scope) error {
 	// Create blocking issues for each failed gate
 	var createdIssues []string
 	for _, result := range results {
 		if !result.Passed {
-			issueID, err := r.CreateBlockingIssue(ctx, originalIssue, result)
+			issueID, _, err := effects.Do(ctx, "blocker:"+string(result.Gate), func() (string, error) {
+				return r.CreateBlockingIssue(ctx, originalIssue, result)
+			})
 			if err != nil {
 				return fmt.Errorf("failed to create blocking issue for %s gate: %w", result.Gate, err)
 			}
@@ -492,7 +740,9 @@ func (r *Runner) handleGateResultsFallback(ctx context.Context, originalIssue *t
 	// Add summary comment
 	summaryComment := fmt.Sprintf("Quality gates failed. Created %d blocking issue(s): %s",
 		len(createdIssues), strings.Join(createdIssues, ", "))
-	if err := r.store.AddComment(ctx, originalIssue.ID, "quality-gates", summaryComment); err != nil {
+	if err := effects.Once(ctx, "comment:summary", func() error {
+		return r.store.AddComment(ctx, originalIssue.ID, "quality-gates", summaryComment)
+	}); err != nil {
 		fmt.Printf("warning: failed to add summary comment: %v\n", err)
 	}
 
@@ -500,7 +750,7 @@ func (r *Runner) handleGateResultsFallback(ctx context.Context, originalIssue *t
 }
 
 // executeFixInPlace creates blocking issues and marks original as blocked
-func (r *Runner) executeFixInPlace(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy) error {
+func (r *Runner) executeFixInPlace(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy, effects *idempotency.Scope) error {
 	// vc-163: Use CreateDiscoveredIssues helper for consistency
 	// This ensures proper discovery type labels, priority calculation, and discovered-from dependencies
 	var createdIssues []string
@@ -513,13 +763,16 @@ func (r *Runner) executeFixInPlace(ctx context.Context, originalIssue *types.Iss
 
 		// Add blocking dependencies for fix_in_place strategy
 		// (CreateDiscoveredIssues already added discovered-from deps)
+		// (keyed, as a re-run gets back issues it already made blockers)
 		for _, id := range discoveredIDs {
 			dep := &types.Dependency{
 				IssueID:     originalIssue.ID,
 				DependsOnID: id,
 				Type:        types.DepBlocks,
 			}
-			if err := r.store.AddDependency(ctx, dep, "ai-supervisor"); err != nil {
+			if err := effects.Once(ctx, "blocks:"+id, func() error {
+				return r.store.AddDependency(ctx, dep, "ai-supervisor")
+			}); err != nil {
 				return fmt.Errorf("failed to create blocking dependency: %w", err)
 			}
 		}
@@ -572,7 +825,9 @@ func (r *Runner) executeFixInPlace(ctx context.Context, originalIssue *types.Iss
 
 	// Add AI's comment if provided
 	if strategy.AddComment != "" {
-		if err := r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", strategy.AddComment); err != nil {
+		if err := effects.Once(ctx, "comment:ai", func() error {
+			return r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", strategy.AddComment)
+		}); err != nil {
 			fmt.Printf("warning: failed to add AI comment: %v\n", err)
 		}
 	}
@@ -583,7 +838,7 @@ func (r *Runner) executeFixInPlace(ctx context.Context, originalIssue *types.Iss
 
 // executeAcceptableFailure closes the issue despite gate failures
 // vc-155: Creates blocker issues for pre-existing problems discovered during gate failures
-func (r *Runner) executeAcceptableFailure(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy) error {
+func (r *Runner) executeAcceptableFailure(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy, effects *idempotency.Scope) error {
 	// vc-155: Create blocker issues for pre-existing problems
 	// When AI identifies gate failures as pre-existing (not caused by current work),
 	// it creates blocker issues to ensure the pre-existing work gets fixed
@@ -610,14 +865,18 @@ func (r *Runner) executeAcceptableFailure(ctx context.Context, originalIssue *ty
 	} else {
 		warningComment = fmt.Sprintf("⚠️ **Quality gates failed but closing anyway (AI decision)**\n\n%s", strategy.AddComment)
 	}
-	if err := r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", warningComment); err != nil {
+	if err := effects.Once(ctx, "comment:ai", func() error {
+		return r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", warningComment)
+	}); err != nil {
 		fmt.Printf("warning: failed to add acceptable failure comment: %v\n", err)
 	}
 
 	// Close if AI recommends it (and if not requiring approval)
 	if strategy.CloseOriginal && !strategy.RequiresApproval {
 		reason := fmt.Sprintf("AI assessed gate failures as acceptable (confidence: %.2f)", strategy.Confidence)
-		if err := r.store.CloseIssue(ctx, originalIssue.ID, reason, "ai-supervisor"); err != nil {
+		if err := effects.Once(ctx, "close", func() error {
+			return r.store.CloseIssue(ctx, originalIssue.ID, reason, "ai-supervisor")
+		}); err != nil {
 			return fmt.Errorf("failed to close issue: %w", err)
 		}
 		if len(createdBlockers) > 0 {
@@ -637,7 +896,7 @@ func (r *Runner) executeAcceptableFailure(ctx context.Context, originalIssue *ty
 }
 
 // executeSplitWork creates new issues and closes original
-func (r *Runner) executeSplitWork(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy) error {
+func (r *Runner) executeSplitWork(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy, effects *idempotency.Scope) error {
 	// vc-163: Use CreateDiscoveredIssues helper for consistency
 	// This ensures proper discovery type labels, priority calculation, and discovered-from dependencies
 	var createdIssues []string
@@ -681,7 +940,9 @@ func (r *Runner) executeSplitWork(ctx context.Context, originalIssue *types.Issu
 
 	// Add comment explaining split
 	if strategy.AddComment != "" {
-		if err := r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", strategy.AddComment); err != nil {
+		if err := effects.Once(ctx, "comment:ai", func() error {
+			return r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", strategy.AddComment)
+		}); err != nil {
 			fmt.Printf("warning: failed to add split work comment: %v\n", err)
 		}
 	}
@@ -689,7 +950,9 @@ func (r *Runner) executeSplitWork(ctx context.Context, originalIssue *types.Issu
 	// Close original if AI recommends it
 	if strategy.CloseOriginal {
 		reason := fmt.Sprintf("Work split into %d new issues: %s", len(createdIssues), strings.Join(createdIssues, ", "))
-		if err := r.store.CloseIssue(ctx, originalIssue.ID, reason, "ai-supervisor"); err != nil {
+		if err := effects.Once(ctx, "close", func() error {
+			return r.store.CloseIssue(ctx, originalIssue.ID, reason, "ai-supervisor")
+		}); err != nil {
 			return fmt.Errorf("failed to close original issue: %w", err)
 		}
 	}
@@ -699,10 +962,12 @@ func (r *Runner) executeSplitWork(ctx context.Context, originalIssue *types.Issu
 }
 
 // executeEscalate flags issue for human review
-func (r *Runner) executeEscalate(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy) error {
+func (r *Runner) executeEscalate(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy, effects *idempotency.Scope) error {
 	// Add escalation comment
 	escalationComment := fmt.Sprintf("🚨 **Escalated for human review**\n\n%s", strategy.AddComment)
-	if err := r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", escalationComment); err != nil {
+	if err := effects.Once(ctx, "comment:ai", func() error {
+		return r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", escalationComment)
+	}); err != nil {
 		fmt.Printf("warning: failed to add escalation comment: %v\n", err)
 	}
 
@@ -734,10 +999,12 @@ func (r *Runner) executeEscalate(ctx context.Context, originalIssue *types.Issue
 // executeRetry suggests retry without creating blocking issues
 //
 //nolint:unparam // error return reserved for future error conditions
-func (r *Runner) executeRetry(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy) error {
+func (r *Runner) executeRetry(ctx context.Context, originalIssue *types.Issue, strategy *ai.RecoveryStrategy, effects *idempotency.Scope) error {
 	// Add retry suggestion comment
 	retryComment := fmt.Sprintf("🔄 **Retry suggested**\n\n%s\n\nThe issue remains open for retry.", strategy.AddComment)
-	if err := r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", retryComment); err != nil {
+	if err := effects.Once(ctx, "comment:ai", func() error {
+		return r.store.AddComment(ctx, originalIssue.ID, "ai-supervisor", retryComment)
+	}); err != nil {
 		fmt.Printf("warning: failed to add retry comment: %v\n", err)
 	}
 
@@ -746,16 +1013,47 @@ func (r *Runner) executeRetry(ctx context.Context, originalIssue *types.Issue, s
 	return nil
 }
 
+// maxInlineGateOutput is how much gate output is quoted in an issue comment.
+// Failed gates with more output than this get the full log attached.
+const maxInlineGateOutput = 500
+
+// attachGateOutput attaches the full output of a failed gate to the issue when
+// it is too long to quote in a comment. Returns the attachment ID, or 0 if
+// nothing was attached.
+func (r *Runner) attachGateOutput(ctx context.Context, issue *types.Issue, result *Result) int64 {
+	if result.Passed || len(result.Output) <= maxInlineGateOutput {
+		return 0
+	}
+	attachment := &types.Attachment{
+		IssueID:   issue.ID,
+		Name:      fmt.Sprintf("%s gate output", result.Gate),
+		Kind:      types.AttachmentGateLog,
+		CreatedBy: "quality-gates",
+	}
+	output := []byte(result.Output)
+	if len(output) > types.MaxAttachmentSize {
+		output = output[len(output)-types.MaxAttachmentSize:] // The end of a log is what matters
+	}
+	if err := r.store.AddAttachment(ctx, attachment, output); err != nil {
+		// Don't fail on logging errors
+		fmt.Printf("warning: failed to attach %s gate output: %v\n", result.Gate, err)
+		return 0
+	}
+	return attachment.ID
+}
+
 // formatGateResult formats a gate result for display
 func (r *Runner) formatGateResult(result *Result) string {
 	status := "✓ PASSED"
-	if !result.Passed {
+	if result.Skipped {
+		status = fmt.Sprintf("⊘ SKIPPED (%s)", result.SkipReason)
+	} else if !result.Passed {
 		status = "✗ FAILED"
 	}
 
 	output := result.Output
-	if len(output) > 500 {
-		output = output[:500] + "\n... (truncated, see blocking issue for full output)"
+	if len(output) > maxInlineGateOutput {
+		output = output[:maxInlineGateOutput] + "\n... (truncated, see blocking issue for full output)"
 	}
 
 	comment := fmt.Sprintf("**Quality Gate: %s** - %s\n", result.Gate, status)
@@ -6,23 +6,17 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/steveyegge/vc/internal/ai"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/memory"
 	"github.com/steveyegge/vc/internal/types"
 )
 
 func TestNewRunner(t *testing.T) {
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	// Test successful creation
 	cfg := &Config{
@@ -69,15 +63,7 @@ func TestRunTestGate_Success(t *testing.T) {
 		t.Skip("Skipping recursive test execution in short mode")
 	}
 
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	runner := &Runner{
 		store:      store,
@@ -99,15 +85,7 @@ func TestRunTestGate_Success(t *testing.T) {
 }
 
 func TestRunLintGate(t *testing.T) {
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	runner := &Runner{
 		store:      store,
@@ -129,15 +107,7 @@ func TestRunLintGate(t *testing.T) {
 }
 
 func TestRunBuildGate_Success(t *testing.T) {
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	runner := &Runner{
 		store:      store,
@@ -163,15 +133,7 @@ func TestRunAll(t *testing.T) {
 		t.Skip("Skipping recursive test execution in short mode")
 	}
 
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	runner := &Runner{
 		store:      store,
@@ -199,15 +161,7 @@ func TestRunAll(t *testing.T) {
 }
 
 func TestCreateBlockingIssue(t *testing.T) {
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	ctx := context.Background()
 
@@ -301,15 +255,7 @@ func TestCreateBlockingIssue(t *testing.T) {
 }
 
 func TestHandleGateResults_AllPassed(t *testing.T) {
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	ctx := context.Background()
 
@@ -367,15 +313,7 @@ func TestHandleGateResults_AllPassed(t *testing.T) {
 }
 
 func TestHandleGateResults_SomeFailed(t *testing.T) {
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	ctx := context.Background()
 
@@ -515,15 +453,7 @@ func TestHandleGateResults_WithAI(t *testing.T) {
 		t.Skip("Skipping AI recovery strategy test: ANTHROPIC_API_KEY not set")
 	}
 
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	// Create AI supervisor
 	supervisor, err := ai.NewSupervisor(&ai.Config{
@@ -601,15 +531,7 @@ func TestHandleGateResults_WithAI_CriticalFailures(t *testing.T) {
 		t.Skip("Skipping AI recovery strategy test: ANTHROPIC_API_KEY not set")
 	}
 
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	// Create AI supervisor
 	supervisor, err := ai.NewSupervisor(&ai.Config{
@@ -680,15 +602,7 @@ func TestHandleGateResults_WithAI_CriticalFailures(t *testing.T) {
 func TestHandleGateResults_NoAI_Fallback(t *testing.T) {
 	// Test that fallback logic works when no supervisor is configured
 
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	ctx := context.Background()
 
@@ -744,18 +658,60 @@ func TestHandleGateResults_NoAI_Fallback(t *testing.T) {
 	}
 }
 
-// TestRunTestGate_DatabaseIsolation verifies that test gate sets environment variables
-// to prevent test database pollution (vc-235)
-func TestRunTestGate_DatabaseIsolation(t *testing.T) {
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
+// TestHandleGateResults_AttachesLongOutput verifies that failed gates with
+// more output than fits in a comment get the full log attached
+func TestHandleGateResults_AttachesLongOutput(t *testing.T) {
+	store := memory.New()
+
+	ctx := context.Background()
+	originalIssue := &types.Issue{
+		ID:                 "vc-test-attach-1",
+		Title:              "Test gate output attachment",
+		Status:             types.StatusInProgress,
+		Priority:           2,
+		IssueType:          types.TypeTask,
+		AcceptanceCriteria: "Gates pass",
+	}
+	if err := store.CreateIssue(ctx, originalIssue, "test"); err != nil {
+		t.Fatalf("Failed to create original issue: %v", err)
+	}
+
+	runner := &Runner{store: store, workingDir: "."}
+
+	longOutput := strings.Repeat("--- FAIL: TestSomething\n", 100)
+	results := []*Result{
+		{Gate: GateTest, Passed: false, Output: longOutput, Error: os.ErrInvalid},
+		{Gate: GateBuild, Passed: false, Output: "short failure", Error: os.ErrInvalid},
+		{Gate: GateLint, Passed: true, Output: strings.Repeat("ok\n", 500)},
+	}
+	if err := runner.HandleGateResults(ctx, originalIssue, results, false); err != nil {
+		t.Fatalf("HandleGateResults failed: %v", err)
+	}
+
+	attachments, err := store.ListAttachments(ctx, originalIssue.ID)
+	if err != nil {
+		t.Fatalf("ListAttachments failed: %v", err)
+	}
+	if len(attachments) != 1 {
+		t.Fatalf("Expected 1 attachment (long failed output only), got %d", len(attachments))
+	}
+	if attachments[0].Kind != types.AttachmentGateLog || attachments[0].Size != int64(len(longOutput)) {
+		t.Errorf("Unexpected attachment: %+v", attachments[0])
+	}
 
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
+	_, data, err := store.GetAttachment(ctx, attachments[0].ID)
 	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
+		t.Fatalf("GetAttachment failed: %v", err)
+	}
+	if string(data) != longOutput {
+		t.Error("Attached output does not match the full gate output")
 	}
-	defer func() { _ = store.Close() }()
+}
+
+// TestRunTestGate_DatabaseIsolation verifies that test gate sets environment variables
+// to prevent test database pollution (vc-235)
+func TestRunTestGate_DatabaseIsolation(t *testing.T) {
+	store := memory.New()
 
 	// Create a minimal Go module
 	goMod := filepath.Join(tempDir, "go.mod")
@@ -811,15 +767,7 @@ func TestDatabaseIsolation(t *testing.T) {
 
 // TestProgressCallback tests that progress callbacks are invoked during gate execution (vc-267)
 func TestProgressCallback(t *testing.T) {
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	// Track progress callbacks
 	var progressCalls []struct {
@@ -878,15 +826,7 @@ func TestProgressCallback_BuiltInGates(t *testing.T) {
 		t.Skip("Skipping built-in gates test in short mode")
 	}
 
-	// Create temp db
-	tempDir := t.TempDir()
-	dbPath := filepath.Join(tempDir, "test.db")
-
-	store, err := storage.NewStorage(context.Background(), &storage.Config{Path: dbPath})
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer func() { _ = store.Close() }()
+	store := memory.New()
 
 	// Track progress callbacks
 	var progressCalls []struct {
@@ -962,3 +902,56 @@ func (m *MockGateProvider) RunAll(ctx context.Context) ([]*Result, bool) {
 	}
 	return m.results, allPassed
 }
+
+// TestHandleGateResults_Rerun verifies that handling the same gate outcome
+// again, as after a crash, doesn't repeat comments or blocking issues
+func TestHandleGateResults_Rerun(t *testing.T) {
+	store := memory.New()
+
+	ctx := context.Background()
+	originalIssue := &types.Issue{
+		ID:                 "vc-test-rerun-1",
+		Title:              "Test gate handling rerun",
+		Status:             types.StatusInProgress,
+		Priority:           2,
+		IssueType:          types.TypeTask,
+		AcceptanceCriteria: "Gates pass",
+	}
+	if err := store.CreateIssue(ctx, originalIssue, "test"); err != nil {
+		t.Fatalf("Failed to create original issue: %v", err)
+	}
+
+	runner := &Runner{store: store, workingDir: "."}
+	results := []*Result{
+		{Gate: GateTest, Passed: false, Output: "Test failure", Error: os.ErrInvalid},
+		{Gate: GateBuild, Passed: true, Output: "ok"},
+	}
+	for run := 1; run <= 2; run++ {
+		if err := runner.HandleGateResults(ctx, originalIssue, results, false); err != nil {
+			t.Fatalf("HandleGateResults run %d failed: %v", run, err)
+		}
+	}
+
+	comments, err := store.GetEvents(ctx, originalIssue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	count := 0
+	for _, event := range comments {
+		if event.EventType == types.EventCommented {
+			count++
+		}
+	}
+	// One per gate and the summary
+	if count != 3 {
+		t.Errorf("Expected 3 comments after two runs, got %d", count)
+	}
+
+	deps, err := store.GetDependencies(ctx, originalIssue.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].ID != "vc-test-rerun-1-gate-test" {
+		t.Errorf("Expected one blocking issue, got %v", deps)
+	}
+}
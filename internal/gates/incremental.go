@@ -0,0 +1,370 @@
+package gates
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// defaultFullRunInterval is how often incremental mode falls back to a full run
+const defaultFullRunInterval = 24 * time.Hour
+
+// IncrementalConfig scopes the build and test gates to the packages affected
+// by the current changes, for large monorepos.
+//
+// Changed files are mapped to their Go packages, and every package that
+// depends on them (including through test imports) is built and tested.
+// Everything else is skipped. As a safety net, a full run still happens
+// periodically, and keeps happening until one passes.
+//
+// Example .vc/gates.yaml:
+//
+//	incremental:
+//	  enabled: true
+//	  full_run_interval: 24h
+type IncrementalConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	FullRunInterval string `yaml:"full_run_interval"` // Duration string (default: 24h)
+}
+
+// Validate checks the incremental config for invalid settings
+func (c *IncrementalConfig) Validate() error {
+	if c.FullRunInterval == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(c.FullRunInterval)
+	if err != nil {
+		return fmt.Errorf("invalid full_run_interval: %w", err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("full_run_interval must be positive (got %v)", d)
+	}
+	return nil
+}
+
+// fullRunInterval returns the parsed full run interval (Validate has already checked it)
+func (c *IncrementalConfig) fullRunInterval() time.Duration {
+	if c.FullRunInterval == "" {
+		return defaultFullRunInterval
+	}
+	d, err := time.ParseDuration(c.FullRunInterval)
+	if err != nil || d <= 0 {
+		return defaultFullRunInterval
+	}
+	return d
+}
+
+// FullRunTracker remembers when the gates last passed a full (non-incremental)
+// run. It is shared across gate runs by the executor; without one, every run
+// is a full run.
+type FullRunTracker struct {
+	mu       sync.Mutex
+	lastPass time.Time
+}
+
+// NewFullRunTracker creates a tracker with no full run recorded yet,
+// so the first run is always a full run
+func NewFullRunTracker() *FullRunTracker {
+	return &FullRunTracker{}
+}
+
+// Due reports whether a full run is due
+func (t *FullRunTracker) Due(interval time.Duration, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastPass.IsZero() || now.Sub(t.lastPass) >= interval
+}
+
+// MarkPassed records a passing full run
+func (t *FullRunTracker) MarkPassed(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastPass = now
+}
+
+// fullRunFiles are files whose change can affect every package
+var fullRunFiles = map[string]bool{
+	"go.mod":  true,
+	"go.sum":  true,
+	"go.work": true,
+}
+
+// goPackage is the subset of `go list` output used to find affected packages
+type goPackage struct {
+	ImportPath string
+	Dir        string
+	Deps       []string // Transitive dependencies
+	TestDeps   []string // Direct imports of the package's tests
+}
+
+// listPackages runs `go list` for all packages in the module
+func (r *Runner) listPackages(ctx context.Context) ([]goPackage, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-e",
+		"-f", `{{.ImportPath}}|{{.Dir}}|{{join .Deps ","}}|{{join .TestImports ","}},{{join .XTestImports ","}}`,
+		"./...")
+	cmd.Dir = r.workingDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed: %w", err)
+	}
+
+	var pkgs []goPackage
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "|", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		pkgs = append(pkgs, goPackage{
+			ImportPath: fields[0],
+			Dir:        fields[1],
+			Deps:       splitList(fields[2]),
+			TestDeps:   splitList(fields[3]),
+		})
+	}
+	return pkgs, scanner.Err()
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// affectedPackages maps changed files (relative to workingDir) to the packages
+// that need to be rebuilt and retested. full is true if the changes can affect
+// every package (e.g. go.mod changed).
+func affectedPackages(workingDir string, changedFiles []string, pkgs []goPackage) (affected []string, full bool) {
+	absWorkingDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		absWorkingDir = workingDir
+	}
+	// go list reports resolved directories (e.g. /private/tmp on macOS)
+	if resolved, err := filepath.EvalSymlinks(absWorkingDir); err == nil {
+		absWorkingDir = resolved
+	}
+
+	byDir := make(map[string]string, len(pkgs))
+	for _, pkg := range pkgs {
+		byDir[pkg.Dir] = pkg.ImportPath
+	}
+
+	// Packages containing changed files. Files below a package that aren't
+	// part of it (testdata, embedded assets) count as changes to that package.
+	changed := make(map[string]bool)
+	for _, file := range changedFiles {
+		if fullRunFiles[filepath.Base(file)] {
+			return nil, true
+		}
+		importPath, ok := owningPackage(absWorkingDir, file, byDir)
+		if !ok {
+			// A Go file outside any known package (new package, build-tag
+			// mismatch, ...) can't be scoped safely
+			if strings.HasSuffix(file, ".go") {
+				return nil, true
+			}
+			continue
+		}
+		changed[importPath] = true
+	}
+
+	// Packages that depend on a changed package
+	dependents := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if changed[pkg.ImportPath] || anyIn(pkg.Deps, changed) {
+			dependents[pkg.ImportPath] = true
+		}
+	}
+
+	// Packages whose tests import an affected package
+	result := make(map[string]bool, len(dependents))
+	for _, pkg := range pkgs {
+		if dependents[pkg.ImportPath] || anyIn(pkg.TestDeps, dependents) {
+			result[pkg.ImportPath] = true
+		}
+	}
+
+	for importPath := range result {
+		affected = append(affected, importPath)
+	}
+	sort.Strings(affected)
+	return affected, false
+}
+
+// owningPackage finds the package whose directory contains a file, walking up
+// from the file's directory but not above the working directory
+func owningPackage(absWorkingDir, file string, byDir map[string]string) (string, bool) {
+	dir := filepath.Dir(filepath.Join(absWorkingDir, file))
+	for {
+		if importPath, ok := byDir[dir]; ok {
+			return importPath, true
+		}
+		if dir == absWorkingDir {
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func anyIn(items []string, set map[string]bool) bool {
+	for _, item := range items {
+		if set[item] {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveScope decides which packages the build and test gates cover: those
+// affected by the changes in incremental mode, limited to the issue's path
+// scope if it has one. A nil scope means a full run.
+func (r *Runner) resolveScope(ctx context.Context) []string {
+	scope := r.resolveIncrementalScope(ctx)
+	if len(r.paths) == 0 {
+		return scope
+	}
+
+	pkgs, err := r.listPackages(ctx)
+	if err != nil {
+		fmt.Printf("warning: path scope: %v (not limiting gates to %s)\n", err, r.paths)
+		return scope
+	}
+	inScope := packagesInScope(r.workingDir, r.paths, pkgs)
+	if scope != nil {
+		affected := make(map[string]bool, len(scope))
+		for _, importPath := range scope {
+			affected[importPath] = true
+		}
+		kept := []string{}
+		for _, importPath := range inScope {
+			if affected[importPath] {
+				kept = append(kept, importPath)
+			}
+		}
+		inScope = kept
+	}
+	fmt.Printf("Path scope: %d of %d packages within %s\n", len(inScope), len(pkgs), r.paths)
+	return inScope
+}
+
+// packagesInScope returns the packages whose directories are within the
+// path scope. The result is never nil, so an empty one marks a scoped run.
+func packagesInScope(workingDir string, paths types.PathScope, pkgs []goPackage) []string {
+	absWorkingDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		absWorkingDir = workingDir
+	}
+	if resolved, err := filepath.EvalSymlinks(absWorkingDir); err == nil {
+		absWorkingDir = resolved
+	}
+
+	inScope := []string{}
+	for _, pkg := range pkgs {
+		rel, err := filepath.Rel(absWorkingDir, pkg.Dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		// The root package is within a scope only if the scope covers it
+		// explicitly; a directory pattern covers the packages below it
+		if rel != "." && paths.Contains(rel) {
+			inScope = append(inScope, pkg.ImportPath)
+		}
+	}
+	sort.Strings(inScope)
+	return inScope
+}
+
+// resolveIncrementalScope decides whether this gate run is incremental, and
+// if so which packages are affected. A nil scope means a full run.
+func (r *Runner) resolveIncrementalScope(ctx context.Context) []string {
+	if r.incremental == nil || !r.incremental.Enabled {
+		return nil
+	}
+	if r.fullRuns == nil || r.fullRuns.Due(r.incremental.fullRunInterval(), time.Now()) {
+		fmt.Printf("Incremental gates: full run (safety net)\n")
+		return nil
+	}
+
+	changedFiles, err := r.changedFiles(ctx)
+	if err != nil {
+		fmt.Printf("warning: incremental gates: %v (running full gates)\n", err)
+		return nil
+	}
+	pkgs, err := r.listPackages(ctx)
+	if err != nil {
+		fmt.Printf("warning: incremental gates: %v (running full gates)\n", err)
+		return nil
+	}
+
+	affected, full := affectedPackages(r.workingDir, changedFiles, pkgs)
+	if full {
+		fmt.Printf("Incremental gates: module files changed, running full gates\n")
+		return nil
+	}
+	fmt.Printf("Incremental gates: %d of %d packages affected by %d changed files\n",
+		len(affected), len(pkgs), len(changedFiles))
+	// Non-nil (possibly empty) scope marks an incremental run
+	return append([]string{}, affected...)
+}
+
+// describePackages describes the build/test package scope, for explain mode
+func (r *Runner) describePackages() string {
+	if len(r.paths) > 0 {
+		return fmt.Sprintf("<packages within %s>", r.paths)
+	}
+	if r.incremental == nil || !r.incremental.Enabled {
+		return "./..."
+	}
+	return fmt.Sprintf("<affected packages> (incremental; full run at least every %v)", r.incremental.fullRunInterval())
+}
+
+// packageArgs returns the package arguments for the build and test gates
+func (r *Runner) packageArgs() []string {
+	if r.scope == nil {
+		return []string{"./..."}
+	}
+	return r.scope
+}
+
+// scopeSkip returns a passing, skipped result if an incremental run has no
+// affected packages for a build or test gate
+func (r *Runner) scopeSkip(gate GateType) *Result {
+	if r.scope == nil || len(r.scope) > 0 {
+		return nil
+	}
+	if len(r.paths) > 0 {
+		return &Result{
+			Gate:       gate,
+			Passed:     true,
+			Skipped:    true,
+			SkipReason: "no packages in scope",
+			Output:     fmt.Sprintf("Gate skipped: no Go packages to check are within the issue's path scope (%s)", r.paths),
+		}
+	}
+	return &Result{
+		Gate:       gate,
+		Passed:     true,
+		Skipped:    true,
+		SkipReason: "no affected packages",
+		Output:     "Gate skipped: no Go packages are affected by the changes (incremental mode)",
+	}
+}
@@ -0,0 +1,146 @@
+package gates
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestAffectedPackages(t *testing.T) {
+	dir := t.TempDir()
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatalf("Failed to resolve temp dir: %v", err)
+	}
+	pkgs := []goPackage{
+		{ImportPath: "example/a", Dir: filepath.Join(resolved, "a")},
+		{ImportPath: "example/b", Dir: filepath.Join(resolved, "b"), Deps: []string{"example/a", "fmt"}},
+		{ImportPath: "example/c", Dir: filepath.Join(resolved, "c"), TestDeps: []string{"example/b"}},
+		{ImportPath: "example/d", Dir: filepath.Join(resolved, "d"), Deps: []string{"fmt"}},
+	}
+
+	affected, full := affectedPackages(dir, []string{"a/a.go", "a/testdata/golden.txt", "README.md"}, pkgs)
+	if full {
+		t.Fatal("Expected incremental scope")
+	}
+	if got := strings.Join(affected, ","); got != "example/a,example/b,example/c" {
+		t.Errorf("Expected a, its dependent b and c (whose tests import b), got %s", got)
+	}
+
+	if _, full := affectedPackages(dir, []string{"go.mod"}, pkgs); !full {
+		t.Error("Expected go.mod change to force a full run")
+	}
+	if _, full := affectedPackages(dir, []string{"e/new.go"}, pkgs); !full {
+		t.Error("Expected Go file outside known packages to force a full run")
+	}
+	if affected, full := affectedPackages(dir, []string{"docs/guide.md"}, pkgs); full || len(affected) != 0 {
+		t.Errorf("Expected docs-only change to affect nothing, got %v (full=%v)", affected, full)
+	}
+}
+
+func TestPackagesInScope(t *testing.T) {
+	dir := t.TempDir()
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatalf("Failed to resolve temp dir: %v", err)
+	}
+	pkgs := []goPackage{
+		{ImportPath: "example", Dir: resolved},
+		{ImportPath: "example/services/payments", Dir: filepath.Join(resolved, "services", "payments")},
+		{ImportPath: "example/services/payments/api", Dir: filepath.Join(resolved, "services", "payments", "api")},
+		{ImportPath: "example/services/billing", Dir: filepath.Join(resolved, "services", "billing")},
+	}
+
+	got := packagesInScope(dir, types.PathScope{"services/payments/**"}, pkgs)
+	if strings.Join(got, ",") != "example/services/payments,example/services/payments/api" {
+		t.Errorf("Expected the payments packages only, got %v", got)
+	}
+	if got := packagesInScope(dir, types.PathScope{"docs"}, pkgs); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty, non-nil scope, got %#v", got)
+	}
+}
+
+func TestFullRunTracker(t *testing.T) {
+	tracker := NewFullRunTracker()
+	now := time.Now()
+	if !tracker.Due(time.Hour, now) {
+		t.Error("Expected first run to be a full run")
+	}
+	tracker.MarkPassed(now)
+	if tracker.Due(time.Hour, now.Add(30*time.Minute)) {
+		t.Error("Expected no full run within the interval")
+	}
+	if !tracker.Due(time.Hour, now.Add(time.Hour)) {
+		t.Error("Expected full run after the interval")
+	}
+}
+
+func TestRunAll_IncrementalScopesBuildAndTest(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	writeFile("go.mod", "module example.com/mono\n\ngo 1.21\n")
+	writeFile("a/a.go", "package a\n\nfunc A() int { return 1 }\n")
+	writeFile("b/b.go", "package b\n\nimport \"example.com/mono/a\"\n\nfunc B() int { return a.A() }\n")
+	writeFile("b/b_test.go", "package b\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) {}\n")
+	writeFile("c/c_test.go", "package c\n\nimport \"testing\"\n\nfunc TestC(t *testing.T) {}\n")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-qm", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git not usable: %v (%s)", err, out)
+		}
+	}
+
+	// Change a; b depends on it, c doesn't
+	writeFile("a/a.go", "package a\n\nfunc A() int { return 2 }\n")
+
+	tracker := NewFullRunTracker()
+	tracker.MarkPassed(time.Now())
+	sorted, err := sortPipeline([]PipelineStage{{Name: "build"}, {Name: "test"}})
+	if err != nil {
+		t.Fatalf("Expected valid pipeline, got %v", err)
+	}
+	runner := &Runner{
+		workingDir:  dir,
+		pipeline:    sorted,
+		incremental: &IncrementalConfig{Enabled: true},
+		fullRuns:    tracker,
+	}
+
+	results, allPassed := runner.RunAll(context.Background())
+	if !allPassed {
+		for _, r := range results {
+			t.Logf("%s: err=%v output=%s", r.Gate, r.Error, r.Output)
+		}
+		t.Fatal("Expected gates to pass")
+	}
+	if strings.Join(runner.scope, ",") != "example.com/mono/a,example.com/mono/b" {
+		t.Errorf("Unexpected scope: %v", runner.scope)
+	}
+	testOutput := results[1].Output
+	if !strings.Contains(testOutput, "example.com/mono/b") || strings.Contains(testOutput, "example.com/mono/c") {
+		t.Errorf("Expected only affected packages to be tested, got:\n%s", testOutput)
+	}
+}
@@ -0,0 +1,193 @@
+package gates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigFile_Missing(t *testing.T) {
+	cfg, err := LoadConfigFile(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error for missing config, got %v", err)
+	}
+	if cfg.Integration != nil {
+		t.Error("Expected no integration config when file is missing")
+	}
+}
+
+func TestLoadConfigFile_FromEnv(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "gates-ci.yaml"), []byte("integration:\n  enabled: true\n"), 0644); err != nil {
+		t.Fatalf("Failed to write gates config: %v", err)
+	}
+
+	// A relative path is relative to the project root
+	t.Setenv("VC_GATES_CONFIG", "gates-ci.yaml")
+	cfg, err := LoadConfigFile(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Integration == nil || !cfg.Integration.Enabled {
+		t.Error("Expected the config named by VC_GATES_CONFIG to be loaded")
+	}
+
+	// Unlike .vc/gates.yaml, a file named explicitly must exist
+	t.Setenv("VC_GATES_CONFIG", filepath.Join(dir, "missing.yaml"))
+	if _, err := LoadConfigFile(dir); err == nil {
+		t.Error("Expected error for a missing VC_GATES_CONFIG file")
+	}
+}
+
+func TestLoadConfigFile_Integration(t *testing.T) {
+	dir := t.TempDir()
+	writeGatesConfig(t, dir, `
+integration:
+  enabled: true
+  compose_file: docker-compose.test.yml
+  ready_timeout: 30s
+  env:
+    DATABASE_URL: postgres://localhost/test
+`)
+
+	cfg, err := LoadConfigFile(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Integration == nil || !cfg.Integration.Enabled {
+		t.Fatal("Expected enabled integration config")
+	}
+	if cfg.Integration.ComposeFile != "docker-compose.test.yml" {
+		t.Errorf("Unexpected compose file: %s", cfg.Integration.ComposeFile)
+	}
+	if cfg.Integration.Env["DATABASE_URL"] != "postgres://localhost/test" {
+		t.Errorf("Unexpected env: %v", cfg.Integration.Env)
+	}
+	if cfg.Integration.testCommand() != DefaultIntegrationTestCommand {
+		t.Errorf("Expected default test command, got %s", cfg.Integration.testCommand())
+	}
+}
+
+func TestLoadConfigFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	writeGatesConfig(t, dir, `
+integration:
+  enabled: true
+  compose_file: docker-compose.yml
+  setup:
+    - ./start-db.sh
+`)
+
+	if _, err := LoadConfigFile(dir); err == nil {
+		t.Error("Expected error when both compose_file and setup are set")
+	}
+}
+
+func TestLoadConfigFile_IntegrationCommands(t *testing.T) {
+	dir := t.TempDir()
+	writeGatesConfig(t, dir, `
+integration:
+  enabled: true
+  setup:
+    - ./start-db.sh
+  ready_check: pg_isready
+  commands:
+    windows:
+      setup:
+        - scripts\start-db.cmd
+      test_command: go test -tags=integration ./...
+`)
+
+	cfg, err := LoadConfigFile(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	windows := cfg.Integration.forOS("windows")
+	if strings.Join(windows.Setup, "|") != `scripts\start-db.cmd` || windows.ReadyCheck != "pg_isready" ||
+		windows.TestCommand != "go test -tags=integration ./..." {
+		t.Errorf("Unexpected windows config: %+v", windows)
+	}
+	if linux := cfg.Integration.forOS("linux"); strings.Join(linux.Setup, "|") != "./start-db.sh" || linux.TestCommand != "" {
+		t.Errorf("Unexpected linux config: %+v", linux)
+	}
+
+	writeGatesConfig(t, dir, `
+integration:
+  enabled: true
+  commands:
+    plan9:
+      test_command: mk test
+`)
+	if _, err := LoadConfigFile(dir); err == nil {
+		t.Error("Expected error for command overrides for an unknown OS")
+	}
+}
+
+func TestRunIntegrationGate_SetupAndTeardown(t *testing.T) {
+	dir := t.TempDir()
+	runner := &Runner{
+		workingDir: dir,
+		integration: &IntegrationConfig{
+			Enabled:     true,
+			Setup:       []string{"touch service-up"},
+			Teardown:    []string{"rm -f service-up", "touch torn-down"},
+			ReadyCheck:  "test -f service-up",
+			TestCommand: "test -f service-up && echo \"$SERVICE_URL\"",
+			Env:         map[string]string{"SERVICE_URL": "http://localhost:9999"},
+		},
+	}
+
+	result := runner.runIntegrationGate(context.Background())
+	if !result.Passed {
+		t.Fatalf("Expected integration gate to pass, got error %v (output: %s)", result.Error, result.Output)
+	}
+	if result.Gate != GateIntegration {
+		t.Errorf("Expected gate type %s, got %s", GateIntegration, result.Gate)
+	}
+	if !strings.Contains(result.Output, "http://localhost:9999") {
+		t.Errorf("Expected test command to see configured env, output: %s", result.Output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "service-up")); !os.IsNotExist(err) {
+		t.Error("Expected service to be torn down")
+	}
+}
+
+func TestRunIntegrationGate_TeardownOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	runner := &Runner{
+		workingDir: dir,
+		integration: &IntegrationConfig{
+			Enabled:     true,
+			Setup:       []string{"touch service-up"},
+			Teardown:    []string{"rm -f service-up"},
+			TestCommand: "exit 1",
+		},
+	}
+
+	result := runner.runIntegrationGate(context.Background())
+	if result.Passed {
+		t.Fatal("Expected integration gate to fail")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "service-up")); !os.IsNotExist(err) {
+		t.Error("Expected teardown to run even when tests fail")
+	}
+}
+
+func TestComposeProjectName(t *testing.T) {
+	name := composeProjectName("/tmp/.sandboxes/Mission_vc-123")
+	if name != "vc-mission_vc-123" {
+		t.Errorf("Unexpected project name: %s", name)
+	}
+}
+
+func writeGatesConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".vc"), 0755); err != nil {
+		t.Fatalf("Failed to create .vc dir: %v", err)
+	}
+	if err := os.WriteFile(ConfigFilePath(dir), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write gates config: %v", err)
+	}
+}
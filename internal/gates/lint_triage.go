@@ -0,0 +1,88 @@
+package gates
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// lintTriageThreshold is the number of lint findings at which the supervisor
+// triages them before recovery. Below this, the raw output fits the recovery
+// prompt well enough on its own.
+const lintTriageThreshold = 10
+
+// lintTriageTimeout bounds the AI triage call
+const lintTriageTimeout = 2 * time.Minute
+
+// lintFindingPattern matches golangci-lint finding lines (file.go:line[:col]: message)
+var lintFindingPattern = regexp.MustCompile(`(?m)^\S+\.go:\d+(:\d+)?: `)
+
+// countLintFindings counts the findings in golangci-lint output
+func countLintFindings(output string) int {
+	return len(lintFindingPattern.FindAllStringIndex(output, -1))
+}
+
+// triageLintFailure asks the supervisor to bucket a large lint failure.
+// Returns nil if triage isn't needed or fails - callers fall back to raw output.
+func (r *Runner) triageLintFailure(ctx context.Context, originalIssue *types.Issue, result *Result) *ai.LintTriage {
+	if result.Gate != GateLint || r.supervisor == nil {
+		return nil
+	}
+	findings := countLintFindings(result.Output)
+	if findings < lintTriageThreshold {
+		return nil
+	}
+
+	changedFiles, err := r.changedFiles(ctx)
+	if err != nil {
+		// Triage still works without the file list, just less precisely
+		fmt.Printf("warning: failed to get changed files for lint triage: %v\n", err)
+	}
+
+	triageCtx, cancel := context.WithTimeout(ctx, lintTriageTimeout)
+	defer cancel()
+
+	triage, err := r.supervisor.TriageLintFailures(triageCtx, originalIssue, CompressOutput(result.Output), changedFiles)
+	if err != nil {
+		fmt.Printf("warning: lint triage failed for %s (%d findings): %v (using raw output)\n", originalIssue.ID, findings, err)
+		return nil
+	}
+	return triage
+}
+
+// changedFiles returns the files changed in the working directory relative to
+// HEAD, including new untracked files, which are often all an agent adds.
+// Paths come NUL-separated so names git would quote arrive as they are.
+func (r *Runner) changedFiles(ctx context.Context) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--name-only", "-z", "HEAD")
+	cmd.Dir = r.workingDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff --name-only failed: %w", err)
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "ls-files", "--others", "--exclude-standard", "-z")
+	cmd.Dir = r.workingDir
+
+	untracked, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w", err)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, path := range strings.Split(string(output)+string(untracked), "\x00") {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,95 @@
+package gates
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestCountLintFindings(t *testing.T) {
+	output := `internal/foo/foo.go:12:2: Error return value of ` + "`f.Close`" + ` is not checked (errcheck)
+internal/foo/bar.go:40: line is 140 characters (lll)
+	defer f.Close()
+	^
+cmd/vc/main.go:7:1: exported function Run should have comment or be unexported (golint)
+3 issues:
+* errcheck: 1
+`
+	if got := countLintFindings(output); got != 3 {
+		t.Errorf("Expected 3 findings, got %d", got)
+	}
+	if got := countLintFindings("golangci-lint not found in PATH"); got != 0 {
+		t.Errorf("Expected 0 findings, got %d", got)
+	}
+}
+
+func TestTriageLintFailure_SkippedWhenNotNeeded(t *testing.T) {
+	issue := &types.Issue{ID: "vc-1", Title: "Test"}
+
+	var many strings.Builder
+	for i := 0; i < lintTriageThreshold; i++ {
+		many.WriteString(fmt.Sprintf("foo.go:%d:1: unused variable (unused)\n", i+1))
+	}
+
+	// No supervisor: never triage
+	runner := &Runner{workingDir: t.TempDir()}
+	if triage := runner.triageLintFailure(context.Background(), issue, &Result{Gate: GateLint, Output: many.String()}); triage != nil {
+		t.Error("Expected no triage without a supervisor")
+	}
+
+	// Non-lint gates are never triaged
+	if triage := runner.triageLintFailure(context.Background(), issue, &Result{Gate: GateTest, Output: many.String()}); triage != nil {
+		t.Error("Expected no triage for non-lint gates")
+	}
+}
+
+func TestChangedFiles_IncludesUntracked(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	writeFile(".gitignore", "*.log\n")
+	writeFile("old.go", "package x\n")
+	writeFile("same.go", "package x\n")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-qm", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git not usable: %v (%s)", err, out)
+		}
+	}
+
+	writeFile("old.go", "package x\n\nvar unused int\n")
+	writeFile("pkg/new.go", "package pkg\n")
+	writeFile("pkg/naïve file.go", "package pkg\n")
+	writeFile("build.log", "ignored\n")
+
+	runner := &Runner{workingDir: dir}
+	files, err := runner.changedFiles(context.Background())
+	if err != nil {
+		t.Fatalf("changedFiles failed: %v", err)
+	}
+	sort.Strings(files)
+	want := []string{"old.go", "pkg/naïve file.go", "pkg/new.go"}
+	if strings.Join(files, "|") != strings.Join(want, "|") {
+		t.Errorf("changedFiles() = %q, want %q", files, want)
+	}
+}
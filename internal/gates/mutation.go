@@ -0,0 +1,264 @@
+package gates
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// MutationConfig controls the optional mutation testing gate.
+//
+// Mutation testing is far too slow to run on every execution, so the gate only
+// runs when the scheduler decides it is due: either the configured interval has
+// elapsed since the last run, or the issue is high-risk (high priority or
+// carries one of the high-risk labels).
+type MutationConfig struct {
+	Enabled          bool          // Enable mutation testing (default: false, opt-in)
+	Command          []string      // Command to run (default: go-mutesting ./...)
+	Interval         time.Duration // Minimum time between scheduled runs (default: 24h, 0 = only high-risk issues)
+	HighRiskPriority int           // Issues with priority <= this always run (default: 0, i.e. P0 only)
+	HighRiskLabels   []string      // Labels that force a run regardless of schedule (default: ["high-risk"])
+	Timeout          time.Duration // Timeout for a single mutation run (default: 15 minutes)
+}
+
+// DefaultMutationConfig returns default mutation testing configuration
+func DefaultMutationConfig() *MutationConfig {
+	return &MutationConfig{
+		Enabled:          false,
+		Command:          []string{"go-mutesting", "./..."},
+		Interval:         24 * time.Hour,
+		HighRiskPriority: 0,
+		HighRiskLabels:   []string{"high-risk"},
+		Timeout:          15 * time.Minute,
+	}
+}
+
+// MutationConfigFromEnv loads mutation testing configuration from environment variables
+func MutationConfigFromEnv() (*MutationConfig, error) {
+	cfg := DefaultMutationConfig()
+
+	// VC_MUTATION_ENABLED
+	if val := os.Getenv("VC_MUTATION_ENABLED"); val != "" {
+		enabled, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VC_MUTATION_ENABLED: %w", err)
+		}
+		cfg.Enabled = enabled
+	}
+
+	// VC_MUTATION_COMMAND
+	if val := os.Getenv("VC_MUTATION_COMMAND"); val != "" {
+		fields := strings.Fields(val)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("VC_MUTATION_COMMAND must not be blank")
+		}
+		cfg.Command = fields
+	}
+
+	// VC_MUTATION_INTERVAL
+	if val := os.Getenv("VC_MUTATION_INTERVAL"); val != "" {
+		interval, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VC_MUTATION_INTERVAL: %w", err)
+		}
+		if interval < 0 {
+			return nil, fmt.Errorf("VC_MUTATION_INTERVAL must be non-negative (got %v)", interval)
+		}
+		cfg.Interval = interval
+	}
+
+	// VC_MUTATION_HIGH_RISK_PRIORITY
+	if val := os.Getenv("VC_MUTATION_HIGH_RISK_PRIORITY"); val != "" {
+		priority, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VC_MUTATION_HIGH_RISK_PRIORITY: %w", err)
+		}
+		if priority < -1 || priority > 4 {
+			return nil, fmt.Errorf("VC_MUTATION_HIGH_RISK_PRIORITY must be between -1 and 4 (got %d)", priority)
+		}
+		cfg.HighRiskPriority = priority
+	}
+
+	// VC_MUTATION_TIMEOUT
+	if val := os.Getenv("VC_MUTATION_TIMEOUT"); val != "" {
+		timeout, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VC_MUTATION_TIMEOUT: %w", err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("VC_MUTATION_TIMEOUT must be positive (got %v)", timeout)
+		}
+		cfg.Timeout = timeout
+	}
+
+	return cfg, nil
+}
+
+// MutationScore summarizes a mutation testing run
+type MutationScore struct {
+	Score    float64 // Fraction of mutants killed (0.0-1.0)
+	Killed   int     // Mutants detected by the test suite
+	Survived int     // Mutants the test suite failed to detect
+	Skipped  int     // Mutants that were skipped (duplicates, compile errors)
+	Total    int     // Total mutants generated
+}
+
+// Summary returns a one-line human-readable description of the score
+func (m *MutationScore) Summary() string {
+	return fmt.Sprintf("mutation score %.2f (%d killed, %d survived, %d skipped, %d total)",
+		m.Score, m.Killed, m.Survived, m.Skipped, m.Total)
+}
+
+// go-mutesting prints a final line like:
+// "The mutation score is 0.750000 (6 passed, 2 failed, 0 duplicated, 0 skipped, total is 8)"
+var mutationScoreRe = regexp.MustCompile(`mutation score is ([0-9.]+) \((\d+) passed, (\d+) failed, (\d+) duplicated, (\d+) skipped, total is (\d+)\)`)
+
+// ParseMutationScore extracts the mutation score from go-mutesting output
+func ParseMutationScore(output string) (*MutationScore, error) {
+	match := mutationScoreRe.FindStringSubmatch(output)
+	if match == nil {
+		return nil, fmt.Errorf("no mutation score found in output")
+	}
+
+	score, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mutation score %q: %w", match[1], err)
+	}
+
+	// Regex guarantees digits, so Atoi cannot fail here
+	killed, _ := strconv.Atoi(match[2])
+	survived, _ := strconv.Atoi(match[3])
+	duplicated, _ := strconv.Atoi(match[4])
+	skipped, _ := strconv.Atoi(match[5])
+	total, _ := strconv.Atoi(match[6])
+
+	return &MutationScore{
+		Score:    score,
+		Killed:   killed,
+		Survived: survived,
+		Skipped:  skipped + duplicated,
+		Total:    total,
+	}, nil
+}
+
+// MutationScheduler decides when the mutation gate should run.
+// It is shared across executions so the interval applies executor-wide.
+type MutationScheduler struct {
+	config *MutationConfig
+
+	mu      sync.Mutex
+	lastRun time.Time
+}
+
+// NewMutationScheduler creates a new mutation scheduler
+func NewMutationScheduler(cfg *MutationConfig) *MutationScheduler {
+	if cfg == nil {
+		cfg = DefaultMutationConfig()
+	}
+	return &MutationScheduler{config: cfg}
+}
+
+// Config returns the scheduler's mutation configuration
+func (s *MutationScheduler) Config() *MutationConfig {
+	return s.config
+}
+
+// ShouldRun reports whether mutation testing should run for this issue now.
+// Returns a short reason suitable for logging when it should run.
+func (s *MutationScheduler) ShouldRun(issue *types.Issue, labels []string, now time.Time) (bool, string) {
+	if !s.config.Enabled {
+		return false, ""
+	}
+
+	if issue != nil && issue.Priority <= s.config.HighRiskPriority {
+		return true, fmt.Sprintf("high-risk priority P%d", issue.Priority)
+	}
+	for _, label := range labels {
+		for _, riskLabel := range s.config.HighRiskLabels {
+			if label == riskLabel {
+				return true, fmt.Sprintf("high-risk label %q", label)
+			}
+		}
+	}
+
+	if s.config.Interval <= 0 {
+		return false, ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastRun.IsZero() || now.Sub(s.lastRun) >= s.config.Interval {
+		return true, "scheduled run due"
+	}
+	return false, ""
+}
+
+// MarkRun records that a mutation run happened at the given time
+func (s *MutationScheduler) MarkRun(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRun = now
+}
+
+// RunMutationGate executes the configured mutation testing command.
+// The gate is informational: Passed reflects whether the tool ran and produced
+// a score, not whether the score was "good enough". Callers feed the score into
+// test coverage analysis rather than blocking on it.
+func (r *Runner) RunMutationGate(ctx context.Context, cfg *MutationConfig) (*Result, *MutationScore) {
+	result := &Result{Gate: GateMutation}
+
+	if cfg == nil || len(cfg.Command) == 0 {
+		result.Error = fmt.Errorf("mutation command not configured")
+		return result, nil
+	}
+
+	if _, err := exec.LookPath(cfg.Command[0]); err != nil {
+		result.Error = fmt.Errorf("%s not found in PATH", cfg.Command[0])
+		result.Output = fmt.Sprintf("%s is not installed or not in PATH", cfg.Command[0])
+		return result, nil
+	}
+
+	runCtx := ctx
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, cfg.Command[0], cfg.Command[1:]...)
+	cmd.Dir = r.workingDir
+	cmd.Env = append(os.Environ(),
+		"VC_DB_PATH=:memory:",
+		"BD_DB_PATH=:memory:",
+	)
+
+	// go-mutesting exits non-zero when mutants survive, so the exit code alone
+	// doesn't tell us whether the run succeeded - the score line does.
+	output, _ := cmd.CombinedOutput()
+	result.Output = string(output)
+
+	if runCtx.Err() != nil {
+		result.Error = fmt.Errorf("mutation testing canceled: %w", runCtx.Err())
+		if result.Output == "" {
+			result.Output = "Mutation testing canceled due to timeout"
+		}
+		return result, nil
+	}
+
+	score, err := ParseMutationScore(result.Output)
+	if err != nil {
+		result.Error = fmt.Errorf("mutation testing produced no score: %w", err)
+		return result, nil
+	}
+
+	result.Passed = true
+	return result, score
+}
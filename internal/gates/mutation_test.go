@@ -0,0 +1,122 @@
+package gates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestParseMutationScore(t *testing.T) {
+	output := `PASS "/tmp/go-mutesting/foo.go.0" with checksum abc
+FAIL "/tmp/go-mutesting/foo.go.1" with checksum def
+The mutation score is 0.750000 (6 passed, 2 failed, 1 duplicated, 3 skipped, total is 12)`
+
+	score, err := ParseMutationScore(output)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if score.Score != 0.75 {
+		t.Errorf("Expected score 0.75, got %f", score.Score)
+	}
+	if score.Killed != 6 || score.Survived != 2 {
+		t.Errorf("Expected 6 killed / 2 survived, got %d / %d", score.Killed, score.Survived)
+	}
+	if score.Skipped != 4 {
+		t.Errorf("Expected duplicated+skipped = 4, got %d", score.Skipped)
+	}
+	if score.Total != 12 {
+		t.Errorf("Expected total 12, got %d", score.Total)
+	}
+
+	if _, err := ParseMutationScore("no score here"); err == nil {
+		t.Error("Expected error for output without score")
+	}
+}
+
+func TestMutationScheduler_ShouldRun(t *testing.T) {
+	now := time.Now()
+	lowRisk := &types.Issue{ID: "vc-1", Priority: 2}
+	p0 := &types.Issue{ID: "vc-2", Priority: 0}
+
+	t.Run("disabled never runs", func(t *testing.T) {
+		cfg := DefaultMutationConfig()
+		sched := NewMutationScheduler(cfg)
+		if run, _ := sched.ShouldRun(p0, []string{"high-risk"}, now); run {
+			t.Error("Expected disabled scheduler not to run")
+		}
+	})
+
+	t.Run("high-risk priority always runs", func(t *testing.T) {
+		cfg := DefaultMutationConfig()
+		cfg.Enabled = true
+		sched := NewMutationScheduler(cfg)
+		sched.MarkRun(now)
+		if run, _ := sched.ShouldRun(p0, nil, now); !run {
+			t.Error("Expected P0 issue to run regardless of schedule")
+		}
+	})
+
+	t.Run("high-risk label always runs", func(t *testing.T) {
+		cfg := DefaultMutationConfig()
+		cfg.Enabled = true
+		sched := NewMutationScheduler(cfg)
+		sched.MarkRun(now)
+		if run, _ := sched.ShouldRun(lowRisk, []string{"high-risk"}, now); !run {
+			t.Error("Expected high-risk label to force a run")
+		}
+	})
+
+	t.Run("interval gates scheduled runs", func(t *testing.T) {
+		cfg := DefaultMutationConfig()
+		cfg.Enabled = true
+		cfg.Interval = time.Hour
+		sched := NewMutationScheduler(cfg)
+
+		if run, _ := sched.ShouldRun(lowRisk, nil, now); !run {
+			t.Error("Expected first scheduled run to be due")
+		}
+		sched.MarkRun(now)
+		if run, _ := sched.ShouldRun(lowRisk, nil, now.Add(30*time.Minute)); run {
+			t.Error("Expected no run before interval elapsed")
+		}
+		if run, _ := sched.ShouldRun(lowRisk, nil, now.Add(time.Hour)); !run {
+			t.Error("Expected run once interval elapsed")
+		}
+	})
+
+	t.Run("zero interval only runs high-risk", func(t *testing.T) {
+		cfg := DefaultMutationConfig()
+		cfg.Enabled = true
+		cfg.Interval = 0
+		sched := NewMutationScheduler(cfg)
+		if run, _ := sched.ShouldRun(lowRisk, nil, now); run {
+			t.Error("Expected no scheduled run with zero interval")
+		}
+	})
+}
+
+func TestMutationConfigFromEnv(t *testing.T) {
+	t.Setenv("VC_MUTATION_ENABLED", "true")
+	t.Setenv("VC_MUTATION_COMMAND", "go-mutesting ./internal/...")
+	t.Setenv("VC_MUTATION_INTERVAL", "2h")
+
+	cfg, err := MutationConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !cfg.Enabled {
+		t.Error("Expected mutation testing enabled")
+	}
+	if len(cfg.Command) != 2 || cfg.Command[1] != "./internal/..." {
+		t.Errorf("Unexpected command: %v", cfg.Command)
+	}
+	if cfg.Interval != 2*time.Hour {
+		t.Errorf("Expected 2h interval, got %v", cfg.Interval)
+	}
+
+	t.Setenv("VC_MUTATION_TIMEOUT", "-1s")
+	if _, err := MutationConfigFromEnv(); err == nil {
+		t.Error("Expected error for negative timeout")
+	}
+}
@@ -0,0 +1,219 @@
+package gates
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Gate overrides let a specific gate be skipped for a specific issue instead of
+// disabling quality gates globally to unblock one issue.
+//
+// An override needs two labels:
+//   - gate-override:<gate>           requests the skip (e.g. gate-override:lint)
+//   - gate-override-approved:<gate>  approves it; must be added by a human
+//
+// The approver is taken from the label_added audit event, so every skip can be
+// traced back to the person who allowed it.
+const (
+	// OverrideLabelPrefix requests that a gate be skipped for an issue
+	OverrideLabelPrefix = "gate-override:"
+	// OverrideApprovedLabelPrefix approves a requested gate override
+	OverrideApprovedLabelPrefix = "gate-override-approved:"
+)
+
+// GateOverride is an approved request to skip a gate for one issue
+type GateOverride struct {
+	Gate        GateType
+	RequestedBy string
+	ApprovedBy  string
+	ApprovedAt  time.Time
+}
+
+// SkipReason returns the reason recorded on the skipped gate result
+func (o *GateOverride) SkipReason() string {
+	return fmt.Sprintf("gate override approved by %s", o.ApprovedBy)
+}
+
+// OverrideStore is the subset of storage needed to resolve gate overrides
+type OverrideStore interface {
+	GetLabels(ctx context.Context, issueID string) ([]string, error)
+	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
+}
+
+// systemActors are the actors VC's own components record their changes as
+var systemActors = []string{"ai-supervisor", "quality-gates", "vc-executor", "executor"}
+
+// InstanceStore is the subset of storage needed to find executor actors
+type InstanceStore interface {
+	GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error)
+}
+
+// AutomatedActors returns the actors whose approvals ResolveOverrides must
+// ignore: VC's own components, the running executors (which act as their
+// instance IDs) and extra. If the executors can't be listed, the rest are
+// still returned.
+func AutomatedActors(ctx context.Context, store InstanceStore, extra ...string) []string {
+	actors := append(append([]string{}, systemActors...), extra...)
+	instances, err := store.GetActiveInstances(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to list executors for gate override approvals: %v\n", err)
+		return actors
+	}
+	for _, instance := range instances {
+		actors = append(actors, instance.InstanceID)
+	}
+	return actors
+}
+
+// PendingOverride is a requested override that can't be honored yet
+type PendingOverride struct {
+	Gate        GateType
+	Reason      string
+	RequestedAt time.Time // When the request label was added (zero if unknown)
+}
+
+// ResolveOverrides returns the approved gate overrides for an issue, plus any
+// requests that are not (validly) approved yet.
+//
+// automatedActors lists actors whose approvals are ignored (see
+// AutomatedActors) - an agent must not be able to wave
+// its own work through a gate. Nor may anyone approve their own request, so
+// a request whose requester isn't in the audit trail stays pending.
+func ResolveOverrides(ctx context.Context, store OverrideStore, issueID string, automatedActors []string) ([]*GateOverride, []PendingOverride, error) {
+	issueLabels, err := store.GetLabels(ctx, issueID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get labels: %w", err)
+	}
+
+	requested := make(map[GateType]bool)
+	approved := make(map[GateType]bool)
+	for _, label := range issueLabels {
+		switch {
+		case strings.HasPrefix(label, OverrideApprovedLabelPrefix):
+			approved[GateType(strings.TrimPrefix(label, OverrideApprovedLabelPrefix))] = true
+		case strings.HasPrefix(label, OverrideLabelPrefix):
+			requested[GateType(strings.TrimPrefix(label, OverrideLabelPrefix))] = true
+		}
+	}
+
+	if len(requested) == 0 {
+		return nil, nil, nil
+	}
+
+	// Events are returned newest first, so the first match is the latest add
+	issueEvents, err := store.GetEvents(ctx, issueID, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	var overrides []*GateOverride
+	var pending []PendingOverride
+	for gate := range requested {
+		var requestedBy string
+		var requestedAt time.Time
+		if requestEvent := findLabelAddedEvent(issueEvents, OverrideLabelPrefix+string(gate)); requestEvent != nil {
+			requestedBy, requestedAt = requestEvent.Actor, requestEvent.CreatedAt
+		}
+		pend := func(reason string) {
+			pending = append(pending, PendingOverride{Gate: gate, Reason: reason, RequestedAt: requestedAt})
+		}
+
+		if !approved[gate] {
+			pend("awaiting " + OverrideApprovedLabelPrefix + string(gate) + " label")
+			continue
+		}
+
+		approvalEvent := findLabelAddedEvent(issueEvents, OverrideApprovedLabelPrefix+string(gate))
+		if approvalEvent == nil || approvalEvent.Actor == "" {
+			pend("approver could not be determined from audit trail")
+			continue
+		}
+		if requestedBy == "" {
+			pend("requester could not be determined from audit trail, so the approval cannot be verified")
+			continue
+		}
+		if isAutomatedActor(approvalEvent.Actor, automatedActors) {
+			pend(fmt.Sprintf("approval by automated actor %s is not accepted", approvalEvent.Actor))
+			continue
+		}
+		if approvalEvent.Actor == requestedBy {
+			pend(fmt.Sprintf("%s requested the override and cannot also approve it", requestedBy))
+			continue
+		}
+
+		overrides = append(overrides, &GateOverride{
+			Gate:        gate,
+			RequestedBy: requestedBy,
+			ApprovedBy:  approvalEvent.Actor,
+			ApprovedAt:  approvalEvent.CreatedAt,
+		})
+	}
+
+	// Deterministic order for logging and tests
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].Gate < overrides[j].Gate })
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Gate < pending[j].Gate })
+
+	return overrides, pending, nil
+}
+
+// findLabelAddedEvent returns the most recent label_added event for a label
+func findLabelAddedEvent(issueEvents []*types.Event, label string) *types.Event {
+	want := "Added label: " + label
+	for _, event := range issueEvents {
+		if event.EventType != types.EventLabelAdded {
+			continue
+		}
+		if event.Comment != nil && *event.Comment == want {
+			return event
+		}
+		if event.NewValue != nil && *event.NewValue == label {
+			return event
+		}
+	}
+	return nil
+}
+
+func isAutomatedActor(actor string, automatedActors []string) bool {
+	for _, a := range automatedActors {
+		if actor == a {
+			return true
+		}
+	}
+	return false
+}
+
+// OverrideLabeler is the subset of storage needed to decide gate overrides
+type OverrideLabeler interface {
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	RemoveLabel(ctx context.Context, issueID, label, actor string) error
+}
+
+// ApproveOverride approves a requested override of gate for an issue as
+// actor. An approval already there that isn't accepted (e.g. by an
+// automated actor) is replaced, so this one is recorded in the audit trail.
+func ApproveOverride(ctx context.Context, store OverrideLabeler, issueID string, gate GateType, actor string) error {
+	label := OverrideApprovedLabelPrefix + string(gate)
+	if err := store.RemoveLabel(ctx, issueID, label, actor); err != nil {
+		return fmt.Errorf("failed to remove label %s: %w", label, err)
+	}
+	if err := store.AddLabel(ctx, issueID, label, actor); err != nil {
+		return fmt.Errorf("failed to add label %s: %w", label, err)
+	}
+	return nil
+}
+
+// RejectOverride rejects a requested override of gate for an issue as
+// actor, withdrawing the request so the gate keeps running
+func RejectOverride(ctx context.Context, store OverrideLabeler, issueID string, gate GateType, actor string) error {
+	label := OverrideLabelPrefix + string(gate)
+	if err := store.RemoveLabel(ctx, issueID, label, actor); err != nil {
+		return fmt.Errorf("failed to remove label %s: %w", label, err)
+	}
+	return nil
+}
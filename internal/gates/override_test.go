@@ -0,0 +1,204 @@
+package gates
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// overrideTestStore implements OverrideStore for testing
+type overrideTestStore struct {
+	labels []string
+	events []*types.Event
+}
+
+func (s *overrideTestStore) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	return s.labels, nil
+}
+
+func (s *overrideTestStore) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	return s.events, nil
+}
+
+// addLabel mimics beads: labels are recorded as label_added events, newest first
+func (s *overrideTestStore) addLabel(label, actor string, at time.Time) {
+	comment := "Added label: " + label
+	s.labels = append(s.labels, label)
+	s.events = append([]*types.Event{{
+		IssueID:   "vc-1",
+		EventType: types.EventLabelAdded,
+		Actor:     actor,
+		Comment:   &comment,
+		CreatedAt: at,
+	}}, s.events...)
+}
+
+func TestResolveOverrides_Approved(t *testing.T) {
+	now := time.Now()
+	store := &overrideTestStore{}
+	store.addLabel("gate-override:lint", "alice", now)
+	store.addLabel("gate-override-approved:lint", "bob", now.Add(time.Minute))
+
+	overrides, pending, err := ResolveOverrides(context.Background(), store, "vc-1", []string{"executor-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending overrides, got %v", pending)
+	}
+	if len(overrides) != 1 {
+		t.Fatalf("Expected 1 override, got %d", len(overrides))
+	}
+	o := overrides[0]
+	if o.Gate != GateLint || o.RequestedBy != "alice" || o.ApprovedBy != "bob" {
+		t.Errorf("Unexpected override: %+v", o)
+	}
+	if !o.ApprovedAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected approval time from audit event, got %v", o.ApprovedAt)
+	}
+}
+
+func TestResolveOverrides_RequiresApproval(t *testing.T) {
+	store := &overrideTestStore{}
+	store.addLabel("gate-override:test", "alice", time.Now())
+
+	overrides, pending, err := ResolveOverrides(context.Background(), store, "vc-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(overrides) != 0 {
+		t.Errorf("Expected no approved overrides without approval label, got %d", len(overrides))
+	}
+	if len(pending) != 1 || pending[0].Gate != GateTest {
+		t.Errorf("Expected pending test override, got %v", pending)
+	}
+}
+
+func TestResolveOverrides_RejectsAutomatedApprover(t *testing.T) {
+	store := &overrideTestStore{}
+	store.addLabel("gate-override:build", "alice", time.Now())
+	store.addLabel("gate-override-approved:build", "ai-supervisor", time.Now())
+
+	overrides, pending, err := ResolveOverrides(context.Background(), store, "vc-1", []string{"ai-supervisor"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(overrides) != 0 {
+		t.Error("Expected approval from automated actor to be rejected")
+	}
+	if len(pending) != 1 {
+		t.Errorf("Expected 1 pending override, got %d", len(pending))
+	}
+}
+
+func TestResolveOverrides_RejectsSelfApproval(t *testing.T) {
+	store := &overrideTestStore{}
+	store.addLabel("gate-override:test", "alice", time.Now())
+	store.addLabel("gate-override-approved:test", "alice", time.Now())
+
+	overrides, pending, err := ResolveOverrides(context.Background(), store, "vc-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(overrides) != 0 {
+		t.Error("Expected approval by the requester to be rejected")
+	}
+	if len(pending) != 1 || pending[0].Gate != GateTest {
+		t.Errorf("Expected pending test override, got %v", pending)
+	}
+}
+
+func TestResolveOverrides_UnknownRequester(t *testing.T) {
+	store := &overrideTestStore{}
+	store.addLabel("gate-override-approved:test", "bob", time.Now())
+	// The request label has no label_added event, e.g. from an import
+	store.labels = append(store.labels, "gate-override:test")
+
+	overrides, pending, err := ResolveOverrides(context.Background(), store, "vc-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(overrides) != 0 {
+		t.Error("Expected an approval that can't be checked against the requester to be rejected")
+	}
+	if len(pending) != 1 || pending[0].Gate != GateTest {
+		t.Errorf("Expected pending test override, got %v", pending)
+	}
+}
+
+func (s *overrideTestStore) GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error) {
+	return []*types.ExecutorInstance{{InstanceID: "3f2a9c1e"}}, nil
+}
+
+func TestResolveOverrides_RejectsExecutorApprover(t *testing.T) {
+	store := &overrideTestStore{}
+	store.addLabel("gate-override:lint", "alice", time.Now())
+	store.addLabel("gate-override-approved:lint", "3f2a9c1e", time.Now())
+
+	// The CLI, API and Slack don't know the executor's actor; the store does
+	overrides, pending, err := ResolveOverrides(context.Background(), store, "vc-1", AutomatedActors(context.Background(), store))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(overrides) != 0 || len(pending) != 1 {
+		t.Errorf("Expected approval by a running executor to be rejected, got %d overrides / %d pending", len(overrides), len(pending))
+	}
+}
+
+func TestResolveOverrides_ApprovalWithoutRequest(t *testing.T) {
+	store := &overrideTestStore{}
+	store.addLabel("gate-override-approved:lint", "bob", time.Now())
+
+	overrides, pending, err := ResolveOverrides(context.Background(), store, "vc-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(overrides) != 0 || len(pending) != 0 {
+		t.Errorf("Expected approval alone to do nothing, got %d overrides / %d pending", len(overrides), len(pending))
+	}
+}
+
+func (s *overrideTestStore) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	s.addLabel(label, actor, time.Now())
+	return nil
+}
+
+func (s *overrideTestStore) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	var kept []string
+	for _, l := range s.labels {
+		if l != label {
+			kept = append(kept, l)
+		}
+	}
+	s.labels = kept
+	return nil
+}
+
+func TestApproveAndRejectOverride(t *testing.T) {
+	ctx := context.Background()
+	store := &overrideTestStore{}
+	store.addLabel("gate-override:lint", "alice", time.Now())
+	store.addLabel("gate-override:test", "alice", time.Now())
+	// Not accepted, so it's replaced by the human approval below
+	store.addLabel("gate-override-approved:lint", "executor-1", time.Now())
+
+	if err := ApproveOverride(ctx, store, "vc-1", GateLint, "bob"); err != nil {
+		t.Fatalf("ApproveOverride failed: %v", err)
+	}
+	if err := RejectOverride(ctx, store, "vc-1", GateTest, "bob"); err != nil {
+		t.Fatalf("RejectOverride failed: %v", err)
+	}
+
+	overrides, pending, err := ResolveOverrides(ctx, store, "vc-1", []string{"executor-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("Expected the rejected request to be withdrawn, got pending %v", pending)
+	}
+	if len(overrides) != 1 || overrides[0].Gate != GateLint || overrides[0].ApprovedBy != "bob" {
+		t.Errorf("Unexpected overrides: %+v", overrides)
+	}
+}
@@ -0,0 +1,247 @@
+package gates
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// PipelineStage declares one gate in a custom gate pipeline.
+//
+// Stages form a DAG through Needs: a stage runs only after every stage it needs
+// has passed, so a stage can rely on the artifacts its dependencies produce.
+// Several stages may need the same stage (fan-out) and one stage may need
+// several (fan-in).
+//
+// Example .vc/gates.yaml:
+//
+//	pipeline:
+//	  - name: generate
+//	    command: go generate ./...
+//	  - name: build
+//	    needs: [generate]
+//	  - name: test
+//	    needs: [build]
+//	  - name: lint
+//	    needs: [build]
+//	  - name: vet
+//	    command: go vet ./...
+//	    needs: [build]
+//	  - name: package
+//	    command: make package
+//	    commands:
+//	      windows: scripts\package.cmd
+//	    needs: [test, lint, vet]
+type PipelineStage struct {
+	Name     string            `yaml:"name"`     // Gate name (build, test, lint, integration are built in)
+	Command  string            `yaml:"command"`  // Shell command (optional for built-in gates, required otherwise)
+	Commands map[string]string `yaml:"commands"` // Per-OS overrides of Command, keyed by GOOS (windows, darwin, linux, ...)
+	Needs    []string          `yaml:"needs"`    // Stages that must pass before this one runs
+}
+
+// builtinGates are the gates that can be used in a pipeline without a command
+var builtinGates = map[GateType]bool{
+	GateBuild:       true,
+	GateTest:        true,
+	GateLint:        true,
+	GateIntegration: true,
+}
+
+// gateStage is a runnable pipeline stage
+type gateStage struct {
+	gateType GateType
+	runFunc  func(context.Context) *Result
+	needs    []GateType
+	command  string   // What the gate runs, for explain mode
+	tools    []string // Programs the gate runs, for explain mode
+	timeout  string   // Gate-specific timeout, for explain mode ("" = none beyond the overall timeout)
+	custom   bool     // Runs a shell command instead of a built-in gate
+}
+
+// sortPipeline validates a pipeline and returns its stages in execution order.
+// Stages keep their declared order unless a dependency forces otherwise.
+func sortPipeline(stages []PipelineStage) ([]PipelineStage, error) {
+	byName := make(map[string]PipelineStage, len(stages))
+	for i, stage := range stages {
+		if stage.Name == "" {
+			return nil, fmt.Errorf("stage %d has no name", i+1)
+		}
+		if _, dup := byName[stage.Name]; dup {
+			return nil, fmt.Errorf("duplicate stage %q", stage.Name)
+		}
+		if stage.Command == "" && !builtinGates[GateType(stage.Name)] {
+			return nil, fmt.Errorf("stage %q needs a command (only build, test, lint and integration are built in)", stage.Name)
+		}
+		for goos, command := range stage.Commands {
+			if !knownGOOS[goos] {
+				return nil, fmt.Errorf("stage %q has command override for unknown OS %q", stage.Name, goos)
+			}
+			if command == "" {
+				return nil, fmt.Errorf("stage %q has empty command override for %s", stage.Name, goos)
+			}
+		}
+		byName[stage.Name] = stage
+	}
+	for _, stage := range stages {
+		for _, need := range stage.Needs {
+			if _, ok := byName[need]; !ok {
+				return nil, fmt.Errorf("stage %q needs unknown stage %q", stage.Name, need)
+			}
+			if need == stage.Name {
+				return nil, fmt.Errorf("stage %q needs itself", stage.Name)
+			}
+		}
+	}
+
+	// Repeatedly take the first declared stage whose needs are all placed
+	sorted := make([]PipelineStage, 0, len(stages))
+	placed := make(map[string]bool, len(stages))
+	for len(sorted) < len(stages) {
+		progress := false
+		for _, stage := range stages {
+			if placed[stage.Name] || !allPlaced(stage.Needs, placed) {
+				continue
+			}
+			sorted = append(sorted, stage)
+			placed[stage.Name] = true
+			progress = true
+			break
+		}
+		if !progress {
+			var remaining []string
+			for _, stage := range stages {
+				if !placed[stage.Name] {
+					remaining = append(remaining, stage.Name)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between stages: %s", strings.Join(remaining, ", "))
+		}
+	}
+	return sorted, nil
+}
+
+func allPlaced(needs []string, placed map[string]bool) bool {
+	for _, need := range needs {
+		if !placed[need] {
+			return false
+		}
+	}
+	return true
+}
+
+// stages returns the gates to run, in execution order
+func (r *Runner) stages() []gateStage {
+	if len(r.pipeline) == 0 {
+		return r.defaultStages()
+	}
+
+	stages := make([]gateStage, 0, len(r.pipeline))
+	for _, stage := range r.pipeline {
+		needs := make([]GateType, 0, len(stage.Needs))
+		for _, need := range stage.Needs {
+			needs = append(needs, GateType(need))
+		}
+		gate := r.builtinStage(GateType(stage.Name))
+		if command := stage.commandFor(runtime.GOOS); command != "" {
+			gate = gateStage{command: command, tools: shellTools(command), custom: true}
+		}
+		gate.gateType = GateType(stage.Name)
+		gate.runFunc = r.pipelineStageFunc(stage)
+		gate.needs = needs
+		stages = append(stages, gate)
+	}
+	return stages
+}
+
+// defaultStages is the built-in pipeline: build -> test -> lint (-> integration).
+// The gates don't depend on each other, so all of them run even if one fails -
+// this gives comprehensive feedback about all quality issues.
+func (r *Runner) defaultStages() []gateStage {
+	// BUILD runs first to catch compilation errors before running tests
+	// This prevents confusing test failures on code that doesn't even compile
+	stages := []gateStage{
+		r.builtinStage(GateBuild),
+		r.builtinStage(GateTest),
+		r.builtinStage(GateLint),
+	}
+
+	// Integration tests run last: they are the slowest and need services
+	if r.integration != nil && r.integration.Enabled {
+		stages = append(stages, r.builtinStage(GateIntegration))
+	}
+	return stages
+}
+
+// builtinStage returns the stage for a built-in gate
+func (r *Runner) builtinStage(gate GateType) gateStage {
+	switch gate {
+	case GateBuild:
+		return gateStage{gateType: GateBuild, runFunc: r.runBuildGate, command: "go build " + r.describePackages(), tools: []string{"go"}}
+	case GateTest:
+		return gateStage{gateType: GateTest, runFunc: r.runTestGate, command: "go test -short -timeout=2m " + r.describePackages(),
+			timeout: "2m per test binary", tools: []string{"go"}}
+	case GateLint:
+		command := "golangci-lint run ./..."
+		if _, err := ResolveTool("golangci-lint"); err != nil {
+			command += " (golangci-lint not found - gate will fail)"
+		}
+		return gateStage{gateType: GateLint, runFunc: r.runLintGate, command: command, tools: []string{"golangci-lint"}}
+	default:
+		return gateStage{gateType: GateIntegration, runFunc: r.runIntegrationGate, command: r.describeIntegration(),
+			timeout: r.integrationTimeouts(), tools: r.integrationTools()}
+	}
+}
+
+// pipelineStageFunc returns the function that runs a declared stage
+func (r *Runner) pipelineStageFunc(stage PipelineStage) func(context.Context) *Result {
+	if command := stage.commandFor(runtime.GOOS); command != "" {
+		return func(ctx context.Context) *Result {
+			return r.runCommandGate(ctx, GateType(stage.Name), command)
+		}
+	}
+
+	switch GateType(stage.Name) {
+	case GateBuild:
+		return r.runBuildGate
+	case GateTest:
+		return r.runTestGate
+	case GateLint:
+		return r.runLintGate
+	default:
+		return r.runIntegrationGate
+	}
+}
+
+// runCommandGate runs a custom pipeline stage command
+func (r *Runner) runCommandGate(ctx context.Context, gate GateType, command string) *Result {
+	result := &Result{Gate: gate}
+
+	output, err := r.runShellCommand(ctx, command, nil)
+	result.Output = output
+
+	// Check if command was killed due to context cancellation (vc-119)
+	if ctx.Err() != nil {
+		result.Error = fmt.Errorf("%s canceled: %w", gate, ctx.Err())
+		return result
+	}
+	if err != nil {
+		result.Error = fmt.Errorf("%s failed: %w", gate, err)
+		return result
+	}
+
+	result.Passed = true
+	return result
+}
+
+// unmetDependency returns the first dependency of a stage that didn't pass.
+// Stages skipped by an approved override count as passed.
+func unmetDependency(stage gateStage, completed map[GateType]*Result, blocked map[GateType]bool) (GateType, bool) {
+	for _, need := range stage.needs {
+		result, ok := completed[need]
+		if !ok || !result.Passed || blocked[need] {
+			return need, true
+		}
+	}
+	return "", false
+}
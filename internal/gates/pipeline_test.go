@@ -0,0 +1,180 @@
+package gates
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSortPipeline_FanOutFanIn(t *testing.T) {
+	// Declared out of order: package first, generate last
+	stages := []PipelineStage{
+		{Name: "package", Command: "true", Needs: []string{"test", "lint", "vet"}},
+		{Name: "test", Needs: []string{"build"}},
+		{Name: "lint", Needs: []string{"build"}},
+		{Name: "vet", Command: "go vet ./...", Needs: []string{"build"}},
+		{Name: "build", Needs: []string{"generate"}},
+		{Name: "generate", Command: "go generate ./..."},
+	}
+
+	sorted, err := sortPipeline(stages)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var names []string
+	for _, stage := range sorted {
+		names = append(names, stage.Name)
+	}
+	want := "generate,build,test,lint,vet,package"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("Expected order %s, got %s", want, got)
+	}
+}
+
+func TestSortPipeline_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		stages []PipelineStage
+		errMsg string
+	}{
+		{
+			name:   "cycle",
+			stages: []PipelineStage{{Name: "a", Command: "true", Needs: []string{"b"}}, {Name: "b", Command: "true", Needs: []string{"a"}}},
+			errMsg: "dependency cycle",
+		},
+		{
+			name:   "unknown dependency",
+			stages: []PipelineStage{{Name: "build", Needs: []string{"generate"}}},
+			errMsg: "unknown stage",
+		},
+		{
+			name:   "custom stage without command",
+			stages: []PipelineStage{{Name: "vet"}},
+			errMsg: "needs a command",
+		},
+		{
+			name:   "duplicate stage",
+			stages: []PipelineStage{{Name: "build"}, {Name: "build"}},
+			errMsg: "duplicate stage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sortPipeline(tt.stages)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestRunAll_PipelineUsesArtifacts(t *testing.T) {
+	sorted, err := sortPipeline([]PipelineStage{
+		{Name: "compile", Command: "test -f generated.txt && touch binary", Needs: []string{"generate"}},
+		{Name: "generate", Command: "touch generated.txt"},
+		{Name: "smoke", Command: "test -f binary", Needs: []string{"compile"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid pipeline, got %v", err)
+	}
+	runner := &Runner{workingDir: t.TempDir(), pipeline: sorted}
+
+	results, allPassed := runner.RunAll(context.Background())
+	if !allPassed {
+		for _, r := range results {
+			t.Logf("%s: passed=%v err=%v output=%s", r.Gate, r.Passed, r.Error, r.Output)
+		}
+		t.Fatal("Expected pipeline to pass")
+	}
+	if len(results) != 3 || results[0].Gate != "generate" || results[2].Gate != "smoke" {
+		t.Errorf("Unexpected results order: %v", results)
+	}
+}
+
+func TestRunAll_PipelineSkipsDependentsOfFailedStage(t *testing.T) {
+	sorted, err := sortPipeline([]PipelineStage{
+		{Name: "compile", Command: "exit 1"},
+		{Name: "unit", Command: "true", Needs: []string{"compile"}},
+		{Name: "style", Command: "true"},
+		{Name: "package", Command: "true", Needs: []string{"unit", "style"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid pipeline, got %v", err)
+	}
+	runner := &Runner{workingDir: t.TempDir(), pipeline: sorted}
+
+	results, allPassed := runner.RunAll(context.Background())
+	if allPassed {
+		t.Fatal("Expected pipeline to fail")
+	}
+
+	byGate := make(map[GateType]*Result)
+	for _, r := range results {
+		byGate[r.Gate] = r
+	}
+	if byGate["compile"].Passed {
+		t.Error("Expected compile to fail")
+	}
+	if !byGate["style"].Passed || byGate["style"].Skipped {
+		t.Error("Expected independent stage to run and pass")
+	}
+	for _, gate := range []GateType{"unit", "package"} {
+		if !byGate[gate].Skipped {
+			t.Errorf("Expected %s to be skipped after compile failed", gate)
+		}
+	}
+}
+
+func TestRunAll_PipelineOverriddenDependencyCountsAsPassed(t *testing.T) {
+	sorted, err := sortPipeline([]PipelineStage{
+		{Name: "compile", Command: "exit 1"},
+		{Name: "unit", Command: "true", Needs: []string{"compile"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected valid pipeline, got %v", err)
+	}
+	runner := &Runner{
+		workingDir: t.TempDir(),
+		pipeline:   sorted,
+		overrides:  map[GateType]*GateOverride{"compile": {Gate: "compile", ApprovedBy: "bob"}},
+	}
+
+	results, allPassed := runner.RunAll(context.Background())
+	if !allPassed {
+		t.Fatal("Expected pipeline to pass with overridden stage")
+	}
+	if results[1].Skipped {
+		t.Error("Expected dependent of overridden stage to run")
+	}
+}
+
+func TestLoadConfigFile_Pipeline(t *testing.T) {
+	dir := t.TempDir()
+	writeGatesConfig(t, dir, `
+pipeline:
+  - name: build
+  - name: vet
+    command: go vet ./...
+    needs: [build]
+`)
+
+	cfg, err := LoadConfigFile(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(cfg.Pipeline) != 2 || cfg.Pipeline[1].Needs[0] != "build" {
+		t.Errorf("Unexpected pipeline: %+v", cfg.Pipeline)
+	}
+
+	writeGatesConfig(t, dir, `
+pipeline:
+  - name: vet
+    needs: [missing]
+    command: go vet ./...
+`)
+	if _, err := LoadConfigFile(dir); err == nil {
+		t.Error("Expected error for unknown dependency")
+	}
+}
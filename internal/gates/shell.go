@@ -0,0 +1,165 @@
+package gates
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Shells that gate commands can run under (shell: in .vc/gates.yaml).
+// The default is sh on Unix-like systems and cmd on Windows.
+const (
+	ShellSh         = "sh"
+	ShellBash       = "bash"
+	ShellCmd        = "cmd"
+	ShellPowerShell = "powershell"
+	ShellPwsh       = "pwsh"
+)
+
+// knownGOOS are the operating systems accepted as keys of per-OS command overrides
+var knownGOOS = map[string]bool{
+	"linux":   true,
+	"darwin":  true,
+	"windows": true,
+	"freebsd": true,
+	"openbsd": true,
+	"netbsd":  true,
+}
+
+// validateShell checks that a configured shell is supported
+func validateShell(shell string) error {
+	switch shell {
+	case "", ShellSh, ShellBash, ShellCmd, ShellPowerShell, ShellPwsh:
+		return nil
+	default:
+		return fmt.Errorf("unsupported shell %q (use sh, bash, cmd, powershell or pwsh)", shell)
+	}
+}
+
+// shellInvocation returns the program and arguments that run a command line
+// under the given shell on the given OS
+func shellInvocation(shell, goos, command string) (string, []string) {
+	if shell == "" {
+		shell = ShellSh
+		if goos == "windows" {
+			shell = ShellCmd
+		}
+	}
+
+	switch shell {
+	case ShellCmd:
+		return "cmd", []string{"/C", command}
+	case ShellPowerShell, ShellPwsh:
+		return shell, []string{"-NoProfile", "-NonInteractive", "-Command", command}
+	default:
+		return shell, []string{"-c", command}
+	}
+}
+
+// cmdCommandLine returns the Windows command line that runs command under
+// cmd. cmd doesn't unquote its arguments the way Go quotes them, so the
+// command line is passed as is: /S makes cmd strip only the outer quotes and
+// run what's between them verbatim.
+func cmdCommandLine(command string) string {
+	return `cmd /S /C "` + command + `"`
+}
+
+// commandFor returns the stage command for an OS, preferring a per-OS override
+func (s PipelineStage) commandFor(goos string) string {
+	if command, ok := s.Commands[goos]; ok {
+		return command
+	}
+	return s.Command
+}
+
+// ResolveTool finds a gate's tool binary. Besides PATH, it checks GOBIN and
+// GOPATH/bin, where `go install` puts tools but which often aren't on PATH
+// (especially on Windows and macOS CI runners).
+// exec.LookPath already handles .exe/PATHEXT on Windows; the fallback
+// locations need the suffix added explicitly.
+func ResolveTool(name string) (string, error) {
+	path, lookErr := exec.LookPath(name)
+	if lookErr == nil {
+		return path, nil
+	}
+
+	binary := name
+	if runtime.GOOS == "windows" && !strings.HasSuffix(strings.ToLower(name), ".exe") {
+		binary += ".exe"
+	}
+
+	var dirs []string
+	if gobin := os.Getenv("GOBIN"); gobin != "" {
+		dirs = append(dirs, gobin)
+	}
+	if gopath := os.Getenv("GOPATH"); gopath != "" {
+		for _, dir := range filepath.SplitList(gopath) {
+			dirs = append(dirs, filepath.Join(dir, "bin"))
+		}
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, "go", "bin"))
+	}
+
+	for _, dir := range dirs {
+		candidate := filepath.Join(dir, binary)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", lookErr
+}
+
+// shellCommand builds an exec.Cmd that runs a command line under the runner's shell
+func (r *Runner) shellCommand(ctx context.Context, command string) *exec.Cmd {
+	name, args := shellInvocation(r.shell, runtime.GOOS, command)
+	cmd := exec.CommandContext(ctx, name, args...)
+	if name == ShellCmd {
+		setCommandLine(cmd, cmdCommandLine(command))
+	}
+	cmd.Dir = r.workingDir
+	return cmd
+}
+
+// shellPrefixes are shell keywords that come before the program a command
+// runs
+var shellPrefixes = map[string]bool{
+	"if": true, "then": true, "else": true, "elif": true, "while": true, "until": true, "do": true,
+	"!": true, "{": true, "exec": true, "command": true, "time": true,
+}
+
+// shellBuiltins are commands the shell runs itself
+var shellBuiltins = map[string]bool{
+	"cd": true, "export": true, "set": true, "unset": true, "echo": true, "exit": true, "true": true, "false": true,
+	"test": true, "[": true, "[[": true, "source": true, ".": true, "for": true, "case": true,
+	"fi": true, "done": true, "esac": true, "}": true,
+}
+
+// shellTools returns the programs a shell command line runs: the first word
+// of each command in it, skipping variable assignments, shell builtins and
+// paths (project scripts are checked out, not installed). It is a best
+// effort for diagnostics, not a shell parser.
+func shellTools(command string) []string {
+	var tools []string
+	seen := map[string]bool{}
+	replacer := strings.NewReplacer("&&", "\n", "||", "\n", ";", "\n", "|", "\n", "(", "\n", ")", "\n")
+	for _, segment := range strings.Split(replacer.Replace(command), "\n") {
+		for _, word := range strings.Fields(segment) {
+			if strings.Contains(word, "=") && !strings.HasPrefix(word, "=") {
+				continue // VAR=value prefix
+			}
+			if shellPrefixes[word] || strings.HasPrefix(word, "-") {
+				continue // e.g. "if", "command -v"
+			}
+			if !shellBuiltins[word] && !strings.ContainsAny(word, "/\\$`'\"") && !seen[word] {
+				seen[word] = true
+				tools = append(tools, word)
+			}
+			break
+		}
+	}
+	return tools
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package gates
+
+import "os/exec"
+
+// setCommandLine does nothing outside Windows, where programs get their
+// arguments as they are rather than as one command line to parse
+func setCommandLine(cmd *exec.Cmd, line string) {}
@@ -0,0 +1,130 @@
+package gates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestShellInvocation(t *testing.T) {
+	tests := []struct {
+		shell    string
+		goos     string
+		wantName string
+		wantArgs string
+	}{
+		{"", "linux", "sh", "-c|go test ./..."},
+		{"", "darwin", "sh", "-c|go test ./..."},
+		{"", "windows", "cmd", "/C|go test ./..."},
+		{ShellBash, "windows", "bash", "-c|go test ./..."},
+		{ShellPowerShell, "windows", "powershell", "-NoProfile|-NonInteractive|-Command|go test ./..."},
+		{ShellPwsh, "darwin", "pwsh", "-NoProfile|-NonInteractive|-Command|go test ./..."},
+	}
+
+	for _, tt := range tests {
+		name, args := shellInvocation(tt.shell, tt.goos, "go test ./...")
+		if name != tt.wantName || strings.Join(args, "|") != tt.wantArgs {
+			t.Errorf("shellInvocation(%q, %q) = %s %v, want %s %s", tt.shell, tt.goos, name, args, tt.wantName, tt.wantArgs)
+		}
+	}
+}
+
+func TestCmdCommandLine(t *testing.T) {
+	command := `go test -run "TestA|TestB" ./... && echo "done"`
+	want := `cmd /S /C "go test -run "TestA|TestB" ./... && echo "done""`
+	if got := cmdCommandLine(command); got != want {
+		t.Errorf("cmdCommandLine() = %s, want %s", got, want)
+	}
+}
+
+func TestShellTools(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"go vet ./...", "go"},
+		{"CGO_ENABLED=0 go build ./... && npm test", "go|npm"},
+		{"cd web && npm ci; npm run lint | tee lint.log", "npm|tee"},
+		{"if command -v buf; then buf lint; fi", "buf"},
+		{"echo building && ./scripts/build.sh && make", "make"},
+		{"$GO test ./...", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(shellTools(tt.command), "|"); got != tt.want {
+			t.Errorf("shellTools(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestPipelineStage_CommandFor(t *testing.T) {
+	stage := PipelineStage{
+		Name:     "package",
+		Command:  "make package",
+		Commands: map[string]string{"windows": `scripts\package.cmd`},
+	}
+	if got := stage.commandFor("windows"); got != `scripts\package.cmd` {
+		t.Errorf("Expected windows override, got %q", got)
+	}
+	if got := stage.commandFor("darwin"); got != "make package" {
+		t.Errorf("Expected default command on darwin, got %q", got)
+	}
+}
+
+func TestLoadConfigFile_ShellAndOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeGatesConfig(t, dir, `
+shell: fish
+`)
+	if _, err := LoadConfigFile(dir); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+
+	writeGatesConfig(t, dir, `
+pipeline:
+  - name: vet
+    command: go vet ./...
+    commands:
+      plan9: vet.rc
+`)
+	if _, err := LoadConfigFile(dir); err == nil {
+		t.Error("Expected error for override on unknown OS")
+	}
+}
+
+func TestResolveTool_GOBIN(t *testing.T) {
+	gobin := t.TempDir()
+	binary := "vc-fake-linter"
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	if err := os.WriteFile(filepath.Join(gobin, binary), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake tool: %v", err)
+	}
+	t.Setenv("GOBIN", gobin)
+	t.Setenv("PATH", t.TempDir())
+
+	path, err := ResolveTool("vc-fake-linter")
+	if err != nil {
+		t.Fatalf("Expected tool to be found in GOBIN, got %v", err)
+	}
+	if path != filepath.Join(gobin, binary) {
+		t.Errorf("Unexpected tool path: %s", path)
+	}
+}
+
+func TestRunShellCommand_UsesConfiguredShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("bash-specific syntax")
+	}
+	runner := &Runner{workingDir: t.TempDir(), shell: ShellBash}
+	out, err := runner.runShellCommand(context.Background(), `[[ -n "$BASH_VERSION" ]] && echo bash`, nil)
+	if err != nil {
+		t.Skipf("bash not available: %v", err)
+	}
+	if strings.TrimSpace(out) != "bash" {
+		t.Errorf("Expected command to run under bash, got %q", out)
+	}
+}
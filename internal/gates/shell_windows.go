@@ -0,0 +1,12 @@
+package gates
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setCommandLine makes cmd start with exactly this command line, instead of
+// one built by quoting its arguments
+func setCommandLine(cmd *exec.Cmd, line string) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: line}
+}
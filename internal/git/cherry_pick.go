@@ -0,0 +1,88 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CherryPickResult describes a cherry-pick
+type CherryPickResult struct {
+	// Commit is the new HEAD after all commits were picked ("" on conflict)
+	Commit string
+
+	// Conflicts lists the files that conflicted; the cherry-pick was
+	// abandoned and the tree left as it was
+	Conflicts []string
+
+	// ConflictingCommit is the commit that didn't apply cleanly
+	ConflictingCommit string
+}
+
+// CherryPick applies commits, oldest first, on top of HEAD, each as a new
+// commit recording where it was picked from ("cherry picked from commit").
+// The working tree must be clean. If a commit conflicts, the whole
+// cherry-pick is abandoned and the conflicting files are reported in the
+// result rather than as an error.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) CherryPick(ctx context.Context, repoPath string, commits []string) (*CherryPickResult, error) {
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits to cherry-pick")
+	}
+	dirty, err := g.HasUncommittedChanges(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("working tree has uncommitted changes")
+	}
+	start, err := g.ResolveRef(ctx, repoPath, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CherryPickResult{}
+	for _, commit := range commits {
+		cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "cherry-pick", "-x", commit)
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			continue
+		}
+		conflicts := g.getConflictedFiles(ctx, repoPath)
+		g.abortCherryPick(ctx, repoPath, start)
+		if len(conflicts) == 0 {
+			return nil, fmt.Errorf("git cherry-pick %s failed: %w\nOutput: %s", commit, err, output)
+		}
+		result.Conflicts = conflicts
+		result.ConflictingCommit = commit
+		return result, nil
+	}
+
+	result.Commit, err = g.ResolveRef(ctx, repoPath, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// abortCherryPick abandons a cherry-pick in progress and moves HEAD back to
+// start, undoing commits already picked, best effort
+func (g *Git) abortCherryPick(ctx context.Context, repoPath, start string) {
+	_ = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "cherry-pick", "--abort").Run()
+	_ = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "reset", "--hard", start).Run()
+}
+
+// ResolveRef resolves a branch, tag or other revision to a commit hash.
+// Returns an error if it doesn't name a commit.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) ResolveRef(ctx context.Context, repoPath, ref string) (string, error) {
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "rev-parse", "--verify", "-q", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s in %s: %w", ref, repoPath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCherryPick(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name, content, message string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", "-A")
+		run("commit", "-m", message)
+		return run("rev-parse", "HEAD")
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+	initial := commit("a.txt", "one\n", "initial")
+	run("branch", "release")
+	fix := commit("b.txt", "fix\n", "fix b")
+	other := commit("c.txt", "c\n", "add c")
+	conflicting := commit("a.txt", "two\n", "change a")
+	run("checkout", "-q", "release")
+	commit("a.txt", "release\n", "release change to a")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+
+	if hash, err := g.ResolveRef(ctx, dir, "main~3"); err != nil || hash != initial {
+		t.Errorf("ResolveRef(main~3) = %q, %v; want %s", hash, err, initial)
+	}
+	if _, err := g.ResolveRef(ctx, dir, "no-such-branch"); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+	if _, err := g.CherryPick(ctx, dir, nil); err == nil {
+		t.Error("expected an error for no commits")
+	}
+
+	// A conflict abandons the whole cherry-pick, including commits already picked
+	before := run("rev-parse", "HEAD")
+	result, err := g.CherryPick(ctx, dir, []string{fix, conflicting})
+	if err != nil {
+		t.Fatalf("CherryPick() error = %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "a.txt" || result.ConflictingCommit != conflicting {
+		t.Errorf("CherryPick() = %+v, want a conflict in a.txt from %s", result, conflicting)
+	}
+	if head := run("rev-parse", "HEAD"); head != before {
+		t.Errorf("HEAD = %s after a conflict, want %s", head, before)
+	}
+	if status := run("status", "--porcelain"); status != "" {
+		t.Errorf("working tree not clean after a conflict:\n%s", status)
+	}
+
+	result, err = g.CherryPick(ctx, dir, []string{fix, other})
+	if err != nil {
+		t.Fatalf("CherryPick() error = %v", err)
+	}
+	if len(result.Conflicts) != 0 || result.Commit != run("rev-parse", "HEAD") {
+		t.Errorf("CherryPick() = %+v, want the new HEAD", result)
+	}
+	if log := run("log", "--format=%s", before+"..HEAD"); log != "add c\nfix b" {
+		t.Errorf("picked commits = %q, want both in order", log)
+	}
+	if body := run("log", "-1", "--format=%b", "HEAD~1"); !strings.Contains(body, "cherry picked from commit "+fix) {
+		t.Errorf("picked commit doesn't record its origin: %q", body)
+	}
+}
@@ -0,0 +1,169 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CodeOwnersPaths are where a CODEOWNERS file is looked for, in order. The
+// first found is used, as GitHub and GitLab do.
+var CodeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// CodeOwners maps files to their owners by the rules of a CODEOWNERS file
+type CodeOwners struct {
+	Path  string // File the rules were read from, relative to the repository
+	rules []codeOwnersRule
+}
+
+// codeOwnersRule is one line of a CODEOWNERS file
+type codeOwnersRule struct {
+	pattern string
+	match   *regexp.Regexp
+	owners  []string // Empty: the files are explicitly unowned
+}
+
+// CodeOwner is an owner of files under review
+type CodeOwner struct {
+	Name  string // As written in CODEOWNERS: "@user", "@org/team" or an email
+	Files int    // How many of the files they own
+}
+
+// IsTeam reports whether the owner is a team ("@org/team")
+func (o CodeOwner) IsTeam() bool {
+	return strings.HasPrefix(o.Name, "@") && strings.Contains(o.Name, "/")
+}
+
+// IsUser reports whether the owner is a user by login ("@user")
+func (o CodeOwner) IsUser() bool {
+	return strings.HasPrefix(o.Name, "@") && !strings.Contains(o.Name, "/")
+}
+
+// Login returns the owner's name without the leading "@", e.g. "alice" or
+// "org/team"; emails are returned unchanged
+func (o CodeOwner) Login() string {
+	return strings.TrimPrefix(o.Name, "@")
+}
+
+// LoadCodeOwners reads the CODEOWNERS file of the repository at repoPath
+// from the first of CodeOwnersPaths that exists. Returns nil if there is none.
+func LoadCodeOwners(repoPath string) (*CodeOwners, error) {
+	for _, rel := range CodeOwnersPaths {
+		f, err := os.Open(filepath.Join(repoPath, rel))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", rel, err)
+		}
+		defer f.Close()
+		return ParseCodeOwners(rel, f)
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses a CODEOWNERS file read from path. Each line is a
+// gitignore-style pattern followed by its owners; the last matching line
+// wins. Comments, GitLab section headers ("[Section]") and negated patterns,
+// which CODEOWNERS doesn't support, are skipped.
+func ParseCodeOwners(path string, r io.Reader) (*CodeOwners, error) {
+	co := &CodeOwners{Path: path}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") ||
+			strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") || strings.HasPrefix(fields[0], "!") {
+			continue
+		}
+		rule := codeOwnersRule{pattern: fields[0], match: codeOwnersPattern(fields[0])}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break // Trailing comment
+			}
+			rule.owners = append(rule.owners, owner)
+		}
+		co.rules = append(co.rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return co, nil
+}
+
+// codeOwnersPattern compiles a CODEOWNERS pattern. As in gitignore, a
+// pattern with a slash before its end is relative to the repository root
+// and any other matches at any depth; a pattern matching a directory
+// matches everything in it.
+func codeOwnersPattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var re strings.Builder
+	if anchored {
+		re.WriteString("^")
+	} else {
+		re.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			re.WriteString("[^/]*")
+		case pattern[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if dirOnly {
+		re.WriteString("/.*$")
+	} else {
+		re.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(re.String())
+}
+
+// Owners returns the owners of file, a path relative to the repository
+// root; nil if it's unowned
+func (c *CodeOwners) Owners(file string) []string {
+	file = strings.TrimPrefix(filepath.ToSlash(file), "/")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].match.MatchString(file) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// OwnersOf ranks the owners of files, most files owned first
+func (c *CodeOwners) OwnersOf(files []string) []CodeOwner {
+	counts := make(map[string]int)
+	for _, file := range files {
+		for _, owner := range c.Owners(file) {
+			counts[owner]++
+		}
+	}
+	owners := make([]CodeOwner, 0, len(counts))
+	for name, n := range counts {
+		owners = append(owners, CodeOwner{Name: name, Files: n})
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].Files != owners[j].Files {
+			return owners[i].Files > owners[j].Files
+		}
+		return owners[i].Name < owners[j].Name
+	})
+	return owners
+}
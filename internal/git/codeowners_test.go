@@ -0,0 +1,90 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testCodeOwners = `# Default owners
+*                   @acme/core
+
+# Frontend
+*.js                @acme/web   # trailing comment
+/docs/              docs@acme.com
+apps/               @alice
+/build/logs/
+internal/**/api.go  @acme/api @bob
+
+[Section]
+!ignored.go         @nobody
+`
+
+func TestCodeOwners(t *testing.T) {
+	co, err := ParseCodeOwners("CODEOWNERS", strings.NewReader(testCodeOwners))
+	if err != nil {
+		t.Fatalf("ParseCodeOwners() error = %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"main.go", []string{"@acme/core"}},
+		{"web/src/app.js", []string{"@acme/web"}},
+		{"docs/guide.md", []string{"docs@acme.com"}},
+		{"src/docs/guide.md", []string{"@acme/core"}}, // /docs/ is anchored
+		{"apps/cli/main.go", []string{"@alice"}},
+		{"services/apps/x.go", []string{"@alice"}}, // apps/ matches at any depth
+		{"apps", []string{"@acme/core"}},           // A file, not the directory
+		{"build/logs/out.log", nil},                // Explicitly unowned
+		{"internal/api.go", []string{"@acme/api", "@bob"}},
+		{"internal/v1/http/api.go", []string{"@acme/api", "@bob"}},
+		{"ignored.go", []string{"@acme/core"}},
+	}
+	for _, tt := range tests {
+		if got := co.Owners(tt.file); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+
+	owners := co.OwnersOf([]string{"main.go", "cmd/x.go", "web/app.js", "internal/api.go", "build/logs/a"})
+	want := []CodeOwner{{"@acme/core", 2}, {"@acme/api", 1}, {"@acme/web", 1}, {"@bob", 1}}
+	if !reflect.DeepEqual(owners, want) {
+		t.Errorf("OwnersOf() = %v, want %v", owners, want)
+	}
+	if !owners[0].IsTeam() || owners[0].IsUser() || owners[0].Login() != "acme/core" {
+		t.Errorf("unexpected kind of %v", owners[0])
+	}
+	if bob := owners[3]; !bob.IsUser() || bob.Login() != "bob" {
+		t.Errorf("unexpected kind of %v", bob)
+	}
+}
+
+func TestLoadCodeOwners(t *testing.T) {
+	dir := t.TempDir()
+	co, err := LoadCodeOwners(dir)
+	if err != nil || co != nil {
+		t.Fatalf("LoadCodeOwners() = %v, %v; want nil without a CODEOWNERS file", co, err)
+	}
+
+	// .github/CODEOWNERS takes precedence over the root one
+	if err := os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("* @root\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte("* @github\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	co, err = LoadCodeOwners(dir)
+	if err != nil {
+		t.Fatalf("LoadCodeOwners() error = %v", err)
+	}
+	if co.Path != ".github/CODEOWNERS" || !reflect.DeepEqual(co.Owners("x.go"), []string{"@github"}) {
+		t.Errorf("loaded %s with owners %v", co.Path, co.Owners("x.go"))
+	}
+}
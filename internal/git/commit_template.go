@@ -0,0 +1,259 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Commit message formats
+const (
+	CommitFormatConventional = "conventional" // type(scope): description
+	CommitFormatPlain        = "plain"        // Free-form imperative subject
+)
+
+// CommitTemplate describes the commit messages a repository wants, so
+// generated messages follow its conventions rather than VC's. Repositories
+// override the default in .vc/commit_template (YAML):
+//
+//	format: plain
+//	subject_prefix: "{ticket}: "
+//	ticket_pattern: "[A-Z][A-Z0-9]+-[0-9]+"
+//	sections: [Why, Testing]
+//	trailers: ["Jira: {ticket}", "Refs: {issue}"]
+//	instructions: Mention user-visible changes first.
+//
+// In subject_prefix and trailers, {issue} is the VC issue ID and {ticket}
+// the first match of ticket_pattern in the issue's title or description
+// (the issue ID if there's none).
+type CommitTemplate struct {
+	// Format is "conventional" or "plain" (default: conventional)
+	Format string `yaml:"format"`
+
+	// Types are the commit types allowed in conventional subjects
+	// (default: ConventionalCommitTypes)
+	Types []string `yaml:"types"`
+
+	// MaxSubjectLength is the longest subject allowed
+	// (default: MaxCommitSubjectLength)
+	MaxSubjectLength int `yaml:"max_subject_length"`
+
+	// SubjectPrefix must start every subject, e.g. "{ticket}: " (optional)
+	SubjectPrefix string `yaml:"subject_prefix"`
+
+	// TicketPattern is a regular expression matching the repository's
+	// ticket IDs, e.g. "[A-Z][A-Z0-9]+-[0-9]+" for JIRA-123 (optional)
+	TicketPattern string `yaml:"ticket_pattern"`
+
+	// Sections are headings the body must have, each on a line of its own
+	// followed by a colon, e.g. "Why:" (optional)
+	Sections []string `yaml:"sections"`
+
+	// Trailers must end the body; missing ones are added
+	// (default: "Refs: {issue}")
+	Trailers []string `yaml:"trailers"`
+
+	// Instructions are extra guidance for the model (optional)
+	Instructions string `yaml:"instructions"`
+
+	ticketRegex *regexp.Regexp
+}
+
+// DefaultCommitTemplate returns VC's own commit conventions: conventional
+// commits ending with a "Refs: <issue-id>" trailer
+func DefaultCommitTemplate() *CommitTemplate {
+	t := &CommitTemplate{}
+	_ = t.Validate() // Fills in the defaults
+	return t
+}
+
+// CommitTemplatePath returns the location of a project's commit template
+func CommitTemplatePath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".vc", "commit_template")
+}
+
+// LoadCommitTemplate loads a project's commit template from
+// .vc/commit_template. A missing file is not an error - it returns
+// DefaultCommitTemplate(). Unset fields keep their defaults.
+func LoadCommitTemplate(projectRoot string) (*CommitTemplate, error) {
+	path := CommitTemplatePath(projectRoot)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultCommitTemplate(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading commit template: %w", err)
+	}
+
+	var t CommitTemplate
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parsing commit template: %w", err)
+	}
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid commit template %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// Validate checks the template for invalid settings and fills in defaults
+func (t *CommitTemplate) Validate() error {
+	switch t.Format {
+	case "":
+		t.Format = CommitFormatConventional
+	case CommitFormatConventional, CommitFormatPlain:
+	default:
+		return fmt.Errorf("format must be %q or %q (got %q)", CommitFormatConventional, CommitFormatPlain, t.Format)
+	}
+	if len(t.Types) == 0 {
+		t.Types = ConventionalCommitTypes
+	}
+	if t.MaxSubjectLength < 0 {
+		return fmt.Errorf("max_subject_length cannot be negative (got %d)", t.MaxSubjectLength)
+	}
+	if t.MaxSubjectLength == 0 {
+		t.MaxSubjectLength = MaxCommitSubjectLength
+	}
+	if t.TicketPattern != "" {
+		re, err := regexp.Compile(t.TicketPattern)
+		if err != nil {
+			return fmt.Errorf("invalid ticket_pattern: %w", err)
+		}
+		t.ticketRegex = re
+	}
+	if t.Trailers == nil {
+		t.Trailers = []string{IssueTrailerKey + ": {issue}"}
+	}
+	for _, trailer := range t.Trailers {
+		if !trailerRegex.MatchString(trailer) {
+			return fmt.Errorf("trailer %q is not in the form Key: value", trailer)
+		}
+	}
+	for _, section := range t.Sections {
+		if strings.TrimSpace(section) == "" || strings.Contains(section, "\n") {
+			return fmt.Errorf("invalid section %q", section)
+		}
+	}
+	return nil
+}
+
+// Ticket returns the ticket a commit for req refers to: the first match of
+// the ticket pattern in the issue's title or description, or the issue ID
+func (t *CommitTemplate) Ticket(req CommitMessageRequest) string {
+	if t.ticketRegex != nil {
+		if ticket := t.ticketRegex.FindString(req.IssueTitle + "\n" + req.IssueDescription); ticket != "" {
+			return ticket
+		}
+	}
+	return req.IssueID
+}
+
+// SubjectPrefixFor returns the subject prefix for req, placeholders filled in
+func (t *CommitTemplate) SubjectPrefixFor(req CommitMessageRequest) string {
+	return t.expand(t.SubjectPrefix, req)
+}
+
+// TrailersFor returns the required trailers for req, placeholders filled
+// in. Trailers referring to an empty issue ID are left out.
+func (t *CommitTemplate) TrailersFor(req CommitMessageRequest) []string {
+	var trailers []string
+	for _, trailer := range t.Trailers {
+		expanded := t.expand(trailer, req)
+		if _, value, _ := strings.Cut(expanded, ":"); strings.TrimSpace(value) == "" {
+			continue
+		}
+		trailers = append(trailers, expanded)
+	}
+	return trailers
+}
+
+// expand fills in the {issue} and {ticket} placeholders
+func (t *CommitTemplate) expand(s string, req CommitMessageRequest) string {
+	return strings.NewReplacer("{issue}", req.IssueID, "{ticket}", t.Ticket(req)).Replace(s)
+}
+
+// Check checks a generated commit message against the template. Returns a
+// description of each rule broken; none means the message is valid.
+func (t *CommitTemplate) Check(msg CommitMessageResponse, req CommitMessageRequest) []string {
+	var violations []string
+
+	subject := msg.Subject
+	if strings.Contains(subject, "\n") {
+		violations = append(violations, "subject must be a single line")
+		subject = strings.SplitN(subject, "\n", 2)[0]
+	}
+	if len(subject) > t.MaxSubjectLength {
+		violations = append(violations, fmt.Sprintf("subject is %d characters, the limit is %d", len(subject), t.MaxSubjectLength))
+	}
+
+	if prefix := t.SubjectPrefixFor(req); prefix != "" {
+		rest, ok := strings.CutPrefix(subject, prefix)
+		if !ok {
+			violations = append(violations, fmt.Sprintf("subject must start with %q", prefix))
+		}
+		subject = rest
+	}
+
+	if t.Format == CommitFormatConventional {
+		m := conventionalSubjectRegex.FindStringSubmatch(subject)
+		if m == nil {
+			violations = append(violations, fmt.Sprintf("subject %q is not in the form type(scope): description", subject))
+		} else {
+			if !t.allowsType(m[1]) {
+				violations = append(violations, fmt.Sprintf("type %q is not one of %s", m[1], strings.Join(t.Types, ", ")))
+			}
+			if strings.HasSuffix(m[3], ".") {
+				violations = append(violations, "subject must not end with a period")
+			}
+		}
+	} else if strings.HasSuffix(subject, ".") {
+		violations = append(violations, "subject must not end with a period")
+	}
+
+	for _, section := range t.Sections {
+		if !hasSection(msg.Body, section) {
+			violations = append(violations, fmt.Sprintf("body must have a %q section", section+":"))
+		}
+	}
+
+	for _, trailer := range t.TrailersFor(req) {
+		if !hasTrailer(msg.Body, trailer) {
+			violations = append(violations, fmt.Sprintf("body must end with a %q trailer", trailer))
+		}
+	}
+
+	return violations
+}
+
+// WithTrailers returns body ending with the template's required trailers
+// for req, adding any that are missing
+func (t *CommitTemplate) WithTrailers(body string, req CommitMessageRequest) string {
+	for _, trailer := range t.TrailersFor(req) {
+		body = withTrailer(body, trailer)
+	}
+	return body
+}
+
+func (t *CommitTemplate) allowsType(commitType string) bool {
+	for _, allowed := range t.Types {
+		if allowed == commitType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSection reports whether body has a line consisting of the section's
+// heading, e.g. "Why:"
+func hasSection(body, section string) bool {
+	heading := strings.TrimSuffix(strings.TrimSpace(section), ":") + ":"
+	for _, line := range strings.Split(body, "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), heading) {
+			return true
+		}
+	}
+	return false
+}
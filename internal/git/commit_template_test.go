@@ -0,0 +1,110 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCommitTemplate(t *testing.T) {
+	dir := t.TempDir()
+
+	// No template: VC's own conventions
+	tmpl, err := LoadCommitTemplate(dir)
+	if err != nil {
+		t.Fatalf("LoadCommitTemplate() without a file failed: %v", err)
+	}
+	if tmpl.Format != CommitFormatConventional || tmpl.MaxSubjectLength != MaxCommitSubjectLength ||
+		len(tmpl.Trailers) != 1 || tmpl.Trailers[0] != "Refs: {issue}" {
+		t.Errorf("default template = %+v", tmpl)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ".vc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(CommitTemplatePath(dir), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("format: plain\nsubject_prefix: \"{ticket}: \"\nticket_pattern: \"[A-Z][A-Z0-9]+-[0-9]+\"\nsections: [Why]\ntrailers: [\"Jira: {ticket}\"]\n")
+	tmpl, err = LoadCommitTemplate(dir)
+	if err != nil {
+		t.Fatalf("LoadCommitTemplate() failed: %v", err)
+	}
+	req := CommitMessageRequest{IssueID: "vc-7", IssueTitle: "Retry failed charges", IssueDescription: "See PAY-123 for details"}
+	if got := tmpl.SubjectPrefixFor(req); got != "PAY-123: " {
+		t.Errorf("SubjectPrefixFor() = %q, want %q", got, "PAY-123: ")
+	}
+	if got := tmpl.TrailersFor(req); len(got) != 1 || got[0] != "Jira: PAY-123" {
+		t.Errorf("TrailersFor() = %v, want [Jira: PAY-123]", got)
+	}
+	// Without a ticket, the issue ID stands in
+	if got := tmpl.Ticket(CommitMessageRequest{IssueID: "vc-7", IssueTitle: "No ticket"}); got != "vc-7" {
+		t.Errorf("Ticket() without a match = %q, want vc-7", got)
+	}
+
+	for _, bad := range []string{
+		"format: gitmoji\n",
+		"ticket_pattern: \"[A-Z\"\n",
+		"trailers: [\"not a trailer\"]\n",
+		"max_subject_length: -1\n",
+		"format: [\n",
+	} {
+		write(bad)
+		if _, err := LoadCommitTemplate(dir); err == nil {
+			t.Errorf("LoadCommitTemplate(%q) = nil error, want one", bad)
+		}
+	}
+}
+
+func TestCommitTemplateCheck(t *testing.T) {
+	tmpl := &CommitTemplate{
+		Format:        CommitFormatPlain,
+		SubjectPrefix: "{ticket}: ",
+		TicketPattern: `[A-Z]+-[0-9]+`,
+		Sections:      []string{"Why", "Testing"},
+		Trailers:      []string{"Jira: {ticket}", "Refs: {issue}"},
+	}
+	if err := tmpl.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	req := CommitMessageRequest{IssueID: "vc-1", IssueTitle: "PAY-9: retry charges"}
+	body := "Why:\nCharges failed.\n\nTesting:\nUnit tests.\n\nJira: PAY-9\nRefs: vc-1"
+
+	tests := []struct {
+		name    string
+		subject string
+		body    string
+		want    string // Substring of the only violation; empty for valid
+	}{
+		{name: "valid", subject: "PAY-9: Retry failed charges", body: body},
+		{name: "missing prefix", subject: "Retry failed charges", body: body, want: `start with "PAY-9: "`},
+		{name: "trailing period", subject: "PAY-9: Retry failed charges.", body: body, want: "period"},
+		{name: "missing section", subject: "PAY-9: Retry failed charges", body: "Why:\nCharges failed.\n\nJira: PAY-9\nRefs: vc-1", want: `"Testing:" section`},
+		{name: "missing trailer", subject: "PAY-9: Retry failed charges", body: "Why:\nx\n\nTesting:\ny\n\nRefs: vc-1", want: `"Jira: PAY-9" trailer`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := tmpl.Check(CommitMessageResponse{Subject: tt.subject, Body: tt.body}, req)
+			if tt.want == "" {
+				if len(violations) != 0 {
+					t.Errorf("expected a valid message, got %v", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || !strings.Contains(violations[0], tt.want) {
+				t.Errorf("violations = %v, want one mentioning %q", violations, tt.want)
+			}
+		})
+	}
+
+	// Missing trailers are added
+	got := tmpl.WithTrailers("Why:\nx\n\nTesting:\ny", req)
+	if want := "Why:\nx\n\nTesting:\ny\n\nJira: PAY-9\nRefs: vc-1"; got != want {
+		t.Errorf("WithTrailers() = %q, want %q", got, want)
+	}
+}
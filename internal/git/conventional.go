@@ -0,0 +1,99 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ConventionalCommitTypes are the commit types generated messages may use
+var ConventionalCommitTypes = []string{"feat", "fix", "docs", "refactor", "test", "chore", "perf", "build", "ci", "style", "revert"}
+
+// MaxCommitSubjectLength is the longest subject line a generated commit
+// message may have
+const MaxCommitSubjectLength = 72
+
+// IssueTrailerKey is the trailer that ties a commit to its issue, e.g.
+// "Refs: vc-119"
+const IssueTrailerKey = "Refs"
+
+// conventionalSubjectRegex matches "type(scope)!: description", with the
+// scope and breaking-change marker optional
+var conventionalSubjectRegex = regexp.MustCompile(`^([a-z]+)(\([a-z0-9][a-z0-9._/-]*\))?!?: (\S.*)$`)
+
+// trailerRegex matches a git trailer line, e.g. "Co-Authored-By: ..."
+var trailerRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*: \S`)
+
+// ValidateCommitMessage checks a generated commit message against the
+// conventional-commit rules VC uses by default (DefaultCommitTemplate): a
+// known type, an optional lowercase scope, a subject of at most
+// MaxCommitSubjectLength characters without a trailing period, and a
+// "Refs: <issue-id>" trailer in the body. Returns a description of each rule
+// broken; none means the message is valid.
+func ValidateCommitMessage(msg CommitMessageResponse, issueID string) []string {
+	return DefaultCommitTemplate().Check(msg, CommitMessageRequest{IssueID: issueID})
+}
+
+// WithIssueTrailer returns body ending with the "Refs: <issue-id>" trailer,
+// adding it to an existing trailer block or as a new paragraph
+func WithIssueTrailer(body, issueID string) string {
+	if issueID == "" {
+		return body
+	}
+	return withTrailer(body, IssueTrailerKey+": "+issueID)
+}
+
+// withTrailer returns body ending with trailer ("Key: value"), adding it to
+// an existing trailer block or as a new paragraph
+func withTrailer(body, trailer string) string {
+	if hasTrailer(body, trailer) {
+		return body
+	}
+	body = strings.TrimRight(body, "\n ")
+	switch {
+	case body == "":
+		return trailer
+	case endsWithTrailers(body):
+		return body + "\n" + trailer
+	default:
+		return body + "\n\n" + trailer
+	}
+}
+
+// hasTrailer reports whether the last paragraph of body is a trailer block
+// containing trailer ("Key: value"; the key is case-insensitive)
+func hasTrailer(body, trailer string) bool {
+	if !endsWithTrailers(body) {
+		return false
+	}
+	wantKey, wantValue, _ := strings.Cut(trailer, ":")
+	for _, line := range strings.Split(lastParagraph(body), "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		if strings.EqualFold(key, wantKey) && strings.TrimSpace(value) == strings.TrimSpace(wantValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsWithTrailers reports whether the last paragraph of a message consists
+// of git trailers only
+func endsWithTrailers(message string) bool {
+	paragraph := lastParagraph(message)
+	if paragraph == "" {
+		return false
+	}
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !trailerRegex.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+func lastParagraph(message string) string {
+	message = strings.TrimRight(message, "\n ")
+	if i := strings.LastIndex(message, "\n\n"); i >= 0 {
+		return message[i+2:]
+	}
+	return message
+}
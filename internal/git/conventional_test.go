@@ -0,0 +1,123 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+func TestValidateCommitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		body    string
+		want    string // Substring of the only violation; empty for valid
+	}{
+		{name: "valid", subject: "feat(git): validate commit messages", body: "Why.\n\nRefs: vc-1"},
+		{name: "no scope", subject: "fix: handle empty diffs", body: "Refs: vc-1"},
+		{name: "breaking change", subject: "refactor(api)!: rename the client", body: "Why.\n\nCo-Authored-By: A <a@b>\nRefs: vc-1"},
+		{name: "not conventional", subject: "Validate commit messages", body: "Refs: vc-1", want: "not in the form"},
+		{name: "unknown type", subject: "feature(git): validate", body: "Refs: vc-1", want: "type \"feature\""},
+		{name: "uppercase scope", subject: "feat(Git): validate", body: "Refs: vc-1", want: "not in the form"},
+		{name: "trailing period", subject: "docs: explain rollback.", body: "Refs: vc-1", want: "period"},
+		{name: "too long", subject: "feat(git): " + strings.Repeat("x", 70), body: "Refs: vc-1", want: "characters"},
+		{name: "multiline subject", subject: "fix: one\ntwo", body: "Refs: vc-1", want: "single line"},
+		{name: "missing trailer", subject: "fix: handle empty diffs", body: "Why.", want: "Refs: vc-1"},
+		{name: "trailer for another issue", subject: "fix: handle empty diffs", body: "Why.\n\nRefs: vc-2", want: "Refs: vc-1"},
+		{name: "issue mentioned in prose only", subject: "fix: handle empty diffs", body: "Refs: vc-1 was wrong.\n\nMore text", want: "Refs: vc-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := ValidateCommitMessage(CommitMessageResponse{Subject: tt.subject, Body: tt.body}, "vc-1")
+			if tt.want == "" {
+				if len(violations) != 0 {
+					t.Errorf("expected a valid message, got %v", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || !strings.Contains(violations[0], tt.want) {
+				t.Errorf("violations = %v, want one mentioning %q", violations, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithIssueTrailer(t *testing.T) {
+	tests := []struct {
+		body, want string
+	}{
+		{"", "Refs: vc-1"},
+		{"Why.", "Why.\n\nRefs: vc-1"},
+		{"Why.\n\nCo-Authored-By: A <a@b>\n", "Why.\n\nCo-Authored-By: A <a@b>\nRefs: vc-1"},
+		{"Why.\n\nRefs: vc-1", "Why.\n\nRefs: vc-1"},
+	}
+	for _, tt := range tests {
+		if got := WithIssueTrailer(tt.body, "vc-1"); got != tt.want {
+			t.Errorf("WithIssueTrailer(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+// TestGenerateCommitMessageReprompts checks that a message breaking the
+// rules is sent back to the model with the violations
+func TestGenerateCommitMessageReprompts(t *testing.T) {
+	replies := []string{
+		`{"subject": "Added validation.", "body": "Checks messages."}`,
+		`{"subject": "feat(git): validate generated commit messages", "body": "Checks messages."}`,
+	}
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		prompts = append(prompts, req.Messages[0].Content[0].Text)
+		reply := replies[min(len(prompts), len(replies))-1]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id": "msg_1", "type": "message", "role": "assistant", "model": "test-model",
+			"content":     []map[string]string{{"type": "text", "text": reply}},
+			"stop_reason": "end_turn",
+			"usage":       map[string]int{"input_tokens": 1, "output_tokens": 1},
+		})
+	}))
+	defer server.Close()
+
+	client := anthropic.NewClient(option.WithAPIKey("test"), option.WithBaseURL(server.URL), option.WithMaxRetries(0))
+	gen := NewMessageGenerator(&client, "test-model")
+	msg, err := gen.GenerateCommitMessage(context.Background(), CommitMessageRequest{IssueID: "vc-1", IssueTitle: "Validate messages"})
+	if err != nil {
+		t.Fatalf("GenerateCommitMessage failed: %v", err)
+	}
+	if msg.Subject != "feat(git): validate generated commit messages" || msg.Body != "Checks messages.\n\nRefs: vc-1" {
+		t.Errorf("message = %+v", msg)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected 2 prompts, got %d", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "Added validation.") || !strings.Contains(prompts[1], "not in the form") {
+		t.Errorf("second prompt should explain what was wrong:\n%s", prompts[1])
+	}
+
+	// A model that never gets it right fails the generation
+	replies = replies[:1]
+	prompts = nil
+	if _, err := gen.GenerateCommitMessage(context.Background(), CommitMessageRequest{IssueID: "vc-1"}); err == nil {
+		t.Error("expected an error when every attempt is invalid")
+	}
+	if len(prompts) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(prompts))
+	}
+}
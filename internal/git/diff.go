@@ -0,0 +1,175 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// emptyTreeHash is git's hash of the empty tree, the base of a repository
+// with no commits
+const emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// FileDiff is the part of a diff that changes one file
+type FileDiff struct {
+	// Path is the file's path after the change (before it, for deletions)
+	Path string
+
+	// OldPath is the file's path before a rename; empty otherwise
+	OldPath string
+
+	// Patch is the file's section of the diff, from its "diff --git" header
+	Patch string
+
+	// Additions and Deletions count the added and removed lines
+	Additions int
+	Deletions int
+
+	// Binary is true for binary files, whose patch has no content
+	Binary bool
+}
+
+// Diff is a diff split into one FileDiff per changed file
+type Diff struct {
+	Files []FileDiff
+}
+
+// ParseDiff splits the output of git diff into per-file diffs
+func ParseDiff(raw string) *Diff {
+	d := &Diff{}
+	var current *FileDiff
+	var patch strings.Builder
+	inHunk := false
+
+	flush := func() {
+		if current != nil {
+			current.Patch = patch.String()
+			d.Files = append(d.Files, *current)
+		}
+		patch.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		if line == "" {
+			continue
+		}
+		content := strings.TrimSuffix(line, "\n")
+		if strings.HasPrefix(content, "diff --git ") {
+			flush()
+			current = &FileDiff{Path: headerPath(content)}
+			inHunk = false
+		}
+		if current == nil {
+			continue // Not a diff: no file header yet
+		}
+		patch.WriteString(line)
+
+		switch {
+		case strings.HasPrefix(content, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(content, "+"):
+			current.Additions++
+		case inHunk && strings.HasPrefix(content, "-"):
+			current.Deletions++
+		case inHunk:
+		case strings.HasPrefix(content, "rename from "):
+			current.OldPath = strings.TrimPrefix(content, "rename from ")
+		case strings.HasPrefix(content, "rename to "):
+			current.Path = strings.TrimPrefix(content, "rename to ")
+		case strings.HasPrefix(content, "+++ b/"):
+			current.Path = strings.TrimPrefix(content, "+++ b/")
+		case strings.HasPrefix(content, "Binary files "), content == "GIT binary patch":
+			current.Binary = true
+		}
+	}
+	flush()
+	return d
+}
+
+// headerPath extracts the path from a "diff --git a/<path> b/<path>" header.
+// The +++ line, when there is one, gives it unambiguously.
+func headerPath(header string) string {
+	rest := strings.TrimPrefix(header, "diff --git ")
+	if i := strings.LastIndex(rest, " b/"); i >= 0 {
+		return rest[i+3:]
+	}
+	return strings.TrimPrefix(rest, "a/")
+}
+
+// String returns the diff as git printed it
+func (d *Diff) String() string {
+	var out strings.Builder
+	for _, f := range d.Files {
+		out.WriteString(f.Patch)
+	}
+	return out.String()
+}
+
+// Only returns the part of the diff that changes the given paths. A path
+// ending in "/" (as git status reports untracked directories) matches
+// everything under it.
+func (d *Diff) Only(paths []string) *Diff {
+	matches := func(file string) bool {
+		for _, p := range paths {
+			if file == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(file, p)) {
+				return true
+			}
+		}
+		return false
+	}
+	filtered := &Diff{}
+	for _, f := range d.Files {
+		if matches(f.Path) || (f.OldPath != "" && matches(f.OldPath)) {
+			filtered.Files = append(filtered.Files, f)
+		}
+	}
+	return filtered
+}
+
+// Diff returns the changes in the working tree of repoPath relative to base
+// (HEAD if empty): staged and unstaged changes to tracked files, plus
+// untracked files as additions. Ignored files are left out.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) Diff(ctx context.Context, repoPath, base string) (*Diff, error) {
+	if base == "" {
+		base = "HEAD"
+		// A repository without commits is diffed against the empty tree
+		cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "rev-parse", "--verify", "-q", "HEAD")
+		if err := cmd.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+				return nil, fmt.Errorf("git rev-parse failed in %s: %w", repoPath, err)
+			}
+			base = emptyTreeHash
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "diff", "--no-color", "--no-ext-diff", "-M", base, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s failed in %s: %w", base, repoPath, err)
+	}
+	raw := string(output)
+
+	cmd = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "ls-files", "--others", "--exclude-standard", "-z")
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed in %s: %w", repoPath, err)
+	}
+	for _, path := range bytes.Split(output, []byte{0}) {
+		if len(path) == 0 {
+			continue
+		}
+		// --no-index exits 1 when the files differ, which they always do here
+		cmd = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "diff", "--no-color", "--no-ext-diff", "--no-index", "--", "/dev/null", string(path))
+		out, err := cmd.Output()
+		if exitErr, ok := err.(*exec.ExitError); err != nil && (!ok || exitErr.ExitCode() != 1) {
+			return nil, fmt.Errorf("git diff of untracked file %s failed in %s: %w", path, repoPath, err)
+		}
+		raw += string(out)
+	}
+
+	return ParseDiff(raw), nil
+}
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// promptContextLines is how many unchanged lines are kept around each change
+// in diffs sent to prompts; git's default of three mostly repeats code the
+// model doesn't need to judge the change
+const promptContextLines = 1
+
+var (
+	// vendoredDirs hold third-party code nobody reviews line by line
+	vendoredDirs = []string{"vendor", "node_modules", "third_party"}
+
+	// lockFiles are generated by package managers
+	lockFiles = map[string]bool{
+		"go.sum":            true,
+		"package-lock.json": true,
+		"yarn.lock":         true,
+		"pnpm-lock.yaml":    true,
+		"Cargo.lock":        true,
+		"Gemfile.lock":      true,
+		"poetry.lock":       true,
+		"composer.lock":     true,
+	}
+
+	// generatedSuffixes name files produced by code generators and minifiers
+	generatedSuffixes = []string{".pb.go", "_generated.go", ".gen.go", ".min.js", ".min.css"}
+
+	// generatedMarkerRe matches Go's "Code generated ... DO NOT EDIT." header
+	// in the added or unchanged lines of a patch
+	generatedMarkerRe = regexp.MustCompile(`(?m)^[+ ]// Code generated .* DO NOT EDIT\.$`)
+)
+
+// IsGenerated reports whether a file is vendored, a lock file or generated
+// code, going by its path and, for Go's generated code header, its patch
+func IsGenerated(f FileDiff) bool {
+	for _, dir := range strings.Split(path.Dir(f.Path), "/") {
+		for _, vendored := range vendoredDirs {
+			if dir == vendored {
+				return true
+			}
+		}
+	}
+	if lockFiles[path.Base(f.Path)] {
+		return true
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(f.Path, suffix) {
+			return true
+		}
+	}
+	return generatedMarkerRe.MatchString(f.Patch)
+}
+
+// Compress returns the diff made smaller for a prompt without losing what
+// changed: the patches of vendored and generated files are replaced by a
+// line saying they changed, and runs of unchanged lines are cut to
+// promptContextLines around each change
+func (d *Diff) Compress() *Diff {
+	compressed := &Diff{}
+	for _, f := range d.Files {
+		switch {
+		case f.Binary:
+		case IsGenerated(f):
+			f.Patch = fmt.Sprintf("diff --git a/%s b/%s\n[vendored or generated file: content omitted]\n", f.Path, f.Path)
+		default:
+			f.Patch = collapseContext(f.Patch, promptContextLines)
+		}
+		compressed.Files = append(compressed.Files, f)
+	}
+	return compressed
+}
+
+// collapseContext keeps at most n unchanged lines before and after each
+// change in a patch. Unchanged lines cut from between two changes are
+// replaced by a line counting them, so the model knows the changes are apart.
+func collapseContext(patch string, n int) string {
+	var out strings.Builder
+	var run []string // Unchanged lines since the last change
+	inHunk, changed := false, false
+
+	flush := func(beforeChange bool) {
+		switch {
+		case !changed:
+			// Leading context: keep the lines just before the first change
+			if beforeChange && len(run) > n {
+				run = run[len(run)-n:]
+			}
+		case !beforeChange:
+			// Trailing context: keep the lines just after the last change
+			if len(run) > n {
+				run = run[:n]
+			}
+		case len(run) > 2*n+1:
+			// Between changes: keep both ends and count the middle
+			for _, line := range run[:n] {
+				out.WriteString(line)
+			}
+			fmt.Fprintf(&out, "[... %d unchanged lines]\n", len(run)-2*n)
+			run = run[len(run)-n:]
+		}
+		for _, line := range run {
+			out.WriteString(line)
+		}
+		run = run[:0]
+	}
+
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		if line == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			if inHunk {
+				flush(false)
+			}
+			inHunk, changed = true, false
+			out.WriteString(line)
+		case inHunk && strings.HasPrefix(line, " "):
+			run = append(run, line)
+		case inHunk && (strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-")):
+			flush(true)
+			changed = true
+			out.WriteString(line)
+		case strings.HasPrefix(line, "\\") && len(run) > 0:
+			// "\ No newline at end of file" belongs to the line before it
+			run[len(run)-1] += line
+		default:
+			flush(false)
+			out.WriteString(line)
+		}
+	}
+	flush(false)
+	return out.String()
+}
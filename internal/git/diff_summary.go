@@ -0,0 +1,156 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxSummaryPatchSize is the most of one file's patch sent to be summarized
+	maxSummaryPatchSize = 60000
+
+	// maxCachedSummaries bounds the summary cache; it is emptied when full
+	maxCachedSummaries = 1000
+)
+
+// DiffSummarizer renders diffs to fit a prompt, replacing the patches of
+// files too large to include with AI summaries. Summaries are cached by
+// patch content, so code review, commit messages and PR descriptions of the
+// same change summarize each file once.
+type DiffSummarizer struct {
+	gen *MessageGenerator // Makes the AI calls; nil summarizes by line counts only
+
+	mu    sync.Mutex
+	cache map[string]string // Summary by hash of path and patch
+}
+
+// NewDiffSummarizer creates a DiffSummarizer that makes AI calls through gen.
+// With a nil gen, files are summarized by their line counts only.
+func NewDiffSummarizer(gen *MessageGenerator) *DiffSummarizer {
+	return &DiffSummarizer{
+		gen:   gen,
+		cache: make(map[string]string),
+	}
+}
+
+// Render returns d as text of at most budget bytes. The diff is compressed
+// first, and the whole of it is returned if it fits. Otherwise the smallest patches are kept while each
+// fits its share of what is left, and the other files are summarized.
+func (s *DiffSummarizer) Render(ctx context.Context, d *Diff, budget int) string {
+	d = d.Compress()
+	raw := d.String()
+	if budget <= 0 || len(raw) <= budget {
+		return raw
+	}
+
+	// Give small files their patches first; each file's share of the budget
+	// grows with what smaller files leave unused
+	order := make([]int, len(d.Files))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(d.Files[order[a]].Patch) < len(d.Files[order[b]].Patch)
+	})
+	keep := make([]bool, len(d.Files))
+	remaining := budget
+	for i, idx := range order {
+		size := len(d.Files[idx].Patch)
+		if size > remaining/(len(order)-i) {
+			break
+		}
+		keep[idx] = true
+		remaining -= size
+	}
+
+	var out strings.Builder
+	for i, f := range d.Files {
+		if keep[i] {
+			out.WriteString(f.Patch)
+			continue
+		}
+		summary, err := s.Summarize(ctx, f)
+		if err != nil {
+			summary = fmt.Sprintf("(summary unavailable: %v)", err)
+		}
+		fmt.Fprintf(&out, "diff --git a/%s b/%s\n[patch summarized: %d additions, %d deletions]\n%s\n",
+			f.Path, f.Path, f.Additions, f.Deletions, summary)
+	}
+
+	rendered := out.String()
+	if len(rendered) > budget {
+		const marker = "\n... (truncated)\n"
+		rendered = truncateUTF8(rendered, max(budget-len(marker), 0)) + marker
+	}
+	return rendered
+}
+
+// Summarize returns a short description of what a file's patch changes,
+// from the cache when the same patch was summarized before
+func (s *DiffSummarizer) Summarize(ctx context.Context, f FileDiff) (string, error) {
+	if f.Binary {
+		return "Binary file changed.", nil
+	}
+	if s.gen == nil {
+		return fmt.Sprintf("%d lines added, %d lines removed.", f.Additions, f.Deletions), nil
+	}
+
+	sum := sha256.Sum256([]byte(f.Path + "\x00" + f.Patch))
+	key := hex.EncodeToString(sum[:])
+	s.mu.Lock()
+	summary, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok {
+		return summary, nil
+	}
+
+	summary, err := s.gen.complete(ctx, "diff-summary", buildDiffSummaryPrompt(f))
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize diff of %s: %w", f.Path, err)
+	}
+	summary = strings.TrimSpace(summary)
+
+	s.mu.Lock()
+	if len(s.cache) >= maxCachedSummaries {
+		s.cache = make(map[string]string)
+	}
+	s.cache[key] = summary
+	s.mu.Unlock()
+	return summary, nil
+}
+
+// buildDiffSummaryPrompt constructs the prompt for summarizing one file's patch
+func buildDiffSummaryPrompt(f FileDiff) string {
+	var prompt strings.Builder
+	prompt.WriteString("You are summarizing one file's changes for reviewers of an AI-supervised coding agent's work.\n\n")
+	prompt.WriteString(fmt.Sprintf("## File\n\n%s (%d additions, %d deletions)\n\n", f.Path, f.Additions, f.Deletions))
+
+	patch := f.Patch
+	if len(patch) > maxSummaryPatchSize {
+		patch = truncateUTF8(patch, maxSummaryPatchSize) + "\n... (truncated)"
+	}
+	prompt.WriteString("## Diff\n\n```diff\n")
+	prompt.WriteString(patch)
+	prompt.WriteString("\n```\n\n")
+
+	prompt.WriteString("## Instructions\n\n")
+	prompt.WriteString("In 2-4 sentences of plain text, say what changed in this file and anything a reviewer\n")
+	prompt.WriteString("should look at closely. Name the functions and types involved. No Markdown, no preamble.\n")
+	return prompt.String()
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a UTF-8 character
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && n < len(s) && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n]
+}
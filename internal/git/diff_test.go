@@ -0,0 +1,221 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+func TestGitDiff(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	// Before the first commit everything is new
+	write("a.txt", "one\ntwo\n")
+	d, err := g.Diff(ctx, dir, "")
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(d.Files) != 1 || d.Files[0].Path != "a.txt" || d.Files[0].Additions != 2 {
+		t.Fatalf("Diff() on an unborn branch = %+v", d.Files)
+	}
+
+	run("add", "-A")
+	run("commit", "-m", "initial")
+	write("a.txt", "one\nthree\n") // Unstaged
+	write("b.txt", "staged\n")     // Staged
+	run("add", "b.txt")
+	write("dir/c.txt", "untracked\n") // Untracked
+	write(".gitignore", "*.log\n")    // Untracked
+	write("build.log", "ignored\n")   // Ignored
+
+	d, err = g.Diff(ctx, dir, "")
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	got := map[string]FileDiff{}
+	for _, f := range d.Files {
+		got[f.Path] = f
+	}
+	if len(got) != 4 {
+		t.Fatalf("Diff() changed %d files, want 4: %+v", len(got), d.Files)
+	}
+	if f := got["a.txt"]; f.Additions != 1 || f.Deletions != 1 || !strings.Contains(f.Patch, "+three") {
+		t.Errorf("a.txt = %+v", f)
+	}
+	if f := got["dir/c.txt"]; f.Additions != 1 || !strings.HasPrefix(f.Patch, "diff --git a/dir/c.txt b/dir/c.txt") {
+		t.Errorf("dir/c.txt = %+v", f)
+	}
+	if _, ok := got["b.txt"]; !ok {
+		t.Error("staged file missing from the diff")
+	}
+
+	only := d.Only([]string{"a.txt", "dir/"})
+	if len(only.Files) != 2 {
+		t.Errorf("Only() = %+v, want a.txt and dir/c.txt", only.Files)
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	raw := "diff --git a/old.go b/new.go\n" +
+		"similarity index 90%\n" +
+		"rename from old.go\n" +
+		"rename to new.go\n" +
+		"--- a/old.go\n" +
+		"+++ b/new.go\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" package main\n" +
+		"-// old\n" +
+		"+// new\n" +
+		"diff --git a/gone.txt b/gone.txt\n" +
+		"deleted file mode 100644\n" +
+		"--- a/gone.txt\n" +
+		"+++ /dev/null\n" +
+		"@@ -1 +0,0 @@\n" +
+		"--- a line that looked like a header\n" +
+		"diff --git a/logo.png b/logo.png\n" +
+		"Binary files a/logo.png and b/logo.png differ\n"
+
+	d := ParseDiff(raw)
+	if len(d.Files) != 3 {
+		t.Fatalf("ParseDiff() found %d files, want 3", len(d.Files))
+	}
+	if f := d.Files[0]; f.Path != "new.go" || f.OldPath != "old.go" || f.Additions != 1 || f.Deletions != 1 {
+		t.Errorf("rename = %+v", f)
+	}
+	if f := d.Files[1]; f.Path != "gone.txt" || f.Deletions != 1 || f.Additions != 0 {
+		t.Errorf("deletion = %+v", f)
+	}
+	if f := d.Files[2]; f.Path != "logo.png" || !f.Binary {
+		t.Errorf("binary = %+v", f)
+	}
+	if d.String() != raw {
+		t.Error("String() should reproduce the parsed diff")
+	}
+}
+
+// TestDiffSummarizerRender checks that oversized files are summarized, once
+func TestDiffSummarizerRender(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id": "msg_1", "type": "message", "role": "assistant", "model": "test-model",
+			"content":     []map[string]string{{"type": "text", "text": "Rewrites the generated table."}},
+			"stop_reason": "end_turn",
+			"usage":       map[string]int{"input_tokens": 1, "output_tokens": 1},
+		})
+	}))
+	defer server.Close()
+
+	small := "diff --git a/small.go b/small.go\n--- a/small.go\n+++ b/small.go\n@@ -1 +1 @@\n-a\n+b\n"
+	large := "diff --git a/table.go b/table.go\n--- a/table.go\n+++ b/table.go\n@@ -1,500 +1,500 @@\n" +
+		strings.Repeat("-old row\n+new row\n", 500)
+	d := ParseDiff(small + large)
+
+	client := anthropic.NewClient(option.WithAPIKey("test"), option.WithBaseURL(server.URL), option.WithMaxRetries(0))
+	diffs := NewMessageGenerator(&client, "test-model").Diffs()
+	ctx := context.Background()
+
+	if got := diffs.Render(ctx, d, 0); got != small+large {
+		t.Error("an unlimited budget should return the whole diff")
+	}
+
+	rendered := diffs.Render(ctx, d, 1000)
+	if len(rendered) > 1000 {
+		t.Errorf("rendered %d bytes, budget was 1000", len(rendered))
+	}
+	if !strings.Contains(rendered, small) {
+		t.Errorf("small patch should be kept:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, "500 additions, 500 deletions") || !strings.Contains(rendered, "Rewrites the generated table.") {
+		t.Errorf("large patch should be summarized:\n%s", rendered)
+	}
+
+	// The same patch in another prompt reuses the summary
+	_ = diffs.Render(ctx, ParseDiff(large), 500)
+	if requests != 1 {
+		t.Errorf("expected 1 summary request, got %d", requests)
+	}
+
+	// Without AI, files are summarized by line counts
+	rendered = NewDiffSummarizer(nil).Render(ctx, d, 1000)
+	if !strings.Contains(rendered, "500 lines added, 500 lines removed.") {
+		t.Errorf("expected a line count summary:\n%s", rendered)
+	}
+}
+
+// TestDiffCompress checks that generated files are left out and unchanged
+// lines are cut down around each change
+func TestDiffCompress(t *testing.T) {
+	unchanged := func(from, to int) string {
+		var lines strings.Builder
+		for i := from; i <= to; i++ {
+			lines.WriteString(" line " + strconv.Itoa(i) + "\n")
+		}
+		return lines.String()
+	}
+	code := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,17 +1,17 @@\n" +
+		unchanged(1, 3) + "-old 4\n+new 4\n" + unchanged(5, 12) + "-old 13\n+new 13\n" + unchanged(14, 16) +
+		" line 17\n\\ No newline at end of file\n"
+	generated := "diff --git a/api/api.pb.go b/api/api.pb.go\n--- a/api/api.pb.go\n+++ b/api/api.pb.go\n@@ -1 +1 @@\n-a\n+b\n"
+	header := "diff --git a/gen.go b/gen.go\n--- /dev/null\n+++ b/gen.go\n@@ -0,0 +1,2 @@\n" +
+		"+// Code generated by stringer. DO NOT EDIT.\n+package main\n"
+	vendored := "diff --git a/vendor/x/x.go b/vendor/x/x.go\n--- a/vendor/x/x.go\n+++ b/vendor/x/x.go\n@@ -1 +1 @@\n-a\n+b\n"
+
+	d := ParseDiff(code + generated + header + vendored).Compress()
+	if len(d.Files) != 4 {
+		t.Fatalf("Compress() kept %d files, want 4", len(d.Files))
+	}
+
+	want := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,17 +1,17 @@\n" +
+		" line 3\n-old 4\n+new 4\n line 5\n[... 6 unchanged lines]\n line 12\n-old 13\n+new 13\n line 14\n"
+	if got := d.Files[0].Patch; got != want {
+		t.Errorf("compressed patch = %q, want %q", got, want)
+	}
+	if f := d.Files[0]; f.Additions != 2 || f.Deletions != 2 {
+		t.Errorf("line counts changed: %+v", f)
+	}
+
+	for _, f := range d.Files[1:] {
+		if !strings.Contains(f.Patch, "[vendored or generated file: content omitted]") {
+			t.Errorf("%s should be omitted, got %q", f.Path, f.Patch)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DiscardChanges reverts uncommitted changes to paths: files in HEAD are
+// restored (staged and unstaged changes alike) and files that aren't are
+// unstaged and deleted.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) DiscardChanges(ctx context.Context, repoPath string, paths []string) error {
+	var tracked, added []string
+	for _, p := range paths {
+		p = strings.TrimSuffix(p, "/")
+		if p == "" || filepath.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+			return fmt.Errorf("invalid path %q", p)
+		}
+		cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "cat-file", "-e", "HEAD:"+p)
+		if cmd.Run() == nil {
+			tracked = append(tracked, p)
+		} else {
+			added = append(added, p)
+		}
+	}
+
+	if len(tracked) > 0 {
+		args := append([]string{"-C", repoPath, "checkout", "HEAD", "--"}, tracked...)
+		if output, err := exec.CommandContext(ctx, g.gitPath, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to restore %s: %w\nOutput: %s", strings.Join(tracked, ", "), err, output)
+		}
+	}
+	if len(added) > 0 {
+		args := append([]string{"-C", repoPath, "rm", "-r", "-q", "--cached", "--ignore-unmatch", "--"}, added...)
+		if output, err := exec.CommandContext(ctx, g.gitPath, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to unstage %s: %w\nOutput: %s", strings.Join(added, ", "), err, output)
+		}
+		for _, p := range added {
+			if err := os.RemoveAll(filepath.Join(repoPath, p)); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
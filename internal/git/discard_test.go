@@ -0,0 +1,75 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiscardChanges(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+	write("services/payments/pay.go", "package payments\n")
+	write("services/billing/bill.go", "package billing\n")
+	run("add", "-A")
+	run("commit", "-m", "initial")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	// Modified, staged and untracked files outside the kept path
+	write("services/payments/pay.go", "package payments\n\n// in scope\n")
+	write("services/billing/bill.go", "package billing\n\n// out of scope\n")
+	write("services/billing/new.go", "package billing\n")
+	run("add", "services/billing/new.go")
+	write("services/billing/extra/untracked.go", "package extra\n")
+
+	err = g.DiscardChanges(ctx, dir, []string{
+		"services/billing/bill.go",
+		"services/billing/new.go",
+		"services/billing/extra/",
+	})
+	if err != nil {
+		t.Fatalf("DiscardChanges() failed: %v", err)
+	}
+	if status := run("status", "--porcelain"); status != "M services/payments/pay.go" {
+		t.Errorf("status after discarding = %q, want only the in-scope change", status)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "services/billing/extra")); !os.IsNotExist(err) {
+		t.Errorf("untracked directory was not deleted: %v", err)
+	}
+
+	for _, bad := range []string{"/etc/passwd", "../outside", ".."} {
+		if err := g.DiscardChanges(ctx, dir, []string{bad}); err == nil {
+			t.Errorf("DiscardChanges(%q) = nil, want an error", bad)
+		}
+	}
+}
@@ -2,13 +2,16 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
 )
 
 // EventTracker wraps GitOperations and emits events to the event store
@@ -122,6 +125,15 @@ func (et *EventTracker) CommitChanges(ctx context.Context, repoPath string, opts
 	if len(opts.CoAuthors) > 0 {
 		eventData["co_authors"] = opts.CoAuthors
 	}
+	if len(opts.Trailers) > 0 {
+		eventData["trailers"] = opts.Trailers
+	}
+	if opts.SignFormat != "" {
+		eventData["signed"] = opts.SignFormat
+	}
+	if opts.CommitterName != "" {
+		eventData["committer"] = opts.CommitterName
+	}
 
 	if eventErr := et.emitEvent(ctx, severity, message, eventData); eventErr != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
@@ -243,6 +255,380 @@ func (et *EventTracker) ValidateConflictResolution(ctx context.Context, repoPath
 	return resolved, err
 }
 
+// Snapshot snapshots the working tree and tracks the operation
+func (et *EventTracker) Snapshot(ctx context.Context, repoPath string) (*types.WorkspaceSnapshot, error) {
+	snapshot, err := et.git.Snapshot(ctx, repoPath)
+
+	// Track snapshot operation
+	eventData := map[string]interface{}{
+		"command": "snapshot",
+		"success": err == nil,
+	}
+	if snapshot != nil {
+		eventData["commit_sha"] = snapshot.CommitSHA
+		eventData["dirty_hash"] = snapshot.DirtyHash
+	}
+
+	if eventErr := et.emitEvent(ctx, events.SeverityInfo, "Workspace snapshot", eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+
+	return snapshot, err
+}
+
+// RemoteURL returns a remote's URL (not tracked; it's a read-only lookup)
+func (et *EventTracker) RemoteURL(ctx context.Context, repoPath, remote string) (string, error) {
+	return et.git.RemoteURL(ctx, repoPath, remote)
+}
+
+// Push pushes a branch or tag and tracks the operation
+func (et *EventTracker) Push(ctx context.Context, repoPath string, opts PushOptions) error {
+	err := et.git.Push(ctx, repoPath, opts)
+
+	var blocked *PushBlockedError
+	if errors.As(err, &blocked) {
+		et.emitPushBlocked(ctx, blocked)
+		return err
+	}
+
+	// Track push operation (never the token)
+	severity := events.SeverityInfo
+	message := fmt.Sprintf("Pushed %s to %s", opts.name(), opts.Remote)
+	if err != nil {
+		severity = events.SeverityError
+		message = fmt.Sprintf("Failed to push %s: %v", opts.name(), err)
+	}
+	eventData := map[string]interface{}{
+		"command": "push",
+		"success": err == nil,
+		"remote":  opts.Remote,
+		"branch":  opts.Branch,
+		"tag":     opts.Tag,
+	}
+
+	if eventErr := et.emitEvent(ctx, severity, message, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+
+	return err
+}
+
+// Fetch fetches a branch and tracks the operation
+func (et *EventTracker) Fetch(ctx context.Context, repoPath string, opts PushOptions) error {
+	err := et.git.Fetch(ctx, repoPath, opts)
+
+	// Track fetch operation (never the token)
+	severity := events.SeverityInfo
+	message := fmt.Sprintf("Fetched %s from %s", opts.Branch, opts.Remote)
+	if err != nil {
+		severity = events.SeverityError
+		message = fmt.Sprintf("Failed to fetch %s: %v", opts.Branch, err)
+	}
+	eventData := map[string]interface{}{
+		"command": "fetch",
+		"success": err == nil,
+		"remote":  opts.Remote,
+		"branch":  opts.Branch,
+	}
+
+	if eventErr := et.emitEvent(ctx, severity, message, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+
+	return err
+}
+
+// CheckPush runs pre-push checks, tracking the push they block
+func (et *EventTracker) CheckPush(ctx context.Context, repoPath string, opts PushOptions, checks PushChecks) error {
+	err := et.git.CheckPush(ctx, repoPath, opts, checks)
+
+	var blocked *PushBlockedError
+	if errors.As(err, &blocked) {
+		et.emitPushBlocked(ctx, blocked)
+	}
+	return err
+}
+
+// emitPushBlocked stores a push blocked event
+func (et *EventTracker) emitPushBlocked(ctx context.Context, blocked *PushBlockedError) {
+	violations := make([]string, len(blocked.Violations))
+	for i, v := range blocked.Violations {
+		violations[i] = v.String()
+	}
+	event := &events.AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       events.EventTypePushBlocked,
+		Timestamp:  time.Now(),
+		IssueID:    et.issueID,
+		ExecutorID: et.executorID,
+		AgentID:    et.agentID,
+		Severity:   events.SeverityWarning,
+		Message:    fmt.Sprintf("Push of %s to %s blocked: %d pre-push check(s) failed", blocked.Branch, blocked.Remote, len(violations)),
+	}
+	if err := event.SetPushBlockedData(events.PushBlockedData{
+		Remote:     blocked.Remote,
+		Branch:     blocked.Branch,
+		Violations: violations,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to set push blocked data: %v\n", err)
+	}
+	if err := et.store.StoreAgentEvent(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store push blocked event: %v\n", err)
+	}
+}
+
+// Revert reverts commits and tracks the operation
+func (et *EventTracker) Revert(ctx context.Context, repoPath string, commits []string, message string) (string, error) {
+	revertCommit, err := et.git.Revert(ctx, repoPath, commits, message)
+
+	severity := events.SeverityInfo
+	msg := fmt.Sprintf("Reverted %s", strings.Join(commits, ", "))
+	if err != nil {
+		severity = events.SeverityError
+		msg = fmt.Sprintf("Failed to revert %s: %v", strings.Join(commits, ", "), err)
+	}
+	eventData := map[string]interface{}{
+		"command":       "revert",
+		"success":       err == nil,
+		"commits":       commits,
+		"revert_commit": revertCommit,
+	}
+
+	if eventErr := et.emitEvent(ctx, severity, msg, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+
+	return revertCommit, err
+}
+
+// IsAncestor checks commit ancestry (not tracked; it's a read-only lookup)
+func (et *EventTracker) IsAncestor(ctx context.Context, repoPath, commit string) (bool, error) {
+	return et.git.IsAncestor(ctx, repoPath, commit)
+}
+
+// Diff returns the working tree's changes relative to base (not tracked)
+func (et *EventTracker) Diff(ctx context.Context, repoPath, base string) (*Diff, error) {
+	return et.git.Diff(ctx, repoPath, base)
+}
+
+// Stash saves uncommitted changes and tracks the operation
+func (et *EventTracker) Stash(ctx context.Context, repoPath, message string) (string, error) {
+	stash, err := et.git.Stash(ctx, repoPath, message)
+	if err == nil && stash == "" {
+		return stash, nil // Nothing stashed
+	}
+
+	severity := events.SeverityInfo
+	eventMessage := fmt.Sprintf("Git stash successful: %s", stash[:min(8, len(stash))])
+	eventData := map[string]interface{}{
+		"command": "git",
+		"args":    []string{"stash", "push", "--include-untracked", "-m", message},
+		"success": err == nil,
+	}
+	if err != nil {
+		severity = events.SeverityError
+		eventMessage = fmt.Sprintf("Git stash failed: %v", err)
+	} else {
+		eventData["stash"] = stash
+	}
+	if eventErr := et.emitEvent(ctx, severity, eventMessage, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+	return stash, err
+}
+
+// RestoreStash applies a stash and tracks the operation
+func (et *EventTracker) RestoreStash(ctx context.Context, repoPath, stash string) error {
+	err := et.git.RestoreStash(ctx, repoPath, stash)
+
+	severity := events.SeverityInfo
+	eventMessage := fmt.Sprintf("Git stash restored: %s", stash[:min(8, len(stash))])
+	eventData := map[string]interface{}{
+		"command": "git",
+		"args":    []string{"stash", "apply"},
+		"stash":   stash,
+		"success": err == nil,
+	}
+	if err != nil {
+		severity = events.SeverityWarning
+		eventMessage = fmt.Sprintf("Git stash restore failed: %v", err)
+	}
+	if eventErr := et.emitEvent(ctx, severity, eventMessage, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+	return err
+}
+
+// CheckoutBranch checks out a branch (not tracked)
+func (et *EventTracker) CheckoutBranch(ctx context.Context, repoPath, branch, startPoint string) error {
+	return et.git.CheckoutBranch(ctx, repoPath, branch, startPoint)
+}
+
+// DiscardChanges reverts uncommitted changes to paths and tracks the operation
+func (et *EventTracker) DiscardChanges(ctx context.Context, repoPath string, paths []string) error {
+	err := et.git.DiscardChanges(ctx, repoPath, paths)
+
+	severity := events.SeverityInfo
+	message := fmt.Sprintf("Git discard successful: %d path(s)", len(paths))
+	eventData := map[string]interface{}{
+		"command": "git",
+		"args":    append([]string{"checkout", "HEAD", "--"}, paths...),
+		"success": err == nil,
+	}
+	if err != nil {
+		severity = events.SeverityError
+		message = fmt.Sprintf("Git discard failed: %v", err)
+	}
+	if eventErr := et.emitEvent(ctx, severity, message, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+	return err
+}
+
+// CapturePatch returns the changes since base as a patch (not tracked; it only reads)
+func (et *EventTracker) CapturePatch(ctx context.Context, repoPath, base string) (string, error) {
+	return et.git.CapturePatch(ctx, repoPath, base)
+}
+
+// CheckPatch checks that a patch applies (not tracked; it changes nothing)
+func (et *EventTracker) CheckPatch(ctx context.Context, repoPath, patch string) error {
+	return et.git.CheckPatch(ctx, repoPath, patch)
+}
+
+// ApplyPatch applies a patch and tracks the operation
+func (et *EventTracker) ApplyPatch(ctx context.Context, repoPath, patch string) error {
+	err := et.git.ApplyPatch(ctx, repoPath, patch)
+
+	severity := events.SeverityInfo
+	message := fmt.Sprintf("Git apply successful: %d bytes", len(patch))
+	eventData := map[string]interface{}{
+		"command": "git",
+		"args":    []string{"apply", "--binary"},
+		"success": err == nil,
+	}
+	if err != nil {
+		severity = events.SeverityError
+		message = fmt.Sprintf("Git apply failed: %v", err)
+	}
+	if eventErr := et.emitEvent(ctx, severity, message, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+	return err
+}
+
+// ResolveRef resolves a revision to a commit (not tracked; it's a read-only lookup)
+func (et *EventTracker) ResolveRef(ctx context.Context, repoPath, ref string) (string, error) {
+	return et.git.ResolveRef(ctx, repoPath, ref)
+}
+
+// CherryPick cherry-picks commits and tracks the operation
+func (et *EventTracker) CherryPick(ctx context.Context, repoPath string, commits []string) (*CherryPickResult, error) {
+	result, err := et.git.CherryPick(ctx, repoPath, commits)
+
+	severity := events.SeverityInfo
+	message := fmt.Sprintf("Git cherry-pick successful: %d commit(s)", len(commits))
+	eventData := map[string]interface{}{
+		"command": "git",
+		"args":    append([]string{"cherry-pick", "-x"}, commits...),
+		"success": err == nil && result != nil && len(result.Conflicts) == 0,
+	}
+	switch {
+	case err != nil:
+		severity = events.SeverityError
+		message = fmt.Sprintf("Git cherry-pick failed: %v", err)
+	case len(result.Conflicts) > 0:
+		severity = events.SeverityWarning
+		message = fmt.Sprintf("Git cherry-pick has conflicts (%d files)", len(result.Conflicts))
+		eventData["has_conflicts"] = true
+		eventData["conflicted_files"] = result.Conflicts
+	default:
+		eventData["commit_hash"] = result.Commit
+	}
+	if eventErr := et.emitEvent(ctx, severity, message, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+	return result, err
+}
+
+// SuggestReviewers ranks recent authors of files (not tracked; it's a read-only lookup)
+func (et *EventTracker) SuggestReviewers(ctx context.Context, repoPath string, files []string, opts ReviewerOptions) ([]Reviewer, error) {
+	return et.git.SuggestReviewers(ctx, repoPath, files, opts)
+}
+
+// HeavyFiles finds LFS-tracked files and large binaries (not tracked; it's a read-only lookup)
+func (et *EventTracker) HeavyFiles(ctx context.Context, repoPath string, paths []string, maxBinarySize int64) ([]HeavyFile, error) {
+	return et.git.HeavyFiles(ctx, repoPath, paths, maxBinarySize)
+}
+
+// SubmoduleChanges lists changed submodules (not tracked; it's a read-only lookup)
+func (et *EventTracker) SubmoduleChanges(ctx context.Context, repoPath string) ([]SubmoduleChange, error) {
+	return et.git.SubmoduleChanges(ctx, repoPath)
+}
+
+// UpdateSubmodules initializes and updates submodules and tracks the operation
+func (et *EventTracker) UpdateSubmodules(ctx context.Context, repoPath string, keep []string) error {
+	err := et.git.UpdateSubmodules(ctx, repoPath, keep)
+
+	severity := events.SeverityInfo
+	message := "Git submodule update successful"
+	eventData := map[string]interface{}{
+		"command": "git",
+		"args":    []string{"submodule", "update", "--init", "--recursive"},
+		"keep":    keep,
+		"success": err == nil,
+	}
+	if err != nil {
+		severity = events.SeverityError
+		message = fmt.Sprintf("Git submodule update failed: %v", err)
+	}
+	if eventErr := et.emitEvent(ctx, severity, message, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+	return err
+}
+
+// ResetSubmodules resets submodule pointers and tracks the operation
+func (et *EventTracker) ResetSubmodules(ctx context.Context, repoPath string, paths []string) error {
+	err := et.git.ResetSubmodules(ctx, repoPath, paths)
+
+	severity := events.SeverityInfo
+	message := fmt.Sprintf("Git submodule reset successful: %d submodule(s)", len(paths))
+	eventData := map[string]interface{}{
+		"command": "git",
+		"args":    append([]string{"submodule", "update", "--init", "--recursive", "--"}, paths...),
+		"success": err == nil,
+	}
+	if err != nil {
+		severity = events.SeverityError
+		message = fmt.Sprintf("Git submodule reset failed: %v", err)
+	}
+	if eventErr := et.emitEvent(ctx, severity, message, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+	return err
+}
+
+// CreateTag creates an annotated tag and tracks the operation
+func (et *EventTracker) CreateTag(ctx context.Context, repoPath, name, message, ref string) error {
+	err := et.git.CreateTag(ctx, repoPath, name, message, ref)
+
+	severity := events.SeverityInfo
+	msg := fmt.Sprintf("Git tag successful: %s", name)
+	eventData := map[string]interface{}{
+		"command": "git",
+		"args":    []string{"tag", "-a", name, ref},
+		"success": err == nil,
+	}
+	if err != nil {
+		severity = events.SeverityError
+		msg = fmt.Sprintf("Git tag failed: %v", err)
+	}
+	if eventErr := et.emitEvent(ctx, severity, msg, eventData); eventErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store git event: %v\n", eventErr)
+	}
+	return err
+}
+
 // emitEvent creates and stores a git operation event
 func (et *EventTracker) emitEvent(ctx context.Context, severity events.EventSeverity, message string, data map[string]interface{}) error {
 	event := &events.AgentEvent{
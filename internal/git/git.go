@@ -57,8 +57,10 @@ func (g *Git) HasUncommittedChanges(ctx context.Context, repoPath string) (bool,
 // SECURITY: repoPath must be a validated, trusted path. This function
 // does not perform path validation or sandboxing.
 func (g *Git) GetStatus(ctx context.Context, repoPath string) (*Status, error) {
-	// Use git status --porcelain for machine-readable output
-	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "status", "--porcelain")
+	// Use git status --porcelain for machine-readable output. Untracked
+	// files are listed one by one, not collapsed into their directory, so
+	// path filters and scopes can tell them apart.
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "status", "--porcelain", "--untracked-files=all")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("git status failed in %s: %w", repoPath, err)
@@ -120,33 +122,77 @@ func (g *Git) CommitChanges(ctx context.Context, repoPath string, opts CommitOpt
 	}
 
 	// Stage changes if requested
-	if opts.AddAll {
-		addCmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "add", "-A")
+	if opts.AddAll || len(opts.Paths) > 0 {
+		addArgs := []string{"-C", repoPath, "add", "-A"}
+		if len(opts.Paths) > 0 {
+			addArgs = append(append(addArgs, "--"), opts.Paths...)
+		}
+		addCmd := exec.CommandContext(ctx, g.gitPath, addArgs...)
 		if err := addCmd.Run(); err != nil {
 			return "", fmt.Errorf("git add failed in %s: %w", repoPath, err)
 		}
 	}
 
-	// Build commit message with co-authors
+	// Build commit message with co-authors and trailers
 	message := opts.Message
-	if len(opts.CoAuthors) > 0 {
-		message += "\n"
+	if len(opts.CoAuthors) > 0 || len(opts.Trailers) > 0 {
+		// Join an existing trailer block (e.g. "Refs: vc-119") rather than
+		// starting a second one
+		trimmed := strings.TrimRight(message, "\n ")
+		if strings.Contains(trimmed, "\n\n") && endsWithTrailers(trimmed) {
+			message = trimmed
+		} else {
+			message += "\n"
+		}
 		for _, coAuthor := range opts.CoAuthors {
 			message += fmt.Sprintf("\nCo-Authored-By: %s", coAuthor)
 		}
+		for _, trailer := range opts.Trailers {
+			message += "\n" + trailer
+		}
 	}
 
 	// Build commit command
-	args := []string{"-C", repoPath, "commit", "-m", message}
+	args := []string{"-C", repoPath}
+	if opts.SignFormat != "" {
+		args = append(args, "-c", "gpg.format="+signingProgramFormat(opts.SignFormat))
+	}
+	args = append(args, "commit", "-m", message)
+	if opts.SignFormat != "" {
+		if opts.SigningKey != "" {
+			args = append(args, "--gpg-sign="+opts.SigningKey)
+		} else {
+			args = append(args, "--gpg-sign")
+		}
+	}
 	if opts.Author != "" {
 		args = append(args, "--author", opts.Author)
 	}
 	if opts.AllowEmpty {
 		args = append(args, "--allow-empty")
 	}
+	if opts.Amend {
+		args = append(args, "--amend")
+	}
+	if len(opts.Paths) > 0 {
+		args = append(append(args, "--"), opts.Paths...)
+	}
 
 	commitCmd := exec.CommandContext(ctx, g.gitPath, args...)
-	if err := commitCmd.Run(); err != nil {
+	if opts.CommitterName != "" || opts.CommitterEmail != "" {
+		commitCmd.Env = os.Environ()
+		if opts.CommitterName != "" {
+			commitCmd.Env = append(commitCmd.Env, "GIT_COMMITTER_NAME="+opts.CommitterName)
+		}
+		if opts.CommitterEmail != "" {
+			commitCmd.Env = append(commitCmd.Env, "GIT_COMMITTER_EMAIL="+opts.CommitterEmail)
+		}
+	}
+	if output, err := commitCmd.CombinedOutput(); err != nil {
+		if opts.SignFormat != "" {
+			// Signing failures (missing key, no agent) are only explained in the output
+			return "", fmt.Errorf("git commit failed in %s: %w\nOutput: %s", repoPath, err, output)
+		}
 		return "", fmt.Errorf("git commit failed in %s: %w", repoPath, err)
 	}
 
@@ -161,6 +207,15 @@ func (g *Git) CommitChanges(ctx context.Context, repoPath string, opts CommitOpt
 	return commitHash, nil
 }
 
+// signingProgramFormat maps a signing format to git's gpg.format value
+// ("gpg" is git's "openpgp")
+func signingProgramFormat(format string) string {
+	if format == "gpg" {
+		return "openpgp"
+	}
+	return format
+}
+
 // GetDiff returns the git diff output for the repository.
 // This can be used to provide context to the AI for commit message generation.
 // SECURITY: repoPath must be a validated, trusted path. This function
@@ -268,7 +323,11 @@ func (g *Git) Rebase(ctx context.Context, repoPath string, opts RebaseOptions) (
 	result.BaseBranch = opts.BaseBranch
 
 	// Perform the rebase
-	rebaseCmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "rebase", opts.BaseBranch)
+	args := []string{"-C", repoPath, "rebase"}
+	if opts.Autostash {
+		args = append(args, "--autostash")
+	}
+	rebaseCmd := exec.CommandContext(ctx, g.gitPath, append(args, opts.BaseBranch)...)
 	output, err := rebaseCmd.CombinedOutput()
 
 	if err != nil {
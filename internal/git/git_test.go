@@ -96,6 +96,7 @@ func TestGitOperations(t *testing.T) {
 			CoAuthors: []string{
 				"Claude <noreply@anthropic.com>",
 			},
+			Trailers:   []string{"VC-Execution: 42"},
 			AddAll:     true,
 			AllowEmpty: false,
 		}
@@ -140,6 +141,10 @@ func TestGitOperations(t *testing.T) {
 		if !strings.Contains(message, "Co-Authored-By: Claude <noreply@anthropic.com>") {
 			t.Errorf("Commit message doesn't contain co-author: %s", message)
 		}
+
+		if !strings.Contains(message, "Co-Authored-By: Claude <noreply@anthropic.com>\nVC-Execution: 42") {
+			t.Errorf("Commit message doesn't end with the trailer block: %s", message)
+		}
 	})
 
 	// Test 6: Modify file and commit again
@@ -173,6 +178,48 @@ func TestGitOperations(t *testing.T) {
 			t.Error("Expected non-empty commit hash")
 		}
 	})
+
+	// Test 7: Commit only some paths, then amend the commit
+	t.Run("CommitPathsAndAmend", func(t *testing.T) {
+		for name, content := range map[string]string{"keep.txt": "keep", "skip.txt": "skip"} {
+			if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+
+		first, err := git.CommitChanges(ctx, tmpDir, CommitOptions{Message: "test: add keep", Paths: []string{"keep.txt"}})
+		if err != nil {
+			t.Fatalf("CommitChanges failed: %v", err)
+		}
+		status, err := git.GetStatus(ctx, tmpDir)
+		if err != nil {
+			t.Fatalf("GetStatus failed: %v", err)
+		}
+		if len(status.Untracked) != 1 || status.Untracked[0] != "skip.txt" {
+			t.Errorf("Expected only skip.txt left uncommitted, got: %+v", status)
+		}
+
+		if err := os.WriteFile(filepath.Join(tmpDir, "keep.txt"), []byte("keep v2"), 0644); err != nil {
+			t.Fatalf("Failed to modify keep.txt: %v", err)
+		}
+		amended, err := git.CommitChanges(ctx, tmpDir, CommitOptions{Message: "test: add keep v2", Paths: []string{"keep.txt"}, Amend: true})
+		if err != nil {
+			t.Fatalf("CommitChanges with Amend failed: %v", err)
+		}
+		if amended == first {
+			t.Error("Expected amending to produce a new commit hash")
+		}
+
+		cmd := exec.Command("git", "log", "--format=%s")
+		cmd.Dir = tmpDir
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("git log failed: %v", err)
+		}
+		if strings.Contains(string(output), "test: add keep\n") || !strings.HasPrefix(string(output), "test: add keep v2\n") {
+			t.Errorf("Expected the commit to be replaced, got log:\n%s", output)
+		}
+	})
 }
 
 // TestGitNotAvailable tests behavior when git is not available
@@ -0,0 +1,163 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// binarySniffSize is how much of a file is read to decide whether it is
+// binary: like git, a NUL byte in the first 8000 bytes makes it binary
+const binarySniffSize = 8000
+
+// Reasons a file is too heavy to show in prompts
+const (
+	HeavyLFS    = "lfs"    // Tracked by Git LFS
+	HeavyBinary = "binary" // A large binary file
+)
+
+// HeavyFile is a file whose content doesn't belong in a prompt or in an
+// agent's changes: a Git LFS-tracked file or a large binary
+type HeavyFile struct {
+	Path   string
+	Reason string // HeavyLFS or HeavyBinary
+	Size   int64  // Size in the working tree, in bytes (0 if it doesn't exist)
+}
+
+// String describes the file, e.g. "assets/logo.psd (Git LFS)"
+func (h HeavyFile) String() string {
+	return fmt.Sprintf("%s (%s)", h.Path, h.kind())
+}
+
+// kind describes what makes the file heavy
+func (h HeavyFile) kind() string {
+	if h.Reason == HeavyLFS {
+		return "Git LFS"
+	}
+	return fmt.Sprintf("binary, %d KB", h.Size/1024)
+}
+
+// LFSPatterns returns the path patterns the repository's top-level
+// .gitattributes tracks with Git LFS (filter=lfs), in file order. A
+// repository without a .gitattributes has none.
+func LFSPatterns(repoPath string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".gitattributes"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			if attr == "filter=lfs" {
+				patterns = append(patterns, fields[0])
+				break
+			}
+		}
+	}
+	return patterns, nil
+}
+
+// HeavyFiles returns which of paths are tracked by Git LFS, according to the
+// repository's attributes, or are binary files in the working tree of at
+// least maxBinarySize bytes (0 = LFS files only). Directories and missing
+// files are only reported if LFS tracks them.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) HeavyFiles(ctx context.Context, repoPath string, paths []string, maxBinarySize int64) ([]HeavyFile, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	// check-attr -z prints "<path>\0filter\0<value>\0" per path
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "check-attr", "-z", "--stdin", "filter")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git check-attr failed in %s: %w", repoPath, err)
+	}
+	lfs := make(map[string]bool)
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "lfs" {
+			lfs[fields[i]] = true
+		}
+	}
+
+	var heavy []HeavyFile
+	for _, path := range paths {
+		var size int64
+		info, err := os.Stat(filepath.Join(repoPath, path))
+		if err == nil && info.Mode().IsRegular() {
+			size = info.Size()
+		}
+		switch {
+		case lfs[path]:
+			heavy = append(heavy, HeavyFile{Path: path, Reason: HeavyLFS, Size: size})
+		case maxBinarySize > 0 && size >= maxBinarySize && isBinaryFile(filepath.Join(repoPath, path)):
+			heavy = append(heavy, HeavyFile{Path: path, Reason: HeavyBinary, Size: size})
+		}
+	}
+	return heavy, nil
+}
+
+// isBinaryFile reports whether the start of a file has a NUL byte
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+	buf := make([]byte, binarySniffSize)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return false
+	}
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
+
+// Omit returns the diff with the patches of heavy files replaced by a line
+// saying their content was left out, so prompts still see that they changed
+func (d *Diff) Omit(heavy []HeavyFile) *Diff {
+	if len(heavy) == 0 {
+		return d
+	}
+	byPath := make(map[string]HeavyFile, len(heavy))
+	for _, h := range heavy {
+		byPath[h.Path] = h
+	}
+	omitted := &Diff{}
+	for _, f := range d.Files {
+		h, ok := byPath[f.Path]
+		if !ok && f.OldPath != "" {
+			h, ok = byPath[f.OldPath]
+		}
+		if ok {
+			f.Patch = fmt.Sprintf("diff --git a/%s b/%s\n[%s file: content omitted]\n", f.Path, f.Path, h.kind())
+		}
+		omitted.Files = append(omitted.Files, f)
+	}
+	return omitted
+}
+
+// Paths returns the paths of the files the diff changes
+func (d *Diff) Paths() []string {
+	paths := make([]string, len(d.Files))
+	for i, f := range d.Files {
+		paths[i] = f.Path
+	}
+	return paths
+}
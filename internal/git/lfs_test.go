@@ -0,0 +1,91 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeavyFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init").CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	write := func(name string, content []byte) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".gitattributes", []byte("# media\n*.psd filter=lfs diff=lfs merge=lfs -text\nassets/** filter=lfs diff=lfs merge=lfs -text\n*.go text\n"))
+	write("logo.psd", []byte("version https://git-lfs.github.com/spec/v1\n"))
+	write("assets/font.ttf", []byte("font"))
+	write("model.bin", append([]byte{0, 1, 2}, bytes.Repeat([]byte("x"), 4096)...))
+	write("small.bin", []byte{0, 1, 2})
+	write("data.csv", []byte(strings.Repeat("1,2,3\n", 1000)))
+	write("main.go", []byte("package main\n"))
+
+	patterns, err := LFSPatterns(dir)
+	if err != nil {
+		t.Fatalf("LFSPatterns() error = %v", err)
+	}
+	if !reflect.DeepEqual(patterns, []string{"*.psd", "assets/**"}) {
+		t.Errorf("LFSPatterns() = %v", patterns)
+	}
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+	paths := []string{"main.go", "logo.psd", "assets/font.ttf", "model.bin", "small.bin", "data.csv", "gone.psd"}
+	heavy, err := g.HeavyFiles(ctx, dir, paths, 1024)
+	if err != nil {
+		t.Fatalf("HeavyFiles() error = %v", err)
+	}
+	var got []string
+	for _, h := range heavy {
+		got = append(got, h.String())
+	}
+	want := []string{"logo.psd (Git LFS)", "assets/font.ttf (Git LFS)", "model.bin (binary, 4 KB)", "gone.psd (Git LFS)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HeavyFiles() = %v, want %v", got, want)
+	}
+
+	heavy, err = g.HeavyFiles(ctx, dir, paths, 0)
+	if err != nil {
+		t.Fatalf("HeavyFiles() error = %v", err)
+	}
+	if len(heavy) != 3 {
+		t.Errorf("HeavyFiles() without a binary limit = %v, want only the LFS files", heavy)
+	}
+}
+
+func TestDiffOmit(t *testing.T) {
+	raw := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-old\n+new\n" +
+		"diff --git a/logo.psd b/logo.psd\n--- a/logo.psd\n+++ b/logo.psd\n@@ -1,3 +1,3 @@\n-oid sha256:aaa\n+oid sha256:bbb\n"
+	d := ParseDiff(raw)
+	if !reflect.DeepEqual(d.Paths(), []string{"main.go", "logo.psd"}) {
+		t.Errorf("Paths() = %v", d.Paths())
+	}
+
+	omitted := d.Omit([]HeavyFile{{Path: "logo.psd", Reason: HeavyLFS}}).String()
+	if !strings.Contains(omitted, "+new") {
+		t.Errorf("Omit() dropped an ordinary file:\n%s", omitted)
+	}
+	if strings.Contains(omitted, "oid sha256") || !strings.Contains(omitted, "diff --git a/logo.psd b/logo.psd\n[Git LFS file: content omitted]\n") {
+		t.Errorf("Omit() kept the LFS file's content:\n%s", omitted)
+	}
+	if d.Omit(nil) != d {
+		t.Error("Omit(nil) should return the diff unchanged")
+	}
+}
@@ -9,28 +9,143 @@ import (
 	"github.com/steveyegge/vc/internal/ai"
 )
 
+// maxPromptDiffSize is the most diff text put in a commit message or PR
+// description prompt; larger diffs are partly summarized
+const maxPromptDiffSize = 10000
+
 // MessageGenerator generates commit messages using AI.
 type MessageGenerator struct {
-	client        *anthropic.Client
-	model         string
-	retryAttempts int
+	client             *anthropic.Client
+	model              string
+	retryAttempts      int
+	validationAttempts int             // Generations allowed to produce a valid commit message
+	diffs              *DiffSummarizer // Fits diffs into prompts
 }
 
 // NewMessageGenerator creates a new MessageGenerator.
 func NewMessageGenerator(client *anthropic.Client, model string) *MessageGenerator {
-	return &MessageGenerator{
-		client:        client,
-		model:         model,
-		retryAttempts: 3,
+	m := &MessageGenerator{
+		client:             client,
+		model:              model,
+		retryAttempts:      3,
+		validationAttempts: 3,
 	}
+	m.diffs = NewDiffSummarizer(m)
+	return m
+}
+
+// Diffs returns the generator's diff summarizer, so other prompts built
+// from the same change reuse its cached summaries
+func (m *MessageGenerator) Diffs() *DiffSummarizer {
+	return m.diffs
 }
 
-// GenerateCommitMessage generates a commit message using AI.
+// GenerateCommitMessage generates a commit message using AI, following the
+// request's commit template. The message is checked against the template
+// (missing trailers are simply added); if it breaks the rules, the model is
+// asked again with the violations, and an error is returned if it never
+// gets them right.
 func (m *MessageGenerator) GenerateCommitMessage(ctx context.Context, req CommitMessageRequest) (*CommitMessageResponse, error) {
-	prompt := m.buildPrompt(req)
+	req.Diff = m.fitDiff(ctx, req.Diff)
+	template := req.Template
+	if template == nil {
+		template = DefaultCommitTemplate()
+	}
+
+	var previous *CommitMessageResponse
+	var violations []string
+
+	for attempt := 1; attempt <= m.validationAttempts; attempt++ {
+		prompt := m.buildPrompt(req, template)
+		if previous != nil {
+			prompt += buildViolationFeedback(previous, violations)
+		}
+
+		responseText, err := m.complete(ctx, "commit-message", prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate commit message: %w", err)
+		}
+
+		// Parse the JSON response
+		parseResult := ai.Parse[CommitMessageResponse](responseText, ai.ParseOptions{
+			Context:   "commit message response",
+			LogErrors: ai.BoolPtr(true),
+		})
+
+		if !parseResult.Success {
+			return nil, fmt.Errorf("failed to parse commit message response: %s (response: %s)", parseResult.Error, responseText)
+		}
+
+		msg := parseResult.Data
+		msg.Subject = strings.TrimSpace(msg.Subject)
+		msg.Body = template.WithTrailers(strings.TrimSpace(msg.Body), req)
+		violations = template.Check(msg, req)
+		if len(violations) == 0 {
+			return &msg, nil
+		}
+		previous = &msg
+	}
+
+	return nil, fmt.Errorf("generated commit message %q is invalid after %d attempts: %s",
+		previous.Subject, m.validationAttempts, strings.Join(violations, "; "))
+}
+
+// fitDiff fits raw diff output into maxPromptDiffSize, summarizing the files
+// that don't fit
+func (m *MessageGenerator) fitDiff(ctx context.Context, raw string) string {
+	d := ParseDiff(raw)
+	if len(d.Files) == 0 {
+		// Not git diff output; all we can do is cut it
+		if len(raw) > maxPromptDiffSize {
+			return truncateUTF8(raw, maxPromptDiffSize) + "\n... (truncated)"
+		}
+		return raw
+	}
+	return m.diffs.Render(ctx, d, maxPromptDiffSize)
+}
+
+// buildViolationFeedback tells the model what was wrong with its last message
+func buildViolationFeedback(previous *CommitMessageResponse, violations []string) string {
+	var feedback strings.Builder
+	feedback.WriteString("\n## Previous Attempt\n\n")
+	feedback.WriteString(fmt.Sprintf("Your previous subject was: %s\n\n", previous.Subject))
+	feedback.WriteString("It was rejected because:\n")
+	for _, violation := range violations {
+		feedback.WriteString(fmt.Sprintf("- %s\n", violation))
+	}
+	feedback.WriteString("\nGenerate a corrected commit message in the same JSON format.\n")
+	return feedback.String()
+}
 
+// GeneratePRDescription generates a pull request title and description using AI.
+func (m *MessageGenerator) GeneratePRDescription(ctx context.Context, req PRDescriptionRequest) (*PRDescriptionResponse, error) {
+	req.Diff = m.fitDiff(ctx, req.Diff)
+	prompt := m.buildPRPrompt(req)
+
+	responseText, err := m.complete(ctx, "pr-description", prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PR description: %w", err)
+	}
+
+	parseResult := ai.Parse[PRDescriptionResponse](responseText, ai.ParseOptions{
+		Context:   "PR description response",
+		LogErrors: ai.BoolPtr(true),
+	})
+
+	if !parseResult.Success {
+		return nil, fmt.Errorf("failed to parse PR description response: %s (response: %s)", parseResult.Error, responseText)
+	}
+	if strings.TrimSpace(parseResult.Data.Title) == "" || strings.TrimSpace(parseResult.Data.Body) == "" {
+		return nil, fmt.Errorf("PR description response is missing a title or body")
+	}
+
+	return &parseResult.Data, nil
+}
+
+// complete sends prompt to the model, with retries, and returns the text of the reply.
+func (m *MessageGenerator) complete(ctx context.Context, operation, prompt string) (string, error) {
 	var response *anthropic.Message
-	err := m.retryWithBackoff(ctx, "commit-message", func(attemptCtx context.Context) error {
+	err := m.retryWithBackoff(ctx, operation, func(attemptCtx context.Context) error {
 		resp, apiErr := m.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
 			Model:     anthropic.Model(m.model),
 			MaxTokens: 2048,
@@ -44,9 +159,8 @@ func (m *MessageGenerator) GenerateCommitMessage(ctx context.Context, req Commit
 		response = resp
 		return nil
 	})
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate commit message: %w", err)
+		return "", err
 	}
 
 	// Extract text from response
@@ -56,29 +170,84 @@ func (m *MessageGenerator) GenerateCommitMessage(ctx context.Context, req Commit
 			responseText += block.Text
 		}
 	}
+	return responseText, nil
+}
 
-	// Parse the JSON response
-	parseResult := ai.Parse[CommitMessageResponse](responseText, ai.ParseOptions{
-		Context:   "commit message response",
-		LogErrors: ai.BoolPtr(true),
-	})
+// buildPRPrompt constructs the prompt for pull request description generation.
+func (m *MessageGenerator) buildPRPrompt(req PRDescriptionRequest) string {
+	var prompt strings.Builder
 
-	if !parseResult.Success {
-		return nil, fmt.Errorf("failed to parse commit message response: %s (response: %s)", parseResult.Error, responseText)
+	prompt.WriteString("You are writing a pull request description for changes made by an AI-supervised coding agent.\n\n")
+
+	prompt.WriteString("## Issue Context\n\n")
+	prompt.WriteString(fmt.Sprintf("**Issue ID**: %s\n", req.IssueID))
+	prompt.WriteString(fmt.Sprintf("**Title**: %s\n", req.IssueTitle))
+	if req.IssueDescription != "" {
+		prompt.WriteString(fmt.Sprintf("**Description**: %s\n", req.IssueDescription))
+	}
+	if req.AcceptanceCriteria != "" {
+		prompt.WriteString(fmt.Sprintf("**Acceptance Criteria**: %s\n", req.AcceptanceCriteria))
 	}
+	prompt.WriteString("\n")
 
-	return &parseResult.Data, nil
+	if len(req.Commits) > 0 {
+		prompt.WriteString("## Commits\n\n")
+		for _, commit := range req.Commits {
+			prompt.WriteString(fmt.Sprintf("- %s\n", commit))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("## Changed Files\n\n")
+	if len(req.ChangedFiles) > 0 {
+		for _, file := range req.ChangedFiles {
+			prompt.WriteString(fmt.Sprintf("- %s\n", file))
+		}
+	} else {
+		prompt.WriteString("(no files listed)\n")
+	}
+	prompt.WriteString("\n")
+
+	if req.Diff != "" {
+		prompt.WriteString("## Diff\n\n")
+		prompt.WriteString("```diff\n")
+		prompt.WriteString(req.Diff) // Already fitted to maxPromptDiffSize
+		prompt.WriteString("\n```\n\n")
+	}
+
+	prompt.WriteString("## Instructions\n\n")
+	prompt.WriteString("Generate a pull request with:\n")
+	prompt.WriteString("1. **Title**: One-line summary (72 chars max) in imperative mood, without the issue ID\n")
+	prompt.WriteString("2. **Body**: Markdown for reviewers: what changed and why, anything that needs a careful look,\n")
+	prompt.WriteString("   and how the change was verified. Don't repeat the file list or invent test results.\n\n")
+
+	prompt.WriteString("Respond with JSON:\n")
+	prompt.WriteString("```json\n")
+	prompt.WriteString("{\n")
+	prompt.WriteString("  \"title\": \"Concise summary of the change\",\n")
+	prompt.WriteString("  \"body\": \"What changed and why.\\n\\nWhat reviewers should look at.\"\n")
+	prompt.WriteString("}\n")
+	prompt.WriteString("```\n")
+
+	return prompt.String()
 }
 
 // buildPrompt constructs the prompt for commit message generation.
-func (m *MessageGenerator) buildPrompt(req CommitMessageRequest) string {
+func (m *MessageGenerator) buildPrompt(req CommitMessageRequest, template *CommitTemplate) string {
 	var prompt strings.Builder
 
 	prompt.WriteString("You are a commit message generator for an AI-supervised coding agent.\n\n")
-	prompt.WriteString("Generate a clear, concise commit message following conventional commits format.\n\n")
+	if template.Format == CommitFormatConventional {
+		prompt.WriteString("Generate a clear, concise commit message following conventional commits format.\n\n")
+	} else {
+		prompt.WriteString("Generate a clear, concise commit message following this repository's conventions.\n\n")
+	}
 
 	prompt.WriteString("## Issue Context\n\n")
 	prompt.WriteString(fmt.Sprintf("**Issue ID**: %s\n", req.IssueID))
+	if ticket := template.Ticket(req); ticket != req.IssueID {
+		prompt.WriteString(fmt.Sprintf("**Ticket**: %s\n", ticket))
+	}
 	prompt.WriteString(fmt.Sprintf("**Title**: %s\n", req.IssueTitle))
 	if req.IssueDescription != "" {
 		prompt.WriteString(fmt.Sprintf("**Description**: %s\n", req.IssueDescription))
@@ -98,34 +267,73 @@ func (m *MessageGenerator) buildPrompt(req CommitMessageRequest) string {
 	if req.Diff != "" {
 		prompt.WriteString("## Diff\n\n")
 		prompt.WriteString("```diff\n")
-		// Truncate diff if too large (keep first 10000 chars)
-		diff := req.Diff
-		if len(diff) > 10000 {
-			diff = diff[:10000] + "\n... (truncated)"
-		}
-		prompt.WriteString(diff)
+		prompt.WriteString(req.Diff) // Already fitted to maxPromptDiffSize
 		prompt.WriteString("\n```\n\n")
 	}
 
+	prefix := template.SubjectPrefixFor(req)
+	trailers := template.TrailersFor(req)
+	example := prefix + "concise description"
+
 	prompt.WriteString("## Instructions\n\n")
 	prompt.WriteString("Generate a commit message with:\n")
-	prompt.WriteString("1. **Subject**: One-line summary (50 chars max), format: `type(scope): description`\n")
-	prompt.WriteString("   - Types: feat, fix, docs, refactor, test, chore\n")
-	prompt.WriteString("   - Include issue ID in subject: e.g., `feat(git): implement auto-commit (vc-119)`\n")
-	prompt.WriteString("2. **Body**: Detailed explanation of what changed and why (wrap at 72 chars)\n")
+	if template.Format == CommitFormatConventional {
+		example = prefix + "feat(scope): concise description"
+		prompt.WriteString(fmt.Sprintf("1. **Subject**: One-line summary (aim for 50 chars, %d max), format: `%stype(scope): description`\n", template.MaxSubjectLength, prefix))
+		prompt.WriteString(fmt.Sprintf("   - Types: %s\n", strings.Join(template.Types, ", ")))
+		prompt.WriteString("   - Scope is optional, lowercase; no period at the end\n")
+		prompt.WriteString(fmt.Sprintf("   - e.g., `%sfeat(git): implement auto-commit`\n", prefix))
+	} else {
+		prompt.WriteString(fmt.Sprintf("1. **Subject**: One-line summary (aim for 50 chars, %d max) in imperative mood, no period at the end\n", template.MaxSubjectLength))
+		if prefix != "" {
+			prompt.WriteString(fmt.Sprintf("   - Start it with `%s`\n", prefix))
+		}
+	}
+	prompt.WriteString("2. **Body**: Detailed explanation of what changed and why (wrap at 72 chars)")
+	if len(template.Sections) > 0 {
+		var headings []string
+		for _, section := range template.Sections {
+			headings = append(headings, "`"+strings.TrimSuffix(section, ":")+":`")
+		}
+		prompt.WriteString(fmt.Sprintf(", with the sections %s, each heading on a line of its own", strings.Join(headings, ", ")))
+	}
+	switch len(trailers) {
+	case 0:
+	case 1:
+		prompt.WriteString(fmt.Sprintf(", ending with the trailer `%s`", trailers[0]))
+	default:
+		prompt.WriteString(fmt.Sprintf(", ending with the trailers `%s`", strings.Join(trailers, "`, `")))
+	}
+	prompt.WriteString("\n")
 	prompt.WriteString("3. **Reasoning**: Brief explanation of your commit message choice\n\n")
 
 	prompt.WriteString("Guidelines:\n")
 	prompt.WriteString("- Focus on the 'why' not just the 'what'\n")
 	prompt.WriteString("- Be specific about the functionality added/changed\n")
 	prompt.WriteString("- Use imperative mood: 'add feature' not 'added feature'\n")
-	prompt.WriteString("- Keep subject concise, put details in body\n\n")
+	prompt.WriteString("- Keep subject concise, put details in body\n")
+	if template.Instructions != "" {
+		prompt.WriteString(fmt.Sprintf("- %s\n", strings.TrimSpace(template.Instructions)))
+	}
+	prompt.WriteString("\n")
+
+	body := "Detailed explanation of changes.\\n\\nWhy this change was needed."
+	if len(template.Sections) > 0 {
+		var sections []string
+		for _, section := range template.Sections {
+			sections = append(sections, strings.TrimSuffix(section, ":")+":\\n...")
+		}
+		body = strings.Join(sections, "\\n\\n")
+	}
+	if len(trailers) > 0 {
+		body += "\\n\\n" + strings.Join(trailers, "\\n")
+	}
 
 	prompt.WriteString("Respond with JSON:\n")
 	prompt.WriteString("```json\n")
 	prompt.WriteString("{\n")
-	prompt.WriteString("  \"subject\": \"feat(scope): concise description (vc-XXX)\",\n")
-	prompt.WriteString("  \"body\": \"Detailed explanation of changes.\\n\\nWhy this change was needed.\",\n")
+	prompt.WriteString(fmt.Sprintf("  \"subject\": \"%s\",\n", example))
+	prompt.WriteString(fmt.Sprintf("  \"body\": \"%s\",\n", body))
 	prompt.WriteString("  \"reasoning\": \"Why I chose this message\"\n")
 	prompt.WriteString("}\n")
 	prompt.WriteString("```\n")
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CapturePatch returns everything that changed in repoPath's working tree
+// since base (HEAD if empty) as a binary-safe patch: commits made since
+// base, staged and unstaged changes, and untracked files. Returns "" if
+// nothing changed. The repository's index is left alone.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) CapturePatch(ctx context.Context, repoPath, base string) (string, error) {
+	if base == "" {
+		base = "HEAD"
+	}
+
+	// Stage the whole working tree into a scratch index, so untracked files
+	// are part of the patch without touching the real index
+	index, err := os.CreateTemp("", "vc-patch-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	indexPath := index.Name()
+	_ = index.Close()
+	defer func() { _ = os.Remove(indexPath) }()
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+indexPath)
+	for _, args := range [][]string{{"read-tree", "HEAD"}, {"add", "-A"}} {
+		cmd := exec.CommandContext(ctx, g.gitPath, append([]string{"-C", repoPath}, args...)...)
+		cmd.Env = env
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git %s failed in %s: %w\nOutput: %s", args[0], repoPath, err, output)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "diff", "--cached", "--binary", "--no-color", "--no-ext-diff", "-M", base, "--")
+	cmd.Env = env
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff %s failed in %s: %w", base, repoPath, err)
+	}
+	return string(output), nil
+}
+
+// CheckPatch reports whether a patch from CapturePatch applies cleanly to
+// repoPath's working tree, without applying it.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) CheckPatch(ctx context.Context, repoPath, patch string) error {
+	return g.applyPatch(ctx, repoPath, patch, "--check")
+}
+
+// ApplyPatch applies a patch from CapturePatch to repoPath's working tree.
+// Nothing is changed if any part of it doesn't apply.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) ApplyPatch(ctx context.Context, repoPath, patch string) error {
+	return g.applyPatch(ctx, repoPath, patch)
+}
+
+// applyPatch runs git apply on patch, passed through a temporary file
+func (g *Git) applyPatch(ctx context.Context, repoPath, patch string, flags ...string) error {
+	if strings.TrimSpace(patch) == "" {
+		return nil
+	}
+	dir, err := os.MkdirTemp("", "vc-patch-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	patchFile := filepath.Join(dir, "proposal.patch")
+	if err := os.WriteFile(patchFile, []byte(patch), 0600); err != nil {
+		return fmt.Errorf("failed to write patch: %w", err)
+	}
+
+	args := append([]string{"-C", repoPath, "apply", "--binary", "--whitespace=nowarn"}, flags...)
+	cmd := exec.CommandContext(ctx, g.gitPath, append(args, patchFile)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("patch does not apply in %s: %w\nOutput: %s", repoPath, err, output)
+	}
+	return nil
+}
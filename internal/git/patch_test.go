@@ -0,0 +1,99 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCaptureAndApplyPatch(t *testing.T) {
+	ctx := context.Background()
+	src := t.TempDir()
+	dst := t.TempDir()
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(dir, name string, content []byte) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run(src, "init", "--initial-branch=main")
+	run(src, "config", "user.name", "Test User")
+	run(src, "config", "user.email", "test@example.com")
+	write(src, "README.md", []byte("# Test\n"))
+	write(src, "old.txt", []byte("remove me\n"))
+	run(src, "add", "-A")
+	run(src, "commit", "-m", "initial")
+	base := run(src, "rev-parse", "HEAD")
+	run(dst, "clone", "-q", src, ".")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	if patch, err := g.CapturePatch(ctx, src, ""); err != nil || patch != "" {
+		t.Fatalf("CapturePatch() on a clean tree = %q, %v; want no patch", patch, err)
+	}
+
+	// A commit, an unstaged edit, a deletion, and untracked text and binary files
+	write(src, "committed.go", []byte("package main\n"))
+	run(src, "add", "committed.go")
+	run(src, "commit", "-m", "agent commit")
+	write(src, "README.md", []byte("# Test\n\nMore\n"))
+	if err := os.Remove(filepath.Join(src, "old.txt")); err != nil {
+		t.Fatal(err)
+	}
+	write(src, "new.txt", []byte("new\n"))
+	write(src, "logo.png", []byte{0x89, 'P', 'N', 'G', 0, 0, 0, 1})
+
+	patch, err := g.CapturePatch(ctx, src, base)
+	if err != nil {
+		t.Fatalf("CapturePatch() failed: %v", err)
+	}
+	for _, file := range []string{"committed.go", "README.md", "old.txt", "new.txt", "logo.png"} {
+		if !strings.Contains(patch, "b/"+file) {
+			t.Errorf("patch is missing %s:\n%s", file, patch)
+		}
+	}
+	if staged := run(src, "diff", "--cached", "--name-only"); staged != "" {
+		t.Errorf("CapturePatch() changed the index: %s", staged)
+	}
+
+	if err := g.CheckPatch(ctx, dst, patch); err != nil {
+		t.Fatalf("CheckPatch() = %v, want nil", err)
+	}
+	if status := run(dst, "status", "--porcelain"); status != "" {
+		t.Fatalf("CheckPatch() changed the working tree:\n%s", status)
+	}
+	if err := g.ApplyPatch(ctx, dst, patch); err != nil {
+		t.Fatalf("ApplyPatch() failed: %v", err)
+	}
+	for _, file := range []string{"committed.go", "README.md", "new.txt", "logo.png"} {
+		want, _ := os.ReadFile(filepath.Join(src, file))
+		got, err := os.ReadFile(filepath.Join(dst, file))
+		if err != nil || string(got) != string(want) {
+			t.Errorf("%s after ApplyPatch() = %q, %v; want %q", file, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "old.txt")); !os.IsNotExist(err) {
+		t.Errorf("old.txt was not deleted: %v", err)
+	}
+
+	// It no longer applies once the files have moved on
+	if err := g.CheckPatch(ctx, dst, patch); err == nil {
+		t.Error("CheckPatch() on a tree it was already applied to = nil, want an error")
+	}
+}
@@ -0,0 +1,247 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pre-push checks, as reported in PushViolation.Check
+const (
+	PushCheckOversizedFile = "oversized_file"
+	PushCheckBinaryFile    = "binary_file"
+	PushCheckSecret        = "secret"
+	PushCheckForcePush     = "force_push"
+)
+
+// PushChecks configures the safety checks run before a push
+type PushChecks struct {
+	// MaxFileSize is the largest file a pushed commit may add or change, in
+	// bytes (0 = no limit)
+	MaxFileSize int64
+
+	// AllowBinary allows pushed commits to add or change binary files
+	AllowBinary bool
+
+	// AllowForcePush allows pushes that rewrite the remote branch's history
+	AllowForcePush bool
+
+	// ScanSecrets blocks pushes whose commits add lines that look like
+	// credentials
+	ScanSecrets bool
+}
+
+// PushViolation is one reason a push was blocked
+type PushViolation struct {
+	// Check is the check that failed, one of the PushCheck* constants
+	Check string
+
+	// Commit is the commit that introduced the problem (empty for force pushes)
+	Commit string
+
+	// Path is the offending file (empty for force pushes)
+	Path string
+
+	// Detail describes the problem; it never includes the secret itself
+	Detail string
+}
+
+// String returns a one-line description, e.g.
+// "secret in config.go (1a2b3c4d): GitHub token"
+func (v PushViolation) String() string {
+	if v.Path == "" {
+		return fmt.Sprintf("%s: %s", v.Check, v.Detail)
+	}
+	commit := v.Commit
+	if len(commit) > 8 {
+		commit = commit[:8]
+	}
+	return fmt.Sprintf("%s in %s (%s): %s", v.Check, v.Path, commit, v.Detail)
+}
+
+// PushBlockedError is returned when pre-push checks block a push
+type PushBlockedError struct {
+	Remote     string
+	Branch     string
+	Violations []PushViolation
+}
+
+func (e *PushBlockedError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		descriptions[i] = v.String()
+	}
+	return fmt.Sprintf("push of %s to %s blocked by pre-push checks: %s", e.Branch, e.Remote, strings.Join(descriptions, "; "))
+}
+
+// secretPatterns match added lines that look like credentials
+var secretPatterns = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"Anthropic API key", regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+	{"hardcoded credential", regexp.MustCompile(`(?i)(api[_-]?key|secret|password|passwd|token)["']?\s*[:=]\s*["'][^"'\s]{12,}["']`)},
+}
+
+// CheckPush runs pre-push checks on what pushing opts.Branch (or opts.Tag)
+// to opts.Remote would send: its commits that no branch of the remote has
+// yet.
+// Returns a *PushBlockedError listing every violation, nil if the push is
+// safe, or another error if the checks could not run.
+//
+// Force pushes are detected from the remote-tracking branch, so they are
+// only seen as of the last fetch. Tags have no tracking ref and aren't
+// checked for them.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) CheckPush(ctx context.Context, repoPath string, opts PushOptions, checks PushChecks) error {
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	local := opts.ref()
+	git := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, g.gitPath, append([]string{"-C", repoPath}, args...)...)
+		return cmd.Output()
+	}
+
+	var violations []PushViolation
+
+	tracking := "refs/remotes/" + opts.Remote + "/" + opts.Branch
+	if _, err := git("rev-parse", "--verify", "-q", tracking); err == nil && opts.Tag == "" && !checks.AllowForcePush {
+		out, err := git("rev-list", "--count", local+".."+tracking)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s with %s: %w", opts.Branch, tracking, err)
+		}
+		if n := strings.TrimSpace(string(out)); n != "0" {
+			violations = append(violations, PushViolation{
+				Check:  PushCheckForcePush,
+				Detail: fmt.Sprintf("pushing would rewrite %s/%s, dropping %s commit(s) not on %s", opts.Remote, opts.Branch, n, opts.Branch),
+			})
+		}
+	}
+
+	out, err := git("rev-list", "--reverse", local, "--not", "--remotes="+opts.Remote)
+	if err != nil {
+		return fmt.Errorf("failed to list commits to push from %s: %w", opts.name(), err)
+	}
+	for _, commit := range strings.Fields(string(out)) {
+		commitViolations, err := g.checkPushedCommit(ctx, repoPath, commit, checks)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, commitViolations...)
+	}
+
+	if len(violations) > 0 {
+		return &PushBlockedError{Remote: opts.Remote, Branch: opts.name(), Violations: violations}
+	}
+	return nil
+}
+
+// checkPushedCommit checks the files one commit adds or changes
+func (g *Git) checkPushedCommit(ctx context.Context, repoPath, commit string, checks PushChecks) ([]PushViolation, error) {
+	var violations []PushViolation
+	diffTree := func(args ...string) ([]byte, error) {
+		args = append([]string{"-C", repoPath, "diff-tree", "-r", "--root", "--no-commit-id", "--no-renames"}, args...)
+		out, err := exec.CommandContext(ctx, g.gitPath, append(args, commit)...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("git diff-tree %s failed in %s: %w", commit, repoPath, err)
+		}
+		return out, nil
+	}
+
+	// --raw -z: ":<mode> <mode> <blob> <blob> <status>\0<path>\0" per file
+	raw, err := diffTree("--raw", "-z")
+	if err != nil {
+		return nil, err
+	}
+	fields := bytes.Split(raw, []byte{0})
+	for i := 0; i+1 < len(fields); i += 2 {
+		meta := strings.Fields(string(fields[i]))
+		path := string(fields[i+1])
+		if len(meta) < 5 || strings.HasPrefix(meta[4], "D") || checks.MaxFileSize <= 0 {
+			continue
+		}
+		out, err := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "cat-file", "-s", meta[3]).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get size of %s in %s: %w", path, commit, err)
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse size of %s in %s: %w", path, commit, err)
+		}
+		if size > checks.MaxFileSize {
+			violations = append(violations, PushViolation{
+				Check:  PushCheckOversizedFile,
+				Commit: commit,
+				Path:   path,
+				Detail: fmt.Sprintf("%d bytes, the limit is %d", size, checks.MaxFileSize),
+			})
+		}
+	}
+
+	if checks.AllowBinary && !checks.ScanSecrets {
+		return violations, nil
+	}
+	patch, err := diffTree("-p", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range ParseDiff(string(patch)).Files {
+		if f.Binary {
+			if !checks.AllowBinary {
+				violations = append(violations, PushViolation{
+					Check:  PushCheckBinaryFile,
+					Commit: commit,
+					Path:   f.Path,
+					Detail: "binary files are not pushed",
+				})
+			}
+			continue
+		}
+		if checks.ScanSecrets {
+			if name := findSecret(f.Patch); name != "" {
+				violations = append(violations, PushViolation{
+					Check:  PushCheckSecret,
+					Commit: commit,
+					Path:   f.Path,
+					Detail: "added line looks like a " + name,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// findSecret returns the name of the first kind of credential found in the
+// lines a patch adds, or "" if there is none
+func findSecret(patch string) string {
+	inHunk := false
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			inHunk = true
+			continue
+		}
+		if !inHunk || !strings.HasPrefix(line, "+") {
+			continue
+		}
+		for _, p := range secretPatterns {
+			if p.re.MatchString(line) {
+				return p.name
+			}
+		}
+	}
+	return ""
+}
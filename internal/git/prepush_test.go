@@ -0,0 +1,104 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckPush(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	remote := t.TempDir()
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name string, content []byte, message string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(dir, "add", "-A")
+		run(dir, "commit", "-m", message)
+	}
+	run(remote, "init", "--bare")
+	run(dir, "init", "--initial-branch=main")
+	run(dir, "config", "user.name", "Test User")
+	run(dir, "config", "user.email", "test@example.com")
+	run(dir, "remote", "add", "origin", remote)
+	commit("README.md", []byte("# Test\n"), "initial")
+	run(dir, "push", "origin", "main")
+	run(dir, "checkout", "-b", "feature")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+	checks := PushChecks{MaxFileSize: 1024, ScanSecrets: true}
+	opts := PushOptions{Branch: "feature"}
+
+	// A plain change passes; what's already on the remote isn't checked
+	commit("main.go", []byte("package main\n"), "add main")
+	if err := g.CheckPush(ctx, dir, opts, checks); err != nil {
+		t.Fatalf("CheckPush() = %v, want nil", err)
+	}
+
+	commit("big.txt", []byte(strings.Repeat("x", 2048)), "add big file")
+	commit("logo.png", []byte{0x89, 'P', 'N', 'G', 0, 0, 0, 1}, "add binary")
+	commit("config.go", []byte("package main\n\nconst token = \"ghp_"+strings.Repeat("a", 36)+"\"\n"), "add config")
+
+	err = g.CheckPush(ctx, dir, opts, checks)
+	var blocked *PushBlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("CheckPush() = %v, want a PushBlockedError", err)
+	}
+	got := map[string]string{}
+	for _, v := range blocked.Violations {
+		got[v.Check] = v.Path
+	}
+	want := map[string]string{PushCheckOversizedFile: "big.txt", PushCheckBinaryFile: "logo.png", PushCheckSecret: "config.go"}
+	if len(got) != len(want) || got[PushCheckOversizedFile] != "big.txt" || got[PushCheckBinaryFile] != "logo.png" || got[PushCheckSecret] != "config.go" {
+		t.Errorf("violations = %+v, want %v", blocked.Violations, want)
+	}
+	if strings.Contains(err.Error(), "ghp_") {
+		t.Errorf("error leaks the secret: %v", err)
+	}
+
+	// Push with checks doesn't push a blocked branch
+	if err := g.Push(ctx, dir, PushOptions{Branch: "feature", Checks: &checks}); !errors.As(err, &blocked) {
+		t.Fatalf("Push() = %v, want a PushBlockedError", err)
+	}
+	if out, _ := exec.Command("git", "-C", remote, "rev-parse", "--verify", "-q", "feature").Output(); len(out) != 0 {
+		t.Error("blocked branch was pushed")
+	}
+
+	// Relaxed checks let it through
+	relaxed := PushChecks{AllowBinary: true}
+	if err := g.Push(ctx, dir, PushOptions{Branch: "feature", Checks: &relaxed}); err != nil {
+		t.Fatalf("Push() with relaxed checks = %v", err)
+	}
+	run(dir, "fetch", "origin")
+
+	// Rewriting the pushed branch is a force push
+	run(dir, "reset", "--hard", "HEAD~2")
+	commit("other.go", []byte("package main\n"), "diverge")
+	err = g.CheckPush(ctx, dir, opts, relaxed)
+	if !errors.As(err, &blocked) || len(blocked.Violations) != 1 || blocked.Violations[0].Check != PushCheckForcePush {
+		t.Fatalf("CheckPush() after a rewrite = %v, want a force push violation", err)
+	}
+	relaxed.AllowForcePush = true
+	if err := g.CheckPush(ctx, dir, opts, relaxed); err != nil {
+		t.Errorf("CheckPush() with force pushes allowed = %v", err)
+	}
+}
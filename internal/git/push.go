@@ -0,0 +1,168 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrPushRejected is wrapped by Push errors when the remote refused the push
+// because its branch has commits the pushed one doesn't (it advanced since
+// the branch was last fetched)
+var ErrPushRejected = errors.New("remote branch has advanced")
+
+// RemoteAdvanced reports whether err means the remote branch advanced past
+// the local one: the push was rejected as non-fast-forward, or pre-push
+// checks blocked it only because the last fetched remote branch has
+// commits the local one doesn't. Fetching and rebasing onto the remote
+// branch resolves either.
+func RemoteAdvanced(err error) bool {
+	if errors.Is(err, ErrPushRejected) {
+		return true
+	}
+	var blocked *PushBlockedError
+	if !errors.As(err, &blocked) || len(blocked.Violations) == 0 {
+		return false
+	}
+	for _, v := range blocked.Violations {
+		if v.Check != PushCheckForcePush {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoteURL returns the fetch URL of a remote.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) RemoteURL(ctx context.Context, repoPath, remote string) (string, error) {
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "remote", "get-url", remote)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote %s: %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Push pushes a branch or tag to a remote.
+// With a token and an HTTPS remote, the token is passed to git as an
+// authorization header through the environment, so it never appears in the
+// command line or the repository's config. With opts.Checks, CheckPush runs
+// first and a blocked push is never attempted. A push the remote rejects
+// because its branch advanced returns an error wrapping ErrPushRejected.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) Push(ctx context.Context, repoPath string, opts PushOptions) error {
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	if opts.Checks != nil {
+		if err := g.CheckPush(ctx, repoPath, opts, *opts.Checks); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"-C", repoPath, "push"}
+	if opts.SetUpstream && opts.Tag == "" {
+		args = append(args, "--set-upstream")
+	}
+	if opts.ForceWithLease {
+		args = append(args, "--force-with-lease")
+	}
+	args = append(args, opts.Remote, opts.ref()+":"+opts.ref())
+
+	cmd := exec.CommandContext(ctx, g.gitPath, args...)
+	env, err := g.remoteEnv(ctx, repoPath, opts.Remote, opts.Token, opts.TokenUser)
+	if err != nil {
+		return err
+	}
+	cmd.Env = env
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if rejected(string(output)) {
+			return fmt.Errorf("git push %s %s rejected: %w\nOutput: %s", opts.Remote, opts.name(), ErrPushRejected, redact(string(output), opts.Token))
+		}
+		return fmt.Errorf("git push %s %s failed: %w\nOutput: %s", opts.Remote, opts.name(), err, redact(string(output), opts.Token))
+	}
+	return nil
+}
+
+// Fetch updates the remote-tracking branch of opts.Branch from opts.Remote,
+// authenticating like Push. The local branch is left alone.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) Fetch(ctx context.Context, repoPath string, opts PushOptions) error {
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+	if opts.Branch == "" {
+		return fmt.Errorf("branch is required")
+	}
+	refspec := "+refs/heads/" + opts.Branch + ":refs/remotes/" + opts.Remote + "/" + opts.Branch
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "fetch", "--no-tags", opts.Remote, refspec)
+	env, err := g.remoteEnv(ctx, repoPath, opts.Remote, opts.Token, opts.TokenUser)
+	if err != nil {
+		return err
+	}
+	cmd.Env = env
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git fetch %s %s failed: %w\nOutput: %s", opts.Remote, opts.Branch, err, redact(string(output), opts.Token))
+	}
+	return nil
+}
+
+// remoteEnv returns the environment for git commands that talk to remote.
+// With a token and an HTTPS remote, the token is passed as an authorization
+// header, so it never appears in the command line or the repository's
+// config.
+func (g *Git) remoteEnv(ctx context.Context, repoPath, remote, token, tokenUser string) ([]string, error) {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if token == "" {
+		return env, nil
+	}
+	url, err := g.RemoteURL(ctx, repoPath, remote)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(url, "https://") {
+		if tokenUser == "" {
+			tokenUser = "x-access-token"
+		}
+		credentials := base64.StdEncoding.EncodeToString([]byte(tokenUser + ":" + token))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+		)
+	}
+	return env, nil
+}
+
+// rejected reports whether git push output says the remote refused the
+// push because it has commits the pushed ref doesn't
+func rejected(output string) bool {
+	for _, reason := range []string{"(fetch first)", "(non-fast-forward)", "(stale info)"} {
+		if strings.Contains(output, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact removes secret from s
+func redact(s, secret string) string {
+	if secret == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, secret, "[REDACTED]")
+}
@@ -0,0 +1,143 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPush(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	remote := t.TempDir()
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run(remote, "init", "--bare")
+	run(dir, "init", "--initial-branch=main")
+	run(dir, "config", "user.name", "Test User")
+	run(dir, "config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(dir, "add", "-A")
+	run(dir, "commit", "-m", "initial")
+	run(dir, "checkout", "-b", "vc/vc-1-feature")
+	run(dir, "remote", "add", "origin", remote)
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+
+	url, err := g.RemoteURL(ctx, dir, "origin")
+	if err != nil || url != remote {
+		t.Fatalf("RemoteURL() = %q, %v; want %q", url, err, remote)
+	}
+	if _, err := g.RemoteURL(ctx, dir, "missing"); err == nil {
+		t.Error("expected an error for a missing remote")
+	}
+
+	// The token is only used for HTTPS remotes; a local remote ignores it
+	if err := g.Push(ctx, dir, PushOptions{Branch: "vc/vc-1-feature", Token: "secret", SetUpstream: true}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if got, want := run(remote, "rev-parse", "vc/vc-1-feature"), run(dir, "rev-parse", "HEAD"); got != want {
+		t.Errorf("remote branch at %s, want %s", got, want)
+	}
+	if upstream := run(dir, "rev-parse", "--abbrev-ref", "@{upstream}"); upstream != "origin/vc/vc-1-feature" {
+		t.Errorf("upstream = %q, want origin/vc/vc-1-feature", upstream)
+	}
+
+	err = g.Push(ctx, dir, PushOptions{Branch: "no-such-branch", Token: "secret"})
+	if err == nil {
+		t.Fatal("expected pushing a missing branch to fail")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error leaks the token: %v", err)
+	}
+	if RemoteAdvanced(err) {
+		t.Errorf("RemoteAdvanced(%v) = true for a missing branch", err)
+	}
+}
+
+func TestPushRejectedAndFetch(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	other := t.TempDir()
+	remote := t.TempDir()
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(dir, name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(dir, "add", "-A")
+		run(dir, "commit", "-m", "add "+name)
+	}
+	run(remote, "init", "--bare")
+	run(dir, "init", "--initial-branch=main")
+	run(dir, "remote", "add", "origin", remote)
+	commit(dir, "a.txt")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+	if err := g.Push(ctx, dir, PushOptions{Branch: "main"}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	// Someone else pushes to the branch
+	run(other, "clone", "-q", "-b", "main", remote, ".")
+	commit(other, "b.txt")
+	run(other, "push", "-q", "origin", "main")
+	commit(dir, "c.txt")
+
+	err = g.Push(ctx, dir, PushOptions{Branch: "main"})
+	if !errors.Is(err, ErrPushRejected) || !RemoteAdvanced(err) {
+		t.Fatalf("Push() error = %v, want ErrPushRejected", err)
+	}
+
+	if err := g.Fetch(ctx, dir, PushOptions{Branch: "main"}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if got, want := run(dir, "rev-parse", "origin/main"), run(other, "rev-parse", "HEAD"); got != want {
+		t.Errorf("origin/main at %s after fetch, want %s", got, want)
+	}
+
+	// With the advanced branch fetched, pre-push checks see it first
+	err = g.Push(ctx, dir, PushOptions{Branch: "main", Checks: &PushChecks{}})
+	var blocked *PushBlockedError
+	if !errors.As(err, &blocked) || !RemoteAdvanced(err) {
+		t.Fatalf("Push() error = %v, want it blocked as a force push", err)
+	}
+
+	run(dir, "rebase", "-q", "origin/main")
+	if err := g.Push(ctx, dir, PushOptions{Branch: "main", Checks: &PushChecks{}}); err != nil {
+		t.Fatalf("Push after rebase failed: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Revert creates a single commit that undoes commits, which are reverted
+// newest first. The working tree must be clean. If any commit doesn't revert
+// cleanly, the revert is abandoned and the tree is left as it was.
+// Returns the hash of the revert commit.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) Revert(ctx context.Context, repoPath string, commits []string, message string) (string, error) {
+	if len(commits) == 0 {
+		return "", fmt.Errorf("no commits to revert")
+	}
+	if message == "" {
+		return "", fmt.Errorf("revert message is required")
+	}
+	dirty, err := g.HasUncommittedChanges(ctx, repoPath)
+	if err != nil {
+		return "", err
+	}
+	if dirty {
+		return "", fmt.Errorf("working tree has uncommitted changes")
+	}
+
+	for _, commit := range commits {
+		cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "revert", "--no-commit", commit)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			g.abortRevert(ctx, repoPath)
+			return "", fmt.Errorf("git revert %s failed: %w\nOutput: %s", commit, err, output)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "commit", "-m", message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		g.abortRevert(ctx, repoPath)
+		return "", fmt.Errorf("git commit failed: %w\nOutput: %s", err, output)
+	}
+
+	cmd = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "rev-parse", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get revert commit hash: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// abortRevert undoes a revert in progress, best effort
+func (g *Git) abortRevert(ctx context.Context, repoPath string) {
+	if err := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "revert", "--abort").Run(); err != nil {
+		// No sequencer state (e.g. the commit step failed): reset what was staged
+		_ = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "reset", "--merge").Run()
+	}
+}
+
+// IsAncestor reports whether commit is reachable from HEAD, i.e. whether
+// it's part of the checked out history.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) IsAncestor(ctx context.Context, repoPath, commit string) (bool, error) {
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "merge-base", "--is-ancestor", commit, "HEAD")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check whether %s is in HEAD's history: %w\nOutput: %s", commit, err, output)
+}
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRevert(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(name, content, message string) string {
+		t.Helper()
+		write(name, content)
+		run("add", "-A")
+		run("commit", "-m", message)
+		return run("rev-parse", "HEAD")
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+	initial := commit("README.md", "# Test\n", "initial")
+	first := commit("a.txt", "one\n", "add a")
+	second := commit("a.txt", "two\n", "change a")
+	run("checkout", "-q", "-b", "other", initial)
+	elsewhere := commit("b.txt", "b\n", "add b")
+	run("checkout", "-q", "main")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+
+	if ok, err := g.IsAncestor(ctx, dir, first); err != nil || !ok {
+		t.Errorf("IsAncestor(first) = %v, %v; want true", ok, err)
+	}
+	if ok, err := g.IsAncestor(ctx, dir, elsewhere); err != nil || ok {
+		t.Errorf("IsAncestor(commit on another branch) = %v, %v; want false", ok, err)
+	}
+	if _, err := g.IsAncestor(ctx, dir, "no-such-commit"); err == nil {
+		t.Error("expected an error for an unknown commit")
+	}
+
+	// Reverting the older commit alone conflicts with the newer one
+	if _, err := g.Revert(ctx, dir, []string{first}, "Revert add a"); err == nil {
+		t.Fatal("expected a conflicting revert to fail")
+	}
+	if status := run("status", "--porcelain"); status != "" {
+		t.Fatalf("failed revert left changes behind:\n%s", status)
+	}
+
+	write("dirty.txt", "wip")
+	if _, err := g.Revert(ctx, dir, []string{second}, "Revert change a"); err == nil {
+		t.Error("expected reverting with uncommitted changes to fail")
+	}
+	os.Remove(filepath.Join(dir, "dirty.txt"))
+
+	revertCommit, err := g.Revert(ctx, dir, []string{second, first}, "Revert a")
+	if err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+	if head := run("rev-parse", "HEAD"); head != revertCommit {
+		t.Errorf("revert commit = %s, HEAD = %s", revertCommit, head)
+	}
+	if msg := run("log", "-1", "--format=%s"); msg != "Revert a" {
+		t.Errorf("revert commit message = %q", msg)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); !os.IsNotExist(err) {
+		t.Errorf("a.txt should be gone after the revert, stat error: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxReviewerFiles caps how many files' history SuggestReviewers reads
+const maxReviewerFiles = 100
+
+// maxReviewerCommitsPerFile caps how many commits per file are counted
+const maxReviewerCommitsPerFile = 100
+
+// Reviewer is a suggested reviewer: someone who recently authored commits
+// to the files under review
+type Reviewer struct {
+	Name    string
+	Email   string
+	Files   int // How many of the files they changed
+	Commits int // How many commits to those files they authored
+}
+
+// String returns "Name <email>"
+func (r Reviewer) String() string {
+	return fmt.Sprintf("%s <%s>", r.Name, r.Email)
+}
+
+// ReviewerOptions configures SuggestReviewers
+type ReviewerOptions struct {
+	// Since only counts commits after this time (zero = all history)
+	Since time.Time
+
+	// Max is the number of reviewers returned (0 = all)
+	Max int
+
+	// Exclude lists authors never suggested, by email or name
+	// (case-insensitive)
+	Exclude []string
+}
+
+// SuggestReviewers ranks the authors of recent commits to files, most files
+// touched first, then most commits. The repository's own committer identity
+// (user.email), which vc commits as, is never suggested. Files without
+// history (e.g. new files) are ignored.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) SuggestReviewers(ctx context.Context, repoPath string, files []string, opts ReviewerOptions) ([]Reviewer, error) {
+	exclude := make(map[string]bool)
+	for _, author := range opts.Exclude {
+		exclude[strings.ToLower(strings.TrimSpace(author))] = true
+	}
+	if self, err := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "config", "user.email").Output(); err == nil {
+		if email := strings.ToLower(strings.TrimSpace(string(self))); email != "" {
+			exclude[email] = true
+		}
+	}
+	if len(files) > maxReviewerFiles {
+		files = files[:maxReviewerFiles]
+	}
+
+	byEmail := make(map[string]*Reviewer)
+	for _, file := range files {
+		args := []string{"-C", repoPath, "log", "--no-merges", "--format=%aN%x00%aE",
+			fmt.Sprintf("--max-count=%d", maxReviewerCommitsPerFile)}
+		if !opts.Since.IsZero() {
+			args = append(args, "--since="+opts.Since.Format(time.RFC3339))
+		}
+		args = append(args, "--", file)
+		output, err := exec.CommandContext(ctx, g.gitPath, args...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("git log %s failed in %s: %w", file, repoPath, err)
+		}
+
+		counted := make(map[string]bool)
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			name, email, ok := strings.Cut(line, "\x00")
+			if !ok || email == "" {
+				continue
+			}
+			key := strings.ToLower(email)
+			if exclude[key] || exclude[strings.ToLower(name)] {
+				continue
+			}
+			reviewer := byEmail[key]
+			if reviewer == nil {
+				reviewer = &Reviewer{Name: name, Email: email}
+				byEmail[key] = reviewer
+			}
+			reviewer.Commits++
+			if !counted[key] {
+				counted[key] = true
+				reviewer.Files++
+			}
+		}
+	}
+
+	reviewers := make([]Reviewer, 0, len(byEmail))
+	for _, reviewer := range byEmail {
+		reviewers = append(reviewers, *reviewer)
+	}
+	sort.Slice(reviewers, func(i, j int) bool {
+		a, b := reviewers[i], reviewers[j]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Email < b.Email
+	})
+	if opts.Max > 0 && len(reviewers) > opts.Max {
+		reviewers = reviewers[:opts.Max]
+	}
+	return reviewers, nil
+}
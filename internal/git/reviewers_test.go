@@ -0,0 +1,84 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuggestReviewers(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	commitAs := func(name, email, date, file string) {
+		t.Helper()
+		path := filepath.Join(dir, file)
+		content, _ := os.ReadFile(path)
+		if err := os.WriteFile(path, append(content, []byte(name+"\n")...), 0644); err != nil {
+			t.Fatal(err)
+		}
+		env := []string{"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=" + email, "GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}
+		run(env, "add", "-A")
+		run(env, "commit", "-m", "change "+file)
+	}
+	run(nil, "init", "--initial-branch=main")
+	run(nil, "config", "user.name", "vc")
+	run(nil, "config", "user.email", "vc@example.com")
+
+	recent := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
+	old := time.Now().Add(-400 * 24 * time.Hour).Format(time.RFC3339)
+	commitAs("Cy", "cy@example.com", old, "b.go")
+	commitAs("Ada", "ada@example.com", recent, "a.go")
+	commitAs("Ada", "ada@example.com", recent, "b.go")
+	commitAs("Bob", "bob@example.com", recent, "a.go")
+	commitAs("Bob", "bob@example.com", recent, "a.go")
+	commitAs("Bot", "bot@example.com", recent, "a.go")
+	commitAs("vc", "vc@example.com", recent, "b.go")
+	commitAs("Dee", "dee@example.com", recent, "other.go")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+	files := []string{"a.go", "b.go", "new.go"}
+
+	got, err := g.SuggestReviewers(ctx, dir, files, ReviewerOptions{})
+	if err != nil {
+		t.Fatalf("SuggestReviewers() error = %v", err)
+	}
+	var names []string
+	for _, r := range got {
+		names = append(names, r.Name)
+	}
+	// Ada touched both files; Bob made more commits to one; vc itself is never suggested
+	if strings.Join(names, ",") != "Ada,Bob,Bot,Cy" {
+		t.Errorf("reviewers = %v, want [Ada Bob Bot Cy]", names)
+	}
+	if got[0].Files != 2 || got[0].Commits != 2 || got[0].String() != "Ada <ada@example.com>" {
+		t.Errorf("top reviewer = %+v", got[0])
+	}
+
+	got, err = g.SuggestReviewers(ctx, dir, files, ReviewerOptions{
+		Since:   time.Now().Add(-30 * 24 * time.Hour),
+		Max:     2,
+		Exclude: []string{"BOT@example.com", "Bob"},
+	})
+	if err != nil {
+		t.Fatalf("SuggestReviewers() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "Ada" {
+		t.Errorf("reviewers = %+v, want only Ada (Cy is too old, Bob and Bot excluded)", got)
+	}
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommitSigningAndCommitter(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	keyDir := t.TempDir()
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	key := filepath.Join(keyDir, "id_ed25519")
+	run(keyDir, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "vc-bot", "-f", key)
+	run(dir, "git", "init", "--initial-branch=main")
+	run(dir, "git", "config", "user.name", "Test User")
+	run(dir, "git", "config", "user.email", "test@example.com")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.CommitChanges(ctx, dir, CommitOptions{
+		Message:        "Signed commit",
+		AddAll:         true,
+		CommitterName:  "vc-bot",
+		CommitterEmail: "vc-bot@example.com",
+		SignFormat:     "ssh",
+		SigningKey:     key,
+	})
+	if err != nil {
+		t.Fatalf("CommitChanges failed: %v", err)
+	}
+
+	if got := run(dir, "git", "log", "-1", "--format=%an <%ae> / %cn <%ce>"); got != "Test User <test@example.com> / vc-bot <vc-bot@example.com>" {
+		t.Errorf("author / committer = %q, want the configured author and vc-bot as committer", got)
+	}
+
+	// The signature verifies against the key
+	publicKey, err := os.ReadFile(key + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowedSigners := filepath.Join(keyDir, "allowed_signers")
+	if err := os.WriteFile(allowedSigners, []byte("vc-bot@example.com "+string(publicKey)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(dir, "git", "-c", "gpg.ssh.allowedSignersFile="+allowedSigners, "verify-commit", "HEAD")
+
+	// Signing with a key that doesn't exist fails and says why
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.CommitChanges(ctx, dir, CommitOptions{
+		Message:    "Unsigned commit",
+		AddAll:     true,
+		SignFormat: "ssh",
+		SigningKey: filepath.Join(keyDir, "missing"),
+	})
+	if err == nil {
+		t.Fatal("expected signing with a missing key to fail")
+	}
+	if !strings.Contains(err.Error(), "Output:") {
+		t.Errorf("error should include git's output: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// statusEntry is one path reported by git status
+type statusEntry struct {
+	code string // XY status code
+	path string
+	orig string // Source path of a rename or copy
+}
+
+// Snapshot identifies the working tree: the HEAD commit, plus a SHA-256 over
+// the status and current contents of every changed or untracked file.
+// Ignored files are not part of the snapshot.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) Snapshot(ctx context.Context, repoPath string) (*types.WorkspaceSnapshot, error) {
+	snapshot := &types.WorkspaceSnapshot{}
+
+	// rev-parse --verify -q exits 1 without output on an unborn branch
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "rev-parse", "--verify", "-q", "HEAD")
+	output, err := cmd.Output()
+	if err == nil {
+		snapshot.CommitSHA = strings.TrimSpace(string(output))
+	} else if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+		return nil, fmt.Errorf("git rev-parse failed in %s: %w", repoPath, err)
+	}
+
+	cmd = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed in %s: %w", repoPath, err)
+	}
+	entries := parseStatusZ(output)
+	if len(entries) == 0 {
+		return snapshot, nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].path < entries[j].path })
+	h := sha256.New()
+	snapshot.Files = make(map[string]string, len(entries))
+	for _, e := range entries {
+		fh := sha256.New()
+		w := io.MultiWriter(h, fh)
+		fmt.Fprintf(w, "%s %s\x00%s\x00", e.code, e.path, e.orig)
+		if err := hashWorktreeFile(w, filepath.Join(repoPath, e.path)); err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", e.path, err)
+		}
+		fileHash := hex.EncodeToString(fh.Sum(nil))
+		snapshot.Files[e.path] = fileHash
+		if e.orig != "" {
+			snapshot.Files[e.orig] = fileHash
+		}
+	}
+	snapshot.DirtyHash = hex.EncodeToString(h.Sum(nil))
+	return snapshot, nil
+}
+
+// parseStatusZ parses the output of git status --porcelain=v1 -z, where each
+// entry is "XY path" and renames and copies are followed by their source path
+func parseStatusZ(output []byte) []statusEntry {
+	fields := bytes.Split(output, []byte{0})
+	var entries []statusEntry
+	for i := 0; i < len(fields); i++ {
+		field := string(fields[i])
+		if len(field) < 4 {
+			continue
+		}
+		e := statusEntry{code: field[:2], path: field[3:]}
+		if (e.code[0] == 'R' || e.code[0] == 'C') && i+1 < len(fields) {
+			i++
+			e.orig = string(fields[i])
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// hashWorktreeFile writes what is at path to h: a file's contents, a
+// symlink's target, or a marker if it was deleted or is a directory (such as
+// a submodule, whose state the status code already carries)
+func hashWorktreeFile(h io.Writer, path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		_, _ = io.WriteString(h, "deleted\x00")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		_, _ = io.WriteString(h, "symlink\x00"+target+"\x00")
+	case info.IsDir():
+		_, _ = io.WriteString(h, "dir\x00")
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fmt.Fprintf(h, "file %o %d\x00", info.Mode().Perm(), info.Size())
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
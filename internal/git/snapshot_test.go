@@ -0,0 +1,88 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+	snap := func() (commit, dirty string) {
+		t.Helper()
+		s, err := g.Snapshot(ctx, dir)
+		if err != nil {
+			t.Fatalf("Snapshot failed: %v", err)
+		}
+		return s.CommitSHA, s.DirtyHash
+	}
+
+	// No commits yet: an empty commit and a hash of the untracked file
+	write("a.txt", "one")
+	commit, dirty := snap()
+	if commit != "" || dirty == "" {
+		t.Errorf("Unborn branch: got commit %q dirty %q, want no commit and a dirty hash", commit, dirty)
+	}
+
+	run("add", "-A")
+	run("commit", "-m", "initial")
+	commit, dirty = snap()
+	if len(commit) != 40 || dirty != "" {
+		t.Errorf("Clean tree: got commit %q dirty %q, want a full SHA and no dirty hash", commit, dirty)
+	}
+
+	write("a.txt", "two")
+	_, first := snap()
+	if first == "" {
+		t.Fatal("Expected a dirty hash after modifying a file")
+	}
+	if _, again := snap(); again != first {
+		t.Errorf("Dirty hash not stable: %q then %q", first, again)
+	}
+
+	write("a.txt", "three")
+	if _, changed := snap(); changed == first {
+		t.Error("Dirty hash should change with file contents")
+	}
+
+	write("a.txt", "one")
+	if _, restored := snap(); restored != "" {
+		t.Errorf("Expected a clean tree after restoring the file, got dirty hash %q", restored)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if _, deleted := snap(); deleted == "" {
+		t.Error("Expected a dirty hash after deleting a file")
+	}
+
+	if _, err := g.Snapshot(ctx, t.TempDir()); err == nil {
+		t.Error("Expected an error outside a git repository")
+	}
+}
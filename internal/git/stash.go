@@ -0,0 +1,98 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Stash saves the working tree's uncommitted changes, including untracked
+// files, to the stash with message and leaves the working tree clean.
+// Returns the stash commit, or "" if there was nothing to stash.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) Stash(ctx context.Context, repoPath, message string) (string, error) {
+	before := g.stashTop(ctx, repoPath)
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "stash", "push", "--include-untracked", "-m", message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git stash failed in %s: %w\nOutput: %s", repoPath, err, output)
+	}
+	after := g.stashTop(ctx, repoPath)
+	if after == before {
+		return "", nil // No local changes to save
+	}
+	return after, nil
+}
+
+// RestoreStash applies a stash saved by Stash to the working tree and drops
+// it. If it can't be applied cleanly (e.g. it conflicts with commits made
+// since), the stash is kept and an error naming it is returned.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) RestoreStash(ctx context.Context, repoPath, stash string) error {
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "stash", "list", "--format=%H")
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("git stash list failed in %s: %w", repoPath, err)
+	}
+	ref := ""
+	for i, commit := range strings.Fields(string(output)) {
+		if commit == stash {
+			ref = fmt.Sprintf("stash@{%d}", i)
+			break
+		}
+	}
+	if ref == "" {
+		return fmt.Errorf("stash %s not found in %s", safePrefix(stash, 8), repoPath)
+	}
+
+	cmd = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "stash", "apply", ref)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to apply %s (%s) in %s, it is kept in the stash: %w\nOutput: %s",
+			ref, safePrefix(stash, 8), repoPath, err, output)
+	}
+	cmd = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "stash", "drop", ref)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("applied %s but failed to drop it: %w\nOutput: %s", ref, err, output)
+	}
+	return nil
+}
+
+// CheckoutBranch checks out branch, creating it at startPoint (HEAD if
+// empty) unless it already exists.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) CheckoutBranch(ctx context.Context, repoPath, branch, startPoint string) error {
+	args := []string{"-C", repoPath, "checkout", branch}
+	verify := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "rev-parse", "--verify", "-q", "refs/heads/"+branch)
+	if err := verify.Run(); err != nil {
+		args = []string{"-C", repoPath, "checkout", "-b", branch}
+		if startPoint != "" {
+			args = append(args, startPoint)
+		}
+	}
+	cmd := exec.CommandContext(ctx, g.gitPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to check out %s in %s: %w\nOutput: %s", branch, repoPath, err, output)
+	}
+	return nil
+}
+
+// stashTop returns the latest stash commit, or "" if the stash is empty
+func (g *Git) stashTop(ctx context.Context, repoPath string) string {
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "rev-parse", "--verify", "-q", "refs/stash")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// safePrefix returns at most the first n bytes of s
+func safePrefix(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStash(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+	write("README.md", "# Test\n")
+	run("add", "-A")
+	run("commit", "-m", "initial")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+
+	// Nothing to stash
+	if stash, err := g.Stash(ctx, dir, "empty"); err != nil || stash != "" {
+		t.Fatalf("Stash() on a clean tree = %q, %v; want nothing stashed", stash, err)
+	}
+
+	// Modified and untracked files are set aside and restored
+	write("README.md", "# Test\n\nWork in progress\n")
+	write("notes.txt", "todo\n")
+	stash, err := g.Stash(ctx, dir, "wip")
+	if err != nil || stash == "" {
+		t.Fatalf("Stash() = %q, %v", stash, err)
+	}
+	if status := run("status", "--porcelain"); status != "" {
+		t.Fatalf("expected a clean tree after stashing, got:\n%s", status)
+	}
+	if err := g.RestoreStash(ctx, dir, stash); err != nil {
+		t.Fatalf("RestoreStash() failed: %v", err)
+	}
+	if status := run("status", "--porcelain"); !strings.Contains(status, "README.md") || !strings.Contains(status, "notes.txt") {
+		t.Errorf("expected the changes restored, got:\n%s", status)
+	}
+	if list := run("stash", "list"); list != "" {
+		t.Errorf("expected the stash dropped, got %s", list)
+	}
+
+	// A stash that conflicts with later commits is kept
+	stash, err = g.Stash(ctx, dir, "wip")
+	if err != nil {
+		t.Fatalf("Stash() failed: %v", err)
+	}
+	write("README.md", "# Test\n\nCommitted meanwhile\n")
+	run("commit", "-am", "conflicting change")
+	if err := g.RestoreStash(ctx, dir, stash); err == nil {
+		t.Fatal("expected RestoreStash() to fail on a conflict")
+	}
+	if list := run("stash", "list", "--format=%H"); list != stash {
+		t.Errorf("expected the stash kept, got %q", list)
+	}
+
+	// CheckoutBranch creates a missing branch and reuses an existing one
+	run("reset", "--hard")
+	run("clean", "-fd")
+	if err := g.CheckoutBranch(ctx, dir, "vc/feature", ""); err != nil {
+		t.Fatalf("CheckoutBranch() failed: %v", err)
+	}
+	run("checkout", "main")
+	if err := g.CheckoutBranch(ctx, dir, "vc/feature", ""); err != nil {
+		t.Fatalf("CheckoutBranch() of an existing branch failed: %v", err)
+	}
+	if current := run("branch", "--show-current"); current != "vc/feature" {
+		t.Errorf("on %q, want vc/feature", current)
+	}
+}
@@ -0,0 +1,169 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitlinkMode is the file mode git records submodules with
+const gitlinkMode = "160000"
+
+// SubmoduleChange is a submodule that differs from what HEAD records: its
+// pointer (checked-out commit) moved, or it has uncommitted changes inside
+type SubmoduleChange struct {
+	Path string
+
+	// From is the commit HEAD records ("" for a new submodule)
+	From string
+
+	// To is the commit checked out in the working tree ("" if the
+	// submodule was removed or isn't checked out)
+	To string
+
+	// Dirty is true if the submodule has uncommitted changes of its own,
+	// which the superproject can't commit
+	Dirty bool
+}
+
+// PointerChanged reports whether committing the superproject would record
+// a different commit for the submodule
+func (c SubmoduleChange) PointerChanged() bool {
+	return c.From != c.To
+}
+
+// String describes the change, e.g. "libs/core (1a2b3c4d -> 5e6f7a8b, dirty)"
+func (c SubmoduleChange) String() string {
+	var parts []string
+	if c.PointerChanged() {
+		parts = append(parts, fmt.Sprintf("%s -> %s", shortCommit(c.From), shortCommit(c.To)))
+	}
+	if c.Dirty {
+		parts = append(parts, "dirty")
+	}
+	return fmt.Sprintf("%s (%s)", c.Path, strings.Join(parts, ", "))
+}
+
+// shortCommit abbreviates a commit hash for display; "" is shown as "none"
+func shortCommit(hash string) string {
+	if hash == "" {
+		return "none"
+	}
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+// SubmoduleChanges returns the submodules of repoPath whose checked-out
+// commit differs from the one HEAD records, or that have uncommitted changes
+// inside them. A repository without commits or submodules has none.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) SubmoduleChanges(ctx context.Context, repoPath string) ([]SubmoduleChange, error) {
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "rev-parse", "--verify", "-q", "HEAD")
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("git rev-parse failed in %s: %w", repoPath, err)
+	}
+
+	// Raw diff lines are ":<old mode> <new mode> <old hash> <new hash> <status>\t<path>"
+	cmd = exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "diff", "--raw", "--no-abbrev", "--no-renames", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff --raw failed in %s: %w", repoPath, err)
+	}
+
+	var changes []SubmoduleChange
+	for _, line := range strings.Split(string(output), "\n") {
+		meta, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(strings.TrimPrefix(meta, ":"))
+		if !ok || len(fields) < 4 || (fields[0] != gitlinkMode && fields[1] != gitlinkMode) {
+			continue
+		}
+		change := SubmoduleChange{Path: path}
+		if fields[0] == gitlinkMode {
+			change.From = fields[2]
+		}
+		if fields[1] == gitlinkMode {
+			dir := filepath.Join(repoPath, path)
+			if head, err := exec.CommandContext(ctx, g.gitPath, "-C", dir, "rev-parse", "HEAD").Output(); err == nil {
+				change.To = strings.TrimSpace(string(head))
+			}
+			if status, err := exec.CommandContext(ctx, g.gitPath, "-C", dir, "status", "--porcelain").Output(); err == nil {
+				change.Dirty = len(strings.TrimSpace(string(status))) > 0
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// UpdateSubmodules initializes the submodules of repoPath and checks each
+// out at the commit the index records (recursively), so builds and gates see
+// the tree HEAD describes. Submodules listed in keep are left as they are.
+// A repository without a .gitmodules file has nothing to update.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) UpdateSubmodules(ctx context.Context, repoPath string, keep []string) error {
+	if _, err := os.Stat(filepath.Join(repoPath, ".gitmodules")); os.IsNotExist(err) {
+		return nil
+	}
+
+	// "submodule.<name>.path <path>" per submodule
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.path$`)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil // No submodules configured
+		}
+		return fmt.Errorf("failed to list submodules in %s: %w", repoPath, err)
+	}
+	skip := make(map[string]bool, len(keep))
+	for _, path := range keep {
+		skip[path] = true
+	}
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if _, path, ok := strings.Cut(line, " "); ok && !skip[path] {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	args := append([]string{"-C", repoPath, "submodule", "update", "--init", "--recursive", "--"}, paths...)
+	if output, err := exec.CommandContext(ctx, g.gitPath, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git submodule update failed in %s: %w\nOutput: %s", repoPath, err, output)
+	}
+	return nil
+}
+
+// ResetSubmodules moves the pointers of the given submodules back to the
+// commits HEAD records: the pointers are unstaged and the submodules checked
+// out at those commits. Commits made inside a submodule stay in its
+// repository; uncommitted changes inside it that the checkout would
+// overwrite make it fail.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) ResetSubmodules(ctx context.Context, repoPath string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	for _, args := range [][]string{
+		{"reset", "-q", "HEAD", "--"},
+		{"submodule", "update", "--init", "--recursive", "--"},
+	} {
+		args = append(append([]string{"-C", repoPath}, args...), paths...)
+		if output, err := exec.CommandContext(ctx, g.gitPath, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s failed in %s: %w\nOutput: %s", args[2], repoPath, err, output)
+		}
+	}
+	return nil
+}
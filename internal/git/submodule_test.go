@@ -0,0 +1,118 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSubmodules(t *testing.T) {
+	ctx := context.Background()
+	// Submodules are cloned from a local path, which git only allows when asked
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	root := t.TempDir()
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test User", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test User", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	lib := filepath.Join(root, "lib")
+	repo := filepath.Join(root, "repo")
+	for _, dir := range []string{lib, repo} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		git(dir, "init", "-q")
+	}
+	if err := os.WriteFile(filepath.Join(lib, "lib.go"), []byte("package lib\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(lib, "add", "-A")
+	git(lib, "commit", "-q", "-m", "lib")
+	git(repo, "submodule", "add", "-q", lib, "libs/lib")
+	git(repo, "commit", "-q", "-m", "add lib")
+	recorded := git(repo, "rev-parse", "HEAD:libs/lib")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("NewGit failed: %v", err)
+	}
+	sub := filepath.Join(repo, "libs", "lib")
+	changes := func() []SubmoduleChange {
+		t.Helper()
+		changes, err := g.SubmoduleChanges(ctx, repo)
+		if err != nil {
+			t.Fatalf("SubmoduleChanges() error = %v", err)
+		}
+		return changes
+	}
+
+	if got := changes(); len(got) != 0 {
+		t.Errorf("SubmoduleChanges() on a clean tree = %v", got)
+	}
+
+	// Uncommitted changes inside the submodule leave its pointer alone
+	if err := os.WriteFile(filepath.Join(sub, "lib.go"), []byte("package lib\n\nvar X = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := changes()
+	if len(got) != 1 || got[0].PointerChanged() || !got[0].Dirty || got[0].From != recorded {
+		t.Fatalf("SubmoduleChanges() with a dirty submodule = %+v", got)
+	}
+
+	// A commit inside it moves the pointer
+	git(sub, "commit", "-q", "-am", "change lib")
+	moved := git(sub, "rev-parse", "HEAD")
+	got = changes()
+	if len(got) != 1 || !got[0].PointerChanged() || got[0].Dirty || got[0].To != moved {
+		t.Fatalf("SubmoduleChanges() with a moved pointer = %+v", got)
+	}
+	if want := "libs/lib (" + recorded[:8] + " -> " + moved[:8] + ")"; got[0].String() != want {
+		t.Errorf("String() = %q, want %q", got[0].String(), want)
+	}
+
+	// Kept submodules aren't touched by an update
+	if err := g.UpdateSubmodules(ctx, repo, []string{"libs/lib"}); err != nil {
+		t.Fatalf("UpdateSubmodules() error = %v", err)
+	}
+	if head := git(sub, "rev-parse", "HEAD"); head != moved {
+		t.Errorf("UpdateSubmodules() moved a kept submodule to %s", head)
+	}
+
+	if err := g.ResetSubmodules(ctx, repo, []string{"libs/lib"}); err != nil {
+		t.Fatalf("ResetSubmodules() error = %v", err)
+	}
+	if got := changes(); len(got) != 0 {
+		t.Errorf("SubmoduleChanges() after reset = %+v", got)
+	}
+
+	// Update initializes submodules that aren't checked out
+	git(repo, "submodule", "deinit", "-q", "-f", "libs/lib")
+	if _, err := os.Stat(filepath.Join(sub, "lib.go")); !os.IsNotExist(err) {
+		t.Fatalf("deinit left lib.go behind: %v", err)
+	}
+	if err := g.UpdateSubmodules(ctx, repo, nil); err != nil {
+		t.Fatalf("UpdateSubmodules() error = %v", err)
+	}
+	if head := git(sub, "rev-parse", "HEAD"); head != recorded {
+		t.Errorf("UpdateSubmodules() checked out %s, want %s", head, recorded)
+	}
+
+	// Nothing to do without submodules
+	if err := g.UpdateSubmodules(ctx, lib, nil); err != nil {
+		t.Errorf("UpdateSubmodules() without submodules error = %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CreateTag creates an annotated tag named name at ref (HEAD if empty),
+// with message as its annotation. Fails if the tag already exists.
+// SECURITY: repoPath must be a validated, trusted path. This function
+// does not perform path validation or sandboxing.
+func (g *Git) CreateTag(ctx context.Context, repoPath, name, message, ref string) error {
+	if name == "" {
+		return fmt.Errorf("tag name is required")
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if err := exec.CommandContext(ctx, g.gitPath, "check-ref-format", "refs/tags/"+name).Run(); err != nil {
+		return fmt.Errorf("invalid tag name %q", name)
+	}
+
+	// The annotation is read from stdin so it can't be taken for an option
+	cmd := exec.CommandContext(ctx, g.gitPath, "-C", repoPath, "tag", "-a", "-F", "-", name, ref)
+	cmd.Stdin = strings.NewReader(message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git tag %s failed in %s: %w\nOutput: %s", name, repoPath, err, output)
+	}
+	return nil
+}
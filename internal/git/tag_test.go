@@ -0,0 +1,67 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateAndPushTag(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	remote := t.TempDir()
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run(remote, "init", "--bare")
+	run(dir, "init", "--initial-branch=main")
+	run(dir, "config", "user.name", "Test User")
+	run(dir, "config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(dir, "add", "-A")
+	run(dir, "commit", "-m", "initial")
+	run(dir, "remote", "add", "origin", remote)
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+
+	if err := g.CreateTag(ctx, dir, "v1.0.0", "Release v1.0.0\n\n- First release", ""); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	if kind := run(dir, "cat-file", "-t", "v1.0.0"); kind != "tag" {
+		t.Errorf("v1.0.0 is a %s, want an annotated tag", kind)
+	}
+	if got := run(dir, "tag", "-l", "--format=%(contents:subject)", "v1.0.0"); got != "Release v1.0.0" {
+		t.Errorf("tag subject = %q", got)
+	}
+	if err := g.CreateTag(ctx, dir, "v1.0.0", "again", ""); err == nil {
+		t.Error("expected an error for an existing tag")
+	}
+	if err := g.CreateTag(ctx, dir, "bad..name", "bad", ""); err == nil {
+		t.Error("expected an error for an invalid tag name")
+	}
+
+	if err := g.Push(ctx, dir, PushOptions{Tag: "v1.0.0", Checks: &PushChecks{ScanSecrets: true}}); err != nil {
+		t.Fatalf("Push of tag failed: %v", err)
+	}
+	if got, want := run(remote, "rev-parse", "v1.0.0^{commit}"), run(dir, "rev-parse", "HEAD"); got != want {
+		t.Errorf("remote tag at %s, want %s", got, want)
+	}
+	if err := g.Push(ctx, dir, PushOptions{Branch: "main", Tag: "v1.0.0"}); err == nil {
+		t.Error("expected an error when pushing a branch and a tag at once")
+	}
+}
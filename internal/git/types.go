@@ -2,6 +2,9 @@ package git
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/steveyegge/vc/internal/types"
 )
 
 // GitOperations provides git operations for the executor.
@@ -30,6 +33,89 @@ type GitOperations interface {
 	// ValidateConflictResolution checks if conflicts have been properly resolved.
 	// Returns true if no conflict markers remain in the specified files.
 	ValidateConflictResolution(ctx context.Context, repoPath string, files []string) (bool, error)
+
+	// Snapshot identifies the current state of the working tree: the HEAD
+	// commit plus a hash of any uncommitted changes.
+	Snapshot(ctx context.Context, repoPath string) (*types.WorkspaceSnapshot, error)
+
+	// RemoteURL returns the fetch URL of a remote.
+	RemoteURL(ctx context.Context, repoPath, remote string) (string, error)
+
+	// Push pushes a branch or tag to a remote.
+	Push(ctx context.Context, repoPath string, opts PushOptions) error
+
+	// Fetch updates the remote-tracking branch of a branch.
+	Fetch(ctx context.Context, repoPath string, opts PushOptions) error
+
+	// CheckPush runs pre-push safety checks on what pushing a branch or tag
+	// would send. Returns a *PushBlockedError if any check fails.
+	CheckPush(ctx context.Context, repoPath string, opts PushOptions, checks PushChecks) error
+
+	// Revert creates one commit undoing the given commits.
+	// Returns the revert commit's hash if successful.
+	Revert(ctx context.Context, repoPath string, commits []string, message string) (string, error)
+
+	// IsAncestor reports whether commit is in HEAD's history.
+	IsAncestor(ctx context.Context, repoPath, commit string) (bool, error)
+
+	// Diff returns the working tree's changes relative to base (HEAD if
+	// empty), including untracked files, split per file.
+	Diff(ctx context.Context, repoPath, base string) (*Diff, error)
+
+	// Stash saves uncommitted changes, including untracked files, and
+	// leaves the working tree clean. Returns the stash commit, or "" if
+	// there was nothing to stash.
+	Stash(ctx context.Context, repoPath, message string) (string, error)
+
+	// RestoreStash applies a stash saved by Stash and drops it, keeping it
+	// if it doesn't apply cleanly.
+	RestoreStash(ctx context.Context, repoPath, stash string) error
+
+	// CheckoutBranch checks out a branch, creating it at startPoint if it
+	// doesn't exist.
+	CheckoutBranch(ctx context.Context, repoPath, branch, startPoint string) error
+
+	// DiscardChanges reverts uncommitted changes to the given paths,
+	// deleting files that aren't in HEAD.
+	DiscardChanges(ctx context.Context, repoPath string, paths []string) error
+
+	// CapturePatch returns the changes since base (HEAD if empty),
+	// including commits and untracked files, as a binary-safe patch.
+	CapturePatch(ctx context.Context, repoPath, base string) (string, error)
+
+	// CheckPatch reports whether a patch applies cleanly, without
+	// applying it.
+	CheckPatch(ctx context.Context, repoPath, patch string) error
+
+	// ApplyPatch applies a patch to the working tree, all or nothing.
+	ApplyPatch(ctx context.Context, repoPath, patch string) error
+
+	// ResolveRef resolves a branch, tag or other revision to a commit hash.
+	ResolveRef(ctx context.Context, repoPath, ref string) (string, error)
+
+	// CherryPick applies commits on top of HEAD, abandoning the whole
+	// cherry-pick if any of them conflicts.
+	CherryPick(ctx context.Context, repoPath string, commits []string) (*CherryPickResult, error)
+
+	// SuggestReviewers ranks the recent authors of files as reviewers.
+	SuggestReviewers(ctx context.Context, repoPath string, files []string, opts ReviewerOptions) ([]Reviewer, error)
+
+	// HeavyFiles returns which paths are Git LFS-tracked or large binaries.
+	HeavyFiles(ctx context.Context, repoPath string, paths []string, maxBinarySize int64) ([]HeavyFile, error)
+
+	// SubmoduleChanges returns submodules whose pointer moved or that have
+	// uncommitted changes inside them.
+	SubmoduleChanges(ctx context.Context, repoPath string) ([]SubmoduleChange, error)
+
+	// UpdateSubmodules initializes submodules and checks them out at their
+	// recorded commits, except those in keep.
+	UpdateSubmodules(ctx context.Context, repoPath string, keep []string) error
+
+	// ResetSubmodules moves submodule pointers back to the commits HEAD records.
+	ResetSubmodules(ctx context.Context, repoPath string, paths []string) error
+
+	// CreateTag creates an annotated tag at ref (HEAD if empty).
+	CreateTag(ctx context.Context, repoPath, name, message, ref string) error
 }
 
 // Status represents the git status of a repository.
@@ -53,6 +139,63 @@ type Status struct {
 	HasChanges bool
 }
 
+// PushOptions configures a git push operation.
+type PushOptions struct {
+	// Remote to push to (default: origin)
+	Remote string
+
+	// Branch is the local branch to push; it's pushed under the same name
+	Branch string
+
+	// Tag is the tag to push instead of a branch; it's pushed under the
+	// same name
+	Tag string
+
+	// Token authenticates pushes to HTTPS remotes (optional)
+	Token string
+
+	// TokenUser is the user name sent with Token (default: "x-access-token",
+	// as GitHub expects; GitLab expects "oauth2")
+	TokenUser string
+
+	// SetUpstream makes the pushed branch the local branch's upstream
+	SetUpstream bool
+
+	// ForceWithLease overwrites the remote branch if it's where we last saw it
+	ForceWithLease bool
+
+	// Checks, when set, are run first; a push they fail is not attempted
+	// and returns a *PushBlockedError
+	Checks *PushChecks
+}
+
+// ref returns the full ref being pushed
+func (o PushOptions) ref() string {
+	if o.Tag != "" {
+		return "refs/tags/" + o.Tag
+	}
+	return "refs/heads/" + o.Branch
+}
+
+// name returns the branch or tag being pushed
+func (o PushOptions) name() string {
+	if o.Tag != "" {
+		return o.Tag
+	}
+	return o.Branch
+}
+
+// validate checks that exactly one of Branch and Tag is set
+func (o PushOptions) validate() error {
+	if o.Branch == "" && o.Tag == "" {
+		return fmt.Errorf("branch or tag is required")
+	}
+	if o.Branch != "" && o.Tag != "" {
+		return fmt.Errorf("cannot push both branch %s and tag %s", o.Branch, o.Tag)
+	}
+	return nil
+}
+
 // CommitOptions configures a git commit operation.
 type CommitOptions struct {
 	// Message is the commit message
@@ -64,11 +207,36 @@ type CommitOptions struct {
 	// CoAuthors is a list of co-authors to add to the commit message
 	CoAuthors []string
 
+	// Trailers are extra "Key: value" trailers added after the co-authors,
+	// e.g. "VC-Execution: 42"
+	Trailers []string
+
 	// AddAll stages all changes before committing (git add -A)
 	AddAll bool
 
 	// AllowEmpty allows creating an empty commit
 	AllowEmpty bool
+
+	// Paths limits the commit to these paths, which are staged first
+	// (including untracked and deleted files). Changes elsewhere, staged or
+	// not, are left out of the commit. Empty means everything staged.
+	Paths []string
+
+	// Amend replaces the HEAD commit instead of creating a new one
+	Amend bool
+
+	// CommitterName and CommitterEmail override the committer identity
+	// (optional, uses git config if empty)
+	CommitterName  string
+	CommitterEmail string
+
+	// SignFormat signs the commit: "gpg" or "ssh" (optional, empty leaves
+	// signing to the repository's commit.gpgsign setting)
+	SignFormat string
+
+	// SigningKey is the GPG key ID or SSH key to sign with (optional, uses
+	// the repository's user.signingkey if empty)
+	SigningKey string
 }
 
 // CommitMessageRequest contains information for generating a commit message.
@@ -85,8 +253,13 @@ type CommitMessageRequest struct {
 	// ChangedFiles lists the files that were modified
 	ChangedFiles []string
 
-	// Diff is the git diff output (optional, can be large)
+	// Diff is the git diff output (optional, can be large: files that don't
+	// fit the prompt are summarized)
 	Diff string
+
+	// Template is the repository's commit message conventions (optional,
+	// nil uses DefaultCommitTemplate)
+	Template *CommitTemplate
 }
 
 // CommitMessageResponse contains the AI-generated commit message.
@@ -101,6 +274,40 @@ type CommitMessageResponse struct {
 	Reasoning string `json:"reasoning"`
 }
 
+// PRDescriptionRequest contains information for generating a pull request description.
+type PRDescriptionRequest struct {
+	// IssueID is the issue being worked on
+	IssueID string
+
+	// IssueTitle is the title of the issue
+	IssueTitle string
+
+	// IssueDescription provides context about the issue
+	IssueDescription string
+
+	// AcceptanceCriteria is what the work had to satisfy
+	AcceptanceCriteria string
+
+	// Commits lists the subjects of the commits in the pull request
+	Commits []string
+
+	// ChangedFiles lists the files that were modified
+	ChangedFiles []string
+
+	// Diff is the diff against the base branch (optional, can be large:
+	// files that don't fit the prompt are summarized)
+	Diff string
+}
+
+// PRDescriptionResponse contains the AI-generated pull request title and description.
+type PRDescriptionResponse struct {
+	// Title is the pull request title
+	Title string `json:"title"`
+
+	// Body is the Markdown description
+	Body string `json:"body"`
+}
+
 // RebaseOptions configures a git rebase operation.
 type RebaseOptions struct {
 	// BaseBranch is the branch to rebase onto (e.g., "main", "origin/main")
@@ -113,6 +320,10 @@ type RebaseOptions struct {
 	// Continue will continue a rebase after resolving conflicts
 	// This is mutually exclusive with BaseBranch and Abort
 	Continue bool
+
+	// Autostash stashes uncommitted changes before rebasing onto
+	// BaseBranch and restores them afterwards
+	Autostash bool
 }
 
 // RebaseResult contains the outcome of a rebase operation.
@@ -0,0 +1,167 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// IssueBranchPrefix prefixes the branches WorkflowManager creates
+const IssueBranchPrefix = "vc/"
+
+// maxBranchSlugLength bounds the title part of an issue branch name
+const maxBranchSlugLength = 40
+
+var branchSlugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// WorkflowManager runs each issue on its own branch instead of the checked
+// out one. Start cuts vc/<issue-id>-<slug> from the current branch; gates run
+// and commits land there; Merge brings the work back only once it has been
+// accepted, and Leave returns to the base branch keeping the work on the
+// issue branch for the next attempt.
+type WorkflowManager struct {
+	git      *Git
+	repoPath string
+}
+
+// IssueBranch is an issue's branch and the branch it merges into
+type IssueBranch struct {
+	IssueID    string
+	Name       string // vc/<issue-id>-<slug>
+	BaseBranch string // Branch checked out when work started
+	Resumed    bool   // The branch already existed, from an earlier attempt
+}
+
+// MergeResult describes a merged issue branch
+type MergeResult struct {
+	FastForward bool   // The base branch was fast-forwarded rather than merged
+	Commit      string // The base branch's new tip
+	Stashed     string // Message of the stash holding uncommitted leftovers, if any
+}
+
+// NewWorkflowManager creates a workflow manager for the repository at repoPath.
+// SECURITY: repoPath must be a validated, trusted path.
+func NewWorkflowManager(g *Git, repoPath string) *WorkflowManager {
+	return &WorkflowManager{git: g, repoPath: repoPath}
+}
+
+// IssueBranchName returns the branch an issue is worked on, e.g.
+// "vc/vc-a1b2-fix-login-timeout"
+func IssueBranchName(issueID, title string) string {
+	slug := strings.Trim(branchSlugRegex.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxBranchSlugLength {
+		slug = strings.TrimRight(slug[:maxBranchSlugLength], "-")
+	}
+	if slug == "" {
+		return IssueBranchPrefix + issueID
+	}
+	return IssueBranchPrefix + issueID + "-" + slug
+}
+
+// Start checks out the issue's branch, creating it from the current branch
+// unless an earlier attempt left it behind. The working tree must be clean,
+// so uncommitted work on the base branch is never carried onto the issue
+// branch.
+func (w *WorkflowManager) Start(ctx context.Context, issueID, title string) (*IssueBranch, error) {
+	base, err := w.run(ctx, "symbolic-ref", "--short", "-q", "HEAD")
+	if err != nil || base == "" {
+		return nil, fmt.Errorf("HEAD is not on a branch in %s", w.repoPath)
+	}
+	if strings.HasPrefix(base, IssueBranchPrefix) {
+		return nil, fmt.Errorf("already on issue branch %s (check out the base branch first)", base)
+	}
+	dirty, err := w.git.HasUncommittedChanges(ctx, w.repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("working tree in %s has uncommitted changes", w.repoPath)
+	}
+
+	branch := &IssueBranch{IssueID: issueID, Name: IssueBranchName(issueID, title), BaseBranch: base}
+	if _, err := w.run(ctx, "rev-parse", "--verify", "-q", "refs/heads/"+branch.Name); err == nil {
+		branch.Resumed = true
+		_, err = w.run(ctx, "checkout", branch.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check out %s: %w", branch.Name, err)
+		}
+		return branch, nil
+	}
+	if _, err := w.run(ctx, "checkout", "-b", branch.Name); err != nil {
+		return nil, fmt.Errorf("failed to create branch %s: %w", branch.Name, err)
+	}
+	return branch, nil
+}
+
+// Merge brings the issue branch into its base branch, fast-forwarding when
+// possible, and deletes it. If the merge conflicts it is aborted and the
+// issue branch kept. Either way the base branch is left checked out.
+//
+// Only committed work is merged. Changes left uncommitted on the issue
+// branch (e.g. files kept out of the commit by auto-commit paths or a path
+// scope) are stashed, so they neither block the checkout nor reach the base
+// branch, and can be recovered with git stash.
+func (w *WorkflowManager) Merge(ctx context.Context, branch *IssueBranch) (*MergeResult, error) {
+	dirty, err := w.git.HasUncommittedChanges(ctx, w.repoPath)
+	if err != nil {
+		return nil, err
+	}
+	result := &MergeResult{FastForward: true}
+	if dirty {
+		message := fmt.Sprintf("vc: uncommitted changes left on %s", branch.Name)
+		if _, err := w.run(ctx, "stash", "push", "--include-untracked", "-m", message); err != nil {
+			return nil, fmt.Errorf("failed to stash uncommitted changes on %s: %w", branch.Name, err)
+		}
+		result.Stashed = message
+	}
+	if _, err := w.run(ctx, "checkout", branch.BaseBranch); err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %w", branch.BaseBranch, err)
+	}
+
+	if _, err := w.run(ctx, "merge", "--ff-only", branch.Name); err != nil {
+		result.FastForward = false
+		message := fmt.Sprintf("Merge branch '%s'", branch.Name)
+		if _, err := w.run(ctx, "merge", "--no-ff", "-m", message, branch.Name); err != nil {
+			_, _ = w.run(ctx, "merge", "--abort")
+			return nil, fmt.Errorf("failed to merge %s into %s: %w", branch.Name, branch.BaseBranch, err)
+		}
+	}
+	if result.Commit, err = w.run(ctx, "rev-parse", "HEAD"); err != nil {
+		return nil, fmt.Errorf("failed to get merge commit: %w", err)
+	}
+	if _, err := w.run(ctx, "branch", "-d", branch.Name); err != nil {
+		return result, fmt.Errorf("merged, but failed to delete %s: %w", branch.Name, err)
+	}
+	return result, nil
+}
+
+// Leave returns to the base branch without merging. Uncommitted work is
+// first committed to the issue branch with message, so the next attempt
+// resumes from it and the base branch stays untouched.
+func (w *WorkflowManager) Leave(ctx context.Context, branch *IssueBranch, message string) error {
+	dirty, err := w.git.HasUncommittedChanges(ctx, w.repoPath)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		if _, err := w.git.CommitChanges(ctx, w.repoPath, CommitOptions{Message: message, AddAll: true}); err != nil {
+			return fmt.Errorf("failed to save work on %s: %w", branch.Name, err)
+		}
+	}
+	if _, err := w.run(ctx, "checkout", branch.BaseBranch); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", branch.BaseBranch, err)
+	}
+	return nil
+}
+
+// run runs a git command in the repository and returns its trimmed output
+func (w *WorkflowManager) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, w.git.gitPath, append([]string{"-C", w.repoPath}, args...)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w (output: %s)", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
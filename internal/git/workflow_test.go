@@ -0,0 +1,208 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIssueBranchName(t *testing.T) {
+	tests := []struct {
+		issueID, title, want string
+	}{
+		{"vc-a1b2", "Fix login timeout", "vc/vc-a1b2-fix-login-timeout"},
+		{"vc-a1b2", "Add OAuth2.0 support (phase #2)!", "vc/vc-a1b2-add-oauth2-0-support-phase-2"},
+		{"vc-a1b2", "???", "vc/vc-a1b2"},
+		{"vc-a1b2", "A very long title that goes on and on past the slug limit", "vc/vc-a1b2-a-very-long-title-that-goes-on-and-on-pa"},
+	}
+	for _, tt := range tests {
+		if got := IssueBranchName(tt.issueID, tt.title); got != tt.want {
+			t.Errorf("IssueBranchName(%q, %q) = %q, want %q", tt.issueID, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestWorkflowManager(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+	write("README.md", "# Test")
+	run("add", "-A")
+	run("commit", "-m", "initial")
+
+	g, err := NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+	w := NewWorkflowManager(g, dir)
+
+	t.Run("RefusesDirtyTree", func(t *testing.T) {
+		write("README.md", "# Dirty")
+		if _, err := w.Start(ctx, "vc-1", "Dirty"); err == nil {
+			t.Error("Expected Start to refuse a dirty working tree")
+		}
+		run("checkout", "--", "README.md")
+	})
+
+	t.Run("LeaveKeepsWorkOnBranch", func(t *testing.T) {
+		branch, err := w.Start(ctx, "vc-1", "Add feature")
+		if err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		if branch.Name != "vc/vc-1-add-feature" || branch.BaseBranch != "main" || branch.Resumed {
+			t.Errorf("Unexpected branch: %+v", branch)
+		}
+		if current := run("branch", "--show-current"); current != branch.Name {
+			t.Errorf("Expected %s checked out, got %s", branch.Name, current)
+		}
+
+		write("feature.txt", "half done")
+		if err := w.Leave(ctx, branch, "wip"); err != nil {
+			t.Fatalf("Leave failed: %v", err)
+		}
+		if current := run("branch", "--show-current"); current != "main" {
+			t.Errorf("Expected main checked out after Leave, got %s", current)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "feature.txt")); !os.IsNotExist(err) {
+			t.Error("Expected the unfinished work to stay off main")
+		}
+	})
+
+	t.Run("MergeFastForwards", func(t *testing.T) {
+		branch, err := w.Start(ctx, "vc-1", "Add feature")
+		if err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		if !branch.Resumed {
+			t.Error("Expected the branch from the earlier attempt to be resumed")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "feature.txt")); err != nil {
+			t.Errorf("Expected the earlier attempt's work on the branch: %v", err)
+		}
+		write("feature.txt", "done")
+		run("commit", "-am", "finish feature")
+
+		result, err := w.Merge(ctx, branch)
+		if err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		if !result.FastForward || result.Commit != run("rev-parse", "HEAD") {
+			t.Errorf("Unexpected merge result: %+v", result)
+		}
+		if current := run("branch", "--show-current"); current != "main" {
+			t.Errorf("Expected main checked out after Merge, got %s", current)
+		}
+		if branches := run("branch", "--list", "vc/*"); branches != "" {
+			t.Errorf("Expected the issue branch to be deleted, got %q", branches)
+		}
+	})
+
+	t.Run("MergeStashesUncommittedLeftovers", func(t *testing.T) {
+		branch, err := w.Start(ctx, "vc-3", "Scoped change")
+		if err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		write("scoped.txt", "in scope")
+		run("add", "scoped.txt")
+		run("commit", "-m", "scoped change")
+		// Left out of the commit, as an auto-commit exclusion or path scope would
+		write("README.md", "# Out of scope")
+		write("notes.txt", "untracked")
+
+		result, err := w.Merge(ctx, branch)
+		if err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		if result.Stashed == "" {
+			t.Error("Expected the leftovers to be reported as stashed")
+		}
+		if current := run("branch", "--show-current"); current != "main" {
+			t.Errorf("Expected main checked out after Merge, got %s", current)
+		}
+		if status := run("status", "--porcelain"); status != "" {
+			t.Errorf("Expected the leftovers to stay off main, got %q", status)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "scoped.txt")); err != nil {
+			t.Errorf("Expected the committed work merged: %v", err)
+		}
+		if stash := run("stash", "list"); !strings.Contains(stash, result.Stashed) {
+			t.Errorf("Expected a stash named %q, got %q", result.Stashed, stash)
+		}
+		if _, err := w.Start(ctx, "vc-4", "Next issue"); err != nil {
+			t.Errorf("Expected the next Start to succeed: %v", err)
+		}
+		run("checkout", "main")
+	})
+
+	t.Run("MergeCreatesMergeCommitWhenBaseMoved", func(t *testing.T) {
+		branch, err := w.Start(ctx, "vc-2", "Other change")
+		if err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		write("other.txt", "other")
+		run("add", "-A")
+		run("commit", "-m", "other change")
+		run("checkout", "main")
+		write("README.md", "# Moved on")
+		run("commit", "-am", "main moved on")
+		run("checkout", branch.Name)
+
+		result, err := w.Merge(ctx, branch)
+		if err != nil {
+			t.Fatalf("Merge failed: %v", err)
+		}
+		if result.FastForward {
+			t.Error("Expected a merge commit, not a fast-forward")
+		}
+		if parents := strings.Fields(run("show", "-s", "--format=%P", "HEAD")); len(parents) != 2 {
+			t.Errorf("Expected a merge commit with 2 parents, got %v", parents)
+		}
+	})
+
+	t.Run("MergeConflictKeepsBranch", func(t *testing.T) {
+		branch, err := w.Start(ctx, "vc-3", "Conflicting change")
+		if err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		write("README.md", "# Branch version")
+		run("commit", "-am", "branch edit")
+		run("checkout", "main")
+		write("README.md", "# Main version")
+		run("commit", "-am", "main edit")
+		run("checkout", branch.Name)
+
+		if _, err := w.Merge(ctx, branch); err == nil {
+			t.Fatal("Expected the merge to conflict")
+		}
+		if current := run("branch", "--show-current"); current != "main" {
+			t.Errorf("Expected main checked out after a failed merge, got %s", current)
+		}
+		if status := run("status", "--porcelain"); status != "" {
+			t.Errorf("Expected the aborted merge to leave a clean tree, got %q", status)
+		}
+		if branches := run("branch", "--list", branch.Name); branches == "" {
+			t.Error("Expected the issue branch to be kept after a conflict")
+		}
+	})
+}
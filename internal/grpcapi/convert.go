@@ -0,0 +1,151 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/steveyegge/vc/api/vcpb"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timestamp converts t, leaving the zero time unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// optionalTimestamp converts t, leaving nil unset
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+// fromTimestamp converts t, returning the zero time when it is unset
+func fromTimestamp(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}
+
+// optionalInt32 converts an optional int
+func optionalInt32(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
+
+// toIssue converts an issue, with its labels and dependencies if given
+func toIssue(issue *types.Issue, labels []string, deps []*types.Dependency) *vcpb.Issue {
+	out := &vcpb.Issue{
+		Id:                 issue.ID,
+		Title:              issue.Title,
+		Description:        issue.Description,
+		Design:             issue.Design,
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+		Notes:              issue.Notes,
+		Status:             string(issue.Status),
+		Priority:           int32(issue.Priority),
+		IssueType:          string(issue.IssueType),
+		IssueSubtype:       string(issue.IssueSubtype),
+		Assignee:           issue.Assignee,
+		EstimatedMinutes:   optionalInt32(issue.EstimatedMinutes),
+		CreatedAt:          timestamp(issue.CreatedAt),
+		UpdatedAt:          timestamp(issue.UpdatedAt),
+		ClosedAt:           optionalTimestamp(issue.ClosedAt),
+		Labels:             labels,
+	}
+	for _, dep := range deps {
+		out.Dependencies = append(out.Dependencies, &vcpb.Dependency{
+			IssueId:     dep.IssueID,
+			DependsOnId: dep.DependsOnID,
+			Type:        string(dep.Type),
+			CreatedAt:   timestamp(dep.CreatedAt),
+			CreatedBy:   dep.CreatedBy,
+		})
+	}
+	return out
+}
+
+// toComment converts a comment
+func toComment(comment *types.Comment) *vcpb.Comment {
+	return &vcpb.Comment{
+		Id:        comment.ID,
+		IssueId:   comment.IssueID,
+		ParentId:  comment.ParentID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: timestamp(comment.CreatedAt),
+		EditedAt:  optionalTimestamp(comment.EditedAt),
+	}
+}
+
+// toExecution converts an execution
+func toExecution(execution *types.Execution) *vcpb.Execution {
+	out := &vcpb.Execution{
+		Id:                 execution.ID,
+		IssueId:            execution.IssueID,
+		ExecutorInstanceId: execution.ExecutorInstanceID,
+		AgentProvider:      execution.AgentProvider,
+		PromptHash:         execution.PromptHash,
+		Status:             string(execution.Status),
+		ExitCode:           optionalInt32(execution.ExitCode),
+		Error:              execution.Error,
+		StartedAt:          timestamp(execution.StartedAt),
+		CompletedAt:        optionalTimestamp(execution.CompletedAt),
+		CostUsd:            execution.CostUSD,
+		CommitHash:         execution.CommitHash,
+		RevertCommit:       execution.RevertCommit,
+		RollbackIssueId:    execution.RollbackIssueID,
+	}
+	if execution.AgentDuration != 0 {
+		out.AgentDuration = durationpb.New(execution.AgentDuration)
+	}
+	return out
+}
+
+// toIssueEvent converts an issue audit event
+func toIssueEvent(event *types.Event) *vcpb.IssueEvent {
+	return &vcpb.IssueEvent{
+		Id:        event.ID,
+		IssueId:   event.IssueID,
+		EventType: string(event.EventType),
+		Actor:     event.Actor,
+		OldValue:  event.OldValue,
+		NewValue:  event.NewValue,
+		Comment:   event.Comment,
+		CreatedAt: timestamp(event.CreatedAt),
+	}
+}
+
+// toAgentEvent converts an agent event. Its data is converted through
+// JSON, as the REST API returns it.
+func toAgentEvent(event *events.AgentEvent) *vcpb.AgentEvent {
+	out := &vcpb.AgentEvent{
+		Id:         event.ID,
+		Type:       string(event.Type),
+		Timestamp:  timestamp(event.Timestamp),
+		IssueId:    event.IssueID,
+		ExecutorId: event.ExecutorID,
+		AgentId:    event.AgentID,
+		Severity:   string(event.Severity),
+		Message:    event.Message,
+	}
+	if len(event.Data) > 0 {
+		var data map[string]interface{}
+		if raw, err := json.Marshal(event.Data); err == nil && json.Unmarshal(raw, &data) == nil {
+			out.Data, _ = structpb.NewStruct(data)
+		}
+	}
+	return out
+}
@@ -0,0 +1,187 @@
+// Package grpcapi serves VC's gRPC API (see api/vcpb), the low-latency
+// counterpart of the REST API for programs that integrate with VC: the
+// same core operations on issues, executions and events, plus WatchEvents
+// to stream new events instead of polling for them.
+//
+// Every call needs an "authorization: Bearer <token>" metadata entry; the
+// tokens are the REST API's, and changes are recorded as made by the
+// token's actor.
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/api/vcpb"
+	"github.com/steveyegge/vc/internal/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Config holds gRPC server configuration
+type Config struct {
+	Store storage.Storage
+
+	// Tokens maps each accepted bearer token to the actor it acts as
+	// (see config.ServeConfig.ParseTokens)
+	Tokens map[string]string
+}
+
+// Server implements the VC gRPC service over a store
+type Server struct {
+	vcpb.UnimplementedVCServer
+
+	store  storage.Storage
+	tokens map[string]string
+
+	// shutdown is closed when Serve's context is done, ending watches
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+// NewServer creates a gRPC API server
+func NewServer(cfg Config) (*Server, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if len(cfg.Tokens) == 0 {
+		return nil, fmt.Errorf("at least one API token is required")
+	}
+	return &Server{store: cfg.Store, tokens: cfg.Tokens, shutdown: make(chan struct{})}, nil
+}
+
+// GRPCServer returns a grpc.Server with the VC service registered and every
+// call authenticated
+func (s *Server) GRPCServer() *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(s.authenticateUnary),
+		grpc.StreamInterceptor(s.authenticateStream),
+	)
+	vcpb.RegisterVCServer(server, s)
+	return server
+}
+
+// ListenAndServe serves the API on addr until ctx is done, then shuts down
+// gracefully
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the API on listener until ctx is done. Open WatchEvents
+// streams end when ctx is done.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	server := s.GRPCServer()
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		s.shutdownOnce.Do(func() { close(s.shutdown) })
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(10 * time.Second):
+			server.Stop()
+		}
+		return nil
+	}
+}
+
+// actorKey carries the authenticated actor on a call's context
+type actorKey struct{}
+
+// authenticateUnary rejects unary calls without a valid bearer token
+func (s *Server) authenticateUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authenticateStream rejects streaming calls without a valid bearer token
+func (s *Server) authenticateStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream is a stream whose context carries the actor
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context { return s.ctx }
+
+// authenticate returns ctx with the actor of the call's bearer token
+func (s *Server) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if values := md.Get("authorization"); len(values) > 0 {
+		token, _ = strings.CutPrefix(values[0], "Bearer ")
+	}
+	// Compare against every token so timing doesn't reveal which one matched
+	var actor string
+	for candidate, candidateActor := range s.tokens {
+		if token != "" && subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			actor = candidateActor
+		}
+	}
+	if actor == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+	return context.WithValue(ctx, actorKey{}, actor), nil
+}
+
+// actor returns the authenticated actor of a call
+func actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// errNotFound marks errors answered with codes.NotFound
+var errNotFound = errors.New("not found")
+
+// invalidArgument returns an error answered with codes.InvalidArgument
+func invalidArgument(format string, args ...interface{}) error {
+	return status.Errorf(codes.InvalidArgument, format, args...)
+}
+
+// toStatus converts err to a gRPC status error with a matching code
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, errNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, storage.ErrReadOnly):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
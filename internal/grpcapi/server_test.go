@@ -0,0 +1,226 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/api/vcpb"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const testToken = "0123456789abcdef"
+
+// newTestClient serves the API over store on a local port and returns a
+// client authenticated with token
+func newTestClient(t *testing.T, store storage.Storage, token string) vcpb.VCClient {
+	t.Helper()
+	server, err := NewServer(Config{Store: store, Tokens: map[string]string{testToken: "alice"}})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = server.Serve(ctx, listener)
+		close(done)
+	}()
+
+	client, conn, err := vcpb.NewClient(listener.Addr().String(), token)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		<-done
+	})
+	return client
+}
+
+func wantCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("error = %v, want code %s", err, want)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	client := newTestClient(t, memory.New(), "wrong-token-0000")
+	_, err := client.ListIssues(context.Background(), &vcpb.ListIssuesRequest{})
+	wantCode(t, err, codes.Unauthenticated)
+}
+
+func TestIssueLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	client := newTestClient(t, store, testToken)
+
+	created, err := client.CreateIssue(ctx, &vcpb.CreateIssueRequest{
+		Title:              "Add retries",
+		AcceptanceCriteria: "Retries happen",
+		Priority:           proto.Int32(1),
+		Labels:             []string{"backend"},
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if created.Status != "open" || created.Priority != 1 || created.IssueType != "task" || len(created.Labels) != 1 {
+		t.Errorf("created = %+v", created)
+	}
+	_, err = client.CreateIssue(ctx, &vcpb.CreateIssueRequest{Title: "Bad", Priority: proto.Int32(9)})
+	wantCode(t, err, codes.InvalidArgument)
+
+	updated, err := client.UpdateIssue(ctx, &vcpb.UpdateIssueRequest{Id: created.Id, Status: proto.String("in_progress"), Assignee: proto.String("bob")})
+	if err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if updated.Status != "in_progress" || updated.Assignee != "bob" || updated.Title != "Add retries" {
+		t.Errorf("updated = %+v", updated)
+	}
+	_, err = client.UpdateIssue(ctx, &vcpb.UpdateIssueRequest{Id: created.Id, Status: proto.String("closed")})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = client.UpdateIssue(ctx, &vcpb.UpdateIssueRequest{Id: "vc-missing", Title: proto.String("x")})
+	wantCode(t, err, codes.NotFound)
+
+	comment, err := client.AddComment(ctx, &vcpb.AddCommentRequest{IssueId: created.Id, Body: "Looks good"})
+	if err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if comment.Author != "alice" || comment.Body != "Looks good" {
+		t.Errorf("comment = %+v", comment)
+	}
+
+	closed, err := client.CloseIssue(ctx, &vcpb.CloseIssueRequest{Id: created.Id, Reason: "Shipped"})
+	if err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+	if closed.Status != "closed" || closed.ClosedAt == nil {
+		t.Errorf("closed = %+v", closed)
+	}
+
+	list, err := client.ListIssues(ctx, &vcpb.ListIssuesRequest{Status: "closed", Page: &vcpb.PageRequest{Limit: 10}})
+	if err != nil {
+		t.Fatalf("ListIssues() error = %v", err)
+	}
+	if len(list.Issues) != 1 || list.Issues[0].Id != created.Id || list.NextCursor != "" {
+		t.Errorf("ListIssues() = %+v", list)
+	}
+
+	history, err := client.ListEvents(ctx, &vcpb.ListEventsRequest{IssueId: created.Id})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	var closedBy string
+	for _, event := range history.Events {
+		if event.EventType == string(types.EventClosed) {
+			closedBy = event.Actor
+		}
+	}
+	if closedBy != "alice" {
+		t.Errorf("audit trail = %+v, want a close by alice", history.Events)
+	}
+}
+
+func TestExecutions(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	client := newTestClient(t, store, testToken)
+	issue := &types.Issue{Title: "Work", AcceptanceCriteria: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded,
+		CostUSD: 0.5, AgentDuration: 90 * time.Second}
+	if err := store.CreateExecution(ctx, execution); err != nil {
+		t.Fatalf("CreateExecution() error = %v", err)
+	}
+
+	list, err := client.ListExecutions(ctx, &vcpb.ListExecutionsRequest{IssueId: issue.ID})
+	if err != nil {
+		t.Fatalf("ListExecutions() error = %v", err)
+	}
+	if len(list.Executions) != 1 || list.Executions[0].CostUsd != 0.5 || list.Executions[0].AgentDuration.AsDuration() != 90*time.Second {
+		t.Errorf("ListExecutions() = %+v", list)
+	}
+	got, err := client.GetExecution(ctx, &vcpb.GetExecutionRequest{Id: execution.ID})
+	if err != nil || got.Status != "succeeded" {
+		t.Errorf("GetExecution() = %+v, %v", got, err)
+	}
+	_, err = client.GetExecution(ctx, &vcpb.GetExecutionRequest{Id: 999})
+	wantCode(t, err, codes.NotFound)
+	_, err = client.ListExecutions(ctx, &vcpb.ListExecutionsRequest{Status: "exploded"})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestWatchEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	store := memory.New()
+	client := newTestClient(t, store, testToken)
+	issue := &types.Issue{Title: "Work", AcceptanceCriteria: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+
+	stream, err := client.WatchEvents(ctx, &vcpb.WatchEventsRequest{
+		AgentEvents:     true,
+		AgentEventTypes: []string{string(events.EventTypeProgress)},
+	})
+	if err != nil {
+		t.Fatalf("WatchEvents() error = %v", err)
+	}
+	// The stream starts from now; give the server its first check
+	time.Sleep(100 * time.Millisecond)
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "executor"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	for _, event := range []*events.AgentEvent{
+		{Type: events.EventTypeFileModified, Timestamp: time.Now(), IssueID: issue.ID, Severity: events.SeverityInfo, Message: "skipped"},
+		{Type: events.EventTypeProgress, Timestamp: time.Now(), IssueID: issue.ID, Severity: events.SeverityInfo, Message: "halfway",
+			Data: map[string]interface{}{"step": 2, "files": []string{"a.go"}}},
+	} {
+		if err := store.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("StoreAgentEvent() error = %v", err)
+		}
+	}
+
+	var issueEvent *vcpb.IssueEvent
+	var agentEvent *vcpb.AgentEvent
+	for issueEvent == nil || agentEvent == nil {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		switch event := resp.Event.(type) {
+		case *vcpb.WatchEventsResponse_IssueEvent:
+			if issueEvent != nil {
+				t.Errorf("extra issue event %+v", event.IssueEvent)
+			}
+			issueEvent = event.IssueEvent
+		case *vcpb.WatchEventsResponse_AgentEvent:
+			if agentEvent != nil {
+				t.Errorf("extra agent event %+v", event.AgentEvent)
+			}
+			agentEvent = event.AgentEvent
+		}
+	}
+	if issueEvent.EventType != string(types.EventStatusChanged) || issueEvent.Actor != "executor" {
+		t.Errorf("issue event = %+v", issueEvent)
+	}
+	if agentEvent.Message != "halfway" || agentEvent.Data.GetFields()["step"].GetNumberValue() != 2 {
+		t.Errorf("agent event = %+v, want only the progress event", agentEvent)
+	}
+}
@@ -0,0 +1,316 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/vc/api/vcpb"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// GetIssue implements vcpb.VCServer
+func (s *Server) GetIssue(ctx context.Context, req *vcpb.GetIssueRequest) (*vcpb.Issue, error) {
+	issue, err := s.issueDetail(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return issue, nil
+}
+
+// issueDetail loads an issue with its labels and dependencies
+func (s *Server) issueDetail(ctx context.Context, id string) (*vcpb.Issue, error) {
+	issue, err := s.getIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.store.GetLabels(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.store.GetDependencyRecords(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toIssue(issue, labels, deps), nil
+}
+
+// getIssue loads an issue, failing with errNotFound if it doesn't exist
+func (s *Server) getIssue(ctx context.Context, id string) (*types.Issue, error) {
+	if id == "" {
+		return nil, invalidArgument("issue ID is required")
+	}
+	issue, err := s.store.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s: %w", id, errNotFound)
+	}
+	return issue, nil
+}
+
+// pageRequest converts a page request, validating it
+func pageRequest(page *vcpb.PageRequest) (types.PageRequest, error) {
+	out := types.PageRequest{
+		Cursor: page.GetCursor(),
+		Limit:  int(page.GetLimit()),
+		Since:  fromTimestamp(page.GetSince()),
+	}
+	if page.GetDescending() {
+		out.Order = types.SortDescending
+	}
+	if err := out.Validate(); err != nil {
+		return out, invalidArgument("%v", err)
+	}
+	return out, nil
+}
+
+// ListIssues implements vcpb.VCServer
+func (s *Server) ListIssues(ctx context.Context, req *vcpb.ListIssuesRequest) (*vcpb.ListIssuesResponse, error) {
+	filter := types.IssueFilter{Labels: req.GetLabels(), Project: req.GetProject()}
+	if req.GetStatus() != "" {
+		status := types.Status(req.GetStatus())
+		if !status.IsValid() {
+			return nil, invalidArgument("invalid status: %q", req.GetStatus())
+		}
+		filter.Status = &status
+	}
+	if req.GetIssueType() != "" {
+		issueType := types.IssueType(req.GetIssueType())
+		if !issueType.IsValid() {
+			return nil, invalidArgument("invalid issue type: %q", req.GetIssueType())
+		}
+		filter.IssueType = &issueType
+	}
+	if req.Priority != nil {
+		priority := int(req.GetPriority())
+		filter.Priority = &priority
+	}
+	if req.GetAssignee() != "" {
+		assignee := req.GetAssignee()
+		filter.Assignee = &assignee
+	}
+	page, err := pageRequest(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetOrderByUpdated() {
+		page.OrderBy = types.IssueOrderUpdated
+	}
+
+	result, err := s.store.SearchIssuesPage(ctx, req.GetQuery(), filter, page)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &vcpb.ListIssuesResponse{NextCursor: result.NextCursor}
+	for _, issue := range result.Issues {
+		resp.Issues = append(resp.Issues, toIssue(issue, nil, nil))
+	}
+	return resp, nil
+}
+
+// CreateIssue implements vcpb.VCServer
+func (s *Server) CreateIssue(ctx context.Context, req *vcpb.CreateIssueRequest) (*vcpb.Issue, error) {
+	issue := &types.Issue{
+		Title:              req.GetTitle(),
+		Description:        req.GetDescription(),
+		Design:             req.GetDesign(),
+		AcceptanceCriteria: req.GetAcceptanceCriteria(),
+		Notes:              req.GetNotes(),
+		Status:             types.StatusOpen,
+		Priority:           2,
+		IssueType:          types.IssueType(req.GetIssueType()),
+		Assignee:           req.GetAssignee(),
+	}
+	if req.Priority != nil {
+		issue.Priority = int(req.GetPriority())
+	}
+	if req.EstimatedMinutes != nil {
+		minutes := int(req.GetEstimatedMinutes())
+		issue.EstimatedMinutes = &minutes
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = types.TypeTask
+	}
+	if err := issue.Validate(); err != nil {
+		return nil, invalidArgument("%v", err)
+	}
+
+	if err := s.store.CreateIssue(ctx, issue, actor(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+	for _, label := range req.GetLabels() {
+		if err := s.store.AddLabel(ctx, issue.ID, label, actor(ctx)); err != nil {
+			return nil, toStatus(fmt.Errorf("created %s but failed to add label %s: %w", issue.ID, label, err))
+		}
+	}
+	detail, err := s.issueDetail(ctx, issue.ID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return detail, nil
+}
+
+// UpdateIssue implements vcpb.VCServer
+func (s *Server) UpdateIssue(ctx context.Context, req *vcpb.UpdateIssueRequest) (*vcpb.Issue, error) {
+	id := req.GetId()
+	issue, err := s.getIssue(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	// Validate the result of the update before applying it
+	updated := *issue
+	updates := map[string]interface{}{}
+	setString := func(field string, value *string, dest *string) {
+		if value != nil {
+			updates[field] = *value
+			*dest = *value
+		}
+	}
+	setString("title", req.Title, &updated.Title)
+	setString("description", req.Description, &updated.Description)
+	setString("design", req.Design, &updated.Design)
+	setString("acceptance_criteria", req.AcceptanceCriteria, &updated.AcceptanceCriteria)
+	setString("notes", req.Notes, &updated.Notes)
+	setString("assignee", req.Assignee, &updated.Assignee)
+	if req.Status != nil {
+		status := types.Status(req.GetStatus())
+		if status == types.StatusClosed {
+			return nil, invalidArgument("close issues with CloseIssue")
+		}
+		updates["status"] = string(status)
+		updated.Status = status
+	}
+	if req.Priority != nil {
+		updates["priority"] = int(req.GetPriority())
+		updated.Priority = int(req.GetPriority())
+	}
+	if req.EstimatedMinutes != nil {
+		minutes := int(req.GetEstimatedMinutes())
+		updates["estimated_minutes"] = minutes
+		updated.EstimatedMinutes = &minutes
+	}
+	if len(updates) == 0 {
+		return nil, invalidArgument("nothing to update")
+	}
+	if err := updated.Validate(); err != nil {
+		return nil, invalidArgument("%v", err)
+	}
+
+	if req.Status != nil {
+		s.store.LogStatusChangeFromUpdates(ctx, id, updates, actor(ctx), "updated via API")
+	}
+	if err := s.store.UpdateIssue(ctx, id, updates, actor(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+	detail, err := s.issueDetail(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return detail, nil
+}
+
+// CloseIssue implements vcpb.VCServer
+func (s *Server) CloseIssue(ctx context.Context, req *vcpb.CloseIssueRequest) (*vcpb.Issue, error) {
+	reason := req.GetReason()
+	if reason == "" {
+		reason = "Closed via API"
+	}
+	if _, err := s.getIssue(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.store.CloseIssue(ctx, req.GetId(), reason, actor(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+	detail, err := s.issueDetail(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return detail, nil
+}
+
+// AddComment implements vcpb.VCServer
+func (s *Server) AddComment(ctx context.Context, req *vcpb.AddCommentRequest) (*vcpb.Comment, error) {
+	if strings.TrimSpace(req.GetBody()) == "" {
+		return nil, invalidArgument("comment body is required")
+	}
+	id := req.GetIssueId()
+	if _, err := s.getIssue(ctx, id); err != nil {
+		return nil, toStatus(err)
+	}
+	if err := s.store.AddComment(ctx, id, actor(ctx), req.GetBody()); err != nil {
+		return nil, toStatus(err)
+	}
+	comments, err := s.store.GetComments(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	// Comments are oldest first; find ours from the end
+	for i := len(comments) - 1; i >= 0; i-- {
+		if comments[i].Author == actor(ctx) && comments[i].Body == req.GetBody() {
+			return toComment(comments[i]), nil
+		}
+	}
+	return nil, toStatus(fmt.Errorf("comment on %s was added but can't be found", id))
+}
+
+// ListExecutions implements vcpb.VCServer
+func (s *Server) ListExecutions(ctx context.Context, req *vcpb.ListExecutionsRequest) (*vcpb.ListExecutionsResponse, error) {
+	filter := types.ExecutionFilter{
+		IssueID: req.GetIssueId(),
+		Status:  types.ExecutionStatus(req.GetStatus()),
+		Since:   fromTimestamp(req.GetSince()),
+		Limit:   int(req.GetLimit()),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, invalidArgument("invalid status: %q", filter.Status)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 50
+	}
+	executions, err := s.store.ListExecutions(ctx, filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &vcpb.ListExecutionsResponse{}
+	for _, execution := range executions {
+		resp.Executions = append(resp.Executions, toExecution(execution))
+	}
+	return resp, nil
+}
+
+// GetExecution implements vcpb.VCServer
+func (s *Server) GetExecution(ctx context.Context, req *vcpb.GetExecutionRequest) (*vcpb.Execution, error) {
+	execution, err := s.store.GetExecution(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if execution == nil {
+		return nil, toStatus(fmt.Errorf("execution %d: %w", req.GetId(), errNotFound))
+	}
+	return toExecution(execution), nil
+}
+
+// ListEvents implements vcpb.VCServer
+func (s *Server) ListEvents(ctx context.Context, req *vcpb.ListEventsRequest) (*vcpb.ListEventsResponse, error) {
+	if req.GetIssueId() != "" {
+		if _, err := s.getIssue(ctx, req.GetIssueId()); err != nil {
+			return nil, toStatus(err)
+		}
+	}
+	page, err := pageRequest(req.GetPage())
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.store.GetEventsPage(ctx, req.GetIssueId(), page)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &vcpb.ListEventsResponse{NextCursor: result.NextCursor}
+	for _, event := range result.Events {
+		resp.Events = append(resp.Events, toIssueEvent(event))
+	}
+	return resp, nil
+}
@@ -0,0 +1,129 @@
+package grpcapi
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/vc/api/vcpb"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// watchInterval is how often WatchEvents checks the database for new events
+const watchInterval = 500 * time.Millisecond
+
+// watcher tracks where a WatchEvents stream has read up to
+type watcher struct {
+	req    *vcpb.WatchEventsRequest
+	stream vcpb.VC_WatchEventsServer
+	types  map[string]bool // Agent event types to send; nil for all
+
+	issueCursor string
+	issueSince  time.Time
+	agentSince  time.Time
+	seenAgent   map[string]time.Time
+}
+
+// WatchEvents implements vcpb.VCServer
+func (s *Server) WatchEvents(req *vcpb.WatchEventsRequest, stream vcpb.VC_WatchEventsServer) error {
+	ctx := stream.Context()
+	if req.GetIssueId() != "" {
+		if _, err := s.getIssue(ctx, req.GetIssueId()); err != nil {
+			return toStatus(err)
+		}
+	}
+	since := fromTimestamp(req.GetSince())
+	if since.IsZero() {
+		since = time.Now()
+	}
+	w := &watcher{req: req, stream: stream, issueSince: since, agentSince: since, seenAgent: map[string]time.Time{}}
+	if len(req.GetAgentEventTypes()) > 0 {
+		w.types = map[string]bool{}
+		for _, eventType := range req.GetAgentEventTypes() {
+			w.types[eventType] = true
+		}
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.sendIssueEvents(w); err != nil {
+			return toStatus(err)
+		}
+		if req.GetAgentEvents() {
+			if err := s.sendAgentEvents(w); err != nil {
+				return toStatus(err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.shutdown:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendIssueEvents sends the audit events recorded since the last check,
+// walking the audit trail from the last event sent
+func (s *Server) sendIssueEvents(w *watcher) error {
+	for {
+		page := types.PageRequest{Cursor: w.issueCursor, Limit: types.MaxPageSize}
+		if w.issueCursor == "" {
+			page.Since = w.issueSince
+		}
+		result, err := s.store.GetEventsPage(w.stream.Context(), w.req.GetIssueId(), page)
+		if err != nil {
+			return err
+		}
+		for _, event := range result.Events {
+			resp := &vcpb.WatchEventsResponse{Event: &vcpb.WatchEventsResponse_IssueEvent{IssueEvent: toIssueEvent(event)}}
+			if err := w.stream.Send(resp); err != nil {
+				return err
+			}
+			w.issueCursor = types.PageCursor{Key: types.PageKey(event.CreatedAt), ID: strconv.FormatInt(event.ID, 10)}.Encode()
+		}
+		if result.NextCursor == "" {
+			return nil
+		}
+	}
+}
+
+// sendAgentEvents sends the agent events recorded since the last check,
+// oldest first
+func (s *Server) sendAgentEvents(w *watcher) error {
+	// Ask from a second early in case timestamps are stored with less
+	// precision; events already sent are skipped below
+	after := w.agentSince.Add(-time.Second)
+	agentEvents, err := s.store.GetAgentEvents(w.stream.Context(), events.EventFilter{IssueID: w.req.GetIssueId(), AfterTime: after})
+	if err != nil {
+		return err
+	}
+	latest := w.agentSince
+	// Newest first
+	for i := len(agentEvents) - 1; i >= 0; i-- {
+		event := agentEvents[i]
+		if _, seen := w.seenAgent[event.ID]; seen || event.Timestamp.Before(w.agentSince) {
+			continue
+		}
+		w.seenAgent[event.ID] = event.Timestamp
+		if event.Timestamp.After(latest) {
+			latest = event.Timestamp
+		}
+		if w.types != nil && !w.types[string(event.Type)] {
+			continue
+		}
+		resp := &vcpb.WatchEventsResponse{Event: &vcpb.WatchEventsResponse_AgentEvent{AgentEvent: toAgentEvent(event)}}
+		if err := w.stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	w.agentSince = latest
+	for id, at := range w.seenAgent {
+		if at.Before(after) {
+			delete(w.seenAgent, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package hosting
+
+import "context"
+
+// Check run conclusions
+const (
+	CheckSuccess = "success"
+	CheckFailure = "failure"
+	CheckSkipped = "skipped"
+)
+
+// CheckRun is the finished result of a check on a commit, shown on the pull
+// requests it is the head of
+type CheckRun struct {
+	Name        string
+	HeadSHA     string
+	Conclusion  string // CheckSuccess, CheckFailure or CheckSkipped
+	Title       string
+	Summary     string // Markdown
+	Text        string // Markdown details, e.g. the check's output
+	Annotations []CheckAnnotation
+}
+
+// CheckAnnotation points a check run's reader at a line of a file
+type CheckAnnotation struct {
+	Path    string // Relative to the repository root
+	Line    int
+	Title   string
+	Message string
+}
+
+// CheckReporter is implemented by providers that show check runs on pull
+// requests (GitHub)
+type CheckReporter interface {
+	// CreateCheckRun reports a finished check run, returning its URL
+	CreateCheckRun(ctx context.Context, run CheckRun) (string, error)
+}
+
+var _ CheckReporter = (*GitHub)(nil)
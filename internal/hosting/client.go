@@ -0,0 +1,132 @@
+package hosting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// APIError is an error response from a provider's API
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+	Details    []string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s API returned %d: %s", e.Provider, e.StatusCode, e.Message)
+	if len(e.Details) > 0 {
+		msg += " (" + strings.Join(e.Details, "; ") + ")"
+	}
+	return msg
+}
+
+// apiClient sends JSON requests to a provider's REST API
+type apiClient struct {
+	provider string // For error messages
+	baseURL  string
+	headers  map[string]string // Set on every request, including authentication
+	http     *http.Client
+}
+
+func newAPIClient(provider, baseURL string, headers map[string]string) *apiClient {
+	return &apiClient{
+		provider: provider,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		headers:  headers,
+		http:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request with body encoded as JSON and decodes the response into out
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return c.apiError(resp.StatusCode, data)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// apiError builds an APIError from an error response. GitHub reports
+// {"message": "...", "errors": [...]}; GitLab's "message" may also be a
+// list or an object of field errors, and some errors only set "error".
+func (c *apiClient) apiError(status int, data []byte) *APIError {
+	apiErr := &APIError{Provider: c.provider, StatusCode: status, Message: http.StatusText(status)}
+	var payload struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+		Errors  []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(data, &payload) != nil {
+		return apiErr
+	}
+
+	var message string
+	var messages []string
+	var fields map[string][]string
+	switch {
+	case json.Unmarshal(payload.Message, &message) == nil && message != "":
+		apiErr.Message = message
+	case json.Unmarshal(payload.Message, &messages) == nil && len(messages) > 0:
+		apiErr.Message = strings.Join(messages, "; ")
+	case json.Unmarshal(payload.Message, &fields) == nil && len(fields) > 0:
+		for field, errs := range fields {
+			apiErr.Details = append(apiErr.Details, field+" "+strings.Join(errs, ", "))
+		}
+		sort.Strings(apiErr.Details)
+	case payload.Error != "":
+		apiErr.Message = payload.Error
+	}
+	for _, e := range payload.Errors {
+		detail := e.Message
+		if detail == "" {
+			detail = strings.TrimSpace(e.Field + " " + e.Code)
+		}
+		apiErr.Details = append(apiErr.Details, detail)
+	}
+	return apiErr
+}
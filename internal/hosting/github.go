@@ -0,0 +1,250 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+// GitHub is the GitHub provider
+type GitHub struct {
+	api   *apiClient
+	token string
+	owner string
+	repo  string
+}
+
+// NewGitHub creates a GitHub provider for owner/repo
+func NewGitHub(cfg config.GitHubConfig, owner, repo string) *GitHub {
+	return &GitHub{
+		api: newAPIClient("GitHub", cfg.APIURL, map[string]string{
+			"Accept":               "application/vnd.github+json",
+			"X-GitHub-Api-Version": "2022-11-28",
+			"Authorization":        "Bearer " + cfg.Token,
+		}),
+		token: cfg.Token,
+		owner: owner,
+		repo:  repo,
+	}
+}
+
+// Name returns config.HostingGitHub
+func (g *GitHub) Name() string { return config.HostingGitHub }
+
+// Repo returns the repository as owner/name
+func (g *GitHub) Repo() string { return g.owner + "/" + g.repo }
+
+// PushUser returns the user name GitHub expects with a token
+func (g *GitHub) PushUser() string { return "x-access-token" }
+
+// Token returns the API token
+func (g *GitHub) Token() string { return g.token }
+
+// githubPullRequest is the part of a GitHub pull request VC uses
+type githubPullRequest struct {
+	Number   int        `json:"number"`
+	URL      string     `json:"html_url"`
+	Title    string     `json:"title"`
+	State    string     `json:"state"` // "open" or "closed"
+	Draft    bool       `json:"draft"`
+	Merged   bool       `json:"merged"`
+	MergedAt *time.Time `json:"merged_at"`
+	Head     struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// status returns the pull request's status: open, draft, merged or closed
+func (pr *githubPullRequest) status() string {
+	switch {
+	case pr.Merged || pr.MergedAt != nil:
+		return StatusMerged
+	case pr.State == "closed":
+		return StatusClosed
+	case pr.Draft:
+		return StatusDraft
+	}
+	return StatusOpen
+}
+
+func (pr *githubPullRequest) toPullRequest() *PullRequest {
+	return &PullRequest{
+		Number:       pr.Number,
+		URL:          pr.URL,
+		Title:        pr.Title,
+		Status:       pr.status(),
+		SourceBranch: pr.Head.Ref,
+		TargetBranch: pr.Base.Ref,
+	}
+}
+
+// CreatePullRequest opens a pull request, then labels it and requests
+// reviews
+func (g *GitHub) CreatePullRequest(ctx context.Context, req NewPullRequest) (*PullRequest, error) {
+	body := map[string]interface{}{
+		"title": req.Title,
+		"body":  req.Body,
+		"head":  req.SourceBranch,
+		"base":  req.TargetBranch,
+		"draft": req.Draft,
+	}
+	var pr githubPullRequest
+	if err := g.api.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/pulls", g.owner, g.repo), body, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create pull request for %s: %w", req.SourceBranch, err)
+	}
+
+	// Pull requests are labelled through the issues API
+	if len(req.Labels) > 0 {
+		path := fmt.Sprintf("/repos/%s/%s/issues/%d/labels", g.owner, g.repo, pr.Number)
+		if err := g.api.do(ctx, http.MethodPost, path, map[string][]string{"labels": req.Labels}, nil); err != nil {
+			return pr.toPullRequest(), fmt.Errorf("failed to label pull request #%d: %w", pr.Number, err)
+		}
+	}
+	if len(req.Reviewers) > 0 || len(req.TeamReviewers) > 0 {
+		if err := g.requestReviews(ctx, pr.Number, req.Reviewers, req.TeamReviewers); err != nil {
+			return pr.toPullRequest(), err
+		}
+	}
+	return pr.toPullRequest(), nil
+}
+
+// requestReviews asks users and teams ("org/team") to review a pull
+// request. GitHub takes teams by slug, within the repository's organization.
+func (g *GitHub) requestReviews(ctx context.Context, number int, users, teams []string) error {
+	slugs := []string{}
+	for _, team := range teams {
+		_, slug, _ := strings.Cut(team, "/")
+		slugs = append(slugs, slug)
+	}
+	body := map[string][]string{"reviewers": append([]string{}, users...), "team_reviewers": slugs}
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/requested_reviewers", g.owner, g.repo, number)
+	if err := g.api.do(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to request reviews on pull request #%d: %w", number, err)
+	}
+	return nil
+}
+
+// GetPullRequest fetches a pull request by number
+func (g *GitHub) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var pr githubPullRequest
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d", g.owner, g.repo, number), nil, &pr); err != nil {
+		return nil, fmt.Errorf("failed to get pull request #%d: %w", number, err)
+	}
+	return pr.toPullRequest(), nil
+}
+
+// CommentOnPullRequest adds a comment to a pull request, through the issues API
+func (g *GitHub) CommentOnPullRequest(ctx context.Context, number int, body string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", g.owner, g.repo, number)
+	if err := g.api.do(ctx, http.MethodPost, path, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on pull request #%d: %w", number, err)
+	}
+	return nil
+}
+
+// githubCheckRuns is the part of a commit's check runs VC uses
+type githubCheckRuns struct {
+	CheckRuns []struct {
+		Name       string `json:"name"`
+		Status     string `json:"status"`     // "queued", "in_progress" or "completed"
+		Conclusion string `json:"conclusion"` // Set once completed
+		URL        string `json:"html_url"`
+	} `json:"check_runs"`
+}
+
+// githubCombinedStatus is the part of a commit's combined status VC uses
+type githubCombinedStatus struct {
+	Statuses []struct {
+		Context string `json:"context"`
+		State   string `json:"state"` // "pending", "success", "failure" or "error"
+		URL     string `json:"target_url"`
+	} `json:"statuses"`
+}
+
+// GetCIStatus returns the check runs (GitHub Actions and apps) and commit
+// statuses (older integrations) of a pull request's head commit
+func (g *GitHub) GetCIStatus(ctx context.Context, number int) (*CIStatus, error) {
+	var pr githubPullRequest
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/pulls/%d", g.owner, g.repo, number), nil, &pr); err != nil {
+		return nil, fmt.Errorf("failed to get pull request #%d: %w", number, err)
+	}
+
+	var checks []CICheck
+	var runs githubCheckRuns
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/commits/%s/check-runs", g.owner, g.repo, pr.Head.SHA), nil, &runs); err != nil {
+		return nil, fmt.Errorf("failed to get check runs of pull request #%d: %w", number, err)
+	}
+	for _, run := range runs.CheckRuns {
+		state := CIPending
+		if run.Status == "completed" {
+			switch run.Conclusion {
+			case "success", "neutral", "skipped":
+				state = CISuccess
+			default:
+				state = CIFailure
+			}
+		}
+		checks = append(checks, CICheck{Name: run.Name, State: state, URL: run.URL})
+	}
+
+	var combined githubCombinedStatus
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/commits/%s/status", g.owner, g.repo, pr.Head.SHA), nil, &combined); err != nil {
+		return nil, fmt.Errorf("failed to get commit statuses of pull request #%d: %w", number, err)
+	}
+	for _, status := range combined.Statuses {
+		state := CIPending
+		switch status.State {
+		case "success":
+			state = CISuccess
+		case "failure", "error":
+			state = CIFailure
+		}
+		checks = append(checks, CICheck{Name: status.Context, State: state, URL: status.URL})
+	}
+
+	return newCIStatus(checks), nil
+}
+
+// MergePullRequest merges a pull request with the given method
+func (g *GitHub) MergePullRequest(ctx context.Context, number int, opts MergeOptions) error {
+	method := opts.Method
+	if method == "" {
+		method = MergeCommit
+	}
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/merge", g.owner, g.repo, number)
+	if err := g.api.do(ctx, http.MethodPut, path, map[string]string{"merge_method": method}, nil); err != nil {
+		return fmt.Errorf("failed to merge pull request #%d: %w", number, err)
+	}
+	return nil
+}
+
+// githubRelease is the part of a GitHub release VC uses
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	URL     string `json:"html_url"`
+}
+
+// CreateRelease publishes a release for a pushed tag
+func (g *GitHub) CreateRelease(ctx context.Context, req NewRelease) (*Release, error) {
+	body := map[string]interface{}{
+		"tag_name":   req.Tag,
+		"name":       req.Name,
+		"body":       req.Body,
+		"draft":      req.Draft,
+		"prerelease": req.Prerelease,
+	}
+	var release githubRelease
+	if err := g.api.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/releases", g.owner, g.repo), body, &release); err != nil {
+		return nil, fmt.Errorf("failed to create release for %s: %w", req.Tag, err)
+	}
+	return &Release{Tag: release.TagName, Name: release.Name, URL: release.URL}, nil
+}
@@ -0,0 +1,89 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// GitHub's limits on check run output
+const (
+	maxCheckAnnotations = 50    // Per request; more are added by updating the run
+	maxCheckText        = 65535 // Characters of the summary and of the text
+)
+
+// githubCheckOutput is the output of a check run as GitHub takes it
+type githubCheckOutput struct {
+	Title       string                  `json:"title"`
+	Summary     string                  `json:"summary"`
+	Text        string                  `json:"text,omitempty"`
+	Annotations []githubCheckAnnotation `json:"annotations,omitempty"`
+}
+
+type githubCheckAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// CreateCheckRun reports a finished check run on the head commit, adding
+// annotations beyond the first 50 in further requests. GitHub only accepts
+// check runs from GitHub Apps, so other tokens get a 403.
+func (g *GitHub) CreateCheckRun(ctx context.Context, run CheckRun) (string, error) {
+	annotations := make([]githubCheckAnnotation, 0, len(run.Annotations))
+	for _, a := range run.Annotations {
+		annotations = append(annotations, githubCheckAnnotation{
+			Path: a.Path, StartLine: a.Line, EndLine: a.Line, AnnotationLevel: "failure", Title: a.Title, Message: a.Message,
+		})
+	}
+	output := githubCheckOutput{Title: run.Title, Summary: truncateCheckText(run.Summary), Text: truncateCheckText(run.Text)}
+	output.Annotations, annotations = splitAnnotations(annotations)
+
+	var created struct {
+		ID  int64  `json:"id"`
+		URL string `json:"html_url"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/check-runs", g.owner, g.repo)
+	body := map[string]interface{}{
+		"name":       run.Name,
+		"head_sha":   run.HeadSHA,
+		"status":     "completed",
+		"conclusion": run.Conclusion,
+		"output":     output,
+	}
+	if err := g.api.do(ctx, http.MethodPost, path, body, &created); err != nil {
+		return "", fmt.Errorf("failed to create check run %q: %w", run.Name, err)
+	}
+	for len(annotations) > 0 {
+		more := githubCheckOutput{Title: output.Title, Summary: output.Summary}
+		more.Annotations, annotations = splitAnnotations(annotations)
+		update := map[string]interface{}{"output": more}
+		if err := g.api.do(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", path, created.ID), update, nil); err != nil {
+			return created.URL, fmt.Errorf("failed to annotate check run %q: %w", run.Name, err)
+		}
+	}
+	return created.URL, nil
+}
+
+// splitAnnotations returns the annotations that fit in one request, and the
+// rest
+func splitAnnotations(annotations []githubCheckAnnotation) (batch, rest []githubCheckAnnotation) {
+	if len(annotations) <= maxCheckAnnotations {
+		return annotations, nil
+	}
+	return annotations[:maxCheckAnnotations], annotations[maxCheckAnnotations:]
+}
+
+// truncateCheckText cuts text to what GitHub accepts, keeping its end,
+// where tools print their failures
+func truncateCheckText(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxCheckText {
+		return text
+	}
+	const marker = "…(truncated)\n"
+	return marker + string(runes[len(runes)-maxCheckText+len([]rune(marker)):])
+}
@@ -0,0 +1,74 @@
+package hosting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestGitHubCreateCheckRun(t *testing.T) {
+	var created map[string]interface{}
+	var updates []githubCheckOutput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/check-runs":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 77, "html_url": "https://github.com/acme/widgets/runs/77"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/widgets/check-runs/77":
+			var update struct {
+				Output githubCheckOutput `json:"output"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			updates = append(updates, update.Output)
+			_, _ = w.Write([]byte(`{"id": 77}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var annotations []CheckAnnotation
+	for i := 1; i <= maxCheckAnnotations+1; i++ {
+		annotations = append(annotations, CheckAnnotation{Path: "main.go", Line: i, Message: fmt.Sprintf("problem %d", i)})
+	}
+	provider := NewGitHub(config.GitHubConfig{Token: "ghs_app", APIURL: server.URL}, "acme", "widgets")
+	url, err := provider.CreateCheckRun(context.Background(), CheckRun{
+		Name: "vc / lint", HeadSHA: "abc123", Conclusion: CheckFailure, Title: "51 problems",
+		Summary: "Lint failed", Text: strings.Repeat("x", maxCheckText+10), Annotations: annotations,
+	})
+	if err != nil {
+		t.Fatalf("CreateCheckRun failed: %v", err)
+	}
+	if url != "https://github.com/acme/widgets/runs/77" {
+		t.Errorf("url = %q", url)
+	}
+	if created["name"] != "vc / lint" || created["head_sha"] != "abc123" || created["status"] != "completed" || created["conclusion"] != "failure" {
+		t.Errorf("unexpected check run %v", created)
+	}
+	output := created["output"].(map[string]interface{})
+	if text := output["text"].(string); len([]rune(text)) != maxCheckText || !strings.HasPrefix(text, "…(truncated)") {
+		t.Errorf("text of %d characters, want %d truncated", len([]rune(text)), maxCheckText)
+	}
+	first := output["annotations"].([]interface{})
+	if len(first) != maxCheckAnnotations {
+		t.Fatalf("created with %d annotations, want %d", len(first), maxCheckAnnotations)
+	}
+	if a := first[0].(map[string]interface{}); a["path"] != "main.go" || a["start_line"] != float64(1) || a["annotation_level"] != "failure" {
+		t.Errorf("unexpected annotation %v", a)
+	}
+	if len(updates) != 1 || len(updates[0].Annotations) != 1 || updates[0].Annotations[0].StartLine != maxCheckAnnotations+1 {
+		t.Errorf("expected the last annotation added by an update, got %+v", updates)
+	}
+}
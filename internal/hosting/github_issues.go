@@ -0,0 +1,229 @@
+package hosting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// githubIssue is the part of a GitHub issue VC uses
+type githubIssue struct {
+	Number int    `json:"number"`
+	URL    string `json:"html_url"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone *githubMilestone `json:"milestone"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PullRequest *struct{} `json:"pull_request"` // Set on pull requests, which the issues API lists too
+}
+
+func (i *githubIssue) toIssue() *Issue {
+	issue := &Issue{
+		Number:    i.Number,
+		URL:       i.URL,
+		Title:     i.Title,
+		Body:      i.Body,
+		State:     i.State,
+		Author:    i.User.Login,
+		UpdatedAt: i.UpdatedAt,
+	}
+	for _, label := range i.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	if i.Milestone != nil {
+		issue.Milestone = i.Milestone.Title
+	}
+	return issue
+}
+
+// githubMilestone is the part of a GitHub milestone VC uses
+type githubMilestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// githubComment is the part of a GitHub issue comment VC uses
+type githubComment struct {
+	ID       int64  `json:"id"`
+	Body     string `json:"body"`
+	IssueURL string `json:"issue_url"` // API URL ending in /issues/<number>
+	User     struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *githubComment) toComment() *IssueComment {
+	comment := &IssueComment{ID: c.ID, Author: c.User.Login, Body: c.Body, CreatedAt: c.CreatedAt}
+	if i := strings.LastIndex(c.IssueURL, "/"); i >= 0 {
+		comment.IssueNumber, _ = strconv.Atoi(c.IssueURL[i+1:])
+	}
+	return comment
+}
+
+// ListIssues returns the repository's issues, open and closed, updated at or
+// after since (every issue if since is zero). Pull requests are left out.
+func (g *GitHub) ListIssues(ctx context.Context, since time.Time) ([]*Issue, error) {
+	query := url.Values{"state": {"all"}, "sort": {"updated"}, "direction": {"asc"}}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	var issues []*Issue
+	err := listPages(ctx, g.api, fmt.Sprintf("/repos/%s/%s/issues", g.owner, g.repo), query, func(issue githubIssue) {
+		if issue.PullRequest == nil {
+			issues = append(issues, issue.toIssue())
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	return issues, nil
+}
+
+// CreateIssue opens an issue
+func (g *GitHub) CreateIssue(ctx context.Context, req NewIssue) (*Issue, error) {
+	body := map[string]interface{}{"title": req.Title, "body": req.Body}
+	if len(req.Labels) > 0 {
+		body["labels"] = req.Labels
+	}
+	if req.Milestone != "" {
+		number, err := g.milestone(ctx, req.Milestone)
+		if err != nil {
+			return nil, err
+		}
+		body["milestone"] = number
+	}
+	var issue githubIssue
+	if err := g.api.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/issues", g.owner, g.repo), body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to create issue %q: %w", req.Title, err)
+	}
+	return issue.toIssue(), nil
+}
+
+// UpdateIssue changes an issue's title, body, state or milestone
+func (g *GitHub) UpdateIssue(ctx context.Context, number int, update IssueUpdate) (*Issue, error) {
+	body := make(map[string]interface{})
+	if update.Title != nil {
+		body["title"] = *update.Title
+	}
+	if update.Body != nil {
+		body["body"] = *update.Body
+	}
+	if update.State != nil {
+		body["state"] = *update.State
+	}
+	if update.Milestone != nil {
+		body["milestone"] = nil
+		if *update.Milestone != "" {
+			number, err := g.milestone(ctx, *update.Milestone)
+			if err != nil {
+				return nil, err
+			}
+			body["milestone"] = number
+		}
+	}
+	var issue githubIssue
+	if err := g.api.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/issues/%d", g.owner, g.repo, number), body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to update issue #%d: %w", number, err)
+	}
+	return issue.toIssue(), nil
+}
+
+// AddIssueLabels adds labels to an issue. Labels the repository doesn't
+// have yet are created.
+func (g *GitHub) AddIssueLabels(ctx context.Context, number int, labels []string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/labels", g.owner, g.repo, number)
+	if err := g.api.do(ctx, http.MethodPost, path, map[string][]string{"labels": labels}, nil); err != nil {
+		return fmt.Errorf("failed to label issue #%d: %w", number, err)
+	}
+	return nil
+}
+
+// RemoveIssueLabel removes a label from an issue. Removing a label the
+// issue doesn't have is not an error.
+func (g *GitHub) RemoveIssueLabel(ctx context.Context, number int, label string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/labels/%s", g.owner, g.repo, number, url.PathEscape(label))
+	err := g.api.do(ctx, http.MethodDelete, path, nil, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove label %q from issue #%d: %w", label, number, err)
+	}
+	return nil
+}
+
+// ListComments returns the comments on the repository's issues created or
+// edited at or after since (every comment if since is zero), oldest first
+func (g *GitHub) ListComments(ctx context.Context, since time.Time) ([]*IssueComment, error) {
+	query := url.Values{"sort": {"created"}, "direction": {"asc"}}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	var comments []*IssueComment
+	err := listPages(ctx, g.api, fmt.Sprintf("/repos/%s/%s/issues/comments", g.owner, g.repo), query, func(comment githubComment) {
+		comments = append(comments, comment.toComment())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issue comments: %w", err)
+	}
+	return comments, nil
+}
+
+// ListIssueComments returns the comments on an issue, oldest first
+func (g *GitHub) ListIssueComments(ctx context.Context, number int) ([]*IssueComment, error) {
+	var comments []*IssueComment
+	err := listPages(ctx, g.api, fmt.Sprintf("/repos/%s/%s/issues/%d/comments", g.owner, g.repo, number), url.Values{}, func(comment githubComment) {
+		comments = append(comments, comment.toComment())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments on issue #%d: %w", number, err)
+	}
+	return comments, nil
+}
+
+// CreateComment adds a comment to an issue
+func (g *GitHub) CreateComment(ctx context.Context, number int, body string) (*IssueComment, error) {
+	var comment githubComment
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", g.owner, g.repo, number)
+	if err := g.api.do(ctx, http.MethodPost, path, map[string]string{"body": body}, &comment); err != nil {
+		return nil, fmt.Errorf("failed to comment on issue #%d: %w", number, err)
+	}
+	return comment.toComment(), nil
+}
+
+// milestone returns the number of the milestone titled title, creating it
+// if the repository doesn't have one
+func (g *GitHub) milestone(ctx context.Context, title string) (int, error) {
+	path := fmt.Sprintf("/repos/%s/%s/milestones", g.owner, g.repo)
+	number := 0
+	err := listPages(ctx, g.api, path, url.Values{"state": {"all"}}, func(m githubMilestone) {
+		if m.Title == title && number == 0 {
+			number = m.Number
+		}
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list milestones: %w", err)
+	}
+	if number != 0 {
+		return number, nil
+	}
+	var created githubMilestone
+	if err := g.api.do(ctx, http.MethodPost, path, map[string]string{"title": title}, &created); err != nil {
+		return 0, fmt.Errorf("failed to create milestone %q: %w", title, err)
+	}
+	return created.Number, nil
+}
@@ -0,0 +1,207 @@
+package hosting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestGitHubListIssues(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/issues" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("state") != "all" || query.Get("since") != "2026-01-02T03:04:05Z" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		pages = append(pages, query.Get("page"))
+		// A full first page, then a short one
+		var items []string
+		if query.Get("page") == "1" {
+			for i := 1; i <= pageSize; i++ {
+				items = append(items, fmt.Sprintf(`{"number": %d, "title": "Issue %d", "state": "open"}`, i, i))
+			}
+			// Pull requests are listed too
+			items[1] = `{"number": 2, "title": "A pull request", "state": "open", "pull_request": {}}`
+		} else {
+			items = append(items, `{"number": 101, "title": "Bug", "body": "Crashes", "state": "closed",
+				"labels": [{"name": "bug"}, {"name": "P1"}], "user": {"login": "octocat"}, "updated_at": "2026-01-03T00:00:00Z"}`)
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	issues, err := provider.ListIssues(context.Background(), time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ListIssues failed: %v", err)
+	}
+	if !reflect.DeepEqual(pages, []string{"1", "2"}) {
+		t.Errorf("requested pages %v, want [1 2]", pages)
+	}
+	if len(issues) != pageSize {
+		t.Fatalf("got %d issues, want %d (without the pull request)", len(issues), pageSize)
+	}
+	want := &Issue{Number: 101, Title: "Bug", Body: "Crashes", State: "closed", Labels: []string{"bug", "P1"},
+		Author: "octocat", UpdatedAt: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)}
+	if last := issues[len(issues)-1]; !reflect.DeepEqual(last, want) || !last.Closed() {
+		t.Errorf("last issue = %+v, want %+v", last, want)
+	}
+}
+
+func TestGitHubUpdateIssue(t *testing.T) {
+	var got map[string]interface{}
+	var added map[string][]string
+	var removed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/widgets/issues/7":
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			_, _ = w.Write([]byte(`{"number": 7, "title": "Renamed", "state": "closed", "labels": []}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/issues/7/labels":
+			if err := json.NewDecoder(r.Body).Decode(&added); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			_, _ = w.Write([]byte(`[]`))
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/repos/acme/widgets/issues/7/labels/"):
+			removed = append(removed, strings.TrimPrefix(r.URL.Path, "/repos/acme/widgets/issues/7/labels/"))
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Label does not exist"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	ctx := context.Background()
+	title, state := "Renamed", "closed"
+	issue, err := provider.UpdateIssue(ctx, 7, IssueUpdate{Title: &title, State: &state})
+	if err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	// Only the fields set are sent
+	want := map[string]interface{}{"title": "Renamed", "state": "closed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("request body = %+v, want %+v", got, want)
+	}
+	if issue.Number != 7 || issue.Title != "Renamed" || !issue.Closed() {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+
+	if err := provider.AddIssueLabels(ctx, 7, []string{"P1", "area: ui"}); err != nil {
+		t.Fatalf("AddIssueLabels failed: %v", err)
+	}
+	if !reflect.DeepEqual(added["labels"], []string{"P1", "area: ui"}) {
+		t.Errorf("added labels = %v", added)
+	}
+	// A label the issue doesn't have is already removed
+	if err := provider.RemoveIssueLabel(ctx, 7, "area: ui"); err != nil {
+		t.Fatalf("RemoveIssueLabel failed: %v", err)
+	}
+	if !reflect.DeepEqual(removed, []string{"area: ui"}) {
+		t.Errorf("removed labels = %v", removed)
+	}
+}
+
+func TestGitHubComments(t *testing.T) {
+	var posted map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/widgets/issues/comments":
+			_, _ = w.Write([]byte(`[{"id": 11, "body": "Seeing this too", "user": {"login": "octocat"},
+				"issue_url": "https://api.github.com/repos/acme/widgets/issues/7", "created_at": "2026-01-03T00:00:00Z"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/issues/7/comments":
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 12, "body": "Fixed", "user": {"login": "vc-bot"},
+				"issue_url": "https://api.github.com/repos/acme/widgets/issues/7"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	comments, err := provider.ListComments(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("ListComments failed: %v", err)
+	}
+	want := &IssueComment{ID: 11, IssueNumber: 7, Author: "octocat", Body: "Seeing this too", CreatedAt: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)}
+	if len(comments) != 1 || !reflect.DeepEqual(comments[0], want) {
+		t.Errorf("comments = %+v, want [%+v]", comments, want)
+	}
+
+	comment, err := provider.CreateComment(context.Background(), 7, "Fixed")
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+	if posted["body"] != "Fixed" || comment.ID != 12 || comment.IssueNumber != 7 {
+		t.Errorf("posted %v, got comment %+v", posted, comment)
+	}
+}
+
+func TestGitHubIssueMilestones(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/widgets/milestones":
+			if r.URL.Query().Get("state") != "all" {
+				t.Errorf("closed milestones aren't listed: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[{"number": 1, "title": "v1"}, {"number": 2, "title": "v2"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/milestones":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 3, "title": "v3"}`))
+		case r.URL.Path == "/repos/acme/widgets/issues" || r.URL.Path == "/repos/acme/widgets/issues/7":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			bodies = append(bodies, body)
+			_, _ = w.Write([]byte(`{"number": 7, "state": "open", "milestone": {"number": 2, "title": "v2"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	ctx := context.Background()
+	issue, err := provider.CreateIssue(ctx, NewIssue{Title: "Export", Milestone: "v2"})
+	if err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if issue.Milestone != "v2" {
+		t.Errorf("milestone = %q, want v2", issue.Milestone)
+	}
+	// A missing milestone is created, and "" removes the milestone
+	for _, title := range []string{"v3", ""} {
+		if _, err := provider.UpdateIssue(ctx, 7, IssueUpdate{Milestone: &title}); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+	}
+	want := []map[string]interface{}{{"title": "Export", "body": "", "milestone": float64(2)}, {"milestone": float64(3)}, {"milestone": nil}}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("request bodies = %+v, want %+v", bodies, want)
+	}
+}
@@ -0,0 +1,227 @@
+package hosting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestGitHubPullRequestStatus(t *testing.T) {
+	tests := []struct {
+		pr   githubPullRequest
+		want string
+	}{
+		{githubPullRequest{State: "open"}, StatusOpen},
+		{githubPullRequest{State: "open", Draft: true}, StatusDraft},
+		{githubPullRequest{State: "closed"}, StatusClosed},
+		{githubPullRequest{State: "closed", Merged: true}, StatusMerged},
+	}
+	for _, tt := range tests {
+		if got := tt.pr.status(); got != tt.want {
+			t.Errorf("status() of %+v = %q, want %q", tt.pr, got, tt.want)
+		}
+	}
+}
+
+func TestGitHubCreatePullRequest(t *testing.T) {
+	var got map[string]interface{}
+	var labels map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer ghp_secret" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/pulls":
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.com/acme/widgets/pull/7", "state": "open",
+				"head": {"ref": "vc/vc-1-feature"}, "base": {"ref": "main"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/issues/7/labels":
+			if err := json.NewDecoder(r.Body).Decode(&labels); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	pr, err := provider.CreatePullRequest(context.Background(), NewPullRequest{
+		Title: "Add feature", Body: "Details", SourceBranch: "vc/vc-1-feature", TargetBranch: "main", Labels: []string{"vc"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest failed: %v", err)
+	}
+	if got["title"] != "Add feature" || got["head"] != "vc/vc-1-feature" || got["base"] != "main" {
+		t.Errorf("unexpected request body: %+v", got)
+	}
+	if !reflect.DeepEqual(labels["labels"], []string{"vc"}) {
+		t.Errorf("labels = %v, want [vc]", labels)
+	}
+	want := &PullRequest{Number: 7, URL: "https://github.com/acme/widgets/pull/7", Status: StatusOpen, SourceBranch: "vc/vc-1-feature", TargetBranch: "main"}
+	if !reflect.DeepEqual(pr, want) {
+		t.Errorf("pull request = %+v, want %+v", pr, want)
+	}
+}
+
+func TestGitHubCreatePullRequestReviewers(t *testing.T) {
+	var requested map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/pulls":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 7, "state": "open", "head": {"ref": "vc/vc-1-feature"}, "base": {"ref": "main"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/pulls/7/requested_reviewers":
+			if err := json.NewDecoder(r.Body).Decode(&requested); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	_, err := provider.CreatePullRequest(context.Background(), NewPullRequest{
+		SourceBranch: "vc/vc-1-feature", TargetBranch: "main",
+		Reviewers: []string{"carol"}, TeamReviewers: []string{"acme/payments"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest failed: %v", err)
+	}
+	want := map[string][]string{"reviewers": {"carol"}, "team_reviewers": {"payments"}}
+	if !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested reviewers = %v, want %v", requested, want)
+	}
+}
+
+func TestGitHubAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message": "Validation Failed", "errors": [{"message": "A pull request already exists for acme:vc/vc-1-feature."}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	_, err := provider.CreatePullRequest(context.Background(), NewPullRequest{SourceBranch: "vc/vc-1-feature", TargetBranch: "main"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "422") || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("error should carry the status and GitHub's message, got: %v", err)
+	}
+}
+
+func TestGitHubGetCIStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/widgets/pulls/7":
+			_, _ = w.Write([]byte(`{"number": 7, "state": "open", "head": {"ref": "vc/vc-1-feature", "sha": "abc123"}}`))
+		case "/repos/acme/widgets/commits/abc123/check-runs":
+			_, _ = w.Write([]byte(`{"check_runs": [
+				{"name": "test", "status": "completed", "conclusion": "success", "html_url": "https://ci/test"},
+				{"name": "lint", "status": "in_progress"}]}`))
+		case "/repos/acme/widgets/commits/abc123/status":
+			_, _ = w.Write([]byte(`{"statuses": [{"context": "ci/legacy", "state": "error", "target_url": "https://ci/legacy"}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	status, err := provider.GetCIStatus(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetCIStatus failed: %v", err)
+	}
+	want := &CIStatus{State: CIFailure, Checks: []CICheck{
+		{Name: "test", State: CISuccess, URL: "https://ci/test"},
+		{Name: "lint", State: CIPending},
+		{Name: "ci/legacy", State: CIFailure, URL: "https://ci/legacy"},
+	}}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("status = %+v, want %+v", status, want)
+	}
+}
+
+func TestGitHubCommentAndMerge(t *testing.T) {
+	var comment, merge map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/issues/7/comments":
+			if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/repos/acme/widgets/pulls/7/merge":
+			if err := json.NewDecoder(r.Body).Decode(&merge); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			_, _ = w.Write([]byte(`{"merged": true}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	if err := provider.CommentOnPullRequest(context.Background(), 7, "Looks good"); err != nil {
+		t.Fatalf("CommentOnPullRequest failed: %v", err)
+	}
+	if comment["body"] != "Looks good" {
+		t.Errorf("comment = %v", comment)
+	}
+	if err := provider.MergePullRequest(context.Background(), 7, MergeOptions{}); err != nil {
+		t.Fatalf("MergePullRequest failed: %v", err)
+	}
+	if merge["merge_method"] != MergeCommit {
+		t.Errorf("merge = %v, want the merge method by default", merge)
+	}
+}
+
+func TestGitHubCreateRelease(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/widgets/releases" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"tag_name": "v1.2.0", "name": "v1.2.0", "html_url": "https://github.com/acme/widgets/releases/tag/v1.2.0"}`))
+	}))
+	defer server.Close()
+
+	provider := NewGitHub(config.GitHubConfig{Token: "ghp_secret", APIURL: server.URL}, "acme", "widgets")
+	release, err := provider.CreateRelease(context.Background(), NewRelease{Tag: "v1.2.0", Name: "v1.2.0", Body: "## Features", Draft: true})
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+	want := map[string]interface{}{"tag_name": "v1.2.0", "name": "v1.2.0", "body": "## Features", "draft": true, "prerelease": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("request = %v, want %v", got, want)
+	}
+	if release.URL != "https://github.com/acme/widgets/releases/tag/v1.2.0" || release.Tag != "v1.2.0" {
+		t.Errorf("release = %+v", release)
+	}
+}
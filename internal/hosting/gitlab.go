@@ -0,0 +1,214 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+// GitLab is the GitLab provider. Its merge requests are VC's pull requests.
+type GitLab struct {
+	api     *apiClient
+	token   string
+	project string // Full path, e.g. group/subgroup/name
+}
+
+// NewGitLab creates a GitLab provider for the project at path
+func NewGitLab(cfg config.GitLabConfig, project string) *GitLab {
+	return &GitLab{
+		api:     newAPIClient("GitLab", cfg.APIURL, map[string]string{"PRIVATE-TOKEN": cfg.Token}),
+		token:   cfg.Token,
+		project: project,
+	}
+}
+
+// Name returns config.HostingGitLab
+func (g *GitLab) Name() string { return config.HostingGitLab }
+
+// Repo returns the project's full path
+func (g *GitLab) Repo() string { return g.project }
+
+// PushUser returns the user name GitLab expects with a token
+func (g *GitLab) PushUser() string { return "oauth2" }
+
+// Token returns the API token
+func (g *GitLab) Token() string { return g.token }
+
+// gitlabMergeRequest is the part of a GitLab merge request VC uses
+type gitlabMergeRequest struct {
+	IID          int    `json:"iid"`
+	URL          string `json:"web_url"`
+	Title        string `json:"title"`
+	State        string `json:"state"` // "opened", "closed", "locked" or "merged"
+	Draft        bool   `json:"draft"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	HeadPipeline *struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+		URL    string `json:"web_url"`
+	} `json:"head_pipeline"`
+}
+
+// status returns the merge request's status: open, draft, merged or closed
+func (mr *gitlabMergeRequest) status() string {
+	switch {
+	case mr.State == "merged":
+		return StatusMerged
+	case mr.State == "closed":
+		return StatusClosed
+	case mr.Draft:
+		return StatusDraft
+	}
+	return StatusOpen
+}
+
+func (mr *gitlabMergeRequest) toPullRequest() *PullRequest {
+	return &PullRequest{
+		Number:       mr.IID,
+		URL:          mr.URL,
+		Title:        mr.Title,
+		Status:       mr.status(),
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+	}
+}
+
+// projectPath returns the API path of the project's merge requests
+func (g *GitLab) projectPath() string {
+	return "/projects/" + url.PathEscape(g.project) + "/merge_requests"
+}
+
+// CreatePullRequest opens a merge request, labelled and with its reviewers
+// on creation. GitLab marks merge requests as drafts by their title.
+func (g *GitLab) CreatePullRequest(ctx context.Context, req NewPullRequest) (*PullRequest, error) {
+	title := req.Title
+	if req.Draft && !strings.HasPrefix(title, "Draft:") {
+		title = "Draft: " + title
+	}
+	body := map[string]interface{}{
+		"title":         title,
+		"description":   req.Body,
+		"source_branch": req.SourceBranch,
+		"target_branch": req.TargetBranch,
+	}
+	if len(req.Labels) > 0 {
+		body["labels"] = strings.Join(req.Labels, ",")
+	}
+	// Reviewers are set by user ID. Teams can't review on GitLab.
+	reviewerIDs, lookupErr := g.userIDs(ctx, req.Reviewers)
+	if len(reviewerIDs) > 0 {
+		body["reviewer_ids"] = reviewerIDs
+	}
+	var mr gitlabMergeRequest
+	if err := g.api.do(ctx, http.MethodPost, g.projectPath(), body, &mr); err != nil {
+		return nil, fmt.Errorf("failed to create merge request for %s: %w", req.SourceBranch, err)
+	}
+	return mr.toPullRequest(), lookupErr
+}
+
+// userIDs looks up the IDs of users by username. Users not found are
+// left out and reported in the error, along with failed lookups.
+func (g *GitLab) userIDs(ctx context.Context, usernames []string) ([]int, error) {
+	var ids []int
+	var missing []string
+	for _, username := range usernames {
+		var users []struct {
+			ID int `json:"id"`
+		}
+		if err := g.api.do(ctx, http.MethodGet, "/users?username="+url.QueryEscape(username), nil, &users); err != nil || len(users) == 0 {
+			missing = append(missing, username)
+			continue
+		}
+		ids = append(ids, users[0].ID)
+	}
+	if len(missing) > 0 {
+		return ids, fmt.Errorf("failed to find GitLab reviewers %s", strings.Join(missing, ", "))
+	}
+	return ids, nil
+}
+
+// GetPullRequest fetches a merge request by its project-level number (iid)
+func (g *GitLab) GetPullRequest(ctx context.Context, number int) (*PullRequest, error) {
+	var mr gitlabMergeRequest
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("%s/%d", g.projectPath(), number), nil, &mr); err != nil {
+		return nil, fmt.Errorf("failed to get merge request !%d: %w", number, err)
+	}
+	return mr.toPullRequest(), nil
+}
+
+// CommentOnPullRequest adds a note to a merge request
+func (g *GitLab) CommentOnPullRequest(ctx context.Context, number int, body string) error {
+	path := fmt.Sprintf("%s/%d/notes", g.projectPath(), number)
+	if err := g.api.do(ctx, http.MethodPost, path, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on merge request !%d: %w", number, err)
+	}
+	return nil
+}
+
+// GetCIStatus returns the status of a merge request's head pipeline, as a
+// single check
+func (g *GitLab) GetCIStatus(ctx context.Context, number int) (*CIStatus, error) {
+	var mr gitlabMergeRequest
+	if err := g.api.do(ctx, http.MethodGet, fmt.Sprintf("%s/%d", g.projectPath(), number), nil, &mr); err != nil {
+		return nil, fmt.Errorf("failed to get merge request !%d: %w", number, err)
+	}
+	if mr.HeadPipeline == nil {
+		return newCIStatus(nil), nil
+	}
+
+	state := CIPending // created, pending, running, manual, scheduled...
+	switch mr.HeadPipeline.Status {
+	case "success", "skipped":
+		state = CISuccess
+	case "failed", "canceled":
+		state = CIFailure
+	}
+	return newCIStatus([]CICheck{{
+		Name:  fmt.Sprintf("pipeline #%d", mr.HeadPipeline.ID),
+		State: state,
+		URL:   mr.HeadPipeline.URL,
+	}}), nil
+}
+
+// MergePullRequest merges a merge request. GitLab rebases or not according
+// to the project's merge method, so only merge and squash can be asked for.
+func (g *GitLab) MergePullRequest(ctx context.Context, number int, opts MergeOptions) error {
+	if opts.Method == MergeRebase {
+		return fmt.Errorf("GitLab merges with the project's merge method; use %q or %q", MergeCommit, MergeSquash)
+	}
+	body := map[string]bool{"squash": opts.Method == MergeSquash}
+	if err := g.api.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d/merge", g.projectPath(), number), body, nil); err != nil {
+		return fmt.Errorf("failed to merge merge request !%d: %w", number, err)
+	}
+	return nil
+}
+
+// gitlabRelease is the part of a GitLab release VC uses
+type gitlabRelease struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Links   struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// CreateRelease publishes a release for a pushed tag. GitLab has no draft
+// or prerelease flags, so those are ignored.
+func (g *GitLab) CreateRelease(ctx context.Context, req NewRelease) (*Release, error) {
+	body := map[string]string{
+		"tag_name":    req.Tag,
+		"name":        req.Name,
+		"description": req.Body,
+	}
+	var release gitlabRelease
+	path := "/projects/" + url.PathEscape(g.project) + "/releases"
+	if err := g.api.do(ctx, http.MethodPost, path, body, &release); err != nil {
+		return nil, fmt.Errorf("failed to create release for %s: %w", req.Tag, err)
+	}
+	return &Release{Tag: release.TagName, Name: release.Name, URL: release.Links.Self}, nil
+}
@@ -0,0 +1,231 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// gitlabIssue is the part of a GitLab issue VC uses
+type gitlabIssue struct {
+	IID         int      `json:"iid"`
+	URL         string   `json:"web_url"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"` // "opened" or "closed"
+	Labels      []string `json:"labels"`
+	Milestone   *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (i *gitlabIssue) toIssue() *Issue {
+	issue := &Issue{
+		Number:    i.IID,
+		URL:       i.URL,
+		Title:     i.Title,
+		Body:      i.Description,
+		State:     "open",
+		Labels:    i.Labels,
+		Author:    i.Author.Username,
+		UpdatedAt: i.UpdatedAt,
+	}
+	if i.State == "closed" {
+		issue.State = "closed"
+	}
+	if i.Milestone != nil {
+		issue.Milestone = i.Milestone.Title
+	}
+	return issue
+}
+
+// gitlabNote is the part of a GitLab note (comment) VC uses
+type gitlabNote struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	System bool   `json:"system"` // Notes GitLab adds for changes, e.g. "added ~bug label"
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (n *gitlabNote) toComment(number int) *IssueComment {
+	return &IssueComment{ID: n.ID, IssueNumber: number, Author: n.Author.Username, Body: n.Body, CreatedAt: n.CreatedAt}
+}
+
+// issuesPath returns the API path of the project's issues
+func (g *GitLab) issuesPath() string {
+	return "/projects/" + url.PathEscape(g.project) + "/issues"
+}
+
+// ListIssues returns the project's issues, open and closed, updated at or
+// after since (every issue if since is zero)
+func (g *GitLab) ListIssues(ctx context.Context, since time.Time) ([]*Issue, error) {
+	query := url.Values{"scope": {"all"}, "state": {"all"}, "order_by": {"updated_at"}, "sort": {"asc"}}
+	if !since.IsZero() {
+		query.Set("updated_after", since.UTC().Format(time.RFC3339))
+	}
+	var issues []*Issue
+	err := listPages(ctx, g.api, g.issuesPath(), query, func(issue gitlabIssue) {
+		issues = append(issues, issue.toIssue())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	return issues, nil
+}
+
+// CreateIssue opens an issue
+func (g *GitLab) CreateIssue(ctx context.Context, req NewIssue) (*Issue, error) {
+	body := map[string]interface{}{"title": req.Title, "description": req.Body}
+	if len(req.Labels) > 0 {
+		body["labels"] = strings.Join(req.Labels, ",")
+	}
+	if req.Milestone != "" {
+		id, err := g.milestone(ctx, req.Milestone)
+		if err != nil {
+			return nil, err
+		}
+		body["milestone_id"] = id
+	}
+	var issue gitlabIssue
+	if err := g.api.do(ctx, http.MethodPost, g.issuesPath(), body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to create issue %q: %w", req.Title, err)
+	}
+	return issue.toIssue(), nil
+}
+
+// UpdateIssue changes an issue's title, body, state or milestone
+func (g *GitLab) UpdateIssue(ctx context.Context, number int, update IssueUpdate) (*Issue, error) {
+	body := make(map[string]interface{})
+	if update.Title != nil {
+		body["title"] = *update.Title
+	}
+	if update.Body != nil {
+		body["description"] = *update.Body
+	}
+	if update.State != nil {
+		body["state_event"] = "reopen"
+		if *update.State == "closed" {
+			body["state_event"] = "close"
+		}
+	}
+	if update.Milestone != nil {
+		body["milestone_id"] = 0 // Unassigns the milestone
+		if *update.Milestone != "" {
+			id, err := g.milestone(ctx, *update.Milestone)
+			if err != nil {
+				return nil, err
+			}
+			body["milestone_id"] = id
+		}
+	}
+	return g.updateIssue(ctx, number, body)
+}
+
+// AddIssueLabels adds labels to an issue. Labels the project doesn't have
+// yet are created.
+func (g *GitLab) AddIssueLabels(ctx context.Context, number int, labels []string) error {
+	if _, err := g.updateIssue(ctx, number, map[string]interface{}{"add_labels": strings.Join(labels, ",")}); err != nil {
+		return fmt.Errorf("failed to label issue #%d: %w", number, err)
+	}
+	return nil
+}
+
+// RemoveIssueLabel removes a label from an issue. Removing a label the
+// issue doesn't have is not an error.
+func (g *GitLab) RemoveIssueLabel(ctx context.Context, number int, label string) error {
+	if _, err := g.updateIssue(ctx, number, map[string]interface{}{"remove_labels": label}); err != nil {
+		return fmt.Errorf("failed to remove label %q from issue #%d: %w", label, number, err)
+	}
+	return nil
+}
+
+func (g *GitLab) updateIssue(ctx context.Context, number int, body map[string]interface{}) (*Issue, error) {
+	var issue gitlabIssue
+	if err := g.api.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", g.issuesPath(), number), body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to update issue #%d: %w", number, err)
+	}
+	return issue.toIssue(), nil
+}
+
+// ListComments returns the comments on the project's issues created at or
+// after since (every comment if since is zero), oldest first. GitLab has no
+// project-wide list of comments, but commenting updates an issue, so the
+// comments of the issues updated since are listed.
+func (g *GitLab) ListComments(ctx context.Context, since time.Time) ([]*IssueComment, error) {
+	issues, err := g.ListIssues(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	var comments []*IssueComment
+	for _, issue := range issues {
+		issueComments, err := g.ListIssueComments(ctx, issue.Number)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range issueComments {
+			if !comment.CreatedAt.Before(since) {
+				comments = append(comments, comment)
+			}
+		}
+	}
+	return comments, nil
+}
+
+// ListIssueComments returns the comments on an issue, oldest first. Notes
+// GitLab adds for changes to the issue are left out.
+func (g *GitLab) ListIssueComments(ctx context.Context, number int) ([]*IssueComment, error) {
+	query := url.Values{"order_by": {"created_at"}, "sort": {"asc"}}
+	var comments []*IssueComment
+	err := listPages(ctx, g.api, fmt.Sprintf("%s/%d/notes", g.issuesPath(), number), query, func(note gitlabNote) {
+		if !note.System {
+			comments = append(comments, note.toComment(number))
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments on issue #%d: %w", number, err)
+	}
+	return comments, nil
+}
+
+// CreateComment adds a comment to an issue
+func (g *GitLab) CreateComment(ctx context.Context, number int, body string) (*IssueComment, error) {
+	var note gitlabNote
+	path := fmt.Sprintf("%s/%d/notes", g.issuesPath(), number)
+	if err := g.api.do(ctx, http.MethodPost, path, map[string]string{"body": body}, &note); err != nil {
+		return nil, fmt.Errorf("failed to comment on issue #%d: %w", number, err)
+	}
+	return note.toComment(number), nil
+}
+
+// milestone returns the ID of the milestone titled title, the project's or
+// one of its groups', creating it in the project if there is none
+func (g *GitLab) milestone(ctx context.Context, title string) (int, error) {
+	path := "/projects/" + url.PathEscape(g.project) + "/milestones"
+	var milestones []struct {
+		ID int `json:"id"`
+	}
+	query := url.Values{"title": {title}, "include_ancestors": {"true"}}
+	if err := g.api.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &milestones); err != nil {
+		return 0, fmt.Errorf("failed to find milestone %q: %w", title, err)
+	}
+	if len(milestones) > 0 {
+		return milestones[0].ID, nil
+	}
+	var created struct {
+		ID int `json:"id"`
+	}
+	if err := g.api.do(ctx, http.MethodPost, path, map[string]string{"title": title}, &created); err != nil {
+		return 0, fmt.Errorf("failed to create milestone %q: %w", title, err)
+	}
+	return created.ID, nil
+}
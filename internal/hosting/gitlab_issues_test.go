@@ -0,0 +1,160 @@
+package hosting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestGitLabListIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/projects/acme%2Fwidgets/issues" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		query := r.URL.Query()
+		if query.Get("state") != "all" || query.Get("scope") != "all" || query.Get("updated_after") != "2026-01-02T03:04:05Z" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`[{"iid": 4, "web_url": "https://gitlab.com/acme/widgets/-/issues/4", "title": "Audit log",
+			"description": "Who did what", "state": "opened", "labels": ["P1", "backend"], "milestone": {"title": "v2"},
+			"author": {"username": "maria"}, "updated_at": "2026-01-03T10:00:00Z"},
+			{"iid": 5, "title": "Done", "state": "closed", "labels": []}]`))
+	}))
+	defer server.Close()
+
+	provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/widgets")
+	issues, err := provider.ListIssues(context.Background(), time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ListIssues failed: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	want := &Issue{Number: 4, URL: "https://gitlab.com/acme/widgets/-/issues/4", Title: "Audit log", Body: "Who did what",
+		State: "open", Labels: []string{"P1", "backend"}, Milestone: "v2", Author: "maria",
+		UpdatedAt: time.Date(2026, 1, 3, 10, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(issues[0], want) {
+		t.Errorf("first issue = %+v, want %+v", issues[0], want)
+	}
+	if !issues[1].Closed() || issues[1].Milestone != "" {
+		t.Errorf("second issue = %+v", issues[1])
+	}
+}
+
+func TestGitLabUpdateIssue(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/acme/widgets/milestones":
+			// Only v3 exists, in the project's group
+			if r.URL.Query().Get("include_ancestors") != "true" {
+				t.Errorf("group milestones aren't looked up: %s", r.URL.RawQuery)
+			}
+			if r.URL.Query().Get("title") == "v3" {
+				_, _ = w.Write([]byte(`[{"id": 33, "title": "v3"}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/acme/widgets/milestones":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 44, "title": "v4"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/projects/acme/widgets/issues/7":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			bodies = append(bodies, body)
+			_, _ = w.Write([]byte(`{"iid": 7, "title": "Renamed", "state": "closed", "milestone": {"title": "v3"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/widgets")
+	ctx := context.Background()
+	title, state, milestone := "Renamed", "closed", "v3"
+	issue, err := provider.UpdateIssue(ctx, 7, IssueUpdate{Title: &title, State: &state, Milestone: &milestone})
+	if err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if issue.Number != 7 || !issue.Closed() || issue.Milestone != "v3" {
+		t.Errorf("unexpected issue %+v", issue)
+	}
+	reopen, missing, none := "open", "v4", ""
+	if _, err := provider.UpdateIssue(ctx, 7, IssueUpdate{State: &reopen, Milestone: &missing}); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if _, err := provider.UpdateIssue(ctx, 7, IssueUpdate{Milestone: &none}); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := provider.AddIssueLabels(ctx, 7, []string{"P1", "area: ui"}); err != nil {
+		t.Fatalf("AddIssueLabels failed: %v", err)
+	}
+	if err := provider.RemoveIssueLabel(ctx, 7, "P2"); err != nil {
+		t.Fatalf("RemoveIssueLabel failed: %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"title": "Renamed", "state_event": "close", "milestone_id": float64(33)},
+		{"state_event": "reopen", "milestone_id": float64(44)},
+		{"milestone_id": float64(0)},
+		{"add_labels": "P1,area: ui"},
+		{"remove_labels": "P2"},
+	}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("request bodies = %+v, want %+v", bodies, want)
+	}
+}
+
+func TestGitLabComments(t *testing.T) {
+	var posted map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/acme/widgets/issues":
+			_, _ = w.Write([]byte(`[{"iid": 4, "state": "opened"}]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/acme/widgets/issues/4/notes":
+			_, _ = w.Write([]byte(`[
+				{"id": 1, "body": "Old", "author": {"username": "maria"}, "created_at": "2026-01-01T00:00:00Z"},
+				{"id": 2, "body": "added ~backend label", "system": true, "created_at": "2026-01-05T00:00:00Z"},
+				{"id": 3, "body": "Needed for SOC 2", "author": {"username": "maria"}, "created_at": "2026-01-05T00:00:00Z"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/acme/widgets/issues/4/notes":
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 9, "body": "From VC", "author": {"username": "vc-bot"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/widgets")
+	ctx := context.Background()
+	// Comments older than since and system notes are left out
+	comments, err := provider.ListComments(ctx, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ListComments failed: %v", err)
+	}
+	want := []*IssueComment{{ID: 3, IssueNumber: 4, Author: "maria", Body: "Needed for SOC 2", CreatedAt: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)}}
+	if !reflect.DeepEqual(comments, want) {
+		t.Errorf("comments = %+v, want %+v", comments, want)
+	}
+
+	comment, err := provider.CreateComment(ctx, 4, "From VC")
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+	if posted["body"] != "From VC" || comment.ID != 9 || comment.IssueNumber != 4 {
+		t.Errorf("posted %v, got %+v", posted, comment)
+	}
+}
@@ -0,0 +1,231 @@
+package hosting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestGitLabMergeRequestStatus(t *testing.T) {
+	tests := []struct {
+		mr   gitlabMergeRequest
+		want string
+	}{
+		{gitlabMergeRequest{State: "opened"}, StatusOpen},
+		{gitlabMergeRequest{State: "opened", Draft: true}, StatusDraft},
+		{gitlabMergeRequest{State: "locked"}, StatusOpen},
+		{gitlabMergeRequest{State: "closed"}, StatusClosed},
+		{gitlabMergeRequest{State: "merged"}, StatusMerged},
+	}
+	for _, tt := range tests {
+		if got := tt.mr.status(); got != tt.want {
+			t.Errorf("status() of %+v = %q, want %q", tt.mr, got, tt.want)
+		}
+	}
+}
+
+func TestGitLabCreatePullRequest(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.Header.Get("PRIVATE-TOKEN"); token != "glpat_secret" {
+			t.Errorf("PRIVATE-TOKEN = %q", token)
+		}
+		// The project path is a single, escaped path segment
+		if r.Method != http.MethodPost || r.URL.EscapedPath() != "/projects/acme%2Ftools%2Fwidgets/merge_requests" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"iid": 12, "web_url": "https://gitlab.com/acme/tools/widgets/-/merge_requests/12",
+			"state": "opened", "draft": true, "source_branch": "vc/vc-1-feature", "target_branch": "main"}`))
+	}))
+	defer server.Close()
+
+	provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/tools/widgets")
+	pr, err := provider.CreatePullRequest(context.Background(), NewPullRequest{
+		Title: "Add feature", Body: "Details", SourceBranch: "vc/vc-1-feature", TargetBranch: "main",
+		Draft: true, Labels: []string{"vc", "automated"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest failed: %v", err)
+	}
+	if got["title"] != "Draft: Add feature" || got["description"] != "Details" || got["labels"] != "vc,automated" ||
+		got["source_branch"] != "vc/vc-1-feature" || got["target_branch"] != "main" {
+		t.Errorf("unexpected request body: %+v", got)
+	}
+	want := &PullRequest{Number: 12, URL: "https://gitlab.com/acme/tools/widgets/-/merge_requests/12", Status: StatusDraft,
+		SourceBranch: "vc/vc-1-feature", TargetBranch: "main"}
+	if !reflect.DeepEqual(pr, want) {
+		t.Errorf("merge request = %+v, want %+v", pr, want)
+	}
+}
+
+func TestGitLabCreatePullRequestReviewers(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/users":
+			if r.URL.Query().Get("username") == "carol" {
+				_, _ = w.Write([]byte(`[{"id": 42, "username": "carol"}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"iid": 12, "state": "opened"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/widgets")
+	pr, err := provider.CreatePullRequest(context.Background(), NewPullRequest{
+		SourceBranch: "vc/vc-1-feature", TargetBranch: "main",
+		Reviewers: []string{"carol", "ghost"}, TeamReviewers: []string{"acme/payments"},
+	})
+	// Opened with the reviewers found, reporting the others
+	if pr == nil || pr.Number != 12 {
+		t.Fatalf("CreatePullRequest() = %+v, want the merge request", pr)
+	}
+	if err == nil || !strings.Contains(err.Error(), "ghost") {
+		t.Errorf("error = %v, want the missing reviewer reported", err)
+	}
+	if !reflect.DeepEqual(got["reviewer_ids"], []interface{}{float64(42)}) {
+		t.Errorf("reviewer_ids = %v, want [42]", got["reviewer_ids"])
+	}
+}
+
+func TestGitLabAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"message": ["Another open merge request already exists for this source branch: !11"]}`))
+	}))
+	defer server.Close()
+
+	provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/widgets")
+	_, err := provider.CreatePullRequest(context.Background(), NewPullRequest{SourceBranch: "vc/vc-1-feature", TargetBranch: "main"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "409") || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("error should carry the status and GitLab's message, got: %v", err)
+	}
+}
+
+func TestGitLabGetCIStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{"running pipeline", `{"iid": 12, "head_pipeline": {"id": 5, "status": "running", "web_url": "https://ci/5"}}`, CIPending},
+		{"passed pipeline", `{"iid": 12, "head_pipeline": {"id": 5, "status": "success", "web_url": "https://ci/5"}}`, CISuccess},
+		{"failed pipeline", `{"iid": 12, "head_pipeline": {"id": 5, "status": "failed", "web_url": "https://ci/5"}}`, CIFailure},
+		{"no pipeline", `{"iid": 12, "head_pipeline": null}`, CINone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() != "/projects/acme%2Fwidgets/merge_requests/12" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+				}
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/widgets")
+			status, err := provider.GetCIStatus(context.Background(), 12)
+			if err != nil {
+				t.Fatalf("GetCIStatus failed: %v", err)
+			}
+			if status.State != tt.want {
+				t.Errorf("state = %q, want %q", status.State, tt.want)
+			}
+			if tt.want != CINone && (len(status.Checks) != 1 || status.Checks[0].URL != "https://ci/5") {
+				t.Errorf("checks = %+v, want the pipeline", status.Checks)
+			}
+		})
+	}
+}
+
+func TestGitLabCommentAndMerge(t *testing.T) {
+	var note map[string]string
+	var merge map[string]bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/projects/acme%2Fwidgets/merge_requests/12/notes":
+			if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case r.Method == http.MethodPut && r.URL.EscapedPath() == "/projects/acme%2Fwidgets/merge_requests/12/merge":
+			if err := json.NewDecoder(r.Body).Decode(&merge); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			_, _ = w.Write([]byte(`{"iid": 12, "state": "merged"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/widgets")
+	if err := provider.CommentOnPullRequest(context.Background(), 12, "Looks good"); err != nil {
+		t.Fatalf("CommentOnPullRequest failed: %v", err)
+	}
+	if note["body"] != "Looks good" {
+		t.Errorf("note = %v", note)
+	}
+	if err := provider.MergePullRequest(context.Background(), 12, MergeOptions{Method: MergeSquash}); err != nil {
+		t.Fatalf("MergePullRequest failed: %v", err)
+	}
+	if !merge["squash"] {
+		t.Errorf("merge = %v, want squash", merge)
+	}
+	if err := provider.MergePullRequest(context.Background(), 12, MergeOptions{Method: MergeRebase}); err == nil {
+		t.Error("expected an error for the rebase method")
+	}
+}
+
+func TestGitLabCreateRelease(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.EscapedPath() != "/projects/acme%2Fwidgets/releases" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"tag_name": "v1.2.0", "name": "v1.2.0", "_links": {"self": "https://gitlab.com/acme/widgets/-/releases/v1.2.0"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewGitLab(config.GitLabConfig{Token: "glpat_secret", APIURL: server.URL}, "acme/widgets")
+	release, err := provider.CreateRelease(context.Background(), NewRelease{Tag: "v1.2.0", Name: "v1.2.0", Body: "## Features"})
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+	if got["tag_name"] != "v1.2.0" || got["description"] != "## Features" {
+		t.Errorf("request = %v", got)
+	}
+	if release.URL != "https://gitlab.com/acme/widgets/-/releases/v1.2.0" {
+		t.Errorf("release = %+v", release)
+	}
+}
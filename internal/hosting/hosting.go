@@ -0,0 +1,287 @@
+// Package hosting is VC's git hosting integration: opening pull requests
+// (GitHub) or merge requests (GitLab) from issue branches and following
+// their status, through each provider's REST API.
+//
+// Both kinds are called pull requests here. Providers implement the
+// Provider interface and share the HTTP plumbing and the tracking of
+// opened pull requests, which are recorded as agent events on their issue
+// so their status can be followed without another table. Higher layers
+// only use Provider; other hosts plug in with Register.
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+// Pull request statuses
+const (
+	StatusOpen   = "open"
+	StatusDraft  = "draft"
+	StatusMerged = "merged"
+	StatusClosed = "closed" // Closed without merging
+)
+
+// PullRequest is a pull request or merge request as VC sees it
+type PullRequest struct {
+	Number       int // Number within the repository (GitLab's iid)
+	URL          string
+	Title        string
+	Status       string
+	SourceBranch string
+	TargetBranch string
+}
+
+// NewPullRequest describes a pull request to open
+type NewPullRequest struct {
+	Title        string
+	Body         string
+	SourceBranch string // Branch with the changes
+	TargetBranch string // Branch to merge into
+	Draft        bool
+	Labels       []string
+
+	// Reviewers are the logins of users asked to review
+	Reviewers []string
+	// TeamReviewers are the teams asked to review, as "org/team" (GitHub only)
+	TeamReviewers []string
+}
+
+// Release is a release published for a tag
+type Release struct {
+	Tag  string
+	Name string
+	URL  string
+}
+
+// NewRelease describes a release to publish for a tag already pushed
+type NewRelease struct {
+	Tag        string
+	Name       string
+	Body       string // Release notes, in Markdown
+	Draft      bool   // Not published yet (GitHub only)
+	Prerelease bool   // Marked as not production-ready (GitHub only)
+}
+
+// Combined CI states of a pull request
+const (
+	CIPending = "pending"
+	CISuccess = "success"
+	CIFailure = "failure"
+	CINone    = "none" // No checks reported
+)
+
+// CICheck is one CI check on a pull request's latest commit: a GitHub check
+// run or commit status, or a GitLab pipeline
+type CICheck struct {
+	Name  string
+	State string // CIPending, CISuccess or CIFailure
+	URL   string
+}
+
+// CIStatus is the CI status of a pull request's latest commit
+type CIStatus struct {
+	State  string // Combined over all checks
+	Checks []CICheck
+}
+
+// newCIStatus combines checks: failure if any failed, else pending if any
+// hasn't finished, else success (none without checks)
+func newCIStatus(checks []CICheck) *CIStatus {
+	status := &CIStatus{State: CINone, Checks: checks}
+	for _, check := range checks {
+		switch {
+		case check.State == CIFailure:
+			status.State = CIFailure
+		case check.State == CIPending && status.State != CIFailure:
+			status.State = CIPending
+		case status.State == CINone:
+			status.State = CISuccess
+		}
+	}
+	return status
+}
+
+// Merge methods
+const (
+	MergeCommit = "merge"
+	MergeSquash = "squash"
+	MergeRebase = "rebase"
+)
+
+// MergeOptions configures merging a pull request
+type MergeOptions struct {
+	// Method is MergeCommit, MergeSquash or MergeRebase (default: MergeCommit)
+	Method string
+}
+
+// Provider opens, inspects, comments on and merges pull requests in one
+// repository
+type Provider interface {
+	// Name returns the provider name (config.HostingGitHub or config.HostingGitLab)
+	Name() string
+
+	// Repo returns the repository's path, e.g. owner/name
+	Repo() string
+
+	// CreatePullRequest opens a pull request. If it was opened but
+	// labelling it or requesting reviews failed, both the pull request and
+	// an error are returned.
+	CreatePullRequest(ctx context.Context, req NewPullRequest) (*PullRequest, error)
+
+	// GetPullRequest fetches a pull request by number
+	GetPullRequest(ctx context.Context, number int) (*PullRequest, error)
+
+	// CommentOnPullRequest adds a comment to a pull request
+	CommentOnPullRequest(ctx context.Context, number int, body string) error
+
+	// GetCIStatus returns the CI status of a pull request's latest commit
+	GetCIStatus(ctx context.Context, number int) (*CIStatus, error)
+
+	// MergePullRequest merges a pull request
+	MergePullRequest(ctx context.Context, number int, opts MergeOptions) error
+
+	// CreateRelease publishes a release for a tag that is on the remote
+	CreateRelease(ctx context.Context, req NewRelease) (*Release, error)
+
+	// PushUser is the user name git sends with the token when pushing over HTTPS
+	PushUser() string
+
+	// Token returns the token for API calls and pushes
+	Token() string
+}
+
+// DisplayName returns how a provider's name is written in messages, e.g.
+// "GitHub" for config.HostingGitHub
+func DisplayName(provider string) string {
+	switch provider {
+	case config.HostingGitHub:
+		return "GitHub"
+	case config.HostingGitLab:
+		return "GitLab"
+	}
+	return provider
+}
+
+// Factory creates a provider for repo from the hosting configuration
+type Factory func(cfg config.HostingConfig, repo string) (Provider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a provider available under name. When no provider is
+// configured, a remote whose host name contains name selects it (e.g.
+// "gitea" for gitea.example.com). Registering a name again replaces it.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[name] = factory
+}
+
+func init() {
+	Register(config.HostingGitHub, func(cfg config.HostingConfig, repo string) (Provider, error) {
+		owner, repoName, ok := strings.Cut(repo, "/")
+		if !ok || strings.Contains(repoName, "/") {
+			return nil, fmt.Errorf("GitHub repository must be owner/name (got %q)", repo)
+		}
+		if cfg.GitHub.Token == "" {
+			return nil, fmt.Errorf("no GitHub token configured")
+		}
+		return NewGitHub(cfg.GitHub, owner, repoName), nil
+	})
+	Register(config.HostingGitLab, func(cfg config.HostingConfig, repo string) (Provider, error) {
+		if cfg.GitLab.Token == "" {
+			return nil, fmt.Errorf("no GitLab token configured")
+		}
+		return NewGitLab(cfg.GitLab, repo), nil
+	})
+}
+
+// detectProvider returns the registered provider named in host, if any
+func detectProvider(host string) string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.Contains(host, name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// Open returns the provider for a repository: the configured provider, or
+// the one the remote URL's host suggests. The repository is the configured
+// one, or the remote URL's path.
+func Open(cfg config.HostingConfig, remoteURL string) (Provider, error) {
+	name := cfg.Provider
+	// An unparseable remote is fine if the repository is configured
+	host, path, parseErr := ParseRemoteURL(remoteURL)
+	if name == "" {
+		// By host name, else whichever provider has a token (self-hosted)
+		name = detectProvider(strings.ToLower(host))
+		if name == "" {
+			name = config.HostingGitHub
+			if cfg.GitLab.Token != "" && cfg.GitHub.Token == "" {
+				name = config.HostingGitLab
+			}
+		}
+	}
+
+	switch name {
+	case config.HostingGitHub:
+		if cfg.GitHub.Repo != "" {
+			path = cfg.GitHub.Repo
+		}
+	case config.HostingGitLab:
+		if cfg.GitLab.Project != "" {
+			path = cfg.GitLab.Project
+		}
+	}
+	if path == "" {
+		return nil, parseErr
+	}
+	return NewProvider(cfg, name, path)
+}
+
+// NewProvider returns the named provider for repo
+func NewProvider(cfg config.HostingConfig, name, repo string) (Provider, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown git hosting provider %q", name)
+	}
+	return factory(cfg, repo)
+}
+
+// remoteURLRegex matches the host and repository path in remote URLs:
+// https://host/path(.git), ssh://git@host[:port]/path(.git) and
+// git@host:path(.git)
+var remoteURLRegex = regexp.MustCompile(`^(?:(?:https?|ssh|git)://(?:[^@/]+@)?([^/:]+)(?::\d+)?/|[^@/\s]+@([^:/\s]+):)([^\s]+?)(?:\.git)?/?$`)
+
+// ParseRemoteURL extracts the host and repository path (owner/name, or
+// group/subgroup/name on GitLab) from a git remote URL
+func ParseRemoteURL(url string) (host, path string, err error) {
+	m := remoteURLRegex.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil || !strings.Contains(m[3], "/") {
+		return "", "", fmt.Errorf("can't tell the repository from remote URL %q (set VC_GITHUB_REPO or VC_GITLAB_PROJECT)", url)
+	}
+	host = m[1]
+	if host == "" {
+		host = m[2]
+	}
+	return host, m[3], nil
+}
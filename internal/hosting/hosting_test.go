@@ -0,0 +1,123 @@
+package hosting
+
+import (
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		url        string
+		host, path string
+		wantErr    bool
+	}{
+		{url: "https://github.com/acme/widgets.git", host: "github.com", path: "acme/widgets"},
+		{url: "https://github.com/acme/widgets", host: "github.com", path: "acme/widgets"},
+		{url: "https://user@github.example.com/acme/widgets/", host: "github.example.com", path: "acme/widgets"},
+		{url: "git@github.com:acme/widgets.git", host: "github.com", path: "acme/widgets"},
+		{url: "ssh://git@github.com/acme/my.widgets.git", host: "github.com", path: "acme/my.widgets"},
+		{url: "ssh://git@gitlab.example.com:2222/acme/tools/widgets.git", host: "gitlab.example.com", path: "acme/tools/widgets"},
+		{url: "git@gitlab.com:acme/tools/widgets.git", host: "gitlab.com", path: "acme/tools/widgets"},
+		{url: "/srv/git/widgets.git", wantErr: true},
+		{url: "https://github.com/widgets", wantErr: true},
+	}
+	for _, tt := range tests {
+		host, path, err := ParseRemoteURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRemoteURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if host != tt.host || path != tt.path {
+			t.Errorf("ParseRemoteURL(%q) = %q, %q; want %q, %q", tt.url, host, path, tt.host, tt.path)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	both := config.DefaultHostingConfig()
+	both.GitHub.Token = "gh"
+	both.GitLab.Token = "gl"
+
+	gitlabOnly := config.DefaultHostingConfig()
+	gitlabOnly.GitLab.Token = "gl"
+
+	explicit := both
+	explicit.Provider = config.HostingGitLab
+	explicit.GitLab.Project = "acme/tools/widgets"
+
+	tests := []struct {
+		name      string
+		cfg       config.HostingConfig
+		remoteURL string
+		provider  string
+		repo      string
+		wantErr   bool
+	}{
+		{name: "GitHub remote", cfg: both, remoteURL: "git@github.com:acme/widgets.git", provider: config.HostingGitHub, repo: "acme/widgets"},
+		{name: "GitLab remote", cfg: both, remoteURL: "https://gitlab.example.com/acme/tools/widgets.git", provider: config.HostingGitLab, repo: "acme/tools/widgets"},
+		{name: "only a GitLab token", cfg: gitlabOnly, remoteURL: "https://git.example.com/acme/widgets.git", provider: config.HostingGitLab, repo: "acme/widgets"},
+		{name: "configured provider and project", cfg: explicit, remoteURL: "/srv/git/widgets.git", provider: config.HostingGitLab, repo: "acme/tools/widgets"},
+		{name: "unparseable remote", cfg: both, remoteURL: "/srv/git/widgets.git", wantErr: true},
+		{name: "nested path on GitHub", cfg: both, remoteURL: "https://github.com/acme/tools/widgets.git", wantErr: true},
+		{name: "no token for the detected provider", cfg: gitlabOnly, remoteURL: "https://github.com/acme/widgets.git", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := Open(tt.cfg, tt.remoteURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if provider.Name() != tt.provider || provider.Repo() != tt.repo {
+				t.Errorf("Open() = %s %s, want %s %s", provider.Name(), provider.Repo(), tt.provider, tt.repo)
+			}
+		})
+	}
+}
+
+func TestNewCIStatus(t *testing.T) {
+	tests := []struct {
+		states []string
+		want   string
+	}{
+		{nil, CINone},
+		{[]string{CISuccess, CISuccess}, CISuccess},
+		{[]string{CISuccess, CIPending}, CIPending},
+		{[]string{CIFailure, CIPending}, CIFailure},
+		{[]string{CIPending, CIFailure, CISuccess}, CIFailure},
+	}
+	for _, tt := range tests {
+		var checks []CICheck
+		for _, state := range tt.states {
+			checks = append(checks, CICheck{State: state})
+		}
+		if got := newCIStatus(checks).State; got != tt.want {
+			t.Errorf("newCIStatus(%v) = %q, want %q", tt.states, got, tt.want)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("gitea", func(cfg config.HostingConfig, repo string) (Provider, error) {
+		return NewGitLab(cfg.GitLab, repo), nil
+	})
+	defer func() {
+		factoriesMu.Lock()
+		delete(factories, "gitea")
+		factoriesMu.Unlock()
+	}()
+
+	provider, err := Open(config.DefaultHostingConfig(), "https://gitea.example.com/acme/widgets.git")
+	if err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	if provider.Repo() != "acme/widgets" {
+		t.Errorf("Open() repo = %q, want acme/widgets", provider.Repo())
+	}
+	if _, err := NewProvider(config.DefaultHostingConfig(), "bitbucket", "acme/widgets"); err == nil {
+		t.Error("expected an error for an unregistered provider")
+	}
+}
@@ -0,0 +1,116 @@
+package hosting
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// pageSize is the number of items requested per page of a list
+const pageSize = 100
+
+// Issue is an issue in a provider's issue tracker
+type Issue struct {
+	Number    int // Number within the repository (GitLab's iid)
+	URL       string
+	Title     string
+	Body      string
+	State     string // "open" or "closed"
+	Labels    []string
+	Milestone string // Title of the issue's milestone, "" if none
+	Author    string
+	UpdatedAt time.Time
+}
+
+// Closed reports whether the issue is closed
+func (i *Issue) Closed() bool { return i.State == "closed" }
+
+// IssueComment is a comment on an issue
+type IssueComment struct {
+	ID          int64
+	IssueNumber int
+	Author      string
+	Body        string
+	CreatedAt   time.Time
+}
+
+// NewIssue describes an issue to open
+type NewIssue struct {
+	Title     string
+	Body      string
+	Labels    []string
+	Milestone string // Title of the milestone, created if the repository doesn't have it
+}
+
+// IssueUpdate is a change to an issue. Nil fields are left unchanged.
+type IssueUpdate struct {
+	Title     *string
+	Body      *string
+	State     *string // "open" or "closed"
+	Milestone *string // Title of the milestone, created if needed; "" removes the issue's
+}
+
+// IssueTracker is a provider's issue tracker, which 'vc github sync' and
+// 'vc gitlab sync' mirror VC issues to
+type IssueTracker interface {
+	// Name returns the provider name (config.HostingGitHub or config.HostingGitLab)
+	Name() string
+
+	// Repo returns the repository's path, e.g. owner/name
+	Repo() string
+
+	// ListIssues returns the repository's issues, open and closed, updated
+	// at or after since (every issue if since is zero)
+	ListIssues(ctx context.Context, since time.Time) ([]*Issue, error)
+
+	// CreateIssue opens an issue
+	CreateIssue(ctx context.Context, req NewIssue) (*Issue, error)
+
+	// UpdateIssue changes an issue's title, body, state or milestone
+	UpdateIssue(ctx context.Context, number int, update IssueUpdate) (*Issue, error)
+
+	// AddIssueLabels adds labels to an issue, creating those the
+	// repository doesn't have yet
+	AddIssueLabels(ctx context.Context, number int, labels []string) error
+
+	// RemoveIssueLabel removes a label from an issue. Removing a label the
+	// issue doesn't have is not an error.
+	RemoveIssueLabel(ctx context.Context, number int, label string) error
+
+	// ListComments returns the comments on the repository's issues created
+	// at or after since (every comment if since is zero), oldest first.
+	// Comments edited since may be included.
+	ListComments(ctx context.Context, since time.Time) ([]*IssueComment, error)
+
+	// ListIssueComments returns the comments on an issue, oldest first
+	ListIssueComments(ctx context.Context, number int) ([]*IssueComment, error)
+
+	// CreateComment adds a comment to an issue
+	CreateComment(ctx context.Context, number int, body string) (*IssueComment, error)
+}
+
+// listPages requests the pages of a list until one comes back short,
+// passing each item to collect. GitHub and GitLab page lists alike.
+func listPages[T any](ctx context.Context, api *apiClient, path string, query url.Values, collect func(T)) error {
+	for page := 1; ; page++ {
+		query.Set("per_page", strconv.Itoa(pageSize))
+		query.Set("page", strconv.Itoa(page))
+		var items []T
+		if err := api.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &items); err != nil {
+			return err
+		}
+		for _, item := range items {
+			collect(item)
+		}
+		if len(items) < pageSize {
+			return nil
+		}
+	}
+}
+
+var (
+	_ IssueTracker = (*GitHub)(nil)
+	_ IssueTracker = (*GitLab)(nil)
+)
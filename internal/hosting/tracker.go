@@ -0,0 +1,194 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+)
+
+// EventStore is the storage the tracker needs: pull requests are recorded
+// as agent events on their issue, and final outcomes as issue comments
+type EventStore interface {
+	StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error
+	GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error)
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+}
+
+// TrackedPullRequest is a pull request opened for an issue, with its last
+// known status
+type TrackedPullRequest struct {
+	events.PullRequestData
+	IssueID   string
+	CreatedAt time.Time
+	UpdatedAt time.Time // When the status was last seen to change
+}
+
+// Final reports whether the pull request is merged or closed, so its status
+// no longer needs checking
+func (pr *TrackedPullRequest) Final() bool {
+	return pr.Status == StatusMerged || pr.Status == StatusClosed
+}
+
+// RecordCreated records that a pull request was opened for an issue
+func RecordCreated(ctx context.Context, store EventStore, issueID, executorID string, provider Provider, pr *PullRequest) error {
+	data := events.PullRequestData{
+		Provider:   provider.Name(),
+		Number:     pr.Number,
+		URL:        pr.URL,
+		Repo:       provider.Repo(),
+		Branch:     pr.SourceBranch,
+		BaseBranch: pr.TargetBranch,
+		Status:     pr.Status,
+	}
+	return storeEvent(ctx, store, events.EventTypePullRequestCreated, events.SeverityInfo, issueID, executorID,
+		fmt.Sprintf("Opened pull request %s", pr.URL), data)
+}
+
+// ListTracked returns the pull requests opened for an issue (or for all
+// issues if issueID is empty) with their last known status, oldest first
+func ListTracked(ctx context.Context, store EventStore, issueID string) ([]*TrackedPullRequest, error) {
+	var all []*events.AgentEvent
+	for _, eventType := range []events.EventType{events.EventTypePullRequestCreated, events.EventTypePullRequestStatus} {
+		evs, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issueID, Type: eventType})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request events: %w", err)
+		}
+		all = append(all, evs...)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+
+	tracked := make(map[string]*TrackedPullRequest)
+	var order []*TrackedPullRequest
+	for _, ev := range all {
+		data, err := ev.GetPullRequestData()
+		if err != nil || data.Number == 0 {
+			continue
+		}
+		if data.Provider == "" {
+			data.Provider = config.HostingGitHub // Recorded before GitLab support
+		}
+		key := fmt.Sprintf("%s:%s#%d", data.Provider, data.Repo, data.Number)
+		pr, ok := tracked[key]
+		if !ok {
+			if ev.Type != events.EventTypePullRequestCreated {
+				continue // Status of a pull request whose creation was pruned
+			}
+			pr = &TrackedPullRequest{PullRequestData: *data, IssueID: ev.IssueID, CreatedAt: ev.Timestamp, UpdatedAt: ev.Timestamp}
+			tracked[key] = pr
+			order = append(order, pr)
+			continue
+		}
+		pr.Status = data.Status
+		pr.UpdatedAt = ev.Timestamp
+	}
+	return order, nil
+}
+
+// StatusChange is a tracked pull request whose status changed
+type StatusChange struct {
+	PullRequest    *TrackedPullRequest
+	PreviousStatus string
+}
+
+// Sync checks every tracked pull request that isn't merged or closed and
+// records status changes as events. Merged and closed pull requests are also
+// noted on their issue. Pull requests that can't be checked (including those
+// on a provider without a token) are skipped and reported in the returned error.
+func Sync(ctx context.Context, store EventStore, cfg config.HostingConfig, executorID string) ([]StatusChange, error) {
+	tracked, err := ListTracked(ctx, store, "")
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make(map[string]Provider)
+	var changes []StatusChange
+	var failures []string
+	for _, pr := range tracked {
+		if pr.Final() {
+			continue
+		}
+		if ctx.Err() != nil {
+			return changes, ctx.Err()
+		}
+
+		key := pr.Provider + ":" + pr.Repo
+		provider, ok := providers[key]
+		if !ok {
+			provider, err = NewProvider(cfg, pr.Provider, pr.Repo)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", pr.URL, err))
+				continue
+			}
+			providers[key] = provider
+		}
+
+		current, err := provider.GetPullRequest(ctx, pr.Number)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", pr.URL, err))
+			continue
+		}
+		if current.Status == pr.Status {
+			continue
+		}
+
+		previous := pr.Status
+		data := pr.PullRequestData
+		data.Status = current.Status
+		data.PreviousStatus = previous
+		severity := events.SeverityInfo
+		if current.Status == StatusClosed {
+			severity = events.SeverityWarning
+		}
+		if err := storeEvent(ctx, store, events.EventTypePullRequestStatus, severity, pr.IssueID, executorID,
+			fmt.Sprintf("Pull request %s is now %s (was %s)", pr.URL, current.Status, previous), data); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", pr.URL, err))
+			continue
+		}
+		pr.Status = current.Status
+		changes = append(changes, StatusChange{PullRequest: pr, PreviousStatus: previous})
+
+		var comment string
+		switch current.Status {
+		case StatusMerged:
+			comment = fmt.Sprintf("Pull request merged into %s: %s", pr.BaseBranch, pr.URL)
+		case StatusClosed:
+			comment = fmt.Sprintf("Pull request closed without merging: %s", pr.URL)
+		}
+		if comment != "" {
+			if err := store.AddComment(ctx, pr.IssueID, executorID, comment); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: failed to add comment: %v", pr.URL, err))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return changes, fmt.Errorf("failed to sync %d pull request(s): %s", len(failures), strings.Join(failures, "; "))
+	}
+	return changes, nil
+}
+
+// storeEvent stores a pull request event
+func storeEvent(ctx context.Context, store EventStore, eventType events.EventType, severity events.EventSeverity, issueID, executorID, message string, data events.PullRequestData) error {
+	event := &events.AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		Severity:   severity,
+		Message:    message,
+	}
+	if err := event.SetPullRequestData(data); err != nil {
+		return err
+	}
+	if err := store.StoreAgentEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to store pull request event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package hosting
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	cfg := storage.DefaultConfig()
+	cfg.Path = t.TempDir() + "/test.db"
+	store, err := storage.NewStorage(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	issue := &types.Issue{Title: "Add feature", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	var merged atomic.Bool
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.EscapedPath() != "/projects/acme%2Fwidgets/merge_requests/7" {
+			http.NotFound(w, r)
+			return
+		}
+		if merged.Load() {
+			_, _ = w.Write([]byte(`{"iid": 7, "state": "merged"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"iid": 7, "state": "opened"}`))
+	}))
+	defer server.Close()
+	hostingCfg := config.DefaultHostingConfig()
+	hostingCfg.GitLab.Token = "glpat_secret"
+	hostingCfg.GitLab.APIURL = server.URL
+
+	provider := NewGitLab(hostingCfg.GitLab, "acme/widgets")
+	pr := &PullRequest{Number: 7, URL: "https://gitlab.com/acme/widgets/-/merge_requests/7", Status: StatusOpen,
+		SourceBranch: "vc/feature", TargetBranch: "main"}
+	if err := RecordCreated(ctx, store, issue.ID, "exec-1", provider, pr); err != nil {
+		t.Fatalf("RecordCreated failed: %v", err)
+	}
+
+	tracked, err := ListTracked(ctx, store, issue.ID)
+	if err != nil {
+		t.Fatalf("ListTracked failed: %v", err)
+	}
+	if len(tracked) != 1 || tracked[0].Number != 7 || tracked[0].Status != StatusOpen || tracked[0].Branch != "vc/feature" ||
+		tracked[0].Provider != config.HostingGitLab {
+		t.Fatalf("unexpected tracked pull requests: %+v", tracked)
+	}
+
+	// Unchanged status records nothing
+	changes, err := Sync(ctx, store, hostingCfg, "exec-1")
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("Sync() = %v, %v; want no changes", changes, err)
+	}
+
+	merged.Store(true)
+	changes, err = Sync(ctx, store, hostingCfg, "exec-1")
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].PreviousStatus != StatusOpen || changes[0].PullRequest.Status != StatusMerged {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	tracked, err = ListTracked(ctx, store, "")
+	if err != nil {
+		t.Fatalf("ListTracked failed: %v", err)
+	}
+	if len(tracked) != 1 || tracked[0].Status != StatusMerged || !tracked[0].Final() {
+		t.Fatalf("expected the pull request tracked as merged, got %+v", tracked)
+	}
+
+	// Merged pull requests are no longer checked
+	before := requests.Load()
+	if _, err := Sync(ctx, store, hostingCfg, "exec-1"); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if requests.Load() != before {
+		t.Error("expected merged pull requests not to be checked again")
+	}
+
+	evs, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	found := false
+	for _, ev := range evs {
+		if ev.EventType == types.EventCommented && ev.Comment != nil && strings.HasPrefix(*ev.Comment, "Pull request merged into main") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected the merge to be noted on the issue")
+	}
+}
@@ -0,0 +1,113 @@
+// Package idempotency makes the side effects of supervisor decisions safe to
+// re-run. Handlers that act on AI decisions (filing discovered issues, posting
+// comments, closing issues, recording a recovery strategy) record a key in
+// storage for each effect they apply and check for it first, so a handler
+// retried after a crash skips what its earlier run already did instead of
+// applying it twice.
+//
+// Keys are grouped into a Scope: one handler acting on one issue with one set
+// of inputs. A key is recorded right after its effect is applied, so a crash
+// in between can still repeat that one effect; the window is a single storage
+// write rather than a whole handler run. Keys expire after TTL, so a handler
+// that legitimately runs again later on the same inputs acts again.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// TTL is how long a recorded key suppresses its effect. Crashed work is
+// recovered when an executor next starts, well within a day.
+const TTL = 24 * time.Hour
+
+// Store records idempotency keys (implemented by storage.Storage)
+type Store interface {
+	GetIdempotencyKey(ctx context.Context, key string, since time.Time) (string, bool, error)
+	RecordIdempotencyKey(ctx context.Context, key, result string) error
+}
+
+// Hash returns a short, stable hash of parts, for keying effects on inputs
+// too long to put in a key (gate output, issue titles)
+func Hash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Scope namespaces the keys of one handler acting on one issue. Two runs with
+// the same handler, issue and inputs share a scope, so the second skips the
+// effects the first applied.
+type Scope struct {
+	store  Store
+	prefix string
+}
+
+// NewScope returns the scope of handler acting on issueID with the given
+// inputs. A nil store gives a scope that applies every effect.
+func NewScope(store Store, handler, issueID string, inputs ...string) *Scope {
+	prefix := handler + ":" + issueID
+	if len(inputs) > 0 {
+		prefix += ":" + Hash(inputs...)
+	}
+	return &Scope{store: store, prefix: prefix}
+}
+
+// Key returns the storage key of effect in this scope
+func (s *Scope) Key(effect string) string {
+	return s.prefix + ":" + effect
+}
+
+// Lookup returns the result recorded for effect, if it was applied within
+// TTL. Storage errors are reported and treated as not applied, so a broken
+// store never blocks work.
+func (s *Scope) Lookup(ctx context.Context, effect string) (string, bool) {
+	if s.store == nil {
+		return "", false
+	}
+	result, ok, err := s.store.GetIdempotencyKey(ctx, s.Key(effect), time.Now().Add(-TTL))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check idempotency key %s: %v (applying)\n", s.Key(effect), err)
+		return "", false
+	}
+	return result, ok
+}
+
+// Record records that effect was applied, with its result (e.g. the ID of a
+// created issue). Failures are reported but not returned: the effect itself
+// already happened.
+func (s *Scope) Record(ctx context.Context, effect, result string) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.RecordIdempotencyKey(ctx, s.Key(effect), result); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record idempotency key %s: %v\n", s.Key(effect), err)
+	}
+}
+
+// Do applies effect unless it was already applied in this scope, in which
+// case it returns the recorded result instead. applied reports whether apply
+// ran. A failed apply records nothing, so the next run tries again.
+func (s *Scope) Do(ctx context.Context, effect string, apply func() (string, error)) (result string, applied bool, err error) {
+	if result, ok := s.Lookup(ctx, effect); ok {
+		return result, false, nil
+	}
+	result, err = apply()
+	if err != nil {
+		return "", true, err
+	}
+	s.Record(ctx, effect, result)
+	return result, true, nil
+}
+
+// Once is Do for effects without a result
+func (s *Scope) Once(ctx context.Context, effect string, apply func() error) error {
+	_, _, err := s.Do(ctx, effect, func() (string, error) {
+		return "", apply()
+	})
+	return err
+}
@@ -0,0 +1,87 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+)
+
+func TestScopeDo(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	calls := 0
+	apply := func() (string, error) {
+		calls++
+		return "vc-1", nil
+	}
+
+	scope := NewScope(store, "gates", "vc-9", "test=failed")
+	result, applied, err := scope.Do(ctx, "blocker:test", apply)
+	if err != nil || !applied || result != "vc-1" {
+		t.Fatalf("first Do = (%q, %v, %v), want (vc-1, true, nil)", result, applied, err)
+	}
+
+	// A re-run in the same scope gets the recorded result without applying
+	result, applied, err = NewScope(store, "gates", "vc-9", "test=failed").Do(ctx, "blocker:test", apply)
+	if err != nil || applied || result != "vc-1" {
+		t.Errorf("re-run Do = (%q, %v, %v), want (vc-1, false, nil)", result, applied, err)
+	}
+	if calls != 1 {
+		t.Errorf("effect applied %d times, want 1", calls)
+	}
+
+	// Other inputs are another scope
+	if _, applied, _ := NewScope(store, "gates", "vc-9", "test=passed").Do(ctx, "blocker:test", apply); !applied {
+		t.Error("effect in a scope with other inputs was skipped")
+	}
+}
+
+func TestScopeDoFailure(t *testing.T) {
+	ctx := context.Background()
+	scope := NewScope(memory.New(), "gates", "vc-9")
+
+	// A failed effect isn't recorded, so the next run tries again
+	if err := scope.Once(ctx, "close", func() error { return errors.New("boom") }); err == nil {
+		t.Fatal("Once swallowed the effect's error")
+	}
+	ran := false
+	if err := scope.Once(ctx, "close", func() error { ran = true; return nil }); err != nil || !ran {
+		t.Errorf("effect wasn't retried after failing (err %v)", err)
+	}
+}
+
+func TestScopeNilStore(t *testing.T) {
+	ctx := context.Background()
+	scope := NewScope(nil, "gates", "vc-9")
+
+	calls := 0
+	for i := 0; i < 2; i++ {
+		if err := scope.Once(ctx, "comment", func() error { calls++; return nil }); err != nil {
+			t.Fatalf("Once failed: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("effect applied %d times without a store, want 2", calls)
+	}
+}
+
+func TestDeleteIdempotencyKeysBefore(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	scope := NewScope(store, "gates", "vc-9")
+	scope.Record(ctx, "comment", "")
+
+	if n, err := store.DeleteIdempotencyKeysBefore(ctx, time.Now().Add(-TTL)); err != nil || n != 0 {
+		t.Errorf("deleted %d fresh keys (err %v), want 0", n, err)
+	}
+	if n, err := store.DeleteIdempotencyKeysBefore(ctx, time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("deleted %d expired keys (err %v), want 1", n, err)
+	}
+	if _, ok := scope.Lookup(ctx, "comment"); ok {
+		t.Error("deleted key is still found")
+	}
+}
@@ -0,0 +1,236 @@
+package issuesync
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/hosting"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Fields are the synced fields of an issue, as VC values
+type Fields struct {
+	Title              string   `json:"title"`
+	Description        string   `json:"description"`
+	AcceptanceCriteria string   `json:"acceptance_criteria"`
+	Closed             bool     `json:"closed"`
+	Priority           int      `json:"priority"`
+	Labels             []string `json:"labels"` // Sorted, without the milestone's
+	// Milestone is the title of the remote milestone, a VC label with the
+	// milestone prefix; "" if there is none or milestones aren't synced
+	Milestone string `json:"milestone,omitempty"`
+}
+
+// vcFields returns the synced fields of a VC issue. Of several labels with
+// the milestone prefix, the first is the milestone and the others are
+// ignored.
+func (s *Syncer) vcFields(issue *types.Issue, labels []string) Fields {
+	fields := Fields{
+		Title:              strings.TrimSpace(issue.Title),
+		Description:        strings.TrimSpace(issue.Description),
+		AcceptanceCriteria: strings.TrimSpace(issue.AcceptanceCriteria),
+		Closed:             issue.Status == types.StatusClosed,
+		Priority:           issue.Priority,
+	}
+	var other []string
+	for _, label := range sortedLabels(labels) {
+		milestone, ok := s.milestone(label)
+		switch {
+		case !ok:
+			other = append(other, label)
+		case fields.Milestone == "":
+			fields.Milestone = milestone
+		}
+	}
+	fields.Labels = other
+	return fields
+}
+
+// remoteFields returns the synced fields of a remote issue. Fields the
+// remote doesn't have (acceptance criteria without their section, priority
+// without a P0-P4 label) are taken from fallback.
+func (s *Syncer) remoteFields(gh *hosting.Issue, fallback Fields) Fields {
+	fields := Fields{Title: strings.TrimSpace(gh.Title), Closed: gh.Closed(), Priority: -1}
+	if s.cfg.MilestonePrefix != "" {
+		fields.Milestone = gh.Milestone
+	}
+	var hasAcceptance bool
+	fields.Description, fields.AcceptanceCriteria, hasAcceptance = parseBody(gh.Body)
+	if !hasAcceptance {
+		fields.AcceptanceCriteria = fallback.AcceptanceCriteria
+	}
+	var labels []string
+	for _, label := range gh.Labels {
+		switch {
+		case label == labelBug || label == labelEnhancement:
+		case priorityLabelRegex.MatchString(label):
+			// The highest priority if there are several
+			if p := int(label[1] - '0'); fields.Priority < 0 || p < fields.Priority {
+				fields.Priority = p
+			}
+		default:
+			// Labels that read as milestones in VC are left out, so they
+			// don't change the milestone
+			if vcLabel := s.vcLabel(label); !s.isMilestone(vcLabel) {
+				labels = append(labels, vcLabel)
+			}
+		}
+	}
+	if fields.Priority < 0 {
+		fields.Priority = fallback.Priority
+	}
+	fields.Labels = sortedLabels(labels)
+	return fields
+}
+
+// milestone returns the milestone title a VC label stands for, reporting
+// whether it stands for one
+func (s *Syncer) milestone(label string) (string, bool) {
+	if s.cfg.MilestonePrefix == "" {
+		return "", false
+	}
+	title, ok := strings.CutPrefix(label, s.cfg.MilestonePrefix)
+	return title, ok && title != ""
+}
+
+func (s *Syncer) isMilestone(label string) bool {
+	_, ok := s.milestone(label)
+	return ok
+}
+
+// formatBody returns the remote body of an issue: its description, its
+// acceptance criteria and the marker linking it to the VC issue
+func formatBody(issueID string, fields Fields) string {
+	var b strings.Builder
+	if fields.Description != "" {
+		b.WriteString(fields.Description + "\n\n")
+	}
+	if fields.AcceptanceCriteria != "" {
+		b.WriteString(acceptanceHeading + "\n\n" + fields.AcceptanceCriteria + "\n\n")
+	}
+	fmt.Fprintf(&b, "<!-- vc-issue: %s -->", issueID)
+	return b.String()
+}
+
+// parseBody splits a remote body into the description and the acceptance
+// criteria, reporting whether it has an acceptance criteria section
+func parseBody(body string) (description, acceptance string, hasAcceptance bool) {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = issueMarkerRegex.ReplaceAllString(body, "\n")
+	lines := strings.Split(body, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == acceptanceHeading {
+			description = strings.TrimSpace(strings.Join(lines[:i], "\n"))
+			acceptance = strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+			return description, acceptance, true
+		}
+	}
+	return strings.TrimSpace(body), "", false
+}
+
+// merge returns the three-way merge of the VC and remote fields given base,
+// and the conflicts: fields both changed, decided for the remote if
+// remoteWins. Labels are merged as a set and never conflict.
+func merge(vc, remote, base Fields, remoteWins bool) (Fields, []types.SyncConflict) {
+	var conflicts []types.SyncConflict
+	pick := func(field, v, g, b string) bool {
+		switch {
+		case v == g || g == b:
+			return false
+		case v == b:
+			return true
+		}
+		winner := types.SyncLocal
+		if remoteWins {
+			winner = types.SyncRemote
+		}
+		conflicts = append(conflicts, types.SyncConflict{Field: field, Local: v, Remote: g, Winner: winner})
+		return remoteWins
+	}
+	status := func(closed bool) string {
+		if closed {
+			return string(types.StatusClosed)
+		}
+		return string(types.StatusOpen)
+	}
+
+	merged := vc
+	if pick("title", vc.Title, remote.Title, base.Title) {
+		merged.Title = remote.Title
+	}
+	if pick("description", vc.Description, remote.Description, base.Description) {
+		merged.Description = remote.Description
+	}
+	if pick("acceptance_criteria", vc.AcceptanceCriteria, remote.AcceptanceCriteria, base.AcceptanceCriteria) {
+		merged.AcceptanceCriteria = remote.AcceptanceCriteria
+	}
+	if pick("status", status(vc.Closed), status(remote.Closed), status(base.Closed)) {
+		merged.Closed = remote.Closed
+	}
+	if pick("priority", strconv.Itoa(vc.Priority), strconv.Itoa(remote.Priority), strconv.Itoa(base.Priority)) {
+		merged.Priority = remote.Priority
+	}
+	if pick("milestone", vc.Milestone, remote.Milestone, base.Milestone) {
+		merged.Milestone = remote.Milestone
+	}
+
+	labels := make(map[string]bool)
+	vcLabels, remoteLabels, baseLabels := labelSet(vc.Labels), labelSet(remote.Labels), labelSet(base.Labels)
+	for label := range vcLabels {
+		if remoteLabels[label] || !baseLabels[label] {
+			labels[label] = true
+		}
+	}
+	for label := range remoteLabels {
+		if !vcLabels[label] && !baseLabels[label] {
+			labels[label] = true
+		}
+	}
+	merged.Labels = nil
+	for label := range labels {
+		merged.Labels = append(merged.Labels, label)
+	}
+	merged.Labels = sortedLabels(merged.Labels)
+	return merged, conflicts
+}
+
+func equalFields(a, b Fields) bool {
+	added, removed := diffLabels(a.Labels, b.Labels)
+	return a.Title == b.Title && a.Description == b.Description && a.AcceptanceCriteria == b.AcceptanceCriteria &&
+		a.Closed == b.Closed && a.Priority == b.Priority && a.Milestone == b.Milestone && len(added) == 0 && len(removed) == 0
+}
+
+// diffLabels returns the labels to add to and remove from "from" to get "to"
+func diffLabels(from, to []string) (added, removed []string) {
+	fromSet, toSet := labelSet(from), labelSet(to)
+	for _, label := range to {
+		if !fromSet[label] {
+			added = append(added, label)
+		}
+	}
+	for _, label := range from {
+		if !toSet[label] {
+			removed = append(removed, label)
+		}
+	}
+	return added, removed
+}
+
+func labelSet(labels []string) map[string]bool {
+	set := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		set[label] = true
+	}
+	return set
+}
+
+func sortedLabels(labels []string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), labels...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,612 @@
+// Package issuesync mirrors VC issues to a git host's issue tracker (GitHub
+// Issues or GitLab issues) and back, so stakeholders who live there can
+// follow and steer the autonomous backlog without VC tooling.
+//
+// Every VC issue is linked to one remote issue. Unclosed VC issues without
+// a link are opened on the remote, and open remote issues without one are
+// imported into VC (with the sync label only, when one is configured).
+// Linked issues are then merged three ways, like 'vc sync' merges two
+// databases: each side is compared with the base, the issue as both sides
+// left it after the last sync, and a field changed on one side is copied
+// to the other. A field both sides changed is a conflict, decided by the
+// configured preference (the side updated last by default).
+//
+// The synced fields are the title, description, acceptance criteria (an
+// "Acceptance Criteria" section of the remote body), open or closed,
+// priority (a P0-P4 label on the remote), labels, renamed through the
+// label map, and, when a milestone prefix is configured, the milestone (a
+// VC label made of the prefix and the milestone's title). The issue type
+// becomes a bug or enhancement label when an issue is opened on the
+// remote, and is read from them when one is imported. New comments are
+// copied both ways; edits are not.
+//
+// The sync state (links and bases) is kept in a file next to the database.
+// Issues VC opens on the remote carry a hidden marker with their VC ID, so
+// links lost with the state are restored instead of duplicated.
+package issuesync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/hosting"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// clockSkew is how far before the last sync remote changes are fetched
+// from, in case the remote's clock is behind ours
+const clockSkew = time.Minute
+
+// acceptanceHeading starts the acceptance criteria in a remote issue body
+const acceptanceHeading = "## Acceptance Criteria"
+
+var (
+	// issueMarkerRegex matches the marker linking a remote issue to its VC issue
+	issueMarkerRegex = regexp.MustCompile(`\n*<!-- vc-issue: (\S+) -->\s*`)
+	// commentMarkerRegex matches the marker on comments copied from VC
+	commentMarkerRegex = regexp.MustCompile(`<!-- vc-comment: (\d+) -->`)
+	// priorityLabelRegex matches the remote labels priorities map to
+	priorityLabelRegex = regexp.MustCompile(`^P([0-4])$`)
+)
+
+// Type labels on the remote
+const (
+	labelBug         = "bug"
+	labelEnhancement = "enhancement"
+)
+
+// Actor returns who a sync with provider records its changes to VC issues
+// as, e.g. "github-sync"
+func Actor(provider string) string {
+	return provider + "-sync"
+}
+
+// CommentActorPrefix returns the start of the actor of comments imported
+// from provider, followed by the remote user name, e.g. "github:"
+func CommentActorPrefix(provider string) string {
+	return provider + ":"
+}
+
+// Store is the storage the sync uses
+type Store interface {
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	CloseIssue(ctx context.Context, id string, reason string, actor string) error
+	GetLabels(ctx context.Context, issueID string) ([]string, error)
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	RemoveLabel(ctx context.Context, issueID, label, actor string) error
+	GetComments(ctx context.Context, issueID string) ([]*types.Comment, error)
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+}
+
+// Stats reports what a sync did
+type Stats struct {
+	VC     SideStats
+	Remote SideStats
+	// Relinked counts remote issues linked back to their VC issue by the
+	// marker in their body, after the sync state was lost
+	Relinked int
+	// Conflicts lists fields both sides changed. Local is the VC value,
+	// Remote the remote one.
+	Conflicts []types.SyncConflict
+}
+
+// SideStats counts the changes a sync made on one side
+type SideStats struct {
+	IssuesCreated int
+	IssuesUpdated int
+	CommentsAdded int
+}
+
+// Changed reports whether the sync changed anything on this side
+func (s SideStats) Changed() bool {
+	return s != SideStats{}
+}
+
+// Syncer syncs a VC database with a repository's issues
+type Syncer struct {
+	store     Store
+	client    hosting.IssueTracker
+	cfg       config.IssueSyncConfig
+	statePath string
+	actor     string // Actor(provider)
+	remote    string // Provider name for messages, e.g. "GitHub"
+	now       func() time.Time
+}
+
+// NewSyncer creates a syncer that keeps its state in statePath (see
+// StatePath)
+func NewSyncer(store Store, client hosting.IssueTracker, cfg config.IssueSyncConfig, statePath string) *Syncer {
+	return &Syncer{
+		store:     store,
+		client:    client,
+		cfg:       cfg,
+		statePath: statePath,
+		actor:     Actor(client.Name()),
+		remote:    hosting.DisplayName(client.Name()),
+		now:       time.Now,
+	}
+}
+
+// Run syncs every configured interval until ctx is done
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats, err := s.Sync(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("issuesync: issues sync failed", "provider", s.client.Name(), "repo", s.client.Repo(), "error", err)
+			}
+			if stats != nil && (stats.VC.Changed() || stats.Remote.Changed()) {
+				fmt.Printf("%s sync: %d issue(s) created, %d updated, %d comment(s) copied in VC; %d created, %d updated, %d copied on %s\n",
+					s.remote, stats.VC.IssuesCreated, stats.VC.IssuesUpdated, stats.VC.CommentsAdded,
+					stats.Remote.IssuesCreated, stats.Remote.IssuesUpdated, stats.Remote.CommentsAdded, s.remote)
+			}
+		}
+	}
+}
+
+// Sync runs one sync and saves its state. The state is saved even if the
+// sync fails part way, so issues it created stay linked.
+func (s *Syncer) Sync(ctx context.Context) (*Stats, error) {
+	state, err := LoadState(s.statePath)
+	if err != nil {
+		return nil, err
+	}
+	if state.Repo == "" {
+		state.Repo = s.client.Repo()
+	}
+	stats := &Stats{}
+	syncErr := s.sync(ctx, state, stats)
+	if err := SaveState(s.statePath, state); err != nil {
+		return stats, err
+	}
+	return stats, syncErr
+}
+
+// sync runs one sync, updating state as it goes
+func (s *Syncer) sync(ctx context.Context, state *State, stats *Stats) error {
+	started := s.now()
+	since := state.SyncedAt
+	if !since.IsZero() {
+		since = since.Add(-clockSkew)
+	}
+
+	remoteIssues, err := s.client.ListIssues(ctx, since)
+	if err != nil {
+		return err
+	}
+	byNumber := make(map[int]*Link)
+	byIssue := make(map[string]*Link)
+	for _, link := range state.Links {
+		byNumber[link.Number] = link
+		byIssue[link.IssueID] = link
+	}
+	addLink := func(link *Link) {
+		state.Links = append(state.Links, link)
+		byNumber[link.Number] = link
+		byIssue[link.IssueID] = link
+	}
+
+	// Remote issues changed since the last sync; unchanged ones are as
+	// their base says
+	changed := make(map[int]*hosting.Issue)
+	// Issues linked by this sync, whose comments are all fetched
+	fresh := make(map[int]bool)
+	for _, gh := range remoteIssues {
+		if byNumber[gh.Number] != nil {
+			changed[gh.Number] = gh
+			continue
+		}
+		if link, err := s.relink(ctx, gh, byIssue); err != nil {
+			return err
+		} else if link != nil {
+			addLink(link)
+			changed[gh.Number] = gh
+			fresh[gh.Number] = true
+			stats.Relinked++
+			continue
+		}
+		if gh.Closed() || !s.wanted(gh.Labels, s.vcLabel) {
+			continue
+		}
+		link, err := s.importIssue(ctx, gh)
+		if err != nil {
+			return err
+		}
+		addLink(link)
+		fresh[gh.Number] = true
+		stats.VC.IssuesCreated++
+	}
+
+	issues, err := s.store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to list issues: %w", err)
+	}
+	for _, issue := range issues {
+		if byIssue[issue.ID] != nil || issue.Status == types.StatusClosed {
+			continue
+		}
+		labels, err := s.store.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get labels of %s: %w", issue.ID, err)
+		}
+		if !s.wanted(labels, func(label string) string { return label }) {
+			continue
+		}
+		link, err := s.openRemote(ctx, issue, labels)
+		if err != nil {
+			return err
+		}
+		addLink(link)
+		stats.Remote.IssuesCreated++
+	}
+
+	for _, link := range state.Links {
+		if err := s.syncLink(ctx, link, changed[link.Number], stats); err != nil {
+			return err
+		}
+	}
+	if err := s.syncComments(ctx, state.Links, since, fresh, stats); err != nil {
+		return err
+	}
+
+	state.SyncedAt = started
+	return nil
+}
+
+// wanted reports whether an issue with labels is synced when it has no
+// link yet. name converts the labels to VC names.
+func (s *Syncer) wanted(labels []string, name func(string) string) bool {
+	if s.cfg.Label == "" {
+		return true
+	}
+	for _, label := range labels {
+		if name(label) == s.cfg.Label {
+			return true
+		}
+	}
+	return false
+}
+
+// relink links a remote issue VC opened back to its VC issue, from the
+// marker in its body. Returns nil if it has none or its issue is gone or
+// linked.
+func (s *Syncer) relink(ctx context.Context, gh *hosting.Issue, byIssue map[string]*Link) (*Link, error) {
+	m := issueMarkerRegex.FindStringSubmatch(gh.Body)
+	if m == nil || byIssue[m[1]] != nil {
+		return nil, nil
+	}
+	issue, err := s.store.GetIssue(ctx, m[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", m[1], err)
+	}
+	if issue == nil {
+		return nil, nil
+	}
+	labels, err := s.store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels of %s: %w", issue.ID, err)
+	}
+	// Without a base, what differs is taken from VC
+	return &Link{IssueID: issue.ID, Number: gh.Number, URL: gh.URL, Base: s.remoteFields(gh, s.vcFields(issue, labels))}, nil
+}
+
+// importIssue creates a VC issue for a remote issue
+func (s *Syncer) importIssue(ctx context.Context, gh *hosting.Issue) (*Link, error) {
+	description, acceptance, _ := parseBody(gh.Body)
+	if acceptance == "" {
+		acceptance = "Resolves " + gh.URL
+	}
+	issue := &types.Issue{
+		Title:              gh.Title,
+		Description:        description,
+		AcceptanceCriteria: acceptance,
+		Status:             types.StatusOpen,
+		Priority:           2,
+		IssueType:          types.TypeTask,
+	}
+	var labels []string
+	for _, label := range gh.Labels {
+		switch {
+		case label == labelBug:
+			issue.IssueType = types.TypeBug
+		case label == labelEnhancement:
+			issue.IssueType = types.TypeFeature
+		case priorityLabelRegex.MatchString(label):
+			issue.Priority = int(label[1] - '0')
+		default:
+			labels = append(labels, s.vcLabel(label))
+		}
+	}
+	if s.cfg.MilestonePrefix != "" && gh.Milestone != "" {
+		labels = append(labels, s.cfg.MilestonePrefix+gh.Milestone)
+	}
+	if err := s.store.CreateIssue(ctx, issue, s.actor); err != nil {
+		return nil, fmt.Errorf("failed to import %s issue #%d: %w", s.remote, gh.Number, err)
+	}
+	for _, label := range labels {
+		if err := s.store.AddLabel(ctx, issue.ID, label, s.actor); err != nil {
+			return nil, fmt.Errorf("failed to label %s: %w", issue.ID, err)
+		}
+	}
+	return &Link{IssueID: issue.ID, Number: gh.Number, URL: gh.URL, Base: s.vcFields(issue, labels)}, nil
+}
+
+// openRemote opens a remote issue for a VC issue
+func (s *Syncer) openRemote(ctx context.Context, issue *types.Issue, labels []string) (*Link, error) {
+	fields := s.vcFields(issue, labels)
+	remoteLabels := []string{fmt.Sprintf("P%d", fields.Priority)}
+	switch issue.IssueType {
+	case types.TypeBug:
+		remoteLabels = append(remoteLabels, labelBug)
+	case types.TypeFeature:
+		remoteLabels = append(remoteLabels, labelEnhancement)
+	}
+	for _, label := range fields.Labels {
+		remoteLabels = append(remoteLabels, s.remoteLabel(label))
+	}
+	gh, err := s.client.CreateIssue(ctx, hosting.NewIssue{
+		Title:     fields.Title,
+		Body:      formatBody(issue.ID, fields),
+		Labels:    remoteLabels,
+		Milestone: fields.Milestone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s on %s: %w", issue.ID, s.remote, err)
+	}
+	return &Link{IssueID: issue.ID, Number: gh.Number, URL: gh.URL, Base: fields}, nil
+}
+
+// syncLink merges a linked issue. gh is the remote issue if it changed
+// since the last sync, nil if it is as the base says.
+func (s *Syncer) syncLink(ctx context.Context, link *Link, gh *hosting.Issue, stats *Stats) error {
+	issue, err := s.store.GetIssue(ctx, link.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", link.IssueID, err)
+	}
+	if issue == nil {
+		return nil // Deleted issues aren't propagated
+	}
+	labels, err := s.store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get labels of %s: %w", issue.ID, err)
+	}
+	vc := s.vcFields(issue, labels)
+	remote := link.Base
+	remoteWins := false
+	if gh != nil {
+		remote = s.remoteFields(gh, link.Base)
+		switch s.cfg.Prefer {
+		case s.cfg.Provider:
+			remoteWins = true
+		case config.IssueSyncPreferNewer:
+			remoteWins = gh.UpdatedAt.After(issue.UpdatedAt)
+		}
+	}
+
+	merged, conflicts := merge(vc, remote, link.Base, remoteWins)
+	for _, c := range conflicts {
+		c.IssueID = issue.ID
+		stats.Conflicts = append(stats.Conflicts, c)
+	}
+	if err := s.updateVC(ctx, issue.ID, link.Number, vc, merged); err != nil {
+		return err
+	}
+	if err := s.updateRemote(ctx, issue.ID, link.Number, remote, merged); err != nil {
+		return err
+	}
+	if !equalFields(vc, merged) {
+		stats.VC.IssuesUpdated++
+	}
+	if !equalFields(remote, merged) {
+		stats.Remote.IssuesUpdated++
+	}
+	link.Base = merged
+	return nil
+}
+
+// updateVC brings a VC issue from its fields to the merged ones
+func (s *Syncer) updateVC(ctx context.Context, issueID string, number int, from, to Fields) error {
+	updates := make(map[string]interface{})
+	if from.Title != to.Title {
+		updates["title"] = to.Title
+	}
+	if from.Description != to.Description {
+		updates["description"] = to.Description
+	}
+	if from.AcceptanceCriteria != to.AcceptanceCriteria {
+		updates["acceptance_criteria"] = to.AcceptanceCriteria
+	}
+	if from.Priority != to.Priority {
+		updates["priority"] = to.Priority
+	}
+	if from.Closed && !to.Closed {
+		updates["status"] = string(types.StatusOpen)
+	}
+	if len(updates) > 0 {
+		if err := s.store.UpdateIssue(ctx, issueID, updates, s.actor); err != nil {
+			return fmt.Errorf("failed to update %s: %w", issueID, err)
+		}
+	}
+	if !from.Closed && to.Closed {
+		if err := s.store.CloseIssue(ctx, issueID, fmt.Sprintf("Closed on %s (#%d)", s.remote, number), s.actor); err != nil {
+			return fmt.Errorf("failed to close %s: %w", issueID, err)
+		}
+	}
+	added, removed := diffLabels(from.Labels, to.Labels)
+	if from.Milestone != to.Milestone {
+		if to.Milestone != "" {
+			added = append(added, s.cfg.MilestonePrefix+to.Milestone)
+		}
+		if from.Milestone != "" {
+			removed = append(removed, s.cfg.MilestonePrefix+from.Milestone)
+		}
+	}
+	for _, label := range added {
+		if err := s.store.AddLabel(ctx, issueID, label, s.actor); err != nil {
+			return fmt.Errorf("failed to label %s: %w", issueID, err)
+		}
+	}
+	for _, label := range removed {
+		if err := s.store.RemoveLabel(ctx, issueID, label, s.actor); err != nil {
+			return fmt.Errorf("failed to unlabel %s: %w", issueID, err)
+		}
+	}
+	return nil
+}
+
+// updateRemote brings a remote issue from its fields to the merged ones
+func (s *Syncer) updateRemote(ctx context.Context, issueID string, number int, from, to Fields) error {
+	var update hosting.IssueUpdate
+	if from.Title != to.Title {
+		update.Title = &to.Title
+	}
+	if from.Description != to.Description || from.AcceptanceCriteria != to.AcceptanceCriteria {
+		body := formatBody(issueID, to)
+		update.Body = &body
+	}
+	if from.Closed != to.Closed {
+		state := "open"
+		if to.Closed {
+			state = "closed"
+		}
+		update.State = &state
+	}
+	if from.Milestone != to.Milestone {
+		update.Milestone = &to.Milestone
+	}
+	if update != (hosting.IssueUpdate{}) {
+		if _, err := s.client.UpdateIssue(ctx, number, update); err != nil {
+			return err
+		}
+	}
+
+	added, removed := diffLabels(from.Labels, to.Labels)
+	var add, remove []string
+	for _, label := range added {
+		add = append(add, s.remoteLabel(label))
+	}
+	for _, label := range removed {
+		remove = append(remove, s.remoteLabel(label))
+	}
+	if from.Priority != to.Priority {
+		add = append(add, fmt.Sprintf("P%d", to.Priority))
+		remove = append(remove, fmt.Sprintf("P%d", from.Priority))
+	}
+	if len(add) > 0 {
+		if err := s.client.AddIssueLabels(ctx, number, add); err != nil {
+			return err
+		}
+	}
+	for _, label := range remove {
+		if err := s.client.RemoveIssueLabel(ctx, number, label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncComments copies new comments between linked issues. Remote comments
+// are fetched since the last sync, except for fresh issues, linked
+// by this sync, whose comments are all fetched.
+func (s *Syncer) syncComments(ctx context.Context, links []*Link, since time.Time, fresh map[int]bool, stats *Stats) error {
+	byNumber := make(map[int][]*hosting.IssueComment)
+	if len(links) > len(fresh) {
+		comments, err := s.client.ListComments(ctx, since)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			byNumber[comment.IssueNumber] = append(byNumber[comment.IssueNumber], comment)
+		}
+	}
+
+	for _, link := range links {
+		remoteComments := byNumber[link.Number]
+		if fresh[link.Number] {
+			var err error
+			if remoteComments, err = s.client.ListIssueComments(ctx, link.Number); err != nil {
+				return err
+			}
+		}
+		seenRemote := idSet(link.RemoteComments)
+		seenVC := idSet(link.VCComments)
+		commentPrefix := CommentActorPrefix(s.client.Name())
+		for _, comment := range remoteComments {
+			if seenRemote[comment.ID] {
+				continue
+			}
+			link.RemoteComments = append(link.RemoteComments, comment.ID)
+			// Copied from VC before the sync state was lost
+			if m := commentMarkerRegex.FindStringSubmatch(comment.Body); m != nil {
+				id, _ := strconv.ParseInt(m[1], 10, 64)
+				link.VCComments = append(link.VCComments, id)
+				seenVC[id] = true
+				continue
+			}
+			if err := s.store.AddComment(ctx, link.IssueID, commentPrefix+comment.Author, comment.Body); err != nil {
+				return fmt.Errorf("failed to copy comment to %s: %w", link.IssueID, err)
+			}
+			stats.VC.CommentsAdded++
+		}
+
+		comments, err := s.store.GetComments(ctx, link.IssueID)
+		if err != nil {
+			return fmt.Errorf("failed to get comments of %s: %w", link.IssueID, err)
+		}
+		for _, comment := range comments {
+			if seenVC[comment.ID] || strings.HasPrefix(comment.Author, commentPrefix) {
+				continue
+			}
+			body := fmt.Sprintf("**%s** commented in VC:\n\n%s\n\n<!-- vc-comment: %d -->", comment.Author, comment.Body, comment.ID)
+			created, err := s.client.CreateComment(ctx, link.Number, body)
+			if err != nil {
+				return err
+			}
+			link.VCComments = append(link.VCComments, comment.ID)
+			link.RemoteComments = append(link.RemoteComments, created.ID)
+			stats.Remote.CommentsAdded++
+		}
+	}
+	return nil
+}
+
+// remoteLabel returns the remote name of a VC label
+func (s *Syncer) remoteLabel(label string) string {
+	if mapped, ok := s.cfg.LabelMap[label]; ok {
+		return mapped
+	}
+	return label
+}
+
+// vcLabel returns the VC name of a remote label
+func (s *Syncer) vcLabel(label string) string {
+	for vcLabel, remoteLabel := range s.cfg.LabelMap {
+		if remoteLabel == label {
+			return vcLabel
+		}
+	}
+	return label
+}
+
+func idSet(ids []int64) map[int64]bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
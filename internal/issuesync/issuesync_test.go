@@ -0,0 +1,365 @@
+package issuesync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/hosting"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// fakeTracker is an in-memory repository's issue tracker
+type fakeTracker struct {
+	provider string
+	issues   map[int]*hosting.Issue
+	comments []*hosting.IssueComment
+	nextID   int64
+}
+
+func newFakeTracker(provider string) *fakeTracker {
+	return &fakeTracker{provider: provider, issues: make(map[int]*hosting.Issue), nextID: 1000}
+}
+
+func (f *fakeTracker) Name() string { return f.provider }
+
+func (f *fakeTracker) Repo() string { return "acme/widgets" }
+
+// add creates an issue as a user of the tracker would
+func (f *fakeTracker) add(title, body string, labels ...string) *hosting.Issue {
+	number := len(f.issues) + 1
+	issue := &hosting.Issue{Number: number, URL: fmt.Sprintf("https://github.com/acme/widgets/issues/%d", number),
+		Title: title, Body: body, State: "open", Labels: labels, Author: "octocat", UpdatedAt: time.Now()}
+	f.issues[number] = issue
+	return issue
+}
+
+func (f *fakeTracker) comment(number int, author, body string) {
+	f.nextID++
+	f.comments = append(f.comments, &hosting.IssueComment{ID: f.nextID, IssueNumber: number, Author: author, Body: body, CreatedAt: time.Now()})
+}
+
+func (f *fakeTracker) ListIssues(ctx context.Context, since time.Time) ([]*hosting.Issue, error) {
+	var issues []*hosting.Issue
+	for number := 1; number <= len(f.issues); number++ {
+		if issue := f.issues[number]; !issue.UpdatedAt.Before(since) {
+			copied := *issue
+			issues = append(issues, &copied)
+		}
+	}
+	return issues, nil
+}
+
+func (f *fakeTracker) CreateIssue(ctx context.Context, req hosting.NewIssue) (*hosting.Issue, error) {
+	issue := f.add(req.Title, req.Body, req.Labels...)
+	issue.Milestone = req.Milestone
+	issue.Author = "vc-bot"
+	return issue, nil
+}
+
+func (f *fakeTracker) UpdateIssue(ctx context.Context, number int, update hosting.IssueUpdate) (*hosting.Issue, error) {
+	issue := f.issues[number]
+	if update.Title != nil {
+		issue.Title = *update.Title
+	}
+	if update.Body != nil {
+		issue.Body = *update.Body
+	}
+	if update.State != nil {
+		issue.State = *update.State
+	}
+	if update.Milestone != nil {
+		issue.Milestone = *update.Milestone
+	}
+	issue.UpdatedAt = time.Now()
+	return issue, nil
+}
+
+func (f *fakeTracker) AddIssueLabels(ctx context.Context, number int, labels []string) error {
+	f.issues[number].Labels = append(f.issues[number].Labels, labels...)
+	return nil
+}
+
+func (f *fakeTracker) RemoveIssueLabel(ctx context.Context, number int, label string) error {
+	issue := f.issues[number]
+	var kept []string
+	for _, l := range issue.Labels {
+		if l != label {
+			kept = append(kept, l)
+		}
+	}
+	issue.Labels = kept
+	return nil
+}
+
+func (f *fakeTracker) ListComments(ctx context.Context, since time.Time) ([]*hosting.IssueComment, error) {
+	var comments []*hosting.IssueComment
+	for _, c := range f.comments {
+		if !c.CreatedAt.Before(since) {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+func (f *fakeTracker) ListIssueComments(ctx context.Context, number int) ([]*hosting.IssueComment, error) {
+	var comments []*hosting.IssueComment
+	for _, c := range f.comments {
+		if c.IssueNumber == number {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+func (f *fakeTracker) CreateComment(ctx context.Context, number int, body string) (*hosting.IssueComment, error) {
+	f.comment(number, "vc-bot", body)
+	return f.comments[len(f.comments)-1], nil
+}
+
+func sortedCopy(labels []string) []string {
+	sorted := append([]string(nil), labels...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	github := newFakeTracker(config.HostingGitHub)
+	cfg := config.DefaultIssueSyncConfig(config.HostingGitHub)
+	cfg.LabelMap = map[string]string{"ui": "area: ui"}
+	statePath := filepath.Join(t.TempDir(), "sync", "github-acme-widgets.json")
+	syncer := NewSyncer(store, github, cfg, statePath)
+
+	bug := &types.Issue{Title: "Login fails", Description: "500 on submit", AcceptanceCriteria: "Login works",
+		Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+	if err := store.CreateIssue(ctx, bug, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if err := store.AddLabel(ctx, bug.ID, "ui", "alice"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	if err := store.AddComment(ctx, bug.ID, "alice", "Started after the deploy"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	done := &types.Issue{Title: "Old work", AcceptanceCriteria: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, done, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if err := store.CloseIssue(ctx, done.ID, "done", "alice"); err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+	request := github.add("Dark mode", "Please add it\r\n\r\n## Acceptance Criteria\r\n\r\nA toggle in settings", "enhancement", "P0", "docs")
+	github.comment(request.Number, "octocat", "+1, my eyes")
+	github.add("Closed idea", "Nope").State = "closed"
+
+	// First sync: the open issues of each side are created on the other
+	stats, err := syncer.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.Remote.IssuesCreated != 1 || stats.VC.IssuesCreated != 1 || stats.Remote.CommentsAdded != 1 || stats.VC.CommentsAdded != 1 {
+		t.Errorf("first sync stats = %+v", stats)
+	}
+	pushed := github.issues[3]
+	if pushed == nil || pushed.Title != "Login fails" {
+		t.Fatalf("expected %s on GitHub as #3, got %+v", bug.ID, github.issues)
+	}
+	if want := "500 on submit\n\n## Acceptance Criteria\n\nLogin works\n\n<!-- vc-issue: " + bug.ID + " -->"; pushed.Body != want {
+		t.Errorf("body = %q, want %q", pushed.Body, want)
+	}
+	if got := sortedCopy(pushed.Labels); !reflect.DeepEqual(got, []string{"P1", "area: ui", "bug"}) {
+		t.Errorf("labels on GitHub = %v", got)
+	}
+	if c := github.comments[len(github.comments)-1]; c.IssueNumber != 3 || !strings.Contains(c.Body, "**alice** commented in VC") {
+		t.Errorf("expected alice's comment on #3, got %+v", c)
+	}
+
+	feature, err := store.SearchIssues(ctx, "Dark mode", types.IssueFilter{})
+	if err != nil || len(feature) != 1 {
+		t.Fatalf("expected the GitHub issue imported, got %v (err %v)", feature, err)
+	}
+	imported := feature[0]
+	if imported.IssueType != types.TypeFeature || imported.Priority != 0 || imported.Description != "Please add it" ||
+		imported.AcceptanceCriteria != "A toggle in settings" {
+		t.Errorf("imported issue = %+v", imported)
+	}
+	if labels, _ := store.GetLabels(ctx, imported.ID); !reflect.DeepEqual(labels, []string{"docs"}) {
+		t.Errorf("imported labels = %v, want [docs]", labels)
+	}
+	comments, _ := store.GetComments(ctx, imported.ID)
+	if len(comments) != 1 || comments[0].Author != "github:octocat" || comments[0].Body != "+1, my eyes" {
+		t.Errorf("imported comments = %+v", comments)
+	}
+
+	// Nothing changed, so nothing is copied back
+	stats, err = syncer.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.VC.Changed() || stats.Remote.Changed() || len(stats.Conflicts) != 0 {
+		t.Errorf("expected an idle sync, got %+v", stats)
+	}
+
+	// Changes to different fields on each side are merged
+	github.issues[3].Title = "Login fails on Safari"
+	github.issues[3].Labels = []string{"bug", "P1", "area: ui", "regression"}
+	github.issues[3].UpdatedAt = time.Now()
+	github.comment(3, "octocat", "Safari only")
+	if err := store.UpdateIssue(ctx, bug.ID, map[string]interface{}{"description": "500 on submit from Safari", "priority": 0}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if err := store.CloseIssue(ctx, imported.ID, "shipped", "alice"); err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+	stats, err = syncer.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(stats.Conflicts) != 0 || stats.VC.CommentsAdded != 1 {
+		t.Errorf("merge stats = %+v", stats)
+	}
+	got, _ := store.GetIssue(ctx, bug.ID)
+	if got.Title != "Login fails on Safari" || got.Priority != 0 {
+		t.Errorf("VC issue after merge = %+v", got)
+	}
+	if labels, _ := store.GetLabels(ctx, bug.ID); !reflect.DeepEqual(sortedCopy(labels), []string{"regression", "ui"}) {
+		t.Errorf("VC labels after merge = %v", labels)
+	}
+	if !strings.HasPrefix(github.issues[3].Body, "500 on submit from Safari\n") {
+		t.Errorf("GitHub body after merge = %q", github.issues[3].Body)
+	}
+	if labels := sortedCopy(github.issues[3].Labels); !reflect.DeepEqual(labels, []string{"P0", "area: ui", "bug", "regression"}) {
+		t.Errorf("GitHub labels after merge = %v", labels)
+	}
+	if !github.issues[request.Number].Closed() {
+		t.Error("expected the issue closed in VC to be closed on GitHub")
+	}
+
+	// Both sides retitle the issue: GitHub's edit is newer
+	if err := store.UpdateIssue(ctx, bug.ID, map[string]interface{}{"title": "Safari login"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	github.issues[3].Title = "Safari login broken"
+	github.issues[3].UpdatedAt = time.Now().Add(time.Second)
+	stats, err = syncer.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	want := []types.SyncConflict{{IssueID: bug.ID, Field: "title", Local: "Safari login", Remote: "Safari login broken", Winner: types.SyncRemote}}
+	if !reflect.DeepEqual(stats.Conflicts, want) {
+		t.Errorf("conflicts = %+v, want %+v", stats.Conflicts, want)
+	}
+	if got, _ := store.GetIssue(ctx, bug.ID); got.Title != "Safari login broken" {
+		t.Errorf("title = %q, want GitHub's", got.Title)
+	}
+
+	// Losing the state relinks the issues VC opened rather than duplicating them
+	if err := os.Remove(statePath); err != nil {
+		t.Fatalf("failed to remove state: %v", err)
+	}
+	stats, err = syncer.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.Relinked != 1 || stats.Remote.IssuesCreated != 0 || stats.Remote.CommentsAdded != 0 {
+		t.Errorf("expected %s relinked without duplicates, got %+v", bug.ID, stats)
+	}
+}
+
+func TestSyncMilestones(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	gitlab := newFakeTracker(config.HostingGitLab)
+	cfg := config.DefaultIssueSyncConfig(config.HostingGitLab)
+	cfg.MilestonePrefix = "release:"
+	syncer := NewSyncer(store, gitlab, cfg, StatePath(t.TempDir(), config.HostingGitLab, "acme/widgets"))
+
+	planned := &types.Issue{Title: "Export to CSV", AcceptanceCriteria: "CSV downloads", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, planned, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	for _, label := range []string{"release:v2", "backend"} {
+		if err := store.AddLabel(ctx, planned.ID, label, "alice"); err != nil {
+			t.Fatalf("AddLabel() error = %v", err)
+		}
+	}
+	scheduled := gitlab.add("Audit log", "Who did what")
+	scheduled.Milestone = "v1.5"
+	gitlab.comment(scheduled.Number, "maria", "Needed for SOC 2")
+
+	if _, err := syncer.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	pushed := gitlab.issues[2]
+	if pushed == nil || pushed.Milestone != "v2" || !reflect.DeepEqual(sortedCopy(pushed.Labels), []string{"P2", "backend"}) {
+		t.Fatalf("expected %s on GitLab in milestone v2, got %+v", planned.ID, pushed)
+	}
+	found, err := store.SearchIssues(ctx, "Audit log", types.IssueFilter{})
+	if err != nil || len(found) != 1 {
+		t.Fatalf("expected the GitLab issue imported, got %v (err %v)", found, err)
+	}
+	imported := found[0]
+	if labels, _ := store.GetLabels(ctx, imported.ID); !reflect.DeepEqual(labels, []string{"release:v1.5"}) {
+		t.Errorf("imported labels = %v, want [release:v1.5]", labels)
+	}
+	if comments, _ := store.GetComments(ctx, imported.ID); len(comments) != 1 || comments[0].Author != "gitlab:maria" {
+		t.Errorf("imported comments = %+v", comments)
+	}
+
+	// Moving the issue in VC moves it on GitLab, and the other way round
+	if err := store.RemoveLabel(ctx, planned.ID, "release:v2", "alice"); err != nil {
+		t.Fatalf("RemoveLabel() error = %v", err)
+	}
+	if err := store.AddLabel(ctx, planned.ID, "release:v3", "alice"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	scheduled.Milestone = ""
+	scheduled.UpdatedAt = time.Now()
+	stats, err := syncer.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.VC.IssuesUpdated != 1 || stats.Remote.IssuesUpdated != 1 || len(stats.Conflicts) != 0 {
+		t.Errorf("milestone sync stats = %+v", stats)
+	}
+	if pushed.Milestone != "v3" {
+		t.Errorf("GitLab milestone = %q, want v3", pushed.Milestone)
+	}
+	if labels, _ := store.GetLabels(ctx, imported.ID); len(labels) != 0 {
+		t.Errorf("labels after the milestone was removed = %v", labels)
+	}
+}
+
+func TestParseBody(t *testing.T) {
+	tests := []struct {
+		body                    string
+		description, acceptance string
+		hasAcceptance           bool
+	}{
+		{"Just a description", "Just a description", "", false},
+		{"Desc\n\n## Acceptance Criteria\n\n- works\n\n<!-- vc-issue: vc-1 -->", "Desc", "- works", true},
+		{"## Acceptance Criteria\r\nIt works\r\n", "", "It works", true},
+		{"<!-- vc-issue: vc-1 -->", "", "", false},
+	}
+	for _, tt := range tests {
+		description, acceptance, hasAcceptance := parseBody(tt.body)
+		if description != tt.description || acceptance != tt.acceptance || hasAcceptance != tt.hasAcceptance {
+			t.Errorf("parseBody(%q) = %q, %q, %v; want %q, %q, %v", tt.body,
+				description, acceptance, hasAcceptance, tt.description, tt.acceptance, tt.hasAcceptance)
+		}
+	}
+	fields := Fields{Description: "Desc", AcceptanceCriteria: "- works"}
+	if description, acceptance, _ := parseBody(formatBody("vc-1", fields)); description != "Desc" || acceptance != "- works" {
+		t.Errorf("formatBody doesn't round-trip: %q, %q", description, acceptance)
+	}
+}
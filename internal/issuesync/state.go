@@ -0,0 +1,76 @@
+package issuesync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// State is what the sync remembers between runs
+type State struct {
+	Repo string `json:"repo"`
+	// SyncedAt is when the last sync started; remote changes since then
+	// are fetched
+	SyncedAt time.Time `json:"synced_at"`
+	Links    []*Link   `json:"links"`
+}
+
+// Link ties a VC issue to a remote issue
+type Link struct {
+	IssueID string `json:"issue_id"`
+	Number  int    `json:"number"`
+	URL     string `json:"url"`
+	// Base is the issue as both sides left it after the last sync
+	Base Fields `json:"base"`
+	// Comments on both sides, by their ID on each side, so none is copied
+	// twice
+	VCComments []int64 `json:"vc_comments,omitempty"`
+	// Named for GitHub, the first provider synced, so older states load
+	RemoteComments []int64 `json:"github_comments,omitempty"`
+}
+
+// StatePath returns where the state of syncing the database in dbDir with
+// repo (its path, e.g. owner/name) of provider is kept
+func StatePath(dbDir, provider, repo string) string {
+	return filepath.Join(dbDir, "sync", provider+"-"+strings.ReplaceAll(repo, "/", "-")+".json")
+}
+
+// LoadState reads the state a previous sync saved at path, or returns an
+// empty state if there is none
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issue sync state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to read issue sync state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// SaveState writes state to path for the next sync, replacing the previous
+// state only once the new one is complete
+func SaveState(path string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode issue sync state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create sync state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write issue sync state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save issue sync state: %w", err)
+	}
+	return nil
+}
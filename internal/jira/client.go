@@ -0,0 +1,315 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+// pageSize is how many issues are requested per search page
+const pageSize = 100
+
+// timeLayout is how Jira formats timestamps
+const timeLayout = "2006-01-02T15:04:05.000-0700"
+
+// APIError is an error response from the Jira REST API
+type APIError struct {
+	StatusCode int
+	Messages   []string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("Jira API returned %d", e.StatusCode)
+	if len(e.Messages) > 0 {
+		msg += ": " + strings.Join(e.Messages, "; ")
+	}
+	return msg
+}
+
+// Issue is a Jira issue with the fields a search requested
+type Issue struct {
+	Key      string
+	Type     string // Issue type name, e.g. Epic, Story, Bug
+	Status   string // Workflow status name
+	Done     bool   // The status is in the Done category
+	Priority string // Priority name, e.g. High
+	Parent   string // Key of the parent issue: the epic, or the story of a sub-task
+	Updated  time.Time
+	// Fields holds every requested field by ID, for Field
+	Fields map[string]json.RawMessage
+}
+
+// Field returns a field as text: strings as they are, numbers formatted,
+// options and users by their value or name, and lists joined with commas.
+// Returns "" for fields that are empty or weren't requested.
+func (i *Issue) Field(id string) string {
+	return fieldText(i.Fields[id])
+}
+
+// Transition is a workflow transition available on an issue
+type Transition struct {
+	ID   string
+	Name string
+	To   string // Name of the status the transition leads to
+}
+
+// Client talks to the Jira REST API (version 2, whose text fields are
+// plain wiki markup on both Jira Cloud and Jira Server)
+type Client struct {
+	baseURL string
+	cloud   bool   // Jira Cloud, authenticated with an email and API token
+	auth    string // Authorization header
+	http    *http.Client
+}
+
+// NewClient creates a client for the Jira site of cfg. With an email it
+// authenticates to Jira Cloud with an API token; without one, to Jira
+// Server or Data Center with a personal access token.
+func NewClient(cfg config.JiraConfig) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(cfg.URL, "/"),
+		cloud:   cfg.Email != "",
+		auth:    "Bearer " + cfg.Token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+	if c.cloud {
+		c.auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.Email+":"+cfg.Token))
+	}
+	return c
+}
+
+// BrowseURL returns the web page of an issue
+func (c *Client) BrowseURL(key string) string {
+	return c.baseURL + "/browse/" + key
+}
+
+// SearchIssues returns every issue matching jql with the given fields
+// (the key, type, status, priority, parent and update time are always
+// read when requested)
+func (c *Client) SearchIssues(ctx context.Context, jql string, fields []string) ([]*Issue, error) {
+	query := url.Values{}
+	query.Set("jql", jql)
+	query.Set("fields", strings.Join(fields, ","))
+	query.Set("maxResults", strconv.Itoa(pageSize))
+
+	var issues []*Issue
+	for {
+		var page struct {
+			Issues        []json.RawMessage `json:"issues"`
+			Total         int               `json:"total"`
+			NextPageToken string            `json:"nextPageToken"`
+		}
+		// Jira Cloud replaced the offset search with token pagination
+		path := "/rest/api/2/search"
+		if c.cloud {
+			path = "/rest/api/2/search/jql"
+		} else {
+			query.Set("startAt", strconv.Itoa(len(issues)))
+		}
+		if err := c.do(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &page); err != nil {
+			return nil, fmt.Errorf("failed to search Jira issues: %w", err)
+		}
+		for _, raw := range page.Issues {
+			issue, err := parseIssue(raw)
+			if err != nil {
+				return nil, err
+			}
+			issues = append(issues, issue)
+		}
+		if c.cloud {
+			if page.NextPageToken == "" {
+				return issues, nil
+			}
+			query.Set("nextPageToken", page.NextPageToken)
+		} else if len(page.Issues) == 0 || len(issues) >= page.Total {
+			return issues, nil
+		}
+	}
+}
+
+// AddComment comments on an issue
+func (c *Client) AddComment(ctx context.Context, key, body string) error {
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment", map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", key, err)
+	}
+	return nil
+}
+
+// Transitions returns the workflow transitions available on an issue
+func (c *Client) Transitions(ctx context.Context, key string) ([]Transition, error) {
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"/transitions", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get transitions of %s: %w", key, err)
+	}
+	transitions := make([]Transition, 0, len(resp.Transitions))
+	for _, t := range resp.Transitions {
+		transitions = append(transitions, Transition{ID: t.ID, Name: t.Name, To: t.To.Name})
+	}
+	return transitions, nil
+}
+
+// Transition moves an issue through a workflow transition
+func (c *Client) Transition(ctx context.Context, key, transitionID string) error {
+	body := map[string]interface{}{"transition": map[string]string{"id": transitionID}}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/transitions", body, nil); err != nil {
+		return fmt.Errorf("failed to transition %s: %w", key, err)
+	}
+	return nil
+}
+
+// do sends a request with body encoded as JSON and decodes the response into out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.auth)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return apiError(resp.StatusCode, data)
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// apiError builds an APIError from an error response, which Jira reports
+// as {"errorMessages": [...], "errors": {"field": "message"}}
+func apiError(status int, data []byte) *APIError {
+	apiErr := &APIError{StatusCode: status}
+	var payload struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if json.Unmarshal(data, &payload) == nil {
+		apiErr.Messages = append(apiErr.Messages, payload.ErrorMessages...)
+		var fields []string
+		for field, msg := range payload.Errors {
+			fields = append(fields, field+": "+msg)
+		}
+		sort.Strings(fields)
+		apiErr.Messages = append(apiErr.Messages, fields...)
+	}
+	if len(apiErr.Messages) == 0 {
+		apiErr.Messages = []string{http.StatusText(status)}
+	}
+	return apiErr
+}
+
+// parseIssue decodes an issue of a search response
+func parseIssue(raw json.RawMessage) (*Issue, error) {
+	var payload struct {
+		Key    string                     `json:"key"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode Jira issue: %w", err)
+	}
+	issue := &Issue{Key: payload.Key, Fields: payload.Fields}
+	issue.Type = fieldText(payload.Fields["issuetype"])
+	issue.Priority = fieldText(payload.Fields["priority"])
+
+	var status struct {
+		Name           string `json:"name"`
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	}
+	if data := payload.Fields["status"]; len(data) > 0 && json.Unmarshal(data, &status) == nil {
+		issue.Status = status.Name
+		issue.Done = status.StatusCategory.Key == "done"
+	}
+	var parent struct {
+		Key string `json:"key"`
+	}
+	if data := payload.Fields["parent"]; len(data) > 0 && json.Unmarshal(data, &parent) == nil {
+		issue.Parent = parent.Key
+	}
+	if updated := fieldText(payload.Fields["updated"]); updated != "" {
+		t, err := time.Parse(timeLayout, updated)
+		if err != nil {
+			return nil, fmt.Errorf("invalid update time of %s: %w", issue.Key, err)
+		}
+		issue.Updated = t
+	}
+	return issue, nil
+}
+
+// fieldText returns a field value as text (see Issue.Field)
+func fieldText(data json.RawMessage) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(data, &n) == nil {
+		return n.String()
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(data, &list) == nil {
+		var values []string
+		for _, item := range list {
+			if v := fieldText(item); v != "" {
+				values = append(values, v)
+			}
+		}
+		return strings.Join(values, ", ")
+	}
+	var object map[string]json.RawMessage
+	if json.Unmarshal(data, &object) == nil {
+		for _, key := range []string{"value", "name", "displayName", "key"} {
+			if v := fieldText(object[key]); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	return "" // null or a boolean
+}
@@ -0,0 +1,165 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestSearchIssuesCloud(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/search/jql" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if user, token, ok := r.BasicAuth(); !ok || user != "bot@acme.com" || token != "secret" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		query := r.URL.Query()
+		if query.Get("jql") != `project = "ENG"` || query.Get("fields") != "summary,status" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		tokens = append(tokens, query.Get("nextPageToken"))
+		if query.Get("nextPageToken") == "" {
+			_, _ = w.Write([]byte(`{"issues": [{"key": "ENG-1", "fields": {"summary": "Checkout v2",
+				"issuetype": {"name": "Epic"}, "priority": {"name": "High"},
+				"status": {"name": "To Do", "statusCategory": {"key": "new"}}, "updated": "2026-01-03T10:00:00.000+0100"}}],
+				"nextPageToken": "page2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"issues": [{"key": "ENG-2", "fields": {"summary": "Card form", "parent": {"key": "ENG-1"},
+			"status": {"name": "Done", "statusCategory": {"key": "done"}}}}], "isLast": true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(config.JiraConfig{URL: server.URL, Email: "bot@acme.com", Token: "secret"})
+	issues, err := client.SearchIssues(context.Background(), `project = "ENG"`, []string{"summary", "status"})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if !reflect.DeepEqual(tokens, []string{"", "page2"}) {
+		t.Errorf("requested pages %q, want first and page2", tokens)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	epic := issues[0]
+	if epic.Key != "ENG-1" || epic.Type != "Epic" || epic.Priority != "High" || epic.Status != "To Do" || epic.Done ||
+		!epic.Updated.Equal(time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC)) || epic.Field("summary") != "Checkout v2" {
+		t.Errorf("unexpected epic %+v", epic)
+	}
+	if story := issues[1]; story.Parent != "ENG-1" || !story.Done {
+		t.Errorf("unexpected story %+v", story)
+	}
+}
+
+func TestSearchIssuesServer(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/search" || r.Header.Get("Authorization") != "Bearer pat" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		startAt := r.URL.Query().Get("startAt")
+		offsets = append(offsets, startAt)
+		var items []string
+		count := pageSize
+		if startAt != "0" {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			items = append(items, fmt.Sprintf(`{"key": "ENG-%s-%d", "fields": {}}`, startAt, i))
+		}
+		_, _ = fmt.Fprintf(w, `{"startAt": %s, "total": %d, "issues": [%s]}`, startAt, pageSize+1, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	client := NewClient(config.JiraConfig{URL: server.URL + "/", Token: "pat"})
+	issues, err := client.SearchIssues(context.Background(), "project = ENG", nil)
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != pageSize+1 || !reflect.DeepEqual(offsets, []string{"0", fmt.Sprint(pageSize)}) {
+		t.Errorf("got %d issues from offsets %v", len(issues), offsets)
+	}
+}
+
+func TestTransitionsAndComments(t *testing.T) {
+	var transitioned, commented map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/ENG-2/transitions":
+			_, _ = w.Write([]byte(`{"transitions": [{"id": "21", "name": "Start work", "to": {"name": "In Progress"}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/ENG-2/transitions":
+			if err := json.NewDecoder(r.Body).Decode(&transitioned); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/ENG-2/comment":
+			if err := json.NewDecoder(r.Body).Decode(&commented); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "10001"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages": ["Issue does not exist or you do not have permission to see it."], "errors": {}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(config.JiraConfig{URL: server.URL, Token: "pat"})
+	ctx := context.Background()
+	transitions, err := client.Transitions(ctx, "ENG-2")
+	if err != nil {
+		t.Fatalf("Transitions failed: %v", err)
+	}
+	if want := []Transition{{ID: "21", Name: "Start work", To: "In Progress"}}; !reflect.DeepEqual(transitions, want) {
+		t.Errorf("transitions = %+v, want %+v", transitions, want)
+	}
+	if err := client.Transition(ctx, "ENG-2", "21"); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if want := map[string]interface{}{"transition": map[string]interface{}{"id": "21"}}; !reflect.DeepEqual(transitioned, want) {
+		t.Errorf("transition request = %v, want %v", transitioned, want)
+	}
+	if err := client.AddComment(ctx, "ENG-2", "Done in VC"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if commented["body"] != "Done in VC" {
+		t.Errorf("comment request = %v", commented)
+	}
+
+	err = client.AddComment(ctx, "ENG-404", "Lost")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("AddComment error = %v, want a 404 APIError", err)
+	}
+}
+
+func TestFieldText(t *testing.T) {
+	tests := map[string]string{
+		`"plain"`:                        "plain",
+		`3.5`:                            "3.5",
+		`null`:                           "",
+		`{"value": "Large", "id": "10"}`: "Large",
+		`{"displayName": "Ada", "accountId": "1"}`:            "Ada",
+		`[{"name": "backend"}, {"name": "api"}, null]`:        "backend, api",
+		`{"self": "https://acme.atlassian.net/rest/api/2/x"}`: "",
+	}
+	for raw, want := range tests {
+		if got := fieldText(json.RawMessage(raw)); got != want {
+			t.Errorf("fieldText(%s) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+// Package jira connects VC to a Jira project, so teams that plan in Jira
+// can hand work to the executor and follow it without leaving Jira.
+//
+// A sync imports the project's unresolved issues (narrowed by the
+// configured JQL): epics become missions and other issues VC issues,
+// children of the mission of their epic (or of the issue of their parent,
+// for sub-tasks). The VC fields are read from the Jira fields of the field
+// map. Once imported, Jira owns those fields: a field changed in Jira since
+// the last import is copied to VC again, while fields only changed in VC
+// are left alone.
+//
+// Progress flows the other way. When a linked VC issue changes status, the
+// Jira issue is moved through the workflow transition to the status the
+// status map gives, or, when there is none, the change is reported as a
+// comment. Every finished execution of the issue is summarized in a
+// comment.
+//
+// The sync state (links and the values last imported) is kept in a file
+// next to the database. Imported issues carry a jira:KEY label, so links
+// lost with the state are restored instead of duplicated.
+package jira
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Actor is who the sync records its changes to VC issues as
+const Actor = "jira-sync"
+
+// LabelPrefix starts the label linking an imported issue to its Jira
+// issue, followed by the Jira key
+const LabelPrefix = "jira:"
+
+// clockSkew is how far before the last sync Jira changes are fetched from,
+// in case Jira's clock is behind ours
+const clockSkew = time.Minute
+
+// typeEpic is the Jira issue type imported as a mission
+const typeEpic = "epic"
+
+// priorities maps Jira's default priority names, and those of older Jira
+// versions, to VC priorities. Other names import as P2.
+var priorities = map[string]int{
+	"highest": 0, "blocker": 0,
+	"high": 1, "critical": 1,
+	"medium": 2, "major": 2,
+	"low": 3, "minor": 3,
+	"lowest": 4, "trivial": 4,
+}
+
+// API is the Jira API the sync uses (see Client)
+type API interface {
+	BrowseURL(key string) string
+	SearchIssues(ctx context.Context, jql string, fields []string) ([]*Issue, error)
+	AddComment(ctx context.Context, key, body string) error
+	Transitions(ctx context.Context, key string) ([]Transition, error)
+	Transition(ctx context.Context, key, transitionID string) error
+}
+
+// Store is the storage the sync uses
+type Store interface {
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
+	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	CreateMission(ctx context.Context, mission *types.Mission, actor string) error
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+}
+
+// Stats reports what a sync did
+type Stats struct {
+	MissionsImported int // Epics imported as missions
+	IssuesImported   int
+	IssuesUpdated    int // Imported issues whose Jira fields changed
+	// Relinked counts Jira issues linked back to the VC issue imported
+	// from them by its label, after the sync state was lost
+	Relinked    int
+	Transitions int // Jira issues moved to a new status
+	Comments    int // Execution summaries and status changes without a transition
+}
+
+// Changed reports whether the sync changed anything on either side
+func (s Stats) Changed() bool {
+	return s != Stats{}
+}
+
+// Syncer syncs a VC database with a Jira project
+type Syncer struct {
+	store     Store
+	client    API
+	cfg       config.JiraConfig
+	statePath string
+	now       func() time.Time
+}
+
+// NewSyncer creates a syncer that keeps its state in statePath (see
+// StatePath)
+func NewSyncer(store Store, client API, cfg config.JiraConfig, statePath string) *Syncer {
+	return &Syncer{store: store, client: client, cfg: cfg, statePath: statePath, now: time.Now}
+}
+
+// Run syncs every configured interval until ctx is done
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats, err := s.Sync(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("jira: Jira sync failed", "project", s.cfg.Project, "error", err)
+			}
+			if stats != nil && stats.Changed() {
+				fmt.Printf("Jira sync: %d mission(s) and %d issue(s) imported, %d updated; %d transition(s) and %d comment(s) posted to %s\n",
+					stats.MissionsImported, stats.IssuesImported, stats.IssuesUpdated, stats.Transitions, stats.Comments, s.cfg.Project)
+			}
+		}
+	}
+}
+
+// Sync runs one sync and saves its state. The state is saved even if the
+// sync fails part way, so issues it imported stay linked.
+func (s *Syncer) Sync(ctx context.Context) (*Stats, error) {
+	state, err := LoadState(s.statePath)
+	if err != nil {
+		return nil, err
+	}
+	if state.Project == "" {
+		state.Project = s.cfg.Project
+	}
+	stats := &Stats{}
+	syncErr := s.sync(ctx, state, stats)
+	if err := SaveState(s.statePath, state); err != nil {
+		return stats, err
+	}
+	return stats, syncErr
+}
+
+// sync runs one sync, updating state as it goes
+func (s *Syncer) sync(ctx context.Context, state *State, stats *Stats) error {
+	started := s.now()
+	jiraIssues, err := s.client.SearchIssues(ctx, s.query(state.SyncedAt, started), s.fields())
+	if err != nil {
+		return err
+	}
+	// Epics first, so their stories can be made children of their mission
+	sort.SliceStable(jiraIssues, func(i, j int) bool {
+		return isEpic(jiraIssues[i]) && !isEpic(jiraIssues[j])
+	})
+
+	byKey := make(map[string]*Link)
+	for _, link := range state.Links {
+		byKey[link.Key] = link
+	}
+	for _, ji := range jiraIssues {
+		if link := byKey[ji.Key]; link != nil {
+			if err := s.updateVC(ctx, link, ji, stats); err != nil {
+				return err
+			}
+			link.JiraStatus = ji.Status
+			continue
+		}
+
+		link, err := s.relink(ctx, ji)
+		if err != nil {
+			return err
+		}
+		if link != nil {
+			stats.Relinked++
+		} else if ji.Done {
+			continue
+		} else if link, err = s.importIssue(ctx, ji, byKey, stats); err != nil {
+			return err
+		}
+		state.Links = append(state.Links, link)
+		byKey[link.Key] = link
+	}
+
+	for _, link := range state.Links {
+		if err := s.report(ctx, link, stats); err != nil {
+			return err
+		}
+	}
+
+	state.SyncedAt = started
+	return nil
+}
+
+// query returns the JQL selecting the project's issues, those updated
+// since the last sync once there was one. The relative form ("-15m") is
+// used because absolute JQL times are in the Jira user's time zone.
+func (s *Syncer) query(syncedAt, now time.Time) string {
+	jql := fmt.Sprintf("project = %q", s.cfg.Project)
+	if s.cfg.JQL != "" {
+		jql += " AND (" + s.cfg.JQL + ")"
+	}
+	if !syncedAt.IsZero() {
+		minutes := int(math.Ceil((now.Sub(syncedAt) + clockSkew).Minutes()))
+		jql += fmt.Sprintf(` AND updated >= "-%dm"`, minutes)
+	}
+	return jql + " ORDER BY created ASC"
+}
+
+// fields returns the Jira fields a sync reads
+func (s *Syncer) fields() []string {
+	fields := []string{"issuetype", "status", "priority", "parent", "updated"}
+	if s.cfg.EpicLinkField != "" {
+		fields = append(fields, s.cfg.EpicLinkField)
+	}
+	for _, field := range sortedKeys(s.cfg.FieldMap) {
+		fields = append(fields, s.cfg.FieldMap[field])
+	}
+	return fields
+}
+
+// values returns the VC field values of a Jira issue, by VC field name
+// (the priority by its Jira name)
+func (s *Syncer) values(ji *Issue) map[string]string {
+	values := map[string]string{"priority": ji.Priority}
+	for field, jiraField := range s.cfg.FieldMap {
+		values[field] = strings.TrimSpace(ji.Field(jiraField))
+	}
+	if values["title"] == "" {
+		values["title"] = ji.Key
+	}
+	return values
+}
+
+// relink links a Jira issue back to the VC issue imported from it, by its
+// label. Returns nil if there is none.
+func (s *Syncer) relink(ctx context.Context, ji *Issue) (*Link, error) {
+	issues, err := s.store.GetIssuesByLabel(ctx, LabelPrefix+ji.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the issue imported from %s: %w", ji.Key, err)
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+	issue := issues[0]
+	// Without a base, Jira's values are taken as imported; executions
+	// already run aren't reported again
+	link := &Link{Key: ji.Key, IssueID: issue.ID, Base: s.values(ji), JiraStatus: ji.Status, Status: issue.Status}
+	if link.Execution, err = s.lastExecution(ctx, issue.ID); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// importIssue creates a mission for a Jira epic, or a VC issue for any
+// other Jira issue, as a child of the mission or issue of its parent
+func (s *Syncer) importIssue(ctx context.Context, ji *Issue, byKey map[string]*Link, stats *Stats) (*Link, error) {
+	values := s.values(ji)
+	url := s.client.BrowseURL(ji.Key)
+	issue := types.Issue{
+		Title:              values["title"],
+		Description:        values["description"],
+		AcceptanceCriteria: values["acceptance_criteria"],
+		Design:             values["design"],
+		Notes:              values["notes"],
+		Status:             types.StatusOpen,
+		Priority:           priority(ji.Priority),
+		IssueType:          issueType(ji.Type),
+	}
+
+	if issue.IssueType == types.TypeEpic {
+		issue.IssueSubtype = types.SubtypeMission
+		mission := &types.Mission{Issue: issue, Goal: issue.Description, Context: "Imported from Jira " + url}
+		if mission.Goal == "" {
+			mission.Goal = issue.Title
+		}
+		if err := s.store.CreateMission(ctx, mission, Actor); err != nil {
+			return nil, fmt.Errorf("failed to import Jira epic %s: %w", ji.Key, err)
+		}
+		issue.ID = mission.ID
+		stats.MissionsImported++
+	} else {
+		if issue.AcceptanceCriteria == "" {
+			issue.AcceptanceCriteria = "Resolves " + url
+		}
+		if err := s.store.CreateIssue(ctx, &issue, Actor); err != nil {
+			return nil, fmt.Errorf("failed to import Jira issue %s: %w", ji.Key, err)
+		}
+		stats.IssuesImported++
+	}
+	if err := s.store.AddLabel(ctx, issue.ID, LabelPrefix+ji.Key, Actor); err != nil {
+		return nil, fmt.Errorf("failed to label %s: %w", issue.ID, err)
+	}
+
+	parentKey := ji.Parent
+	if s.cfg.EpicLinkField != "" && ji.Field(s.cfg.EpicLinkField) != "" {
+		parentKey = ji.Field(s.cfg.EpicLinkField)
+	}
+	if parent := byKey[parentKey]; parentKey != "" && parent != nil {
+		dep := &types.Dependency{IssueID: issue.ID, DependsOnID: parent.IssueID, Type: types.DepParentChild}
+		if err := s.store.AddDependency(ctx, dep, Actor); err != nil {
+			return nil, fmt.Errorf("failed to add %s to %s: %w", issue.ID, parent.IssueID, err)
+		}
+	}
+	return &Link{Key: ji.Key, IssueID: issue.ID, Base: values, JiraStatus: ji.Status, Status: issue.Status}, nil
+}
+
+// updateVC copies the fields changed in Jira since the last import to
+// the linked VC issue
+func (s *Syncer) updateVC(ctx context.Context, link *Link, ji *Issue, stats *Stats) error {
+	values := s.values(ji)
+	updates := make(map[string]interface{})
+	for field, value := range values {
+		if value == link.Base[field] {
+			continue
+		}
+		if field == "priority" {
+			updates[field] = priority(value)
+		} else {
+			updates[field] = value
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	issue, err := s.store.GetIssue(ctx, link.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", link.IssueID, err)
+	}
+	if issue == nil {
+		return nil // Deleted issues aren't reimported
+	}
+	if err := s.store.UpdateIssue(ctx, link.IssueID, updates, Actor); err != nil {
+		return fmt.Errorf("failed to update %s from %s: %w", link.IssueID, ji.Key, err)
+	}
+	link.Base = values
+	stats.IssuesUpdated++
+	return nil
+}
+
+// report posts a linked issue's status change and finished executions
+// to Jira
+func (s *Syncer) report(ctx context.Context, link *Link, stats *Stats) error {
+	issue, err := s.store.GetIssue(ctx, link.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", link.IssueID, err)
+	}
+	if issue == nil {
+		return nil
+	}
+	if issue.Status != link.Status {
+		if err := s.reportStatus(ctx, link, issue, stats); err != nil {
+			return err
+		}
+		link.Status = issue.Status
+	}
+
+	executions, err := s.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID})
+	if err != nil {
+		return fmt.Errorf("failed to list executions of %s: %w", issue.ID, err)
+	}
+	sort.Slice(executions, func(i, j int) bool { return executions[i].ID < executions[j].ID })
+	for _, e := range executions {
+		if e.ID <= link.Execution {
+			continue
+		}
+		if e.Status == types.ExecutionRunning {
+			break // Reported once it finishes, in order
+		}
+		if err := s.client.AddComment(ctx, link.Key, executionSummary(issue.ID, e)); err != nil {
+			return err
+		}
+		link.Execution = e.ID
+		stats.Comments++
+	}
+	return nil
+}
+
+// reportStatus moves a Jira issue to the status its VC issue's maps to, or
+// comments on the change if there is no such status or transition
+func (s *Syncer) reportStatus(ctx context.Context, link *Link, issue *types.Issue, stats *Stats) error {
+	target, mapped := s.cfg.StatusMap[string(issue.Status)]
+	if mapped && strings.EqualFold(target, link.JiraStatus) {
+		return nil
+	}
+	body := fmt.Sprintf("VC moved %s to %s.", issue.ID, issue.Status)
+	if mapped {
+		transitions, err := s.client.Transitions(ctx, link.Key)
+		if err != nil {
+			return err
+		}
+		for _, t := range transitions {
+			if strings.EqualFold(t.To, target) || strings.EqualFold(t.Name, target) {
+				if err := s.client.Transition(ctx, link.Key, t.ID); err != nil {
+					return err
+				}
+				link.JiraStatus = t.To
+				stats.Transitions++
+				return nil
+			}
+		}
+		body += fmt.Sprintf(" No transition to %q is available from %q.", target, link.JiraStatus)
+	}
+	if err := s.client.AddComment(ctx, link.Key, body); err != nil {
+		return err
+	}
+	stats.Comments++
+	return nil
+}
+
+// lastExecution returns the ID of an issue's latest finished execution
+func (s *Syncer) lastExecution(ctx context.Context, issueID string) (int64, error) {
+	executions, err := s.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issueID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list executions of %s: %w", issueID, err)
+	}
+	var last int64
+	for _, e := range executions {
+		if e.Status != types.ExecutionRunning && e.ID > last {
+			last = e.ID
+		}
+	}
+	return last, nil
+}
+
+// executionSummary describes a finished execution in Jira wiki markup
+func executionSummary(issueID string, e *types.Execution) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "VC execution #%d of %s *%s*", e.ID, issueID, e.Status)
+	if e.AgentDuration > 0 {
+		fmt.Fprintf(&b, " after %s", e.AgentDuration.Round(time.Second))
+	}
+	if e.CostUSD > 0 {
+		fmt.Fprintf(&b, " ($%.2f)", e.CostUSD)
+	}
+	b.WriteString(".")
+	if e.CommitHash != "" {
+		fmt.Fprintf(&b, "\nCommit: {{%s}}", e.CommitHash)
+	}
+	if e.Error != "" {
+		fmt.Fprintf(&b, "\nError: {noformat}%s{noformat}", e.Error)
+	}
+	return b.String()
+}
+
+// issueType returns the VC type a Jira issue type is imported as
+func issueType(name string) types.IssueType {
+	switch strings.ToLower(name) {
+	case typeEpic:
+		return types.TypeEpic
+	case "bug":
+		return types.TypeBug
+	case "story", "new feature", "improvement":
+		return types.TypeFeature
+	default:
+		return types.TypeTask
+	}
+}
+
+// priority returns the VC priority of a Jira priority name
+func priority(name string) int {
+	if p, ok := priorities[strings.ToLower(name)]; ok {
+		return p
+	}
+	return 2
+}
+
+func isEpic(ji *Issue) bool {
+	return strings.EqualFold(ji.Type, typeEpic)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,248 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// fakeJira is an in-memory Jira project with a To Do -> In Progress -> Done
+// workflow
+type fakeJira struct {
+	issues   []*Issue
+	comments map[string][]string
+	queries  []string
+}
+
+// workflow lists the transitions available from each status
+var workflow = map[string][]Transition{
+	"To Do":       {{ID: "11", Name: "Start work", To: "In Progress"}},
+	"In Progress": {{ID: "21", Name: "Resolve", To: "Done"}, {ID: "31", Name: "Stop work", To: "To Do"}},
+}
+
+func newFakeJira() *fakeJira {
+	return &fakeJira{comments: make(map[string][]string)}
+}
+
+// add creates an issue as a Jira user would
+func (f *fakeJira) add(key, issueType, parent string, fields map[string]interface{}) *Issue {
+	raw := make(map[string]json.RawMessage)
+	for id, value := range fields {
+		raw[id], _ = json.Marshal(value)
+	}
+	issue := &Issue{Key: key, Type: issueType, Status: "To Do", Priority: "Medium", Parent: parent, Updated: time.Now(), Fields: raw}
+	f.issues = append(f.issues, issue)
+	return issue
+}
+
+func (f *fakeJira) get(key string) *Issue {
+	for _, issue := range f.issues {
+		if issue.Key == key {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (f *fakeJira) BrowseURL(key string) string { return "https://jira.test/browse/" + key }
+
+// SearchIssues returns every issue; the syncer must cope with issues that
+// didn't change
+func (f *fakeJira) SearchIssues(ctx context.Context, jql string, fields []string) ([]*Issue, error) {
+	f.queries = append(f.queries, jql)
+	issues := make([]*Issue, 0, len(f.issues))
+	for _, issue := range f.issues {
+		copied := *issue
+		issues = append(issues, &copied)
+	}
+	return issues, nil
+}
+
+func (f *fakeJira) AddComment(ctx context.Context, key, body string) error {
+	f.comments[key] = append(f.comments[key], body)
+	return nil
+}
+
+func (f *fakeJira) Transitions(ctx context.Context, key string) ([]Transition, error) {
+	return workflow[f.get(key).Status], nil
+}
+
+func (f *fakeJira) Transition(ctx context.Context, key, transitionID string) error {
+	issue := f.get(key)
+	for _, t := range workflow[issue.Status] {
+		if t.ID == transitionID {
+			issue.Status = t.To
+			issue.Done = t.To == "Done"
+			return nil
+		}
+	}
+	return &APIError{StatusCode: 400, Messages: []string{"transition not available"}}
+}
+
+// imported returns the VC issue imported from a Jira issue
+func imported(t *testing.T, store *memory.Store, key string) *types.Issue {
+	t.Helper()
+	issues, err := store.GetIssuesByLabel(context.Background(), LabelPrefix+key)
+	if err != nil || len(issues) != 1 {
+		t.Fatalf("expected one issue imported from %s, got %v (err %v)", key, issues, err)
+	}
+	return issues[0]
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	jira := newFakeJira()
+	cfg := config.DefaultJiraConfig()
+	cfg.Project = "ENG"
+	cfg.FieldMap["acceptance_criteria"] = "customfield_10035"
+	statePath := filepath.Join(t.TempDir(), "sync", "jira-ENG.json")
+	syncer := NewSyncer(store, jira, cfg, statePath)
+	start := time.Now()
+	syncer.now = func() time.Time { return start }
+
+	// The story comes before its epic, which must still be imported first
+	jira.add("ENG-2", "Story", "ENG-1", map[string]interface{}{"summary": "Card form", "description": "Collect card details",
+		"customfield_10035": "Cards are validated"})
+	jira.add("ENG-1", "Epic", "", map[string]interface{}{"summary": "Checkout v2", "description": "Rebuild checkout"}).Priority = "High"
+	done := jira.add("ENG-3", "Bug", "", map[string]interface{}{"summary": "Fixed long ago"})
+	done.Status, done.Done = "Done", true
+	jira.add("ENG-4", "Sub-task", "ENG-2", map[string]interface{}{"summary": "Card form tests"})
+
+	// First sync: unresolved issues are imported
+	stats, err := syncer.Sync(ctx)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.MissionsImported != 1 || stats.IssuesImported != 2 || stats.Transitions != 0 || stats.Comments != 0 {
+		t.Errorf("first sync stats = %+v", stats)
+	}
+	if q := jira.queries[0]; q != `project = "ENG" ORDER BY created ASC` {
+		t.Errorf("first query = %q", q)
+	}
+
+	epic := imported(t, store, "ENG-1")
+	mission, err := store.GetMission(ctx, epic.ID)
+	if err != nil {
+		t.Fatalf("GetMission() error = %v", err)
+	}
+	if mission.IssueSubtype != types.SubtypeMission || mission.Goal != "Rebuild checkout" || mission.Priority != 1 ||
+		mission.Context != "Imported from Jira https://jira.test/browse/ENG-1" {
+		t.Errorf("imported mission = %+v", mission)
+	}
+	story := imported(t, store, "ENG-2")
+	if story.IssueType != types.TypeFeature || story.Title != "Card form" || story.AcceptanceCriteria != "Cards are validated" || story.Priority != 2 {
+		t.Errorf("imported story = %+v", story)
+	}
+	subtask := imported(t, store, "ENG-4")
+	if subtask.IssueType != types.TypeTask || subtask.AcceptanceCriteria != "Resolves https://jira.test/browse/ENG-4" {
+		t.Errorf("imported sub-task = %+v", subtask)
+	}
+	if issues, _ := store.GetIssuesByLabel(ctx, LabelPrefix+"ENG-3"); len(issues) != 0 {
+		t.Errorf("resolved issue ENG-3 was imported: %v", issues)
+	}
+	for child, parent := range map[string]string{story.ID: epic.ID, subtask.ID: story.ID} {
+		deps, err := store.GetDependencyRecords(ctx, child)
+		if err != nil || len(deps) != 1 || deps[0].DependsOnID != parent || deps[0].Type != types.DepParentChild {
+			t.Errorf("dependencies of %s = %+v (err %v), want a child of %s", child, deps, err, parent)
+		}
+	}
+
+	// Nothing changed, so nothing is imported or posted again
+	syncer.now = func() time.Time { return start.Add(10 * time.Minute) }
+	if stats, err = syncer.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.Changed() {
+		t.Errorf("idle sync stats = %+v", stats)
+	}
+	if q := jira.queries[1]; !strings.HasSuffix(q, ` AND updated >= "-11m" ORDER BY created ASC`) {
+		t.Errorf("second query = %q", q)
+	}
+
+	// Jira renames the story while VC edits its description and works on it
+	jira.get("ENG-2").Fields["summary"] = json.RawMessage(`"Card form v2"`)
+	if err := store.UpdateIssue(ctx, story.ID, map[string]interface{}{"description": "Collect and tokenize card details",
+		"status": string(types.StatusInProgress)}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	finished := &types.Execution{IssueID: story.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded,
+		AgentDuration: 3 * time.Minute, CostUSD: 0.5, CommitHash: "abc1234"}
+	running := &types.Execution{IssueID: story.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning}
+	for _, e := range []*types.Execution{finished, running} {
+		if err := store.CreateExecution(ctx, e); err != nil {
+			t.Fatalf("CreateExecution() error = %v", err)
+		}
+	}
+	if err := store.UpdateIssue(ctx, subtask.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if err := store.CloseIssue(ctx, epic.ID, "done", "alice"); err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+
+	if stats, err = syncer.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.IssuesUpdated != 1 || stats.Transitions != 1 || stats.Comments != 3 {
+		t.Errorf("third sync stats = %+v", stats)
+	}
+	story, _ = store.GetIssue(ctx, story.ID)
+	if story.Title != "Card form v2" || story.Description != "Collect and tokenize card details" {
+		t.Errorf("story after sync = %+v, want Jira's title and VC's description", story)
+	}
+	if status := jira.get("ENG-2").Status; status != "In Progress" {
+		t.Errorf("ENG-2 status = %q, want In Progress", status)
+	}
+	want := "VC execution #1 of " + story.ID + " *succeeded* after 3m0s ($0.50).\nCommit: {{abc1234}}"
+	if comments := jira.comments["ENG-2"]; len(comments) != 1 || comments[0] != want {
+		t.Errorf("ENG-2 comments = %q, want [%q]", comments, want)
+	}
+	// Blocked isn't mapped, and To Do has no transition to Done
+	if comments := jira.comments["ENG-4"]; len(comments) != 1 || comments[0] != "VC moved "+subtask.ID+" to blocked." {
+		t.Errorf("ENG-4 comments = %q", comments)
+	}
+	if comments := jira.comments["ENG-1"]; len(comments) != 1 || !strings.Contains(comments[0], `No transition to "Done" is available from "To Do"`) {
+		t.Errorf("ENG-1 comments = %q", comments)
+	}
+
+	// The running execution finishes
+	running.Status = types.ExecutionFailed
+	running.Error = "tests failed"
+	if err := store.UpdateExecution(ctx, running); err != nil {
+		t.Fatalf("UpdateExecution() error = %v", err)
+	}
+	if stats, err = syncer.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.Comments != 1 || len(jira.comments["ENG-2"]) != 2 || !strings.Contains(jira.comments["ENG-2"][1], "*failed*") {
+		t.Errorf("fourth sync stats = %+v, ENG-2 comments = %q", stats, jira.comments["ENG-2"])
+	}
+
+	// Losing the state relinks the imported issues instead of duplicating
+	// them or reposting executions
+	if err := os.Remove(statePath); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if stats, err = syncer.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.Relinked != 3 || stats.MissionsImported != 0 || stats.IssuesImported != 0 || stats.Comments != 0 {
+		t.Errorf("sync after state loss stats = %+v", stats)
+	}
+}
+
+func TestExecutionSummary(t *testing.T) {
+	e := &types.Execution{ID: 7, Status: types.ExecutionFailed, Error: "agent crashed"}
+	if got, want := executionSummary("vc-42", e), "VC execution #7 of vc-42 *failed*.\nError: {noformat}agent crashed{noformat}"; got != want {
+		t.Errorf("executionSummary() = %q, want %q", got, want)
+	}
+}
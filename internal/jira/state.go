@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// State is what the sync remembers between runs
+type State struct {
+	Project string `json:"project"`
+	// SyncedAt is when the last sync started; Jira issues updated since
+	// then are fetched
+	SyncedAt time.Time `json:"synced_at"`
+	Links    []*Link   `json:"links"`
+}
+
+// Link ties a VC issue (or mission) to the Jira issue it was imported from
+type Link struct {
+	Key     string `json:"key"`
+	IssueID string `json:"issue_id"`
+	// Base holds the Jira values last imported, by VC field name (the
+	// priority by its Jira name), so only fields changed in Jira since
+	// overwrite VC edits
+	Base map[string]string `json:"base"`
+	// JiraStatus is the Jira status when last seen
+	JiraStatus string `json:"jira_status"`
+	// Status is the VC status last reported to Jira
+	Status types.Status `json:"status"`
+	// Execution is the ID of the last execution reported to Jira
+	Execution int64 `json:"execution,omitempty"`
+}
+
+// StatePath returns where the state of syncing the database in dbDir with
+// a Jira project is kept
+func StatePath(dbDir, project string) string {
+	return filepath.Join(dbDir, "sync", "jira-"+project+".json")
+}
+
+// LoadState reads the state a previous sync saved at path, or returns an
+// empty state if there is none
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Jira sync state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to read Jira sync state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// SaveState writes state to path for the next sync, replacing the previous
+// state only once the new one is complete
+func SaveState(path string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode Jira sync state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create sync state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write Jira sync state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save Jira sync state: %w", err)
+	}
+	return nil
+}
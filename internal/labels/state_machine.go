@@ -84,6 +84,13 @@ func TransitionState(ctx context.Context, store Storage, issueID, fromLabel, toL
 	}
 
 	// Log the transition to agent_events for monitoring
+	return LogTransition(ctx, store, issueID, fromLabel, toLabel, trigger, actor)
+}
+
+// LogTransition logs a state transition to agent_events for monitoring.
+// TransitionState calls it; callers that move the labels themselves (e.g.
+// inside a transaction with other changes) call it once that has committed.
+func LogTransition(ctx context.Context, store Storage, issueID, fromLabel, toLabel, trigger, actor string) error {
 	event := &events.AgentEvent{
 		Type:      events.EventTypeLabelStateTransition,
 		Timestamp: time.Now(),
@@ -0,0 +1,94 @@
+// Package logging sets up VC's structured logging on log/slog.
+//
+// Code logs with the slog package functions. Fields that describe the work
+// in progress, like the issue and execution, are attached to a context with
+// With once, and every record logged with that context (slog.InfoContext and
+// friends) carries them, so callers deep in the AI supervisor need not know
+// which issue they are working for.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+// Field names used across VC's log records
+const (
+	KeyIssueID     = "issue_id"
+	KeyExecutionID = "execution_id"
+	KeyOperation   = "operation"
+	KeyProvider    = "provider"
+	KeyModel       = "model"
+)
+
+type attrsKey struct{}
+
+// With returns a copy of ctx carrying the given fields, as slog key/value
+// pairs, in addition to any it already carries
+func With(ctx context.Context, args ...any) context.Context {
+	if len(args) == 0 {
+		return ctx
+	}
+	var attrs []slog.Attr
+	if existing, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		attrs = append(attrs, existing...)
+	}
+	record := slog.Record{}
+	record.Add(args...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+// Attrs returns the fields attached to ctx by With
+func Attrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// NewHandler returns a handler writing records to w in the configured format
+// and level, adding the fields attached to each record's context
+func NewHandler(cfg config.LoggingConfig, w io.Writer) slog.Handler {
+	opts := &slog.HandlerOptions{Level: cfg.SlogLevel()}
+	var handler slog.Handler
+	if cfg.Format == config.LogFormatJSON {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	return contextHandler{handler}
+}
+
+// Setup makes a handler for cfg writing to w the default slog logger
+func Setup(cfg config.LoggingConfig, w io.Writer) {
+	slog.SetDefault(slog.New(NewHandler(cfg, w)))
+}
+
+// contextHandler adds the fields attached to a record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := Attrs(ctx); len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(config.LoggingConfig{Level: "info", Format: config.LogFormatJSON}, &buf))
+
+	ctx := With(context.Background(), KeyIssueID, "vc-1")
+	ctx = With(ctx, KeyExecutionID, int64(7))
+	logger.InfoContext(ctx, "AI call", KeyOperation, "assessment", KeyProvider, "anthropic")
+	logger.DebugContext(ctx, "not logged at info")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d records, want 1:\n%s", len(lines), buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("record is not JSON: %v\n%s", err, lines[0])
+	}
+	want := map[string]interface{}{
+		"msg":          "AI call",
+		"level":        "INFO",
+		KeyIssueID:     "vc-1",
+		KeyExecutionID: float64(7),
+		KeyOperation:   "assessment",
+		KeyProvider:    "anthropic",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("record[%q] = %v, want %v", key, record[key], value)
+		}
+	}
+
+	if attrs := Attrs(context.Background()); len(attrs) != 0 {
+		t.Errorf("Attrs() = %v on a bare context, want none", attrs)
+	}
+}
+
+func TestTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(config.LoggingConfig{Level: "debug", Format: config.LogFormatText}, &buf))
+	logger.DebugContext(With(context.Background(), KeyIssueID, "vc-2"), "parsed event", "tool", "read")
+
+	if out := buf.String(); !strings.Contains(out, "level=DEBUG") || !strings.Contains(out, "issue_id=vc-2") || !strings.Contains(out, "tool=read") {
+		t.Errorf("text record = %q", out)
+	}
+}
@@ -0,0 +1,454 @@
+// Package maintenance runs the periodic housekeeping a long-lived VC
+// deployment needs: a duplicate sweep of open issues, event pruning per the
+// retention policy, SQLite VACUUM and ANALYZE, removal of stale sandboxes,
+// worktrees and mission branches, and a check of in_progress issues that no
+// running executor holds.
+//
+// 'vc daemon' runs the Job on a cron schedule (nightly by default) and
+// 'vc cleanup all' runs it once. Anything that needs a human is collected
+// into a health report issue.
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/idempotency"
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/schedule"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Actor is who the job changes issues as
+const Actor = "maintenance"
+
+// ReportLabel marks the health report issues the job files
+const ReportLabel = "health-report"
+
+// heartbeatTimeout is how long an executor can go without a heartbeat
+// before its claims count as orphaned, matching the executor's default
+// stale threshold
+const heartbeatTimeout = 5 * time.Minute
+
+// branchRetentionDays is how old a mission branch without a worktree must be
+// before it is deleted, as on executor startup
+const branchRetentionDays = 7
+
+// Store is the storage the job maintains
+type Store interface {
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
+	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+
+	CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error)
+	CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error)
+	CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error)
+	CompactIssueEvents(ctx context.Context, retentionDays, batchSize int) (int, error)
+	CleanupExecutionOutput(ctx context.Context, retentionDays int) (int, error)
+	DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int, error)
+	VacuumDatabase(ctx context.Context) error
+	AnalyzeDatabase(ctx context.Context) error
+
+	GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error)
+	GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error)
+	ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error
+}
+
+// Config configures a Job
+type Config struct {
+	Maintenance config.MaintenanceConfig
+	Retention   config.EventRetentionConfig
+	Store       Store
+
+	// Deduplicator compares open issues for the duplicate sweep
+	// (optional: nil skips the sweep)
+	Deduplicator deduplication.Deduplicator
+
+	// Where sandboxes are created and the repository they are worktrees of
+	// (optional: empty skips sandbox and branch cleanup)
+	SandboxRoot string
+	ParentRepo  string
+}
+
+// Step is the outcome of one maintenance task
+type Step struct {
+	Name     string
+	Summary  string   // What was done
+	Problems []string // What needs a human
+	Err      error    // Why the task failed, if it did
+}
+
+// Report is the outcome of a maintenance run
+type Report struct {
+	StartedAt time.Time
+	Duration  time.Duration
+	Steps     []Step
+	IssueID   string // The health report issue filed or updated, "" if nothing needed one
+}
+
+// Problems returns how many problems the run found, counting failed steps
+func (r *Report) Problems() int {
+	n := 0
+	for _, step := range r.Steps {
+		n += len(step.Problems)
+		if step.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Job runs the maintenance tasks
+type Job struct {
+	cfg   Config
+	cron  *schedule.Cron
+	store Store
+	now   func() time.Time
+}
+
+// NewJob creates a maintenance job
+func NewJob(cfg Config) (*Job, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if err := cfg.Maintenance.Validate(); err != nil {
+		return nil, err
+	}
+	cron, err := schedule.ParseCron(cfg.Maintenance.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	return &Job{cfg: cfg, cron: cron, store: cfg.Store, now: time.Now}, nil
+}
+
+// Next returns when the job next runs after t
+func (j *Job) Next(t time.Time) time.Time {
+	return j.cron.Next(t)
+}
+
+// Run runs the job on its schedule until ctx is done
+func (j *Job) Run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(j.Next(j.now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		report := j.RunOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Printf("Maintenance: finished in %v, %d problem(s) found", report.Duration.Round(time.Second), report.Problems())
+		if report.IssueID != "" {
+			fmt.Printf(" (health report %s)", report.IssueID)
+		}
+		fmt.Println()
+	}
+}
+
+// RunOnce runs every maintenance task and files a health report if any
+// found problems. Tasks are independent: one failing doesn't stop the rest.
+func (j *Job) RunOnce(ctx context.Context) *Report {
+	report := &Report{StartedAt: j.now()}
+	for _, task := range []func(context.Context) Step{
+		j.sweepDuplicates,
+		j.pruneEvents,
+		j.verifyInProgress,
+		j.cleanSandboxes,
+		j.optimizeDatabase,
+	} {
+		if ctx.Err() != nil {
+			break
+		}
+		report.Steps = append(report.Steps, task(ctx))
+	}
+	report.Duration = j.now().Sub(report.StartedAt)
+
+	if report.Problems() > 0 && ctx.Err() == nil {
+		issueID, err := j.fileReport(ctx, report)
+		if err != nil {
+			slog.Warn("maintenance: failed to file health report", "error", err)
+		}
+		report.IssueID = issueID
+	}
+	return report
+}
+
+// sweepDuplicates checks the most recently updated open issues against the
+// other open issues. Suspected duplicates are reported, not closed: which of
+// the two to keep is a human's call.
+func (j *Job) sweepDuplicates(ctx context.Context) Step {
+	step := Step{Name: "Duplicate sweep"}
+	limit := j.cfg.Maintenance.DedupMaxIssues
+	if j.cfg.Deduplicator == nil || limit == 0 {
+		step.Summary = "skipped"
+		return step
+	}
+
+	status := types.StatusOpen
+	issues, err := j.store.SearchIssues(ctx, "", types.IssueFilter{Status: &status})
+	if err != nil {
+		step.Err = fmt.Errorf("failed to list open issues: %w", err)
+		return step
+	}
+	sort.Slice(issues, func(a, b int) bool { return issues[a].UpdatedAt.After(issues[b].UpdatedAt) })
+	if len(issues) > limit {
+		issues = issues[:limit]
+	}
+
+	reported := make(map[[2]string]bool)
+	checked := 0
+	for _, issue := range issues {
+		decision, err := j.cfg.Deduplicator.CheckDuplicate(ctx, issue)
+		if err != nil {
+			slog.Warn("maintenance: duplicate check failed", "issue", issue.ID, "error", err)
+			continue
+		}
+		checked++
+		if !decision.IsDuplicate {
+			continue
+		}
+		pair := [2]string{issue.ID, decision.DuplicateOf}
+		if pair[0] > pair[1] {
+			pair[0], pair[1] = pair[1], pair[0]
+		}
+		if reported[pair] {
+			continue
+		}
+		reported[pair] = true
+		step.Problems = append(step.Problems, fmt.Sprintf("%s looks like a duplicate of %s (confidence %.2f): %s",
+			issue.ID, decision.DuplicateOf, decision.Confidence, decision.Reasoning))
+	}
+	step.Summary = fmt.Sprintf("checked %d open issues, %d suspected duplicates", checked, len(step.Problems))
+	return step
+}
+
+// pruneEvents enforces the event retention policy, as the executor's event
+// cleanup does
+func (j *Job) pruneEvents(ctx context.Context) Step {
+	step := Step{Name: "Event pruning"}
+	cfg := j.cfg.Retention
+	if !cfg.CleanupEnabled {
+		step.Summary = "skipped (VC_EVENT_CLEANUP_ENABLED=false)"
+		return step
+	}
+
+	byAge, err := j.store.CleanupEventsByAge(ctx, cfg.RetentionDays, cfg.RetentionCriticalDays, cfg.CleanupBatchSize)
+	if err != nil {
+		step.Err = fmt.Errorf("time-based cleanup failed: %w", err)
+		return step
+	}
+	byIssue, err := j.store.CleanupEventsByIssueLimit(ctx, cfg.PerIssueLimitEvents, cfg.CleanupBatchSize)
+	if err != nil {
+		step.Err = fmt.Errorf("per-issue limit cleanup failed: %w", err)
+		return step
+	}
+	byGlobal, err := j.store.CleanupEventsByGlobalLimit(ctx, int(float64(cfg.GlobalLimitEvents)*0.95), cfg.CleanupBatchSize)
+	if err != nil {
+		step.Err = fmt.Errorf("global limit cleanup failed: %w", err)
+		return step
+	}
+	compacted := 0
+	if cfg.IssueEventRetentionDays > 0 {
+		if compacted, err = j.store.CompactIssueEvents(ctx, cfg.IssueEventRetentionDays, cfg.CleanupBatchSize); err != nil {
+			step.Err = fmt.Errorf("issue event compaction failed: %w", err)
+			return step
+		}
+	}
+	output, err := j.store.CleanupExecutionOutput(ctx, cfg.RetentionDays)
+	if err != nil {
+		step.Err = fmt.Errorf("execution output cleanup failed: %w", err)
+		return step
+	}
+	step.Summary = fmt.Sprintf("deleted %d events (%d over %d days old, %d over per-issue limit, %d over global limit), compacted %d issue events and deleted %d lines of agent output",
+		byAge+byIssue+byGlobal, byAge, cfg.RetentionDays, byIssue, byGlobal, compacted, output)
+	return step
+}
+
+// verifyInProgress looks for in_progress issues nobody is working on. A
+// claim held by an executor that stopped running is released and the issue
+// reopened; an issue in progress with no claim at all may be a human's, so
+// it is only reported.
+func (j *Job) verifyInProgress(ctx context.Context) Step {
+	step := Step{Name: "In-progress issues"}
+	status := types.StatusInProgress
+	issues, err := j.store.SearchIssues(ctx, "", types.IssueFilter{Status: &status})
+	if err != nil {
+		step.Err = fmt.Errorf("failed to list in_progress issues: %w", err)
+		return step
+	}
+	instances, err := j.store.GetActiveInstances(ctx)
+	if err != nil {
+		step.Err = fmt.Errorf("failed to list executor instances: %w", err)
+		return step
+	}
+	now := j.now()
+	alive := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		alive[instance.InstanceID] = now.Sub(instance.LastHeartbeat) <= heartbeatTimeout
+	}
+
+	var reopened []string
+	for _, issue := range issues {
+		state, err := j.store.GetExecutionState(ctx, issue.ID)
+		if err != nil {
+			step.Err = fmt.Errorf("failed to get execution state of %s: %w", issue.ID, err)
+			return step
+		}
+		switch {
+		case state == nil:
+			step.Problems = append(step.Problems, fmt.Sprintf("%s (%s) is in_progress but no executor has claimed it; close it or set it back to open if nobody is working on it",
+				issue.ID, issue.Title))
+		case alive[state.ExecutorInstanceID]:
+		case state.LeaseExpiresAt != nil && state.LeaseExpiresAt.After(now):
+		default:
+			comment := fmt.Sprintf("Reopened by maintenance: executor %s that claimed this issue is no longer running", state.ExecutorInstanceID)
+			if err := j.store.ReleaseIssueAndReopen(ctx, issue.ID, Actor, comment); err != nil {
+				step.Err = fmt.Errorf("failed to reopen %s: %w", issue.ID, err)
+				return step
+			}
+			reopened = append(reopened, issue.ID)
+		}
+	}
+	step.Summary = fmt.Sprintf("checked %d in_progress issues", len(issues))
+	if len(reopened) > 0 {
+		step.Summary += fmt.Sprintf(", reopened %s claimed by executors that stopped", strings.Join(reopened, ", "))
+	}
+	return step
+}
+
+// cleanSandboxes removes sandboxes unused for longer than the configured
+// age, prunes worktree records and deletes old mission branches with no
+// worktree. Sandboxes of issues still in progress are kept.
+func (j *Job) cleanSandboxes(ctx context.Context) Step {
+	step := Step{Name: "Sandboxes"}
+	if j.cfg.SandboxRoot == "" || j.cfg.ParentRepo == "" {
+		step.Summary = "skipped (sandboxes disabled)"
+		return step
+	}
+
+	status := types.StatusInProgress
+	issues, err := j.store.SearchIssues(ctx, "", types.IssueFilter{Status: &status})
+	if err != nil {
+		step.Err = fmt.Errorf("failed to list in_progress issues: %w", err)
+		return step
+	}
+	inProgress := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		inProgress[issue.ID] = true
+	}
+
+	maxAge := time.Duration(j.cfg.Maintenance.StaleSandboxDays) * 24 * time.Hour
+	removed, err := sandbox.RemoveStaleSandboxes(ctx, j.cfg.SandboxRoot, j.cfg.ParentRepo, maxAge,
+		func(missionID string) bool { return inProgress[missionID] })
+	if err != nil {
+		step.Err = err
+	}
+	if err := sandbox.PruneWorktrees(ctx, j.cfg.ParentRepo); err != nil && step.Err == nil {
+		step.Err = err
+	}
+	branches := 0
+	if gitOps, err := git.NewGit(ctx); err == nil {
+		if branches, err = gitOps.CleanupOrphanedBranches(ctx, j.cfg.ParentRepo, branchRetentionDays, false); err != nil && step.Err == nil {
+			step.Err = err
+		}
+	}
+	step.Summary = fmt.Sprintf("removed %d sandboxes unused for %d days and %d orphaned mission branches",
+		len(removed), j.cfg.Maintenance.StaleSandboxDays, branches)
+	return step
+}
+
+// optimizeDatabase deletes expired idempotency keys, refreshes query planner
+// statistics and, if configured, reclaims free space. It runs last, after the other tasks have deleted
+// what they will.
+func (j *Job) optimizeDatabase(ctx context.Context) Step {
+	step := Step{Name: "Database"}
+	expired, err := j.store.DeleteIdempotencyKeysBefore(ctx, j.now().Add(-idempotency.TTL))
+	if err != nil {
+		step.Err = fmt.Errorf("idempotency key cleanup failed: %w", err)
+		return step
+	}
+	if err := j.store.AnalyzeDatabase(ctx); err != nil {
+		step.Err = fmt.Errorf("ANALYZE failed: %w", err)
+		return step
+	}
+	ran := "ANALYZE ran"
+	if j.cfg.Maintenance.Vacuum {
+		if err := j.store.VacuumDatabase(ctx); err != nil {
+			step.Err = fmt.Errorf("VACUUM failed: %w", err)
+			return step
+		}
+		ran = "ANALYZE and VACUUM ran"
+	}
+	step.Summary = fmt.Sprintf("%s, deleted %d expired idempotency keys", ran, expired)
+	return step
+}
+
+// fileReport records the report on the open health report issue, or files
+// one if there is none, so problems that persist night after night don't
+// pile up issues
+func (j *Job) fileReport(ctx context.Context, report *Report) (string, error) {
+	body := report.Markdown()
+
+	existing, err := j.store.GetIssuesByLabel(ctx, ReportLabel)
+	if err != nil {
+		return "", fmt.Errorf("failed to list health reports: %w", err)
+	}
+	for _, issue := range existing {
+		if issue.Status != types.StatusClosed {
+			if err := j.store.AddComment(ctx, issue.ID, Actor, body); err != nil {
+				return "", fmt.Errorf("failed to update %s: %w", issue.ID, err)
+			}
+			return issue.ID, nil
+		}
+	}
+
+	issue := &types.Issue{
+		Title:              fmt.Sprintf("Maintenance found %d problem(s)", report.Problems()),
+		Description:        body,
+		IssueType:          types.TypeTask,
+		Priority:           2,
+		Status:             types.StatusOpen,
+		AcceptanceCriteria: "Each problem in the report is resolved or dismissed",
+	}
+	if err := j.store.CreateIssue(ctx, issue, Actor); err != nil {
+		return "", fmt.Errorf("failed to file health report: %w", err)
+	}
+	for _, label := range []string{ReportLabel, "no-auto-claim"} {
+		if err := j.store.AddLabel(ctx, issue.ID, label, Actor); err != nil {
+			return issue.ID, fmt.Errorf("failed to label %s: %w", issue.ID, err)
+		}
+	}
+	return issue.ID, nil
+}
+
+// Markdown renders the report
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Maintenance run of %s (%v): %d problem(s).\n", r.StartedAt.Format("2006-01-02 15:04 MST"), r.Duration.Round(time.Second), r.Problems())
+	for _, step := range r.Steps {
+		fmt.Fprintf(&b, "\n## %s\n%s\n", step.Name, step.Summary)
+		if step.Err != nil {
+			fmt.Fprintf(&b, "\n**Failed:** %v\n", step.Err)
+		}
+		if len(step.Problems) > 0 {
+			b.WriteString("\n")
+			for _, problem := range step.Problems {
+				fmt.Fprintf(&b, "- %s\n", problem)
+			}
+		}
+	}
+	return b.String()
+}
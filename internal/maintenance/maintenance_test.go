@@ -0,0 +1,116 @@
+package maintenance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// pairDeduplicator reports the issues in dups as duplicates of each other
+type pairDeduplicator struct {
+	dups map[string]string
+}
+
+func (d *pairDeduplicator) CheckDuplicate(ctx context.Context, candidate *types.Issue) (*deduplication.DuplicateDecision, error) {
+	if other, ok := d.dups[candidate.ID]; ok {
+		return &deduplication.DuplicateDecision{IsDuplicate: true, DuplicateOf: other, Confidence: 0.9, Reasoning: "same bug"}, nil
+	}
+	return &deduplication.DuplicateDecision{}, nil
+}
+
+func (d *pairDeduplicator) DeduplicateBatch(ctx context.Context, candidates []*types.Issue) (*deduplication.DeduplicationResult, error) {
+	return &deduplication.DeduplicationResult{UniqueIssues: candidates}, nil
+}
+
+func TestRunOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+
+	create := func(title string) *types.Issue {
+		issue := &types.Issue{Title: title, IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 2, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	crash := create("Crash on save")
+	crashAgain := create("Saving crashes")
+	unclaimed := create("Started by hand")
+	orphaned := create("Claimed by a dead executor")
+
+	if err := store.UpdateIssue(ctx, unclaimed.ID, map[string]interface{}{"status": types.StatusInProgress}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	instance := &types.ExecutorInstance{InstanceID: "gone", Hostname: "host", PID: 1, Status: types.ExecutorStatusStopped,
+		StartedAt: time.Now(), LastHeartbeat: time.Now(), Version: "test", Metadata: "{}"}
+	if err := store.RegisterInstance(ctx, instance); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, orphaned.ID, "gone"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+
+	job, err := NewJob(Config{
+		Maintenance:  config.DefaultMaintenanceConfig(),
+		Retention:    config.DefaultEventRetentionConfig(),
+		Store:        store,
+		Deduplicator: &pairDeduplicator{dups: map[string]string{crash.ID: crashAgain.ID, crashAgain.ID: crash.ID}},
+	})
+	if err != nil {
+		t.Fatalf("NewJob failed: %v", err)
+	}
+
+	report := job.RunOnce(ctx)
+	for _, step := range report.Steps {
+		if step.Err != nil {
+			t.Errorf("%s failed: %v", step.Name, step.Err)
+		}
+	}
+	// One duplicate pair and one unclaimed issue
+	if report.Problems() != 2 {
+		t.Fatalf("found %d problems, want 2:\n%s", report.Problems(), report.Markdown())
+	}
+	if report.IssueID == "" {
+		t.Fatal("no health report filed")
+	}
+	body := report.Markdown()
+	for _, want := range []string{"looks like a duplicate", crash.ID, crashAgain.ID, unclaimed.ID, "reopened " + orphaned.ID} {
+		if !strings.Contains(body, want) {
+			t.Errorf("report doesn't mention %q:\n%s", want, body)
+		}
+	}
+
+	reopened, err := store.GetIssue(ctx, orphaned.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if reopened.Status != types.StatusOpen {
+		t.Errorf("orphaned issue is %s, want open", reopened.Status)
+	}
+
+	// Problems that persist go on the open report instead of a new one
+	again := job.RunOnce(ctx)
+	if again.IssueID != report.IssueID {
+		t.Errorf("second run reported to %s, want %s", again.IssueID, report.IssueID)
+	}
+	reports, err := store.GetIssuesByLabel(ctx, ReportLabel)
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel failed: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Errorf("%d health reports filed, want 1", len(reports))
+	}
+	comments, err := store.GetComments(ctx, report.IssueID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Errorf("health report has %d comments, want 1", len(comments))
+	}
+}
@@ -120,22 +120,25 @@ func (o *Orchestrator) RejectPlan(ctx context.Context, missionID string, rejecte
 
 // CreatePhasesFromPlan creates phase epics from an approved plan
 // This is called after a plan has been approved (or auto-approved)
-// If any phase creation fails, all previously created phases are cleaned up (rollback)
+// Each phase becomes a child epic of the mission, with blocks dependencies
+// between phases. All epics and dependencies are written in one storage
+// transaction, so a failure part way through leaves no orphaned phases.
 func (o *Orchestrator) CreatePhasesFromPlan(ctx context.Context, missionID string, plan *types.MissionPlan, actor string) ([]string, error) {
-	var phaseIDs []string
-
 	// Get mission to inherit priority
 	mission, err := o.store.GetIssue(ctx, missionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mission: %w", err)
 	}
 
-	// Helper function to cleanup created phases on error
-	cleanup := func() {
-		for _, phaseID := range phaseIDs {
-			// Best effort cleanup - log errors but don't fail
-			if err := o.store.CloseIssue(ctx, phaseID, "Rollback: phase creation failed", "system"); err != nil {
-				fmt.Printf("Warning: failed to cleanup phase %s during rollback: %v\n", phaseID, err)
+	// Validate phase dependencies before writing anything
+	// plannedPhase.Dependencies contains epic numbers (1-indexed)
+	for _, plannedPhase := range plan.Phases {
+		for _, depPhaseNum := range plannedPhase.Dependencies {
+			if depPhaseNum < 1 || depPhaseNum > len(plan.Phases) {
+				return nil, fmt.Errorf("invalid phase dependency: phase %d depends on non-existent phase %d", plannedPhase.PhaseNumber, depPhaseNum)
+			}
+			if depPhaseNum == plannedPhase.PhaseNumber {
+				return nil, fmt.Errorf("invalid phase dependency: phase %d cannot depend on itself", plannedPhase.PhaseNumber)
 			}
 		}
 	}
@@ -145,65 +148,54 @@ func (o *Orchestrator) CreatePhasesFromPlan(ctx context.Context, missionID strin
 		return nil, fmt.Errorf("phase structure validation failed: %w", err)
 	}
 
-	// Create each planned epic as a child epic
-	for _, plannedPhase := range plan.Phases {
-		childEpic := &types.Issue{
-			Title:              plannedPhase.Title,
-			Description:        plannedPhase.Description,
-			IssueType:          types.TypeEpic,
-			Status:             types.StatusOpen,
-			Priority:           mission.Priority, // Inherit from parent mission
-			Design:             fmt.Sprintf("Strategy: %s\n\nTasks:\n%s", plannedPhase.Strategy, joinTasks(plannedPhase.Tasks)),
-			AcceptanceCriteria: fmt.Sprintf("Complete all tasks for this epic:\n%s", joinTasks(plannedPhase.Tasks)),
-		}
-
-		// Create the child epic issue
-		if err := o.store.CreateIssue(ctx, childEpic, actor); err != nil {
-			cleanup()
-			return nil, fmt.Errorf("failed to create child epic %d: %w", plannedPhase.PhaseNumber, err)
-		}
-
-		epicID := childEpic.ID
-		phaseIDs = append(phaseIDs, epicID)
-
-		// Add parent-child dependency: child epic depends on mission
-		dep := &types.Dependency{
-			IssueID:     epicID,
-			DependsOnID: missionID,
-			Type:        types.DepParentChild,
-		}
-		if err := o.store.AddDependency(ctx, dep, actor); err != nil {
-			cleanup()
-			return nil, fmt.Errorf("failed to add parent-child dependency for epic %s: %w", epicID, err)
-		}
-
-		// Add blocks dependencies to other child epics
-		// plannedPhase.Dependencies contains epic numbers (1-indexed)
-		// We need to convert to epic IDs
-		for _, depPhaseNum := range plannedPhase.Dependencies {
-			// Basic validation: epic numbers must be valid
-			if depPhaseNum < 1 || depPhaseNum > len(plan.Phases) {
-				cleanup()
-				return nil, fmt.Errorf("invalid phase dependency: phase %d depends on non-existent phase %d", plannedPhase.PhaseNumber, depPhaseNum)
+	var phaseIDs []string
+	err = o.store.RunInVCTransaction(ctx, func(tx *storage.VCTransaction) error {
+		// Create each planned epic as a child epic
+		for _, plannedPhase := range plan.Phases {
+			childEpic := &types.Issue{
+				Title:              plannedPhase.Title,
+				Description:        plannedPhase.Description,
+				IssueType:          types.TypeEpic,
+				Status:             types.StatusOpen,
+				Priority:           mission.Priority, // Inherit from parent mission
+				Design:             fmt.Sprintf("Strategy: %s\n\nTasks:\n%s", plannedPhase.Strategy, joinTasks(plannedPhase.Tasks)),
+				AcceptanceCriteria: fmt.Sprintf("Complete all tasks for this epic:\n%s", joinTasks(plannedPhase.Tasks)),
 			}
-			// Skip if referring to itself
-			if depPhaseNum == plannedPhase.PhaseNumber {
-				cleanup()
-				return nil, fmt.Errorf("invalid phase dependency: phase %d cannot depend on itself", plannedPhase.PhaseNumber)
+			if err := tx.CreateIssue(ctx, childEpic, actor); err != nil {
+				return fmt.Errorf("failed to create child epic %d: %w", plannedPhase.PhaseNumber, err)
 			}
-			depPhaseID := phaseIDs[depPhaseNum-1]
+			phaseIDs = append(phaseIDs, childEpic.ID)
 
-			// Create blocks dependency: current epic blocks on dependency epic
-			blocksDep := &types.Dependency{
-				IssueID:     epicID,
-				DependsOnID: depPhaseID,
-				Type:        types.DepBlocks,
+			// Add parent-child dependency: child epic depends on mission
+			dep := &types.Dependency{
+				IssueID:     childEpic.ID,
+				DependsOnID: missionID,
+				Type:        types.DepParentChild,
+			}
+			if err := tx.AddDependency(ctx, dep, actor); err != nil {
+				return fmt.Errorf("failed to add parent-child dependency for epic %s: %w", childEpic.ID, err)
 			}
-			if err := o.store.AddDependency(ctx, blocksDep, actor); err != nil {
-				cleanup()
-				return nil, fmt.Errorf("failed to add blocks dependency for epic %s: %w", epicID, err)
+		}
+
+		// Add blocks dependencies between child epics once all exist, so a
+		// phase may depend on one listed after it
+		for i, plannedPhase := range plan.Phases {
+			for _, depPhaseNum := range plannedPhase.Dependencies {
+				// Create blocks dependency: current epic blocks on dependency epic
+				blocksDep := &types.Dependency{
+					IssueID:     phaseIDs[i],
+					DependsOnID: phaseIDs[depPhaseNum-1],
+					Type:        types.DepBlocks,
+				}
+				if err := tx.AddDependency(ctx, blocksDep, actor); err != nil {
+					return fmt.Errorf("failed to add blocks dependency for epic %s: %w", phaseIDs[i], err)
+				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return phaseIDs, nil
@@ -8,7 +8,8 @@ import (
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// TestCreatePhasesFromPlan_Rollback tests that phase creation rolls back on failure
+// TestCreatePhasesFromPlan_Rollback tests that phase creation rolls back on failure,
+// leaving no orphaned phases behind
 func TestCreatePhasesFromPlan_Rollback(t *testing.T) {
 	ctx := context.Background()
 
@@ -84,15 +85,16 @@ func TestCreatePhasesFromPlan_Rollback(t *testing.T) {
 		t.Errorf("Expected 0 phaseIDs on rollback, got %d: %v", len(phaseIDs), phaseIDs)
 	}
 
-	// Verify previously created phases were closed (rollback)
-	if len(store.closedIssues) != 2 {
-		t.Errorf("Expected 2 phases to be closed during rollback, got %d: %v", len(store.closedIssues), store.closedIssues)
-	}
-
-	// Verify no open phases remain (only mission should be left)
+	// Verify no phases remain, open or closed (only mission should be left)
 	if len(store.issues) != 1 {
 		t.Errorf("Expected 1 issue after rollback (mission only), got %d", len(store.issues))
 	}
+	if len(store.closedIssues) != 0 {
+		t.Errorf("Expected no closed phases after rollback, got %d: %v", len(store.closedIssues), store.closedIssues)
+	}
+	if len(store.dependencies) != 0 {
+		t.Errorf("Expected no dependencies after rollback, got %d", len(store.dependencies))
+	}
 }
 
 // TestCreatePhasesFromPlan_RollbackOnDependencyFailure tests rollback when dependency creation fails
@@ -163,8 +165,52 @@ func TestCreatePhasesFromPlan_RollbackOnDependencyFailure(t *testing.T) {
 		t.Errorf("Expected 0 phaseIDs on rollback, got %d", len(phaseIDs))
 	}
 
-	// Verify created phases were closed (rollback)
-	if len(store.closedIssues) == 0 {
-		t.Error("Expected phases to be closed during rollback, got 0")
+	// Verify the phases created before the failure were rolled back
+	if len(store.issues) != 1 {
+		t.Errorf("Expected 1 issue after rollback (mission only), got %d", len(store.issues))
+	}
+	if len(store.dependencies) != 0 {
+		t.Errorf("Expected no dependencies after rollback, got %d", len(store.dependencies))
+	}
+}
+
+// TestCreatePhasesFromPlan_InvalidDependencyWritesNothing tests that a plan
+// with a bad phase dependency is rejected before any phase is created
+func TestCreatePhasesFromPlan_InvalidDependencyWritesNothing(t *testing.T) {
+	ctx := context.Background()
+
+	store := NewMockStorage()
+	store.issues["mission-1"] = &types.Issue{
+		ID:        "mission-1",
+		Title:     "Test Mission",
+		IssueType: types.TypeEpic,
+		Status:    types.StatusOpen,
+	}
+
+	orchestrator, err := NewOrchestrator(&Config{
+		Store:   store,
+		Planner: &MockPlanner{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create orchestrator: %v", err)
+	}
+
+	plan := &types.MissionPlan{
+		MissionID: "mission-1",
+		Phases: []types.PlannedPhase{
+			{PhaseNumber: 1, Title: "Phase 1", Tasks: []string{"Task 1"}},
+			{PhaseNumber: 2, Title: "Phase 2", Tasks: []string{"Task 2"}, Dependencies: []int{5}},
+		},
+		GeneratedAt: time.Now(),
+	}
+
+	if _, err := orchestrator.CreatePhasesFromPlan(ctx, "mission-1", plan, "test-user"); err == nil {
+		t.Fatal("Expected error for dependency on non-existent phase, got nil")
+	}
+	if len(store.issues) != 1 {
+		t.Errorf("Expected only the mission to exist, got %d issues", len(store.issues))
+	}
+	if store.depCallCount != 0 {
+		t.Errorf("Expected no dependency writes, got %d", store.depCallCount)
 	}
 }
@@ -3,12 +3,14 @@ package mission
 import (
 	"context"
 	"errors"
+	"io"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/beads"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -151,6 +153,12 @@ func (m *MockStorage) CloseIssue(ctx context.Context, id string, reason string,
 func (m *MockStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
 	return nil, nil
 }
+func (m *MockStorage) SearchIssuesPage(ctx context.Context, query string, filter types.IssueFilter, page types.PageRequest) (*types.IssuePage, error) {
+	return &types.IssuePage{}, nil
+}
+func (m *MockStorage) CloneIssue(ctx context.Context, id string, opts types.CloneOptions, actor string) (*types.CloneResult, error) {
+	return nil, nil
+}
 func (m *MockStorage) GetDiagnosis(ctx context.Context, issueID string) (*types.TestFailureDiagnosis, error) {
 	return nil, nil
 }
@@ -188,6 +196,12 @@ func (m *MockStorage) GetDependencyTree(ctx context.Context, issueID string, max
 	return nil, nil
 }
 func (m *MockStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) { return nil, nil }
+func (m *MockStorage) GetBlockers(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetBlocked(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return nil, nil
+}
 func (m *MockStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
 	return nil
 }
@@ -201,9 +215,110 @@ func (m *MockStorage) GetLabels(ctx context.Context, issueID string) ([]string,
 func (m *MockStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
 	return nil, nil
 }
+
+func (m *MockStorage) ListLabelDefinitions(ctx context.Context) ([]*types.LabelDefinition, error) {
+	return nil, nil
+}
+
+func (m *MockStorage) GetLabelDefinition(ctx context.Context, name string) (*types.LabelDefinition, error) {
+	return nil, nil
+}
+
+func (m *MockStorage) SaveLabelDefinition(ctx context.Context, def *types.LabelDefinition) error {
+	return nil
+}
+
+func (m *MockStorage) RenameLabel(ctx context.Context, oldName, newName, actor string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockStorage) DeleteLabel(ctx context.Context, name, actor string) (int, error) {
+	return 0, nil
+}
+func (m *MockStorage) ListProjects(ctx context.Context) ([]*types.Project, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetProject(ctx context.Context, name string) (*types.Project, error) {
+	return nil, nil
+}
+func (m *MockStorage) SaveProject(ctx context.Context, project *types.Project) error {
+	return nil
+}
+func (m *MockStorage) SetIssueProject(ctx context.Context, issueID, project, actor string) error {
+	return nil
+}
+func (m *MockStorage) ListCustomFields(ctx context.Context) ([]*types.CustomField, error) {
+	return nil, nil
+}
+func (m *MockStorage) SaveCustomField(ctx context.Context, field *types.CustomField) error {
+	return nil
+}
+func (m *MockStorage) DeleteCustomField(ctx context.Context, project, name string) error {
+	return nil
+}
+func (m *MockStorage) SetCustomFieldValue(ctx context.Context, issueID, name, value, actor string) error {
+	return nil
+}
+func (m *MockStorage) GetCustomFieldValues(ctx context.Context, issueID string) ([]*types.CustomFieldValue, error) {
+	return nil, nil
+}
+func (m *MockStorage) ListSavedFilters(ctx context.Context) ([]*types.SavedFilter, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetSavedFilter(ctx context.Context, name string) (*types.SavedFilter, error) {
+	return nil, nil
+}
+func (m *MockStorage) SaveFilter(ctx context.Context, filter *types.SavedFilter) error {
+	return nil
+}
+func (m *MockStorage) DeleteFilter(ctx context.Context, name string) error {
+	return nil
+}
+func (m *MockStorage) ListIssuesByFilter(ctx context.Context, name string) ([]*types.Issue, error) {
+	return nil, nil
+}
+func (m *MockStorage) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetSchedule(ctx context.Context, name string) (*types.Schedule, error) {
+	return nil, nil
+}
+func (m *MockStorage) SaveSchedule(ctx context.Context, schedule *types.Schedule) error {
+	return nil
+}
+func (m *MockStorage) DeleteSchedule(ctx context.Context, name string) error {
+	return nil
+}
+func (m *MockStorage) ClaimScheduleRun(ctx context.Context, name string, now, next time.Time) (bool, error) {
+	return false, nil
+}
+func (m *MockStorage) SetScheduleLastIssue(ctx context.Context, name, issueID string) error {
+	return nil
+}
 func (m *MockStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
 	return nil, nil
 }
+func (m *MockStorage) GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error) {
+	return &types.EventPage{}, nil
+}
+func (m *MockStorage) GetAuditLog(ctx context.Context, issueID string, limit int) ([]*types.AuditEntry, error) {
+	return nil, nil
+}
+func (m *MockStorage) GetComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	return nil, nil
+}
+func (m *MockStorage) ReplyToComment(ctx context.Context, parentID int64, actor, body string) (*types.Comment, error) {
+	return nil, nil
+}
+func (m *MockStorage) EditComment(ctx context.Context, commentID int64, actor, body string) error {
+	return nil
+}
+func (m *MockStorage) AddReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	return nil
+}
+func (m *MockStorage) RemoveReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	return nil
+}
 func (m *MockStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) { return nil, nil }
 func (m *MockStorage) RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error {
 	return nil
@@ -238,6 +353,10 @@ func (m *MockStorage) GetReadyDependentsOfBlockedBaselines(ctx context.Context,
 	return nil, nil, nil
 }
 func (m *MockStorage) IsEpicComplete(ctx context.Context, epicID string) (bool, error) { return false, nil }
+
+func (m *MockStorage) GetEpicRollup(ctx context.Context, epicID string) (*types.EpicRollup, error) {
+	return nil, nil
+}
 func (m *MockStorage) GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error) {
 	return nil, errors.New("not implemented in mock")
 }
@@ -245,6 +364,12 @@ func (m *MockStorage) GetMissionsNeedingGates(ctx context.Context) ([]*types.Iss
 	return nil, nil
 }
 func (m *MockStorage) ClaimIssue(ctx context.Context, issueID, instanceID string) error { return nil }
+func (m *MockStorage) ClaimNextReadyIssue(ctx context.Context, workerID string, lease time.Duration, project string) (*types.Issue, error) {
+	return nil, nil
+}
+func (m *MockStorage) RenewClaimLease(ctx context.Context, issueID, workerID string, lease time.Duration) error {
+	return nil
+}
 func (m *MockStorage) ReleaseIssue(ctx context.Context, issueID string) error           { return nil }
 func (m *MockStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error {
 	return nil
@@ -282,6 +407,57 @@ func (m *MockStorage) GetExecutionHistoryPaginated(ctx context.Context, issueID
 func (m *MockStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
 	return nil
 }
+func (m *MockStorage) CreateExecution(ctx context.Context, execution *types.Execution) error {
+	return nil
+}
+func (m *MockStorage) UpdateExecution(ctx context.Context, execution *types.Execution) error {
+	return nil
+}
+func (m *MockStorage) GetExecution(ctx context.Context, id int64) (*types.Execution, error) {
+	return nil, nil
+}
+func (m *MockStorage) ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error) {
+	return nil, nil
+}
+func (m *MockStorage) DeleteExecution(ctx context.Context, id int64) error {
+	return nil
+}
+func (m *MockStorage) RecordAIUsage(ctx context.Context, usage *types.AIUsage) error {
+	return nil
+}
+func (m *MockStorage) ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error) {
+	return nil, nil
+}
+func (m *MockStorage) AppendExecutionOutput(ctx context.Context, lines []*types.OutputLine) error {
+	return nil
+}
+func (m *MockStorage) ListExecutionOutput(ctx context.Context, filter types.OutputFilter) ([]*types.OutputLine, error) {
+	return nil, nil
+}
+func (m *MockStorage) CleanupExecutionOutput(ctx context.Context, retentionDays int) (int, error) {
+	return 0, nil
+}
+func (m *MockStorage) AddAttachment(ctx context.Context, attachment *types.Attachment, data []byte) error {
+	return nil
+}
+func (m *MockStorage) GetAttachment(ctx context.Context, id int64) (*types.Attachment, []byte, error) {
+	return nil, nil, nil
+}
+func (m *MockStorage) ListAttachments(ctx context.Context, issueID string) ([]*types.Attachment, error) {
+	return nil, nil
+}
+func (m *MockStorage) DeleteAttachment(ctx context.Context, id int64) error {
+	return nil
+}
+func (m *MockStorage) GetIdempotencyKey(ctx context.Context, key string, since time.Time) (string, bool, error) {
+	return "", false, nil
+}
+func (m *MockStorage) RecordIdempotencyKey(ctx context.Context, key, result string) error {
+	return nil
+}
+func (m *MockStorage) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
 func (m *MockStorage) GetConfig(ctx context.Context, key string) (string, error) {
 	return "", nil
 }
@@ -297,6 +473,9 @@ func (m *MockStorage) CleanupEventsByIssueLimit(ctx context.Context, perIssueLim
 func (m *MockStorage) CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error) {
 	return 0, nil
 }
+func (m *MockStorage) CompactIssueEvents(ctx context.Context, retentionDays, batchSize int) (int, error) {
+	return 0, nil
+}
 
 // Watchdog methods
 func (m *MockStorage) RecordWatchdogIntervention(ctx context.Context, issueID string) error {
@@ -309,6 +488,23 @@ func (m *MockStorage) GetEventCounts(ctx context.Context) (*types.EventCounts, e
 func (m *MockStorage) VacuumDatabase(ctx context.Context) error {
 	return nil
 }
+func (m *MockStorage) AnalyzeDatabase(ctx context.Context) error {
+	return nil
+}
+func (m *MockStorage) Export(ctx context.Context, w io.Writer) error {
+	return nil
+}
+func (m *MockStorage) Import(ctx context.Context, r io.Reader) (*types.ImportStats, error) {
+	return &types.ImportStats{}, nil
+}
+
+func (m *MockStorage) ImportEvents(ctx context.Context, events []*types.Event) (int, error) {
+	return 0, nil
+}
+
+func (m *MockStorage) ArchiveIssues(ctx context.Context, opts types.ArchiveOptions, w io.Writer) (*types.ArchiveStats, error) {
+	return &types.ArchiveStats{}, nil
+}
 
 // Status change logging (vc-n4lx)
 func (m *MockStorage) LogStatusChange(ctx context.Context, issueID string, newStatus types.Status, actor, reason string) {
@@ -351,9 +547,64 @@ func (m *MockStorage) DeletePlan(ctx context.Context, missionID string) error {
 func (m *MockStorage) ListDraftPlans(ctx context.Context) ([]*types.MissionPlan, error) {
 	return nil, nil
 }
+func (m *MockStorage) Watch(ctx context.Context, filter types.WatchFilter) (<-chan types.ChangeEvent, error) {
+	return nil, nil
+}
 
+// RunInVCTransaction runs fn against the mock itself, restoring issues,
+// dependencies and closed issues if fn fails
 func (m *MockStorage) RunInVCTransaction(ctx context.Context, fn func(tx *storage.VCTransaction) error) error {
-	return nil // Mock does not support transactions
+	issues := make(map[string]*types.Issue, len(m.issues))
+	for id, issue := range m.issues {
+		issues[id] = issue
+	}
+	dependencies := append([]*types.Dependency(nil), m.dependencies...)
+	closedIssues := append([]string(nil), m.closedIssues...)
+
+	if err := fn(beads.NewVCTransaction(&mockTx{m: m})); err != nil {
+		m.issues = issues
+		m.dependencies = dependencies
+		m.closedIssues = closedIssues
+		return err
+	}
+	return nil
+}
+
+// mockTx adapts MockStorage to the transaction backend interface
+type mockTx struct {
+	m *MockStorage
+}
+
+func (t *mockTx) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	return t.m.CreateIssue(ctx, issue, actor)
+}
+func (t *mockTx) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	return t.m.CreateIssues(ctx, issues, actor)
+}
+func (t *mockTx) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	return t.m.AddDependency(ctx, dep, actor)
+}
+func (t *mockTx) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	return t.m.RemoveDependency(ctx, issueID, dependsOnID, actor)
+}
+func (t *mockTx) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	return t.m.AddLabel(ctx, issueID, label, actor)
+}
+func (t *mockTx) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	return t.m.RemoveLabel(ctx, issueID, label, actor)
+}
+func (t *mockTx) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	return t.m.UpdateIssue(ctx, id, updates, actor)
+}
+func (t *mockTx) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	return t.m.CloseIssue(ctx, id, reason, actor)
+}
+func (t *mockTx) DeleteIssue(ctx context.Context, id string) error {
+	delete(t.m.issues, id)
+	return nil
+}
+func (t *mockTx) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	return t.m.GetIssue(ctx, id)
 }
 
 func TestGenerateAndStorePlan_RequiresApproval(t *testing.T) {
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// CheckAlerts queues an alert for each escalation and each P0 issue that
+// became blocked since the last check, then tries to send them
+func (n *Notifier) CheckAlerts(ctx context.Context) error {
+	for {
+		page := types.PageRequest{Cursor: n.issueCursor, Limit: types.MaxPageSize}
+		if n.issueCursor == "" {
+			page.Since = n.issueSince
+		}
+		result, err := n.store.GetEventsPage(ctx, "", page)
+		if err != nil {
+			return fmt.Errorf("failed to read issue events: %w", err)
+		}
+		for _, event := range result.Events {
+			if err := n.issueAlert(ctx, event); err != nil {
+				return err
+			}
+			n.issueCursor = types.PageCursor{Key: types.PageKey(event.CreatedAt), ID: strconv.FormatInt(event.ID, 10)}.Encode()
+		}
+		if result.NextCursor == "" {
+			break
+		}
+	}
+	n.flush()
+	return nil
+}
+
+// issueAlert queues the alert an audit event calls for, if any
+func (n *Notifier) issueAlert(ctx context.Context, event *types.Event) error {
+	var subject, reason string
+	switch event.EventType {
+	case types.EventLabelAdded:
+		label := event.AddedLabel()
+		if !types.IsEscalationLabel(label) {
+			return nil
+		}
+		subject = "Escalated"
+		reason = fmt.Sprintf("%s added the %q label: VC needs a human to resolve this issue.", event.Actor, label)
+
+	case types.EventStatusChanged:
+		oldStatus, newStatus := event.StatusChange()
+		if newStatus != string(types.StatusBlocked) || oldStatus == newStatus {
+			return nil
+		}
+		subject = "P0 blocked"
+		reason = fmt.Sprintf("%s moved this P0 issue from %s to blocked.", event.Actor, oldStatus)
+
+	default:
+		return nil
+	}
+
+	issue, err := n.store.GetIssue(ctx, event.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %w", event.IssueID, err)
+	}
+	if issue == nil {
+		return nil
+	}
+	if event.EventType == types.EventStatusChanged && issue.Priority != 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s\n\n", reason)
+	fmt.Fprintf(&body, "Issue:    %s\n", issue.ID)
+	fmt.Fprintf(&body, "Title:    %s\n", issue.Title)
+	fmt.Fprintf(&body, "Status:   %s\n", issue.Status)
+	fmt.Fprintf(&body, "Priority: P%d\n", issue.Priority)
+	fmt.Fprintf(&body, "Type:     %s\n", issue.IssueType)
+	labels, err := n.store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get labels of %s: %w", issue.ID, err)
+	}
+	if owners := types.LabelOwners(labels); len(owners) > 0 {
+		fmt.Fprintf(&body, "Owners:   %s\n", strings.Join(owners, ", "))
+	}
+	fmt.Fprintf(&body, "At:       %s\n", event.CreatedAt.Local().Format("2006-01-02 15:04 MST"))
+	if issue.Description != "" {
+		fmt.Fprintf(&body, "\n%s\n", issue.Description)
+	}
+	fmt.Fprintf(&body, "\nDetails: vc show %s\n", issue.ID)
+
+	n.queue(Message{
+		Subject: fmt.Sprintf("[vc] %s: %s %s", subject, issue.ID, issue.Title),
+		Body:    body.String(),
+	})
+	return nil
+}
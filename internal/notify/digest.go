@@ -0,0 +1,234 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// digestTopIssues is how many of the most expensive issues a digest lists
+const digestTopIssues = 5
+
+// Digest is what happened between Since and Until
+type Digest struct {
+	Since time.Time
+	Until time.Time
+
+	Executions int
+	ByStatus   map[types.ExecutionStatus]int
+	CostUSD    float64
+	TopIssues  []IssueCost         // Most expensive issues first
+	Failures   []*types.Execution  // Failed executions, oldest first
+	Discovered []*types.Issue      // Issues filed by VC during the period
+	titles     map[string]string   // Issue titles for the report
+	labels     map[string][]string // Discovery labels of discovered issues
+	parents    map[string]string   // Issue each discovered issue came from
+}
+
+// IssueCost is the executions spent on one issue
+type IssueCost struct {
+	IssueID    string
+	Executions int
+	CostUSD    float64
+}
+
+// BuildDigest gathers the executions started and issues discovered between
+// since and until
+func (n *Notifier) BuildDigest(ctx context.Context, since, until time.Time) (*Digest, error) {
+	digest := &Digest{
+		Since:    since,
+		Until:    until,
+		ByStatus: map[types.ExecutionStatus]int{},
+		titles:   map[string]string{},
+		labels:   map[string][]string{},
+		parents:  map[string]string{},
+	}
+
+	executions, err := n.store.ListExecutions(ctx, types.ExecutionFilter{Since: since})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions: %w", err)
+	}
+	byIssue := map[string]*IssueCost{}
+	// Oldest first
+	for i := len(executions) - 1; i >= 0; i-- {
+		execution := executions[i]
+		if !execution.StartedAt.Before(until) {
+			continue
+		}
+		digest.Executions++
+		digest.ByStatus[execution.Status]++
+		digest.CostUSD += execution.CostUSD
+		if execution.Status == types.ExecutionFailed {
+			digest.Failures = append(digest.Failures, execution)
+		}
+		cost := byIssue[execution.IssueID]
+		if cost == nil {
+			cost = &IssueCost{IssueID: execution.IssueID}
+			byIssue[execution.IssueID] = cost
+		}
+		cost.Executions++
+		cost.CostUSD += execution.CostUSD
+	}
+	for _, cost := range byIssue {
+		digest.TopIssues = append(digest.TopIssues, *cost)
+	}
+	sort.Slice(digest.TopIssues, func(i, j int) bool {
+		if digest.TopIssues[i].CostUSD != digest.TopIssues[j].CostUSD {
+			return digest.TopIssues[i].CostUSD > digest.TopIssues[j].CostUSD
+		}
+		return digest.TopIssues[i].IssueID < digest.TopIssues[j].IssueID
+	})
+	if len(digest.TopIssues) > digestTopIssues {
+		digest.TopIssues = digest.TopIssues[:digestTopIssues]
+	}
+
+	if err := n.findDiscovered(ctx, digest); err != nil {
+		return nil, err
+	}
+
+	for _, cost := range digest.TopIssues {
+		n.lookupTitle(ctx, digest, cost.IssueID)
+	}
+	for _, execution := range digest.Failures {
+		n.lookupTitle(ctx, digest, execution.IssueID)
+	}
+	return digest, nil
+}
+
+// findDiscovered adds the issues created in the digest's period that VC
+// discovered: labeled discovered:* or linked to the issue they came from
+func (n *Notifier) findDiscovered(ctx context.Context, digest *Digest) error {
+	page := types.PageRequest{Since: digest.Since, Limit: types.MaxPageSize}
+	for {
+		result, err := n.store.SearchIssuesPage(ctx, "", types.IssueFilter{}, page)
+		if err != nil {
+			return fmt.Errorf("failed to list new issues: %w", err)
+		}
+		for _, issue := range result.Issues {
+			if !issue.CreatedAt.Before(digest.Until) {
+				continue
+			}
+			labels, err := n.store.GetLabels(ctx, issue.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get labels for %s: %w", issue.ID, err)
+			}
+			var discoveryLabels []string
+			for _, label := range labels {
+				// Decomposed fragments split existing work rather than find new work
+				if strings.HasPrefix(label, "discovered:") && label != types.LabelDiscoveredDecomposed {
+					discoveryLabels = append(discoveryLabels, label)
+				}
+			}
+			deps, err := n.store.GetDependencyRecords(ctx, issue.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get dependencies for %s: %w", issue.ID, err)
+			}
+			for _, dep := range deps {
+				if dep.Type == types.DepDiscoveredFrom {
+					digest.parents[issue.ID] = dep.DependsOnID
+				}
+			}
+			if len(discoveryLabels) == 0 && digest.parents[issue.ID] == "" {
+				continue
+			}
+			digest.Discovered = append(digest.Discovered, issue)
+			digest.labels[issue.ID] = discoveryLabels
+			digest.titles[issue.ID] = issue.Title
+		}
+		if result.NextCursor == "" {
+			return nil
+		}
+		page.Cursor = result.NextCursor
+	}
+}
+
+// lookupTitle records an issue's title for the report, if it still exists
+func (n *Notifier) lookupTitle(ctx context.Context, digest *Digest, issueID string) {
+	if _, ok := digest.titles[issueID]; ok {
+		return
+	}
+	issue, err := n.store.GetIssue(ctx, issueID)
+	if err != nil || issue == nil {
+		digest.titles[issueID] = ""
+		return
+	}
+	digest.titles[issueID] = issue.Title
+}
+
+// Report renders the digest as plain text
+func (d *Digest) Report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Period: %s to %s\n\n", d.Since.Local().Format("2006-01-02 15:04"), d.Until.Local().Format("2006-01-02 15:04 MST"))
+
+	fmt.Fprintf(&b, "EXECUTIONS: %d, costing $%.2f\n", d.Executions, d.CostUSD)
+	for _, status := range []types.ExecutionStatus{types.ExecutionSucceeded, types.ExecutionIncomplete, types.ExecutionFailed,
+		types.ExecutionInterrupted, types.ExecutionRunning} {
+		if count := d.ByStatus[status]; count > 0 {
+			fmt.Fprintf(&b, "  %-12s %d\n", status, count)
+		}
+	}
+
+	if len(d.TopIssues) > 0 {
+		b.WriteString("\nMOST EXPENSIVE ISSUES\n")
+		for _, cost := range d.TopIssues {
+			fmt.Fprintf(&b, "  %s  $%.2f over %d execution(s)  %s\n", cost.IssueID, cost.CostUSD, cost.Executions, d.titles[cost.IssueID])
+		}
+	}
+
+	if len(d.Failures) > 0 {
+		b.WriteString("\nFAILED EXECUTIONS\n")
+		for _, execution := range d.Failures {
+			fmt.Fprintf(&b, "  %s  %s  %s\n", execution.IssueID, d.titles[execution.IssueID], execution.Error)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nNEWLY DISCOVERED ISSUES: %d\n", len(d.Discovered))
+	for _, issue := range d.Discovered {
+		fmt.Fprintf(&b, "  %s  P%d %s  %s", issue.ID, issue.Priority, issue.IssueType, issue.Title)
+		var notes []string
+		if labels := d.labels[issue.ID]; len(labels) > 0 {
+			notes = append(notes, strings.Join(labels, ", "))
+		}
+		if parent := d.parents[issue.ID]; parent != "" {
+			notes = append(notes, "from "+parent)
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(notes, "; "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SendDigest emails the digest for the period between since and until
+func (n *Notifier) SendDigest(ctx context.Context, since, until time.Time) error {
+	digest, err := n.BuildDigest(ctx, since, until)
+	if err != nil {
+		return err
+	}
+	report := digest.Report()
+
+	body := report
+	if n.summarizer != nil && digest.Executions+len(digest.Discovered) > 0 {
+		summary, err := n.summarizer.SummarizeDigest(ctx, report, digestSummaryLength)
+		if err != nil {
+			// The report stands on its own; send it without the overview
+			slog.Warn("notify: failed to summarize daily digest", "error", err)
+		} else if summary = strings.TrimSpace(summary); summary != "" {
+			body = summary + "\n\n" + strings.Repeat("-", 60) + "\n\n" + report
+		}
+	}
+
+	n.queue(Message{
+		Subject: fmt.Sprintf("[vc] Daily digest for %s: %d executions, $%.2f, %d new issues",
+			until.Local().Format("Jan 2"), digest.Executions, digest.CostUSD, len(digest.Discovered)),
+		Body: body,
+	})
+	n.flush()
+	return nil
+}
@@ -0,0 +1,215 @@
+// Package notify emails people about VC activity over SMTP.
+//
+// A Notifier sends two kinds of email. Alerts go out as soon as an issue is
+// escalated to a human or a P0 issue becomes blocked, found by watching the
+// issue audit trail. The daily digest summarizes the executions, costs and
+// newly discovered issues of the last day; when an AI summarizer is
+// available its overview heads the digest, followed by the full report.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+const (
+	// maxPending bounds the emails kept for retry while the mail server is
+	// unreachable; the oldest are dropped first
+	maxPending = 100
+	// digestSummaryLength is the longest AI overview asked for
+	digestSummaryLength = 1500
+)
+
+// Store is the storage a Notifier reads from
+type Store interface {
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetLabels(ctx context.Context, issueID string) ([]string, error)
+	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
+	SearchIssuesPage(ctx context.Context, query string, filter types.IssueFilter, page types.PageRequest) (*types.IssuePage, error)
+	GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error)
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+}
+
+// Summarizer writes the overview at the top of the daily digest from its
+// plain-text report. *ai.Supervisor implements it.
+type Summarizer interface {
+	SummarizeDigest(ctx context.Context, report string, maxLength int) (string, error)
+}
+
+// Message is an email to the configured recipients
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Notifier sends alert and digest emails
+type Notifier struct {
+	cfg        config.EmailConfig
+	store      Store
+	summarizer Summarizer
+	send       func(msg Message) error
+	now        func() time.Time
+
+	// Where the next alert check picks up. Only events after the notifier
+	// was created are alerted on.
+	issueCursor string
+	issueSince  time.Time
+	pending     []Message
+
+	// The next digest covers digestSince until it is sent at nextDigest
+	digestSince time.Time
+	nextDigest  time.Time
+}
+
+// NewNotifier creates a notifier for cfg, which must be enabled. summarizer
+// may be nil, in which case digests have no AI overview.
+func NewNotifier(cfg config.EmailConfig, store Store, summarizer Summarizer) (*Notifier, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("SMTP host is not configured")
+	}
+
+	start := time.Now()
+	n := &Notifier{
+		cfg:        cfg,
+		store:      store,
+		summarizer: summarizer,
+		now:        time.Now,
+		issueSince: start,
+	}
+	n.send = n.sendSMTP
+	n.nextDigest = nextDigestTime(start, cfg.DigestHour)
+	n.digestSince = n.nextDigest.AddDate(0, 0, -1)
+	return n, nil
+}
+
+// NextDigest returns when the next digest is due
+func (n *Notifier) NextDigest() time.Time {
+	return n.nextDigest
+}
+
+// Run checks for alerts every poll interval, and sends the digest once a
+// day, until ctx is done
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(n.cfg.PollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if len(n.pending) > 0 {
+				slog.Warn("notify: dropping unsent emails on shutdown", "pending", len(n.pending))
+			}
+			return
+		case <-ticker.C:
+			if n.cfg.Alerts {
+				if err := n.CheckAlerts(ctx); err != nil && ctx.Err() == nil {
+					slog.Warn("notify: failed to check for alerts", "error", err)
+				}
+			}
+			if n.cfg.Digest && !n.now().Before(n.nextDigest) {
+				until := n.now()
+				if err := n.SendDigest(ctx, n.digestSince, until); err != nil && ctx.Err() == nil {
+					slog.Error("notify: failed to send daily digest", "error", err)
+				}
+				// A failed digest isn't retried: the next one covers the
+				// period since this one was due
+				n.digestSince = until
+				n.nextDigest = nextDigestTime(until, n.cfg.DigestHour)
+			}
+			n.flush()
+		}
+	}
+}
+
+// nextDigestTime returns the first time after t at hour o'clock local time
+func nextDigestTime(t time.Time, hour int) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, t.Location())
+	if !next.After(t) {
+		next = time.Date(t.Year(), t.Month(), t.Day()+1, hour, 0, 0, 0, t.Location())
+	}
+	return next
+}
+
+// queue adds an email to the pending list, dropping the oldest when full
+func (n *Notifier) queue(msg Message) {
+	if len(n.pending) >= maxPending {
+		slog.Error("notify: too many unsent emails, dropping oldest", "subject", n.pending[0].Subject)
+		n.pending = n.pending[1:]
+	}
+	n.pending = append(n.pending, msg)
+}
+
+// flush sends the pending emails in order, stopping at the first failure so
+// the rest are retried on the next poll
+func (n *Notifier) flush() {
+	for len(n.pending) > 0 {
+		if err := n.send(n.pending[0]); err != nil {
+			slog.Warn("notify: failed to send email, will retry", "subject", n.pending[0].Subject,
+				"pending", len(n.pending), "error", err)
+			return
+		}
+		n.pending = n.pending[1:]
+	}
+}
+
+// sendSMTP delivers msg through the configured mail server. net/smtp uses
+// STARTTLS whenever the server supports it.
+func (n *Notifier) sendSMTP(msg Message) error {
+	from, err := mail.ParseAddress(n.cfg.From)
+	if err != nil {
+		return err
+	}
+	var to []string
+	for _, recipient := range n.cfg.To {
+		addr, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return err
+		}
+		to = append(to, addr.Address)
+	}
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.SMTPHost)
+	}
+	body, err := formatMessage(n.cfg, msg, n.now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(n.cfg.Addr(), auth, from.Address, to, body)
+}
+
+// formatMessage renders msg as a plain-text RFC 5322 email
+func formatMessage(cfg config.EmailConfig, msg Message, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("Auto-Submitted: auto-generated\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,234 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// outbox records the emails a notifier sends, failing while err is set
+type outbox struct {
+	sent []Message
+	err  error
+}
+
+func (o *outbox) send(msg Message) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.sent = append(o.sent, msg)
+	return nil
+}
+
+type fakeSummarizer struct {
+	report string
+}
+
+func (f *fakeSummarizer) SummarizeDigest(ctx context.Context, report string, maxLength int) (string, error) {
+	f.report = report
+	return "A quiet day; vc-1 needs a look.", nil
+}
+
+func testConfig() config.EmailConfig {
+	cfg := config.DefaultEmailConfig()
+	cfg.SMTPHost = "smtp.example.com"
+	cfg.From = "VC <vc@example.com>"
+	cfg.To = []string{"alice@example.com"}
+	return cfg
+}
+
+func newNotifier(t *testing.T, store Store, summarizer Summarizer) (*Notifier, *outbox) {
+	t.Helper()
+	n, err := NewNotifier(testConfig(), store, summarizer)
+	if err != nil {
+		t.Fatalf("NewNotifier() error = %v", err)
+	}
+	box := &outbox{}
+	n.send = box.send
+	// Everything the test records happens after the notifier starts
+	time.Sleep(2 * time.Millisecond)
+	return n, box
+}
+
+func createIssue(t *testing.T, store *memory.Store, title string, priority int) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: title, Description: "Details of " + title, AcceptanceCriteria: "Done",
+		Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	return issue
+}
+
+func TestCheckAlerts(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	urgent := createIssue(t, store, "Production down", 0)
+	routine := createIssue(t, store, "Tidy docs", 2)
+	n, box := newNotifier(t, store, nil)
+
+	blocked := map[string]interface{}{"status": string(types.StatusBlocked)}
+	if err := store.UpdateIssue(ctx, urgent.ID, blocked, "executor"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if err := store.UpdateIssue(ctx, routine.ID, blocked, "executor"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if err := store.AddLabel(ctx, routine.ID, "escalated", "ai-supervisor"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	if err := store.AddLabel(ctx, routine.ID, "docs", "alice"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	if err := store.AddLabel(ctx, routine.ID, types.OwnerLabel("@acme/docs"), "executor"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+
+	if err := n.CheckAlerts(ctx); err != nil {
+		t.Fatalf("CheckAlerts() error = %v", err)
+	}
+	if err := n.CheckAlerts(ctx); err != nil {
+		t.Fatalf("CheckAlerts() error = %v", err)
+	}
+
+	if len(box.sent) != 2 {
+		t.Fatalf("sent %d emails, want the blocked P0 and the escalation: %+v", len(box.sent), box.sent)
+	}
+	if got := box.sent[0]; !strings.Contains(got.Subject, "P0 blocked: "+urgent.ID) || !strings.Contains(got.Body, "Details of Production down") {
+		t.Errorf("blocked P0 alert = %+v", got)
+	}
+	if got := box.sent[1]; !strings.Contains(got.Subject, "Escalated: "+routine.ID) || !strings.Contains(got.Body, `"escalated"`) ||
+		!strings.Contains(got.Body, "Owners:   @acme/docs") {
+		t.Errorf("escalation alert = %+v", got)
+	}
+}
+
+func TestPendingEmailsAreRetried(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := createIssue(t, store, "Stuck", 2)
+	n, box := newNotifier(t, store, nil)
+	box.err = errors.New("connection refused")
+
+	if err := store.AddLabel(ctx, issue.ID, "escalation", "executor-escalation"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	if err := n.CheckAlerts(ctx); err != nil {
+		t.Fatalf("CheckAlerts() error = %v", err)
+	}
+	if len(n.pending) != 1 {
+		t.Fatalf("pending = %d after a failed send, want 1", len(n.pending))
+	}
+
+	box.err = nil
+	n.flush()
+	if len(n.pending) != 0 || len(box.sent) != 1 {
+		t.Errorf("pending = %d, sent = %d after the server recovered", len(n.pending), len(box.sent))
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	since := time.Now().Add(-time.Hour)
+	parent := createIssue(t, store, "Add caching", 1)
+	labeled := createIssue(t, store, "Cache misses on restart", 1)
+	linked := createIssue(t, store, "Flaky cache test", 2)
+	createIssue(t, store, "Filed by a human", 2)
+	if err := store.AddLabel(ctx, labeled.ID, types.LabelDiscoveredBlocker, "ai-supervisor"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	dep := &types.Dependency{IssueID: linked.ID, DependsOnID: parent.ID, Type: types.DepDiscoveredFrom}
+	if err := store.AddDependency(ctx, dep, "ai-supervisor"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	for _, execution := range []*types.Execution{
+		{IssueID: parent.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded, CostUSD: 1.5},
+		{IssueID: parent.ID, AgentProvider: "claude-code", Status: types.ExecutionFailed, CostUSD: 0.5, Error: "agent crashed"},
+	} {
+		if err := store.CreateExecution(ctx, execution); err != nil {
+			t.Fatalf("CreateExecution() error = %v", err)
+		}
+	}
+	summarizer := &fakeSummarizer{}
+	n, box := newNotifier(t, store, summarizer)
+
+	if err := n.SendDigest(ctx, since, time.Now()); err != nil {
+		t.Fatalf("SendDigest() error = %v", err)
+	}
+	if len(box.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(box.sent))
+	}
+	msg := box.sent[0]
+	if !strings.Contains(msg.Subject, "2 executions, $2.00, 2 new issues") {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if !strings.HasPrefix(msg.Body, "A quiet day") || !strings.Contains(msg.Body, summarizer.report) {
+		t.Errorf("Body should open with the summary and include the report:\n%s", msg.Body)
+	}
+	for _, want := range []string{
+		parent.ID + "  $2.00 over 2 execution(s)  Add caching",
+		"agent crashed",
+		labeled.ID + "  P1 task  Cache misses on restart (discovered:blocker)",
+		linked.ID + "  P2 task  Flaky cache test (from " + parent.ID + ")",
+	} {
+		if !strings.Contains(summarizer.report, want) {
+			t.Errorf("report is missing %q:\n%s", want, summarizer.report)
+		}
+	}
+	if strings.Contains(summarizer.report, "Filed by a human") {
+		t.Errorf("report lists an issue VC didn't discover:\n%s", summarizer.report)
+	}
+}
+
+func TestNextDigestTime(t *testing.T) {
+	loc := time.UTC
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{time.Date(2025, 3, 1, 7, 59, 0, 0, loc), time.Date(2025, 3, 1, 8, 0, 0, 0, loc)},
+		{time.Date(2025, 3, 1, 8, 0, 0, 0, loc), time.Date(2025, 3, 2, 8, 0, 0, 0, loc)},
+		{time.Date(2025, 3, 31, 23, 0, 0, 0, loc), time.Date(2025, 4, 1, 8, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		if got := nextDigestTime(tt.now, 8); !got.Equal(tt.want) {
+			t.Errorf("nextDigestTime(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMessage(t *testing.T) {
+	cfg := testConfig()
+	cfg.To = []string{"alice@example.com", "Bob <bob@example.com>"}
+	body, err := formatMessage(cfg, Message{Subject: "[vc] Escalated: vc-1 Fix “quotes”", Body: "line one\nline two"}, time.Now())
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "[vc] Escalated: vc-1 Fix “quotes”" {
+		t.Errorf("Subject = %q (%v)", subject, err)
+	}
+	if to, err := msg.Header.AddressList("To"); err != nil || len(to) != 2 {
+		t.Errorf("To = %v (%v)", to, err)
+	}
+	text, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil || string(text) != "line one\r\nline two" {
+		t.Errorf("body = %q (%v)", text, err)
+	}
+}
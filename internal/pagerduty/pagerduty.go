@@ -0,0 +1,435 @@
+// Package pagerduty pages a human through the PagerDuty Events API (v2)
+// when VC is stuck on critical work.
+//
+// A Pager watches the database for P0 issues that become blocked and for P0
+// issues whose executions keep failing, and triggers an incident for each.
+// The incident's details carry the AI supervisor's summary of the situation
+// when one is available, followed by the factual report it was written
+// from. Incidents are keyed by issue, so later pages about the same issue
+// join its open incident, and they are resolved when the issue is closed.
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+const (
+	// maxPending bounds the events kept for retry while PagerDuty is
+	// unreachable; the oldest are dropped first
+	maxPending = 100
+	// executionWindow is how many recent executions are checked for
+	// failures on each poll
+	executionWindow = 100
+	// recentExecutions is how many of an issue's executions are reported
+	recentExecutions = 5
+	// summaryLength is the longest AI summary asked for
+	summaryLength = 1500
+	// maxSummary is the Events API limit on an event's summary line
+	maxSummary = 1024
+	// requestTimeout bounds each request to the Events API
+	requestTimeout = 10 * time.Second
+)
+
+// Store is the storage a Pager reads from
+type Store interface {
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error)
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+}
+
+// Summarizer writes the summary of a page from its plain-text report.
+// *ai.Supervisor implements it.
+type Summarizer interface {
+	SummarizeIncident(ctx context.Context, report string, maxLength int) (string, error)
+}
+
+// Event actions
+const (
+	ActionTrigger = "trigger"
+	ActionResolve = "resolve"
+)
+
+// Event is an Events API v2 event
+type Event struct {
+	RoutingKey  string   `json:"routing_key"`
+	EventAction string   `json:"event_action"`
+	DedupKey    string   `json:"dedup_key"`
+	Payload     *Payload `json:"payload,omitempty"`
+}
+
+// Payload describes what a trigger event is about
+type Payload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Component     string                 `json:"component,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// errRejected marks events the Events API refused as invalid; they are
+// dropped rather than retried
+var errRejected = errors.New("event rejected")
+
+// Pager triggers and resolves PagerDuty incidents for stuck P0 work
+type Pager struct {
+	cfg        config.PagerDutyConfig
+	store      Store
+	summarizer Summarizer
+	client     *http.Client
+
+	// Where the next poll picks up. Only events and executions after the
+	// pager was created are paged on.
+	issueCursor       string
+	issueSince        time.Time
+	executionsSince   time.Time
+	checkedExecutions map[int64]bool
+
+	// Issues paged about since their incident was last resolved
+	paged   map[string]bool
+	pending []Event
+}
+
+// NewPager creates a pager for cfg, which must be enabled. summarizer may
+// be nil, in which case pages carry only the report.
+func NewPager(cfg config.PagerDutyConfig, store Store, summarizer Summarizer) (*Pager, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("PagerDuty routing key is not configured")
+	}
+
+	start := time.Now()
+	return &Pager{
+		cfg:               cfg,
+		store:             store,
+		summarizer:        summarizer,
+		client:            &http.Client{Timeout: requestTimeout},
+		issueSince:        start,
+		executionsSince:   start,
+		checkedExecutions: make(map[int64]bool),
+		paged:             make(map[string]bool),
+	}, nil
+}
+
+// Run checks for stuck P0 work and sends pages every poll interval until
+// ctx is done
+func (p *Pager) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.PollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if len(p.pending) > 0 {
+				slog.Warn("pagerduty: dropping unsent events on shutdown", "pending", len(p.pending))
+			}
+			return
+		case <-ticker.C:
+			if err := p.Poll(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("pagerduty: failed to check for stuck work", "error", err)
+			}
+			p.Flush(ctx)
+		}
+	}
+}
+
+// Pending returns how many events are waiting to be sent
+func (p *Pager) Pending() int {
+	return len(p.pending)
+}
+
+// Poll queues pages for the P0 issues that became blocked or failed too
+// many executions in a row since the last poll, and resolves the incidents
+// of issues that were closed
+func (p *Pager) Poll(ctx context.Context) error {
+	if err := p.pollIssueEvents(ctx); err != nil {
+		return fmt.Errorf("failed to read issue events: %w", err)
+	}
+	if err := p.pollExecutions(ctx); err != nil {
+		return fmt.Errorf("failed to read executions: %w", err)
+	}
+	return nil
+}
+
+// pollIssueEvents walks the issue audit trail from the last event seen
+func (p *Pager) pollIssueEvents(ctx context.Context) error {
+	for {
+		page := types.PageRequest{Cursor: p.issueCursor, Limit: types.MaxPageSize}
+		if p.issueCursor == "" {
+			page.Since = p.issueSince
+		}
+		result, err := p.store.GetEventsPage(ctx, "", page)
+		if err != nil {
+			return err
+		}
+		for _, event := range result.Events {
+			if err := p.issueEvent(ctx, event); err != nil {
+				return err
+			}
+			p.issueCursor = types.PageCursor{Key: types.PageKey(event.CreatedAt), ID: strconv.FormatInt(event.ID, 10)}.Encode()
+		}
+		if result.NextCursor == "" {
+			return nil
+		}
+	}
+}
+
+// issueEvent pages for a P0 issue moving to blocked, and resolves the
+// incident of a paged issue that was closed
+func (p *Pager) issueEvent(ctx context.Context, event *types.Event) error {
+	if event.EventType != types.EventStatusChanged && event.EventType != types.EventClosed {
+		return nil
+	}
+	oldStatus, newStatus := event.StatusChange()
+	if oldStatus == newStatus {
+		return nil
+	}
+	if newStatus == string(types.StatusClosed) {
+		if p.paged[event.IssueID] {
+			p.resolve(event.IssueID)
+		}
+		return nil
+	}
+	if newStatus != string(types.StatusBlocked) {
+		return nil
+	}
+
+	issue, err := p.store.GetIssue(ctx, event.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %w", event.IssueID, err)
+	}
+	if issue == nil || issue.Priority != 0 {
+		return nil
+	}
+	reason := fmt.Sprintf("%s moved this P0 issue from %s to blocked.", event.Actor, oldStatus)
+	return p.trigger(ctx, issue, "P0 blocked", reason, event.CreatedAt)
+}
+
+// pollExecutions pages for P0 issues whose latest executions, including
+// one that finished since the last poll, failed FailureThreshold times in a
+// row
+func (p *Pager) pollExecutions(ctx context.Context) error {
+	executions, err := p.store.ListExecutions(ctx, types.ExecutionFilter{Limit: executionWindow})
+	if err != nil {
+		return err
+	}
+	window := make(map[int64]bool, len(executions))
+	for i := len(executions) - 1; i >= 0; i-- {
+		execution := executions[i]
+		window[execution.ID] = true
+		if execution.CompletedAt == nil || execution.CompletedAt.Before(p.executionsSince) || p.checkedExecutions[execution.ID] {
+			continue
+		}
+		p.checkedExecutions[execution.ID] = true
+		if !failed(execution) || p.paged[execution.IssueID] {
+			continue
+		}
+		if err := p.checkFailures(ctx, execution); err != nil {
+			return err
+		}
+	}
+	// Executions that left the window can't be seen again
+	for id := range p.checkedExecutions {
+		if !window[id] {
+			delete(p.checkedExecutions, id)
+		}
+	}
+	return nil
+}
+
+// checkFailures pages if the issue of a failed execution is an open P0
+// whose executions have failed often enough in a row
+func (p *Pager) checkFailures(ctx context.Context, execution *types.Execution) error {
+	issue, err := p.store.GetIssue(ctx, execution.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %w", execution.IssueID, err)
+	}
+	if issue == nil || issue.Priority != 0 || issue.Status == types.StatusClosed {
+		return nil
+	}
+	history, err := p.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID, Limit: p.cfg.FailureThreshold})
+	if err != nil {
+		return fmt.Errorf("failed to list executions of %s: %w", issue.ID, err)
+	}
+	if len(history) < p.cfg.FailureThreshold {
+		return nil
+	}
+	for _, previous := range history {
+		if !failed(previous) {
+			return nil
+		}
+	}
+	reason := fmt.Sprintf("The last %d executions of this P0 issue failed; VC hasn't been able to recover.", len(history))
+	return p.trigger(ctx, issue, "P0 failing", reason, *execution.CompletedAt)
+}
+
+// failed reports whether an execution ended without completing its issue.
+// Interrupted executions were paused on purpose.
+func failed(execution *types.Execution) bool {
+	return execution.Status == types.ExecutionFailed || execution.Status == types.ExecutionIncomplete
+}
+
+// trigger queues a page about issue
+func (p *Pager) trigger(ctx context.Context, issue *types.Issue, kind, reason string, at time.Time) error {
+	report, err := p.report(ctx, issue, reason)
+	if err != nil {
+		return err
+	}
+	details := map[string]interface{}{
+		"issue_id": issue.ID,
+		"title":    issue.Title,
+		"reason":   reason,
+		"report":   report,
+		"details":  "vc show " + issue.ID,
+	}
+	if p.summarizer != nil {
+		summary, err := p.summarizer.SummarizeIncident(ctx, report, summaryLength)
+		if err != nil {
+			slog.Warn("pagerduty: paging without an AI summary", "issue", issue.ID, "error", err)
+		} else if summary = strings.TrimSpace(summary); summary != "" {
+			details["ai_summary"] = summary
+		}
+	}
+
+	p.paged[issue.ID] = true
+	p.queue(Event{
+		RoutingKey:  p.cfg.RoutingKey,
+		EventAction: ActionTrigger,
+		DedupKey:    dedupKey(issue.ID),
+		Payload: &Payload{
+			Summary:       truncate(fmt.Sprintf("[vc] %s: %s %s", kind, issue.ID, issue.Title), maxSummary),
+			Source:        p.cfg.Source,
+			Severity:      "critical",
+			Timestamp:     at.UTC().Format(time.RFC3339),
+			Component:     issue.ID,
+			Class:         kind,
+			CustomDetails: details,
+		},
+	})
+	return nil
+}
+
+// resolve queues the resolution of issue's incident
+func (p *Pager) resolve(issueID string) {
+	delete(p.paged, issueID)
+	p.queue(Event{RoutingKey: p.cfg.RoutingKey, EventAction: ActionResolve, DedupKey: dedupKey(issueID)})
+}
+
+// report describes issue and its recent executions as plain text
+func (p *Pager) report(ctx context.Context, issue *types.Issue, reason string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", reason)
+	fmt.Fprintf(&b, "Issue:    %s\n", issue.ID)
+	fmt.Fprintf(&b, "Title:    %s\n", issue.Title)
+	fmt.Fprintf(&b, "Status:   %s\n", issue.Status)
+	fmt.Fprintf(&b, "Type:     %s\n", issue.IssueType)
+	if issue.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", issue.Description)
+	}
+
+	executions, err := p.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID, Limit: recentExecutions})
+	if err != nil {
+		return "", fmt.Errorf("failed to list executions of %s: %w", issue.ID, err)
+	}
+	if len(executions) > 0 {
+		b.WriteString("\nRecent executions:\n")
+	}
+	for _, execution := range executions {
+		fmt.Fprintf(&b, "- #%d %s, started %s, %v", execution.ID, execution.Status,
+			execution.StartedAt.Local().Format("2006-01-02 15:04 MST"), execution.Duration().Round(time.Second))
+		if execution.Error != "" {
+			fmt.Fprintf(&b, ": %s", execution.Error)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// dedupKey returns the incident key of an issue
+func dedupKey(issueID string) string {
+	return "vc/" + issueID
+}
+
+// truncate shortens s to at most n bytes, on a rune boundary
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// queue adds an event to the pending list, dropping the oldest when full
+func (p *Pager) queue(event Event) {
+	if len(p.pending) >= maxPending {
+		slog.Error("pagerduty: too many unsent events, dropping oldest", "action", p.pending[0].EventAction, "dedup_key", p.pending[0].DedupKey)
+		p.pending = p.pending[1:]
+	}
+	p.pending = append(p.pending, event)
+}
+
+// Flush sends the pending events in order, stopping at the first failure so
+// the rest are retried on the next poll. Events PagerDuty rejects as
+// invalid are dropped.
+func (p *Pager) Flush(ctx context.Context) {
+	for len(p.pending) > 0 && ctx.Err() == nil {
+		event := p.pending[0]
+		err := p.send(ctx, event)
+		if errors.Is(err, errRejected) {
+			slog.Error("pagerduty: dropping rejected event", "action", event.EventAction, "dedup_key", event.DedupKey, "error", err)
+		} else if err != nil {
+			slog.Warn("pagerduty: failed to send event, will retry", "action", event.EventAction, "dedup_key", event.DedupKey,
+				"pending", len(p.pending), "error", err)
+			return
+		}
+		p.pending = p.pending[1:]
+	}
+}
+
+// send posts event to the Events API once
+func (p *Pager) send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errRejected, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.EventsURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "vc-pagerduty")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return nil
+	case resp.StatusCode == http.StatusBadRequest:
+		return fmt.Errorf("%w: PagerDuty returned %s: %s", errRejected, resp.Status, strings.TrimSpace(string(respBody)))
+	default:
+		return fmt.Errorf("PagerDuty returned %s", resp.Status)
+	}
+}
@@ -0,0 +1,224 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// eventsAPI records the events posted to it, answering with status
+type eventsAPI struct {
+	mu       sync.Mutex
+	received []Event
+	status   int
+}
+
+func (a *eventsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.status != 0 {
+		w.WriteHeader(a.status)
+		return
+	}
+	var event Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	a.received = append(a.received, event)
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte(`{"status":"success","dedup_key":"` + event.DedupKey + `"}`))
+}
+
+func (a *eventsAPI) events() []Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Event(nil), a.received...)
+}
+
+type fakeSummarizer struct {
+	reports []string
+}
+
+func (f *fakeSummarizer) SummarizeIncident(ctx context.Context, report string, maxLength int) (string, error) {
+	f.reports = append(f.reports, report)
+	return "The agent keeps failing the build; check the CI image.", nil
+}
+
+func newPager(t *testing.T, store Store, summarizer Summarizer) (*Pager, *eventsAPI) {
+	t.Helper()
+	api := &eventsAPI{}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	cfg := config.DefaultPagerDutyConfig()
+	cfg.RoutingKey = "routing-key"
+	cfg.EventsURL = server.URL
+	p, err := NewPager(cfg, store, summarizer)
+	if err != nil {
+		t.Fatalf("NewPager() error = %v", err)
+	}
+	// Everything the test records happens after the pager starts
+	time.Sleep(2 * time.Millisecond)
+	return p, api
+}
+
+func createIssue(t *testing.T, store *memory.Store, title string, priority int) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: title, Description: "Details of " + title, AcceptanceCriteria: "Done",
+		Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	return issue
+}
+
+func recordExecution(t *testing.T, store *memory.Store, issueID string, status types.ExecutionStatus, errMsg string) {
+	t.Helper()
+	completed := time.Now()
+	execution := &types.Execution{IssueID: issueID, AgentProvider: "claude-code", Status: status, Error: errMsg,
+		StartedAt: completed.Add(-time.Minute), CompletedAt: &completed}
+	if err := store.CreateExecution(context.Background(), execution); err != nil {
+		t.Fatalf("CreateExecution() error = %v", err)
+	}
+}
+
+func pollAndFlush(t *testing.T, p *Pager) {
+	t.Helper()
+	ctx := context.Background()
+	if err := p.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	p.Flush(ctx)
+}
+
+func TestBlockedP0Pages(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	urgent := createIssue(t, store, "Production down", 0)
+	routine := createIssue(t, store, "Tidy docs", 2)
+	summarizer := &fakeSummarizer{}
+	p, api := newPager(t, store, summarizer)
+
+	blocked := map[string]interface{}{"status": string(types.StatusBlocked)}
+	for _, issue := range []*types.Issue{urgent, routine} {
+		if err := store.UpdateIssue(ctx, issue.ID, blocked, "executor"); err != nil {
+			t.Fatalf("UpdateIssue() error = %v", err)
+		}
+	}
+	pollAndFlush(t, p)
+	pollAndFlush(t, p)
+
+	sent := api.events()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d events, want a page for the blocked P0: %+v", len(sent), sent)
+	}
+	event := sent[0]
+	if event.EventAction != ActionTrigger || event.RoutingKey != "routing-key" || event.DedupKey != "vc/"+urgent.ID {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Payload == nil || event.Payload.Severity != "critical" || !strings.Contains(event.Payload.Summary, "P0 blocked: "+urgent.ID) {
+		t.Fatalf("payload = %+v", event.Payload)
+	}
+	if got := event.Payload.CustomDetails["ai_summary"]; got != "The agent keeps failing the build; check the CI image." {
+		t.Errorf("ai_summary = %v", got)
+	}
+	if len(summarizer.reports) != 1 || !strings.Contains(summarizer.reports[0], "Details of Production down") {
+		t.Errorf("summarizer reports = %q", summarizer.reports)
+	}
+
+	// Closing the issue resolves its incident
+	if err := store.CloseIssue(ctx, urgent.ID, "fixed by hand", "alice"); err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+	pollAndFlush(t, p)
+	sent = api.events()
+	if len(sent) != 2 || sent[1].EventAction != ActionResolve || sent[1].DedupKey != "vc/"+urgent.ID || sent[1].Payload != nil {
+		t.Errorf("events after close = %+v", sent)
+	}
+}
+
+func TestRepeatedFailuresPage(t *testing.T) {
+	store := memory.New()
+	urgent := createIssue(t, store, "Payments broken", 0)
+	routine := createIssue(t, store, "Tidy docs", 2)
+	p, api := newPager(t, store, nil)
+
+	recordExecution(t, store, urgent.ID, types.ExecutionFailed, "gates failed: go test")
+	recordExecution(t, store, urgent.ID, types.ExecutionIncomplete, "")
+	for i := 0; i < 3; i++ {
+		recordExecution(t, store, routine.ID, types.ExecutionFailed, "agent crashed")
+	}
+	pollAndFlush(t, p)
+	if sent := api.events(); len(sent) != 0 {
+		t.Fatalf("paged before the threshold: %+v", sent)
+	}
+
+	recordExecution(t, store, urgent.ID, types.ExecutionFailed, "gates failed: go vet")
+	pollAndFlush(t, p)
+	recordExecution(t, store, urgent.ID, types.ExecutionFailed, "gates failed: go vet")
+	pollAndFlush(t, p)
+
+	sent := api.events()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d events, want one page for the failing P0: %+v", len(sent), sent)
+	}
+	if !strings.Contains(sent[0].Payload.Summary, "P0 failing: "+urgent.ID) {
+		t.Errorf("summary = %q", sent[0].Payload.Summary)
+	}
+	if _, ok := sent[0].Payload.CustomDetails["ai_summary"]; ok {
+		t.Error("ai_summary set without a summarizer")
+	}
+	report, _ := sent[0].Payload.CustomDetails["report"].(string)
+	if !strings.Contains(report, "gates failed: go vet") || !strings.Contains(report, "last 3 executions") {
+		t.Errorf("report = %q", report)
+	}
+}
+
+func TestFailedSendsAreRetried(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := createIssue(t, store, "Production down", 0)
+	p, api := newPager(t, store, nil)
+	api.status = http.StatusServiceUnavailable
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, "executor"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	pollAndFlush(t, p)
+	if p.Pending() != 1 {
+		t.Fatalf("Pending() = %d after a failed send, want 1", p.Pending())
+	}
+
+	api.status = 0
+	p.Flush(ctx)
+	if p.Pending() != 0 || len(api.events()) != 1 {
+		t.Errorf("Pending() = %d, sent = %d after PagerDuty recovered", p.Pending(), len(api.events()))
+	}
+
+	// Events PagerDuty rejects are dropped, not retried forever
+	api.status = http.StatusBadRequest
+	p.resolve(issue.ID)
+	p.Flush(ctx)
+	if p.Pending() != 0 {
+		t.Errorf("Pending() = %d after a rejected event, want 0", p.Pending())
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("héllo", 2); got != "h" {
+		t.Errorf("truncate() = %q, want it to stop before a split rune", got)
+	}
+	if got := truncate("hello", 10); got != "hello" {
+		t.Errorf("truncate() = %q", got)
+	}
+}
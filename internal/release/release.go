@@ -0,0 +1,437 @@
+// Package release turns finished missions into releases: when a mission
+// labelled "release:<version>" is closed, it generates release notes from
+// the issues that closed under the mission, creates an annotated tag for the
+// version at the checked out commit, and optionally pushes the tag and
+// publishes a release for it on the git hosting provider.
+package release
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/hosting"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// LabelPrefix marks labels that make a mission a release, e.g.
+// "release:v1.2.0" tags v1.2.0 when the mission closes
+const LabelPrefix = "release:"
+
+// Release outcomes
+const (
+	StatusPublished = "published" // Tag pushed and release published on the hosting provider
+	StatusTagged    = "tagged"    // Tag created locally only
+	StatusSkipped   = "skipped"   // Tag already exists from an earlier run
+	StatusFailed    = "failed"    // Release couldn't be made
+)
+
+// Label returns the label that makes a mission the release of version
+func Label(version string) string {
+	return LabelPrefix + version
+}
+
+// Version returns the version labels name, or "" if they don't make a
+// release. With several release labels the first one wins.
+func Version(labels []string) string {
+	for _, label := range labels {
+		if version, ok := strings.CutPrefix(label, LabelPrefix); ok && version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// Config configures a Manager
+type Config struct {
+	Store      storage.Storage
+	Git        git.GitOperations
+	RepoPath   string                  // Repository to tag; its HEAD is released
+	Hosting    config.HostingConfig    // Publishes releases; without a token tags are only created locally
+	PushChecks config.PushChecksConfig // Pre-push checks for release tags
+	Publish    bool                    // Push tags and publish releases on the hosting provider
+	Draft      bool                    // Publish releases as drafts (GitHub only)
+	Actor      string                  // Recorded as the author of comments
+	ExecutorID string                  // Recorded on release events (optional)
+}
+
+// Manager makes releases from closed missions
+type Manager struct {
+	store      storage.Storage
+	git        git.GitOperations
+	repoPath   string
+	hosting    config.HostingConfig
+	pushChecks config.PushChecksConfig
+	publish    bool
+	draft      bool
+	actor      string
+	executorID string
+}
+
+// Result describes the release of a mission
+type Result struct {
+	Tag    string
+	Status string
+	Commit string         // Commit the tag points to
+	Issues []*types.Issue // Closed issues the notes were generated from
+	Notes  string         // Release notes, in Markdown
+	URL    string         // StatusPublished
+	Reason string         // Why the release wasn't published
+}
+
+// New creates a release manager
+func New(cfg Config) (*Manager, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	if cfg.Git == nil {
+		return nil, fmt.Errorf("git operations are required")
+	}
+	if cfg.RepoPath == "" {
+		return nil, fmt.Errorf("repository path is required")
+	}
+	if cfg.Actor == "" {
+		cfg.Actor = "vc-release"
+	}
+	if cfg.Hosting.Remote == "" {
+		cfg.Hosting.Remote = "origin"
+	}
+	return &Manager{
+		store:      cfg.Store,
+		git:        cfg.Git,
+		repoPath:   cfg.RepoPath,
+		hosting:    cfg.Hosting,
+		pushChecks: cfg.PushChecks,
+		publish:    cfg.Publish,
+		draft:      cfg.Draft,
+		actor:      cfg.Actor,
+		executorID: cfg.ExecutorID,
+	}, nil
+}
+
+// ReleaseMission releases a closed mission whose labels name a version.
+// Returns nil if the mission isn't a release. Failures to tag or publish
+// are reported in the result; an error means the mission can't be released
+// yet, e.g. it is still open or some of its work hasn't landed.
+func (m *Manager) ReleaseMission(ctx context.Context, missionID string) (*Result, error) {
+	labels, err := m.store.GetLabels(ctx, missionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels of %s: %w", missionID, err)
+	}
+	version := Version(labels)
+	if version == "" {
+		return nil, nil
+	}
+	mission, err := m.store.GetIssue(ctx, missionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mission %s: %w", missionID, err)
+	}
+	if mission == nil {
+		return nil, fmt.Errorf("mission %s not found", missionID)
+	}
+	if mission.IssueType != types.TypeEpic {
+		return nil, fmt.Errorf("%s is a %s, only missions and epics can be released", missionID, mission.IssueType)
+	}
+	if mission.Status != types.StatusClosed {
+		return nil, fmt.Errorf("mission %s is %s; it is released once closed", missionID, mission.Status)
+	}
+
+	result := &Result{Tag: version}
+	if commit, err := m.git.ResolveRef(ctx, m.repoPath, "refs/tags/"+version); err == nil {
+		result.Status = StatusSkipped
+		result.Commit = commit
+		result.Reason = fmt.Sprintf("tag %s already exists", version)
+		return result, nil
+	}
+
+	issues, err := m.ClosedIssues(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkLanded(ctx, issues); err != nil {
+		return nil, err
+	}
+	result.Issues = issues
+	result.Notes = Notes(mission, version, issues)
+
+	m.release(ctx, mission, result)
+	m.record(ctx, mission, result)
+	return result, nil
+}
+
+// ClosedIssues returns the closed issues under an epic, searching child
+// epics recursively, in the order they were closed. Epics themselves are
+// left out; their children describe the work.
+func (m *Manager) ClosedIssues(ctx context.Context, epicID string) ([]*types.Issue, error) {
+	var issues []*types.Issue
+	seen := map[string]bool{epicID: true}
+	var walk func(parentID string) error
+	walk = func(parentID string) error {
+		dependents, err := m.store.GetDependents(ctx, parentID)
+		if err != nil {
+			return fmt.Errorf("failed to get children of %s: %w", parentID, err)
+		}
+		for _, child := range dependents {
+			if seen[child.ID] {
+				continue
+			}
+			isChild, err := m.isChild(ctx, child.ID, parentID)
+			if err != nil {
+				return err
+			}
+			if !isChild {
+				continue
+			}
+			seen[child.ID] = true
+			if child.IssueType == types.TypeEpic {
+				if err := walk(child.ID); err != nil {
+					return err
+				}
+				continue
+			}
+			if child.Status == types.StatusClosed {
+				issues = append(issues, child)
+			}
+		}
+		return nil
+	}
+	if err := walk(epicID); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i].ClosedAt, issues[j].ClosedAt
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return issues[i].ID < issues[j].ID
+	})
+	return issues, nil
+}
+
+// isChild reports whether issueID is a child of parentID, as opposed to
+// depending on it in some other way
+func (m *Manager) isChild(ctx context.Context, issueID, parentID string) (bool, error) {
+	deps, err := m.store.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get dependencies of %s: %w", issueID, err)
+	}
+	for _, dep := range deps {
+		if dep.DependsOnID == parentID && dep.Type == types.DepParentChild {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkLanded returns an error naming the issues whose committed work isn't
+// in the checked out branch's history, e.g. because their pull requests
+// haven't merged yet. Issues closed without commits have nothing to land.
+func (m *Manager) checkLanded(ctx context.Context, issues []*types.Issue) error {
+	var missing []string
+	for _, issue := range issues {
+		execs, err := m.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID})
+		if err != nil {
+			return fmt.Errorf("failed to list executions of %s: %w", issue.ID, err)
+		}
+		for _, exec := range execs {
+			if exec.CommitHash == "" || exec.IsRolledBack() {
+				continue
+			}
+			landed, err := m.git.IsAncestor(ctx, m.repoPath, exec.CommitHash)
+			if err != nil {
+				return err
+			}
+			if !landed {
+				missing = append(missing, fmt.Sprintf("%s (%s)", issue.ID, shortHash(exec.CommitHash)))
+				break
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("work for %s hasn't landed in %s yet", strings.Join(missing, ", "), m.repoPath)
+	}
+	return nil
+}
+
+// Notes generates release notes for version from the issues closed under a
+// mission, grouped by issue type
+func Notes(mission *types.Issue, version string, issues []*types.Issue) string {
+	sections := []struct {
+		title string
+		types []types.IssueType
+	}{
+		{"Features", []types.IssueType{types.TypeFeature}},
+		{"Bug Fixes", []types.IssueType{types.TypeBug}},
+		{"Other Changes", []types.IssueType{types.TypeTask, types.TypeChore}},
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n%s (%s)\n", version, mission.Title, mission.ID)
+	for _, section := range sections {
+		var lines []string
+		for _, issue := range issues {
+			for _, t := range section.types {
+				if issue.IssueType == t {
+					lines = append(lines, fmt.Sprintf("- %s (%s)", issue.Title, issue.ID))
+				}
+			}
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n### %s\n\n%s\n", section.title, strings.Join(lines, "\n"))
+		}
+	}
+	if len(issues) == 0 {
+		b.WriteString("\nNo issues were closed under this mission.\n")
+	}
+	return b.String()
+}
+
+// release tags HEAD and, when asked to and a hosting token is configured,
+// pushes the tag and publishes a release for it
+func (m *Manager) release(ctx context.Context, mission *types.Issue, result *Result) {
+	fail := func(reason string) {
+		result.Status = StatusFailed
+		result.Reason = reason
+	}
+
+	commit, err := m.git.ResolveRef(ctx, m.repoPath, "HEAD")
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	result.Commit = commit
+	message := fmt.Sprintf("%s: %s\n\n%s", result.Tag, mission.Title, result.Notes)
+	if err := m.git.CreateTag(ctx, m.repoPath, result.Tag, message, commit); err != nil {
+		fail(err.Error())
+		return
+	}
+
+	result.Status = StatusTagged
+	if !m.publish {
+		return
+	}
+	if !m.hosting.Enabled() {
+		result.Reason = "no git hosting token is configured to publish the release with"
+		return
+	}
+	url, err := m.publishRelease(ctx, mission, result)
+	if err != nil {
+		result.Reason = fmt.Sprintf("tagged, but publishing failed: %v", err)
+		return
+	}
+	result.Status = StatusPublished
+	result.URL = url
+}
+
+// publishRelease pushes the release tag and publishes a release for it.
+// Returns the release URL.
+func (m *Manager) publishRelease(ctx context.Context, mission *types.Issue, result *Result) (string, error) {
+	remoteURL, err := m.git.RemoteURL(ctx, m.repoPath, m.hosting.Remote)
+	if err != nil {
+		return "", err
+	}
+	provider, err := hosting.Open(m.hosting, remoteURL)
+	if err != nil {
+		return "", err
+	}
+	if err := m.git.Push(ctx, m.repoPath, git.PushOptions{
+		Remote:    m.hosting.Remote,
+		Tag:       result.Tag,
+		Token:     provider.Token(),
+		TokenUser: provider.PushUser(),
+		Checks:    m.gitPushChecks(),
+	}); err != nil {
+		return "", err
+	}
+	release, err := provider.CreateRelease(ctx, hosting.NewRelease{
+		Tag:        result.Tag,
+		Name:       fmt.Sprintf("%s: %s", result.Tag, mission.Title),
+		Body:       result.Notes,
+		Draft:      m.draft,
+		Prerelease: strings.Contains(result.Tag, "-"), // Semantic versions like v1.2.0-rc.1
+	})
+	if err != nil {
+		return "", err
+	}
+	return release.URL, nil
+}
+
+// gitPushChecks returns the pre-push checks to run, or nil if they are off
+func (m *Manager) gitPushChecks() *git.PushChecks {
+	if !m.pushChecks.Enabled {
+		return nil
+	}
+	return &git.PushChecks{
+		MaxFileSize:    int64(m.pushChecks.MaxFileSizeKB) * 1024,
+		AllowBinary:    m.pushChecks.AllowBinary,
+		AllowForcePush: m.pushChecks.AllowForcePush,
+		ScanSecrets:    m.pushChecks.ScanSecrets,
+	}
+}
+
+// record comments on the mission and logs a release event
+func (m *Manager) record(ctx context.Context, mission *types.Issue, result *Result) {
+	severity := events.SeverityInfo
+	var comment string
+	switch result.Status {
+	case StatusPublished:
+		comment = fmt.Sprintf("Released %s at %s: %s\n\n%s", result.Tag, shortHash(result.Commit), result.URL, result.Notes)
+	case StatusTagged:
+		comment = fmt.Sprintf("Tagged %s at %s", result.Tag, shortHash(result.Commit))
+		if result.Reason != "" {
+			severity = events.SeverityWarning
+			comment += fmt.Sprintf(" (not published: %s)", result.Reason)
+		}
+		comment += "\n\n" + result.Notes
+	default:
+		severity = events.SeverityWarning
+		comment = fmt.Sprintf("Release %s failed: %s", result.Tag, result.Reason)
+	}
+	if err := m.store.AddComment(ctx, mission.ID, m.actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to comment on %s: %v\n", mission.ID, err)
+	}
+
+	issueIDs := make([]string, len(result.Issues))
+	for i, issue := range result.Issues {
+		issueIDs[i] = issue.ID
+	}
+	event := &events.AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       events.EventTypeRelease,
+		Timestamp:  time.Now(),
+		IssueID:    mission.ID,
+		ExecutorID: m.executorID,
+		Severity:   severity,
+		Message:    strings.SplitN(comment, "\n", 2)[0],
+	}
+	if err := event.SetReleaseData(events.ReleaseData{
+		Tag:    result.Tag,
+		Commit: result.Commit,
+		Issues: issueIDs,
+		Status: result.Status,
+		URL:    result.URL,
+		Reason: result.Reason,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to set release data: %v\n", err)
+	}
+	if err := m.store.StoreAgentEvent(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store release event: %v\n", err)
+	}
+}
+
+// shortHash abbreviates a commit hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
@@ -0,0 +1,202 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestVersion(t *testing.T) {
+	if got := Version([]string{"bug", "release:", Label("v1.2.0"), Label("v2.0.0")}); got != "v1.2.0" {
+		t.Errorf("Version() = %q, want v1.2.0", got)
+	}
+	if got := Version([]string{"backport:release-1.x"}); got != "" {
+		t.Errorf("Version() = %q, want none", got)
+	}
+}
+
+func TestReleaseMission(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	remote := t.TempDir()
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name, message string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(message+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(dir, "add", "-A")
+		run(dir, "commit", "-m", message)
+		return run(dir, "rev-parse", "HEAD")
+	}
+	run(remote, "init", "--bare")
+	run(dir, "init", "--initial-branch=main")
+	run(dir, "config", "user.name", "Test User")
+	run(dir, "config", "user.email", "test@example.com")
+	run(dir, "remote", "add", "origin", remote)
+	commit("a.txt", "initial")
+
+	store := memory.New()
+	newIssue := func(title string, issueType types.IssueType, parent *types.Issue) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: issueType, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if parent != nil {
+			dep := &types.Dependency{IssueID: issue.ID, DependsOnID: parent.ID, Type: types.DepParentChild}
+			if err := store.AddDependency(ctx, dep, "test"); err != nil {
+				t.Fatalf("AddDependency failed: %v", err)
+			}
+		}
+		return issue
+	}
+	closeIssue := func(issue *types.Issue, commit string) {
+		t.Helper()
+		if commit != "" {
+			execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded, CommitHash: commit}
+			if err := store.CreateExecution(ctx, execution); err != nil {
+				t.Fatalf("CreateExecution failed: %v", err)
+			}
+		}
+		if err := store.CloseIssue(ctx, issue.ID, "done", "test"); err != nil {
+			t.Fatalf("CloseIssue failed: %v", err)
+		}
+	}
+
+	mission := newIssue("Widget search", types.TypeEpic, nil)
+	if err := store.AddLabel(ctx, mission.ID, Label("v1.2.0"), "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	feature := newIssue("Search widgets by name", types.TypeFeature, mission)
+	phase := newIssue("Phase 2", types.TypeEpic, mission)
+	fix := newIssue("Fix empty search results", types.TypeBug, phase)
+	chore := newIssue("Update docs", types.TypeChore, phase)
+	open := newIssue("Not done", types.TypeTask, phase)
+	discovered := newIssue("Found along the way", types.TypeTask, nil)
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: discovered.ID, DependsOnID: mission.ID, Type: types.DepDiscoveredFrom}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	var released map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/widgets/releases" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&released); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"tag_name": "v1.2.0", "html_url": "https://github.com/acme/widgets/releases/tag/v1.2.0"}`))
+	}))
+	defer server.Close()
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+	hostingCfg := config.DefaultHostingConfig()
+	hostingCfg.Provider = config.HostingGitHub
+	hostingCfg.GitHub = config.GitHubConfig{Token: "ghp_secret", Repo: "acme/widgets", APIURL: server.URL}
+	manager, err := New(Config{Store: store, Git: gitOps, RepoPath: dir, Hosting: hostingCfg, Publish: true, Actor: "test"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := manager.ReleaseMission(ctx, mission.ID); err == nil {
+		t.Error("expected an error releasing an open mission")
+	}
+	if result, err := manager.ReleaseMission(ctx, feature.ID); err != nil || result != nil {
+		t.Errorf("ReleaseMission(unlabelled) = %v, %v; want nothing to do", result, err)
+	}
+
+	closeIssue(feature, commit("b.txt", "add search"))
+	run(dir, "checkout", "-q", "-b", "unmerged")
+	unmerged := commit("c.txt", "fix search")
+	run(dir, "checkout", "-q", "main")
+	closeIssue(fix, unmerged)
+	closeIssue(chore, "")
+	closeIssue(discovered, "")
+	closeIssue(mission, "")
+
+	if _, err := manager.ReleaseMission(ctx, mission.ID); err == nil || !strings.Contains(err.Error(), fix.ID) {
+		t.Errorf("ReleaseMission() error = %v, want %s's unmerged work named", err, fix.ID)
+	}
+	run(dir, "merge", "-q", "unmerged")
+
+	result, err := manager.ReleaseMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("ReleaseMission() error = %v", err)
+	}
+	if result.Status != StatusPublished || result.URL != "https://github.com/acme/widgets/releases/tag/v1.2.0" {
+		t.Fatalf("result = %+v, want a published release", result)
+	}
+	if len(result.Issues) != 3 {
+		t.Errorf("release notes cover %d issues, want the 3 closed children (not %s, %s or %s)",
+			len(result.Issues), phase.ID, open.ID, discovered.ID)
+	}
+	for _, want := range []string{
+		"## v1.2.0",
+		"### Features\n\n- Search widgets by name (" + feature.ID + ")",
+		"### Bug Fixes\n\n- Fix empty search results (" + fix.ID + ")",
+		"### Other Changes\n\n- Update docs (" + chore.ID + ")",
+	} {
+		if !strings.Contains(result.Notes, want) {
+			t.Errorf("notes missing %q:\n%s", want, result.Notes)
+		}
+	}
+	if released["tag_name"] != "v1.2.0" || released["body"] != result.Notes || released["prerelease"] != false {
+		t.Errorf("release request = %v", released)
+	}
+	if kind := run(dir, "cat-file", "-t", "v1.2.0"); kind != "tag" {
+		t.Errorf("v1.2.0 is a %s, want an annotated tag", kind)
+	}
+	if got := run(remote, "rev-parse", "v1.2.0^{commit}"); got != run(dir, "rev-parse", "HEAD") {
+		t.Errorf("pushed tag at %s, want HEAD", got)
+	}
+
+	if again, err := manager.ReleaseMission(ctx, mission.ID); err != nil || again.Status != StatusSkipped {
+		t.Errorf("second release = %+v, %v; want it skipped", again, err)
+	}
+
+	evts, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: mission.ID, Type: events.EventTypeRelease})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(evts) != 1 {
+		t.Fatalf("got %d release events, want 1", len(evts))
+	}
+	if data, err := evts[0].GetReleaseData(); err != nil || data.Status != StatusPublished || len(data.Issues) != 3 {
+		t.Errorf("event data = %+v, %v", data, err)
+	}
+	comments, err := store.GetComments(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0].Body, result.URL) {
+		t.Errorf("comments = %+v, want the release noted on the mission", comments)
+	}
+}
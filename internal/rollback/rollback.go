@@ -0,0 +1,226 @@
+// Package rollback undoes the commit an execution produced when it turns out
+// to have broken the build: it reverts the commit, files a follow-up issue to
+// redo the work, and records the linkage on the execution, the original issue
+// and the event log.
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Config configures a Manager
+type Config struct {
+	Store      storage.Storage
+	Git        git.GitOperations
+	RepoPath   string // Repository the commits were made in
+	Actor      string // Recorded as the creator of follow-up issues and comments
+	ExecutorID string // Recorded on rollback events (optional)
+}
+
+// Manager rolls back execution commits
+type Manager struct {
+	store      storage.Storage
+	git        git.GitOperations
+	repoPath   string
+	actor      string
+	executorID string
+}
+
+// Result describes a rollback
+type Result struct {
+	Execution       *types.Execution // With RevertCommit and RollbackIssueID set
+	RevertCommit    string
+	FollowUpIssueID string // Empty if filing the follow-up issue failed
+}
+
+// New creates a rollback manager
+func New(cfg Config) (*Manager, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	if cfg.Git == nil {
+		return nil, fmt.Errorf("git operations are required")
+	}
+	if cfg.RepoPath == "" {
+		return nil, fmt.Errorf("repository path is required")
+	}
+	if cfg.Actor == "" {
+		cfg.Actor = "vc-rollback"
+	}
+	return &Manager{
+		store:      cfg.Store,
+		git:        cfg.Git,
+		repoPath:   cfg.RepoPath,
+		actor:      cfg.Actor,
+		executorID: cfg.ExecutorID,
+	}, nil
+}
+
+// RollbackExecution reverts the commit an execution produced.
+// If the commit was reverted but recording the follow-up failed, both the
+// result and an error are returned: the revert itself stands.
+func (m *Manager) RollbackExecution(ctx context.Context, executionID int64, reason string) (*Result, error) {
+	exec, err := m.store.GetExecution(ctx, executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution %d: %w", executionID, err)
+	}
+	if exec == nil {
+		return nil, fmt.Errorf("execution %d not found", executionID)
+	}
+	return m.rollback(ctx, exec, reason)
+}
+
+// RollbackCommit reverts a commit made by an execution, looked up by hash
+// (a full hash, as recorded on the execution)
+func (m *Manager) RollbackCommit(ctx context.Context, commitHash, reason string) (*Result, error) {
+	execs, err := m.store.ListExecutions(ctx, types.ExecutionFilter{CommitHash: commitHash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the execution of commit %s: %w", commitHash, err)
+	}
+	if len(execs) == 0 {
+		return nil, fmt.Errorf("no execution produced commit %s", commitHash)
+	}
+	return m.rollback(ctx, execs[0], reason)
+}
+
+func (m *Manager) rollback(ctx context.Context, exec *types.Execution, reason string) (*Result, error) {
+	if exec.CommitHash == "" {
+		return nil, fmt.Errorf("execution %d made no commit", exec.ID)
+	}
+	if exec.IsRolledBack() {
+		return nil, fmt.Errorf("execution %d was already rolled back by %s", exec.ID, exec.RevertCommit)
+	}
+	if reason == "" {
+		reason = "rolled back manually"
+	}
+	onHead, err := m.git.IsAncestor(ctx, m.repoPath, exec.CommitHash)
+	if err != nil {
+		return nil, err
+	}
+	if !onHead {
+		return nil, fmt.Errorf("commit %s is not in the checked out branch's history", shortHash(exec.CommitHash))
+	}
+
+	issue, err := m.store.GetIssue(ctx, exec.IssueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", exec.IssueID, err)
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s not found", exec.IssueID)
+	}
+
+	message := fmt.Sprintf("Revert %s work on %s: %s\n\n%s\n\nThis reverts commit %s (execution %d).",
+		shortHash(exec.CommitHash), issue.ID, issue.Title, reason, exec.CommitHash, exec.ID)
+	revertCommit, err := m.git.Revert(ctx, m.repoPath, []string{exec.CommitHash}, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revert %s: %w", shortHash(exec.CommitHash), err)
+	}
+
+	// The revert stands from here on; record as much of it as possible
+	result := &Result{Execution: exec, RevertCommit: revertCommit}
+	var errs []string
+	followUp, err := m.fileFollowUp(ctx, issue, exec, revertCommit, reason)
+	if err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		result.FollowUpIssueID = followUp
+	}
+
+	exec.RevertCommit = revertCommit
+	exec.RollbackIssueID = result.FollowUpIssueID
+	if err := m.store.UpdateExecution(ctx, exec); err != nil {
+		errs = append(errs, fmt.Sprintf("failed to record the rollback on execution %d: %v", exec.ID, err))
+	}
+
+	comment := fmt.Sprintf("Commit %s from execution %d was rolled back by %s: %s",
+		shortHash(exec.CommitHash), exec.ID, shortHash(revertCommit), reason)
+	if result.FollowUpIssueID != "" {
+		comment += fmt.Sprintf("\nFollow-up issue: %s", result.FollowUpIssueID)
+	}
+	if err := m.store.AddComment(ctx, issue.ID, m.actor, comment); err != nil {
+		errs = append(errs, fmt.Sprintf("failed to comment on %s: %v", issue.ID, err))
+	}
+
+	if err := m.recordEvent(ctx, exec, revertCommit, result.FollowUpIssueID, reason); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return result, fmt.Errorf("reverted %s as %s, but: %s",
+			shortHash(exec.CommitHash), shortHash(revertCommit), strings.Join(errs, "; "))
+	}
+	return result, nil
+}
+
+// fileFollowUp files an issue to redo the reverted work, discovered from the
+// original issue
+func (m *Manager) fileFollowUp(ctx context.Context, issue *types.Issue, exec *types.Execution, revertCommit, reason string) (string, error) {
+	followUp := &types.Issue{
+		Title: fmt.Sprintf("Redo %s after rollback: %s", issue.ID, issue.Title),
+		Description: fmt.Sprintf("Commit %s, made for %s by execution %d, was reverted by %s.\n\nReason: %s\n\n"+
+			"Original description:\n%s",
+			exec.CommitHash, issue.ID, exec.ID, revertCommit, reason, issue.Description),
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+		Status:             types.StatusOpen,
+		Priority:           issue.Priority,
+		IssueType:          types.TypeBug,
+	}
+	if followUp.AcceptanceCriteria == "" {
+		followUp.AcceptanceCriteria = fmt.Sprintf("The work from %s is redone and all quality gates pass", issue.ID)
+	}
+	if err := m.store.CreateIssue(ctx, followUp, m.actor); err != nil {
+		return "", fmt.Errorf("failed to file follow-up issue: %w", err)
+	}
+
+	dep := &types.Dependency{
+		IssueID:     followUp.ID,
+		DependsOnID: issue.ID,
+		Type:        types.DepDiscoveredFrom,
+	}
+	if err := m.store.AddDependency(ctx, dep, m.actor); err != nil {
+		return followUp.ID, fmt.Errorf("failed to link follow-up issue %s to %s: %w", followUp.ID, issue.ID, err)
+	}
+	return followUp.ID, nil
+}
+
+func (m *Manager) recordEvent(ctx context.Context, exec *types.Execution, revertCommit, followUpID, reason string) error {
+	event := &events.AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       events.EventTypeCommitRolledBack,
+		Timestamp:  time.Now(),
+		IssueID:    exec.IssueID,
+		ExecutorID: m.executorID,
+		Severity:   events.SeverityWarning,
+		Message:    fmt.Sprintf("Rolled back commit %s: %s", shortHash(exec.CommitHash), reason),
+	}
+	if err := event.SetRollbackData(events.RollbackData{
+		ExecutionID:     exec.ID,
+		Commit:          exec.CommitHash,
+		RevertCommit:    revertCommit,
+		FollowUpIssueID: followUpID,
+		Reason:          reason,
+	}); err != nil {
+		return err
+	}
+	if err := m.store.StoreAgentEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to store rollback event: %w", err)
+	}
+	return nil
+}
+
+// shortHash abbreviates a commit hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
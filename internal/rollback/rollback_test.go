@@ -0,0 +1,140 @@
+package rollback
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestRollbackExecution(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name, content, message string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", "-A")
+		run("commit", "-m", message)
+		return run("rev-parse", "HEAD")
+	}
+	run("init", "--initial-branch=main")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+	commit("README.md", "# Test\n", "initial")
+	broken := commit("main.go", "package main\n\nfunc main() { broken }\n", "vc-1: add main")
+
+	store := memory.New()
+	issue := &types.Issue{
+		Title:              "Add main",
+		Description:        "Add an entry point",
+		AcceptanceCriteria: "main.go builds",
+		Status:             types.StatusOpen,
+		Priority:           1,
+		IssueType:          types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionSucceeded, CommitHash: broken}
+	if err := store.CreateExecution(ctx, execution); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+	noCommit := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionIncomplete}
+	if err := store.CreateExecution(ctx, noCommit); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+
+	gitOps, err := git.NewGit(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create Git instance: %v", err)
+	}
+	manager, err := New(Config{Store: store, Git: gitOps, RepoPath: dir, Actor: "test"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := manager.RollbackExecution(ctx, noCommit.ID, "broken"); err == nil {
+		t.Error("expected rolling back an execution without a commit to fail")
+	}
+	if _, err := manager.RollbackCommit(ctx, "0000000000000000000000000000000000000000", "broken"); err == nil {
+		t.Error("expected rolling back an unknown commit to fail")
+	}
+
+	result, err := manager.RollbackCommit(ctx, broken, "baseline build failed")
+	if err != nil {
+		t.Fatalf("RollbackCommit failed: %v", err)
+	}
+	if head := run("rev-parse", "HEAD"); head != result.RevertCommit {
+		t.Errorf("HEAD = %s, want the revert commit %s", head, result.RevertCommit)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); !os.IsNotExist(err) {
+		t.Errorf("main.go should be gone after the rollback, stat error: %v", err)
+	}
+
+	// The execution links the revert and the follow-up issue
+	got, err := store.GetExecution(ctx, execution.ID)
+	if err != nil {
+		t.Fatalf("GetExecution failed: %v", err)
+	}
+	if got.RevertCommit != result.RevertCommit || got.RollbackIssueID == "" || got.RollbackIssueID != result.FollowUpIssueID {
+		t.Errorf("execution not linked to the rollback: %+v", got)
+	}
+
+	followUp, err := store.GetIssue(ctx, result.FollowUpIssueID)
+	if err != nil || followUp == nil {
+		t.Fatalf("follow-up issue %s not found: %v", result.FollowUpIssueID, err)
+	}
+	if followUp.IssueType != types.TypeBug || followUp.Status != types.StatusOpen || !strings.Contains(followUp.Description, "baseline build failed") {
+		t.Errorf("unexpected follow-up issue: %+v", followUp)
+	}
+	deps, err := store.GetDependencyRecords(ctx, followUp.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != issue.ID || deps[0].Type != types.DepDiscoveredFrom {
+		t.Errorf("follow-up issue should be discovered from %s: %+v", issue.ID, deps)
+	}
+
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0].Body, result.FollowUpIssueID) {
+		t.Errorf("original issue should get a comment naming the follow-up: %+v", comments)
+	}
+
+	evs, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeCommitRolledBack})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(evs) != 1 {
+		t.Fatalf("expected 1 rollback event, got %d", len(evs))
+	}
+	data, err := evs[0].GetRollbackData()
+	if err != nil || data.ExecutionID != execution.ID || data.Commit != broken || data.RevertCommit != result.RevertCommit {
+		t.Errorf("unexpected rollback event data: %+v, %v", data, err)
+	}
+
+	if _, err := manager.RollbackExecution(ctx, execution.ID, "again"); err == nil {
+		t.Error("expected rolling back twice to fail")
+	}
+}
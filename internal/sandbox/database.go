@@ -94,7 +94,7 @@ func initSandboxDB(ctx context.Context, sandboxPath, missionID, parentDBPath str
 
 // storeSandboxMetadata creates a metadata table and stores sandbox provenance information
 func storeSandboxMetadata(ctx context.Context, dbPath, missionID, parentDBPath string) error {
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", storage.DatabaseURI(dbPath))
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
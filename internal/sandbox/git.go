@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // createWorktree creates a git worktree for the sandbox.
@@ -393,6 +394,58 @@ func PruneWorktrees(ctx context.Context, parentRepo string) error {
 	return nil
 }
 
+// RemoveStaleSandboxes removes the sandboxes under sandboxRoot that haven't
+// been modified for olderThan, except those of missions inUse reports as
+// still being worked on, and returns the paths removed. It works from disk
+// rather than a Manager's tracking, so it also finds sandboxes left behind by
+// executors that exited or crashed.
+func RemoveStaleSandboxes(ctx context.Context, sandboxRoot, parentRepo string, olderThan time.Duration, inUse func(missionID string) bool) ([]string, error) {
+	entries, err := os.ReadDir(sandboxRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // No sandbox directory yet
+		}
+		return nil, fmt.Errorf("failed to read sandbox root: %w", err)
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var removed []string
+	var lastErr error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if missionID := sandboxMissionID(entry.Name()); missionID != "" && inUse(missionID) {
+			continue
+		}
+		path := filepath.Join(sandboxRoot, entry.Name())
+		if err := removeWorktree(ctx, parentRepo, path); err != nil {
+			lastErr = fmt.Errorf("failed to remove sandbox %s: %w", path, err)
+			continue
+		}
+		removed = append(removed, path)
+	}
+	return removed, lastErr
+}
+
+// sandboxMissionID returns the mission a sandbox directory was created for,
+// from its name: mission-<id> or sandbox-<id>-<unix time>
+func sandboxMissionID(name string) string {
+	if id, ok := strings.CutPrefix(name, "mission-"); ok {
+		return id
+	}
+	if rest, ok := strings.CutPrefix(name, "sandbox-"); ok {
+		if i := strings.LastIndex(rest, "-"); i > 0 {
+			return rest[:i]
+		}
+	}
+	return ""
+}
+
 // validateGitRepo checks if a directory is a git repository.
 // Returns an error if the path doesn't exist or is not a git repo.
 func validateGitRepo(path string) error {
@@ -0,0 +1,435 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Worktree pool defaults
+const (
+	DefaultPoolMaxWorktrees = 4
+	DefaultPoolStaleAfter   = 2 * time.Hour
+)
+
+// poolLockPrefix starts the lock reason of worktrees assigned by a pool
+const poolLockPrefix = "vc-pool"
+
+// ErrPoolExhausted is returned by Acquire when every worktree is assigned
+// and the pool is at its maximum size
+var ErrPoolExhausted = fmt.Errorf("worktree pool exhausted")
+
+// WorktreePoolConfig configures a WorktreePool
+type WorktreePoolConfig struct {
+	// ParentRepo is the repository the worktrees belong to
+	ParentRepo string
+
+	// Root is the directory the worktrees are created in
+	// Default: <ParentRepo>/.sandboxes/pool
+	Root string
+
+	// MaxWorktrees is the most worktrees the pool keeps
+	// Default: 4
+	MaxWorktrees int
+
+	// StaleAfter is how long a worktree can stay assigned without a Touch
+	// before GC takes it back, e.g. because its executor crashed
+	// Default: 2 hours
+	StaleAfter time.Duration
+}
+
+// PooledWorktree is a worktree in a pool
+type PooledWorktree struct {
+	Path       string
+	Owner      string    // Execution (or other user) it's assigned to; empty when idle
+	AssignedAt time.Time // When it was assigned, or last touched
+}
+
+// Idle reports whether the worktree is free to assign
+func (w *PooledWorktree) Idle() bool {
+	return w.Owner == ""
+}
+
+// WorktreePool manages a bounded set of detached git worktrees of one
+// repository, so executions can run side by side without a full clone
+// each. Worktrees are created on demand, assigned to one owner at a time,
+// cleaned when released and reused by the next owner.
+//
+// Assignments are recorded as git worktree locks, so they survive restarts,
+// are visible to 'git worktree list' and are safe between processes: locking
+// is what claims a worktree.
+type WorktreePool struct {
+	cfg WorktreePoolConfig
+	mu  sync.Mutex // Serializes Acquire, Touch, Release and GC within the process
+}
+
+// NewWorktreePool creates a pool, adopting worktrees left in its root by
+// earlier runs
+func NewWorktreePool(cfg WorktreePoolConfig) (*WorktreePool, error) {
+	if err := validateGitRepo(cfg.ParentRepo); err != nil {
+		return nil, fmt.Errorf("parent repo validation failed: %w", err)
+	}
+	if cfg.Root == "" {
+		cfg.Root = filepath.Join(cfg.ParentRepo, ".sandboxes", "pool")
+	}
+	if cfg.MaxWorktrees == 0 {
+		cfg.MaxWorktrees = DefaultPoolMaxWorktrees
+	}
+	if cfg.MaxWorktrees < 1 {
+		return nil, fmt.Errorf("max worktrees must be at least 1 (got %d)", cfg.MaxWorktrees)
+	}
+	if cfg.StaleAfter == 0 {
+		cfg.StaleAfter = DefaultPoolStaleAfter
+	}
+	if err := os.MkdirAll(cfg.Root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create worktree pool root: %w", err)
+	}
+	// git reports resolved absolute paths; compare against the same
+	root, err := filepath.Abs(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, fmt.Errorf("failed to resolve worktree pool root: %w", err)
+	}
+	cfg.Root = root
+	return &WorktreePool{cfg: cfg}, nil
+}
+
+// Root returns the directory the pool's worktrees live in
+func (p *WorktreePool) Root() string {
+	return p.cfg.Root
+}
+
+// List returns the pool's worktrees, in path order
+func (p *WorktreePool) List(ctx context.Context) ([]*PooledWorktree, error) {
+	entries, err := p.listGitWorktrees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var worktrees []*PooledWorktree
+	for _, entry := range entries {
+		if entry.inPool {
+			worktrees = append(worktrees, entry.worktree)
+		}
+	}
+	return worktrees, nil
+}
+
+// Acquire assigns a worktree to owner, checked out (detached) at ref: an
+// idle one, cleaned, or a new one if the pool isn't full. Returns
+// ErrPoolExhausted if every worktree is assigned.
+func (p *WorktreePool) Acquire(ctx context.Context, owner, ref string) (*PooledWorktree, error) {
+	if owner == "" || strings.ContainsAny(owner, " \n") {
+		return nil, fmt.Errorf("invalid worktree owner %q", owner)
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	worktrees, err := p.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, wt := range worktrees {
+		if !wt.Idle() {
+			continue
+		}
+		if err := p.lock(ctx, wt, owner); err != nil {
+			continue // Claimed by another process in the meantime
+		}
+		if err := p.reset(ctx, wt.Path, ref); err != nil {
+			_ = p.unlock(ctx, wt)
+			return nil, fmt.Errorf("failed to recycle worktree %s: %w", wt.Path, err)
+		}
+		return wt, nil
+	}
+
+	if len(worktrees) >= p.cfg.MaxWorktrees {
+		return nil, ErrPoolExhausted
+	}
+	return p.create(ctx, owner, ref, worktrees)
+}
+
+// Touch renews a worktree's assignment, so GC doesn't take it back while
+// its owner is still working. The lock is rewritten in place: unlocking and
+// locking again would let another process claim or reclaim the worktree in
+// between. Fails if the worktree is no longer locked for its owner.
+func (p *WorktreePool) Touch(ctx context.Context, wt *PooledWorktree) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if wt.Idle() {
+		return fmt.Errorf("worktree %s is not assigned", wt.Path)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--absolute-git-dir")
+	cmd.Dir = wt.Path
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to find git directory of worktree %s: %w", wt.Path, err)
+	}
+	// git keeps a worktree's lock reason in the "locked" file of its git directory
+	lockFile := filepath.Join(strings.TrimSpace(string(output)), "locked")
+	reason, err := os.ReadFile(lockFile)
+	if err != nil {
+		return fmt.Errorf("worktree %s is no longer locked: %w", wt.Path, err)
+	}
+	if owner, _, ok := parseLockReason(string(reason)); !ok || owner != wt.Owner {
+		return fmt.Errorf("worktree %s is no longer assigned to %s", wt.Path, wt.Owner)
+	}
+
+	now := time.Now()
+	tmp := lockFile + ".tmp"
+	if err := os.WriteFile(tmp, []byte(lockReason(wt.Owner, now)), 0o644); err != nil {
+		return fmt.Errorf("failed to renew lock on worktree %s: %w", wt.Path, err)
+	}
+	if err := os.Rename(tmp, lockFile); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to renew lock on worktree %s: %w", wt.Path, err)
+	}
+	wt.AssignedAt = now
+	return nil
+}
+
+// Release cleans a worktree (discarding all changes, including untracked
+// and ignored files) and returns it to the pool. A worktree that can't be
+// cleaned is removed instead.
+func (p *WorktreePool) Release(ctx context.Context, wt *PooledWorktree) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.release(ctx, wt)
+}
+
+// release is Release for callers holding p.mu
+func (p *WorktreePool) release(ctx context.Context, wt *PooledWorktree) error {
+	if err := p.reset(ctx, wt.Path, "HEAD"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to clean worktree %s, removing it: %v\n", wt.Path, err)
+		_ = p.unlock(ctx, wt)
+		return removeWorktree(ctx, p.cfg.ParentRepo, wt.Path)
+	}
+	return p.unlock(ctx, wt)
+}
+
+// GC takes back worktrees assigned for longer than StaleAfter, removes
+// directories in the pool's root that aren't worktrees (e.g. from an
+// interrupted cleanup) and idle worktrees beyond MaxWorktrees, and prunes
+// git's records of deleted worktrees. Returns how many worktrees were
+// reclaimed or removed.
+func (p *WorktreePool) GC(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := PruneWorktrees(ctx, p.cfg.ParentRepo); err != nil {
+		return 0, err
+	}
+	entries, err := p.listGitWorktrees(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	registered := make(map[string]bool)
+	var worktrees []*PooledWorktree
+	for _, entry := range entries {
+		registered[entry.worktree.Path] = true
+		if entry.inPool {
+			worktrees = append(worktrees, entry.worktree)
+		}
+	}
+
+	// Stale assignments
+	for _, wt := range worktrees {
+		if !wt.Idle() && time.Since(wt.AssignedAt) > p.cfg.StaleAfter {
+			fmt.Fprintf(os.Stderr, "Reclaiming worktree %s assigned to %s since %s\n",
+				wt.Path, wt.Owner, wt.AssignedAt.Format(time.RFC3339))
+			if err := p.release(ctx, wt); err != nil {
+				return count, fmt.Errorf("failed to reclaim worktree %s: %w", wt.Path, err)
+			}
+			wt.Owner = ""
+			count++
+		}
+	}
+
+	// Leftover directories
+	dirEntries, err := os.ReadDir(p.cfg.Root)
+	if err != nil {
+		return count, fmt.Errorf("failed to read worktree pool root: %w", err)
+	}
+	for _, dirEntry := range dirEntries {
+		path := filepath.Join(p.cfg.Root, dirEntry.Name())
+		if !registered[path] {
+			if err := os.RemoveAll(path); err != nil {
+				return count, fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+			count++
+		}
+	}
+
+	// Idle worktrees over the limit (e.g. after lowering it)
+	excess := len(worktrees) - p.cfg.MaxWorktrees
+	for i := len(worktrees) - 1; i >= 0 && excess > 0; i-- {
+		if worktrees[i].Idle() {
+			if err := removeWorktree(ctx, p.cfg.ParentRepo, worktrees[i].Path); err != nil {
+				return count, err
+			}
+			excess--
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// RemoveAll removes every idle worktree in the pool
+func (p *WorktreePool) RemoveAll(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	worktrees, err := p.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, wt := range worktrees {
+		if wt.Idle() {
+			if err := removeWorktree(ctx, p.cfg.ParentRepo, wt.Path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// create adds a worktree named after the first free wt-<n>, assigned to owner
+func (p *WorktreePool) create(ctx context.Context, owner, ref string, existing []*PooledWorktree) (*PooledWorktree, error) {
+	taken := make(map[string]bool)
+	for _, wt := range existing {
+		taken[wt.Path] = true
+	}
+	var path string
+	for n := 1; ; n++ {
+		path = filepath.Join(p.cfg.Root, fmt.Sprintf("wt-%d", n))
+		if taken[path] {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue // Leftover directory, removed by GC
+		}
+		break
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "worktree", "add", "--detach", "--lock",
+		"--reason", lockReason(owner, time.Now()), path, ref)
+	cmd.Dir = p.cfg.ParentRepo
+	if output, err := cmd.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(path) // Best-effort cleanup
+		return nil, fmt.Errorf("git worktree add failed: %w (output: %s)", err, string(output))
+	}
+	return &PooledWorktree{Path: path, Owner: owner, AssignedAt: time.Now()}, nil
+}
+
+// reset checks out ref in a worktree, discarding all changes
+func (p *WorktreePool) reset(ctx context.Context, path, ref string) error {
+	for _, args := range [][]string{
+		{"checkout", "--force", "--detach", ref},
+		{"clean", "-ffdx"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = path
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s failed: %w (output: %s)", args[0], err, string(output))
+		}
+	}
+	return nil
+}
+
+func (p *WorktreePool) lock(ctx context.Context, wt *PooledWorktree, owner string) error {
+	now := time.Now()
+	cmd := exec.CommandContext(ctx, "git", "worktree", "lock", "--reason", lockReason(owner, now), wt.Path)
+	cmd.Dir = p.cfg.ParentRepo
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree lock failed: %w (output: %s)", err, string(output))
+	}
+	wt.Owner = owner
+	wt.AssignedAt = now
+	return nil
+}
+
+func (p *WorktreePool) unlock(ctx context.Context, wt *PooledWorktree) error {
+	cmd := exec.CommandContext(ctx, "git", "worktree", "unlock", wt.Path)
+	cmd.Dir = p.cfg.ParentRepo
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree unlock failed: %w (output: %s)", err, string(output))
+	}
+	wt.Owner = ""
+	wt.AssignedAt = time.Time{}
+	return nil
+}
+
+// gitWorktreeEntry is a worktree as 'git worktree list' reports it
+type gitWorktreeEntry struct {
+	worktree *PooledWorktree
+	inPool   bool // In the pool's root
+}
+
+// listGitWorktrees lists the parent repository's worktrees
+func (p *WorktreePool) listGitWorktrees(ctx context.Context) ([]gitWorktreeEntry, error) {
+	cmd := exec.CommandContext(ctx, "git", "worktree", "list", "--porcelain")
+	cmd.Dir = p.cfg.ParentRepo
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git worktree list failed: %w", err)
+	}
+
+	var entries []gitWorktreeEntry
+	var current *gitWorktreeEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			path := strings.TrimPrefix(line, "worktree ")
+			entries = append(entries, gitWorktreeEntry{
+				worktree: &PooledWorktree{Path: path},
+				inPool:   filepath.Dir(path) == p.cfg.Root,
+			})
+			current = &entries[len(entries)-1]
+		case strings.HasPrefix(line, "locked") && current != nil:
+			reason := strings.TrimSpace(strings.TrimPrefix(line, "locked"))
+			owner, since, ok := parseLockReason(reason)
+			if !ok {
+				// Locked by someone else: never hand it out
+				owner, since = "(locked: "+reason+")", time.Now()
+			}
+			current.worktree.Owner = owner
+			current.worktree.AssignedAt = since
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].worktree.Path < entries[j].worktree.Path })
+	return entries, nil
+}
+
+// lockReason records an assignment as a worktree lock reason
+func lockReason(owner string, since time.Time) string {
+	return fmt.Sprintf("%s %s %s", poolLockPrefix, owner, since.UTC().Format(time.RFC3339))
+}
+
+// parseLockReason reads an assignment back from a lock reason
+func parseLockReason(reason string) (owner string, since time.Time, ok bool) {
+	fields := strings.Fields(reason)
+	if len(fields) != 3 || fields[0] != poolLockPrefix {
+		return "", time.Time{}, false
+	}
+	since, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return fields[1], since, true
+}
@@ -0,0 +1,136 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWorktreePool(t *testing.T) {
+	ctx := context.Background()
+	repo, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	pool, err := NewWorktreePool(WorktreePoolConfig{ParentRepo: repo, Root: t.TempDir(), MaxWorktrees: 2})
+	if err != nil {
+		t.Fatalf("NewWorktreePool failed: %v", err)
+	}
+
+	first, err := pool.Acquire(ctx, "exec-1", "main")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	second, err := pool.Acquire(ctx, "exec-2", "main")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if first.Path == second.Path {
+		t.Fatalf("both owners got %s", first.Path)
+	}
+	if _, err := pool.Acquire(ctx, "exec-3", "main"); !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("Acquire on a full pool = %v, want ErrPoolExhausted", err)
+	}
+
+	// Assignments are recorded in git, so a second pool (another process,
+	// or a restart) sees them
+	other, err := NewWorktreePool(WorktreePoolConfig{ParentRepo: repo, Root: pool.Root(), MaxWorktrees: 2})
+	if err != nil {
+		t.Fatalf("NewWorktreePool failed: %v", err)
+	}
+	listed, err := other.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Owner != "exec-1" || listed[1].Owner != "exec-2" {
+		t.Fatalf("List() = %+v, want worktrees assigned to exec-1 and exec-2", listed)
+	}
+
+	// Released worktrees are cleaned and recycled
+	if err := os.WriteFile(filepath.Join(first.Path, "README.md"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(first.Path, "scratch.txt"), []byte("scratch"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Release(ctx, first); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	recycled, err := pool.Acquire(ctx, "exec-3", "main")
+	if err != nil {
+		t.Fatalf("Acquire after Release failed: %v", err)
+	}
+	if recycled.Path != first.Path || recycled.Owner != "exec-3" {
+		t.Errorf("Acquire() = %+v, want %s recycled for exec-3", recycled, first.Path)
+	}
+	status, err := getGitStatus(ctx, recycled.Path)
+	if err != nil {
+		t.Fatalf("getGitStatus failed: %v", err)
+	}
+	if status != "" {
+		t.Errorf("recycled worktree is not clean:\n%s", status)
+	}
+
+	// GC takes back stale assignments and removes leftover directories
+	if err := os.MkdirAll(filepath.Join(pool.Root(), "leftover"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	stale, err := NewWorktreePool(WorktreePoolConfig{ParentRepo: repo, Root: pool.Root(), MaxWorktrees: 2, StaleAfter: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("NewWorktreePool failed: %v", err)
+	}
+	if err := pool.Touch(ctx, second); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+	listed, _ = pool.List(ctx)
+	for _, wt := range listed {
+		if wt.Path == second.Path && wt.Owner != second.Owner {
+			t.Errorf("worktree %s owned by %q after Touch, want %q", wt.Path, wt.Owner, second.Owner)
+		}
+	}
+	n, err := stale.GC(ctx)
+	if err != nil {
+		t.Fatalf("GC failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("GC() = %d, want 2 reclaimed worktrees and 1 removed directory", n)
+	}
+	if _, err := os.Stat(filepath.Join(pool.Root(), "leftover")); !os.IsNotExist(err) {
+		t.Errorf("leftover directory not removed: %v", err)
+	}
+	listed, _ = pool.List(ctx)
+	for _, wt := range listed {
+		if !wt.Idle() {
+			t.Errorf("worktree %s still assigned to %s after GC", wt.Path, wt.Owner)
+		}
+	}
+	if err := pool.Touch(ctx, second); err == nil {
+		t.Error("expected Touch on a reclaimed worktree to fail")
+	}
+
+	// Lowering the limit lets GC shrink the pool; worktrees locked by
+	// anything other than the pool are left alone
+	cmd := exec.Command("git", "worktree", "lock", "--reason", "manual", listed[0].Path)
+	cmd.Dir = repo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git worktree lock failed: %v\n%s", err, out)
+	}
+	smaller, err := NewWorktreePool(WorktreePoolConfig{ParentRepo: repo, Root: pool.Root(), MaxWorktrees: 1})
+	if err != nil {
+		t.Fatalf("NewWorktreePool failed: %v", err)
+	}
+	if _, err := smaller.GC(ctx); err != nil {
+		t.Fatalf("GC failed: %v", err)
+	}
+	listed, _ = smaller.List(ctx)
+	if len(listed) != 1 || !strings.Contains(listed[0].Owner, "manual") {
+		t.Errorf("List() = %+v, want only the manually locked worktree", listed)
+	}
+	if _, err := smaller.Acquire(ctx, "exec-4", "main"); !errors.Is(err, ErrPoolExhausted) {
+		t.Errorf("Acquire of a manually locked worktree = %v, want ErrPoolExhausted", err)
+	}
+}
@@ -0,0 +1,223 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearch bounds how far ahead Next looks for a matching time, so an
+// expression that can never match (e.g. "0 0 30 2 *") fails instead of
+// searching forever
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// macros are the named schedules accepted in place of five fields
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field describes one of the five fields of a cron expression
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int // Names accepted for values, e.g. "jan"
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Sunday is 0 or 7
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Cron is a parsed cron expression: five fields (minute, hour, day of
+// month, month, day of week), each "*", a value, a range "a-b", a list
+// "a,b" or a step "*/n" or "a-b/n", or one of the macros @hourly, @daily,
+// @weekly, @monthly and @yearly. As in cron, when both day fields are
+// restricted a time matches if either does.
+type Cron struct {
+	expr                         string
+	minute, hour, dom, month     uint64 // Bit i is set if value i matches
+	dow                          uint64
+	domRestricted, dowRestricted bool
+}
+
+// ParseCron parses a cron expression
+func ParseCron(expr string) (*Cron, error) {
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := macros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron macro %q (use @hourly, @daily, @weekly, @monthly or @yearly)", spec)
+		}
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(fields))
+	}
+
+	c := &Cron{expr: strings.TrimSpace(expr)}
+	var err error
+	if c.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if c.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if c.dom, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if c.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if c.dow, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domRestricted = !strings.HasPrefix(fields[2], "*")
+	c.dowRestricted = !strings.HasPrefix(fields[4], "*")
+
+	if c.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches", expr)
+	}
+	return c, nil
+}
+
+// String returns the expression as written
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Next returns the first minute after t that the expression matches, in
+// t's location, or the zero time if there is none within five years
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = forward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = nextHour(t)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// nextHour returns the start of the hour after t's. It adds elapsed time
+// rather than calling time.Date, which normalizes a time skipped by a
+// daylight saving change backwards.
+func nextHour(t time.Time) time.Time {
+	return t.Add(time.Duration(60-t.Minute()) * time.Minute)
+}
+
+// forward returns next, or the start of the next hour if next, normalized
+// out of a daylight saving gap, isn't after t
+func forward(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return nextHour(t)
+}
+
+// dayMatches reports whether t's day matches the day fields
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domRestricted && c.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// parseField parses one field into a bitset of the values it matches
+func parseField(spec string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(spec, ",") {
+		lo, hi, step := f.min, f.max, 1
+		rangeSpec := item
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, item)
+			}
+			step, rangeSpec = n, item[:i]
+		}
+
+		switch {
+		case rangeSpec == "*":
+		case strings.Contains(rangeSpec, "-"):
+			parts := strings.SplitN(rangeSpec, "-", 2)
+			var err error
+			if lo, err = f.value(parts[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(parts[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range in %s field %q: %d is after %d", f.name, item, lo, hi)
+			}
+		default:
+			value, err := f.value(rangeSpec)
+			if err != nil {
+				return 0, err
+			}
+			// "a/n" runs from a to the end of the range
+			lo, hi = value, value
+			if step > 1 {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value of the field, a number or a name
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s %d out of range (%d-%d)", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
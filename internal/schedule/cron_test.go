@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "0 3 * * *"},
+		{expr: "*/15 9-17 * * mon-fri"},
+		{expr: "0 0 1,15 * *"},
+		{expr: "30 2 * jan-mar 7"},
+		{expr: "@daily"},
+		{expr: "@Weekly"},
+		{expr: "0 0 * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "* 24 * * *", wantErr: true},
+		{expr: "0 0 0 * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "0 0 * * 5-1", wantErr: true},
+		{expr: "0 0 * foo *", wantErr: true},
+		{expr: "@fortnightly", wantErr: true},
+		{expr: "0 0 30 2 *", wantErr: true}, // Never matches
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCron(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Friday 16 October 2026, 14:07:30
+	from := time.Date(2026, 10, 16, 14, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2026, 10, 16, 14, 8, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2026, 10, 16, 14, 15, 0, 0, time.UTC)},
+		{expr: "0 3 * * *", want: time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)},
+		{expr: "@hourly", want: time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", want: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{expr: "@monthly", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 9 * * mon", want: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 9 * * 7", want: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "5/20 14 * * *", want: time.Date(2026, 10, 16, 14, 25, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (the 20th, or a Saturday)
+		{expr: "0 0 20 * sat", want: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) failed: %v", tt.expr, err)
+			}
+			if got := cron.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronNextAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	cron, err := ParseCron("0 3 * * *")
+	if err != nil {
+		t.Fatalf("ParseCron failed: %v", err)
+	}
+
+	// Clocks go forward at 2:00 on 8 March 2026
+	next := cron.Next(time.Date(2026, 3, 7, 12, 0, 0, 0, loc))
+	if want := time.Date(2026, 3, 8, 3, 0, 0, 0, loc); !next.Equal(want) {
+		t.Errorf("Next() = %v, want %v", next, want)
+	}
+	next = cron.Next(next)
+	if want := time.Date(2026, 3, 9, 3, 0, 0, 0, loc); !next.Equal(want) {
+		t.Errorf("Next() = %v, want %v", next, want)
+	}
+}
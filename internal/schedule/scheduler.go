@@ -0,0 +1,170 @@
+// Package schedule files recurring issues: nightly dependency bumps, a
+// weekly lint-debt cleanup, a monthly dead-code sweep.
+//
+// A schedule (types.Schedule) pairs a cron expression with an issue
+// template. While an executor runs, its Scheduler checks every minute for
+// schedules that are due and creates their issues, which are then claimed
+// and executed like any other ready work. A run is skipped while the issue
+// of the schedule's previous run is still open, unless the schedule allows
+// overlap. Runs missed while no executor was running collapse into one.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Actor is who schedules create issues as
+const Actor = "scheduler"
+
+// pollInterval is how often the scheduler checks for due schedules, the
+// resolution of a cron expression
+const pollInterval = time.Minute
+
+// Store is the storage a Scheduler uses
+type Store interface {
+	ListSchedules(ctx context.Context) ([]*types.Schedule, error)
+	ClaimScheduleRun(ctx context.Context, name string, now, next time.Time) (bool, error)
+	SetScheduleLastIssue(ctx context.Context, name, issueID string) error
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+}
+
+// Run is the outcome of one due schedule
+type Run struct {
+	Schedule string
+	IssueID  string // Issue created, "" if the run was skipped
+	Skipped  string // Why no issue was created
+}
+
+// Scheduler creates the issues of due schedules
+type Scheduler struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewScheduler creates a scheduler for the schedules in store
+func NewScheduler(store Store) *Scheduler {
+	return &Scheduler{store: store, now: time.Now}
+}
+
+// Run creates the issues of due schedules every minute until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runs, err := s.RunDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("schedule: failed to run due schedules", "error", err)
+			}
+			for _, run := range runs {
+				if run.IssueID != "" {
+					fmt.Printf("Schedule: %s created %s\n", run.Schedule, run.IssueID)
+				} else {
+					fmt.Printf("Schedule: %s skipped (%s)\n", run.Schedule, run.Skipped)
+				}
+			}
+		}
+	}
+}
+
+// RunDue runs every enabled schedule that is due, returning what each did.
+// A schedule with an invalid cron expression is logged and left alone.
+func (s *Scheduler) RunDue(ctx context.Context) ([]Run, error) {
+	schedules, err := s.store.ListSchedules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	now := s.now()
+	var runs []Run
+	for _, schedule := range schedules {
+		if !schedule.Enabled || schedule.NextRunAt.After(now) {
+			continue
+		}
+		cron, err := ParseCron(schedule.Cron)
+		if err != nil {
+			slog.Warn("schedule: skipping schedule with an invalid cron expression", "schedule", schedule.Name, "error", err)
+			continue
+		}
+
+		// The next run is counted from now, so runs missed while nothing
+		// was polling collapse into this one
+		claimed, err := s.store.ClaimScheduleRun(ctx, schedule.Name, now, cron.Next(now))
+		if err != nil {
+			return runs, err
+		}
+		if !claimed {
+			continue
+		}
+
+		run := Run{Schedule: schedule.Name}
+		if reason, err := s.overlap(ctx, schedule); err != nil {
+			return runs, err
+		} else if reason != "" {
+			run.Skipped = reason
+			runs = append(runs, run)
+			continue
+		}
+		issue, err := CreateIssue(ctx, s.store, schedule, Actor)
+		if err != nil {
+			return runs, fmt.Errorf("schedule %s: %w", schedule.Name, err)
+		}
+		run.IssueID = issue.ID
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// overlap returns why a run of schedule should be skipped because its
+// previous run's issue is still open, or "" if it shouldn't be
+func (s *Scheduler) overlap(ctx context.Context, schedule *types.Schedule) (string, error) {
+	if schedule.AllowOverlap || schedule.LastIssueID == "" {
+		return "", nil
+	}
+	previous, err := s.store.GetIssue(ctx, schedule.LastIssueID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", schedule.LastIssueID, err)
+	}
+	if previous == nil || previous.Status == types.StatusClosed {
+		return "", nil
+	}
+	return fmt.Sprintf("%s from the previous run is still %s", previous.ID, previous.Status), nil
+}
+
+// CreateIssue creates an issue from schedule's template and records it as
+// the schedule's latest, whether or not the schedule is due
+func CreateIssue(ctx context.Context, store Store, schedule *types.Schedule, actor string) (*types.Issue, error) {
+	issue := schedule.NewIssue()
+	if err := store.CreateIssue(ctx, issue, actor); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	for _, label := range schedule.IssueLabels() {
+		if err := store.AddLabel(ctx, issue.ID, label, actor); err != nil {
+			return issue, fmt.Errorf("failed to label %s: %w", issue.ID, err)
+		}
+	}
+	if err := store.SetScheduleLastIssue(ctx, schedule.Name, issue.ID); err != nil {
+		return issue, err
+	}
+	return issue, nil
+}
+
+// NextRun returns when a schedule with cron expression expr next runs after t
+func NextRun(expr string, t time.Time) (time.Time, error) {
+	cron, err := ParseCron(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cron.Next(t), nil
+}
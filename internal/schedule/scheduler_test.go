@@ -0,0 +1,155 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestRunDue(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	now := time.Date(2026, 10, 16, 3, 0, 30, 0, time.UTC)
+
+	nightly := &types.Schedule{
+		Name:               "nightly-deps",
+		Cron:               "0 3 * * *",
+		Enabled:            true,
+		Title:              "Bump dependencies",
+		AcceptanceCriteria: "Dependencies are current and gates pass",
+		IssueType:          types.TypeTask,
+		Priority:           2,
+		Labels:             []string{"deps"},
+		NextRunAt:          now.Add(-30 * time.Second),
+	}
+	weekly := &types.Schedule{
+		Name:      "lint-debt",
+		Cron:      "0 9 * * mon",
+		Enabled:   true,
+		Title:     "Clean up lint debt",
+		IssueType: types.TypeChore,
+		Priority:  3,
+		NextRunAt: now.Add(time.Hour),
+	}
+	disabled := &types.Schedule{
+		Name:      "dead-code",
+		Cron:      "@monthly",
+		Title:     "Sweep dead code",
+		IssueType: types.TypeChore,
+		NextRunAt: now.Add(-time.Hour),
+	}
+	for _, schedule := range []*types.Schedule{nightly, weekly, disabled} {
+		if err := store.SaveSchedule(ctx, schedule); err != nil {
+			t.Fatalf("SaveSchedule(%s) failed: %v", schedule.Name, err)
+		}
+	}
+
+	s := NewScheduler(store)
+	s.now = func() time.Time { return now }
+	runs, err := s.RunDue(ctx)
+	if err != nil {
+		t.Fatalf("RunDue failed: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Schedule != "nightly-deps" || runs[0].IssueID == "" {
+		t.Fatalf("expected only nightly-deps to create an issue, got %+v", runs)
+	}
+
+	issue, err := store.GetIssue(ctx, runs[0].IssueID)
+	if err != nil || issue == nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Title != "Bump dependencies" || issue.Status != types.StatusOpen || issue.Priority != 2 {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	labels, _ := store.GetLabels(ctx, issue.ID)
+	want := map[string]bool{"deps": true, types.ScheduledLabel: true, "schedule:nightly-deps": true}
+	if len(labels) != len(want) {
+		t.Errorf("expected labels %v, got %v", want, labels)
+	}
+	for _, label := range labels {
+		if !want[label] {
+			t.Errorf("unexpected label %s", label)
+		}
+	}
+
+	got, _ := store.GetSchedule(ctx, "nightly-deps")
+	if got.LastIssueID != issue.ID || got.LastRunAt == nil || !got.NextRunAt.Equal(time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the run recorded and the next run tomorrow, got %+v", got)
+	}
+
+	// Nothing is due again until tomorrow
+	if runs, err := s.RunDue(ctx); err != nil || len(runs) != 0 {
+		t.Errorf("expected no runs, got %+v (err %v)", runs, err)
+	}
+
+	// Tomorrow's run is skipped while today's issue is open...
+	now = now.Add(24 * time.Hour)
+	runs, err = s.RunDue(ctx)
+	if err != nil {
+		t.Fatalf("RunDue failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected nightly-deps and lint-debt to run, got %+v", runs)
+	}
+	for _, run := range runs {
+		switch run.Schedule {
+		case "nightly-deps":
+			if run.IssueID != "" || run.Skipped == "" {
+				t.Errorf("expected nightly-deps to be skipped while %s is open, got %+v", issue.ID, run)
+			}
+		case "lint-debt":
+			if run.IssueID == "" {
+				t.Errorf("expected lint-debt to create an issue, got %+v", run)
+			}
+		}
+	}
+
+	// ...and runs again once it is closed
+	if err := store.CloseIssue(ctx, issue.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	now = now.Add(24 * time.Hour)
+	runs, err = s.RunDue(ctx)
+	if err != nil {
+		t.Fatalf("RunDue failed: %v", err)
+	}
+	if len(runs) != 1 || runs[0].IssueID == "" || runs[0].IssueID == issue.ID {
+		t.Errorf("expected a new nightly-deps issue, got %+v", runs)
+	}
+}
+
+func TestRunDueClaimsEachRunOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	schedule := &types.Schedule{
+		Name:         "hourly",
+		Cron:         "@hourly",
+		Enabled:      true,
+		AllowOverlap: true,
+		Title:        "Check the build",
+		IssueType:    types.TypeChore,
+		NextRunAt:    now,
+	}
+	if err := store.SaveSchedule(ctx, schedule); err != nil {
+		t.Fatalf("SaveSchedule failed: %v", err)
+	}
+
+	// Two executors polling the same database
+	created := 0
+	for i := 0; i < 2; i++ {
+		s := NewScheduler(store)
+		s.now = func() time.Time { return now }
+		runs, err := s.RunDue(ctx)
+		if err != nil {
+			t.Fatalf("RunDue failed: %v", err)
+		}
+		created += len(runs)
+	}
+	if created != 1 {
+		t.Errorf("expected one issue for the run, got %d", created)
+	}
+}
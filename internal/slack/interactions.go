@@ -0,0 +1,317 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Request headers Slack signs interactions with
+const (
+	HeaderTimestamp = "X-Slack-Request-Timestamp"
+	HeaderSignature = "X-Slack-Signature"
+)
+
+// maxClockSkew is how old a signed request may be before it's refused as
+// a possible replay
+const maxClockSkew = 5 * time.Minute
+
+// interaction is the part of a block_actions payload the bot uses
+type interaction struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+// Handler returns the handler of the bot's interactivity endpoint,
+// POST /slack/interactions
+func (b *Bot) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /slack/interactions", b.handleInteraction)
+	return mux
+}
+
+// ListenAndServe serves the interactivity endpoint on addr until ctx is
+// done, then shuts down gracefully
+func (b *Bot) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{Handler: b.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to shut down Slack endpoint: %w", err)
+		}
+		return nil
+	}
+}
+
+// handleInteraction records the decision of a button click
+func (b *Bot) handleInteraction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if err := b.verify(r.Header, body); err != nil {
+		slog.Warn("slack: refused interaction", "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	var payload interaction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	// Acknowledge at once: the message is updated through the response URL
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 || payload.ResponseURL == "" {
+		return
+	}
+
+	ctx := context.WithoutCancel(r.Context())
+	if !b.cfg.CanApprove(payload.User.ID) {
+		b.reply(ctx, payload.ResponseURL, "You're not allowed to decide VC requests (see VC_SLACK_APPROVERS).")
+		return
+	}
+	action := payload.Actions[0]
+	req, err := parseRequest(action.Value)
+	if err != nil {
+		b.reply(ctx, payload.ResponseURL, err.Error())
+		return
+	}
+	actor := "slack:" + payload.User.Username
+	if payload.User.Username == "" {
+		actor = "slack:" + payload.User.ID
+	}
+
+	var issue *types.Issue
+	var outcome, decision string
+	switch action.ActionID {
+	case actionApprove:
+		issue, outcome, err = b.approve(ctx, req, actor)
+		decision = fmt.Sprintf("✅ Approved by <@%s>: %s", payload.User.ID, outcome)
+	case actionReject:
+		issue, outcome, err = b.reject(ctx, req, actor)
+		decision = fmt.Sprintf("❌ Rejected by <@%s>: %s", payload.User.ID, outcome)
+	default:
+		return
+	}
+	if err != nil {
+		b.reply(ctx, payload.ResponseURL, fmt.Sprintf("Couldn't record the decision: %v", err))
+		return
+	}
+	if err := b.respond(ctx, payload.ResponseURL, decision, decisionBlocks(req, issue, decision), false); err != nil {
+		slog.Warn("slack: failed to update message", "issue", req.issueID, "error", err)
+	}
+}
+
+// reply answers only the person who clicked
+func (b *Bot) reply(ctx context.Context, responseURL, text string) {
+	if err := b.respond(ctx, responseURL, text, nil, true); err != nil {
+		slog.Warn("slack: failed to reply", "error", err)
+	}
+}
+
+// verify checks a request's Slack signature: "v0=" followed by the hex
+// HMAC-SHA256 of "v0:<timestamp>:<body>" with the signing secret
+func (b *Bot) verify(header http.Header, body []byte) error {
+	timestamp := header.Get(HeaderTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	if skew := b.now().Sub(time.Unix(seconds, 0)); skew > maxClockSkew || skew < -maxClockSkew {
+		return fmt.Errorf("timestamp is %v off", skew.Round(time.Second))
+	}
+	if !hmac.Equal([]byte(header.Get(HeaderSignature)), []byte(Sign(b.cfg.SigningSecret, timestamp, body))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// Sign returns the X-Slack-Signature header value of a request body sent
+// at timestamp (Unix seconds)
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// approve records the approval of a request as actor, returning the issue
+// and the outcome
+func (b *Bot) approve(ctx context.Context, req request, actor string) (*types.Issue, string, error) {
+	issue, err := b.openIssue(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	if req.kind == kindOverride {
+		if err := gates.ApproveOverride(ctx, b.store, issue.ID, req.gate, actor); err != nil {
+			return nil, "", err
+		}
+		// Resume the issue if its gates failed; the gate is skipped next time
+		labels, err := b.store.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get labels: %w", err)
+		}
+		if issue.Status == types.StatusBlocked && slices.Contains(labels, "quality-gates-failed") {
+			if err := b.store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+				return nil, "", fmt.Errorf("failed to reopen %s: %w", issue.ID, err)
+			}
+			return issue, fmt.Sprintf("`%s` is skipped and %s reopened", req.gate, issue.ID), nil
+		}
+		return issue, fmt.Sprintf("`%s` is skipped on the next run", req.gate), nil
+	}
+
+	deps, err := b.store.GetDependencyRecords(ctx, issue.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	var resumed []string
+	for _, dep := range deps {
+		if dep.Type != types.DepDiscoveredFrom {
+			continue
+		}
+		if err := b.resume(ctx, dep.DependsOnID, actor); err != nil {
+			return nil, "", err
+		}
+		resumed = append(resumed, dep.DependsOnID)
+	}
+	if err := b.store.CloseIssue(ctx, issue.ID, "Approved in Slack by "+actor, actor); err != nil {
+		return nil, "", fmt.Errorf("failed to close %s: %w", issue.ID, err)
+	}
+	if len(resumed) == 0 {
+		return issue, issue.ID + " closed", nil
+	}
+	return issue, fmt.Sprintf("%s closed, VC resumes %s", issue.ID, strings.Join(resumed, ", ")), nil
+}
+
+// reject records the rejection of a request as actor, returning the issue
+// and the outcome
+func (b *Bot) reject(ctx context.Context, req request, actor string) (*types.Issue, string, error) {
+	issue, err := b.openIssue(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	var comment, outcome string
+	if req.kind == kindOverride {
+		if err := gates.RejectOverride(ctx, b.store, issue.ID, req.gate, actor); err != nil {
+			return nil, "", err
+		}
+		comment = fmt.Sprintf("Gate override for **%s** rejected in Slack by %s; the gate keeps running.", req.gate, actor)
+		outcome = fmt.Sprintf("`%s` keeps running", req.gate)
+	} else {
+		comment = fmt.Sprintf("Rejected in Slack by %s; left for a human to resolve.", actor)
+		outcome = "left for a human to resolve"
+	}
+	if err := b.store.AddComment(ctx, issue.ID, actor, comment); err != nil {
+		return nil, "", fmt.Errorf("failed to comment on %s: %w", issue.ID, err)
+	}
+	return issue, outcome, nil
+}
+
+// openIssue returns the issue of a request that still awaits a decision
+func (b *Bot) openIssue(ctx context.Context, req request) (*types.Issue, error) {
+	issue, err := b.store.GetIssue(ctx, req.issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue %s: %w", req.issueID, err)
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s not found", req.issueID)
+	}
+	if issue.Status == types.StatusClosed {
+		return nil, fmt.Errorf("%s is already closed", issue.ID)
+	}
+	if req.kind == kindOverride {
+		pending, err := b.pendingOverride(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if !pending {
+			return nil, fmt.Errorf("no pending override of gate %s on %s (already decided?)", req.gate, issue.ID)
+		}
+	}
+	return issue, nil
+}
+
+// pendingOverride reports whether a gate override request awaits approval
+func (b *Bot) pendingOverride(ctx context.Context, req request) (bool, error) {
+	_, pending, err := gates.ResolveOverrides(ctx, b.store, req.issueID, gates.AutomatedActors(ctx, b.store))
+	if err != nil {
+		return false, err
+	}
+	for _, p := range pending {
+		if p.Gate == req.gate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resume lets VC work on an issue again: its no-auto-claim label is
+// removed and it's reopened if blocked
+func (b *Bot) resume(ctx context.Context, issueID, actor string) error {
+	issue, err := b.store.GetIssue(ctx, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %w", issueID, err)
+	}
+	if issue == nil || issue.Status == types.StatusClosed {
+		return nil
+	}
+	labels, err := b.store.GetLabels(ctx, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to get labels of %s: %w", issueID, err)
+	}
+	if slices.Contains(labels, "no-auto-claim") {
+		if err := b.store.RemoveLabel(ctx, issueID, "no-auto-claim", actor); err != nil {
+			return fmt.Errorf("failed to remove no-auto-claim from %s: %w", issueID, err)
+		}
+	}
+	if issue.Status == types.StatusBlocked {
+		if err := b.store.UpdateIssue(ctx, issueID, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+			return fmt.Errorf("failed to reopen %s: %w", issueID, err)
+		}
+	}
+	return nil
+}
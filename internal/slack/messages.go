@@ -0,0 +1,129 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Kinds of requests posted to Slack
+const (
+	kindEscalation = "escalation"
+	kindOverride   = "override"
+)
+
+// Button action IDs
+const (
+	actionApprove = "vc_approve"
+	actionReject  = "vc_reject"
+)
+
+// maxDescription bounds how many characters of an escalation's
+// description are posted
+const maxDescription = 500
+
+// request is something a human is asked to approve or reject
+type request struct {
+	kind    string
+	issueID string
+	gate    gates.GateType // For overrides
+}
+
+// value encodes the request as a button value, e.g. "override:vc-12:lint"
+func (r request) value() string {
+	if r.kind == kindOverride {
+		return r.kind + ":" + r.issueID + ":" + string(r.gate)
+	}
+	return r.kind + ":" + r.issueID
+}
+
+// parseRequest decodes a button value
+func parseRequest(value string) (request, error) {
+	parts := strings.SplitN(value, ":", 3)
+	switch {
+	case len(parts) == 2 && parts[0] == kindEscalation && parts[1] != "":
+		return request{kind: kindEscalation, issueID: parts[1]}, nil
+	case len(parts) == 3 && parts[0] == kindOverride && parts[1] != "" && parts[2] != "":
+		return request{kind: kindOverride, issueID: parts[1], gate: gates.GateType(parts[2])}, nil
+	}
+	return request{}, fmt.Errorf("unknown request %q", value)
+}
+
+// block is a Slack Block Kit block
+type block map[string]interface{}
+
+func mrkdwn(text string) map[string]string {
+	return map[string]string{"type": "mrkdwn", "text": text}
+}
+
+func section(text string) block {
+	return block{"type": "section", "text": mrkdwn(text)}
+}
+
+func contextBlock(text string) block {
+	return block{"type": "context", "elements": []interface{}{mrkdwn(text)}}
+}
+
+func button(actionID, label, style, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":      "button",
+		"action_id": actionID,
+		"text":      map[string]string{"type": "plain_text", "text": label},
+		"style":     style,
+		"value":     value,
+	}
+}
+
+// requestText is the plain-text summary of a request, shown in
+// notifications
+func requestText(r request, issue *types.Issue) string {
+	if r.kind == kindOverride {
+		return fmt.Sprintf("Gate override requested: %s on %s %s", r.gate, issue.ID, issue.Title)
+	}
+	return fmt.Sprintf("Escalated: %s %s", issue.ID, issue.Title)
+}
+
+// headline is the first line of a request's message
+func headline(r request, issue *types.Issue) string {
+	if r.kind == kindOverride {
+		return fmt.Sprintf("*Gate override requested:* skip `%s` for *%s* %s", r.gate, issue.ID, escape(issue.Title))
+	}
+	return fmt.Sprintf("*Escalated:* *%s* %s", issue.ID, escape(issue.Title))
+}
+
+// requestBlocks builds the message asking to decide a request, made by
+// event, on an issue whose code belongs to owners
+func requestBlocks(r request, issue *types.Issue, event *types.Event, owners []string) []block {
+	text := headline(r, issue)
+	if r.kind == kindEscalation && issue.Description != "" {
+		description := issue.Description
+		if runes := []rune(description); len(runes) > maxDescription {
+			description = strings.TrimSpace(string(runes[:maxDescription])) + "…"
+		}
+		text += "\n" + escape(description)
+	}
+	details := fmt.Sprintf("%s added `%s` · P%d · %s · `vc show %s`", escape(event.Actor), event.AddedLabel(), issue.Priority, issue.Status, issue.ID)
+	if len(owners) > 0 {
+		details += " · owned by " + escape(strings.Join(owners, ", "))
+	}
+	return []block{
+		section(text),
+		contextBlock(details),
+		{"type": "actions", "block_id": "vc_decision", "elements": []interface{}{
+			button(actionApprove, "Approve", "primary", r.value()),
+			button(actionReject, "Reject", "danger", r.value()),
+		}},
+	}
+}
+
+// decisionBlocks builds the message that replaces a decided request
+func decisionBlocks(r request, issue *types.Issue, decision string) []block {
+	return []block{section(headline(r, issue)), contextBlock(decision)}
+}
+
+// escape escapes the characters Slack treats as markup in text
+func escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,227 @@
+// Package slack lets people approve and reject what VC asks a human for
+// from Slack, without opening a terminal.
+//
+// A Bot watches the database for escalations (issues labelled escalation
+// or escalated) and gate override requests (gate-override:<gate> labels)
+// and posts each to a channel with Approve and Reject buttons. Slack sends
+// the clicks to the bot's interactivity endpoint, /slack/interactions,
+// signed with the app's signing secret. Decisions are recorded as made by
+// "slack:<username>":
+//
+//   - Approving an override adds its gate-override-approved:<gate> label,
+//     and reopens the issue if its gates failed, so the executor picks it
+//     up again and skips the gate
+//   - Rejecting an override removes its request label; the gate keeps
+//     running
+//   - Approving an escalation closes it and lets VC resume the issues it
+//     was discovered from: their no-auto-claim label is removed and they
+//     are reopened if blocked
+//   - Rejecting an escalation leaves it open for a human, with a comment
+//
+// The message is then replaced with the decision and who made it.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Store is the storage a Bot reads requests from and records decisions in
+type Store interface {
+	GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error)
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetLabels(ctx context.Context, issueID string) ([]string, error)
+	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
+	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	RemoveLabel(ctx context.Context, issueID, label, actor string) error
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	CloseIssue(ctx context.Context, id string, reason string, actor string) error
+	GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error)
+}
+
+// Bot posts approval requests to Slack and records the decisions made on
+// them
+type Bot struct {
+	cfg    config.SlackConfig
+	store  Store
+	client *http.Client
+	now    func() time.Time
+
+	// Where the next poll picks up. Only requests made after the bot was
+	// created are posted.
+	issueCursor string
+	issueSince  time.Time
+}
+
+// NewBot creates a bot for cfg, which must be enabled
+func NewBot(cfg config.SlackConfig, store Store) (*Bot, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("Slack bot token is not configured")
+	}
+	return &Bot{
+		cfg:        cfg,
+		store:      store,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+		issueSince: time.Now(),
+	}, nil
+}
+
+// Run posts new requests every poll interval until ctx is done
+func (b *Bot) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.PollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.Poll(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("slack: failed to post requests", "error", err)
+			}
+		}
+	}
+}
+
+// Poll posts the escalations and gate override requests made since the
+// last poll. A request that fails to post stops the poll and is tried
+// again on the next one.
+func (b *Bot) Poll(ctx context.Context) error {
+	for {
+		page := types.PageRequest{Cursor: b.issueCursor, Limit: types.MaxPageSize}
+		if b.issueCursor == "" {
+			page.Since = b.issueSince
+		}
+		result, err := b.store.GetEventsPage(ctx, "", page)
+		if err != nil {
+			return fmt.Errorf("failed to read issue events: %w", err)
+		}
+		for _, event := range result.Events {
+			if err := b.issueEvent(ctx, event); err != nil {
+				return err
+			}
+			b.issueCursor = types.PageCursor{Key: types.PageKey(event.CreatedAt), ID: strconv.FormatInt(event.ID, 10)}.Encode()
+		}
+		if result.NextCursor == "" {
+			return nil
+		}
+	}
+}
+
+// issueEvent posts the request an audit event makes, if any
+func (b *Bot) issueEvent(ctx context.Context, event *types.Event) error {
+	if event.EventType != types.EventLabelAdded {
+		return nil
+	}
+	label := event.AddedLabel()
+	var req request
+	switch {
+	case types.IsEscalationLabel(label):
+		req = request{kind: kindEscalation, issueID: event.IssueID}
+	case strings.HasPrefix(label, gates.OverrideLabelPrefix):
+		req = request{kind: kindOverride, issueID: event.IssueID, gate: gates.GateType(strings.TrimPrefix(label, gates.OverrideLabelPrefix))}
+	default:
+		return nil
+	}
+
+	issue, err := b.store.GetIssue(ctx, event.IssueID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %w", event.IssueID, err)
+	}
+	if issue == nil || issue.Status == types.StatusClosed {
+		return nil
+	}
+	if req.kind == kindOverride {
+		// Skip requests approved by the time they're seen
+		if pending, err := b.pendingOverride(ctx, req); err != nil || !pending {
+			return err
+		}
+	}
+
+	labels, err := b.store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get labels of %s: %w", issue.ID, err)
+	}
+	blocks := requestBlocks(req, issue, event, types.LabelOwners(labels))
+	return b.postMessage(ctx, requestText(req, issue), blocks)
+}
+
+// postMessage posts a message to the configured channel
+func (b *Bot) postMessage(ctx context.Context, text string, blocks []block) error {
+	body := map[string]interface{}{"channel": b.cfg.Channel, "text": text, "blocks": blocks}
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := b.call(ctx, b.cfg.APIURL+"/chat.postMessage", body, &result); err != nil {
+		return fmt.Errorf("failed to post to Slack: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("failed to post to Slack: %s", result.Error)
+	}
+	return nil
+}
+
+// respond replaces the message a button was clicked on through its
+// response URL, or answers only the person who clicked if ephemeral
+func (b *Bot) respond(ctx context.Context, responseURL string, text string, blocks []block, ephemeral bool) error {
+	body := map[string]interface{}{"text": text}
+	if ephemeral {
+		body["response_type"] = "ephemeral"
+		body["replace_original"] = false
+	} else {
+		body["replace_original"] = true
+		body["blocks"] = blocks
+	}
+	return b.call(ctx, responseURL, body, nil)
+}
+
+// call POSTs a JSON body to url with the bot token, decoding the response
+// into result unless it's nil
+func (b *Bot) call(ctx context.Context, url string, body interface{}, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+b.cfg.BotToken)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Slack returned %s", resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,287 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+const testSecret = "s3cret"
+
+// fakeSlack records the messages posted to chat.postMessage and the
+// responses sent to /respond
+type fakeSlack struct {
+	mu        sync.Mutex
+	posted    []map[string]interface{}
+	responses []map[string]interface{}
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	switch r.URL.Path {
+	case "/api/chat.postMessage":
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_auth"}`))
+			return
+		}
+		f.posted = append(f.posted, body)
+		_, _ = w.Write([]byte(`{"ok": true, "ts": "1.2"}`))
+	case "/respond":
+		f.responses = append(f.responses, body)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeSlack) lastResponse(t *testing.T) map[string]interface{} {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.responses) == 0 {
+		t.Fatal("no response was sent")
+	}
+	return f.responses[len(f.responses)-1]
+}
+
+func newBot(t *testing.T, store Store, approvers ...string) (*Bot, *fakeSlack, string) {
+	t.Helper()
+	slack := &fakeSlack{}
+	server := httptest.NewServer(slack)
+	t.Cleanup(server.Close)
+
+	cfg := config.DefaultSlackConfig()
+	cfg.BotToken = "xoxb-test"
+	cfg.SigningSecret = testSecret
+	cfg.Channel = "C0123"
+	cfg.APIURL = server.URL + "/api"
+	cfg.Approvers = approvers
+	bot, err := NewBot(cfg, store)
+	if err != nil {
+		t.Fatalf("NewBot() error = %v", err)
+	}
+	// Everything the test records happens after the bot starts
+	time.Sleep(2 * time.Millisecond)
+	return bot, slack, server.URL + "/respond"
+}
+
+func createIssue(t *testing.T, store *memory.Store, title string, status types.Status) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: title, AcceptanceCriteria: "Done", Status: status, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	return issue
+}
+
+func addLabel(t *testing.T, store *memory.Store, issueID, label, actor string) {
+	t.Helper()
+	if err := store.AddLabel(context.Background(), issueID, label, actor); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+}
+
+// click sends a signed button click to the bot as Slack user bob (U1)
+func click(t *testing.T, bot *Bot, actionID, value, responseURL string) int {
+	t.Helper()
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":         "block_actions",
+		"user":         map[string]string{"id": "U1", "username": "bob"},
+		"actions":      []map[string]string{{"action_id": actionID, "value": value}},
+		"response_url": responseURL,
+	})
+	body := "payload=" + url.QueryEscape(string(payload))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(testSecret, timestamp, []byte(body)))
+	rec := httptest.NewRecorder()
+	bot.Handler().ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestPollPostsRequests(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	bot, slack, _ := newBot(t, store)
+
+	overridden := createIssue(t, store, "Flaky lint", types.StatusBlocked)
+	addLabel(t, store, overridden.ID, "backend", "alice")
+	addLabel(t, store, overridden.ID, gates.OverrideLabelPrefix+"lint", "alice")
+	// Approved before the bot sees it, so not posted
+	addLabel(t, store, overridden.ID, gates.OverrideLabelPrefix+"test", "alice")
+	addLabel(t, store, overridden.ID, gates.OverrideApprovedLabelPrefix+"test", "carol")
+	escalated := createIssue(t, store, "Baseline stuck", types.StatusOpen)
+	addLabel(t, store, escalated.ID, types.OwnerLabel("@acme/ci"), "executor")
+	addLabel(t, store, escalated.ID, "escalation", "executor-escalation")
+
+	if err := bot.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(slack.posted) != 2 {
+		t.Fatalf("posted %d messages, want 2: %v", len(slack.posted), slack.posted)
+	}
+	first, _ := json.Marshal(slack.posted[0])
+	if slack.posted[0]["channel"] != "C0123" || !strings.Contains(string(first), `"value":"override:`+overridden.ID+`:lint"`) ||
+		!strings.Contains(string(first), "vc_approve") || !strings.Contains(string(first), "vc_reject") {
+		t.Errorf("unexpected override message: %s", first)
+	}
+	second, _ := json.Marshal(slack.posted[1])
+	if !strings.Contains(string(second), `"value":"escalation:`+escalated.ID+`"`) || !strings.Contains(string(second), "owned by @acme/ci") {
+		t.Errorf("unexpected escalation message: %s", second)
+	}
+
+	// Requests are posted once
+	if err := bot.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(slack.posted) != 2 {
+		t.Errorf("posted %d messages after a second poll, want 2", len(slack.posted))
+	}
+}
+
+func TestApproveOverride(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	bot, slack, respondURL := newBot(t, store)
+
+	issue := createIssue(t, store, "Flaky lint", types.StatusBlocked)
+	addLabel(t, store, issue.ID, "quality-gates-failed", "executor")
+	addLabel(t, store, issue.ID, gates.OverrideLabelPrefix+"lint", "alice")
+
+	if code := click(t, bot, actionApprove, "override:"+issue.ID+":lint", respondURL); code != http.StatusOK {
+		t.Fatalf("click returned %d", code)
+	}
+	overrides, pending, err := gates.ResolveOverrides(ctx, store, issue.ID, gates.AutomatedActors(ctx, store))
+	if err != nil {
+		t.Fatalf("ResolveOverrides() error = %v", err)
+	}
+	if len(pending) != 0 || len(overrides) != 1 || overrides[0].ApprovedBy != "slack:bob" || overrides[0].RequestedBy != "alice" {
+		t.Errorf("overrides = %+v, pending = %+v", overrides, pending)
+	}
+	updated, _ := store.GetIssue(ctx, issue.ID)
+	if updated.Status != types.StatusOpen {
+		t.Errorf("status = %s, want the issue reopened", updated.Status)
+	}
+	response := slack.lastResponse(t)
+	if response["replace_original"] != true || !strings.Contains(response["text"].(string), "Approved by <@U1>") {
+		t.Errorf("unexpected response %v", response)
+	}
+
+	// A second click finds nothing to decide
+	click(t, bot, actionReject, "override:"+issue.ID+":lint", respondURL)
+	response = slack.lastResponse(t)
+	if response["response_type"] != "ephemeral" || !strings.Contains(response["text"].(string), "no pending override") {
+		t.Errorf("unexpected response %v", response)
+	}
+}
+
+func TestRejectOverride(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	bot, slack, respondURL := newBot(t, store)
+
+	issue := createIssue(t, store, "Flaky lint", types.StatusBlocked)
+	addLabel(t, store, issue.ID, gates.OverrideLabelPrefix+"lint", "alice")
+
+	click(t, bot, actionReject, "override:"+issue.ID+":lint", respondURL)
+	labels, _ := store.GetLabels(ctx, issue.ID)
+	if slices.Contains(labels, gates.OverrideLabelPrefix+"lint") {
+		t.Errorf("request label kept after rejection: %v", labels)
+	}
+	updated, _ := store.GetIssue(ctx, issue.ID)
+	if updated.Status != types.StatusBlocked {
+		t.Errorf("status = %s, want blocked", updated.Status)
+	}
+	if response := slack.lastResponse(t); !strings.Contains(response["text"].(string), "Rejected by <@U1>") {
+		t.Errorf("unexpected response %v", response)
+	}
+}
+
+func TestDecideEscalation(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	bot, slack, respondURL := newBot(t, store)
+
+	baseline := createIssue(t, store, "Baseline test failure", types.StatusBlocked)
+	addLabel(t, store, baseline.ID, "no-auto-claim", "executor-escalation")
+	escalation := createIssue(t, store, "ESCALATED: Baseline test needs human intervention", types.StatusOpen)
+	addLabel(t, store, escalation.ID, "escalation", "executor-escalation")
+	dep := &types.Dependency{IssueID: escalation.ID, DependsOnID: baseline.ID, Type: types.DepDiscoveredFrom}
+	if err := store.AddDependency(ctx, dep, "executor-escalation"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+
+	// Rejecting leaves everything for a human
+	click(t, bot, actionReject, "escalation:"+escalation.ID, respondURL)
+	if updated, _ := store.GetIssue(ctx, escalation.ID); updated.Status != types.StatusOpen {
+		t.Errorf("escalation status = %s after rejection, want open", updated.Status)
+	}
+
+	click(t, bot, actionApprove, "escalation:"+escalation.ID, respondURL)
+	if updated, _ := store.GetIssue(ctx, escalation.ID); updated.Status != types.StatusClosed {
+		t.Errorf("escalation status = %s after approval, want closed", updated.Status)
+	}
+	resumed, _ := store.GetIssue(ctx, baseline.ID)
+	labels, _ := store.GetLabels(ctx, baseline.ID)
+	if resumed.Status != types.StatusOpen || slices.Contains(labels, "no-auto-claim") {
+		t.Errorf("baseline not resumed: status %s, labels %v", resumed.Status, labels)
+	}
+	if response := slack.lastResponse(t); !strings.Contains(response["text"].(string), "VC resumes "+baseline.ID) {
+		t.Errorf("unexpected response %v", response)
+	}
+}
+
+func TestInteractionAuthorization(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	bot, slack, respondURL := newBot(t, store, "U9")
+
+	issue := createIssue(t, store, "Flaky lint", types.StatusBlocked)
+	addLabel(t, store, issue.ID, gates.OverrideLabelPrefix+"lint", "alice")
+	value := "override:" + issue.ID + ":lint"
+
+	// bob (U1) isn't an approver
+	click(t, bot, actionApprove, value, respondURL)
+	if response := slack.lastResponse(t); response["response_type"] != "ephemeral" {
+		t.Errorf("unexpected response %v", response)
+	}
+
+	// Unsigned, forged and replayed requests are refused
+	body := "payload=" + url.QueryEscape(`{"type": "block_actions"}`)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	for name, headers := range map[string][2]string{
+		"unsigned": {now, ""},
+		"forged":   {now, Sign("wrong", now, []byte(body))},
+		"replayed": {old, Sign(testSecret, old, []byte(body))},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+		req.Header.Set(HeaderTimestamp, headers[0])
+		req.Header.Set(HeaderSignature, headers[1])
+		rec := httptest.NewRecorder()
+		bot.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s request returned %d, want 401", name, rec.Code)
+		}
+	}
+
+	if _, pending, _ := gates.ResolveOverrides(ctx, store, issue.ID, gates.AutomatedActors(ctx, store)); len(pending) != 1 {
+		t.Errorf("override decided without authorization: pending %v", pending)
+	}
+}
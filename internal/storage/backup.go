@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ncruces/go-sqlite3/driver"
+	_ "github.com/ncruces/go-sqlite3/embed"
+	"github.com/steveyegge/vc/internal/storage/beads"
+)
+
+// BackupFilePrefix and BackupFileExt name backups written by the scheduler and
+// by `vc backup` without an explicit output path. PruneBackups only ever
+// touches files matching this pattern.
+const (
+	BackupFilePrefix = "vc-backup-"
+	BackupFileExt    = ".db"
+)
+
+// backupTimeFormat sorts lexically in time order and is safe in file names
+const backupTimeFormat = "20060102-150405"
+
+// BackupInfo describes a backup file on disk
+type BackupInfo struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// DefaultBackupDir returns the default backup directory for a database:
+// .beads/backups next to the database file.
+func DefaultBackupDir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "backups")
+}
+
+// BackupFileName returns the file name used for a backup taken at t
+func BackupFileName(t time.Time) string {
+	return BackupFilePrefix + t.Format(backupTimeFormat) + BackupFileExt
+}
+
+// BackupDatabase writes a consistent copy of the SQLite database at dbPath to
+// destPath using the SQLite online backup API. It is safe to run while the
+// executor is using the database: the copy reflects a single point in time.
+//
+// destPath must not already exist, so a backup never silently replaces an
+// older one. The backup is encrypted with the same passphrase as the database.
+func BackupDatabase(ctx context.Context, dbPath, destPath string) error {
+	return BackupDatabaseEncrypted(ctx, dbPath, destPath, Passphrase())
+}
+
+// BackupDatabaseEncrypted is BackupDatabase with the backup encrypted with
+// passphrase instead; an empty passphrase writes an unencrypted backup. This
+// is how a database is encrypted, decrypted or given a new passphrase.
+func BackupDatabaseEncrypted(ctx context.Context, dbPath, destPath, passphrase string) error {
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("database not found: %w", err)
+	}
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup destination already exists: %s", destPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	err := withRawConn(ctx, dbPath, func(conn driver.Conn) error {
+		return conn.Raw().Backup("main", beads.DatabaseURI(destPath, passphrase))
+	})
+	if err != nil {
+		// Don't leave a partial backup behind that looks like a good one
+		_ = os.Remove(destPath)
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	return nil
+}
+
+// RestoreDatabase replaces the contents of the database at dbPath with the
+// backup at backupPath. The backup is verified before anything is written.
+//
+// Restoring underneath a running executor would corrupt its view of the
+// world, so this refuses while the exclusive lock is held by a live process.
+func RestoreDatabase(ctx context.Context, backupPath, dbPath string) error {
+	if err := VerifyBackup(ctx, backupPath); err != nil {
+		return err
+	}
+	if lock, held := ExclusiveLockHolder(dbPath); held {
+		return fmt.Errorf("cannot restore while a VC executor is running (PID %d on %s, started %s); stop it first",
+			lock.PID, lock.Hostname, lock.StartedAt.Format(time.RFC3339))
+	}
+
+	err := withRawConn(ctx, dbPath, func(conn driver.Conn) error {
+		return conn.Raw().Restore("main", DatabaseURI(backupPath))
+	})
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	return nil
+}
+
+// VerifyBackup checks that backupPath is an intact SQLite database that looks
+// like a VC/Beads database (it has an issues table). An encrypted backup must
+// use the database's passphrase.
+func VerifyBackup(ctx context.Context, backupPath string) error {
+	if _, err := os.Stat(backupPath); err != nil {
+		return fmt.Errorf("backup not found: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", DatabaseURI(backupPath, "mode=ro"))
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("backup %s is not a valid database: %w", backupPath, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup %s failed integrity check: %s", backupPath, result)
+	}
+
+	var name string
+	err = db.QueryRowContext(ctx,
+		"SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'issues'").Scan(&name)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("backup %s does not contain an issues table", backupPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect backup: %w", err)
+	}
+	return nil
+}
+
+// ListBackups returns the backups in dir, newest first. A missing directory
+// is not an error: it just means no backups have been taken yet.
+func ListBackups(dir string) ([]BackupInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var backups []BackupInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, BackupFilePrefix) || !strings.HasSuffix(name, BackupFileExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, BackupInfo{
+			Path:    filepath.Join(dir, name),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	// Names embed the timestamp, so sorting by name sorts by age
+	sort.Slice(backups, func(i, j int) bool {
+		return filepath.Base(backups[i].Path) > filepath.Base(backups[j].Path)
+	})
+	return backups, nil
+}
+
+// PruneBackups deletes all but the keep newest backups in dir and returns
+// how many were deleted. keep <= 0 keeps everything.
+func PruneBackups(dir string, keep int) (int, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return 0, err
+	}
+	if len(backups) <= keep {
+		return 0, nil
+	}
+
+	deleted := 0
+	for _, backup := range backups[keep:] {
+		if err := os.Remove(backup.Path); err != nil && !os.IsNotExist(err) {
+			return deleted, fmt.Errorf("failed to delete old backup %s: %w", backup.Path, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// withRawConn opens dbPath with the ncruces driver and hands fn the
+// underlying SQLite connection, which exposes the backup API.
+func withRawConn(ctx context.Context, dbPath string, fn func(driver.Conn) error) error {
+	db, err := sql.Open("sqlite3", DatabaseURI(dbPath))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		raw, ok := driverConn.(driver.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection type %T", driverConn)
+		}
+		return fn(raw)
+	})
+}
@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createTestDatabase creates a minimal database with an issues table
+func createTestDatabase(t *testing.T, path string, titles ...string) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE IF NOT EXISTS issues (id INTEGER PRIMARY KEY, title TEXT)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	for _, title := range titles {
+		if _, err := db.Exec("INSERT INTO issues (title) VALUES (?)", title); err != nil {
+			t.Fatalf("failed to insert issue: %v", err)
+		}
+	}
+}
+
+func issueTitles(t *testing.T, path string) []string {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT title FROM issues ORDER BY id")
+	if err != nil {
+		t.Fatalf("failed to query issues: %v", err)
+	}
+	defer rows.Close()
+
+	var titles []string
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+		titles = append(titles, title)
+	}
+	return titles
+}
+
+func TestBackupAndRestoreDatabase(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dbPath := filepath.Join(beadsDir, "beads.db")
+	createTestDatabase(t, dbPath, "first", "second")
+
+	backupPath := filepath.Join(DefaultBackupDir(dbPath), BackupFileName(time.Now()))
+	if err := BackupDatabase(ctx, dbPath, backupPath); err != nil {
+		t.Fatalf("BackupDatabase failed: %v", err)
+	}
+	if err := VerifyBackup(ctx, backupPath); err != nil {
+		t.Fatalf("VerifyBackup failed on fresh backup: %v", err)
+	}
+
+	// Refuses to overwrite an existing backup
+	if err := BackupDatabase(ctx, dbPath, backupPath); err == nil {
+		t.Error("expected error backing up over an existing file")
+	}
+
+	// Change the live database, then roll it back
+	createTestDatabase(t, dbPath, "third")
+	if got := issueTitles(t, dbPath); len(got) != 3 {
+		t.Fatalf("expected 3 issues before restore, got %v", got)
+	}
+
+	if err := RestoreDatabase(ctx, backupPath, dbPath); err != nil {
+		t.Fatalf("RestoreDatabase failed: %v", err)
+	}
+	got := issueTitles(t, dbPath)
+	if strings.Join(got, ",") != "first,second" {
+		t.Errorf("after restore got issues %v, want [first second]", got)
+	}
+}
+
+func TestRestoreDatabaseRejectsInvalidBackups(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dbPath := filepath.Join(beadsDir, "beads.db")
+	createTestDatabase(t, dbPath, "keep me")
+
+	garbage := filepath.Join(tmpDir, "garbage.db")
+	if err := os.WriteFile(garbage, []byte("definitely not sqlite"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreDatabase(ctx, garbage, dbPath); err == nil {
+		t.Error("expected error restoring from a non-database file")
+	}
+
+	// A valid SQLite file that isn't a VC database
+	other := filepath.Join(tmpDir, "other.db")
+	db, err := sql.Open("sqlite3", other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE TABLE widgets (id INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if err := RestoreDatabase(ctx, other, dbPath); err == nil || !strings.Contains(err.Error(), "issues table") {
+		t.Errorf("expected missing issues table error, got %v", err)
+	}
+
+	if err := RestoreDatabase(ctx, filepath.Join(tmpDir, "missing.db"), dbPath); err == nil {
+		t.Error("expected error restoring from a missing file")
+	}
+
+	if got := issueTitles(t, dbPath); len(got) != 1 || got[0] != "keep me" {
+		t.Errorf("database changed by failed restores: %v", got)
+	}
+}
+
+func TestRestoreDatabaseRefusesWhileLocked(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	beadsDir := filepath.Join(tmpDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dbPath := filepath.Join(beadsDir, "beads.db")
+	createTestDatabase(t, dbPath, "live")
+
+	backupPath := filepath.Join(tmpDir, "backup.db")
+	if err := BackupDatabase(ctx, dbPath, backupPath); err != nil {
+		t.Fatalf("BackupDatabase failed: %v", err)
+	}
+
+	// This process holds the lock, so it is alive
+	lockPath, err := AcquireExclusiveLock(dbPath, "test")
+	if err != nil {
+		t.Fatalf("AcquireExclusiveLock failed: %v", err)
+	}
+	defer ReleaseExclusiveLock(lockPath)
+
+	err = RestoreDatabase(ctx, backupPath, dbPath)
+	if err == nil || !strings.Contains(err.Error(), "executor is running") {
+		t.Errorf("expected restore to be refused while locked, got %v", err)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		name := BackupFileName(base.Add(time.Duration(i) * time.Hour))
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Files that aren't managed backups are never touched
+	if err := os.WriteFile(filepath.Join(dir, "manual.db"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := PruneBackups(dir, 2)
+	if err != nil {
+		t.Fatalf("PruneBackups failed: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("deleted = %d, want 3", deleted)
+	}
+
+	backups, err := ListBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups left, got %d", len(backups))
+	}
+	if filepath.Base(backups[0].Path) != BackupFileName(base.Add(4*time.Hour)) {
+		t.Errorf("newest backup = %s, want the 04:00 backup", filepath.Base(backups[0].Path))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "manual.db")); err != nil {
+		t.Errorf("unmanaged file was deleted: %v", err)
+	}
+
+	if deleted, err := PruneBackups(dir, 0); err != nil || deleted != 0 {
+		t.Errorf("PruneBackups(keep=0) = %d, %v; want 0, nil", deleted, err)
+	}
+	if backups, err := ListBackups(filepath.Join(dir, "missing")); err != nil || len(backups) != 0 {
+		t.Errorf("ListBackups on missing dir = %v, %v; want empty, nil", backups, err)
+	}
+}
+
+func TestBackupDatabaseEncrypted(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "beads.db")
+	createTestDatabase(t, dbPath, "proprietary excerpt")
+
+	encryptedPath := filepath.Join(tmpDir, "encrypted.db")
+	if err := BackupDatabaseEncrypted(ctx, dbPath, encryptedPath, "s3cret"); err != nil {
+		t.Fatalf("BackupDatabaseEncrypted failed: %v", err)
+	}
+	data, err := os.ReadFile(encryptedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "proprietary excerpt") || strings.HasPrefix(string(data), "SQLite format 3") {
+		t.Error("encrypted backup contains plaintext")
+	}
+
+	// Only readable with the passphrase
+	if err := VerifyBackup(ctx, encryptedPath); err == nil {
+		t.Error("expected VerifyBackup to fail without the passphrase")
+	}
+	t.Setenv("VC_DB_PASSPHRASE", "s3cret")
+	if err := VerifyBackup(ctx, encryptedPath); err != nil {
+		t.Errorf("VerifyBackup failed with the passphrase: %v", err)
+	}
+	if got := issueTitles(t, DatabaseURI(encryptedPath)); strings.Join(got, ",") != "proprietary excerpt" {
+		t.Errorf("encrypted backup has issues %v", got)
+	}
+
+	// And back: an empty passphrase writes an unencrypted copy
+	decryptedPath := filepath.Join(tmpDir, "decrypted.db")
+	if err := BackupDatabaseEncrypted(ctx, encryptedPath, decryptedPath, ""); err != nil {
+		t.Fatalf("BackupDatabaseEncrypted (decrypt) failed: %v", err)
+	}
+	if got := issueTitles(t, decryptedPath); strings.Join(got, ",") != "proprietary excerpt" {
+		t.Errorf("decrypted copy has issues %v", got)
+	}
+}
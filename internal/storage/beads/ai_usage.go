@@ -0,0 +1,83 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// AI USAGE (VC extension table: vc_quota_operations)
+// ======================================================================
+
+// RecordAIUsage records an AI supervisor call. An IssueID that isn't a known
+// issue (e.g. "SYSTEM") is stored as NULL.
+func (s *VCStorage) RecordAIUsage(ctx context.Context, usage *types.AIUsage) error {
+	if err := usage.Validate(); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_quota_operations (
+			id, timestamp, issue_id, operation_type, model,
+			input_tokens, output_tokens, cost, duration_ms
+		) VALUES (?, ?, (SELECT id FROM issues WHERE id = ?), ?, ?, ?, ?, ?, ?)
+	`, usage.ID, usage.Timestamp, usage.IssueID, usage.Operation, usage.Model,
+		usage.InputTokens, usage.OutputTokens, usage.CostUSD, usage.Duration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to record AI usage: %w", err)
+	}
+	return nil
+}
+
+// ListAIUsage returns the usage records matching filter, oldest first
+func (s *VCStorage) ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error) {
+	var where []string
+	var args []interface{}
+	if filter.IssueID != "" {
+		where = append(where, "issue_id = ?")
+		args = append(args, filter.IssueID)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "timestamp >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, "timestamp < ?")
+		args = append(args, filter.Until)
+	}
+
+	query := `SELECT id, timestamp, issue_id, operation_type, model, input_tokens, output_tokens, cost, duration_ms
+		FROM vc_quota_operations`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY timestamp, id"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI usage: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*types.AIUsage
+	for rows.Next() {
+		var usage types.AIUsage
+		var issueID sql.NullString
+		var durationMs sql.NullInt64
+		if err := rows.Scan(&usage.ID, &usage.Timestamp, &issueID, &usage.Operation, &usage.Model,
+			&usage.InputTokens, &usage.OutputTokens, &usage.CostUSD, &durationMs); err != nil {
+			return nil, fmt.Errorf("failed to scan AI usage: %w", err)
+		}
+		usage.IssueID = issueID.String
+		usage.Duration = time.Duration(durationMs.Int64) * time.Millisecond
+		result = append(result, &usage)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating AI usage: %w", err)
+	}
+	return result, nil
+}
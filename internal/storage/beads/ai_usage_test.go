@@ -0,0 +1,57 @@
+package beads
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestAIUsage(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	issue := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	now := time.Now()
+	for _, usage := range []*types.AIUsage{
+		{ID: "u2", Timestamp: now, IssueID: "SYSTEM", Operation: "loop-detection", Model: "haiku", InputTokens: 10, OutputTokens: 1, CostUSD: 0.01},
+		{ID: "u1", Timestamp: now.Add(-time.Hour), IssueID: issue.ID, Operation: "assessment", Model: "sonnet",
+			InputTokens: 1000, OutputTokens: 100, CostUSD: 0.25, Duration: 1500 * time.Millisecond},
+		{ID: "u0", Timestamp: now.Add(-48 * time.Hour), Operation: "digest-summarization", Model: "sonnet", CostUSD: 0.5},
+	} {
+		if err := store.RecordAIUsage(ctx, usage); err != nil {
+			t.Fatalf("RecordAIUsage(%s) failed: %v", usage.ID, err)
+		}
+	}
+	if err := store.RecordAIUsage(ctx, &types.AIUsage{ID: "bad", Model: "sonnet"}); err == nil {
+		t.Error("expected usage without an operation to be rejected")
+	}
+
+	usage, err := store.ListAIUsage(ctx, types.AIUsageFilter{Since: now.Add(-24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("ListAIUsage failed: %v", err)
+	}
+	if len(usage) != 2 || usage[0].ID != "u1" || usage[1].ID != "u2" {
+		t.Fatalf("expected u1 then u2, got %+v", usage)
+	}
+	if got := usage[0]; got.IssueID != issue.ID || got.InputTokens != 1000 || got.CostUSD != 0.25 || got.Duration != 1500*time.Millisecond {
+		t.Errorf("unexpected usage: %+v", got)
+	}
+	if usage[1].IssueID != "" {
+		t.Errorf("expected an unknown issue to be stored as empty, got %q", usage[1].IssueID)
+	}
+
+	usage, err = store.ListAIUsage(ctx, types.AIUsageFilter{IssueID: issue.ID, Until: now.Add(-2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("ListAIUsage failed: %v", err)
+	}
+	if len(usage) != 0 {
+		t.Errorf("expected nothing before the issue's usage, got %+v", usage)
+	}
+}
@@ -0,0 +1,107 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ARCHIVE (long-closed issues moved out to a dump)
+// ======================================================================
+
+// ArchiveIssues moves issues closed before opts.ClosedBefore out of the
+// database, writing them with their history to w as a dump that Import
+// restores. Issues linked by a dependency to one that stays, and issues with
+// attachments or custom field values, are kept. The dump is written (and
+// synced, if w is a file) inside the transaction that deletes the issues, so
+// a failed write deletes nothing.
+func (s *VCStorage) ArchiveIssues(ctx context.Context, opts types.ArchiveOptions, w io.Writer) (*types.ArchiveStats, error) {
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin archive transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	candidates, pinned, err := archiveCandidates(ctx, tx, opts)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := exportGraph(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	archived := export.ArchiveSet(candidates, graph.Dependencies)
+	archive := export.Subgraph(graph, archived)
+	stats := export.ArchiveStatsFor(archive)
+	stats.Kept = len(candidates) - len(archived) + pinned
+	if opts.DryRun || len(archived) == 0 {
+		return stats, nil
+	}
+
+	if err := export.Write(w, archive); err != nil {
+		return nil, err
+	}
+	if f, ok := w.(interface{ Sync() error }); ok {
+		if err := f.Sync(); err != nil {
+			return nil, fmt.Errorf("failed to sync archive: %w", err)
+		}
+	}
+
+	// Dependencies, labels, events and VC state go with the issue (ON DELETE CASCADE)
+	for _, issue := range archive.Issues {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM issues WHERE id = ?`, issue.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete archived issue %s: %w", issue.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit archive: %w", err)
+	}
+	return stats, nil
+}
+
+// archiveCandidates returns the issues closed before opts.ClosedBefore that
+// have no attachments or custom field values, and how many that do were left
+// out
+func archiveCandidates(ctx context.Context, tx *sql.Tx, opts types.ArchiveOptions) (map[string]bool, int, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, closed_at,
+		       EXISTS (SELECT 1 FROM vc_attachments a WHERE a.issue_id = i.id)
+		       OR EXISTS (SELECT 1 FROM vc_custom_field_values v WHERE v.issue_id = i.id)
+		FROM issues i
+		WHERE status = 'closed' AND closed_at IS NOT NULL
+	`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query closed issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	// closed_at is compared here rather than in SQL: its stored text format
+	// depends on who wrote it
+	candidates := make(map[string]bool)
+	pinned := 0
+	for rows.Next() {
+		var id string
+		var closedAt sql.NullTime
+		var hasExtras bool
+		if err := rows.Scan(&id, &closedAt, &hasExtras); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan closed issue: %w", err)
+		}
+		if !closedAt.Valid || !closedAt.Time.Before(opts.ClosedBefore) {
+			continue
+		}
+		if hasExtras {
+			pinned++
+		} else {
+			candidates[id] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read closed issues: %w", err)
+	}
+	return candidates, pinned, nil
+}
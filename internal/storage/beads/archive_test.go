@@ -0,0 +1,117 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestArchiveIssues(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	longAgo := time.Now().AddDate(0, 0, -200)
+	create := func(title string, closedAt *time.Time) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, AcceptanceCriteria: "Done", IssueType: types.TypeTask,
+			Status: types.StatusOpen, Priority: 2}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if closedAt != nil {
+			if err := store.CloseIssue(ctx, issue.ID, "done", "test"); err != nil {
+				t.Fatalf("CloseIssue failed: %v", err)
+			}
+			if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"closed_at": *closedAt}, "test"); err != nil {
+				t.Fatalf("UpdateIssue failed: %v", err)
+			}
+		}
+		return issue
+	}
+	depend := func(from, to *types.Issue) {
+		t.Helper()
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: from.ID, DependsOnID: to.ID, Type: types.DepBlocks}, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	// An old pair linked only to each other goes together
+	oldA := create("Old A", &longAgo)
+	oldB := create("Old B", &longAgo)
+	depend(oldB, oldA)
+	if err := store.AddComment(ctx, oldA.ID, "test", "history worth keeping"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, oldA.ID, "backend", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	// An old issue an open issue still depends on stays
+	linked := create("Old but linked", &longAgo)
+	depend(create("Open", nil), linked)
+	// So does one holding an attachment
+	attached := create("Old with attachment", &longAgo)
+	if err := store.AddAttachment(ctx, &types.Attachment{IssueID: attached.ID, Name: "log",
+		Kind: types.AttachmentOther, CreatedBy: "test"}, []byte("output")); err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+	now := time.Now()
+	recent := create("Recently closed", &now)
+
+	opts := types.ArchiveOptions{ClosedBefore: time.Now().AddDate(0, 0, -90), DryRun: true}
+	stats, err := store.ArchiveIssues(ctx, opts, nil)
+	if err != nil {
+		t.Fatalf("ArchiveIssues(dry run) failed: %v", err)
+	}
+	if stats.Issues != 2 || stats.Kept != 2 {
+		t.Errorf("expected 2 archived and 2 kept, got %+v", stats)
+	}
+	if issue, _ := store.GetIssue(ctx, oldA.ID); issue == nil {
+		t.Fatal("expected a dry run to delete nothing")
+	}
+
+	opts.DryRun = false
+	var archive bytes.Buffer
+	stats, err = store.ArchiveIssues(ctx, opts, &archive)
+	if err != nil {
+		t.Fatalf("ArchiveIssues failed: %v", err)
+	}
+	if stats.Issues != 2 || stats.Dependencies != 1 || stats.Labels != 1 || stats.Events == 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	for _, issue := range []*types.Issue{oldA, oldB} {
+		if got, _ := store.GetIssue(ctx, issue.ID); got != nil {
+			t.Errorf("expected %s to be archived", issue.ID)
+		}
+	}
+	for _, issue := range []*types.Issue{linked, attached, recent} {
+		if got, _ := store.GetIssue(ctx, issue.ID); got == nil {
+			t.Errorf("expected %s (%s) to stay", issue.ID, issue.Title)
+		}
+	}
+
+	// The archive restores with its history
+	imported, err := store.Import(ctx, &archive)
+	if err != nil {
+		t.Fatalf("Import of the archive failed: %v", err)
+	}
+	if imported.Issues != 2 || imported.Dependencies != 1 {
+		t.Errorf("expected the archive to restore both issues and their dependency, got %+v", imported)
+	}
+	events, err := store.GetEvents(ctx, oldA.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	found := false
+	for _, event := range events {
+		if event.Comment != nil && *event.Comment == "history worth keeping" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected the restored issue to keep its comment")
+	}
+}
@@ -0,0 +1,176 @@
+package beads
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// AddAttachment stores data as a blob and attaches it to attachment.IssueID.
+// ID, ContentHash, Size and CreatedAt are filled in on success. Identical
+// content is stored once no matter how many times it is attached.
+func (s *VCStorage) AddAttachment(ctx context.Context, attachment *types.Attachment, data []byte) error {
+	if err := attachment.Validate(); err != nil {
+		return fmt.Errorf("invalid attachment: %w", err)
+	}
+	if len(data) > types.MaxAttachmentSize {
+		return fmt.Errorf("attachment %q is %d bytes, exceeds limit of %d", attachment.Name, len(data), types.MaxAttachmentSize)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	createdAt := attachment.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO vc_blobs (hash, size, data, created_at) VALUES (?, ?, ?, ?)
+	`, hash, len(data), data, createdAt); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+
+	var executionID sql.NullInt64
+	if attachment.ExecutionID != nil {
+		executionID = sql.NullInt64{Int64: *attachment.ExecutionID, Valid: true}
+	}
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO vc_attachments (issue_id, execution_id, name, kind, blob_hash, created_at, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, attachment.IssueID, executionID, attachment.Name, string(attachment.Kind), hash, createdAt, attachment.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to add attachment to %s: %w", attachment.IssueID, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get attachment ID: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit attachment: %w", err)
+	}
+
+	attachment.ID = id
+	attachment.ContentHash = hash
+	attachment.Size = int64(len(data))
+	attachment.CreatedAt = createdAt
+	return nil
+}
+
+// GetAttachment retrieves an attachment and its content.
+// Returns nil, nil, nil if the attachment doesn't exist.
+func (s *VCStorage) GetAttachment(ctx context.Context, id int64) (*types.Attachment, []byte, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT a.id, a.issue_id, a.execution_id, a.name, a.kind, a.blob_hash, b.size,
+		       a.created_at, a.created_by, b.data
+		FROM vc_attachments a
+		INNER JOIN vc_blobs b ON a.blob_hash = b.hash
+		WHERE a.id = ?
+	`, id)
+
+	var data []byte
+	attachment, err := scanAttachment(row, &data)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get attachment %d: %w", id, err)
+	}
+	return attachment, data, nil
+}
+
+// ListAttachments retrieves attachment metadata for an issue, oldest first
+func (s *VCStorage) ListAttachments(ctx context.Context, issueID string) ([]*types.Attachment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.id, a.issue_id, a.execution_id, a.name, a.kind, a.blob_hash, b.size,
+		       a.created_at, a.created_by
+		FROM vc_attachments a
+		INNER JOIN vc_blobs b ON a.blob_hash = b.hash
+		WHERE a.issue_id = ?
+		ORDER BY a.id ASC
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments for %s: %w", issueID, err)
+	}
+	defer rows.Close()
+
+	var attachments []*types.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// DeleteAttachment removes an attachment, and its blob if nothing else
+// references the same content
+func (s *VCStorage) DeleteAttachment(ctx context.Context, id int64) error {
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var hash string
+	err = tx.QueryRowContext(ctx, `SELECT blob_hash FROM vc_attachments WHERE id = ?`, id).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("attachment %d not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up attachment %d: %w", id, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vc_attachments WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete attachment %d: %w", id, err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM vc_blobs
+		WHERE hash = ? AND NOT EXISTS (SELECT 1 FROM vc_attachments WHERE blob_hash = ?)
+	`, hash, hash); err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// scanAttachment scans an attachment row. If data is non-nil the row must
+// have the blob content as its last column.
+func scanAttachment(row interface{ Scan(...any) error }, data *[]byte) (*types.Attachment, error) {
+	var attachment types.Attachment
+	var executionID sql.NullInt64
+	var kind string
+
+	dest := []any{
+		&attachment.ID, &attachment.IssueID, &executionID, &attachment.Name, &kind,
+		&attachment.ContentHash, &attachment.Size, &attachment.CreatedAt, &attachment.CreatedBy,
+	}
+	if data != nil {
+		dest = append(dest, data)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	attachment.Kind = types.AttachmentKind(kind)
+	if executionID.Valid {
+		attachment.ExecutionID = &executionID.Int64
+	}
+	return &attachment, nil
+}
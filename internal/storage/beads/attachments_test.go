@@ -0,0 +1,112 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestAttachments(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	issue := &types.Issue{
+		Title:              "Task",
+		Status:             types.StatusOpen,
+		Priority:           2,
+		IssueType:          types.TypeTask,
+		AcceptanceCriteria: "Done",
+	}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.RegisterInstance(ctx, &types.ExecutorInstance{
+		InstanceID:    "exec-1",
+		Hostname:      "host",
+		PID:           1,
+		Version:       "test",
+		StartedAt:     time.Now(),
+		LastHeartbeat: time.Now(),
+		Status:        types.ExecutorStatusRunning,
+	}); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	attempt := &types.ExecutionAttempt{
+		IssueID:            issue.ID,
+		ExecutorInstanceID: "exec-1",
+		AttemptNumber:      1,
+		StartedAt:          time.Now(),
+	}
+	if err := store.RecordExecutionAttempt(ctx, attempt); err != nil {
+		t.Fatalf("RecordExecutionAttempt failed: %v", err)
+	}
+
+	// Larger than anything that fits in a comment, and binary-safe
+	transcript := bytes.Repeat([]byte("tool_use\x00result\n"), 50000)
+	first := &types.Attachment{
+		IssueID:     issue.ID,
+		ExecutionID: &attempt.ID,
+		Name:        "agent transcript",
+		Kind:        types.AttachmentTranscript,
+		CreatedBy:   "test",
+	}
+	if err := store.AddAttachment(ctx, first, transcript); err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+	second := &types.Attachment{IssueID: issue.ID, Name: "copy", Kind: types.AttachmentOther}
+	if err := store.AddAttachment(ctx, second, transcript); err != nil {
+		t.Fatalf("AddAttachment (duplicate content) failed: %v", err)
+	}
+	if first.ID == 0 || first.ID == second.ID || first.ContentHash != second.ContentHash {
+		t.Errorf("unexpected attachment metadata: %+v %+v", first, second)
+	}
+
+	var blobs int
+	if err := store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vc_blobs`).Scan(&blobs); err != nil {
+		t.Fatalf("count blobs: %v", err)
+	}
+	if blobs != 1 {
+		t.Errorf("identical content should be stored once, got %d blobs", blobs)
+	}
+
+	if err := store.AddAttachment(ctx, &types.Attachment{IssueID: issue.ID, Name: "x", Kind: "movie"}, nil); err == nil {
+		t.Error("expected invalid kind to be rejected")
+	}
+
+	got, data, err := store.GetAttachment(ctx, first.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetAttachment failed: %v", err)
+	}
+	if !bytes.Equal(data, transcript) {
+		t.Error("attachment content does not round-trip")
+	}
+	if got.ExecutionID == nil || *got.ExecutionID != attempt.ID || got.Size != int64(len(transcript)) ||
+		got.Kind != types.AttachmentTranscript || got.CreatedBy != "test" {
+		t.Errorf("unexpected attachment: %+v", got)
+	}
+
+	list, err := store.ListAttachments(ctx, issue.ID)
+	if err != nil || len(list) != 2 || list[0].ID != first.ID || list[1].ID != second.ID {
+		t.Fatalf("unexpected attachment list: %+v %v", list, err)
+	}
+
+	if err := store.DeleteAttachment(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	if _, data, _ := store.GetAttachment(ctx, second.ID); !bytes.Equal(data, transcript) {
+		t.Error("deleting one attachment removed shared content")
+	}
+	if err := store.DeleteAttachment(ctx, second.ID); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	if err := store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vc_blobs`).Scan(&blobs); err != nil || blobs != 0 {
+		t.Errorf("unreferenced blob not removed: %d %v", blobs, err)
+	}
+	if got, _, err := store.GetAttachment(ctx, first.ID); got != nil || err != nil {
+		t.Errorf("expected nil for deleted attachment, got %+v %v", got, err)
+	}
+}
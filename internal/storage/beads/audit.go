@@ -0,0 +1,100 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertAuditEntries writes audit entries, stamping them with the current time
+func insertAuditEntries(ctx context.Context, db execer, entries []*types.AuditEntry) error {
+	now := time.Now()
+	for _, entry := range entries {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO vc_audit_log (issue_id, operation, field, old_value, new_value, actor, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, entry.IssueID, string(entry.Operation), entry.Field, entry.OldValue, entry.NewValue, entry.Actor, now); err != nil {
+			return fmt.Errorf("failed to record audit entry for %s: %w", entry.IssueID, err)
+		}
+		entry.CreatedAt = now
+	}
+	return nil
+}
+
+// recordAudit writes audit entries for a mutation Beads has already committed.
+// The mutation stands even if this fails, so failures are only logged.
+func (s *VCStorage) recordAudit(ctx context.Context, entries []*types.AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := insertAuditEntries(ctx, s.db, entries); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// auditStatusChangeTx records a raw-SQL status change made inside tx, before
+// the issues row is updated
+func auditStatusChangeTx(ctx context.Context, tx *sql.Tx, issueID string, newStatus types.Status, actor string) error {
+	var oldStatus string
+	if err := tx.QueryRowContext(ctx, `SELECT status FROM issues WHERE id = ?`, issueID).Scan(&oldStatus); err != nil {
+		return fmt.Errorf("failed to read status of %s for audit: %w", issueID, err)
+	}
+	if oldStatus == string(newStatus) {
+		return nil
+	}
+	return insertAuditEntries(ctx, tx, []*types.AuditEntry{{
+		IssueID:   issueID,
+		Operation: types.AuditUpdate,
+		Field:     "status",
+		OldValue:  oldStatus,
+		NewValue:  string(newStatus),
+		Actor:     actor,
+	}})
+}
+
+// GetAuditLog returns field-level changes to an issue, newest first.
+// limit <= 0 means no limit.
+func (s *VCStorage) GetAuditLog(ctx context.Context, issueID string, limit int) ([]*types.AuditEntry, error) {
+	query := `
+		SELECT id, issue_id, operation, field, old_value, new_value, actor, created_at
+		FROM vc_audit_log
+		WHERE issue_id = ?
+		ORDER BY id DESC
+	`
+	args := []interface{}{issueID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*types.AuditEntry
+	for rows.Next() {
+		var entry types.AuditEntry
+		var operation string
+		if err := rows.Scan(&entry.ID, &entry.IssueID, &operation, &entry.Field,
+			&entry.OldValue, &entry.NewValue, &entry.Actor, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.Operation = types.AuditOperation(operation)
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,146 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	issue := &types.Issue{
+		Title:              "Audited task",
+		Status:             types.StatusOpen,
+		Priority:           2,
+		IssueType:          types.TypeTask,
+		AcceptanceCriteria: "Done",
+	}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"notes": "first note"}, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.RegisterInstance(ctx, &types.ExecutorInstance{
+		InstanceID:    "exec-1",
+		Hostname:      "host",
+		PID:           1,
+		Version:       "test",
+		StartedAt:     time.Now(),
+		LastHeartbeat: time.Now(),
+		Status:        types.ExecutorStatusRunning,
+	}); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, "exec-1"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "done", "carol"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	entries, err := store.GetAuditLog(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+
+	byField := func(op types.AuditOperation, field, actor string) *types.AuditEntry {
+		for _, entry := range entries {
+			if entry.Operation == op && entry.Field == field && entry.Actor == actor {
+				return entry
+			}
+		}
+		return nil
+	}
+	if e := byField(types.AuditCreate, "title", "alice"); e == nil || e.NewValue != "Audited task" {
+		t.Errorf("expected create entry for title, got %+v", e)
+	}
+	if e := byField(types.AuditUpdate, "notes", "bob"); e == nil || e.OldValue != "" || e.NewValue != "first note" {
+		t.Errorf("expected notes update by bob, got %+v", e)
+	}
+	if e := byField(types.AuditUpdate, "status", "exec-1"); e == nil || e.OldValue != "open" || e.NewValue != "in_progress" {
+		t.Errorf("expected claim status change, got %+v", e)
+	}
+	if e := byField(types.AuditUpdate, "status", "carol"); e == nil || e.OldValue != "in_progress" || e.NewValue != "closed" {
+		t.Errorf("expected close status change, got %+v", e)
+	}
+	if e := byField(types.AuditUpdate, "closed_at", "carol"); e == nil || e.NewValue == "" {
+		t.Errorf("expected closed_at to be audited, got %+v", e)
+	}
+	if entries[0].Actor != "carol" || entries[len(entries)-1].Operation != types.AuditCreate {
+		t.Errorf("expected newest-first order, got first %+v, last %+v", entries[0], entries[len(entries)-1])
+	}
+	for _, entry := range entries {
+		if entry.CreatedAt.IsZero() {
+			t.Errorf("entry %d has no timestamp", entry.ID)
+		}
+	}
+
+	if limited, _ := store.GetAuditLog(ctx, issue.ID, 1); len(limited) != 1 {
+		t.Errorf("expected limit to apply, got %d entries", len(limited))
+	}
+}
+
+func TestAuditLogTransactions(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	issue := &types.Issue{
+		Title:              "Transactional",
+		Status:             types.StatusOpen,
+		Priority:           2,
+		IssueType:          types.TypeTask,
+		AcceptanceCriteria: "Done",
+	}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// A rolled back update leaves no audit entry
+	_ = store.RunInVCTransaction(ctx, func(tx *VCTransaction) error {
+		if err := tx.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Never"}, "bob"); err != nil {
+			return err
+		}
+		return fmt.Errorf("abort")
+	})
+	entries, _ := store.GetAuditLog(ctx, issue.ID, 0)
+	for _, entry := range entries {
+		if entry.Operation != types.AuditCreate {
+			t.Errorf("expected only create entries after rollback, got %+v", entry)
+		}
+	}
+
+	// A committed update and delete are recorded, and survive the delete
+	err := store.RunInVCTransaction(ctx, func(tx *VCTransaction) error {
+		if err := tx.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 0}, "bob"); err != nil {
+			return err
+		}
+		return tx.DeleteIssue(ctx, issue.ID)
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+	entries, err = store.GetAuditLog(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if len(entries) == 0 || entries[0].Operation != types.AuditDelete {
+		t.Fatalf("expected delete entries first, got %+v", entries)
+	}
+	var sawPriority bool
+	for _, entry := range entries {
+		if entry.Field == "priority" && entry.Operation == types.AuditUpdate && entry.NewValue == "0" && entry.Actor == "bob" {
+			sawPriority = true
+		}
+	}
+	if !sawPriority {
+		t.Error("expected committed priority change to be audited")
+	}
+}
@@ -0,0 +1,149 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// CloneIssue copies an issue, and optionally its subtree and dependencies,
+// as new open issues. Execution history, events and attachments stay with
+// the originals. Issues, labels and dependencies are created in one
+// transaction; mission state and custom fields are copied after it commits,
+// the same way CreateIssue adds mission state.
+func (s *VCStorage) CloneIssue(ctx context.Context, id string, opts types.CloneOptions, actor string) (*types.CloneResult, error) {
+	root, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("issue %s not found", id)
+	}
+
+	// Originals, parents before children
+	order := []string{id}
+	if opts.IncludeChildren {
+		seen := map[string]bool{id: true}
+		for i := 0; i < len(order); i++ {
+			children, err := s.childIDs(ctx, order[i])
+			if err != nil {
+				return nil, err
+			}
+			for _, child := range children {
+				if !seen[child] {
+					seen[child] = true
+					order = append(order, child)
+				}
+			}
+		}
+	}
+
+	originals := make(map[string]*types.Issue, len(order))
+	labels := make(map[string][]string, len(order))
+	deps := make(map[string][]*types.Dependency, len(order))
+	for _, original := range order {
+		if originals[original], err = s.GetIssue(ctx, original); err != nil {
+			return nil, err
+		}
+		if labels[original], err = s.GetLabels(ctx, original); err != nil {
+			return nil, fmt.Errorf("failed to get labels of %s: %w", original, err)
+		}
+		if deps[original], err = s.GetDependencyRecords(ctx, original); err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", original, err)
+		}
+	}
+
+	result := &types.CloneResult{IDs: make(map[string]string, len(order))}
+	err = s.RunInVCTransaction(ctx, func(tx *VCTransaction) error {
+		for _, original := range order {
+			clone := types.NewClone(originals[original], opts)
+			if err := tx.CreateIssue(ctx, clone, actor); err != nil {
+				return fmt.Errorf("failed to clone %s: %w", original, err)
+			}
+			result.IDs[original] = clone.ID
+			for _, label := range labels[original] {
+				if !types.CopiesLabelOnClone(label) {
+					continue
+				}
+				if err := tx.AddLabel(ctx, clone.ID, label, actor); err != nil {
+					return fmt.Errorf("failed to copy label %s to %s: %w", label, clone.ID, err)
+				}
+			}
+		}
+		for _, original := range order {
+			for _, dep := range deps[original] {
+				if !dep.ClonedWith(result.IDs, opts) {
+					continue
+				}
+				target := dep.DependsOnID
+				if targetClone, ok := result.IDs[target]; ok {
+					target = targetClone
+				}
+				if err := tx.AddDependency(ctx, &types.Dependency{IssueID: result.IDs[original], DependsOnID: target, Type: dep.Type}, actor); err != nil {
+					return fmt.Errorf("failed to copy dependency %s -> %s: %w", original, dep.DependsOnID, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, original := range order {
+		if originals[original].IssueSubtype == "" || originals[original].IssueSubtype == types.SubtypeNormal {
+			continue
+		}
+		if _, err := s.db.ExecContext(ctx, `
+			INSERT INTO vc_mission_state (issue_id, subtype, goal, context, approval_required, created_at, updated_at)
+			SELECT ?, subtype, goal, context, approval_required, ?, ?
+			FROM vc_mission_state WHERE issue_id = ?
+		`, result.IDs[original], now, now, original); err != nil {
+			return nil, fmt.Errorf("failed to copy mission state of %s: %w", original, err)
+		}
+	}
+
+	for _, original := range order {
+		if _, err := s.db.ExecContext(ctx, `
+			INSERT INTO vc_custom_field_values (issue_id, name, value, updated_at)
+			SELECT ?, name, value, ?
+			FROM vc_custom_field_values WHERE issue_id = ?
+		`, result.IDs[original], now, original); err != nil {
+			return nil, fmt.Errorf("failed to copy custom fields of %s: %w", original, err)
+		}
+	}
+
+	if result.Root, err = s.GetIssue(ctx, result.IDs[id]); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// childIDs returns the IDs of an issue's parent-child children
+func (s *VCStorage) childIDs(ctx context.Context, parentID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT issue_id FROM dependencies
+		WHERE depends_on_id = ? AND type = ?
+		ORDER BY issue_id
+	`, parentID, types.DepParentChild)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query children of %s: %w", parentID, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan child id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating children: %w", err)
+	}
+	return ids, nil
+}
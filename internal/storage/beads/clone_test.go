@@ -0,0 +1,112 @@
+package beads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestCloneIssue(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	mission := &types.Mission{
+		Issue: types.Issue{Title: "Release", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeEpic,
+			IssueSubtype: types.SubtypeMission, Assignee: "alice", AcceptanceCriteria: "Shipped"},
+		Goal:       "Ship the release",
+		BranchName: "mission/release",
+	}
+	if err := store.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("CreateMission failed: %v", err)
+	}
+	newTask := func(title string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	build, test, outside := newTask("Build"), newTask("Test"), newTask("Outside")
+	for _, dep := range []*types.Dependency{
+		{IssueID: build.ID, DependsOnID: mission.ID, Type: types.DepParentChild},
+		{IssueID: test.ID, DependsOnID: mission.ID, Type: types.DepParentChild},
+		{IssueID: test.ID, DependsOnID: build.ID, Type: types.DepBlocks},
+		{IssueID: build.ID, DependsOnID: outside.ID, Type: types.DepBlocks},
+	} {
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	for _, label := range []string{"release", "gates-failed"} {
+		if err := store.AddLabel(ctx, build.ID, label, "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	t.Run("root only", func(t *testing.T) {
+		result, err := store.CloneIssue(ctx, build.ID, types.CloneOptions{TitleSuffix: " (M2)"}, "test")
+		if err != nil {
+			t.Fatalf("CloneIssue failed: %v", err)
+		}
+		if len(result.IDs) != 1 || result.Root.Title != "Build (M2)" || result.Root.ID == build.ID {
+			t.Errorf("unexpected clone: %+v", result)
+		}
+		labels, _ := store.GetLabels(ctx, result.Root.ID)
+		if len(labels) != 1 || labels[0] != "release" {
+			t.Errorf("expected only the release label, got %v", labels)
+		}
+		if deps, _ := store.GetDependencyRecords(ctx, result.Root.ID); len(deps) != 0 {
+			t.Errorf("expected no dependencies, got %d", len(deps))
+		}
+	})
+
+	t.Run("subtree with dependencies", func(t *testing.T) {
+		result, err := store.CloneIssue(ctx, mission.ID, types.CloneOptions{IncludeChildren: true, IncludeDependencies: true}, "test")
+		if err != nil {
+			t.Fatalf("CloneIssue failed: %v", err)
+		}
+		if len(result.IDs) != 3 {
+			t.Fatalf("expected 3 issues cloned, got %v", result.IDs)
+		}
+		root := result.Root
+		if root.Status != types.StatusOpen || root.Assignee != "" || root.IssueSubtype != types.SubtypeMission {
+			t.Errorf("expected an open, unassigned mission, got %+v", root)
+		}
+		cloned, err := store.GetMission(ctx, root.ID)
+		if err != nil {
+			t.Fatalf("GetMission failed: %v", err)
+		}
+		if cloned.Goal != "Ship the release" || cloned.BranchName != "" {
+			t.Errorf("expected the goal but not the branch to be copied, got %+v", cloned)
+		}
+
+		want := map[string]types.DependencyType{
+			root.ID:    types.DepParentChild,
+			outside.ID: types.DepBlocks,
+		}
+		deps, _ := store.GetDependencyRecords(ctx, result.IDs[build.ID])
+		if len(deps) != 2 {
+			t.Fatalf("expected 2 dependencies on the cloned build, got %+v", deps)
+		}
+		for _, dep := range deps {
+			if want[dep.DependsOnID] != dep.Type {
+				t.Errorf("unexpected dependency %s -> %s (%s)", dep.IssueID, dep.DependsOnID, dep.Type)
+			}
+		}
+		deps, _ = store.GetDependencyRecords(ctx, result.IDs[test.ID])
+		if len(deps) != 2 {
+			t.Errorf("expected the cloned test to keep its parent and blocker, got %+v", deps)
+		}
+		for _, dep := range deps {
+			if dep.DependsOnID != root.ID && dep.DependsOnID != result.IDs[build.ID] {
+				t.Errorf("expected dependencies among the clones, got %s -> %s", dep.IssueID, dep.DependsOnID)
+			}
+		}
+	})
+
+	if _, err := store.CloneIssue(ctx, "vc-missing", types.CloneOptions{}, "test"); err == nil {
+		t.Error("expected cloning a missing issue to fail")
+	}
+}
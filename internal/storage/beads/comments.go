@@ -0,0 +1,287 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// THREADED COMMENTS (replies, edit history and reactions on "commented" events)
+// ======================================================================
+
+// GetComments returns an issue's comments oldest first, flat, with their
+// parent, edit history and reactions
+func (s *VCStorage) GetComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.id, e.actor, e.comment, e.created_at, r.parent_id
+		FROM events e
+		LEFT JOIN vc_comment_replies r ON r.comment_id = e.id
+		WHERE e.issue_id = ? AND e.event_type = ?
+		ORDER BY e.created_at, e.id
+	`, issueID, types.EventCommented)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments of %s: %w", issueID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var comments []*types.Comment
+	byID := make(map[int64]*types.Comment)
+	for rows.Next() {
+		c := &types.Comment{IssueID: issueID}
+		var body sql.NullString
+		var parentID sql.NullInt64
+		if err := rows.Scan(&c.ID, &c.Author, &body, &c.CreatedAt, &parentID); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		c.Body = body.String
+		if parentID.Valid {
+			c.ParentID = &parentID.Int64
+		}
+		comments = append(comments, c)
+		byID[c.ID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read comments of %s: %w", issueID, err)
+	}
+	if len(comments) == 0 {
+		return comments, nil
+	}
+
+	if err := s.loadCommentEdits(ctx, issueID, byID); err != nil {
+		return nil, err
+	}
+	if err := s.loadCommentReactions(ctx, issueID, byID); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// loadCommentEdits fills in the edit history and current body of an issue's comments
+func (s *VCStorage) loadCommentEdits(ctx context.Context, issueID string, byID map[int64]*types.Comment) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ce.comment_id, ce.previous_body, ce.body, ce.edited_by, ce.edited_at
+		FROM vc_comment_edits ce
+		INNER JOIN events e ON e.id = ce.comment_id
+		WHERE e.issue_id = ?
+		ORDER BY ce.id
+	`, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to query comment edits of %s: %w", issueID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var commentID int64
+		var body string
+		edit := &types.CommentEdit{}
+		if err := rows.Scan(&commentID, &edit.PreviousBody, &body, &edit.EditedBy, &edit.EditedAt); err != nil {
+			return fmt.Errorf("failed to scan comment edit: %w", err)
+		}
+		if c := byID[commentID]; c != nil {
+			c.Edits = append(c.Edits, edit)
+			c.Body = body
+			editedAt := edit.EditedAt
+			c.EditedAt = &editedAt
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read comment edits of %s: %w", issueID, err)
+	}
+	return nil
+}
+
+// loadCommentReactions fills in the reactions to an issue's comments
+func (s *VCStorage) loadCommentReactions(ctx context.Context, issueID string, byID map[int64]*types.Comment) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cr.comment_id, cr.reaction, cr.actor, cr.created_at
+		FROM vc_comment_reactions cr
+		INNER JOIN events e ON e.id = cr.comment_id
+		WHERE e.issue_id = ?
+		ORDER BY cr.created_at, cr.reaction, cr.actor
+	`, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to query comment reactions of %s: %w", issueID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var commentID int64
+		reaction := &types.CommentReaction{}
+		if err := rows.Scan(&commentID, &reaction.Reaction, &reaction.Actor, &reaction.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan comment reaction: %w", err)
+		}
+		if c := byID[commentID]; c != nil {
+			c.Reactions = append(c.Reactions, reaction)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read comment reactions of %s: %w", issueID, err)
+	}
+	return nil
+}
+
+// ReplyToComment adds body as a comment on the parent comment's issue,
+// threaded under it. Like AddComment, it bumps the issue's updated_at and
+// marks it dirty for export.
+func (s *VCStorage) ReplyToComment(ctx context.Context, parentID int64, actor, body string) (*types.Comment, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("comment body is required")
+	}
+
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	issueID, _, _, err := getCommentTx(ctx, tx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `UPDATE issues SET updated_at = ? WHERE id = ?`, now, issueID); err != nil {
+		return nil, fmt.Errorf("failed to update timestamp: %w", err)
+	}
+	// Stored like CURRENT_TIMESTAMP so the reply sorts among the other events
+	createdAt := now.UTC().Truncate(time.Second)
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, issueID, types.EventCommented, actor, body, createdAt.Format(sqliteTimestampFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add reply: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reply ID: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO vc_comment_replies (comment_id, parent_id) VALUES (?, ?)
+	`, id, parentID); err != nil {
+		return nil, fmt.Errorf("failed to thread reply: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dirty_issues (issue_id, marked_at)
+		VALUES (?, ?)
+		ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+	`, issueID, now); err != nil {
+		return nil, fmt.Errorf("failed to mark issue %s dirty: %w", issueID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit reply: %w", err)
+	}
+	return &types.Comment{
+		ID:        id,
+		IssueID:   issueID,
+		ParentID:  &parentID,
+		Author:    actor,
+		Body:      body,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// EditComment replaces a comment's body with body, recording the previous
+// one. Only the comment's author can edit it; the original event is kept
+// as it was.
+func (s *VCStorage) EditComment(ctx context.Context, commentID int64, actor, body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("comment body is required")
+	}
+
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, author, current, err := getCommentTx(ctx, tx, commentID)
+	if err != nil {
+		return err
+	}
+	if author != actor {
+		return fmt.Errorf("comment %d can only be edited by its author (%s)", commentID, author)
+	}
+	if body == current {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO vc_comment_edits (comment_id, previous_body, body, edited_by, edited_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, commentID, current, body, actor, time.Now()); err != nil {
+		return fmt.Errorf("failed to edit comment %d: %w", commentID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit comment edit: %w", err)
+	}
+	return nil
+}
+
+// AddReaction records actor's reaction to a comment. Reacting twice with
+// the same reaction does nothing.
+func (s *VCStorage) AddReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	if err := types.ValidateReaction(reaction); err != nil {
+		return err
+	}
+
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, _, _, err := getCommentTx(ctx, tx, commentID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO vc_comment_reactions (comment_id, actor, reaction, created_at)
+		VALUES (?, ?, ?, ?)
+	`, commentID, actor, reaction, time.Now()); err != nil {
+		return fmt.Errorf("failed to add reaction to comment %d: %w", commentID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveReaction removes actor's reaction from a comment, if present
+func (s *VCStorage) RemoveReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM vc_comment_reactions WHERE comment_id = ? AND actor = ? AND reaction = ?
+	`, commentID, actor, reaction)
+	if err != nil {
+		return fmt.Errorf("failed to remove reaction from comment %d: %w", commentID, err)
+	}
+	return nil
+}
+
+// getCommentTx returns the issue, author and current body of a comment,
+// failing if commentID isn't a "commented" event
+func getCommentTx(ctx context.Context, tx *sql.Tx, commentID int64) (issueID, author, body string, err error) {
+	var comment, edited sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT e.issue_id, e.actor, e.comment,
+		       (SELECT ce.body FROM vc_comment_edits ce WHERE ce.comment_id = e.id ORDER BY ce.id DESC LIMIT 1)
+		FROM events e
+		WHERE e.id = ? AND e.event_type = ?
+	`, commentID, types.EventCommented).Scan(&issueID, &author, &comment, &edited)
+	if err == sql.ErrNoRows {
+		return "", "", "", fmt.Errorf("comment %d not found", commentID)
+	}
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get comment %d: %w", commentID, err)
+	}
+	if edited.Valid {
+		return issueID, author, edited.String, nil
+	}
+	return issueID, author, comment.String, nil
+}
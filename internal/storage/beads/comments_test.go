@@ -0,0 +1,93 @@
+package beads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestThreadedComments(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	issue := &types.Issue{Title: "Task", AcceptanceCriteria: "Done", IssueType: types.TypeTask,
+		Status: types.StatusOpen, Priority: 2}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.AddComment(ctx, issue.ID, "alice", "Should this retry?"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("GetComments = %v, %v; want one comment", comments, err)
+	}
+	root := comments[0]
+
+	reply, err := store.ReplyToComment(ctx, root.ID, "ai-supervisor", "Yes, with backoff")
+	if err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if _, err := store.ReplyToComment(ctx, reply.ID, "alice", "Agreed"); err != nil {
+		t.Fatalf("ReplyToComment to a reply failed: %v", err)
+	}
+	if err := store.EditComment(ctx, root.ID, "bob", "Hijacked"); err == nil {
+		t.Error("expected an edit by someone other than the author to fail")
+	}
+	for _, body := range []string{"Should this retry on timeout?", "Should this retry on any error?"} {
+		if err := store.EditComment(ctx, root.ID, "alice", body); err != nil {
+			t.Fatalf("EditComment failed: %v", err)
+		}
+	}
+	for _, actor := range []string{"alice", "alice", "bob"} {
+		if err := store.AddReaction(ctx, reply.ID, actor, "+1"); err != nil {
+			t.Fatalf("AddReaction failed: %v", err)
+		}
+	}
+
+	comments, err = store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	threads := types.ThreadComments(comments)
+	if len(threads) != 1 || len(threads[0].Replies) != 1 || len(threads[0].Replies[0].Replies) != 1 {
+		t.Fatalf("expected a three-deep thread, got %+v", threads)
+	}
+	root = threads[0]
+	if root.Body != "Should this retry on any error?" || len(root.Edits) != 2 ||
+		root.Edits[0].PreviousBody != "Should this retry?" ||
+		root.Edits[1].PreviousBody != "Should this retry on timeout?" {
+		t.Errorf("expected the latest body and both edits, got body=%q edits=%+v", root.Body, root.Edits)
+	}
+	if got := root.Replies[0].ReactionCounts()["+1"]; got != 2 {
+		t.Errorf("expected two +1 reactions, got %d", got)
+	}
+
+	// The original event is untouched
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	found := false
+	for _, event := range events {
+		if event.ID == root.ID && event.Comment != nil && *event.Comment == "Should this retry?" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected the comment event to keep its original text")
+	}
+
+	if err := store.RemoveReaction(ctx, reply.ID, "bob", "+1"); err != nil {
+		t.Fatalf("RemoveReaction failed: %v", err)
+	}
+	comments, _ = store.GetComments(ctx, issue.ID)
+	if got := comments[1].ReactionCounts()["+1"]; got != 1 {
+		t.Errorf("expected one +1 reaction left, got %d", got)
+	}
+	if _, err := store.ReplyToComment(ctx, 999999, "alice", "lost"); err == nil {
+		t.Error("expected a reply to a missing comment to fail")
+	}
+}
@@ -457,3 +457,193 @@ func TestConcurrentClaimDifferentIssues(t *testing.T) {
 func testInstanceID(i int) string {
 	return fmt.Sprintf("test-executor-%d", i)
 }
+
+// TestConcurrentClaimNextReadyIssue verifies that workers racing through
+// ClaimNextReadyIssue never get the same issue, and that an expired lease is
+// taken over by another worker
+func TestConcurrentClaimNextReadyIssue(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	numWorkers := 4
+	workerIDs := make([]string, numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		workerIDs[i] = testInstanceID(i)
+		instance := &types.ExecutorInstance{
+			InstanceID:    workerIDs[i],
+			Hostname:      "localhost",
+			PID:           12345 + i,
+			Version:       "test",
+			StartedAt:     time.Now(),
+			LastHeartbeat: time.Now(),
+			Status:        "running",
+		}
+		if err := store.RegisterInstance(ctx, instance); err != nil {
+			t.Fatalf("Failed to register worker %d: %v", i, err)
+		}
+	}
+
+	numIssues := 3
+	for i := 0; i < numIssues; i++ {
+		issue := &types.Issue{
+			Title:              fmt.Sprintf("Claim race issue %d", i),
+			Status:             types.StatusOpen,
+			Priority:           1,
+			IssueType:          types.TypeTask,
+			AcceptanceCriteria: "Test acceptance criteria",
+		}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue %d: %v", i, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	claimedBy := make(map[string]string)
+	for _, workerID := range workerIDs {
+		wg.Add(1)
+		go func(workerID string) {
+			defer wg.Done()
+			issue, err := store.ClaimNextReadyIssue(ctx, workerID, time.Minute, "")
+			if err != nil {
+				if !contains(err.Error(), "database is locked") {
+					t.Errorf("ClaimNextReadyIssue failed for %s: %v", workerID, err)
+				}
+				return
+			}
+			if issue == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if other, dup := claimedBy[issue.ID]; dup {
+				t.Errorf("Issue %s claimed by both %s and %s", issue.ID, other, workerID)
+			}
+			claimedBy[issue.ID] = workerID
+		}(workerID)
+	}
+	wg.Wait()
+
+	if len(claimedBy) == 0 {
+		t.Fatal("Expected at least one issue to be claimed")
+	}
+
+	// Let one lease lapse; an idle worker should take that issue over
+	var expiredID, owner string
+	for id, workerID := range claimedBy {
+		expiredID, owner = id, workerID
+		break
+	}
+	if err := store.RenewClaimLease(ctx, expiredID, owner, time.Millisecond); err != nil {
+		t.Fatalf("RenewClaimLease failed: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	var idle string
+	for _, workerID := range workerIDs {
+		if workerID != owner {
+			idle = workerID
+			break
+		}
+	}
+	// Drain any issues still open so the expired one is the only candidate left
+	for {
+		issue, err := store.ClaimNextReadyIssue(ctx, idle, time.Minute, "")
+		if err != nil {
+			t.Fatalf("ClaimNextReadyIssue failed: %v", err)
+		}
+		if issue == nil {
+			t.Fatalf("Expected expired lease on %s to be taken over", expiredID)
+		}
+		if issue.ID == expiredID {
+			break
+		}
+	}
+
+	state, err := store.GetExecutionState(ctx, expiredID)
+	if err != nil {
+		t.Fatalf("Failed to get execution state: %v", err)
+	}
+	if state.ExecutorInstanceID != idle {
+		t.Errorf("Expected %s to own %s after takeover, got %s", idle, expiredID, state.ExecutorInstanceID)
+	}
+	if state.LeaseExpiresAt == nil || !state.LeaseExpiresAt.After(time.Now()) {
+		t.Errorf("Expected a fresh lease after takeover, got %v", state.LeaseExpiresAt)
+	}
+	if err := store.RenewClaimLease(ctx, expiredID, owner, time.Minute); err == nil {
+		t.Error("Expected the previous owner to be unable to renew a lease that was taken over")
+	}
+}
+
+// TestClaimNextReadyIssueProject verifies that a claim scoped to a project
+// neither claims nor takes over another project's issues
+func TestClaimNextReadyIssueProject(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	for i := 0; i < 2; i++ {
+		instance := &types.ExecutorInstance{
+			InstanceID:    testInstanceID(i),
+			Hostname:      "localhost",
+			PID:           12345 + i,
+			Version:       "test",
+			StartedAt:     time.Now(),
+			LastHeartbeat: time.Now(),
+			Status:        "running",
+		}
+		if err := store.RegisterInstance(ctx, instance); err != nil {
+			t.Fatalf("Failed to register worker %d: %v", i, err)
+		}
+	}
+	for _, name := range []string{"web", "api"} {
+		if err := store.SaveProject(ctx, &types.Project{Name: name}); err != nil {
+			t.Fatalf("SaveProject(%s) failed: %v", name, err)
+		}
+	}
+	newIssue := func(project string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: project + " task", Status: types.StatusOpen, Priority: 1,
+			IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		if err := store.SetIssueProject(ctx, issue.ID, project, "test"); err != nil {
+			t.Fatalf("SetIssueProject failed: %v", err)
+		}
+		return issue
+	}
+
+	// One api issue with an expired lease, another open
+	expired := newIssue("api")
+	if claimed, err := store.ClaimNextReadyIssue(ctx, testInstanceID(0), time.Millisecond, "api"); err != nil || claimed == nil || claimed.ID != expired.ID {
+		t.Fatalf("ClaimNextReadyIssue(api) = %+v, %v, want %s", claimed, err, expired.ID)
+	}
+	open := newIssue("api")
+	time.Sleep(10 * time.Millisecond)
+
+	if claimed, err := store.ClaimNextReadyIssue(ctx, testInstanceID(1), time.Minute, "web"); err != nil || claimed != nil {
+		t.Fatalf("ClaimNextReadyIssue(web) with no web work = %+v, %v, want nothing", claimed, err)
+	}
+
+	web := newIssue("web")
+	if claimed, err := store.ClaimNextReadyIssue(ctx, testInstanceID(1), time.Minute, "web"); err != nil || claimed == nil || claimed.ID != web.ID {
+		t.Fatalf("ClaimNextReadyIssue(web) = %+v, %v, want %s", claimed, err, web.ID)
+	}
+	if issue, _ := store.GetIssue(ctx, open.ID); issue.Status != types.StatusOpen {
+		t.Errorf("api issue %s claimed by a web claim: %s", open.ID, issue.Status)
+	}
+	if state, _ := store.GetExecutionState(ctx, expired.ID); state == nil || state.ExecutorInstanceID != testInstanceID(0) {
+		t.Errorf("expired api lease taken over by a web claim: %+v", state)
+	}
+}
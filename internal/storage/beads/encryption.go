@@ -0,0 +1,121 @@
+package beads
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ncruces/go-sqlite3/util/vfsutil"
+	"github.com/ncruces/go-sqlite3/vfs"
+	// Registers the "xts" VFS, which encrypts database, journal and WAL files
+	_ "github.com/ncruces/go-sqlite3/vfs/xts"
+)
+
+// Encryption at rest.
+//
+// The database holds full prompts, diffs and code excerpts, so it can be
+// encrypted with a passphrase. Encrypted databases are opened through the
+// "xts" VFS (AES-XTS, key derived from the passphrase with PBKDF2), which
+// encrypts every page along with the journal and WAL. Beads owns the
+// connection pool and keeps the URI it was opened with, so the passphrase is
+// not put in it: each passphrase gets its own VFS, registered in this
+// process, that keys the database file itself (see keyedVFS).
+//
+// An encrypted database can't be opened without its passphrase and a plain
+// one can't be opened with a passphrase: both fail with "file is not a
+// database". Use `vc backup --passphrase-env` to convert between the two.
+
+// PassphraseEnvVar names the environment variable holding the database
+// passphrase. Unset or empty means the database is not encrypted.
+const PassphraseEnvVar = "VC_DB_PASSPHRASE"
+
+// uriPathEscaper escapes the characters SQLite URI paths give meaning to
+var uriPathEscaper = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23")
+
+// DatabaseURI returns a URI opening the database file at dbPath, encrypted
+// with passphrase unless it is empty. params are extra URI parameters such
+// as "mode=ro".
+func DatabaseURI(dbPath, passphrase string, params ...string) string {
+	if passphrase != "" {
+		params = append([]string{"vfs=" + keyedVFSName(passphrase)}, params...)
+	}
+	uri := "file:" + uriPathEscaper.Replace(dbPath)
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+	return uri
+}
+
+// keyedVFS is the "xts" VFS with a passphrase built in. It sets the key on
+// every main database file it opens, the way "PRAGMA textkey" would; journal
+// and WAL files take the key of their database.
+type keyedVFS struct {
+	vfs.VFS
+	passphrase string
+}
+
+func (k keyedVFS) OpenFilename(name *vfs.Filename, flags vfs.OpenFlag) (vfs.File, vfs.OpenFlag, error) {
+	file, flags, err := vfsutil.WrapOpenFilename(k.VFS, name, flags)
+	if err != nil || flags&vfs.OPEN_MAIN_DB == 0 {
+		return file, flags, err
+	}
+	if _, err := vfsutil.WrapPragma(file, "textkey", k.passphrase); err != nil {
+		_ = file.Close()
+		return nil, flags, err
+	}
+	return file, flags, nil
+}
+
+var (
+	keyedVFSMu    sync.Mutex
+	keyedVFSNames = map[string]string{}
+)
+
+// keyedVFSName returns the name of the keyedVFS for passphrase, registering
+// it on first use. Names are opaque and say nothing about the passphrase.
+func keyedVFSName(passphrase string) string {
+	keyedVFSMu.Lock()
+	defer keyedVFSMu.Unlock()
+	if name, ok := keyedVFSNames[passphrase]; ok {
+		return name
+	}
+	name := fmt.Sprintf("vc-xts-%d", len(keyedVFSNames)+1)
+	vfs.Register(name, keyedVFS{VFS: vfs.Find("xts"), passphrase: passphrase})
+	keyedVFSNames[passphrase] = name
+	return name
+}
+
+// beadsURI rewrites a URI from DatabaseURI for Beads, which records
+// filepath.Abs of whatever it opens as its database path and looks for
+// issues.jsonl in that directory. Abs would put the URI under the working
+// directory, so the path is prefixed with one "/.." for the "file:" segment
+// and one per directory of the working directory: the path SQLite opens is
+// unchanged and Abs comes out as the database file plus the query.
+func beadsURI(uri string) string {
+	path := strings.TrimPrefix(uri, "file:")
+	wd, err := os.Getwd()
+	if err != nil || !strings.HasPrefix(path, "/") || !filepath.IsAbs(path) {
+		return uri
+	}
+	return "file:" + strings.Repeat("/..", strings.Count(wd, "/")+1) + path
+}
+
+// isNotADatabaseError checks if an error is SQLite's SQLITE_NOTADB, which is
+// what a wrong or missing passphrase looks like
+func isNotADatabaseError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "file is not a database")
+}
+
+// explainOpenError adds a passphrase hint to errors from opening a database
+// that is encrypted differently than expected
+func explainOpenError(err error, dbPath, passphrase string) error {
+	if !isNotADatabaseError(err) {
+		return err
+	}
+	if passphrase == "" {
+		return fmt.Errorf("%w (if %s is encrypted, set %s)", err, dbPath, PassphraseEnvVar)
+	}
+	return fmt.Errorf("%w (%s is not encrypted with this passphrase; check %s)", err, dbPath, PassphraseEnvVar)
+}
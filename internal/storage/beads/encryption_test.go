@@ -0,0 +1,90 @@
+package beads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestEncryptedVCStorage(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), ".beads")
+	dbPath := filepath.Join(dir, "beads.db")
+
+	store, err := NewEncryptedVCStorage(ctx, dbPath, "s3cret")
+	if err != nil {
+		t.Fatalf("NewEncryptedVCStorage failed: %v", err)
+	}
+	issue := &types.Issue{Title: "Proprietary excerpt", Status: types.StatusOpen, Priority: 2,
+		IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Neither the database nor its journal or WAL hold plaintext
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "Proprietary excerpt") {
+			t.Errorf("%s contains plaintext", entry.Name())
+		}
+	}
+
+	if _, err := NewEncryptedVCStorage(ctx, dbPath, ""); err == nil || !strings.Contains(err.Error(), PassphraseEnvVar) {
+		t.Errorf("expected a passphrase hint opening without a passphrase, got %v", err)
+	}
+	if _, err := NewEncryptedVCStorage(ctx, dbPath, "wrong"); err == nil || !strings.Contains(err.Error(), "not encrypted with this passphrase") {
+		t.Errorf("expected a wrong passphrase error, got %v", err)
+	}
+
+	t.Setenv(PassphraseEnvVar, "s3cret")
+	store, err = NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("reopening with %s failed: %v", PassphraseEnvVar, err)
+	}
+	defer store.Close()
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil || got == nil || got.Title != issue.Title {
+		t.Errorf("GetIssue after reopen = %v, %v", got, err)
+	}
+
+	// Beads keeps what it was opened with; it must not hold the passphrase and
+	// must sit in the database directory, where Beads looks for issues.jsonl
+	if store.Path() != dbPath {
+		t.Errorf("Path() = %q, want %q", store.Path(), dbPath)
+	}
+	if beadsPath := store.Storage.Path(); strings.Contains(beadsPath, "s3cret") || filepath.Dir(beadsPath) != dir {
+		t.Errorf("Beads path = %q, want one in %s without the passphrase", beadsPath, dir)
+	}
+}
+
+func TestDatabaseURI(t *testing.T) {
+	tests := []struct {
+		path, passphrase string
+		params           []string
+		want             string
+	}{
+		{"/data/beads.db", "", nil, "file:/data/beads.db"},
+		{"/data/beads.db", "", []string{"mode=ro"}, "file:/data/beads.db?mode=ro"},
+		{"/data/beads.db", "a b&c", []string{"mode=ro"}, "file:/data/beads.db?vfs=" + keyedVFSName("a b&c") + "&mode=ro"},
+		{"/odd?#%/beads.db", "", nil, "file:/odd%3f%23%25/beads.db"},
+	}
+	for _, tt := range tests {
+		if got := DatabaseURI(tt.path, tt.passphrase, tt.params...); got != tt.want {
+			t.Errorf("DatabaseURI(%q, %q, %v) = %q, want %q", tt.path, tt.passphrase, tt.params, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// EXECUTION OUTPUT (VC extension table: vc_execution_output)
+// ======================================================================
+
+// AppendExecutionOutput stores output lines in one transaction and sets
+// their IDs
+func (s *VCStorage) AppendExecutionOutput(ctx context.Context, lines []*types.OutputLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	for _, line := range lines {
+		if err := line.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return withBusyRetry(ctx, func() error {
+		return s.appendExecutionOutputAttempt(ctx, lines)
+	})
+}
+
+// appendExecutionOutputAttempt performs a single append attempt
+func (s *VCStorage) appendExecutionOutputAttempt(ctx context.Context, lines []*types.OutputLine) error {
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO vc_execution_output (execution_id, issue_id, stream, line, timestamp)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare output insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, line := range lines {
+		if line.Timestamp.IsZero() {
+			line.Timestamp = time.Now()
+		}
+		result, err := stmt.ExecContext(ctx, line.ExecutionID, line.IssueID, line.Stream, line.Line, line.Timestamp)
+		if err != nil {
+			return fmt.Errorf("failed to store output of execution %d: %w", line.ExecutionID, err)
+		}
+		if line.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get output line id: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit output: %w", err)
+	}
+	return nil
+}
+
+// ListExecutionOutput returns the output lines matching filter, oldest first
+func (s *VCStorage) ListExecutionOutput(ctx context.Context, filter types.OutputFilter) ([]*types.OutputLine, error) {
+	var where []string
+	var args []interface{}
+	if filter.IssueID != "" {
+		where = append(where, "issue_id = ?")
+		args = append(args, filter.IssueID)
+	}
+	if filter.ExecutionID != 0 {
+		where = append(where, "execution_id = ?")
+		args = append(args, filter.ExecutionID)
+	}
+	if filter.AfterID != 0 {
+		where = append(where, "id > ?")
+		args = append(args, filter.AfterID)
+	}
+
+	query := `SELECT id, execution_id, issue_id, stream, line, timestamp FROM vc_execution_output`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	// The last Limit lines: take them newest first, then reverse
+	if filter.Limit > 0 {
+		query = `SELECT * FROM (` + query + ` ORDER BY id DESC LIMIT ?)`
+		args = append(args, filter.Limit)
+	}
+	query += " ORDER BY id"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution output: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []*types.OutputLine
+	for rows.Next() {
+		var line types.OutputLine
+		if err := rows.Scan(&line.ID, &line.ExecutionID, &line.IssueID, &line.Stream, &line.Line, &line.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan output line: %w", err)
+		}
+		lines = append(lines, &line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating execution output: %w", err)
+	}
+	return lines, nil
+}
+
+// CleanupExecutionOutput deletes the output of executions that finished
+// more than retentionDays ago, and returns the number of lines deleted
+func (s *VCStorage) CleanupExecutionOutput(ctx context.Context, retentionDays int) (int, error) {
+	if retentionDays < 0 {
+		return 0, fmt.Errorf("retention days cannot be negative")
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM vc_execution_output
+		WHERE execution_id IN (
+			SELECT id FROM vc_executions WHERE completed_at IS NOT NULL AND completed_at < ?
+		)
+	`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old execution output: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
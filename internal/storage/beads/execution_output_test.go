@@ -0,0 +1,93 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestExecutionOutput(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	issue := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	first := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionFailed,
+		StartedAt: old, CompletedAt: &old}
+	second := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning}
+	for _, e := range []*types.Execution{first, second} {
+		if err := store.CreateExecution(ctx, e); err != nil {
+			t.Fatalf("CreateExecution failed: %v", err)
+		}
+	}
+
+	var lines []*types.OutputLine
+	for i := 0; i < 5; i++ {
+		lines = append(lines, &types.OutputLine{ExecutionID: second.ID, IssueID: issue.ID, Stream: types.OutputStdout, Line: fmt.Sprintf("line %d", i)})
+	}
+	lines[2].Stream = types.OutputStderr
+	if err := store.AppendExecutionOutput(ctx, lines); err != nil {
+		t.Fatalf("AppendExecutionOutput failed: %v", err)
+	}
+	if lines[0].ID == 0 || lines[4].ID <= lines[0].ID || lines[0].Timestamp.IsZero() {
+		t.Fatalf("expected increasing IDs and timestamps to be set, got %+v", lines[0])
+	}
+	if err := store.AppendExecutionOutput(ctx, []*types.OutputLine{{ExecutionID: first.ID, IssueID: issue.ID, Stream: types.OutputStdout, Line: "old"}}); err != nil {
+		t.Fatalf("AppendExecutionOutput failed: %v", err)
+	}
+	if err := store.AppendExecutionOutput(ctx, []*types.OutputLine{{ExecutionID: second.ID, IssueID: issue.ID, Stream: "stdin"}}); err == nil {
+		t.Error("expected an unknown stream to be rejected")
+	}
+
+	got, err := store.ListExecutionOutput(ctx, types.OutputFilter{ExecutionID: second.ID})
+	if err != nil {
+		t.Fatalf("ListExecutionOutput failed: %v", err)
+	}
+	if len(got) != 5 || got[0].Line != "line 0" || got[2].Stream != types.OutputStderr {
+		t.Fatalf("expected the 5 lines oldest first, got %+v", got)
+	}
+
+	// The last lines, and polling for lines after one
+	got, err = store.ListExecutionOutput(ctx, types.OutputFilter{ExecutionID: second.ID, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListExecutionOutput failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Line != "line 3" || got[1].Line != "line 4" {
+		t.Errorf("expected lines 3 and 4, got %+v", got)
+	}
+	got, err = store.ListExecutionOutput(ctx, types.OutputFilter{IssueID: issue.ID, AfterID: lines[4].ID})
+	if err != nil {
+		t.Fatalf("ListExecutionOutput failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Line != "old" {
+		t.Errorf("expected only the line stored after line 4, got %+v", got)
+	}
+
+	// Only the output of executions that finished long enough ago is cleaned up
+	deleted, err := store.CleanupExecutionOutput(ctx, 30)
+	if err != nil {
+		t.Fatalf("CleanupExecutionOutput failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 line deleted, got %d", deleted)
+	}
+
+	// Output goes with its execution
+	if err := store.DeleteExecution(ctx, second.ID); err != nil {
+		t.Fatalf("DeleteExecution failed: %v", err)
+	}
+	got, err = store.ListExecutionOutput(ctx, types.OutputFilter{IssueID: issue.ID})
+	if err != nil {
+		t.Fatalf("ListExecutionOutput failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no output left, got %+v", got)
+	}
+}
@@ -0,0 +1,215 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// EXECUTIONS (VC extension table: vc_executions)
+// ======================================================================
+
+// executionColumns are the vc_executions columns scanExecution reads
+const executionColumns = `id, issue_id, executor_instance_id, agent_provider, prompt_hash, status, exit_code, error,
+	started_at, completed_at, agent_duration_ms, cost_usd, commit_hash,
+	start_commit, start_dirty_hash, end_commit, end_dirty_hash, revert_commit, rollback_issue_id`
+
+// CreateExecution records an execution and sets its ID, and StartedAt if it
+// is zero
+func (s *VCStorage) CreateExecution(ctx context.Context, execution *types.Execution) error {
+	if err := execution.Validate(); err != nil {
+		return err
+	}
+	if execution.StartedAt.IsZero() {
+		execution.StartedAt = time.Now()
+	}
+
+	startCommit, startDirty := snapshotColumns(execution.StartSnapshot)
+	endCommit, endDirty := snapshotColumns(execution.EndSnapshot)
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_executions (issue_id, executor_instance_id, agent_provider, prompt_hash, status, exit_code, error,
+			started_at, completed_at, agent_duration_ms, cost_usd, commit_hash,
+			start_commit, start_dirty_hash, end_commit, end_dirty_hash, revert_commit, rollback_issue_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, execution.IssueID, executorInstanceID(execution), execution.AgentProvider, execution.PromptHash,
+		execution.Status, execution.ExitCode, execution.Error, execution.StartedAt, execution.CompletedAt,
+		execution.AgentDuration.Milliseconds(), execution.CostUSD, execution.CommitHash,
+		startCommit, startDirty, endCommit, endDirty, execution.RevertCommit, execution.RollbackIssueID)
+	if err != nil {
+		return fmt.Errorf("failed to record execution of %s: %w", execution.IssueID, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get execution id: %w", err)
+	}
+	execution.ID = id
+	return nil
+}
+
+// UpdateExecution saves an execution's outcome: everything but its issue and
+// start time
+func (s *VCStorage) UpdateExecution(ctx context.Context, execution *types.Execution) error {
+	if err := execution.Validate(); err != nil {
+		return err
+	}
+
+	startCommit, startDirty := snapshotColumns(execution.StartSnapshot)
+	endCommit, endDirty := snapshotColumns(execution.EndSnapshot)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE vc_executions SET
+			executor_instance_id = ?, agent_provider = ?, prompt_hash = ?, status = ?, exit_code = ?, error = ?,
+			completed_at = ?, agent_duration_ms = ?, cost_usd = ?, commit_hash = ?,
+			start_commit = ?, start_dirty_hash = ?, end_commit = ?, end_dirty_hash = ?,
+			revert_commit = ?, rollback_issue_id = ?
+		WHERE id = ?
+	`, executorInstanceID(execution), execution.AgentProvider, execution.PromptHash, execution.Status,
+		execution.ExitCode, execution.Error, execution.CompletedAt, execution.AgentDuration.Milliseconds(),
+		execution.CostUSD, execution.CommitHash, startCommit, startDirty, endCommit, endDirty,
+		execution.RevertCommit, execution.RollbackIssueID, execution.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update execution %d: %w", execution.ID, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("execution %d not found", execution.ID)
+	}
+	return nil
+}
+
+// GetExecution returns an execution, or nil if it doesn't exist
+func (s *VCStorage) GetExecution(ctx context.Context, id int64) (*types.Execution, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+executionColumns+` FROM vc_executions WHERE id = ?`, id)
+	execution, err := scanExecution(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution %d: %w", id, err)
+	}
+	return execution, nil
+}
+
+// ListExecutions returns the executions matching filter, newest first
+func (s *VCStorage) ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error) {
+	var where []string
+	var args []interface{}
+	if filter.IssueID != "" {
+		where = append(where, "issue_id = ?")
+		args = append(args, filter.IssueID)
+	}
+	if filter.ExecutorInstanceID != "" {
+		where = append(where, "executor_instance_id = ?")
+		args = append(args, filter.ExecutorInstanceID)
+	}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "started_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if filter.CommitHash != "" {
+		where = append(where, "commit_hash = ?")
+		args = append(args, filter.CommitHash)
+	}
+
+	query := `SELECT ` + executionColumns + ` FROM vc_executions`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY started_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query executions: %w", err)
+	}
+	defer rows.Close()
+
+	var executions []*types.Execution
+	for rows.Next() {
+		execution, err := scanExecution(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan execution: %w", err)
+		}
+		executions = append(executions, execution)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating executions: %w", err)
+	}
+	return executions, nil
+}
+
+// DeleteExecution deletes an execution
+func (s *VCStorage) DeleteExecution(ctx context.Context, id int64) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM vc_executions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete execution %d: %w", id, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("execution %d not found", id)
+	}
+	return nil
+}
+
+// executorInstanceID returns the execution's executor instance, NULL if none
+func executorInstanceID(execution *types.Execution) sql.NullString {
+	return sql.NullString{String: execution.ExecutorInstanceID, Valid: execution.ExecutorInstanceID != ""}
+}
+
+// snapshotColumns returns a snapshot's commit and dirty hash columns, both
+// empty if there is no snapshot
+func snapshotColumns(snapshot *types.WorkspaceSnapshot) (commit, dirtyHash string) {
+	if snapshot == nil {
+		return "", ""
+	}
+	return snapshot.CommitSHA, snapshot.DirtyHash
+}
+
+// snapshotFromColumns is the inverse of snapshotColumns
+func snapshotFromColumns(commit, dirtyHash string) *types.WorkspaceSnapshot {
+	if commit == "" && dirtyHash == "" {
+		return nil
+	}
+	return &types.WorkspaceSnapshot{CommitSHA: commit, DirtyHash: dirtyHash}
+}
+
+// executionScanner is satisfied by both *sql.Row and *sql.Rows
+type executionScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanExecution reads an execution selected with executionColumns
+func scanExecution(row executionScanner) (*types.Execution, error) {
+	var e types.Execution
+	var instanceID sql.NullString
+	var exitCode sql.NullInt64
+	var completedAt sql.NullTime
+	var agentDurationMs int64
+	var startCommit, startDirty, endCommit, endDirty string
+	if err := row.Scan(&e.ID, &e.IssueID, &instanceID, &e.AgentProvider, &e.PromptHash, &e.Status, &exitCode, &e.Error,
+		&e.StartedAt, &completedAt, &agentDurationMs, &e.CostUSD, &e.CommitHash,
+		&startCommit, &startDirty, &endCommit, &endDirty, &e.RevertCommit, &e.RollbackIssueID); err != nil {
+		return nil, err
+	}
+	e.StartSnapshot = snapshotFromColumns(startCommit, startDirty)
+	e.EndSnapshot = snapshotFromColumns(endCommit, endDirty)
+	e.ExecutorInstanceID = instanceID.String
+	if exitCode.Valid {
+		code := int(exitCode.Int64)
+		e.ExitCode = &code
+	}
+	if completedAt.Valid {
+		e.CompletedAt = &completedAt.Time
+	}
+	e.AgentDuration = time.Duration(agentDurationMs) * time.Millisecond
+	return &e, nil
+}
@@ -0,0 +1,102 @@
+package beads
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestExecutions(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	issue := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	first := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning, StartedAt: start,
+		PromptHash: types.PromptHash("do the thing"), StartSnapshot: &types.WorkspaceSnapshot{CommitSHA: "def456"}}
+	if err := store.CreateExecution(ctx, first); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+	second := &types.Execution{IssueID: issue.ID, ExecutorInstanceID: "exec-1", AgentProvider: "amp", Status: types.ExecutionRunning}
+	if err := store.CreateExecution(ctx, second); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+	if first.ID == 0 || second.ID == 0 || second.StartedAt.IsZero() {
+		t.Fatalf("expected IDs and a start time to be set: %+v %+v", first, second)
+	}
+	if err := store.CreateExecution(ctx, &types.Execution{IssueID: issue.ID, Status: types.ExecutionRunning}); err == nil {
+		t.Error("expected an execution without a provider to be rejected")
+	}
+
+	completed := start.Add(10 * time.Minute)
+	exitCode := 0
+	first.Status = types.ExecutionSucceeded
+	first.CompletedAt = &completed
+	first.ExitCode = &exitCode
+	first.AgentDuration = 8 * time.Minute
+	first.CostUSD = 1.25
+	first.CommitHash = "abc123"
+	first.EndSnapshot = &types.WorkspaceSnapshot{CommitSHA: "def456", DirtyHash: "0badf00d"}
+	first.RevertCommit = "fed321"
+	first.RollbackIssueID = "vc-redo"
+	if err := store.UpdateExecution(ctx, first); err != nil {
+		t.Fatalf("UpdateExecution failed: %v", err)
+	}
+
+	got, err := store.GetExecution(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetExecution failed: %v", err)
+	}
+	if got.Status != types.ExecutionSucceeded || got.ExitCode == nil || *got.ExitCode != 0 || got.AgentDuration != 8*time.Minute ||
+		got.CostUSD != 1.25 || got.CommitHash != "abc123" || got.PromptHash != first.PromptHash || got.ExecutorInstanceID != "" {
+		t.Errorf("unexpected execution: %+v", got)
+	}
+	if got.StartSnapshot == nil || got.StartSnapshot.CommitSHA != first.StartSnapshot.CommitSHA ||
+		got.StartSnapshot.DirtyHash != first.StartSnapshot.DirtyHash ||
+		got.EndSnapshot == nil || got.EndSnapshot.CommitSHA != first.EndSnapshot.CommitSHA ||
+		got.EndSnapshot.DirtyHash != first.EndSnapshot.DirtyHash {
+		t.Errorf("snapshots not round-tripped: start %v, end %v", got.StartSnapshot, got.EndSnapshot)
+	}
+	if !got.IsRolledBack() || got.RevertCommit != "fed321" || got.RollbackIssueID != "vc-redo" {
+		t.Errorf("rollback not round-tripped: %q, %q", got.RevertCommit, got.RollbackIssueID)
+	}
+	if got.Duration() != 10*time.Minute {
+		t.Errorf("Duration() = %v, want 10m", got.Duration())
+	}
+	if missing, err := store.GetExecution(ctx, 999); err != nil || missing != nil {
+		t.Errorf("expected nil for a missing execution, got %+v, %v", missing, err)
+	}
+
+	all, err := store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID})
+	if err != nil {
+		t.Fatalf("ListExecutions failed: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != second.ID {
+		t.Errorf("expected both executions, newest first: %+v", all)
+	}
+	running, _ := store.ListExecutions(ctx, types.ExecutionFilter{Status: types.ExecutionRunning})
+	if len(running) != 1 || running[0].ExecutorInstanceID != "exec-1" {
+		t.Errorf("expected only the running execution: %+v", running)
+	}
+	byCommit, _ := store.ListExecutions(ctx, types.ExecutionFilter{CommitHash: "abc123"})
+	if len(byCommit) != 1 || byCommit[0].ID != first.ID {
+		t.Errorf("expected only the execution that made abc123: %+v", byCommit)
+	}
+
+	if err := store.DeleteExecution(ctx, second.ID); err != nil {
+		t.Fatalf("DeleteExecution failed: %v", err)
+	}
+	if err := store.DeleteExecution(ctx, second.ID); err == nil {
+		t.Error("expected deleting a missing execution to fail")
+	}
+	if err := store.UpdateExecution(ctx, second); err == nil {
+		t.Error("expected updating a deleted execution to fail")
+	}
+}
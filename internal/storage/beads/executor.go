@@ -153,7 +153,7 @@ func (s *VCStorage) CleanupStaleInstances(ctx context.Context, staleThresholdSec
 	staleTime := time.Now().Add(-time.Duration(staleThresholdSeconds) * time.Second)
 
 	// Start a transaction to ensure atomic cleanup of instances and their claims
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginWriteTx(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -268,6 +268,10 @@ func (s *VCStorage) CleanupStaleInstances(ctx context.Context, staleThresholdSec
 				return 0, fmt.Errorf("failed to release execution state for issue %s: %w", issueID, err)
 			}
 
+			if err := auditStatusChangeTx(ctx, tx, issueID, types.StatusOpen, "system"); err != nil {
+				return 0, err
+			}
+
 			// Reset issue status to 'open' and clear closed_at
 			_, err = tx.ExecContext(ctx, `
 				UPDATE issues
@@ -371,40 +375,20 @@ func (s *VCStorage) DeleteOldStoppedInstances(ctx context.Context, olderThanSeco
 // ClaimIssue atomically claims an issue for execution
 // Retries on SQLite busy errors to handle concurrent claim attempts
 func (s *VCStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
-	const maxRetries = 5
-	const baseDelay = 10 * time.Millisecond
-
-	var lastErr error
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		if attempt > 0 {
-			// Exponential backoff: 10ms, 20ms, 40ms, 80ms
-			delay := baseDelay * time.Duration(1<<uint(attempt-1))
-			time.Sleep(delay)
-		}
-
-		err := s.claimIssueAttempt(ctx, issueID, executorInstanceID)
-		if err == nil {
-			return nil
-		}
-
-		// Check if error is a SQLite busy error
-		if isSQLiteBusyError(err) {
-			lastErr = err
-			continue // Retry
-		}
-
-		// Non-retryable error
-		return err
-	}
+	return s.claimIssue(ctx, issueID, executorInstanceID, nil)
+}
 
-	// All retries exhausted
-	return lastErr
+// claimIssue claims an issue, with a lease expiring at leaseExpiresAt (nil = no lease)
+func (s *VCStorage) claimIssue(ctx context.Context, issueID, executorInstanceID string, leaseExpiresAt *time.Time) error {
+	return withBusyRetry(ctx, func() error {
+		return s.claimIssueAttempt(ctx, issueID, executorInstanceID, leaseExpiresAt)
+	})
 }
 
 // claimIssueAttempt performs a single claim attempt
-func (s *VCStorage) claimIssueAttempt(ctx context.Context, issueID, executorInstanceID string) error {
+func (s *VCStorage) claimIssueAttempt(ctx context.Context, issueID, executorInstanceID string, leaseExpiresAt *time.Time) error {
 	// Begin transaction to ensure atomicity
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginWriteTx(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -451,19 +435,24 @@ func (s *VCStorage) claimIssueAttempt(ctx context.Context, issueID, executorInst
 
 	// Insert or update claim
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO vc_issue_execution_state (issue_id, executor_instance_id, claimed_at, state, updated_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO vc_issue_execution_state (issue_id, executor_instance_id, claimed_at, state, lease_expires_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
 		ON CONFLICT(issue_id) DO UPDATE SET
 			executor_instance_id = excluded.executor_instance_id,
 			claimed_at = excluded.claimed_at,
 			state = ?,
+			lease_expires_at = excluded.lease_expires_at,
 			updated_at = excluded.updated_at
-	`, issueID, executorInstanceID, time.Now(), types.ExecutionStateClaimed, time.Now(), types.ExecutionStateClaimed)
+	`, issueID, executorInstanceID, time.Now(), types.ExecutionStateClaimed, leaseExpiresAt, time.Now(), types.ExecutionStateClaimed)
 
 	if err != nil {
 		return fmt.Errorf("failed to claim issue: %w", err)
 	}
 
+	if err := auditStatusChangeTx(ctx, tx, issueID, types.StatusInProgress, executorInstanceID); err != nil {
+		return err
+	}
+
 	// Update issue status to in_progress in Beads (through transaction)
 	// Only update if current status is 'open' - refuse to claim closed/blocked issues (vc-173, vc-185)
 	result, err := tx.ExecContext(ctx, `
@@ -492,13 +481,124 @@ func (s *VCStorage) claimIssueAttempt(ctx context.Context, issueID, executorInst
 	return nil
 }
 
-// isSQLiteBusyError checks if an error is a SQLite database locked/busy error
-func isSQLiteBusyError(err error) bool {
-	if err == nil {
-		return false
+// claimCandidateLimit bounds how many ready issues ClaimNextReadyIssue tries
+// before giving up, when other workers keep winning the race for them
+const claimCandidateLimit = 20
+
+// ClaimNextReadyIssue atomically claims the highest-priority ready issue for
+// workerID with a lease, taking over an in_progress issue whose lease has
+// expired first. Only issues in project are considered, unless it is "".
+// Returns (nil, nil) if nothing can be claimed.
+//
+// Each claim is conditional on the issue still being open (or its lease still
+// being expired), so when several workers or vc processes race for the same
+// issue exactly one wins; the losers move on to the next candidate.
+func (s *VCStorage) ClaimNextReadyIssue(ctx context.Context, workerID string, lease time.Duration, project string) (*types.Issue, error) {
+	if lease <= 0 {
+		lease = types.DefaultClaimLease
+	}
+	leaseExpiresAt := time.Now().Add(lease)
+
+	issueID, err := s.takeOverExpiredLease(ctx, workerID, leaseExpiresAt, project)
+	if err != nil {
+		return nil, err
+	}
+
+	if issueID == "" {
+		candidates, err := s.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Limit: claimCandidateLimit, SortPolicy: types.SortPolicyPriority, Project: project})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ready work: %w", err)
+		}
+		for _, candidate := range candidates {
+			if err := s.claimIssue(ctx, candidate.ID, workerID, &leaseExpiresAt); err != nil {
+				if isSQLiteBusyError(err) || ctx.Err() != nil {
+					return nil, err
+				}
+				// Claimed by another worker since GetReadyWork, or not claimable
+				continue
+			}
+			issueID = candidate.ID
+			break
+		}
+	}
+	if issueID == "" {
+		return nil, nil
+	}
+
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claimed issue %s: %w", issueID, err)
+	}
+	return issue, nil
+}
+
+// takeOverExpiredLease moves the highest-priority in_progress issue in project
+// ("" = any) whose claim lease has expired to workerID. Returns the issue ID,
+// or "" if there is none.
+func (s *VCStorage) takeOverExpiredLease(ctx context.Context, workerID string, leaseExpiresAt time.Time, project string) (string, error) {
+	now := time.Now()
+
+	var issueID string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT es.issue_id
+		FROM vc_issue_execution_state es
+		JOIN issues i ON i.id = es.issue_id
+		WHERE es.lease_expires_at IS NOT NULL AND es.lease_expires_at < ?
+		  AND es.state IN ('claimed', 'assessing', 'executing', 'analyzing', 'gates', 'committing')
+		  AND i.status = 'in_progress'
+		  AND (? = '' OR EXISTS (SELECT 1 FROM labels l WHERE l.issue_id = i.id AND l.label = ?))
+		ORDER BY i.priority ASC, es.lease_expires_at ASC
+		LIMIT 1
+	`, now, project, types.ProjectLabel(project)).Scan(&issueID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query expired leases: %w", err)
+	}
+
+	// Conditional on the lease still being expired, so only one worker takes it over
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE vc_issue_execution_state
+		SET executor_instance_id = ?, claimed_at = ?, state = ?, lease_expires_at = ?, updated_at = ?
+		WHERE issue_id = ? AND lease_expires_at < ?
+	`, workerID, now, types.ExecutionStateClaimed, leaseExpiresAt, now, issueID, now)
+	if err != nil {
+		return "", fmt.Errorf("failed to take over expired lease on %s: %w", issueID, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("failed to check rows affected: %w", err)
 	}
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "database is locked") || strings.Contains(errMsg, "SQLITE_BUSY")
+	if rowsAffected == 0 {
+		return "", nil // Another worker took it over first
+	}
+	return issueID, nil
+}
+
+// RenewClaimLease extends workerID's lease on issueID to lease from now
+func (s *VCStorage) RenewClaimLease(ctx context.Context, issueID, workerID string, lease time.Duration) error {
+	if lease <= 0 {
+		lease = types.DefaultClaimLease
+	}
+	now := time.Now()
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE vc_issue_execution_state
+		SET lease_expires_at = ?, updated_at = ?
+		WHERE issue_id = ? AND executor_instance_id = ?
+		  AND state IN ('claimed', 'assessing', 'executing', 'analyzing', 'gates', 'committing')
+	`, now.Add(lease), now, issueID, workerID)
+	if err != nil {
+		return fmt.Errorf("failed to renew lease on %s: %w", issueID, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("cannot renew lease on %s: not claimed by %s", issueID, workerID)
+	}
+	return nil
 }
 
 // GetExecutionState retrieves execution state for an issue
@@ -511,10 +611,11 @@ func (s *VCStorage) GetExecutionState(ctx context.Context, issueID string) (*typ
 	var errorMessage sql.NullString
 	var interventionCount sql.NullInt64
 	var lastInterventionTime sql.NullTime
+	var leaseExpiresAt sql.NullTime
 
 	err := s.db.QueryRowContext(ctx, `
 		SELECT issue_id, executor_instance_id, claimed_at, state, checkpoint_data, error_message, updated_at,
-		       COALESCE(intervention_count, 0) as intervention_count, last_intervention_time
+		       COALESCE(intervention_count, 0) as intervention_count, last_intervention_time, lease_expires_at
 		FROM vc_issue_execution_state
 		WHERE issue_id = ?
 	`, issueID).Scan(
@@ -527,6 +628,7 @@ func (s *VCStorage) GetExecutionState(ctx context.Context, issueID string) (*typ
 		&state.UpdatedAt,
 		&interventionCount,
 		&lastInterventionTime,
+		&leaseExpiresAt,
 	)
 
 	if err != nil {
@@ -555,6 +657,9 @@ func (s *VCStorage) GetExecutionState(ctx context.Context, issueID string) (*typ
 	if lastInterventionTime.Valid {
 		state.LastInterventionTime = &lastInterventionTime.Time
 	}
+	if leaseExpiresAt.Valid {
+		state.LeaseExpiresAt = &leaseExpiresAt.Time
+	}
 
 	return &state, nil
 }
@@ -721,11 +826,16 @@ func (s *VCStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, e
 	s.LogStatusChangeFromUpdates(ctx, issueID, updates, actor,
 		fmt.Sprintf("execution failed, reopening for retry: %s", errorComment))
 
-	err = s.Storage.UpdateIssue(ctx, issueID, updates, actor)
+	before, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to reopen issue: %w", err)
+	}
+	err = withBusyRetry(ctx, func() error { return s.Storage.UpdateIssue(ctx, issueID, updates, actor) })
 
 	if err != nil {
 		return fmt.Errorf("failed to reopen issue: %w", err)
 	}
+	s.auditChange(ctx, issueID, before, actor)
 
 	// Add comment explaining the failure
 	if errorComment != "" {
@@ -806,9 +916,10 @@ func CalculateInterventionBackoff(interventionCount int, lastInterventionTime *t
 // EXECUTION HISTORY (VC extension table: vc_execution_history)
 // ======================================================================
 
-// RecordExecutionAttempt records an execution attempt in history
+// RecordExecutionAttempt records an execution attempt in history and sets
+// attempt.ID so attachments can reference it
 func (s *VCStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
-	_, err := s.db.ExecContext(ctx, `
+	result, err := s.db.ExecContext(ctx, `
 		INSERT INTO vc_execution_history (issue_id, executor_instance_id, attempt_number, started_at, completed_at, success, exit_code, summary, output_sample, error_sample)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, attempt.IssueID, attempt.ExecutorInstanceID, attempt.AttemptNumber, attempt.StartedAt, attempt.CompletedAt,
@@ -817,6 +928,9 @@ func (s *VCStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.E
 	if err != nil {
 		return fmt.Errorf("failed to record execution attempt: %w", err)
 	}
+	if id, err := result.LastInsertId(); err == nil {
+		attempt.ID = id
+	}
 
 	return nil
 }
@@ -852,15 +966,17 @@ func (s *VCStorage) GetExecutionHistoryPaginated(ctx context.Context, issueID st
 	var history []*types.ExecutionAttempt
 	for rows.Next() {
 		var attempt types.ExecutionAttempt
+		var executorID sql.NullString // NULL once the executor instance is deleted (ON DELETE SET NULL)
 		var completedAt sql.NullTime
 		var success sql.NullBool
 		var exitCode sql.NullInt64
 
-		if err := rows.Scan(&attempt.ID, &attempt.IssueID, &attempt.ExecutorInstanceID,
+		if err := rows.Scan(&attempt.ID, &attempt.IssueID, &executorID,
 			&attempt.AttemptNumber, &attempt.StartedAt, &completedAt, &success, &exitCode,
 			&attempt.Summary, &attempt.OutputSample, &attempt.ErrorSample); err != nil {
 			return nil, fmt.Errorf("failed to scan execution attempt: %w", err)
 		}
+		attempt.ExecutorInstanceID = executorID.String
 
 		if completedAt.Valid {
 			attempt.CompletedAt = &completedAt.Time
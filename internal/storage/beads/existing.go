@@ -0,0 +1,54 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+
+	beadsLib "github.com/steveyegge/beads"
+)
+
+// OpenExistingVCStorage opens an existing VC database, encrypted with
+// passphrase unless it is empty, for read-only commands. Unlike
+// NewEncryptedVCStorage it never creates the database or its directory, sets
+// no config and creates no VC extension tables. It is not read-only at the
+// SQLite level: the connection is read-write, and the Beads constructor still
+// switches the journal to WAL and runs its schema, migrations and multi-repo
+// hydration, which are no-ops on a database Beads has already initialized.
+// Writes through VC are refused by wrapping the store in storage.ReadOnly.
+func OpenExistingVCStorage(ctx context.Context, dbPath, passphrase string) (*VCStorage, error) {
+	if dbPath == ":memory:" {
+		return nil, fmt.Errorf("cannot open an existing in-memory database: it would always be empty")
+	}
+
+	// mode=rw fails instead of creating a missing database
+	beadsStore, err := beadsLib.NewSQLiteStorage(ctx, beadsURI(DatabaseURI(absPath(dbPath), passphrase, "mode=rw")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", dbPath, explainOpenError(err, dbPath, passphrase))
+	}
+
+	db := beadsStore.UnderlyingDB()
+	if db == nil {
+		beadsStore.Close()
+		return nil, fmt.Errorf("beads storage did not provide underlying DB")
+	}
+
+	// Without the extension tables every VC query would fail; creating them
+	// is a write, so send the caller to open the database normally once
+	var tables int
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'vc_executor_instances'
+	`).Scan(&tables); err != nil {
+		beadsStore.Close()
+		return nil, fmt.Errorf("failed to check for VC tables: %w", err)
+	}
+	if tables == 0 {
+		beadsStore.Close()
+		return nil, fmt.Errorf("%s is not a VC database yet (open it once without read-only mode to initialize it)", dbPath)
+	}
+
+	return &VCStorage{
+		Storage: beadsStore,
+		db:      db,
+		dbPath:  dbPath,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package beads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	beadsLib "github.com/steveyegge/beads"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestOpenExistingVCStorage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "vc.db")
+
+	if _, err := OpenExistingVCStorage(ctx, dbPath, ""); err == nil {
+		t.Fatal("expected opening a missing database to fail")
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Fatalf("opening a missing database created it: %v", err)
+	}
+
+	// A plain Beads database is refused and left without VC tables
+	plainPath := filepath.Join(dir, "plain.db")
+	plain, err := beadsLib.NewSQLiteStorage(ctx, plainPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage failed: %v", err)
+	}
+	_ = plain.Close()
+	if _, err := OpenExistingVCStorage(ctx, plainPath, ""); err == nil {
+		t.Fatal("expected opening a database without VC tables to fail")
+	}
+	plain, err = beadsLib.NewSQLiteStorage(ctx, plainPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage failed: %v", err)
+	}
+	var tables int
+	if err := plain.UnderlyingDB().QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name LIKE 'vc_%'
+	`).Scan(&tables); err != nil {
+		t.Fatalf("failed to count VC tables: %v", err)
+	}
+	_ = plain.Close()
+	if tables != 0 {
+		t.Errorf("opening a plain Beads database created %d VC tables", tables)
+	}
+
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+	issue := &types.Issue{Title: "Read me", IssueType: types.TypeTask, Status: types.StatusOpen, Priority: 1, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	_ = store.Close()
+
+	existing, err := OpenExistingVCStorage(ctx, dbPath, "")
+	if err != nil {
+		t.Fatalf("OpenExistingVCStorage failed: %v", err)
+	}
+	defer func() { _ = existing.Close() }()
+	got, err := existing.GetIssue(ctx, issue.ID)
+	if err != nil || got == nil || got.Title != issue.Title {
+		t.Fatalf("GetIssue() = %+v, %v", got, err)
+	}
+}
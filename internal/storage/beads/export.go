@@ -0,0 +1,471 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// EXPORT / IMPORT (full issue graph as versioned JSONL)
+// ======================================================================
+
+// Export writes the full issue graph (issues, dependencies, labels, events
+// and execution history) to w as a versioned JSONL dump. All tables are read
+// in one transaction, so the dump is a consistent snapshot even while the
+// executor is writing.
+func (s *VCStorage) Export(ctx context.Context, w io.Writer) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin export transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	graph, err := exportGraph(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Release the read transaction before writing: w may be slow
+	_ = tx.Rollback()
+	return export.Write(w, graph)
+}
+
+// exportGraph reads the full issue graph in tx
+func exportGraph(ctx context.Context, tx *sql.Tx) (*types.ExportGraph, error) {
+	graph := &types.ExportGraph{Header: types.ExportHeader{ExportedAt: time.Now()}}
+
+	var prefix sql.NullString
+	if err := tx.QueryRowContext(ctx, `SELECT value FROM config WHERE key = 'issue_prefix'`).Scan(&prefix); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to read issue prefix: %w", err)
+	}
+	graph.Header.IssuePrefix = prefix.String
+
+	var err error
+	if graph.Issues, err = exportIssues(ctx, tx); err != nil {
+		return nil, err
+	}
+	if graph.Dependencies, err = exportDependencies(ctx, tx); err != nil {
+		return nil, err
+	}
+	if graph.Labels, err = exportLabels(ctx, tx); err != nil {
+		return nil, err
+	}
+	if graph.Events, err = exportEvents(ctx, tx); err != nil {
+		return nil, err
+	}
+	if graph.Executions, err = exportExecutions(ctx, tx); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+func exportIssues(ctx context.Context, tx *sql.Tx) ([]*types.Issue, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria, i.notes,
+		       i.status, i.priority, i.issue_type, i.assignee, i.estimated_minutes,
+		       i.created_at, i.updated_at, i.closed_at, m.subtype
+		FROM issues i
+		LEFT JOIN vc_mission_state m ON m.issue_id = i.id
+		ORDER BY i.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var issues []*types.Issue
+	for rows.Next() {
+		var issue types.Issue
+		var assignee, subtype sql.NullString
+		var estimatedMinutes sql.NullInt64
+		var closedAt sql.NullTime
+		if err := rows.Scan(&issue.ID, &issue.Title, &issue.Description, &issue.Design,
+			&issue.AcceptanceCriteria, &issue.Notes, &issue.Status, &issue.Priority, &issue.IssueType,
+			&assignee, &estimatedMinutes, &issue.CreatedAt, &issue.UpdatedAt, &closedAt, &subtype); err != nil {
+			return nil, fmt.Errorf("failed to scan issue: %w", err)
+		}
+		issue.Assignee = assignee.String
+		if estimatedMinutes.Valid {
+			minutes := int(estimatedMinutes.Int64)
+			issue.EstimatedMinutes = &minutes
+		}
+		if closedAt.Valid {
+			issue.ClosedAt = &closedAt.Time
+		}
+		issue.IssueSubtype = types.IssueSubtype(subtype.String)
+		issues = append(issues, &issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read issues: %w", err)
+	}
+	return issues, nil
+}
+
+func exportDependencies(ctx context.Context, tx *sql.Tx) ([]*types.Dependency, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT issue_id, depends_on_id, type, created_at, created_by
+		FROM dependencies
+		ORDER BY issue_id, depends_on_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependencies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var deps []*types.Dependency
+	for rows.Next() {
+		var dep types.Dependency
+		if err := rows.Scan(&dep.IssueID, &dep.DependsOnID, &dep.Type, &dep.CreatedAt, &dep.CreatedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		deps = append(deps, &dep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dependencies: %w", err)
+	}
+	return deps, nil
+}
+
+func exportLabels(ctx context.Context, tx *sql.Tx) ([]*types.IssueLabel, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT issue_id, label FROM labels ORDER BY issue_id, label`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query labels: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var labels []*types.IssueLabel
+	for rows.Next() {
+		var label types.IssueLabel
+		if err := rows.Scan(&label.IssueID, &label.Label); err != nil {
+			return nil, fmt.Errorf("failed to scan label: %w", err)
+		}
+		labels = append(labels, &label)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read labels: %w", err)
+	}
+	return labels, nil
+}
+
+func exportEvents(ctx context.Context, tx *sql.Tx) ([]*types.Event, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
+		FROM events
+		ORDER BY issue_id, created_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []*types.Event
+	for rows.Next() {
+		var event types.Event
+		var oldValue, newValue, comment sql.NullString
+		if err := rows.Scan(&event.ID, &event.IssueID, &event.EventType, &event.Actor,
+			&oldValue, &newValue, &comment, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		if oldValue.Valid {
+			event.OldValue = &oldValue.String
+		}
+		if newValue.Valid {
+			event.NewValue = &newValue.String
+		}
+		if comment.Valid {
+			event.Comment = &comment.String
+		}
+		result = append(result, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return result, nil
+}
+
+func exportExecutions(ctx context.Context, tx *sql.Tx) ([]*types.ExecutionAttempt, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, issue_id, executor_instance_id, attempt_number, started_at, completed_at, success, exit_code, summary, output_sample, error_sample
+		FROM vc_execution_history
+		ORDER BY issue_id, started_at, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution history: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var history []*types.ExecutionAttempt
+	for rows.Next() {
+		var attempt types.ExecutionAttempt
+		var executorID, summary, outputSample, errorSample sql.NullString
+		var completedAt sql.NullTime
+		var success sql.NullBool
+		var exitCode sql.NullInt64
+		if err := rows.Scan(&attempt.ID, &attempt.IssueID, &executorID, &attempt.AttemptNumber,
+			&attempt.StartedAt, &completedAt, &success, &exitCode,
+			&summary, &outputSample, &errorSample); err != nil {
+			return nil, fmt.Errorf("failed to scan execution attempt: %w", err)
+		}
+		attempt.ExecutorInstanceID = executorID.String
+		attempt.Summary = summary.String
+		attempt.OutputSample = outputSample.String
+		attempt.ErrorSample = errorSample.String
+		if completedAt.Valid {
+			attempt.CompletedAt = &completedAt.Time
+		}
+		if success.Valid {
+			attempt.Success = &success.Bool
+		}
+		if exitCode.Valid {
+			code := int(exitCode.Int64)
+			attempt.ExitCode = &code
+		}
+		history = append(history, &attempt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read execution history: %w", err)
+	}
+	return history, nil
+}
+
+// Import reads a dump written by Export and adds it to the database in a
+// single transaction. Issues keep their IDs and timestamps. Issues that
+// already exist are left untouched, along with their events and execution
+// history; dependencies and labels are added if missing, so importing the
+// same dump twice is a no-op. Imported issues are marked dirty so the next
+// Beads JSONL sync picks them up.
+func (s *VCStorage) Import(ctx context.Context, r io.Reader) (*types.ImportStats, error) {
+	graph, err := export.Read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin import transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stats := &types.ImportStats{}
+	imported := make(map[string]bool, len(graph.Issues))
+	now := time.Now()
+
+	for _, issue := range graph.Issues {
+		var exists int
+		err := tx.QueryRowContext(ctx, `SELECT 1 FROM issues WHERE id = ?`, issue.ID).Scan(&exists)
+		if err == nil {
+			stats.SkippedIssues++
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to check issue %s: %w", issue.ID, err)
+		}
+
+		var assignee interface{}
+		if issue.Assignee != "" {
+			assignee = issue.Assignee
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO issues (
+				id, content_hash, title, description, design, acceptance_criteria, notes,
+				status, priority, issue_type, assignee, estimated_minutes,
+				created_at, updated_at, closed_at, source_repo
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '.')
+		`, issue.ID, vcIssueToBeads(issue).ComputeContentHash(), issue.Title, issue.Description,
+			issue.Design, issue.AcceptanceCriteria, issue.Notes, issue.Status, issue.Priority,
+			issue.IssueType, assignee, issue.EstimatedMinutes, issue.CreatedAt, issue.UpdatedAt, issue.ClosedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import issue %s: %w", issue.ID, err)
+		}
+
+		if issue.IssueSubtype != "" && issue.IssueSubtype != types.SubtypeNormal {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO vc_mission_state (issue_id, subtype, created_at, updated_at)
+				VALUES (?, ?, ?, ?)
+			`, issue.ID, issue.IssueSubtype, issue.CreatedAt, issue.UpdatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import mission state for %s: %w", issue.ID, err)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO dirty_issues (issue_id, marked_at)
+			VALUES (?, ?)
+			ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+		`, issue.ID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark issue %s dirty: %w", issue.ID, err)
+		}
+
+		imported[issue.ID] = true
+		stats.Issues++
+	}
+
+	for _, dep := range graph.Dependencies {
+		result, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO dependencies (issue_id, depends_on_id, type, created_at, created_by)
+			VALUES (?, ?, ?, ?, ?)
+		`, dep.IssueID, dep.DependsOnID, dep.Type, dep.CreatedAt, dep.CreatedBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import dependency %s -> %s: %w", dep.IssueID, dep.DependsOnID, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			stats.Dependencies++
+		}
+	}
+
+	for _, label := range graph.Labels {
+		result, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)`,
+			label.IssueID, label.Label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import label %q on %s: %w", label.Label, label.IssueID, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			stats.Labels++
+		}
+	}
+
+	for _, event := range graph.Events {
+		if !imported[event.IssueID] {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, event.IssueID, event.EventType, event.Actor, event.OldValue, event.NewValue, event.Comment, event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import event for %s: %w", event.IssueID, err)
+		}
+		stats.Events++
+	}
+
+	// Executor instances aren't part of the graph. History whose executor
+	// isn't known here keeps a NULL executor, just as it would after the
+	// instance was cleaned up (ON DELETE SET NULL).
+	knownExecutors := make(map[string]bool)
+	for _, attempt := range graph.Executions {
+		if !imported[attempt.IssueID] {
+			continue
+		}
+		var executorID interface{}
+		if id := attempt.ExecutorInstanceID; id != "" {
+			known, checked := knownExecutors[id]
+			if !checked {
+				var exists int
+				err := tx.QueryRowContext(ctx, `SELECT 1 FROM vc_executor_instances WHERE id = ?`, id).Scan(&exists)
+				if err != nil && err != sql.ErrNoRows {
+					return nil, fmt.Errorf("failed to check executor instance %s: %w", id, err)
+				}
+				known = err == nil
+				knownExecutors[id] = known
+			}
+			if known {
+				executorID = id
+			}
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO vc_execution_history (issue_id, executor_instance_id, attempt_number, started_at, completed_at, success, exit_code, summary, output_sample, error_sample)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, attempt.IssueID, executorID, attempt.AttemptNumber, attempt.StartedAt, attempt.CompletedAt,
+			attempt.Success, attempt.ExitCode, attempt.Summary, attempt.OutputSample, attempt.ErrorSample)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import execution attempt for %s: %w", attempt.IssueID, err)
+		}
+		stats.Executions++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return stats, nil
+}
+
+// ImportEvents adds events copied from another database, keeping their
+// timestamps. Events already recorded (same SyncKey) are skipped.
+func (s *VCStorage) ImportEvents(ctx context.Context, events []*types.Event) (int, error) {
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin event import transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	seen := make(map[string]bool)
+	loaded := make(map[string]bool)
+	added := 0
+	for _, event := range events {
+		if !loaded[event.IssueID] {
+			if err := loadEventKeys(ctx, tx, event.IssueID, seen); err != nil {
+				return 0, err
+			}
+			loaded[event.IssueID] = true
+		}
+		key := event.SyncKey()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, event.IssueID, event.EventType, event.Actor, event.OldValue, event.NewValue, event.Comment, event.CreatedAt)
+		if err != nil {
+			return 0, fmt.Errorf("failed to import event for %s: %w", event.IssueID, err)
+		}
+		added++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit event import: %w", err)
+	}
+	return added, nil
+}
+
+// loadEventKeys adds the SyncKey of every event of issueID to seen. It fails
+// if the issue doesn't exist.
+func loadEventKeys(ctx context.Context, tx *sql.Tx, issueID string, seen map[string]bool) error {
+	var exists int
+	if err := tx.QueryRowContext(ctx, `SELECT 1 FROM issues WHERE id = ?`, issueID).Scan(&exists); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("issue %s not found", issueID)
+		}
+		return fmt.Errorf("failed to check issue %s: %w", issueID, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT event_type, actor, old_value, new_value, comment, created_at
+		FROM events
+		WHERE issue_id = ?
+	`, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to query events of %s: %w", issueID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		event := types.Event{IssueID: issueID}
+		var oldValue, newValue, comment sql.NullString
+		if err := rows.Scan(&event.EventType, &event.Actor, &oldValue, &newValue, &comment, &event.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		if oldValue.Valid {
+			event.OldValue = &oldValue.String
+		}
+		if newValue.Valid {
+			event.NewValue = &newValue.String
+		}
+		if comment.Valid {
+			event.Comment = &comment.String
+		}
+		seen[event.SyncKey()] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read events of %s: %w", issueID, err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src, cleanupSrc := setupTestStorage(t)
+	defer cleanupSrc()
+
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:       "Mission",
+			Description: "Ship it",
+			IssueType:   types.TypeEpic,
+			Status:      types.StatusOpen,
+			Priority:    1,
+		},
+		Goal: "Ship it",
+	}
+	mission.IssueSubtype = types.SubtypeMission
+	if err := src.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("CreateMission failed: %v", err)
+	}
+	minutes := 30
+	task := &types.Issue{
+		Title:              "Task",
+		Description:        "Do the thing",
+		AcceptanceCriteria: "Thing done",
+		IssueType:          types.TypeTask,
+		Status:             types.StatusOpen,
+		Priority:           2,
+		Assignee:           "alice",
+		EstimatedMinutes:   &minutes,
+	}
+	if err := src.CreateIssue(ctx, task, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := src.AddDependency(ctx, &types.Dependency{
+		IssueID: task.ID, DependsOnID: mission.ID, Type: types.DepParentChild,
+	}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := src.AddLabel(ctx, task.ID, "backend", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := src.AddComment(ctx, task.ID, "test", "first try"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if err := src.RegisterInstance(ctx, &types.ExecutorInstance{
+		InstanceID:    "exec-1",
+		Hostname:      "host",
+		PID:           1,
+		Version:       "test",
+		StartedAt:     time.Now(),
+		LastHeartbeat: time.Now(),
+		Status:        types.ExecutorStatusRunning,
+	}); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	success := true
+	completed := time.Now()
+	if err := src.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{
+		IssueID:            task.ID,
+		ExecutorInstanceID: "exec-1",
+		AttemptNumber:      1,
+		StartedAt:          completed.Add(-time.Minute),
+		CompletedAt:        &completed,
+		Success:            &success,
+		Summary:            "worked",
+	}); err != nil {
+		t.Fatalf("RecordExecutionAttempt failed: %v", err)
+	}
+	if err := src.CloseIssue(ctx, task.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	var dump bytes.Buffer
+	if err := src.Export(ctx, &dump); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	// Exporting an unchanged database is deterministic apart from the header
+	var again bytes.Buffer
+	if err := src.Export(ctx, &again); err != nil {
+		t.Fatalf("second Export failed: %v", err)
+	}
+	if afterHeader(dump.Bytes()) != afterHeader(again.Bytes()) {
+		t.Error("two exports of an unchanged database differ")
+	}
+
+	dst, cleanupDst := setupTestStorage(t)
+	defer cleanupDst()
+	stats, err := dst.Import(ctx, bytes.NewReader(dump.Bytes()))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if stats.Issues != 2 || stats.Dependencies != 1 || stats.Labels != 1 || stats.Executions != 1 {
+		t.Errorf("unexpected import stats: %+v", stats)
+	}
+	if stats.Events == 0 {
+		t.Error("expected events to be imported")
+	}
+
+	got, err := dst.GetIssue(ctx, task.ID)
+	if err != nil || got == nil {
+		t.Fatalf("imported task not found: %v", err)
+	}
+	orig, _ := src.GetIssue(ctx, task.ID)
+	if got.Status != types.StatusClosed || got.ClosedAt == nil || got.Assignee != orig.Assignee ||
+		got.EstimatedMinutes == nil || *got.EstimatedMinutes != 30 {
+		t.Errorf("imported task lost fields: %+v", got)
+	}
+	if !got.CreatedAt.Equal(orig.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v (timestamps must be preserved)", got.CreatedAt, orig.CreatedAt)
+	}
+
+	gotMission, err := dst.GetIssue(ctx, mission.ID)
+	if err != nil || gotMission == nil {
+		t.Fatalf("imported mission not found: %v", err)
+	}
+	if gotMission.IssueSubtype != types.SubtypeMission {
+		t.Errorf("mission subtype = %q, want %q", gotMission.IssueSubtype, types.SubtypeMission)
+	}
+
+	deps, err := dst.GetDependencyRecords(ctx, task.ID)
+	if err != nil || len(deps) != 1 || deps[0].DependsOnID != mission.ID || deps[0].Type != types.DepParentChild {
+		t.Errorf("dependencies not imported: %v %v", deps, err)
+	}
+	labels, err := dst.GetLabels(ctx, task.ID)
+	if err != nil || len(labels) != 1 || labels[0] != "backend" {
+		t.Errorf("labels not imported: %v %v", labels, err)
+	}
+	history, err := dst.GetExecutionHistory(ctx, task.ID)
+	if err != nil || len(history) != 1 || history[0].Summary != "worked" || history[0].Success == nil || !*history[0].Success {
+		t.Errorf("execution history not imported: %v %v", history, err)
+	}
+	srcEvents, _ := src.GetEvents(ctx, task.ID, 0)
+	dstEvents, _ := dst.GetEvents(ctx, task.ID, 0)
+	if len(dstEvents) != len(srcEvents) {
+		t.Errorf("imported %d events for task, want %d", len(dstEvents), len(srcEvents))
+	}
+
+	// Re-importing the same dump changes nothing
+	stats, err = dst.Import(ctx, bytes.NewReader(dump.Bytes()))
+	if err != nil {
+		t.Fatalf("re-Import failed: %v", err)
+	}
+	if stats.Issues != 0 || stats.SkippedIssues != 2 || stats.Dependencies != 0 || stats.Labels != 0 || stats.Events != 0 {
+		t.Errorf("re-import should be a no-op, got %+v", stats)
+	}
+	dstEvents, _ = dst.GetEvents(ctx, task.ID, 0)
+	if len(dstEvents) != len(srcEvents) {
+		t.Errorf("re-import duplicated events: %d, want %d", len(dstEvents), len(srcEvents))
+	}
+}
+
+func TestImportRejectsInvalidDumpAtomically(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	// Second issue references a dependency target that isn't in the dump
+	dump := `{"kind":"header","header":{"format":"vc-issue-graph","version":1,"exported_at":"2025-01-01T00:00:00Z"}}
+{"kind":"issue","issue":{"id":"vc-a1","title":"One","description":"","status":"open","priority":2,"issue_type":"chore","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}}
+{"kind":"dependency","dependency":{"issue_id":"vc-a1","depends_on_id":"vc-missing","type":"blocks","created_at":"2025-01-01T00:00:00Z","created_by":"x"}}
+`
+	if _, err := store.Import(ctx, bytes.NewReader([]byte(dump))); err == nil {
+		t.Fatal("expected Import to reject a dangling dependency")
+	}
+	if issue, _ := store.GetIssue(ctx, "vc-a1"); issue != nil {
+		t.Error("failed import must not leave partial data behind")
+	}
+}
+
+// afterHeader strips the header line, which carries the export timestamp
+func afterHeader(dump []byte) string {
+	if i := bytes.IndexByte(dump, '\n'); i >= 0 {
+		return string(dump[i+1:])
+	}
+	return ""
+}
+
+func TestImportEvents(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	issue := &types.Issue{Title: "Task", AcceptanceCriteria: "Done", IssueType: types.TypeTask,
+		Status: types.StatusOpen, Priority: 2}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	comment := "from the team database"
+	copied := &types.Event{IssueID: issue.ID, EventType: types.EventCommented, Actor: "bob",
+		Comment: &comment, CreatedAt: time.Now().Add(-time.Hour)}
+	added, err := store.ImportEvents(ctx, []*types.Event{copied, copied})
+	if err != nil {
+		t.Fatalf("ImportEvents failed: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("expected the duplicate to be skipped, added %d", added)
+	}
+	if added, _ := store.ImportEvents(ctx, []*types.Event{copied}); added != 0 {
+		t.Errorf("expected re-importing to add nothing, added %d", added)
+	}
+
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	found := false
+	for _, event := range events {
+		if event.Actor == "bob" && event.Comment != nil && *event.Comment == comment {
+			found = true
+			if !event.CreatedAt.Truncate(time.Second).Equal(copied.CreatedAt.Truncate(time.Second)) {
+				t.Errorf("expected the event to keep its timestamp, got %v", event.CreatedAt)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected the imported comment in the issue's events")
+	}
+
+	if _, err := store.ImportEvents(ctx, []*types.Event{{IssueID: "vc-missing", EventType: types.EventCommented,
+		Actor: "bob", CreatedAt: time.Now()}}); err == nil {
+		t.Error("expected importing an event of a missing issue to fail")
+	}
+}
@@ -0,0 +1,298 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ListCustomFields returns all custom field definitions, global ones first,
+// then by project and name
+func (s *VCStorage) ListCustomFields(ctx context.Context) ([]*types.CustomField, error) {
+	return listCustomFields(ctx, s.db)
+}
+
+// customFieldQuerier is satisfied by both *sql.DB and *sql.Tx
+type customFieldQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// listCustomFields loads all custom field definitions through q
+func listCustomFields(ctx context.Context, q customFieldQuerier) ([]*types.CustomField, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT project, name, field_type, description, options, created_at, updated_at
+		FROM vc_custom_fields
+		ORDER BY project, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	var fields []*types.CustomField
+	for rows.Next() {
+		var f types.CustomField
+		var options string
+		if err := rows.Scan(&f.Project, &f.Name, &f.Type, &f.Description, &options, &f.CreatedAt, &f.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		if err := json.Unmarshal([]byte(options), &f.Options); err != nil {
+			return nil, fmt.Errorf("failed to parse options of field %s: %w", types.QualifiedCustomFieldName(f.Project, f.Name), err)
+		}
+		fields = append(fields, &f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating custom fields: %w", err)
+	}
+	return fields, nil
+}
+
+// SaveCustomField creates or updates a custom field definition, filling in
+// the timestamps. A project field needs its project to exist.
+func (s *VCStorage) SaveCustomField(ctx context.Context, field *types.CustomField) error {
+	if err := field.Validate(); err != nil {
+		return err
+	}
+	if field.Project != "" {
+		project, err := s.GetProject(ctx, field.Project)
+		if err != nil {
+			return err
+		}
+		if project == nil {
+			return fmt.Errorf("project %s not found", field.Project)
+		}
+	}
+	options, err := json.Marshal(append([]string{}, field.Options...))
+	if err != nil {
+		return fmt.Errorf("failed to encode options: %w", err)
+	}
+
+	now := time.Now()
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO vc_custom_fields (project, name, field_type, description, options, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (project, name) DO UPDATE SET
+			field_type = excluded.field_type,
+			description = excluded.description,
+			options = excluded.options,
+			updated_at = excluded.updated_at
+		RETURNING created_at
+	`, field.Project, field.Name, field.Type, field.Description, string(options), now, now).Scan(&field.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save field %s: %w", types.QualifiedCustomFieldName(field.Project, field.Name), err)
+	}
+	field.UpdatedAt = now
+	return nil
+}
+
+// DeleteCustomField deletes a custom field definition. Once no definition
+// of the name is left, the values of that name are deleted too.
+func (s *VCStorage) DeleteCustomField(ctx context.Context, project, name string) error {
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM vc_custom_fields WHERE project = ? AND name = ?`, project, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete field %s: %w", types.QualifiedCustomFieldName(project, name), err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("field %s not found", types.QualifiedCustomFieldName(project, name))
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM vc_custom_field_values
+		WHERE name = ? AND NOT EXISTS (SELECT 1 FROM vc_custom_fields WHERE name = ?)
+	`, name, name); err != nil {
+		return fmt.Errorf("failed to delete values of field %s: %w", name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit field deletion: %w", err)
+	}
+	return nil
+}
+
+// SetCustomFieldValue sets a custom field on an issue, or clears it if value
+// is "". The value is checked against the definition that applies to the
+// issue's project.
+func (s *VCStorage) SetCustomFieldValue(ctx context.Context, issueID, name, value, actor string) error {
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues WHERE id = ?`, issueID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to look up issue %s: %w", issueID, err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	project, err := issueProject(ctx, tx, issueID)
+	if err != nil {
+		return err
+	}
+	fields, err := listCustomFields(ctx, tx)
+	if err != nil {
+		return err
+	}
+	field := types.ResolveCustomField(fields, project, name)
+	if field == nil {
+		return fmt.Errorf("field %s is not defined for issue %s", name, issueID)
+	}
+
+	var old sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT value FROM vc_custom_field_values WHERE issue_id = ? AND name = ?
+	`, issueID, name).Scan(&old)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get field %s of %s: %w", name, issueID, err)
+	}
+
+	var newValue sql.NullString
+	comment := "Cleared field: " + name
+	if value == "" {
+		if !old.Valid {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM vc_custom_field_values WHERE issue_id = ? AND name = ?
+		`, issueID, name); err != nil {
+			return fmt.Errorf("failed to clear field %s of %s: %w", name, issueID, err)
+		}
+	} else {
+		if value, err = field.NormalizeValue(value); err != nil {
+			return err
+		}
+		if old.Valid && old.String == value {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO vc_custom_field_values (issue_id, name, value, updated_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (issue_id, name) DO UPDATE SET
+				value = excluded.value,
+				updated_at = excluded.updated_at
+		`, issueID, name, value, time.Now()); err != nil {
+			return fmt.Errorf("failed to set field %s of %s: %w", name, issueID, err)
+		}
+		newValue = sql.NullString{String: value, Valid: true}
+		comment = "Set field: " + name
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, issueID, types.EventUpdated, actor, old, newValue, comment); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit field change: %w", err)
+	}
+	return nil
+}
+
+// GetCustomFieldValues returns an issue's custom field values, sorted by
+// name. Values without a definition for the issue's project are left out.
+func (s *VCStorage) GetCustomFieldValues(ctx context.Context, issueID string) ([]*types.CustomFieldValue, error) {
+	fields, err := s.ListCustomFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.GetLabels(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels of %s: %w", issueID, err)
+	}
+	project := types.ProjectFromLabels(labels)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, value FROM vc_custom_field_values WHERE issue_id = ?
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fields of %s: %w", issueID, err)
+	}
+	defer rows.Close()
+
+	var values []*types.CustomFieldValue
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan field value: %w", err)
+		}
+		if field := types.ResolveCustomField(fields, project, name); field != nil {
+			values = append(values, &types.CustomFieldValue{Name: name, Type: field.Type, Value: value})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating field values: %w", err)
+	}
+	types.SortCustomFieldValues(values)
+	return values, nil
+}
+
+// issueProject returns the project an issue is in, or ""
+func issueProject(ctx context.Context, tx *sql.Tx, issueID string) (string, error) {
+	var label string
+	err := tx.QueryRowContext(ctx, `
+		SELECT label FROM labels WHERE issue_id = ? AND label LIKE ? LIMIT 1
+	`, issueID, types.ProjectLabelPrefix+"%").Scan(&label)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get project of %s: %w", issueID, err)
+	}
+	return strings.TrimPrefix(label, types.ProjectLabelPrefix), nil
+}
+
+// customFieldClauses returns WHERE clauses on issues.id, and their
+// arguments, matching issues whose custom fields have the wanted values
+func customFieldClauses(want map[string]string) ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+	for name, value := range want {
+		where = append(where, "id IN (SELECT issue_id FROM vc_custom_field_values WHERE name = ? AND value = ?)")
+		args = append(args, name, value)
+	}
+	return where, args
+}
+
+// filterByCustomFields keeps the issues whose custom fields have the wanted
+// values, in order
+func (s *VCStorage) filterByCustomFields(ctx context.Context, issues []*types.Issue, want map[string]string) ([]*types.Issue, error) {
+	where, args := customFieldClauses(want)
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM issues WHERE `+strings.Join(where, " AND "), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter issues by field: %w", err)
+	}
+	defer rows.Close()
+
+	matching := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		matching[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating issues: %w", err)
+	}
+
+	kept := issues[:0]
+	for _, issue := range issues {
+		if matching[issue.ID] {
+			kept = append(kept, issue)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,121 @@
+package beads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestCustomFields(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	if err := store.SaveProject(ctx, &types.Project{Name: "web"}); err != nil {
+		t.Fatalf("SaveProject failed: %v", err)
+	}
+	for _, f := range []*types.CustomField{
+		{Name: "component", Type: types.CustomFieldString},
+		{Name: "component", Project: "web", Type: types.CustomFieldEnum, Options: []string{"ui", "api"}},
+		{Name: "sla-hours", Type: types.CustomFieldNumber},
+	} {
+		if err := store.SaveCustomField(ctx, f); err != nil {
+			t.Fatalf("SaveCustomField(%s) failed: %v", f.Name, err)
+		}
+	}
+	if err := store.SaveCustomField(ctx, &types.CustomField{Name: "tier", Project: "missing", Type: types.CustomFieldString}); err == nil {
+		t.Error("expected a field of an unknown project to be rejected")
+	}
+	fields, err := store.ListCustomFields(ctx)
+	if err != nil {
+		t.Fatalf("ListCustomFields failed: %v", err)
+	}
+	if len(fields) != 3 || fields[2].Project != "web" || len(fields[2].Options) != 2 {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+
+	var ids []string
+	for _, title := range []string{"A", "B"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	if err := store.SetIssueProject(ctx, ids[1], "web", "test"); err != nil {
+		t.Fatalf("SetIssueProject failed: %v", err)
+	}
+
+	t.Run("values are checked against the issue's definition", func(t *testing.T) {
+		if err := store.SetCustomFieldValue(ctx, ids[0], "component", "anything", "test"); err != nil {
+			t.Errorf("global string field rejected a value: %v", err)
+		}
+		if err := store.SetCustomFieldValue(ctx, ids[1], "component", "anything", "test"); err == nil {
+			t.Error("expected the project's enum to reject a value outside its options")
+		}
+		if err := store.SetCustomFieldValue(ctx, ids[1], "component", "api", "test"); err != nil {
+			t.Errorf("SetCustomFieldValue failed: %v", err)
+		}
+		if err := store.SetCustomFieldValue(ctx, ids[1], "sla-hours", "four", "test"); err == nil {
+			t.Error("expected a number field to reject text")
+		}
+		if err := store.SetCustomFieldValue(ctx, ids[1], "sla-hours", "4.50", "test"); err != nil {
+			t.Errorf("SetCustomFieldValue failed: %v", err)
+		}
+		if err := store.SetCustomFieldValue(ctx, ids[1], "undefined", "x", "test"); err == nil {
+			t.Error("expected an undefined field to be rejected")
+		}
+
+		values, err := store.GetCustomFieldValues(ctx, ids[1])
+		if err != nil {
+			t.Fatalf("GetCustomFieldValues failed: %v", err)
+		}
+		if len(values) != 2 || values[0].Value != "api" || values[0].Type != types.CustomFieldEnum || values[1].Value != "4.5" {
+			t.Errorf("unexpected values: %+v", values)
+		}
+	})
+
+	t.Run("filters match field values", func(t *testing.T) {
+		filter := types.IssueFilter{CustomFields: map[string]string{"component": "api"}}
+		issues, err := store.SearchIssues(ctx, "", filter)
+		if err != nil {
+			t.Fatalf("SearchIssues failed: %v", err)
+		}
+		if len(issues) != 1 || issues[0].ID != ids[1] {
+			t.Errorf("expected only %s, got %v", ids[1], issues)
+		}
+		page, err := store.SearchIssuesPage(ctx, "", filter, types.PageRequest{})
+		if err != nil {
+			t.Fatalf("SearchIssuesPage failed: %v", err)
+		}
+		if len(page.Issues) != 1 || page.Issues[0].ID != ids[1] {
+			t.Errorf("expected only %s in the page, got %v", ids[1], page.Issues)
+		}
+	})
+
+	t.Run("clearing and deleting", func(t *testing.T) {
+		if err := store.SetCustomFieldValue(ctx, ids[1], "sla-hours", "", "test"); err != nil {
+			t.Fatalf("clearing failed: %v", err)
+		}
+		// The global definition of component remains, so values are kept
+		if err := store.DeleteCustomField(ctx, "web", "component"); err != nil {
+			t.Fatalf("DeleteCustomField failed: %v", err)
+		}
+		values, _ := store.GetCustomFieldValues(ctx, ids[0])
+		if len(values) != 1 {
+			t.Errorf("expected the global component value to survive, got %+v", values)
+		}
+		if err := store.DeleteCustomField(ctx, "", "component"); err != nil {
+			t.Fatalf("DeleteCustomField failed: %v", err)
+		}
+		for _, id := range ids {
+			if values, _ := store.GetCustomFieldValues(ctx, id); len(values) != 0 {
+				t.Errorf("expected no values on %s, got %+v", id, values)
+			}
+		}
+		if err := store.DeleteCustomField(ctx, "", "component"); err == nil {
+			t.Error("expected deleting a missing field to fail")
+		}
+	})
+}
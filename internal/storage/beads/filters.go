@@ -0,0 +1,150 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// savedFilterPredicates is the JSON stored in vc_saved_filters.predicates
+type savedFilterPredicates struct {
+	Query     string          `json:"query,omitempty"`
+	Status    types.Status    `json:"status,omitempty"`
+	IssueType types.IssueType `json:"issue_type,omitempty"`
+	Priority  *int            `json:"priority,omitempty"`
+	Assignee  string          `json:"assignee,omitempty"`
+	Labels    []string        `json:"labels,omitempty"`
+	Project   string          `json:"project,omitempty"`
+	Limit     int             `json:"limit,omitempty"`
+
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+}
+
+// scanSavedFilter fills a saved filter from its row's predicates JSON
+func scanSavedFilter(f *types.SavedFilter, predicates string) error {
+	var p savedFilterPredicates
+	if err := json.Unmarshal([]byte(predicates), &p); err != nil {
+		return fmt.Errorf("failed to parse filter %s: %w", f.Name, err)
+	}
+	f.Query, f.Status, f.IssueType, f.Priority = p.Query, p.Status, p.IssueType, p.Priority
+	f.Assignee, f.Labels, f.Project, f.Limit = p.Assignee, p.Labels, p.Project, p.Limit
+	f.CustomFields = p.CustomFields
+	return nil
+}
+
+// ListSavedFilters returns all saved filters, sorted by name
+func (s *VCStorage) ListSavedFilters(ctx context.Context) ([]*types.SavedFilter, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, description, predicates, created_at, updated_at
+		FROM vc_saved_filters
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved filters: %w", err)
+	}
+	defer rows.Close()
+
+	var filters []*types.SavedFilter
+	for rows.Next() {
+		var f types.SavedFilter
+		var predicates string
+		if err := rows.Scan(&f.Name, &f.Description, &predicates, &f.CreatedAt, &f.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saved filter: %w", err)
+		}
+		if err := scanSavedFilter(&f, predicates); err != nil {
+			return nil, err
+		}
+		filters = append(filters, &f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating saved filters: %w", err)
+	}
+	return filters, nil
+}
+
+// GetSavedFilter returns a saved filter, or nil if it doesn't exist
+func (s *VCStorage) GetSavedFilter(ctx context.Context, name string) (*types.SavedFilter, error) {
+	f := &types.SavedFilter{Name: name}
+	var predicates string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT description, predicates, created_at, updated_at
+		FROM vc_saved_filters
+		WHERE name = ?
+	`, name).Scan(&f.Description, &predicates, &f.CreatedAt, &f.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved filter %s: %w", name, err)
+	}
+	if err := scanSavedFilter(f, predicates); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SaveFilter creates or replaces a saved filter, filling in the timestamps
+func (s *VCStorage) SaveFilter(ctx context.Context, filter *types.SavedFilter) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	predicates, err := json.Marshal(savedFilterPredicates{
+		Query:     filter.Query,
+		Status:    filter.Status,
+		IssueType: filter.IssueType,
+		Priority:  filter.Priority,
+		Assignee:  filter.Assignee,
+		Labels:    filter.Labels,
+		Project:   filter.Project,
+		Limit:     filter.Limit,
+
+		CustomFields: filter.CustomFields,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode filter %s: %w", filter.Name, err)
+	}
+
+	now := time.Now()
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO vc_saved_filters (name, description, predicates, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			description = excluded.description,
+			predicates = excluded.predicates,
+			updated_at = excluded.updated_at
+		RETURNING created_at
+	`, filter.Name, filter.Description, string(predicates), now, now).Scan(&filter.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save filter %s: %w", filter.Name, err)
+	}
+	filter.UpdatedAt = now
+	return nil
+}
+
+// DeleteFilter deletes a saved filter
+func (s *VCStorage) DeleteFilter(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM vc_saved_filters WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete filter %s: %w", name, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("filter %s not found", name)
+	}
+	return nil
+}
+
+// ListIssuesByFilter runs a saved filter
+func (s *VCStorage) ListIssuesByFilter(ctx context.Context, name string) ([]*types.Issue, error) {
+	filter, err := s.GetSavedFilter(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return nil, fmt.Errorf("filter %s not found", name)
+	}
+	return s.SearchIssues(ctx, filter.Query, filter.IssueFilter())
+}
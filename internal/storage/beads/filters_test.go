@@ -0,0 +1,75 @@
+package beads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSavedFilters(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	for i, title := range []string{"Fix login", "Fix logout", "Write docs"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1 + i/2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if i == 0 {
+			if err := store.AddLabel(ctx, issue.ID, "frontend", "test"); err != nil {
+				t.Fatalf("AddLabel failed: %v", err)
+			}
+		}
+	}
+
+	p1 := 1
+	filter := &types.SavedFilter{Name: "ready-p1", Description: "Open P1s", Status: types.StatusOpen, Priority: &p1}
+	if err := store.SaveFilter(ctx, filter); err != nil {
+		t.Fatalf("SaveFilter failed: %v", err)
+	}
+	if err := store.SaveFilter(ctx, &types.SavedFilter{Name: "bad", Status: "nope"}); err == nil {
+		t.Error("expected an invalid status to be rejected")
+	}
+
+	issues, err := store.ListIssuesByFilter(ctx, "ready-p1")
+	if err != nil {
+		t.Fatalf("ListIssuesByFilter failed: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Errorf("expected 2 open P1 issues, got %d", len(issues))
+	}
+
+	// Replacing keeps created_at and round-trips every predicate
+	created := filter.CreatedAt
+	filter.Query = "fix"
+	filter.Labels = []string{"frontend"}
+	if err := store.SaveFilter(ctx, filter); err != nil {
+		t.Fatalf("SaveFilter (update) failed: %v", err)
+	}
+	got, err := store.GetSavedFilter(ctx, "ready-p1")
+	if err != nil || got == nil {
+		t.Fatalf("GetSavedFilter failed: %v", err)
+	}
+	if !got.CreatedAt.Equal(created) || got.Query != "fix" || len(got.Labels) != 1 || got.Priority == nil || *got.Priority != 1 {
+		t.Errorf("unexpected saved filter: %+v", got)
+	}
+	issues, err = store.ListIssuesByFilter(ctx, "ready-p1")
+	if err != nil {
+		t.Fatalf("ListIssuesByFilter failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Title != "Fix login" {
+		t.Errorf("expected only Fix login, got %v", issues)
+	}
+
+	if err := store.DeleteFilter(ctx, "ready-p1"); err != nil {
+		t.Fatalf("DeleteFilter failed: %v", err)
+	}
+	if filters, _ := store.ListSavedFilters(ctx); len(filters) != 0 {
+		t.Errorf("expected no filters after delete, got %d", len(filters))
+	}
+	if _, err := store.ListIssuesByFilter(ctx, "ready-p1"); err == nil {
+		t.Error("expected running a deleted filter to fail")
+	}
+}
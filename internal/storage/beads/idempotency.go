@@ -0,0 +1,49 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetIdempotencyKey returns the result recorded for key, if it was recorded
+// at or after since
+func (s *VCStorage) GetIdempotencyKey(ctx context.Context, key string, since time.Time) (string, bool, error) {
+	var result string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT result FROM vc_idempotency_keys WHERE key = ? AND created_at >= ?
+	`, key, since.UTC().Format(sqliteTimestampFormat)).Scan(&result)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get idempotency key %s: %w", key, err)
+	}
+	return result, true, nil
+}
+
+// RecordIdempotencyKey records key with the result of its side effect,
+// replacing any earlier record
+func (s *VCStorage) RecordIdempotencyKey(ctx context.Context, key, result string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_idempotency_keys (key, result, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET result = excluded.result, created_at = excluded.created_at
+	`, key, result, time.Now().UTC().Format(sqliteTimestampFormat))
+	if err != nil {
+		return fmt.Errorf("failed to record idempotency key %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteIdempotencyKeysBefore deletes keys recorded before the given time,
+// returning how many it deleted
+func (s *VCStorage) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM vc_idempotency_keys WHERE created_at < ?`,
+		before.UTC().Format(sqliteTimestampFormat))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete idempotency keys: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
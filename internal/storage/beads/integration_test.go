@@ -3337,6 +3337,17 @@ func TestMissionSandboxMetadataPersistence(t *testing.T) {
 			retrieved.SandboxPath, retrieved.BranchName)
 	})
 
+	t.Run("SearchIssues sets the mission subtype", func(t *testing.T) {
+		epic := types.TypeEpic
+		epics, err := store.SearchIssues(ctx, "Mission with Sandbox", types.IssueFilter{IssueType: &epic})
+		if err != nil {
+			t.Fatalf("SearchIssues failed: %v", err)
+		}
+		if len(epics) != 1 || epics[0].IssueSubtype != types.SubtypeMission {
+			t.Errorf("Expected the mission with subtype %q, got %+v", types.SubtypeMission, epics)
+		}
+	})
+
 	t.Run("UpdateMission updates sandbox metadata", func(t *testing.T) {
 		// Create initial mission without sandbox metadata
 		mission := &types.Mission{
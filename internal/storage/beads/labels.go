@@ -0,0 +1,299 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ListLabelDefinitions returns saved label definitions, system labels and
+// labels in use without a definition, sorted by name
+func (s *VCStorage) ListLabelDefinitions(ctx context.Context) ([]*types.LabelDefinition, error) {
+	defs := make(map[string]*types.LabelDefinition)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, description, color, created_at, updated_at
+		FROM vc_label_definitions
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query label definitions: %w", err)
+	}
+	for rows.Next() {
+		var def types.LabelDefinition
+		if err := rows.Scan(&def.Name, &def.Description, &def.Color, &def.CreatedAt, &def.UpdatedAt); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan label definition: %w", err)
+		}
+		defs[def.Name] = &def
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating label definitions: %w", err)
+	}
+	_ = rows.Close()
+
+	for name := range types.SystemLabels {
+		if defs[name] == nil {
+			defs[name] = &types.LabelDefinition{Name: name}
+		}
+	}
+
+	rows, err = s.db.QueryContext(ctx, `SELECT label, COUNT(*) FROM labels GROUP BY label`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count labels: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan label count: %w", err)
+		}
+		if defs[name] == nil {
+			defs[name] = &types.LabelDefinition{Name: name}
+		}
+		defs[name].IssueCount = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating label counts: %w", err)
+	}
+
+	result := make([]*types.LabelDefinition, 0, len(defs))
+	for _, def := range defs {
+		fillSystemLabel(def)
+		result = append(result, def)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// GetLabelDefinition returns a label's definition, or nil if the label is
+// not defined, not a system label and not on any issue
+func (s *VCStorage) GetLabelDefinition(ctx context.Context, name string) (*types.LabelDefinition, error) {
+	def := &types.LabelDefinition{Name: name}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT description, color, created_at, updated_at
+		FROM vc_label_definitions
+		WHERE name = ?
+	`, name).Scan(&def.Description, &def.Color, &def.CreatedAt, &def.UpdatedAt)
+	saved := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get label definition %s: %w", name, err)
+	}
+
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM labels WHERE label = ?`, name).Scan(&def.IssueCount); err != nil {
+		return nil, fmt.Errorf("failed to count issues labeled %s: %w", name, err)
+	}
+
+	fillSystemLabel(def)
+	if !saved && !def.System && def.IssueCount == 0 {
+		return nil, nil
+	}
+	return def, nil
+}
+
+// fillSystemLabel marks reserved labels as system labels, with their
+// built-in description unless one was saved
+func fillSystemLabel(def *types.LabelDefinition) {
+	def.System = types.IsSystemLabel(def.Name)
+	if def.System && def.Description == "" {
+		def.Description = types.SystemLabels[def.Name]
+	}
+}
+
+// SaveLabelDefinition creates or updates a label's description and color,
+// filling in System and the timestamps
+func (s *VCStorage) SaveLabelDefinition(ctx context.Context, def *types.LabelDefinition) error {
+	if err := def.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO vc_label_definitions (name, description, color, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			description = excluded.description,
+			color = excluded.color,
+			updated_at = excluded.updated_at
+		RETURNING created_at
+	`, def.Name, def.Description, def.Color, now, now).Scan(&def.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save label definition %s: %w", def.Name, err)
+	}
+	def.UpdatedAt = now
+	def.System = types.IsSystemLabel(def.Name)
+	return nil
+}
+
+// RenameLabel renames a label on every issue carrying it, along with its
+// definition, in one transaction. Issues that already carry newName just
+// lose oldName.
+func (s *VCStorage) RenameLabel(ctx context.Context, oldName, newName, actor string) (int, error) {
+	if err := types.ValidateLabelName(newName); err != nil {
+		return 0, err
+	}
+	if oldName == newName {
+		return 0, fmt.Errorf("label %s is already named %s", oldName, newName)
+	}
+	for _, name := range []string{oldName, newName} {
+		if types.IsSystemLabel(name) {
+			return 0, fmt.Errorf("cannot rename %s to %s: %s: %w", oldName, newName, name, types.ErrSystemLabel)
+		}
+	}
+
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	oldDefined, err := labelDefinedTx(ctx, tx, oldName)
+	if err != nil {
+		return 0, err
+	}
+	newDefined, err := labelDefinedTx(ctx, tx, newName)
+	if err != nil {
+		return 0, err
+	}
+	if oldDefined && newDefined {
+		return 0, fmt.Errorf("cannot rename %s: label %s is already defined", oldName, newName)
+	}
+	ids, err := issuesWithLabelTx(ctx, tx, oldName)
+	if err != nil {
+		return 0, err
+	}
+	if !oldDefined && len(ids) == 0 {
+		return 0, fmt.Errorf("label %s not found", oldName)
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		if err := removeLabelTx(ctx, tx, id, oldName, actor, now); err != nil {
+			return 0, err
+		}
+		result, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)`, id, newName)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add label %s to %s: %w", newName, id, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO events (issue_id, event_type, actor, comment)
+				VALUES (?, ?, ?, ?)
+			`, id, types.EventLabelAdded, actor, fmt.Sprintf("Added label: %s", newName)); err != nil {
+				return 0, fmt.Errorf("failed to record event: %w", err)
+			}
+		}
+	}
+
+	if oldDefined {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE vc_label_definitions SET name = ?, updated_at = ? WHERE name = ?
+		`, newName, now, oldName); err != nil {
+			return 0, fmt.Errorf("failed to rename label definition %s: %w", oldName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit label rename: %w", err)
+	}
+	return len(ids), nil
+}
+
+// DeleteLabel removes a label from every issue carrying it, along with its
+// definition, in one transaction
+func (s *VCStorage) DeleteLabel(ctx context.Context, name, actor string) (int, error) {
+	if types.IsSystemLabel(name) {
+		return 0, fmt.Errorf("cannot delete %s: %w", name, types.ErrSystemLabel)
+	}
+
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	defined, err := labelDefinedTx(ctx, tx, name)
+	if err != nil {
+		return 0, err
+	}
+	ids, err := issuesWithLabelTx(ctx, tx, name)
+	if err != nil {
+		return 0, err
+	}
+	if !defined && len(ids) == 0 {
+		return 0, fmt.Errorf("label %s not found", name)
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		if err := removeLabelTx(ctx, tx, id, name, actor, now); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vc_label_definitions WHERE name = ?`, name); err != nil {
+		return 0, fmt.Errorf("failed to delete label definition %s: %w", name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit label delete: %w", err)
+	}
+	return len(ids), nil
+}
+
+// labelDefinedTx reports whether a label has a saved definition
+func labelDefinedTx(ctx context.Context, tx *sql.Tx, name string) (bool, error) {
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM vc_label_definitions WHERE name = ?`, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to look up label definition %s: %w", name, err)
+	}
+	return count > 0, nil
+}
+
+// issuesWithLabelTx returns the IDs of issues carrying label, sorted
+func issuesWithLabelTx(ctx context.Context, tx *sql.Tx, label string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT issue_id FROM labels WHERE label = ? ORDER BY issue_id`, label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues labeled %s: %w", label, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating labeled issues: %w", err)
+	}
+	return ids, nil
+}
+
+// removeLabelTx removes a label from an issue the way Beads' RemoveLabel
+// does: recording a label_removed event and marking the issue dirty for export
+func removeLabelTx(ctx context.Context, tx *sql.Tx, issueID, label, actor string, now time.Time) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM labels WHERE issue_id = ? AND label = ?`, issueID, label); err != nil {
+		return fmt.Errorf("failed to remove label %s from %s: %w", label, issueID, err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment)
+		VALUES (?, ?, ?, ?)
+	`, issueID, types.EventLabelRemoved, actor, fmt.Sprintf("Removed label: %s", label)); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dirty_issues (issue_id, marked_at)
+		VALUES (?, ?)
+		ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+	`, issueID, now); err != nil {
+		return fmt.Errorf("failed to mark issue %s dirty: %w", issueID, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestLabelDefinitions(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	var ids []string
+	for _, title := range []string{"A", "B"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if err := store.AddLabel(ctx, issue.ID, "frontend", "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	if err := store.AddLabel(ctx, ids[1], "ui", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	def := &types.LabelDefinition{Name: "frontend", Description: "UI work", Color: "#1f77b4"}
+	if err := store.SaveLabelDefinition(ctx, def); err != nil {
+		t.Fatalf("SaveLabelDefinition failed: %v", err)
+	}
+	created := def.CreatedAt
+	def.Color = "#ff7f0e"
+	if err := store.SaveLabelDefinition(ctx, def); err != nil {
+		t.Fatalf("SaveLabelDefinition (update) failed: %v", err)
+	}
+	if !def.CreatedAt.Equal(created) {
+		t.Errorf("expected update to keep created_at %v, got %v", created, def.CreatedAt)
+	}
+
+	t.Run("rename propagates to issues and definition", func(t *testing.T) {
+		count, err := store.RenameLabel(ctx, "frontend", "ui", "alice")
+		if err != nil {
+			t.Fatalf("RenameLabel failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2 issues renamed, got %d", count)
+		}
+		for _, id := range ids {
+			labels, _ := store.GetLabels(ctx, id)
+			if len(labels) != 1 || labels[0] != "ui" {
+				t.Errorf("expected %s to have only ui, got %v", id, labels)
+			}
+		}
+		renamed, err := store.GetLabelDefinition(ctx, "ui")
+		if err != nil || renamed == nil {
+			t.Fatalf("GetLabelDefinition failed: %v", err)
+		}
+		if renamed.Color != "#ff7f0e" || renamed.IssueCount != 2 {
+			t.Errorf("expected definition to follow the rename, got %+v", renamed)
+		}
+		if old, _ := store.GetLabelDefinition(ctx, "frontend"); old != nil {
+			t.Errorf("expected frontend to be gone, got %+v", old)
+		}
+
+		events, err := store.GetEvents(ctx, ids[0], 0)
+		if err != nil {
+			t.Fatalf("GetEvents failed: %v", err)
+		}
+		var removed, added bool
+		for _, e := range events {
+			if e.EventType == types.EventLabelRemoved && e.Actor == "alice" {
+				removed = true
+			}
+			if e.EventType == types.EventLabelAdded && e.Actor == "alice" {
+				added = true
+			}
+		}
+		if !removed || !added {
+			t.Errorf("expected label removed and added events by alice, got %d events", len(events))
+		}
+	})
+
+	t.Run("system labels are protected", func(t *testing.T) {
+		if _, err := store.DeleteLabel(ctx, "needs-approval", "alice"); !errors.Is(err, types.ErrSystemLabel) {
+			t.Errorf("expected ErrSystemLabel, got %v", err)
+		}
+		if _, err := store.RenameLabel(ctx, "escalated", "escalate", "alice"); !errors.Is(err, types.ErrSystemLabel) {
+			t.Errorf("expected ErrSystemLabel, got %v", err)
+		}
+		def, err := store.GetLabelDefinition(ctx, "no-auto-claim")
+		if err != nil || def == nil || !def.System || def.Description == "" {
+			t.Errorf("expected built-in definition for no-auto-claim, got %+v, %v", def, err)
+		}
+	})
+
+	t.Run("delete removes label everywhere", func(t *testing.T) {
+		count, err := store.DeleteLabel(ctx, "ui", "alice")
+		if err != nil || count != 2 {
+			t.Fatalf("DeleteLabel = %d, %v; want 2, nil", count, err)
+		}
+		defs, err := store.ListLabelDefinitions(ctx)
+		if err != nil {
+			t.Fatalf("ListLabelDefinitions failed: %v", err)
+		}
+		for _, def := range defs {
+			if !def.System {
+				t.Errorf("expected only system labels to remain, found %+v", def)
+			}
+		}
+		if _, err := store.DeleteLabel(ctx, "ui", "alice"); err == nil {
+			t.Error("expected deleting an unknown label to fail")
+		}
+	})
+}
@@ -260,7 +260,7 @@ func (s *VCStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor s
 	beadsIssue := vcIssueToBeads(issue)
 
 	// Create in Beads
-	if err := s.Storage.CreateIssue(ctx, beadsIssue, actor); err != nil {
+	if err := withBusyRetry(ctx, func() error { return s.Storage.CreateIssue(ctx, beadsIssue, actor) }); err != nil {
 		return err
 	}
 
@@ -279,6 +279,7 @@ func (s *VCStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor s
 		}
 	}
 
+	s.recordAudit(ctx, types.AuditEntriesFor(issue.ID, nil, issue, actor))
 	return nil
 }
 
@@ -394,7 +395,7 @@ func (s *VCStorage) UpdateMission(ctx context.Context, id string, updates map[st
 
 	// Update base issue fields if any
 	if len(baseUpdates) > 0 {
-		if err := s.Storage.UpdateIssue(ctx, id, baseUpdates, actor); err != nil {
+		if err := s.UpdateIssue(ctx, id, baseUpdates, actor); err != nil {
 			return fmt.Errorf("failed to update base issue fields: %w", err)
 		}
 	}
@@ -540,8 +541,32 @@ func (s *VCStorage) UpdateIssue(ctx context.Context, id string, updates map[stri
 		}
 	}
 
+	before, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	// Delegate to Beads (it handles all core issue fields)
-	return s.Storage.UpdateIssue(ctx, id, updates, actor)
+	if err := withBusyRetry(ctx, func() error { return s.Storage.UpdateIssue(ctx, id, updates, actor) }); err != nil {
+		return err
+	}
+
+	s.auditChange(ctx, id, before, actor)
+	return nil
+}
+
+// auditChange records the difference between before and the issue's current
+// state. Audit failures are only logged, since the change is already committed.
+func (s *VCStorage) auditChange(ctx context.Context, id string, before *types.Issue, actor string) {
+	if before == nil {
+		return
+	}
+	after, err := s.GetIssue(ctx, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read %s for audit: %v\n", id, err)
+		return
+	}
+	s.recordAudit(ctx, types.AuditEntriesFor(id, before, after, actor))
 }
 
 // CloseIssue closes an issue in Beads
@@ -561,9 +586,14 @@ func (s *VCStorage) CloseIssue(ctx context.Context, id string, reason string, ac
 		}
 	}
 
+	before, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	// First, clean up execution state if it exists (vc-4820)
 	// This prevents leaving orphaned execution state when closing issues manually
-	_, err := s.db.ExecContext(ctx, `
+	_, err = s.db.ExecContext(ctx, `
 		DELETE FROM vc_issue_execution_state
 		WHERE issue_id = ?
 	`, id)
@@ -578,13 +608,18 @@ func (s *VCStorage) CloseIssue(ctx context.Context, id string, reason string, ac
 	updates := map[string]interface{}{
 		"assignee": nil, // Clear assignee
 	}
-	if err := s.Storage.UpdateIssue(ctx, id, updates, actor); err != nil {
+	if err := withBusyRetry(ctx, func() error { return s.Storage.UpdateIssue(ctx, id, updates, actor) }); err != nil {
 		// Log warning but don't fail the close operation
 		fmt.Fprintf(os.Stderr, "Warning: failed to clear assignee for %s: %v\n", id, err)
 	}
 
 	// Delegate to Beads for the actual issue close
-	return s.Storage.CloseIssue(ctx, id, reason, actor)
+	if err := withBusyRetry(ctx, func() error { return s.Storage.CloseIssue(ctx, id, reason, actor) }); err != nil {
+		return err
+	}
+
+	s.auditChange(ctx, id, before, actor)
+	return nil
 }
 
 // SearchIssues searches issues in Beads with optional label filtering (vc-fwx8)
@@ -596,6 +631,12 @@ func (s *VCStorage) SearchIssues(ctx context.Context, query string, filter types
 		Labels:   filter.Labels, // vc-fwx8: Pass through labels to Beads (Beads supports this!)
 		Limit:    filter.Limit,
 	}
+	if len(filter.CustomFields) > 0 {
+		beadsFilter.Limit = 0 // Applied after the custom field filter below
+	}
+	if filter.Project != "" {
+		beadsFilter.Labels = append(append([]string(nil), filter.Labels...), types.ProjectLabel(filter.Project))
+	}
 
 	// Convert pointer fields if not nil
 	if filter.Status != nil {
@@ -620,10 +661,53 @@ func (s *VCStorage) SearchIssues(ctx context.Context, query string, filter types
 	for i, bi := range beadsIssues {
 		vcIssues[i] = beadsIssueToVC(bi)
 	}
+	if err := s.loadSubtypes(ctx, vcIssues); err != nil {
+		return nil, err
+	}
+
+	if len(filter.CustomFields) > 0 {
+		if vcIssues, err = s.filterByCustomFields(ctx, vcIssues, filter.CustomFields); err != nil {
+			return nil, err
+		}
+		if filter.Limit > 0 && len(vcIssues) > filter.Limit {
+			vcIssues = vcIssues[:filter.Limit]
+		}
+	}
 
 	return vcIssues, nil
 }
 
+// loadSubtypes sets the subtypes of issues from the VC extension table,
+// which only has rows for missions and reviews
+func (s *VCStorage) loadSubtypes(ctx context.Context, issues []*types.Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT issue_id, subtype FROM vc_mission_state")
+	if err != nil {
+		return fmt.Errorf("failed to query subtypes: %w", err)
+	}
+	defer rows.Close()
+
+	subtypes := make(map[string]types.IssueSubtype)
+	for rows.Next() {
+		var issueID, subtype string
+		if err := rows.Scan(&issueID, &subtype); err != nil {
+			return fmt.Errorf("failed to scan subtype: %w", err)
+		}
+		subtypes[issueID] = types.IssueSubtype(subtype)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating subtype rows: %w", err)
+	}
+	for _, issue := range issues {
+		if subtype, ok := subtypes[issue.ID]; ok {
+			issue.IssueSubtype = subtype
+		}
+	}
+	return nil
+}
+
 // ======================================================================
 // DEPENDENCIES (delegate to Beads)
 // ======================================================================
@@ -635,12 +719,12 @@ func (s *VCStorage) AddDependency(ctx context.Context, dep *types.Dependency, ac
 		DependsOnID: dep.DependsOnID,
 		Type:        beads.DependencyType(dep.Type),
 	}
-	return s.Storage.AddDependency(ctx, beadsDep, actor)
+	return withBusyRetry(ctx, func() error { return s.Storage.AddDependency(ctx, beadsDep, actor) })
 }
 
 // RemoveDependency removes a dependency from Beads
 func (s *VCStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
-	return s.Storage.RemoveDependency(ctx, issueID, dependsOnID, actor)
+	return withBusyRetry(ctx, func() error { return s.Storage.RemoveDependency(ctx, issueID, dependsOnID, actor) })
 }
 
 // GetDependencies retrieves dependencies from Beads
@@ -725,6 +809,78 @@ func (s *VCStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error)
 	return vcCycles, nil
 }
 
+// GetBlockers retrieves the unclosed issues blocking issueID via 'blocks' dependencies
+func (s *VCStorage) GetBlockers(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return s.queryBlockIssues(ctx, `
+		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria,
+		       i.notes, i.status, i.priority, i.issue_type, i.assignee,
+		       i.estimated_minutes, i.created_at, i.updated_at, i.closed_at
+		FROM dependencies d
+		INNER JOIN issues i ON d.depends_on_id = i.id
+		WHERE d.issue_id = ?
+		  AND d.type = 'blocks'
+		  AND i.status != 'closed'
+		ORDER BY i.priority ASC, i.created_at ASC
+	`, issueID)
+}
+
+// GetBlocked retrieves the unclosed issues that issueID blocks via 'blocks' dependencies
+func (s *VCStorage) GetBlocked(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return s.queryBlockIssues(ctx, `
+		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria,
+		       i.notes, i.status, i.priority, i.issue_type, i.assignee,
+		       i.estimated_minutes, i.created_at, i.updated_at, i.closed_at
+		FROM dependencies d
+		INNER JOIN issues i ON d.issue_id = i.id
+		WHERE d.depends_on_id = ?
+		  AND d.type = 'blocks'
+		  AND i.status != 'closed'
+		ORDER BY i.priority ASC, i.created_at ASC
+	`, issueID)
+}
+
+// queryBlockIssues runs a GetBlockers/GetBlocked query and scans the issues
+func (s *VCStorage) queryBlockIssues(ctx context.Context, query, issueID string) ([]*types.Issue, error) {
+	rows, err := s.db.QueryContext(ctx, query, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocking dependencies for %s: %w", issueID, err)
+	}
+	defer rows.Close()
+
+	var issues []*types.Issue
+	for rows.Next() {
+		var issue types.Issue
+		var closedAt sql.NullTime
+		var assignee sql.NullString
+		var estimatedMinutes sql.NullInt64
+
+		if err := rows.Scan(
+			&issue.ID, &issue.Title, &issue.Description, &issue.Design,
+			&issue.AcceptanceCriteria, &issue.Notes, &issue.Status,
+			&issue.Priority, &issue.IssueType, &assignee,
+			&estimatedMinutes, &issue.CreatedAt, &issue.UpdatedAt, &closedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan issue: %w", err)
+		}
+
+		if closedAt.Valid {
+			issue.ClosedAt = &closedAt.Time
+		}
+		if assignee.Valid {
+			issue.Assignee = assignee.String
+		}
+		if estimatedMinutes.Valid {
+			val := int(estimatedMinutes.Int64)
+			issue.EstimatedMinutes = &val
+		}
+		issues = append(issues, &issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return issues, nil
+}
+
 // ======================================================================
 // LABELS (delegate to Beads)
 // ======================================================================
@@ -758,6 +914,9 @@ func (s *VCStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 		Limit:      filter.Limit,
 		SortPolicy: beads.SortPolicy(filter.SortPolicy), // Pass through sort policy (vc-190)
 	}
+	if filter.Project != "" {
+		beadsFilter.Labels = []string{types.ProjectLabel(filter.Project)}
+	}
 
 	beadsIssues, err := s.Storage.GetReadyWork(ctx, beadsFilter)
 	if err != nil {
@@ -797,13 +956,14 @@ func (s *VCStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 		return nil, fmt.Errorf("failed to batch-load issue labels: %w", err)
 	}
 
-	// Filter out issues with 'no-auto-claim' label
+	// Filter out issues with 'no-auto-claim' label, and issues deferred
+	// until the AI provider recovers
 	filteredIssues := make([]*types.Issue, 0, len(vcIssues))
 	for _, issue := range vcIssues {
 		labels := issueLabels[issue.ID]
 		hasNoAutoClaim := false
 		for _, label := range labels {
-			if label == "no-auto-claim" {
+			if label == "no-auto-claim" || label == types.LabelWaitingOnAI {
 				hasNoAutoClaim = true
 				break
 			}
@@ -817,31 +977,22 @@ func (s *VCStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) (
 		fmt.Fprintf(os.Stderr, "[work-selection] After no-auto-claim filter: %d issues\n", len(filteredIssues))
 	}
 
-	// vc-165b: Filter out issues with active intervention backoff
+	// vc-165b: Filter out issues with active intervention backoff, loading
+	// the intervention state of all candidates in one query
+	filteredIDs := make(map[string]bool, len(filteredIssues))
+	for _, issue := range filteredIssues {
+		filteredIDs[issue.ID] = true
+	}
+	inBackoff, err := s.batchLoadInterventionBackoff(ctx, filteredIDs)
+	if err != nil {
+		// Log warning but don't fail - include the issues if we can't check state
+		fmt.Fprintf(os.Stderr, "Warning: failed to load intervention backoff: %v\n", err)
+	}
 	backoffFilteredIssues := make([]*types.Issue, 0, len(filteredIssues))
 	for _, issue := range filteredIssues {
-		// Get execution state to check intervention backoff
-		execState, err := s.GetExecutionState(ctx, issue.ID)
-		if err != nil {
-			// Log warning but don't fail - include the issue if we can't check state
-			fmt.Fprintf(os.Stderr, "Warning: failed to get execution state for %s: %v\n", issue.ID, err)
+		if !inBackoff[issue.ID] {
 			backoffFilteredIssues = append(backoffFilteredIssues, issue)
-			continue
 		}
-
-		// If no execution state, no backoff - include it
-		if execState == nil {
-			backoffFilteredIssues = append(backoffFilteredIssues, issue)
-			continue
-		}
-
-		// Calculate backoff duration
-		backoff := CalculateInterventionBackoff(execState.InterventionCount, execState.LastInterventionTime)
-		if backoff == 0 {
-			// No active backoff - include it
-			backoffFilteredIssues = append(backoffFilteredIssues, issue)
-		}
-		// Otherwise skip this issue - it's in backoff period
 	}
 
 	if debugWorkSelection {
@@ -933,6 +1084,49 @@ func (s *VCStorage) enrichWithMissionContext(ctx context.Context, issues []*type
 	return result, nil
 }
 
+// batchLoadInterventionBackoff returns which of the given issues are in an
+// intervention backoff period (vc-165b), in a single query rather than one
+// execution state lookup per issue
+func (s *VCStorage) batchLoadInterventionBackoff(ctx context.Context, issueIDs map[string]bool) (map[string]bool, error) {
+	result := make(map[string]bool)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, 0, len(issueIDs))
+	args := make([]interface{}, 0, len(issueIDs))
+	for id := range issueIDs {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
+	// Issues without interventions are never in backoff
+	query := fmt.Sprintf(`
+		SELECT issue_id, intervention_count, last_intervention_time
+		FROM vc_issue_execution_state
+		WHERE issue_id IN (%s) AND intervention_count > 0 AND last_intervention_time IS NOT NULL
+	`, strings.Join(placeholders, ", "))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query intervention state: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var issueID string
+		var count int
+		var lastIntervention time.Time
+		if err := rows.Scan(&issueID, &count, &lastIntervention); err != nil {
+			return nil, fmt.Errorf("failed to scan intervention state: %w", err)
+		}
+		if CalculateInterventionBackoff(count, &lastIntervention) > 0 {
+			result[issueID] = true
+		}
+	}
+	return result, rows.Err()
+}
+
 // batchLoadLabels loads labels for multiple issues in a single query (vc-239)
 func (s *VCStorage) batchLoadLabels(ctx context.Context, issueIDs map[string]bool) (map[string][]string, error) {
 	if len(issueIDs) == 0 {
@@ -1311,27 +1505,38 @@ func (s *VCStorage) GetReadyBaselineIssues(ctx context.Context, limit int) ([]*t
 
 // AddComment delegates to Beads (already available via embedded beads.Storage)
 
-// GetEvents retrieves events from Beads and converts to VC types
+// GetEvents retrieves an issue's events, newest first. It queries the events
+// table directly rather than through Beads so that events recorded in the same
+// instant come back in a stable order (newest ID first), served by the
+// (issue_id, created_at) index without a separate sort.
 func (s *VCStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
-	beadsEvents, err := s.Storage.GetEvents(ctx, issueID, limit)
+	query := `
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
+		FROM events
+		WHERE issue_id = ?
+		ORDER BY created_at DESC, id DESC
+	`
+	args := []interface{}{issueID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
+	defer func() { _ = rows.Close() }()
 
-	vcEvents := make([]*types.Event, len(beadsEvents))
-	for i, be := range beadsEvents {
-		vcEvents[i] = &types.Event{
-			ID:        be.ID,
-			IssueID:   be.IssueID,
-			EventType: types.EventType(be.EventType),
-			Actor:     be.Actor,
-			OldValue:  be.OldValue,
-			NewValue:  be.NewValue,
-			Comment:   be.Comment,
-			CreatedAt: be.CreatedAt,
+	var vcEvents []*types.Event
+	for rows.Next() {
+		var e types.Event
+		if err := rows.Scan(&e.ID, &e.IssueID, &e.EventType, &e.Actor, &e.OldValue, &e.NewValue, &e.Comment, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
+		vcEvents = append(vcEvents, &e)
 	}
-	return vcEvents, nil
+	return vcEvents, rows.Err()
 }
 
 // ======================================================================
@@ -1923,6 +2128,13 @@ func (s *VCStorage) VacuumDatabase(ctx context.Context) error {
 	return err
 }
 
+// AnalyzeDatabase refreshes the statistics SQLite's query planner uses to
+// pick indexes
+func (s *VCStorage) AnalyzeDatabase(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, "ANALYZE")
+	return err
+}
+
 // ======================================================================
 // CODE REVIEW CHECKPOINTS (vc-1)
 // ======================================================================
@@ -553,3 +553,118 @@ func TestHealthMetricsRetention(t *testing.T) {
 		}
 	}
 }
+
+// TestGetBlockersAndBlocked verifies that only unclosed 'blocks' dependencies
+// are reported, in both directions
+func TestGetBlockersAndBlocked(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	newIssue := func(title string, priority int) *types.Issue {
+		issue := &types.Issue{
+			Title:              title,
+			Status:             types.StatusOpen,
+			Priority:           priority,
+			IssueType:          types.TypeTask,
+			AcceptanceCriteria: "Done",
+		}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	work := newIssue("Work", 2)
+	schema := newIssue("Schema", 1)
+	api := newIssue("API", 2)
+	related := newIssue("Related", 0)
+
+	for _, dep := range []*types.Dependency{
+		{IssueID: work.ID, DependsOnID: schema.ID, Type: types.DepBlocks},
+		{IssueID: work.ID, DependsOnID: api.ID, Type: types.DepBlocks},
+		{IssueID: work.ID, DependsOnID: related.ID, Type: types.DepRelated},
+	} {
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	// Closing the loop would create a cycle
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: schema.ID, DependsOnID: work.ID, Type: types.DepBlocks}, "test"); err == nil {
+		t.Error("expected AddDependency to reject a cycle")
+	}
+
+	blockers, err := store.GetBlockers(ctx, work.ID)
+	if err != nil {
+		t.Fatalf("GetBlockers failed: %v", err)
+	}
+	if len(blockers) != 2 || blockers[0].ID != schema.ID || blockers[1].ID != api.ID {
+		t.Errorf("expected blockers [%s %s] by priority, got %v", schema.ID, api.ID, blockers)
+	}
+
+	blocked, err := store.GetBlocked(ctx, schema.ID)
+	if err != nil {
+		t.Fatalf("GetBlocked failed: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != work.ID {
+		t.Errorf("expected %s to block [%s], got %v", schema.ID, work.ID, blocked)
+	}
+	if blocked, _ := store.GetBlocked(ctx, related.ID); len(blocked) != 0 {
+		t.Errorf("related dependencies must not block, got %v", blocked)
+	}
+
+	if err := store.CloseIssue(ctx, schema.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	blockers, err = store.GetBlockers(ctx, work.ID)
+	if err != nil {
+		t.Fatalf("GetBlockers failed: %v", err)
+	}
+	if len(blockers) != 1 || blockers[0].ID != api.ID {
+		t.Errorf("expected only %s after closing %s, got %v", api.ID, schema.ID, blockers)
+	}
+}
+
+// TestGetReadyWorkSkipsInterventionBackoff verifies that issues the watchdog
+// recently intervened on stay out of the ready queue until their backoff ends
+func TestGetReadyWorkSkipsInterventionBackoff(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	var ids []string
+	for _, title := range []string{"Backed off task", "Ready task"} {
+		issue := &types.Issue{
+			Title:              title,
+			Status:             types.StatusOpen,
+			Priority:           1,
+			IssueType:          types.TypeTask,
+			AcceptanceCriteria: "Test acceptance criteria",
+		}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	if err := store.RecordWatchdogIntervention(ctx, ids[0]); err != nil {
+		t.Fatalf("Failed to record intervention: %v", err)
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != ids[1] {
+		var got []string
+		for _, issue := range ready {
+			got = append(got, issue.ID)
+		}
+		t.Errorf("GetReadyWork returned %v, want only %s", got, ids[1])
+	}
+}
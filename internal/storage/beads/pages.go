@@ -0,0 +1,200 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// pageKeyExpr returns the SQL for a timestamp column's cursor key. strftime
+// normalizes both CURRENT_TIMESTAMP and Go's timestamp format to UTC with
+// milliseconds, matching types.PageKeyFormat, so keys compare as text.
+func pageKeyExpr(column string) string {
+	return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:%%M:%%f', %s)", column)
+}
+
+// pageClauses returns the WHERE clauses, their arguments and the ORDER BY
+// for a keyset page over (keyExpr, id). cursorID is the cursor's ID as the
+// id column's type.
+func pageClauses(page types.PageRequest, cursor *types.PageCursor, keyExpr string, cursorID interface{}) ([]string, []interface{}, string) {
+	var where []string
+	var args []interface{}
+	if since := page.SinceKey(); since != "" {
+		where = append(where, keyExpr+" >= ?")
+		args = append(args, since)
+	}
+	dir, op := "ASC", ">"
+	if page.Descending() {
+		dir, op = "DESC", "<"
+	}
+	if cursor != nil {
+		where = append(where, fmt.Sprintf("(%s, id) %s (?, ?)", keyExpr, op))
+		args = append(args, cursor.Key, cursorID)
+	}
+	return where, args, fmt.Sprintf("ORDER BY %s %s, id %s", keyExpr, dir, dir)
+}
+
+// GetEventsPage returns a page of events, across all issues if issueID is ""
+func (s *VCStorage) GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+	cursor, _ := page.DecodeCursor()
+	var cursorID int64
+	if cursor != nil {
+		var err error
+		if cursorID, err = strconv.ParseInt(cursor.ID, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid page cursor for events: %w", err)
+		}
+	}
+
+	keyExpr := pageKeyExpr("created_at")
+	where, args, orderBy := pageClauses(page, cursor, keyExpr, cursorID)
+	if issueID != "" {
+		where = append([]string{"issue_id = ?"}, where...)
+		args = append([]interface{}{issueID}, args...)
+	}
+	query := `SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at, ` + keyExpr + ` FROM events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " " + orderBy + " LIMIT ?"
+	args = append(args, page.PageSize()+1)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	result := &types.EventPage{}
+	var keys []string
+	for rows.Next() {
+		var e types.Event
+		var key string
+		if err := rows.Scan(&e.ID, &e.IssueID, &e.EventType, &e.Actor, &e.OldValue, &e.NewValue, &e.Comment, &e.CreatedAt, &key); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		result.Events = append(result.Events, &e)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	// The extra row only tells us there is another page
+	if size := page.PageSize(); len(result.Events) > size {
+		result.Events = result.Events[:size]
+		last := result.Events[size-1]
+		result.NextCursor = types.PageCursor{Key: keys[size-1], ID: strconv.FormatInt(last.ID, 10)}.Encode()
+	}
+	return result, nil
+}
+
+// SearchIssuesPage returns a page of the issues SearchIssues would return.
+// It selects the page's IDs with the same predicates Beads' SearchIssues
+// uses, then loads the issues in one batch.
+func (s *VCStorage) SearchIssuesPage(ctx context.Context, query string, filter types.IssueFilter, page types.PageRequest) (*types.IssuePage, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+	cursor, _ := page.DecodeCursor()
+
+	var where []string
+	var args []interface{}
+	if query != "" {
+		where = append(where, "(title LIKE ? OR description LIKE ? OR id LIKE ?)")
+		pattern := "%" + query + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+	if filter.Status != nil {
+		where = append(where, "status = ?")
+		args = append(args, *filter.Status)
+	}
+	if filter.Priority != nil {
+		where = append(where, "priority = ?")
+		args = append(args, *filter.Priority)
+	}
+	issueType := filter.IssueType
+	if filter.Type != nil {
+		issueType = filter.Type
+	}
+	if issueType != nil {
+		where = append(where, "issue_type = ?")
+		args = append(args, *issueType)
+	}
+	if filter.Assignee != nil {
+		where = append(where, "assignee = ?")
+		args = append(args, *filter.Assignee)
+	}
+	labels := filter.Labels
+	if filter.Project != "" {
+		labels = append(append([]string(nil), labels...), types.ProjectLabel(filter.Project))
+	}
+	for _, label := range labels {
+		where = append(where, "id IN (SELECT issue_id FROM labels WHERE label = ?)")
+		args = append(args, label)
+	}
+	fieldWhere, fieldArgs := customFieldClauses(filter.CustomFields)
+	where = append(where, fieldWhere...)
+	args = append(args, fieldArgs...)
+
+	column := types.IssueOrderCreated
+	if page.OrderBy == types.IssueOrderUpdated {
+		column = types.IssueOrderUpdated
+	}
+	var cursorID interface{}
+	if cursor != nil {
+		cursorID = cursor.ID
+	}
+	keyExpr := pageKeyExpr(column)
+	pageWhere, pageArgs, orderBy := pageClauses(page, cursor, keyExpr, cursorID)
+	where = append(where, pageWhere...)
+	args = append(args, pageArgs...)
+
+	sqlQuery := `SELECT id, ` + keyExpr + ` FROM issues`
+	if len(where) > 0 {
+		sqlQuery += " WHERE " + strings.Join(where, " AND ")
+	}
+	sqlQuery += " " + orderBy + " LIMIT ?"
+	args = append(args, page.PageSize()+1)
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	var ids, keys []string
+	for rows.Next() {
+		var id, key string
+		if err := rows.Scan(&id, &key); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		ids = append(ids, id)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating issues: %w", err)
+	}
+	_ = rows.Close()
+
+	result := &types.IssuePage{}
+	if size := page.PageSize(); len(ids) > size {
+		ids = ids[:size]
+		result.NextCursor = types.PageCursor{Key: keys[size-1], ID: ids[size-1]}.Encode()
+	}
+	issues, err := s.GetIssues(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if issue, ok := issues[id]; ok { // Skip issues deleted since the ID query
+			result.Issues = append(result.Issues, issue)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestPagination(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	var ids []string
+	for i := 0; i < 7; i++ {
+		issue := &types.Issue{Title: fmt.Sprintf("Issue %d", i), Status: types.StatusOpen, Priority: i % 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+		if err := store.AddComment(ctx, issue.ID, "test", "note"); err != nil {
+			t.Fatalf("AddComment failed: %v", err)
+		}
+	}
+
+	t.Run("events walk in order without gaps", func(t *testing.T) {
+		for _, order := range []types.SortOrder{types.SortAscending, types.SortDescending} {
+			var seen []int64
+			page := types.PageRequest{Limit: 3, Order: order}
+			for pages := 0; ; pages++ {
+				if pages > 10 {
+					t.Fatal("pagination did not terminate")
+				}
+				result, err := store.GetEventsPage(ctx, "", page)
+				if err != nil {
+					t.Fatalf("GetEventsPage failed: %v", err)
+				}
+				for _, e := range result.Events {
+					seen = append(seen, e.ID)
+				}
+				if result.NextCursor == "" {
+					break
+				}
+				page.Cursor = result.NextCursor
+			}
+			if len(seen) != 14 {
+				t.Fatalf("%s: expected 14 events, got %d", order, len(seen))
+			}
+			for i := 1; i < len(seen); i++ {
+				if (order == types.SortAscending) != (seen[i] > seen[i-1]) {
+					t.Fatalf("%s: events out of order: %v", order, seen)
+				}
+			}
+		}
+	})
+
+	t.Run("events of one issue", func(t *testing.T) {
+		result, err := store.GetEventsPage(ctx, ids[0], types.PageRequest{})
+		if err != nil {
+			t.Fatalf("GetEventsPage failed: %v", err)
+		}
+		if len(result.Events) != 2 || result.NextCursor != "" {
+			t.Errorf("expected 2 events and no next page, got %d (%q)", len(result.Events), result.NextCursor)
+		}
+	})
+
+	t.Run("issues with filter", func(t *testing.T) {
+		p0 := 0
+		filter := types.IssueFilter{Priority: &p0}
+		page := types.PageRequest{Limit: 2, Order: types.SortDescending}
+		var seen []string
+		for {
+			result, err := store.SearchIssuesPage(ctx, "", filter, page)
+			if err != nil {
+				t.Fatalf("SearchIssuesPage failed: %v", err)
+			}
+			for _, issue := range result.Issues {
+				seen = append(seen, issue.ID)
+			}
+			if result.NextCursor == "" {
+				break
+			}
+			page.Cursor = result.NextCursor
+		}
+		want := []string{ids[6], ids[4], ids[2], ids[0]}
+		if fmt.Sprint(seen) != fmt.Sprint(want) {
+			t.Errorf("expected %v, got %v", want, seen)
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		if _, err := store.GetEventsPage(ctx, "", types.PageRequest{Cursor: "!!"}); err == nil {
+			t.Error("expected a malformed cursor to be rejected")
+		}
+		if _, err := store.SearchIssuesPage(ctx, "", types.IssueFilter{}, types.PageRequest{OrderBy: "title"}); err == nil {
+			t.Error("expected an unknown order field to be rejected")
+		}
+	})
+}
@@ -138,7 +138,7 @@ func (s *VCStorage) StorePlan(ctx context.Context, plan *types.MissionPlan, expe
 	}
 
 	// Begin transaction (vc-gxfn: atomic write with rollback on failure)
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.beginWriteTx(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
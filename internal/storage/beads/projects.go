@@ -0,0 +1,189 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// projectCountsQuery counts a project's open and closed issues through its
+// membership label
+const projectCountsQuery = `
+	SELECT
+		COALESCE(SUM(CASE WHEN i.status != 'closed' THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN i.status = 'closed' THEN 1 ELSE 0 END), 0)
+	FROM labels l
+	JOIN issues i ON i.id = l.issue_id
+	WHERE l.label = ?
+`
+
+// ListProjects returns all projects with their issue counts, sorted by name
+func (s *VCStorage) ListProjects(ctx context.Context) ([]*types.Project, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, description, repo_path, default_branch, created_at, updated_at
+		FROM vc_projects
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+	var projects []*types.Project
+	for rows.Next() {
+		var p types.Project
+		if err := rows.Scan(&p.Name, &p.Description, &p.RepoPath, &p.DefaultBranch, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, &p)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating projects: %w", err)
+	}
+	_ = rows.Close()
+
+	for _, p := range projects {
+		if err := s.countProjectIssues(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+	return projects, nil
+}
+
+// GetProject returns a project with its issue counts, or nil if it doesn't exist
+func (s *VCStorage) GetProject(ctx context.Context, name string) (*types.Project, error) {
+	p := &types.Project{Name: name}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT description, repo_path, default_branch, created_at, updated_at
+		FROM vc_projects
+		WHERE name = ?
+	`, name).Scan(&p.Description, &p.RepoPath, &p.DefaultBranch, &p.CreatedAt, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %s: %w", name, err)
+	}
+	if err := s.countProjectIssues(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// countProjectIssues fills in a project's open and closed issue counts
+func (s *VCStorage) countProjectIssues(ctx context.Context, p *types.Project) error {
+	if err := s.db.QueryRowContext(ctx, projectCountsQuery, types.ProjectLabel(p.Name)).Scan(&p.OpenIssues, &p.ClosedIssues); err != nil {
+		return fmt.Errorf("failed to count issues in project %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// SaveProject creates or updates a project, filling in the timestamps
+func (s *VCStorage) SaveProject(ctx context.Context, project *types.Project) error {
+	if err := project.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO vc_projects (name, description, repo_path, default_branch, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			description = excluded.description,
+			repo_path = excluded.repo_path,
+			default_branch = excluded.default_branch,
+			updated_at = excluded.updated_at
+		RETURNING created_at
+	`, project.Name, project.Description, project.RepoPath, project.DefaultBranch, now, now).Scan(&project.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save project %s: %w", project.Name, err)
+	}
+	project.UpdatedAt = now
+	return nil
+}
+
+// SetIssueProject moves an issue into project, or out of any project if
+// project is "", replacing its project label in one transaction
+func (s *VCStorage) SetIssueProject(ctx context.Context, issueID, project, actor string) error {
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues WHERE id = ?`, issueID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to look up issue %s: %w", issueID, err)
+	}
+	if exists == 0 {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	if project != "" {
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM vc_projects WHERE name = ?`, project).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to look up project %s: %w", project, err)
+		}
+		if exists == 0 {
+			return fmt.Errorf("project %s not found", project)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT label FROM labels WHERE issue_id = ? AND label LIKE ?
+	`, issueID, types.ProjectLabelPrefix+"%")
+	if err != nil {
+		return fmt.Errorf("failed to query project labels of %s: %w", issueID, err)
+	}
+	var current []string
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan label: %w", err)
+		}
+		current = append(current, label)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return fmt.Errorf("error iterating labels: %w", err)
+	}
+	_ = rows.Close()
+
+	now := time.Now()
+	want := types.ProjectLabel(project)
+	add := project != ""
+	for _, label := range current {
+		if label == want {
+			add = false // Already in the project
+			continue
+		}
+		if err := removeLabelTx(ctx, tx, issueID, label, actor, now); err != nil {
+			return err
+		}
+	}
+	if add {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO labels (issue_id, label) VALUES (?, ?)`, issueID, want); err != nil {
+			return fmt.Errorf("failed to add label %s to %s: %w", want, issueID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, comment)
+			VALUES (?, ?, ?, ?)
+		`, issueID, types.EventLabelAdded, actor, fmt.Sprintf("Added label: %s", want)); err != nil {
+			return fmt.Errorf("failed to record event: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO dirty_issues (issue_id, marked_at)
+			VALUES (?, ?)
+			ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+		`, issueID, now); err != nil {
+			return fmt.Errorf("failed to mark issue %s dirty: %w", issueID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project change: %w", err)
+	}
+	return nil
+}
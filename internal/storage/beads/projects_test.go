@@ -0,0 +1,109 @@
+package beads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestProjects(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	for _, p := range []*types.Project{
+		{Name: "web", RepoPath: "/src/web", DefaultBranch: "develop"},
+		{Name: "api", Description: "Backend"},
+	} {
+		if err := store.SaveProject(ctx, p); err != nil {
+			t.Fatalf("SaveProject(%s) failed: %v", p.Name, err)
+		}
+	}
+	if err := store.SaveProject(ctx, &types.Project{Name: "Bad Name"}); err == nil {
+		t.Error("expected an invalid project name to be rejected")
+	}
+
+	var ids []string
+	for _, title := range []string{"A", "B", "C"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	for _, id := range ids[:2] {
+		if err := store.SetIssueProject(ctx, id, "web", "test"); err != nil {
+			t.Fatalf("SetIssueProject failed: %v", err)
+		}
+	}
+	if err := store.SetIssueProject(ctx, ids[2], "api", "test"); err != nil {
+		t.Fatalf("SetIssueProject failed: %v", err)
+	}
+	if err := store.SetIssueProject(ctx, ids[0], "missing", "test"); err == nil {
+		t.Error("expected assigning to an unknown project to fail")
+	}
+
+	t.Run("moving replaces the project label", func(t *testing.T) {
+		if err := store.SetIssueProject(ctx, ids[1], "api", "test"); err != nil {
+			t.Fatalf("SetIssueProject failed: %v", err)
+		}
+		labels, _ := store.GetLabels(ctx, ids[1])
+		if len(labels) != 1 || labels[0] != "project:api" {
+			t.Errorf("expected only project:api, got %v", labels)
+		}
+	})
+
+	t.Run("counts and configuration", func(t *testing.T) {
+		if err := store.CloseIssue(ctx, ids[2], "done", "test"); err != nil {
+			t.Fatalf("CloseIssue failed: %v", err)
+		}
+		projects, err := store.ListProjects(ctx)
+		if err != nil {
+			t.Fatalf("ListProjects failed: %v", err)
+		}
+		if len(projects) != 2 || projects[0].Name != "api" || projects[1].Name != "web" {
+			t.Fatalf("expected api and web, got %+v", projects)
+		}
+		if projects[0].OpenIssues != 1 || projects[0].ClosedIssues != 1 {
+			t.Errorf("expected api to have 1 open and 1 closed, got %+v", projects[0])
+		}
+		web, err := store.GetProject(ctx, "web")
+		if err != nil || web == nil {
+			t.Fatalf("GetProject failed: %v", err)
+		}
+		if web.OpenIssues != 1 || web.RepoPath != "/src/web" || web.DefaultBranch != "develop" {
+			t.Errorf("unexpected web project: %+v", web)
+		}
+		if p, err := store.GetProject(ctx, "missing"); err != nil || p != nil {
+			t.Errorf("expected nil for an unknown project, got %v, %v", p, err)
+		}
+	})
+
+	t.Run("ready work and search are filtered by project", func(t *testing.T) {
+		ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Project: "web"})
+		if err != nil {
+			t.Fatalf("GetReadyWork failed: %v", err)
+		}
+		if len(ready) != 1 || ready[0].ID != ids[0] {
+			t.Errorf("expected only %s ready in web, got %v", ids[0], ready)
+		}
+		found, err := store.SearchIssues(ctx, "", types.IssueFilter{Project: "api"})
+		if err != nil {
+			t.Fatalf("SearchIssues failed: %v", err)
+		}
+		if len(found) != 2 {
+			t.Errorf("expected 2 issues in api, got %d", len(found))
+		}
+	})
+
+	t.Run("unassign removes the project label", func(t *testing.T) {
+		if err := store.SetIssueProject(ctx, ids[0], "", "test"); err != nil {
+			t.Fatalf("SetIssueProject failed: %v", err)
+		}
+		labels, _ := store.GetLabels(ctx, ids[0])
+		if len(labels) != 0 {
+			t.Errorf("expected no labels, got %v", labels)
+		}
+	})
+}
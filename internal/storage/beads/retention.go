@@ -0,0 +1,130 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// sqliteTimestampFormat is the format of CURRENT_TIMESTAMP, which Beads uses
+// for event timestamps. Summaries use it too so they sort among the events.
+const sqliteTimestampFormat = "2006-01-02 15:04:05"
+
+// CompactIssueEvents replaces the events of issues closed more than
+// retentionDays ago that are themselves older than that with one summary
+// comment per batchSize events. Creation and close events are kept. Each
+// batch is its own transaction. Returns the number of events removed.
+func (s *VCStorage) CompactIssueEvents(ctx context.Context, retentionDays, batchSize int) (int, error) {
+	if retentionDays < 1 {
+		return 0, fmt.Errorf("retention days must be at least 1")
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1")
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays).UTC().Format(sqliteTimestampFormat)
+
+	// Timestamps are compared with julianday() because Beads stores
+	// closed_at in Go's format and created_at in CURRENT_TIMESTAMP's
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT e.issue_id
+		FROM events e
+		JOIN issues i ON e.issue_id = i.id
+		WHERE i.status = ? AND julianday(i.closed_at) < julianday(?)
+		  AND julianday(e.created_at) < julianday(?)
+		  AND e.event_type NOT IN (?, ?) AND e.actor != ?
+		ORDER BY e.issue_id
+	`, types.StatusClosed, cutoff, cutoff, types.EventCreated, types.EventClosed, types.EventCompactionActor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query issues with compactable events: %w", err)
+	}
+	var issueIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		issueIDs = append(issueIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return 0, fmt.Errorf("error iterating issues with compactable events: %w", err)
+	}
+	_ = rows.Close()
+
+	total := 0
+	for _, issueID := range issueIDs {
+		for {
+			compacted, err := s.compactIssueEventBatch(ctx, issueID, cutoff, batchSize)
+			if err != nil {
+				return total, err
+			}
+			total += compacted
+			if compacted < batchSize {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+// compactIssueEventBatch replaces up to batchSize of an issue's oldest
+// compactable events with a summary comment, returning how many it replaced
+func (s *VCStorage) compactIssueEventBatch(ctx context.Context, issueID, cutoff string, batchSize int) (int, error) {
+	tx, err := s.beginWriteTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, event_type, actor, comment, created_at
+		FROM events
+		WHERE issue_id = ? AND julianday(created_at) < julianday(?)
+		  AND event_type NOT IN (?, ?) AND actor != ?
+		ORDER BY created_at, id
+		LIMIT ?
+	`, issueID, cutoff, types.EventCreated, types.EventClosed, types.EventCompactionActor, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query events of %s: %w", issueID, err)
+	}
+	compaction := types.NewEventCompaction(issueID)
+	var ids []any
+	for rows.Next() {
+		event := types.Event{IssueID: issueID}
+		if err := rows.Scan(&event.ID, &event.EventType, &event.Actor, &event.Comment, &event.CreatedAt); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to scan event: %w", err)
+		}
+		compaction.Add(&event)
+		ids = append(ids, event.ID)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return 0, fmt.Errorf("error iterating events of %s: %w", issueID, err)
+	}
+	_ = rows.Close()
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	if _, err := tx.ExecContext(ctx, `DELETE FROM events WHERE id IN (`+placeholders+`)`, ids...); err != nil {
+		return 0, fmt.Errorf("failed to delete events of %s: %w", issueID, err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, issueID, types.EventCommented, types.EventCompactionActor, compaction.Summary(),
+		compaction.Last.UTC().Format(sqliteTimestampFormat)); err != nil {
+		return 0, fmt.Errorf("failed to record compaction summary for %s: %w", issueID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit event compaction for %s: %w", issueID, err)
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,116 @@
+package beads
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestCompactIssueEvents(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	longAgo := time.Now().AddDate(0, 0, -200)
+	create := func(title string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask,
+			AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		for _, comment := range []string{
+			"AI Usage (assessment): input=100 tokens, output=20 tokens, duration=1s, model=test",
+			"AI Usage (analysis): input=50 tokens, output=10 tokens, duration=1s, model=test",
+			"Agent output: running tests",
+		} {
+			if err := store.AddComment(ctx, issue.ID, "ai-supervisor", comment); err != nil {
+				t.Fatalf("AddComment failed: %v", err)
+			}
+		}
+		return issue
+	}
+	backdate := func(issueID string, closedAt time.Time) {
+		if _, err := store.db.ExecContext(ctx, `UPDATE events SET created_at = ? WHERE issue_id = ?`,
+			longAgo.UTC().Format(sqliteTimestampFormat), issueID); err != nil {
+			t.Fatalf("failed to backdate events: %v", err)
+		}
+		if _, err := store.db.ExecContext(ctx, `UPDATE issues SET closed_at = ? WHERE id = ? AND status = ?`,
+			closedAt, issueID, types.StatusClosed); err != nil {
+			t.Fatalf("failed to backdate close: %v", err)
+		}
+	}
+
+	old := create("Closed long ago")
+	recent := create("Closed recently")
+	open := create("Still open")
+	for _, issue := range []*types.Issue{old, recent} {
+		if err := store.CloseIssue(ctx, issue.ID, "done", "test"); err != nil {
+			t.Fatalf("CloseIssue failed: %v", err)
+		}
+	}
+	backdate(old.ID, longAgo)
+	backdate(recent.ID, time.Now().AddDate(0, 0, -10))
+	backdate(open.ID, longAgo)
+
+	compacted, err := store.CompactIssueEvents(ctx, 90, 2)
+	if err != nil {
+		t.Fatalf("CompactIssueEvents failed: %v", err)
+	}
+	// Three comments plus the update recorded by CloseIssue
+	if compacted != 4 {
+		t.Errorf("compacted = %d, want 4 (the issue closed long ago)", compacted)
+	}
+
+	events, err := store.GetEvents(ctx, old.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	var summaries []string
+	kept := make(map[types.EventType]int)
+	for _, event := range events {
+		if event.Actor == types.EventCompactionActor {
+			summaries = append(summaries, *event.Comment)
+			continue
+		}
+		kept[event.EventType]++
+	}
+	if len(kept) != 2 || kept[types.EventCreated] != 1 || kept[types.EventClosed] != 1 {
+		t.Errorf("kept events = %v, want only created and closed", kept)
+	}
+	// A batch size of 2 splits the four events into two summaries
+	if len(summaries) != 2 {
+		t.Fatalf("got %d summaries, want 2: %v", len(summaries), summaries)
+	}
+	joined := strings.Join(summaries, "\n")
+	if !strings.Contains(joined, "Compacted 2 events") || !strings.Contains(joined, "AI usage: 2 call(s), input=150 tokens, output=30 tokens") {
+		t.Errorf("unexpected summaries:\n%s", joined)
+	}
+
+	for _, issue := range []*types.Issue{recent, open} {
+		events, err := store.GetEvents(ctx, issue.ID, 0)
+		if err != nil {
+			t.Fatalf("GetEvents failed: %v", err)
+		}
+		for _, event := range events {
+			if event.Actor == types.EventCompactionActor {
+				t.Errorf("%s: events were compacted: %s", issue.Title, *event.Comment)
+			}
+		}
+	}
+
+	// Summaries are not compacted again
+	compacted, err = store.CompactIssueEvents(ctx, 90, 2)
+	if err != nil {
+		t.Fatalf("second CompactIssueEvents failed: %v", err)
+	}
+	if compacted != 0 {
+		t.Errorf("second run compacted %d events, want 0", compacted)
+	}
+
+	if _, err := store.CompactIssueEvents(ctx, 0, 100); err == nil {
+		t.Error("expected error for zero retention days")
+	}
+}
@@ -0,0 +1,127 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// GetEpicRollup aggregates the status, effort, blockers and gate runs of an
+// epic's direct children
+func (s *VCStorage) GetEpicRollup(ctx context.Context, epicID string) (*types.EpicRollup, error) {
+	var exists int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues WHERE id = ?`, epicID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to look up epic %s: %w", epicID, err)
+	}
+	if exists == 0 {
+		return nil, fmt.Errorf("epic %s not found", epicID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT i.id, i.status, i.estimated_minutes
+		FROM dependencies d
+		JOIN issues i ON d.issue_id = i.id
+		WHERE d.depends_on_id = ? AND d.type = ?
+	`, epicID, types.DepParentChild)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query children of %s: %w", epicID, err)
+	}
+	var children []*types.Issue
+	for rows.Next() {
+		var child types.Issue
+		var estimate sql.NullInt64
+		if err := rows.Scan(&child.ID, &child.Status, &estimate); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan child: %w", err)
+		}
+		if estimate.Valid {
+			minutes := int(estimate.Int64)
+			child.EstimatedMinutes = &minutes
+		}
+		children = append(children, &child)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating children: %w", err)
+	}
+	_ = rows.Close()
+	rollup := types.NewEpicRollup(epicID, children)
+
+	// Blockers of the epic itself and of its unclosed children
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT DISTINCT blocker.id
+		FROM dependencies d
+		JOIN issues blocker ON d.depends_on_id = blocker.id
+		WHERE d.type = ?
+		  AND blocker.status != ?
+		  AND (d.issue_id = ? OR d.issue_id IN (
+		      SELECT c.issue_id
+		      FROM dependencies c
+		      JOIN issues child ON c.issue_id = child.id
+		      WHERE c.depends_on_id = ? AND c.type = ? AND child.status != ?
+		  ))
+		ORDER BY blocker.id
+	`, types.DepBlocks, types.StatusClosed, epicID, epicID, types.DepParentChild, types.StatusClosed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blockers of %s: %w", epicID, err)
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan blocker: %w", err)
+		}
+		rollup.OpenBlockers = append(rollup.OpenBlockers, id)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating blockers: %w", err)
+	}
+	_ = rows.Close()
+
+	if len(children) == 0 {
+		return rollup, nil
+	}
+
+	// Durations are summed in Go, where the driver has parsed the timestamps
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT h.started_at, h.completed_at
+		FROM vc_execution_history h
+		JOIN dependencies d ON d.issue_id = h.issue_id
+		WHERE d.depends_on_id = ? AND d.type = ? AND h.completed_at IS NOT NULL
+	`, epicID, types.DepParentChild)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution history of %s: %w", epicID, err)
+	}
+	for rows.Next() {
+		var started, completed sql.NullTime
+		if err := rows.Scan(&started, &completed); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan execution attempt: %w", err)
+		}
+		if started.Valid && completed.Valid {
+			rollup.ActualMinutes += completed.Time.Sub(started.Time).Minutes()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating execution history: %w", err)
+	}
+	_ = rows.Close()
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(CASE WHEN json_extract(e.data, '$.all_passed') = 1 THEN 1 ELSE 0 END), 0)
+		FROM vc_agent_events e
+		JOIN dependencies d ON d.issue_id = e.issue_id
+		WHERE d.depends_on_id = ? AND d.type = ?
+		  AND e.type = ?
+		  AND COALESCE(json_extract(e.data, '$.canceled'), 0) != 1
+	`, epicID, types.DepParentChild, events.EventTypeQualityGatesCompleted).Scan(&rollup.GateRuns, &rollup.GateRunsPassed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count gate runs of %s: %w", epicID, err)
+	}
+	return rollup, nil
+}
@@ -0,0 +1,101 @@
+package beads
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestGetEpicRollup(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	create := func(title string, issueType types.IssueType, estimate *int) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: issueType,
+			AcceptanceCriteria: "Done", EstimatedMinutes: estimate}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	addDep := func(from, to string, depType types.DependencyType) {
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: from, DependsOnID: to, Type: depType}, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	thirty, sixty := 30, 60
+
+	epic := create("Epic", types.TypeEpic, nil)
+	done := create("Done", types.TypeTask, &thirty)
+	working := create("Working", types.TypeTask, &sixty)
+	waiting := create("Waiting", types.TypeTask, nil)
+	blocker := create("Blocker", types.TypeBug, nil)
+	for _, child := range []*types.Issue{done, working, waiting} {
+		addDep(child.ID, epic.ID, types.DepParentChild)
+	}
+	addDep(waiting.ID, blocker.ID, types.DepBlocks)
+	if err := store.CloseIssue(ctx, done.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, working.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	now := time.Now()
+	if err := store.RegisterInstance(ctx, &types.ExecutorInstance{
+		InstanceID: "exec-1", Hostname: "host", PID: 1, Version: "test",
+		StartedAt: now, LastHeartbeat: now, Status: types.ExecutorStatusRunning,
+	}); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	completed := now
+	success := true
+	if err := store.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{
+		IssueID: done.ID, ExecutorInstanceID: "exec-1", AttemptNumber: 1,
+		StartedAt: now.Add(-45 * time.Minute), CompletedAt: &completed, Success: &success,
+	}); err != nil {
+		t.Fatalf("RecordExecutionAttempt failed: %v", err)
+	}
+
+	for _, data := range []map[string]interface{}{
+		{"all_passed": false},
+		{"all_passed": true},
+		{"all_passed": false, "canceled": true},
+	} {
+		if err := store.StoreAgentEvent(ctx, &events.AgentEvent{
+			Type: events.EventTypeQualityGatesCompleted, Timestamp: now, IssueID: done.ID,
+			ExecutorID: "exec-1", Severity: events.SeverityInfo, Message: "gates", Data: data,
+		}); err != nil {
+			t.Fatalf("StoreAgentEvent failed: %v", err)
+		}
+	}
+
+	rollup, err := store.GetEpicRollup(ctx, epic.ID)
+	if err != nil {
+		t.Fatalf("GetEpicRollup failed: %v", err)
+	}
+	if rollup.Children != 3 || rollup.Closed() != 1 || rollup.ByStatus[types.StatusInProgress] != 1 || rollup.ByStatus[types.StatusOpen] != 1 {
+		t.Errorf("unexpected status counts: %+v", rollup)
+	}
+	if rollup.EstimatedMinutes != 90 || rollup.UnestimatedChildren != 1 {
+		t.Errorf("expected 90 estimated minutes and 1 unestimated child, got %d and %d",
+			rollup.EstimatedMinutes, rollup.UnestimatedChildren)
+	}
+	if rollup.ActualMinutes < 44.9 || rollup.ActualMinutes > 45.1 {
+		t.Errorf("expected ~45 actual minutes, got %.2f", rollup.ActualMinutes)
+	}
+	if len(rollup.OpenBlockers) != 1 || rollup.OpenBlockers[0] != blocker.ID {
+		t.Errorf("expected open blocker %s, got %v", blocker.ID, rollup.OpenBlockers)
+	}
+	if rollup.GateRuns != 2 || rollup.GateRunsPassed != 1 {
+		t.Errorf("expected 1/2 gate runs passed (canceled run excluded), got %d/%d", rollup.GateRunsPassed, rollup.GateRuns)
+	}
+
+	if _, err := store.GetEpicRollup(ctx, "vc-missing"); err == nil {
+		t.Error("expected error for missing epic")
+	}
+}
@@ -0,0 +1,164 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// scheduleTemplate is the JSON stored in vc_schedules.template
+type scheduleTemplate struct {
+	Title              string          `json:"title"`
+	Description        string          `json:"description,omitempty"`
+	AcceptanceCriteria string          `json:"acceptance_criteria,omitempty"`
+	IssueType          types.IssueType `json:"issue_type"`
+	Priority           int             `json:"priority"`
+	Labels             []string        `json:"labels,omitempty"`
+	Project            string          `json:"project,omitempty"`
+}
+
+// scheduleColumns are the columns scanSchedule reads, in order
+const scheduleColumns = `name, cron, enabled, allow_overlap, template, next_run_at, last_run_at, last_issue_id, created_at, updated_at`
+
+// scanSchedule reads a schedule from a row of scheduleColumns
+func scanSchedule(row interface{ Scan(...any) error }) (*types.Schedule, error) {
+	var s types.Schedule
+	var template string
+	var lastRunAt sql.NullTime
+	if err := row.Scan(&s.Name, &s.Cron, &s.Enabled, &s.AllowOverlap, &template, &s.NextRunAt,
+		&lastRunAt, &s.LastIssueID, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if lastRunAt.Valid {
+		s.LastRunAt = &lastRunAt.Time
+	}
+	var t scheduleTemplate
+	if err := json.Unmarshal([]byte(template), &t); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule %s: %w", s.Name, err)
+	}
+	s.Title, s.Description, s.AcceptanceCriteria = t.Title, t.Description, t.AcceptanceCriteria
+	s.IssueType, s.Priority, s.Labels, s.Project = t.IssueType, t.Priority, t.Labels, t.Project
+	return &s, nil
+}
+
+// ListSchedules returns all schedules, sorted by name
+func (s *VCStorage) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+scheduleColumns+` FROM vc_schedules ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*types.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// GetSchedule returns a schedule, or nil if it doesn't exist
+func (s *VCStorage) GetSchedule(ctx context.Context, name string) (*types.Schedule, error) {
+	schedule, err := scanSchedule(s.db.QueryRowContext(ctx,
+		`SELECT `+scheduleColumns+` FROM vc_schedules WHERE name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule %s: %w", name, err)
+	}
+	return schedule, nil
+}
+
+// SaveSchedule creates or replaces a schedule, filling in the timestamps.
+// Replacing keeps the record of its last run.
+func (s *VCStorage) SaveSchedule(ctx context.Context, schedule *types.Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	template, err := json.Marshal(scheduleTemplate{
+		Title:              schedule.Title,
+		Description:        schedule.Description,
+		AcceptanceCriteria: schedule.AcceptanceCriteria,
+		IssueType:          schedule.IssueType,
+		Priority:           schedule.Priority,
+		Labels:             schedule.Labels,
+		Project:            schedule.Project,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode schedule %s: %w", schedule.Name, err)
+	}
+
+	now := time.Now()
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO vc_schedules (name, cron, enabled, allow_overlap, template, next_run_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			cron = excluded.cron,
+			enabled = excluded.enabled,
+			allow_overlap = excluded.allow_overlap,
+			template = excluded.template,
+			next_run_at = excluded.next_run_at,
+			updated_at = excluded.updated_at
+		RETURNING created_at
+	`, schedule.Name, schedule.Cron, schedule.Enabled, schedule.AllowOverlap, string(template),
+		schedule.NextRunAt, now, now).Scan(&schedule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save schedule %s: %w", schedule.Name, err)
+	}
+	schedule.UpdatedAt = now
+	return nil
+}
+
+// DeleteSchedule deletes a schedule. Issues it created are left alone.
+func (s *VCStorage) DeleteSchedule(ctx context.Context, name string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM vc_schedules WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule %s: %w", name, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("schedule %s not found", name)
+	}
+	return nil
+}
+
+// ClaimScheduleRun records a run of a schedule due by now and moves its next
+// run to next. It is conditional on the run still being due, so when several
+// executors poll the same schedule only one claims each run.
+func (s *VCStorage) ClaimScheduleRun(ctx context.Context, name string, now, next time.Time) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE vc_schedules
+		SET next_run_at = ?, last_run_at = ?
+		WHERE name = ? AND enabled AND next_run_at <= ?
+	`, next, now, name, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim run of schedule %s: %w", name, err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// SetScheduleLastIssue records the issue a schedule's latest run created
+func (s *VCStorage) SetScheduleLastIssue(ctx context.Context, name, issueID string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE vc_schedules SET last_issue_id = ? WHERE name = ?`, issueID, name)
+	if err != nil {
+		return fmt.Errorf("failed to record issue of schedule %s: %w", name, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("schedule %s not found", name)
+	}
+	return nil
+}
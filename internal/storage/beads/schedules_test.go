@@ -0,0 +1,86 @@
+package beads
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSchedules(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	now := time.Now()
+	schedule := &types.Schedule{
+		Name:               "nightly-deps",
+		Cron:               "0 3 * * *",
+		Enabled:            true,
+		Title:              "Bump dependencies",
+		AcceptanceCriteria: "Dependencies are current",
+		IssueType:          types.TypeTask,
+		Priority:           2,
+		Labels:             []string{"deps"},
+		Project:            "web",
+		NextRunAt:          now.Add(-time.Minute),
+	}
+	if err := store.SaveSchedule(ctx, schedule); err != nil {
+		t.Fatalf("SaveSchedule failed: %v", err)
+	}
+	if err := store.SaveSchedule(ctx, &types.Schedule{Name: "bad", Cron: "@daily", Title: "No criteria", IssueType: types.TypeTask}); err == nil {
+		t.Error("expected a task template without acceptance criteria to be rejected")
+	}
+
+	got, err := store.GetSchedule(ctx, "nightly-deps")
+	if err != nil || got == nil {
+		t.Fatalf("GetSchedule failed: %v", err)
+	}
+	if got.Cron != "0 3 * * *" || !got.Enabled || got.Title != "Bump dependencies" || got.Project != "web" ||
+		len(got.Labels) != 1 || got.LastRunAt != nil || !got.NextRunAt.Equal(schedule.NextRunAt) {
+		t.Errorf("unexpected schedule: %+v", got)
+	}
+	if missing, err := store.GetSchedule(ctx, "missing"); err != nil || missing != nil {
+		t.Errorf("expected nil for an unknown schedule, got %v (err %v)", missing, err)
+	}
+
+	// Only the first claim of a due run succeeds
+	next := now.Add(24 * time.Hour)
+	claimed, err := store.ClaimScheduleRun(ctx, "nightly-deps", now, next)
+	if err != nil || !claimed {
+		t.Fatalf("expected to claim the due run, got %v (err %v)", claimed, err)
+	}
+	if claimed, err := store.ClaimScheduleRun(ctx, "nightly-deps", now, next); err != nil || claimed {
+		t.Errorf("expected the run to be claimed once, got %v (err %v)", claimed, err)
+	}
+	if err := store.SetScheduleLastIssue(ctx, "nightly-deps", "vc-1"); err != nil {
+		t.Fatalf("SetScheduleLastIssue failed: %v", err)
+	}
+
+	// Replacing keeps created_at and the last run; disabled schedules
+	// can't be claimed
+	created := schedule.CreatedAt
+	schedule.Enabled = false
+	schedule.NextRunAt = now.Add(-time.Minute)
+	if err := store.SaveSchedule(ctx, schedule); err != nil {
+		t.Fatalf("SaveSchedule (update) failed: %v", err)
+	}
+	got, _ = store.GetSchedule(ctx, "nightly-deps")
+	if !got.CreatedAt.Equal(created) || got.Enabled || got.LastRunAt == nil || got.LastIssueID != "vc-1" {
+		t.Errorf("unexpected schedule after update: %+v", got)
+	}
+	if claimed, err := store.ClaimScheduleRun(ctx, "nightly-deps", now, next); err != nil || claimed {
+		t.Errorf("expected a disabled schedule not to be claimed, got %v (err %v)", claimed, err)
+	}
+
+	if err := store.DeleteSchedule(ctx, "nightly-deps"); err != nil {
+		t.Fatalf("DeleteSchedule failed: %v", err)
+	}
+	if schedules, _ := store.ListSchedules(ctx); len(schedules) != 0 {
+		t.Errorf("expected no schedules after delete, got %d", len(schedules))
+	}
+	if err := store.DeleteSchedule(ctx, "nightly-deps"); err == nil {
+		t.Error("expected deleting a missing schedule to fail")
+	}
+}
@@ -0,0 +1,111 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// SQLite concurrency settings.
+//
+// Several processes share one database: parallel executor workers, the CLI,
+// vc watch. SQLite allows one writer at a time, so contention shows up as
+// SQLITE_BUSY ("database is locked"). Three things keep it from surfacing:
+//
+//   - WAL mode, so readers never block the writer or each other
+//   - busy_timeout (set by Beads on every pooled connection), so a statement
+//     waits for the write lock instead of failing at once
+//   - BEGIN IMMEDIATE for transactions that write, so the write lock is taken
+//     (waiting on busy_timeout) before anything is read. A deferred
+//     transaction that reads and then writes fails with SQLITE_BUSY without
+//     waiting if another connection wrote in between, and can't be saved by
+//     busy_timeout.
+//
+// What still gets through (a busy_timeout that runs out, Beads' own deferred
+// transactions) is retried with withBusyRetry.
+const (
+	// minBusyTimeout is the busy_timeout below which NewVCStorage warns
+	minBusyTimeout = 5 * time.Second
+
+	// maxBusyRetries and busyRetryBaseDelay bound withBusyRetry: delays
+	// double from the base, with jitter, for up to maxBusyRetries retries
+	maxBusyRetries     = 8
+	busyRetryBaseDelay = 10 * time.Millisecond
+)
+
+// writeTxOptions begins a transaction with BEGIN IMMEDIATE (the driver maps
+// serializable isolation to it). Use it for every transaction that writes.
+var writeTxOptions = &sql.TxOptions{Isolation: sql.LevelSerializable}
+
+// beginWriteTx begins a transaction holding the database write lock
+func (s *VCStorage) beginWriteTx(ctx context.Context) (*sql.Tx, error) {
+	return s.db.BeginTx(ctx, writeTxOptions)
+}
+
+// withBusyRetry runs fn, retrying with jittered exponential backoff for as
+// long as it fails with SQLITE_BUSY. fn must be safe to run again after a
+// failure, e.g. a whole transaction that rolled back.
+func withBusyRetry(ctx context.Context, fn func() error) error {
+	delay := busyRetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !isSQLiteBusyError(err) || attempt == maxBusyRetries {
+			return err
+		}
+
+		// Jitter keeps workers that collided from retrying in lockstep
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay)))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// isSQLiteBusyError checks if an error is a SQLite database locked/busy error
+func isSQLiteBusyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "database is locked") || strings.Contains(errMsg, "SQLITE_BUSY")
+}
+
+// configureConcurrency makes sure a file database is in WAL mode and warns if
+// connections don't wait long enough for the write lock. In-memory databases
+// can't use WAL and are left alone.
+func configureConcurrency(ctx context.Context, db *sql.DB, dbPath string) error {
+	if dbPath == ":memory:" || strings.Contains(dbPath, "mode=memory") {
+		return nil
+	}
+
+	var mode string
+	if err := db.QueryRowContext(ctx, `PRAGMA journal_mode`).Scan(&mode); err != nil {
+		return fmt.Errorf("failed to read journal mode: %w", err)
+	}
+	if !strings.EqualFold(mode, "wal") {
+		// journal_mode=WAL is persistent, so this only happens once per database
+		if err := db.QueryRowContext(ctx, `PRAGMA journal_mode=WAL`).Scan(&mode); err != nil {
+			return fmt.Errorf("failed to enable WAL mode: %w", err)
+		}
+		if !strings.EqualFold(mode, "wal") {
+			return fmt.Errorf("failed to enable WAL mode: journal mode is still %s", mode)
+		}
+	}
+
+	var timeoutMs int64
+	if err := db.QueryRowContext(ctx, `PRAGMA busy_timeout`).Scan(&timeoutMs); err != nil {
+		return fmt.Errorf("failed to read busy_timeout: %w", err)
+	}
+	if timeout := time.Duration(timeoutMs) * time.Millisecond; timeout < minBusyTimeout {
+		fmt.Fprintf(os.Stderr, "Warning: SQLite busy_timeout is %v (want at least %v); concurrent writers may see \"database is locked\"\n",
+			timeout, minBusyTimeout)
+	}
+	return nil
+}
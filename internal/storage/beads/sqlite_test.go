@@ -0,0 +1,152 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestConcurrentWriters simulates parallel executor workers and the CLI
+// writing to one database through separate connections pools (as separate
+// processes would), and checks that none of them sees "database is locked"
+func TestConcurrentWriters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping stress test in short mode")
+	}
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	const numStores = 3
+	const writersPerStore = 4
+	const opsPerWriter = 15
+
+	stores := make([]*VCStorage, numStores)
+	for i := range stores {
+		store, err := NewVCStorage(ctx, dbPath)
+		if err != nil {
+			t.Fatalf("Failed to open store %d: %v", i, err)
+		}
+		defer func() { _ = store.Close() }()
+		stores[i] = store
+	}
+	now := time.Now()
+	for s := range stores {
+		for w := 0; w < writersPerStore; w++ {
+			if err := stores[0].RegisterInstance(ctx, &types.ExecutorInstance{
+				InstanceID: fmt.Sprintf("store%d-worker%d", s, w), Hostname: "host", PID: 1, Version: "test",
+				StartedAt: now, LastHeartbeat: now, Status: types.ExecutorStatusRunning,
+			}); err != nil {
+				t.Fatalf("RegisterInstance failed: %v", err)
+			}
+		}
+	}
+
+	var mode string
+	if err := stores[0].db.QueryRowContext(ctx, `PRAGMA journal_mode`).Scan(&mode); err != nil {
+		t.Fatalf("Failed to read journal mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("expected WAL journal mode, got %q", mode)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, numStores*writersPerStore*opsPerWriter)
+	for s, store := range stores {
+		for w := 0; w < writersPerStore; w++ {
+			wg.Add(1)
+			go func(store *VCStorage, worker string) {
+				defer wg.Done()
+				for op := 0; op < opsPerWriter; op++ {
+					issue := &types.Issue{
+						Title:              fmt.Sprintf("%s op %d", worker, op),
+						Status:             types.StatusOpen,
+						Priority:           2,
+						IssueType:          types.TypeTask,
+						AcceptanceCriteria: "Done",
+					}
+					if err := store.CreateIssue(ctx, issue, worker); err != nil {
+						errs <- fmt.Errorf("%s CreateIssue: %w", worker, err)
+						continue
+					}
+					if err := store.ClaimIssue(ctx, issue.ID, worker); err != nil {
+						errs <- fmt.Errorf("%s ClaimIssue: %w", worker, err)
+					}
+					if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"notes": "updated"}, worker); err != nil {
+						errs <- fmt.Errorf("%s UpdateIssue: %w", worker, err)
+					}
+					if err := store.AddLabel(ctx, issue.ID, "stress", worker); err != nil {
+						errs <- fmt.Errorf("%s AddLabel: %w", worker, err)
+					}
+					if err := store.AddComment(ctx, issue.ID, worker, "progress"); err != nil {
+						errs <- fmt.Errorf("%s AddComment: %w", worker, err)
+					}
+					if err := store.CloseIssue(ctx, issue.ID, "done", worker); err != nil {
+						errs <- fmt.Errorf("%s CloseIssue: %w", worker, err)
+					}
+				}
+			}(store, fmt.Sprintf("store%d-worker%d", s, w))
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	closed, err := stores[0].GetIssuesByLabel(ctx, "stress")
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel failed: %v", err)
+	}
+	if want := numStores * writersPerStore * opsPerWriter; len(closed) != want {
+		t.Errorf("expected %d issues, got %d", want, len(closed))
+	}
+}
+
+func TestWithBusyRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries busy errors until success", func(t *testing.T) {
+		calls := 0
+		err := withBusyRetry(ctx, func() error {
+			calls++
+			if calls < 3 {
+				return errors.New("database is locked (5) (SQLITE_BUSY)")
+			}
+			return nil
+		})
+		if err != nil || calls != 3 {
+			t.Errorf("expected success after 3 calls, got %v after %d", err, calls)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		calls := 0
+		err := withBusyRetry(ctx, func() error {
+			calls++
+			return errors.New("constraint failed")
+		})
+		if err == nil || calls != 1 {
+			t.Errorf("expected one failing call, got %v after %d", err, calls)
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := withBusyRetry(ctx, func() error { return errors.New("SQLITE_BUSY") })
+		if !isSQLiteBusyError(err) {
+			t.Errorf("expected the busy error back, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected retry to stop with the context, took %v", elapsed)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	beadsLib "github.com/steveyegge/beads"
@@ -57,6 +58,21 @@ func TestDatabaseTableMissingDetection(t *testing.T) {
 			"vc_gate_baselines",
 			"vc_review_checkpoints",
 			"vc_mission_plans",
+			"vc_blobs",
+			"vc_attachments",
+			"vc_audit_log",
+			"vc_label_definitions",
+			"vc_projects",
+			"vc_saved_filters",
+			"vc_schedules",
+			"vc_custom_fields",
+			"vc_custom_field_values",
+			"vc_executions",
+			"vc_execution_output",
+			"vc_comment_replies",
+			"vc_comment_edits",
+			"vc_comment_reactions",
+			"vc_idempotency_keys",
 		}
 
 		for _, tableName := range vcTables {
@@ -213,10 +229,13 @@ func TestDatabaseTableMissingDetection(t *testing.T) {
 		// Verify critical indexes exist
 		criticalIndexes := []string{
 			"idx_vc_agent_events_issue",
+			"idx_vc_agent_events_issue_timestamp",
 			"idx_vc_agent_events_executor",
 			"idx_vc_agent_events_timestamp",
 			"idx_vc_execution_state",
 			"idx_vc_execution_executor",
+			"idx_vc_issues_status_priority",
+			"idx_vc_events_issue_created",
 		}
 
 		for _, idxName := range criticalIndexes {
@@ -285,6 +304,36 @@ func TestDatabaseRecoveryAfterTableCreation(t *testing.T) {
 	})
 }
 
+// TestSchemaVersionRecorded tests that opening a database records the schema
+// version without lowering a newer one
+func TestSchemaVersionRecorded(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "version.db")
+
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	if got, _ := store.GetConfig(ctx, SchemaVersionKey); got != strconv.Itoa(SchemaVersion) {
+		t.Errorf("Expected schema version %d, got %q", SchemaVersion, got)
+	}
+	// As if a newer VC had opened it
+	newer := strconv.Itoa(SchemaVersion + 1)
+	if err := store.SetConfig(ctx, SchemaVersionKey, newer); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	_ = store.Close()
+
+	store, err = NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	if got, _ := store.GetConfig(ctx, SchemaVersionKey); got != newer {
+		t.Errorf("Expected newer schema version %s to be kept, got %q", newer, got)
+	}
+}
+
 // TestPartialSchemaHandling tests handling of databases with some tables missing
 func TestPartialSchemaHandling(t *testing.T) {
 	ctx := context.Background()
@@ -0,0 +1,134 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// watchBatchSize bounds how many rows of each kind one poll reads, so a
+// watcher that falls far behind catches up in steps instead of one huge query
+const watchBatchSize = 500
+
+// Watch delivers issue and execution changes matching filter, starting from
+// now, until ctx is done or the store is closed; then the channel is closed.
+//
+// Changes are found by polling the events and vc_execution_history tables
+// every filter.PollInterval. Polling (rather than SQLite update hooks, which
+// only fire for writes made on the same connection) means changes made by
+// other processes - the executor, the CLI, a second executor - are seen too.
+func (s *VCStorage) Watch(ctx context.Context, filter types.WatchFilter) (<-chan types.ChangeEvent, error) {
+	interval := filter.PollInterval
+	if interval <= 0 {
+		interval = types.DefaultWatchPollInterval
+	}
+
+	var lastEvent, lastAttempt int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM events`).Scan(&lastEvent); err != nil {
+		return nil, fmt.Errorf("failed to start watch: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM vc_execution_history`).Scan(&lastAttempt); err != nil {
+		return nil, fmt.Errorf("failed to start watch: %w", err)
+	}
+
+	ch := make(chan types.ChangeEvent, 64)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			changes, err := s.changesSince(ctx, &lastEvent, &lastAttempt)
+			if err != nil {
+				if ctx.Err() != nil || strings.Contains(err.Error(), "database is closed") {
+					return
+				}
+				// Transient (e.g. database busy): try again next tick
+				fmt.Fprintf(os.Stderr, "warning: watch poll failed: %v\n", err)
+				continue
+			}
+			for _, change := range changes {
+				if !filter.Matches(change) {
+					continue
+				}
+				select {
+				case ch <- *change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// changesSince reads events and execution attempts recorded after the given
+// IDs, and advances them
+func (s *VCStorage) changesSince(ctx context.Context, lastEvent, lastAttempt *int64) ([]*types.ChangeEvent, error) {
+	var changes []*types.ChangeEvent
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, issue_id, event_type, actor, created_at
+		FROM events
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`, *lastEvent, watchBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	next := *lastEvent
+	for rows.Next() {
+		change := &types.ChangeEvent{Kind: types.ChangeIssue}
+		if err := rows.Scan(&change.EventID, &change.IssueID, &change.EventType, &change.Actor, &change.Timestamp); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		changes = append(changes, change)
+		next = change.EventID
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+	_ = rows.Close()
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT id, issue_id, started_at
+		FROM vc_execution_history
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`, *lastAttempt, watchBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution history: %w", err)
+	}
+	defer rows.Close()
+	nextAttempt := *lastAttempt
+	for rows.Next() {
+		change := &types.ChangeEvent{Kind: types.ChangeExecution}
+		if err := rows.Scan(&change.EventID, &change.IssueID, &change.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan execution attempt: %w", err)
+		}
+		changes = append(changes, change)
+		nextAttempt = change.EventID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating execution history: %w", err)
+	}
+
+	// Only advance once both reads succeeded, so a failed poll is retried whole
+	*lastEvent = next
+	*lastAttempt = nextAttempt
+	return changes, nil
+}
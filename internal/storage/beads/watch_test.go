@@ -0,0 +1,98 @@
+package beads
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store, cleanup := setupTestStorage(t)
+	defer cleanup()
+
+	newIssue := func(title string) *types.Issue {
+		issue := &types.Issue{
+			Title:              title,
+			Status:             types.StatusOpen,
+			Priority:           2,
+			IssueType:          types.TypeTask,
+			AcceptanceCriteria: "Done",
+		}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+
+	// Changes from before the watch started are not replayed
+	watched := newIssue("Watched")
+	other := newIssue("Other")
+
+	changes, err := store.Watch(ctx, types.WatchFilter{
+		IssueIDs:     []string{watched.ID},
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	if err := store.AddComment(ctx, other.ID, "test", "ignored"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if err := store.AddComment(ctx, watched.ID, "alice", "seen"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if err := store.RegisterInstance(ctx, &types.ExecutorInstance{
+		InstanceID:    "exec-1",
+		Hostname:      "host",
+		PID:           1,
+		Version:       "test",
+		StartedAt:     time.Now(),
+		LastHeartbeat: time.Now(),
+		Status:        types.ExecutorStatusRunning,
+	}); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	attempt := &types.ExecutionAttempt{
+		IssueID:            watched.ID,
+		ExecutorInstanceID: "exec-1",
+		AttemptNumber:      1,
+		StartedAt:          time.Now(),
+	}
+	if err := store.RecordExecutionAttempt(ctx, attempt); err != nil {
+		t.Fatalf("RecordExecutionAttempt failed: %v", err)
+	}
+
+	var got []types.ChangeEvent
+	timeout := time.After(2 * time.Second)
+	for len(got) < 2 {
+		select {
+		case change := <-changes:
+			got = append(got, change)
+		case <-timeout:
+			t.Fatalf("timed out waiting for changes, got %+v", got)
+		}
+	}
+	if got[0].Kind != types.ChangeIssue || got[0].IssueID != watched.ID ||
+		got[0].EventType != types.EventCommented || got[0].Actor != "alice" {
+		t.Errorf("unexpected first change: %+v", got[0])
+	}
+	if got[1].Kind != types.ChangeExecution || got[1].IssueID != watched.ID || got[1].EventID != attempt.ID {
+		t.Errorf("unexpected second change: %+v", got[1])
+	}
+
+	// Canceling the context closes the channel
+	cancel()
+	select {
+	case change, ok := <-changes:
+		if ok {
+			t.Errorf("unexpected change after cancel: %+v", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch channel not closed after cancel")
+	}
+}
@@ -17,6 +17,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,12 +35,27 @@ type VCStorage struct {
 	dbPath           string   // Path to database file
 }
 
-// NewVCStorage creates a VC storage instance using Beads as the underlying storage
+// NewVCStorage creates a VC storage instance using Beads as the underlying storage.
+// If VC_DB_PASSPHRASE is set the database is encrypted with it.
 func NewVCStorage(ctx context.Context, dbPath string) (*VCStorage, error) {
+	return NewEncryptedVCStorage(ctx, dbPath, os.Getenv(PassphraseEnvVar))
+}
+
+// NewEncryptedVCStorage is NewVCStorage for a database encrypted with
+// passphrase. An empty passphrase opens an unencrypted database.
+func NewEncryptedVCStorage(ctx context.Context, dbPath, passphrase string) (*VCStorage, error) {
 	// 1. Open Beads storage (creates core tables: issues, dependencies, labels, etc.)
-	beadsStore, err := beadsLib.NewSQLiteStorage(ctx, dbPath)
+	openPath := dbPath
+	if passphrase != "" && dbPath != ":memory:" && !strings.HasPrefix(dbPath, "file:") {
+		// Beads only creates the directory for plain paths
+		if err := os.MkdirAll(filepath.Dir(dbPath), 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+		openPath = beadsURI(DatabaseURI(absPath(dbPath), passphrase))
+	}
+	beadsStore, err := beadsLib.NewSQLiteStorage(ctx, openPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open Beads storage: %w", err)
+		return nil, fmt.Errorf("failed to open Beads storage: %w", explainOpenError(err, dbPath, passphrase))
 	}
 
 	// 1.5. Initialize issue_prefix config if not already set (required by Beads for ID generation)
@@ -55,6 +73,12 @@ func NewVCStorage(ctx context.Context, dbPath string) (*VCStorage, error) {
 		return nil, fmt.Errorf("beads storage did not provide underlying DB")
 	}
 
+	// 2.5. Make sure concurrent executors and the CLI can share the database
+	if err := configureConcurrency(ctx, db, dbPath); err != nil {
+		beadsStore.Close()
+		return nil, fmt.Errorf("failed to configure SQLite for concurrent access: %w", err)
+	}
+
 	// 3. Create VC extension tables using scoped connection for DDL
 	// Use UnderlyingConn(ctx) for DDL operations as recommended by Beads
 	conn, err := beadsStore.UnderlyingConn(ctx)
@@ -67,6 +91,12 @@ func NewVCStorage(ctx context.Context, dbPath string) (*VCStorage, error) {
 		return nil, fmt.Errorf("failed to create VC extension tables: %w", err)
 	}
 
+	// 4. Record the schema version the tables are now at. This goes through
+	// conn, which in-memory databases (one connection) are still holding.
+	if err := recordSchemaVersion(ctx, conn); err != nil {
+		return nil, err
+	}
+
 	return &VCStorage{
 		Storage: beadsStore,
 		db:      db,
@@ -83,6 +113,23 @@ func (s *VCStorage) Close() error {
 	return s.Storage.Close()
 }
 
+// Path returns the absolute path to the database file. Beads' own Path is
+// the URI it was opened with when the database is encrypted.
+func (s *VCStorage) Path() string {
+	if s.dbPath == ":memory:" || strings.HasPrefix(s.dbPath, "file:") {
+		return s.Storage.Path()
+	}
+	return absPath(s.dbPath)
+}
+
+// absPath is filepath.Abs, or path itself if the working directory is gone
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
 // GetDB returns the underlying database connection for advanced operations.
 // This is primarily used by CLI commands that need direct SQL access.
 func (s *VCStorage) GetDB() interface{} {
@@ -123,7 +170,7 @@ func (s *VCStorage) CreateIssues(ctx context.Context, issues []*types.Issue, act
 	}
 
 	// Delegate to Beads bulk create
-	err := s.Storage.CreateIssues(ctx, beadsIssues, actor)
+	err := withBusyRetry(ctx, func() error { return s.Storage.CreateIssues(ctx, beadsIssues, actor) })
 	if err != nil {
 		return err
 	}
@@ -135,6 +182,9 @@ func (s *VCStorage) CreateIssues(ctx context.Context, issues []*types.Issue, act
 		}
 	}
 
+	for _, issue := range issues {
+		s.recordAudit(ctx, types.AuditEntriesFor(issue.ID, nil, issue, actor))
+	}
 	return nil
 }
 
@@ -164,6 +214,11 @@ func createVCExtensionTables(ctx context.Context, conn *sql.Conn) error {
 		return fmt.Errorf("failed to migrate executor_instances table: %w", err)
 	}
 
+	// Migrate executions table for workspace snapshots
+	if err := migrateExecutionsTable(ctx, conn); err != nil {
+		return fmt.Errorf("failed to migrate executions table: %w", err)
+	}
+
 	// Step 3: Create indexes (now that all columns exist)
 	_, err = conn.ExecContext(ctx, vcExtensionIndexSchema)
 	if err != nil {
@@ -263,7 +318,8 @@ func migrateAgentEventsTable(ctx context.Context, conn *sql.Conn) error {
 	return nil
 }
 
-// migrateExecutionStateTable adds intervention tracking columns to vc_issue_execution_state (vc-165b)
+// migrateExecutionStateTable adds intervention tracking (vc-165b) and claim lease
+// columns to vc_issue_execution_state
 // Uses a scoped connection (*sql.Conn) for DDL operations as recommended by Beads
 // Wraps all operations in a transaction for atomicity (vc-zi68)
 func migrateExecutionStateTable(ctx context.Context, conn *sql.Conn) error {
@@ -324,6 +380,27 @@ func migrateExecutionStateTable(ctx context.Context, conn *sql.Conn) error {
 		}
 	}
 
+	// Check if lease_expires_at column exists (ClaimNextReadyIssue leases)
+	var hasLeaseExpiresAt bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) > 0
+		FROM pragma_table_info('vc_issue_execution_state')
+		WHERE name = 'lease_expires_at'
+	`).Scan(&hasLeaseExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to check for lease_expires_at column: %w", err)
+	}
+
+	if !hasLeaseExpiresAt {
+		// NULL means the claim has no lease and is only released by stale instance cleanup
+		_, err = tx.ExecContext(ctx, `
+			ALTER TABLE vc_issue_execution_state ADD COLUMN lease_expires_at DATETIME
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to add lease_expires_at column: %w", err)
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit migration transaction: %w", err)
@@ -374,6 +451,67 @@ func migrateExecutorInstancesTable(ctx context.Context, conn *sql.Conn) error {
 	return nil
 }
 
+// migrateExecutionsTable adds the workspace snapshot and rollback columns to vc_executions
+// Uses a scoped connection (*sql.Conn) for DDL operations as recommended by Beads
+// Wraps all operations in a transaction for atomicity
+func migrateExecutionsTable(ctx context.Context, conn *sql.Conn) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	for _, column := range []string{"start_commit", "start_dirty_hash", "end_commit", "end_dirty_hash", "revert_commit", "rollback_issue_id"} {
+		var exists bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) > 0
+			FROM pragma_table_info('vc_executions')
+			WHERE name = ?
+		`, column).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check for %s column: %w", column, err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE vc_executions ADD COLUMN `+column+` TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("failed to add %s column: %w", column, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration transaction: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion is the version of the VC extension schema this build creates
+// and migrates to. Bump it whenever vcExtensionTableSchema or a migration
+// changes, so 'vc doctor' can spot a database last opened by a newer VC.
+const SchemaVersion = 3
+
+// SchemaVersionKey is the config key a database's schema version is kept
+// under. Databases created before versioning have none.
+const SchemaVersionKey = "vc_schema_version"
+
+// recordSchemaVersion records SchemaVersion, unless a newer VC has already
+// recorded a later one
+func recordSchemaVersion(ctx context.Context, conn *sql.Conn) error {
+	var value string
+	err := conn.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, SchemaVersionKey).Scan(&value)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if recorded, err := strconv.Atoi(value); err == nil && recorded >= SchemaVersion {
+		return nil
+	}
+	if _, err := conn.ExecContext(ctx, `INSERT OR REPLACE INTO config (key, value) VALUES (?, ?)`,
+		SchemaVersionKey, strconv.Itoa(SchemaVersion)); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return nil
+}
+
 // VC-specific extension schema - TABLE DEFINITIONS ONLY
 // These tables coexist with Beads core tables in the same database
 // Following the IntelliJ/Android Studio extensibility model
@@ -459,6 +597,42 @@ CREATE TABLE IF NOT EXISTS vc_execution_history (
     FOREIGN KEY (executor_instance_id) REFERENCES vc_executor_instances(id) ON DELETE SET NULL
 );
 
+-- Executions: one row per agent run, with its provider, prompt, outcome, timing, cost and commit
+CREATE TABLE IF NOT EXISTS vc_executions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT NOT NULL,
+    executor_instance_id TEXT,                -- No FK: executions outlive stopped instances
+    agent_provider TEXT NOT NULL,
+    prompt_hash TEXT NOT NULL DEFAULT '',    -- Hex SHA-256 of the prompt
+    status TEXT NOT NULL CHECK(status IN ('running', 'succeeded', 'incomplete', 'failed', 'interrupted')),
+    exit_code INTEGER,
+    error TEXT NOT NULL DEFAULT '',
+    started_at DATETIME NOT NULL,
+    completed_at DATETIME,
+    agent_duration_ms INTEGER NOT NULL DEFAULT 0,
+    cost_usd REAL NOT NULL DEFAULT 0,
+    commit_hash TEXT NOT NULL DEFAULT '',
+    start_commit TEXT NOT NULL DEFAULT '',     -- Workspace snapshot when the agent started
+    start_dirty_hash TEXT NOT NULL DEFAULT '',
+    end_commit TEXT NOT NULL DEFAULT '',       -- Workspace snapshot when the agent exited
+    end_dirty_hash TEXT NOT NULL DEFAULT '',
+    revert_commit TEXT NOT NULL DEFAULT '',    -- Commit that rolled back commit_hash
+    rollback_issue_id TEXT NOT NULL DEFAULT '', -- Follow-up issue filed by the rollback
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Execution output (agent output lines, stored as they're written so
+-- 'vc tail' can follow a running agent)
+CREATE TABLE IF NOT EXISTS vc_execution_output (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    execution_id INTEGER NOT NULL,
+    issue_id TEXT NOT NULL,
+    stream TEXT NOT NULL CHECK(stream IN ('stdout', 'stderr')),
+    line TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    FOREIGN KEY (execution_id) REFERENCES vc_executions(id) ON DELETE CASCADE
+);
+
 -- Gate baselines (cache of preflight gate results by commit hash)
 -- vc-198: Pre-flight quality gates to prevent work on broken baseline
 CREATE TABLE IF NOT EXISTS vc_gate_baselines (
@@ -569,6 +743,144 @@ CREATE TABLE IF NOT EXISTS vc_mission_plans (
     approved_at DATETIME,                    -- When approved (NULL if not approved)
     FOREIGN KEY (mission_id) REFERENCES issues(id) ON DELETE CASCADE
 );
+
+-- Blobs: content-addressed storage for large artifacts
+-- One row per distinct content; attachments reference blobs by hash
+CREATE TABLE IF NOT EXISTS vc_blobs (
+    hash TEXT PRIMARY KEY,                   -- Hex SHA-256 of data
+    size INTEGER NOT NULL,
+    data BLOB NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Attachments: diffs, gate logs and agent transcripts attached to issues
+-- (and optionally to a single execution attempt) instead of truncated comments
+CREATE TABLE IF NOT EXISTS vc_attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT NOT NULL,
+    execution_id INTEGER,                    -- vc_execution_history row (NULL if not tied to an attempt)
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK(kind IN ('diff', 'gate_log', 'transcript', 'other')),
+    blob_hash TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (execution_id) REFERENCES vc_execution_history(id) ON DELETE SET NULL,
+    FOREIGN KEY (blob_hash) REFERENCES vc_blobs(hash)
+);
+
+-- Audit log: one row per issue field changed by a create, update or delete
+-- No foreign key on issue_id, so the history of a deleted issue survives it
+CREATE TABLE IF NOT EXISTS vc_audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT NOT NULL,
+    operation TEXT NOT NULL CHECK(operation IN ('create', 'update', 'delete')),
+    field TEXT NOT NULL,
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Label definitions: descriptions and colors for labels used in the labels table
+CREATE TABLE IF NOT EXISTS vc_label_definitions (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    color TEXT NOT NULL DEFAULT '',          -- "#rrggbb", or '' for none
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Projects: per-project configuration; issues join a project via a "project:<name>" label
+CREATE TABLE IF NOT EXISTS vc_projects (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    repo_path TEXT NOT NULL DEFAULT '',
+    default_branch TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Saved filters: named issue queries
+CREATE TABLE IF NOT EXISTS vc_saved_filters (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    predicates TEXT NOT NULL DEFAULT '{}',   -- JSON: query, status, issue_type, priority, assignee, labels, project, custom_fields, limit
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Schedules: recurring issues created from a template on a cron schedule
+CREATE TABLE IF NOT EXISTS vc_schedules (
+    name TEXT PRIMARY KEY,
+    cron TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    allow_overlap BOOLEAN NOT NULL DEFAULT 0,
+    template TEXT NOT NULL DEFAULT '{}',     -- JSON: title, description, acceptance_criteria, issue_type, priority, labels, project
+    next_run_at DATETIME NOT NULL,
+    last_run_at DATETIME,
+    last_issue_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Custom fields: typed field definitions, global (project '') or per project
+CREATE TABLE IF NOT EXISTS vc_custom_fields (
+    project TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    field_type TEXT NOT NULL CHECK(field_type IN ('string', 'number', 'enum')),
+    description TEXT NOT NULL DEFAULT '',
+    options TEXT NOT NULL DEFAULT '[]',      -- JSON array of enum values
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project, name)
+);
+
+-- Custom field values of issues, checked against their definition when set
+CREATE TABLE IF NOT EXISTS vc_custom_field_values (
+    issue_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issue_id, name),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Threaded comments: comments stay rows of the events table, these hold
+-- the thread structure, edit history and reactions
+CREATE TABLE IF NOT EXISTS vc_comment_replies (
+    comment_id INTEGER PRIMARY KEY,           -- The reply's "commented" event
+    parent_id INTEGER NOT NULL,               -- The comment it replies to
+    FOREIGN KEY (comment_id) REFERENCES events(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES events(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS vc_comment_edits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    comment_id INTEGER NOT NULL,
+    previous_body TEXT NOT NULL,
+    body TEXT NOT NULL,                       -- The body as of this edit
+    edited_by TEXT NOT NULL,
+    edited_at DATETIME NOT NULL,
+    FOREIGN KEY (comment_id) REFERENCES events(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS vc_comment_reactions (
+    comment_id INTEGER NOT NULL,
+    actor TEXT NOT NULL,
+    reaction TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (comment_id, actor, reaction),
+    FOREIGN KEY (comment_id) REFERENCES events(id) ON DELETE CASCADE
+);
+
+-- Idempotency keys: supervisor side effects already applied, so a handler
+-- re-run after a crash doesn't apply them twice
+CREATE TABLE IF NOT EXISTS vc_idempotency_keys (
+    key TEXT PRIMARY KEY,
+    result TEXT NOT NULL DEFAULT '',         -- What the effect produced, e.g. a created issue's ID
+    created_at TEXT NOT NULL                  -- UTC, in CURRENT_TIMESTAMP's format
+);
 `
 
 // VC-specific extension schema - INDEX DEFINITIONS
@@ -580,8 +892,10 @@ const vcExtensionIndexSchema = `
 CREATE INDEX IF NOT EXISTS idx_vc_mission_subtype ON vc_mission_state(subtype);
 CREATE INDEX IF NOT EXISTS idx_vc_mission_gates ON vc_mission_state(gates_status);
 
--- Agent events indexes
+-- Agent events indexes. Events of an issue are listed in time order, so
+-- the (issue_id, timestamp) index covers the sort too.
 CREATE INDEX IF NOT EXISTS idx_vc_agent_events_issue ON vc_agent_events(issue_id);
+CREATE INDEX IF NOT EXISTS idx_vc_agent_events_issue_timestamp ON vc_agent_events(issue_id, timestamp);
 CREATE INDEX IF NOT EXISTS idx_vc_agent_events_executor ON vc_agent_events(executor_id);
 CREATE INDEX IF NOT EXISTS idx_vc_agent_events_timestamp ON vc_agent_events(timestamp);
 CREATE INDEX IF NOT EXISTS idx_vc_agent_events_type ON vc_agent_events(type);
@@ -598,6 +912,16 @@ CREATE INDEX IF NOT EXISTS idx_vc_execution_executor ON vc_issue_execution_state
 CREATE INDEX IF NOT EXISTS idx_vc_history_issue ON vc_execution_history(issue_id);
 CREATE INDEX IF NOT EXISTS idx_vc_history_started ON vc_execution_history(started_at);
 
+-- Executions indexes
+CREATE INDEX IF NOT EXISTS idx_vc_executions_issue ON vc_executions(issue_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_vc_executions_started ON vc_executions(started_at);
+CREATE INDEX IF NOT EXISTS idx_vc_executions_status ON vc_executions(status);
+CREATE INDEX IF NOT EXISTS idx_vc_executions_commit ON vc_executions(commit_hash);
+
+-- Execution output indexes
+CREATE INDEX IF NOT EXISTS idx_vc_execution_output_execution ON vc_execution_output(execution_id, id);
+CREATE INDEX IF NOT EXISTS idx_vc_execution_output_issue ON vc_execution_output(issue_id, id);
+
 -- Gate baselines indexes
 CREATE INDEX IF NOT EXISTS idx_vc_gate_baselines_timestamp ON vc_gate_baselines(timestamp);
 CREATE INDEX IF NOT EXISTS idx_vc_gate_baselines_branch ON vc_gate_baselines(branch_name);
@@ -618,15 +942,40 @@ CREATE INDEX IF NOT EXISTS idx_vc_quota_snapshots_window ON vc_quota_snapshots(w
 CREATE INDEX IF NOT EXISTS idx_vc_mission_plans_status ON vc_mission_plans(status);
 CREATE INDEX IF NOT EXISTS idx_vc_mission_plans_updated ON vc_mission_plans(updated_at);
 
+-- Attachments indexes
+CREATE INDEX IF NOT EXISTS idx_vc_attachments_issue ON vc_attachments(issue_id);
+CREATE INDEX IF NOT EXISTS idx_vc_attachments_execution ON vc_attachments(execution_id);
+CREATE INDEX IF NOT EXISTS idx_vc_attachments_blob ON vc_attachments(blob_hash);
+
+-- Audit log indexes
+CREATE INDEX IF NOT EXISTS idx_vc_audit_log_issue ON vc_audit_log(issue_id, id);
+
 -- Quota operations indexes (vc-7e21)
 CREATE INDEX IF NOT EXISTS idx_vc_quota_operations_timestamp ON vc_quota_operations(timestamp);
 CREATE INDEX IF NOT EXISTS idx_vc_quota_operations_issue ON vc_quota_operations(issue_id);
 CREATE INDEX IF NOT EXISTS idx_vc_quota_operations_type ON vc_quota_operations(operation_type);
 CREATE INDEX IF NOT EXISTS idx_vc_quota_operations_model ON vc_quota_operations(model);
 
+-- Custom field values index, for filtering issues by field value
+CREATE INDEX IF NOT EXISTS idx_vc_custom_field_values_name ON vc_custom_field_values(name, value);
+
+-- Threaded comments indexes
+CREATE INDEX IF NOT EXISTS idx_vc_comment_replies_parent ON vc_comment_replies(parent_id);
+CREATE INDEX IF NOT EXISTS idx_vc_comment_edits_comment ON vc_comment_edits(comment_id, id);
+
+-- Idempotency keys index, for pruning expired keys
+CREATE INDEX IF NOT EXISTS idx_vc_idempotency_keys_created ON vc_idempotency_keys(created_at);
+
 -- Health metrics indexes (vc-2px0)
 CREATE INDEX IF NOT EXISTS idx_health_metrics_name_time ON health_metrics(metric_name, timestamp);
 CREATE INDEX IF NOT EXISTS idx_health_metrics_timestamp ON health_metrics(timestamp);
+
+-- Indexes on Beads tables for VC's hot queries. Ready work filters issues by
+-- status and sorts them by priority; issue history lists an issue's events
+-- newest first. Labels need none: their (issue_id, label) primary key
+-- already serves lookups by issue.
+CREATE INDEX IF NOT EXISTS idx_vc_issues_status_priority ON issues(status, priority);
+CREATE INDEX IF NOT EXISTS idx_vc_events_issue_created ON events(issue_id, created_at);
 `
 
 // ======================================================================
@@ -929,22 +1278,37 @@ func vcDependencyToBeads(vd *types.Dependency) *beadsLib.Dependency {
 // TRANSACTION WRAPPER (vc-3hjg)
 // ======================================================================
 
-// VCTransaction wraps beadsLib.Transaction to accept VC types instead of Beads types.
+// VCTransaction wraps a backend transaction to accept VC types instead of Beads types.
 // This provides a type-safe interface for VC code to use transactions without
 // needing to know about Beads internal types.
 type VCTransaction struct {
-	tx beadsLib.Transaction
+	backend TxBackend
+}
+
+// TxBackend is the transaction a VCTransaction runs its operations in.
+// The SQLite backend adapts Beads transactions; other storage backends
+// (e.g. internal/storage/memory) provide their own.
+type TxBackend interface {
+	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error
+	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
+	RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	RemoveLabel(ctx context.Context, issueID, label, actor string) error
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	CloseIssue(ctx context.Context, id string, reason string, actor string) error
+	DeleteIssue(ctx context.Context, id string) error
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+}
+
+// NewVCTransaction wraps a backend transaction for use by RunInVCTransaction callers
+func NewVCTransaction(backend TxBackend) *VCTransaction {
+	return &VCTransaction{backend: backend}
 }
 
 // CreateIssue creates an issue within the transaction using VC types
 func (t *VCTransaction) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
-	beadsIssue := vcIssueToBeads(issue)
-	err := t.tx.CreateIssue(ctx, beadsIssue, actor)
-	if err == nil && beadsIssue.ID != "" {
-		// Copy generated ID back to VC issue
-		issue.ID = beadsIssue.ID
-	}
-	return err
+	return t.backend.CreateIssue(ctx, issue, actor)
 }
 
 // CreateIssues creates multiple issues atomically within the transaction using VC types.
@@ -959,7 +1323,88 @@ func (t *VCTransaction) CreateIssues(ctx context.Context, issues []*types.Issue,
 	if len(issues) == 0 {
 		return nil
 	}
+	return t.backend.CreateIssues(ctx, issues, actor)
+}
+
+// AddDependency adds a dependency within the transaction using VC types
+func (t *VCTransaction) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	return t.backend.AddDependency(ctx, dep, actor)
+}
+
+// AddLabel adds a label to an issue within the transaction
+func (t *VCTransaction) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	return t.backend.AddLabel(ctx, issueID, label, actor)
+}
+
+// RemoveLabel removes a label from an issue within the transaction
+func (t *VCTransaction) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	return t.backend.RemoveLabel(ctx, issueID, label, actor)
+}
+
+// UpdateIssue updates an issue within the transaction
+func (t *VCTransaction) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	return t.backend.UpdateIssue(ctx, id, updates, actor)
+}
+
+// CloseIssue closes an issue within the transaction
+func (t *VCTransaction) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	return t.backend.CloseIssue(ctx, id, reason, actor)
+}
+
+// DeleteIssue deletes an issue within the transaction
+func (t *VCTransaction) DeleteIssue(ctx context.Context, id string) error {
+	return t.backend.DeleteIssue(ctx, id)
+}
+
+// GetIssue retrieves an issue within the transaction (for read-your-writes)
+func (t *VCTransaction) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	return t.backend.GetIssue(ctx, id)
+}
+
+// RemoveDependency removes a dependency within the transaction
+func (t *VCTransaction) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	return t.backend.RemoveDependency(ctx, issueID, dependsOnID, actor)
+}
+
+// beadsTx adapts a Beads transaction to TxBackend, converting between VC and Beads types.
+// Audit entries for its mutations are collected in audit and written once the
+// transaction commits.
+type beadsTx struct {
+	tx    beadsLib.Transaction
+	audit []*types.AuditEntry
+}
+
+func (t *beadsTx) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	beadsIssue := vcIssueToBeads(issue)
+	err := t.tx.CreateIssue(ctx, beadsIssue, actor)
+	if err == nil && beadsIssue.ID != "" {
+		// Copy generated ID back to VC issue
+		issue.ID = beadsIssue.ID
+	}
+	if err == nil {
+		t.audit = append(t.audit, types.AuditEntriesFor(issue.ID, nil, issue, actor)...)
+	}
+	return err
+}
+
+// auditMutation runs a mutation of issue id and collects audit entries for it
+func (t *beadsTx) auditMutation(ctx context.Context, id, actor string, mutate func() error) error {
+	before, err := t.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := mutate(); err != nil {
+		return err
+	}
+	after, err := t.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+	t.audit = append(t.audit, types.AuditEntriesFor(id, before, after, actor)...)
+	return nil
+}
 
+func (t *beadsTx) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
 	// Convert VC issues to Beads issues
 	beadsIssues := make([]*beadsLib.Issue, len(issues))
 	for i, issue := range issues {
@@ -967,8 +1412,7 @@ func (t *VCTransaction) CreateIssues(ctx context.Context, issues []*types.Issue,
 	}
 
 	// Bulk create in transaction
-	err := t.tx.CreateIssues(ctx, beadsIssues, actor)
-	if err != nil {
+	if err := t.tx.CreateIssues(ctx, beadsIssues, actor); err != nil {
 		return err
 	}
 
@@ -977,44 +1421,54 @@ func (t *VCTransaction) CreateIssues(ctx context.Context, issues []*types.Issue,
 		if beadsIssue.ID != "" {
 			issues[i].ID = beadsIssue.ID
 		}
+		t.audit = append(t.audit, types.AuditEntriesFor(issues[i].ID, nil, issues[i], actor)...)
 	}
-
 	return nil
 }
 
-// AddDependency adds a dependency within the transaction using VC types
-func (t *VCTransaction) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
-	beadsDep := vcDependencyToBeads(dep)
-	return t.tx.AddDependency(ctx, beadsDep, actor)
+func (t *beadsTx) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	return t.tx.AddDependency(ctx, vcDependencyToBeads(dep), actor)
 }
 
-// AddLabel adds a label to an issue within the transaction
-func (t *VCTransaction) AddLabel(ctx context.Context, issueID, label, actor string) error {
+func (t *beadsTx) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	return t.tx.RemoveDependency(ctx, issueID, dependsOnID, actor)
+}
+
+func (t *beadsTx) AddLabel(ctx context.Context, issueID, label, actor string) error {
 	return t.tx.AddLabel(ctx, issueID, label, actor)
 }
 
-// RemoveLabel removes a label from an issue within the transaction
-func (t *VCTransaction) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+func (t *beadsTx) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
 	return t.tx.RemoveLabel(ctx, issueID, label, actor)
 }
 
-// UpdateIssue updates an issue within the transaction
-func (t *VCTransaction) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
-	return t.tx.UpdateIssue(ctx, id, updates, actor)
+func (t *beadsTx) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	return t.auditMutation(ctx, id, actor, func() error {
+		return t.tx.UpdateIssue(ctx, id, updates, actor)
+	})
 }
 
-// CloseIssue closes an issue within the transaction
-func (t *VCTransaction) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
-	return t.tx.CloseIssue(ctx, id, reason, actor)
+func (t *beadsTx) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	return t.auditMutation(ctx, id, actor, func() error {
+		return t.tx.CloseIssue(ctx, id, reason, actor)
+	})
 }
 
-// DeleteIssue deletes an issue within the transaction
-func (t *VCTransaction) DeleteIssue(ctx context.Context, id string) error {
-	return t.tx.DeleteIssue(ctx, id)
+func (t *beadsTx) DeleteIssue(ctx context.Context, id string) error {
+	before, err := t.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := t.tx.DeleteIssue(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		t.audit = append(t.audit, types.AuditEntriesFor(id, before, nil, "")...)
+	}
+	return nil
 }
 
-// GetIssue retrieves an issue within the transaction (for read-your-writes)
-func (t *VCTransaction) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+func (t *beadsTx) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
 	beadsIssue, err := t.tx.GetIssue(ctx, id)
 	if err != nil {
 		return nil, err
@@ -1022,11 +1476,6 @@ func (t *VCTransaction) GetIssue(ctx context.Context, id string) (*types.Issue,
 	return beadsIssueToVC(beadsIssue), nil
 }
 
-// RemoveDependency removes a dependency within the transaction
-func (t *VCTransaction) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
-	return t.tx.RemoveDependency(ctx, issueID, dependsOnID, actor)
-}
-
 // RunInVCTransaction executes a function within a database transaction using VC types.
 // This wraps Beads' RunInTransaction to provide a VC-type-aware interface.
 //
@@ -1035,10 +1484,16 @@ func (t *VCTransaction) RemoveDependency(ctx context.Context, issueID, dependsOn
 //
 // vc-3hjg: Added for atomic plan approval workflow
 func (s *VCStorage) RunInVCTransaction(ctx context.Context, fn func(tx *VCTransaction) error) error {
-	return s.RunInTransaction(ctx, func(beadsTx beadsLib.Transaction) error {
-		vcTx := &VCTransaction{tx: beadsTx}
-		return fn(vcTx)
+	var backend *beadsTx
+	err := s.RunInTransaction(ctx, func(tx beadsLib.Transaction) error {
+		backend = &beadsTx{tx: tx}
+		return fn(NewVCTransaction(backend))
 	})
+	if err != nil {
+		return err
+	}
+	s.recordAudit(ctx, backend.audit)
+	return nil
 }
 
 // ======================================================================
@@ -1295,61 +1750,6 @@ func (s *VCStorage) GetRecentQuotaSnapshots(ctx context.Context, since time.Time
 	return snapshots, rows.Err()
 }
 
-// StoreQuotaOperation stores a quota operation for cost attribution
-func (s *VCStorage) StoreQuotaOperation(ctx context.Context, op *QuotaOperation) error {
-	// Convert empty issue_id to NULL
-	var issueID interface{}
-	if op.IssueID == "" {
-		issueID = nil
-	} else {
-		issueID = op.IssueID
-	}
-
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO vc_quota_operations (
-			id, timestamp, issue_id, operation_type, model,
-			input_tokens, output_tokens, cost, duration_ms
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, op.ID, op.Timestamp, issueID, op.OperationType, op.Model,
-		op.InputTokens, op.OutputTokens, op.Cost, op.DurationMs)
-
-	if err != nil {
-		return fmt.Errorf("failed to store quota operation: %w", err)
-	}
-	return nil
-}
-
-// GetQuotaOperationsByIssue retrieves all quota operations for a specific issue
-func (s *VCStorage) GetQuotaOperationsByIssue(ctx context.Context, issueID string) ([]*QuotaOperation, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, timestamp, issue_id, operation_type, model,
-		       input_tokens, output_tokens, cost, duration_ms
-		FROM vc_quota_operations
-		WHERE issue_id = ?
-		ORDER BY timestamp
-	`, issueID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query quota operations: %w", err)
-	}
-	defer rows.Close()
-
-	var operations []*QuotaOperation
-	for rows.Next() {
-		var op QuotaOperation
-		var issueIDNull sql.NullString
-		if err := rows.Scan(&op.ID, &op.Timestamp, &issueIDNull, &op.OperationType, &op.Model,
-			&op.InputTokens, &op.OutputTokens, &op.Cost, &op.DurationMs); err != nil {
-			return nil, fmt.Errorf("failed to scan quota operation: %w", err)
-		}
-		if issueIDNull.Valid {
-			op.IssueID = issueIDNull.String
-		}
-		operations = append(operations, &op)
-	}
-
-	return operations, rows.Err()
-}
-
 // CleanupOldQuotaSnapshots removes snapshots older than the given age
 func (s *VCStorage) CleanupOldQuotaSnapshots(ctx context.Context, maxAge time.Duration) (int, error) {
 	cutoff := time.Now().Add(-maxAge)
@@ -1405,19 +1805,6 @@ type QuotaSnapshot struct {
 	IssuesWorked     int
 }
 
-// QuotaOperation represents a quota operation for cost attribution (vc-7e21)
-type QuotaOperation struct {
-	ID            string
-	Timestamp     time.Time
-	IssueID       string
-	OperationType string
-	Model         string
-	InputTokens   int64
-	OutputTokens  int64
-	Cost          float64
-	DurationMs    int64
-}
-
 // ======================================================================
 // HEALTH METRICS METHODS (vc-2px0)
 // ======================================================================
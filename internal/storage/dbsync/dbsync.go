@@ -0,0 +1,447 @@
+// Package dbsync exchanges issues and events between two VC databases, such
+// as a laptop-local database and a shared team database.
+//
+// A sync is a three-way merge. Each side's issue graph is compared with the
+// base: the graph as both sides left it after their last sync. For every
+// issue field, label and dependency, a change on one side since the base is
+// copied to the other. When both sides changed the same field to different
+// values, the last writer wins: the side whose issue was updated later,
+// with ties broken by comparing the values, so the outcome doesn't depend
+// on which database is local. Without a base (the first sync) every
+// difference is a conflict and labels and dependencies are unioned.
+//
+// Issues that exist on one side only are copied with their events and
+// execution history. Events of issues on both sides are exchanged, except
+// the ones a sync recorded itself (types.SyncActor). Deleted issues are not
+// propagated, and neither is executor state such as claims: that belongs
+// to the executors working on each database.
+package dbsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Sync merges local and remote so both hold the same issue graph, given
+// base, the graph after their last sync (nil if they have never synced). It
+// returns what it changed and the new base to pass to the next sync.
+func Sync(ctx context.Context, local, remote storage.Storage, base *types.ExportGraph) (*types.SyncStats, *types.ExportGraph, error) {
+	stats := &types.SyncStats{}
+
+	localGraph, err := snapshot(ctx, local)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read local database: %w", err)
+	}
+	remoteGraph, err := snapshot(ctx, remote)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read remote database: %w", err)
+	}
+	if base == nil {
+		base = &types.ExportGraph{}
+	}
+
+	sides := []*side{
+		{name: types.SyncLocal, store: local, graph: localGraph, stats: &stats.Local},
+		{name: types.SyncRemote, store: remote, graph: remoteGraph, stats: &stats.Remote},
+	}
+	if err := copyMissingIssues(ctx, sides[0], sides[1]); err != nil {
+		return nil, nil, err
+	}
+	if err := copyMissingIssues(ctx, sides[1], sides[0]); err != nil {
+		return nil, nil, err
+	}
+	if err := mergeFields(ctx, sides[0], sides[1], base, stats); err != nil {
+		return nil, nil, err
+	}
+	if err := mergeLabels(ctx, sides[0], sides[1], base); err != nil {
+		return nil, nil, err
+	}
+	if err := mergeDependencies(ctx, sides[0], sides[1], base, stats); err != nil {
+		return nil, nil, err
+	}
+	if err := exchangeEvents(ctx, sides[0], sides[1]); err != nil {
+		return nil, nil, err
+	}
+
+	newBase, err := snapshot(ctx, local)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read local database after sync: %w", err)
+	}
+	// Merging only looks at issues, labels and dependencies
+	newBase.Events, newBase.Executions = nil, nil
+	return stats, newBase, nil
+}
+
+// side is one of the two databases being synced
+type side struct {
+	name  types.SyncSide
+	store storage.Storage
+	graph *types.ExportGraph // As read before the sync, plus copied issues
+	stats *types.SyncSideStats
+	// copied are the issues this sync created on this side
+	copied map[string]bool
+}
+
+// snapshot reads store's full issue graph
+func snapshot(ctx context.Context, store storage.Storage) (*types.ExportGraph, error) {
+	var buf bytes.Buffer
+	if err := store.Export(ctx, &buf); err != nil {
+		return nil, err
+	}
+	return export.Read(&buf)
+}
+
+// copyMissingIssues creates the issues that only from has on to, with their
+// labels, events and execution history. Dependencies are left to
+// mergeDependencies, once every issue exists on both sides.
+func copyMissingIssues(ctx context.Context, from, to *side) error {
+	existing := issueIndex(to.graph)
+	missing := &types.ExportGraph{Header: from.graph.Header}
+	ids := make(map[string]bool)
+	for _, issue := range from.graph.Issues {
+		if existing[issue.ID] == nil {
+			missing.Issues = append(missing.Issues, issue)
+			ids[issue.ID] = true
+		}
+	}
+	if len(missing.Issues) == 0 {
+		return nil
+	}
+	for _, label := range from.graph.Labels {
+		if ids[label.IssueID] {
+			missing.Labels = append(missing.Labels, label)
+		}
+	}
+	for _, event := range from.graph.Events {
+		if ids[event.IssueID] {
+			missing.Events = append(missing.Events, event)
+		}
+	}
+	for _, attempt := range from.graph.Executions {
+		if ids[attempt.IssueID] {
+			missing.Executions = append(missing.Executions, attempt)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, missing); err != nil {
+		return err
+	}
+	importStats, err := to.store.Import(ctx, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to copy issues to %s database: %w", to.name, err)
+	}
+	to.stats.IssuesCreated += importStats.Issues
+	to.copied = ids
+
+	// Later steps compare against what each side holds now
+	to.graph.Issues = append(to.graph.Issues, missing.Issues...)
+	to.graph.Labels = append(to.graph.Labels, missing.Labels...)
+	return nil
+}
+
+// syncedField is an issue field merged by Sync
+type syncedField struct {
+	name  string
+	get   func(*types.Issue) string
+	value func(*types.Issue) interface{} // UpdateIssue value
+}
+
+// syncedFields are the issue fields Sync merges, in the order it applies them
+var syncedFields = []syncedField{
+	{"title", func(i *types.Issue) string { return i.Title }, func(i *types.Issue) interface{} { return i.Title }},
+	{"description", func(i *types.Issue) string { return i.Description }, func(i *types.Issue) interface{} { return i.Description }},
+	{"design", func(i *types.Issue) string { return i.Design }, func(i *types.Issue) interface{} { return i.Design }},
+	{"acceptance_criteria", func(i *types.Issue) string { return i.AcceptanceCriteria }, func(i *types.Issue) interface{} { return i.AcceptanceCriteria }},
+	{"notes", func(i *types.Issue) string { return i.Notes }, func(i *types.Issue) interface{} { return i.Notes }},
+	{"status", func(i *types.Issue) string { return string(i.Status) }, func(i *types.Issue) interface{} { return string(i.Status) }},
+	{"priority", func(i *types.Issue) string { return strconv.Itoa(i.Priority) }, func(i *types.Issue) interface{} { return i.Priority }},
+	{"issue_type", func(i *types.Issue) string { return string(i.IssueType) }, func(i *types.Issue) interface{} { return string(i.IssueType) }},
+	{"assignee", func(i *types.Issue) string { return i.Assignee }, func(i *types.Issue) interface{} { return i.Assignee }},
+	{"estimated_minutes", func(i *types.Issue) string {
+		if i.EstimatedMinutes == nil {
+			return ""
+		}
+		return strconv.Itoa(*i.EstimatedMinutes)
+	}, func(i *types.Issue) interface{} {
+		if i.EstimatedMinutes == nil {
+			return nil
+		}
+		return *i.EstimatedMinutes
+	}},
+}
+
+// mergeFields brings every issue's fields to the merged value on both sides
+func mergeFields(ctx context.Context, local, remote *side, base *types.ExportGraph, stats *types.SyncStats) error {
+	remoteIssues := issueIndex(remote.graph)
+	baseIssues := issueIndex(base)
+	for _, l := range local.graph.Issues {
+		r := remoteIssues[l.ID]
+		b := baseIssues[l.ID]
+		localUpdates := make(map[string]interface{})
+		remoteUpdates := make(map[string]interface{})
+		for _, field := range syncedFields {
+			lv, rv := field.get(l), field.get(r)
+			if lv == rv {
+				continue
+			}
+			winner := types.SyncLocal
+			switch {
+			case b != nil && field.get(b) == lv:
+				winner = types.SyncRemote
+			case b != nil && field.get(b) == rv:
+			default:
+				if r.UpdatedAt.After(l.UpdatedAt) || (r.UpdatedAt.Equal(l.UpdatedAt) && rv > lv) {
+					winner = types.SyncRemote
+				}
+				stats.Conflicts = append(stats.Conflicts, types.SyncConflict{
+					IssueID: l.ID, Field: field.name, Local: lv, Remote: rv, Winner: winner,
+				})
+			}
+			if winner == types.SyncLocal {
+				remoteUpdates[field.name] = field.value(l)
+			} else {
+				localUpdates[field.name] = field.value(r)
+			}
+		}
+		if err := applyUpdates(ctx, local, l.ID, localUpdates); err != nil {
+			return err
+		}
+		if err := applyUpdates(ctx, remote, l.ID, remoteUpdates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyUpdates(ctx context.Context, s *side, id string, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := s.store.UpdateIssue(ctx, id, updates, types.SyncActor); err != nil {
+		return fmt.Errorf("failed to update %s in %s database: %w", id, s.name, err)
+	}
+	s.stats.FieldsUpdated += len(updates)
+	return nil
+}
+
+// mergeSets returns the three-way merge of the keys of local and remote
+// given base: a key both sides have is kept, and a key only one side has is
+// kept if that side added it (base lacks it) and dropped if the other side
+// removed it (base has it).
+func mergeSets(local, remote, base map[string]bool) map[string]bool {
+	merged := make(map[string]bool)
+	for key := range local {
+		if remote[key] || !base[key] {
+			merged[key] = true
+		}
+	}
+	for key := range remote {
+		if !local[key] && !base[key] {
+			merged[key] = true
+		}
+	}
+	return merged
+}
+
+// labelKey identifies a label on an issue
+func labelKey(issueID, label string) string {
+	return issueID + "\x00" + label
+}
+
+func labelSet(graph *types.ExportGraph) map[string]bool {
+	set := make(map[string]bool, len(graph.Labels))
+	for _, label := range graph.Labels {
+		set[labelKey(label.IssueID, label.Label)] = true
+	}
+	return set
+}
+
+// mergeLabels brings both sides' labels to their three-way merge
+func mergeLabels(ctx context.Context, local, remote *side, base *types.ExportGraph) error {
+	localSet, remoteSet := labelSet(local.graph), labelSet(remote.graph)
+	merged := mergeSets(localSet, remoteSet, labelSet(base))
+	for _, s := range []struct {
+		side *side
+		set  map[string]bool
+	}{{local, localSet}, {remote, remoteSet}} {
+		for _, key := range sortedDifference(merged, s.set) {
+			issueID, label := splitKey(key)
+			if err := s.side.store.AddLabel(ctx, issueID, label, types.SyncActor); err != nil {
+				return fmt.Errorf("failed to add label %q to %s in %s database: %w", label, issueID, s.side.name, err)
+			}
+			s.side.stats.LabelsAdded++
+		}
+		for _, key := range sortedDifference(s.set, merged) {
+			issueID, label := splitKey(key)
+			if err := s.side.store.RemoveLabel(ctx, issueID, label, types.SyncActor); err != nil {
+				return fmt.Errorf("failed to remove label %q from %s in %s database: %w", label, issueID, s.side.name, err)
+			}
+			s.side.stats.LabelsRemoved++
+		}
+	}
+	return nil
+}
+
+// dependencyIndex maps "issue\x00depends_on" to each dependency of graph
+func dependencyIndex(graph *types.ExportGraph) map[string]*types.Dependency {
+	index := make(map[string]*types.Dependency, len(graph.Dependencies))
+	for _, dep := range graph.Dependencies {
+		index[labelKey(dep.IssueID, dep.DependsOnID)] = dep
+	}
+	return index
+}
+
+func keySet(index map[string]*types.Dependency) map[string]bool {
+	set := make(map[string]bool, len(index))
+	for key := range index {
+		set[key] = true
+	}
+	return set
+}
+
+// mergeDependencies brings both sides' dependencies to their three-way
+// merge. A dependency the other side rejects, for example because it would
+// close a cycle, is reported in stats.Skipped and left for the next sync.
+func mergeDependencies(ctx context.Context, local, remote *side, base *types.ExportGraph, stats *types.SyncStats) error {
+	localDeps, remoteDeps := dependencyIndex(local.graph), dependencyIndex(remote.graph)
+	localSet, remoteSet := keySet(localDeps), keySet(remoteDeps)
+	merged := mergeSets(localSet, remoteSet, keySet(dependencyIndex(base)))
+
+	// Removals first, so an addition can't be rejected as a cycle that the
+	// merged graph doesn't have
+	for _, s := range []struct {
+		side *side
+		set  map[string]bool
+	}{{local, localSet}, {remote, remoteSet}} {
+		for _, key := range sortedDifference(s.set, merged) {
+			issueID, dependsOnID := splitKey(key)
+			if err := s.side.store.RemoveDependency(ctx, issueID, dependsOnID, types.SyncActor); err != nil {
+				return fmt.Errorf("failed to remove dependency %s -> %s in %s database: %w", issueID, dependsOnID, s.side.name, err)
+			}
+			s.side.stats.DependenciesRemoved++
+		}
+	}
+	for _, s := range []struct {
+		side  *side
+		set   map[string]bool
+		other map[string]*types.Dependency
+	}{{local, localSet, remoteDeps}, {remote, remoteSet, localDeps}} {
+		for _, key := range sortedDifference(merged, s.set) {
+			dep := *s.other[key]
+			if err := s.side.store.AddDependency(ctx, &dep, types.SyncActor); err != nil {
+				stats.Skipped = append(stats.Skipped, fmt.Sprintf("dependency %s -> %s in %s database: %v",
+					dep.IssueID, dep.DependsOnID, s.side.name, err))
+				continue
+			}
+			s.side.stats.DependenciesAdded++
+		}
+	}
+	return nil
+}
+
+// exchangeEvents copies each side's events of shared issues to the other
+func exchangeEvents(ctx context.Context, local, remote *side) error {
+	for _, dir := range []struct{ from, to *side }{{local, remote}, {remote, local}} {
+		var events []*types.Event
+		for _, event := range dir.from.graph.Events {
+			// Events of copied issues went along with them
+			if event.Actor == types.SyncActor || local.copied[event.IssueID] || remote.copied[event.IssueID] {
+				continue
+			}
+			events = append(events, event)
+		}
+		if len(events) == 0 {
+			continue
+		}
+		added, err := dir.to.store.ImportEvents(ctx, events)
+		if err != nil {
+			return fmt.Errorf("failed to copy events to %s database: %w", dir.to.name, err)
+		}
+		dir.to.stats.EventsCopied += added
+	}
+	return nil
+}
+
+func issueIndex(graph *types.ExportGraph) map[string]*types.Issue {
+	index := make(map[string]*types.Issue, len(graph.Issues))
+	for _, issue := range graph.Issues {
+		index[issue.ID] = issue
+	}
+	return index
+}
+
+// sortedDifference returns the keys of a that b lacks, sorted so changes
+// are applied in a stable order
+func sortedDifference(a, b map[string]bool) []string {
+	var keys []string
+	for key := range a {
+		if !b[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func splitKey(key string) (string, string) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == 0 {
+			return key[:i], key[i+1:]
+		}
+	}
+	return key, ""
+}
+
+// LoadBase reads the base a previous sync saved at path, or returns nil if
+// there is none
+func LoadBase(path string) (*types.ExportGraph, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sync base: %w", err)
+	}
+	defer f.Close()
+	base, err := export.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync base %s: %w", path, err)
+	}
+	return base, nil
+}
+
+// SaveBase writes base to path for the next sync, replacing the previous
+// base only once the new one is complete
+func SaveBase(path string, base *types.ExportGraph) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create sync state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write sync base: %w", err)
+	}
+	if err := export.Write(f, base); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write sync base: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save sync base: %w", err)
+	}
+	return nil
+}
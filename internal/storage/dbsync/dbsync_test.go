@@ -0,0 +1,183 @@
+package dbsync
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func newIssue(t *testing.T, store storage.Storage, title string) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2,
+		IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	if err := store.CreateIssue(context.Background(), issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	return issue
+}
+
+func getIssue(t *testing.T, store storage.Storage, id string) *types.Issue {
+	t.Helper()
+	issue, err := store.GetIssue(context.Background(), id)
+	if err != nil || issue == nil {
+		t.Fatalf("GetIssue(%s) = %v, %v", id, issue, err)
+	}
+	return issue
+}
+
+func hasComment(t *testing.T, store storage.Storage, id, comment string) bool {
+	t.Helper()
+	events, err := store.GetEvents(context.Background(), id, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	for _, event := range events {
+		if event.Comment != nil && *event.Comment == comment {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	local, remote := memory.New(), memory.New()
+	// Distinct prefixes keep independently created IDs apart, as hash IDs do
+	if err := remote.SetConfig(ctx, "issue_prefix", "team"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	mine := newIssue(t, local, "Laptop task")
+	if err := local.AddLabel(ctx, mine.ID, "backend", "alice"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	theirs := newIssue(t, remote, "Team task")
+
+	// First sync: each side gets the other's issue with its history
+	stats, base, err := Sync(ctx, local, remote, nil)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if stats.Local.IssuesCreated != 1 || stats.Remote.IssuesCreated != 1 {
+		t.Errorf("expected one issue copied each way, got %+v", stats)
+	}
+	if got := getIssue(t, remote, mine.ID); got.Title != "Laptop task" {
+		t.Errorf("expected the laptop task on the remote, got %q", got.Title)
+	}
+	if labels, _ := remote.GetLabels(ctx, mine.ID); len(labels) != 1 || labels[0] != "backend" {
+		t.Errorf("expected the label to be copied, got %v", labels)
+	}
+	getIssue(t, local, theirs.ID)
+
+	// One-sided changes flow across; both editing notes is a conflict the
+	// later edit wins
+	if err := local.UpdateIssue(ctx, theirs.ID, map[string]interface{}{"title": "Team task (renamed)", "notes": "laptop"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := local.AddComment(ctx, theirs.ID, "alice", "looking into it"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := remote.UpdateIssue(ctx, theirs.ID, map[string]interface{}{"priority": 0, "notes": "team"}, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := remote.RemoveLabel(ctx, mine.ID, "backend", "bob"); err != nil {
+		t.Fatalf("RemoveLabel failed: %v", err)
+	}
+	if err := remote.AddDependency(ctx, &types.Dependency{IssueID: mine.ID, DependsOnID: theirs.ID, Type: types.DepBlocks}, "bob"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	stats, base, err = Sync(ctx, local, remote, base)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	for _, store := range []storage.Storage{local, remote} {
+		got := getIssue(t, store, theirs.ID)
+		if got.Title != "Team task (renamed)" || got.Priority != 0 || got.Notes != "team" {
+			t.Errorf("expected merged fields on both sides, got title=%q priority=%d notes=%q", got.Title, got.Priority, got.Notes)
+		}
+	}
+	if len(stats.Conflicts) != 1 || stats.Conflicts[0].Field != "notes" || stats.Conflicts[0].Winner != types.SyncRemote {
+		t.Errorf("expected one notes conflict won by the remote, got %+v", stats.Conflicts)
+	}
+	if labels, _ := local.GetLabels(ctx, mine.ID); len(labels) != 0 {
+		t.Errorf("expected the label removal to reach the local side, got %v", labels)
+	}
+	if deps, _ := local.GetDependencyRecords(ctx, mine.ID); len(deps) != 1 {
+		t.Errorf("expected the dependency to reach the local side, got %d", len(deps))
+	}
+	if !hasComment(t, remote, theirs.ID, "looking into it") {
+		t.Error("expected the comment to reach the remote side")
+	}
+
+	// Nothing changed since, so a third sync is a no-op
+	stats, _, err = Sync(ctx, local, remote, base)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if stats.Local.Changed() || stats.Remote.Changed() || len(stats.Conflicts) > 0 {
+		t.Errorf("expected a repeated sync to change nothing, got %+v", stats)
+	}
+}
+
+func TestSyncConflictIsSymmetric(t *testing.T) {
+	ctx := context.Background()
+	// Which side is local must not change how a conflict is resolved
+	for _, swap := range []bool{false, true} {
+		a, b := memory.New(), memory.New()
+		issue := newIssue(t, a, "Task")
+		_, base, err := Sync(ctx, a, b, nil)
+		if err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+		if err := a.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": "alice"}, "test"); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+		if err := b.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": "bob"}, "test"); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+		local, remote := storage.Storage(a), storage.Storage(b)
+		if swap {
+			local, remote = remote, local
+		}
+		if _, _, err := Sync(ctx, local, remote, base); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+		want := getIssue(t, b, issue.ID).Assignee // b edited last
+		if got := getIssue(t, a, issue.ID).Assignee; got != want || want != "bob" {
+			t.Errorf("swap=%v: expected both sides to keep bob, got a=%q b=%q", swap, got, want)
+		}
+	}
+}
+
+func TestBaseRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync", "team.jsonl")
+	base, err := LoadBase(path)
+	if err != nil || base != nil {
+		t.Fatalf("LoadBase of a missing file = %v, %v; want nil, nil", base, err)
+	}
+
+	ctx := context.Background()
+	store := memory.New()
+	issue := newIssue(t, store, "Task")
+	_, base, err = Sync(ctx, store, memory.New(), nil)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if err := SaveBase(path, base); err != nil {
+		t.Fatalf("SaveBase failed: %v", err)
+	}
+	loaded, err := LoadBase(path)
+	if err != nil {
+		t.Fatalf("LoadBase failed: %v", err)
+	}
+	if len(loaded.Issues) != 1 || loaded.Issues[0].ID != issue.ID {
+		t.Errorf("expected the saved base to hold %s, got %+v", issue.ID, loaded.Issues)
+	}
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"os"
+
+	"github.com/steveyegge/vc/internal/storage/beads"
+)
+
+// Passphrase returns the database passphrase from VC_DB_PASSPHRASE, or ""
+// if the database is not encrypted
+func Passphrase() string {
+	return os.Getenv(beads.PassphraseEnvVar)
+}
+
+// DatabaseURI returns a URI for opening the database at path with the SQLite
+// driver directly, encrypted with Passphrase() if one is set. params are
+// extra URI parameters such as "mode=ro".
+func DatabaseURI(path string, params ...string) string {
+	return beads.DatabaseURI(path, Passphrase(), params...)
+}
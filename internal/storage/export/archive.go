@@ -0,0 +1,68 @@
+package export
+
+import "github.com/steveyegge/vc/internal/types"
+
+// ArchiveSet returns the candidates that can be archived together: every
+// dependency of an archived issue, in either direction, must be on another
+// archived issue. An archive is then a valid dump on its own, and no issue
+// left behind loses a dependency.
+func ArchiveSet(candidates map[string]bool, deps []*types.Dependency) map[string]bool {
+	set := make(map[string]bool, len(candidates))
+	for id := range candidates {
+		set[id] = true
+	}
+	// Dropping an issue can strand its neighbours, so repeat until stable
+	for changed := true; changed; {
+		changed = false
+		for _, dep := range deps {
+			if set[dep.IssueID] != set[dep.DependsOnID] {
+				delete(set, dep.IssueID)
+				delete(set, dep.DependsOnID)
+				changed = true
+			}
+		}
+	}
+	return set
+}
+
+// Subgraph returns the part of graph about the issues in ids
+func Subgraph(graph *types.ExportGraph, ids map[string]bool) *types.ExportGraph {
+	sub := &types.ExportGraph{Header: graph.Header}
+	for _, issue := range graph.Issues {
+		if ids[issue.ID] {
+			sub.Issues = append(sub.Issues, issue)
+		}
+	}
+	for _, dep := range graph.Dependencies {
+		if ids[dep.IssueID] && ids[dep.DependsOnID] {
+			sub.Dependencies = append(sub.Dependencies, dep)
+		}
+	}
+	for _, label := range graph.Labels {
+		if ids[label.IssueID] {
+			sub.Labels = append(sub.Labels, label)
+		}
+	}
+	for _, event := range graph.Events {
+		if ids[event.IssueID] {
+			sub.Events = append(sub.Events, event)
+		}
+	}
+	for _, attempt := range graph.Executions {
+		if ids[attempt.IssueID] {
+			sub.Executions = append(sub.Executions, attempt)
+		}
+	}
+	return sub
+}
+
+// ArchiveStatsFor counts the contents of an archive
+func ArchiveStatsFor(archive *types.ExportGraph) *types.ArchiveStats {
+	return &types.ArchiveStats{
+		Issues:       len(archive.Issues),
+		Dependencies: len(archive.Dependencies),
+		Labels:       len(archive.Labels),
+		Events:       len(archive.Events),
+		Executions:   len(archive.Executions),
+	}
+}
@@ -0,0 +1,311 @@
+// Package export encodes and decodes VC issue graph dumps.
+//
+// A dump is JSONL: a header record followed by one record per issue,
+// dependency, label, event and execution attempt. Line-per-record keeps
+// diffs small when a backlog is checked into git, and lets large graphs be
+// streamed. Each line looks like:
+//
+//	{"kind":"issue","issue":{...}}
+//
+// Records are written in a stable order (issues by ID, then dependencies,
+// labels, events and executions) so exporting an unchanged database twice
+// produces identical output.
+//
+// Storage backends build a types.ExportGraph and use Write/Read; the format
+// itself lives here so every backend produces the same dump.
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Record kinds
+const (
+	KindHeader     = "header"
+	KindIssue      = "issue"
+	KindDependency = "dependency"
+	KindLabel      = "label"
+	KindEvent      = "event"
+	KindExecution  = "execution"
+)
+
+// maxLineSize bounds a single record. Execution attempts carry output
+// samples, so records can be much larger than bufio's 64KB default.
+const maxLineSize = 64 * 1024 * 1024
+
+// record is one line of a dump. Exactly one payload field is set, matching Kind.
+type record struct {
+	Kind       string                  `json:"kind"`
+	Header     *types.ExportHeader     `json:"header,omitempty"`
+	Issue      *types.Issue            `json:"issue,omitempty"`
+	Dependency *types.Dependency       `json:"dependency,omitempty"`
+	Label      *types.IssueLabel       `json:"label,omitempty"`
+	Event      *types.Event            `json:"event,omitempty"`
+	Execution  *types.ExecutionAttempt `json:"execution,omitempty"`
+}
+
+// Write encodes graph to w as JSONL. The header's Format and Version are
+// always set to the current format; ExportedAt and IssuePrefix are taken
+// from graph.Header.
+func Write(w io.Writer, graph *types.ExportGraph) error {
+	sortGraph(graph)
+
+	header := graph.Header
+	header.Format = types.ExportFormat
+	header.Version = types.ExportVersion
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	write := func(rec record) error {
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("failed to write %s record: %w", rec.Kind, err)
+		}
+		return nil
+	}
+
+	if err := write(record{Kind: KindHeader, Header: &header}); err != nil {
+		return err
+	}
+	for _, issue := range graph.Issues {
+		// MissionContext is computed on read, never stored
+		issueCopy := *issue
+		issueCopy.MissionContext = nil
+		if err := write(record{Kind: KindIssue, Issue: &issueCopy}); err != nil {
+			return err
+		}
+	}
+	for _, dep := range graph.Dependencies {
+		if err := write(record{Kind: KindDependency, Dependency: dep}); err != nil {
+			return err
+		}
+	}
+	for _, label := range graph.Labels {
+		if err := write(record{Kind: KindLabel, Label: label}); err != nil {
+			return err
+		}
+	}
+	for _, event := range graph.Events {
+		if err := write(record{Kind: KindEvent, Event: event}); err != nil {
+			return err
+		}
+	}
+	for _, attempt := range graph.Executions {
+		if err := write(record{Kind: KindExecution, Execution: attempt}); err != nil {
+			return err
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// Read decodes a dump written by Write. It rejects input without a header,
+// dumps from a newer format version, and records that reference issues not
+// present in the dump.
+func Read(r io.Reader) (*types.ExportGraph, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	graph := &types.ExportGraph{}
+	sawHeader := false
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON: %w", line, err)
+		}
+
+		if !sawHeader {
+			if rec.Kind != KindHeader || rec.Header == nil {
+				return nil, fmt.Errorf("line %d: expected %s record, got %q", line, KindHeader, rec.Kind)
+			}
+			if rec.Header.Format != types.ExportFormat {
+				return nil, fmt.Errorf("not a VC export (format %q)", rec.Header.Format)
+			}
+			if rec.Header.Version < 1 || rec.Header.Version > types.ExportVersion {
+				return nil, fmt.Errorf("unsupported export version %d (this build reads up to version %d)",
+					rec.Header.Version, types.ExportVersion)
+			}
+			graph.Header = *rec.Header
+			sawHeader = true
+			continue
+		}
+
+		if err := addRecord(graph, rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	if !sawHeader {
+		return nil, fmt.Errorf("empty export: missing %s record", KindHeader)
+	}
+
+	if err := Validate(graph); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+func addRecord(graph *types.ExportGraph, rec record) error {
+	switch rec.Kind {
+	case KindHeader:
+		return fmt.Errorf("duplicate %s record", KindHeader)
+	case KindIssue:
+		if rec.Issue == nil {
+			return fmt.Errorf("%s record has no payload", rec.Kind)
+		}
+		graph.Issues = append(graph.Issues, rec.Issue)
+	case KindDependency:
+		if rec.Dependency == nil {
+			return fmt.Errorf("%s record has no payload", rec.Kind)
+		}
+		graph.Dependencies = append(graph.Dependencies, rec.Dependency)
+	case KindLabel:
+		if rec.Label == nil {
+			return fmt.Errorf("%s record has no payload", rec.Kind)
+		}
+		graph.Labels = append(graph.Labels, rec.Label)
+	case KindEvent:
+		if rec.Event == nil {
+			return fmt.Errorf("%s record has no payload", rec.Kind)
+		}
+		graph.Events = append(graph.Events, rec.Event)
+	case KindExecution:
+		if rec.Execution == nil {
+			return fmt.Errorf("%s record has no payload", rec.Kind)
+		}
+		graph.Executions = append(graph.Executions, rec.Execution)
+	default:
+		return fmt.Errorf("unknown record kind %q", rec.Kind)
+	}
+	return nil
+}
+
+// Validate checks that every issue is valid and unique, and that every
+// dependency, label, event and execution refers to an issue in the graph
+func Validate(graph *types.ExportGraph) error {
+	ids := make(map[string]bool, len(graph.Issues))
+	for _, issue := range graph.Issues {
+		if issue.ID == "" {
+			return fmt.Errorf("issue %q has no ID", issue.Title)
+		}
+		if ids[issue.ID] {
+			return fmt.Errorf("duplicate issue %s", issue.ID)
+		}
+		if err := validateIssue(issue); err != nil {
+			return fmt.Errorf("invalid issue %s: %w", issue.ID, err)
+		}
+		ids[issue.ID] = true
+	}
+
+	for _, dep := range graph.Dependencies {
+		if !ids[dep.IssueID] || !ids[dep.DependsOnID] {
+			return fmt.Errorf("dependency %s -> %s refers to an issue not in the export", dep.IssueID, dep.DependsOnID)
+		}
+		if !dep.Type.IsValid() {
+			return fmt.Errorf("dependency %s -> %s has invalid type %q", dep.IssueID, dep.DependsOnID, dep.Type)
+		}
+	}
+	for _, label := range graph.Labels {
+		if !ids[label.IssueID] {
+			return fmt.Errorf("label %q refers to issue %s, which is not in the export", label.Label, label.IssueID)
+		}
+	}
+	for _, event := range graph.Events {
+		if !ids[event.IssueID] {
+			return fmt.Errorf("event %d refers to issue %s, which is not in the export", event.ID, event.IssueID)
+		}
+	}
+	for _, attempt := range graph.Executions {
+		if !ids[attempt.IssueID] {
+			return fmt.Errorf("execution attempt %d refers to issue %s, which is not in the export", attempt.ID, attempt.IssueID)
+		}
+	}
+	return nil
+}
+
+// validateIssue checks the fields the storage schema enforces. It
+// deliberately skips creation-time policy such as required acceptance
+// criteria: an import restores issues as they were, including older ones
+// filed before the policy existed.
+func validateIssue(issue *types.Issue) error {
+	if issue.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(issue.Title) > 500 {
+		return fmt.Errorf("title must be 500 characters or less (got %d)", len(issue.Title))
+	}
+	if issue.Priority < 0 || issue.Priority > 4 {
+		return fmt.Errorf("priority must be between 0 and 4 (got %d)", issue.Priority)
+	}
+	if !issue.Status.IsValid() {
+		return fmt.Errorf("invalid status: %s", issue.Status)
+	}
+	if !issue.IssueType.IsValid() {
+		return fmt.Errorf("invalid issue type: %s", issue.IssueType)
+	}
+	if !issue.IssueSubtype.IsValid() {
+		return fmt.Errorf("invalid issue subtype: %s", issue.IssueSubtype)
+	}
+	if (issue.Status == types.StatusClosed) != (issue.ClosedAt != nil) {
+		return fmt.Errorf("closed_at must be set if and only if status is closed")
+	}
+	return nil
+}
+
+// sortGraph puts records in the stable order Write promises
+func sortGraph(graph *types.ExportGraph) {
+	sort.SliceStable(graph.Issues, func(i, j int) bool {
+		return graph.Issues[i].ID < graph.Issues[j].ID
+	})
+	sort.SliceStable(graph.Dependencies, func(i, j int) bool {
+		a, b := graph.Dependencies[i], graph.Dependencies[j]
+		if a.IssueID != b.IssueID {
+			return a.IssueID < b.IssueID
+		}
+		return a.DependsOnID < b.DependsOnID
+	})
+	sort.SliceStable(graph.Labels, func(i, j int) bool {
+		a, b := graph.Labels[i], graph.Labels[j]
+		if a.IssueID != b.IssueID {
+			return a.IssueID < b.IssueID
+		}
+		return a.Label < b.Label
+	})
+	sort.SliceStable(graph.Events, func(i, j int) bool {
+		a, b := graph.Events[i], graph.Events[j]
+		if a.IssueID != b.IssueID {
+			return a.IssueID < b.IssueID
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	sort.SliceStable(graph.Executions, func(i, j int) bool {
+		a, b := graph.Executions[i], graph.Executions[j]
+		if a.IssueID != b.IssueID {
+			return a.IssueID < b.IssueID
+		}
+		if !a.StartedAt.Equal(b.StartedAt) {
+			return a.StartedAt.Before(b.StartedAt)
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func testGraph() *types.ExportGraph {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	closed := created.Add(time.Hour)
+	return &types.ExportGraph{
+		Header: types.ExportHeader{ExportedAt: created, IssuePrefix: "vc"},
+		Issues: []*types.Issue{
+			{ID: "vc-2", Title: "Second", Status: types.StatusClosed, IssueType: types.TypeBug, Priority: 1,
+				CreatedAt: created, UpdatedAt: closed, ClosedAt: &closed},
+			{ID: "vc-1", Title: "First", Status: types.StatusOpen, IssueType: types.TypeChore, Priority: 2,
+				CreatedAt: created, UpdatedAt: created},
+		},
+		Dependencies: []*types.Dependency{
+			{IssueID: "vc-2", DependsOnID: "vc-1", Type: types.DepBlocks, CreatedAt: created, CreatedBy: "test"},
+		},
+		Labels: []*types.IssueLabel{
+			{IssueID: "vc-2", Label: "zeta"},
+			{IssueID: "vc-2", Label: "alpha"},
+		},
+		Events: []*types.Event{
+			{ID: 7, IssueID: "vc-1", EventType: types.EventCreated, Actor: "test", CreatedAt: created},
+		},
+		Executions: []*types.ExecutionAttempt{
+			{ID: 3, IssueID: "vc-2", AttemptNumber: 1, StartedAt: created, Summary: "ok"},
+		},
+	}
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testGraph()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("expected 8 records, got %d:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], `"format":"vc-issue-graph"`) || !strings.Contains(lines[0], `"version":1`) {
+		t.Errorf("first record should be the header, got %s", lines[0])
+	}
+	// Stable order: issues sorted by ID, labels sorted within an issue
+	if !strings.Contains(lines[1], `"id":"vc-1"`) || !strings.Contains(lines[2], `"id":"vc-2"`) {
+		t.Errorf("issues not sorted by ID:\n%s\n%s", lines[1], lines[2])
+	}
+	if !strings.Contains(lines[4], `"alpha"`) {
+		t.Errorf("labels not sorted: %s", lines[4])
+	}
+
+	graph, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if graph.Header.IssuePrefix != "vc" || len(graph.Issues) != 2 || len(graph.Dependencies) != 1 ||
+		len(graph.Labels) != 2 || len(graph.Events) != 1 || len(graph.Executions) != 1 {
+		t.Errorf("round trip lost records: %+v", graph)
+	}
+	if graph.Issues[1].ClosedAt == nil || !graph.Issues[1].ClosedAt.Equal(*testGraph().Issues[0].ClosedAt) {
+		t.Errorf("closed_at not preserved: %v", graph.Issues[1].ClosedAt)
+	}
+}
+
+func TestReadRejectsBadInput(t *testing.T) {
+	header := `{"kind":"header","header":{"format":"vc-issue-graph","version":1,"exported_at":"2025-01-01T00:00:00Z"}}` + "\n"
+	issue := `{"kind":"issue","issue":{"id":"vc-1","title":"One","status":"open","priority":2,"issue_type":"chore"}}` + "\n"
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", "missing header"},
+		{"no header", issue, "expected header"},
+		{"wrong format", `{"kind":"header","header":{"format":"beads","version":1}}` + "\n", "not a VC export"},
+		{"newer version", `{"kind":"header","header":{"format":"vc-issue-graph","version":99}}` + "\n", "unsupported export version"},
+		{"bad json", header + "{not json\n", "invalid JSON"},
+		{"unknown kind", header + `{"kind":"widget"}` + "\n", "unknown record kind"},
+		{"duplicate issue", header + issue + issue, "duplicate issue"},
+		{"dangling label", header + `{"kind":"label","label":{"issue_id":"vc-9","label":"x"}}` + "\n", "not in the export"},
+		{"closed without closed_at", header +
+			`{"kind":"issue","issue":{"id":"vc-1","title":"One","status":"closed","priority":2,"issue_type":"chore"}}` + "\n",
+			"closed_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Read() error = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadAllowsIssuesWithoutAcceptanceCriteria(t *testing.T) {
+	// Creation-time policy doesn't apply to restored history
+	input := `{"kind":"header","header":{"format":"vc-issue-graph","version":1,"exported_at":"2025-01-01T00:00:00Z"}}
+{"kind":"issue","issue":{"id":"vc-1","title":"Old task","status":"open","priority":2,"issue_type":"task"}}
+`
+	if _, err := Read(strings.NewReader(input)); err != nil {
+		t.Errorf("Read() rejected a task without acceptance criteria: %v", err)
+	}
+}
+
+func TestArchiveSet(t *testing.T) {
+	dep := func(from, to string) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: types.DepBlocks}
+	}
+	candidates := map[string]bool{"vc-1": true, "vc-2": true, "vc-3": true, "vc-4": true}
+	deps := []*types.Dependency{
+		dep("vc-2", "vc-1"),    // both archivable
+		dep("vc-4", "vc-3"),    // vc-3 also blocks a live issue...
+		dep("vc-live", "vc-3"), // ...so neither vc-3 nor, through it, vc-4 can go
+	}
+
+	got := ArchiveSet(candidates, deps)
+	if len(got) != 2 || !got["vc-1"] || !got["vc-2"] {
+		t.Errorf("expected only vc-1 and vc-2 to be archivable, got %v", got)
+	}
+	if len(candidates) != 4 {
+		t.Error("expected ArchiveSet to leave candidates unchanged")
+	}
+
+	sub := Subgraph(testGraph(), map[string]bool{"vc-2": true})
+	if err := Validate(sub); err != nil {
+		t.Errorf("expected a subgraph to be a valid dump on its own: %v", err)
+	}
+}
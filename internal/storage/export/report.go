@@ -0,0 +1,315 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Reports are tabular exports for spreadsheets and BI tools, as opposed to
+// the JSONL dump, which is for moving a whole graph between databases. Each
+// table has a fixed set of columns, in a fixed order, so queries and sheets
+// built on an export keep working: columns are only ever added at the end,
+// never renamed, reordered or removed.
+
+// Table is a kind of report row
+type Table string
+
+// Report tables
+const (
+	TableIssues     Table = "issues"     // One row per issue
+	TableExecutions Table = "executions" // One row per agent execution
+	TableGates      Table = "gates"      // One row per quality gate run
+	TableAIUsage    Table = "ai-usage"   // One row per AI supervisor call
+)
+
+// Tables lists the report tables
+var Tables = []Table{TableIssues, TableExecutions, TableGates, TableAIUsage}
+
+// ParseTable parses a table name
+func ParseTable(s string) (Table, error) {
+	for _, t := range Tables {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown table %q (want issues, executions, gates or ai-usage)", s)
+}
+
+// Columns returns the table's columns, in order
+func (t Table) Columns() []string {
+	switch t {
+	case TableIssues:
+		return []string{"id", "title", "issue_type", "issue_subtype", "status", "priority", "assignee",
+			"project", "labels", "estimated_minutes", "created_at", "updated_at", "closed_at"}
+	case TableExecutions:
+		return []string{"id", "issue_id", "executor_instance_id", "agent_provider", "status", "exit_code", "error",
+			"started_at", "completed_at", "agent_duration_seconds", "cost_usd", "commit_hash", "revert_commit",
+			"rollback_issue_id"}
+	case TableGates:
+		return []string{"event_id", "issue_id", "timestamp", "all_passed", "gates_run", "passed_count",
+			"failed_count", "timed_out", "canceled", "error"}
+	case TableAIUsage:
+		return []string{"id", "timestamp", "issue_id", "operation", "model", "input_tokens", "output_tokens",
+			"cost_usd", "duration_seconds"}
+	}
+	return nil
+}
+
+// Report is a table's rows. Each row has a value per column: a string,
+// bool, int, int64, float64, time.Time, []string, or nil for no value.
+type Report struct {
+	Table   Table
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// ReportStore is the storage BuildReport reads
+type ReportStore interface {
+	GetLabels(ctx context.Context, issueID string) ([]string, error)
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+	GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error)
+	ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error)
+}
+
+// ReportQuery selects a report's rows
+type ReportQuery struct {
+	// Issues are the issues to report on: the issues themselves, or their
+	// executions, gate runs and AI usage
+	Issues []*types.Issue
+
+	// AllIssues is set when Issues is every issue, so AI usage not on an
+	// issue (e.g. digests) is reported too
+	AllIssues bool
+
+	// Since drops rows from before it; zero keeps everything. Issues are
+	// kept if they were updated since.
+	Since time.Time
+}
+
+// BuildReport builds a table's report. Rows are in a stable order (issues
+// by ID, everything else oldest first) so an unchanged database exports
+// identically.
+func BuildReport(ctx context.Context, store ReportStore, table Table, query ReportQuery) (*Report, error) {
+	report := &Report{Table: table, Columns: table.Columns(), Rows: [][]interface{}{}}
+	issueIDs := make(map[string]bool, len(query.Issues))
+	for _, issue := range query.Issues {
+		issueIDs[issue.ID] = true
+	}
+
+	switch table {
+	case TableIssues:
+		issues := append([]*types.Issue(nil), query.Issues...)
+		sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+		for _, issue := range issues {
+			if issue.UpdatedAt.Before(query.Since) {
+				continue
+			}
+			labels, err := store.GetLabels(ctx, issue.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get labels of %s: %w", issue.ID, err)
+			}
+			sort.Strings(labels)
+			var estimate interface{}
+			if issue.EstimatedMinutes != nil {
+				estimate = *issue.EstimatedMinutes
+			}
+			var closedAt interface{}
+			if issue.ClosedAt != nil {
+				closedAt = *issue.ClosedAt
+			}
+			report.Rows = append(report.Rows, []interface{}{
+				issue.ID, issue.Title, string(issue.IssueType), string(issue.IssueSubtype), string(issue.Status),
+				issue.Priority, issue.Assignee, types.ProjectFromLabels(labels), labels, estimate,
+				issue.CreatedAt, issue.UpdatedAt, closedAt,
+			})
+		}
+
+	case TableExecutions:
+		executions, err := store.ListExecutions(ctx, types.ExecutionFilter{Since: query.Since})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list executions: %w", err)
+		}
+		sort.Slice(executions, func(i, j int) bool {
+			if !executions[i].StartedAt.Equal(executions[j].StartedAt) {
+				return executions[i].StartedAt.Before(executions[j].StartedAt)
+			}
+			return executions[i].ID < executions[j].ID
+		})
+		for _, e := range executions {
+			if !issueIDs[e.IssueID] {
+				continue
+			}
+			var exitCode, completedAt interface{}
+			if e.ExitCode != nil {
+				exitCode = *e.ExitCode
+			}
+			if e.CompletedAt != nil {
+				completedAt = *e.CompletedAt
+			}
+			report.Rows = append(report.Rows, []interface{}{
+				e.ID, e.IssueID, e.ExecutorInstanceID, e.AgentProvider, string(e.Status), exitCode, e.Error,
+				e.StartedAt, completedAt, e.AgentDuration.Seconds(), e.CostUSD, e.CommitHash, e.RevertCommit,
+				e.RollbackIssueID,
+			})
+		}
+
+	case TableGates:
+		gateEvents, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeQualityGatesCompleted})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list gate runs: %w", err)
+		}
+		sort.Slice(gateEvents, func(i, j int) bool {
+			if !gateEvents[i].Timestamp.Equal(gateEvents[j].Timestamp) {
+				return gateEvents[i].Timestamp.Before(gateEvents[j].Timestamp)
+			}
+			return gateEvents[i].ID < gateEvents[j].ID
+		})
+		for _, event := range gateEvents {
+			if !issueIDs[event.IssueID] || event.Timestamp.Before(query.Since) {
+				continue
+			}
+			errMsg, _ := event.Data["error"].(string)
+			report.Rows = append(report.Rows, []interface{}{
+				event.ID, event.IssueID, event.Timestamp, dataBool(event.Data, "all_passed"),
+				dataInt(event.Data, "gates_run"), dataInt(event.Data, "passed_count"),
+				dataInt(event.Data, "failed_count"), dataBool(event.Data, "timeout"),
+				dataBool(event.Data, "canceled"), errMsg,
+			})
+		}
+
+	case TableAIUsage:
+		usage, err := store.ListAIUsage(ctx, types.AIUsageFilter{Since: query.Since})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list AI usage: %w", err)
+		}
+		for _, u := range usage {
+			if !issueIDs[u.IssueID] && !(query.AllIssues && u.IssueID == "") {
+				continue
+			}
+			report.Rows = append(report.Rows, []interface{}{
+				u.ID, u.Timestamp, u.IssueID, u.Operation, u.Model, u.InputTokens, u.OutputTokens,
+				u.CostUSD, u.Duration.Seconds(),
+			})
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown table %q", table)
+	}
+	return report, nil
+}
+
+// dataBool reads a boolean from event data, false if absent
+func dataBool(data map[string]interface{}, key string) bool {
+	v, _ := data[key].(bool)
+	return v
+}
+
+// dataInt reads a count from event data, which holds ints when the event
+// was just built and float64s once it has been through JSON
+func dataInt(data map[string]interface{}, key string) int {
+	switch v := data[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// WriteCSV writes the report as CSV with a header row. Times are RFC 3339
+// in UTC, lists are joined with semicolons and missing values are empty.
+func (r *Report) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(r.Columns); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = csvCell(value)
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// csvCell formats a value for CSV
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ";")
+	}
+	return fmt.Sprint(value)
+}
+
+// WriteJSON writes the report as a JSON array with an object per row. Every
+// object has every column, in column order; missing values are null.
+func (r *Report) WriteJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, row := range r.Rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, value := range row {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(r.Columns[j])
+			buf.Write(key)
+			buf.WriteByte(':')
+			if t, ok := value.(time.Time); ok {
+				value = t.UTC().Format(time.RFC3339)
+			}
+			if labels, ok := value.([]string); ok && labels == nil {
+				value = []string{}
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", r.Columns[j], err)
+			}
+			buf.Write(data)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := w.Write(out.Bytes())
+	return err
+}
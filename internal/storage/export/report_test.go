@@ -0,0 +1,171 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// fakeReportStore serves fixed rows to BuildReport
+type fakeReportStore struct {
+	labels     map[string][]string
+	executions []*types.Execution
+	gateEvents []*events.AgentEvent
+	usage      []*types.AIUsage
+}
+
+func (f *fakeReportStore) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	return f.labels[issueID], nil
+}
+
+func (f *fakeReportStore) ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error) {
+	return f.executions, nil
+}
+
+func (f *fakeReportStore) GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error) {
+	return f.gateEvents, nil
+}
+
+func (f *fakeReportStore) ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error) {
+	return f.usage, nil
+}
+
+func testReportStore() (*fakeReportStore, []*types.Issue) {
+	at := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	estimate := 30
+	exitCode := 1
+	issues := []*types.Issue{
+		{ID: "vc-2", Title: "Second, with a comma", Status: types.StatusOpen, IssueType: types.TypeBug, Priority: 1,
+			CreatedAt: at, UpdatedAt: at},
+		{ID: "vc-1", Title: "First", Status: types.StatusClosed, IssueType: types.TypeTask, Priority: 2,
+			EstimatedMinutes: &estimate, CreatedAt: at, UpdatedAt: at, ClosedAt: &at},
+	}
+	return &fakeReportStore{
+		labels: map[string][]string{"vc-1": {"urgent", "project:billing"}},
+		executions: []*types.Execution{
+			{ID: 2, IssueID: "vc-1", AgentProvider: "claude-code", Status: types.ExecutionFailed,
+				ExitCode: &exitCode, StartedAt: at.Add(time.Hour), AgentDuration: 90 * time.Second},
+			{ID: 1, IssueID: "vc-2", AgentProvider: "claude-code", Status: types.ExecutionRunning, StartedAt: at},
+		},
+		gateEvents: []*events.AgentEvent{
+			{ID: "ev-1", IssueID: "vc-1", Timestamp: at, Type: events.EventTypeQualityGatesCompleted,
+				Data: map[string]interface{}{"all_passed": false, "gates_run": float64(3), "passed_count": float64(2), "failed_count": float64(1)}},
+		},
+		usage: []*types.AIUsage{
+			{ID: "u-1", Timestamp: at, IssueID: "vc-1", Operation: "assessment", Model: "sonnet", InputTokens: 100, OutputTokens: 20, CostUSD: 0.5},
+			{ID: "u-2", Timestamp: at, Operation: "digest", Model: "haiku", InputTokens: 10, OutputTokens: 5, CostUSD: 0.01},
+		},
+	}, issues
+}
+
+func TestBuildReportIssuesCSV(t *testing.T) {
+	store, issues := testReportStore()
+	report, err := BuildReport(context.Background(), store, TableIssues, ReportQuery{Issues: issues, AllIssues: true})
+	if err != nil {
+		t.Fatalf("BuildReport failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	want := `id,title,issue_type,issue_subtype,status,priority,assignee,project,labels,estimated_minutes,created_at,updated_at,closed_at
+vc-1,First,task,,closed,2,,billing,project:billing;urgent,30,2025-10-01T12:00:00Z,2025-10-01T12:00:00Z,2025-10-01T12:00:00Z
+vc-2,"Second, with a comma",bug,,open,1,,,,,2025-10-01T12:00:00Z,2025-10-01T12:00:00Z,
+`
+	if buf.String() != want {
+		t.Errorf("CSV mismatch:\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestBuildReportExecutionsJSON(t *testing.T) {
+	store, issues := testReportStore()
+	report, err := BuildReport(context.Background(), store, TableExecutions, ReportQuery{Issues: issues})
+	if err != nil {
+		t.Fatalf("BuildReport failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	// Oldest first, and every column present
+	if rows[0]["id"] != float64(1) || rows[1]["id"] != float64(2) {
+		t.Errorf("rows out of order: %v, %v", rows[0]["id"], rows[1]["id"])
+	}
+	if len(rows[0]) != len(TableExecutions.Columns()) {
+		t.Errorf("expected %d columns, got %d", len(TableExecutions.Columns()), len(rows[0]))
+	}
+	if rows[0]["exit_code"] != nil || rows[1]["exit_code"] != float64(1) {
+		t.Errorf("unexpected exit codes: %v, %v", rows[0]["exit_code"], rows[1]["exit_code"])
+	}
+	if rows[1]["agent_duration_seconds"] != float64(90) {
+		t.Errorf("expected 90s duration, got %v", rows[1]["agent_duration_seconds"])
+	}
+	// Keys follow column order
+	if !strings.HasPrefix(strings.TrimSpace(buf.String()), "[\n  {\n    \"id\": 1,\n    \"issue_id\": \"vc-2\"") {
+		t.Errorf("unexpected key order:\n%s", buf.String())
+	}
+}
+
+func TestBuildReportSelectsIssues(t *testing.T) {
+	store, issues := testReportStore()
+	ctx := context.Background()
+
+	// Only vc-1's rows, and no usage outside issues
+	query := ReportQuery{Issues: issues[1:]}
+	for table, want := range map[Table]int{TableIssues: 1, TableExecutions: 1, TableGates: 1, TableAIUsage: 1} {
+		report, err := BuildReport(ctx, store, table, query)
+		if err != nil {
+			t.Fatalf("BuildReport(%s) failed: %v", table, err)
+		}
+		if len(report.Rows) != want {
+			t.Errorf("%s: expected %d rows, got %d", table, want, len(report.Rows))
+		}
+	}
+
+	report, err := BuildReport(ctx, store, TableAIUsage, ReportQuery{Issues: issues, AllIssues: true})
+	if err != nil {
+		t.Fatalf("BuildReport failed: %v", err)
+	}
+	if len(report.Rows) != 2 {
+		t.Errorf("expected usage outside issues with AllIssues, got %d rows", len(report.Rows))
+	}
+
+	report, err = BuildReport(ctx, store, TableGates, ReportQuery{Issues: issues})
+	if err != nil {
+		t.Fatalf("BuildReport failed: %v", err)
+	}
+	row := report.Rows[0]
+	if row[3] != false || row[4] != 3 || row[5] != 2 || row[6] != 1 {
+		t.Errorf("unexpected gate row: %v", row)
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	for _, table := range Tables {
+		got, err := ParseTable(string(table))
+		if err != nil || got != table {
+			t.Errorf("ParseTable(%q) = %q, %v", table, got, err)
+		}
+		if len(table.Columns()) == 0 {
+			t.Errorf("%s has no columns", table)
+		}
+	}
+	if _, err := ParseTable("bogus"); err == nil {
+		t.Error("expected an error for an unknown table")
+	}
+}
@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// maxLineSize bounds a single issues.jsonl line. Issues carry their
+// comments, so lines can outgrow bufio's 64KB default.
+const maxLineSize = 64 * 1024 * 1024
+
+// beadsIssue is one line of a Beads issues.jsonl file
+type beadsIssue struct {
+	ID                 string              `json:"id"`
+	Title              string              `json:"title"`
+	Description        string              `json:"description"`
+	Design             string              `json:"design"`
+	AcceptanceCriteria string              `json:"acceptance_criteria"`
+	Notes              string              `json:"notes"`
+	Status             string              `json:"status"`
+	Priority           int                 `json:"priority"`
+	IssueType          string              `json:"issue_type"`
+	Assignee           string              `json:"assignee"`
+	EstimatedMinutes   *int                `json:"estimated_minutes"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	ClosedAt           *time.Time          `json:"closed_at"`
+	Labels             []string            `json:"labels"`
+	Dependencies       []*types.Dependency `json:"dependencies"`
+	Comments           []*beadsComment     `json:"comments"`
+}
+
+// beadsComment is a comment embedded in a Beads issue
+type beadsComment struct {
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// fromBeads converts a Beads issues.jsonl file. IDs, priorities, labels,
+// dependencies (including parent-child links) and comments carry over as is.
+func fromBeads(r io.Reader, opts Options) (*Result, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	res := &Result{Graph: &types.ExportGraph{}}
+	graph := res.Graph
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+		var bi beadsIssue
+		if err := json.Unmarshal(data, &bi); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON: %w", line, err)
+		}
+		if bi.ID == "" {
+			return nil, fmt.Errorf("line %d: issue %q has no ID", line, bi.Title)
+		}
+
+		issue, err := bi.toIssue(opts.Now)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: issue %s: %w", line, bi.ID, err)
+		}
+		graph.Issues = append(graph.Issues, issue)
+		graph.Events = append(graph.Events, createdEvent(issue, SourceBeads, opts.Actor))
+		addLabels(graph, issue.ID, bi.Labels)
+		for _, dep := range bi.Dependencies {
+			if dep == nil {
+				continue
+			}
+			if !dep.Type.IsValid() {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("dropped dependency %s -> %s with unknown type %q",
+					dep.IssueID, dep.DependsOnID, dep.Type))
+				continue
+			}
+			depCopy := *dep
+			depCopy.IssueID = issue.ID
+			if depCopy.CreatedBy == "" {
+				depCopy.CreatedBy = opts.Actor
+			}
+			if depCopy.CreatedAt.IsZero() {
+				depCopy.CreatedAt = issue.CreatedAt
+			}
+			graph.Dependencies = append(graph.Dependencies, &depCopy)
+		}
+		for _, c := range bi.Comments {
+			if c == nil || c.Text == "" {
+				continue
+			}
+			text := c.Text
+			graph.Events = append(graph.Events, &types.Event{
+				IssueID:   issue.ID,
+				EventType: types.EventCommented,
+				Actor:     c.Author,
+				Comment:   &text,
+				CreatedAt: c.CreatedAt,
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read beads issues: %w", err)
+	}
+	return res, nil
+}
+
+// toIssue converts a Beads issue, normalizing values Beads accepts but VC
+// doesn't
+func (bi *beadsIssue) toIssue(now time.Time) (*types.Issue, error) {
+	status, err := parseStatus(bi.Status)
+	if err != nil {
+		return nil, err
+	}
+	issueType, err := parseIssueType(bi.IssueType)
+	if err != nil {
+		return nil, err
+	}
+	issue := &types.Issue{
+		ID:                 bi.ID,
+		Title:              bi.Title,
+		Description:        bi.Description,
+		Design:             bi.Design,
+		AcceptanceCriteria: bi.AcceptanceCriteria,
+		Notes:              bi.Notes,
+		Status:             status,
+		Priority:           bi.Priority,
+		IssueType:          issueType,
+		Assignee:           bi.Assignee,
+		EstimatedMinutes:   bi.EstimatedMinutes,
+		CreatedAt:          bi.CreatedAt,
+		UpdatedAt:          bi.UpdatedAt,
+		ClosedAt:           bi.ClosedAt,
+	}
+	fillTimestamps(issue, now)
+	return issue, nil
+}
@@ -0,0 +1,360 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// VC fields a generic source can fill in. labels holds a comma- or
+// semicolon-separated list (or a JSON array); parent holds the ID of the
+// parent issue, which becomes a parent-child dependency.
+const (
+	FieldID                 = "id"
+	FieldTitle              = "title"
+	FieldDescription        = "description"
+	FieldDesign             = "design"
+	FieldAcceptanceCriteria = "acceptance_criteria"
+	FieldNotes              = "notes"
+	FieldStatus             = "status"
+	FieldPriority           = "priority"
+	FieldIssueType          = "issue_type"
+	FieldAssignee           = "assignee"
+	FieldEstimatedMinutes   = "estimated_minutes"
+	FieldLabels             = "labels"
+	FieldParent             = "parent"
+	FieldCreatedAt          = "created_at"
+	FieldClosedAt           = "closed_at"
+)
+
+// Fields lists the VC fields a Mapping can map
+var Fields = []string{
+	FieldID, FieldTitle, FieldDescription, FieldDesign, FieldAcceptanceCriteria, FieldNotes,
+	FieldStatus, FieldPriority, FieldIssueType, FieldAssignee, FieldEstimatedMinutes,
+	FieldLabels, FieldParent, FieldCreatedAt, FieldClosedAt,
+}
+
+// Mapping maps VC fields to source fields (CSV columns, or JSON keys, with
+// dots reaching into nested objects, e.g. "fields.summary")
+type Mapping map[string]string
+
+// ParseMapping parses "vcfield=sourcefield" pairs
+func ParseMapping(pairs []string) (Mapping, error) {
+	known := make(map[string]bool, len(Fields))
+	for _, f := range Fields {
+		known[f] = true
+	}
+	m := make(Mapping, len(pairs))
+	for _, pair := range pairs {
+		field, source, ok := strings.Cut(pair, "=")
+		field, source = strings.TrimSpace(field), strings.TrimSpace(source)
+		if !ok || field == "" || source == "" {
+			return nil, fmt.Errorf("invalid mapping %q (expected vcfield=sourcefield)", pair)
+		}
+		if !known[field] {
+			return nil, fmt.Errorf("unknown field %q in mapping (must be one of %s)", field, strings.Join(Fields, ", "))
+		}
+		m[field] = source
+	}
+	return m, nil
+}
+
+// source returns the source field holding a VC field
+func (m Mapping) source(field string) string {
+	if s, ok := m[field]; ok {
+		return s
+	}
+	return field
+}
+
+// readJSONRows reads a JSON array of objects, or a stream of objects (such
+// as JSON lines), flattening nested objects into dotted keys
+func readJSONRows(r io.Reader) ([]map[string]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var objects []map[string]interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := dec.Decode(&objects); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	} else {
+		for {
+			var obj map[string]interface{}
+			if err := dec.Decode(&obj); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid JSON in record %d: %w", len(objects)+1, err)
+			}
+			objects = append(objects, obj)
+		}
+	}
+
+	rows := make([]map[string]string, len(objects))
+	for i, obj := range objects {
+		rows[i] = make(map[string]string)
+		flatten(rows[i], "", obj)
+	}
+	return rows, nil
+}
+
+// flatten stores the values of obj in row as strings, under dotted keys for
+// nested objects. Arrays of scalars become comma-separated lists.
+func flatten(row map[string]string, prefix string, obj map[string]interface{}) {
+	for key, value := range obj {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(row, prefix+key+".", v)
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if item != nil {
+					items = append(items, fmt.Sprint(item))
+				}
+			}
+			row[prefix+key] = strings.Join(items, ",")
+		case nil:
+			row[prefix+key] = ""
+		default:
+			row[prefix+key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// readCSVRows reads a CSV file whose first row names the columns
+func readCSVRows(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty CSV: missing header row")
+	}
+
+	header := records[0]
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // Spreadsheet exports often start with a BOM
+	}
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				row[strings.TrimSpace(column)] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// fromRows converts generic records through opts.Mapping
+func fromRows(rows []map[string]string, opts Options) (*Result, error) {
+	if opts.IssuePrefix == "" {
+		return nil, fmt.Errorf("an issue prefix is required to generate IDs")
+	}
+	res := &Result{Graph: &types.ExportGraph{}}
+	graph := res.Graph
+	m := opts.Mapping
+	for i, row := range rows {
+		get := func(field string) string {
+			return strings.TrimSpace(row[m.source(field)])
+		}
+		n := i + 1
+
+		issue, err := rowToIssue(get, opts.Now)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", n, err)
+		}
+		if issue.ID == "" {
+			issue.ID = generatedID(opts.IssuePrefix, n, issue.Title)
+		}
+		graph.Issues = append(graph.Issues, issue)
+		graph.Events = append(graph.Events, createdEvent(issue, "a backlog file", opts.Actor))
+		addLabels(graph, issue.ID, strings.FieldsFunc(get(FieldLabels), func(r rune) bool {
+			return r == ',' || r == ';'
+		}))
+		if parent := get(FieldParent); parent != "" {
+			graph.Dependencies = append(graph.Dependencies, &types.Dependency{
+				IssueID:     issue.ID,
+				DependsOnID: parent,
+				Type:        types.DepParentChild,
+				CreatedAt:   issue.CreatedAt,
+				CreatedBy:   opts.Actor,
+			})
+		}
+	}
+	return res, nil
+}
+
+// rowToIssue builds an issue from a record's values
+func rowToIssue(get func(field string) string, now time.Time) (*types.Issue, error) {
+	title := get(FieldTitle)
+	if title == "" {
+		return nil, fmt.Errorf("no title (map one with --map title=<field>)")
+	}
+	status, err := parseStatus(get(FieldStatus))
+	if err != nil {
+		return nil, err
+	}
+	priority, err := parsePriority(get(FieldPriority))
+	if err != nil {
+		return nil, err
+	}
+	issueType, err := parseIssueType(get(FieldIssueType))
+	if err != nil {
+		return nil, err
+	}
+	issue := &types.Issue{
+		ID:                 get(FieldID),
+		Title:              title,
+		Description:        get(FieldDescription),
+		Design:             get(FieldDesign),
+		AcceptanceCriteria: get(FieldAcceptanceCriteria),
+		Notes:              get(FieldNotes),
+		Status:             status,
+		Priority:           priority,
+		IssueType:          issueType,
+		Assignee:           get(FieldAssignee),
+	}
+	if v := get(FieldEstimatedMinutes); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes < 0 {
+			return nil, fmt.Errorf("invalid estimated_minutes %q", v)
+		}
+		issue.EstimatedMinutes = &minutes
+	}
+	if issue.CreatedAt, err = parseTime(get(FieldCreatedAt)); err != nil {
+		return nil, err
+	}
+	if v := get(FieldClosedAt); v != "" && status == types.StatusClosed {
+		closedAt, err := parseTime(v)
+		if err != nil {
+			return nil, err
+		}
+		issue.ClosedAt = &closedAt
+	}
+	fillTimestamps(issue, now)
+	return issue, nil
+}
+
+// generatedID derives a stable ID for the nth record, so re-importing the
+// same file skips the issues it already created
+func generatedID(prefix string, n int, title string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s", n, title)))
+	return prefix + "-" + hex.EncodeToString(sum[:4])
+}
+
+// fillTimestamps defaults missing timestamps, and sets closed_at exactly
+// when the issue is closed, as the schema requires
+func fillTimestamps(issue *types.Issue, now time.Time) {
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = now
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = issue.CreatedAt
+	}
+	if issue.Status != types.StatusClosed {
+		issue.ClosedAt = nil
+	} else if issue.ClosedAt == nil {
+		closedAt := issue.UpdatedAt
+		issue.ClosedAt = &closedAt
+	}
+}
+
+// parseStatus maps common tracker statuses onto VC's
+func parseStatus(v string) (types.Status, error) {
+	switch normalize(v) {
+	case "", "open", "new", "todo", "to_do", "backlog", "ready", "reopened":
+		return types.StatusOpen, nil
+	case "in_progress", "doing", "started", "active", "in_review", "review":
+		return types.StatusInProgress, nil
+	case "blocked", "on_hold", "waiting":
+		return types.StatusBlocked, nil
+	case "closed", "done", "resolved", "complete", "completed", "fixed", "wontfix", "won't_fix", "cancelled", "canceled":
+		return types.StatusClosed, nil
+	}
+	return "", fmt.Errorf("unknown status %q", v)
+}
+
+// parseIssueType maps common tracker issue types onto VC's
+func parseIssueType(v string) (types.IssueType, error) {
+	switch normalize(v) {
+	case "", "task", "subtask", "sub_task", "todo":
+		return types.TypeTask, nil
+	case "bug", "defect", "incident":
+		return types.TypeBug, nil
+	case "feature", "story", "user_story", "enhancement", "improvement":
+		return types.TypeFeature, nil
+	case "epic", "initiative":
+		return types.TypeEpic, nil
+	case "chore", "maintenance":
+		return types.TypeChore, nil
+	}
+	return "", fmt.Errorf("unknown issue type %q", v)
+}
+
+// parsePriority accepts 0-4, P0-P4 and priority names (critical, high,
+// medium, low, lowest). Empty means the default, 2.
+func parsePriority(v string) (int, error) {
+	switch normalize(v) {
+	case "":
+		return 2, nil
+	case "critical", "urgent", "highest", "blocker":
+		return 0, nil
+	case "high", "major":
+		return 1, nil
+	case "medium", "normal":
+		return 2, nil
+	case "low", "minor":
+		return 3, nil
+	case "lowest", "trivial":
+		return 4, nil
+	}
+	p, err := strconv.Atoi(strings.TrimPrefix(normalize(v), "p"))
+	if err != nil || p < 0 || p > 4 {
+		return 0, fmt.Errorf("invalid priority %q (must be 0-4, P0-P4, or critical/high/medium/low/lowest)", v)
+	}
+	return p, nil
+}
+
+// timeLayouts are the timestamp formats parseTime accepts
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseTime parses a timestamp; empty means unset
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q (use RFC 3339 or YYYY-MM-DD)", v)
+}
+
+// normalize lowercases v and joins its words with underscores
+func normalize(v string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(v, "-", " "))), "_")
+}
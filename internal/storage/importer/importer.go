@@ -0,0 +1,141 @@
+// Package importer converts backlogs kept in other issue stores into VC
+// issue graphs, so adopting VC doesn't start from an empty database.
+//
+// Every source is converted to a types.ExportGraph, written with the export
+// package and loaded with Storage.Import. That keeps the import itself
+// all-or-nothing and idempotent: issues whose ID already exists are left
+// untouched, so importing the same backlog twice is a no-op.
+//
+// Supported sources:
+//   - beads: the issues.jsonl file Beads keeps in .beads/, with labels,
+//     dependencies and comments
+//   - json: an array of objects (or one object per line)
+//   - csv: a header row followed by one issue per row
+//
+// The generic json and csv sources map VC fields to source fields with a
+// Mapping; fields not mapped are looked up under their VC name.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Source formats
+const (
+	SourceBeads = "beads"
+	SourceJSON  = "json"
+	SourceCSV   = "csv"
+)
+
+// Sources lists the supported source formats
+var Sources = []string{SourceBeads, SourceJSON, SourceCSV}
+
+// Options controls a conversion
+type Options struct {
+	// Mapping maps VC fields to source fields (json and csv only)
+	Mapping Mapping
+	// IssuePrefix prefixes the IDs generated for records without one
+	IssuePrefix string
+	// Actor is recorded as the creator of imported issues and dependencies
+	Actor string
+	// Now stamps records without timestamps (time.Now() if zero)
+	Now time.Time
+}
+
+// Result is a converted backlog, ready to be written with export.Write
+type Result struct {
+	Graph *types.ExportGraph
+	// Warnings lists what could not be carried over, such as dependencies
+	// on issues outside the backlog
+	Warnings []string
+}
+
+// Convert reads a backlog in the given source format
+func Convert(source string, r io.Reader, opts Options) (*Result, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.Actor == "" {
+		opts.Actor = "import"
+	}
+
+	var res *Result
+	var err error
+	switch source {
+	case SourceBeads:
+		res, err = fromBeads(r, opts)
+	case SourceJSON:
+		var rows []map[string]string
+		if rows, err = readJSONRows(r); err == nil {
+			res, err = fromRows(rows, opts)
+		}
+	case SourceCSV:
+		var rows []map[string]string
+		if rows, err = readCSVRows(r); err == nil {
+			res, err = fromRows(rows, opts)
+		}
+	default:
+		return nil, fmt.Errorf("unknown import source %q (must be one of %s)", source, strings.Join(Sources, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Warnings = append(res.Warnings, dropDanglingDependencies(res.Graph)...)
+	if err := export.Validate(res.Graph); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// dropDanglingDependencies removes dependencies on issues outside the graph,
+// which Import would reject, and describes each one removed
+func dropDanglingDependencies(graph *types.ExportGraph) []string {
+	ids := make(map[string]bool, len(graph.Issues))
+	for _, issue := range graph.Issues {
+		ids[issue.ID] = true
+	}
+	var warnings []string
+	kept := graph.Dependencies[:0]
+	for _, dep := range graph.Dependencies {
+		if ids[dep.IssueID] && ids[dep.DependsOnID] {
+			kept = append(kept, dep)
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("dropped %s dependency %s -> %s: %s is not in the backlog",
+			dep.Type, dep.IssueID, dep.DependsOnID, dep.DependsOnID))
+	}
+	graph.Dependencies = kept
+	return warnings
+}
+
+// addLabels attaches labels to an issue in graph, skipping blanks and duplicates
+func addLabels(graph *types.ExportGraph, issueID string, labels []string) {
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		graph.Labels = append(graph.Labels, &types.IssueLabel{IssueID: issueID, Label: label})
+	}
+}
+
+// createdEvent records that an issue was imported from source
+func createdEvent(issue *types.Issue, source, actor string) *types.Event {
+	comment := "Imported from " + source
+	return &types.Event{
+		IssueID:   issue.ID,
+		EventType: types.EventCreated,
+		Actor:     actor,
+		Comment:   &comment,
+		CreatedAt: issue.CreatedAt,
+	}
+}
@@ -0,0 +1,111 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestConvertBeads(t *testing.T) {
+	input := `{"id":"bd-1","title":"Epic","status":"open","priority":1,"issue_type":"epic","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-02T00:00:00Z","labels":["backend","backend"]}
+{"id":"bd-2","title":"Child","status":"closed","priority":3,"issue_type":"task","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-02T00:00:00Z","dependencies":[{"issue_id":"bd-2","depends_on_id":"bd-1","type":"parent-child"},{"issue_id":"bd-2","depends_on_id":"bd-99","type":"blocks"}],"comments":[{"author":"alice","text":"Looks good","created_at":"2025-01-02T00:00:00Z"}]}
+`
+	res, err := Convert(SourceBeads, strings.NewReader(input), Options{})
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	graph := res.Graph
+	if len(graph.Issues) != 2 || graph.Issues[1].Priority != 3 || graph.Issues[1].ClosedAt == nil {
+		t.Fatalf("unexpected issues %+v", graph.Issues)
+	}
+	if len(graph.Dependencies) != 1 || graph.Dependencies[0].Type != types.DepParentChild {
+		t.Errorf("expected only the parent link to survive, got %+v", graph.Dependencies)
+	}
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "bd-99") {
+		t.Errorf("expected a warning about the dangling dependency, got %v", res.Warnings)
+	}
+	if len(graph.Labels) != 1 {
+		t.Errorf("expected duplicate labels to collapse, got %+v", graph.Labels)
+	}
+	comments := 0
+	for _, event := range graph.Events {
+		if event.EventType == types.EventCommented && event.Actor == "alice" {
+			comments++
+		}
+	}
+	if comments != 1 {
+		t.Errorf("expected the comment to become an event, got %d", comments)
+	}
+}
+
+func TestConvertCSV(t *testing.T) {
+	input := "\ufeffKey,Summary,Status,Priority,Type,Tags,Parent\n" +
+		"T-1,Login page,To Do,High,Story,ui;auth,\n" +
+		"T-2,Fix crash,Done,P0,Bug,,T-1\n" +
+		",Untracked idea,,,,,\n"
+	mapping, err := ParseMapping([]string{"id=Key", "title=Summary", "status=Status", "priority=Priority",
+		"issue_type=Type", "labels=Tags", "parent=Parent"})
+	if err != nil {
+		t.Fatalf("ParseMapping failed: %v", err)
+	}
+	res, err := Convert(SourceCSV, strings.NewReader(input), Options{Mapping: mapping, IssuePrefix: "vc"})
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	issues := res.Graph.Issues
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(issues))
+	}
+	if issues[0].ID != "T-1" || issues[0].IssueType != types.TypeFeature || issues[0].Priority != 1 {
+		t.Errorf("unexpected first issue %+v", issues[0])
+	}
+	if issues[1].Status != types.StatusClosed || issues[1].ClosedAt == nil || issues[1].Priority != 0 {
+		t.Errorf("expected a closed P0 bug, got %+v", issues[1])
+	}
+	if !strings.HasPrefix(issues[2].ID, "vc-") || issues[2].Priority != 2 || issues[2].Status != types.StatusOpen {
+		t.Errorf("expected defaults and a generated ID, got %+v", issues[2])
+	}
+	if len(res.Graph.Labels) != 2 || len(res.Graph.Dependencies) != 1 || res.Graph.Dependencies[0].DependsOnID != "T-1" {
+		t.Errorf("expected two labels and a parent link, got %+v / %+v", res.Graph.Labels, res.Graph.Dependencies)
+	}
+
+	// Generated IDs are stable, so a re-import skips what it created
+	again, err := Convert(SourceCSV, strings.NewReader(input), Options{Mapping: mapping, IssuePrefix: "vc"})
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if again.Graph.Issues[2].ID != issues[2].ID {
+		t.Errorf("expected a stable generated ID, got %s and %s", issues[2].ID, again.Graph.Issues[2].ID)
+	}
+}
+
+func TestConvertJSON(t *testing.T) {
+	input := `[{"key":"J-1","fields":{"summary":"Nested","labels":["a","b"],"priority":{"name":"Low"}}}]`
+	mapping, _ := ParseMapping([]string{"id=key", "title=fields.summary", "labels=fields.labels", "priority=fields.priority.name"})
+	res, err := Convert(SourceJSON, strings.NewReader(input), Options{Mapping: mapping, IssuePrefix: "vc"})
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if len(res.Graph.Issues) != 1 || res.Graph.Issues[0].Title != "Nested" || res.Graph.Issues[0].Priority != 3 {
+		t.Errorf("unexpected issues %+v", res.Graph.Issues)
+	}
+	if len(res.Graph.Labels) != 2 {
+		t.Errorf("expected two labels, got %+v", res.Graph.Labels)
+	}
+
+	// JSON lines work too; an unknown status is an error
+	_, err = Convert(SourceJSON, strings.NewReader("{\"title\":\"A\"}\n{\"title\":\"B\",\"status\":\"limbo\"}\n"), Options{IssuePrefix: "vc"})
+	if err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("expected an error for record 2, got %v", err)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	if _, err := ParseMapping([]string{"summary=title"}); err == nil {
+		t.Error("expected an unknown VC field to be rejected")
+	}
+	if _, err := ParseMapping([]string{"title"}); err == nil {
+		t.Error("expected a pair without = to be rejected")
+	}
+}
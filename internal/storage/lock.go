@@ -86,6 +86,30 @@ func ReleaseExclusiveLock(lockPath string) error {
 	return nil
 }
 
+// ExclusiveLockHolder reports whether the exclusive lock for dbPath is held by
+// a live process, returning the lock if so. Stale and unreadable locks are
+// reported as not held.
+func ExclusiveLockHolder(dbPath string) (*ExclusiveLock, bool) {
+	projectRoot, err := GetProjectRoot(dbPath)
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectRoot, ".beads", ".exclusive-lock"))
+	if err != nil {
+		return nil, false
+	}
+
+	var lock ExclusiveLock
+	if json.Unmarshal(data, &lock) != nil {
+		return nil, false
+	}
+	if !isProcessAlive(lock.PID, lock.Hostname) {
+		return nil, false
+	}
+	return &lock, true
+}
+
 // isProcessAlive checks if a process with the given PID exists on the given hostname.
 // Returns true if the process is alive, false otherwise.
 // This is a simplified version of the Beads implementation.
@@ -0,0 +1,312 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// AGENT EVENTS
+// ======================================================================
+
+// StoreAgentEvent stores an agent event, assigning it a new ID
+func (s *Store) StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error {
+	stored, err := copyAgentEvent(event)
+	if err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	s.appendAgentEventLocked(stored)
+	return nil
+}
+
+// storeAgentEventLocked stores an event generated by the store itself,
+// logging (rather than returning) failures. Caller must hold s.mu.
+func (s *Store) storeAgentEventLocked(event *events.AgentEvent) {
+	stored, err := copyAgentEvent(event)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to store %s event for %s: %v\n", event.Type, event.IssueID, err)
+		return
+	}
+	s.appendAgentEventLocked(stored)
+}
+
+func (s *Store) appendAgentEventLocked(event *events.AgentEvent) {
+	s.nextAgentEventID++
+	event.ID = strconv.FormatInt(s.nextAgentEventID, 10)
+	s.agentEvents = append(s.agentEvents, event)
+}
+
+// GetAgentEvents returns agent events matching the filter, newest first
+func (s *Store) GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*events.AgentEvent
+	for _, event := range s.agentEvents {
+		if filter.IssueID != "" && event.IssueID != filter.IssueID {
+			continue
+		}
+		if filter.Type != "" && event.Type != filter.Type {
+			continue
+		}
+		if filter.Severity != "" && event.Severity != filter.Severity {
+			continue
+		}
+		if !filter.AfterTime.IsZero() && !event.Timestamp.After(filter.AfterTime) {
+			continue
+		}
+		if !filter.BeforeTime.IsZero() && event.Timestamp.After(filter.BeforeTime) {
+			continue
+		}
+		result = append(result, event)
+	}
+	sortAgentEvents(result, false)
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return copyAgentEvents(result)
+}
+
+// GetAgentEventsByIssue returns an issue's agent events, oldest first
+func (s *Store) GetAgentEventsByIssue(ctx context.Context, issueID string) ([]*events.AgentEvent, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*events.AgentEvent
+	for _, event := range s.agentEvents {
+		if event.IssueID == issueID {
+			result = append(result, event)
+		}
+	}
+	sortAgentEvents(result, true)
+	return copyAgentEvents(result)
+}
+
+// GetRecentAgentEvents returns the most recent agent events, newest first
+func (s *Store) GetRecentAgentEvents(ctx context.Context, limit int) ([]*events.AgentEvent, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	result := append([]*events.AgentEvent(nil), s.agentEvents...)
+	sortAgentEvents(result, false)
+	if limit >= 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return copyAgentEvents(result)
+}
+
+// sortAgentEvents orders events by timestamp. Events are stored in insertion
+// order, so a stable sort keeps ties in the order they were stored.
+func sortAgentEvents(list []*events.AgentEvent, ascending bool) {
+	if !ascending {
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if ascending {
+			return list[i].Timestamp.Before(list[j].Timestamp)
+		}
+		return list[i].Timestamp.After(list[j].Timestamp)
+	})
+}
+
+func copyAgentEvents(list []*events.AgentEvent) ([]*events.AgentEvent, error) {
+	result := make([]*events.AgentEvent, 0, len(list))
+	for _, event := range list {
+		eventCopy, err := copyAgentEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, eventCopy)
+	}
+	return result, nil
+}
+
+// ======================================================================
+// AGENT EVENT RETENTION
+// ======================================================================
+
+// isCriticalSeverity reports whether events of this severity get the longer retention
+func isCriticalSeverity(severity events.EventSeverity) bool {
+	return severity == events.SeverityError || severity == events.SeverityCritical
+}
+
+// CleanupEventsByAge deletes info/warning events older than retentionDays and
+// error/critical events older than criticalRetentionDays
+func (s *Store) CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error) {
+	if retentionDays < 0 || criticalRetentionDays < 0 {
+		return 0, fmt.Errorf("retention days cannot be negative")
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1")
+	}
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	regularCutoff := now.AddDate(0, 0, -retentionDays)
+	criticalCutoff := now.AddDate(0, 0, -criticalRetentionDays)
+
+	return s.deleteAgentEventsLocked(func(event *events.AgentEvent) bool {
+		switch event.Severity {
+		case events.SeverityInfo, events.SeverityWarning:
+			return event.Timestamp.Before(regularCutoff)
+		case events.SeverityError, events.SeverityCritical:
+			return criticalRetentionDays != retentionDays && event.Timestamp.Before(criticalCutoff)
+		}
+		return false
+	}), nil
+}
+
+// CleanupEventsByIssueLimit keeps at most perIssueLimit events per issue,
+// deleting the oldest non-critical events first (0 means unlimited)
+func (s *Store) CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error) {
+	if perIssueLimit < 0 {
+		return 0, fmt.Errorf("per-issue limit cannot be negative")
+	}
+	if perIssueLimit == 0 {
+		return 0, nil
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1")
+	}
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, event := range s.agentEvents {
+		if event.IssueID != "" {
+			counts[event.IssueID]++
+		}
+	}
+	excess := make(map[string]int)
+	for issueID, count := range counts {
+		if count > perIssueLimit {
+			excess[issueID] = count - perIssueLimit
+		}
+	}
+
+	return s.deleteOldestLocked(func(event *events.AgentEvent) bool {
+		if event.IssueID == "" || excess[event.IssueID] == 0 || isCriticalSeverity(event.Severity) {
+			return false
+		}
+		excess[event.IssueID]--
+		return true
+	}), nil
+}
+
+// CleanupEventsByGlobalLimit deletes the oldest non-critical events until at
+// most globalLimit events remain
+func (s *Store) CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error) {
+	if globalLimit < 1 {
+		return 0, fmt.Errorf("global limit must be at least 1")
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1")
+	}
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	excess := len(s.agentEvents) - globalLimit
+	if excess <= 0 {
+		return 0, nil
+	}
+	return s.deleteOldestLocked(func(event *events.AgentEvent) bool {
+		if excess == 0 || isCriticalSeverity(event.Severity) {
+			return false
+		}
+		excess--
+		return true
+	}), nil
+}
+
+// deleteOldestLocked offers events to shouldDelete oldest first and deletes
+// the ones it accepts. Caller must hold s.mu.
+func (s *Store) deleteOldestLocked(shouldDelete func(*events.AgentEvent) bool) int {
+	byAge := append([]*events.AgentEvent(nil), s.agentEvents...)
+	sortAgentEvents(byAge, true)
+	doomed := make(map[*events.AgentEvent]bool)
+	for _, event := range byAge {
+		if shouldDelete(event) {
+			doomed[event] = true
+		}
+	}
+	return s.deleteAgentEventsLocked(func(event *events.AgentEvent) bool { return doomed[event] })
+}
+
+// deleteAgentEventsLocked deletes matching events. Caller must hold s.mu.
+func (s *Store) deleteAgentEventsLocked(match func(*events.AgentEvent) bool) int {
+	kept := s.agentEvents[:0]
+	deleted := 0
+	for _, event := range s.agentEvents {
+		if match(event) {
+			deleted++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	for i := len(kept); i < len(s.agentEvents); i++ {
+		s.agentEvents[i] = nil
+	}
+	s.agentEvents = kept
+	return deleted
+}
+
+// GetEventCounts returns agent event counts by issue, severity and type
+func (s *Store) GetEventCounts(ctx context.Context) (*types.EventCounts, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	counts := &types.EventCounts{
+		TotalEvents:      len(s.agentEvents),
+		EventsByIssue:    make(map[string]int),
+		EventsBySeverity: make(map[string]int),
+		EventsByType:     make(map[string]int),
+	}
+	for _, event := range s.agentEvents {
+		counts.EventsByIssue[event.IssueID]++
+		severity := string(event.Severity)
+		if severity == "" {
+			severity = "unknown"
+		}
+		counts.EventsBySeverity[severity]++
+		counts.EventsByType[string(event.Type)]++
+	}
+	return counts, nil
+}
+
+// VacuumDatabase is a no-op: there is no file to compact
+func (s *Store) VacuumDatabase(ctx context.Context) error {
+	return nil
+}
+
+// AnalyzeDatabase is a no-op: there is no query planner
+func (s *Store) AnalyzeDatabase(ctx context.Context) error {
+	return nil
+}
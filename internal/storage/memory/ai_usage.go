@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// AI USAGE
+// ======================================================================
+
+// RecordAIUsage records an AI supervisor call. An IssueID that isn't a known
+// issue is stored as empty.
+func (s *Store) RecordAIUsage(ctx context.Context, usage *types.AIUsage) error {
+	if err := usage.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	stored := *usage
+	if _, ok := s.issues[stored.IssueID]; !ok {
+		stored.IssueID = ""
+	}
+	s.aiUsage = append(s.aiUsage, &stored)
+	return nil
+}
+
+// ListAIUsage returns the usage records matching filter, oldest first
+func (s *Store) ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.AIUsage
+	for _, usage := range s.aiUsage {
+		if filter.IssueID != "" && usage.IssueID != filter.IssueID {
+			continue
+		}
+		if !filter.Since.IsZero() && usage.Timestamp.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && !usage.Timestamp.Before(filter.Until) {
+			continue
+		}
+		c := *usage
+		result = append(result, &c)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ARCHIVE
+// ======================================================================
+
+// ArchiveIssues moves issues closed before opts.ClosedBefore out of the
+// store, writing them with their history to w as a dump that Import
+// restores. Issues linked by a dependency to one that stays, and issues with
+// attachments or custom field values, are kept.
+func (s *Store) ArchiveIssues(ctx context.Context, opts types.ArchiveOptions, w io.Writer) (*types.ArchiveStats, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	candidates := make(map[string]bool)
+	pinned := 0
+	for id, issue := range s.issues {
+		if issue.Status != types.StatusClosed || issue.ClosedAt == nil || !issue.ClosedAt.Before(opts.ClosedBefore) {
+			continue
+		}
+		if len(s.attachmentsForIssueLocked(id)) > 0 || len(s.fieldVals[id]) > 0 {
+			pinned++
+			continue
+		}
+		candidates[id] = true
+	}
+
+	graph := s.exportGraphLocked()
+	archived := export.ArchiveSet(candidates, graph.Dependencies)
+	archive := export.Subgraph(graph, archived)
+	stats := export.ArchiveStatsFor(archive)
+	stats.Kept = len(candidates) - len(archived) + pinned
+	if opts.DryRun || len(archived) == 0 {
+		return stats, nil
+	}
+
+	if err := export.Write(w, archive); err != nil {
+		return nil, err
+	}
+	if f, ok := w.(interface{ Sync() error }); ok {
+		if err := f.Sync(); err != nil {
+			return nil, fmt.Errorf("failed to sync archive: %w", err)
+		}
+	}
+	for id := range archived {
+		if err := s.deleteIssueLocked(id); err != nil {
+			return nil, err
+		}
+	}
+	s.notifyLocked()
+	return stats, nil
+}
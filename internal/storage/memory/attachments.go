@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ATTACHMENTS
+// ======================================================================
+
+// AddAttachment stores data and attaches it to attachment.IssueID, filling
+// in ID, ContentHash, Size and CreatedAt
+func (s *Store) AddAttachment(ctx context.Context, attachment *types.Attachment, data []byte) error {
+	if err := attachment.Validate(); err != nil {
+		return fmt.Errorf("invalid attachment: %w", err)
+	}
+	if len(data) > types.MaxAttachmentSize {
+		return fmt.Errorf("attachment %q is %d bytes, exceeds limit of %d", attachment.Name, len(data), types.MaxAttachmentSize)
+	}
+
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.issues[attachment.IssueID]; !ok {
+		return fmt.Errorf("failed to add attachment to %s: issue not found", attachment.IssueID)
+	}
+	if attachment.ExecutionID != nil && s.findAttemptLocked(*attachment.ExecutionID) == nil {
+		return fmt.Errorf("failed to add attachment to %s: execution attempt %d not found",
+			attachment.IssueID, *attachment.ExecutionID)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if _, exists := s.blobs[hash]; !exists {
+		s.blobs[hash] = append([]byte(nil), data...)
+	}
+
+	s.nextAttachmentID++
+	attachment.ID = s.nextAttachmentID
+	attachment.ContentHash = hash
+	attachment.Size = int64(len(data))
+	if attachment.CreatedAt.IsZero() {
+		attachment.CreatedAt = time.Now()
+	}
+	stored := *attachment
+	s.attachments = append(s.attachments, &stored)
+	return nil
+}
+
+// GetAttachment returns an attachment and its content, or nil if it doesn't exist
+func (s *Store) GetAttachment(ctx context.Context, id int64) (*types.Attachment, []byte, error) {
+	if err := s.lock(); err != nil {
+		return nil, nil, err
+	}
+	defer s.mu.Unlock()
+
+	for _, attachment := range s.attachments {
+		if attachment.ID == id {
+			attachmentCopy := *attachment
+			return &attachmentCopy, append([]byte(nil), s.blobs[attachment.ContentHash]...), nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// ListAttachments returns attachment metadata for an issue, oldest first
+func (s *Store) ListAttachments(ctx context.Context, issueID string) ([]*types.Attachment, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Attachment
+	for _, attachment := range s.attachmentsForIssueLocked(issueID) {
+		attachmentCopy := *attachment
+		result = append(result, &attachmentCopy)
+	}
+	return result, nil
+}
+
+// DeleteAttachment removes an attachment, and its content if nothing else
+// references it
+func (s *Store) DeleteAttachment(ctx context.Context, id int64) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if !s.deleteAttachmentLocked(id) {
+		return fmt.Errorf("attachment %d not found", id)
+	}
+	return nil
+}
+
+// attachmentsForIssueLocked returns the (uncopied) attachments of an issue,
+// oldest first. Caller must hold s.mu.
+func (s *Store) attachmentsForIssueLocked(issueID string) []*types.Attachment {
+	var result []*types.Attachment
+	for _, attachment := range s.attachments {
+		if attachment.IssueID == issueID {
+			result = append(result, attachment)
+		}
+	}
+	return result
+}
+
+// deleteAttachmentLocked removes an attachment and drops its content once
+// unreferenced. Reports whether the attachment existed. Caller must hold s.mu.
+func (s *Store) deleteAttachmentLocked(id int64) bool {
+	for i, attachment := range s.attachments {
+		if attachment.ID != id {
+			continue
+		}
+		s.attachments = append(s.attachments[:i:i], s.attachments[i+1:]...)
+		for _, other := range s.attachments {
+			if other.ContentHash == attachment.ContentHash {
+				return true
+			}
+		}
+		delete(s.blobs, attachment.ContentHash)
+		return true
+	}
+	return false
+}
+
+// findAttemptLocked returns the execution attempt with the given ID, or nil.
+// Caller must hold s.mu.
+func (s *Store) findAttemptLocked(id int64) *types.ExecutionAttempt {
+	for _, attempt := range s.attempts {
+		if attempt.ID == id {
+			return attempt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// CLONING
+// ======================================================================
+
+// CloneIssue copies an issue, and optionally its subtree and dependencies,
+// as new open issues. Execution history, events and attachments stay with
+// the originals. The clone is all or nothing.
+func (s *Store) CloneIssue(ctx context.Context, id string, opts types.CloneOptions, actor string) (*types.CloneResult, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.issues[id]; !ok {
+		return nil, fmt.Errorf("issue %s not found", id)
+	}
+
+	// Originals, parents before children
+	order := []string{id}
+	if opts.IncludeChildren {
+		seen := map[string]bool{id: true}
+		for i := 0; i < len(order); i++ {
+			for _, dep := range s.deps {
+				if dep.Type == types.DepParentChild && dep.DependsOnID == order[i] && !seen[dep.IssueID] {
+					seen[dep.IssueID] = true
+					order = append(order, dep.IssueID)
+				}
+			}
+		}
+	}
+
+	result := &types.CloneResult{IDs: make(map[string]string, len(order))}
+	err := s.atomically(func() error {
+		for _, original := range order {
+			clone := types.NewClone(s.issues[original], opts)
+			if err := s.createIssueLocked(clone, actor); err != nil {
+				return fmt.Errorf("failed to clone %s: %w", original, err)
+			}
+			result.IDs[original] = clone.ID
+			if mission := s.missions[original]; mission != nil {
+				s.missions[clone.ID] = &missionState{
+					Goal:             mission.Goal,
+					Context:          mission.Context,
+					ApprovalRequired: mission.ApprovalRequired,
+				}
+			}
+
+			for name, value := range s.fieldVals[original] {
+				if s.fieldVals[clone.ID] == nil {
+					s.fieldVals[clone.ID] = make(map[string]string)
+				}
+				s.fieldVals[clone.ID][name] = value
+			}
+
+			labels := make([]string, 0, len(s.labels[original]))
+			for label := range s.labels[original] {
+				if types.CopiesLabelOnClone(label) {
+					labels = append(labels, label)
+				}
+			}
+			sort.Strings(labels)
+			for _, label := range labels {
+				if err := s.addLabelLocked(clone.ID, label, actor); err != nil {
+					return err
+				}
+			}
+		}
+
+		var deps []*types.Dependency
+		for _, dep := range s.deps {
+			cloneID, ok := result.IDs[dep.IssueID]
+			if !ok {
+				continue
+			}
+			if dep.ClonedWith(result.IDs, opts) {
+				target := dep.DependsOnID
+				if targetClone, ok := result.IDs[target]; ok {
+					target = targetClone
+				}
+				deps = append(deps, &types.Dependency{IssueID: cloneID, DependsOnID: target, Type: dep.Type})
+			}
+		}
+		for _, dep := range deps {
+			if err := s.addDependencyLocked(dep, actor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Root = s.getIssueLocked(result.IDs[id])
+	return result, nil
+}
@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// commentState is what threading adds to a "commented" event (the
+// equivalent of the vc_comment_* tables)
+type commentState struct {
+	parentID  *int64
+	body      *string // Current body, if edited
+	edits     []*types.CommentEdit
+	reactions []*types.CommentReaction
+}
+
+// copy returns a copy whose slices can be appended to independently.
+// Edits and reactions are never modified in place.
+func (c *commentState) copy() *commentState {
+	return &commentState{
+		parentID:  c.parentID,
+		body:      c.body,
+		edits:     append([]*types.CommentEdit(nil), c.edits...),
+		reactions: append([]*types.CommentReaction(nil), c.reactions...),
+	}
+}
+
+// GetComments returns an issue's comments oldest first, flat, with their
+// parent, edit history and reactions
+func (s *Store) GetComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var comments []*types.Comment
+	for _, event := range s.events {
+		if event.IssueID == issueID && event.EventType == types.EventCommented {
+			comments = append(comments, s.commentLocked(event))
+		}
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+	return comments, nil
+}
+
+// commentLocked builds the comment for a "commented" event. Caller must hold s.mu.
+func (s *Store) commentLocked(event *types.Event) *types.Comment {
+	c := &types.Comment{
+		ID:        event.ID,
+		IssueID:   event.IssueID,
+		Author:    event.Actor,
+		CreatedAt: event.CreatedAt,
+	}
+	if event.Comment != nil {
+		c.Body = *event.Comment
+	}
+	state := s.comments[event.ID]
+	if state == nil {
+		return c
+	}
+	if state.parentID != nil {
+		parentID := *state.parentID
+		c.ParentID = &parentID
+	}
+	if state.body != nil {
+		c.Body = *state.body
+	}
+	for _, edit := range state.edits {
+		editCopy := *edit
+		c.Edits = append(c.Edits, &editCopy)
+	}
+	if n := len(c.Edits); n > 0 {
+		editedAt := c.Edits[n-1].EditedAt
+		c.EditedAt = &editedAt
+	}
+	for _, reaction := range state.reactions {
+		reactionCopy := *reaction
+		c.Reactions = append(c.Reactions, &reactionCopy)
+	}
+	return c
+}
+
+// findCommentLocked returns the "commented" event with the given ID.
+// Caller must hold s.mu.
+func (s *Store) findCommentLocked(commentID int64) (*types.Event, error) {
+	for _, event := range s.events {
+		if event.ID == commentID && event.EventType == types.EventCommented {
+			return event, nil
+		}
+	}
+	return nil, fmt.Errorf("comment %d not found", commentID)
+}
+
+// commentStateLocked returns the state of a comment, creating it if needed.
+// Caller must hold s.mu.
+func (s *Store) commentStateLocked(commentID int64) *commentState {
+	state := s.comments[commentID]
+	if state == nil {
+		state = &commentState{}
+		s.comments[commentID] = state
+	}
+	return state
+}
+
+// forgetCommentLocked drops the threading state of a deleted event. Replies
+// to it become top-level comments. Caller must hold s.mu.
+func (s *Store) forgetCommentLocked(eventID int64) {
+	delete(s.comments, eventID)
+	for _, state := range s.comments {
+		if state.parentID != nil && *state.parentID == eventID {
+			state.parentID = nil
+		}
+	}
+}
+
+// ReplyToComment adds body as a comment on the parent comment's issue,
+// threaded under it
+func (s *Store) ReplyToComment(ctx context.Context, parentID int64, actor, body string) (*types.Comment, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("comment body is required")
+	}
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	parent, err := s.findCommentLocked(parentID)
+	if err != nil {
+		return nil, err
+	}
+	issue, ok := s.issues[parent.IssueID]
+	if !ok {
+		return nil, fmt.Errorf("issue %s not found", parent.IssueID)
+	}
+	issue.UpdatedAt = time.Now()
+	s.recordEventLocked(parent.IssueID, types.EventCommented, actor, nil, nil, strPtr(body))
+	reply := s.events[len(s.events)-1]
+	s.commentStateLocked(reply.ID).parentID = &parentID
+	return s.commentLocked(reply), nil
+}
+
+// EditComment replaces a comment's body with body, recording the previous
+// one. Only the comment's author can edit it.
+func (s *Store) EditComment(ctx context.Context, commentID int64, actor, body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("comment body is required")
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	event, err := s.findCommentLocked(commentID)
+	if err != nil {
+		return err
+	}
+	if event.Actor != actor {
+		return fmt.Errorf("comment %d can only be edited by its author (%s)", commentID, event.Actor)
+	}
+	current := s.commentLocked(event).Body
+	if body == current {
+		return nil
+	}
+	state := s.commentStateLocked(commentID)
+	state.edits = append(state.edits, &types.CommentEdit{PreviousBody: current, EditedBy: actor, EditedAt: time.Now()})
+	state.body = strPtr(body)
+	return nil
+}
+
+// AddReaction records actor's reaction to a comment. Reacting twice with
+// the same reaction does nothing.
+func (s *Store) AddReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	if err := types.ValidateReaction(reaction); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, err := s.findCommentLocked(commentID); err != nil {
+		return err
+	}
+	state := s.commentStateLocked(commentID)
+	for _, r := range state.reactions {
+		if r.Actor == actor && r.Reaction == reaction {
+			return nil
+		}
+	}
+	state.reactions = append(state.reactions, &types.CommentReaction{Reaction: reaction, Actor: actor, CreatedAt: time.Now()})
+	return nil
+}
+
+// RemoveReaction removes actor's reaction from a comment, if present
+func (s *Store) RemoveReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	state := s.comments[commentID]
+	if state == nil {
+		return nil
+	}
+	var kept []*types.CommentReaction
+	for _, r := range state.reactions {
+		if r.Actor != actor || r.Reaction != reaction {
+			kept = append(kept, r)
+		}
+	}
+	state.reactions = kept
+	return nil
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// EXECUTION OUTPUT
+// ======================================================================
+
+// AppendExecutionOutput stores output lines and sets their IDs
+func (s *Store) AppendExecutionOutput(ctx context.Context, lines []*types.OutputLine) error {
+	for _, line := range lines {
+		if err := line.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	for _, line := range lines {
+		if s.executionLocked(line.ExecutionID) == nil {
+			return fmt.Errorf("execution %d not found", line.ExecutionID)
+		}
+	}
+	for _, line := range lines {
+		if line.Timestamp.IsZero() {
+			line.Timestamp = time.Now()
+		}
+		s.nextOutputID++
+		line.ID = s.nextOutputID
+		stored := *line
+		s.output = append(s.output, &stored)
+	}
+	return nil
+}
+
+// ListExecutionOutput returns the output lines matching filter, oldest first
+func (s *Store) ListExecutionOutput(ctx context.Context, filter types.OutputFilter) ([]*types.OutputLine, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.OutputLine
+	for _, line := range s.output {
+		if filter.IssueID != "" && line.IssueID != filter.IssueID {
+			continue
+		}
+		if filter.ExecutionID != 0 && line.ExecutionID != filter.ExecutionID {
+			continue
+		}
+		if line.ID <= filter.AfterID {
+			continue
+		}
+		c := *line
+		result = append(result, &c)
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[len(result)-filter.Limit:]
+	}
+	return result, nil
+}
+
+// CleanupExecutionOutput deletes the output of executions that finished
+// more than retentionDays ago, and returns the number of lines deleted
+func (s *Store) CleanupExecutionOutput(ctx context.Context, retentionDays int) (int, error) {
+	if retentionDays < 0 {
+		return 0, fmt.Errorf("retention days cannot be negative")
+	}
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	before := len(s.output)
+	s.deleteOutputLocked(func(line *types.OutputLine) bool {
+		execution := s.executionLocked(line.ExecutionID)
+		return execution != nil && execution.CompletedAt != nil && execution.CompletedAt.Before(cutoff)
+	})
+	return before - len(s.output), nil
+}
+
+// deleteOutputLocked deletes the output lines matching drop. Caller must
+// hold s.mu.
+func (s *Store) deleteOutputLocked(drop func(*types.OutputLine) bool) {
+	output := s.output[:0]
+	for _, line := range s.output {
+		if !drop(line) {
+			output = append(output, line)
+		}
+	}
+	s.output = output
+}
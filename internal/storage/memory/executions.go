@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// EXECUTIONS
+// ======================================================================
+
+// CreateExecution records an execution and sets its ID, and StartedAt if it
+// is zero
+func (s *Store) CreateExecution(ctx context.Context, execution *types.Execution) error {
+	if err := execution.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.issues[execution.IssueID]; !ok {
+		return fmt.Errorf("issue %s not found", execution.IssueID)
+	}
+	if execution.StartedAt.IsZero() {
+		execution.StartedAt = time.Now()
+	}
+	s.nextExecutionID++
+	execution.ID = s.nextExecutionID
+	s.executions = append(s.executions, copyExecution(execution))
+	return nil
+}
+
+// UpdateExecution saves an execution's outcome: everything but its issue and
+// start time
+func (s *Store) UpdateExecution(ctx context.Context, execution *types.Execution) error {
+	if err := execution.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	stored := s.executionLocked(execution.ID)
+	if stored == nil {
+		return fmt.Errorf("execution %d not found", execution.ID)
+	}
+	updated := copyExecution(execution)
+	updated.IssueID, updated.StartedAt = stored.IssueID, stored.StartedAt
+	*stored = *updated
+	return nil
+}
+
+// GetExecution returns an execution, or nil if it doesn't exist
+func (s *Store) GetExecution(ctx context.Context, id int64) (*types.Execution, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	if stored := s.executionLocked(id); stored != nil {
+		return copyExecution(stored), nil
+	}
+	return nil, nil
+}
+
+// ListExecutions returns the executions matching filter, newest first
+func (s *Store) ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Execution
+	for _, execution := range s.executions {
+		if filter.IssueID != "" && execution.IssueID != filter.IssueID {
+			continue
+		}
+		if filter.ExecutorInstanceID != "" && execution.ExecutorInstanceID != filter.ExecutorInstanceID {
+			continue
+		}
+		if filter.Status != "" && execution.Status != filter.Status {
+			continue
+		}
+		if !filter.Since.IsZero() && execution.StartedAt.Before(filter.Since) {
+			continue
+		}
+		if filter.CommitHash != "" && execution.CommitHash != filter.CommitHash {
+			continue
+		}
+		result = append(result, copyExecution(execution))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].StartedAt.Equal(result[j].StartedAt) {
+			return result[i].StartedAt.After(result[j].StartedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// DeleteExecution deletes an execution
+func (s *Store) DeleteExecution(ctx context.Context, id int64) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	for i, execution := range s.executions {
+		if execution.ID == id {
+			s.executions = append(s.executions[:i], s.executions[i+1:]...)
+			s.deleteOutputLocked(func(line *types.OutputLine) bool { return line.ExecutionID == id })
+			return nil
+		}
+	}
+	return fmt.Errorf("execution %d not found", id)
+}
+
+// executionLocked returns the stored execution with id, or nil. Caller must
+// hold s.mu.
+func (s *Store) executionLocked(id int64) *types.Execution {
+	for _, execution := range s.executions {
+		if execution.ID == id {
+			return execution
+		}
+	}
+	return nil
+}
+
+// copyExecution returns a copy that shares no pointers with e
+func copyExecution(e *types.Execution) *types.Execution {
+	c := *e
+	if e.ExitCode != nil {
+		exitCode := *e.ExitCode
+		c.ExitCode = &exitCode
+	}
+	if e.CompletedAt != nil {
+		completedAt := *e.CompletedAt
+		c.CompletedAt = &completedAt
+	}
+	if e.StartSnapshot != nil {
+		snapshot := *e.StartSnapshot
+		c.StartSnapshot = &snapshot
+	}
+	if e.EndSnapshot != nil {
+		snapshot := *e.EndSnapshot
+		c.EndSnapshot = &snapshot
+	}
+	return &c
+}
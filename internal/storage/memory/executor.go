@@ -0,0 +1,657 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// executorStatusCrashed marks instances that stopped heartbeating
+const executorStatusCrashed types.ExecutorStatus = "crashed"
+
+// ======================================================================
+// EXECUTOR INSTANCES
+// ======================================================================
+
+// RegisterInstance registers (or re-registers) an executor instance (vc-130)
+func (s *Store) RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	stored := *instance
+	if stored.SelfHealingMode == "" {
+		stored.SelfHealingMode = "HEALTHY"
+	}
+	s.instances[instance.InstanceID] = &stored
+	return nil
+}
+
+// MarkInstanceStopped marks an executor instance as stopped
+func (s *Store) MarkInstanceStopped(ctx context.Context, instanceID string) error {
+	return s.updateInstance(instanceID, func(instance *types.ExecutorInstance) {
+		instance.Status = types.ExecutorStatusStopped
+	})
+}
+
+// UpdateHeartbeat records a heartbeat for an executor instance
+func (s *Store) UpdateHeartbeat(ctx context.Context, instanceID string) error {
+	return s.updateInstance(instanceID, func(instance *types.ExecutorInstance) {
+		instance.LastHeartbeat = time.Now()
+	})
+}
+
+// UpdateSelfHealingMode persists an executor's self-healing mode (vc-556f)
+func (s *Store) UpdateSelfHealingMode(ctx context.Context, instanceID string, mode string) error {
+	return s.updateInstance(instanceID, func(instance *types.ExecutorInstance) {
+		instance.SelfHealingMode = mode
+	})
+}
+
+func (s *Store) updateInstance(instanceID string, update func(*types.ExecutorInstance)) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	instance, ok := s.instances[instanceID]
+	if !ok {
+		return fmt.Errorf("executor instance %s not found", instanceID)
+	}
+	update(instance)
+	return nil
+}
+
+// GetActiveInstances returns running executor instances, oldest first
+func (s *Store) GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.ExecutorInstance
+	for _, instance := range s.instances {
+		if instance.Status == types.ExecutorStatusRunning {
+			instanceCopy := *instance
+			result = append(result, &instanceCopy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartedAt.Before(result[j].StartedAt)
+	})
+	return result, nil
+}
+
+// CleanupStaleInstances releases claims held by executors that stopped
+// heartbeating (or stopped without releasing), marks stale executors crashed,
+// and returns the number of instances cleaned up
+func (s *Store) CleanupStaleInstances(ctx context.Context, staleThreshold int) (int, error) {
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	staleTime := time.Now().Add(-time.Duration(staleThreshold) * time.Second)
+
+	// Stale: running but no recent heartbeat. Orphaned: stopped but still holding claims.
+	reasons := make(map[string]string)
+	for id, instance := range s.instances {
+		switch {
+		case instance.Status == types.ExecutorStatusRunning && instance.LastHeartbeat.Before(staleTime):
+			reasons[id] = fmt.Sprintf("became stale (no heartbeat for %d seconds)", staleThreshold)
+		case instance.Status == types.ExecutorStatusStopped && s.holdsClaimsLocked(id):
+			reasons[id] = "was already stopped but claim remained (orphaned)"
+		}
+	}
+	if len(reasons) == 0 {
+		return 0, nil
+	}
+
+	for issueID, state := range s.execStates {
+		reason, ok := reasons[state.ExecutorInstanceID]
+		if !ok {
+			continue
+		}
+		instanceID := state.ExecutorInstanceID
+		state.State = types.ExecutionStatePending
+		state.ExecutorInstanceID = ""
+		state.UpdatedAt = time.Now()
+
+		if issue, ok := s.issues[issueID]; ok {
+			before := copyIssue(issue)
+			issue.Status = types.StatusOpen
+			issue.ClosedAt = nil
+			issue.UpdatedAt = time.Now()
+			s.recordAuditLocked(types.AuditEntriesFor(issueID, before, issue, "system"))
+		}
+
+		message := fmt.Sprintf("Issue automatically released - executor instance %s %s", instanceID, reason)
+		s.storeAgentEventLocked(&events.AgentEvent{
+			Type:      events.EventType("issue_released"),
+			Timestamp: time.Now(),
+			IssueID:   issueID,
+			Message:   message,
+			Data: map[string]interface{}{
+				"instance_id": instanceID,
+				"reason":      message,
+			},
+		})
+	}
+
+	for id := range reasons {
+		if s.instances[id].Status == types.ExecutorStatusRunning {
+			s.instances[id].Status = executorStatusCrashed
+		}
+	}
+	return len(reasons), nil
+}
+
+// holdsClaimsLocked reports whether an executor still owns any execution state.
+// Caller must hold s.mu.
+func (s *Store) holdsClaimsLocked(instanceID string) bool {
+	for _, state := range s.execStates {
+		if state.ExecutorInstanceID == instanceID {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteOldStoppedInstances deletes stopped/crashed instances started more
+// than olderThanSeconds ago, always keeping the maxToKeep most recent
+func (s *Store) DeleteOldStoppedInstances(ctx context.Context, olderThanSeconds int, maxToKeep int) (int, error) {
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-time.Duration(olderThanSeconds) * time.Second)
+
+	var stopped []*types.ExecutorInstance
+	for _, instance := range s.instances {
+		if instance.Status == types.ExecutorStatusStopped || instance.Status == executorStatusCrashed {
+			stopped = append(stopped, instance)
+		}
+	}
+	sort.Slice(stopped, func(i, j int) bool {
+		return stopped[i].StartedAt.After(stopped[j].StartedAt)
+	})
+
+	deleted := 0
+	for i, instance := range stopped {
+		if i < maxToKeep || !instance.StartedAt.Before(cutoff) {
+			continue
+		}
+		delete(s.instances, instance.InstanceID)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// ======================================================================
+// ISSUE EXECUTION STATE
+// ======================================================================
+
+// activeClaimStates are the execution states that hold a claim on an issue
+var activeClaimStates = map[types.ExecutionState]bool{
+	types.ExecutionStateClaimed:    true,
+	types.ExecutionStateAssessing:  true,
+	types.ExecutionStateExecuting:  true,
+	types.ExecutionStateAnalyzing:  true,
+	types.ExecutionStateGates:      true,
+	types.ExecutionStateCommitting: true,
+}
+
+// ClaimIssue atomically claims an open issue for an executor
+func (s *Store) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	return s.claimIssueLocked(issueID, executorInstanceID, nil)
+}
+
+// claimIssueLocked claims an open issue, with a lease expiring at
+// leaseExpiresAt (nil = no lease). Caller must hold s.mu.
+func (s *Store) claimIssueLocked(issueID, executorInstanceID string, leaseExpiresAt *time.Time) error {
+	issue, ok := s.issues[issueID]
+	if !ok {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	// vc-e3j2: Work without acceptance criteria can't be validated
+	if (issue.IssueType == types.TypeTask || issue.IssueType == types.TypeBug) &&
+		strings.TrimSpace(issue.AcceptanceCriteria) == "" {
+		return fmt.Errorf("cannot claim issue %s: acceptance_criteria is required for %s issues (needed to validate completion)", issueID, issue.IssueType)
+	}
+	state, hasState := s.execStates[issueID]
+	if hasState && activeClaimStates[state.State] {
+		return fmt.Errorf("issue %s already claimed by %s", issueID, state.ExecutorInstanceID)
+	}
+	if issue.Status != types.StatusOpen {
+		return fmt.Errorf("cannot claim issue %s: issue is not open (may be closed, blocked, or in_progress)", issueID)
+	}
+
+	now := time.Now()
+	if !hasState {
+		state = &types.IssueExecutionState{IssueID: issueID}
+		s.execStates[issueID] = state
+	}
+	state.ExecutorInstanceID = executorInstanceID
+	state.ClaimedAt = now
+	state.State = types.ExecutionStateClaimed
+	state.LeaseExpiresAt = leaseExpiresAt
+	state.UpdatedAt = now
+
+	before := copyIssue(issue)
+	issue.Status = types.StatusInProgress
+	issue.UpdatedAt = now
+	s.recordAuditLocked(types.AuditEntriesFor(issueID, before, issue, executorInstanceID))
+	return nil
+}
+
+// ClaimNextReadyIssue atomically claims the highest-priority ready issue for
+// workerID with a lease, taking over an in_progress issue whose lease has
+// expired first. Only issues in project are considered, unless it is "".
+// Returns (nil, nil) if nothing can be claimed.
+func (s *Store) ClaimNextReadyIssue(ctx context.Context, workerID string, lease time.Duration, project string) (*types.Issue, error) {
+	if lease <= 0 {
+		lease = types.DefaultClaimLease
+	}
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	leaseExpiresAt := now.Add(lease)
+
+	inProject := func(issueID string) bool {
+		return project == "" || s.issueProjectLocked(issueID) == project
+	}
+
+	var expired []*types.Issue
+	for issueID, state := range s.execStates {
+		issue, ok := s.issues[issueID]
+		if ok && inProject(issueID) && issue.Status == types.StatusInProgress && activeClaimStates[state.State] &&
+			state.LeaseExpiresAt != nil && state.LeaseExpiresAt.Before(now) {
+			expired = append(expired, issue)
+		}
+	}
+	if len(expired) > 0 {
+		s.sortByPriorityOldestLocked(expired)
+		issue := expired[0]
+		state := s.execStates[issue.ID]
+		state.ExecutorInstanceID = workerID
+		state.ClaimedAt = now
+		state.State = types.ExecutionStateClaimed
+		state.LeaseExpiresAt = &leaseExpiresAt
+		state.UpdatedAt = now
+		return copyIssue(issue), nil
+	}
+
+	for _, candidate := range s.readyWorkLocked(types.WorkFilter{Status: types.StatusOpen, SortPolicy: types.SortPolicyPriority}) {
+		if !inProject(candidate.ID) {
+			continue
+		}
+		if err := s.claimIssueLocked(candidate.ID, workerID, &leaseExpiresAt); err != nil {
+			continue // Not claimable (e.g. missing acceptance criteria)
+		}
+		return copyIssue(s.issues[candidate.ID]), nil
+	}
+	return nil, nil
+}
+
+// RenewClaimLease extends workerID's lease on issueID to lease from now
+func (s *Store) RenewClaimLease(ctx context.Context, issueID, workerID string, lease time.Duration) error {
+	if lease <= 0 {
+		lease = types.DefaultClaimLease
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	state, ok := s.execStates[issueID]
+	if !ok || !activeClaimStates[state.State] || state.ExecutorInstanceID != workerID {
+		return fmt.Errorf("cannot renew lease on %s: not claimed by %s", issueID, workerID)
+	}
+	now := time.Now()
+	leaseExpiresAt := now.Add(lease)
+	state.LeaseExpiresAt = &leaseExpiresAt
+	state.UpdatedAt = now
+	return nil
+}
+
+// GetExecutionState returns an issue's execution state (nil if none)
+func (s *Store) GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+	return copyExecutionState(s.execStates[issueID]), nil
+}
+
+// UpdateExecutionState moves an issue's execution state, enforcing the state machine
+func (s *Store) UpdateExecutionState(ctx context.Context, issueID string, newState types.ExecutionState) error {
+	if !newState.IsValid() {
+		return fmt.Errorf("invalid execution state: %s", newState)
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	current, ok := s.execStates[issueID]
+	if !ok {
+		if newState != types.ExecutionStatePending && newState != types.ExecutionStateClaimed {
+			return fmt.Errorf("invalid initial execution state: %s (must be pending or claimed)", newState)
+		}
+		s.execStates[issueID] = &types.IssueExecutionState{
+			IssueID:   issueID,
+			State:     newState,
+			UpdatedAt: time.Now(),
+		}
+		return nil
+	}
+
+	// Allow idempotent same-state transitions (vc-57d7)
+	if current.State == newState {
+		return nil
+	}
+	if !current.State.CanTransitionTo(newState) {
+		return fmt.Errorf("invalid state transition: cannot transition from %s to %s (valid transitions: %v)",
+			current.State, newState, current.State.ValidTransitions())
+	}
+	current.State = newState
+	current.UpdatedAt = time.Now()
+	return nil
+}
+
+// SaveCheckpoint stores checkpoint data (as JSON) on an existing execution state
+func (s *Store) SaveCheckpoint(ctx context.Context, issueID string, checkpointData interface{}) error {
+	dataJSON, err := json.Marshal(checkpointData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint data: %w", err)
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if state, ok := s.execStates[issueID]; ok {
+		state.CheckpointData = string(dataJSON)
+		state.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// GetCheckpoint returns an issue's checkpoint data ("" if none)
+func (s *Store) GetCheckpoint(ctx context.Context, issueID string) (string, error) {
+	if err := s.lock(); err != nil {
+		return "", err
+	}
+	defer s.mu.Unlock()
+
+	if state, ok := s.execStates[issueID]; ok {
+		return state.CheckpointData, nil
+	}
+	return "", nil
+}
+
+// ReleaseIssue releases an issue claim. Idempotent.
+func (s *Store) ReleaseIssue(ctx context.Context, issueID string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	delete(s.execStates, issueID)
+	return nil
+}
+
+// ReleaseIssueAndReopen marks execution failed, reopens the issue and
+// records the error as a comment
+func (s *Store) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if state, ok := s.execStates[issueID]; ok {
+		state.State = types.ExecutionStateFailed
+		state.ErrorMessage = errorComment
+		state.UpdatedAt = time.Now()
+	}
+
+	// Log status change for audit trail (vc-n4lx)
+	s.logStatusChangeLocked(issueID, types.StatusOpen, actor,
+		fmt.Sprintf("execution failed, reopening for retry: %s", errorComment))
+	if err := s.updateIssueLocked(issueID, map[string]interface{}{"status": "open"}, actor); err != nil {
+		return fmt.Errorf("failed to reopen issue: %w", err)
+	}
+
+	if errorComment != "" {
+		issue := s.issues[issueID]
+		issue.UpdatedAt = time.Now()
+		s.recordEventLocked(issueID, types.EventCommented, actor, nil, nil, strPtr(errorComment))
+	}
+	return nil
+}
+
+// RecordWatchdogIntervention bumps an issue's intervention count for backoff (vc-165b)
+func (s *Store) RecordWatchdogIntervention(ctx context.Context, issueID string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	state, ok := s.execStates[issueID]
+	if !ok {
+		state = &types.IssueExecutionState{IssueID: issueID, State: types.ExecutionStatePending}
+		s.execStates[issueID] = state
+	}
+	state.InterventionCount++
+	state.LastInterventionTime = timePtr(now)
+	state.UpdatedAt = now
+	return nil
+}
+
+// ======================================================================
+// STATUS CHANGE LOGGING (vc-n4lx)
+// ======================================================================
+
+// LogStatusChange logs a status change to stderr for the audit trail.
+// Call it before UpdateIssue so the old status is still available.
+func (s *Store) LogStatusChange(ctx context.Context, issueID string, newStatus types.Status, actor, reason string) {
+	if err := s.lock(); err != nil {
+		return
+	}
+	defer s.mu.Unlock()
+	s.logStatusChangeLocked(issueID, newStatus, actor, reason)
+}
+
+// logStatusChangeLocked logs a status change. Caller must hold s.mu.
+func (s *Store) logStatusChangeLocked(issueID string, newStatus types.Status, actor, reason string) {
+	now := time.Now().Format(time.RFC3339)
+
+	issue, ok := s.issues[issueID]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "[STATUS CHANGE] %s %s <unknown> → %s (actor: %s, reason: %s)\n",
+			now, issueID, newStatus, actor, reason)
+		return
+	}
+	if issue.Status == newStatus {
+		fmt.Fprintf(os.Stderr, "[STATUS NO-OP] %s %s %s (actor: %s, reason: %s)\n",
+			now, issueID, newStatus, actor, reason)
+		return
+	}
+
+	logPrefix := "[STATUS CHANGE]"
+	if strings.HasPrefix(issueID, "vc-baseline-") {
+		logPrefix = "🚨 [BASELINE STATUS]"
+	}
+	fmt.Fprintf(os.Stderr, "%s %s %s %s → %s (actor: %s, reason: %s)\n",
+		logPrefix, now, issueID, issue.Status, newStatus, actor, reason)
+}
+
+// LogStatusChangeFromUpdates calls LogStatusChange if updates contains a status
+func (s *Store) LogStatusChangeFromUpdates(ctx context.Context, issueID string, updates map[string]interface{}, actor, reason string) {
+	statusVal, hasStatus := updates["status"]
+	if !hasStatus {
+		return
+	}
+	newStatus := statusOf(statusVal)
+	if newStatus == "" {
+		fmt.Fprintf(os.Stderr, "[STATUS CHANGE] %s %s <unknown-type:%T> (actor: %s, reason: %s)\n",
+			time.Now().Format(time.RFC3339), issueID, statusVal, actor, reason)
+		return
+	}
+	s.LogStatusChange(ctx, issueID, newStatus, actor, reason)
+}
+
+// ======================================================================
+// EXECUTION HISTORY
+// ======================================================================
+
+// RecordExecutionAttempt records an execution attempt in history and sets attempt.ID
+func (s *Store) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.nextAttemptID++
+	attempt.ID = s.nextAttemptID
+	stored := *attempt
+	s.attempts = append(s.attempts, &stored)
+	s.notifyLocked()
+	return nil
+}
+
+// GetExecutionHistory returns up to 1000 execution attempts for an issue
+func (s *Store) GetExecutionHistory(ctx context.Context, issueID string) ([]*types.ExecutionAttempt, error) {
+	return s.GetExecutionHistoryPaginated(ctx, issueID, 1000, 0)
+}
+
+// GetExecutionHistoryPaginated returns a page of execution attempts, oldest first (vc-59)
+func (s *Store) GetExecutionHistoryPaginated(ctx context.Context, issueID string, limit, offset int) ([]*types.ExecutionAttempt, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be > 0, got %d", limit)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must be >= 0, got %d", offset)
+	}
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var matching []*types.ExecutionAttempt
+	for _, attempt := range s.attempts {
+		if attempt.IssueID == issueID {
+			matching = append(matching, attempt)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].StartedAt.Before(matching[j].StartedAt)
+	})
+
+	var result []*types.ExecutionAttempt
+	for i := offset; i < len(matching) && len(result) < limit; i++ {
+		attemptCopy := *matching[i]
+		result = append(result, &attemptCopy)
+	}
+	return result, nil
+}
+
+// ======================================================================
+// INTERRUPT METADATA
+// ======================================================================
+
+// SaveInterruptMetadata saves (or replaces) the context of a paused task.
+// An existing resumed_at timestamp is kept.
+func (s *Store) SaveInterruptMetadata(ctx context.Context, metadata *types.InterruptMetadata) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	stored := *metadata
+	stored.ResumedAt = nil
+	if existing, ok := s.interrupts[metadata.IssueID]; ok && existing.ResumedAt != nil {
+		stored.ResumedAt = timePtr(*existing.ResumedAt)
+	}
+	s.interrupts[metadata.IssueID] = &stored
+	return nil
+}
+
+// GetInterruptMetadata returns a task's interrupt metadata (nil if none)
+func (s *Store) GetInterruptMetadata(ctx context.Context, issueID string) (*types.InterruptMetadata, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	metadata, ok := s.interrupts[issueID]
+	if !ok {
+		return nil, nil
+	}
+	return copyInterrupt(metadata), nil
+}
+
+// MarkInterruptResumed records that an interrupted task was resumed
+func (s *Store) MarkInterruptResumed(ctx context.Context, issueID string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if metadata, ok := s.interrupts[issueID]; ok {
+		metadata.ResumedAt = timePtr(time.Now())
+		metadata.ResumeCount++
+	}
+	return nil
+}
+
+// DeleteInterruptMetadata removes a task's interrupt metadata
+func (s *Store) DeleteInterruptMetadata(ctx context.Context, issueID string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	delete(s.interrupts, issueID)
+	return nil
+}
+
+// ListInterruptedIssues returns all interrupt metadata, most recent first
+func (s *Store) ListInterruptedIssues(ctx context.Context) ([]*types.InterruptMetadata, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.InterruptMetadata
+	for _, metadata := range s.interrupts {
+		result = append(result, copyInterrupt(metadata))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].InterruptedAt.After(result[j].InterruptedAt)
+	})
+	return result, nil
+}
+
+func copyInterrupt(metadata *types.InterruptMetadata) *types.InterruptMetadata {
+	c := *metadata
+	if metadata.ResumedAt != nil {
+		c.ResumedAt = timePtr(*metadata.ResumedAt)
+	}
+	return &c
+}
@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/export"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// EXPORT / IMPORT
+// ======================================================================
+
+// Export writes the full issue graph to w as a versioned JSONL dump
+func (s *Store) Export(ctx context.Context, w io.Writer) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	graph := s.exportGraphLocked()
+	s.mu.Unlock()
+
+	// Encode outside the lock: w may be slow
+	return export.Write(w, graph)
+}
+
+// exportGraphLocked copies the full issue graph. Caller must hold s.mu.
+func (s *Store) exportGraphLocked() *types.ExportGraph {
+	graph := &types.ExportGraph{
+		Header: types.ExportHeader{
+			ExportedAt:  time.Now(),
+			IssuePrefix: s.config["issue_prefix"],
+		},
+	}
+	for _, issue := range s.issues {
+		graph.Issues = append(graph.Issues, copyIssue(issue))
+	}
+	for _, dep := range s.deps {
+		depCopy := *dep
+		graph.Dependencies = append(graph.Dependencies, &depCopy)
+	}
+	for issueID, set := range s.labels {
+		for label := range set {
+			graph.Labels = append(graph.Labels, &types.IssueLabel{IssueID: issueID, Label: label})
+		}
+	}
+	for _, event := range s.events {
+		eventCopy := *event
+		graph.Events = append(graph.Events, &eventCopy)
+	}
+	for _, attempt := range s.attempts {
+		attemptCopy := *attempt
+		graph.Executions = append(graph.Executions, &attemptCopy)
+	}
+	return graph
+}
+
+// Import reads a dump written by Export and adds it to the store atomically.
+// Issues that already exist are left untouched, along with their events and
+// execution history; dependencies and labels are added if missing, so
+// importing the same dump twice is a no-op.
+func (s *Store) Import(ctx context.Context, r io.Reader) (*types.ImportStats, error) {
+	graph, err := export.Read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	// The dump was validated up front, so nothing below can fail part way
+	stats := &types.ImportStats{}
+	imported := make(map[string]bool, len(graph.Issues))
+	for _, issue := range graph.Issues {
+		if _, exists := s.issues[issue.ID]; exists {
+			stats.SkippedIssues++
+			continue
+		}
+		stored := copyIssue(issue)
+		s.nextSeq++
+		s.issues[stored.ID] = stored
+		s.issueSeq[stored.ID] = s.nextSeq
+		if stored.IssueSubtype != types.SubtypeNormal {
+			s.missions[stored.ID] = &missionState{}
+		}
+		s.reserveIssueIDLocked(stored.ID)
+		imported[stored.ID] = true
+		stats.Issues++
+	}
+
+	for _, dep := range graph.Dependencies {
+		if s.findDependencyLocked(dep.IssueID, dep.DependsOnID) != nil {
+			continue
+		}
+		depCopy := *dep
+		s.deps = append(s.deps, &depCopy)
+		stats.Dependencies++
+	}
+
+	for _, label := range graph.Labels {
+		set := s.labels[label.IssueID]
+		if set == nil {
+			set = make(map[string]bool)
+			s.labels[label.IssueID] = set
+		}
+		if set[label.Label] {
+			continue
+		}
+		set[label.Label] = true
+		stats.Labels++
+	}
+
+	for _, event := range graph.Events {
+		if !imported[event.IssueID] {
+			continue
+		}
+		s.nextEventID++
+		eventCopy := *event
+		eventCopy.ID = s.nextEventID
+		s.events = append(s.events, &eventCopy)
+		stats.Events++
+	}
+
+	for _, attempt := range graph.Executions {
+		if !imported[attempt.IssueID] {
+			continue
+		}
+		s.nextAttemptID++
+		attemptCopy := *attempt
+		attemptCopy.ID = s.nextAttemptID
+		s.attempts = append(s.attempts, &attemptCopy)
+		stats.Executions++
+	}
+	if stats.Events > 0 || stats.Executions > 0 {
+		s.notifyLocked()
+	}
+	return stats, nil
+}
+
+// ImportEvents adds events copied from another database, keeping their
+// timestamps. Events already recorded (same SyncKey) are skipped.
+func (s *Store) ImportEvents(ctx context.Context, events []*types.Event) (int, error) {
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		if _, ok := s.issues[event.IssueID]; !ok {
+			return 0, fmt.Errorf("issue %s not found", event.IssueID)
+		}
+	}
+	seen := make(map[string]bool, len(s.events))
+	for _, event := range s.events {
+		seen[event.SyncKey()] = true
+	}
+
+	added := 0
+	for _, event := range events {
+		key := event.SyncKey()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		s.nextEventID++
+		eventCopy := *event
+		eventCopy.ID = s.nextEventID
+		s.events = append(s.events, &eventCopy)
+		added++
+	}
+	if added > 0 {
+		s.notifyLocked()
+	}
+	return added, nil
+}
+
+// reserveIssueIDLocked makes sure generated IDs never collide with an
+// imported prefix-N ID. Caller must hold s.mu.
+func (s *Store) reserveIssueIDLocked(id string) {
+	prefix := s.config["issue_prefix"]
+	if !strings.HasPrefix(id, prefix+"-") {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(id, prefix+"-"))
+	if err == nil && n > s.nextIssueID {
+		s.nextIssueID = n
+	}
+}
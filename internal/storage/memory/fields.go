@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// CUSTOM FIELDS
+// ======================================================================
+
+// customFieldKey identifies a custom field definition
+type customFieldKey struct {
+	project string
+	name    string
+}
+
+// ListCustomFields returns all custom field definitions, global ones first,
+// then by project and name
+func (s *Store) ListCustomFields(ctx context.Context) ([]*types.CustomField, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	return s.customFieldsLocked(), nil
+}
+
+// customFieldsLocked returns copies of all definitions, sorted. Caller must
+// hold s.mu.
+func (s *Store) customFieldsLocked() []*types.CustomField {
+	fields := make([]*types.CustomField, 0, len(s.fields))
+	for _, f := range s.fields {
+		c := *f
+		c.Options = append([]string(nil), f.Options...)
+		fields = append(fields, &c)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].Project != fields[j].Project {
+			return fields[i].Project < fields[j].Project
+		}
+		return fields[i].Name < fields[j].Name
+	})
+	return fields
+}
+
+// SaveCustomField creates or updates a custom field definition, filling in
+// the timestamps. A project field needs its project to exist.
+func (s *Store) SaveCustomField(ctx context.Context, field *types.CustomField) error {
+	if err := field.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if field.Project != "" {
+		if _, ok := s.projects[field.Project]; !ok {
+			return fmt.Errorf("project %s not found", field.Project)
+		}
+	}
+
+	key := customFieldKey{field.Project, field.Name}
+	now := time.Now()
+	field.CreatedAt = now
+	if existing, ok := s.fields[key]; ok {
+		field.CreatedAt = existing.CreatedAt
+	}
+	field.UpdatedAt = now
+	saved := *field
+	saved.Options = append([]string(nil), field.Options...)
+	s.fields[key] = &saved
+	return nil
+}
+
+// DeleteCustomField deletes a custom field definition. Once no definition
+// of the name is left, the values of that name are deleted too.
+func (s *Store) DeleteCustomField(ctx context.Context, project, name string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	key := customFieldKey{project, name}
+	if _, ok := s.fields[key]; !ok {
+		return fmt.Errorf("field %s not found", types.QualifiedCustomFieldName(project, name))
+	}
+	delete(s.fields, key)
+	for other := range s.fields {
+		if other.name == name {
+			return nil
+		}
+	}
+	for _, values := range s.fieldVals {
+		delete(values, name)
+	}
+	return nil
+}
+
+// SetCustomFieldValue sets a custom field on an issue, or clears it if value
+// is "". The value is checked against the definition that applies to the
+// issue's project.
+func (s *Store) SetCustomFieldValue(ctx context.Context, issueID, name, value, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.issues[issueID]; !ok {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	project := s.issueProjectLocked(issueID)
+	field := types.ResolveCustomField(s.customFieldsLocked(), project, name)
+	if field == nil {
+		return fmt.Errorf("field %s is not defined for issue %s", name, issueID)
+	}
+
+	old, had := s.fieldVals[issueID][name]
+	if value == "" {
+		if !had {
+			return nil
+		}
+		delete(s.fieldVals[issueID], name)
+		s.recordEventLocked(issueID, types.EventUpdated, actor, strPtr(old), nil, strPtr("Cleared field: "+name))
+		return nil
+	}
+
+	value, err := field.NormalizeValue(value)
+	if err != nil {
+		return err
+	}
+	if had && old == value {
+		return nil
+	}
+	if s.fieldVals[issueID] == nil {
+		s.fieldVals[issueID] = make(map[string]string)
+	}
+	s.fieldVals[issueID][name] = value
+	var oldValue *string
+	if had {
+		oldValue = strPtr(old)
+	}
+	s.recordEventLocked(issueID, types.EventUpdated, actor, oldValue, strPtr(value), strPtr("Set field: "+name))
+	return nil
+}
+
+// GetCustomFieldValues returns an issue's custom field values, sorted by
+// name. Values without a definition for the issue's project are left out.
+func (s *Store) GetCustomFieldValues(ctx context.Context, issueID string) ([]*types.CustomFieldValue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	fields := s.customFieldsLocked()
+	project := s.issueProjectLocked(issueID)
+	var values []*types.CustomFieldValue
+	for name, value := range s.fieldVals[issueID] {
+		if field := types.ResolveCustomField(fields, project, name); field != nil {
+			values = append(values, &types.CustomFieldValue{Name: name, Type: field.Type, Value: value})
+		}
+	}
+	types.SortCustomFieldValues(values)
+	return values, nil
+}
+
+// issueProjectLocked returns the project an issue is in, or "". Caller must
+// hold s.mu.
+func (s *Store) issueProjectLocked(issueID string) string {
+	for label := range s.labels[issueID] {
+		if strings.HasPrefix(label, types.ProjectLabelPrefix) {
+			return strings.TrimPrefix(label, types.ProjectLabelPrefix)
+		}
+	}
+	return ""
+}
+
+// hasCustomFieldValuesLocked reports whether an issue's custom fields have
+// all the wanted values. Caller must hold s.mu.
+func (s *Store) hasCustomFieldValuesLocked(issueID string, want map[string]string) bool {
+	for name, value := range want {
+		if got, ok := s.fieldVals[issueID][name]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
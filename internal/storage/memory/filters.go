@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// SAVED FILTERS
+// ======================================================================
+
+// ListSavedFilters returns all saved filters, sorted by name
+func (s *Store) ListSavedFilters(ctx context.Context) ([]*types.SavedFilter, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	filters := make([]*types.SavedFilter, 0, len(s.filters))
+	for _, f := range s.filters {
+		filters = append(filters, copySavedFilter(f))
+	}
+	sort.Slice(filters, func(i, j int) bool { return filters[i].Name < filters[j].Name })
+	return filters, nil
+}
+
+// GetSavedFilter returns a saved filter, or nil if it doesn't exist
+func (s *Store) GetSavedFilter(ctx context.Context, name string) (*types.SavedFilter, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	f, ok := s.filters[name]
+	if !ok {
+		return nil, nil
+	}
+	return copySavedFilter(f), nil
+}
+
+// SaveFilter creates or replaces a saved filter, filling in the timestamps
+func (s *Store) SaveFilter(ctx context.Context, filter *types.SavedFilter) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	filter.CreatedAt = now
+	if existing, ok := s.filters[filter.Name]; ok {
+		filter.CreatedAt = existing.CreatedAt
+	}
+	filter.UpdatedAt = now
+	s.filters[filter.Name] = copySavedFilter(filter)
+	return nil
+}
+
+// DeleteFilter deletes a saved filter
+func (s *Store) DeleteFilter(ctx context.Context, name string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.filters[name]; !ok {
+		return fmt.Errorf("filter %s not found", name)
+	}
+	delete(s.filters, name)
+	return nil
+}
+
+// ListIssuesByFilter runs a saved filter
+func (s *Store) ListIssuesByFilter(ctx context.Context, name string) ([]*types.Issue, error) {
+	filter, err := s.GetSavedFilter(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return nil, fmt.Errorf("filter %s not found", name)
+	}
+	return s.SearchIssues(ctx, filter.Query, filter.IssueFilter())
+}
+
+// copySavedFilter returns a deep copy of a saved filter
+func copySavedFilter(f *types.SavedFilter) *types.SavedFilter {
+	c := *f
+	if f.Priority != nil {
+		priority := *f.Priority
+		c.Priority = &priority
+	}
+	c.Labels = append([]string(nil), f.Labels...)
+	if f.CustomFields != nil {
+		c.CustomFields = make(map[string]string, len(f.CustomFields))
+		for name, value := range f.CustomFields {
+			c.CustomFields[name] = value
+		}
+	}
+	return &c
+}
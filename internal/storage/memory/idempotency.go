@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// ======================================================================
+// IDEMPOTENCY KEYS
+// ======================================================================
+
+// idempotencyRecord is the result recorded for an idempotency key
+type idempotencyRecord struct {
+	result    string
+	createdAt time.Time
+}
+
+// GetIdempotencyKey returns the result recorded for key, if it was recorded
+// at or after since
+func (s *Store) GetIdempotencyKey(ctx context.Context, key string, since time.Time) (string, bool, error) {
+	if err := s.lock(); err != nil {
+		return "", false, err
+	}
+	defer s.mu.Unlock()
+
+	record, ok := s.idempotency[key]
+	if !ok || record.createdAt.Before(since) {
+		return "", false, nil
+	}
+	return record.result, true, nil
+}
+
+// RecordIdempotencyKey records key with the result of its side effect,
+// replacing any earlier record
+func (s *Store) RecordIdempotencyKey(ctx context.Context, key, result string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	s.idempotency[key] = &idempotencyRecord{result: result, createdAt: time.Now()}
+	return nil
+}
+
+// DeleteIdempotencyKeysBefore deletes keys recorded before the given time,
+// returning how many it deleted
+func (s *Store) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int, error) {
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, record := range s.idempotency {
+		if record.createdAt.Before(before) {
+			delete(s.idempotency, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,1421 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ISSUES
+// ======================================================================
+
+// CreateIssue validates and creates an issue, generating an ID if none is set
+func (s *Store) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	// Validate issue fields including acceptance_criteria requirements (vc-e3j2)
+	if err := issue.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	return s.createIssueLocked(issue, actor)
+}
+
+// CreateIssues creates several issues atomically (vc-3hjg).
+// Like the Beads batch path, it skips the VC acceptance criteria check.
+func (s *Store) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	return s.atomically(func() error {
+		for _, issue := range issues {
+			if err := s.createIssueLocked(issue, actor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// validateCore applies the Beads-level issue validation (no acceptance criteria rules)
+func validateCore(issue *types.Issue) error {
+	if len(issue.Title) == 0 {
+		return fmt.Errorf("title is required")
+	}
+	if len(issue.Title) > 500 {
+		return fmt.Errorf("title must be 500 characters or less (got %d)", len(issue.Title))
+	}
+	if issue.Priority < 0 || issue.Priority > 4 {
+		return fmt.Errorf("priority must be between 0 and 4 (got %d)", issue.Priority)
+	}
+	if !issue.Status.IsValid() {
+		return fmt.Errorf("invalid status: %s", issue.Status)
+	}
+	if !issue.IssueType.IsValid() {
+		return fmt.Errorf("invalid issue type: %s", issue.IssueType)
+	}
+	if !issue.IssueSubtype.IsValid() {
+		return fmt.Errorf("invalid issue subtype: %s", issue.IssueSubtype)
+	}
+	if issue.EstimatedMinutes != nil && *issue.EstimatedMinutes < 0 {
+		return fmt.Errorf("estimated_minutes cannot be negative")
+	}
+	if issue.Status == types.StatusClosed && issue.ClosedAt == nil {
+		return fmt.Errorf("closed issues must have closed_at timestamp")
+	}
+	if issue.Status != types.StatusClosed && issue.ClosedAt != nil {
+		return fmt.Errorf("non-closed issues cannot have closed_at timestamp")
+	}
+	return nil
+}
+
+// createIssueLocked creates an issue. Caller must hold s.mu.
+func (s *Store) createIssueLocked(issue *types.Issue, actor string) error {
+	if err := validateCore(issue); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	prefix := s.config["issue_prefix"]
+	if prefix == "" {
+		return fmt.Errorf("database not initialized: issue_prefix config is missing")
+	}
+
+	id := issue.ID
+	if id == "" {
+		id = s.generateIDLocked(prefix)
+	} else {
+		if !strings.HasPrefix(id, prefix+"-") {
+			return fmt.Errorf("issue ID '%s' does not match configured prefix '%s'", id, prefix)
+		}
+		if dot := strings.LastIndex(id, "."); dot > 0 {
+			parentID := id[:dot]
+			if _, ok := s.issues[parentID]; !ok {
+				return fmt.Errorf("parent issue %s does not exist", parentID)
+			}
+		}
+		if _, exists := s.issues[id]; exists {
+			return fmt.Errorf("issue %s already exists", id)
+		}
+	}
+
+	now := time.Now()
+	stored := copyIssue(issue)
+	stored.ID = id
+	stored.CreatedAt = now
+	stored.UpdatedAt = now
+	stored.MissionContext = nil // Computed by GetReadyWork, never stored
+
+	s.nextSeq++
+	s.issues[id] = stored
+	s.issueSeq[id] = s.nextSeq
+	if stored.IssueSubtype != types.SubtypeNormal {
+		s.missions[id] = &missionState{}
+	}
+
+	issueJSON, _ := json.Marshal(stored)
+	s.recordEventLocked(id, types.EventCreated, actor, nil, strPtr(string(issueJSON)), nil)
+	s.recordAuditLocked(types.AuditEntriesFor(id, nil, stored, actor))
+
+	// Copy generated ID back
+	issue.ID = id
+	return nil
+}
+
+// generateIDLocked returns the next unused sequential ID. Caller must hold s.mu.
+func (s *Store) generateIDLocked(prefix string) string {
+	for {
+		s.nextIssueID++
+		id := fmt.Sprintf("%s-%d", prefix, s.nextIssueID)
+		if _, exists := s.issues[id]; !exists {
+			return id
+		}
+	}
+}
+
+// GetIssue retrieves an issue by ID (nil if it doesn't exist)
+func (s *Store) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+	return s.getIssueLocked(id), nil
+}
+
+// getIssueLocked returns a copy of an issue, or nil. Caller must hold s.mu.
+func (s *Store) getIssueLocked(id string) *types.Issue {
+	return copyIssue(s.issues[id])
+}
+
+// GetIssues retrieves multiple issues by ID (vc-58).
+// Missing issues are omitted from the result map.
+func (s *Store) GetIssues(ctx context.Context, ids []string) (map[string]*types.Issue, error) {
+	if len(ids) == 0 {
+		return make(map[string]*types.Issue), nil
+	}
+	if len(ids) > maxBatchSize {
+		return nil, fmt.Errorf("batch size %d exceeds maximum of %d (SQLite variable limit)", len(ids), maxBatchSize)
+	}
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	result := make(map[string]*types.Issue, len(ids))
+	for _, id := range ids {
+		if issue, ok := s.issues[id]; ok {
+			result[id] = copyIssue(issue)
+		}
+	}
+	return result, nil
+}
+
+// UpdateIssue updates issue fields
+func (s *Store) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	return s.updateIssueLocked(id, updates, actor)
+}
+
+// allowedUpdateFields are the issue fields UpdateIssue accepts (same as Beads)
+var allowedUpdateFields = map[string]bool{
+	"status":              true,
+	"priority":            true,
+	"title":               true,
+	"assignee":            true,
+	"description":         true,
+	"design":              true,
+	"acceptance_criteria": true,
+	"notes":               true,
+	"issue_type":          true,
+	"estimated_minutes":   true,
+	"external_ref":        true,
+	"closed_at":           true,
+}
+
+// updateIssueLocked applies updates to an issue. Caller must hold s.mu.
+func (s *Store) updateIssueLocked(id string, updates map[string]interface{}, actor string) error {
+	stored, ok := s.issues[id]
+	if !ok {
+		return fmt.Errorf("issue %s not found", id)
+	}
+
+	// Apply to a copy so a bad field leaves the issue untouched
+	updated := copyIssue(stored)
+	for key, value := range updates {
+		if !allowedUpdateFields[key] {
+			return fmt.Errorf("invalid field for update: %s", key)
+		}
+		if err := applyIssueField(updated, key, value); err != nil {
+			return err
+		}
+	}
+
+	// Keep closed_at in sync with status unless it was set explicitly
+	if _, hasClosedAt := updates["closed_at"]; !hasClosedAt {
+		if _, hasStatus := updates["status"]; hasStatus {
+			if updated.Status == types.StatusClosed && stored.Status != types.StatusClosed {
+				updated.ClosedAt = timePtr(time.Now())
+			} else if updated.Status != types.StatusClosed && stored.Status == types.StatusClosed {
+				updated.ClosedAt = nil
+			}
+		}
+	}
+	updated.UpdatedAt = time.Now()
+
+	eventType := types.EventUpdated
+	if statusVal, hasStatus := updates["status"]; hasStatus {
+		eventType = types.EventStatusChanged
+		if statusOf(statusVal) == types.StatusClosed {
+			eventType = types.EventClosed
+		} else if stored.Status == types.StatusClosed {
+			eventType = types.EventReopened
+		}
+	}
+
+	oldJSON, _ := json.Marshal(stored)
+	newJSON, _ := json.Marshal(updates)
+	s.issues[id] = updated
+	s.recordEventLocked(id, eventType, actor, strPtr(string(oldJSON)), strPtr(string(newJSON)), nil)
+	s.recordAuditLocked(types.AuditEntriesFor(id, stored, updated, actor))
+	return nil
+}
+
+// statusOf converts a status update value to a Status ("" if it isn't one)
+func statusOf(value interface{}) types.Status {
+	switch v := value.(type) {
+	case types.Status:
+		return v
+	case string:
+		return types.Status(v)
+	}
+	return ""
+}
+
+// applyIssueField sets a single field on an issue, validating the value
+func applyIssueField(issue *types.Issue, key string, value interface{}) error {
+	switch key {
+	case "status":
+		status := statusOf(value)
+		if !status.IsValid() {
+			return fmt.Errorf("invalid status: %v", value)
+		}
+		issue.Status = status
+	case "priority":
+		priority, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("priority must be an integer")
+		}
+		if priority < 0 || priority > 4 {
+			return fmt.Errorf("priority must be between 0 and 4 (got %d)", priority)
+		}
+		issue.Priority = priority
+	case "issue_type":
+		var issueType types.IssueType
+		switch v := value.(type) {
+		case types.IssueType:
+			issueType = v
+		case string:
+			issueType = types.IssueType(v)
+		}
+		if !issueType.IsValid() {
+			return fmt.Errorf("invalid issue type: %v", value)
+		}
+		issue.IssueType = issueType
+	case "title":
+		title, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("title must be a string")
+		}
+		if len(title) == 0 || len(title) > 500 {
+			return fmt.Errorf("title must be 1-500 characters")
+		}
+		issue.Title = title
+	case "estimated_minutes":
+		switch v := value.(type) {
+		case nil:
+			issue.EstimatedMinutes = nil
+		case int:
+			if v < 0 {
+				return fmt.Errorf("estimated_minutes cannot be negative")
+			}
+			issue.EstimatedMinutes = &v
+		case *int:
+			if v != nil && *v < 0 {
+				return fmt.Errorf("estimated_minutes cannot be negative")
+			}
+			if v == nil {
+				issue.EstimatedMinutes = nil
+			} else {
+				minutes := *v
+				issue.EstimatedMinutes = &minutes
+			}
+		default:
+			return fmt.Errorf("estimated_minutes must be an integer")
+		}
+	case "closed_at":
+		switch v := value.(type) {
+		case nil:
+			issue.ClosedAt = nil
+		case time.Time:
+			issue.ClosedAt = &v
+		case *time.Time:
+			if v == nil {
+				issue.ClosedAt = nil
+			} else {
+				issue.ClosedAt = timePtr(*v)
+			}
+		default:
+			return fmt.Errorf("closed_at must be a time")
+		}
+	case "external_ref":
+		// Not part of the VC issue model; accepted for compatibility
+	default:
+		// Remaining fields are free-form strings (assignee may be nil to clear it)
+		var text string
+		switch v := value.(type) {
+		case nil:
+		case string:
+			text = v
+		default:
+			return fmt.Errorf("%s must be a string", key)
+		}
+		switch key {
+		case "assignee":
+			issue.Assignee = text
+		case "description":
+			issue.Description = text
+		case "design":
+			issue.Design = text
+		case "acceptance_criteria":
+			issue.AcceptanceCriteria = text
+		case "notes":
+			issue.Notes = text
+		}
+	}
+	return nil
+}
+
+// CloseIssue closes an issue, clearing its execution state and assignee (vc-4820, vc-3e0o)
+func (s *Store) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.issues[id]; !ok {
+		return fmt.Errorf("issue not found: %s", id)
+	}
+	delete(s.execStates, id)
+	if err := s.updateIssueLocked(id, map[string]interface{}{"assignee": nil}, actor); err != nil {
+		return err
+	}
+	return s.closeIssueLocked(id, reason, actor)
+}
+
+// closeIssueLocked marks an issue closed. Caller must hold s.mu.
+func (s *Store) closeIssueLocked(id string, reason string, actor string) error {
+	issue, ok := s.issues[id]
+	if !ok {
+		return fmt.Errorf("issue not found: %s", id)
+	}
+	before := copyIssue(issue)
+	now := time.Now()
+	issue.Status = types.StatusClosed
+	issue.ClosedAt = timePtr(now)
+	issue.UpdatedAt = now
+	s.recordEventLocked(id, types.EventClosed, actor, nil, nil, strPtr(reason))
+	s.recordAuditLocked(types.AuditEntriesFor(id, before, issue, actor))
+	return nil
+}
+
+// deleteIssueLocked removes an issue and everything attached to it. Caller must hold s.mu.
+func (s *Store) deleteIssueLocked(id string) error {
+	issue, ok := s.issues[id]
+	if !ok {
+		return fmt.Errorf("issue not found: %s", id)
+	}
+	// The audit log is kept, so the issue's history outlives it
+	s.recordAuditLocked(types.AuditEntriesFor(id, issue, nil, ""))
+	delete(s.issues, id)
+	delete(s.issueSeq, id)
+	delete(s.missions, id)
+	delete(s.labels, id)
+	delete(s.fieldVals, id)
+	delete(s.execStates, id)
+
+	deps := s.deps[:0]
+	for _, dep := range s.deps {
+		if dep.IssueID != id && dep.DependsOnID != id {
+			deps = append(deps, dep)
+		}
+	}
+	s.deps = deps
+
+	executions := s.executions[:0]
+	for _, execution := range s.executions {
+		if execution.IssueID != id {
+			executions = append(executions, execution)
+		}
+	}
+	s.executions = executions
+	s.deleteOutputLocked(func(line *types.OutputLine) bool { return line.IssueID == id })
+
+	// Usage outlives its issue, as in the database
+	for _, usage := range s.aiUsage {
+		if usage.IssueID == id {
+			usage.IssueID = ""
+		}
+	}
+
+	attempts := s.attempts[:0]
+	for _, attempt := range s.attempts {
+		if attempt.IssueID != id {
+			attempts = append(attempts, attempt)
+		}
+	}
+	s.attempts = attempts
+
+	kept := s.events[:0]
+	for _, event := range s.events {
+		if event.IssueID != id {
+			kept = append(kept, event)
+		} else {
+			s.forgetCommentLocked(event.ID)
+		}
+	}
+	s.events = kept
+
+	for _, attachment := range s.attachmentsForIssueLocked(id) {
+		s.deleteAttachmentLocked(attachment.ID)
+	}
+	return nil
+}
+
+// SearchIssues finds issues whose title, description or ID contains query
+func (s *Store) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	issueType := filter.IssueType
+	if filter.Type != nil {
+		issueType = filter.Type
+	}
+	query = strings.ToLower(query)
+
+	var result []*types.Issue
+	for _, issue := range s.issues {
+		if query != "" &&
+			!strings.Contains(strings.ToLower(issue.Title), query) &&
+			!strings.Contains(strings.ToLower(issue.Description), query) &&
+			!strings.Contains(strings.ToLower(issue.ID), query) {
+			continue
+		}
+		if filter.Status != nil && issue.Status != *filter.Status {
+			continue
+		}
+		if filter.Priority != nil && issue.Priority != *filter.Priority {
+			continue
+		}
+		if issueType != nil && issue.IssueType != *issueType {
+			continue
+		}
+		if filter.Assignee != nil && issue.Assignee != *filter.Assignee {
+			continue
+		}
+		if !s.hasAllLabelsLocked(issue.ID, filter.Labels) {
+			continue
+		}
+		if filter.Project != "" && !s.labels[issue.ID][types.ProjectLabel(filter.Project)] {
+			continue
+		}
+		if !s.hasCustomFieldValuesLocked(issue.ID, filter.CustomFields) {
+			continue
+		}
+		result = append(result, issue)
+	}
+
+	s.sortByPriorityNewestLocked(result)
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return copyIssues(result), nil
+}
+
+// ======================================================================
+// MISSIONS
+// ======================================================================
+
+// CreateMission creates a mission epic with its mission metadata
+func (s *Store) CreateMission(ctx context.Context, mission *types.Mission, actor string) error {
+	if err := mission.Issue.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if err := s.createIssueLocked(&mission.Issue, actor); err != nil {
+		return err
+	}
+	if state, ok := s.missions[mission.ID]; ok {
+		state.Goal = mission.Goal
+		state.Context = mission.Context
+		state.ApprovalRequired = mission.ApprovalRequired
+		state.ApprovedAt = nil
+		if mission.ApprovedAt != nil {
+			state.ApprovedAt = timePtr(*mission.ApprovedAt)
+		}
+		state.ApprovedBy = mission.ApprovedBy
+		state.SandboxPath = mission.SandboxPath
+		state.BranchName = mission.BranchName
+		state.IterationCount = mission.IterationCount
+		state.GatesStatus = mission.GatesStatus
+	}
+
+	// Emit mission_created event (vc-266)
+	var parentEpicID string
+	for _, dep := range s.dependenciesLocked(mission.ID) {
+		if dep.IssueType == types.TypeEpic {
+			parentEpicID = dep.ID
+			break
+		}
+	}
+	s.storeAgentEventLocked(&events.AgentEvent{
+		Type:      events.EventTypeMissionCreated,
+		Timestamp: time.Now(),
+		IssueID:   mission.ID,
+		Severity:  events.SeverityInfo,
+		Message:   fmt.Sprintf("Mission created: %s (goal: %s)", mission.ID, mission.Goal),
+		Data: map[string]interface{}{
+			"mission_id":        mission.ID,
+			"parent_epic_id":    parentEpicID,
+			"goal":              mission.Goal,
+			"approval_required": mission.ApprovalRequired,
+			"actor":             actor,
+		},
+	})
+	return nil
+}
+
+// GetMission retrieves a mission with its mission metadata
+func (s *Store) GetMission(ctx context.Context, id string) (*types.Mission, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+	return s.getMissionLocked(id)
+}
+
+// getMissionLocked builds a mission from its issue and state. Caller must hold s.mu.
+func (s *Store) getMissionLocked(id string) (*types.Mission, error) {
+	issue, ok := s.issues[id]
+	if !ok {
+		return nil, fmt.Errorf("issue %s not found", id)
+	}
+	state, ok := s.missions[id]
+	if !ok || issue.IssueSubtype != types.SubtypeMission {
+		return nil, fmt.Errorf("issue %s is not a mission", id)
+	}
+	mission := &types.Mission{
+		Issue:            *copyIssue(issue),
+		Goal:             state.Goal,
+		Context:          state.Context,
+		ApprovalRequired: state.ApprovalRequired,
+		ApprovedBy:       state.ApprovedBy,
+		SandboxPath:      state.SandboxPath,
+		BranchName:       state.BranchName,
+		IterationCount:   state.IterationCount,
+		GatesStatus:      state.GatesStatus,
+	}
+	if state.ApprovedAt != nil {
+		mission.ApprovedAt = timePtr(*state.ApprovedAt)
+	}
+	return mission, nil
+}
+
+// missionFields are the UpdateMission keys stored as mission metadata
+// rather than base issue fields
+var missionFields = map[string]bool{
+	"approved_at":       true,
+	"approved_by":       true,
+	"goal":              true,
+	"context":           true,
+	"sandbox_path":      true,
+	"branch_name":       true,
+	"approval_required": true,
+	"iteration_count":   true,
+	"gates_status":      true,
+}
+
+// UpdateMission updates mission metadata and/or base issue fields
+func (s *Store) UpdateMission(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	oldMission, err := s.getMissionLocked(id)
+	if err != nil {
+		return fmt.Errorf("failed to get current mission state: %w", err)
+	}
+
+	baseUpdates := make(map[string]interface{})
+	missionUpdates := make(map[string]interface{})
+	for key, value := range updates {
+		if missionFields[key] {
+			missionUpdates[key] = value
+		} else {
+			baseUpdates[key] = value
+		}
+	}
+
+	if len(baseUpdates) > 0 {
+		if err := s.updateIssueLocked(id, baseUpdates, actor); err != nil {
+			return fmt.Errorf("failed to update base issue fields: %w", err)
+		}
+	}
+
+	if len(missionUpdates) > 0 {
+		// Apply to a copy so a bad value leaves the mission untouched
+		state := *s.missions[id]
+		for key, value := range missionUpdates {
+			if err := applyMissionField(&state, key, value); err != nil {
+				return fmt.Errorf("failed to update mission metadata: %w", err)
+			}
+		}
+		*s.missions[id] = state
+	}
+
+	// Emit mission_metadata_updated event (vc-266)
+	if len(updates) > 0 {
+		updatedFields := make([]string, 0, len(updates))
+		changes := make(map[string]interface{}, len(updates))
+		for key, newValue := range updates {
+			updatedFields = append(updatedFields, key)
+			changes[key] = map[string]interface{}{
+				"old_value": missionFieldValue(oldMission, key),
+				"new_value": newValue,
+			}
+		}
+		s.storeAgentEventLocked(&events.AgentEvent{
+			Type:      events.EventTypeMissionMetadataUpdated,
+			Timestamp: time.Now(),
+			IssueID:   id,
+			Severity:  events.SeverityInfo,
+			Message:   fmt.Sprintf("Mission metadata updated: %s (fields: %v)", id, updatedFields),
+			Data: map[string]interface{}{
+				"mission_id":     id,
+				"updated_fields": updatedFields,
+				"changes":        changes,
+				"actor":          actor,
+			},
+		})
+	}
+	return nil
+}
+
+// applyMissionField sets a single mission metadata field
+func applyMissionField(state *missionState, key string, value interface{}) error {
+	switch key {
+	case "approved_at":
+		switch v := value.(type) {
+		case nil:
+			state.ApprovedAt = nil
+		case time.Time:
+			state.ApprovedAt = &v
+		case *time.Time:
+			if v == nil {
+				state.ApprovedAt = nil
+			} else {
+				state.ApprovedAt = timePtr(*v)
+			}
+		default:
+			return fmt.Errorf("approved_at must be a time")
+		}
+	case "approval_required":
+		required, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("approval_required must be a bool")
+		}
+		state.ApprovalRequired = required
+	case "iteration_count":
+		count, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("iteration_count must be an integer")
+		}
+		state.IterationCount = count
+	default:
+		var text string
+		switch v := value.(type) {
+		case nil:
+		case string:
+			text = v
+		default:
+			return fmt.Errorf("%s must be a string", key)
+		}
+		switch key {
+		case "approved_by":
+			state.ApprovedBy = text
+		case "goal":
+			state.Goal = text
+		case "context":
+			state.Context = text
+		case "sandbox_path":
+			state.SandboxPath = text
+		case "branch_name":
+			state.BranchName = text
+		case "gates_status":
+			state.GatesStatus = text
+		default:
+			return fmt.Errorf("invalid mission field: %s", key)
+		}
+	}
+	return nil
+}
+
+// missionFieldValue returns the old value of an updated field, for change events
+func missionFieldValue(m *types.Mission, key string) interface{} {
+	switch key {
+	case "approved_at":
+		return m.ApprovedAt
+	case "approved_by":
+		return m.ApprovedBy
+	case "goal":
+		return m.Goal
+	case "context":
+		return m.Context
+	case "sandbox_path":
+		return m.SandboxPath
+	case "branch_name":
+		return m.BranchName
+	case "status":
+		return m.Status
+	case "priority":
+		return m.Priority
+	case "approval_required":
+		return m.ApprovalRequired
+	case "iteration_count":
+		return m.IterationCount
+	case "gates_status":
+		return m.GatesStatus
+	}
+	return nil
+}
+
+// ======================================================================
+// DEPENDENCIES
+// ======================================================================
+
+// AddDependency adds a dependency between two issues, rejecting cycles
+func (s *Store) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	return s.addDependencyLocked(dep, actor)
+}
+
+// addDependencyLocked adds a dependency. Caller must hold s.mu.
+func (s *Store) addDependencyLocked(dep *types.Dependency, actor string) error {
+	if !dep.Type.IsValid() {
+		return fmt.Errorf("invalid dependency type: %s (must be blocks, related, parent-child, or discovered-from)", dep.Type)
+	}
+	source, ok := s.issues[dep.IssueID]
+	if !ok {
+		return fmt.Errorf("issue %s not found", dep.IssueID)
+	}
+	target, ok := s.issues[dep.DependsOnID]
+	if !ok {
+		return fmt.Errorf("dependency target %s not found", dep.DependsOnID)
+	}
+	if dep.IssueID == dep.DependsOnID {
+		return fmt.Errorf("issue cannot depend on itself")
+	}
+	// Parent-child points from child to parent, so an epic can't be the child of a task
+	if dep.Type == types.DepParentChild && source.IssueType == types.TypeEpic && target.IssueType != types.TypeEpic {
+		return fmt.Errorf("invalid parent-child dependency: parent (%s) cannot depend on child (%s). Use: bd dep add %s %s --type parent-child",
+			dep.IssueID, dep.DependsOnID, dep.DependsOnID, dep.IssueID)
+	}
+	if s.findDependencyLocked(dep.IssueID, dep.DependsOnID) != nil {
+		return fmt.Errorf("dependency from %s to %s already exists", dep.IssueID, dep.DependsOnID)
+	}
+	// Adding issue → target closes a cycle if target already reaches issue
+	if s.reachableLocked(dep.DependsOnID, dep.IssueID) {
+		return fmt.Errorf("cannot add dependency: would create a cycle (%s → %s → ... → %s)",
+			dep.IssueID, dep.DependsOnID, dep.IssueID)
+	}
+
+	stored := *dep
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = time.Now()
+	}
+	if stored.CreatedBy == "" {
+		stored.CreatedBy = actor
+	}
+	s.deps = append(s.deps, &stored)
+	s.recordEventLocked(dep.IssueID, types.EventDependencyAdded, actor, nil, nil,
+		strPtr(fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID)))
+	return nil
+}
+
+// reachableLocked reports whether to can be reached from from by following
+// dependencies of any type. Caller must hold s.mu.
+func (s *Store) reachableLocked(from, to string) bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			return true
+		}
+		for _, dep := range s.deps {
+			if dep.IssueID == current && !visited[dep.DependsOnID] {
+				visited[dep.DependsOnID] = true
+				queue = append(queue, dep.DependsOnID)
+			}
+		}
+	}
+	return false
+}
+
+// findDependencyLocked returns the dependency from issueID to dependsOnID, or nil
+func (s *Store) findDependencyLocked(issueID, dependsOnID string) *types.Dependency {
+	for _, dep := range s.deps {
+		if dep.IssueID == issueID && dep.DependsOnID == dependsOnID {
+			return dep
+		}
+	}
+	return nil
+}
+
+// RemoveDependency removes a dependency
+func (s *Store) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	return s.removeDependencyLocked(issueID, dependsOnID, actor)
+}
+
+// removeDependencyLocked removes a dependency. Caller must hold s.mu.
+func (s *Store) removeDependencyLocked(issueID, dependsOnID string, actor string) error {
+	for i, dep := range s.deps {
+		if dep.IssueID == issueID && dep.DependsOnID == dependsOnID {
+			s.deps = append(s.deps[:i:i], s.deps[i+1:]...)
+			s.recordEventLocked(issueID, types.EventDependencyRemoved, actor, nil, nil,
+				strPtr(fmt.Sprintf("Removed dependency on %s", dependsOnID)))
+			return nil
+		}
+	}
+	return fmt.Errorf("dependency from %s to %s does not exist", issueID, dependsOnID)
+}
+
+// GetDependencies returns the issues that issueID depends on
+func (s *Store) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+	return copyIssues(s.dependenciesLocked(issueID)), nil
+}
+
+// dependenciesLocked returns (uncopied) issues that issueID depends on, by priority.
+// Caller must hold s.mu.
+func (s *Store) dependenciesLocked(issueID string) []*types.Issue {
+	var result []*types.Issue
+	for _, dep := range s.deps {
+		if dep.IssueID == issueID {
+			if issue, ok := s.issues[dep.DependsOnID]; ok {
+				result = append(result, issue)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Priority < result[j].Priority })
+	return result
+}
+
+// GetDependents returns the issues that depend on issueID
+func (s *Store) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Issue
+	for _, dep := range s.deps {
+		if dep.DependsOnID == issueID {
+			if issue, ok := s.issues[dep.IssueID]; ok {
+				result = append(result, issue)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Priority < result[j].Priority })
+	return copyIssues(result), nil
+}
+
+// GetBlockers returns the unclosed issues blocking issueID via 'blocks' dependencies
+func (s *Store) GetBlockers(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Issue
+	for _, dep := range s.deps {
+		if dep.IssueID != issueID || dep.Type != types.DepBlocks {
+			continue
+		}
+		if blocker, ok := s.issues[dep.DependsOnID]; ok && blocker.Status != types.StatusClosed {
+			result = append(result, blocker)
+		}
+	}
+	s.sortByPriorityOldestLocked(result)
+	return copyIssues(result), nil
+}
+
+// GetBlocked returns the unclosed issues that issueID blocks via 'blocks' dependencies
+func (s *Store) GetBlocked(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Issue
+	for _, dep := range s.deps {
+		if dep.DependsOnID != issueID || dep.Type != types.DepBlocks {
+			continue
+		}
+		if blocked, ok := s.issues[dep.IssueID]; ok && blocked.Status != types.StatusClosed {
+			result = append(result, blocked)
+		}
+	}
+	s.sortByPriorityOldestLocked(result)
+	return copyIssues(result), nil
+}
+
+// GetDependencyRecords returns the raw dependency records for an issue
+func (s *Store) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Dependency
+	for _, dep := range s.deps {
+		if dep.IssueID == issueID {
+			depCopy := *dep
+			result = append(result, &depCopy)
+		}
+	}
+	return result, nil
+}
+
+// GetDependencyTree returns an issue and its transitive dependencies,
+// each at the shallowest depth it is reachable from the root
+func (s *Store) GetDependencyTree(ctx context.Context, issueID string, maxDepth int) ([]*types.TreeNode, error) {
+	if maxDepth <= 0 {
+		maxDepth = 50
+	}
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	root, ok := s.issues[issueID]
+	if !ok {
+		return nil, nil
+	}
+
+	nodes := []*types.TreeNode{{Issue: *copyIssue(root), Depth: 0, Truncated: maxDepth == 0}}
+	visited := map[string]bool{issueID: true}
+	level := []string{issueID}
+	for depth := 1; depth <= maxDepth && len(level) > 0; depth++ {
+		var next []*types.Issue
+		for _, id := range level {
+			for _, dep := range s.deps {
+				if dep.IssueID != id || visited[dep.DependsOnID] {
+					continue
+				}
+				if issue, ok := s.issues[dep.DependsOnID]; ok {
+					visited[dep.DependsOnID] = true
+					next = append(next, issue)
+				}
+			}
+		}
+		sort.Slice(next, func(i, j int) bool {
+			if next[i].Priority != next[j].Priority {
+				return next[i].Priority < next[j].Priority
+			}
+			return next[i].ID < next[j].ID
+		})
+		level = level[:0]
+		for _, issue := range next {
+			nodes = append(nodes, &types.TreeNode{Issue: *copyIssue(issue), Depth: depth, Truncated: depth == maxDepth})
+			level = append(level, issue.ID)
+		}
+	}
+	return nodes, nil
+}
+
+// DetectCycles finds dependency cycles. AddDependency rejects cycles, so this
+// only finds anything if the store was populated some other way.
+func (s *Store) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	adjacency := make(map[string][]string)
+	for _, dep := range s.deps {
+		adjacency[dep.IssueID] = append(adjacency[dep.IssueID], dep.DependsOnID)
+	}
+
+	var cycles [][]*types.Issue
+	seen := make(map[string]bool)
+	var path []string
+	onPath := make(map[string]bool)
+	var visit func(start, current string)
+	visit = func(start, current string) {
+		for _, next := range adjacency[current] {
+			if next == start {
+				// Only record each cycle once, from its smallest ID
+				key := strings.Join(path, ",")
+				if !seen[key] {
+					seen[key] = true
+					cycle := make([]*types.Issue, 0, len(path))
+					for _, id := range path {
+						if issue, ok := s.issues[id]; ok {
+							cycle = append(cycle, copyIssue(issue))
+						}
+					}
+					cycles = append(cycles, cycle)
+				}
+				continue
+			}
+			if onPath[next] || next < start || len(path) >= 100 {
+				continue
+			}
+			onPath[next] = true
+			path = append(path, next)
+			visit(start, next)
+			path = path[:len(path)-1]
+			onPath[next] = false
+		}
+	}
+
+	starts := make([]string, 0, len(adjacency))
+	for id := range adjacency {
+		starts = append(starts, id)
+	}
+	sort.Strings(starts)
+	for _, start := range starts {
+		path = []string{start}
+		onPath = map[string]bool{start: true}
+		visit(start, start)
+	}
+	return cycles, nil
+}
+
+// ======================================================================
+// LABELS
+// ======================================================================
+
+// AddLabel adds a label to an issue (no-op if already present)
+func (s *Store) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	return s.addLabelLocked(issueID, label, actor)
+}
+
+// addLabelLocked adds a label. Caller must hold s.mu.
+func (s *Store) addLabelLocked(issueID, label, actor string) error {
+	if _, ok := s.issues[issueID]; !ok {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	set := s.labels[issueID]
+	if set == nil {
+		set = make(map[string]bool)
+		s.labels[issueID] = set
+	}
+	if set[label] {
+		return nil
+	}
+	set[label] = true
+	s.recordEventLocked(issueID, types.EventLabelAdded, actor, nil, nil, strPtr(fmt.Sprintf("Added label: %s", label)))
+	return nil
+}
+
+// RemoveLabel removes a label from an issue (no-op if not present)
+func (s *Store) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	s.removeLabelLocked(issueID, label, actor)
+	return nil
+}
+
+// removeLabelLocked removes a label. Caller must hold s.mu.
+func (s *Store) removeLabelLocked(issueID, label, actor string) {
+	if !s.labels[issueID][label] {
+		return
+	}
+	delete(s.labels[issueID], label)
+	s.recordEventLocked(issueID, types.EventLabelRemoved, actor, nil, nil, strPtr(fmt.Sprintf("Removed label: %s", label)))
+}
+
+// GetLabels returns an issue's labels, sorted
+func (s *Store) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var labels []string
+	for label := range s.labels[issueID] {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// GetIssuesByLabel returns issues with a label, by priority then newest first
+func (s *Store) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Issue
+	for id, issue := range s.issues {
+		if s.labels[id][label] {
+			result = append(result, issue)
+		}
+	}
+	s.sortByPriorityNewestLocked(result)
+	return copyIssues(result), nil
+}
+
+// hasAllLabelsLocked reports whether an issue has every label. Caller must hold s.mu.
+func (s *Store) hasAllLabelsLocked(issueID string, labels []string) bool {
+	for _, label := range labels {
+		if !s.labels[issueID][label] {
+			return false
+		}
+	}
+	return true
+}
+
+// ======================================================================
+// COMMENTS AND AUDIT EVENTS
+// ======================================================================
+
+// AddComment adds a comment to an issue's audit trail
+func (s *Store) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	issue, ok := s.issues[issueID]
+	if !ok {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	issue.UpdatedAt = time.Now()
+	s.recordEventLocked(issueID, types.EventCommented, actor, nil, nil, strPtr(comment))
+	return nil
+}
+
+// GetEvents returns an issue's audit events, newest first
+func (s *Store) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Event
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if s.events[i].IssueID != issueID {
+			continue
+		}
+		eventCopy := *s.events[i]
+		result = append(result, &eventCopy)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// GetAuditLog returns field-level changes to an issue, newest first.
+// limit <= 0 means no limit.
+func (s *Store) GetAuditLog(ctx context.Context, issueID string, limit int) ([]*types.AuditEntry, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.AuditEntry
+	for i := len(s.audit) - 1; i >= 0; i-- {
+		if s.audit[i].IssueID != issueID {
+			continue
+		}
+		entryCopy := *s.audit[i]
+		result = append(result, &entryCopy)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// CompactIssueEvents replaces the events of issues closed more than
+// retentionDays ago that are themselves older than that with one summary
+// comment per batchSize events. Creation and close events are kept. Returns
+// the number of events removed.
+func (s *Store) CompactIssueEvents(ctx context.Context, retentionDays, batchSize int) (int, error) {
+	if retentionDays < 1 {
+		return 0, fmt.Errorf("retention days must be at least 1")
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1")
+	}
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	current := make(map[string]*types.EventCompaction)
+	chunkOf := make(map[*types.Event]*types.EventCompaction)
+	lastOf := make(map[*types.EventCompaction]*types.Event)
+	for _, event := range s.events {
+		issue := s.issues[event.IssueID]
+		if issue == nil || issue.Status != types.StatusClosed || issue.ClosedAt == nil || !issue.ClosedAt.Before(cutoff) {
+			continue
+		}
+		if !event.CreatedAt.Before(cutoff) || !types.IsCompactableEvent(event) {
+			continue
+		}
+		chunk := current[event.IssueID]
+		if chunk == nil || chunk.Events == batchSize {
+			chunk = types.NewEventCompaction(event.IssueID)
+			current[event.IssueID] = chunk
+		}
+		chunk.Add(event)
+		chunkOf[event] = chunk
+		lastOf[chunk] = event
+	}
+	if len(chunkOf) == 0 {
+		return 0, nil
+	}
+
+	// Each summary takes the place of the last event it replaces
+	kept := make([]*types.Event, 0, len(s.events)-len(chunkOf)+len(lastOf))
+	for _, event := range s.events {
+		chunk := chunkOf[event]
+		if chunk == nil {
+			kept = append(kept, event)
+			continue
+		}
+		s.forgetCommentLocked(event.ID)
+		if lastOf[chunk] == event {
+			s.nextEventID++
+			kept = append(kept, &types.Event{
+				ID:        s.nextEventID,
+				IssueID:   chunk.IssueID,
+				EventType: types.EventCommented,
+				Actor:     types.EventCompactionActor,
+				Comment:   strPtr(chunk.Summary()),
+				CreatedAt: chunk.Last,
+			})
+		}
+	}
+	s.events = kept
+	return len(chunkOf), nil
+}
+
+// recordAuditLocked appends audit log entries. Caller must hold s.mu.
+func (s *Store) recordAuditLocked(entries []*types.AuditEntry) {
+	now := time.Now()
+	for _, entry := range entries {
+		s.nextAuditID++
+		entry.ID = s.nextAuditID
+		entry.CreatedAt = now
+		s.audit = append(s.audit, entry)
+	}
+}
+
+// recordEventLocked appends an audit event. Caller must hold s.mu.
+func (s *Store) recordEventLocked(issueID string, eventType types.EventType, actor string, oldValue, newValue, comment *string) {
+	s.nextEventID++
+	s.events = append(s.events, &types.Event{
+		ID:        s.nextEventID,
+		IssueID:   issueID,
+		EventType: eventType,
+		Actor:     actor,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Comment:   comment,
+		CreatedAt: time.Now(),
+	})
+	s.notifyLocked()
+}
+
+// ======================================================================
+// STATISTICS
+// ======================================================================
+
+// GetStatistics returns issue counts and average lead time
+func (s *Store) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	stats := &types.Statistics{TotalIssues: len(s.issues)}
+	var leadTimeHours float64
+	var closedWithTime int
+	for _, issue := range s.issues {
+		switch issue.Status {
+		case types.StatusOpen:
+			stats.OpenIssues++
+			if len(s.openBlockersLocked(issue.ID)) == 0 {
+				stats.ReadyIssues++
+			}
+		case types.StatusInProgress:
+			stats.InProgressIssues++
+		case types.StatusClosed:
+			stats.ClosedIssues++
+			if issue.ClosedAt != nil {
+				leadTimeHours += issue.ClosedAt.Sub(issue.CreatedAt).Hours()
+				closedWithTime++
+			}
+		}
+		if isActiveStatus(issue.Status) && len(s.openBlockersLocked(issue.ID)) > 0 {
+			stats.BlockedIssues++
+		}
+	}
+	if closedWithTime > 0 {
+		stats.AverageLeadTime = leadTimeHours / float64(closedWithTime)
+	}
+	return stats, nil
+}
+
+// ======================================================================
+// HELPERS
+// ======================================================================
+
+// sortByPriorityNewestLocked orders issues by priority, then newest first.
+// Caller must hold s.mu.
+func (s *Store) sortByPriorityNewestLocked(issues []*types.Issue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Priority != issues[j].Priority {
+			return issues[i].Priority < issues[j].Priority
+		}
+		return s.newerLocked(issues[i], issues[j])
+	})
+}
+
+// newerLocked reports whether a was created after b, using insertion order for ties
+func (s *Store) newerLocked(a, b *types.Issue) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return s.issueSeq[a.ID] > s.issueSeq[b.ID]
+}
+
+func copyIssues(issues []*types.Issue) []*types.Issue {
+	if issues == nil {
+		return nil
+	}
+	result := make([]*types.Issue, len(issues))
+	for i, issue := range issues {
+		result[i] = copyIssue(issue)
+	}
+	return result
+}
+
+func strPtr(s string) *string {
+	return &s
+}
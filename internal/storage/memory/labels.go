@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// LABEL DEFINITIONS
+// ======================================================================
+
+// ListLabelDefinitions returns saved label definitions, system labels and
+// labels in use without a definition, sorted by name
+func (s *Store) ListLabelDefinitions(ctx context.Context) ([]*types.LabelDefinition, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	names := make(map[string]bool)
+	for name := range s.labelDefs {
+		names[name] = true
+	}
+	for name := range types.SystemLabels {
+		names[name] = true
+	}
+	for _, set := range s.labels {
+		for label := range set {
+			names[label] = true
+		}
+	}
+
+	defs := make([]*types.LabelDefinition, 0, len(names))
+	for name := range names {
+		defs = append(defs, s.labelDefinitionLocked(name))
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs, nil
+}
+
+// GetLabelDefinition returns a label's definition, or nil if the label is
+// not defined, not a system label and not on any issue
+func (s *Store) GetLabelDefinition(ctx context.Context, name string) (*types.LabelDefinition, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	def := s.labelDefinitionLocked(name)
+	if _, saved := s.labelDefs[name]; !saved && !def.System && def.IssueCount == 0 {
+		return nil, nil
+	}
+	return def, nil
+}
+
+// labelDefinitionLocked builds a label's definition from its saved
+// definition (if any) and current usage. Caller must hold s.mu.
+func (s *Store) labelDefinitionLocked(name string) *types.LabelDefinition {
+	def := &types.LabelDefinition{Name: name}
+	if saved, ok := s.labelDefs[name]; ok {
+		*def = *saved
+	}
+	def.System = types.IsSystemLabel(name)
+	if def.System && def.Description == "" {
+		def.Description = types.SystemLabels[name]
+	}
+	def.IssueCount = len(s.issuesWithLabelLocked(name))
+	return def
+}
+
+// issuesWithLabelLocked returns the IDs of issues carrying label, sorted.
+// Caller must hold s.mu.
+func (s *Store) issuesWithLabelLocked(label string) []string {
+	var ids []string
+	for id, set := range s.labels {
+		if set[label] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// SaveLabelDefinition creates or updates a label's description and color,
+// filling in System and the timestamps
+func (s *Store) SaveLabelDefinition(ctx context.Context, def *types.LabelDefinition) error {
+	if err := def.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	def.System = types.IsSystemLabel(def.Name)
+	def.UpdatedAt = now
+	if existing, ok := s.labelDefs[def.Name]; ok {
+		def.CreatedAt = existing.CreatedAt
+	} else {
+		def.CreatedAt = now
+	}
+	saved := *def
+	saved.IssueCount = 0
+	s.labelDefs[def.Name] = &saved
+	return nil
+}
+
+// RenameLabel renames a label on every issue carrying it, along with its
+// definition. Issues that already carry newName just lose oldName.
+func (s *Store) RenameLabel(ctx context.Context, oldName, newName, actor string) (int, error) {
+	if err := types.ValidateLabelName(newName); err != nil {
+		return 0, err
+	}
+	if oldName == newName {
+		return 0, fmt.Errorf("label %s is already named %s", oldName, newName)
+	}
+	for _, name := range []string{oldName, newName} {
+		if types.IsSystemLabel(name) {
+			return 0, fmt.Errorf("cannot rename %s to %s: %s: %w", oldName, newName, name, types.ErrSystemLabel)
+		}
+	}
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	oldDef, defined := s.labelDefs[oldName]
+	if _, exists := s.labelDefs[newName]; exists && defined {
+		return 0, fmt.Errorf("cannot rename %s: label %s is already defined", oldName, newName)
+	}
+	ids := s.issuesWithLabelLocked(oldName)
+	if !defined && len(ids) == 0 {
+		return 0, fmt.Errorf("label %s not found", oldName)
+	}
+
+	for _, id := range ids {
+		s.removeLabelLocked(id, oldName, actor)
+		if err := s.addLabelLocked(id, newName, actor); err != nil {
+			return 0, err
+		}
+	}
+	if defined {
+		delete(s.labelDefs, oldName)
+		oldDef.Name = newName
+		oldDef.UpdatedAt = time.Now()
+		s.labelDefs[newName] = oldDef
+	}
+	return len(ids), nil
+}
+
+// DeleteLabel removes a label from every issue carrying it, along with its
+// definition
+func (s *Store) DeleteLabel(ctx context.Context, name, actor string) (int, error) {
+	if types.IsSystemLabel(name) {
+		return 0, fmt.Errorf("cannot delete %s: %w", name, types.ErrSystemLabel)
+	}
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	_, defined := s.labelDefs[name]
+	ids := s.issuesWithLabelLocked(name)
+	if !defined && len(ids) == 0 {
+		return 0, fmt.Errorf("label %s not found", name)
+	}
+
+	for _, id := range ids {
+		s.removeLabelLocked(id, name, actor)
+	}
+	delete(s.labelDefs, name)
+	return len(ids), nil
+}
@@ -0,0 +1,425 @@
+// Package memory provides an in-memory implementation of storage.Storage.
+//
+// It mirrors the semantics of the SQLite-backed Beads storage (validation,
+// ordering, audit events, execution state rules) without touching disk, so it
+// can be used by unit tests and throwaway `vc --memory` runs. All state is lost
+// when the process exits.
+//
+// The store is safe for concurrent use. Every operation holds a single mutex,
+// so RunInVCTransaction callbacks must only use the transaction they are given
+// (calling back into the store from inside a transaction deadlocks).
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// defaultIssuePrefix matches the prefix VCStorage configures for new databases
+const defaultIssuePrefix = "vc"
+
+// maxBatchSize matches the SQLite variable limit enforced by VCStorage.GetIssues (vc-4573)
+const maxBatchSize = 500
+
+// missionState holds the mission-specific fields of a mission epic
+// (the equivalent of the vc_mission_state table)
+type missionState struct {
+	Goal             string
+	Context          string
+	ApprovalRequired bool
+	ApprovedAt       *time.Time
+	ApprovedBy       string
+	SandboxPath      string
+	BranchName       string
+	IterationCount   int
+	GatesStatus      string
+}
+
+// planRecord is a stored mission plan (the equivalent of vc_mission_plans)
+type planRecord struct {
+	data      []byte
+	status    string
+	iteration int
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// Store is an in-memory storage backend
+type Store struct {
+	mu     sync.Mutex
+	closed bool
+
+	// Core issue tracking (Beads tables)
+	issues      map[string]*types.Issue
+	issueSeq    map[string]int64 // Insertion order, used to break timestamp ties
+	nextSeq     int64
+	nextIssueID int
+	missions    map[string]*missionState
+	deps        []*types.Dependency
+	labels      map[string]map[string]bool
+	labelDefs   map[string]*types.LabelDefinition
+	projects    map[string]*types.Project
+	filters     map[string]*types.SavedFilter
+	schedules   map[string]*types.Schedule
+	fields      map[customFieldKey]*types.CustomField
+	fieldVals   map[string]map[string]string // Issue ID -> field name -> value
+	events      []*types.Event
+	nextEventID int64
+	comments    map[int64]*commentState // Comment event ID -> thread, edits, reactions
+	config      map[string]string
+
+	// VC extension state
+	agentEvents      []*events.AgentEvent
+	nextAgentEventID int64
+	instances        map[string]*types.ExecutorInstance
+	execStates       map[string]*types.IssueExecutionState
+	attempts         []*types.ExecutionAttempt
+	nextAttemptID    int64
+	executions       []*types.Execution
+	nextExecutionID  int64
+	output           []*types.OutputLine
+	nextOutputID     int64
+	aiUsage          []*types.AIUsage
+	interrupts       map[string]*types.InterruptMetadata
+	plans            map[string]*planRecord
+	diagnoses        map[string][]byte
+	attachments      []*types.Attachment
+	nextAttachmentID int64
+	blobs            map[string][]byte // Content by hex SHA-256
+	audit            []*types.AuditEntry
+	nextAuditID      int64
+	idempotency      map[string]*idempotencyRecord
+
+	// changed is closed (and replaced) whenever an event or execution attempt
+	// is recorded, waking Watch goroutines
+	changed chan struct{}
+}
+
+// New creates an empty in-memory store with the default issue prefix configured
+func New() *Store {
+	return &Store{
+		issues:      make(map[string]*types.Issue),
+		issueSeq:    make(map[string]int64),
+		missions:    make(map[string]*missionState),
+		labels:      make(map[string]map[string]bool),
+		labelDefs:   make(map[string]*types.LabelDefinition),
+		projects:    make(map[string]*types.Project),
+		filters:     make(map[string]*types.SavedFilter),
+		schedules:   make(map[string]*types.Schedule),
+		fields:      make(map[customFieldKey]*types.CustomField),
+		fieldVals:   make(map[string]map[string]string),
+		comments:    make(map[int64]*commentState),
+		config:      map[string]string{"issue_prefix": defaultIssuePrefix},
+		instances:   make(map[string]*types.ExecutorInstance),
+		execStates:  make(map[string]*types.IssueExecutionState),
+		interrupts:  make(map[string]*types.InterruptMetadata),
+		plans:       make(map[string]*planRecord),
+		diagnoses:   make(map[string][]byte),
+		blobs:       make(map[string][]byte),
+		idempotency: make(map[string]*idempotencyRecord),
+		changed:     make(chan struct{}),
+	}
+}
+
+// lock acquires the store mutex, failing if the store has been closed.
+// Callers must unlock when it returns nil.
+func (s *Store) lock() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return fmt.Errorf("storage is closed")
+	}
+	return nil
+}
+
+// Close closes the store. All subsequent operations fail.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.changed) // Watchers notice the store is closed and stop
+	}
+	return nil
+}
+
+// GetConfig returns a config value ("" if unset)
+func (s *Store) GetConfig(ctx context.Context, key string) (string, error) {
+	if err := s.lock(); err != nil {
+		return "", err
+	}
+	defer s.mu.Unlock()
+	return s.config[key], nil
+}
+
+// SetConfig sets a config value
+func (s *Store) SetConfig(ctx context.Context, key, value string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	s.config[key] = value
+	return nil
+}
+
+// GetIssuePrefix returns the configured issue prefix (e.g., "vc")
+func (s *Store) GetIssuePrefix(ctx context.Context) (string, error) {
+	prefix, err := s.GetConfig(ctx, "issue_prefix")
+	if err != nil {
+		return "", fmt.Errorf("failed to get issue_prefix config: %w", err)
+	}
+	if prefix == "" {
+		return "", fmt.Errorf("issue_prefix not configured in database")
+	}
+	return prefix, nil
+}
+
+// ======================================================================
+// TRANSACTIONS
+// ======================================================================
+
+// snapshot is a copy of the state a transaction can modify
+type snapshot struct {
+	issues      map[string]*types.Issue
+	issueSeq    map[string]int64
+	nextSeq     int64
+	nextIssueID int
+	missions    map[string]*missionState
+	deps        []*types.Dependency
+	labels      map[string]map[string]bool
+	events      []*types.Event
+	nextEventID int64
+	comments    map[int64]*commentState
+	execStates  map[string]*types.IssueExecutionState
+	attachments []*types.Attachment
+	blobs       map[string][]byte
+	audit       []*types.AuditEntry
+	nextAuditID int64
+}
+
+// takeSnapshot copies the transactional state. Caller must hold s.mu.
+func (s *Store) takeSnapshot() *snapshot {
+	snap := &snapshot{
+		issues:      make(map[string]*types.Issue, len(s.issues)),
+		issueSeq:    make(map[string]int64, len(s.issueSeq)),
+		nextSeq:     s.nextSeq,
+		nextIssueID: s.nextIssueID,
+		missions:    make(map[string]*missionState, len(s.missions)),
+		deps:        make([]*types.Dependency, len(s.deps)),
+		labels:      make(map[string]map[string]bool, len(s.labels)),
+		events:      append([]*types.Event(nil), s.events...),
+		nextEventID: s.nextEventID,
+		comments:    make(map[int64]*commentState, len(s.comments)),
+		execStates:  make(map[string]*types.IssueExecutionState, len(s.execStates)),
+		// Attachments and blobs are never modified in place, so shallow copies suffice
+		attachments: append([]*types.Attachment(nil), s.attachments...),
+		blobs:       make(map[string][]byte, len(s.blobs)),
+		// Audit entries are append-only
+		audit:       append([]*types.AuditEntry(nil), s.audit...),
+		nextAuditID: s.nextAuditID,
+	}
+	for hash, data := range s.blobs {
+		snap.blobs[hash] = data
+	}
+	for id, issue := range s.issues {
+		snap.issues[id] = copyIssue(issue)
+	}
+	for id, seq := range s.issueSeq {
+		snap.issueSeq[id] = seq
+	}
+	for id, m := range s.missions {
+		mCopy := *m
+		snap.missions[id] = &mCopy
+	}
+	for i, dep := range s.deps {
+		depCopy := *dep
+		snap.deps[i] = &depCopy
+	}
+	for id, set := range s.labels {
+		setCopy := make(map[string]bool, len(set))
+		for label := range set {
+			setCopy[label] = true
+		}
+		snap.labels[id] = setCopy
+	}
+	for id, state := range s.execStates {
+		snap.execStates[id] = copyExecutionState(state)
+	}
+	for id, state := range s.comments {
+		snap.comments[id] = state.copy()
+	}
+	return snap
+}
+
+// restoreSnapshot rolls the transactional state back. Caller must hold s.mu.
+func (s *Store) restoreSnapshot(snap *snapshot) {
+	s.issues = snap.issues
+	s.issueSeq = snap.issueSeq
+	s.nextSeq = snap.nextSeq
+	s.nextIssueID = snap.nextIssueID
+	s.missions = snap.missions
+	s.deps = snap.deps
+	s.labels = snap.labels
+	s.events = snap.events
+	s.nextEventID = snap.nextEventID
+	s.comments = snap.comments
+	s.execStates = snap.execStates
+	s.attachments = snap.attachments
+	s.blobs = snap.blobs
+	s.audit = snap.audit
+	s.nextAuditID = snap.nextAuditID
+}
+
+// atomically runs fn and rolls back its changes if it fails or panics.
+// Caller must hold s.mu.
+func (s *Store) atomically(fn func() error) (err error) {
+	snap := s.takeSnapshot()
+	defer func() {
+		if r := recover(); r != nil {
+			s.restoreSnapshot(snap)
+			panic(r)
+		}
+		if err != nil {
+			s.restoreSnapshot(snap)
+		}
+	}()
+	return fn()
+}
+
+// RunInVCTransaction executes fn atomically. If fn returns an error or panics,
+// every change made through tx is rolled back.
+//
+// fn must only use tx: the store is locked for the duration of the transaction.
+func (s *Store) RunInVCTransaction(ctx context.Context, fn func(tx *beads.VCTransaction) error) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	return s.atomically(func() error {
+		return fn(beads.NewVCTransaction(&memTx{s: s}))
+	})
+}
+
+// memTx implements beads.TxBackend on top of the locked store.
+// Like Beads transactions, it skips VC-level validation (acceptance criteria,
+// execution state cleanup on close).
+type memTx struct {
+	s *Store
+}
+
+func (t *memTx) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	return t.s.createIssueLocked(issue, actor)
+}
+
+func (t *memTx) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	for _, issue := range issues {
+		if err := t.s.createIssueLocked(issue, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *memTx) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	return t.s.addDependencyLocked(dep, actor)
+}
+
+func (t *memTx) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	return t.s.removeDependencyLocked(issueID, dependsOnID, actor)
+}
+
+func (t *memTx) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	return t.s.addLabelLocked(issueID, label, actor)
+}
+
+func (t *memTx) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	t.s.removeLabelLocked(issueID, label, actor)
+	return nil
+}
+
+func (t *memTx) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	return t.s.updateIssueLocked(id, updates, actor)
+}
+
+func (t *memTx) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	return t.s.closeIssueLocked(id, reason, actor)
+}
+
+func (t *memTx) DeleteIssue(ctx context.Context, id string) error {
+	return t.s.deleteIssueLocked(id)
+}
+
+func (t *memTx) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	return t.s.getIssueLocked(id), nil
+}
+
+// ======================================================================
+// COPY HELPERS
+// ======================================================================
+
+// Stored values are never handed out directly, so callers can't mutate
+// store state by modifying returned structs.
+
+func copyIssue(issue *types.Issue) *types.Issue {
+	if issue == nil {
+		return nil
+	}
+	c := *issue
+	if issue.EstimatedMinutes != nil {
+		minutes := *issue.EstimatedMinutes
+		c.EstimatedMinutes = &minutes
+	}
+	if issue.ClosedAt != nil {
+		closedAt := *issue.ClosedAt
+		c.ClosedAt = &closedAt
+	}
+	if issue.MissionContext != nil {
+		mc := *issue.MissionContext
+		c.MissionContext = &mc
+	}
+	return &c
+}
+
+func copyExecutionState(state *types.IssueExecutionState) *types.IssueExecutionState {
+	if state == nil {
+		return nil
+	}
+	c := *state
+	if state.LastInterventionTime != nil {
+		t := *state.LastInterventionTime
+		c.LastInterventionTime = &t
+	}
+	if state.LeaseExpiresAt != nil {
+		t := *state.LeaseExpiresAt
+		c.LeaseExpiresAt = &t
+	}
+	return &c
+}
+
+// copyAgentEvent copies an event, round-tripping Data through JSON the way
+// the SQLite backend does (so numbers come back as float64)
+func copyAgentEvent(event *events.AgentEvent) (*events.AgentEvent, error) {
+	c := *event
+	if event.Data != nil {
+		raw, err := json.Marshal(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event data: %w", err)
+		}
+		c.Data = nil
+		if err := json.Unmarshal(raw, &c.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+		}
+	}
+	return &c, nil
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,1310 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func newTask(title string, priority int) *types.Issue {
+	return &types.Issue{
+		Title:              title,
+		Status:             types.StatusOpen,
+		Priority:           priority,
+		IssueType:          types.TypeTask,
+		AcceptanceCriteria: "It works",
+	}
+}
+
+func mustCreate(t *testing.T, store *Store, issue *types.Issue) *types.Issue {
+	t.Helper()
+	if err := store.CreateIssue(context.Background(), issue, "test"); err != nil {
+		t.Fatalf("CreateIssue(%q) failed: %v", issue.Title, err)
+	}
+	return issue
+}
+
+func TestCreateAndGetIssue(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	issue := mustCreate(t, store, newTask("First", 1))
+	if issue.ID != "vc-1" {
+		t.Errorf("expected generated ID vc-1, got %s", issue.ID)
+	}
+
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Title != "First" || got.CreatedAt.IsZero() {
+		t.Errorf("unexpected issue: %+v", got)
+	}
+
+	// Returned issues are copies
+	got.Title = "Mutated"
+	again, _ := store.GetIssue(ctx, issue.ID)
+	if again.Title != "First" {
+		t.Errorf("mutating a returned issue changed the store: %s", again.Title)
+	}
+
+	missing, err := store.GetIssue(ctx, "vc-999")
+	if err != nil || missing != nil {
+		t.Errorf("expected nil, nil for missing issue, got %v, %v", missing, err)
+	}
+
+	// Acceptance criteria are required for tasks (vc-e3j2)
+	noAC := newTask("No criteria", 1)
+	noAC.AcceptanceCriteria = ""
+	if err := store.CreateIssue(ctx, noAC, "test"); err == nil {
+		t.Error("expected error creating task without acceptance criteria")
+	}
+
+	// Explicit IDs must use the configured prefix
+	wrongPrefix := newTask("Wrong prefix", 1)
+	wrongPrefix.ID = "bd-1"
+	if err := store.CreateIssue(ctx, wrongPrefix, "test"); err == nil {
+		t.Error("expected error for ID with wrong prefix")
+	}
+}
+
+func TestUpdateAndCloseIssue(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	issue := mustCreate(t, store, newTask("Task", 2))
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"bogus": 1}, "test"); err == nil ||
+		!strings.Contains(err.Error(), "invalid field for update") {
+		t.Errorf("expected invalid field error, got %v", err)
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 7}, "test"); err == nil {
+		t.Error("expected error for out-of-range priority")
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": "in_progress", "assignee": "alice"}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	closed, _ := store.GetIssue(ctx, issue.ID)
+	if closed.Status != types.StatusClosed || closed.ClosedAt == nil {
+		t.Errorf("expected closed issue with closed_at, got %s %v", closed.Status, closed.ClosedAt)
+	}
+	if closed.Assignee != "" {
+		t.Errorf("expected assignee cleared on close, got %q", closed.Assignee)
+	}
+
+	// Reopening clears closed_at
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": types.StatusOpen}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	reopened, _ := store.GetIssue(ctx, issue.ID)
+	if reopened.ClosedAt != nil {
+		t.Error("expected closed_at cleared on reopen")
+	}
+
+	evts, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(evts) == 0 || evts[0].EventType != types.EventReopened {
+		t.Errorf("expected newest event to be reopened, got %+v", evts)
+	}
+}
+
+func TestDependenciesAndReadyWork(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	blocker := mustCreate(t, store, newTask("Blocker", 2))
+	blocked := mustCreate(t, store, newTask("Blocked", 0))
+	free := mustCreate(t, store, newTask("Free", 1))
+	skip := mustCreate(t, store, newTask("Skip", 1))
+	if err := store.AddLabel(ctx, skip.ID, "no-auto-claim", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	cycle := &types.Dependency{IssueID: blocker.ID, DependsOnID: blocked.ID, Type: types.DepRelated}
+	if err := store.AddDependency(ctx, cycle, "test"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{SortPolicy: types.SortPolicyPriority})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if ids := issueIDs(ready); ids != fmt.Sprintf("%s,%s", free.ID, blocker.ID) {
+		t.Errorf("unexpected ready work: %s", ids)
+	}
+
+	blockedIssues, err := store.GetBlockedIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(blockedIssues) != 1 || blockedIssues[0].ID != blocked.ID || blockedIssues[0].BlockedByCount != 1 {
+		t.Errorf("unexpected blocked issues: %+v", blockedIssues)
+	}
+	if blockers, _ := store.GetBlockers(ctx, blocked.ID); issueIDs(blockers) != blocker.ID {
+		t.Errorf("unexpected blockers: %s", issueIDs(blockers))
+	}
+	if dependents, _ := store.GetBlocked(ctx, blocker.ID); issueIDs(dependents) != blocked.ID {
+		t.Errorf("unexpected blocked-by: %s", issueIDs(dependents))
+	}
+
+	// Closing the blocker unblocks the dependent
+	if err := store.CloseIssue(ctx, blocker.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if blockers, _ := store.GetBlockers(ctx, blocked.ID); len(blockers) != 0 {
+		t.Errorf("closed blocker still reported: %s", issueIDs(blockers))
+	}
+	ready, _ = store.GetReadyWork(ctx, types.WorkFilter{SortPolicy: types.SortPolicyPriority})
+	if ids := issueIDs(ready); ids != fmt.Sprintf("%s,%s", blocked.ID, free.ID) {
+		t.Errorf("unexpected ready work after close: %s", ids)
+	}
+
+	tree, err := store.GetDependencyTree(ctx, blocked.ID, 0)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	if len(tree) != 2 || tree[1].ID != blocker.ID || tree[1].Depth != 1 {
+		t.Errorf("unexpected tree: %+v", tree)
+	}
+}
+
+func TestMissionContext(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:        "Mission",
+			Status:       types.StatusOpen,
+			Priority:     1,
+			IssueType:    types.TypeEpic,
+			IssueSubtype: types.SubtypeMission,
+		},
+		Goal:        "Ship it",
+		SandboxPath: "/tmp/sandbox",
+		BranchName:  "mission/ship-it",
+	}
+	if err := store.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("CreateMission failed: %v", err)
+	}
+	task := mustCreate(t, store, newTask("Task", 1))
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: mission.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].MissionContext == nil || ready[0].MissionContext.BranchName != "mission/ship-it" {
+		t.Fatalf("expected task with mission context, got %+v", ready)
+	}
+
+	if err := store.UpdateMission(ctx, mission.ID, map[string]interface{}{"goal": "Ship it twice", "priority": 0}, "test"); err != nil {
+		t.Fatalf("UpdateMission failed: %v", err)
+	}
+	got, err := store.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetMission failed: %v", err)
+	}
+	if got.Goal != "Ship it twice" || got.Priority != 0 {
+		t.Errorf("unexpected mission after update: goal=%q priority=%d", got.Goal, got.Priority)
+	}
+
+	// Missions waiting on quality gates don't hand out work (vc-239)
+	if err := store.AddLabel(ctx, mission.ID, "needs-quality-gates", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	ready, _ = store.GetReadyWork(ctx, types.WorkFilter{})
+	if len(ready) != 0 {
+		t.Errorf("expected no ready work while mission needs gates, got %d", len(ready))
+	}
+	needingGates, _ := store.GetMissionsNeedingGates(ctx)
+	if len(needingGates) != 1 || needingGates[0].ID != mission.ID {
+		t.Errorf("unexpected missions needing gates: %+v", needingGates)
+	}
+
+	agentEvents, _ := store.GetAgentEventsByIssue(ctx, mission.ID)
+	if len(agentEvents) != 2 || agentEvents[0].Type != events.EventTypeMissionCreated {
+		t.Errorf("expected mission_created and mission_metadata_updated events, got %d", len(agentEvents))
+	}
+}
+
+func TestClaimAndExecutionState(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	issue := mustCreate(t, store, newTask("Claim me", 1))
+
+	if err := store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateExecuting); err == nil {
+		t.Error("expected error for invalid initial state")
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, "exec-1"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, "exec-2"); err == nil || !strings.Contains(err.Error(), "already claimed by exec-1") {
+		t.Errorf("expected already claimed error, got %v", err)
+	}
+	claimed, _ := store.GetIssue(ctx, issue.ID)
+	if claimed.Status != types.StatusInProgress {
+		t.Errorf("expected in_progress after claim, got %s", claimed.Status)
+	}
+
+	if err := store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateGates); err == nil {
+		t.Error("expected error skipping states")
+	}
+	if err := store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateAssessing); err != nil {
+		t.Fatalf("UpdateExecutionState failed: %v", err)
+	}
+
+	if err := store.ReleaseIssueAndReopen(ctx, issue.ID, "test", "agent crashed"); err != nil {
+		t.Fatalf("ReleaseIssueAndReopen failed: %v", err)
+	}
+	state, _ := store.GetExecutionState(ctx, issue.ID)
+	if state.State != types.ExecutionStateFailed || state.ErrorMessage != "agent crashed" {
+		t.Errorf("unexpected execution state: %+v", state)
+	}
+	reopened, _ := store.GetIssue(ctx, issue.ID)
+	if reopened.Status != types.StatusOpen {
+		t.Errorf("expected reopened issue, got %s", reopened.Status)
+	}
+}
+
+func TestClaimNextReadyIssue(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	low := mustCreate(t, store, newTask("Low priority", 2))
+	high := mustCreate(t, store, newTask("High priority", 0))
+
+	first, err := store.ClaimNextReadyIssue(ctx, "worker-1", time.Minute, "")
+	if err != nil {
+		t.Fatalf("ClaimNextReadyIssue failed: %v", err)
+	}
+	if first == nil || first.ID != high.ID || first.Status != types.StatusInProgress {
+		t.Fatalf("expected %s claimed in_progress, got %+v", high.ID, first)
+	}
+	second, _ := store.ClaimNextReadyIssue(ctx, "worker-2", time.Minute, "")
+	if second == nil || second.ID != low.ID {
+		t.Fatalf("expected %s for second worker, got %+v", low.ID, second)
+	}
+	if none, _ := store.ClaimNextReadyIssue(ctx, "worker-3", time.Minute, ""); none != nil {
+		t.Errorf("expected nothing left to claim, got %s", none.ID)
+	}
+
+	if err := store.RenewClaimLease(ctx, high.ID, "worker-2", time.Minute); err == nil {
+		t.Error("expected error renewing another worker's lease")
+	}
+
+	// worker-1 stops renewing: once its lease lapses worker-3 takes over
+	if err := store.RenewClaimLease(ctx, high.ID, "worker-1", time.Nanosecond); err != nil {
+		t.Fatalf("RenewClaimLease failed: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	takenOver, err := store.ClaimNextReadyIssue(ctx, "worker-3", time.Minute, "")
+	if err != nil {
+		t.Fatalf("ClaimNextReadyIssue failed: %v", err)
+	}
+	if takenOver == nil || takenOver.ID != high.ID {
+		t.Fatalf("expected expired lease on %s to be taken over, got %+v", high.ID, takenOver)
+	}
+	state, _ := store.GetExecutionState(ctx, high.ID)
+	if state.ExecutorInstanceID != "worker-3" || state.LeaseExpiresAt == nil || !state.LeaseExpiresAt.After(time.Now()) {
+		t.Errorf("unexpected execution state after takeover: %+v", state)
+	}
+	if err := store.RenewClaimLease(ctx, high.ID, "worker-1", time.Minute); err == nil {
+		t.Error("expected error renewing a lease that was taken over")
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	issue := mustCreate(t, store, newTask("Audited", 2))
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Renamed", "priority": 1}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, "exec-1"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "done", "bob"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	entries, err := store.GetAuditLog(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	// Newest first: close, claim, then the update's two fields (in reverse field order)
+	if len(entries) < 6 {
+		t.Fatalf("expected at least 6 entries, got %d", len(entries))
+	}
+	if entries[0].Field != "closed_at" || entries[0].Actor != "bob" {
+		t.Errorf("expected newest entry to be bob setting closed_at, got %+v", entries[0])
+	}
+	if entries[1].Field != "status" || entries[1].OldValue != "in_progress" || entries[1].NewValue != "closed" {
+		t.Errorf("expected in_progress -> closed, got %+v", entries[1])
+	}
+	if entries[2].Field != "status" || entries[2].Actor != "exec-1" || entries[2].NewValue != "in_progress" {
+		t.Errorf("expected claim to be audited, got %+v", entries[2])
+	}
+	if entries[3].Field != "priority" || entries[3].OldValue != "2" || entries[3].NewValue != "1" {
+		t.Errorf("expected priority change, got %+v", entries[3])
+	}
+	if entries[4].Field != "title" || entries[4].OldValue != "Audited" || entries[4].NewValue != "Renamed" {
+		t.Errorf("expected title change, got %+v", entries[4])
+	}
+	if last := entries[len(entries)-1]; last.Operation != types.AuditCreate || last.Actor != "test" {
+		t.Errorf("expected oldest entry to be the create, got %+v", last)
+	}
+
+	if limited, _ := store.GetAuditLog(ctx, issue.ID, 2); len(limited) != 2 {
+		t.Errorf("expected limit to apply, got %d entries", len(limited))
+	}
+
+	// Deleting the issue keeps (and extends) its history; rolled back changes leave none
+	err = store.RunInVCTransaction(ctx, func(tx *beads.VCTransaction) error {
+		return tx.DeleteIssue(ctx, issue.ID)
+	})
+	if err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	afterDelete, _ := store.GetAuditLog(ctx, issue.ID, 0)
+	if len(afterDelete) <= len(entries) || afterDelete[0].Operation != types.AuditDelete {
+		t.Errorf("expected delete entries on top of %d, got %+v", len(entries), afterDelete[0])
+	}
+
+	other := mustCreate(t, store, newTask("Rolled back", 2))
+	_ = store.RunInVCTransaction(ctx, func(tx *beads.VCTransaction) error {
+		if err := tx.UpdateIssue(ctx, other.ID, map[string]interface{}{"title": "Never"}, "alice"); err != nil {
+			return err
+		}
+		return fmt.Errorf("abort")
+	})
+	otherEntries, _ := store.GetAuditLog(ctx, other.ID, 0)
+	for _, entry := range otherEntries {
+		if entry.Operation != types.AuditCreate {
+			t.Errorf("expected rolled back update to leave no audit entry, got %+v", entry)
+		}
+	}
+}
+
+func TestCleanupStaleInstances(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	issue := mustCreate(t, store, newTask("Orphan", 1))
+
+	instance := &types.ExecutorInstance{
+		InstanceID:    "exec-1",
+		Status:        types.ExecutorStatusRunning,
+		StartedAt:     time.Now().Add(-time.Hour),
+		LastHeartbeat: time.Now().Add(-time.Hour),
+	}
+	if err := store.RegisterInstance(ctx, instance); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, "exec-1"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+
+	cleaned, err := store.CleanupStaleInstances(ctx, 300)
+	if err != nil {
+		t.Fatalf("CleanupStaleInstances failed: %v", err)
+	}
+	if cleaned != 1 {
+		t.Errorf("expected 1 instance cleaned, got %d", cleaned)
+	}
+	released, _ := store.GetIssue(ctx, issue.ID)
+	if released.Status != types.StatusOpen {
+		t.Errorf("expected released issue to be open, got %s", released.Status)
+	}
+	active, _ := store.GetActiveInstances(ctx)
+	if len(active) != 0 {
+		t.Errorf("expected no active instances, got %d", len(active))
+	}
+}
+
+func TestPlansOptimisticLocking(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	plan := &types.MissionPlan{
+		MissionID: "vc-1",
+		Phases: []types.PlannedPhase{{
+			PhaseNumber:     1,
+			Title:           "Phase 1",
+			Description:     "First phase",
+			Strategy:        "Small steps",
+			Tasks:           []string{"task1"},
+			EstimatedEffort: "1 day",
+		}},
+		Strategy:        "Incremental",
+		EstimatedEffort: "1 day",
+		Status:          "draft",
+	}
+
+	iteration, err := store.StorePlan(ctx, plan, 0)
+	if err != nil || iteration != 1 {
+		t.Fatalf("StorePlan = %d, %v; want 1, nil", iteration, err)
+	}
+	if iteration, err = store.StorePlan(ctx, plan, 1); err != nil || iteration != 2 {
+		t.Fatalf("StorePlan = %d, %v; want 2, nil", iteration, err)
+	}
+	if _, err := store.StorePlan(ctx, plan, 1); !errors.Is(err, beads.ErrStaleIteration) {
+		t.Errorf("expected ErrStaleIteration, got %v", err)
+	}
+
+	drafts, _ := store.ListDraftPlans(ctx)
+	if len(drafts) != 1 {
+		t.Errorf("expected 1 draft plan, got %d", len(drafts))
+	}
+	plan.Status = "approved"
+	if _, err := store.StorePlan(ctx, plan, 0); err != nil {
+		t.Fatalf("StorePlan failed: %v", err)
+	}
+	if drafts, _ = store.ListDraftPlans(ctx); len(drafts) != 0 {
+		t.Errorf("expected no draft plans after approval, got %d", len(drafts))
+	}
+}
+
+func TestTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	existing := mustCreate(t, store, newTask("Existing", 1))
+
+	var createdID string
+	err := store.RunInVCTransaction(ctx, func(tx *beads.VCTransaction) error {
+		issue := newTask("In transaction", 1)
+		if err := tx.CreateIssue(ctx, issue, "test"); err != nil {
+			return err
+		}
+		createdID = issue.ID
+		if err := tx.AddLabel(ctx, existing.ID, "touched", "test"); err != nil {
+			return err
+		}
+		return fmt.Errorf("abort")
+	})
+	if err == nil || err.Error() != "abort" {
+		t.Fatalf("expected abort error, got %v", err)
+	}
+
+	if issue, _ := store.GetIssue(ctx, createdID); issue != nil {
+		t.Error("expected issue created in rolled-back transaction to be gone")
+	}
+	if labels, _ := store.GetLabels(ctx, existing.ID); len(labels) != 0 {
+		t.Errorf("expected label to be rolled back, got %v", labels)
+	}
+
+	err = store.RunInVCTransaction(ctx, func(tx *beads.VCTransaction) error {
+		return tx.AddLabel(ctx, existing.ID, "kept", "test")
+	})
+	if err != nil {
+		t.Fatalf("RunInVCTransaction failed: %v", err)
+	}
+	if labels, _ := store.GetLabels(ctx, existing.ID); len(labels) != 1 || labels[0] != "kept" {
+		t.Errorf("expected committed label, got %v", labels)
+	}
+}
+
+func TestAgentEventRetention(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	old := time.Now().AddDate(0, 0, -40)
+
+	for i, severity := range []events.EventSeverity{events.SeverityInfo, events.SeverityWarning, events.SeverityError} {
+		event := &events.AgentEvent{
+			Type:      events.EventTypeProgress,
+			Timestamp: old.Add(time.Duration(i) * time.Minute),
+			IssueID:   "vc-1",
+			Severity:  severity,
+			Data:      map[string]interface{}{"count": i},
+		}
+		if err := store.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("StoreAgentEvent failed: %v", err)
+		}
+	}
+
+	recent, _ := store.GetRecentAgentEvents(ctx, 10)
+	if len(recent) != 3 || recent[0].Severity != events.SeverityError {
+		t.Fatalf("expected 3 events newest first, got %+v", recent)
+	}
+	if _, ok := recent[0].Data["count"].(float64); !ok {
+		t.Errorf("expected event data to round-trip through JSON, got %T", recent[0].Data["count"])
+	}
+
+	if _, err := store.CleanupEventsByAge(ctx, -1, 90, 100); err == nil {
+		t.Error("expected error for negative retention")
+	}
+	deleted, err := store.CleanupEventsByAge(ctx, 30, 90, 100)
+	if err != nil {
+		t.Fatalf("CleanupEventsByAge failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 non-critical events deleted, got %d", deleted)
+	}
+	counts, _ := store.GetEventCounts(ctx)
+	if counts.TotalEvents != 1 || counts.EventsBySeverity["error"] != 1 {
+		t.Errorf("unexpected counts after cleanup: %+v", counts)
+	}
+}
+
+func TestCloseStore(t *testing.T) {
+	store := New()
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := store.GetIssue(context.Background(), "vc-1"); err == nil {
+		t.Error("expected error using a closed store")
+	}
+}
+
+func issueIDs(issues []*types.Issue) string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	src := New()
+
+	parent := mustCreate(t, src, newTask("Parent", 1))
+	child := mustCreate(t, src, newTask("Child", 2))
+	if err := src.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: parent.ID, Type: types.DepBlocks}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := src.AddLabel(ctx, child.ID, "frontend", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := src.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{IssueID: child.ID, AttemptNumber: 1, StartedAt: time.Now()}); err != nil {
+		t.Fatalf("RecordExecutionAttempt failed: %v", err)
+	}
+
+	var dump strings.Builder
+	if err := src.Export(ctx, &dump); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	dst := New()
+	stats, err := dst.Import(ctx, strings.NewReader(dump.String()))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if stats.Issues != 2 || stats.Dependencies != 1 || stats.Labels != 1 || stats.Executions != 1 || stats.Events == 0 {
+		t.Errorf("unexpected import stats: %+v", stats)
+	}
+
+	// The imported blocker still blocks
+	ready, err := dst.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != parent.ID {
+		t.Errorf("expected only %s ready after import, got %v", parent.ID, ready)
+	}
+
+	// Generated IDs don't collide with imported ones
+	fresh := mustCreate(t, dst, newTask("Fresh", 2))
+	if fresh.ID == parent.ID || fresh.ID == child.ID {
+		t.Errorf("generated ID %s collides with an imported issue", fresh.ID)
+	}
+
+	stats, err = dst.Import(ctx, strings.NewReader(dump.String()))
+	if err != nil {
+		t.Fatalf("re-Import failed: %v", err)
+	}
+	if stats.Issues != 0 || stats.SkippedIssues != 2 || stats.Events != 0 || stats.Executions != 0 {
+		t.Errorf("re-import should be a no-op, got %+v", stats)
+	}
+
+	if _, err := dst.Import(ctx, strings.NewReader(`{"kind":"issue"}`)); err == nil {
+		t.Error("expected error importing a dump without a header")
+	}
+}
+
+func TestAttachments(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	issue := mustCreate(t, store, newTask("Task", 1))
+
+	log := []byte("FAIL: TestSomething\n")
+	first := &types.Attachment{IssueID: issue.ID, Name: "test output", Kind: types.AttachmentGateLog}
+	if err := store.AddAttachment(ctx, first, log); err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+	second := &types.Attachment{IssueID: issue.ID, Name: "retry output", Kind: types.AttachmentGateLog}
+	if err := store.AddAttachment(ctx, second, log); err != nil {
+		t.Fatalf("AddAttachment failed: %v", err)
+	}
+	if first.ID == second.ID || first.ContentHash != second.ContentHash || first.Size != int64(len(log)) {
+		t.Errorf("unexpected attachment metadata: %+v %+v", first, second)
+	}
+	if len(store.blobs) != 1 {
+		t.Errorf("identical content should be stored once, got %d blobs", len(store.blobs))
+	}
+
+	bad := &types.Attachment{IssueID: "vc-missing", Name: "x", Kind: types.AttachmentOther}
+	if err := store.AddAttachment(ctx, bad, log); err == nil {
+		t.Error("expected error attaching to a missing issue")
+	}
+
+	list, err := store.ListAttachments(ctx, issue.ID)
+	if err != nil || len(list) != 2 || list[0].ID != first.ID {
+		t.Fatalf("unexpected attachment list: %+v %v", list, err)
+	}
+	got, data, err := store.GetAttachment(ctx, second.ID)
+	if err != nil || got == nil || got.Name != "retry output" || string(data) != string(log) {
+		t.Errorf("GetAttachment = %+v %q %v", got, data, err)
+	}
+
+	// The blob survives until its last reference is gone
+	if err := store.DeleteAttachment(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	if _, data, _ := store.GetAttachment(ctx, second.ID); string(data) != string(log) {
+		t.Error("deleting one attachment removed shared content")
+	}
+	if err := store.DeleteAttachment(ctx, second.ID); err != nil {
+		t.Fatalf("DeleteAttachment failed: %v", err)
+	}
+	if len(store.blobs) != 0 {
+		t.Errorf("unreferenced blob not removed")
+	}
+	if got, _, _ := store.GetAttachment(ctx, second.ID); got != nil {
+		t.Error("expected nil for deleted attachment")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := New()
+
+	before := mustCreate(t, store, newTask("Before watch", 1))
+	changes, err := store.Watch(ctx, types.WatchFilter{
+		Kinds:      []types.ChangeKind{types.ChangeIssue},
+		EventTypes: []types.EventType{types.EventCreated, types.EventClosed},
+	})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	// Filtered out: comment on an old issue
+	if err := store.AddComment(ctx, before.ID, "test", "noise"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	after := mustCreate(t, store, newTask("After watch", 1))
+	if err := store.CloseIssue(ctx, before.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	want := []struct {
+		id        string
+		eventType types.EventType
+	}{{after.ID, types.EventCreated}, {before.ID, types.EventClosed}}
+	for _, w := range want {
+		select {
+		case change := <-changes:
+			if change.IssueID != w.id || change.EventType != w.eventType || change.Kind != types.ChangeIssue {
+				t.Errorf("got change %+v, want %s %s", change, w.id, w.eventType)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s %s", w.id, w.eventType)
+		}
+	}
+
+	// Closing the store ends the watch
+	_ = store.Close()
+	select {
+	case change, ok := <-changes:
+		if ok {
+			t.Errorf("unexpected change after close: %+v", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch channel not closed after store Close")
+	}
+}
+
+func TestLabelDefinitions(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	a := mustCreate(t, store, newTask("A", 1))
+	b := mustCreate(t, store, newTask("B", 1))
+	for _, id := range []string{a.ID, b.ID} {
+		if err := store.AddLabel(ctx, id, "frontend", "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	if err := store.AddLabel(ctx, b.ID, "ui", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	def := &types.LabelDefinition{Name: "frontend", Description: "UI work", Color: "#1f77b4"}
+	if err := store.SaveLabelDefinition(ctx, def); err != nil {
+		t.Fatalf("SaveLabelDefinition failed: %v", err)
+	}
+	if err := store.SaveLabelDefinition(ctx, &types.LabelDefinition{Name: "x", Color: "blue"}); err == nil {
+		t.Error("expected invalid color to be rejected")
+	}
+
+	// Issues that already carry the new name just lose the old one
+	count, err := store.RenameLabel(ctx, "frontend", "ui", "alice")
+	if err != nil {
+		t.Fatalf("RenameLabel failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 issues renamed, got %d", count)
+	}
+	if labels, _ := store.GetLabels(ctx, b.ID); len(labels) != 1 || labels[0] != "ui" {
+		t.Errorf("expected b to have only ui, got %v", labels)
+	}
+	renamed, err := store.GetLabelDefinition(ctx, "ui")
+	if err != nil || renamed == nil {
+		t.Fatalf("GetLabelDefinition failed: %v", err)
+	}
+	if renamed.Description != "UI work" || renamed.IssueCount != 2 {
+		t.Errorf("expected definition to follow the rename, got %+v", renamed)
+	}
+	if old, _ := store.GetLabelDefinition(ctx, "frontend"); old != nil {
+		t.Errorf("expected frontend to be gone, got %+v", old)
+	}
+
+	for _, name := range []string{"no-auto-claim", "needs-approval"} {
+		if _, err := store.DeleteLabel(ctx, name, "alice"); !errors.Is(err, types.ErrSystemLabel) {
+			t.Errorf("expected deleting %s to fail with ErrSystemLabel, got %v", name, err)
+		}
+	}
+	if _, err := store.RenameLabel(ctx, "ui", "escalated", "alice"); !errors.Is(err, types.ErrSystemLabel) {
+		t.Errorf("expected renaming onto a system label to fail, got %v", err)
+	}
+
+	count, err = store.DeleteLabel(ctx, "ui", "alice")
+	if err != nil || count != 2 {
+		t.Fatalf("DeleteLabel = %d, %v; want 2, nil", count, err)
+	}
+	if labels, _ := store.GetLabels(ctx, a.ID); len(labels) != 0 {
+		t.Errorf("expected no labels left, got %v", labels)
+	}
+
+	defs, err := store.ListLabelDefinitions(ctx)
+	if err != nil {
+		t.Fatalf("ListLabelDefinitions failed: %v", err)
+	}
+	if len(defs) != len(types.SystemLabels) {
+		t.Errorf("expected only system labels to remain, got %d definitions", len(defs))
+	}
+}
+
+func TestGetEpicRollup(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	thirty := 30
+
+	epic := mustCreate(t, store, &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic})
+	done := newTask("Done", 1)
+	done.EstimatedMinutes = &thirty
+	done = mustCreate(t, store, done)
+	waiting := mustCreate(t, store, newTask("Waiting", 1))
+	blocker := mustCreate(t, store, newTask("Blocker", 0))
+	for _, dep := range []*types.Dependency{
+		{IssueID: done.ID, DependsOnID: epic.ID, Type: types.DepParentChild},
+		{IssueID: waiting.ID, DependsOnID: epic.ID, Type: types.DepParentChild},
+		{IssueID: waiting.ID, DependsOnID: blocker.ID, Type: types.DepBlocks},
+	} {
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	if err := store.CloseIssue(ctx, done.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	now := time.Now()
+	if err := store.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{
+		IssueID: done.ID, ExecutorInstanceID: "exec-1", AttemptNumber: 1,
+		StartedAt: now.Add(-20 * time.Minute), CompletedAt: &now,
+	}); err != nil {
+		t.Fatalf("RecordExecutionAttempt failed: %v", err)
+	}
+	for _, passed := range []bool{true, false} {
+		if err := store.StoreAgentEvent(ctx, &events.AgentEvent{
+			Type: events.EventTypeQualityGatesCompleted, Timestamp: now, IssueID: done.ID,
+			Severity: events.SeverityInfo, Data: map[string]interface{}{"all_passed": passed},
+		}); err != nil {
+			t.Fatalf("StoreAgentEvent failed: %v", err)
+		}
+	}
+
+	rollup, err := store.GetEpicRollup(ctx, epic.ID)
+	if err != nil {
+		t.Fatalf("GetEpicRollup failed: %v", err)
+	}
+	if rollup.Children != 2 || rollup.Closed() != 1 || rollup.EstimatedMinutes != 30 || rollup.UnestimatedChildren != 1 {
+		t.Errorf("unexpected rollup counts: %+v", rollup)
+	}
+	if rollup.ActualMinutes < 19.9 || rollup.ActualMinutes > 20.1 {
+		t.Errorf("expected ~20 actual minutes, got %.2f", rollup.ActualMinutes)
+	}
+	if len(rollup.OpenBlockers) != 1 || rollup.OpenBlockers[0] != blocker.ID {
+		t.Errorf("expected open blocker %s, got %v", blocker.ID, rollup.OpenBlockers)
+	}
+	if rollup.GatePassRate() != 0.5 {
+		t.Errorf("expected gate pass rate 0.5, got %v", rollup.GatePassRate())
+	}
+}
+
+func TestCompactIssueEvents(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+
+	old := mustCreate(t, store, newTask("Closed long ago", 1))
+	open := mustCreate(t, store, newTask("Still open", 1))
+	for _, issue := range []*types.Issue{old, open} {
+		for _, comment := range []string{
+			"AI Usage (assessment): input=100 tokens, output=20 tokens, duration=1s, model=test",
+			"Agent output: running tests",
+		} {
+			if err := store.AddComment(ctx, issue.ID, "ai-supervisor", comment); err != nil {
+				t.Fatalf("AddComment failed: %v", err)
+			}
+		}
+	}
+	if err := store.CloseIssue(ctx, old.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	longAgo := time.Now().AddDate(0, 0, -200)
+	store.issues[old.ID].ClosedAt = &longAgo
+	for _, event := range store.events {
+		event.CreatedAt = longAgo
+	}
+
+	compacted, err := store.CompactIssueEvents(ctx, 90, 100)
+	if err != nil {
+		t.Fatalf("CompactIssueEvents failed: %v", err)
+	}
+	// Both comments plus the update recorded by CloseIssue
+	if compacted != 3 {
+		t.Errorf("compacted = %d, want 3", compacted)
+	}
+
+	evts, err := store.GetEvents(ctx, old.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	var summary string
+	for _, event := range evts {
+		switch {
+		case event.Actor == types.EventCompactionActor:
+			summary = *event.Comment
+		case event.EventType != types.EventCreated && event.EventType != types.EventClosed:
+			t.Errorf("event %s was not compacted", event.EventType)
+		}
+	}
+	if !strings.Contains(summary, "Compacted 3 events") || !strings.Contains(summary, "AI usage: 1 call(s), input=100 tokens, output=20 tokens") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	evts, err = store.GetEvents(ctx, open.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(evts) != 3 {
+		t.Errorf("open issue has %d events, want 3 (not compacted)", len(evts))
+	}
+
+	if compacted, _ := store.CompactIssueEvents(ctx, 90, 100); compacted != 0 {
+		t.Errorf("second run compacted %d events, want 0", compacted)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	for _, name := range []string{"web", "api"} {
+		if err := store.SaveProject(ctx, &types.Project{Name: name}); err != nil {
+			t.Fatalf("SaveProject(%s) failed: %v", name, err)
+		}
+	}
+	a := mustCreate(t, store, newTask("A", 1))
+	b := mustCreate(t, store, newTask("B", 1))
+	mustCreate(t, store, newTask("C", 1))
+	for id, project := range map[string]string{a.ID: "web", b.ID: "web"} {
+		if err := store.SetIssueProject(ctx, id, project, "test"); err != nil {
+			t.Fatalf("SetIssueProject failed: %v", err)
+		}
+	}
+	if err := store.SetIssueProject(ctx, b.ID, "api", "test"); err != nil {
+		t.Fatalf("SetIssueProject (move) failed: %v", err)
+	}
+	if err := store.SetIssueProject(ctx, a.ID, "missing", "test"); err == nil {
+		t.Error("expected assigning to an unknown project to fail")
+	}
+
+	labels, _ := store.GetLabels(ctx, b.ID)
+	if len(labels) != 1 || labels[0] != "project:api" {
+		t.Errorf("expected only project:api on %s, got %v", b.ID, labels)
+	}
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Project: "web"})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != a.ID {
+		t.Errorf("expected only %s ready in web, got %v", a.ID, ready)
+	}
+	web, _ := store.GetProject(ctx, "web")
+	if web == nil || web.OpenIssues != 1 {
+		t.Errorf("expected web to have 1 open issue, got %+v", web)
+	}
+}
+
+func TestSavedFilters(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	mustCreate(t, store, newTask("Fix login", 1))
+	mustCreate(t, store, newTask("Write docs", 1))
+	mustCreate(t, store, newTask("Fix typo", 3))
+
+	p1 := 1
+	if err := store.SaveFilter(ctx, &types.SavedFilter{Name: "fix-p1", Query: "fix", Priority: &p1}); err != nil {
+		t.Fatalf("SaveFilter failed: %v", err)
+	}
+	issues, err := store.ListIssuesByFilter(ctx, "fix-p1")
+	if err != nil {
+		t.Fatalf("ListIssuesByFilter failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Title != "Fix login" {
+		t.Errorf("expected only Fix login, got %v", issues)
+	}
+
+	if err := store.DeleteFilter(ctx, "fix-p1"); err != nil {
+		t.Fatalf("DeleteFilter failed: %v", err)
+	}
+	if f, _ := store.GetSavedFilter(ctx, "fix-p1"); f != nil {
+		t.Errorf("expected filter to be deleted, got %+v", f)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	var ids []string
+	for i := 0; i < 5; i++ {
+		ids = append(ids, mustCreate(t, store, newTask(fmt.Sprintf("Task %d", i), 1)).ID)
+	}
+
+	var events []int64
+	page := types.PageRequest{Limit: 2}
+	for {
+		result, err := store.GetEventsPage(ctx, "", page)
+		if err != nil {
+			t.Fatalf("GetEventsPage failed: %v", err)
+		}
+		for _, e := range result.Events {
+			events = append(events, e.ID)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+	if len(events) != 5 || events[0] > events[4] {
+		t.Errorf("expected 5 events oldest first, got %v", events)
+	}
+
+	first, err := store.SearchIssuesPage(ctx, "", types.IssueFilter{}, types.PageRequest{Limit: 3, Order: types.SortDescending})
+	if err != nil {
+		t.Fatalf("SearchIssuesPage failed: %v", err)
+	}
+	if len(first.Issues) != 3 || first.Issues[0].ID != ids[4] || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %d issues, cursor %q", len(first.Issues), first.NextCursor)
+	}
+	second, err := store.SearchIssuesPage(ctx, "", types.IssueFilter{}, types.PageRequest{Limit: 3, Order: types.SortDescending, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("SearchIssuesPage failed: %v", err)
+	}
+	if len(second.Issues) != 2 || second.Issues[1].ID != ids[0] || second.NextCursor != "" {
+		t.Errorf("unexpected second page: %v, cursor %q", second.Issues, second.NextCursor)
+	}
+}
+
+func TestCloneIssue(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	epic := mustCreate(t, store, &types.Issue{Title: "Epic", Status: types.StatusClosed, Priority: 1,
+		IssueType: types.TypeEpic, AcceptanceCriteria: "Done", ClosedAt: timePtr(time.Now())})
+	child := mustCreate(t, store, newTask("Child", 1))
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: epic.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, child.ID, "interrupted", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	result, err := store.CloneIssue(ctx, epic.ID, types.CloneOptions{IncludeChildren: true}, "test")
+	if err != nil {
+		t.Fatalf("CloneIssue failed: %v", err)
+	}
+	if result.Root.Status != types.StatusOpen || result.Root.ClosedAt != nil {
+		t.Errorf("expected an open clone, got %+v", result.Root)
+	}
+	childClone := result.IDs[child.ID]
+	deps, _ := store.GetDependencyRecords(ctx, childClone)
+	if len(deps) != 1 || deps[0].DependsOnID != result.Root.ID {
+		t.Errorf("expected the child clone under the epic clone, got %+v", deps)
+	}
+	if labels, _ := store.GetLabels(ctx, childClone); len(labels) != 0 {
+		t.Errorf("expected execution state labels to be dropped, got %v", labels)
+	}
+}
+
+func TestCustomFields(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	if err := store.SaveProject(ctx, &types.Project{Name: "web"}); err != nil {
+		t.Fatalf("SaveProject failed: %v", err)
+	}
+	for _, f := range []*types.CustomField{
+		{Name: "component", Type: types.CustomFieldString},
+		{Name: "component", Project: "web", Type: types.CustomFieldEnum, Options: []string{"ui", "api"}},
+	} {
+		if err := store.SaveCustomField(ctx, f); err != nil {
+			t.Fatalf("SaveCustomField failed: %v", err)
+		}
+	}
+	a := mustCreate(t, store, newTask("A", 1))
+	b := mustCreate(t, store, newTask("B", 1))
+	if err := store.SetIssueProject(ctx, b.ID, "web", "test"); err != nil {
+		t.Fatalf("SetIssueProject failed: %v", err)
+	}
+
+	if err := store.SetCustomFieldValue(ctx, a.ID, "component", "anything", "test"); err != nil {
+		t.Errorf("global string field rejected a value: %v", err)
+	}
+	if err := store.SetCustomFieldValue(ctx, b.ID, "component", "anything", "test"); err == nil {
+		t.Error("expected the project's enum to reject a value outside its options")
+	}
+	if err := store.SetCustomFieldValue(ctx, b.ID, "component", "api", "test"); err != nil {
+		t.Fatalf("SetCustomFieldValue failed: %v", err)
+	}
+
+	issues, _ := store.SearchIssues(ctx, "", types.IssueFilter{CustomFields: map[string]string{"component": "api"}})
+	if len(issues) != 1 || issues[0].ID != b.ID {
+		t.Errorf("expected only %s, got %v", b.ID, issues)
+	}
+
+	if err := store.DeleteCustomField(ctx, "", "component"); err != nil {
+		t.Fatalf("DeleteCustomField failed: %v", err)
+	}
+	if values, _ := store.GetCustomFieldValues(ctx, a.ID); len(values) != 0 {
+		t.Errorf("expected no definition to apply to %s, got %+v", a.ID, values)
+	}
+	if values, _ := store.GetCustomFieldValues(ctx, b.ID); len(values) != 1 || values[0].Value != "api" {
+		t.Errorf("expected the project value to survive, got %+v", values)
+	}
+}
+
+func TestExecutions(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	issue := mustCreate(t, store, newTask("Task", 1))
+
+	execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning}
+	if err := store.CreateExecution(ctx, execution); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+	if err := store.CreateExecution(ctx, &types.Execution{IssueID: "vc-missing", AgentProvider: "amp", Status: types.ExecutionRunning}); err == nil {
+		t.Error("expected an execution of a missing issue to be rejected")
+	}
+
+	execution.Status = types.ExecutionFailed
+	execution.Error = "agent crashed"
+	if err := store.UpdateExecution(ctx, execution); err != nil {
+		t.Fatalf("UpdateExecution failed: %v", err)
+	}
+	execution.Error = "changed after saving"
+	got, _ := store.GetExecution(ctx, execution.ID)
+	if got == nil || got.Status != types.ExecutionFailed || got.Error != "agent crashed" {
+		t.Errorf("unexpected execution: %+v", got)
+	}
+
+	failed, _ := store.ListExecutions(ctx, types.ExecutionFilter{Status: types.ExecutionFailed})
+	if len(failed) != 1 {
+		t.Errorf("expected one failed execution, got %d", len(failed))
+	}
+	if err := store.DeleteExecution(ctx, execution.ID); err != nil {
+		t.Fatalf("DeleteExecution failed: %v", err)
+	}
+	if got, _ := store.GetExecution(ctx, execution.ID); got != nil {
+		t.Errorf("expected the execution to be deleted, got %+v", got)
+	}
+}
+
+func TestExecutionOutput(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	issue := mustCreate(t, store, newTask("Task", 1))
+	execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning}
+	if err := store.CreateExecution(ctx, execution); err != nil {
+		t.Fatalf("CreateExecution failed: %v", err)
+	}
+
+	var lines []*types.OutputLine
+	for i := 0; i < 3; i++ {
+		lines = append(lines, &types.OutputLine{ExecutionID: execution.ID, IssueID: issue.ID, Stream: types.OutputStdout, Line: fmt.Sprintf("line %d", i)})
+	}
+	if err := store.AppendExecutionOutput(ctx, lines); err != nil {
+		t.Fatalf("AppendExecutionOutput failed: %v", err)
+	}
+	if err := store.AppendExecutionOutput(ctx, []*types.OutputLine{{ExecutionID: 99, IssueID: issue.ID, Stream: types.OutputStdout}}); err == nil {
+		t.Error("expected output of a missing execution to be rejected")
+	}
+
+	got, _ := store.ListExecutionOutput(ctx, types.OutputFilter{ExecutionID: execution.ID, Limit: 2})
+	if len(got) != 2 || got[0].Line != "line 1" || got[1].Line != "line 2" {
+		t.Errorf("expected the last 2 lines, got %+v", got)
+	}
+	got, _ = store.ListExecutionOutput(ctx, types.OutputFilter{IssueID: issue.ID, AfterID: lines[1].ID})
+	if len(got) != 1 || got[0].Line != "line 2" {
+		t.Errorf("expected the line after line 1, got %+v", got)
+	}
+
+	// Output of a running execution is kept; deleting the execution drops it
+	if deleted, _ := store.CleanupExecutionOutput(ctx, 0); deleted != 0 {
+		t.Errorf("expected running output to be kept, got %d deleted", deleted)
+	}
+	if err := store.DeleteExecution(ctx, execution.ID); err != nil {
+		t.Fatalf("DeleteExecution failed: %v", err)
+	}
+	if got, _ := store.ListExecutionOutput(ctx, types.OutputFilter{}); len(got) != 0 {
+		t.Errorf("expected no output left, got %+v", got)
+	}
+}
+
+func TestArchiveIssues(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	longAgo := time.Now().AddDate(0, 0, -200)
+	closeOld := func(issue *types.Issue) {
+		t.Helper()
+		if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": types.StatusClosed, "closed_at": longAgo}, "test"); err != nil {
+			t.Fatalf("UpdateIssue failed: %v", err)
+		}
+	}
+	old := mustCreate(t, store, newTask("Old", 1))
+	closeOld(old)
+	if err := store.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{IssueID: old.ID, AttemptNumber: 1, StartedAt: longAgo}); err != nil {
+		t.Fatalf("RecordExecutionAttempt failed: %v", err)
+	}
+	linked := mustCreate(t, store, newTask("Old but linked", 1))
+	closeOld(linked)
+	open := mustCreate(t, store, newTask("Open", 1))
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: open.ID, DependsOnID: linked.ID, Type: types.DepBlocks}, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	var archive bytes.Buffer
+	stats, err := store.ArchiveIssues(ctx, types.ArchiveOptions{ClosedBefore: time.Now().AddDate(0, 0, -90)}, &archive)
+	if err != nil {
+		t.Fatalf("ArchiveIssues failed: %v", err)
+	}
+	if stats.Issues != 1 || stats.Kept != 1 || stats.Executions != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if got, _ := store.GetIssue(ctx, old.ID); got != nil {
+		t.Error("expected the old issue to be archived")
+	}
+	if history, _ := store.GetExecutionHistory(ctx, old.ID); len(history) != 0 {
+		t.Errorf("expected the archived issue's execution history to go with it, got %d attempts", len(history))
+	}
+	if got, _ := store.GetIssue(ctx, linked.ID); got == nil {
+		t.Error("expected the linked issue to stay")
+	}
+
+	imported, err := store.Import(ctx, &archive)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if imported.Issues != 1 || imported.Executions != 1 {
+		t.Errorf("expected the archive to restore the issue and its history, got %+v", imported)
+	}
+}
+
+func TestThreadedComments(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	issue := mustCreate(t, store, newTask("Task", 2))
+
+	if err := store.AddComment(ctx, issue.ID, "alice", "Should this retry?"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("GetComments = %v, %v; want one comment", comments, err)
+	}
+	root := comments[0]
+	reply, err := store.ReplyToComment(ctx, root.ID, "ai-supervisor", "Yes, with backoff")
+	if err != nil {
+		t.Fatalf("ReplyToComment failed: %v", err)
+	}
+	if err := store.EditComment(ctx, root.ID, "bob", "Hijacked"); err == nil {
+		t.Error("expected an edit by someone other than the author to fail")
+	}
+	if err := store.EditComment(ctx, root.ID, "alice", "Should this retry on timeout?"); err != nil {
+		t.Fatalf("EditComment failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := store.AddReaction(ctx, reply.ID, "alice", "+1"); err != nil {
+			t.Fatalf("AddReaction failed: %v", err)
+		}
+	}
+	if err := store.AddReaction(ctx, reply.ID, "bob", "two words"); err == nil {
+		t.Error("expected a reaction with whitespace to be rejected")
+	}
+
+	comments, err = store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	threads := types.ThreadComments(comments)
+	if len(threads) != 1 || len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+		t.Fatalf("expected the reply threaded under the comment, got %+v", threads)
+	}
+	root = threads[0]
+	if root.Body != "Should this retry on timeout?" || len(root.Edits) != 1 ||
+		root.Edits[0].PreviousBody != "Should this retry?" || root.EditedAt == nil {
+		t.Errorf("expected the edited body with its history, got %+v", root)
+	}
+	if got := root.Replies[0].ReactionCounts()["+1"]; got != 1 {
+		t.Errorf("expected one +1 reaction, got %d", got)
+	}
+
+	if err := store.RemoveReaction(ctx, reply.ID, "alice", "+1"); err != nil {
+		t.Fatalf("RemoveReaction failed: %v", err)
+	}
+	comments, _ = store.GetComments(ctx, issue.ID)
+	if len(comments[1].Reactions) != 0 {
+		t.Errorf("expected the reaction to be removed, got %+v", comments[1].Reactions)
+	}
+	if _, err := store.ReplyToComment(ctx, 9999, "alice", "lost"); err == nil {
+		t.Error("expected a reply to a missing comment to fail")
+	}
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// PAGINATION
+// ======================================================================
+
+// pageEntry is an item in a paged listing with its sort key
+type pageEntry[T any] struct {
+	item T
+	key  string // types.PageKey of the item's timestamp
+	id   string
+}
+
+// paginate sorts entries by (key, id), using cmpID to order IDs, and returns
+// the requested page and the cursor of the next one ("" on the last page)
+func paginate[T any](entries []pageEntry[T], page types.PageRequest, cursor *types.PageCursor, cmpID func(a, b string) int) ([]T, string) {
+	cmp := func(key, id string, otherKey, otherID string) int {
+		if c := strings.Compare(key, otherKey); c != 0 {
+			return c
+		}
+		return cmpID(id, otherID)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		c := cmp(entries[i].key, entries[i].id, entries[j].key, entries[j].id)
+		if page.Descending() {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	since := page.SinceKey()
+	size := page.PageSize()
+	var result []T
+	var last pageEntry[T]
+	for _, e := range entries {
+		if since != "" && e.key < since {
+			continue
+		}
+		if cursor != nil {
+			c := cmp(e.key, e.id, cursor.Key, cursor.ID)
+			if (page.Descending() && c >= 0) || (!page.Descending() && c <= 0) {
+				continue
+			}
+		}
+		if len(result) == size {
+			return result, types.PageCursor{Key: last.key, ID: last.id}.Encode()
+		}
+		result = append(result, e.item)
+		last = e
+	}
+	return result, ""
+}
+
+// compareEventIDs orders event IDs numerically
+func compareEventIDs(a, b string) int {
+	x, _ := strconv.ParseInt(a, 10, 64)
+	y, _ := strconv.ParseInt(b, 10, 64)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// GetEventsPage returns a page of events, across all issues if issueID is ""
+func (s *Store) GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+	cursor, _ := page.DecodeCursor()
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var entries []pageEntry[*types.Event]
+	for _, event := range s.events {
+		if issueID != "" && event.IssueID != issueID {
+			continue
+		}
+		eventCopy := *event
+		entries = append(entries, pageEntry[*types.Event]{
+			item: &eventCopy,
+			key:  types.PageKey(event.CreatedAt),
+			id:   strconv.FormatInt(event.ID, 10),
+		})
+	}
+	events, next := paginate(entries, page, cursor, compareEventIDs)
+	return &types.EventPage{Events: events, NextCursor: next}, nil
+}
+
+// SearchIssuesPage returns a page of the issues SearchIssues would return
+func (s *Store) SearchIssuesPage(ctx context.Context, query string, filter types.IssueFilter, page types.PageRequest) (*types.IssuePage, error) {
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+	cursor, _ := page.DecodeCursor()
+	filter.Limit = 0
+	issues, err := s.SearchIssues(ctx, query, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]pageEntry[*types.Issue], len(issues))
+	for i, issue := range issues {
+		at := issue.CreatedAt
+		if page.OrderBy == types.IssueOrderUpdated {
+			at = issue.UpdatedAt
+		}
+		entries[i] = pageEntry[*types.Issue]{item: issue, key: types.PageKey(at), id: issue.ID}
+	}
+	result, next := paginate(entries, page, cursor, strings.Compare)
+	return &types.IssuePage{Issues: result, NextCursor: next}, nil
+}
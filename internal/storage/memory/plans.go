@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// MISSION PLANS
+// ======================================================================
+
+// StorePlan stores a mission plan and returns its new iteration.
+// If expectedIteration > 0 and doesn't match the stored iteration,
+// ErrStaleIteration is returned (vc-un1o).
+func (s *Store) StorePlan(ctx context.Context, plan *types.MissionPlan, expectedIteration int) (int, error) {
+	if plan == nil {
+		return 0, fmt.Errorf("plan cannot be nil")
+	}
+	if err := plan.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid plan: %w", err)
+	}
+	if expectedIteration < 0 {
+		return 0, fmt.Errorf("expectedIteration must be >= 0 (got %d)", expectedIteration)
+	}
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal plan JSON: %w", err)
+	}
+
+	if err := s.lock(); err != nil {
+		return 0, err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	record, exists := s.plans[plan.MissionID]
+	if !exists {
+		s.plans[plan.MissionID] = &planRecord{
+			data:      planJSON,
+			status:    plan.Status,
+			iteration: 1,
+			createdAt: now,
+			updatedAt: now,
+		}
+		return 1, nil
+	}
+	if expectedIteration > 0 && record.iteration != expectedIteration {
+		return 0, beads.ErrStaleIteration
+	}
+	record.data = planJSON
+	record.status = plan.Status
+	record.iteration++
+	record.updatedAt = now
+	return record.iteration, nil
+}
+
+// GetPlan returns a mission's plan and iteration (nil, 0 if none)
+func (s *Store) GetPlan(ctx context.Context, missionID string) (*types.MissionPlan, int, error) {
+	if err := s.lock(); err != nil {
+		return nil, 0, err
+	}
+	defer s.mu.Unlock()
+
+	record, ok := s.plans[missionID]
+	if !ok {
+		return nil, 0, nil
+	}
+	plan, err := record.plan()
+	if err != nil {
+		return nil, 0, err
+	}
+	return plan, record.iteration, nil
+}
+
+// GetPlanHistory returns a mission's plans. Only the latest iteration is kept.
+func (s *Store) GetPlanHistory(ctx context.Context, missionID string) ([]*types.MissionPlan, error) {
+	plan, _, err := s.GetPlan(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return []*types.MissionPlan{}, nil
+	}
+	return []*types.MissionPlan{plan}, nil
+}
+
+// DeletePlan removes a mission's plan. Idempotent.
+func (s *Store) DeletePlan(ctx context.Context, missionID string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	delete(s.plans, missionID)
+	return nil
+}
+
+// ListDraftPlans returns plans that aren't approved, most recently updated first
+func (s *Store) ListDraftPlans(ctx context.Context) ([]*types.MissionPlan, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var records []*planRecord
+	for _, record := range s.plans {
+		if record.status != "approved" {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].updatedAt.After(records[j].updatedAt)
+	})
+
+	var plans []*types.MissionPlan
+	for _, record := range records {
+		plan, err := record.plan()
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// plan decodes a stored plan, restoring its status
+func (r *planRecord) plan() (*types.MissionPlan, error) {
+	var plan types.MissionPlan
+	if err := json.Unmarshal(r.data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal plan JSON: %w", err)
+	}
+	plan.Status = r.status
+	return &plan, nil
+}
+
+// ======================================================================
+// TEST FAILURE DIAGNOSES (vc-9aa9)
+// ======================================================================
+
+// StoreDiagnosis stores (or replaces) the test failure diagnosis for an issue
+func (s *Store) StoreDiagnosis(ctx context.Context, issueID string, diagnosis *types.TestFailureDiagnosis) error {
+	if diagnosis == nil {
+		return fmt.Errorf("diagnosis cannot be nil")
+	}
+	data, err := json.Marshal(diagnosis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnosis: %w", err)
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+	s.diagnoses[issueID] = data
+	return nil
+}
+
+// GetDiagnosis returns the test failure diagnosis for an issue (nil if none)
+func (s *Store) GetDiagnosis(ctx context.Context, issueID string) (*types.TestFailureDiagnosis, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	data, ok := s.diagnoses[issueID]
+	if !ok {
+		return nil, nil
+	}
+	var diagnosis types.TestFailureDiagnosis
+	if err := json.Unmarshal(data, &diagnosis); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal diagnosis: %w", err)
+	}
+	return &diagnosis, nil
+}
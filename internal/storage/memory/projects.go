@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// PROJECTS
+// ======================================================================
+
+// ListProjects returns all projects with their issue counts, sorted by name
+func (s *Store) ListProjects(ctx context.Context) ([]*types.Project, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	projects := make([]*types.Project, 0, len(s.projects))
+	for name := range s.projects {
+		projects = append(projects, s.projectLocked(name))
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, nil
+}
+
+// GetProject returns a project with its issue counts, or nil if it doesn't exist
+func (s *Store) GetProject(ctx context.Context, name string) (*types.Project, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.projects[name]; !ok {
+		return nil, nil
+	}
+	return s.projectLocked(name), nil
+}
+
+// projectLocked copies a saved project and counts its issues. Caller must
+// hold s.mu.
+func (s *Store) projectLocked(name string) *types.Project {
+	project := *s.projects[name]
+	for _, id := range s.issuesWithLabelLocked(types.ProjectLabel(name)) {
+		if s.issues[id].Status == types.StatusClosed {
+			project.ClosedIssues++
+		} else {
+			project.OpenIssues++
+		}
+	}
+	return &project
+}
+
+// SaveProject creates or updates a project, filling in the timestamps
+func (s *Store) SaveProject(ctx context.Context, project *types.Project) error {
+	if err := project.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	project.CreatedAt = now
+	if existing, ok := s.projects[project.Name]; ok {
+		project.CreatedAt = existing.CreatedAt
+	}
+	project.UpdatedAt = now
+	saved := *project
+	saved.OpenIssues, saved.ClosedIssues = 0, 0
+	s.projects[project.Name] = &saved
+	return nil
+}
+
+// SetIssueProject moves an issue into project, or out of any project if
+// project is ""
+func (s *Store) SetIssueProject(ctx context.Context, issueID, project, actor string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.issues[issueID]; !ok {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	if project != "" {
+		if _, ok := s.projects[project]; !ok {
+			return fmt.Errorf("project %s not found", project)
+		}
+	}
+
+	for label := range s.labels[issueID] {
+		if strings.HasPrefix(label, types.ProjectLabelPrefix) && label != types.ProjectLabel(project) {
+			s.removeLabelLocked(issueID, label, actor)
+		}
+	}
+	if project == "" {
+		return nil
+	}
+	return s.addLabelLocked(issueID, types.ProjectLabel(project), actor)
+}
@@ -0,0 +1,499 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// maxBlockedDepth bounds how far blockage propagates down parent-child chains (same as Beads)
+const maxBlockedDepth = 50
+
+// maxMissionDepth bounds the parent-child walk from a task up to its mission
+const maxMissionDepth = 10
+
+// isActiveStatus reports whether an issue in this status can block others
+func isActiveStatus(status types.Status) bool {
+	return status == types.StatusOpen || status == types.StatusInProgress || status == types.StatusBlocked
+}
+
+// openBlockersLocked returns the active issues blocking issueID via 'blocks'
+// dependencies, in dependency order. Caller must hold s.mu.
+func (s *Store) openBlockersLocked(issueID string) []string {
+	var blockers []string
+	for _, dep := range s.deps {
+		if dep.IssueID != issueID || dep.Type != types.DepBlocks {
+			continue
+		}
+		if blocker, ok := s.issues[dep.DependsOnID]; ok && isActiveStatus(blocker.Status) {
+			blockers = append(blockers, blocker.ID)
+		}
+	}
+	return blockers
+}
+
+// hasUnclosedBlockerLocked reports whether issueID has a 'blocks' dependency
+// on any issue that isn't closed. Caller must hold s.mu.
+func (s *Store) hasUnclosedBlockerLocked(issueID string) bool {
+	for _, dep := range s.deps {
+		if dep.IssueID != issueID || dep.Type != types.DepBlocks {
+			continue
+		}
+		if blocker, ok := s.issues[dep.DependsOnID]; ok && blocker.Status != types.StatusClosed {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedSetLocked returns every blocked issue: issues with an active 'blocks'
+// dependency, plus their parent-child descendants. Caller must hold s.mu.
+func (s *Store) blockedSetLocked() map[string]bool {
+	blocked := make(map[string]bool)
+	var frontier []string
+	for id := range s.issues {
+		if len(s.openBlockersLocked(id)) > 0 {
+			blocked[id] = true
+			frontier = append(frontier, id)
+		}
+	}
+	for depth := 0; depth < maxBlockedDepth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, parentID := range frontier {
+			for _, dep := range s.deps {
+				if dep.Type == types.DepParentChild && dep.DependsOnID == parentID && !blocked[dep.IssueID] {
+					blocked[dep.IssueID] = true
+					next = append(next, dep.IssueID)
+				}
+			}
+		}
+		frontier = next
+	}
+	return blocked
+}
+
+// GetReadyWork returns unblocked, claimable work. Epics, in-progress issues,
+// issues labeled no-auto-claim, issues in watchdog backoff and tasks of
+// missions waiting on quality gates are excluded.
+func (s *Store) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+	return s.readyWorkLocked(filter), nil
+}
+
+// readyWorkLocked implements GetReadyWork, returning copies. Caller must hold s.mu.
+func (s *Store) readyWorkLocked(filter types.WorkFilter) []*types.Issue {
+	// Beads-level selection: status/priority/assignee filters, not blocked, sorted, limited
+	blocked := s.blockedSetLocked()
+	var candidates []*types.Issue
+	for id, issue := range s.issues {
+		if filter.Status == "" {
+			if issue.Status != types.StatusOpen && issue.Status != types.StatusInProgress {
+				continue
+			}
+		} else if issue.Status != filter.Status {
+			continue
+		}
+		if filter.Priority != nil && issue.Priority != *filter.Priority {
+			continue
+		}
+		if filter.Assignee != nil && issue.Assignee != *filter.Assignee {
+			continue
+		}
+		if filter.Project != "" && !s.labels[id][types.ProjectLabel(filter.Project)] {
+			continue
+		}
+		if blocked[id] {
+			continue
+		}
+		candidates = append(candidates, issue)
+	}
+	s.sortReadyWorkLocked(candidates, filter.SortPolicy, time.Now())
+	if filter.Limit > 0 && len(candidates) > filter.Limit {
+		candidates = candidates[:filter.Limit]
+	}
+
+	// VC-level filtering (vc-203, vc-185, vc-4ec0, vc-165b)
+	var ready []*types.Issue
+	for _, issue := range candidates {
+		if issue.IssueType == types.TypeEpic ||
+			issue.Status == types.StatusBlocked ||
+			issue.Status == types.StatusInProgress {
+			continue
+		}
+		if s.labels[issue.ID]["no-auto-claim"] || s.labels[issue.ID][types.LabelWaitingOnAI] {
+			continue
+		}
+		if state, ok := s.execStates[issue.ID]; ok &&
+			beads.CalculateInterventionBackoff(state.InterventionCount, state.LastInterventionTime) > 0 {
+			continue
+		}
+		ready = append(ready, copyIssue(issue))
+	}
+
+	// vc-234, vc-239: Attach mission context, skipping missions waiting on quality gates
+	result := make([]*types.Issue, 0, len(ready))
+	for _, issue := range ready {
+		missionCtx, err := s.missionForTaskLocked(issue.ID)
+		if err != nil {
+			result = append(result, issue)
+			continue
+		}
+		if s.labels[missionCtx.MissionID]["needs-quality-gates"] {
+			continue
+		}
+		issue.MissionContext = missionCtx
+		result = append(result, issue)
+	}
+
+	if os.Getenv("VC_DEBUG_WORK_SELECTION") != "" {
+		fmt.Fprintf(os.Stderr, "[work-selection] GetReadyWork: %d candidates, %d ready\n", len(candidates), len(result))
+	}
+	return result
+}
+
+// sortReadyWorkLocked orders ready work by sort policy (vc-190). Hybrid, the
+// default, puts issues from the last 48 hours first by priority, then older
+// issues oldest first. Caller must hold s.mu.
+func (s *Store) sortReadyWorkLocked(issues []*types.Issue, policy types.SortPolicy, now time.Time) {
+	older := func(a, b *types.Issue) bool {
+		return s.newerLocked(b, a)
+	}
+	recentCutoff := now.Add(-48 * time.Hour)
+
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		switch policy {
+		case types.SortPolicyPriority:
+			if a.Priority != b.Priority {
+				return a.Priority < b.Priority
+			}
+		case types.SortPolicyOldest:
+		default:
+			aRecent := !a.CreatedAt.Before(recentCutoff)
+			bRecent := !b.CreatedAt.Before(recentCutoff)
+			if aRecent != bRecent {
+				return aRecent
+			}
+			if aRecent && a.Priority != b.Priority {
+				return a.Priority < b.Priority
+			}
+		}
+		return older(a, b)
+	})
+}
+
+// GetBlockedIssues returns active issues blocked by other active issues
+func (s *Store) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.BlockedIssue
+	for id, issue := range s.issues {
+		if !isActiveStatus(issue.Status) {
+			continue
+		}
+		blockers := s.openBlockersLocked(id)
+		if len(blockers) == 0 {
+			continue
+		}
+		result = append(result, &types.BlockedIssue{
+			Issue:          *copyIssue(issue),
+			BlockedByCount: len(blockers),
+			BlockedBy:      blockers,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority < result[j].Priority
+		}
+		return s.issueSeq[result[i].ID] < s.issueSeq[result[j].ID]
+	})
+	return result, nil
+}
+
+// readyLabeledLocked returns open, non-epic issues with a label and no
+// unclosed blockers, by priority. Caller must hold s.mu.
+func (s *Store) readyLabeledLocked(label string, limit int) []*types.Issue {
+	var result []*types.Issue
+	for id, issue := range s.issues {
+		if !s.labels[id][label] || issue.Status != types.StatusOpen || issue.IssueType == types.TypeEpic {
+			continue
+		}
+		if s.hasUnclosedBlockerLocked(id) {
+			continue
+		}
+		result = append(result, issue)
+	}
+	s.sortByPriorityOldestLocked(result)
+	return copyIssues(applyLimit(result, limit))
+}
+
+// GetReadyBlockers returns ready issues labeled discovered:blocker, by priority
+func (s *Store) GetReadyBlockers(ctx context.Context, limit int) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+	return s.readyLabeledLocked("discovered:blocker", limit), nil
+}
+
+// GetReadyBaselineIssues returns ready issues labeled baseline-failure (vc-1nks)
+func (s *Store) GetReadyBaselineIssues(ctx context.Context, limit int) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+	return s.readyLabeledLocked("baseline-failure", limit), nil
+}
+
+// GetReadyDependentsOfBlockedBaselines returns ready children of blocked
+// baseline-failure issues, and a map of child ID to baseline ID (vc-1nks)
+func (s *Store) GetReadyDependentsOfBlockedBaselines(ctx context.Context, limit int) ([]*types.Issue, map[string]string, error) {
+	if err := s.lock(); err != nil {
+		return nil, nil, err
+	}
+	defer s.mu.Unlock()
+
+	type pair struct {
+		issue      *types.Issue
+		baselineID string
+	}
+	var pairs []pair
+	for baselineID, baseline := range s.issues {
+		if !s.labels[baselineID]["baseline-failure"] || baseline.Status != types.StatusOpen ||
+			!s.hasUnclosedBlockerLocked(baselineID) {
+			continue
+		}
+		for _, dep := range s.deps {
+			if dep.Type != types.DepParentChild || dep.DependsOnID != baselineID {
+				continue
+			}
+			child, ok := s.issues[dep.IssueID]
+			if !ok || child.Status != types.StatusOpen || child.IssueType == types.TypeEpic {
+				continue
+			}
+			if s.hasUnclosedBlockerLocked(child.ID) || s.hasDependencyTypeLocked(child.ID, types.DepDiscoveredFrom) {
+				continue
+			}
+			pairs = append(pairs, pair{issue: child, baselineID: baselineID})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i].issue, pairs[j].issue
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.ID != b.ID {
+			return s.newerLocked(b, a)
+		}
+		return pairs[i].baselineID < pairs[j].baselineID
+	})
+	if limit >= 0 && len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	issues := make([]*types.Issue, 0, len(pairs))
+	baselineMap := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		issues = append(issues, copyIssue(p.issue))
+		baselineMap[p.issue.ID] = p.baselineID
+	}
+	return issues, baselineMap, nil
+}
+
+// hasDependencyTypeLocked reports whether issueID has any dependency of a type.
+// Caller must hold s.mu.
+func (s *Store) hasDependencyTypeLocked(issueID string, depType types.DependencyType) bool {
+	for _, dep := range s.deps {
+		if dep.IssueID == issueID && dep.Type == depType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEpicComplete reports whether all of an epic's children and blockers are closed
+func (s *Store) IsEpicComplete(ctx context.Context, epicID string) (bool, error) {
+	if err := s.lock(); err != nil {
+		return false, err
+	}
+	defer s.mu.Unlock()
+
+	for _, dep := range s.deps {
+		var other string
+		switch {
+		case dep.Type == types.DepParentChild && dep.DependsOnID == epicID:
+			other = dep.IssueID
+		case dep.Type == types.DepBlocks && dep.IssueID == epicID:
+			other = dep.DependsOnID
+		default:
+			continue
+		}
+		if issue, ok := s.issues[other]; ok && issue.Status != types.StatusClosed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// GetEpicRollup aggregates the status, effort, blockers and gate runs of an
+// epic's direct children
+func (s *Store) GetEpicRollup(ctx context.Context, epicID string) (*types.EpicRollup, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.issues[epicID]; !ok {
+		return nil, fmt.Errorf("epic %s not found", epicID)
+	}
+
+	var children []*types.Issue
+	childIDs := make(map[string]bool)
+	for _, dep := range s.deps {
+		if dep.Type == types.DepParentChild && dep.DependsOnID == epicID {
+			if child, ok := s.issues[dep.IssueID]; ok && !childIDs[child.ID] {
+				children = append(children, child)
+				childIDs[child.ID] = true
+			}
+		}
+	}
+	rollup := types.NewEpicRollup(epicID, children)
+
+	// Blockers of the epic itself and of its unclosed children
+	blockers := make(map[string]bool)
+	for _, dep := range s.deps {
+		if dep.Type != types.DepBlocks {
+			continue
+		}
+		if dep.IssueID != epicID && !(childIDs[dep.IssueID] && s.issues[dep.IssueID].Status != types.StatusClosed) {
+			continue
+		}
+		if blocker, ok := s.issues[dep.DependsOnID]; ok && blocker.Status != types.StatusClosed {
+			blockers[blocker.ID] = true
+		}
+	}
+	for id := range blockers {
+		rollup.OpenBlockers = append(rollup.OpenBlockers, id)
+	}
+	sort.Strings(rollup.OpenBlockers)
+
+	for _, attempt := range s.attempts {
+		if childIDs[attempt.IssueID] && attempt.CompletedAt != nil {
+			rollup.ActualMinutes += attempt.CompletedAt.Sub(attempt.StartedAt).Minutes()
+		}
+	}
+
+	for _, event := range s.agentEvents {
+		if !childIDs[event.IssueID] || event.Type != events.EventTypeQualityGatesCompleted {
+			continue
+		}
+		if canceled, _ := event.Data["canceled"].(bool); canceled {
+			continue
+		}
+		rollup.GateRuns++
+		if passed, _ := event.Data["all_passed"].(bool); passed {
+			rollup.GateRunsPassed++
+		}
+	}
+	return rollup, nil
+}
+
+// GetMissionForTask finds the closest mission epic above a task via parent-child dependencies
+func (s *Store) GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+	return s.missionForTaskLocked(taskID)
+}
+
+// missionForTaskLocked walks parent-child dependencies upward, breadth first.
+// Caller must hold s.mu.
+func (s *Store) missionForTaskLocked(taskID string) (*types.MissionContext, error) {
+	visited := map[string]bool{taskID: true}
+	level := []string{taskID}
+	for depth := 1; depth <= maxMissionDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, id := range level {
+			for _, dep := range s.deps {
+				if dep.Type != types.DepParentChild || dep.IssueID != id || visited[dep.DependsOnID] {
+					continue
+				}
+				visited[dep.DependsOnID] = true
+				next = append(next, dep.DependsOnID)
+			}
+		}
+		sort.Strings(next)
+		for _, id := range next {
+			issue, ok := s.issues[id]
+			if !ok || issue.IssueType != types.TypeEpic || issue.IssueSubtype != types.SubtypeMission {
+				continue
+			}
+			state := s.missions[id]
+			if state == nil {
+				state = &missionState{}
+			}
+			return &types.MissionContext{
+				MissionID:   id,
+				SandboxPath: state.SandboxPath,
+				BranchName:  state.BranchName,
+			}, nil
+		}
+		level = next
+	}
+	return nil, fmt.Errorf("task %s is not part of a mission (no parent-child dependency to mission epic)", taskID)
+}
+
+// GetMissionsNeedingGates returns missions labeled needs-quality-gates whose
+// gates aren't already running (at most 10)
+func (s *Store) GetMissionsNeedingGates(ctx context.Context) ([]*types.Issue, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	var result []*types.Issue
+	for id, issue := range s.issues {
+		if issue.IssueType != types.TypeEpic || issue.IssueSubtype != types.SubtypeMission {
+			continue
+		}
+		if !s.labels[id]["needs-quality-gates"] || s.labels[id]["gates-running"] {
+			continue
+		}
+		result = append(result, issue)
+	}
+	s.sortByPriorityOldestLocked(result)
+	return copyIssues(applyLimit(result, 10)), nil
+}
+
+// sortByPriorityOldestLocked orders issues by priority, then oldest first.
+// Caller must hold s.mu.
+func (s *Store) sortByPriorityOldestLocked(issues []*types.Issue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Priority != issues[j].Priority {
+			return issues[i].Priority < issues[j].Priority
+		}
+		return s.newerLocked(issues[j], issues[i])
+	})
+}
+
+// applyLimit truncates a slice to limit items, with SQL LIMIT semantics
+// (negative means no limit)
+func applyLimit(issues []*types.Issue, limit int) []*types.Issue {
+	if limit >= 0 && len(issues) > limit {
+		return issues[:limit]
+	}
+	return issues
+}
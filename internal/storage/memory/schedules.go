@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// SCHEDULES
+// ======================================================================
+
+// ListSchedules returns all schedules, sorted by name
+func (s *Store) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	schedules := make([]*types.Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, copySchedule(schedule))
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Name < schedules[j].Name })
+	return schedules, nil
+}
+
+// GetSchedule returns a schedule, or nil if it doesn't exist
+func (s *Store) GetSchedule(ctx context.Context, name string) (*types.Schedule, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[name]
+	if !ok {
+		return nil, nil
+	}
+	return copySchedule(schedule), nil
+}
+
+// SaveSchedule creates or replaces a schedule, filling in the timestamps.
+// Replacing keeps the record of its last run.
+func (s *Store) SaveSchedule(ctx context.Context, schedule *types.Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	schedule.CreatedAt = now
+	if existing, ok := s.schedules[schedule.Name]; ok {
+		schedule.CreatedAt = existing.CreatedAt
+		schedule.LastRunAt = existing.LastRunAt
+		schedule.LastIssueID = existing.LastIssueID
+	}
+	schedule.UpdatedAt = now
+	s.schedules[schedule.Name] = copySchedule(schedule)
+	return nil
+}
+
+// DeleteSchedule deletes a schedule. Issues it created are left alone.
+func (s *Store) DeleteSchedule(ctx context.Context, name string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	if _, ok := s.schedules[name]; !ok {
+		return fmt.Errorf("schedule %s not found", name)
+	}
+	delete(s.schedules, name)
+	return nil
+}
+
+// ClaimScheduleRun records a run of a schedule due by now and moves its next
+// run to next, unless the run is no longer due
+func (s *Store) ClaimScheduleRun(ctx context.Context, name string, now, next time.Time) (bool, error) {
+	if err := s.lock(); err != nil {
+		return false, err
+	}
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[name]
+	if !ok || !schedule.Enabled || schedule.NextRunAt.After(now) {
+		return false, nil
+	}
+	schedule.NextRunAt = next
+	ranAt := now
+	schedule.LastRunAt = &ranAt
+	return true, nil
+}
+
+// SetScheduleLastIssue records the issue a schedule's latest run created
+func (s *Store) SetScheduleLastIssue(ctx context.Context, name, issueID string) error {
+	if err := s.lock(); err != nil {
+		return err
+	}
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[name]
+	if !ok {
+		return fmt.Errorf("schedule %s not found", name)
+	}
+	schedule.LastIssueID = issueID
+	return nil
+}
+
+// copySchedule returns a deep copy of a schedule
+func copySchedule(schedule *types.Schedule) *types.Schedule {
+	c := *schedule
+	if schedule.LastRunAt != nil {
+		lastRunAt := *schedule.LastRunAt
+		c.LastRunAt = &lastRunAt
+	}
+	c.Labels = append([]string(nil), schedule.Labels...)
+	return &c
+}
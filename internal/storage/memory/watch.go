@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// WATCH
+// ======================================================================
+
+// Watch delivers issue and execution changes matching filter, starting from
+// now, until ctx is done or the store is closed; then the channel is closed.
+// Changes are pushed as soon as they are committed, so filter.PollInterval is
+// ignored. Changes rolled back by a failed transaction are never delivered.
+func (s *Store) Watch(ctx context.Context, filter types.WatchFilter) (<-chan types.ChangeEvent, error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	lastEvent, lastAttempt := s.nextEventID, s.nextAttemptID
+	s.mu.Unlock()
+
+	ch := make(chan types.ChangeEvent, 64)
+	go func() {
+		defer close(ch)
+		for {
+			if err := s.lock(); err != nil {
+				return
+			}
+			changes := s.changesSinceLocked(&lastEvent, &lastAttempt)
+			wake := s.changed
+			s.mu.Unlock()
+
+			for _, change := range changes {
+				if !filter.Matches(change) {
+					continue
+				}
+				select {
+				case ch <- *change:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-wake:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// changesSinceLocked returns events and execution attempts recorded after the
+// given IDs, and advances them. Caller must hold s.mu.
+func (s *Store) changesSinceLocked(lastEvent, lastAttempt *int64) []*types.ChangeEvent {
+	var changes []*types.ChangeEvent
+	for _, event := range s.events {
+		if event.ID <= *lastEvent {
+			continue
+		}
+		changes = append(changes, &types.ChangeEvent{
+			Kind:      types.ChangeIssue,
+			IssueID:   event.IssueID,
+			EventType: event.EventType,
+			Actor:     event.Actor,
+			EventID:   event.ID,
+			Timestamp: event.CreatedAt,
+		})
+		*lastEvent = event.ID
+	}
+	for _, attempt := range s.attempts {
+		if attempt.ID <= *lastAttempt {
+			continue
+		}
+		changes = append(changes, &types.ChangeEvent{
+			Kind:      types.ChangeExecution,
+			IssueID:   attempt.IssueID,
+			EventID:   attempt.ID,
+			Timestamp: attempt.StartedAt,
+		})
+		*lastAttempt = attempt.ID
+	}
+	return changes
+}
+
+// notifyLocked wakes Watch goroutines. Caller must hold s.mu.
+func (s *Store) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
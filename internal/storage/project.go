@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// projectCandidateLimit bounds how many candidates the scoped ready queries
+// fetch before dropping issues from other projects
+const projectCandidateLimit = 500
+
+// projectStorage confines a Storage to one project: issues it creates join
+// the project, and searches, ready work and claims only see the project's issues.
+// Everything else, including lookups by ID, passes through unchanged.
+type projectStorage struct {
+	Storage
+	project string
+}
+
+// ScopeToProject returns a Storage confined to project, so an executor (or a
+// dashboard) sharing the database with other projects works only on its own
+// backlog. project must already exist; "" returns store unchanged.
+func ScopeToProject(store Storage, project string) Storage {
+	if project == "" {
+		return store
+	}
+	return &projectStorage{Storage: store, project: project}
+}
+
+// CreateIssue creates the issue in the project
+func (p *projectStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := p.Storage.CreateIssue(ctx, issue, actor); err != nil {
+		return err
+	}
+	return p.joinProject(ctx, issue, actor)
+}
+
+// CreateIssues creates the issues in the project
+func (p *projectStorage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	if err := p.Storage.CreateIssues(ctx, issues, actor); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		if err := p.joinProject(ctx, issue, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinProject adds a newly created issue to the project
+func (p *projectStorage) joinProject(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := p.Storage.SetIssueProject(ctx, issue.ID, p.project, actor); err != nil {
+		return fmt.Errorf("failed to add %s to project %s: %w", issue.ID, p.project, err)
+	}
+	return nil
+}
+
+// SearchIssues searches the project's issues unless filter names a project
+func (p *projectStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	if filter.Project == "" {
+		filter.Project = p.project
+	}
+	return p.Storage.SearchIssues(ctx, query, filter)
+}
+
+// SearchIssuesPage pages through the project's issues unless filter names a
+// project
+func (p *projectStorage) SearchIssuesPage(ctx context.Context, query string, filter types.IssueFilter, page types.PageRequest) (*types.IssuePage, error) {
+	if filter.Project == "" {
+		filter.Project = p.project
+	}
+	return p.Storage.SearchIssuesPage(ctx, query, filter, page)
+}
+
+// GetReadyWork returns the project's ready work unless filter names a project
+func (p *projectStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	if filter.Project == "" {
+		filter.Project = p.project
+	}
+	return p.Storage.GetReadyWork(ctx, filter)
+}
+
+// ClaimNextReadyIssue claims the project's next ready issue unless project
+// names another
+func (p *projectStorage) ClaimNextReadyIssue(ctx context.Context, workerID string, lease time.Duration, project string) (*types.Issue, error) {
+	if project == "" {
+		project = p.project
+	}
+	return p.Storage.ClaimNextReadyIssue(ctx, workerID, lease, project)
+}
+
+// GetReadyBlockers returns the project's ready blockers
+func (p *projectStorage) GetReadyBlockers(ctx context.Context, limit int) ([]*types.Issue, error) {
+	issues, err := p.Storage.GetReadyBlockers(ctx, projectCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	return p.filterIssues(ctx, issues, limit)
+}
+
+// GetReadyBaselineIssues returns the project's ready baseline issues
+func (p *projectStorage) GetReadyBaselineIssues(ctx context.Context, limit int) ([]*types.Issue, error) {
+	issues, err := p.Storage.GetReadyBaselineIssues(ctx, projectCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	return p.filterIssues(ctx, issues, limit)
+}
+
+// GetReadyDependentsOfBlockedBaselines returns the project's ready dependents
+// of blocked baselines
+func (p *projectStorage) GetReadyDependentsOfBlockedBaselines(ctx context.Context, limit int) ([]*types.Issue, map[string]string, error) {
+	issues, baselines, err := p.Storage.GetReadyDependentsOfBlockedBaselines(ctx, projectCandidateLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	issues, err = p.filterIssues(ctx, issues, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	scoped := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		scoped[issue.ID] = baselines[issue.ID]
+	}
+	return issues, scoped, nil
+}
+
+// filterIssues keeps up to limit issues that are in the project, in order
+func (p *projectStorage) filterIssues(ctx context.Context, issues []*types.Issue, limit int) ([]*types.Issue, error) {
+	var result []*types.Issue
+	for _, issue := range issues {
+		labels, err := p.Storage.GetLabels(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get labels for %s: %w", issue.ID, err)
+		}
+		if types.ProjectFromLabels(labels) != p.project {
+			continue
+		}
+		result = append(result, issue)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Unscoped returns the Storage under a ScopeToProject wrapper, or store
+// itself if it isn't scoped, for callers that need the concrete backend
+func Unscoped(store Storage) Storage {
+	if p, ok := store.(*projectStorage); ok {
+		return p.Storage
+	}
+	return store
+}
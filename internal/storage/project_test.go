@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestScopeToProject(t *testing.T) {
+	ctx := context.Background()
+	base := memory.New()
+	for _, name := range []string{"web", "api"} {
+		if err := base.SaveProject(ctx, &types.Project{Name: name}); err != nil {
+			t.Fatalf("SaveProject(%s) failed: %v", name, err)
+		}
+	}
+	web := ScopeToProject(base, "web")
+	api := ScopeToProject(base, "api")
+	if ScopeToProject(base, "") != Storage(base) {
+		t.Error("expected an empty project to leave the store unscoped")
+	}
+	if Unscoped(web) != Storage(base) {
+		t.Error("expected Unscoped to return the underlying store")
+	}
+
+	newIssue := func(store Storage, title string, labels ...string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1,
+			IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		for _, label := range labels {
+			if err := store.AddLabel(ctx, issue.ID, label, "test"); err != nil {
+				t.Fatalf("AddLabel failed: %v", err)
+			}
+		}
+		return issue
+	}
+	webIssue := newIssue(web, "Web task")
+	newIssue(api, "API task")
+	webBlocker := newIssue(web, "Web blocker", "discovered:blocker")
+	newIssue(api, "API blocker", "discovered:blocker")
+
+	labels, _ := base.GetLabels(ctx, webIssue.ID)
+	if types.ProjectFromLabels(labels) != "web" {
+		t.Errorf("expected issue created through the scoped store to be in web, got %v", labels)
+	}
+
+	ready, err := web.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 2 {
+		t.Errorf("expected 2 ready issues in web, got %d", len(ready))
+	}
+	found, err := api.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("expected 2 issues in api, got %d", len(found))
+	}
+
+	blockers, err := web.GetReadyBlockers(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetReadyBlockers failed: %v", err)
+	}
+	if len(blockers) != 1 || blockers[0].ID != webBlocker.ID {
+		t.Errorf("expected only %s as a web blocker, got %v", webBlocker.ID, blockers)
+	}
+}
@@ -0,0 +1,339 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ErrReadOnly is returned by every method of a ReadOnly store that would
+// write to the database
+var ErrReadOnly = errors.New("storage is read-only")
+
+// readOnlyStorage refuses every write to a Storage. Reads, Watch, Export and
+// Close pass through unchanged.
+type readOnlyStorage struct {
+	Storage
+}
+
+// ReadOnly returns a Storage that reads from store but fails every write with
+// ErrReadOnly, so dashboards, reports and backups can attach to a live
+// executor's database without any chance of changing its state. Unscoped
+// does not unwrap it.
+func ReadOnly(store Storage) Storage {
+	if _, ok := store.(*readOnlyStorage); ok {
+		return store
+	}
+	return &readOnlyStorage{Storage: store}
+}
+
+// IsReadOnly reports whether store was returned by ReadOnly
+func IsReadOnly(store Storage) bool {
+	_, ok := store.(*readOnlyStorage)
+	return ok
+}
+
+func (r *readOnlyStorage) StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) CompactIssueEvents(ctx context.Context, retentionDays, batchSize int) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) VacuumDatabase(ctx context.Context) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) AnalyzeDatabase(ctx context.Context) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CreateMission(ctx context.Context, mission *types.Mission, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) UpdateMission(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CloneIssue(ctx context.Context, id string, opts types.CloneOptions, actor string) (*types.CloneResult, error) {
+	return nil, ErrReadOnly
+}
+
+func (r *readOnlyStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SaveLabelDefinition(ctx context.Context, def *types.LabelDefinition) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) RenameLabel(ctx context.Context, oldName, newName, actor string) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteLabel(ctx context.Context, name, actor string) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) SaveProject(ctx context.Context, project *types.Project) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SetIssueProject(ctx context.Context, issueID, project, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SaveCustomField(ctx context.Context, field *types.CustomField) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteCustomField(ctx context.Context, project, name string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SetCustomFieldValue(ctx context.Context, issueID, name, value, actor string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SaveFilter(ctx context.Context, filter *types.SavedFilter) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteFilter(ctx context.Context, name string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SaveSchedule(ctx context.Context, schedule *types.Schedule) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteSchedule(ctx context.Context, name string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) ClaimScheduleRun(ctx context.Context, name string, now, next time.Time) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (r *readOnlyStorage) SetScheduleLastIssue(ctx context.Context, name, issueID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) StoreDiagnosis(ctx context.Context, issueID string, diagnosis *types.TestFailureDiagnosis) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) ReplyToComment(ctx context.Context, parentID int64, actor, body string) (*types.Comment, error) {
+	return nil, ErrReadOnly
+}
+
+func (r *readOnlyStorage) EditComment(ctx context.Context, commentID int64, actor, body string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) AddReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) RemoveReaction(ctx context.Context, commentID int64, actor, reaction string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) MarkInstanceStopped(ctx context.Context, instanceID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) UpdateHeartbeat(ctx context.Context, instanceID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) UpdateSelfHealingMode(ctx context.Context, instanceID string, mode string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CleanupStaleInstances(ctx context.Context, staleThreshold int) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteOldStoppedInstances(ctx context.Context, olderThanSeconds int, maxToKeep int) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) ClaimNextReadyIssue(ctx context.Context, workerID string, lease time.Duration, project string) (*types.Issue, error) {
+	return nil, ErrReadOnly
+}
+
+func (r *readOnlyStorage) RenewClaimLease(ctx context.Context, issueID, workerID string, lease time.Duration) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SaveCheckpoint(ctx context.Context, issueID string, checkpointData interface{}) error {
+	return ErrReadOnly
+}
+
+// LogStatusChange does nothing: it has no error to report the refusal with
+func (r *readOnlyStorage) LogStatusChange(ctx context.Context, issueID string, newStatus types.Status, actor, reason string) {
+}
+
+// LogStatusChangeFromUpdates does nothing, like LogStatusChange
+func (r *readOnlyStorage) LogStatusChangeFromUpdates(ctx context.Context, issueID string, updates map[string]interface{}, actor, reason string) {
+}
+
+func (r *readOnlyStorage) ReleaseIssue(ctx context.Context, issueID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) RecordWatchdogIntervention(ctx context.Context, issueID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) SaveInterruptMetadata(ctx context.Context, metadata *types.InterruptMetadata) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) MarkInterruptResumed(ctx context.Context, issueID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteInterruptMetadata(ctx context.Context, issueID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CreateExecution(ctx context.Context, execution *types.Execution) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) UpdateExecution(ctx context.Context, execution *types.Execution) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteExecution(ctx context.Context, id int64) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) AppendExecutionOutput(ctx context.Context, lines []*types.OutputLine) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) CleanupExecutionOutput(ctx context.Context, retentionDays int) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) RecordAIUsage(ctx context.Context, usage *types.AIUsage) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) AddAttachment(ctx context.Context, attachment *types.Attachment, data []byte) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteAttachment(ctx context.Context, id int64) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) RecordIdempotencyKey(ctx context.Context, key, result string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) SetConfig(ctx context.Context, key, value string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) StorePlan(ctx context.Context, plan *types.MissionPlan, expectedIteration int) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) DeletePlan(ctx context.Context, missionID string) error {
+	return ErrReadOnly
+}
+
+func (r *readOnlyStorage) Import(ctx context.Context, rd io.Reader) (*types.ImportStats, error) {
+	return nil, ErrReadOnly
+}
+
+func (r *readOnlyStorage) ImportEvents(ctx context.Context, events []*types.Event) (int, error) {
+	return 0, ErrReadOnly
+}
+
+func (r *readOnlyStorage) ArchiveIssues(ctx context.Context, opts types.ArchiveOptions, w io.Writer) (*types.ArchiveStats, error) {
+	return nil, ErrReadOnly
+}
+
+// RunInVCTransaction refuses to start a transaction: VCTransaction only writes
+func (r *readOnlyStorage) RunInVCTransaction(ctx context.Context, fn func(tx *VCTransaction) error) error {
+	return ErrReadOnly
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// readOnlyPassThrough are the Storage methods a ReadOnly store still runs,
+// besides the ones named like reads (Get*, List*, Search*)
+var readOnlyPassThrough = map[string]bool{
+	"DetectCycles":   true,
+	"IsEpicComplete": true,
+	"Watch":          true,
+	"Export":         true,
+	"Close":          true,
+	// No error to return; they silently do nothing
+	"LogStatusChange":            true,
+	"LogStatusChangeFromUpdates": true,
+}
+
+func TestReadOnlyRefusesEveryWrite(t *testing.T) {
+	store := ReadOnly(memory.New())
+	value := reflect.ValueOf(store)
+	storageType := reflect.TypeOf((*Storage)(nil)).Elem()
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	for i := 0; i < storageType.NumMethod(); i++ {
+		name := storageType.Method(i).Name
+		if readOnlyPassThrough[name] || strings.HasPrefix(name, "Get") ||
+			strings.HasPrefix(name, "List") || strings.HasPrefix(name, "Search") {
+			continue
+		}
+		method := value.MethodByName(name)
+		args := make([]reflect.Value, method.Type().NumIn())
+		for j := range args {
+			if in := method.Type().In(j); in == ctxType {
+				args[j] = reflect.ValueOf(context.Background())
+			} else {
+				args[j] = reflect.Zero(in)
+			}
+		}
+		results := method.Call(args)
+		if len(results) == 0 {
+			t.Errorf("%s: no error to refuse with; add it to readOnlyPassThrough if it may be skipped", name)
+			continue
+		}
+		err, _ := results[len(results)-1].Interface().(error)
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s returned %v, want ErrReadOnly", name, err)
+		}
+	}
+}
+
+func TestReadOnlyReads(t *testing.T) {
+	ctx := context.Background()
+	base := memory.New()
+	issue := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 1,
+		IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	if err := base.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	store := ReadOnly(base)
+	if ReadOnly(store) != store {
+		t.Error("expected ReadOnly of a read-only store to return it unchanged")
+	}
+	if !IsReadOnly(store) || IsReadOnly(base) {
+		t.Error("expected IsReadOnly to recognize only the wrapped store")
+	}
+
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil || got == nil || got.Title != "Task" {
+		t.Fatalf("GetIssue = %v, %v; want the issue", got, err)
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Changed"}, "test"); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("UpdateIssue returned %v, want ErrReadOnly", err)
+	}
+	store.LogStatusChange(ctx, issue.ID, types.StatusClosed, "test", "ignored")
+	if got, _ := base.GetIssue(ctx, issue.ID); got.Title != "Task" {
+		t.Errorf("expected the underlying issue to be unchanged, got title %q", got.Title)
+	}
+}
+
+func TestNewStorageReadOnly(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "vc.db")
+
+	if _, err := NewStorage(ctx, &Config{Path: path, ReadOnly: true}); err == nil {
+		t.Fatal("expected opening a missing database read-only to fail")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the failed read-only open not to create %s (stat: %v)", path, err)
+	}
+
+	rw, err := NewStorage(ctx, &Config{Path: path})
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+	issue := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 1,
+		IssueType: types.TypeTask, AcceptanceCriteria: "Done"}
+	if err := rw.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// Attach while the read-write store is still open, like a dashboard
+	// next to a running executor
+	ro, err := NewStorage(ctx, &Config{Path: path, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("NewStorage(ReadOnly) failed: %v", err)
+	}
+	defer ro.Close()
+	defer rw.Close()
+
+	if !IsReadOnly(ro) {
+		t.Error("expected a ReadOnly config to return a read-only store")
+	}
+	got, err := ro.GetIssue(ctx, issue.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetIssue = %v, %v; want the issue", got, err)
+	}
+	if err := ro.AddComment(ctx, issue.ID, "test", "hello"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddComment returned %v, want ErrReadOnly", err)
+	}
+}
@@ -2,10 +2,13 @@ package storage
 
 import (
 	"context"
+	"io"
 	"os"
+	"time"
 
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/storage/beads"
+	"github.com/steveyegge/vc/internal/storage/memory"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -43,6 +46,8 @@ type VCTransaction = beads.VCTransaction
 //   - internal/repl/conversation_test.go
 //   - internal/repl/conversation_integration_test.go
 //   - internal/watchdog/analyzer_test.go
+//
+// The in-memory backend (internal/storage/memory) must implement new methods too.
 type Storage interface {
 	// Agent Events - structured events extracted from agent output
 	StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error
@@ -54,8 +59,10 @@ type Storage interface {
 	CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error)
 	CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error)
 	CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error)
+	CompactIssueEvents(ctx context.Context, retentionDays, batchSize int) (int, error) // Old events of closed issues become one summary comment
 	GetEventCounts(ctx context.Context) (*types.EventCounts, error)
 	VacuumDatabase(ctx context.Context) error
+	AnalyzeDatabase(ctx context.Context) error // Refresh the query planner's statistics
 
 	// Issues
 	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
@@ -68,6 +75,13 @@ type Storage interface {
 	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
 	CloseIssue(ctx context.Context, id string, reason string, actor string) error
 	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	// SearchIssuesPage is SearchIssues in keyset-paginated pages (see
+	// types.PageRequest); the page size replaces filter.Limit
+	SearchIssuesPage(ctx context.Context, query string, filter types.IssueFilter, page types.PageRequest) (*types.IssuePage, error)
+	// CloneIssue copies an issue as a new open issue, optionally with its
+	// parent-child subtree and dependencies (see types.CloneOptions), but
+	// never its execution history
+	CloneIssue(ctx context.Context, id string, opts types.CloneOptions, actor string) (*types.CloneResult, error)
 
 	// Dependencies
 	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
@@ -77,6 +91,11 @@ type Storage interface {
 	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
 	GetDependencyTree(ctx context.Context, issueID string, maxDepth int) ([]*types.TreeNode, error)
 	DetectCycles(ctx context.Context) ([][]*types.Issue, error)
+	// GetBlockers returns the unclosed issues that issueID is blocked by via
+	// 'blocks' dependencies; GetBlocked returns the unclosed issues that
+	// issueID blocks. Other dependency types never gate ordering.
+	GetBlockers(ctx context.Context, issueID string) ([]*types.Issue, error)
+	GetBlocked(ctx context.Context, issueID string) ([]*types.Issue, error)
 
 	// Labels
 	AddLabel(ctx context.Context, issueID, label, actor string) error
@@ -84,6 +103,57 @@ type Storage interface {
 	GetLabels(ctx context.Context, issueID string) ([]string, error)
 	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
 
+	// Label definitions: descriptions and colors for labels, and renaming or
+	// deleting a label on every issue at once. ListLabelDefinitions includes
+	// system labels and labels in use that have no saved definition;
+	// GetLabelDefinition returns nil for a label that is none of these.
+	// RenameLabel and DeleteLabel return the number of issues changed and fail
+	// with types.ErrSystemLabel for reserved labels (see types.SystemLabels).
+	ListLabelDefinitions(ctx context.Context) ([]*types.LabelDefinition, error)
+	GetLabelDefinition(ctx context.Context, name string) (*types.LabelDefinition, error)
+	SaveLabelDefinition(ctx context.Context, def *types.LabelDefinition) error
+	RenameLabel(ctx context.Context, oldName, newName, actor string) (int, error)
+	DeleteLabel(ctx context.Context, name, actor string) (int, error)
+
+	// Projects: namespaces for issues (see types.Project). Membership is the
+	// issue's project label; SetIssueProject replaces it ("" removes it) and
+	// fails if the project doesn't exist. GetProject returns nil for an
+	// unknown project. Use ScopeToProject to confine a Storage to one project.
+	ListProjects(ctx context.Context) ([]*types.Project, error)
+	GetProject(ctx context.Context, name string) (*types.Project, error)
+	SaveProject(ctx context.Context, project *types.Project) error
+	SetIssueProject(ctx context.Context, issueID, project, actor string) error
+
+	// Custom fields: typed fields on issues (see types.CustomField), global
+	// or per project. SetCustomFieldValue checks the value against the
+	// definition that applies to the issue's project ("" clears it).
+	// DeleteCustomField also deletes the values of a name once no definition
+	// of it is left.
+	ListCustomFields(ctx context.Context) ([]*types.CustomField, error)
+	SaveCustomField(ctx context.Context, field *types.CustomField) error
+	DeleteCustomField(ctx context.Context, project, name string) error
+	SetCustomFieldValue(ctx context.Context, issueID, name, value, actor string) error
+	GetCustomFieldValues(ctx context.Context, issueID string) ([]*types.CustomFieldValue, error)
+
+	// Saved filters: named issue queries (see types.SavedFilter) run by
+	// ListIssuesByFilter. GetSavedFilter returns nil for an unknown name.
+	ListSavedFilters(ctx context.Context) ([]*types.SavedFilter, error)
+	GetSavedFilter(ctx context.Context, name string) (*types.SavedFilter, error)
+	SaveFilter(ctx context.Context, filter *types.SavedFilter) error
+	DeleteFilter(ctx context.Context, name string) error
+	ListIssuesByFilter(ctx context.Context, name string) ([]*types.Issue, error)
+
+	// Schedules: recurring issues (see types.Schedule). GetSchedule returns
+	// nil for an unknown name. ClaimScheduleRun moves a due schedule's next
+	// run to next, returning false if the run is no longer due (e.g. another
+	// executor claimed it), so each run creates at most one issue.
+	ListSchedules(ctx context.Context) ([]*types.Schedule, error)
+	GetSchedule(ctx context.Context, name string) (*types.Schedule, error)
+	SaveSchedule(ctx context.Context, schedule *types.Schedule) error
+	DeleteSchedule(ctx context.Context, name string) error
+	ClaimScheduleRun(ctx context.Context, name string, now, next time.Time) (bool, error)
+	SetScheduleLastIssue(ctx context.Context, name, issueID string) error
+
 	// Ready Work & Blocking
 	GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error)
 	GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error)
@@ -93,6 +163,10 @@ type Storage interface {
 
 	// Epic Completion (vc-232)
 	IsEpicComplete(ctx context.Context, epicID string) (bool, error)
+	// GetEpicRollup aggregates an epic's or mission's direct (parent-child)
+	// children: counts by status, estimated vs actual effort, open blockers
+	// and quality gate pass rate
+	GetEpicRollup(ctx context.Context, epicID string) (*types.EpicRollup, error)
 
 	// Mission Context (vc-233)
 	GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error)
@@ -107,6 +181,25 @@ type Storage interface {
 	// Events
 	AddComment(ctx context.Context, issueID, actor, comment string) error
 	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
+	// GetEventsPage walks events in keyset-paginated pages (see
+	// types.PageRequest), across all issues if issueID is ""
+	GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error)
+	// GetAuditLog returns field-level changes to an issue (including after it is
+	// deleted), newest first; limit <= 0 means no limit
+	GetAuditLog(ctx context.Context, issueID string, limit int) ([]*types.AuditEntry, error)
+
+	// Threaded Comments (comments are "commented" events; see types.Comment)
+	// GetComments returns an issue's comments oldest first, flat (nest them
+	// with types.ThreadComments)
+	GetComments(ctx context.Context, issueID string) ([]*types.Comment, error)
+	// ReplyToComment adds a comment to the parent comment's issue, in its thread
+	ReplyToComment(ctx context.Context, parentID int64, actor, body string) (*types.Comment, error)
+	// EditComment replaces a comment's body, keeping the old one in its
+	// history. Only the comment's author can edit it.
+	EditComment(ctx context.Context, commentID int64, actor, body string) error
+	// AddReaction and RemoveReaction are idempotent
+	AddReaction(ctx context.Context, commentID int64, actor, reaction string) error
+	RemoveReaction(ctx context.Context, commentID int64, actor, reaction string) error
 
 	// Statistics
 	GetStatistics(ctx context.Context) (*types.Statistics, error)
@@ -122,6 +215,15 @@ type Storage interface {
 
 	// Issue Execution State (Checkpoint/Resume)
 	ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error
+	// ClaimNextReadyIssue atomically claims the highest-priority ready issue for
+	// workerID (a registered executor instance) and moves it to in_progress,
+	// holding it for lease (types.DefaultClaimLease if <= 0). An in_progress
+	// issue whose lease has expired is taken over. Only issues in project are
+	// claimed, unless it is "". Returns nil if nothing can be claimed.
+	ClaimNextReadyIssue(ctx context.Context, workerID string, lease time.Duration, project string) (*types.Issue, error)
+	// RenewClaimLease extends workerID's lease on issueID to lease from now.
+	// Fails if workerID no longer holds the claim (e.g. the lease expired and was taken over).
+	RenewClaimLease(ctx context.Context, issueID, workerID string, lease time.Duration) error
 	GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error)
 	UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error
 	SaveCheckpoint(ctx context.Context, issueID string, checkpointData interface{}) error
@@ -162,8 +264,56 @@ type Storage interface {
 	// Returns attempts in chronological order (oldest first).
 	GetExecutionHistoryPaginated(ctx context.Context, issueID string, limit, offset int) ([]*types.ExecutionAttempt, error)
 
+	// RecordExecutionAttempt stores an attempt and sets attempt.ID
 	RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error
 
+	// Executions - one record per agent run (see types.Execution), written by
+	// the executor. CreateExecution sets ID, and StartedAt if it is zero;
+	// UpdateExecution saves everything but the issue and start time.
+	// GetExecution returns nil for an unknown ID; ListExecutions returns
+	// newest first.
+	CreateExecution(ctx context.Context, execution *types.Execution) error
+	UpdateExecution(ctx context.Context, execution *types.Execution) error
+	GetExecution(ctx context.Context, id int64) (*types.Execution, error)
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+	DeleteExecution(ctx context.Context, id int64) error
+
+	// Execution output - the lines an execution's agent writes (see
+	// types.OutputLine), appended as the agent runs so it can be followed live.
+	// AppendExecutionOutput sets each line's ID. ListExecutionOutput returns
+	// oldest first. Output is deleted with its execution, and
+	// CleanupExecutionOutput deletes the output of executions that finished
+	// more than retentionDays ago.
+	AppendExecutionOutput(ctx context.Context, lines []*types.OutputLine) error
+	ListExecutionOutput(ctx context.Context, filter types.OutputFilter) ([]*types.OutputLine, error)
+	CleanupExecutionOutput(ctx context.Context, retentionDays int) (int, error)
+
+	// AI Usage - one record per AI supervisor call (see types.AIUsage), written
+	// by the cost tracker. An IssueID that isn't a known issue is stored as
+	// empty. ListAIUsage returns oldest first.
+	RecordAIUsage(ctx context.Context, usage *types.AIUsage) error
+	ListAIUsage(ctx context.Context, filter types.AIUsageFilter) ([]*types.AIUsage, error)
+
+	// Attachments - large artifacts (diffs, gate logs, transcripts) kept in full
+	// rather than truncated into comments. Content is stored once per SHA-256.
+	// AddAttachment fills in ID, ContentHash, Size and CreatedAt; GetAttachment
+	// returns nil if the attachment doesn't exist; ListAttachments returns
+	// metadata only, oldest first.
+	AddAttachment(ctx context.Context, attachment *types.Attachment, data []byte) error
+	GetAttachment(ctx context.Context, id int64) (*types.Attachment, []byte, error)
+	ListAttachments(ctx context.Context, issueID string) ([]*types.Attachment, error)
+	DeleteAttachment(ctx context.Context, id int64) error
+
+	// Idempotency keys - record that a supervisor side effect (an issue filed,
+	// a comment posted, a decision made) was applied, so a handler re-run after
+	// a crash doesn't apply it twice (see internal/idempotency).
+	// GetIdempotencyKey returns the result recorded for key, if it was recorded
+	// at or after since; RecordIdempotencyKey records or replaces it.
+	// DeleteIdempotencyKeysBefore deletes keys recorded before a time.
+	GetIdempotencyKey(ctx context.Context, key string, since time.Time) (string, bool, error)
+	RecordIdempotencyKey(ctx context.Context, key, result string) error
+	DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int, error)
+
 	// Config
 	GetConfig(ctx context.Context, key string) (string, error)
 	SetConfig(ctx context.Context, key, value string) error
@@ -188,6 +338,31 @@ type Storage interface {
 	// ListDraftPlans retrieves all plans with status not 'approved' (for cleanup/monitoring)
 	ListDraftPlans(ctx context.Context) ([]*types.MissionPlan, error)
 
+	// Watch delivers issue and execution changes matching filter as they
+	// happen, starting from now, until ctx is done or the store is closed (the
+	// channel is then closed). Lets UIs, webhooks and the watchdog react to
+	// changes without tight polling loops of their own.
+	Watch(ctx context.Context, filter types.WatchFilter) (<-chan types.ChangeEvent, error)
+
+	// Export/Import - versioned JSONL dump of the full issue graph
+	// (issues, dependencies, labels, events, execution history); see internal/storage/export
+	// Export writes a consistent snapshot of the graph to w
+	Export(ctx context.Context, w io.Writer) error
+
+	// Import adds the graph in r atomically, preserving issue IDs and timestamps.
+	// Existing issues are skipped, so re-importing the same dump is a no-op.
+	Import(ctx context.Context, r io.Reader) (*types.ImportStats, error)
+
+	// ImportEvents adds events copied from another database, keeping their
+	// timestamps. Events already recorded (same SyncKey) are skipped.
+	ImportEvents(ctx context.Context, events []*types.Event) (int, error)
+
+	// ArchiveIssues moves issues closed before opts.ClosedBefore out of the
+	// database, writing them with their history to w as a dump that Import
+	// restores. w is written (and synced, if it is a file) before anything is
+	// deleted.
+	ArchiveIssues(ctx context.Context, opts types.ArchiveOptions, w io.Writer) (*types.ArchiveStats, error)
+
 	// Transactions
 	//
 	// RunInVCTransaction executes a function within a database transaction using VC types.
@@ -217,6 +392,18 @@ type Config struct {
 	// Default: ".beads/beads.db"
 	// Special value ":memory:" creates an in-memory database (useful for tests)
 	Path string
+
+	// Memory selects the in-memory backend instead of SQLite. Nothing is
+	// written to disk and Path is ignored; all data is lost on Close.
+	Memory bool
+
+	// Passphrase encrypts the database at rest (see beads.DatabaseURI)
+	// Default: $VC_DB_PASSPHRASE; empty means unencrypted
+	Passphrase string
+
+	// ReadOnly opens an existing database without creating or migrating
+	// anything VC owns, and fails every write with ErrReadOnly (see ReadOnly)
+	ReadOnly bool
 }
 
 // DefaultConfig returns a config with sensible defaults
@@ -229,7 +416,8 @@ func DefaultConfig() *Config {
 		path = ".beads/beads.db"
 	}
 	return &Config{
-		Path: path,
+		Path:       path,
+		Passphrase: Passphrase(),
 	}
 }
 
@@ -242,6 +430,13 @@ func NewStorage(ctx context.Context, cfg *Config) (Storage, error) {
 		cfg = DefaultConfig()
 	}
 
+	if cfg.Memory {
+		if cfg.ReadOnly {
+			return ReadOnly(memory.New()), nil
+		}
+		return memory.New(), nil
+	}
+
 	// Default to standard path if not specified
 	if cfg.Path == "" {
 		// vc-235: Check environment variable before falling back to default
@@ -251,5 +446,16 @@ func NewStorage(ctx context.Context, cfg *Config) (Storage, error) {
 		}
 	}
 
-	return beads.NewVCStorage(ctx, cfg.Path)
+	passphrase := cfg.Passphrase
+	if passphrase == "" {
+		passphrase = Passphrase()
+	}
+	if cfg.ReadOnly {
+		store, err := beads.OpenExistingVCStorage(ctx, cfg.Path, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return ReadOnly(store), nil
+	}
+	return beads.NewEncryptedVCStorage(ctx, cfg.Path, passphrase)
 }
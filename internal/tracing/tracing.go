@@ -0,0 +1,159 @@
+// Package tracing instruments VC's execution pipeline with OpenTelemetry.
+//
+// Code opens spans with Start and closes them with End; while tracing is
+// disabled the global tracer provider is a no-op, so instrumented code pays
+// nothing for it. Setup installs an OTLP/HTTP exporter when tracing is
+// enabled. AI provider calls are traced by AnthropicMiddleware, which
+// records the model and token usage of every request a client makes.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/steveyegge/vc/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names VC's tracer
+const instrumentationName = "github.com/steveyegge/vc"
+
+// Span attribute keys. AI provider spans use the OpenTelemetry GenAI
+// semantic conventions.
+const (
+	AttrIssueID      = "vc.issue.id"
+	AttrIssueType    = "vc.issue.type"
+	AttrExecutionID  = "vc.execution.id"
+	AttrExecutorID   = "vc.executor.id"
+	AttrOperation    = "vc.ai.operation"
+	AttrAgent        = "vc.agent.type"
+	AttrProvider     = "gen_ai.system"
+	AttrModel        = "gen_ai.request.model"
+	AttrMaxTokens    = "gen_ai.request.max_tokens"
+	AttrInputTokens  = "gen_ai.usage.input_tokens"
+	AttrOutputTokens = "gen_ai.usage.output_tokens"
+)
+
+// Setup installs a tracer provider exporting to the configured collector
+// and returns a function that flushes and stops it. When tracing is
+// disabled nothing is installed and the returned function does nothing.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(float64(cfg.SamplePercent)/100))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start opens a span named name as a child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End closes span, marking it failed if err is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetAttributes adds attributes to the span in ctx, if any
+func SetAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// AnthropicMiddleware returns Anthropic client middleware that traces each
+// API request as a GenAI span with its model and token usage
+func AnthropicMiddleware() option.Middleware {
+	return func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		ctx, span := Start(req.Context(), "chat", attribute.String(AttrProvider, "anthropic"))
+		if !span.IsRecording() {
+			span.End()
+			return next(req)
+		}
+
+		var request struct {
+			Model     string `json:"model"`
+			MaxTokens int64  `json:"max_tokens"`
+		}
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				_ = json.NewDecoder(body).Decode(&request)
+				_ = body.Close()
+			}
+		}
+		if request.Model != "" {
+			span.SetName("chat " + request.Model)
+			span.SetAttributes(attribute.String(AttrModel, request.Model), attribute.Int64(AttrMaxTokens, request.MaxTokens))
+		}
+
+		resp, err := next(req.WithContext(ctx))
+		if err != nil {
+			End(span, err)
+			return resp, err
+		}
+		defer span.End()
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+			return resp, nil
+		}
+		if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+			return resp, nil // Streamed responses report usage in events we don't parse
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return resp, err
+		}
+		var response struct {
+			Model string `json:"model"`
+			Usage struct {
+				InputTokens  int64 `json:"input_tokens"`
+				OutputTokens int64 `json:"output_tokens"`
+			} `json:"usage"`
+		}
+		if json.Unmarshal(body, &response) == nil {
+			span.SetAttributes(
+				attribute.String("gen_ai.response.model", response.Model),
+				attribute.Int64(AttrInputTokens, response.Usage.InputTokens),
+				attribute.Int64(AttrOutputTokens, response.Usage.OutputTokens),
+			)
+		}
+		return resp, nil
+	}
+}
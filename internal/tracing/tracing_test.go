@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/steveyegge/vc/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider recording spans for the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestStartEnd(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, parent := Start(context.Background(), "vc.execution", attribute.String(AttrIssueID, "vc-1"))
+	_, child := Start(ctx, "vc.assess")
+	End(child, errors.New("assessment failed"))
+	SetAttributes(ctx, attribute.Int64(AttrExecutionID, 7))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name() != "vc.assess" || spans[0].Status().Code != codes.Error || spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("child span = %s %v, want a failed child of the execution", spans[0].Name(), spans[0].Status())
+	}
+	got := attrs(spans[1])
+	if got[AttrIssueID].AsString() != "vc-1" || got[AttrExecutionID].AsInt64() != 7 {
+		t.Errorf("execution span attributes = %v", got)
+	}
+}
+
+func TestAnthropicMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "msg_1", "type": "message", "role": "assistant", "model": "claude-test",
+			"content": [{"type": "text", "text": "hello"}], "stop_reason": "end_turn",
+			"usage": {"input_tokens": 12, "output_tokens": 3}}`))
+	}))
+	defer server.Close()
+
+	client := anthropic.NewClient(option.WithAPIKey("test"), option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0), option.WithMiddleware(AnthropicMiddleware()))
+	ctx, parent := Start(context.Background(), "ai assessment")
+	message, err := client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     "claude-test",
+		MaxTokens: 100,
+		Messages:  []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock("hi"))},
+	})
+	parent.End()
+	if err != nil {
+		t.Fatalf("Messages.New failed: %v", err)
+	}
+	if message.Content[0].Text != "hello" {
+		t.Errorf("response text = %q; the middleware must leave the body readable", message.Content[0].Text)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "chat claude-test" {
+		t.Fatalf("spans = %v, want a chat span under the operation", spans)
+	}
+	got := attrs(spans[0])
+	if got[AttrProvider].AsString() != "anthropic" || got[AttrModel].AsString() != "claude-test" ||
+		got[AttrMaxTokens].AsInt64() != 100 || got[AttrInputTokens].AsInt64() != 12 || got[AttrOutputTokens].AsInt64() != 3 {
+		t.Errorf("chat span attributes = %v", got)
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Error("chat span is not a child of the operation span")
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.DefaultTracingConfig())
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+}
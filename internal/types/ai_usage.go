@@ -0,0 +1,46 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// AIUsage is one call the AI supervisor made: what it was for, the model,
+// the tokens used and what they cost. Agent spend is recorded on executions
+// instead (see Execution.CostUSD).
+type AIUsage struct {
+	ID           string        `json:"id"`
+	Timestamp    time.Time     `json:"timestamp"`
+	IssueID      string        `json:"issue_id,omitempty"` // Empty for work not on an issue, e.g. digests
+	Operation    string        `json:"operation"`          // e.g. "assessment", "analysis"
+	Model        string        `json:"model"`
+	InputTokens  int64         `json:"input_tokens"`
+	OutputTokens int64         `json:"output_tokens"`
+	CostUSD      float64       `json:"cost_usd"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// Validate checks the usage record's required fields
+func (u *AIUsage) Validate() error {
+	if u.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if u.Operation == "" {
+		return fmt.Errorf("operation is required")
+	}
+	if u.InputTokens < 0 || u.OutputTokens < 0 {
+		return fmt.Errorf("token counts cannot be negative")
+	}
+	if u.CostUSD < 0 {
+		return fmt.Errorf("cost cannot be negative")
+	}
+	return nil
+}
+
+// AIUsageFilter selects usage records for ListAIUsage. Zero values match
+// everything.
+type AIUsageFilter struct {
+	IssueID string
+	Since   time.Time // At or after
+	Until   time.Time // Before
+}
@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+// ArchiveOptions selects the issues Storage.ArchiveIssues moves out of the
+// database
+type ArchiveOptions struct {
+	ClosedBefore time.Time // Archive issues closed before this time
+	DryRun       bool      // Count what would be archived without writing or deleting anything
+}
+
+// ArchiveStats reports what Storage.ArchiveIssues archived
+type ArchiveStats struct {
+	Issues       int
+	Dependencies int
+	Labels       int
+	Events       int
+	Executions   int // Execution attempts
+	// Kept counts issues closed long enough that stayed in the database:
+	// ones linked by a dependency to an issue that isn't archived, or with
+	// attachments or custom field values, which archives don't hold
+	Kept int
+}
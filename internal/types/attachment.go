@@ -0,0 +1,63 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxAttachmentSize caps a single attachment. Attachments live in the
+// database alongside everything else, so anything bigger than this belongs
+// on disk, not in an issue.
+const MaxAttachmentSize = 32 * 1024 * 1024
+
+// AttachmentKind categorizes what an attachment holds
+type AttachmentKind string
+
+const (
+	// AttachmentDiff is a code diff produced by an execution
+	AttachmentDiff AttachmentKind = "diff"
+	// AttachmentGateLog is the full output of a quality gate run
+	AttachmentGateLog AttachmentKind = "gate_log"
+	// AttachmentTranscript is an agent transcript
+	AttachmentTranscript AttachmentKind = "transcript"
+	// AttachmentOther is anything else
+	AttachmentOther AttachmentKind = "other"
+)
+
+// IsValid checks if the attachment kind value is valid
+func (k AttachmentKind) IsValid() bool {
+	switch k {
+	case AttachmentDiff, AttachmentGateLog, AttachmentTranscript, AttachmentOther:
+		return true
+	}
+	return false
+}
+
+// Attachment is a large artifact attached to an issue, and optionally to one
+// execution attempt of it. The content is stored once per distinct SHA-256
+// hash, so attaching the same log twice costs nothing extra.
+type Attachment struct {
+	ID          int64          `json:"id"`
+	IssueID     string         `json:"issue_id"`
+	ExecutionID *int64         `json:"execution_id,omitempty"` // vc_execution_history row, if any
+	Name        string         `json:"name"`                   // e.g. "go test output"
+	Kind        AttachmentKind `json:"kind"`
+	ContentHash string         `json:"content_hash"` // hex SHA-256 of the content
+	Size        int64          `json:"size"`
+	CreatedAt   time.Time      `json:"created_at"`
+	CreatedBy   string         `json:"created_by"`
+}
+
+// Validate checks the caller-supplied fields of an attachment
+func (a *Attachment) Validate() error {
+	if a.IssueID == "" {
+		return fmt.Errorf("issue_id is required")
+	}
+	if a.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !a.Kind.IsValid() {
+		return fmt.Errorf("invalid attachment kind: %s (must be diff, gate_log, transcript, or other)", a.Kind)
+	}
+	return nil
+}
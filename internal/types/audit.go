@@ -0,0 +1,117 @@
+package types
+
+import (
+	"strconv"
+	"time"
+)
+
+// AuditOperation is the kind of mutation an audit entry records
+type AuditOperation string
+
+const (
+	AuditCreate AuditOperation = "create"
+	AuditUpdate AuditOperation = "update"
+	AuditDelete AuditOperation = "delete"
+)
+
+// AuditEntry records one field of one issue changing. A single create, update
+// or delete produces one entry per field it changed, all with the same
+// operation, actor and timestamp. Entries outlive the issue they describe.
+type AuditEntry struct {
+	ID        int64          `json:"id"`
+	IssueID   string         `json:"issue_id"`
+	Operation AuditOperation `json:"operation"`
+	Field     string         `json:"field"`
+	OldValue  string         `json:"old_value"` // "" for a create, or a field that was unset
+	NewValue  string         `json:"new_value"` // "" for a delete, or a field that was cleared
+	Actor     string         `json:"actor"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// AuditedIssueFields are the issue fields tracked by the audit log, named as
+// in UpdateIssue and in the order entries are produced
+var AuditedIssueFields = []string{
+	"title",
+	"description",
+	"design",
+	"acceptance_criteria",
+	"notes",
+	"status",
+	"priority",
+	"issue_type",
+	"issue_subtype",
+	"assignee",
+	"estimated_minutes",
+	"closed_at",
+}
+
+// AuditEntriesFor returns an entry for each audited field that differs
+// between before and after. before is nil for a create and after is nil for
+// a delete. Timestamps are left for the storage backend to set.
+func AuditEntriesFor(issueID string, before, after *Issue, actor string) []*AuditEntry {
+	operation := AuditUpdate
+	switch {
+	case before == nil:
+		operation = AuditCreate
+	case after == nil:
+		operation = AuditDelete
+	}
+
+	var entries []*AuditEntry
+	for _, field := range AuditedIssueFields {
+		oldValue := auditFieldValue(before, field)
+		newValue := auditFieldValue(after, field)
+		if oldValue == newValue {
+			continue
+		}
+		entries = append(entries, &AuditEntry{
+			IssueID:   issueID,
+			Operation: operation,
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			Actor:     actor,
+		})
+	}
+	return entries
+}
+
+// auditFieldValue renders an issue field for the audit log ("" if issue is nil)
+func auditFieldValue(issue *Issue, field string) string {
+	if issue == nil {
+		return ""
+	}
+	switch field {
+	case "title":
+		return issue.Title
+	case "description":
+		return issue.Description
+	case "design":
+		return issue.Design
+	case "acceptance_criteria":
+		return issue.AcceptanceCriteria
+	case "notes":
+		return issue.Notes
+	case "status":
+		return string(issue.Status)
+	case "priority":
+		return strconv.Itoa(issue.Priority)
+	case "issue_type":
+		return string(issue.IssueType)
+	case "issue_subtype":
+		return string(issue.IssueSubtype)
+	case "assignee":
+		return issue.Assignee
+	case "estimated_minutes":
+		if issue.EstimatedMinutes == nil {
+			return ""
+		}
+		return strconv.Itoa(*issue.EstimatedMinutes)
+	case "closed_at":
+		if issue.ClosedAt == nil {
+			return ""
+		}
+		return issue.ClosedAt.UTC().Format(time.RFC3339)
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditEntriesFor(t *testing.T) {
+	minutes := 30
+	before := &Issue{
+		Title:            "Fix bug",
+		Status:           StatusOpen,
+		Priority:         2,
+		IssueType:        TypeBug,
+		EstimatedMinutes: &minutes,
+	}
+
+	created := AuditEntriesFor("vc-1", nil, before, "alice")
+	fields := map[string]string{}
+	for _, entry := range created {
+		if entry.Operation != AuditCreate || entry.OldValue != "" || entry.Actor != "alice" {
+			t.Errorf("unexpected create entry: %+v", entry)
+		}
+		fields[entry.Field] = entry.NewValue
+	}
+	want := map[string]string{"title": "Fix bug", "status": "open", "priority": "2", "issue_type": "bug", "estimated_minutes": "30"}
+	if len(fields) != len(want) {
+		t.Errorf("create entries = %v, want %v", fields, want)
+	}
+	for field, value := range want {
+		if fields[field] != value {
+			t.Errorf("create %s = %q, want %q", field, fields[field], value)
+		}
+	}
+
+	closedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	after := *before
+	after.Status = StatusClosed
+	after.ClosedAt = &closedAt
+	after.EstimatedMinutes = nil
+	updated := AuditEntriesFor("vc-1", before, &after, "bob")
+	if len(updated) != 3 {
+		t.Fatalf("expected 3 update entries, got %d: %+v", len(updated), updated)
+	}
+	expected := []AuditEntry{
+		{Field: "status", OldValue: "open", NewValue: "closed"},
+		{Field: "estimated_minutes", OldValue: "30", NewValue: ""},
+		{Field: "closed_at", OldValue: "", NewValue: "2025-01-02T03:04:05Z"},
+	}
+	for i, exp := range expected {
+		got := updated[i]
+		if got.Operation != AuditUpdate || got.Field != exp.Field || got.OldValue != exp.OldValue || got.NewValue != exp.NewValue {
+			t.Errorf("entry %d = %+v, want %+v", i, got, exp)
+		}
+	}
+
+	if unchanged := AuditEntriesFor("vc-1", before, before, "bob"); len(unchanged) != 0 {
+		t.Errorf("expected no entries for an unchanged issue, got %+v", unchanged)
+	}
+
+	for _, entry := range AuditEntriesFor("vc-1", &after, nil, "") {
+		if entry.Operation != AuditDelete || entry.NewValue != "" {
+			t.Errorf("unexpected delete entry: %+v", entry)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package types
+
+// CloneOptions controls what CloneIssue copies along with an issue
+type CloneOptions struct {
+	// IncludeChildren copies the issue's parent-child subtree, keeping its
+	// shape: each copied child is a child of its copied parent
+	IncludeChildren bool
+	// IncludeDependencies copies the other dependencies of every copied
+	// issue. Dependencies on issues that are copied too point at the copies;
+	// the rest (including the root's own parent) point at the originals.
+	IncludeDependencies bool
+	// TitleSuffix is appended to every copied title, e.g. " (milestone 2)"
+	TitleSuffix string
+}
+
+// CloneResult describes a clone
+type CloneResult struct {
+	Root *Issue            `json:"root"` // The copy of the cloned issue
+	IDs  map[string]string `json:"ids"`  // Original issue ID -> copy ID, root included
+}
+
+// cloneSkippedLabels are execution state labels, which describe the
+// original's progress rather than the work, so copies don't get them
+var cloneSkippedLabels = map[string]bool{
+	"escalated":            true,
+	"needs-approval":       true,
+	"needs-human-review":   true,
+	"interrupted":          true,
+	"quality-gates-failed": true,
+	"task-ready":           true,
+	"needs-quality-gates":  true,
+	"gates-running":        true,
+	"gates-failed":         true,
+	"needs-review":         true,
+	"needs-human-approval": true,
+	"approved":             true,
+}
+
+// CopiesLabelOnClone reports whether a clone carries label over from the
+// original
+func CopiesLabelOnClone(label string) bool {
+	return !cloneSkippedLabels[label]
+}
+
+// NewClone returns a fresh, open copy of issue's content for CloneIssue to
+// create: the ID, assignee, timestamps and closed state are not copied
+func NewClone(issue *Issue, opts CloneOptions) *Issue {
+	clone := &Issue{
+		Title:              issue.Title + opts.TitleSuffix,
+		Description:        issue.Description,
+		Design:             issue.Design,
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+		Notes:              issue.Notes,
+		Status:             StatusOpen,
+		Priority:           issue.Priority,
+		IssueType:          issue.IssueType,
+		IssueSubtype:       issue.IssueSubtype,
+	}
+	if issue.EstimatedMinutes != nil {
+		minutes := *issue.EstimatedMinutes
+		clone.EstimatedMinutes = &minutes
+	}
+	return clone
+}
+
+// ClonedWith reports whether CloneIssue copies this dependency of a copied
+// issue, given the original -> copy IDs: the parent-child edges of a copied
+// subtree always, anything else only with IncludeDependencies
+func (d *Dependency) ClonedWith(ids map[string]string, opts CloneOptions) bool {
+	if _, copied := ids[d.DependsOnID]; copied && opts.IncludeChildren && d.Type == DepParentChild {
+		return true
+	}
+	return opts.IncludeDependencies
+}
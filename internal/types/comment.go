@@ -0,0 +1,104 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxReactionLength caps a reaction, which is a short token like "+1",
+// "eyes" or an emoji
+const MaxReactionLength = 32
+
+// Comment is a comment on an issue: a "commented" event, plus its place in a
+// thread, its edit history and its reactions. The event itself is never
+// modified; Body is the latest edit, if any.
+type Comment struct {
+	ID        int64              `json:"id"` // ID of the "commented" event
+	IssueID   string             `json:"issue_id"`
+	ParentID  *int64             `json:"parent_id,omitempty"` // Comment this replies to
+	Author    string             `json:"author"`
+	Body      string             `json:"body"`
+	CreatedAt time.Time          `json:"created_at"`
+	EditedAt  *time.Time         `json:"edited_at,omitempty"`
+	Edits     []*CommentEdit     `json:"edits,omitempty"` // Oldest first
+	Reactions []*CommentReaction `json:"reactions,omitempty"`
+	Replies   []*Comment         `json:"replies,omitempty"` // Filled in by ThreadComments
+}
+
+// CommentEdit records one edit of a comment and the body it replaced
+type CommentEdit struct {
+	PreviousBody string    `json:"previous_body"`
+	EditedBy     string    `json:"edited_by"`
+	EditedAt     time.Time `json:"edited_at"`
+}
+
+// CommentReaction is one actor's reaction to a comment. An actor can leave
+// several different reactions, but each only once.
+type CommentReaction struct {
+	Reaction  string    `json:"reaction"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ValidateReaction checks that a reaction is a short token without spaces
+func ValidateReaction(reaction string) error {
+	if reaction == "" {
+		return fmt.Errorf("reaction is required")
+	}
+	if len(reaction) > MaxReactionLength {
+		return fmt.Errorf("reaction is too long (max %d bytes)", MaxReactionLength)
+	}
+	if strings.IndexFunc(reaction, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("reaction %q must not contain whitespace", reaction)
+	}
+	return nil
+}
+
+// ReactionCounts returns how many actors left each reaction on c
+func (c *Comment) ReactionCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, r := range c.Reactions {
+		counts[r.Reaction]++
+	}
+	return counts
+}
+
+// ThreadComments nests a flat list of comments into threads: it returns the
+// top-level comments, with every reply under its parent. Replies to comments
+// that aren't in the list (e.g. compacted away) become top-level. Siblings
+// are ordered oldest first.
+func ThreadComments(comments []*Comment) []*Comment {
+	byID := make(map[int64]*Comment, len(comments))
+	for _, c := range comments {
+		c.Replies = nil
+		byID[c.ID] = c
+	}
+	var roots []*Comment
+	for _, c := range comments {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok && parent != c {
+				parent.Replies = append(parent.Replies, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	sortComments(roots)
+	return roots
+}
+
+// sortComments orders comments and their replies oldest first
+func sortComments(comments []*Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+	for _, c := range comments {
+		sortComments(c.Replies)
+	}
+}
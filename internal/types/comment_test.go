@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThreadComments(t *testing.T) {
+	now := time.Now()
+	id := func(n int64) *int64 { return &n }
+	comments := []*Comment{
+		{ID: 1, CreatedAt: now},
+		{ID: 2, ParentID: id(1), CreatedAt: now.Add(2 * time.Second)},
+		{ID: 3, ParentID: id(1), CreatedAt: now.Add(time.Second)},
+		{ID: 4, ParentID: id(3), CreatedAt: now.Add(3 * time.Second)},
+		{ID: 5, ParentID: id(99), CreatedAt: now.Add(-time.Second)}, // Parent compacted away
+	}
+	roots := ThreadComments(comments)
+	if len(roots) != 2 || roots[0].ID != 5 || roots[1].ID != 1 {
+		t.Fatalf("expected roots [5 1], got %+v", roots)
+	}
+	replies := roots[1].Replies
+	if len(replies) != 2 || replies[0].ID != 3 || replies[1].ID != 2 {
+		t.Fatalf("expected replies [3 2] oldest first, got %+v", replies)
+	}
+	if len(replies[0].Replies) != 1 || replies[0].Replies[0].ID != 4 {
+		t.Errorf("expected comment 4 under comment 3, got %+v", replies[0].Replies)
+	}
+}
+
+func TestValidateReaction(t *testing.T) {
+	for _, r := range []string{"+1", "eyes", "🎉"} {
+		if err := ValidateReaction(r); err != nil {
+			t.Errorf("ValidateReaction(%q) = %v; want nil", r, err)
+		}
+	}
+	for _, r := range []string{"", "thumbs up", "this-reaction-is-far-too-long-to-be-a-reaction"} {
+		if err := ValidateReaction(r); err == nil {
+			t.Errorf("ValidateReaction(%q) = nil; want an error", r)
+		}
+	}
+}
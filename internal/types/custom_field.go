@@ -0,0 +1,155 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CustomFieldType is the kind of value a custom field holds
+type CustomFieldType string
+
+// Custom field types
+const (
+	CustomFieldString CustomFieldType = "string" // Free text
+	CustomFieldNumber CustomFieldType = "number" // Any decimal number
+	CustomFieldEnum   CustomFieldType = "enum"   // One of the field's Options
+)
+
+// IsValid checks if the custom field type is supported
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case CustomFieldString, CustomFieldNumber, CustomFieldEnum:
+		return true
+	}
+	return false
+}
+
+// customFieldNamePattern matches usable field names, which are also used as
+// prompt headings and in `vc list --field name=value`
+var customFieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+// CustomField defines a typed field that issues can carry in addition to the
+// built-in ones, e.g. a "component" enum or a "customer" string, so teams can
+// track their own attributes without changing Issue. A field defined for a
+// project applies to that project's issues and takes precedence over a
+// global field (Project "") of the same name, which applies to every issue.
+type CustomField struct {
+	Project     string          `json:"project,omitempty"`
+	Name        string          `json:"name"`
+	Type        CustomFieldType `json:"type"`
+	Description string          `json:"description,omitempty"`
+	Options     []string        `json:"options,omitempty"` // The allowed values of an enum field
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the field's name, type and options
+func (f *CustomField) Validate() error {
+	if f.Project != "" {
+		if err := ValidateProjectName(f.Project); err != nil {
+			return err
+		}
+	}
+	if err := ValidateCustomFieldName(f.Name); err != nil {
+		return err
+	}
+	if !f.Type.IsValid() {
+		return fmt.Errorf("invalid field type %q: must be string, number or enum", f.Type)
+	}
+	if f.Type != CustomFieldEnum {
+		if len(f.Options) > 0 {
+			return fmt.Errorf("only enum fields have options")
+		}
+		return nil
+	}
+	if len(f.Options) == 0 {
+		return fmt.Errorf("enum field %s needs at least one option", f.Name)
+	}
+	seen := make(map[string]bool, len(f.Options))
+	for _, option := range f.Options {
+		if strings.TrimSpace(option) == "" {
+			return fmt.Errorf("enum field %s has an empty option", f.Name)
+		}
+		if seen[option] {
+			return fmt.Errorf("enum field %s lists option %q twice", f.Name, option)
+		}
+		seen[option] = true
+	}
+	return nil
+}
+
+// ValidateCustomFieldName checks that a custom field name is usable
+func ValidateCustomFieldName(name string) error {
+	if !customFieldNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid field name %q: use up to 64 lowercase letters, digits, '_' or '-', starting with a letter", name)
+	}
+	return nil
+}
+
+// NormalizeValue checks value against the field's type and returns it in
+// canonical form: numbers without redundant digits ("1.50" becomes "1.5"),
+// enum values exactly as listed in Options
+func (f *CustomField) NormalizeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch f.Type {
+	case CustomFieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("field %s is a number, got %q", f.Name, value)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case CustomFieldEnum:
+		for _, option := range f.Options {
+			if option == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("field %s must be one of %s, got %q", f.Name, strings.Join(f.Options, ", "), value)
+	}
+	return value, nil
+}
+
+// QualifiedCustomFieldName names a field definition in messages:
+// "component" for a global field, "web/component" for one of project web
+func QualifiedCustomFieldName(project, name string) string {
+	if project == "" {
+		return name
+	}
+	return project + "/" + name
+}
+
+// ResolveCustomField returns the definition of name that applies to an issue
+// in project: the project's own definition, else the global one, else nil
+func ResolveCustomField(fields []*CustomField, project, name string) *CustomField {
+	var global *CustomField
+	for _, f := range fields {
+		if f.Name != name {
+			continue
+		}
+		if project != "" && f.Project == project {
+			return f
+		}
+		if f.Project == "" {
+			global = f
+		}
+	}
+	return global
+}
+
+// CustomFieldValue is the value of a custom field on an issue, with the type
+// of the definition it was checked against
+type CustomFieldValue struct {
+	Name  string          `json:"name"`
+	Type  CustomFieldType `json:"type"`
+	Value string          `json:"value"`
+}
+
+// SortCustomFieldValues sorts values by field name
+func SortCustomFieldValues(values []*CustomFieldValue) {
+	sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+}
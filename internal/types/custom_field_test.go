@@ -0,0 +1,64 @@
+package types
+
+import "testing"
+
+func TestCustomFieldNormalizeValue(t *testing.T) {
+	tests := []struct {
+		field   CustomField
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{CustomField{Name: "customer", Type: CustomFieldString}, " Acme ", "Acme", false},
+		{CustomField{Name: "sla", Type: CustomFieldNumber}, "1.50", "1.5", false},
+		{CustomField{Name: "sla", Type: CustomFieldNumber}, "soon", "", true},
+		{CustomField{Name: "component", Type: CustomFieldEnum, Options: []string{"api", "ui"}}, "ui", "ui", false},
+		{CustomField{Name: "component", Type: CustomFieldEnum, Options: []string{"api", "ui"}}, "UI", "", true},
+	}
+	for _, tt := range tests {
+		got, err := tt.field.NormalizeValue(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s.NormalizeValue(%q) = %q, %v; want %q, error %v", tt.field.Name, tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCustomFieldValidate(t *testing.T) {
+	valid := []CustomField{
+		{Name: "customer", Type: CustomFieldString},
+		{Name: "component", Project: "web", Type: CustomFieldEnum, Options: []string{"api", "ui"}},
+	}
+	for _, f := range valid {
+		if err := f.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid: %v", f, err)
+		}
+	}
+	invalid := []CustomField{
+		{Name: "Customer", Type: CustomFieldString},
+		{Name: "customer", Type: "date"},
+		{Name: "component", Type: CustomFieldEnum},
+		{Name: "component", Type: CustomFieldEnum, Options: []string{"api", "api"}},
+		{Name: "sla", Type: CustomFieldNumber, Options: []string{"1"}},
+	}
+	for _, f := range invalid {
+		if err := f.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", f)
+		}
+	}
+}
+
+func TestResolveCustomField(t *testing.T) {
+	fields := []*CustomField{
+		{Name: "component", Type: CustomFieldString},
+		{Name: "component", Project: "web", Type: CustomFieldEnum, Options: []string{"ui"}},
+	}
+	if f := ResolveCustomField(fields, "web", "component"); f == nil || f.Project != "web" {
+		t.Errorf("expected the project definition, got %+v", f)
+	}
+	if f := ResolveCustomField(fields, "api", "component"); f == nil || f.Project != "" {
+		t.Errorf("expected the global definition, got %+v", f)
+	}
+	if f := ResolveCustomField(fields, "", "missing"); f != nil {
+		t.Errorf("expected nil, got %+v", f)
+	}
+}
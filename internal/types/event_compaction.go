@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventCompactionActor is the actor of the summary comment left in place of
+// compacted issue events. Summaries are never compacted again.
+const EventCompactionActor = "event-retention"
+
+// IsCompactableEvent reports whether an issue event may be folded into a
+// compaction summary. Creation and close events are kept so an issue's
+// history still shows when it was opened and finished.
+func IsCompactableEvent(event *Event) bool {
+	switch event.EventType {
+	case EventCreated, EventClosed:
+		return false
+	}
+	return event.Actor != EventCompactionActor
+}
+
+// aiUsagePattern matches the comments the AI supervisor records per API call
+var aiUsagePattern = regexp.MustCompile(`^AI Usage \([^)]*\): input=(\d+) tokens, output=(\d+) tokens`)
+
+// EventCompaction accumulates the issue events removed by one compaction, so
+// they can be replaced by a single summary comment
+type EventCompaction struct {
+	IssueID        string
+	Events         int
+	ByType         map[EventType]int
+	AICalls        int
+	AIInputTokens  int64
+	AIOutputTokens int64
+	First          time.Time
+	Last           time.Time
+}
+
+// NewEventCompaction returns an empty compaction for an issue
+func NewEventCompaction(issueID string) *EventCompaction {
+	return &EventCompaction{IssueID: issueID, ByType: make(map[EventType]int)}
+}
+
+// Add folds an event into the compaction
+func (c *EventCompaction) Add(event *Event) {
+	c.Events++
+	c.ByType[event.EventType]++
+	if c.First.IsZero() || event.CreatedAt.Before(c.First) {
+		c.First = event.CreatedAt
+	}
+	if event.CreatedAt.After(c.Last) {
+		c.Last = event.CreatedAt
+	}
+	if event.Comment == nil {
+		return
+	}
+	if m := aiUsagePattern.FindStringSubmatch(*event.Comment); m != nil {
+		input, _ := strconv.ParseInt(m[1], 10, 64)
+		output, _ := strconv.ParseInt(m[2], 10, 64)
+		c.AICalls++
+		c.AIInputTokens += input
+		c.AIOutputTokens += output
+	}
+}
+
+// Summary renders the comment recorded in place of the compacted events
+func (c *EventCompaction) Summary() string {
+	eventTypes := make([]string, 0, len(c.ByType))
+	for eventType := range c.ByType {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+	sort.Strings(eventTypes)
+	counts := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		counts = append(counts, fmt.Sprintf("%s=%d", eventType, c.ByType[EventType(eventType)]))
+	}
+
+	summary := fmt.Sprintf("Compacted %d events from %s to %s (%s)",
+		c.Events, c.First.UTC().Format("2006-01-02"), c.Last.UTC().Format("2006-01-02"), strings.Join(counts, ", "))
+	if c.AICalls > 0 {
+		summary += fmt.Sprintf("; AI usage: %d call(s), input=%d tokens, output=%d tokens",
+			c.AICalls, c.AIInputTokens, c.AIOutputTokens)
+	}
+	return summary
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// OutputStream is the stream an agent wrote an output line to
+type OutputStream string
+
+// Output streams
+const (
+	OutputStdout OutputStream = "stdout"
+	OutputStderr OutputStream = "stderr"
+)
+
+// IsValid checks if the output stream is a known value
+func (s OutputStream) IsValid() bool {
+	return s == OutputStdout || s == OutputStderr
+}
+
+// OutputLine is a line of an execution's agent output. The executor stores
+// lines as the agent writes them, so a running agent can be followed from
+// another process ('vc tail').
+type OutputLine struct {
+	ID          int64        `json:"id"` // Increases in the order lines were stored
+	ExecutionID int64        `json:"execution_id"`
+	IssueID     string       `json:"issue_id"`
+	Stream      OutputStream `json:"stream"`
+	Line        string       `json:"line"`
+	Timestamp   time.Time    `json:"timestamp"`
+}
+
+// Validate checks the output line's required fields
+func (l *OutputLine) Validate() error {
+	if l.ExecutionID == 0 {
+		return fmt.Errorf("execution_id is required")
+	}
+	if l.IssueID == "" {
+		return fmt.Errorf("issue_id is required")
+	}
+	if !l.Stream.IsValid() {
+		return fmt.Errorf("invalid output stream: %s", l.Stream)
+	}
+	return nil
+}
+
+// OutputFilter selects output lines for ListExecutionOutput. Zero values
+// match everything.
+type OutputFilter struct {
+	IssueID     string
+	ExecutionID int64
+	AfterID     int64 // Only lines stored after this one, to poll for new output
+	Limit       int   // Only the last Limit lines
+}
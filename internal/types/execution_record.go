@@ -0,0 +1,157 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// ExecutionStatus is where an execution is in its lifecycle
+type ExecutionStatus string
+
+// Execution statuses
+const (
+	ExecutionRunning     ExecutionStatus = "running"     // The agent is working
+	ExecutionSucceeded   ExecutionStatus = "succeeded"   // The agent finished and the issue was completed
+	ExecutionIncomplete  ExecutionStatus = "incomplete"  // The agent finished but the issue wasn't completed
+	ExecutionFailed      ExecutionStatus = "failed"      // The agent couldn't start, crashed or its results couldn't be processed
+	ExecutionInterrupted ExecutionStatus = "interrupted" // Paused by request; the issue was reopened
+)
+
+// IsValid checks if the execution status is a known value
+func (s ExecutionStatus) IsValid() bool {
+	switch s {
+	case ExecutionRunning, ExecutionSucceeded, ExecutionIncomplete, ExecutionFailed, ExecutionInterrupted:
+		return true
+	}
+	return false
+}
+
+// IsFinal reports whether the execution has ended
+func (s ExecutionStatus) IsFinal() bool {
+	return s != ExecutionRunning
+}
+
+// Execution is one run of an agent on an issue: what ran it, on which
+// prompt, how it ended, how long it took, what it cost and the commit it
+// produced. The executor records one for every agent it spawns.
+type Execution struct {
+	ID                 int64  `json:"id"`
+	IssueID            string `json:"issue_id"`
+	ExecutorInstanceID string `json:"executor_instance_id,omitempty"`
+
+	AgentProvider string `json:"agent_provider"`        // e.g. "claude-code"
+	PromptHash    string `json:"prompt_hash,omitempty"` // PromptHash of the prompt the agent was given
+
+	Status   ExecutionStatus `json:"status"`
+	ExitCode *int            `json:"exit_code,omitempty"` // nil until the agent exits
+	Error    string          `json:"error,omitempty"`     // Why a failed execution failed
+
+	StartedAt     time.Time     `json:"started_at"`
+	CompletedAt   *time.Time    `json:"completed_at,omitempty"`
+	AgentDuration time.Duration `json:"agent_duration"` // Time the agent ran, excluding result processing
+
+	CostUSD    float64 `json:"cost_usd,omitempty"`    // As reported by the agent
+	CommitHash string  `json:"commit_hash,omitempty"` // Commit made from the execution's changes
+
+	// Set when CommitHash was rolled back: the commit that reverted it and
+	// the issue filed to redo the work
+	RevertCommit    string `json:"revert_commit,omitempty"`
+	RollbackIssueID string `json:"rollback_issue_id,omitempty"`
+
+	// The workspace the agent started from and left behind. nil when it
+	// couldn't be taken, e.g. the working directory isn't a git repository.
+	StartSnapshot *WorkspaceSnapshot `json:"start_snapshot,omitempty"`
+	EndSnapshot   *WorkspaceSnapshot `json:"end_snapshot,omitempty"`
+}
+
+// WorkspaceSnapshot identifies the state of a git working tree without
+// copying it: the commit checked out, plus a hash of the uncommitted changes
+// on top of it. Two snapshots are equal exactly when the working trees were.
+type WorkspaceSnapshot struct {
+	CommitSHA string `json:"commit_sha"`           // HEAD; empty before the first commit
+	DirtyHash string `json:"dirty_hash,omitempty"` // Hex SHA-256 of uncommitted changes; empty when clean
+
+	// Files maps each changed or untracked path to a hex SHA-256 of its
+	// status and contents. It tells which files changed since; not persisted.
+	Files map[string]string `json:"-"`
+}
+
+// IsClean reports whether the working tree matched its commit
+func (s *WorkspaceSnapshot) IsClean() bool {
+	return s.DirtyHash == ""
+}
+
+// String returns a short form, e.g. "1a2b3c4d" or "1a2b3c4d+dirty:5e6f7a8b",
+// or "(none)" for a nil snapshot
+func (s *WorkspaceSnapshot) String() string {
+	if s == nil {
+		return "(none)"
+	}
+	commit := shortHash(s.CommitSHA)
+	if commit == "" {
+		commit = "(no commit)"
+	}
+	if s.IsClean() {
+		return commit
+	}
+	return commit + "+dirty:" + shortHash(s.DirtyHash)
+}
+
+// shortHash abbreviates a hex hash for display
+func shortHash(h string) string {
+	if len(h) > 8 {
+		return h[:8]
+	}
+	return h
+}
+
+// Validate checks the execution's required fields and status
+func (e *Execution) Validate() error {
+	if e.IssueID == "" {
+		return fmt.Errorf("issue_id is required")
+	}
+	if e.AgentProvider == "" {
+		return fmt.Errorf("agent_provider is required")
+	}
+	if !e.Status.IsValid() {
+		return fmt.Errorf("invalid execution status: %s", e.Status)
+	}
+	if e.CostUSD < 0 {
+		return fmt.Errorf("cost cannot be negative")
+	}
+	return nil
+}
+
+// IsRolledBack reports whether the execution's commit was reverted
+func (e *Execution) IsRolledBack() bool {
+	return e.RevertCommit != ""
+}
+
+// Duration returns how long the execution took from start to completion, or
+// so far if it is still running
+func (e *Execution) Duration() time.Duration {
+	if e.CompletedAt != nil {
+		return e.CompletedAt.Sub(e.StartedAt)
+	}
+	return time.Since(e.StartedAt)
+}
+
+// ExecutionFilter selects executions for ListExecutions. Zero values match
+// everything.
+type ExecutionFilter struct {
+	IssueID            string
+	ExecutorInstanceID string
+	Status             ExecutionStatus
+	Since              time.Time // Started at or after
+	CommitHash         string    // Produced this commit
+	Limit              int
+}
+
+// PromptHash identifies a prompt without storing it: the hex SHA-256 of its
+// text, so executions given the same prompt can be grouped
+func PromptHash(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,48 @@
+package types
+
+import "time"
+
+// ExportFormat identifies a VC issue graph dump. It is written in the header
+// record so a dump can be recognized without relying on the file name.
+const ExportFormat = "vc-issue-graph"
+
+// ExportVersion is the current dump format version. Bump it when a change
+// would make older readers misinterpret a dump; adding optional fields does
+// not require a bump.
+const ExportVersion = 1
+
+// ExportHeader is the first record of every dump
+type ExportHeader struct {
+	Format      string    `json:"format"`
+	Version     int       `json:"version"`
+	ExportedAt  time.Time `json:"exported_at"`
+	IssuePrefix string    `json:"issue_prefix,omitempty"`
+}
+
+// IssueLabel is a single label attached to an issue
+type IssueLabel struct {
+	IssueID string `json:"issue_id"`
+	Label   string `json:"label"`
+}
+
+// ExportGraph is the full issue graph held by a storage backend: issues and
+// everything hanging off them. It is what Storage.Export writes and
+// Storage.Import reads.
+type ExportGraph struct {
+	Header       ExportHeader        `json:"header"`
+	Issues       []*Issue            `json:"issues"`
+	Dependencies []*Dependency       `json:"dependencies"`
+	Labels       []*IssueLabel       `json:"labels"`
+	Events       []*Event            `json:"events"`
+	Executions   []*ExecutionAttempt `json:"executions"`
+}
+
+// ImportStats reports what Storage.Import did
+type ImportStats struct {
+	Issues        int // Issues created
+	SkippedIssues int // Issues that already existed and were left untouched
+	Dependencies  int // Dependencies added
+	Labels        int // Labels added
+	Events        int // Events restored
+	Executions    int // Execution attempts restored
+}
@@ -1,5 +1,13 @@
 package types
 
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
 // Discovery label constants for issues created during analysis.
 // These labels categorize discovered work by urgency and relationship to the mission.
 const (
@@ -26,4 +34,117 @@ const (
 	// LabelDecomposed marks parent issues that have been decomposed into children (vc-rzqe).
 	// These issues act as coordinators and should auto-close when all children complete.
 	LabelDecomposed = "decomposed"
+
+	// LabelWaitingOnAI marks issues deferred while the AI provider is down.
+	// They aren't claimed until the provider recovers and the label is removed.
+	LabelWaitingOnAI = "waiting-on-ai"
 )
+
+// ErrSystemLabel is returned when renaming or deleting a reserved system label
+var ErrSystemLabel = errors.New("system labels are reserved and cannot be renamed or deleted")
+
+// SystemLabels are the labels VC itself reads or applies - the AI supervisor,
+// executor and quality gates act on them - mapped to their descriptions.
+// They can be added to and removed from issues like any other label, but
+// their definitions can't be renamed or deleted.
+var SystemLabels = map[string]string{
+	"escalated":               "Escalated to a human by the AI supervisor",
+	"escalation":              "Filed to ask a human to resolve a problem VC couldn't",
+	"needs-approval":          "Waiting for a human to approve the supervisor's decision",
+	"needs-human-review":      "Completed work that needs a human to review it",
+	"no-auto-claim":           "Never claimed automatically by an executor",
+	"baseline-failure":        "Fixes a quality gate failing on the base branch",
+	"baseline-stuck":          "Filed because baseline failures have blocked all work",
+	"system":                  "Filed by VC's own preflight checks",
+	"rebase-conflict":         "Resolves conflicts rebasing a mission branch",
+	"code-review-sweep":       "Filed by a code review sweep",
+	"quota-crisis":            "Filed because the AI quota is predicted to run out",
+	"meta-issue":              "About missing issue details rather than code",
+	"interrupted":             "Execution was interrupted and can be resumed",
+	"quality-gates-failed":    "Quality gates failed on the last attempt",
+	"generated:plan":          "Created from an approved mission plan",
+	"task-ready":              "Ready for a code worker to claim",
+	"needs-quality-gates":     "Waiting for quality gates to run",
+	"gates-running":           "Quality gates are running",
+	"gates-failed":            "Quality gates failed",
+	"needs-review":            "Waiting for review",
+	"needs-human-approval":    "Waiting for human approval",
+	"approved":                "Approved for merge",
+	LabelDiscoveredBlocker:    "Discovered work that blocks mission progress",
+	LabelDiscoveredRelated:    "Discovered work related to the mission",
+	LabelDiscoveredBackground: "Discovered work unrelated to the mission",
+	LabelDiscoveredSupervisor: "Filed by the AI supervisor",
+	LabelDiscoveredDecomposed: "Part of a decomposed task",
+	LabelDecomposed:           "Decomposed into child issues",
+	LabelWaitingOnAI:          "Deferred until the AI provider recovers",
+}
+
+// IsSystemLabel reports whether name is a reserved system label. Project
+// labels are reserved too: they change with the project, not on their own.
+func IsSystemLabel(name string) bool {
+	_, ok := SystemLabels[name]
+	return ok || strings.HasPrefix(name, ProjectLabelPrefix)
+}
+
+// IsEscalationLabel reports whether label marks an issue as needing a
+// human: "escalated" is added to issues the AI supervisor gives up on,
+// "escalation" to issues filed to ask for help
+func IsEscalationLabel(label string) bool {
+	return label == "escalated" || label == "escalation"
+}
+
+// OwnerLabelPrefix marks the owners of the code an issue's work touched,
+// from CODEOWNERS, e.g. "owner:@acme/backend"
+const OwnerLabelPrefix = "owner:"
+
+// OwnerLabel returns the label naming owner as an owner of an issue's code
+func OwnerLabel(owner string) string {
+	return OwnerLabelPrefix + owner
+}
+
+// LabelOwners returns the owners named by owner labels among labels
+func LabelOwners(labels []string) []string {
+	var owners []string
+	for _, label := range labels {
+		if owner, ok := strings.CutPrefix(label, OwnerLabelPrefix); ok && owner != "" {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+// labelColorPattern matches the #rrggbb colors label definitions use
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// LabelDefinition describes a label independently of the issues it's on
+type LabelDefinition struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Color       string    `json:"color,omitempty"` // "#rrggbb", or "" for none
+	System      bool      `json:"system"`          // Reserved; see SystemLabels
+	IssueCount  int       `json:"issue_count"`     // Issues carrying the label (filled in by list queries)
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate checks the label definition's name and color
+func (d *LabelDefinition) Validate() error {
+	if err := ValidateLabelName(d.Name); err != nil {
+		return err
+	}
+	if d.Color != "" && !labelColorPattern.MatchString(d.Color) {
+		return fmt.Errorf("invalid label color %q: must be #rrggbb", d.Color)
+	}
+	return nil
+}
+
+// ValidateLabelName checks that a label name is usable
+func ValidateLabelName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("label name is required")
+	}
+	if strings.TrimSpace(name) != name || strings.ContainsAny(name, " \t\n,") {
+		return fmt.Errorf("invalid label name %q: must not contain whitespace or commas", name)
+	}
+	return nil
+}
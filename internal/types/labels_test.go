@@ -0,0 +1,75 @@
+package types
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLabelDefinitionValidate(t *testing.T) {
+	tests := []struct {
+		def     LabelDefinition
+		wantErr bool
+	}{
+		{LabelDefinition{Name: "frontend"}, false},
+		{LabelDefinition{Name: "frontend", Color: "#1F77b4"}, false},
+		{LabelDefinition{Name: ""}, true},
+		{LabelDefinition{Name: "two words"}, true},
+		{LabelDefinition{Name: "a,b"}, true},
+		{LabelDefinition{Name: "frontend", Color: "blue"}, true},
+		{LabelDefinition{Name: "frontend", Color: "#fff"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.def.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.def, err, tt.wantErr)
+		}
+	}
+	if !IsSystemLabel("needs-approval") || !IsSystemLabel(LabelDiscoveredBlocker) || IsSystemLabel("frontend") {
+		t.Error("IsSystemLabel misclassified a label")
+	}
+}
+
+// labelLiteral matches a label name passed as a literal to the storage
+// methods that add, remove or look up labels, or to hasLabel helpers
+var labelLiteral = regexp.MustCompile(`(?:AddLabel|RemoveLabel)\([^,()]+,[^,()]+,\s*"([^"]+)"|(?:GetIssuesByLabel|[hH]asLabel)\([^,()]+(?:,[^,()]+)?,?\s*"([^"]+)"\)|label == "([^"]+)"`)
+
+// TestSystemLabelsCoverCode checks that every label VC's code applies or
+// reads by name is a system label, so it can't be renamed or deleted out
+// from under the code
+func TestSystemLabelsCoverCode(t *testing.T) {
+	root := filepath.Join("..", "..")
+	seen := map[string]string{}
+	for _, dir := range []string{"internal", "cmd"} {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, match := range labelLiteral.FindAllStringSubmatch(string(data), -1) {
+				for _, label := range match[1:] {
+					if label != "" {
+						seen[label] = path
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to scan %s: %v", dir, err)
+		}
+	}
+	if len(seen) == 0 {
+		t.Fatal("found no label literals; the pattern no longer matches the code")
+	}
+	for label, path := range seen {
+		if !IsSystemLabel(label) {
+			t.Errorf("%s uses label %q, which is missing from SystemLabels", path, label)
+		}
+	}
+}
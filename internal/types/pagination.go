@@ -0,0 +1,138 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Keyset pagination.
+//
+// Paged listings order items by a timestamp with the item's ID breaking ties,
+// and a page's NextCursor records the (timestamp, ID) of its last item. The
+// next page starts strictly after that key, so walking a large history never
+// loads more than one page, and rows inserted or deleted meanwhile don't
+// shift later pages the way OFFSET does.
+
+const (
+	// DefaultPageSize is the page size of a PageRequest without a Limit
+	DefaultPageSize = 100
+	// MaxPageSize caps PageRequest.Limit
+	MaxPageSize = 500
+)
+
+// PageKeyFormat is the layout of a cursor's timestamp: UTC with millisecond
+// precision, so keys sort as text and match what SQLite's strftime('%f')
+// produces
+const PageKeyFormat = "2006-01-02 15:04:05.000"
+
+// SortOrder is the direction of a paged listing
+type SortOrder string
+
+// Sort orders
+const (
+	SortAscending  SortOrder = "asc"  // Oldest first (default)
+	SortDescending SortOrder = "desc" // Newest first
+)
+
+// Fields paged issue listings can be ordered by
+const (
+	IssueOrderCreated = "created_at" // Default
+	IssueOrderUpdated = "updated_at"
+)
+
+// PageRequest asks for one page of a listing
+type PageRequest struct {
+	Cursor  string    // NextCursor of the previous page; "" for the first page
+	Since   time.Time // Only items at or after Since; zero for no bound
+	Limit   int       // Page size; <= 0 means DefaultPageSize, capped at MaxPageSize
+	Order   SortOrder // "" means SortAscending
+	OrderBy string    // Issues only: IssueOrderCreated ("") or IssueOrderUpdated
+}
+
+// Validate checks the request's order, ordering field and cursor
+func (p PageRequest) Validate() error {
+	if p.Order != "" && p.Order != SortAscending && p.Order != SortDescending {
+		return fmt.Errorf("invalid sort order %q: must be %s or %s", p.Order, SortAscending, SortDescending)
+	}
+	if p.OrderBy != "" && p.OrderBy != IssueOrderCreated && p.OrderBy != IssueOrderUpdated {
+		return fmt.Errorf("invalid order field %q: must be %s or %s", p.OrderBy, IssueOrderCreated, IssueOrderUpdated)
+	}
+	if _, err := p.DecodeCursor(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PageSize returns the effective page size
+func (p PageRequest) PageSize() int {
+	if p.Limit <= 0 {
+		return DefaultPageSize
+	}
+	if p.Limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return p.Limit
+}
+
+// Descending reports whether the listing runs newest first
+func (p PageRequest) Descending() bool {
+	return p.Order == SortDescending
+}
+
+// SinceKey returns Since as a cursor timestamp, or "" if unset
+func (p PageRequest) SinceKey() string {
+	if p.Since.IsZero() {
+		return ""
+	}
+	return PageKey(p.Since)
+}
+
+// DecodeCursor returns the position the request's cursor names, or nil for
+// the first page
+func (p PageRequest) DecodeCursor() (*PageCursor, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page cursor: %w", err)
+	}
+	key, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("invalid page cursor")
+	}
+	if _, err := time.Parse(PageKeyFormat, key); err != nil {
+		return nil, fmt.Errorf("invalid page cursor: %w", err)
+	}
+	return &PageCursor{Key: key, ID: id}, nil
+}
+
+// PageCursor is the sort key and ID of the last item on a page
+type PageCursor struct {
+	Key string // Timestamp in PageKeyFormat
+	ID  string
+}
+
+// Encode returns the cursor as an opaque string for PageRequest.Cursor
+func (c PageCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Key + "|" + c.ID))
+}
+
+// PageKey formats a timestamp as a cursor key
+func PageKey(t time.Time) string {
+	return t.UTC().Format(PageKeyFormat)
+}
+
+// EventPage is one page of issue events
+type EventPage struct {
+	Events     []*Event `json:"events"`
+	NextCursor string   `json:"next_cursor,omitempty"` // "" on the last page
+}
+
+// IssuePage is one page of issues
+type IssuePage struct {
+	Issues     []*Issue `json:"issues"`
+	NextCursor string   `json:"next_cursor,omitempty"` // "" on the last page
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ProjectLabelPrefix marks the label that puts an issue in a project, e.g.
+// "project:web". An issue is in at most one project; issues without a
+// project label belong to no project.
+const ProjectLabelPrefix = "project:"
+
+// ProjectLabel returns the label for membership in project
+func ProjectLabel(project string) string {
+	return ProjectLabelPrefix + project
+}
+
+// ProjectFromLabels returns the project an issue with these labels is in, or
+// "" if none
+func ProjectFromLabels(labels []string) string {
+	for _, label := range labels {
+		if strings.HasPrefix(label, ProjectLabelPrefix) {
+			return strings.TrimPrefix(label, ProjectLabelPrefix)
+		}
+	}
+	return ""
+}
+
+// projectNamePattern matches usable project names: short, lowercase and safe
+// in labels, branch names and paths
+var projectNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// Project is a namespace for issues, so one database (and one executor or
+// dashboard) can serve several repositories without mixing their backlogs.
+// Events and executions belong to the project of their issue.
+type Project struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// RepoPath is the repository the project's issues are worked in; an
+	// executor scoped to the project uses it as its parent repo and
+	// working directory unless configured otherwise
+	RepoPath string `json:"repo_path,omitempty"`
+	// DefaultBranch overrides the executor's default branch for sandboxes
+	DefaultBranch string `json:"default_branch,omitempty"`
+
+	// OpenIssues and ClosedIssues are filled in by list queries
+	OpenIssues   int `json:"open_issues"`
+	ClosedIssues int `json:"closed_issues"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the project's name
+func (p *Project) Validate() error {
+	return ValidateProjectName(p.Name)
+}
+
+// ValidateProjectName checks that a project name is usable
+func ValidateProjectName(name string) error {
+	if name == "" {
+		return fmt.Errorf("project name is required")
+	}
+	if !projectNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid project name %q: use up to 64 lowercase letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
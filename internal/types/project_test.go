@@ -0,0 +1,28 @@
+package types
+
+import "testing"
+
+func TestValidateProjectName(t *testing.T) {
+	for _, name := range []string{"web", "api-v2", "my_app.io", "0day"} {
+		if err := ValidateProjectName(name); err != nil {
+			t.Errorf("ValidateProjectName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "Web", "-web", "my app", "a/b"} {
+		if err := ValidateProjectName(name); err == nil {
+			t.Errorf("ValidateProjectName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestProjectFromLabels(t *testing.T) {
+	if got := ProjectFromLabels([]string{"frontend", ProjectLabel("web")}); got != "web" {
+		t.Errorf("ProjectFromLabels = %q, want web", got)
+	}
+	if got := ProjectFromLabels([]string{"frontend"}); got != "" {
+		t.Errorf("ProjectFromLabels = %q, want empty", got)
+	}
+	if !IsSystemLabel(ProjectLabel("web")) {
+		t.Error("expected project labels to be system labels")
+	}
+}
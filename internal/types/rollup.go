@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EpicRollup aggregates the direct children of an epic or mission (issues
+// linked to it by parent-child dependencies)
+type EpicRollup struct {
+	EpicID   string         `json:"epic_id"`
+	Children int            `json:"children"`
+	ByStatus map[Status]int `json:"by_status"`
+
+	// EstimatedMinutes sums the children's estimates; UnestimatedChildren
+	// counts children without one
+	EstimatedMinutes    int `json:"estimated_minutes"`
+	UnestimatedChildren int `json:"unestimated_children"`
+	// ActualMinutes is the wall-clock time of the children's finished
+	// execution attempts
+	ActualMinutes float64 `json:"actual_minutes"`
+
+	// OpenBlockers are the unclosed issues blocking the epic or one of its
+	// unclosed children, sorted
+	OpenBlockers []string `json:"open_blockers,omitempty"`
+
+	// GateRuns counts the children's completed quality gate runs (canceled
+	// runs excluded); GateRunsPassed those in which every gate passed
+	GateRuns       int `json:"gate_runs"`
+	GateRunsPassed int `json:"gate_runs_passed"`
+}
+
+// NewEpicRollup counts children by status and sums their estimates. Storage
+// backends fill in effort, blockers and gate runs.
+func NewEpicRollup(epicID string, children []*Issue) *EpicRollup {
+	rollup := &EpicRollup{EpicID: epicID, ByStatus: make(map[Status]int)}
+	for _, child := range children {
+		rollup.Children++
+		rollup.ByStatus[child.Status]++
+		if child.EstimatedMinutes != nil {
+			rollup.EstimatedMinutes += *child.EstimatedMinutes
+		} else {
+			rollup.UnestimatedChildren++
+		}
+	}
+	return rollup
+}
+
+// Closed returns the number of closed children
+func (r *EpicRollup) Closed() int {
+	return r.ByStatus[StatusClosed]
+}
+
+// GatePassRate returns the fraction of gate runs that passed (0 if none ran)
+func (r *EpicRollup) GatePassRate() float64 {
+	if r.GateRuns == 0 {
+		return 0
+	}
+	return float64(r.GateRunsPassed) / float64(r.GateRuns)
+}
+
+// Summary renders the rollup on one line, e.g. for prompts and the CLI
+func (r *EpicRollup) Summary() string {
+	var parts []string
+	parts = append(parts, fmt.Sprintf("%d/%d children closed", r.Closed(), r.Children))
+	for _, status := range []Status{StatusInProgress, StatusBlocked, StatusOpen} {
+		if n := r.ByStatus[status]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	parts = append(parts, fmt.Sprintf("effort %.0f/%d min", r.ActualMinutes, r.EstimatedMinutes))
+	if len(r.OpenBlockers) > 0 {
+		parts = append(parts, fmt.Sprintf("%d open blocker(s)", len(r.OpenBlockers)))
+	}
+	if r.GateRuns > 0 {
+		parts = append(parts, fmt.Sprintf("gates passed %d/%d (%.0f%%)", r.GateRunsPassed, r.GateRuns, r.GatePassRate()*100))
+	}
+	return strings.Join(parts, ", ")
+}
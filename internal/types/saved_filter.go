@@ -0,0 +1,93 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SavedFilter is a named issue query, so a combination of predicates used
+// again and again (e.g. "ready-p1": open P1 tasks) is written once and run
+// by name. Unset predicates match everything.
+type SavedFilter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Query     string    `json:"query,omitempty"` // Text in title, description or ID
+	Status    Status    `json:"status,omitempty"`
+	IssueType IssueType `json:"issue_type,omitempty"`
+	Priority  *int      `json:"priority,omitempty"`
+	Assignee  string    `json:"assignee,omitempty"`
+	Labels    []string  `json:"labels,omitempty"` // Issue must carry all of them
+	Project   string    `json:"project,omitempty"`
+	Limit     int       `json:"limit,omitempty"`
+
+	CustomFields map[string]string `json:"custom_fields,omitempty"` // Field name -> value
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the filter's name and predicates
+func (f *SavedFilter) Validate() error {
+	if strings.TrimSpace(f.Name) == "" {
+		return fmt.Errorf("filter name is required")
+	}
+	if strings.TrimSpace(f.Name) != f.Name || strings.ContainsAny(f.Name, " \t\n,") {
+		return fmt.Errorf("invalid filter name %q: must not contain whitespace or commas", f.Name)
+	}
+	if f.Status != "" && !f.Status.IsValid() {
+		return fmt.Errorf("invalid status: %s", f.Status)
+	}
+	if f.IssueType != "" && !f.IssueType.IsValid() {
+		return fmt.Errorf("invalid issue type: %s", f.IssueType)
+	}
+	if f.Priority != nil && (*f.Priority < 0 || *f.Priority > 4) {
+		return fmt.Errorf("priority must be between 0 and 4 (got %d)", *f.Priority)
+	}
+	for _, label := range f.Labels {
+		if err := ValidateLabelName(label); err != nil {
+			return err
+		}
+	}
+	if f.Project != "" {
+		if err := ValidateProjectName(f.Project); err != nil {
+			return err
+		}
+	}
+	for name := range f.CustomFields {
+		if err := ValidateCustomFieldName(name); err != nil {
+			return err
+		}
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("limit cannot be negative")
+	}
+	return nil
+}
+
+// IssueFilter returns the filter's predicates other than Query as an
+// IssueFilter for SearchIssues
+func (f *SavedFilter) IssueFilter() IssueFilter {
+	filter := IssueFilter{
+		Priority: f.Priority,
+		Labels:   f.Labels,
+		Project:  f.Project,
+		Limit:    f.Limit,
+
+		CustomFields: f.CustomFields,
+	}
+	if f.Status != "" {
+		status := f.Status
+		filter.Status = &status
+	}
+	if f.IssueType != "" {
+		issueType := f.IssueType
+		filter.IssueType = &issueType
+	}
+	if f.Assignee != "" {
+		assignee := f.Assignee
+		filter.Assignee = &assignee
+	}
+	return filter
+}
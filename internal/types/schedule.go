@@ -0,0 +1,95 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduledLabel marks every issue a schedule creates, alongside the
+// "schedule:<name>" label naming the schedule
+const ScheduledLabel = "scheduled"
+
+// ScheduleLabelPrefix marks the label naming the schedule that created an
+// issue, e.g. "schedule:nightly-deps"
+const ScheduleLabelPrefix = "schedule:"
+
+// Schedule creates an issue from a template on a cron schedule, so recurring
+// work (nightly dependency bumps, a weekly lint-debt cleanup) is filed and
+// executed like any other issue. Cron expressions are evaluated in the
+// executor's local time.
+type Schedule struct {
+	Name    string `json:"name"`
+	Cron    string `json:"cron"` // Five-field cron expression, or a macro such as @daily
+	Enabled bool   `json:"enabled"`
+
+	// AllowOverlap creates a run's issue even while the previous run's is
+	// still open; by default such a run is skipped
+	AllowOverlap bool `json:"allow_overlap,omitempty"`
+
+	// The issue each run creates
+	Title              string    `json:"title"`
+	Description        string    `json:"description,omitempty"`
+	AcceptanceCriteria string    `json:"acceptance_criteria,omitempty"`
+	IssueType          IssueType `json:"issue_type"`
+	Priority           int       `json:"priority"`
+	Labels             []string  `json:"labels,omitempty"`
+	Project            string    `json:"project,omitempty"`
+
+	NextRunAt   time.Time  `json:"next_run_at"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	LastIssueID string     `json:"last_issue_id,omitempty"` // Issue created by the last run that created one
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the schedule's name and issue template. The cron
+// expression is only checked for presence; parsing it is up to the scheduler.
+func (s *Schedule) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("schedule name is required")
+	}
+	if strings.TrimSpace(s.Name) != s.Name || strings.ContainsAny(s.Name, " \t\n,") {
+		return fmt.Errorf("invalid schedule name %q: must not contain whitespace or commas", s.Name)
+	}
+	if strings.TrimSpace(s.Cron) == "" {
+		return fmt.Errorf("cron expression is required")
+	}
+	for _, label := range s.Labels {
+		if err := ValidateLabelName(label); err != nil {
+			return err
+		}
+	}
+	if s.Project != "" {
+		if err := ValidateProjectName(s.Project); err != nil {
+			return err
+		}
+	}
+	if err := s.NewIssue().Validate(); err != nil {
+		return fmt.Errorf("invalid issue template: %w", err)
+	}
+	return nil
+}
+
+// NewIssue returns the open issue a run of the schedule creates
+func (s *Schedule) NewIssue() *Issue {
+	return &Issue{
+		Title:              s.Title,
+		Description:        s.Description,
+		AcceptanceCriteria: s.AcceptanceCriteria,
+		Status:             StatusOpen,
+		Priority:           s.Priority,
+		IssueType:          s.IssueType,
+	}
+}
+
+// IssueLabels returns the labels of an issue the schedule creates: its
+// template's, its project's, and those marking it as scheduled
+func (s *Schedule) IssueLabels() []string {
+	labels := append([]string(nil), s.Labels...)
+	if s.Project != "" {
+		labels = append(labels, ProjectLabel(s.Project))
+	}
+	return append(labels, ScheduledLabel, ScheduleLabelPrefix+s.Name)
+}
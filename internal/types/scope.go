@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ScopeLabelPrefix marks labels that confine an issue's changes to part of
+// the repository, e.g. "scope:services/payments/**". An issue may have
+// several; issues without one may change any file.
+const ScopeLabelPrefix = "scope:"
+
+// PathScope is the set of path patterns an issue's changes must stay
+// within. An empty scope contains every path.
+type PathScope []string
+
+// ScopeLabel returns the label that adds pattern to an issue's scope
+func ScopeLabel(pattern string) string {
+	return ScopeLabelPrefix + pattern
+}
+
+// ScopeFromLabels returns the path scope of an issue with these labels
+func ScopeFromLabels(labels []string) PathScope {
+	var scope PathScope
+	for _, label := range labels {
+		if pattern, ok := strings.CutPrefix(label, ScopeLabelPrefix); ok && pattern != "" {
+			scope = append(scope, pattern)
+		}
+	}
+	return scope
+}
+
+// Contains reports whether a repository-relative path is in scope
+func (s PathScope) Contains(file string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, pattern := range s {
+		if MatchPathPattern(file, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// Split separates files into those in scope and those outside it
+func (s PathScope) Split(files []string) (in, out []string) {
+	for _, file := range files {
+		if s.Contains(file) {
+			in = append(in, file)
+		} else {
+			out = append(out, file)
+		}
+	}
+	return in, out
+}
+
+// String returns the patterns, comma-separated
+func (s PathScope) String() string {
+	return strings.Join(s, ", ")
+}
+
+// MatchPathPattern reports whether a repository-relative path matches a
+// glob pattern. As in .gitignore, a pattern matches a path or any directory
+// containing it, and a pattern without a slash matches names at any depth:
+// "docs" matches "docs/guide.md", "*.md" matches "docs/guide.md", and
+// "cmd/*" and "cmd/**" match "cmd/vc/main.go".
+func MatchPathPattern(file, pattern string) bool {
+	pattern = strings.TrimSuffix(strings.TrimSuffix(pattern, "/"), "/**")
+	anyDepth := !strings.Contains(pattern, "/")
+	for p := strings.TrimSuffix(file, "/"); p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+		if anyDepth {
+			if ok, _ := path.Match(pattern, path.Base(p)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidatePathPattern checks that a path pattern is a valid glob
+func ValidatePathPattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("path pattern cannot be empty")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchPathPattern(t *testing.T) {
+	tests := []struct {
+		file    string
+		pattern string
+		want    bool
+	}{
+		{"main.go", "*.go", true},
+		{"internal/executor/executor.go", "*.go", true},
+		{"internal/executor/executor.go", "internal", true},
+		{"internal/executor/executor.go", "internal/", true},
+		{"internal/executor/executor.go", "internal/*", true},
+		{"internal/executor/executor.go", "internal/**", true},
+		{"internal/executor/executor.go", "executor", true},
+		{"internal/executor/executor.go", "cmd", false},
+		{"internal/executor/executor.go", "*.md", false},
+		{"docs/guide.md", "docs/*.md", true},
+		{"docs/api/guide.md", "api/*.md", false}, // Patterns with a slash are anchored
+		{"newdir/", "newdir", true},              // Untracked directories are reported with a trailing slash
+		{"services/payments/api/handler.go", "services/payments/**", true},
+		{"services/billing/api/handler.go", "services/payments/**", false},
+		{"services/payments-v2/main.go", "services/payments/**", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPathPattern(tt.file, tt.pattern); got != tt.want {
+			t.Errorf("MatchPathPattern(%q, %q) = %v, want %v", tt.file, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePathPattern(t *testing.T) {
+	if err := ValidatePathPattern("internal/*.go"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidatePathPattern("["); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+	if err := ValidatePathPattern(" "); err == nil {
+		t.Error("expected an error for an empty pattern")
+	}
+}
+
+func TestPathScope(t *testing.T) {
+	scope := ScopeFromLabels([]string{"backend", ScopeLabel("services/payments/**"), "scope:", ScopeLabel("go.mod")})
+	if !reflect.DeepEqual(scope, PathScope{"services/payments/**", "go.mod"}) {
+		t.Fatalf("ScopeFromLabels() = %v", scope)
+	}
+
+	in, out := scope.Split([]string{"services/payments/api.go", "go.mod", "services/billing/api.go"})
+	if !reflect.DeepEqual(in, []string{"services/payments/api.go", "go.mod"}) || !reflect.DeepEqual(out, []string{"services/billing/api.go"}) {
+		t.Errorf("Split() = %v, %v", in, out)
+	}
+
+	if !PathScope(nil).Contains("anything/at/all.go") {
+		t.Error("an empty scope should contain every path")
+	}
+}
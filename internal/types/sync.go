@@ -0,0 +1,74 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SyncActor is the actor recorded on every change a sync makes. Events by
+// this actor are not copied between databases, so syncing doesn't echo its
+// own bookkeeping back and forth.
+const SyncActor = "vc-sync"
+
+// SyncSide names one of the two databases in a sync
+type SyncSide string
+
+const (
+	SyncLocal  SyncSide = "local"
+	SyncRemote SyncSide = "remote"
+)
+
+// SyncConflict is a field both databases changed since the last sync. The
+// value of Winner is kept on both sides.
+type SyncConflict struct {
+	IssueID string   `json:"issue_id"`
+	Field   string   `json:"field"`
+	Local   string   `json:"local"`
+	Remote  string   `json:"remote"`
+	Winner  SyncSide `json:"winner"`
+}
+
+// SyncSideStats counts the changes a sync made to one database
+type SyncSideStats struct {
+	IssuesCreated       int // Issues copied from the other database
+	FieldsUpdated       int // Issue fields set to the merged value
+	LabelsAdded         int
+	LabelsRemoved       int
+	DependenciesAdded   int
+	DependenciesRemoved int
+	EventsCopied        int // Events copied from the other database
+}
+
+// Changed reports whether the sync changed anything on this side
+func (s SyncSideStats) Changed() bool {
+	return s != SyncSideStats{}
+}
+
+// SyncStats reports what a sync did to each database
+type SyncStats struct {
+	Local     SyncSideStats
+	Remote    SyncSideStats
+	Conflicts []SyncConflict
+	Skipped   []string // Changes that could not be applied, such as a dependency that would close a cycle
+}
+
+// SyncKey identifies an event across databases, where its ID differs. Two
+// events with the same key are the same event.
+func (e *Event) SyncKey() string {
+	value := func(s *string) string {
+		if s == nil {
+			return "\x00"
+		}
+		return *s
+	}
+	return strings.Join([]string{
+		e.IssueID,
+		string(e.EventType),
+		e.Actor,
+		fmt.Sprint(e.CreatedAt.UTC().Truncate(time.Second).Unix()),
+		value(e.OldValue),
+		value(e.NewValue),
+		value(e.Comment),
+	}, "\x1f")
+}
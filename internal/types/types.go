@@ -178,6 +178,51 @@ type Event struct {
 	CreatedAt time.Time  `json:"created_at"`
 }
 
+// StatusChange returns the statuses before and after a status_changed,
+// closed or reopened event. Either is "" when the event didn't record it.
+func (e *Event) StatusChange() (oldStatus, newStatus string) {
+	oldStatus, newStatus = eventStatus(e.OldValue), eventStatus(e.NewValue)
+	if newStatus == "" && e.EventType == EventClosed {
+		newStatus = string(StatusClosed)
+	}
+	return oldStatus, newStatus
+}
+
+// eventStatus extracts the status from an event's old or new value: a JSON
+// object with a "status" field (an issue, or the updates applied to one) or
+// a bare status
+func eventStatus(value *string) string {
+	if value == nil || *value == "" {
+		return ""
+	}
+	var fields struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(*value), &fields); err == nil {
+		return fields.Status
+	}
+	if Status(*value).IsValid() {
+		return *value
+	}
+	return ""
+}
+
+// AddedLabel returns the label a label_added event added, or ""
+func (e *Event) AddedLabel() string {
+	if e.EventType != EventLabelAdded {
+		return ""
+	}
+	if e.NewValue != nil && *e.NewValue != "" {
+		return *e.NewValue
+	}
+	if e.Comment != nil {
+		if label, ok := strings.CutPrefix(*e.Comment, "Added label: "); ok {
+			return label
+		}
+	}
+	return ""
+}
+
 // EventCounts holds event count statistics for monitoring
 type EventCounts struct {
 	TotalEvents      int
@@ -236,7 +281,12 @@ type IssueFilter struct {
 	Type      *IssueType // Alias for IssueType (for compatibility)
 	Assignee  *string
 	Labels    []string
+	Project   string // Only issues in this project
 	Limit     int
+
+	// CustomFields matches issues whose custom fields have these values
+	// (field name -> value), all of them
+	CustomFields map[string]string
 }
 
 // WorkFilter is used to filter ready work queries
@@ -260,6 +310,7 @@ type WorkFilter struct {
 	Status     Status
 	Priority   *int
 	Assignee   *string
+	Project    string // Only issues in this project
 	Limit      int
 	SortPolicy SortPolicy
 }
@@ -406,8 +457,13 @@ type IssueExecutionState struct {
 	ErrorMessage          string         `json:"error_message,omitempty"`
 	InterventionCount     int            `json:"intervention_count"`              // vc-165b: Count of watchdog interventions
 	LastInterventionTime  *time.Time     `json:"last_intervention_time,omitempty"` // vc-165b: When last intervention occurred
+	LeaseExpiresAt        *time.Time     `json:"lease_expires_at,omitempty"`       // Claim lease from ClaimNextReadyIssue (nil = no lease)
 }
 
+// DefaultClaimLease is how long a ClaimNextReadyIssue claim lasts unless renewed.
+// Once a lease expires, another worker may take the issue over.
+const DefaultClaimLease = 5 * time.Minute
+
 // Validate checks if the issue execution state has valid field values
 func (s *IssueExecutionState) Validate() error {
 	if s.IssueID == "" {
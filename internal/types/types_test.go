@@ -342,6 +342,53 @@ func TestAcceptanceCriteriaRequiredTypes(t *testing.T) {
 	}
 }
 
+// TestEventStatusChangeAndAddedLabel tests reading statuses and labels from
+// audit events as the storage backends record them
+func TestEventStatusChangeAndAddedLabel(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name      string
+		event     Event
+		wantOld   string
+		wantNew   string
+		wantLabel string
+	}{
+		{
+			name:    "status change with issue and updates JSON",
+			event:   Event{EventType: EventStatusChanged, OldValue: str(`{"id":"vc-1","status":"open"}`), NewValue: str(`{"status":"blocked"}`)},
+			wantOld: "open",
+			wantNew: "blocked",
+		},
+		{
+			name:    "close without a new value",
+			event:   Event{EventType: EventClosed, OldValue: str("in_progress"), Comment: str("done")},
+			wantOld: "in_progress",
+			wantNew: "closed",
+		},
+		{
+			name:      "label added recorded in the comment",
+			event:     Event{EventType: EventLabelAdded, Comment: str("Added label: escalated")},
+			wantLabel: "escalated",
+		},
+		{
+			name:  "label removed",
+			event: Event{EventType: EventLabelRemoved, NewValue: str("escalated")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStatus, newStatus := tt.event.StatusChange()
+			if tt.event.EventType != EventLabelAdded && tt.event.EventType != EventLabelRemoved &&
+				(oldStatus != tt.wantOld || newStatus != tt.wantNew) {
+				t.Errorf("StatusChange() = %q, %q, want %q, %q", oldStatus, newStatus, tt.wantOld, tt.wantNew)
+			}
+			if label := tt.event.AddedLabel(); label != tt.wantLabel {
+				t.Errorf("AddedLabel() = %q, want %q", label, tt.wantLabel)
+			}
+		})
+	}
+}
+
 // contains is a helper to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 || indexOfSubstring(s, substr) >= 0)
@@ -0,0 +1,79 @@
+package types
+
+import "time"
+
+// ChangeKind categorizes what a ChangeEvent is about
+type ChangeKind string
+
+const (
+	// ChangeIssue is an issue lifecycle change (anything recorded in the
+	// issue's audit trail: create, update, status change, comment, ...)
+	ChangeIssue ChangeKind = "issue"
+	// ChangeExecution is a new execution attempt recorded for an issue
+	ChangeExecution ChangeKind = "execution"
+)
+
+// ChangeEvent is a single change delivered by Storage.Watch
+type ChangeEvent struct {
+	Kind      ChangeKind `json:"kind"`
+	IssueID   string     `json:"issue_id"`
+	EventType EventType  `json:"event_type,omitempty"` // ChangeIssue only
+	Actor     string     `json:"actor,omitempty"`      // ChangeIssue only
+	// EventID is the audit event ID for ChangeIssue, or the execution
+	// attempt ID for ChangeExecution
+	EventID   int64     `json:"event_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WatchFilter selects which changes Storage.Watch delivers. The zero value
+// matches everything.
+type WatchFilter struct {
+	IssueIDs   []string     // Only these issues (empty = all)
+	Kinds      []ChangeKind // Only these kinds (empty = all)
+	EventTypes []EventType  // Only these issue event types (empty = all); ignored for executions
+
+	// PollInterval is how often backends without native notification check
+	// for changes (0 = DefaultWatchPollInterval)
+	PollInterval time.Duration
+}
+
+// DefaultWatchPollInterval is the poll interval used when a WatchFilter
+// doesn't set one
+const DefaultWatchPollInterval = time.Second
+
+// Matches reports whether the filter selects the event
+func (f WatchFilter) Matches(e *ChangeEvent) bool {
+	if len(f.IssueIDs) > 0 && !containsString(f.IssueIDs, e.IssueID) {
+		return false
+	}
+	if len(f.Kinds) > 0 {
+		found := false
+		for _, k := range f.Kinds {
+			if k == e.Kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.EventTypes) > 0 && e.Kind == ChangeIssue {
+		for _, t := range f.EventTypes {
+			if t == e.EventType {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package types
+
+import "testing"
+
+func TestWatchFilterMatches(t *testing.T) {
+	comment := &ChangeEvent{Kind: ChangeIssue, IssueID: "vc-1", EventType: EventCommented}
+	execution := &ChangeEvent{Kind: ChangeExecution, IssueID: "vc-2"}
+
+	tests := []struct {
+		name   string
+		filter WatchFilter
+		event  *ChangeEvent
+		want   bool
+	}{
+		{"zero filter matches all", WatchFilter{}, comment, true},
+		{"issue match", WatchFilter{IssueIDs: []string{"vc-1"}}, comment, true},
+		{"issue mismatch", WatchFilter{IssueIDs: []string{"vc-1"}}, execution, false},
+		{"kind match", WatchFilter{Kinds: []ChangeKind{ChangeExecution}}, execution, true},
+		{"kind mismatch", WatchFilter{Kinds: []ChangeKind{ChangeExecution}}, comment, false},
+		{"event type match", WatchFilter{EventTypes: []EventType{EventCommented}}, comment, true},
+		{"event type mismatch", WatchFilter{EventTypes: []EventType{EventClosed}}, comment, false},
+		{"event types ignored for executions", WatchFilter{EventTypes: []EventType{EventClosed}}, execution, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
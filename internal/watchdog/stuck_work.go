@@ -0,0 +1,463 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// StuckWorkStore is the storage the stuck work watchdog reads claims and
+// activity from and records its actions to
+type StuckWorkStore interface {
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error)
+	GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error)
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+	UpdateExecution(ctx context.Context, execution *types.Execution) error
+	ListExecutionOutput(ctx context.Context, filter types.OutputFilter) ([]*types.OutputLine, error)
+	GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error)
+	StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+	AddLabel(ctx context.Context, issueID, label, actor string) error
+	ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error
+}
+
+// AICaller makes a one-off AI call (implemented by ai.Supervisor)
+type AICaller interface {
+	CallAI(ctx context.Context, prompt string, operation string, model string, maxTokens int) (string, error)
+}
+
+// AgentKiller stops the agent working on an issue, returning false if no
+// agent for the issue runs in this process (implemented by executor.Pool)
+type AgentKiller interface {
+	KillAgent(issueID, reason string) bool
+}
+
+// StuckKind is why the watchdog considers an issue stuck
+type StuckKind string
+
+const (
+	// StuckLongInProgress means the issue has been claimed for longer than expected
+	StuckLongInProgress StuckKind = "long_in_progress"
+	// StuckIdleExecution means the running execution stopped producing events and output
+	StuckIdleExecution StuckKind = "idle_execution"
+	// StuckDeadWorker means the executor holding the claim stopped heartbeating
+	StuckDeadWorker StuckKind = "dead_worker"
+)
+
+// StuckAction is what the watchdog does about stuck work
+type StuckAction string
+
+const (
+	// StuckNudge leaves a comment asking the agent or a human to move the work along
+	StuckNudge StuckAction = "nudge"
+	// StuckKill stops the agent (or releases a dead worker's claim) and reopens the issue
+	StuckKill StuckAction = "kill"
+	// StuckEscalate hands the issue to a human
+	StuckEscalate StuckAction = "escalate"
+)
+
+// stuckWorkActor is the actor recorded on the watchdog's comments and labels
+const stuckWorkActor = "watchdog"
+
+// StuckWork is an issue the watchdog found stuck
+type StuckWork struct {
+	Issue        *types.Issue
+	Kind         StuckKind
+	WorkerID     string // Executor instance holding the claim
+	ClaimedAt    time.Time
+	Execution    *types.Execution // Running execution, if any
+	LastActivity time.Time        // Latest event or output line (zero without a running execution)
+	Detail       string           // Human-readable explanation of the finding
+}
+
+// StuckDecision is the AI supervisor's verdict on stuck work
+type StuckDecision struct {
+	Action    StuckAction `json:"action"`
+	Reasoning string      `json:"reasoning"`
+	Message   string      `json:"message"` // Comment left on the issue
+}
+
+// StuckWorkWatchdog periodically looks for issues stuck in progress, running
+// executions that went quiet and claims held by dead workers, asks the AI
+// supervisor whether to nudge, kill or escalate each, and records what it did
+// as a comment and a watchdog_alert agent event
+type StuckWorkWatchdog struct {
+	cfg    config.StuckWorkConfig
+	store  StuckWorkStore
+	ai     AICaller    // nil = rule-based decisions
+	killer AgentKiller // nil = only dead workers' claims can be released
+
+	// heartbeatTimeout is how long an executor can go without a heartbeat
+	// before its claims count as held by a dead worker
+	heartbeatTimeout time.Duration
+
+	mu       sync.Mutex
+	lastActs map[string]time.Time // Issue ID -> when the watchdog last acted on it
+	history  map[string][]string  // Issue ID -> what the watchdog did so far
+}
+
+// NewStuckWorkWatchdog creates a stuck work watchdog. caller and killer are
+// optional: without caller, dead workers are killed and everything else is
+// nudged; without killer, live agents can't be stopped and kills on them
+// become escalations.
+func NewStuckWorkWatchdog(cfg config.StuckWorkConfig, store StuckWorkStore, caller AICaller, killer AgentKiller) (*StuckWorkWatchdog, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	return &StuckWorkWatchdog{
+		cfg:              cfg,
+		store:            store,
+		ai:               caller,
+		killer:           killer,
+		heartbeatTimeout: 5 * time.Minute, // Matches the executor's default stale threshold
+		lastActs:         make(map[string]time.Time),
+		history:          make(map[string][]string),
+	}, nil
+}
+
+// Run checks for stuck work every check interval until ctx is canceled
+func (w *StuckWorkWatchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.CheckInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Check(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("watchdog: failed to check for stuck work", "error", err)
+			}
+		}
+	}
+}
+
+// Check scans for stuck work and acts on each finding. Failing to act on
+// one issue is logged and doesn't stop the others.
+func (w *StuckWorkWatchdog) Check(ctx context.Context) error {
+	stuck, err := w.Scan(ctx)
+	if err != nil {
+		return err
+	}
+	for _, work := range stuck {
+		decision := w.decide(ctx, work)
+		if err := w.act(ctx, work, decision); err != nil {
+			slog.Warn("watchdog: failed to act on stuck work", "issue", work.Issue.ID, "kind", work.Kind,
+				"action", decision.Action, "error", err)
+		}
+	}
+	return nil
+}
+
+// Scan returns the in-progress issues that are stuck, skipping those the
+// watchdog acted on within the last idle period
+func (w *StuckWorkWatchdog) Scan(ctx context.Context) ([]StuckWork, error) {
+	inProgress := types.StatusInProgress
+	issues, err := w.store.SearchIssues(ctx, "", types.IssueFilter{Status: &inProgress})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list in-progress issues: %w", err)
+	}
+	instances, err := w.store.GetActiveInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executor instances: %w", err)
+	}
+	heartbeats := make(map[string]time.Time, len(instances))
+	for _, instance := range instances {
+		heartbeats[instance.InstanceID] = instance.LastHeartbeat
+	}
+
+	now := time.Now()
+	var stuck []StuckWork
+	for _, issue := range issues {
+		if w.coolingDown(issue.ID, now) {
+			continue
+		}
+		state, err := w.store.GetExecutionState(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get execution state for %s: %w", issue.ID, err)
+		}
+		if state == nil || state.ExecutorInstanceID == "" {
+			continue // In progress by hand, not claimed by an executor
+		}
+		work := StuckWork{Issue: issue, WorkerID: state.ExecutorInstanceID, ClaimedAt: state.ClaimedAt}
+
+		heartbeat, alive := heartbeats[state.ExecutorInstanceID]
+		if !alive {
+			work.Kind = StuckDeadWorker
+			work.Detail = fmt.Sprintf("executor %s holds the claim but is no longer running", state.ExecutorInstanceID)
+		} else if silent := now.Sub(heartbeat); silent > w.heartbeatTimeout {
+			work.Kind = StuckDeadWorker
+			work.Detail = fmt.Sprintf("executor %s holds the claim but hasn't heartbeated for %v",
+				state.ExecutorInstanceID, silent.Round(time.Second))
+		}
+
+		executions, err := w.store.ListExecutions(ctx, types.ExecutionFilter{IssueID: issue.ID, Status: types.ExecutionRunning, Limit: 1})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list executions for %s: %w", issue.ID, err)
+		}
+		if len(executions) > 0 {
+			work.Execution = executions[0]
+			if work.LastActivity, err = w.lastActivity(ctx, executions[0]); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case work.Kind == StuckDeadWorker:
+		case work.Execution != nil && now.Sub(work.LastActivity) > w.cfg.Idle():
+			work.Kind = StuckIdleExecution
+			work.Detail = fmt.Sprintf("execution #%d has had no events or output for %v",
+				work.Execution.ID, now.Sub(work.LastActivity).Round(time.Second))
+		case now.Sub(state.ClaimedAt) > w.cfg.MaxInProgress():
+			work.Kind = StuckLongInProgress
+			work.Detail = fmt.Sprintf("claimed by %s %v ago, longer than the expected %v",
+				state.ExecutorInstanceID, now.Sub(state.ClaimedAt).Round(time.Second), w.cfg.MaxInProgress())
+		default:
+			continue
+		}
+		stuck = append(stuck, work)
+	}
+	return stuck, nil
+}
+
+// lastActivity returns when execution last showed signs of life: its latest
+// agent event (other than the watchdog's own) or output line, or its start
+func (w *StuckWorkWatchdog) lastActivity(ctx context.Context, execution *types.Execution) (time.Time, error) {
+	last := execution.StartedAt
+
+	recent, err := w.store.GetAgentEvents(ctx, events.EventFilter{IssueID: execution.IssueID, AfterTime: execution.StartedAt, Limit: 20})
+	if err != nil {
+		return last, fmt.Errorf("failed to get events for %s: %w", execution.IssueID, err)
+	}
+	for _, event := range recent {
+		if event.Type != events.EventTypeWatchdog && event.Timestamp.After(last) {
+			last = event.Timestamp
+			break // Newest first
+		}
+	}
+
+	output, err := w.store.ListExecutionOutput(ctx, types.OutputFilter{ExecutionID: execution.ID, Limit: 1})
+	if err != nil {
+		return last, fmt.Errorf("failed to get output of execution #%d: %w", execution.ID, err)
+	}
+	for _, line := range output {
+		if line.Timestamp.After(last) {
+			last = line.Timestamp
+		}
+	}
+	return last, nil
+}
+
+// coolingDown reports whether the watchdog acted on issueID within the last
+// idle period, giving its nudge or the agent time to take effect
+func (w *StuckWorkWatchdog) coolingDown(issueID string, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	last, ok := w.lastActs[issueID]
+	return ok && now.Sub(last) < w.cfg.Idle()
+}
+
+// decide asks the AI supervisor what to do about work, falling back to the
+// rule-based default when there's no supervisor or the call fails
+func (w *StuckWorkWatchdog) decide(ctx context.Context, work StuckWork) StuckDecision {
+	fallback := defaultStuckDecision(work)
+	if w.ai == nil {
+		return fallback
+	}
+
+	response, err := w.ai.CallAI(ctx, w.buildStuckWorkPrompt(work), "stuck-work-decision", "", 1024)
+	if err != nil {
+		slog.Warn("watchdog: AI stuck work decision failed, using default", "issue", work.Issue.ID, "error", err)
+		return fallback
+	}
+	parseResult := ai.Parse[StuckDecision](response, ai.ParseOptions{
+		Context:   "stuck work decision response",
+		LogErrors: ai.BoolPtr(true),
+	})
+	if !parseResult.Success {
+		return fallback
+	}
+	decision := parseResult.Data
+	switch decision.Action {
+	case StuckNudge, StuckKill, StuckEscalate:
+	default:
+		slog.Warn("watchdog: AI chose an unknown action, using default", "issue", work.Issue.ID, "action", decision.Action)
+		return fallback
+	}
+	return decision
+}
+
+// defaultStuckDecision kills dead workers' claims, since nobody is left to
+// nudge, and nudges everything else
+func defaultStuckDecision(work StuckWork) StuckDecision {
+	if work.Kind == StuckDeadWorker {
+		return StuckDecision{Action: StuckKill, Reasoning: "The executor holding the claim is gone, so the issue can't make progress until it is reopened."}
+	}
+	return StuckDecision{
+		Action:    StuckNudge,
+		Reasoning: "First sign of stuck work; giving it another chance before stopping it.",
+		Message:   "This issue looks stuck. If it's waiting on something, say what; otherwise wrap up or split the remaining work.",
+	}
+}
+
+// buildStuckWorkPrompt describes the stuck work and what the watchdog already
+// did about it, asking for a nudge, kill or escalate decision
+func (w *StuckWorkWatchdog) buildStuckWorkPrompt(work StuckWork) string {
+	var b strings.Builder
+	b.WriteString("You are the watchdog of an autonomous coding system. An issue looks stuck and you must decide what to do.\n\n")
+	fmt.Fprintf(&b, "Issue: %s - %s\n", work.Issue.ID, work.Issue.Title)
+	fmt.Fprintf(&b, "Type: %s, Priority: P%d\n", work.Issue.IssueType, work.Issue.Priority)
+	if work.Issue.Description != "" {
+		fmt.Fprintf(&b, "Description:\n%s\n", truncateForPrompt(work.Issue.Description, 1000))
+	}
+	fmt.Fprintf(&b, "\nFinding (%s): %s\n", work.Kind, work.Detail)
+	fmt.Fprintf(&b, "Claimed at: %s\n", work.ClaimedAt.Format(time.RFC3339))
+	if work.Execution != nil {
+		fmt.Fprintf(&b, "Running execution #%d started at %s, last activity at %s\n",
+			work.Execution.ID, work.Execution.StartedAt.Format(time.RFC3339), work.LastActivity.Format(time.RFC3339))
+	}
+
+	w.mu.Lock()
+	history := w.history[work.Issue.ID]
+	w.mu.Unlock()
+	if len(history) > 0 {
+		b.WriteString("\nWhat the watchdog already did about this issue:\n")
+		for _, entry := range history {
+			fmt.Fprintf(&b, "- %s\n", entry)
+		}
+	}
+
+	b.WriteString(`
+Actions:
+- "nudge": leave a comment asking for the work to move along. Right for a first sign of trouble.
+- "kill": stop the agent and reopen the issue so it is retried. Right when the agent is hung or the worker is dead.
+- "escalate": hand the issue to a human. Right when nudges and kills haven't helped or the work needs judgment.
+
+Respond with ONLY a JSON object:
+{"action": "nudge|kill|escalate", "reasoning": "why, in one or two sentences", "message": "comment to leave on the issue"}
+`)
+	return b.String()
+}
+
+// truncateForPrompt shortens s to at most max bytes without splitting a rune
+func truncateForPrompt(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max] + "..."
+}
+
+// act carries out decision on work and records what happened
+func (w *StuckWorkWatchdog) act(ctx context.Context, work StuckWork, decision StuckDecision) error {
+	action, outcome := decision.Action, ""
+	if action == StuckKill {
+		var err error
+		if outcome, action, err = w.kill(ctx, work); err != nil {
+			return err
+		}
+	}
+	if action == StuckEscalate {
+		if err := w.store.AddLabel(ctx, work.Issue.ID, "escalated", stuckWorkActor); err != nil {
+			return fmt.Errorf("failed to escalate %s: %w", work.Issue.ID, err)
+		}
+		if outcome == "" {
+			outcome = "Escalated to a human."
+		}
+	}
+	if outcome == "" {
+		outcome = "Nudged."
+	}
+
+	comment := fmt.Sprintf("Watchdog: %s. %s\nReason: %s", work.Detail, outcome, decision.Reasoning)
+	if decision.Message != "" {
+		comment += "\n\n" + decision.Message
+	}
+	if err := w.store.AddComment(ctx, work.Issue.ID, stuckWorkActor, comment); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", work.Issue.ID, err)
+	}
+	w.record(ctx, work, action, decision.Reasoning)
+	return nil
+}
+
+// kill stops work's agent, or releases the claim of a dead worker. It
+// returns the outcome and the action actually taken: kills that aren't
+// allowed or can't reach the agent become escalations.
+func (w *StuckWorkWatchdog) kill(ctx context.Context, work StuckWork) (string, StuckAction, error) {
+	if !w.cfg.AllowKill {
+		return "Escalated instead of killing (VC_STUCK_WORK_ALLOW_KILL is off).", StuckEscalate, nil
+	}
+	reason := fmt.Sprintf("watchdog: %s", work.Detail)
+	if w.killer != nil && w.killer.KillAgent(work.Issue.ID, reason) {
+		return "Stopped the agent; the executor will reopen the issue.", StuckKill, nil
+	}
+	if work.Kind != StuckDeadWorker {
+		return fmt.Sprintf("Escalated instead of killing: the agent runs on executor %s, which this watchdog can't stop.", work.WorkerID),
+			StuckEscalate, nil
+	}
+
+	if work.Execution != nil {
+		completed := time.Now()
+		execution := *work.Execution
+		execution.Status = types.ExecutionFailed
+		execution.Error = reason
+		execution.CompletedAt = &completed
+		if err := w.store.UpdateExecution(ctx, &execution); err != nil {
+			return "", StuckKill, fmt.Errorf("failed to fail execution #%d: %w", execution.ID, err)
+		}
+	}
+	if err := w.store.ReleaseIssueAndReopen(ctx, work.Issue.ID, stuckWorkActor, reason); err != nil {
+		return "", StuckKill, fmt.Errorf("failed to release %s: %w", work.Issue.ID, err)
+	}
+	return fmt.Sprintf("Released the claim of executor %s and reopened the issue.", work.WorkerID), StuckKill, nil
+}
+
+// record remembers the action for the cooldown and later prompts, and stores
+// it as a watchdog_alert agent event
+func (w *StuckWorkWatchdog) record(ctx context.Context, work StuckWork, action StuckAction, reasoning string) {
+	now := time.Now()
+	w.mu.Lock()
+	w.lastActs[work.Issue.ID] = now
+	w.history[work.Issue.ID] = append(w.history[work.Issue.ID],
+		fmt.Sprintf("%s: %s (%s)", now.Format(time.RFC3339), action, work.Kind))
+	w.mu.Unlock()
+
+	severity := events.SeverityWarning
+	if action != StuckNudge {
+		severity = events.SeverityError
+	}
+	event := &events.AgentEvent{
+		ID:         fmt.Sprintf("watchdog-stuck-%s-%d", work.Issue.ID, now.UnixNano()),
+		Type:       events.EventTypeWatchdog,
+		Timestamp:  now,
+		IssueID:    work.Issue.ID,
+		ExecutorID: work.WorkerID,
+		AgentID:    "stuck-work-watchdog",
+		Severity:   severity,
+		Message:    fmt.Sprintf("Stuck work (%s): %s", work.Kind, action),
+		Data: map[string]interface{}{
+			"kind":      string(work.Kind),
+			"action":    string(action),
+			"reasoning": reasoning,
+			"detail":    work.Detail,
+		},
+	}
+	if err := w.store.StoreAgentEvent(ctx, event); err != nil {
+		slog.Warn("watchdog: failed to record stuck work action", "issue", work.Issue.ID, "error", err)
+	}
+}
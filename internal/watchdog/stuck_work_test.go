@@ -0,0 +1,199 @@
+package watchdog
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+type fakeAICaller struct {
+	response string
+	prompts  []string
+}
+
+func (f *fakeAICaller) CallAI(ctx context.Context, prompt string, operation string, model string, maxTokens int) (string, error) {
+	f.prompts = append(f.prompts, prompt)
+	return f.response, nil
+}
+
+type fakeKiller struct {
+	local  bool
+	killed []string
+}
+
+func (f *fakeKiller) KillAgent(issueID, reason string) bool {
+	if !f.local {
+		return false
+	}
+	f.killed = append(f.killed, issueID)
+	return true
+}
+
+// claimedIssue creates an issue claimed by instanceID, registering the
+// instance as running when alive
+func claimedIssue(t *testing.T, store *memory.Store, instanceID string, alive bool) *types.Issue {
+	t.Helper()
+	ctx := context.Background()
+	if alive {
+		instance := &types.ExecutorInstance{InstanceID: instanceID, Hostname: "build-01", PID: os.Getpid(),
+			Status: types.ExecutorStatusRunning, StartedAt: time.Now(), LastHeartbeat: time.Now(), Version: "test"}
+		if err := store.RegisterInstance(ctx, instance); err != nil {
+			t.Fatalf("RegisterInstance() error = %v", err)
+		}
+	}
+	issue := &types.Issue{Title: "Refactor parser", Description: "Split the parser", AcceptanceCriteria: "Tests pass",
+		Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, instanceID); err != nil {
+		t.Fatalf("ClaimIssue() error = %v", err)
+	}
+	return issue
+}
+
+func runningExecution(t *testing.T, store *memory.Store, issueID string, startedAt time.Time) *types.Execution {
+	t.Helper()
+	execution := &types.Execution{IssueID: issueID, AgentProvider: "claude-code", Status: types.ExecutionRunning, StartedAt: startedAt}
+	if err := store.CreateExecution(context.Background(), execution); err != nil {
+		t.Fatalf("CreateExecution() error = %v", err)
+	}
+	return execution
+}
+
+func newStuckWorkWatchdog(t *testing.T, store *memory.Store, caller AICaller, killer AgentKiller) *StuckWorkWatchdog {
+	t.Helper()
+	w, err := NewStuckWorkWatchdog(config.DefaultStuckWorkConfig(), store, caller, killer)
+	if err != nil {
+		t.Fatalf("NewStuckWorkWatchdog() error = %v", err)
+	}
+	return w
+}
+
+func watchdogEvents(t *testing.T, store *memory.Store, issueID string) []*events.AgentEvent {
+	t.Helper()
+	found, err := store.GetAgentEvents(context.Background(), events.EventFilter{IssueID: issueID, Type: events.EventTypeWatchdog})
+	if err != nil {
+		t.Fatalf("GetAgentEvents() error = %v", err)
+	}
+	return found
+}
+
+func TestStuckWorkReleasesDeadWorkerClaims(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := claimedIssue(t, store, "crashed-executor", false)
+	execution := runningExecution(t, store, issue.ID, time.Now())
+	healthy := claimedIssue(t, store, "live-executor", true)
+	w := newStuckWorkWatchdog(t, store, nil, &fakeKiller{})
+
+	if err := w.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+	if got.Status != types.StatusOpen {
+		t.Errorf("status = %s, want the dead worker's issue reopened", got.Status)
+	}
+	failed, err := store.GetExecution(ctx, execution.ID)
+	if err != nil {
+		t.Fatalf("GetExecution() error = %v", err)
+	}
+	if failed.Status != types.ExecutionFailed || !strings.Contains(failed.Error, "crashed-executor") {
+		t.Errorf("execution = %s %q, want failed naming the dead executor", failed.Status, failed.Error)
+	}
+	recorded := watchdogEvents(t, store, issue.ID)
+	if len(recorded) != 1 || recorded[0].Data["action"] != "kill" || recorded[0].Data["kind"] != "dead_worker" {
+		t.Errorf("watchdog events = %+v", recorded)
+	}
+	if len(watchdogEvents(t, store, healthy.ID)) != 0 {
+		t.Error("acted on an issue held by a live executor")
+	}
+}
+
+func TestStuckWorkKillsIdleAgentsAndCoolsDown(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := claimedIssue(t, store, "live-executor", true)
+	runningExecution(t, store, issue.ID, time.Now().Add(-2*time.Hour))
+	caller := &fakeAICaller{response: `{"action": "kill", "reasoning": "No output for two hours", "message": "Retrying from scratch"}`}
+	killer := &fakeKiller{local: true}
+	w := newStuckWorkWatchdog(t, store, caller, killer)
+
+	for i := 0; i < 2; i++ {
+		if err := w.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+	}
+
+	if len(killer.killed) != 1 || killer.killed[0] != issue.ID {
+		t.Errorf("killed = %v, want %s killed once", killer.killed, issue.ID)
+	}
+	if len(caller.prompts) != 1 || !strings.Contains(caller.prompts[0], "idle_execution") {
+		t.Errorf("prompts = %q", caller.prompts)
+	}
+	comments, err := store.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments() error = %v", err)
+	}
+	if len(comments) != 1 || comments[0].Author != "watchdog" || !strings.Contains(comments[0].Body, "Retrying from scratch") {
+		t.Errorf("comments = %+v", comments)
+	}
+}
+
+func TestStuckWorkEscalatesWhenKillIsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := claimedIssue(t, store, "remote-executor", true)
+	caller := &fakeAICaller{response: `{"action": "kill", "reasoning": "Claimed for too long"}`}
+	w := newStuckWorkWatchdog(t, store, caller, &fakeKiller{})
+	w.cfg.MaxInProgressMinutes = 0 // Every claim is too old
+
+	if err := w.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLabels() error = %v", err)
+	}
+	if len(labels) != 1 || labels[0] != "escalated" {
+		t.Errorf("labels = %v, want escalated", labels)
+	}
+	recorded := watchdogEvents(t, store, issue.ID)
+	if len(recorded) != 1 || recorded[0].Data["action"] != "escalate" || recorded[0].Data["kind"] != "long_in_progress" {
+		t.Errorf("watchdog events = %+v", recorded)
+	}
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+	if got.Status != types.StatusInProgress {
+		t.Errorf("status = %s, want the live executor's claim left alone", got.Status)
+	}
+}
+
+func TestStuckWorkNudgesByDefault(t *testing.T) {
+	store := memory.New()
+	issue := claimedIssue(t, store, "live-executor", true)
+	runningExecution(t, store, issue.ID, time.Now().Add(-time.Hour))
+	w := newStuckWorkWatchdog(t, store, &fakeAICaller{response: "not json"}, nil)
+
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	recorded := watchdogEvents(t, store, issue.ID)
+	if len(recorded) != 1 || recorded[0].Data["action"] != "nudge" {
+		t.Errorf("watchdog events = %+v, want a nudge when the AI answer can't be parsed", recorded)
+	}
+}
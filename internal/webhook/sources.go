@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// pollIssueEvents queues status changes and escalations from the issue
+// audit trail, walking it from the last event seen
+func (d *Dispatcher) pollIssueEvents(ctx context.Context) error {
+	if !d.enabled[EventIssueStatusChanged] && !d.enabled[EventEscalation] {
+		return nil
+	}
+	for {
+		page := types.PageRequest{Cursor: d.issueCursor, Limit: types.MaxPageSize}
+		if d.issueCursor == "" {
+			page.Since = d.issueSince
+		}
+		result, err := d.store.GetEventsPage(ctx, "", page)
+		if err != nil {
+			return err
+		}
+		for _, event := range result.Events {
+			d.issueEvent(event)
+			d.issueCursor = types.PageCursor{Key: types.PageKey(event.CreatedAt), ID: strconv.FormatInt(event.ID, 10)}.Encode()
+		}
+		if result.NextCursor == "" {
+			return nil
+		}
+	}
+}
+
+// issueEvent queues the webhook event an audit event stands for, if any
+func (d *Dispatcher) issueEvent(event *types.Event) {
+	id := fmt.Sprintf("issue-event-%d", event.ID)
+	switch event.EventType {
+	case types.EventStatusChanged, types.EventClosed, types.EventReopened:
+		oldStatus, newStatus := event.StatusChange()
+		data := map[string]interface{}{
+			"change":     string(event.EventType),
+			"actor":      event.Actor,
+			"old_status": oldStatus,
+			"new_status": newStatus,
+		}
+		if event.Comment != nil {
+			data["reason"] = *event.Comment
+		}
+		d.enqueue(Event{ID: id, Type: EventIssueStatusChanged, Timestamp: event.CreatedAt, IssueID: event.IssueID, Data: data})
+
+	case types.EventLabelAdded:
+		label := event.AddedLabel()
+		if !types.IsEscalationLabel(label) {
+			return
+		}
+		d.enqueue(Event{ID: id, Type: EventEscalation, Timestamp: event.CreatedAt, IssueID: event.IssueID,
+			Data: map[string]interface{}{"label": label, "actor": event.Actor}})
+	}
+}
+
+// pollGateEvents queues gate failures from the agent events recorded when
+// an issue's gates finish or a mission's QA gates fail
+func (d *Dispatcher) pollGateEvents(ctx context.Context) error {
+	// Ask from a second early in case timestamps are stored with less
+	// precision; events already sent are skipped below
+	after := d.agentSince.Add(-time.Second)
+	latest := d.agentSince
+	for _, eventType := range []events.EventType{events.EventTypeQualityGatesCompleted, events.EventTypeQualityGateFail} {
+		agentEvents, err := d.store.GetAgentEvents(ctx, events.EventFilter{Type: eventType, AfterTime: after})
+		if err != nil {
+			return err
+		}
+		for _, event := range agentEvents {
+			if _, seen := d.seenAgentEvents[event.ID]; seen || event.Timestamp.Before(d.agentSince) {
+				continue
+			}
+			d.seenAgentEvents[event.ID] = event.Timestamp
+			if event.Timestamp.After(latest) {
+				latest = event.Timestamp
+			}
+			if gateFailed(event) {
+				data := map[string]interface{}{"source": string(event.Type), "message": event.Message, "severity": string(event.Severity)}
+				for key, value := range event.Data {
+					data[key] = value
+				}
+				d.enqueue(Event{ID: "agent-event-" + event.ID, Type: EventGateFailed, Timestamp: event.Timestamp, IssueID: event.IssueID, Data: data})
+			}
+		}
+	}
+	d.agentSince = latest
+	for id, at := range d.seenAgentEvents {
+		if at.Before(after) {
+			delete(d.seenAgentEvents, id)
+		}
+	}
+	return nil
+}
+
+// gateFailed reports whether a gate event records a failure. Gates
+// canceled by executor shutdown didn't fail.
+func gateFailed(event *events.AgentEvent) bool {
+	if event.Type == events.EventTypeQualityGateFail {
+		return true
+	}
+	if canceled, _ := event.Data["canceled"].(bool); canceled {
+		return false
+	}
+	passed, _ := event.Data["all_passed"].(bool)
+	return !passed
+}
+
+// pollExecutions queues the executions that finished since the last poll
+func (d *Dispatcher) pollExecutions(ctx context.Context) error {
+	executions, err := d.store.ListExecutions(ctx, types.ExecutionFilter{Limit: executionWindow})
+	if err != nil {
+		return err
+	}
+	window := make(map[int64]bool, len(executions))
+	// Oldest first, so deliveries go out in the order executions started
+	for i := len(executions) - 1; i >= 0; i-- {
+		execution := executions[i]
+		window[execution.ID] = true
+		if execution.CompletedAt == nil || execution.CompletedAt.Before(d.executionsSince) || d.sentExecutions[execution.ID] {
+			continue
+		}
+		d.sentExecutions[execution.ID] = true
+		data := map[string]interface{}{
+			"execution_id":     execution.ID,
+			"status":           string(execution.Status),
+			"agent_provider":   execution.AgentProvider,
+			"started_at":       execution.StartedAt,
+			"completed_at":     execution.CompletedAt,
+			"duration_seconds": execution.CompletedAt.Sub(execution.StartedAt).Seconds(),
+			"cost_usd":         execution.CostUSD,
+		}
+		if execution.ExitCode != nil {
+			data["exit_code"] = *execution.ExitCode
+		}
+		if execution.Error != "" {
+			data["error"] = execution.Error
+		}
+		if execution.CommitHash != "" {
+			data["commit_hash"] = execution.CommitHash
+		}
+		d.enqueue(Event{ID: fmt.Sprintf("execution-%d", execution.ID), Type: EventExecutionCompleted,
+			Timestamp: *execution.CompletedAt, IssueID: execution.IssueID, Data: data})
+	}
+	// Executions that left the window can't be seen again
+	for id := range d.sentExecutions {
+		if !window[id] {
+			delete(d.sentExecutions, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+// Package webhook sends VC lifecycle events to an external URL, so other
+// systems can react to them without polling the database.
+//
+// A Dispatcher watches the database for new issue status changes, finished
+// executions, gate failures and escalations, and POSTs each as a JSON Event.
+// Payloads are signed with HMAC-SHA256 over the body: the X-VC-Signature-256
+// header is "sha256=" followed by the hex digest (see Sign). Failed
+// deliveries stay in an in-memory retry queue, retried with exponential
+// backoff until they succeed or run out of attempts; each keeps its event
+// ID (the X-VC-Delivery header), so receivers can drop duplicates.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// EventType identifies what happened
+type EventType string
+
+// Webhook event types
+const (
+	// EventIssueStatusChanged: an issue's status changed, including closes
+	// and reopens
+	EventIssueStatusChanged EventType = "issue.status_changed"
+	// EventExecutionCompleted: an agent execution finished, successfully
+	// or not
+	EventExecutionCompleted EventType = "execution.completed"
+	// EventGateFailed: quality gates failed for an issue or a mission
+	EventGateFailed EventType = "gate.failed"
+	// EventEscalation: an issue was labeled for a human to resolve
+	EventEscalation EventType = "escalation"
+)
+
+// EventTypes lists every webhook event type
+var EventTypes = []EventType{EventIssueStatusChanged, EventExecutionCompleted, EventGateFailed, EventEscalation}
+
+// Event is the payload of a webhook delivery
+type Event struct {
+	// ID is unique per event and stays the same across retries
+	ID        string                 `json:"id"`
+	Type      EventType              `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	IssueID   string                 `json:"issue_id"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Delivery headers
+const (
+	HeaderEvent     = "X-VC-Event"
+	HeaderDelivery  = "X-VC-Delivery"
+	HeaderSignature = "X-VC-Signature-256"
+)
+
+const (
+	// maxQueue bounds the retry queue; the oldest deliveries are dropped
+	// when a receiver is down for long
+	maxQueue = 1000
+	// baseBackoff is the wait before the first retry, doubled for each
+	// later one up to maxBackoff
+	baseBackoff = 5 * time.Second
+	maxBackoff  = 10 * time.Minute
+	// executionWindow is how many recent executions are checked for
+	// completion on each poll
+	executionWindow = 100
+)
+
+// Store is the storage a Dispatcher reads events from
+type Store interface {
+	GetEventsPage(ctx context.Context, issueID string, page types.PageRequest) (*types.EventPage, error)
+	GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error)
+	ListExecutions(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
+}
+
+// delivery is a queued event with its retry state
+type delivery struct {
+	event    Event
+	body     []byte
+	attempts int
+	next     time.Time
+}
+
+// Dispatcher finds new lifecycle events and delivers them to the webhook URL
+type Dispatcher struct {
+	cfg     config.WebhookConfig
+	store   Store
+	client  *http.Client
+	enabled map[EventType]bool
+	now     func() time.Time
+
+	// Where the next poll picks up. Only events after the dispatcher was
+	// created are sent.
+	issueCursor     string
+	issueSince      time.Time
+	agentSince      time.Time
+	seenAgentEvents map[string]time.Time
+	executionsSince time.Time
+	sentExecutions  map[int64]bool
+
+	queue []*delivery
+}
+
+// NewDispatcher creates a dispatcher for cfg, which must be enabled
+func NewDispatcher(cfg config.WebhookConfig, store Store) (*Dispatcher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("webhook URL is not configured")
+	}
+	enabled := make(map[EventType]bool)
+	for _, name := range cfg.Events {
+		eventType := EventType(name)
+		known := false
+		for _, t := range EventTypes {
+			known = known || t == eventType
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown webhook event %q (want one of %v)", name, EventTypes)
+		}
+		enabled[eventType] = true
+	}
+	if len(enabled) == 0 {
+		for _, t := range EventTypes {
+			enabled[t] = true
+		}
+	}
+
+	start := time.Now()
+	return &Dispatcher{
+		cfg:             cfg,
+		store:           store,
+		client:          &http.Client{Timeout: cfg.Timeout()},
+		enabled:         enabled,
+		now:             time.Now,
+		issueSince:      start,
+		agentSince:      start,
+		seenAgentEvents: make(map[string]time.Time),
+		executionsSince: start,
+		sentExecutions:  make(map[int64]bool),
+	}, nil
+}
+
+// Run polls for events and delivers them every poll interval until ctx is
+// done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if len(d.queue) > 0 {
+				slog.Warn("webhook: dropping undelivered events on shutdown", "pending", len(d.queue))
+			}
+			return
+		case <-ticker.C:
+			if err := d.Poll(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("webhook: failed to check for events", "error", err)
+			}
+			d.Flush(ctx)
+		}
+	}
+}
+
+// Pending returns how many deliveries are queued
+func (d *Dispatcher) Pending() int {
+	return len(d.queue)
+}
+
+// Poll queues the events that happened since the last poll
+func (d *Dispatcher) Poll(ctx context.Context) error {
+	if err := d.pollIssueEvents(ctx); err != nil {
+		return fmt.Errorf("failed to read issue events: %w", err)
+	}
+	if d.enabled[EventGateFailed] {
+		if err := d.pollGateEvents(ctx); err != nil {
+			return fmt.Errorf("failed to read gate events: %w", err)
+		}
+	}
+	if d.enabled[EventExecutionCompleted] {
+		if err := d.pollExecutions(ctx); err != nil {
+			return fmt.Errorf("failed to read executions: %w", err)
+		}
+	}
+	return nil
+}
+
+// Flush tries each delivery that is due, keeping failures for retry
+func (d *Dispatcher) Flush(ctx context.Context) {
+	var remaining []*delivery
+	for _, item := range d.queue {
+		if ctx.Err() != nil || item.next.After(d.now()) {
+			remaining = append(remaining, item)
+			continue
+		}
+		err := d.send(ctx, item)
+		if err == nil {
+			continue
+		}
+		item.attempts++
+		if item.attempts >= d.cfg.MaxAttempts {
+			slog.Error("webhook: giving up on delivery", "event", item.event.Type, "delivery", item.event.ID,
+				"attempts", item.attempts, "error", err)
+			continue
+		}
+		item.next = d.now().Add(backoff(item.attempts))
+		slog.Warn("webhook: delivery failed, will retry", "event", item.event.Type, "delivery", item.event.ID,
+			"attempt", item.attempts, "retry_at", item.next, "error", err)
+		remaining = append(remaining, item)
+	}
+	d.queue = remaining
+}
+
+// backoff returns the wait before retrying a delivery that failed attempts
+// times
+func backoff(attempts int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+	return wait
+}
+
+// send POSTs a delivery once
+func (d *Dispatcher) send(ctx context.Context, item *delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.cfg.URL, bytes.NewReader(item.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "vc-webhook")
+	req.Header.Set(HeaderEvent, string(item.event.Type))
+	req.Header.Set(HeaderDelivery, item.event.ID)
+	req.Header.Set(HeaderSignature, Sign(d.cfg.Secret, item.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Drain so the connection is reused
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the X-VC-Signature-256 header value for a payload
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// enqueue adds an event to the queue if its type is enabled
+func (d *Dispatcher) enqueue(event Event) {
+	if !d.enabled[event.Type] {
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("webhook: failed to encode event", "event", event.Type, "delivery", event.ID, "error", err)
+		return
+	}
+	if len(d.queue) >= maxQueue {
+		dropped := d.queue[0]
+		slog.Error("webhook: retry queue full, dropping oldest delivery", "event", dropped.event.Type, "delivery", dropped.event.ID)
+		d.queue = d.queue[1:]
+	}
+	d.queue = append(d.queue, &delivery{event: event, body: body, next: d.now()})
+}
@@ -0,0 +1,242 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/memory"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+const testSecret = "s3cret"
+
+// receiver records the webhook deliveries it accepts, answering the first
+// few with an error when failures is set
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	events   []Event
+	invalid  int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	if req.Header.Get(HeaderSignature) != Sign(testSecret, body) {
+		r.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	var event Event
+	_ = json.Unmarshal(body, &event)
+	if req.Header.Get(HeaderEvent) != string(event.Type) || req.Header.Get(HeaderDelivery) != event.ID {
+		r.invalid++
+	}
+	r.events = append(r.events, event)
+}
+
+func (r *receiver) received() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+func newDispatcher(t *testing.T, store Store, url string, events ...string) *Dispatcher {
+	t.Helper()
+	cfg := config.DefaultWebhookConfig()
+	cfg.URL = url
+	cfg.Secret = testSecret
+	cfg.Events = events
+	d, err := NewDispatcher(cfg, store)
+	if err != nil {
+		t.Fatalf("NewDispatcher() error = %v", err)
+	}
+	// Everything the test records happens after the dispatcher starts
+	time.Sleep(2 * time.Millisecond)
+	return d
+}
+
+func createIssue(t *testing.T, store *memory.Store) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: "Work", AcceptanceCriteria: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	return issue
+}
+
+func TestDispatcherDeliversLifecycleEvents(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := createIssue(t, store)
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+	d := newDispatcher(t, store, server.URL)
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "executor"); err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "escalation", "executor-escalation"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "unrelated", "alice"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	for _, event := range []*events.AgentEvent{
+		{ID: "gates-1", Type: events.EventTypeQualityGatesCompleted, Timestamp: time.Now(), IssueID: issue.ID,
+			Data: map[string]interface{}{"all_passed": false, "failed_count": 1}},
+		{ID: "gates-2", Type: events.EventTypeQualityGatesCompleted, Timestamp: time.Now(), IssueID: issue.ID,
+			Data: map[string]interface{}{"all_passed": true}},
+		{ID: "gates-3", Type: events.EventTypeQualityGatesCompleted, Timestamp: time.Now(), IssueID: issue.ID,
+			Data: map[string]interface{}{"all_passed": false, "canceled": true}},
+	} {
+		if err := store.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("StoreAgentEvent() error = %v", err)
+		}
+	}
+	execution := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning}
+	if err := store.CreateExecution(ctx, execution); err != nil {
+		t.Fatalf("CreateExecution() error = %v", err)
+	}
+	running := &types.Execution{IssueID: issue.ID, AgentProvider: "claude-code", Status: types.ExecutionRunning}
+	if err := store.CreateExecution(ctx, running); err != nil {
+		t.Fatalf("CreateExecution() error = %v", err)
+	}
+	completed := time.Now()
+	execution.Status = types.ExecutionSucceeded
+	execution.CompletedAt = &completed
+	execution.CostUSD = 0.25
+	if err := store.UpdateExecution(ctx, execution); err != nil {
+		t.Fatalf("UpdateExecution() error = %v", err)
+	}
+
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	d.Flush(ctx)
+	// A second poll finds nothing new
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	d.Flush(ctx)
+
+	got := map[EventType][]Event{}
+	for _, event := range recv.received() {
+		got[event.Type] = append(got[event.Type], event)
+	}
+	if recv.invalid != 0 {
+		t.Errorf("%d deliveries had a bad signature or headers", recv.invalid)
+	}
+	if status := got[EventIssueStatusChanged]; len(status) != 1 || status[0].Data["old_status"] != "open" ||
+		status[0].Data["new_status"] != "in_progress" || status[0].IssueID != issue.ID {
+		t.Errorf("status changes = %+v", status)
+	}
+	if escalations := got[EventEscalation]; len(escalations) != 1 || escalations[0].Data["label"] != "escalation" {
+		t.Errorf("escalations = %+v", escalations)
+	}
+	if failures := got[EventGateFailed]; len(failures) != 1 || failures[0].Data["failed_count"] != 1.0 {
+		t.Errorf("gate failures = %+v, want only the failed run", failures)
+	}
+	if executions := got[EventExecutionCompleted]; len(executions) != 1 || executions[0].Data["status"] != "succeeded" ||
+		executions[0].Data["cost_usd"] != 0.25 {
+		t.Errorf("completed executions = %+v", executions)
+	}
+	if d.Pending() != 0 {
+		t.Errorf("Pending() = %d after successful deliveries", d.Pending())
+	}
+}
+
+func TestDispatcherRetriesFailedDeliveries(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := createIssue(t, store)
+	recv := &receiver{failures: 2}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+	d := newDispatcher(t, store, server.URL, string(EventIssueStatusChanged))
+	clock := time.Now()
+	d.now = func() time.Time { return clock }
+
+	if err := store.CloseIssue(ctx, issue.ID, "done", "alice"); err != nil {
+		t.Fatalf("CloseIssue() error = %v", err)
+	}
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	d.Flush(ctx) // Fails
+	d.Flush(ctx) // Not due yet
+	if len(recv.received()) != 0 || d.Pending() != 1 {
+		t.Fatalf("received %d, pending %d after a failure; want the delivery queued", len(recv.received()), d.Pending())
+	}
+	clock = clock.Add(baseBackoff)
+	d.Flush(ctx) // Fails again
+	clock = clock.Add(baseBackoff)
+	d.Flush(ctx) // Backoff doubled: not due yet
+	if d.Pending() != 1 || len(recv.received()) != 0 {
+		t.Fatalf("delivery retried before its backoff elapsed")
+	}
+	clock = clock.Add(baseBackoff)
+	d.Flush(ctx)
+	got := recv.received()
+	if len(got) != 1 || got[0].Data["new_status"] != "closed" || got[0].Data["reason"] != "done" || d.Pending() != 0 {
+		t.Errorf("received %+v, pending %d; want the close delivered", got, d.Pending())
+	}
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	issue := createIssue(t, store)
+	recv := &receiver{failures: 100}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+	d := newDispatcher(t, store, server.URL)
+	d.cfg.MaxAttempts = 2
+	clock := time.Now()
+	d.now = func() time.Time { return clock }
+
+	if err := store.AddLabel(ctx, issue.ID, "escalated", "ai-supervisor"); err != nil {
+		t.Fatalf("AddLabel() error = %v", err)
+	}
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	d.Flush(ctx)
+	clock = clock.Add(maxBackoff)
+	d.Flush(ctx)
+	if d.Pending() != 0 {
+		t.Errorf("Pending() = %d after MaxAttempts failures, want the delivery dropped", d.Pending())
+	}
+}
+
+func TestNewDispatcherRejectsUnknownEvents(t *testing.T) {
+	cfg := config.DefaultWebhookConfig()
+	cfg.URL = "https://hooks.example.com"
+	cfg.Secret = testSecret
+	cfg.Events = []string{"issue.created"}
+	if _, err := NewDispatcher(cfg, memory.New()); err == nil {
+		t.Error("NewDispatcher() accepted an unknown event type")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if backoff(1) != baseBackoff || backoff(2) != 2*baseBackoff || backoff(30) != maxBackoff {
+		t.Errorf("backoff = %v, %v, %v", backoff(1), backoff(2), backoff(30))
+	}
+}